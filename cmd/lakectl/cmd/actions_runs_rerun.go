@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+const runsRerunRequiredArgs = 2
+
+var actionsRunsRerunCmd = &cobra.Command{
+	Use:               "rerun <repository URI> <run_id>",
+	Short:             "Run the actions of a failed run again",
+	Long:              `Run the actions of a failed post-commit or post-merge run again, as a new run with the same event information`,
+	Example:           "lakectl actions runs rerun " + myRepoExample + " " + myRunIDExample,
+	Args:              cobra.ExactArgs(runsRerunRequiredArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+		fmt.Println("Repository:", u)
+		runID := args[1]
+
+		client := getClient()
+		resp, err := client.RerunRunWithResponse(cmd.Context(), u.Repository, runID)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(actionRunResultTemplate, convertRunResultTable(resp.JSON201))
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	actionsRunsCmd.AddCommand(actionsRunsRerunCmd)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administer the lakeFS installation",
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(adminCmd)
+}
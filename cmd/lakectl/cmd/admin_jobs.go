@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var adminJobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Monitor the background jobs of the lakeFS server",
+}
+
+func formatJobTime(t *int64) string {
+	if t == nil {
+		return ""
+	}
+	return time.Unix(*t, 0).String()
+}
+
+func jobRunRow(run apigen.JobRun) []interface{} {
+	return []interface{}{
+		run.Id,
+		run.Status,
+		time.Unix(run.StartTime, 0).String(),
+		formatJobTime(run.EndTime),
+		run.Attempts,
+		run.Owner,
+		apiutil.Value(run.Error),
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	adminCmd.AddCommand(adminJobsCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var adminJobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the background jobs with their schedules and latest runs",
+	Run: func(cmd *cobra.Command, args []string) {
+		clt := getClient()
+		resp, err := clt.ListJobsWithResponse(cmd.Context())
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		jobs := resp.JSON200.Results
+		rows := make([][]interface{}, len(jobs))
+		for i, job := range jobs {
+			var status, started, errMsg string
+			if job.LastRun != nil {
+				status = job.LastRun.Status
+				started = formatJobTime(&job.LastRun.StartTime)
+				errMsg = apiutil.Value(job.LastRun.Error)
+			}
+			rows[i] = []interface{}{job.Name, job.Schedule, formatJobTime(job.NextRun), status, started, errMsg}
+		}
+		PrintTable(rows, []interface{}{"Job", "Schedule", "Next Run", "Last Status", "Last Started", "Last Error"}, &apigen.Pagination{}, 0)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	adminJobsCmd.AddCommand(adminJobsListCmd)
+}
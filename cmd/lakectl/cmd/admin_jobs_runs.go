@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var adminJobsRunsCmd = &cobra.Command{
+	Use:     "runs <job>",
+	Short:   "List the latest runs of a background job, newest first",
+	Example: "lakectl admin jobs runs delete_expired_deleted_branches",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+
+		clt := getClient()
+		resp, err := clt.ListJobRunsWithResponse(cmd.Context(), args[0], &apigen.ListJobRunsParams{
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		runs := resp.JSON200.Results
+		rows := make([][]interface{}, len(runs))
+		for i, run := range runs {
+			rows[i] = jobRunRow(run)
+		}
+		PrintTable(rows, []interface{}{"Run ID", "Status", "Started", "Ended", "Attempts", "Instance", "Error"}, &apigen.Pagination{}, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	adminJobsRunsCmd.Flags().Int("amount", defaultAmountArgumentValue, "how many runs to return")
+
+	adminJobsCmd.AddCommand(adminJobsRunsCmd)
+}
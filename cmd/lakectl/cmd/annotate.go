@@ -34,55 +34,34 @@ var annotateCmd = &cobra.Command{
 		firstParent := Must(cmd.Flags().GetBool("first-parent"))
 		client := getClient()
 		pfx := apigen.PaginationPrefix(*pathURI.Path)
-		context := cmd.Context()
-		resp, err := client.ListObjectsWithResponse(context, pathURI.Repository, pathURI.Ref, &apigen.ListObjectsParams{Prefix: &pfx})
-		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
-		if resp.JSON200 == nil {
-			Die("Bad response from server", 1)
-		}
-		var listObjectsDelimiter apigen.PaginationDelimiter
+		var delimiter apigen.PaginationDelimiter
 		if !recursive {
-			listObjectsDelimiter = PathDelimiter
+			delimiter = PathDelimiter
 		}
 		var from string
-		limit := true
 		for {
-			params := &apigen.ListObjectsParams{
-				Prefix:    &pfx,
-				After:     apiutil.Ptr(apigen.PaginationAfter(from)),
-				Delimiter: &listObjectsDelimiter,
+			params := &apigen.AnnotateObjectsParams{
+				Prefix:      &pfx,
+				After:       apiutil.Ptr(apigen.PaginationAfter(from)),
+				Delimiter:   &delimiter,
+				FirstParent: &firstParent,
 			}
-			listObjectsResp, err := client.ListObjectsWithResponse(context, pathURI.Repository, pathURI.Ref, params)
-			DieOnErrorOrUnexpectedStatusCode(listObjectsResp, err, http.StatusOK)
+			resp, err := client.AnnotateObjectsWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, params)
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
 			if resp.JSON200 == nil {
 				Die("Bad response from server", 1)
 			}
-			for _, obj := range listObjectsResp.JSON200.Results {
-				logCommitsParams := &apigen.LogCommitsParams{
-					Amount:      apiutil.Ptr(apigen.PaginationAmount(1)),
-					Limit:       &limit,
-					FirstParent: &firstParent,
-				}
-				if recursive {
-					logCommitsParams.Objects = &[]string{obj.Path}
-				} else {
-					logCommitsParams.Prefixes = &[]string{obj.Path}
-				}
-				logCommitsResp, err := client.LogCommitsWithResponse(context, pathURI.Repository, pathURI.Ref, logCommitsParams)
-				DieOnErrorOrUnexpectedStatusCode(logCommitsResp, err, http.StatusOK)
-				if resp.JSON200 == nil {
-					Die("Bad response from server", 1)
-				}
+			for _, obj := range resp.JSON200.Results {
 				data := objectCommitData{
 					Object: obj.Path,
 				}
-				if len(logCommitsResp.JSON200.Results) > 0 {
-					data.Commit = logCommitsResp.JSON200.Results[0]
-					data.CommitMessage = splitOnNewLine(stringTrimLen(logCommitsResp.JSON200.Results[0].Message, annotateMessageSize))
+				if obj.Commit != nil {
+					data.Commit = *obj.Commit
+					data.CommitMessage = splitOnNewLine(stringTrimLen(obj.Commit.Message, annotateMessageSize))
 				}
 				Write(annotateTemplate, data)
 			}
-			pagination := listObjectsResp.JSON200.Pagination
+			pagination := resp.JSON200.Pagination
 			if !pagination.HasMore {
 				break
 			}
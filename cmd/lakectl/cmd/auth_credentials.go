@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var authCredentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Monitor and revoke the access key credentials of all users",
+}
+
+//nolint:gochecknoinits
+func init() {
+	authCmd.AddCommand(authCredentialsCmd)
+}
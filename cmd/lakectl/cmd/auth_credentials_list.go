@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var authCredentialsList = &cobra.Command{
+	Use:   "list",
+	Short: "List the access key credentials of all users with their last use",
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+
+		clt := getClient()
+		resp, err := clt.ListCredentialsActivityWithResponse(cmd.Context(), &apigen.ListCredentialsActivityParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		credentials := resp.JSON200.Results
+		rows := make([][]interface{}, len(credentials))
+		for i, c := range credentials {
+			lastUsed := "never"
+			if c.LastUsedDate != nil {
+				lastUsed = time.Unix(*c.LastUsedDate, 0).String()
+			}
+			rows[i] = []interface{}{
+				c.AccessKeyId,
+				c.UserId,
+				time.Unix(c.CreationDate, 0).String(),
+				lastUsed,
+				apiutil.Value(c.LastUsedIp),
+			}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Access Key ID", "User", "Issued Date", "Last Used", "Last Used From"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	addPaginationFlags(authCredentialsList)
+
+	authCredentialsCmd.AddCommand(authCredentialsList)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authCredentialsRevoke = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke access key credentials of any user",
+	Long: `Revoke access key credentials of any user.  The credentials are deleted, and all lakeFS servers reject
+requests signed by them within the revocation TTL of their auth cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		accessKeyID := Must(cmd.Flags().GetString("access-key-id"))
+
+		clt := getClient()
+		resp, err := clt.RevokeCredentialsWithResponse(cmd.Context(), accessKeyID)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+
+		fmt.Printf("Credentials %s revoked successfully\n", accessKeyID)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authCredentialsRevoke.Flags().String("access-key-id", "", "Access key ID of the credentials to revoke")
+	_ = authCredentialsRevoke.MarkFlagRequired("access-key-id")
+
+	authCredentialsCmd.AddCommand(authCredentialsRevoke)
+}
@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var authGroupsPermissionBoundary = &cobra.Command{
+	Use:   "permission-boundary",
+	Short: "Manage group permission boundary",
+	Long:  "Manage the permission boundary of a group: a policy that limits the permissions the group gets from its policies.",
+}
+
+//nolint:gochecknoinits
+func init() {
+	authGroupsCmd.AddCommand(authGroupsPermissionBoundary)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authGroupsPermissionBoundaryRemove = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the permission boundary of a group",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		clt := getClient()
+
+		resp, err := clt.DeleteGroupPermissionBoundaryWithResponse(cmd.Context(), id)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+
+		fmt.Println("Permission boundary removed successfully")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authGroupsPermissionBoundaryRemove.Flags().String("id", "", "Group identifier")
+	_ = authGroupsPermissionBoundaryRemove.MarkFlagRequired("id")
+
+	authGroupsPermissionBoundary.AddCommand(authGroupsPermissionBoundaryRemove)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authGroupsPermissionBoundarySet = &cobra.Command{
+	Use:   "set",
+	Short: "Set the permission boundary of a group",
+	Long:  "Set the permission boundary of a group, replacing its current boundary.  The group is allowed only actions that are allowed both by its policies and by the boundary policy.",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		policy := Must(cmd.Flags().GetString("policy"))
+		clt := getClient()
+
+		resp, err := clt.SetGroupPermissionBoundaryWithResponse(cmd.Context(), id, policy)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+
+		fmt.Println("Permission boundary set successfully")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authGroupsPermissionBoundarySet.Flags().String("id", "", "Group identifier")
+	_ = authGroupsPermissionBoundarySet.MarkFlagRequired("id")
+	authGroupsPermissionBoundarySet.Flags().String("policy", "", "Policy identifier")
+	_ = authGroupsPermissionBoundarySet.MarkFlagRequired("policy")
+
+	authGroupsPermissionBoundary.AddCommand(authGroupsPermissionBoundarySet)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+)
+
+var authGroupsPermissionBoundaryShow = &cobra.Command{
+	Use:   "show",
+	Short: "Show the permission boundary of a group",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		clt := getClient()
+
+		resp, err := clt.GetGroupPermissionBoundaryWithResponse(cmd.Context(), id)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		policy := *resp.JSON200
+		Write(policyDetailsTemplate, struct {
+			ID           string
+			CreationDate int64
+			StatementDoc StatementDoc
+		}{
+			ID:           policy.Id,
+			CreationDate: swag.Int64Value(policy.CreationDate),
+			StatementDoc: StatementDoc{Statement: policy.Statement},
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authGroupsPermissionBoundaryShow.Flags().String("id", "", "Group identifier")
+	_ = authGroupsPermissionBoundaryShow.MarkFlagRequired("id")
+
+	authGroupsPermissionBoundary.AddCommand(authGroupsPermissionBoundaryShow)
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var authSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Monitor and revoke the login sessions of all users",
+}
+
+//nolint:gochecknoinits
+func init() {
+	authCmd.AddCommand(authSessionsCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var authSessionsList = &cobra.Command{
+	Use:   "list",
+	Short: "List the active login sessions of all users",
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+
+		clt := getClient()
+		resp, err := clt.ListLoginSessionsWithResponse(cmd.Context(), &apigen.ListLoginSessionsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		sessions := resp.JSON200.Results
+		rows := make([][]interface{}, len(sessions))
+		for i, s := range sessions {
+			rows[i] = []interface{}{
+				s.Id,
+				s.UserId,
+				time.Unix(s.CreationDate, 0).String(),
+				time.Unix(s.ExpirationDate, 0).String(),
+				time.Unix(s.LastUsedDate, 0).String(),
+				apiutil.Value(s.LastUsedIp),
+			}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Session ID", "User", "Created", "Expires", "Last Used", "Last Used From"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	addPaginationFlags(authSessionsList)
+
+	authSessionsCmd.AddCommand(authSessionsList)
+}
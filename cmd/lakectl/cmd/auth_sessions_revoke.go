@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authSessionsRevoke = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke a login session",
+	Long: `Revoke a login session.  All lakeFS servers reject requests authenticated by the login token of the
+session within the revocation TTL of their auth cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sessionID := Must(cmd.Flags().GetString("session-id"))
+
+		clt := getClient()
+		resp, err := clt.RevokeLoginSessionWithResponse(cmd.Context(), sessionID)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+
+		fmt.Printf("Session %s revoked successfully\n", sessionID)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authSessionsRevoke.Flags().String("session-id", "", "ID of the session to revoke")
+	_ = authSessionsRevoke.MarkFlagRequired("session-id")
+
+	authSessionsCmd.AddCommand(authSessionsRevoke)
+}
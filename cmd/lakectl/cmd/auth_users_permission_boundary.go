@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var authUsersPermissionBoundary = &cobra.Command{
+	Use:   "permission-boundary",
+	Short: "Manage user permission boundary",
+	Long:  "Manage the permission boundary of a user: a policy that limits the permissions the user gets from its policies.",
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersCmd.AddCommand(authUsersPermissionBoundary)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authUsersPermissionBoundaryRemove = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the permission boundary of a user",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		clt := getClient()
+
+		resp, err := clt.DeleteUserPermissionBoundaryWithResponse(cmd.Context(), id)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+
+		fmt.Println("Permission boundary removed successfully")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersPermissionBoundaryRemove.Flags().String("id", "", "Username (email for password-based users)")
+	_ = authUsersPermissionBoundaryRemove.MarkFlagRequired("id")
+
+	authUsersPermissionBoundary.AddCommand(authUsersPermissionBoundaryRemove)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authUsersPermissionBoundarySet = &cobra.Command{
+	Use:   "set",
+	Short: "Set the permission boundary of a user",
+	Long:  "Set the permission boundary of a user, replacing its current boundary.  The user is allowed only actions that are allowed both by its policies and by the boundary policy.",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		policy := Must(cmd.Flags().GetString("policy"))
+		clt := getClient()
+
+		resp, err := clt.SetUserPermissionBoundaryWithResponse(cmd.Context(), id, policy)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+
+		fmt.Println("Permission boundary set successfully")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersPermissionBoundarySet.Flags().String("id", "", "Username (email for password-based users)")
+	_ = authUsersPermissionBoundarySet.MarkFlagRequired("id")
+	authUsersPermissionBoundarySet.Flags().String("policy", "", "Policy identifier")
+	_ = authUsersPermissionBoundarySet.MarkFlagRequired("policy")
+
+	authUsersPermissionBoundary.AddCommand(authUsersPermissionBoundarySet)
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var authUsersTokens = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage user personal access tokens",
+	Long: `Manage user personal access tokens.  A token authenticates API requests as the user, limited to the actions
+and repositories of its scope, until it expires.  Set it as credentials.access_token (or the
+LAKECTL_CREDENTIALS_ACCESS_TOKEN environment variable) to use it with lakectl.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersCmd.AddCommand(authUsersTokens)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const (
+	defaultAccessTokenExpiry = 30 * 24 * time.Hour
+
+	accessTokenCreatedTemplate = `{{ "Access token created successfully." | green }}
+{{ "Token ID:" | ljust 12 }} {{ .AccessToken.Id | bold }}
+{{ "Expires:" | ljust 12 }} {{ .AccessToken.ExpirationDate | date }}
+{{ "Token:" | ljust 12 }} {{ .Token | bold }}
+
+{{ "Keep this token somewhere safe since you will not be able to see it again" | yellow }}
+`
+)
+
+var authUsersTokensCreate = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a personal access token",
+	Example: "lakectl auth users tokens create --action 'fs:Read*' --action fs:ListObjects --repository example-repo --expiry 168h",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		description := Must(cmd.Flags().GetString("description"))
+		actions := Must(cmd.Flags().GetStringSlice("action"))
+		repositories := Must(cmd.Flags().GetStringSlice("repository"))
+		expiry := Must(cmd.Flags().GetDuration("expiry"))
+		clt := getClient()
+
+		if id == "" {
+			resp, err := clt.GetCurrentUserWithResponse(cmd.Context())
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			id = resp.JSON200.User.Id
+		}
+
+		body := apigen.CreateUserAccessTokenJSONRequestBody{
+			ExpirationDate: time.Now().Add(expiry).Unix(),
+			Scope:          apigen.AccessTokenScope{Actions: actions},
+		}
+		if description != "" {
+			body.Description = apiutil.Ptr(description)
+		}
+		if len(repositories) > 0 {
+			body.Scope.Repositories = apiutil.Ptr(repositories)
+		}
+		resp, err := clt.CreateUserAccessTokenWithResponse(cmd.Context(), id, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		Write(accessTokenCreatedTemplate, resp.JSON201)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersTokensCreate.Flags().String("id", "", "Username (email for password-based users, default: current user)")
+	authUsersTokensCreate.Flags().String("description", "", "Token description")
+	authUsersTokensCreate.Flags().StringSlice("action", nil, "Action allowed by the token, may contain wildcards (repeat for multiple actions)")
+	_ = authUsersTokensCreate.MarkFlagRequired("action")
+	authUsersTokensCreate.Flags().StringSlice("repository", nil, "Repository the token is limited to (repeat for multiple repositories, default: all repositories)")
+	authUsersTokensCreate.Flags().Duration("expiry", defaultAccessTokenExpiry, "Time until the token expires")
+
+	authUsersTokens.AddCommand(authUsersTokensCreate)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var authUsersTokensDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a personal access token",
+	Run: func(cmd *cobra.Command, args []string) {
+		id := Must(cmd.Flags().GetString("id"))
+		tokenID := Must(cmd.Flags().GetString("token-id"))
+		clt := getClient()
+
+		if id == "" {
+			resp, err := clt.GetCurrentUserWithResponse(cmd.Context())
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			id = resp.JSON200.User.Id
+		}
+		resp, err := clt.DeleteUserAccessTokenWithResponse(cmd.Context(), id, tokenID)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+
+		fmt.Println("Access token deleted successfully")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersTokensDelete.Flags().String("id", "", "Username (email for password-based users, default: current user)")
+	authUsersTokensDelete.Flags().String("token-id", "", "ID of the token to delete")
+	_ = authUsersTokensDelete.MarkFlagRequired("token-id")
+
+	authUsersTokens.AddCommand(authUsersTokensDelete)
+}
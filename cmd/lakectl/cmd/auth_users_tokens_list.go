@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var authUsersTokensList = &cobra.Command{
+	Use:   "list",
+	Short: "List personal access tokens",
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		id := Must(cmd.Flags().GetString("id"))
+
+		clt := getClient()
+		if id == "" {
+			resp, err := clt.GetCurrentUserWithResponse(cmd.Context())
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			id = resp.JSON200.User.Id
+		}
+
+		resp, err := clt.ListUserAccessTokensWithResponse(cmd.Context(), id, &apigen.ListUserAccessTokensParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		tokens := resp.JSON200.Results
+		rows := make([][]interface{}, len(tokens))
+		for i, t := range tokens {
+			repositories := "*"
+			if t.Scope.Repositories != nil && len(*t.Scope.Repositories) > 0 {
+				repositories = strings.Join(*t.Scope.Repositories, ",")
+			}
+			lastUsed := "never"
+			if t.LastUsedDate != nil {
+				lastUsed = time.Unix(*t.LastUsedDate, 0).String()
+			}
+			rows[i] = []interface{}{
+				t.Id,
+				apiutil.Value(t.Description),
+				strings.Join(t.Scope.Actions, ","),
+				repositories,
+				time.Unix(t.ExpirationDate, 0).String(),
+				lastUsed,
+			}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Token ID", "Description", "Actions", "Repositories", "Expires", "Last Used"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	authUsersTokensList.Flags().String("id", "", "Username (email for password-based users, default: current user)")
+	addPaginationFlags(authUsersTokensList)
+
+	authUsersTokens.AddCommand(authUsersTokensList)
+}
@@ -18,7 +18,8 @@ import (
 
 const bisectCommitTemplate = `{{ range $val := . }}
 ID:            {{ $val.Id|yellow }}{{if $val.Committer }}
-Author:        {{ $val.Committer }}{{end}}
+Author:        {{ $val.Committer }}{{end}}{{ if and $val.Author (ne (value $val.Author) $val.Committer) }}
+Created By:    {{ value $val.Author }}{{ end }}
 Date:          {{ $val.CreationDate|date }}
 Meta Range ID: {{ $val.MetaRangeId }}
 {{ if gt ($val.Parents|len) 1 -}}
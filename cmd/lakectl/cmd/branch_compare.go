@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const branchCompareTemplate = `Left:        {{ .Left }} ({{ .Result.LeftCommitId | printf "%.16s" }})
+Right:       {{ .Right }} ({{ .Result.RightCommitId | printf "%.16s" }})
+Merge base:  {{ .Result.BaseCommitId | yellow }}
+Left is {{ .Result.Ahead }} commit(s) ahead and {{ .Result.Behind }} commit(s) behind right.
+{{ if eq .Result.Ahead 0 }}Right already contains all commits of left.
+{{ else if eq .Result.Behind 0 }}{{ "Fast-forward possible:" | green }} right can be fast-forwarded to left.
+{{ else }}{{ "Fast-forward not possible:" | red }} the refs diverged, merging left into right creates a merge commit.
+{{ end }}`
+
+var branchCompareCmd = &cobra.Command{
+	Use:   "compare <left ref URI> <right ref URI>",
+	Short: "Show the merge base of two refs and how many commits each has that the other does not",
+	Long: `Show the merge base (best common ancestor) of two refs in the same repository, and the number of commits
+reachable from each ref and not from the other. When right is not ahead of left, it can be fast-forwarded to left.`,
+	Example:           "lakectl branch compare " + myRepoExample + "/feature " + myRepoExample + "/" + myBranchExample,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		left := MustParseRefURI("left ref URI", args[0])
+		right := MustParseRefURI("right ref URI", args[1])
+		if left.Repository != right.Repository {
+			Die("both references must belong to the same repository", 1)
+		}
+
+		resp, err := client.GetMergeBaseWithResponse(cmd.Context(), left.Repository, left.Ref, right.Ref)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(branchCompareTemplate, struct {
+			Left   string
+			Right  string
+			Result *apigen.MergeBaseResult
+		}{
+			Left:   left.String(),
+			Right:  right.String(),
+			Result: resp.JSON200,
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchCmd.AddCommand(branchCompareCmd)
+}
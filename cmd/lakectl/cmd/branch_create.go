@@ -19,6 +19,7 @@ var branchCreateCmd = &cobra.Command{
 		u := MustParseBranchURI("branch URI", args[0])
 		client := getClient()
 		sourceRawURI := Must(cmd.Flags().GetString("source"))
+		existOK := Must(cmd.Flags().GetBool("exist-ok"))
 		sourceURI, err := uri.ParseWithBaseURI(sourceRawURI, baseURI)
 		if err != nil {
 			DieFmt("failed to parse source URI: %s", err)
@@ -29,8 +30,9 @@ var branchCreateCmd = &cobra.Command{
 		}
 
 		resp, err := client.CreateBranchWithResponse(cmd.Context(), u.Repository, apigen.CreateBranchJSONRequestBody{
-			Name:   u.Ref,
-			Source: sourceURI.Ref,
+			Name:    u.Ref,
+			Source:  sourceURI.Ref,
+			ExistOk: &existOK,
 		})
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
 		fmt.Printf("created branch '%s' %s\n", u.Ref, string(resp.Body))
@@ -41,6 +43,7 @@ var branchCreateCmd = &cobra.Command{
 func init() {
 	branchCreateCmd.Flags().StringP("source", "s", "", "source branch uri")
 	_ = branchCreateCmd.MarkFlagRequired("source")
+	branchCreateCmd.Flags().Bool("exist-ok", false, "succeed if the branch already exists at the commit of the source")
 	_ = branchCreateCmd.RegisterFlagCompletionFunc("source", ValidArgsRepository)
 
 	branchCmd.AddCommand(branchCreateCmd)
@@ -9,8 +9,11 @@ import (
 )
 
 var branchDeleteCmd = &cobra.Command{
-	Use:               "delete <branch URI>",
-	Short:             "Delete a branch in a repository, along with its uncommitted changes (CAREFUL)",
+	Use:   "delete <branch URI>",
+	Short: "Delete a branch in a repository, along with its uncommitted changes (CAREFUL)",
+	Long: `Delete a branch in a repository, along with its uncommitted changes (CAREFUL). Unless disabled on the server,
+the branch can be restored at its last commit using 'lakectl branch restore' during a retention period. Its
+uncommitted changes cannot be restored.`,
 	Example:           "lakectl branch delete " + myRepoExample + "/" + myBranchExample,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
@@ -17,8 +19,13 @@ var branchListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		amount := Must(cmd.Flags().GetInt("amount"))
 		after := Must(cmd.Flags().GetString("after"))
+		deleted := Must(cmd.Flags().GetBool("deleted"))
 		u := MustParseRepoURI("repository URI", args[0])
 		client := getClient()
+		if deleted {
+			listDeletedBranches(cmd, client, u.Repository, after, amount)
+			return
+		}
 		resp, err := client.ListBranchesWithResponse(cmd.Context(), u.Repository, &apigen.ListBranchesParams{
 			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
 			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
@@ -31,18 +38,43 @@ var branchListCmd = &cobra.Command{
 		refs := resp.JSON200.Results
 		rows := make([][]interface{}, len(refs))
 		for i, row := range refs {
-			rows[i] = []interface{}{row.Id, row.CommitId}
+			locked := ""
+			if row.Lock != nil {
+				locked = fmt.Sprintf("locked by %s: %s", row.Lock.LockedBy, row.Lock.Reason)
+			}
+			rows[i] = []interface{}{row.Id, row.CommitId, locked}
 		}
 
 		pagination := resp.JSON200.Pagination
-		PrintTable(rows, []interface{}{"Branch", "Commit ID"}, &pagination, amount)
+		PrintTable(rows, []interface{}{"Branch", "Commit ID", "Lock"}, &pagination, amount)
 	},
 }
 
+func listDeletedBranches(cmd *cobra.Command, client apigen.ClientWithResponsesInterface, repository, after string, amount int) {
+	resp, err := client.ListDeletedBranchesWithResponse(cmd.Context(), repository, &apigen.ListDeletedBranchesParams{
+		After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+		Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+	})
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+
+	branches := resp.JSON200.Results
+	rows := make([][]interface{}, len(branches))
+	for i, row := range branches {
+		rows[i] = []interface{}{row.Id, row.CommitId, time.Unix(row.DeletedAt, 0).String(), time.Unix(row.ExpiresAt, 0).String()}
+	}
+
+	pagination := resp.JSON200.Pagination
+	PrintTable(rows, []interface{}{"Branch", "Commit ID", "Deleted At", "Expires At"}, &pagination, amount)
+}
+
 //nolint:gochecknoinits
 func init() {
 	branchListCmd.Flags().Int("amount", defaultAmountArgumentValue, "number of results to return")
 	branchListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	branchListCmd.Flags().Bool("deleted", false, "list deleted branches that can be restored")
 
 	branchCmd.AddCommand(branchListCmd)
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const branchLockReasonFlagName = "reason"
+
+var branchLockCmd = &cobra.Command{
+	Use:   "lock <branch URI>",
+	Short: "Lock a branch, rejecting any change to it",
+	Long: `Lock a branch for a maintenance window or a freeze: writes, commits, merges into the branch, resets and
+reverts are rejected until it is unlocked.`,
+	Example:           "lakectl branch lock " + myRepoExample + "/" + myBranchExample + " --reason 'quarterly freeze'",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		reason := Must(cmd.Flags().GetString(branchLockReasonFlagName))
+		client := getClient()
+		u := MustParseBranchURI("branch URI", args[0])
+		resp, err := client.LockBranchWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.LockBranchJSONRequestBody{
+			Reason: reason,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Branch %s locked\n", u)
+	},
+}
+
+var branchUnlockCmd = &cobra.Command{
+	Use:               "unlock <branch URI>",
+	Short:             "Unlock a locked branch",
+	Example:           "lakectl branch unlock " + myRepoExample + "/" + myBranchExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseBranchURI("branch URI", args[0])
+		resp, err := client.UnlockBranchWithResponse(cmd.Context(), u.Repository, u.Ref)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Branch %s unlocked\n", u)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchLockCmd.Flags().String(branchLockReasonFlagName, "", "reason for locking the branch")
+	_ = branchLockCmd.MarkFlagRequired(branchLockReasonFlagName)
+
+	branchCmd.AddCommand(branchLockCmd)
+	branchCmd.AddCommand(branchUnlockCmd)
+}
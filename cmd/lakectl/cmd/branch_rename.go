@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var branchRenameCmd = &cobra.Command{
+	Use:   "rename <branch URI> <new name>",
+	Short: "Rename a branch, keeping its commits and uncommitted changes",
+	Long: `Rename a branch, keeping its commits and uncommitted changes. Branch protection rules, lifecycle rules and
+garbage collection retention of the branch name move to the new name, and renaming the default branch of the
+repository makes the new name its default branch. Locked branches, and branches selected by name by their actions,
+are not renamed.`,
+	Example:           "lakectl branch rename " + myRepoExample + "/master main",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseBranchURI("branch URI", args[0])
+		resp, err := client.RenameBranchWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.RenameBranchJSONRequestBody{
+			Name: args[1],
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Branch %s renamed to %s\n", u, args[1])
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchCmd.AddCommand(branchRenameCmd)
+}
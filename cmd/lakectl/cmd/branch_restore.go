@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var branchRestoreCmd = &cobra.Command{
+	Use:   "restore <branch URI>",
+	Short: "Restore a deleted branch",
+	Long: `Restore a deleted branch at the commit it pointed at when it was deleted. Uncommitted changes of the branch
+are not restored. Deleted branches can be restored until the retention period configured on the server passes,
+use 'lakectl branch list --deleted' to list them.`,
+	Example:           "lakectl branch restore " + myRepoExample + "/" + myBranchExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseBranchURI("branch URI", args[0])
+		resp, err := client.RestoreBranchWithResponse(cmd.Context(), u.Repository, u.Ref)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Branch %s restored at commit %s\n", u, resp.JSON201.CommitId)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchCmd.AddCommand(branchRestoreCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -24,6 +25,9 @@ var branchShowCmd = &cobra.Command{
 		}
 		branch := resp.JSON200
 		fmt.Println("Commit ID:", branch.CommitId)
+		if branch.Lock != nil {
+			fmt.Printf("Locked by %s since %s: %s\n", branch.Lock.LockedBy, time.Unix(branch.Lock.CreationDate, 0), branch.Lock.Reason)
+		}
 	},
 }
 
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	branchWaitSinceFlagName        = "since"
+	branchWaitTimeoutFlagName      = "timeout"
+	branchWaitPollIntervalFlagName = "poll-interval"
+)
+
+var branchWaitCmd = &cobra.Command{
+	Use:   "wait <branch URI>",
+	Short: "Wait for a branch to advance past a commit",
+	Long: `Wait until a commit or a merge advances the branch past a commit, then print the ID of the commit it advanced to.
+Without --since, wait for the branch to advance from its current commit.
+With --meta, wait for a commit whose metadata includes all the given pairs, ignoring other commits.
+Exits with an error if --timeout passes first.`,
+	Example: `lakectl branch wait ` + myRepoExample + `/main --since 600dc0ffee --timeout 10m
+lakectl branch wait ` + myRepoExample + `/main --meta pipeline=ingest`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		since := Must(cmd.Flags().GetString(branchWaitSinceFlagName))
+		timeout := Must(cmd.Flags().GetDuration(branchWaitTimeoutFlagName))
+		pollInterval := Must(cmd.Flags().GetDuration(branchWaitPollIntervalFlagName))
+		if pollInterval < minimumPollInterval {
+			DieFmt("Poll interval must be at least %s", minimumPollInterval)
+		}
+		kvPairs := Must(getKV(cmd, metaFlagName))
+		var metadata apigen.CommitSearchMetadata
+		for k, v := range kvPairs {
+			metadata = append(metadata, k+"="+v)
+		}
+
+		u := MustParseBranchURI("branch URI", args[0])
+		client := getClient()
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if since == "" {
+			since = getBranchCommitID(ctx, client, u)
+		} else {
+			// compare full commit IDs with the commit of the branch
+			resp, err := client.GetCommitWithResponse(ctx, u.Repository, since)
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			since = resp.JSON200.Id
+		}
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			if head := getBranchCommitID(ctx, client, u); head != since {
+				if commitID := findCommitSince(ctx, client, u, since, metadata); commitID != "" {
+					fmt.Println(commitID)
+					return
+				}
+				// the branch advanced without a matching commit
+				since = head
+			}
+			select {
+			case <-ctx.Done():
+				DieFmt("Timed out waiting for branch %s to advance", u)
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func getBranchCommitID(ctx context.Context, client apigen.ClientWithResponsesInterface, u *uri.URI) string {
+	resp, err := client.GetBranchWithResponse(ctx, u.Repository, u.Ref)
+	if ctx.Err() != nil {
+		DieFmt("Timed out waiting for branch %s to advance", u)
+	}
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	return resp.JSON200.CommitId
+}
+
+// findCommitSince returns the latest commit of the branch after since with metadata, empty if there is none
+func findCommitSince(ctx context.Context, client apigen.ClientWithResponsesInterface, u *uri.URI, since string, metadata apigen.CommitSearchMetadata) string {
+	params := &apigen.LogCommitsParams{
+		Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		Limit:  apiutil.Ptr(true),
+		StopAt: apiutil.Ptr(since),
+	}
+	if len(metadata) > 0 {
+		params.Metadata = &metadata
+	}
+	resp, err := client.LogCommitsWithResponse(ctx, u.Repository, u.Ref, params)
+	if ctx.Err() != nil {
+		DieFmt("Timed out waiting for branch %s to advance", u)
+	}
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	for _, commit := range resp.JSON200.Results {
+		if commit.Id != since {
+			return commit.Id
+		}
+	}
+	return ""
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchWaitCmd.Flags().String(branchWaitSinceFlagName, "", "commit ID the branch should advance from, defaults to the current commit of the branch")
+	branchWaitCmd.Flags().Duration(branchWaitTimeoutFlagName, 0, "maximum time to wait, 0 to wait with no limit")
+	branchWaitCmd.Flags().Duration(branchWaitPollIntervalFlagName, defaultPollInterval, "interval between checks of the branch")
+	branchWaitCmd.Flags().StringSlice(metaFlagName, []string{}, "wait for a commit whose metadata includes this key value pair in the form of key=value")
+	branchCmd.AddCommand(branchWaitCmd)
+}
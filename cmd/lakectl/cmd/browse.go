@@ -0,0 +1,474 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/diff"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	browsePageSize    = 100
+	browseListSize    = 20
+	browsePreviewSize = 4096
+)
+
+// errBrowseQuit is returned by the views of the browser when the user quits it
+var errBrowseQuit = errors.New("quit")
+
+const browseDetailsTemplate = `
+--------- Details ----------
+{{ .Details }}`
+
+// browseItem is an entry of a browser view, running action when selected
+type browseItem struct {
+	Label   string
+	Details string
+	action  func() error
+}
+
+// browseFetchFunc returns the page of items after after, the position of the next page and whether there are more
+type browseFetchFunc func(after string) ([]browseItem, string, bool, error)
+
+type browser struct {
+	ctx    context.Context
+	client *apigen.ClientWithResponses
+}
+
+var browseCmd = &cobra.Command{
+	Use:   "browse [repository URI]",
+	Short: "Browse repositories, branches, commits and objects in an interactive terminal UI",
+	Long: `Navigate repositories, branches, tags, commits and objects from the terminal, view the changes of commits
+and branches, compare branches and merge them.
+Use the arrow keys to move, enter to select, "/" to search the current list, and ctrl+c to quit.`,
+	Example: `lakectl browse
+lakectl browse lakefs://example-repo
+lakectl browse lakefs://example-repo/main`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !isTerminal {
+			Die("browse requires an interactive terminal", 1)
+		}
+		b := &browser{ctx: cmd.Context(), client: getClient()}
+		var err error
+		switch {
+		case len(args) == 0:
+			err = b.repositories()
+		default:
+			u, parseErr := uri.ParseWithBaseURI(args[0], baseURI)
+			if parseErr != nil {
+				DieFmt("repository URI %s", parseErr)
+			}
+			if u.Ref == "" {
+				err = b.repository(u.Repository)
+			} else {
+				err = b.ref(u.Repository, u.Ref, b.isBranch(u.Repository, u.Ref))
+			}
+		}
+		if err != nil && !errors.Is(err, errBrowseQuit) {
+			DieErr(err)
+		}
+	},
+}
+
+// choose shows items under label, returning the index of the selected item
+func (b *browser) choose(label string, items []browseItem, cursor int) (int, error) {
+	s := promptui.Select{
+		Label: label,
+		Items: items,
+		Size:  browseListSize,
+		Templates: &promptui.SelectTemplates{
+			Label:    "{{ . }}",
+			Active:   "▸ {{ .Label | cyan }}",
+			Inactive: "  {{ .Label }}",
+			Selected: "▸ {{ .Label | faint }}",
+			Details:  browseDetailsTemplate,
+		},
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(items[index].Label), strings.ToLower(input))
+		},
+		HideHelp: true,
+	}
+	i, _, err := s.RunCursorAt(cursor, 0)
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return 0, errBrowseQuit
+	}
+	return i, err
+}
+
+// list is a view of fixed items followed by the items returned by fetch, loaded a page at a time. It returns when the
+// user goes back.
+func (b *browser) list(label string, fixed []browseItem, fetch browseFetchFunc) error {
+	var (
+		fetched []browseItem
+		after   string
+		hasMore = fetch != nil
+	)
+	load := func() error {
+		items, next, more, err := fetch(after)
+		if err != nil {
+			return err
+		}
+		fetched = append(fetched, items...)
+		after, hasMore = next, more
+		return nil
+	}
+	if hasMore {
+		if err := load(); err != nil {
+			return err
+		}
+	}
+	cursor := 0
+	for {
+		items := append([]browseItem{{Label: ".."}}, fixed...)
+		items = append(items, fetched...)
+		if hasMore {
+			items = append(items, browseItem{Label: "(more)"})
+		}
+		i, err := b.choose(label, items, cursor)
+		if err != nil {
+			return err
+		}
+		cursor = i
+		switch {
+		case i == 0:
+			return nil
+		case hasMore && i == len(items)-1:
+			err = load()
+		case items[i].action != nil:
+			err = items[i].action()
+		}
+		if errors.Is(err, errBrowseQuit) {
+			return err
+		}
+		if err != nil {
+			Write("{{ . | red }}\n", err.Error())
+		}
+	}
+}
+
+// pause waits for the user to read the output of an action
+func (b *browser) pause() error {
+	p := promptui.Prompt{Label: "Press enter to continue"}
+	_, err := p.Run()
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return errBrowseQuit
+	}
+	return nil
+}
+
+func (b *browser) repositories() error {
+	return b.list("Repositories", nil, func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.ListRepositoriesWithResponse(b.ctx, &apigen.ListRepositoriesParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		items := make([]browseItem, 0, len(resp.JSON200.Results))
+		for _, repo := range resp.JSON200.Results {
+			id := repo.Id
+			items = append(items, browseItem{
+				Label: id,
+				Details: fmt.Sprintf("Storage namespace: %s\nDefault branch:    %s\nCreated:           %s",
+					repo.StorageNamespace, repo.DefaultBranch, time.Unix(repo.CreationDate, 0)),
+				action: func() error { return b.repository(id) },
+			})
+		}
+		return items, resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+func (b *browser) repository(repository string) error {
+	resp, err := b.client.GetRepositoryWithResponse(b.ctx, repository)
+	if err := RetrieveError(resp, err); err != nil {
+		return err
+	}
+	defaultBranch := resp.JSON200.DefaultBranch
+	return b.list("lakefs://"+repository, []browseItem{
+		{Label: "Branches", action: func() error { return b.branches(repository) }},
+		{Label: "Tags", action: func() error { return b.tags(repository) }},
+		{Label: "Default branch (" + defaultBranch + ")", action: func() error { return b.ref(repository, defaultBranch, true) }},
+	}, nil)
+}
+
+func (b *browser) isBranch(repository, ref string) bool {
+	resp, err := b.client.GetBranchWithResponse(b.ctx, repository, ref)
+	return err == nil && resp.JSON200 != nil
+}
+
+func (b *browser) fetchBranches(repository string, action func(branch string) error) browseFetchFunc {
+	return func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.ListBranchesWithResponse(b.ctx, repository, &apigen.ListBranchesParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		items := make([]browseItem, 0, len(resp.JSON200.Results))
+		for _, branch := range resp.JSON200.Results {
+			id := branch.Id
+			items = append(items, browseItem{
+				Label:   id,
+				Details: "Commit: " + branch.CommitId,
+				action:  func() error { return action(id) },
+			})
+		}
+		return items, resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	}
+}
+
+func (b *browser) branches(repository string) error {
+	return b.list("Branches of lakefs://"+repository, nil, b.fetchBranches(repository, func(branch string) error {
+		return b.ref(repository, branch, true)
+	}))
+}
+
+func (b *browser) tags(repository string) error {
+	return b.list("Tags of lakefs://"+repository, nil, func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.ListTagsWithResponse(b.ctx, repository, &apigen.ListTagsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		items := make([]browseItem, 0, len(resp.JSON200.Results))
+		for _, tag := range resp.JSON200.Results {
+			id := tag.Id
+			items = append(items, browseItem{
+				Label:   id,
+				Details: "Commit: " + tag.CommitId,
+				action:  func() error { return b.ref(repository, id, false) },
+			})
+		}
+		return items, resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+// ref is the view of a branch, tag or commit
+func (b *browser) ref(repository, ref string, isBranch bool) error {
+	items := []browseItem{
+		{Label: "Objects", action: func() error { return b.objects(repository, ref, "") }},
+		{Label: "Log", action: func() error { return b.log(repository, ref, nil) }},
+	}
+	if isBranch {
+		items = append(items,
+			browseItem{Label: "Uncommitted changes", action: func() error { return b.uncommitted(repository, ref) }},
+			browseItem{Label: "Compare with branch", action: func() error { return b.compare(repository, ref) }},
+			browseItem{Label: "Merge into branch", action: func() error { return b.merge(repository, ref) }},
+		)
+	}
+	return b.list(fmt.Sprintf("lakefs://%s/%s", repository, ref), items, nil)
+}
+
+func (b *browser) log(repository, ref string, objects []string) error {
+	label := fmt.Sprintf("Log of lakefs://%s/%s", repository, ref)
+	if len(objects) > 0 {
+		label += "/" + objects[0]
+	}
+	return b.list(label, nil, func(after string) ([]browseItem, string, bool, error) {
+		params := &apigen.LogCommitsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+		}
+		if len(objects) > 0 {
+			params.Objects = &objects
+		}
+		resp, err := b.client.LogCommitsWithResponse(b.ctx, repository, ref, params)
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		items := make([]browseItem, 0, len(resp.JSON200.Results))
+		for _, commit := range resp.JSON200.Results {
+			commit := commit
+			message, _, _ := strings.Cut(commit.Message, "\n")
+			items = append(items, browseItem{
+				Label: fmt.Sprintf("%.16s %s", commit.Id, message),
+				Details: fmt.Sprintf("ID:        %s\nCommitter: %s\nDate:      %s\nMessage:   %s",
+					commit.Id, commit.Committer, time.Unix(commit.CreationDate, 0), commit.Message),
+				action: func() error { return b.commit(repository, commit) },
+			})
+		}
+		return items, resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+func (b *browser) commit(repository string, commit apigen.Commit) error {
+	items := []browseItem{
+		{Label: "Objects", action: func() error { return b.objects(repository, commit.Id, "") }},
+		{Label: "Log", action: func() error { return b.log(repository, commit.Id, nil) }},
+	}
+	if len(commit.Parents) > 0 {
+		items = append(items, browseItem{Label: "Changes", action: func() error {
+			return b.changes(repository, commit.Parents[0], commit.Id, "two_dot")
+		}})
+	}
+	return b.list(fmt.Sprintf("Commit %s of lakefs://%s", commit.Id, repository), items, nil)
+}
+
+func (b *browser) objects(repository, ref, prefix string) error {
+	return b.list(fmt.Sprintf("lakefs://%s/%s/%s", repository, ref, prefix), nil, func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.ListObjectsWithResponse(b.ctx, repository, ref, &apigen.ListObjectsParams{
+			After:     apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount:    apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+			Delimiter: apiutil.Ptr(apigen.PaginationDelimiter(PathDelimiter)),
+			Prefix:    apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		items := make([]browseItem, 0, len(resp.JSON200.Results))
+		for _, stats := range resp.JSON200.Results {
+			stats := stats
+			name := strings.TrimPrefix(stats.Path, prefix)
+			if stats.PathType == "common_prefix" {
+				items = append(items, browseItem{
+					Label:  name,
+					action: func() error { return b.objects(repository, ref, stats.Path) },
+				})
+				continue
+			}
+			items = append(items, browseItem{
+				Label: name,
+				Details: fmt.Sprintf("Size:     %s\nModified: %s\nChecksum: %s",
+					humanBytes(apiutil.Value(stats.SizeBytes)), time.Unix(stats.Mtime, 0), stats.Checksum),
+				action: func() error { return b.object(repository, ref, stats) },
+			})
+		}
+		return items, resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+func (b *browser) object(repository, ref string, stats apigen.ObjectStats) error {
+	return b.list(fmt.Sprintf("lakefs://%s/%s/%s", repository, ref, stats.Path), []browseItem{
+		{Label: "Metadata", action: func() error {
+			Write(fsStatTemplate, &stats)
+			return b.pause()
+		}},
+		{Label: "Preview", action: func() error { return b.preview(repository, ref, stats.Path) }},
+		{Label: "Log", action: func() error { return b.log(repository, ref, []string{stats.Path}) }},
+	}, nil)
+}
+
+// preview prints the beginning of the object at path, if it is text
+func (b *browser) preview(repository, ref, path string) error {
+	resp, err := b.client.GetObject(b.ctx, repository, ref, &apigen.GetObjectParams{
+		Path:  path,
+		Range: apiutil.Ptr(fmt.Sprintf("bytes=0-%d", browsePreviewSize-1)),
+	})
+	if err := RetrieveError(resp, err); err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, browsePreviewSize))
+	if err != nil {
+		return err
+	}
+	// a range may split the last character
+	for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+		data = data[:len(data)-1]
+	}
+	if !utf8.Valid(data) {
+		fmt.Println("Binary object, no preview")
+	} else {
+		fmt.Println(string(data))
+	}
+	return b.pause()
+}
+
+func diffItems(results []apigen.Diff) []browseItem {
+	items := make([]browseItem, 0, len(results))
+	for _, d := range results {
+		action, _ := diff.Fmt(d.Type)
+		items = append(items, browseItem{Label: fmt.Sprintf("%s %s", action, d.Path)})
+	}
+	return items
+}
+
+func (b *browser) uncommitted(repository, branch string) error {
+	return b.list(fmt.Sprintf("Uncommitted changes of lakefs://%s/%s", repository, branch), nil, func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.DiffBranchWithResponse(b.ctx, repository, branch, &apigen.DiffBranchParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		return diffItems(resp.JSON200.Results), resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+// changes lists the changes between refs left and right, diffType is "two_dot" or "three_dot"
+func (b *browser) changes(repository, left, right, diffType string) error {
+	return b.list(fmt.Sprintf("Changes of %s from %s in lakefs://%s", right, left, repository), nil, func(after string) ([]browseItem, string, bool, error) {
+		resp, err := b.client.DiffRefsWithResponse(b.ctx, repository, left, right, &apigen.DiffRefsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(browsePageSize)),
+			Type:   apiutil.Ptr(diffType),
+		})
+		if err := RetrieveError(resp, err); err != nil {
+			return nil, "", false, err
+		}
+		return diffItems(resp.JSON200.Results), resp.JSON200.Pagination.NextOffset, resp.JSON200.Pagination.HasMore, nil
+	})
+}
+
+// compare lists the changes that merging branch into another branch would bring
+func (b *browser) compare(repository, branch string) error {
+	return b.list("Compare "+branch+" with", nil, b.fetchBranches(repository, func(other string) error {
+		return b.changes(repository, other, branch, "three_dot")
+	}))
+}
+
+func (b *browser) merge(repository, source string) error {
+	return b.list("Merge "+source+" into", nil, b.fetchBranches(repository, func(destination string) error {
+		if destination == source {
+			return fmt.Errorf("cannot merge %s into itself", source)
+		}
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Merge %s into %s", source, destination),
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			if errors.Is(err, promptui.ErrInterrupt) {
+				return errBrowseQuit
+			}
+			return nil
+		}
+		resp, err := b.client.MergeIntoBranchWithResponse(b.ctx, repository, source, destination, apigen.MergeIntoBranchJSONRequestBody{})
+		if resp != nil && resp.JSON409 != nil {
+			Write("{{ . | red }}\n", "Conflict found.")
+			return b.pause()
+		}
+		if err := RetrieveError(resp, err); err != nil {
+			return err
+		}
+		Write(mergeCreateTemplate, struct {
+			Merge  FromTo
+			Result *apigen.MergeResult
+		}{
+			Merge:  FromTo{FromRef: source, ToRef: destination},
+			Result: resp.JSON200,
+		})
+		return b.pause()
+	}))
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(browseCmd)
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
 	"github.com/treeverse/lakefs/pkg/uri"
 )
 
@@ -15,23 +20,45 @@ var errInvalidKeyValueFormat = errors.New(`invalid key/value pair - should be se
 const (
 	dateFlagName         = "epoch-time-seconds"
 	allowEmptyCommit     = "allow-empty-commit"
+	amendFlagName        = "amend"
+	commitPrefixFlagName = "prefix"
+	interactiveFlagName  = "interactive"
+	asyncFlagName        = "async"
+	commitSelectPageSize = 1000
+	commitSelectListSize = 20
 	commitCreateTemplate = `Commit for branch "{{.Branch.Ref}}" completed.
 
 ID: {{.Commit.Id|yellow}}
 Message: {{.Commit.Message}}
 Timestamp: {{.Commit.CreationDate|date}}
 Parents: {{.Commit.Parents|join ", "}}
-
+{{ with .Commit.Stats }}Objects: {{ .ObjectCount }} ({{ .SizeBytes|human_bytes }}), {{ .ObjectCountDelta | printf "%+d" }} ({{ .SizeBytesDelta|human_bytes_delta }}) from parent
+{{ end }}
 `
 )
 
 var commitCmd = &cobra.Command{
-	Use:               "commit <branch URI>",
-	Short:             "Commit changes on a given branch",
+	Use:   "commit <branch URI>",
+	Short: "Commit changes on a given branch",
+	Long: `Commit changes on a given branch.
+Use --prefix or --interactive to commit only some of the uncommitted changes, leaving the others uncommitted.
+Use --async to commit a large number of changes in the background, polling for the commit to complete.`,
+	Example: `lakectl commit lakefs://example-repo/main -m "ingest raw data" --prefix raw/
+lakectl commit lakefs://example-repo/main -m "fix report" -i
+lakectl commit lakefs://example-repo/main -m "daily ingest" --async`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
-		message, kvPairs := getCommitFlags(cmd)
+		amend := Must(cmd.Flags().GetBool(amendFlagName))
+		var message string
+		var kvPairs map[string]string
+		if amend {
+			// amending keeps the message and metadata of the head commit unless set
+			message = Must(cmd.Flags().GetString(commitMsgFlagName))
+			kvPairs = Must(getKV(cmd, metaFlagName))
+		} else {
+			message, kvPairs = getCommitFlags(cmd)
+		}
 		date := Must(cmd.Flags().GetInt64(dateFlagName))
 		emptyCommitBool := Must(cmd.Flags().GetBool(allowEmptyCommit))
 		datePtr := &date
@@ -39,26 +66,59 @@ var commitCmd = &cobra.Command{
 			datePtr = nil
 		}
 
+		prefixes := Must(cmd.Flags().GetStringSlice(commitPrefixFlagName))
+		interactive := Must(cmd.Flags().GetBool(interactiveFlagName))
+		if amend && (interactive || len(prefixes) > 0) {
+			DieFmt("cannot combine --%s with --%s or --%s", amendFlagName, commitPrefixFlagName, interactiveFlagName)
+		}
+		async := Must(cmd.Flags().GetBool(asyncFlagName))
+		if async && (amend || interactive || len(prefixes) > 0) {
+			DieFmt("cannot combine --%s with --%s, --%s or --%s", asyncFlagName, amendFlagName, commitPrefixFlagName, interactiveFlagName)
+		}
+
 		branchURI := MustParseBranchURI("branch URI", args[0])
 		fmt.Println("Branch:", branchURI)
+		client := getClient()
+
+		var paths []string
+		if interactive {
+			paths = selectUncommittedChanges(cmd.Context(), client, branchURI)
+			if len(paths) == 0 {
+				Die("No changes selected", 1)
+			}
+		}
 
 		// do commit
-		metadata := apigen.CommitCreation_Metadata{
-			AdditionalProperties: kvPairs,
+		var metadata *apigen.CommitCreation_Metadata
+		if !amend || len(kvPairs) > 0 {
+			metadata = &apigen.CommitCreation_Metadata{
+				AdditionalProperties: kvPairs,
+			}
 		}
-		client := getClient()
-		resp, err := client.CommitWithResponse(cmd.Context(), branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		body := apigen.CommitJSONRequestBody{
 			Message:    message,
-			Metadata:   &metadata,
+			Metadata:   metadata,
 			Date:       datePtr,
 			AllowEmpty: &emptyCommitBool,
-		})
-		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
-		if resp.JSON201 == nil {
-			Die("Bad response from server", 1)
+			Amend:      &amend,
+		}
+		if len(paths) > 0 {
+			body.Paths = &paths
+		}
+		if len(prefixes) > 0 {
+			body.Prefixes = &prefixes
+		}
+		var commit *apigen.Commit
+		if async {
+			commit = commitAsync(cmd.Context(), client, branchURI, body)
+		} else {
+			resp, err := client.CommitWithResponse(cmd.Context(), branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, body)
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+			if resp.JSON201 == nil {
+				Die("Bad response from server", 1)
+			}
+			commit = resp.JSON201
 		}
-
-		commit := resp.JSON201
 		Write(commitCreateTemplate, struct {
 			Branch *uri.URI
 			Commit *apigen.Commit
@@ -66,10 +126,121 @@ var commitCmd = &cobra.Command{
 	},
 }
 
+// commitAsync starts a commit in the background and polls its status until it completes
+func commitAsync(ctx context.Context, client apigen.ClientWithResponsesInterface, branchURI *uri.URI, body apigen.CommitJSONRequestBody) *apigen.Commit {
+	resp, err := client.CommitAsyncWithResponse(ctx, branchURI.Repository, branchURI.Ref, apigen.CommitAsyncJSONRequestBody(body))
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusAccepted)
+	if resp.JSON202 == nil {
+		Die("Bad response from server", 1)
+	}
+	taskID := resp.JSON202.Id
+	fmt.Println("Commit task:", taskID)
+
+	status, err := backoff.RetryWithData(func() (*apigen.CommitAsyncStatus, error) {
+		resp, err := client.CommitAsyncStatusWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.CommitAsyncStatusParams{
+			TaskId: taskID,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			return nil, backoff.Permanent(fmt.Errorf("commit status %w: %s", helpers.ErrRequestFailed, resp.Status()))
+		}
+		if !resp.JSON200.Done {
+			return nil, ErrTaskNotCompleted
+		}
+		return resp.JSON200, nil
+	}, backoff.WithContext(backoff.NewConstantBackOff(defaultPollInterval), ctx))
+	switch {
+	case err != nil:
+		DieErr(err)
+	case status.Error != nil:
+		DieFmt("Commit failed: %s", *status.Error)
+	case status.Commit == nil:
+		Die("Commit failed: no commit returned", 1)
+	}
+	return status.Commit
+}
+
+type commitSelectItem struct {
+	Label    string
+	Path     string
+	Selected bool
+}
+
+// selectUncommittedChanges lets the user pick uncommitted changes of the branch, returning the paths picked
+func selectUncommittedChanges(ctx context.Context, client apigen.ClientWithResponsesInterface, branchURI *uri.URI) []string {
+	var (
+		items []*commitSelectItem
+		after string
+	)
+	for {
+		resp, err := client.DiffBranchWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.DiffBranchParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(commitSelectPageSize)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, d := range resp.JSON200.Results {
+			items = append(items, &commitSelectItem{Label: d.Type + " " + d.Path, Path: d.Path})
+		}
+		if !resp.JSON200.Pagination.HasMore {
+			break
+		}
+		after = resp.JSON200.Pagination.NextOffset
+	}
+	if len(items) == 0 {
+		Die("No uncommitted changes", 1)
+	}
+
+	// the first item ends the selection, the others toggle the selection of a change
+	done := &commitSelectItem{Label: "Commit selected changes"}
+	cursor := 1
+	for {
+		s := promptui.Select{
+			Label: "Select changes to commit",
+			Items: append([]*commitSelectItem{done}, items...),
+			Size:  commitSelectListSize,
+			Templates: &promptui.SelectTemplates{
+				Label:    "{{ . }}",
+				Active:   `▸ {{ if .Path }}{{ if .Selected }}[x]{{ else }}[ ]{{ end }} {{ end }}{{ .Label | cyan }}`,
+				Inactive: `  {{ if .Path }}{{ if .Selected }}[x]{{ else }}[ ]{{ end }} {{ end }}{{ .Label }}`,
+				Selected: `▸ {{ .Label | faint }}`,
+			},
+			HideHelp:     true,
+			HideSelected: true,
+		}
+		i, _, err := s.RunCursorAt(cursor, 0)
+		if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+			Die("Commit canceled", 1)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		if i == 0 {
+			break
+		}
+		items[i-1].Selected = !items[i-1].Selected
+		cursor = i
+	}
+
+	var paths []string
+	for _, item := range items {
+		if item.Selected {
+			paths = append(paths, item.Path)
+		}
+	}
+	return paths
+}
+
 //nolint:gochecknoinits
 func init() {
 	commitCmd.Flags().Int64(dateFlagName, -1, "create commit with a custom unix epoch date in seconds")
 	commitCmd.Flags().Bool(allowEmptyCommit, false, "allow a commit with no changes")
+	commitCmd.Flags().Bool(amendFlagName, false, "replace the head commit of the branch with a commit of its changes and the uncommitted changes, keeping its message and metadata unless set")
+	commitCmd.Flags().StringSlice(commitPrefixFlagName, nil, "commit only the uncommitted changes under these prefixes, leaving the others uncommitted")
+	commitCmd.Flags().BoolP(interactiveFlagName, "i", false, "select the uncommitted changes to commit, leaving the others uncommitted")
+	commitCmd.Flags().Bool(asyncFlagName, false, "commit in the background and poll for the commit to complete, instead of waiting on a single request")
 	if err := commitCmd.Flags().MarkHidden(dateFlagName); err != nil {
 		DieErr(err)
 	}
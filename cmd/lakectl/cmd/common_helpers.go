@@ -82,6 +82,19 @@ type Pagination struct {
 	After   string
 }
 
+func humanBytes(b int64) string {
+	var unit int64 = 1000
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := unit, 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "kMGTPE"[exp])
+}
+
 func WriteTo(tpl string, data interface{}, w io.Writer) {
 	templ := template.New("output")
 	templ.Funcs(template.FuncMap{
@@ -121,17 +134,18 @@ func WriteTo(tpl string, data interface{}, w io.Writer) {
 			return ""
 		},
 		"lower": strings.ToLower,
-		"human_bytes": func(b int64) string {
-			var unit int64 = 1000
-			if b < unit {
-				return fmt.Sprintf("%d B", b)
+		"value": func(s *string) string {
+			if s == nil {
+				return ""
 			}
-			div, exp := unit, 0
-			for n := b / unit; n >= unit; n /= unit {
-				div *= unit
-				exp++
+			return *s
+		},
+		"human_bytes": humanBytes,
+		"human_bytes_delta": func(b int64) string {
+			if b < 0 {
+				return "-" + humanBytes(-b)
 			}
-			return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "kMGTPE"[exp])
+			return "+" + humanBytes(b)
 		},
 		"join": func(sep string, args []string) string {
 			return strings.Join(args, sep)
@@ -309,6 +323,23 @@ func MustParseBranchURI(name, s string) *uri.URI {
 	return u
 }
 
+// MustParsePrefixURI parses a ref URI with an optional path part
+func MustParsePrefixURI(name, s string) *uri.URI {
+	u, err := uri.ParseWithBaseURI(s, baseURI)
+	if err != nil {
+		DieFmt("%s %s", name, err)
+	}
+	if u.Path == nil {
+		err = u.ValidateRef()
+	} else {
+		err = u.ValidateFullyQualified()
+	}
+	if err != nil {
+		DieFmt("%s %s", name, err)
+	}
+	return u
+}
+
 func MustParsePathURI(name, s string) *uri.URI {
 	u, err := uri.ParseWithBaseURI(s, baseURI)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/uri"
 )
 
 // configCmd represents the config command
@@ -26,6 +27,9 @@ var configCmd = &cobra.Command{
 			viper.SetConfigFile(filepath.Join(home, ".lakectl.yaml"))
 		}
 		fmt.Printf("Config file %s will be used\n", viper.ConfigFileUsed())
+		if profile != "" {
+			fmt.Printf("Profile %s will be configured\n", profile)
+		}
 
 		// get user input
 		questions := []struct {
@@ -55,9 +59,25 @@ var configCmd = &cobra.Command{
 					},
 				},
 			},
+			{
+				Key: "base_uri",
+				Prompt: promptui.Prompt{
+					Label: "Default base URI, optional (e.g. lakefs://example-repo/main)",
+					Validate: func(rawURI string) error {
+						if rawURI == "" {
+							return nil
+						}
+						_, err := uri.Parse(rawURI)
+						return err
+					},
+				},
+			},
 		}
 
 		for _, question := range questions {
+			if profile != "" {
+				question.Key = profileKey(profile, question.Key)
+			}
 			question.Prompt.Default = viper.GetString(question.Key)
 			val, err := question.Prompt.Run()
 			if err != nil {
@@ -21,12 +21,13 @@ const (
 	minDiffPageSize = 50
 	maxDiffPageSize = 1000
 
-	twoWayFlagName = "two-way"
+	twoWayFlagName      = "two-way"
+	uncommittedFlagName = "uncommitted"
 )
 
 var diffCmd = &cobra.Command{
 	Use:   `diff <ref URI> [ref URI]`,
-	Short: "Show changes between two commits, or the currently uncommitted changes",
+	Short: "Show changes between two commits, two directories, or the currently uncommitted changes",
 	Example: fmt.Sprintf(`
 	lakectl diff lakefs://example-repo/example-branch
 	Show uncommitted changes in example-branch.
@@ -42,7 +43,14 @@ var diffCmd = &cobra.Command{
 	Uncommitted changes are not shown.
 
 	lakectl diff --%s lakefs://example-repo/main lakefs://example-repo/dev$
-	Show changes between the tip of the main and the dev branch, including uncommitted changes on dev.`, twoWayFlagName, twoWayFlagName),
+	Show changes between the tip of the main and the dev branch, including uncommitted changes on dev.
+
+	lakectl diff --%s lakefs://example-repo/v1.0 lakefs://example-repo/dev
+	Show changes between the v1.0 tag and the dev branch, including uncommitted changes on dev.
+
+	lakectl diff lakefs://example-repo/main/tables/events/ lakefs://example-repo/main/backfill/events/
+	Compare the objects under two directories, here of the same branch, by their path relative to each
+	directory and their checksum. Uncommitted objects of branches are compared.`, twoWayFlagName, twoWayFlagName, uncommittedFlagName),
 
 	Args: cobra.RangeArgs(diffCmdMinArgs, diffCmdMaxArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -57,17 +65,34 @@ var diffCmd = &cobra.Command{
 			// got one arg ref: uncommitted changes diff
 			branchURI := MustParseBranchURI("branch URI", args[0])
 			fmt.Println("Ref:", branchURI)
-			printDiffBranch(cmd.Context(), client, branchURI.Repository, branchURI.Ref)
+			printDiffBranch(cmd.Context(), client, branchURI.Repository, branchURI.Ref, "")
+			return
+		}
+
+		if left, right := MustParsePrefixURI("left URI", args[0]), MustParsePrefixURI("right URI", args[1]); left.GetPath() != "" || right.GetPath() != "" {
+			// got a path: compare the objects of two directories
+			fmt.Printf("Left: %s\nRight: %s\n", left, right)
+			printDiffPrefixes(cmd.Context(), client, left, right)
 			return
 		}
 
 		twoWay := Must(cmd.Flags().GetBool(twoWayFlagName))
+		uncommitted := Must(cmd.Flags().GetBool(uncommittedFlagName))
 		leftRefURI := MustParseRefURI("left ref", args[0])
-		rightRefURI := MustParseRefURI("right ref", args[1])
+		var rightRefURI *uri.URI
+		if uncommitted {
+			rightRefURI = MustParseBranchURI("right branch", args[1])
+		} else {
+			rightRefURI = MustParseRefURI("right ref", args[1])
+		}
 		fmt.Printf("Left ref: %s\nRight ref: %s\n", leftRefURI, rightRefURI)
 		if leftRefURI.Repository != rightRefURI.Repository {
 			Die("both references must belong to the same repository", 1)
 		}
+		if uncommitted {
+			printDiffBranch(cmd.Context(), client, rightRefURI.Repository, rightRefURI.Ref, leftRefURI.Ref)
+			return
+		}
 		printDiffRefs(cmd.Context(), client, leftRefURI, rightRefURI, twoWay)
 	},
 }
@@ -84,13 +109,21 @@ func (p *pageSize) Next() int {
 	return p.Value()
 }
 
-func printDiffBranch(ctx context.Context, client apigen.ClientWithResponsesInterface, repository string, branch string) {
-	var after string
+// printDiffBranch prints the uncommitted changes of branch, compared to base or to the branch HEAD when base is empty
+func printDiffBranch(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, branch, base string) {
+	var (
+		after    string
+		baseFlag *string
+	)
+	if base != "" {
+		baseFlag = &base
+	}
 	pageSize := pageSize(minDiffPageSize)
 	for {
 		resp, err := client.DiffBranchWithResponse(ctx, repository, branch, &apigen.DiffBranchParams{
 			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
 			Amount: apiutil.Ptr(apigen.PaginationAmount(pageSize)),
+			Base:   baseFlag,
 		})
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
 		if resp.JSON200 == nil {
@@ -123,6 +156,20 @@ func printDiffRefs(ctx context.Context, client apigen.ClientWithResponsesInterfa
 	}
 }
 
+func printDiffPrefixes(ctx context.Context, client apigen.ClientWithResponsesInterface, left, right *uri.URI) {
+	diffs := make(chan apigen.Diff, maxDiffPageSize)
+	var wg errgroup.Group
+	wg.Go(func() error {
+		return diff.StreamPrefixDiffs(ctx, client, left, right, diffs)
+	})
+	for d := range diffs {
+		FmtDiff(d, true)
+	}
+	if err := wg.Wait(); err != nil {
+		DieErr(err)
+	}
+}
+
 func FmtDiff(d apigen.Diff, withDirection bool) {
 	action, color := diff.Fmt(d.Type)
 
@@ -140,6 +187,7 @@ func FmtDiff(d apigen.Diff, withDirection bool) {
 //nolint:gochecknoinits
 func init() {
 	diffCmd.Flags().Bool(twoWayFlagName, false, "Use two-way diff: show difference between the given refs, regardless of a common ancestor.")
+	diffCmd.Flags().Bool(uncommittedFlagName, false, "Compare the left ref to the right branch including its uncommitted changes.")
 
 	rootCmd.AddCommand(diffCmd)
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const (
+	fsCommentMessageFlagName = "message"
+	fsCommentReplyToFlagName = "reply-to"
+
+	fsCommentCreateTemplate = `Comment {{ .Id | yellow }} added to {{ .Path | yellow }} at {{ .Ref }} ({{ .CommitId }})
+`
+)
+
+var fsCommentCmd = &cobra.Command{
+	Use:   "comment <path URI>",
+	Short: "Comment on an object",
+	Long: `Comment on the object at a ref, or reply to a comment on it with --reply-to. Comments on an object are listed
+at any ref by "lakectl fs stat --comments".`,
+	Example:           "lakectl fs comment " + myRepoExample + "/" + myBranchExample + "/data/file.csv -m \"null counts look off\"",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		message := Must(cmd.Flags().GetString(fsCommentMessageFlagName))
+		replyTo := Must(cmd.Flags().GetString(fsCommentReplyToFlagName))
+		if message == "" {
+			DieFmt("comment message is required, use --%s", fsCommentMessageFlagName)
+		}
+
+		body := apigen.CreateObjectCommentJSONRequestBody{Body: message}
+		if replyTo != "" {
+			body.ParentId = apiutil.Ptr(replyTo)
+		}
+		client := getClient()
+		resp, err := client.CreateObjectCommentWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.CreateObjectCommentParams{
+			Path: *pathURI.Path,
+		}, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(fsCommentCreateTemplate, resp.JSON201)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsCommentCmd.Flags().StringP(fsCommentMessageFlagName, "m", "", "comment message")
+	fsCommentCmd.Flags().String(fsCommentReplyToFlagName, "", "ID of the comment to reply to")
+
+	fsCmd.AddCommand(fsCommentCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var fsDiffContentCmd = &cobra.Command{
+	Use:   "diff-content <left path URI> <right path URI>",
+	Short: "Show a unified diff of the content of two text objects",
+	Long: `Show a unified diff of the content of two objects in the same repository, typically the same path on two refs.
+A missing object is compared as empty. Only text objects up to 1 MiB are compared, the content type is detected from
+the object data.`,
+	Example:           "lakectl fs diff-content " + myRepoExample + "/main/conf/app.yaml " + myRepoExample + "/" + myBranchExample + "/conf/app.yaml",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		contextLines := Must(cmd.Flags().GetInt("context"))
+		left := MustParsePathURI("left path URI", args[0])
+		right := MustParsePathURI("right path URI", args[1])
+		if left.Repository != right.Repository {
+			Die("both paths must belong to the same repository", 1)
+		}
+
+		client := getClient()
+		resp, err := client.DiffObjectContentWithResponse(cmd.Context(), left.Repository, left.Ref, right.Ref, &apigen.DiffObjectContentParams{
+			Path:      left.GetPath(),
+			RightPath: right.Path,
+			Context:   &contextLines,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		diff := strings.TrimSuffix(resp.JSON200.Diff, "\n")
+		if diff == "" {
+			return
+		}
+		for _, line := range strings.Split(diff, "\n") {
+			_, _ = os.Stdout.WriteString(fmtDiffContentLine(line) + "\n")
+		}
+	},
+}
+
+func fmtDiffContentLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return text.Bold.Sprint(line)
+	case strings.HasPrefix(line, "@@"):
+		return text.FgHiCyan.Sprint(line)
+	case strings.HasPrefix(line, "+"):
+		return text.FgHiGreen.Sprint(line)
+	case strings.HasPrefix(line, "-"):
+		return text.FgHiRed.Sprint(line)
+	default:
+		return line
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsDiffContentCmd.Flags().Int("context", 3, "number of unchanged lines shown around each change")
+	fsCmd.AddCommand(fsDiffContentCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	fsDuDepthFlagName = "depth"
+	fsDuBytesFlagName = "bytes"
+)
+
+const fsDuTemplate = `{{ . | table -}}`
+
+var fsDuCmd = &cobra.Command{
+	Use:   "du <path URI>",
+	Short: "Show the logical size and object count of each directory under a given path",
+	Long: `Show the logical size and object count of each directory under a given path, down to the given depth.
+Sizes are aggregated by the server and include the objects at any depth under a directory. Directories are listed
+deepest first, with the total of the path last. The server scans a limited number of objects, and a warning
+shows when the sizes leave out objects beyond it.`,
+	Example:           "lakectl fs du lakefs://example-repo/main/raw/ --depth 2",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		depth := Must(cmd.Flags().GetInt(fsDuDepthFlagName))
+		inBytes := Must(cmd.Flags().GetBool(fsDuBytesFlagName))
+		client := getClient()
+
+		prefix := pathURI.GetPath()
+		if prefix != "" && !strings.HasSuffix(prefix, PathDelimiter) {
+			prefix += PathDelimiter
+		}
+		resp, err := client.GetObjectTreeWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.GetObjectTreeParams{
+			Prefix:    (*apigen.PaginationPrefix)(swag.String(prefix)),
+			Delimiter: swag.String(PathDelimiter),
+			Depth:     swag.Int(depth),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		size := humanBytes
+		if inBytes {
+			size = func(b int64) string { return strconv.FormatInt(b, 10) }
+		}
+		refURI := &uri.URI{Repository: pathURI.Repository, Ref: pathURI.Ref}
+		rows := make([][]interface{}, 0)
+		var walk func(node apigen.ObjectTree)
+		walk = func(node apigen.ObjectTree) {
+			if node.Children != nil {
+				for _, child := range *node.Children {
+					walk(child)
+				}
+			}
+			rows = append(rows, []interface{}{size(node.SizeBytes), node.ObjectCount, refURI.String() + uri.PathSeparator + node.Path})
+		}
+		walk(*resp.JSON200)
+		Write(fsDuTemplate, &Table{
+			Headers: []interface{}{"Size", "Objects", "Path"},
+			Rows:    rows,
+		})
+		warnTreeTruncated(resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsDuCmd.Flags().Int(fsDuDepthFlagName, 1, "number of directory levels to show")
+	fsDuCmd.Flags().Bool(fsDuBytesFlagName, false, "show sizes in bytes rather than in human-readable units")
+	fsCmd.AddCommand(fsDuCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	fsGetCmdMinArgs  = 1
+	fsGetCmdMaxArgs  = 2
+	parallelFlagName = "parallel"
+)
+
+var fsGetCmd = &cobra.Command{
+	Use:   "get <path URI> [<destination path>]",
+	Short: "Download a single object, optionally in parallel range requests",
+	Long: `Download a single object to a local file. With --parallel N, parts of the object are downloaded in N
+concurrent range requests and the file is verified against the object checksum, which is faster and more robust
+than a single request for large objects.`,
+	Example:           "lakectl fs get " + myRepoExample + "/main/data/large.parquet --parallel 8",
+	Args:              cobra.RangeArgs(fsGetCmdMinArgs, fsGetCmdMaxArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		src, dest := getSyncArgs(args, true, false)
+		client := getClient()
+		preSignMode := getPresignMode(cmd, client)
+		partSize := Must(cmd.Flags().GetInt64(partSizeFlagName))
+		if partSize < helpers.MinDownloadPartSize {
+			DieFmt("part size must be at least %d bytes", helpers.MinDownloadPartSize)
+		}
+		parallel := Must(cmd.Flags().GetInt(parallelFlagName))
+		if parallel < 1 {
+			DieFmt("Invalid value for parallel (%d), minimum is 1.\n", parallel)
+		}
+		remotePath := src.GetPath()
+		if remotePath == "" || strings.HasSuffix(remotePath, uri.PathSeparator) {
+			DieFmt("path URI must be an object: %s", src)
+		}
+
+		// if dest is a directory, add the file name
+		if s, _ := os.Stat(dest); s != nil && s.IsDir() {
+			dest += uri.PathSeparator
+		}
+		if strings.HasSuffix(dest, uri.PathSeparator) {
+			dest += filepath.Base(remotePath)
+		}
+
+		d := helpers.NewDownloader(client, preSignMode.Enabled)
+		d.PartSize = partSize
+		d.Concurrency = parallel
+		if err := d.Download(cmd.Context(), *src, dest); err != nil {
+			DieErr(err)
+		}
+		fmt.Printf("download: %s to %s\n", src.String(), dest)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	withPresignFlag(fsGetCmd)
+	fsGetCmd.Flags().Int64(partSizeFlagName, helpers.DefaultDownloadPartSize, "part size in bytes for parallel download")
+	fsGetCmd.Flags().Int(parallelFlagName, 1, "number of parts of the object to download concurrently using range requests, 1 to download it in a single request")
+	fsCmd.AddCommand(fsGetCmd)
+}
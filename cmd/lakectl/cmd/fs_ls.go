@@ -18,6 +18,7 @@ var fsLsCmd = &cobra.Command{
 		client := getClient()
 		pathURI := MustParsePathURI("path URI", args[0])
 		recursive := Must(cmd.Flags().GetBool(recursiveFlagName))
+		storageClass := Must(cmd.Flags().GetString("storage-class"))
 		prefix := *pathURI.Path
 
 		// prefix we need to trim in ls output (non-recursive)
@@ -38,6 +39,9 @@ var fsLsCmd = &cobra.Command{
 				After:     apiutil.Ptr(apigen.PaginationAfter(from)),
 				Delimiter: &paramsDelimiter,
 			}
+			if storageClass != "" {
+				params.StorageClass = &storageClass
+			}
 			resp, err := client.ListObjectsWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, params)
 			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
 			if resp.JSON200 == nil {
@@ -66,6 +70,7 @@ var fsLsCmd = &cobra.Command{
 //nolint:gochecknoinits
 func init() {
 	withRecursiveFlag(fsLsCmd, "list all objects under the specified path")
+	fsLsCmd.Flags().String("storage-class", "", "list only objects of this storage class")
 	fsCmd.AddCommand(fsLsCmd)
 }
 
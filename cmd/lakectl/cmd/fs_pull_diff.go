@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/diff"
+	"github.com/treeverse/lakefs/pkg/local"
+	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/sync/errgroup"
+)
+
+var fsPullDiffCmd = &cobra.Command{
+	Use:   "pull-diff <from path URI> <to path URI> <directory>",
+	Short: "Download only the objects that changed between two refs into a local directory",
+	Long: `Update a local directory holding a copy of a lakeFS path at one ref to the same path at another ref. Only objects
+that were added or changed between the refs are downloaded, and objects removed between them are deleted locally.
+Other local files are left untouched, so local changes to files that did not change between the refs are kept.`,
+	Example: `lakectl fs pull-diff lakefs://example-repo/v1/data/ lakefs://example-repo/v2/data/ ./data`,
+	Args:    cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun := Must(cmd.Flags().GetBool("dry-run"))
+		from := MustParsePathURI("from path URI", args[0])
+		to := MustParsePathURI("to path URI", args[1])
+		if from.Repository != to.Repository {
+			DieFmt("both path URIs must be in the same repository")
+		}
+		prefix := to.GetPath()
+		if prefix != "" && !strings.HasSuffix(prefix, uri.PathSeparator) {
+			prefix += uri.PathSeparator
+		}
+		if fromPrefix := from.GetPath(); fromPrefix != prefix && fromPrefix+uri.PathSeparator != prefix {
+			DieFmt("both path URIs must have the same path")
+		}
+		to.Path = &prefix
+		localPath := Must(filepath.Abs(Must(homedir.Expand(args[2]))))
+		stat, err := os.Stat(localPath)
+		if err != nil {
+			DieErr(err)
+		}
+		if !stat.IsDir() {
+			DieFmt("'%s' is not a directory", localPath)
+		}
+
+		ctx := cmd.Context()
+		client := getClient()
+		d := make(chan apigen.Diff, maxDiffPageSize)
+		var wg errgroup.Group
+		wg.Go(func() error {
+			// compare the contents of the refs, not the changes since their merge base
+			return diff.StreamRepositoryDiffs(ctx, client, from, to, prefix, d, true)
+		})
+		c := make(chan *local.Change, filesChanSize)
+		wg.Go(func() error {
+			defer close(c)
+			for dif := range d {
+				relPath := strings.TrimPrefix(dif.Path, prefix)
+				// skip directory markers
+				if dif.PathType != "object" || relPath == "" || strings.HasSuffix(relPath, uri.PathSeparator) {
+					continue
+				}
+				c <- &local.Change{
+					Source: local.ChangeSourceRemote,
+					Path:   relPath,
+					Type:   local.ChangeTypeFromString(dif.Type),
+				}
+			}
+			return nil
+		})
+
+		if dryRun {
+			var actions []fsSyncAction
+			for change := range c {
+				action := fsSyncAction{Path: change.Path, Action: "download"}
+				if change.Type == local.ChangeTypeRemoved {
+					action.Action = "delete"
+				}
+				actions = append(actions, action)
+			}
+			if err := wg.Wait(); err != nil {
+				DieErr(err)
+			}
+			Write(fsSyncDryRunTemplate, actions)
+			return
+		}
+
+		s := local.NewSyncManager(ctx, client, getSyncFlags(cmd, client))
+		err = s.Sync(localPath, to, c)
+		if err != nil {
+			DieErr(err)
+		}
+		if err := wg.Wait(); err != nil {
+			DieErr(err)
+		}
+		Write(localSummaryTemplate, struct {
+			Operation string
+			local.Tasks
+		}{
+			Operation: "Pull diff",
+			Tasks:     s.Summary(),
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsPullDiffCmd.Flags().Bool("dry-run", false, "show the files that would be downloaded or deleted, without changing them")
+	withSyncFlags(fsPullDiffCmd)
+
+	fsCmd.AddCommand(fsPullDiffCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const fsRestoreTemplate = `{{ range $val := . -}}
+{{ $val.Status | printf "%-12s" }} {{ $val.StorageClass | printf "%-14s" }} {{ $val.Expiry | printf "%-29s" }} {{ $val.Path | yellow }}
+{{ end -}}
+`
+
+type restoreStatusRow struct {
+	Path         string
+	Status       string
+	StorageClass string
+	Expiry       string
+}
+
+var fsRestoreCmd = &cobra.Command{
+	Use:   "restore <path URI>",
+	Short: "Restore archived objects from cold storage",
+	Long: `Initiate a restore of the objects under the path that are archived on the underlying storage, and show the
+restore status of each object. Restores complete asynchronously on the underlying storage, run with --status to
+follow their progress.`,
+	Example:           "lakectl fs restore lakefs://example-repo/main/archive/ --days 7 --tier Bulk",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		days := Must(cmd.Flags().GetInt("days"))
+		tier := Must(cmd.Flags().GetString("tier"))
+		statusOnly := Must(cmd.Flags().GetBool("status"))
+		if days <= 0 {
+			DieFmt("days must be positive")
+		}
+		client := getClient()
+
+		prefix := apigen.PaginationPrefix(*pathURI.Path)
+		var from string
+		for {
+			var results *apigen.ObjectRestoreList
+			if statusOnly {
+				resp, err := client.GetObjectsRestoreStatusWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.GetObjectsRestoreStatusParams{
+					Prefix: &prefix,
+					After:  apiutil.Ptr(apigen.PaginationAfter(from)),
+				})
+				DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+				results = resp.JSON200
+			} else {
+				body := apigen.RestoreObjectsJSONRequestBody{Days: days}
+				if tier != "" {
+					body.Tier = &tier
+				}
+				resp, err := client.RestoreObjectsWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.RestoreObjectsParams{
+					Prefix: &prefix,
+					After:  apiutil.Ptr(apigen.PaginationAfter(from)),
+				}, body)
+				DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+				results = resp.JSON200
+			}
+			if results == nil {
+				Die("Bad response from server", 1)
+			}
+
+			rows := make([]restoreStatusRow, 0, len(results.Results))
+			for _, result := range results.Results {
+				row := restoreStatusRow{
+					Path:         result.Path,
+					Status:       result.Status,
+					StorageClass: apiutil.Value(result.StorageClass),
+				}
+				if result.Expiry != nil {
+					row.Expiry = time.Unix(*result.Expiry, 0).String()
+				}
+				rows = append(rows, row)
+			}
+			Write(fsRestoreTemplate, rows)
+
+			if !results.Pagination.HasMore {
+				break
+			}
+			from = results.Pagination.NextOffset
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsRestoreCmd.Flags().Int("days", 1, "number of days the restored copy of each object is kept before it expires")
+	fsRestoreCmd.Flags().String("tier", "", "retrieval tier of the restores (Expedited, Standard or Bulk on S3; High or Standard on Azure)")
+	fsRestoreCmd.Flags().Bool("status", false, "only show the restore status of the objects, without initiating restores")
+
+	fsCmd.AddCommand(fsRestoreCmd)
+}
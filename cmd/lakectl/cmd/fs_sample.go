@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const (
+	fsSampleModeFlagName   = "mode"
+	fsSampleLimitFlagName  = "limit"
+	fsSampleFormatFlagName = "format"
+
+	fsSampleDefaultLimit = 20
+)
+
+const fsSampleTemplate = `{{ .Table | table }}`
+
+var fsSampleCmd = &cobra.Command{
+	Use:   "sample <path URI>",
+	Short: "Show a sample of the records of a CSV, JSON lines or Parquet object",
+	Long: `Show a sample of the records of a CSV, JSON lines or Parquet object.
+Only the parts of the object the sample is taken from are read, so large objects can be previewed without downloading them.
+The format of the object is detected from the extension of its path unless --format is given.`,
+	Example:           "lakectl fs sample --mode random --limit 50 " + myRepoExample + "/main/events/2024/part-0001.parquet",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		mode := Must(cmd.Flags().GetString(fsSampleModeFlagName))
+		limit := Must(cmd.Flags().GetInt(fsSampleLimitFlagName))
+		format := Must(cmd.Flags().GetString(fsSampleFormatFlagName))
+		isJSON := Must(cmd.Flags().GetBool(jsonFlagName))
+		client := getClient()
+
+		params := &apigen.SampleObjectParams{
+			Path:  *pathURI.Path,
+			Mode:  apiutil.Ptr(mode),
+			Limit: apiutil.Ptr(limit),
+		}
+		if format != "" {
+			params.Format = apiutil.Ptr(format)
+		}
+		resp, err := client.SampleObjectWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, params)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		sample := resp.JSON200
+		if isJSON {
+			Write("{{ . | json }}", sample)
+			return
+		}
+
+		headers := make([]interface{}, len(sample.Columns))
+		for i, column := range sample.Columns {
+			headers[i] = column
+		}
+		rows := make([][]interface{}, len(sample.Rows))
+		for i, row := range sample.Rows {
+			rows[i] = make([]interface{}, len(sample.Columns))
+			for j, column := range sample.Columns {
+				if value, ok := row.Get(column); ok && value != nil {
+					rows[i][j] = value
+				} else {
+					rows[i][j] = ""
+				}
+			}
+		}
+		Write(fsSampleTemplate, struct{ Table *Table }{&Table{Headers: headers, Rows: rows}})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsSampleCmd.Flags().String(fsSampleModeFlagName, "head", "records to sample: head, tail or random")
+	fsSampleCmd.Flags().Int(fsSampleLimitFlagName, fsSampleDefaultLimit, "maximal number of records to show")
+	fsSampleCmd.Flags().String(fsSampleFormatFlagName, "", "format of the object: csv, jsonl or parquet (default: by the extension of its path)")
+	fsSampleCmd.Flags().BoolP(jsonFlagName, "p", false, "show the sample as JSON")
+	fsCmd.AddCommand(fsSampleCmd)
+}
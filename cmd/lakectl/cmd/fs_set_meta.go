@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const (
+	fsSetMetaContentTypeFlagName = "content-type"
+	fsSetMetaMergeFlagName       = "merge"
+)
+
+var fsSetMetaCmd = &cobra.Command{
+	Use:   "set-meta <path URI>",
+	Short: "Update the user metadata and content type of an object without uploading it again",
+	Long: `Stage the object with its user metadata or content type replaced. The staged object points at the same data,
+nothing is uploaded. Without --merge, the user metadata set by --meta replaces all user metadata of the object.`,
+	Example:           "lakectl fs set-meta " + myRepoExample + "/" + myBranchExample + "/data/file.csv --meta owner=analytics --content-type text/csv",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		kvPairs, err := getKV(cmd, metaFlagName)
+		if err != nil {
+			DieErr(err)
+		}
+		contentType := Must(cmd.Flags().GetString(fsSetMetaContentTypeFlagName))
+		merge := Must(cmd.Flags().GetBool(fsSetMetaMergeFlagName))
+		setMeta := cmd.Flags().Changed(metaFlagName)
+		if !setMeta && contentType == "" {
+			DieFmt("nothing to update, use --%s or --%s", metaFlagName, fsSetMetaContentTypeFlagName)
+		}
+
+		client := getClient()
+		body := apigen.UpdateObjectMetadataJSONRequestBody{}
+		if setMeta {
+			if merge {
+				statResp, err := client.StatObjectWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.StatObjectParams{
+					Path:         *pathURI.Path,
+					UserMetadata: swag.Bool(true),
+				})
+				DieOnErrorOrUnexpectedStatusCode(statResp, err, http.StatusOK)
+				if statResp.JSON200 == nil {
+					Die("Bad response from server", 1)
+				}
+				if statResp.JSON200.Metadata != nil {
+					for k, v := range statResp.JSON200.Metadata.AdditionalProperties {
+						if _, ok := kvPairs[k]; !ok {
+							kvPairs[k] = v
+						}
+					}
+				}
+			}
+			body.UserMetadata = &apigen.ObjectUserMetadata{AdditionalProperties: kvPairs}
+		}
+		if contentType != "" {
+			body.ContentType = &contentType
+		}
+		resp, err := client.UpdateObjectMetadataWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.UpdateObjectMetadataParams{
+			Path: *pathURI.Path,
+		}, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(fsStatTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	withMetadataFlag(fsSetMetaCmd)
+	fsSetMetaCmd.Flags().String(fsSetMetaContentTypeFlagName, "", "content type of the object")
+	fsSetMetaCmd.Flags().Bool(fsSetMetaMergeFlagName, false, "keep the current user metadata keys not set by --"+metaFlagName)
+
+	fsCmd.AddCommand(fsSetMetaCmd)
+}
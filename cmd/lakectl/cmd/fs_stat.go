@@ -1,13 +1,32 @@
 package cmd
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
 )
 
+const (
+	fsStatDetailsFlagName  = "details"
+	fsStatCommentsFlagName = "comments"
+)
+
+// objectDetails is an object with the commit that introduced its current version and its underlying storage class
+type objectDetails struct {
+	*apigen.ObjectStats
+	Commit      *apigen.Commit
+	Uncommitted bool
+	// UnderlyingStorageClass is the current storage class of the physical object, which lifecycle rules of the
+	// underlying storage may have changed since it was written
+	UnderlyingStorageClass *string
+}
+
 var fsStatCmd = &cobra.Command{
 	Use:               "stat <path URI>",
 	Short:             "View object metadata",
@@ -15,6 +34,8 @@ var fsStatCmd = &cobra.Command{
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := MustParsePathURI("path URI", args[0])
+		details := Must(cmd.Flags().GetBool(fsStatDetailsFlagName))
+		comments := Must(cmd.Flags().GetBool(fsStatCommentsFlagName))
 		client := getClient()
 		preSignMode := getPresignMode(cmd, client)
 
@@ -27,11 +48,114 @@ var fsStatCmd = &cobra.Command{
 		if resp.JSON200 == nil {
 			Die("Bad response from server", 1)
 		}
-
-		Write(fsStatTemplate, resp.JSON200)
+		if details {
+			Write(fsStatDetailsTemplate, getObjectDetails(cmd.Context(), client, pathURI, resp.JSON200))
+		} else {
+			Write(fsStatTemplate, resp.JSON200)
+		}
+		if comments {
+			Write(fsStatCommentsTemplate, getObjectCommentThreads(cmd.Context(), client, pathURI))
+		}
 	},
 }
 
+// objectCommentLine is a comment on an object, indented under the comment it replies to
+type objectCommentLine struct {
+	apigen.ObjectComment
+	Indent string
+}
+
+// getObjectCommentThreads returns the comments on the object at pathURI, each followed by its replies
+func getObjectCommentThreads(ctx context.Context, client *apigen.ClientWithResponses, pathURI *uri.URI) []objectCommentLine {
+	var (
+		threads []apigen.ObjectComment
+		replies = make(map[string][]apigen.ObjectComment)
+		after   string
+	)
+	for {
+		resp, err := client.ListObjectCommentsWithResponse(ctx, pathURI.Repository, pathURI.Ref, &apigen.ListObjectCommentsParams{
+			Path:  *pathURI.Path,
+			After: apiutil.Ptr(apigen.PaginationAfter(after)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, comment := range resp.JSON200.Results {
+			if comment.ParentId == nil {
+				threads = append(threads, comment)
+			} else {
+				replies[*comment.ParentId] = append(replies[*comment.ParentId], comment)
+			}
+		}
+		if !resp.JSON200.Pagination.HasMore {
+			break
+		}
+		after = resp.JSON200.Pagination.NextOffset
+	}
+
+	var lines []objectCommentLine
+	var add func(comment apigen.ObjectComment, indent string)
+	add = func(comment apigen.ObjectComment, indent string) {
+		comment.Body = strings.ReplaceAll(comment.Body, "\n", "\n"+indent+"\t\t")
+		lines = append(lines, objectCommentLine{ObjectComment: comment, Indent: indent})
+		for _, reply := range replies[comment.Id] {
+			add(reply, indent+"\t")
+		}
+	}
+	for _, comment := range threads {
+		add(comment, "")
+	}
+	return lines
+}
+
+// getObjectDetails completes the stats of the object at pathURI with the latest commit that modified it, whether
+// its branch has uncommitted changes to it, and its storage class on the underlying storage
+func getObjectDetails(ctx context.Context, client *apigen.ClientWithResponses, pathURI *uri.URI, stats *apigen.ObjectStats) *objectDetails {
+	details := &objectDetails{ObjectStats: stats}
+	path := *pathURI.Path
+
+	annotateResp, err := client.AnnotateObjectsWithResponse(ctx, pathURI.Repository, pathURI.Ref, &apigen.AnnotateObjectsParams{
+		Prefix: apiutil.Ptr(apigen.PaginationPrefix(path)),
+		Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+	})
+	DieOnErrorOrUnexpectedStatusCode(annotateResp, err, http.StatusOK)
+	if annotateResp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	if results := annotateResp.JSON200.Results; len(results) > 0 && results[0].Path == path {
+		details.Commit = results[0].Commit
+	}
+
+	// only a branch can have uncommitted changes, any other ref is either not found or not a valid branch name
+	branchResp, err := client.GetBranchWithResponse(ctx, pathURI.Repository, pathURI.Ref)
+	if err != nil {
+		DieErr(err)
+	}
+	if status := branchResp.StatusCode(); status != http.StatusNotFound && status != http.StatusBadRequest {
+		DieOnErrorOrUnexpectedStatusCode(branchResp, err, http.StatusOK)
+		diffResp, err := client.DiffBranchWithResponse(ctx, pathURI.Repository, pathURI.Ref, &apigen.DiffBranchParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix(path)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(diffResp, err, http.StatusOK)
+		if diffResp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		results := diffResp.JSON200.Results
+		details.Uncommitted = len(results) > 0 && results[0].Path == path
+	}
+
+	propertiesResp, err := client.GetUnderlyingPropertiesWithResponse(ctx, pathURI.Repository, pathURI.Ref, &apigen.GetUnderlyingPropertiesParams{
+		Path: path,
+	})
+	DieOnErrorOrUnexpectedStatusCode(propertiesResp, err, http.StatusOK)
+	if propertiesResp.JSON200 != nil {
+		details.UnderlyingStorageClass = propertiesResp.JSON200.StorageClass
+	}
+	return details
+}
+
 const fsStatTemplate = `Path: {{.Path | yellow }}
 Modified Time: {{.Mtime|date}}
 Size: {{ .SizeBytes }} bytes
@@ -40,7 +164,13 @@ Physical Address: {{ .PhysicalAddress }}
 {{- if .PhysicalAddressExpiry }}
 Physical Address Expires: {{ .PhysicalAddressExpiry|date }}{{end}}
 Checksum: {{ .Checksum }}
-Content-Type: {{ .ContentType }}{{ if and $.Metadata $.Metadata.AdditionalProperties }}
+Content-Type: {{ .ContentType }}
+{{- if .StorageClass }}
+Storage Class: {{ .StorageClass }}{{end}}
+{{- if .Source }}
+Source ETag: {{ .Source.Etag }}
+{{- if .Source.VersionId }}
+Source Version ID: {{ .Source.VersionId }}{{end}}{{end}}{{ if and $.Metadata $.Metadata.AdditionalProperties }}
 Metadata:
 	{{ range $key, $value := .Metadata.AdditionalProperties }}
 	{{ $key | printf "%-18s" }} = {{ $value }}
@@ -48,8 +178,35 @@ Metadata:
 {{- end }}
 `
 
+const fsStatDetailsTemplate = fsStatTemplate + `Underlying Storage Class: {{ if .UnderlyingStorageClass }}{{ .UnderlyingStorageClass }}{{ else }}-{{ end }}
+{{- if .Commit }}
+Last Modified By Commit: {{ .Commit.Id | yellow }}
+	Committer:     {{ .Commit.Committer }}
+{{- if and .Commit.Author (ne (value .Commit.Author) .Commit.Committer) }}
+	Created By:    {{ value .Commit.Author }}
+{{- end }}
+	Creation Date: {{ .Commit.CreationDate|date }}
+	Message:       {{ .Commit.Message }}
+{{- else }}
+Last Modified By Commit: -
+{{- end }}
+{{- if .Uncommitted }}
+Uncommitted Changes: {{ "yes" | red }}
+{{- end }}
+`
+
+const fsStatCommentsTemplate = `Comments:
+{{- range . }}
+{{ .Indent }}	{{ .Id | yellow }} by {{ .Author }} on {{ .CreationDate|date }} at {{ .Ref }} ({{ .CommitId }})
+{{ .Indent }}		{{ .Body }}
+{{- else }} -
+{{- end }}
+`
+
 //nolint:gochecknoinits
 func init() {
 	withPresignFlag(fsStatCmd)
+	fsStatCmd.Flags().Bool(fsStatCommentsFlagName, false, "Show the comments on the object, with their replies")
+	fsStatCmd.Flags().Bool(fsStatDetailsFlagName, false, "Show the commit that introduced the current version of the object, uncommitted changes to it and its storage class")
 	fsCmd.AddCommand(fsStatCmd)
 }
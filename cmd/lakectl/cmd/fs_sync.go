@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/local"
+	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/sync/errgroup"
+)
+
+const fsSyncDryRunTemplate = `{{ range $val := . -}}
+{{ $val.Action | printf "%-9s" }} {{ $val.Path | yellow }}
+{{ end -}}
+`
+
+type fsSyncAction struct {
+	Action string
+	Path   string
+}
+
+var fsSyncCmd = &cobra.Command{
+	Use:   "sync <source> <destination>",
+	Short: "Synchronize a local directory and a lakeFS path",
+	Long: `Copy the files that differ from source to destination, where one of them is a local directory and the other
+a lakeFS path URI. Files are compared by size and modification time, or by content checksum with --checksum, so
+repeated runs only copy what changed since the last sync. Files that exist only on the destination are kept unless
+--delete is set.
+
+Unlike "lakectl local", the local directory is not tracked and is not tied to a commit, which makes sync suitable for
+scheduled jobs and CI pipelines.`,
+	Example: `lakectl fs sync ./data lakefs://example-repo/main/data/ --delete
+lakectl fs sync lakefs://example-repo/main/data/ ./data --checksum`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		deleteExtra := Must(cmd.Flags().GetBool("delete"))
+		checksum := Must(cmd.Flags().GetBool("checksum"))
+		dryRun := Must(cmd.Flags().GetBool("dry-run"))
+
+		source, destination := args[0], args[1]
+		upload := isLakeFSURI(destination)
+		if upload == isLakeFSURI(source) {
+			DieFmt("exactly one of source and destination must be a lakeFS path URI")
+		}
+		remoteArg, localArg := source, destination
+		if upload {
+			remoteArg, localArg = destination, source
+		}
+		remote := MustParsePathURI("path URI", remoteArg)
+		if p := remote.GetPath(); p != "" && !strings.HasSuffix(p, uri.PathSeparator) {
+			p += uri.PathSeparator
+			remote.Path = &p
+		}
+		localPath := Must(filepath.Abs(Must(homedir.Expand(localArg))))
+		if upload {
+			stat, err := os.Stat(localPath)
+			if err != nil {
+				DieErr(err)
+			}
+			if !stat.IsDir() {
+				DieFmt("source '%s' is not a directory", localPath)
+			}
+		} else if err := os.MkdirAll(localPath, local.DefaultDirectoryMask); err != nil {
+			DieErr(err)
+		}
+
+		client := getClient()
+		syncFlags := getSyncFlags(cmd, client)
+		changes := fsSyncChanges(cmd.Context(), client, remote, localPath, upload, deleteExtra, checksum, syncFlags.Parallelism)
+		if dryRun {
+			actions := make([]fsSyncAction, 0, len(changes))
+			for _, change := range changes {
+				action := fsSyncAction{Path: change.Path, Action: "upload"}
+				switch {
+				case change.Type == local.ChangeTypeRemoved:
+					action.Action = "delete"
+				case change.Source == local.ChangeSourceRemote:
+					action.Action = "download"
+				}
+				actions = append(actions, action)
+			}
+			Write(fsSyncDryRunTemplate, actions)
+			return
+		}
+
+		c := make(chan *local.Change, filesChanSize)
+		go func() {
+			defer close(c)
+			for _, change := range changes {
+				c <- change
+			}
+		}()
+		s := local.NewSyncManager(cmd.Context(), client, syncFlags)
+		if err := s.Sync(localPath, remote, c); err != nil {
+			DieErr(err)
+		}
+		Write(localSummaryTemplate, struct {
+			Operation string
+			local.Tasks
+		}{
+			Operation: "Sync",
+			Tasks:     s.Summary(),
+		})
+	},
+}
+
+func isLakeFSURI(s string) bool {
+	return strings.HasPrefix(s, uri.LakeFSSchema+uri.LakeFSSchemaSeparator)
+}
+
+// fsSyncChanges returns the changes that copy the local directory to the remote path when upload is set, or the remote
+// path to the local directory otherwise. Files that exist only on the destination are removed if deleteExtra is set.
+// Checksums of up to parallelism files are computed at once.
+func fsSyncChanges(ctx context.Context, client apigen.ClientWithResponsesInterface, remote *uri.URI, localPath string, upload, deleteExtra, checksum bool, parallelism int) local.Changes {
+	fmt.Printf("diff 'local://%s' <--> '%s'...\n", localPath, remote)
+	objects := make(chan apigen.ObjectStats, maxDiffPageSize)
+	var wg errgroup.Group
+	wg.Go(func() error {
+		return local.ListRemote(ctx, client, remote, objects)
+	})
+	var (
+		changes local.Changes
+		err     error
+	)
+	if checksum {
+		changes, err = local.DiffLocalWithChecksum(objects, localPath, parallelism)
+	} else {
+		changes, err = local.DiffLocalWithHeadConcurrently(objects, localPath, parallelism)
+	}
+	if err != nil {
+		DieErr(err)
+	}
+	if err := wg.Wait(); err != nil {
+		DieErr(err)
+	}
+
+	if !upload {
+		// the diff describes the local directory relative to the remote path, reverse it to copy the remote path
+		changes = local.Undo(changes)
+	}
+	result := make(local.Changes, 0, len(changes))
+	for _, change := range changes {
+		if change.Type == local.ChangeTypeRemoved && !deleteExtra {
+			continue
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsSyncCmd.Flags().Bool("delete", false, "delete files that exist only on the destination")
+	fsSyncCmd.Flags().Bool("checksum", false, "compare files of the same size by content checksum instead of modification time")
+	fsSyncCmd.Flags().Bool("dry-run", false, "show the files that would be copied or deleted, without changing them")
+	withSyncFlags(fsSyncCmd)
+
+	fsCmd.AddCommand(fsSyncCmd)
+}
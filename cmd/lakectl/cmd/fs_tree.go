@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const (
+	fsTreeDepthFlagName = "depth"
+	fsTreeIndent        = "  "
+)
+
+const fsTreeTemplate = `{{ range . -}}
+{{ .Indent }}{{ .Path | yellow }} {{ .ObjectCount }} objects, {{ .SizeBytes | human_bytes }}, {{ .ChildCount }} children
+{{ end -}}
+`
+
+const fsTreeTruncatedTemplate = `{{ "Too many objects to scan: counts and sizes include only the first objects" | yellow }}
+`
+
+type fsTreeLine struct {
+	Indent      string
+	Path        string
+	ChildCount  int
+	ObjectCount int64
+	SizeBytes   int64
+}
+
+var fsTreeCmd = &cobra.Command{
+	Use:               "tree <path URI>",
+	Short:             "Show the directory tree under a given path, with object counts and sizes",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		depth := Must(cmd.Flags().GetInt(fsTreeDepthFlagName))
+		client := getClient()
+
+		prefix := pathURI.GetPath()
+		if prefix != "" && !strings.HasSuffix(prefix, PathDelimiter) {
+			prefix += PathDelimiter
+		}
+		resp, err := client.GetObjectTreeWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.GetObjectTreeParams{
+			Prefix:    (*apigen.PaginationPrefix)(swag.String(prefix)),
+			Delimiter: swag.String(PathDelimiter),
+			Depth:     swag.Int(depth),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		var lines []fsTreeLine
+		var walk func(node apigen.ObjectTree, level int, parent string)
+		walk = func(node apigen.ObjectTree, level int, parent string) {
+			path := strings.TrimPrefix(node.Path, parent)
+			if level == 0 {
+				path = pathURI.String()
+			}
+			lines = append(lines, fsTreeLine{
+				Indent:      strings.Repeat(fsTreeIndent, level),
+				Path:        path,
+				ChildCount:  node.ChildCount,
+				ObjectCount: node.ObjectCount,
+				SizeBytes:   node.SizeBytes,
+			})
+			if node.Children == nil {
+				return
+			}
+			for _, child := range *node.Children {
+				walk(child, level+1, node.Path)
+			}
+		}
+		walk(*resp.JSON200, 0, "")
+		Write(fsTreeTemplate, lines)
+		warnTreeTruncated(resp.JSON200)
+	},
+}
+
+// warnTreeTruncated warns when tree was built from only part of the objects under it
+func warnTreeTruncated(tree *apigen.ObjectTree) {
+	if swag.BoolValue(tree.Truncated) {
+		WriteTo(fsTreeTruncatedTemplate, nil, os.Stderr)
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsTreeCmd.Flags().Int(fsTreeDepthFlagName, 1, "number of directory levels to show")
+	fsCmd.AddCommand(fsTreeCmd)
+}
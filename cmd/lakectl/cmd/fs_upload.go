@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
@@ -15,9 +16,22 @@ import (
 	"github.com/treeverse/lakefs/pkg/uri"
 )
 
+const fsUploadSummaryTemplate = `
+Upload Summary:
+
+{{"Uploaded:" | printf|yellow}} {{.Uploaded|yellow}} ({{.Bytes|human_bytes}})
+{{"Skipped:" | printf|green}}  {{.Skipped|green}} uploaded by an interrupted session
+{{"Elapsed:" | printf}}  {{.Elapsed}}
+`
+
 var fsUploadCmd = &cobra.Command{
-	Use:               "upload <path URI>",
-	Short:             "Upload a local file to the specified URI",
+	Use:   "upload <path URI>",
+	Short: "Upload a local file to the specified URI",
+	Long: `Upload a local file to the specified URI, or with --recursive every file under a local directory, keeping their
+relative paths. Files already uploaded with the same size and modification time, or the same content with --checksum,
+are skipped. With --journal, uploaded files are recorded so that an interrupted upload resumes where it stopped when
+run again, and the journal is removed once the upload completes.`,
+	Example:           `lakectl fs upload --recursive --source ./data lakefs://example-repo/main/data/ --checksum --journal upload.journal`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -27,6 +41,8 @@ var fsUploadCmd = &cobra.Command{
 		source := Must(cmd.Flags().GetString("source"))
 		contentType := Must(cmd.Flags().GetString("content-type"))
 		recursive := Must(cmd.Flags().GetBool(recursiveFlagName))
+		checksum := Must(cmd.Flags().GetBool("checksum"))
+		journalPath := Must(cmd.Flags().GetString("journal"))
 		remotePath := pathURI.GetPath()
 		ctx := cmd.Context()
 
@@ -46,36 +62,63 @@ var fsUploadCmd = &cobra.Command{
 		}
 
 		// Try recursive upload
-		if !strings.HasSuffix(source, string(filepath.Separator)) {
-			source += string(filepath.Separator)
+		fullPath, err := filepath.Abs(source)
+		if err != nil {
+			DieErr(err)
+		}
+		start := time.Now()
+		var journal *local.Journal
+		if journalPath != "" {
+			journal, err = local.OpenJournal(journalPath)
+			if err != nil {
+				DieErr(err)
+			}
 		}
-		changes := localDiff(ctx, client, pathURI, source)
+		changes := fsSyncChanges(ctx, client, pathURI, fullPath, true, false, checksum, syncFlags.Parallelism)
 		// sync changes
+		var skipped uint64
 		c := make(chan *local.Change, filesChanSize)
 		go func() {
 			defer close(c)
 			for _, change := range changes {
-				if change.Type == local.ChangeTypeRemoved {
-					continue
+				if journal != nil {
+					info, err := os.Stat(filepath.Join(fullPath, filepath.FromSlash(change.Path)))
+					if err == nil && journal.Uploaded(change.Path, local.UploadDestination(pathURI, change.Path).String(), info) {
+						skipped++
+						continue
+					}
 				}
 				c <- change
 			}
 		}()
 		s := local.NewSyncManager(ctx, client, syncFlags)
-		fullPath, err := filepath.Abs(source)
-		if err != nil {
-			DieErr(err)
+		if journal != nil {
+			s.SetJournal(journal)
 		}
 		err = s.Sync(fullPath, pathURI, c)
 		if err != nil {
+			if journal != nil {
+				_ = journal.Close()
+				DieFmt("%s\nRun the same command to resume the upload from journal %s", err, journalPath)
+			}
 			DieErr(err)
 		}
-		Write(localSummaryTemplate, struct {
-			Operation string
+		if journal != nil {
+			if err := journal.Remove(); err != nil {
+				DieErr(err)
+			}
+		}
+		summary := s.Summary()
+		Write(fsUploadSummaryTemplate, struct {
 			local.Tasks
+			Bytes   int64
+			Skipped uint64
+			Elapsed time.Duration
 		}{
-			Operation: "Upload",
-			Tasks:     s.Summary(),
+			Tasks:   summary,
+			Bytes:   int64(summary.UploadedBytes),
+			Skipped: skipped,
+			Elapsed: time.Since(start).Round(time.Millisecond),
 		})
 	},
 }
@@ -98,6 +141,8 @@ func init() {
 	_ = fsUploadCmd.MarkFlagRequired("source")
 	fsUploadCmd.Flags().StringP("content-type", "", "", "MIME type of contents")
 	withRecursiveFlag(fsUploadCmd, "recursively copy all files under local source")
+	fsUploadCmd.Flags().Bool("checksum", false, "with --recursive, skip files whose content checksum matches the existing object instead of comparing modification time")
+	fsUploadCmd.Flags().String("journal", "", "with --recursive, file recording the uploaded files, to resume an interrupted upload")
 	withSyncFlags(fsUploadCmd)
 
 	fsCmd.AddCommand(fsUploadCmd)
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+const gcPlanSampleRatioFlagName = "sample-ratio"
+
+const gcPlanTemplate = `Active Commits: {{ .ActiveCommits }}
+Expired Commits: {{ .ExpiredCommits }}
+Reclaimable Objects: {{ .ReclaimableObjects }}{{ if lt .SampleRatio 1.0 }} (estimated){{ end }}
+Reclaimable Size: {{ .ReclaimableBytes | human_bytes }}
+Branches: {{ range $branch := .Branches }}
+  - Branch: {{ $branch.BranchId }}
+    Retention Days: {{ $branch.RetentionDays }}
+    Retained Commits: {{ $branch.RetainedCommits }}
+    Retained Objects: {{ $branch.RetainedObjects }}
+    Retained Size: {{ $branch.RetainedBytes | human_bytes }}{{ end }}
+`
+
+var gcPlanCmd = &cobra.Command{
+	Use:   "plan <repository URI>",
+	Short: "Estimate what garbage collection would reclaim",
+	Long: `Estimate the committed objects and bytes that garbage collection would reclaim, and what each branch
+retains, without deleting anything. Uses the repository garbage collection policy, or the policy JSON given
+with --filename (same format as set-config) to try out rules before setting them.`,
+	Example:           "lakectl gc plan " + myRepoExample + " -f config.json",
+	Args:              cobra.ExactArgs(gcSetConfigCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+		filename := Must(cmd.Flags().GetString(filenameFlagName))
+		sampleRatio := Must(cmd.Flags().GetFloat64(gcPlanSampleRatioFlagName))
+		isJSON := Must(cmd.Flags().GetBool(jsonFlagName))
+
+		body := apigen.PlanGCRulesJSONRequestBody{
+			SampleRatio: &sampleRatio,
+		}
+		if filename != "" {
+			reader := os.Stdin
+			if filename != "-" {
+				f, err := os.Open(filename)
+				if err != nil {
+					DieErr(err)
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+				reader = f
+			}
+			var rules apigen.GarbageCollectionRules
+			if err := json.NewDecoder(reader).Decode(&rules); err != nil {
+				DieErr(err)
+			}
+			body.Rules = &rules
+		}
+		client := getClient()
+		ctx := cmd.Context()
+		resp, err := client.PlanGCRulesWithResponse(ctx, u.Repository, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusAccepted)
+		if resp.JSON202 == nil {
+			Die("Bad response from server", 1)
+		}
+		taskID := resp.JSON202.Id
+
+		// wait for the plan to complete
+		status, err := backoff.RetryWithData(func() (*apigen.GarbageCollectionPlanStatus, error) {
+			resp, err := client.PlanGCRulesStatusWithResponse(ctx, u.Repository, &apigen.PlanGCRulesStatusParams{
+				TaskId: taskID,
+			})
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				return nil, backoff.Permanent(fmt.Errorf("plan status %w: %s", helpers.ErrRequestFailed, resp.Status()))
+			}
+			if !resp.JSON200.Done {
+				return nil, ErrTaskNotCompleted
+			}
+			return resp.JSON200, nil
+		}, backoff.WithContext(backoff.NewConstantBackOff(defaultPollInterval), ctx))
+		switch {
+		case err != nil:
+			DieErr(err)
+		case status.Error != nil:
+			DieFmt("GC plan failed: %s", *status.Error)
+		case status.Plan == nil:
+			Die("GC plan failed: no plan returned", 1)
+		}
+		if isJSON {
+			Write("{{ . | json }}", status.Plan)
+		} else {
+			Write(gcPlanTemplate, status.Plan)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	gcPlanCmd.Flags().StringP(filenameFlagName, "f", "", "file containing the GC policy as JSON to plan with, instead of the repository policy")
+	gcPlanCmd.Flags().Float64(gcPlanSampleRatioFlagName, 1, "ratio of objects examined to estimate the results (0, 1]")
+	gcPlanCmd.Flags().BoolP(jsonFlagName, "p", false, "get plan as JSON")
+
+	gcCmd.AddCommand(gcPlanCmd)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const (
+	legalHoldAddCmdArgs    = 3
+	legalHoldDeleteCmdArgs = 3
+
+	legalHoldReasonFlagName = "reason"
+)
+
+var legalHoldCmd = &cobra.Command{
+	Use:   "legal-hold",
+	Short: "Place and release legal holds on commits and tags",
+	Long: `Pin historical snapshots: objects reachable from a commit or a tag under legal hold are never reclaimed by
+garbage collection, and a tag under legal hold cannot be deleted.`,
+}
+
+var legalHoldListCmd = &cobra.Command{
+	Use:               "list <repository URI>",
+	Short:             "List all legal holds",
+	Example:           "lakectl legal-hold list " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		resp, err := client.ListLegalHoldsWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		rows := make([][]interface{}, len(resp.JSON200.Results))
+		for i, hold := range resp.JSON200.Results {
+			ts := time.Unix(hold.CreationDate, 0).String()
+			rows[i] = []interface{}{hold.Kind, hold.Id, hold.Reason, hold.CreatedBy, ts}
+		}
+		PrintTable(rows, []interface{}{"Kind", "ID", "Reason", "Created By", "Creation Date"}, &apigen.Pagination{
+			HasMore: false,
+			Results: len(rows),
+		}, len(rows))
+	},
+}
+
+var legalHoldAddCmd = &cobra.Command{
+	Use:               "add <repository URI> <commit|tag> <ID>",
+	Short:             "Place a legal hold on a commit or a tag",
+	Long:              "Place a legal hold on a tag, or on the commit any reference resolves to",
+	Example:           "lakectl legal-hold add " + myRepoExample + " tag v1.0 --reason 'case #1234'",
+	Args:              cobra.ExactArgs(legalHoldAddCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		reason := Must(cmd.Flags().GetString(legalHoldReasonFlagName))
+		client := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		resp, err := client.SetLegalHoldWithResponse(cmd.Context(), u.Repository, apigen.SetLegalHoldJSONRequestBody{
+			Kind:   args[1],
+			Id:     args[2],
+			Reason: reason,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Legal hold placed on %s %s\n", resp.JSON200.Kind, resp.JSON200.Id)
+	},
+}
+
+var legalHoldDeleteCmd = &cobra.Command{
+	Use:               "delete <repository URI> <commit|tag> <ID>",
+	Short:             "Release the legal hold of a commit or a tag",
+	Example:           "lakectl legal-hold delete " + myRepoExample + " tag v1.0",
+	Args:              cobra.ExactArgs(legalHoldDeleteCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		resp, err := client.DeleteLegalHoldWithResponse(cmd.Context(), u.Repository, &apigen.DeleteLegalHoldParams{
+			Kind: args[1],
+			Id:   args[2],
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(legalHoldCmd)
+	legalHoldCmd.AddCommand(legalHoldListCmd)
+	legalHoldCmd.AddCommand(legalHoldAddCmd)
+	legalHoldCmd.AddCommand(legalHoldDeleteCmd)
+
+	legalHoldAddCmd.Flags().String(legalHoldReasonFlagName, "", "reason for the legal hold")
+	_ = legalHoldAddCmd.MarkFlagRequired(legalHoldReasonFlagName)
+}
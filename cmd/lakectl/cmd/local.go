@@ -28,6 +28,7 @@ const (
 	pullOperation     LocalOperation = "pull"
 	checkoutOperation LocalOperation = "checkout"
 	cloneOperation    LocalOperation = "clone"
+	verifyOperation   LocalOperation = "verify"
 )
 
 const localSummaryTemplate = `
@@ -74,6 +75,40 @@ func localDiff(ctx context.Context, client apigen.ClientWithResponsesInterface,
 	return changes
 }
 
+// localLock takes the lock of the synced directory for operation, dies if another operation holds it
+func localLock(dir string, operation LocalOperation) *local.Lock {
+	lock, err := local.AcquireLock(dir, string(operation))
+	if errors.Is(err, local.ErrLocked) {
+		DieFmt("directory '%s' is in use: %s", dir, err)
+	}
+	if err != nil {
+		DieErr(err)
+	}
+	return lock
+}
+
+// localLockIndex locks the synced directory containing path for operation, and reads its index under the lock
+func localLockIndex(path string, operation LocalOperation) (*local.Index, *local.Lock, error) {
+	idx, err := local.ReadIndex(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	lock := localLock(idx.LocalPath(), operation)
+	// re-read, the index may have been updated by the previous lock holder
+	idx, err = local.ReadIndex(idx.LocalPath())
+	if err != nil {
+		localReleaseLock(lock)
+		return nil, nil, err
+	}
+	return idx, lock, nil
+}
+
+func localReleaseLock(lock *local.Lock) {
+	if err := lock.Release(); err != nil {
+		WriteTo("{{.Error|red}}\n", struct{ Error string }{Error: "Failed to release lock: " + err.Error()}, os.Stderr)
+	}
+}
+
 func localHandleSyncInterrupt(ctx context.Context, idx *local.Index, operation string) context.Context {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -41,13 +41,14 @@ var localCheckoutCmd = &cobra.Command{
 func localCheckout(cmd *cobra.Command, localPath string, specifiedRef string, confirmByFlag bool) {
 	client := getClient()
 	syncFlags := getSyncFlags(cmd, client)
-	idx, err := local.ReadIndex(localPath)
+	idx, lock, err := localLockIndex(localPath, checkoutOperation)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			DieFmt("directory %s is not linked to a lakeFS path", localPath)
 		}
 		DieErr(err)
 	}
+	defer localReleaseLock(lock)
 
 	remote, err := idx.GetCurrentURI()
 	if err != nil {
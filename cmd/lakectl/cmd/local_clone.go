@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -13,6 +14,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/fileutil"
 	"github.com/treeverse/lakefs/pkg/local"
 	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -24,10 +26,36 @@ const (
 var localCloneCmd = &cobra.Command{
 	Use:   "clone <path URI> [directory]",
 	Short: "Clone a path from a lakeFS repository into a new directory.",
-	Args:  cobra.RangeArgs(localCloneMinArgs, localCloneMaxArgs),
+	Long: `Clone a path from a lakeFS repository into a new directory.
+
+With --from-bundle, clone the path and commit pinned by a bundle written by "lakectl local share" instead of a path
+URI, and verify that the cloned objects match the bundle.`,
+	Example: `lakectl local clone lakefs://example-repo/main/data/ data/
+lakectl local clone --from-bundle bundle.json data/`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if Must(cmd.Flags().GetString("from-bundle")) != "" {
+			return cobra.MaximumNArgs(localCloneMaxArgs-1)(cmd, args)
+		}
+		return cobra.RangeArgs(localCloneMinArgs, localCloneMaxArgs)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
-		remote, localPath := getSyncArgs(args, true, false)
+		var (
+			bundle    *local.Bundle
+			remote    *uri.URI
+			localPath string
+		)
+		if bundlePath := Must(cmd.Flags().GetString("from-bundle")); bundlePath != "" {
+			var err error
+			bundle, err = local.ReadBundle(bundlePath)
+			if err != nil {
+				DieErr(err)
+			}
+			remote = MustParsePathURI("bundle source", bundle.Source)
+			_, localPath = getSyncArgs(args, false, false)
+		} else {
+			remote, localPath = getSyncArgs(args, true, false)
+		}
 		syncFlags := getSyncFlags(cmd, client)
 		updateIgnore := Must(cmd.Flags().GetBool(localGitIgnoreFlagName))
 		empty, err := fileutil.IsDirEmpty(localPath)
@@ -44,6 +72,13 @@ var localCloneCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		if bundle != nil {
+			// sync to the pinned commit, keep tracking the path URI of the bundle
+			head = resolveCommitOrDie(ctx, client, remote.Repository, bundle.Commit)
+			if _, err := local.WriteIndex(localPath, remote, head, ""); err != nil {
+				DieErr(err)
+			}
+		}
 		stableRemote := remote.WithRef(head)
 		// Dynamically construct changes
 		ch := make(chan *local.Change, filesChanSize)
@@ -82,16 +117,20 @@ var localCloneCmd = &cobra.Command{
 				after = listResp.JSON200.Pagination.NextOffset
 			}
 		}()
-		idx, err := local.ReadIndex(localPath)
+		idx, lock, err := localLockIndex(localPath, cloneOperation)
 		if err != nil {
 			DieErr(err)
 		}
+		defer localReleaseLock(lock)
 		sigCtx := localHandleSyncInterrupt(ctx, idx, string(cloneOperation))
 		s := local.NewSyncManager(sigCtx, client, syncFlags)
 		err = s.Sync(localPath, stableRemote, ch)
 		if err != nil {
 			DieErr(err)
 		}
+		if bundle != nil {
+			localVerifyBundle(ctx, client, bundle, stableRemote)
+		}
 		fmt.Printf("\nSuccessfully cloned %s to %s.\n", remote, localPath)
 		Write(localSummaryTemplate, struct {
 			Operation string
@@ -103,8 +142,32 @@ var localCloneCmd = &cobra.Command{
 	},
 }
 
+// localVerifyBundle dies unless the objects of remote match bundle, and prints the rest of the state it pins
+func localVerifyBundle(ctx context.Context, client apigen.ClientWithResponsesInterface, bundle *local.Bundle, remote *uri.URI) {
+	objects := make(chan apigen.ObjectStats, maxDiffPageSize)
+	var wg errgroup.Group
+	wg.Go(func() error {
+		return local.ListRemote(ctx, client, remote, objects)
+	})
+	verifyErr := bundle.VerifyObjects(objects)
+	if err := wg.Wait(); err != nil {
+		DieErr(err)
+	}
+	if verifyErr != nil {
+		DieErr(verifyErr)
+	}
+	fmt.Printf("\nVerified %d objects against the bundle.\n", bundle.Objects)
+	if bundle.GitCommit != "" {
+		fmt.Printf("Bundle pins git commit %s\n", bundle.GitCommit)
+	}
+	if bundle.RunSpecSHA256 != "" {
+		fmt.Printf("Bundle pins run spec SHA-256 %s\n", bundle.RunSpecSHA256)
+	}
+}
+
 //nolint:gochecknoinits
 func init() {
+	localCloneCmd.Flags().String("from-bundle", "", "Bundle written by \"lakectl local share\" to clone instead of a path URI")
 	withGitIgnoreFlag(localCloneCmd)
 	withSyncFlags(localCloneCmd)
 	localCmd.AddCommand(localCloneCmd)
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
@@ -11,10 +13,16 @@ import (
 	"github.com/treeverse/lakefs/pkg/diff"
 	"github.com/treeverse/lakefs/pkg/git"
 	"github.com/treeverse/lakefs/pkg/local"
+	"github.com/treeverse/lakefs/pkg/mlflow"
 	"github.com/treeverse/lakefs/pkg/uri"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	localMLflowLogCommitFlagName = "mlflow-log-commit"
+	mlflowRequestTimeout         = 30 * time.Second
+)
+
 func findConflicts(changes local.Changes) (conflicts []string) {
 	for _, c := range changes {
 		if c.Type == local.ChangeTypeConflict {
@@ -27,17 +35,24 @@ func findConflicts(changes local.Changes) (conflicts []string) {
 var localCommitCmd = &cobra.Command{
 	Use:   "commit [directory]",
 	Short: "Commit changes from local directory to the lakeFS branch it tracks.",
-	Args:  localDefaultArgsRange,
+	Long: `Commit changes from local directory to the lakeFS branch it tracks.
+
+When run in a git repository, the git commit is added to the commit metadata. When run inside an active MLflow run,
+detected by the MLFLOW_RUN_ID environment variable, the run ID, experiment ID and tracking URI are added to the
+commit metadata, and with --mlflow-log-commit the URI of the created commit is logged back to the run as a tag.`,
+	Args: localDefaultArgsRange,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		_, localPath := getSyncArgs(args, false, false)
 		syncFlags := getSyncFlags(cmd, client)
 		message, kvPairs := getCommitFlags(cmd)
+		mlflowLogCommit := Must(cmd.Flags().GetBool(localMLflowLogCommitFlagName))
 
-		idx, err := local.ReadIndex(localPath)
+		idx, lock, err := localLockIndex(localPath, commitOperation)
 		if err != nil {
 			DieErr(err)
 		}
+		defer localReleaseLock(lock)
 
 		remote, err := idx.GetCurrentURI()
 		if err != nil {
@@ -137,6 +152,14 @@ var localCommitCmd = &cobra.Command{
 			}
 		}
 
+		// add the active MLflow run to kv pairs, if any
+		mlflowRun := mlflow.ActiveRun()
+		if mlflowRun != nil {
+			for k, v := range mlflow.MetadataFor(mlflowRun) {
+				kvPairs[k] = v
+			}
+		}
+
 		// commit!
 		response, err := client.CommitWithResponse(cmd.Context(), remote.Repository, remote.Ref, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
 			Message: message,
@@ -165,6 +188,17 @@ var localCommitCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+
+		if mlflowRun != nil && mlflowLogCommit {
+			commitURI := remote.WithRef(newHead).String()
+			httpClient := &http.Client{Timeout: mlflowRequestTimeout}
+			if err := mlflow.SetTag(cmd.Context(), httpClient, mlflowRun, mlflow.CommitURITag, commitURI); err != nil {
+				// the commit succeeded, only report the failure to log it
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: failed logging commit URI to MLflow run %s: %s\n", mlflowRun.ID, err)
+			} else {
+				fmt.Printf("Logged %s to MLflow run %s as tag %s\n", commitURI, mlflowRun.ID, mlflow.CommitURITag)
+			}
+		}
 	},
 }
 
@@ -172,5 +206,7 @@ var localCommitCmd = &cobra.Command{
 func init() {
 	withCommitFlags(localCommitCmd, false)
 	withSyncFlags(localCommitCmd)
+	localCommitCmd.Flags().Bool(localMLflowLogCommitFlagName, false,
+		"Log the URI of the created commit to the active MLflow run as tag "+mlflow.CommitURITag)
 	localCmd.AddCommand(localCommitCmd)
 }
@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var localConfigCmd = &cobra.Command{
+	Use:   "config [sub-command]",
+	Short: "Manage the configuration of directories synced with lakeFS",
+}
+
+//nolint:gochecknoinits
+func init() {
+	localCmd.AddCommand(localConfigCmd)
+}
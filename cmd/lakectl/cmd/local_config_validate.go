@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/local"
+)
+
+const localConfigValidTemplate = `{{ .Path | bold }} is valid (version {{ .Version }}), tracking {{ .Remote | yellow }} at {{ .AtHead }}
+`
+
+var localConfigValidateCmd = &cobra.Command{
+	Use:   "validate [directory]",
+	Short: "Validate the lakeFS reference file of a synced directory, migrating it to the current format if needed",
+	Args:  localDefaultArgsRange,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, localPath := getSyncArgs(args, false, false)
+		idx, err := local.ReadIndex(localPath)
+		if err != nil {
+			DieErr(err)
+		}
+		if err := idx.Validate(); err != nil {
+			DieErr(err)
+		}
+		switch LocalOperation(idx.ActiveOperation) {
+		case "", commitOperation, pullOperation, checkoutOperation, cloneOperation:
+		default:
+			DieErr(fmt.Errorf("active_operation '%s': %w", idx.ActiveOperation, ErrUnknownOperation))
+		}
+		Write(localConfigValidTemplate, struct {
+			Path    string
+			Version int
+			Remote  string
+			AtHead  string
+		}{
+			Path:    filepath.Join(idx.LocalPath(), local.IndexFileName),
+			Version: idx.Version,
+			Remote:  idx.PathURI,
+			AtHead:  idx.AtHead,
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	localConfigCmd.AddCommand(localConfigValidateCmd)
+}
@@ -21,10 +21,11 @@ var localPullCmd = &cobra.Command{
 		_, localPath := getSyncArgs(args, false, false)
 		force := Must(cmd.Flags().GetBool(localForceFlagName))
 		syncFlags := getSyncFlags(cmd, client)
-		idx, err := local.ReadIndex(localPath)
+		idx, lock, err := localLockIndex(localPath, pullOperation)
 		if err != nil {
 			DieErr(err)
 		}
+		defer localReleaseLock(lock)
 
 		remote, err := idx.GetCurrentURI()
 		if err != nil {
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/git"
+	"github.com/treeverse/lakefs/pkg/local"
+	"golang.org/x/sync/errgroup"
+)
+
+var localShareCmd = &cobra.Command{
+	Use:   "share [directory]",
+	Short: "Write a bundle that reproduces the state of the directory with \"lakectl local clone --from-bundle\"",
+	Long: `Write a small reproducibility bundle of a synced directory: its lakeFS path URI pinned to the commit it is synced to,
+the number and digest of the checksums of its objects, the commit of the git repository containing the directory, and
+optionally the SHA-256 digest of the spec of the run that used it. A colleague passes the bundle to
+"lakectl local clone --from-bundle" to get exactly the same files.
+
+The directory must have no local changes, commit them with "lakectl local commit" first.`,
+	Example: `lakectl local share data/ -o bundle.json
+lakectl local share data/ --run-spec train.yaml > bundle.json`,
+	Args: localDefaultArgsRange,
+	Run: func(cmd *cobra.Command, args []string) {
+		output := Must(cmd.Flags().GetString("output"))
+		runSpec := Must(cmd.Flags().GetString("run-spec"))
+		_, localPath := getSyncArgs(args, false, false)
+		abs, err := filepath.Abs(localPath)
+		if err != nil {
+			DieErr(err)
+		}
+		idx, err := local.ReadIndex(abs)
+		if err != nil {
+			DieErr(err)
+		}
+		remote, err := idx.GetCurrentURI()
+		if err != nil {
+			DieErr(err)
+		}
+		dieOnInterruptedOperation(LocalOperation(idx.ActiveOperation), false)
+
+		ctx := cmd.Context()
+		client := getClient()
+		remoteBase := remote.WithRef(idx.AtHead)
+		objects := make(chan apigen.ObjectStats, maxDiffPageSize)
+		var wg errgroup.Group
+		wg.Go(func() error {
+			return local.ListRemote(ctx, client, remoteBase, objects)
+		})
+		changes, err := local.DiffLocalWithHead(objects, idx.LocalPath())
+		if err != nil {
+			DieErr(err)
+		}
+		if err := wg.Wait(); err != nil {
+			DieErr(err)
+		}
+		if len(changes) > 0 {
+			DieFmt("directory '%s' has %d local change(s), commit them with \"lakectl local commit\" before sharing it", idx.LocalPath(), len(changes))
+		}
+
+		bundle := &local.Bundle{
+			Version: local.BundleVersion,
+			Source:  remote.String(),
+			Commit:  idx.AtHead,
+		}
+		objects = make(chan apigen.ObjectStats, maxDiffPageSize)
+		wg.Go(func() error {
+			return local.ListRemote(ctx, client, remoteBase, objects)
+		})
+		bundle.Objects, bundle.ObjectsSHA256 = local.DigestObjects(objects)
+		if err := wg.Wait(); err != nil {
+			DieErr(err)
+		}
+		if git.IsRepository(idx.LocalPath()) {
+			bundle.GitCommit, err = git.CurrentCommit(idx.LocalPath())
+			if err != nil {
+				DieErr(err)
+			}
+		}
+		if runSpec != "" {
+			bundle.RunSpecSHA256, err = local.FileSHA256(runSpec)
+			if err != nil {
+				DieErr(err)
+			}
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				DieErr(err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		if err := local.WriteBundle(w, bundle); err != nil {
+			DieErr(err)
+		}
+		if output != "" {
+			fmt.Printf("Bundle of %s written to %s\n", remoteBase, output)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	localShareCmd.Flags().StringP("output", "o", "", "File to write the bundle to, standard output if not set")
+	localShareCmd.Flags().String("run-spec", "", "Spec file of the run that used the directory, recorded by its SHA-256 digest")
+	localCmd.AddCommand(localShareCmd)
+}
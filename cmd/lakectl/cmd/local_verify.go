@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/local"
+	"golang.org/x/sync/errgroup"
+)
+
+const localVerifySummaryTemplate = `
+Verify Summary:
+
+{{"Verified:" | printf}} {{.Verified}}
+{{"Modified:" | printf|yellow}} {{.Modified|yellow}}
+{{"Removed:" | printf|yellow}} {{.Removed|yellow}}
+{{"Unverifiable:" | printf}} {{.Unverifiable}}
+{{"Corrupted:" | printf|red}} {{.Corrupted|red}}
+`
+
+var localVerifyCmd = &cobra.Command{
+	Use:   "verify [directory]",
+	Short: "Verify local files against the checksums of the objects of the commit they are synced to",
+	Long: `Re-hash the local files and compare them with the checksums of the objects of the commit the directory is synced to.
+Files edited since they were synced are reported as modified. Files whose content changed while keeping the size and
+modification time of their object are reported as corrupted, and fail the command: normal edits do not do that, it
+happens on disk corruption or tampering. Objects uploaded in multiple parts have no content checksum to compare, and
+are reported as unverifiable.
+
+With --restore, corrupted files are downloaded again from the remote.`,
+	Args: localDefaultArgsRange,
+	Run: func(cmd *cobra.Command, args []string) {
+		restore := Must(cmd.Flags().GetBool("restore"))
+		_, localPath := getSyncArgs(args, false, false)
+		abs, err := filepath.Abs(localPath)
+		if err != nil {
+			DieErr(err)
+		}
+		var idx *local.Index
+		if restore {
+			var lock *local.Lock
+			idx, lock, err = localLockIndex(abs, verifyOperation)
+			if err == nil {
+				defer localReleaseLock(lock)
+			}
+		} else {
+			idx, err = local.ReadIndex(abs)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		remote, err := idx.GetCurrentURI()
+		if err != nil {
+			DieErr(err)
+		}
+		dieOnInterruptedOperation(LocalOperation(idx.ActiveOperation), false)
+
+		remoteBase := remote.WithRef(idx.AtHead)
+		fmt.Printf("verify 'local://%s' against '%s'...\n", idx.LocalPath(), remoteBase)
+		objects := make(chan apigen.ObjectStats, maxDiffPageSize)
+		client := getClient()
+		var wg errgroup.Group
+		wg.Go(func() error {
+			return local.ListRemote(cmd.Context(), client, remoteBase, objects)
+		})
+		results, err := local.Verify(objects, idx.LocalPath())
+		if err != nil {
+			DieErr(err)
+		}
+		if err := wg.Wait(); err != nil {
+			DieErr(err)
+		}
+
+		counts := make(map[local.VerifyStatus]int)
+		t := table.NewWriter()
+		t.SetStyle(table.StyleDouble)
+		t.AppendHeader(table.Row{"status", "path"})
+		for _, r := range results {
+			counts[r.Status]++
+			if r.Status == local.VerifyStatusOK {
+				continue
+			}
+			status := local.VerifyStatusString(r.Status)
+			color := localVerifyStatusColor(r.Status)
+			t.AppendRow(table.Row{color.Sprint(status), color.Sprint(r.Path)})
+		}
+		if t.Length() > 0 {
+			fmt.Printf("\n%s\n", t.Render())
+		}
+		Write(localVerifySummaryTemplate, struct {
+			Verified     int
+			Modified     int
+			Removed      int
+			Unverifiable int
+			Corrupted    int
+		}{
+			Verified:     counts[local.VerifyStatusOK],
+			Modified:     counts[local.VerifyStatusModified],
+			Removed:      counts[local.VerifyStatusRemoved],
+			Unverifiable: counts[local.VerifyStatusUnverifiable],
+			Corrupted:    counts[local.VerifyStatusCorrupted],
+		})
+		corrupted := counts[local.VerifyStatusCorrupted]
+		if corrupted == 0 {
+			return
+		}
+		if !restore {
+			DieFmt("%d corrupted file(s), use \"lakectl local verify --restore\" to download them again", corrupted)
+		}
+
+		c := make(chan *local.Change, filesChanSize)
+		go func() {
+			defer close(c)
+			for _, r := range results {
+				if r.Status == local.VerifyStatusCorrupted {
+					c <- &local.Change{Source: local.ChangeSourceRemote, Path: r.Path, Type: local.ChangeTypeModified}
+				}
+			}
+		}()
+		syncMgr := local.NewSyncManager(cmd.Context(), client, getSyncFlags(cmd, client))
+		if err := syncMgr.Sync(idx.LocalPath(), remoteBase, c); err != nil {
+			DieErr(err)
+		}
+		fmt.Printf("Restored %d corrupted file(s)\n", syncMgr.Summary().Downloaded)
+	},
+}
+
+func localVerifyStatusColor(status local.VerifyStatus) text.Color {
+	switch status {
+	case local.VerifyStatusCorrupted:
+		return text.FgRed
+	case local.VerifyStatusModified, local.VerifyStatusRemoved:
+		return text.FgYellow
+	default:
+		return text.Reset
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	localVerifyCmd.Flags().Bool("restore", false, "Download corrupted files again from the remote")
+	withSyncFlags(localVerifyCmd)
+	localCmd.AddCommand(localVerifyCmd)
+}
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,10 +20,13 @@ import (
 
 const commitsTemplate = `{{ range $val := .Commits }}
 ID:            {{ $val.Id|yellow }}{{if $val.Committer }}
-Author:        {{ $val.Committer }}{{end}}
+Author:        {{ $val.Committer }}{{end}}{{ if and $val.Author (ne (value $val.Author) $val.Committer) }}
+Created By:    {{ value $val.Author }}{{ end }}
 Date:          {{ $val.CreationDate|date }}
 {{ if $.ShowMetaRangeID }}Meta Range ID: {{ $val.MetaRangeId }}
 {{ end -}}
+{{ with $val.Stats }}Objects:       {{ .ObjectCount }} ({{ .SizeBytes|human_bytes }}), {{ .ObjectCountDelta | printf "%+d" }} ({{ .SizeBytesDelta|human_bytes_delta }}) from parent
+{{ end -}}
 {{ if gt ($val.Parents|len) 1 -}}
 Merge:         {{ $val.Parents|join ", "|bold }}
 {{ end }}
@@ -68,10 +73,12 @@ func (d *dotWriter) Write(commits []apigen.Commit) {
 
 // logCmd represents the log command
 var logCmd = &cobra.Command{
-	Use:               "log <branch URI>",
-	Short:             "Show log of commits",
-	Long:              "Show log of commits for a given branch",
-	Example:           "lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg",
+	Use:   "log <branch URI>",
+	Short: "Show log of commits",
+	Long:  "Show log of commits for a given branch. With --follow, wait for new commits on the branch and show them as they land",
+	Example: `lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg
+lakectl log --graph --amount 20 lakefs://example-repository/main
+lakectl log --follow --exec 'echo $LAKECTL_COMMIT_ID' lakefs://example-repository/main`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: ValidArgsRepository,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -80,10 +87,17 @@ var logCmd = &cobra.Command{
 		limit := Must(cmd.Flags().GetBool("limit"))
 		since := Must(cmd.Flags().GetString("since"))
 		dot := Must(cmd.Flags().GetBool("dot"))
+		showGraph := Must(cmd.Flags().GetBool("graph"))
 		firstParent := Must(cmd.Flags().GetBool("first-parent"))
 		objects := Must(cmd.Flags().GetStringSlice("objects"))
 		prefixes := Must(cmd.Flags().GetStringSlice("prefixes"))
 		stopAt := Must(cmd.Flags().GetString("stop-at"))
+		grep := Must(cmd.Flags().GetString("grep"))
+		author := Must(cmd.Flags().GetString("author"))
+		meta := Must(cmd.Flags().GetStringSlice(metaFlagName))
+		follow := Must(cmd.Flags().GetBool("follow"))
+		interval := Must(cmd.Flags().GetDuration("interval"))
+		execCommand := Must(cmd.Flags().GetString("exec"))
 
 		if slices.Contains(objects, "") {
 			Die("Objects list contains empty string!", 1)
@@ -91,6 +105,22 @@ var logCmd = &cobra.Command{
 		if slices.Contains(prefixes, "") {
 			Die("Prefixes list contains empty string!", 1)
 		}
+		if follow && dot {
+			Die("Can't use --follow with --dot", 1)
+		}
+		if showGraph && (dot || follow) {
+			Die("Can't use --graph with --dot or --follow", 1)
+		}
+		if showGraph && (len(objects) > 0 || len(prefixes) > 0 || grep != "" || author != "" || len(meta) > 0) {
+			// filtered logs skip commits, which would leave the lanes of their children dangling
+			Die("Can't use --graph with commit filters", 1)
+		}
+		if execCommand != "" && !follow {
+			Die("--exec requires --follow", 1)
+		}
+		if follow && interval <= 0 {
+			Die("--interval must be positive", 1)
+		}
 
 		pagination := apigen.Pagination{HasMore: true}
 		showMetaRangeID := Must(cmd.Flags().GetBool("show-meta-range-id"))
@@ -113,6 +143,15 @@ var logCmd = &cobra.Command{
 		if len(prefixes) > 0 {
 			logCommitsParams.Prefixes = &prefixes
 		}
+		if grep != "" {
+			logCommitsParams.Message = apiutil.Ptr(apigen.CommitSearchMessage(grep))
+		}
+		if author != "" {
+			logCommitsParams.Committer = apiutil.Ptr(apigen.CommitSearchCommitter(author))
+		}
+		if len(meta) > 0 {
+			logCommitsParams.Metadata = apiutil.Ptr(apigen.CommitSearchMetadata(meta))
+		}
 		if since != "" {
 			sinceParsed, err := time.Parse(time.RFC3339, since)
 			if err != nil {
@@ -121,6 +160,24 @@ var logCmd = &cobra.Command{
 			logCommitsParams.Since = &sinceParsed
 		}
 
+		if follow {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			followLog(ctx, client, branchURI, logCommitsParams, interval, execCommand, showMetaRangeID)
+			return
+		}
+
+		if showGraph {
+			logGraph(cmd.Context(), client, branchURI, &apigen.GetCommitGraphParams{
+				After:       logCommitsParams.After,
+				Amount:      logCommitsParams.Amount,
+				Refs:        &[]string{branchURI.Ref},
+				FirstParent: &firstParent,
+				Since:       logCommitsParams.Since,
+			}, amount != 0, stopAt)
+			return
+		}
+
 		graph := &dotWriter{
 			w:            os.Stdout,
 			repositoryID: branchURI.Repository,
@@ -176,10 +233,17 @@ func init() {
 	logCmd.Flags().Bool("limit", false, "limit result just to amount. By default, returns whether more items are available.")
 	logCmd.Flags().String("after", "", "show results after this value (used for pagination)")
 	logCmd.Flags().Bool("dot", false, "return results in a dotgraph format")
+	logCmd.Flags().Bool("graph", false, "draw an ASCII graph of the commits and their merge parents, one line per commit, decorated with the branches and tags pointing at them")
 	logCmd.Flags().Bool("first-parent", false, "follow only the first parent commit upon seeing a merge commit")
 	logCmd.Flags().Bool("show-meta-range-id", false, "also show meta range ID")
 	logCmd.Flags().StringSlice("objects", nil, "show results that contains changes to at least one path in that list of objects. Use comma separator to pass all objects together")
 	logCmd.Flags().StringSlice("prefixes", nil, "show results that contains changes to at least one path in that list of prefixes. Use comma separator to pass all prefixes together")
 	logCmd.Flags().String("since", "", "show results since this date-time (RFC3339 format)")
 	logCmd.Flags().String("stop-at", "", "a Ref to stop at (included in results)")
+	logCmd.Flags().String("grep", "", "show only commits whose message contains this string")
+	logCmd.Flags().String("author", "", "show only commits made by this committer")
+	logCmd.Flags().StringSlice(metaFlagName, nil, "show only commits with this metadata, in the form of key=value. Use comma separator or repeat the flag to match multiple pairs")
+	logCmd.Flags().Bool("follow", false, "wait for new commits on the branch and show them as they land, until interrupted")
+	logCmd.Flags().Duration("interval", defaultFollowInterval, "how often to check the branch for new commits when following")
+	logCmd.Flags().String("exec", "", "command to run for each new commit when following. The commit is passed in LAKECTL_COMMIT_ID, LAKECTL_COMMIT_MESSAGE, LAKECTL_COMMITTER and LAKECTL_METARANGE_ID environment variables")
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const defaultFollowInterval = 2 * time.Second
+
+// followLog polls the branch head every interval and prints commits as they land on the branch, oldest first,
+// until ctx is done. When execCommand is set, it runs for each new commit.
+func followLog(ctx context.Context, client apigen.ClientWithResponsesInterface, branchURI *uri.URI, params *apigen.LogCommitsParams, interval time.Duration, execCommand string, showMetaRangeID bool) {
+	head := branchHead(ctx, client, branchURI)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		currentHead := branchHead(ctx, client, branchURI)
+		if currentHead == head {
+			continue
+		}
+		commits := commitsSince(ctx, client, branchURI, params, head)
+		for i := len(commits) - 1; i >= 0; i-- {
+			commit := commits[i]
+			Write(commitsTemplate, struct {
+				Commits         []apigen.Commit
+				Pagination      *Pagination
+				ShowMetaRangeID bool
+			}{
+				Commits:         []apigen.Commit{commit},
+				ShowMetaRangeID: showMetaRangeID,
+			})
+			if execCommand != "" {
+				if err := followExecCommand(ctx, branchURI, &commit, execCommand).Run(); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "Command failed for commit %s: %s\n", commit.Id, err)
+				}
+			}
+		}
+		head = currentHead
+	}
+}
+
+func branchHead(ctx context.Context, client apigen.ClientWithResponsesInterface, branchURI *uri.URI) string {
+	resp, err := client.GetBranchWithResponse(ctx, branchURI.Repository, branchURI.Ref)
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	return resp.JSON200.CommitId
+}
+
+// commitsSince lists the commits on the branch newer than commitID, newest first
+func commitsSince(ctx context.Context, client apigen.ClientWithResponsesInterface, branchURI *uri.URI, params *apigen.LogCommitsParams, commitID string) []apigen.Commit {
+	logParams := *params
+	logParams.After = nil
+	logParams.Amount = apiutil.Ptr(apigen.PaginationAmount(internalPageSize))
+	logParams.StopAt = apiutil.Ptr(commitID)
+	var commits []apigen.Commit
+	for {
+		resp, err := client.LogCommitsWithResponse(ctx, branchURI.Repository, branchURI.Ref, &logParams)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, commit := range resp.JSON200.Results {
+			// stop-at commit is included in the results, it was already seen
+			if commit.Id == commitID {
+				return commits
+			}
+			commits = append(commits, commit)
+		}
+		if !resp.JSON200.Pagination.HasMore {
+			return commits
+		}
+		logParams.After = apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset))
+	}
+}
+
+func followExecCommand(ctx context.Context, branchURI *uri.URI, commit *apigen.Commit, command string) *exec.Cmd {
+	var runCommand *exec.Cmd
+	if runtime.GOOS == "windows" {
+		runCommand = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		runCommand = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	// prepare env - add LAKECTL_* based on commit
+	runCommand.Env = runCommand.Environ()
+	runCommand.Env = append(runCommand.Env, "LAKECTL_REPOSITORY="+branchURI.Repository)
+	runCommand.Env = append(runCommand.Env, "LAKECTL_BRANCH="+branchURI.Ref)
+	runCommand.Env = append(runCommand.Env, "LAKECTL_COMMIT_ID="+commit.Id)
+	runCommand.Env = append(runCommand.Env, "LAKECTL_COMMIT_MESSAGE="+commit.Message)
+	runCommand.Env = append(runCommand.Env, "LAKECTL_COMMITTER="+commit.Committer)
+	runCommand.Env = append(runCommand.Env, "LAKECTL_METARANGE_ID="+commit.MetaRangeId)
+
+	// set output to the process output
+	runCommand.Stdout = os.Stdout
+	runCommand.Stderr = os.Stderr
+	return runCommand
+}
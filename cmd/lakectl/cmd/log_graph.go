@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const graphShortIDLength = 8
+
+// graphWriter renders commits as an ASCII graph of their parents, like "git log --graph". Commits must be written
+// children first, as returned by the commit graph. Each lane of the graph holds the commit expected next on it.
+type graphWriter struct {
+	w           io.Writer
+	firstParent bool
+	lanes       []string
+}
+
+// graphPath is a line of the graph moving from a lane to another, one column per row
+type graphPath struct {
+	pos, target int
+}
+
+func (g *graphWriter) writeCommit(commit apigen.CommitGraphNode) {
+	col := indexOf(g.lanes, commit.Id)
+	if col == -1 {
+		g.lanes = append(g.lanes, commit.Id)
+		col = len(g.lanes) - 1
+	}
+	_, _ = fmt.Fprintf(g.w, "%s %s\n", g.commitRow(col), g.describe(commit))
+
+	parents := commit.Parents
+	if g.firstParent && len(parents) > 1 {
+		parents = parents[:1]
+	}
+	// lanes after the commit: the commit is replaced by its parents, and lanes expecting the same commit are joined
+	var next []string
+	add := func(id string) int {
+		if i := indexOf(next, id); i != -1 {
+			return i
+		}
+		next = append(next, id)
+		return len(next) - 1
+	}
+	var paths []graphPath
+	for i, id := range g.lanes {
+		if i != col {
+			paths = append(paths, graphPath{pos: i, target: add(id)})
+			continue
+		}
+		for _, parent := range parents {
+			paths = append(paths, graphPath{pos: i, target: add(parent)})
+		}
+	}
+	g.writeTransition(paths)
+	g.lanes = next
+}
+
+func (g *graphWriter) commitRow(col int) string {
+	var b strings.Builder
+	for i := range g.lanes {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if i == col {
+			b.WriteByte('*')
+		} else {
+			b.WriteByte('|')
+		}
+	}
+	return b.String()
+}
+
+// writeTransition draws the rows moving paths to their target lanes, nothing when all paths stay in their lanes
+func (g *graphWriter) writeTransition(paths []graphPath) {
+	for {
+		moving := false
+		for _, p := range paths {
+			if p.pos != p.target {
+				moving = true
+				break
+			}
+		}
+		if !moving {
+			return
+		}
+		var row []byte
+		put := func(i int, c byte) {
+			for len(row) <= i {
+				row = append(row, ' ')
+			}
+			row[i] = c
+		}
+		for i := range paths {
+			p := &paths[i]
+			switch {
+			case p.target < p.pos:
+				put(2*p.pos-1, '/')
+				p.pos--
+			case p.target > p.pos:
+				put(2*p.pos+1, '\\')
+				p.pos++
+			default:
+				put(2*p.pos, '|')
+			}
+		}
+		_, _ = fmt.Fprintln(g.w, string(row))
+	}
+}
+
+func (g *graphWriter) describe(commit apigen.CommitGraphNode) string {
+	id := commit.Id
+	if len(id) > graphShortIDLength {
+		id = id[:graphShortIDLength]
+	}
+	s := text.FgHiYellow.Sprint(id)
+	refs := make([]string, 0, len(commit.Branches)+len(commit.Tags))
+	for _, branch := range commit.Branches {
+		refs = append(refs, text.FgHiGreen.Sprint(branch))
+	}
+	for _, tag := range commit.Tags {
+		refs = append(refs, text.FgHiBlue.Sprint("tag: "+tag))
+	}
+	if len(refs) > 0 {
+		s += " (" + strings.Join(refs, ", ") + ")"
+	}
+	message, _, _ := strings.Cut(commit.Message, "\n")
+	return s + " " + message
+}
+
+func indexOf(lanes []string, id string) int {
+	for i, lane := range lanes {
+		if lane == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// logGraph draws the commit graph of a branch, fetched page by page, up to the stopAt reference when not empty
+func logGraph(ctx context.Context, client apigen.ClientWithResponsesInterface, u *uri.URI, params *apigen.GetCommitGraphParams, onePage bool, stopAt string) {
+	if stopAt != "" {
+		resp, err := client.GetCommitWithResponse(ctx, u.Repository, stopAt)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		stopAt = resp.JSON200.Id
+	}
+	g := &graphWriter{
+		w:           os.Stdout,
+		firstParent: swag.BoolValue(params.FirstParent),
+	}
+	for {
+		resp, err := client.GetCommitGraphWithResponse(ctx, u.Repository, params)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, commit := range resp.JSON200.Results {
+			g.writeCommit(commit)
+			if commit.Id == stopAt {
+				return
+			}
+		}
+		if onePage || !resp.JSON200.Pagination.HasMore {
+			return
+		}
+		params.After = apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset))
+	}
+}
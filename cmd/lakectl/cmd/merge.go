@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
@@ -14,8 +16,13 @@ const (
 
 	mergeCreateTemplate = `Merged "{{.Merge.FromRef|yellow}}" into "{{.Merge.ToRef|yellow}}" to get "{{.Result.Reference|green}}".
 `
+
+	mergeStrategyFail = "fail"
 )
 
+// mergeStrategies are the values of the strategy flag, resolving conflicts by favoring one side or failing the merge
+var mergeStrategies = []string{"source-wins", "dest-wins", mergeStrategyFail}
+
 type FromTo struct {
 	FromRef string
 	ToRef   string
@@ -39,14 +46,15 @@ var mergeCmd = &cobra.Command{
 		sourceRef := MustParseBranchURI("source ref", args[0])
 		destinationRef := MustParseBranchURI("destination ref", args[1])
 		strategy := Must(cmd.Flags().GetString("strategy"))
+		ffOnly := Must(cmd.Flags().GetBool("ff-only"))
 		fmt.Println("Source:", sourceRef)
 		fmt.Println("Destination:", destinationRef)
 		if destinationRef.Repository != sourceRef.Repository {
 			Die("both references must belong to the same repository", 1)
 		}
 
-		if strategy != "dest-wins" && strategy != "source-wins" && strategy != "" {
-			Die("Invalid strategy value. Expected \"dest-wins\" or \"source-wins\"", 1)
+		if strategy != "" && !slices.Contains(mergeStrategies, strategy) {
+			DieFmt("Invalid strategy value. Expected one of: %s", strings.Join(mergeStrategies, ", "))
 		}
 
 		body := apigen.MergeIntoBranchJSONRequestBody{
@@ -54,8 +62,17 @@ var mergeCmd = &cobra.Command{
 			Metadata: &apigen.Merge_Metadata{AdditionalProperties: kvPairs},
 			Strategy: &strategy,
 		}
+		if ffOnly {
+			body.FastForwardOnly = &ffOnly
+		}
 		resp, err := client.MergeIntoBranchWithResponse(cmd.Context(), destinationRef.Repository, sourceRef.Ref, destinationRef.Ref, body)
 		if resp != nil && resp.JSON409 != nil {
+			if ffOnly {
+				Die("Not possible to fast-forward: destination is not an ancestor of source.", 1)
+			}
+			if strategy == "" || strategy == mergeStrategyFail {
+				Die("Conflict found. Use --strategy source-wins or --strategy dest-wins to resolve conflicts automatically.", 1)
+			}
 			Die("Conflict found.", 1)
 		}
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
@@ -78,7 +95,11 @@ var mergeCmd = &cobra.Command{
 
 //nolint:gochecknoinits
 func init() {
-	mergeCmd.Flags().String("strategy", "", "In case of a merge conflict, this option will force the merge process to automatically favor changes from the dest branch (\"dest-wins\") or from the source branch(\"source-wins\"). In case no selection is made, the merge process will fail in case of a conflict")
+	mergeCmd.Flags().String("strategy", "", "In case of a merge conflict, this option will force the merge process to automatically favor changes from the dest branch (\"dest-wins\") or from the source branch(\"source-wins\"). With \"fail\", or in case no selection is made, the merge process will fail in case of a conflict")
+	_ = mergeCmd.RegisterFlagCompletionFunc("strategy", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return mergeStrategies, cobra.ShellCompDirectiveNoFileComp
+	})
+	mergeCmd.Flags().Bool("ff-only", false, "move the destination branch to the source commit without creating a merge commit, fail unless the destination is an ancestor of the source")
 	withCommitFlags(mergeCmd, true)
 	rootCmd.AddCommand(mergeCmd)
 }
@@ -12,9 +12,8 @@ import (
 )
 
 var metastoreCmd = &cobra.Command{
-	Use:        "metastore",
-	Short:      "Manage metastore commands",
-	Deprecated: "Upcoming releases of lakectl will no longer support this command.",
+	Use:   "metastore",
+	Short: "Manage metastore commands",
 }
 
 func getGlueClient(c *Configuration) *glue.Client {
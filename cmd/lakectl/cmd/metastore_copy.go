@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,10 +12,13 @@ import (
 )
 
 var metastoreCopyCmd = &cobra.Command{
-	Use:        "copy",
-	Short:      "Copy or merge table",
-	Long:       "Copy or merge table. the destination table will point to the selected branch",
-	Deprecated: "Upcoming releases of lakectl will no longer support this command.",
+	Use:   "copy",
+	Short: "Copy or merge table",
+	Long: `Copy or merge table. the destination table will point to the selected branch.
+The locations of the table and its partitions are rewritten from their branch to the destination branch, in order to
+create a table of a new branch, or to update the table of the main branch after merging the branch back.`,
+	Example: `lakectl metastore copy --from-schema default --from-table events --from-branch main --to-branch feature
+lakectl metastore copy --from-schema feature --from-table events --from-branch feature --to-schema default --to-branch main`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fromClientType := Must(cmd.Flags().GetString("from-client-type"))
 		fromDB := Must(cmd.Flags().GetString("from-schema"))
@@ -21,6 +26,7 @@ var metastoreCopyCmd = &cobra.Command{
 		toClientType := Must(cmd.Flags().GetString("to-client-type"))
 		toDB := Must(cmd.Flags().GetString("to-schema"))
 		toTable := Must(cmd.Flags().GetString("to-table"))
+		fromBranch := Must(cmd.Flags().GetString("from-branch"))
 		toBranch := Must(cmd.Flags().GetString("to-branch"))
 		serde := Must(cmd.Flags().GetString("serde"))
 		partition := Must(cmd.Flags().GetStringSlice("partition"))
@@ -30,6 +36,7 @@ var metastoreCopyCmd = &cobra.Command{
 		defer fromClientDeferFunc()
 		toClient, toClientDeferFunc := getMetastoreClient(toClientType, "")
 		defer toClientDeferFunc()
+		verifyMetastoreCopyBranch(cmd.Context(), fromClient, fromDB, fromTable, toBranch, dbfsLocation)
 		if len(toDB) == 0 {
 			toDB = toBranch
 		}
@@ -46,18 +53,38 @@ var metastoreCopyCmd = &cobra.Command{
 			"to_client_type":   toClientType,
 			"to_schema":        toDB,
 			"to_table":         toTable,
+			"from_branch":      fromBranch,
 			"to_branch":        toBranch,
 			"serde":            serde,
 			"partition":        partition,
 		}).Info("Metadata copy or merge table")
 		fmt.Printf("copy %s.%s -> %s.%s\n", fromDB, fromTable, toDB, toTable)
-		err := metastore.CopyOrMerge(cmd.Context(), fromClient, toClient, fromDB, fromTable, toDB, toTable, toBranch, serde, partition, cfg.Metastore.FixSparkPlaceholder, dbfsLocation)
+		err := metastore.CopyOrMerge(cmd.Context(), fromClient, toClient, fromDB, fromTable, toDB, toTable, fromBranch, toBranch, serde, partition, cfg.Metastore.FixSparkPlaceholder, dbfsLocation)
 		if err != nil {
 			DieErr(err)
 		}
 	},
 }
 
+// verifyMetastoreCopyBranch fails unless the destination branch exists in the lakeFS repository of the table, so that
+// the copied table does not point to a missing location
+func verifyMetastoreCopyBranch(ctx context.Context, client metastore.ReadClient, db, table, branch, dbfsLocation string) {
+	tbl, err := client.GetTable(ctx, db, table)
+	if err != nil {
+		DieErr(err)
+	}
+	if tbl.Sd == nil || tbl.Sd.Location == "" {
+		return
+	}
+	location := metastore.HandleDBFSLocation(ctx, tbl.Sd.Location, dbfsLocation)
+	repo, _, err := metastore.ExtractRepoAndBranch(location)
+	if err != nil {
+		DieErr(err)
+	}
+	resp, err := getClient().GetBranchWithResponse(ctx, repo, branch)
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+}
+
 //nolint:gochecknoinits
 func init() {
 	_ = metastoreCopyCmd.Flags().String("from-client-type", "", "metastore type [hive, glue]")
@@ -72,6 +99,7 @@ func init() {
 	_ = metastoreCopyCmd.Flags().String("to-client-type", "", "metastore type [hive, glue]")
 	_ = metastoreCopyCmd.Flags().String("to-schema", "", "destination schema name [default is from-branch]")
 	_ = metastoreCopyCmd.Flags().String("to-table", "", "destination table name [default is  from-table] ")
+	_ = metastoreCopyCmd.Flags().String("from-branch", "", "lakeFS branch of the source table, fail if the table or its partitions are on another branch")
 	_ = metastoreCopyCmd.Flags().String("to-branch", "", "lakeFS branch name")
 	_ = metastoreCopyCmd.MarkFlagRequired("to-branch")
 	_ = metastoreCopyCmd.Flags().String("serde", "", "serde to set copy to  [default is  to-table]")
@@ -3,19 +3,32 @@ package cmd
 import (
 	"net/http"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/metastore"
 )
 
+const symlinkManifestsTemplate = `Commit:    {{ .CommitId | yellow }}
+Location:  {{ .Location }}
+Manifests: {{ .ManifestsCount }}
+Objects:   {{ .ObjectsCount }}
+`
+
 var metastoreCreateSymlinkCmd = &cobra.Command{
-	Use:        "create-symlink",
-	Short:      "Create symlink table and data",
-	Long:       "create table with symlinks, and create the symlinks in s3 in order to access from external services that could only access s3 directly (e.g athena)",
-	Deprecated: "Upcoming releases of lakectl will no longer support this command.",
+	Use:   "create-symlink",
+	Short: "Create symlink manifests of a table at a lakeFS version, and optionally a table reading them",
+	Long: `Create Hive symlink manifests listing the objects of a table path at the commit of a ref, in order to query that
+exact version from external services that could only access the object store directly (e.g. Athena, Trino).
+Manifests are written to the storage namespace of the repository, under a location unique to the commit.
+When the source and destination tables are set, also copy the source table to the destination table reading the
+manifests.`,
+	Example: `lakectl metastore create-symlink --repo example-repo --ref v1.2 --path tables/events
+lakectl metastore create-symlink --repo example-repo --ref main --path tables/events --from-schema default --from-table events --to-schema snapshots --to-table events_v1`,
 	Run: func(cmd *cobra.Command, args []string) {
 		repo := Must(cmd.Flags().GetString("repo"))
+		ref := Must(cmd.Flags().GetString("ref"))
 		branch := Must(cmd.Flags().GetString("branch"))
 		path := Must(cmd.Flags().GetString("path"))
 		fromDB := Must(cmd.Flags().GetString("from-schema"))
@@ -23,23 +36,34 @@ var metastoreCreateSymlinkCmd = &cobra.Command{
 		toDB := Must(cmd.Flags().GetString("to-schema"))
 		toTable := Must(cmd.Flags().GetString("to-table"))
 		fromClientType := Must(cmd.Flags().GetString("from-client-type"))
+		if ref == "" {
+			ref = branch
+		}
+		if ref == "" {
+			Die("Missing ref", 1)
+		}
+		copyTable := fromDB != "" || fromTable != "" || toDB != "" || toTable != ""
+		if copyTable && (fromDB == "" || fromTable == "" || toDB == "" || toTable == "") {
+			Die("Copying the table requires from-schema, from-table, to-schema and to-table", 1)
+		}
 
 		apiClient := getClient()
-		fromClient, toClient, fromClientDeferFunc, toClientDeferFunc := getClients(fromClientType, "glue", "", "")
-		defer fromClientDeferFunc()
-		defer toClientDeferFunc()
-
-		resp, err := apiClient.CreateSymlinkFileWithResponse(cmd.Context(), repo, branch, &apigen.CreateSymlinkFileParams{Location: &path})
-		if err != nil {
-			DieErr(err)
-		}
+		resp, err := apiClient.CreateSymlinkManifestsWithResponse(cmd.Context(), repo, ref, apigen.CreateSymlinkManifestsJSONRequestBody{
+			Prefix: swag.String(path),
+		})
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
 		if resp.JSON201 == nil {
 			Die("Bad response from server", 1)
 		}
-		location := resp.JSON201.Location
+		Write(symlinkManifestsTemplate, resp.JSON201)
+		if !copyTable {
+			return
+		}
 
-		err = metastore.CopyOrMergeToSymlink(cmd.Context(), fromClient, toClient, fromDB, fromTable, toDB, toTable, location, cfg.Metastore.FixSparkPlaceholder)
+		fromClient, toClient, fromClientDeferFunc, toClientDeferFunc := getClients(fromClientType, "glue", "", "")
+		defer fromClientDeferFunc()
+		defer toClientDeferFunc()
+		err = metastore.CopyOrMergeToSymlinkManifests(cmd.Context(), fromClient, toClient, fromDB, fromTable, toDB, toTable, path, resp.JSON201.Location, cfg.Metastore.FixSparkPlaceholder)
 		if err != nil {
 			DieErr(err)
 		}
@@ -50,20 +74,18 @@ var metastoreCreateSymlinkCmd = &cobra.Command{
 func init() {
 	_ = metastoreCreateSymlinkCmd.Flags().String("repo", "", "lakeFS repository name")
 	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("repo")
+	_ = metastoreCreateSymlinkCmd.Flags().String("ref", "", "lakeFS ref (branch, tag or commit ID) of the version to query")
 	_ = metastoreCreateSymlinkCmd.Flags().String("branch", "", "lakeFS branch name")
-	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("branch")
+	_ = metastoreCreateSymlinkCmd.Flags().MarkDeprecated("branch", "use --ref instead")
+	metastoreCreateSymlinkCmd.MarkFlagsMutuallyExclusive("ref", "branch")
 	_ = metastoreCreateSymlinkCmd.Flags().String("path", "", "path to table on lakeFS")
 	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("path")
 	_ = metastoreCreateSymlinkCmd.Flags().String("catalog-id", "", "Glue catalog ID")
 	_ = viper.BindPFlag("metastore.glue.catalog_id", metastoreCreateSymlinkCmd.Flag("catalog-id"))
 	_ = metastoreCreateSymlinkCmd.Flags().String("from-schema", "", "source schema name")
-	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("from-schema")
 	_ = metastoreCreateSymlinkCmd.Flags().String("from-table", "", "source table name")
-	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("from-table")
 	_ = metastoreCreateSymlinkCmd.Flags().String("to-schema", "", "destination schema name")
-	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("to-schema")
 	_ = metastoreCreateSymlinkCmd.Flags().String("to-table", "", "destination table name")
-	_ = metastoreCreateSymlinkCmd.MarkFlagRequired("to-table")
 	_ = metastoreCreateSymlinkCmd.Flags().String("from-client-type", "", "metastore type [hive, glue]")
 
 	metastoreCmd.AddCommand(metastoreCreateSymlinkCmd)
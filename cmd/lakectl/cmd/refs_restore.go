@@ -24,7 +24,9 @@ var refsRestoreCmd = &cobra.Command{
 	Long: `restores refs (branches, commits, tags) from the underlying object store to a bare repository.
 
 This command is expected to run on a bare repository (i.e. one created with 'lakectl repo create-bare').
-Since a bare repo is expected, in case of transient failure, delete the repository and recreate it as bare and retry.`,
+Since a bare repo is expected, in case of transient failure, delete the repository and recreate it as bare and retry.
+Use --replace to roll back a repository with commits to the dump instead: its branches and tags in the dump are reset,
+discarding the uncommitted changes of these branches, and branches and tags created after the dump are kept.`,
 	Example: "aws s3 cp s3://bucket/_lakefs/refs_manifest.json - | lakectl refs-restore lakefs://my-bare-repository --manifest -",
 	Hidden:  true,
 	Args:    cobra.ExactArgs(1),
@@ -53,6 +55,9 @@ Since a bare repo is expected, in case of transient failure, delete the reposito
 		if err != nil {
 			DieErr(err)
 		}
+		if replace := Must(cmd.Flags().GetBool("replace")); replace {
+			manifest.Replace = &replace
+		}
 		// execute the restore operation
 		client := getClient()
 		ctx := cmd.Context()
@@ -108,5 +113,6 @@ func init() {
 	refsRestoreCmd.Flags().String("manifest", "", "path to a refs manifest json file (as generated by `refs-dump`). Alternatively, use \"-\" to read from stdin")
 	refsRestoreCmd.Flags().Duration("poll-interval", defaultPollInterval, "poll status check interval")
 	refsRestoreCmd.Flags().Duration("timeout", defaultPollTimeout, "timeout for polling status checks")
+	refsRestoreCmd.Flags().Bool("replace", false, "roll back a repository with commits to the refs of the manifest, instead of restoring into a bare repository")
 	_ = refsRestoreCmd.MarkFlagRequired("manifest")
 }
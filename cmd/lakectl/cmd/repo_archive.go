@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	repoArchiveManifestName   = ".lakefs_manifest.json"
+	repoArchiveFileMode       = 0o644
+	repoArchiveOutputFlagName = "output"
+	repoArchiveCompressFlag   = "compression"
+
+	archiveCompressionAuto = "auto"
+	archiveCompressionNone = "none"
+	archiveCompressionGzip = "gzip"
+	archiveCompressionZstd = "zstd"
+)
+
+var (
+	errUnknownCompression = errors.New("unknown compression")
+	errObjectSizeMismatch = errors.New("object size differs from its listed size")
+)
+
+// archiveManifest describes the content of an archive, written as its first file
+type archiveManifest struct {
+	Repository string                  `json:"repository"`
+	Ref        string                  `json:"ref"`
+	CommitID   string                  `json:"commit_id"`
+	Prefix     string                  `json:"prefix,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Objects    []archiveManifestObject `json:"objects"`
+}
+
+type archiveManifestObject struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	Checksum     string    `json:"checksum"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+const repoArchiveSummaryTemplate = `Archived {{ .Objects | yellow }} objects ({{ .Size | human_bytes }}) of commit {{ .CommitID | yellow }} to {{ .Output }}
+`
+
+var repoArchiveCmd = &cobra.Command{
+	Use:   "archive <ref URI>",
+	Short: "Archive all objects at a ref into a tar file",
+	Long: `Archive all objects at a ref, or under a path of it, into a tar file accompanied by a manifest.
+
+The ref is resolved to a commit, so the archive holds the objects of that commit, without uncommitted changes.
+The first file of the archive, ` + repoArchiveManifestName + `, lists the commit ID and the path, size and
+checksum of every archived object. The archive is compressed according to the extension of the output file
+(.tar.gz, .tgz, .tar.zst or .tzst) unless --compression is set. Objects are downloaded using pre-signed URLs
+when supported by the server, so their data does not pass through it.`,
+	Example: "lakectl repo archive lakefs://example-repo/main/ --output dataset.tar.zst\n" +
+		"lakectl repo archive lakefs://example-repo/v1.0/datasets/images/ --output - | ssh partner 'cat > images.tar'",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("ref URI", args[0])
+		output := Must(cmd.Flags().GetString(repoArchiveOutputFlagName))
+		compression := Must(cmd.Flags().GetString(repoArchiveCompressFlag))
+		if compression == archiveCompressionAuto {
+			compression = archiveCompressionFromName(output)
+		}
+		if !slices.Contains([]string{archiveCompressionNone, archiveCompressionGzip, archiveCompressionZstd}, compression) {
+			DieFmt("Unknown compression %s", compression)
+		}
+		client := getClient()
+		presign := getPresignMode(cmd, client).Enabled
+		ctx := cmd.Context()
+
+		commitResp, err := client.GetCommitWithResponse(ctx, pathURI.Repository, pathURI.Ref)
+		DieOnErrorOrUnexpectedStatusCode(commitResp, err, http.StatusOK)
+		if commitResp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		manifest := &archiveManifest{
+			Repository: pathURI.Repository,
+			Ref:        pathURI.Ref,
+			CommitID:   commitResp.JSON200.Id,
+			Prefix:     pathURI.GetPath(),
+			CreatedAt:  time.Now().UTC(),
+			Objects:    listArchiveObjects(ctx, client, pathURI.Repository, commitResp.JSON200.Id, pathURI.GetPath()),
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				DieErr(err)
+			}
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		err = writeArchive(ctx, client, presign, w, compression, manifest)
+		if err != nil {
+			if output != "-" {
+				_ = os.Remove(output)
+			}
+			DieErr(err)
+		}
+
+		var size int64
+		for _, o := range manifest.Objects {
+			size += o.Size
+		}
+		if output == "-" {
+			output = "stdout"
+		}
+		WriteTo(repoArchiveSummaryTemplate, struct {
+			Objects  int
+			Size     int64
+			CommitID string
+			Output   string
+		}{
+			Objects:  len(manifest.Objects),
+			Size:     size,
+			CommitID: manifest.CommitID,
+			Output:   output,
+		}, os.Stderr)
+	},
+}
+
+// archiveCompressionFromName returns the compression of an archive by the extension of its file name
+func archiveCompressionFromName(name string) string {
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return archiveCompressionGzip
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		return archiveCompressionZstd
+	default:
+		return archiveCompressionNone
+	}
+}
+
+// listArchiveObjects returns the objects under prefix at commitID, without directory markers
+func listArchiveObjects(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, commitID, prefix string) []archiveManifestObject {
+	var objects []archiveManifestObject
+	var after string
+	for {
+		resp, err := client.ListObjectsWithResponse(ctx, repository, commitID, &apigen.ListObjectsParams{
+			After:  (*apigen.PaginationAfter)(swag.String(after)),
+			Prefix: (*apigen.PaginationPrefix)(swag.String(prefix)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		for _, o := range resp.JSON200.Results {
+			if strings.HasSuffix(o.Path, uri.PathSeparator) {
+				continue
+			}
+			objects = append(objects, archiveManifestObject{
+				Path:         o.Path,
+				Size:         swag.Int64Value(o.SizeBytes),
+				Checksum:     o.Checksum,
+				ContentType:  swag.StringValue(o.ContentType),
+				LastModified: time.Unix(o.Mtime, 0).UTC(),
+			})
+		}
+		if !resp.JSON200.Pagination.HasMore {
+			return objects
+		}
+		after = resp.JSON200.Pagination.NextOffset
+	}
+}
+
+// writeArchive writes the manifest followed by the content of its objects as a tar archive to w.
+// Objects are stored by their path relative to the prefix of the manifest.
+func writeArchive(ctx context.Context, client *apigen.ClientWithResponses, presign bool, w io.Writer, compression string, manifest *archiveManifest) error {
+	var compressor io.WriteCloser
+	switch compression {
+	case archiveCompressionNone:
+	case archiveCompressionGzip:
+		compressor = gzip.NewWriter(w)
+	case archiveCompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		compressor = zw
+	default:
+		return fmt.Errorf("%w: %s", errUnknownCompression, compression)
+	}
+	if compressor != nil {
+		w = compressor
+	}
+	tw := tar.NewWriter(w)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     repoArchiveManifestName,
+		Size:     int64(len(manifestData)),
+		Mode:     repoArchiveFileMode,
+		ModTime:  manifest.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return err
+	}
+
+	for _, o := range manifest.Objects {
+		if err := writeArchiveObject(ctx, client, presign, tw, manifest, o); err != nil {
+			return fmt.Errorf("archive %s: %w", o.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if compressor != nil {
+		return compressor.Close()
+	}
+	return nil
+}
+
+func writeArchiveObject(ctx context.Context, client *apigen.ClientWithResponses, presign bool, tw *tar.Writer, manifest *archiveManifest, o archiveManifestObject) error {
+	resp, err := client.GetObject(ctx, manifest.Repository, manifest.CommitID, &apigen.GetObjectParams{
+		Path:    o.Path,
+		Presign: swag.Bool(presign),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", helpers.ErrRequestFailed, resp.Status)
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     strings.TrimPrefix(strings.TrimPrefix(o.Path, manifest.Prefix), uri.PathSeparator),
+		Size:     o.Size,
+		Mode:     repoArchiveFileMode,
+		ModTime:  o.LastModified,
+	})
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(tw, resp.Body)
+	if errors.Is(err, tar.ErrWriteTooLong) || (err == nil && n != o.Size) {
+		return fmt.Errorf("%w: read %d bytes, listed %d", errObjectSizeMismatch, n, o.Size)
+	}
+	return err
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoArchiveCmd.Flags().StringP(repoArchiveOutputFlagName, "o", "", "output file, or - for stdout")
+	_ = repoArchiveCmd.MarkFlagRequired(repoArchiveOutputFlagName)
+	repoArchiveCmd.Flags().String(repoArchiveCompressFlag, archiveCompressionAuto, "archive compression: auto (by output file extension), none, gzip or zstd")
+	withPresignFlag(repoArchiveCmd)
+
+	repoCmd.AddCommand(repoArchiveCmd)
+}
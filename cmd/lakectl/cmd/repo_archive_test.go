@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/klauspost/compress/zstd"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+func TestArchiveCompressionFromName(t *testing.T) {
+	tests := map[string]string{
+		"data.tar":     archiveCompressionNone,
+		"data.tar.gz":  archiveCompressionGzip,
+		"data.tgz":     archiveCompressionGzip,
+		"data.tar.zst": archiveCompressionZstd,
+		"data.tzst":    archiveCompressionZstd,
+		"-":            archiveCompressionNone,
+	}
+	for name, expected := range tests {
+		if compression := archiveCompressionFromName(name); compression != expected {
+			t.Errorf("archiveCompressionFromName(%s) = %s, expected %s", name, compression, expected)
+		}
+	}
+}
+
+// newArchiveTestClient returns a client of a server serving the content of objects of commit "c1" of repository
+// "repo"
+func newArchiveTestClient(t *testing.T, objects map[string]string) *apigen.ClientWithResponses {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repositories/repo/refs/c1/objects" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		content, ok := objects[r.URL.Query().Get("path")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, content)
+	}))
+	t.Cleanup(server.Close)
+	client, err := apigen.NewClientWithResponses(server.URL + "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// readArchive returns the files of the tar archive in data by name, decompressing it with compression
+func readArchive(t *testing.T, data []byte, compression string) map[string]string {
+	t.Helper()
+	var r io.Reader = bytes.NewReader(data)
+	switch compression {
+	case archiveCompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case archiveCompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	files := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	ctx := context.Background()
+	objects := map[string]string{
+		"data/a.csv":     "a,b\n1,2\n",
+		"data/sub/b.txt": "hello",
+	}
+	client := newArchiveTestClient(t, objects)
+	manifest := &archiveManifest{
+		Repository: "repo",
+		Ref:        "main",
+		CommitID:   "c1",
+		Prefix:     "data/",
+		CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Objects: []archiveManifestObject{
+			{Path: "data/a.csv", Size: int64(len(objects["data/a.csv"])), Checksum: "c-a"},
+			{Path: "data/sub/b.txt", Size: int64(len(objects["data/sub/b.txt"])), Checksum: "c-b"},
+		},
+	}
+	for _, compression := range []string{archiveCompressionNone, archiveCompressionGzip, archiveCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeArchive(ctx, client, false, &buf, compression, manifest); err != nil {
+				t.Fatalf("writeArchive: %s", err)
+			}
+			files := readArchive(t, buf.Bytes(), compression)
+
+			var archivedManifest archiveManifest
+			if err := json.Unmarshal([]byte(files[repoArchiveManifestName]), &archivedManifest); err != nil {
+				t.Fatalf("unmarshal manifest: %s", err)
+			}
+			if diff := deep.Equal(&archivedManifest, manifest); diff != nil {
+				t.Errorf("manifest diff: %s", diff)
+			}
+			delete(files, repoArchiveManifestName)
+			expected := map[string]string{
+				"a.csv":     objects["data/a.csv"],
+				"sub/b.txt": objects["data/sub/b.txt"],
+			}
+			if diff := deep.Equal(files, expected); diff != nil {
+				t.Errorf("archived files diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteArchive_SizeMismatch(t *testing.T) {
+	ctx := context.Background()
+	client := newArchiveTestClient(t, map[string]string{"a.txt": "changed content"})
+	for _, size := range []int64{3, 100} {
+		manifest := &archiveManifest{
+			Repository: "repo",
+			CommitID:   "c1",
+			Objects:    []archiveManifestObject{{Path: "a.txt", Size: size}},
+		}
+		err := writeArchive(ctx, client, false, io.Discard, archiveCompressionNone, manifest)
+		if !errors.Is(err, errObjectSizeMismatch) {
+			t.Errorf("writeArchive of object listed with size %d error = %v, expected %v", size, err, errObjectSizeMismatch)
+		}
+	}
+}
+
+func TestWriteArchive_MissingObject(t *testing.T) {
+	ctx := context.Background()
+	client := newArchiveTestClient(t, nil)
+	manifest := &archiveManifest{
+		Repository: "repo",
+		CommitID:   "c1",
+		Objects:    []archiveManifestObject{{Path: "missing.txt", Size: 1}},
+	}
+	err := writeArchive(ctx, client, false, io.Discard, archiveCompressionNone, manifest)
+	if err == nil || !strings.Contains(err.Error(), "missing.txt") {
+		t.Errorf("writeArchive of a missing object error = %v, expected an error naming the object", err)
+	}
+}
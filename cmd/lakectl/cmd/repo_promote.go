@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// repoPromoteCmd represents the promote repo command
+// lakectl repo promote lakefs://myrepo
+var repoPromoteCmd = &cobra.Command{
+	Use:   "promote <repository URI>",
+	Short: "Promote a replica repository to accept writes",
+	Long: `Promote a read-only replica repository to accept writes, on failover to the installation it is replicated to.
+Replication into the repository stops once it is promoted.`,
+	Example:           "lakectl repo promote " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		clt := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		fmt.Println("Repository:", u)
+		confirmation, err := Confirm(cmd.Flags(), "Are you sure you want to promote repository: "+u.Repository)
+		if err != nil || !confirmation {
+			DieFmt("Promote Repository '%s' aborted\n", u.Repository)
+		}
+		resp, err := clt.PromoteReplicaWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Repository '%s' promoted\n", u.Repository)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	AssignAutoConfirmFlag(repoPromoteCmd.Flags())
+
+	repoCmd.AddCommand(repoPromoteCmd)
+}
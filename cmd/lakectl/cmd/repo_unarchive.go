@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/local"
+	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/sync/errgroup"
+)
+
+// Commit metadata keys recording the provenance of an unarchived archive
+const (
+	unarchiveMetaArchiveName      = "archive_name"
+	unarchiveMetaArchiveSHA256    = "archive_sha256"
+	unarchiveMetaSourceRepository = "archive_source_repository"
+	unarchiveMetaSourceRef        = "archive_source_ref"
+	unarchiveMetaSourceCommitID   = "archive_source_commit_id"
+)
+
+var (
+	errInvalidArchive  = errors.New("invalid archive")
+	errInvalidManifest = errors.New("invalid archive manifest")
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+const repoUnarchiveSummaryTemplate = `Unarchived {{ .Objects | yellow }} objects ({{ .Size | human_bytes }}) of commit {{ .SourceCommitID | yellow }} into {{ .Destination }}
+Commit: {{ .CommitID | yellow }}
+`
+
+// unarchivedObject is an object of an archive extracted to a local file, pending upload
+type unarchivedObject struct {
+	archiveManifestObject
+	file string
+}
+
+var repoUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <archive> <branch URI>",
+	Short: "Import the objects of a tar archive into a branch",
+	Long: `Import the objects of an archive created by "lakectl repo archive" into a branch, under the path of the
+branch URI, and commit them.
+
+The manifest of the archive is validated, and the size and checksum of every object are checked against it
+before anything is uploaded. The branch must have no uncommitted changes. All objects are committed in a single
+commit, with metadata referencing the SHA-256 checksum of the archive and the commit it was created from.
+Compressed archives (gzip or zstd) are detected by their content.`,
+	Example: "lakectl repo unarchive dataset.tar.zst lakefs://example-repo/main/datasets/\n" +
+		"ssh partner 'cat images.tar' | lakectl repo unarchive - lakefs://example-repo/main/images/",
+	Args: cobra.ExactArgs(2), //nolint:gomnd
+	Run: func(cmd *cobra.Command, args []string) {
+		archivePath := args[0]
+		branchURI := MustParsePathURI("branch URI", args[1])
+		message, kvPairs := getCommitFlags(cmd)
+		client := getClient()
+		syncFlags := getSyncFlags(cmd, client)
+		ctx := cmd.Context()
+
+		archiveName := filepath.Base(archivePath)
+		var r io.Reader = os.Stdin
+		if archivePath == "-" {
+			archiveName = "stdin"
+		} else {
+			f, err := os.Open(archivePath)
+			if err != nil {
+				DieErr(err)
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+
+		diffResp, err := client.DiffBranchWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.DiffBranchParams{
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(diffResp, err, http.StatusOK)
+		if diffResp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		if len(diffResp.JSON200.Results) > 0 {
+			DieFmt("Branch %s has uncommitted changes", branchURI.Ref)
+		}
+
+		tmpDir, err := os.MkdirTemp("", "lakectl-unarchive-")
+		if err != nil {
+			DieErr(err)
+		}
+		archiveHash := sha256.New()
+		manifest, objects, err := extractArchive(io.TeeReader(r, archiveHash), tmpDir)
+		if err != nil {
+			_ = os.RemoveAll(tmpDir)
+			DieFmt("%s: %s", archivePath, err)
+		}
+		archiveSHA256 := hex.EncodeToString(archiveHash.Sum(nil))
+
+		prefix := branchURI.GetPath()
+		if prefix != "" && !strings.HasSuffix(prefix, uri.PathSeparator) {
+			prefix += uri.PathSeparator
+		}
+		err = uploadUnarchived(ctx, client, branchURI.Repository, branchURI.Ref, prefix, objects, syncFlags)
+		// extracted files are no longer needed, remove them before exiting on any error
+		_ = os.RemoveAll(tmpDir)
+		if err != nil {
+			DieErr(err)
+		}
+
+		if message == "" {
+			message = "Unarchive " + archiveName
+		}
+		metadata := map[string]string{
+			unarchiveMetaArchiveName:      archiveName,
+			unarchiveMetaArchiveSHA256:    archiveSHA256,
+			unarchiveMetaSourceRepository: manifest.Repository,
+			unarchiveMetaSourceRef:        manifest.Ref,
+			unarchiveMetaSourceCommitID:   manifest.CommitID,
+		}
+		for k, v := range kvPairs {
+			metadata[k] = v
+		}
+		commitResp, err := client.CommitWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:  message,
+			Metadata: &apigen.CommitCreation_Metadata{AdditionalProperties: metadata},
+		})
+		DieOnErrorOrUnexpectedStatusCode(commitResp, err, http.StatusCreated)
+		if commitResp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		var size int64
+		for _, o := range objects {
+			size += o.Size
+		}
+		Write(repoUnarchiveSummaryTemplate, struct {
+			Objects        int
+			Size           int64
+			SourceCommitID string
+			Destination    string
+			CommitID       string
+		}{
+			Objects:        len(objects),
+			Size:           size,
+			SourceCommitID: manifest.CommitID,
+			Destination:    branchURI.String(),
+			CommitID:       commitResp.JSON201.Id,
+		})
+	},
+}
+
+// archiveDecompressor returns a reader of the tar stream of an archive, detecting its compression by its magic bytes
+func archiveDecompressor(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return br, nil
+	}
+}
+
+// validateArchiveManifest checks that the manifest identifies its source commit and lists each object once
+func validateArchiveManifest(manifest *archiveManifest) error {
+	if manifest.CommitID == "" {
+		return fmt.Errorf("%w: missing commit_id", errInvalidManifest)
+	}
+	paths := make(map[string]struct{}, len(manifest.Objects))
+	for _, o := range manifest.Objects {
+		if o.Path == "" || o.Size < 0 {
+			return fmt.Errorf("%w: bad object '%s'", errInvalidManifest, o.Path)
+		}
+		if _, ok := paths[o.Path]; ok {
+			return fmt.Errorf("%w: duplicate object %s", errInvalidManifest, o.Path)
+		}
+		paths[o.Path] = struct{}{}
+	}
+	return nil
+}
+
+// extractArchive reads the manifest and the objects of an archive, extracting the objects into dir after checking
+// their size and checksum against the manifest. The archive is read to its end.
+func extractArchive(r io.Reader, dir string) (*archiveManifest, []unarchivedObject, error) {
+	tr, err := archiveDecompressor(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	// read until the end of the raw archive so that it is fully hashed
+	defer func() { _, _ = io.Copy(io.Discard, r) }()
+	t := tar.NewReader(tr)
+
+	hdr, err := t.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errInvalidArchive, err)
+	}
+	if hdr.Name != repoArchiveManifestName {
+		return nil, nil, fmt.Errorf("%w: first file is %s, expected %s", errInvalidArchive, hdr.Name, repoArchiveManifestName)
+	}
+	var manifest archiveManifest
+	if err := json.NewDecoder(t).Decode(&manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errInvalidManifest, err)
+	}
+	if err := validateArchiveManifest(&manifest); err != nil {
+		return nil, nil, err
+	}
+	pending := make(map[string]archiveManifestObject, len(manifest.Objects))
+	for _, o := range manifest.Objects {
+		name := strings.TrimPrefix(strings.TrimPrefix(o.Path, manifest.Prefix), uri.PathSeparator)
+		pending[name] = o
+	}
+
+	objects := make([]unarchivedObject, 0, len(manifest.Objects))
+	for {
+		hdr, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", errInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		o, ok := pending[hdr.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is not listed in the manifest", errInvalidArchive, hdr.Name)
+		}
+		delete(pending, hdr.Name)
+		// name extracted files by their index, archive paths are only used as object paths
+		file := filepath.Join(dir, strconv.Itoa(len(objects)))
+		if err := extractArchiveObject(t, file, o); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", hdr.Name, err)
+		}
+		o.Path = hdr.Name
+		objects = append(objects, unarchivedObject{archiveManifestObject: o, file: file})
+	}
+	for name := range pending {
+		return nil, nil, fmt.Errorf("%w: %s is listed in the manifest but missing", errInvalidArchive, name)
+	}
+	return &manifest, objects, nil
+}
+
+func extractArchiveObject(r io.Reader, file string, o archiveManifestObject) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	// verify MD5 checksums, other checksums (e.g. multipart ETags) cannot be computed from the content alone
+	var h hash.Hash
+	w := io.Writer(f)
+	if len(o.Checksum) == hex.EncodedLen(md5.Size) {
+		h = md5.New() //nolint:gosec
+		w = io.MultiWriter(f, h)
+	}
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return err
+	}
+	if n != o.Size {
+		return fmt.Errorf("%w: read %d bytes, listed %d", errObjectSizeMismatch, n, o.Size)
+	}
+	if h != nil && hex.EncodeToString(h.Sum(nil)) != o.Checksum {
+		return fmt.Errorf("%w: checksum differs from %s", errInvalidArchive, o.Checksum)
+	}
+	return f.Close()
+}
+
+// uploadUnarchived uploads the extracted objects under prefix of a branch, in parallel
+func uploadUnarchived(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, branch, prefix string, objects []unarchivedObject, syncFlags local.SyncFlags) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(syncFlags.Parallelism)
+	for _, o := range objects {
+		o := o
+		g.Go(func() error {
+			f, err := os.Open(o.file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			objPath := prefix + o.Path
+			if syncFlags.Presign {
+				_, err = helpers.ClientUploadPreSign(ctx, client, repository, branch, objPath, nil, o.ContentType, f, syncFlags.PresignMultipart)
+			} else {
+				_, err = helpers.ClientUpload(ctx, client, repository, branch, objPath, nil, o.ContentType, f)
+			}
+			if err != nil {
+				return fmt.Errorf("upload %s: %w", objPath, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+//nolint:gochecknoinits
+func init() {
+	withCommitFlags(repoUnarchiveCmd, true)
+	withSyncFlags(repoUnarchiveCmd)
+
+	repoCmd.AddCommand(repoUnarchiveCmd)
+}
@@ -57,6 +57,8 @@ type Configuration struct {
 	Credentials struct {
 		AccessKeyID     lakefsconfig.OnlyString `mapstructure:"access_key_id"`
 		SecretAccessKey lakefsconfig.OnlyString `mapstructure:"secret_access_key"`
+		// AccessToken is a personal access token, used instead of the access key when set
+		AccessToken lakefsconfig.OnlyString `mapstructure:"access_token"`
 	} `mapstructure:"credentials"`
 	Server struct {
 		EndpointURL lakefsconfig.OnlyString `mapstructure:"endpoint_url"`
@@ -84,6 +86,24 @@ type Configuration struct {
 		// setting FixSparkPlaceholder to true will change spark placeholder with the actual location. for more information see https://github.com/treeverse/lakeFS/issues/2213
 		FixSparkPlaceholder bool `mapstructure:"fix_spark_placeholder"`
 	}
+	// BaseURI is the default value of the --base-uri flag
+	BaseURI lakefsconfig.OnlyString `mapstructure:"base_uri"`
+	// Profiles are named sets of settings, the profile selected by --profile overrides the top level settings
+	Profiles map[string]Profile `mapstructure:"profiles"`
+}
+
+// Profile is a named lakeFS installation to work with: its endpoint, credentials and default base URI.
+type Profile struct {
+	Credentials struct {
+		AccessKeyID     lakefsconfig.OnlyString `mapstructure:"access_key_id"`
+		SecretAccessKey lakefsconfig.OnlyString `mapstructure:"secret_access_key"`
+		// AccessToken is a personal access token, used instead of the access key when set
+		AccessToken lakefsconfig.OnlyString `mapstructure:"access_token"`
+	} `mapstructure:"credentials"`
+	Server struct {
+		EndpointURL lakefsconfig.OnlyString `mapstructure:"endpoint_url"`
+	} `mapstructure:"server"`
+	BaseURI lakefsconfig.OnlyString `mapstructure:"base_uri"`
 }
 
 type versionInfo struct {
@@ -107,6 +127,10 @@ var (
 	//   `--base-uri lakefs://repo1 /main/file.md` will resolve to path `lakefs://repo1/main/file.md`
 	baseURI string
 
+	// profile is the name of the configuration profile to use, set by the environment variable LAKECTL_PROFILE and
+	// override by flag 'profile'
+	profile string
+
 	// logLevel logging level (default is off)
 	logLevel string
 	// logFormat logging output format
@@ -314,6 +338,9 @@ var rootCmd = &cobra.Command{
 			// other errors while reading the config file
 			DieFmt("error reading configuration file: %v", cfgErr)
 		}
+		if profile != "" {
+			applyProfile(profile)
+		}
 
 		err = viper.UnmarshalExact(&cfg, viper.DecodeHook(
 			mapstructure.ComposeDecodeHookFunc(
@@ -322,6 +349,9 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			DieFmt("error unmarshal configuration: %v", err)
 		}
+		if baseURI == "" {
+			baseURI = string(cfg.BaseURI)
+		}
 
 		if cmd.HasParent() {
 			// Don't send statistics for root command or if one of the excluding
@@ -431,11 +461,21 @@ func getClient() *apigen.ClientWithResponses {
 		Transport: transport,
 	}
 
-	accessKeyID := cfg.Credentials.AccessKeyID
-	secretAccessKey := cfg.Credentials.SecretAccessKey
-	basicAuthProvider, err := securityprovider.NewSecurityProviderBasicAuth(string(accessKeyID), string(secretAccessKey))
-	if err != nil {
-		DieErr(err)
+	var authRequestEditor apigen.RequestEditorFn
+	if accessToken := cfg.Credentials.AccessToken; accessToken != "" {
+		bearerTokenProvider, err := securityprovider.NewSecurityProviderBearerToken(string(accessToken))
+		if err != nil {
+			DieErr(err)
+		}
+		authRequestEditor = bearerTokenProvider.Intercept
+	} else {
+		accessKeyID := cfg.Credentials.AccessKeyID
+		secretAccessKey := cfg.Credentials.SecretAccessKey
+		basicAuthProvider, err := securityprovider.NewSecurityProviderBasicAuth(string(accessKeyID), string(secretAccessKey))
+		if err != nil {
+			DieErr(err)
+		}
+		authRequestEditor = basicAuthProvider.Intercept
 	}
 
 	serverEndpoint, err := apiutil.NormalizeLakeFSEndpoint(cfg.Server.EndpointURL.String())
@@ -446,7 +486,7 @@ func getClient() *apigen.ClientWithResponses {
 	client, err := apigen.NewClientWithResponses(
 		serverEndpoint,
 		apigen.WithHTTPClient(httpClient),
-		apigen.WithRequestEditorFn(basicAuthProvider.Intercept),
+		apigen.WithRequestEditorFn(authRequestEditor),
 		apigen.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 			req.Header.Set("User-Agent", "lakectl/"+version.Version)
 			return nil
@@ -476,6 +516,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $HOME/.lakectl.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&noColorRequested, "no-color", getEnvNoColor(), "don't use fancy output colors (default value can be set by NO_COLOR environment variable)")
 	rootCmd.PersistentFlags().StringVarP(&baseURI, "base-uri", "", os.Getenv("LAKECTL_BASE_URI"), "base URI used for lakeFS address parse")
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "", os.Getenv("LAKECTL_PROFILE"), "configuration profile to use (default value can be set by LAKECTL_PROFILE environment variable)")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", "none", "set logging level")
 	rootCmd.PersistentFlags().StringVarP(&logFormat, "log-format", "", "", "set logging output format")
 	rootCmd.PersistentFlags().StringSliceVarP(&logOutputs, "log-output", "", []string{}, "set logging output(s)")
@@ -526,3 +567,25 @@ func initConfig() {
 		DieFmt("Failed to read config file '%s': %s", viper.ConfigFileUsed(), cfgErr)
 	}
 }
+
+// applyProfile overrides the top level configuration with the settings of the named profile. Environment variables
+// and flags still take precedence over the profile settings.
+func applyProfile(name string) {
+	key := profileKey(name, "")
+	if !viper.IsSet(key) {
+		DieFmt("profile '%s' not found in config file '%s'", name, viper.ConfigFileUsed())
+	}
+	if err := viper.MergeConfigMap(viper.GetStringMap(key)); err != nil {
+		DieFmt("error applying profile '%s': %v", name, err)
+	}
+}
+
+// profileKey returns the configuration key of setting under the named profile, or of the profile itself if setting
+// is empty.
+func profileKey(name, setting string) string {
+	key := "profiles." + name
+	if setting != "" {
+		key += "." + setting
+	}
+	return key
+}
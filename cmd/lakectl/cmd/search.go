@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const (
+	searchRepositoryFlagName = "repository"
+	searchBranchFlagName     = "branch"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <words>...",
+	Short: "Search objects by words of their path and metadata",
+	Long: `Search the objects committed on indexed branches of all repositories by words of their path and user metadata.
+Every word must begin a word of the path or metadata of a result, so "cust ord" finds "tables/customer_orders/".
+Search must be enabled on the lakeFS server.`,
+	Example: "lakectl search customer orders --branch main",
+	Args:    cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		repository := Must(cmd.Flags().GetString(searchRepositoryFlagName))
+		branch := Must(cmd.Flags().GetString(searchBranchFlagName))
+
+		params := &apigen.SearchObjectsParams{
+			Q:      strings.Join(args, " "),
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		}
+		if repository != "" {
+			params.Repository = apiutil.Ptr(repository)
+		}
+		if branch != "" {
+			params.Branch = apiutil.Ptr(branch)
+		}
+		client := getClient()
+		resp, err := client.SearchObjectsWithResponse(cmd.Context(), params)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, result := range results {
+			lastModified := ""
+			if result.LastModified != nil {
+				lastModified = time.Unix(*result.LastModified, 0).String()
+			}
+			rows[i] = []interface{}{"lakefs://" + result.Repository + "/" + result.Branch + "/" + result.Path, lastModified, result.CommitId}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Object", "Last Modified", "Commit ID"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	searchCmd.Flags().Int("amount", defaultAmountArgumentValue, "number of results to return")
+	searchCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	searchCmd.Flags().String(searchRepositoryFlagName, "", "return only results of this repository")
+	searchCmd.Flags().String(searchBranchFlagName, "", "return only results of branches of this name")
+
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// stashCmd represents the stash command
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Set aside uncommitted changes of a branch and apply them later",
+	Long: `Move the uncommitted changes of a branch into a named stash, leaving the branch at its last commit.
+Stashed changes can later be applied to the same branch or to any other branch of the repository.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(stashCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const stashApplyLong = `Stage the changes of a stash on a branch, which may differ from the branch they were stashed from.
+Stashed changes replace the current values of the objects they change. Applying fails if the branch has
+uncommitted changes to any of these objects.`
+
+var stashApplyCmd = &cobra.Command{
+	Use:               "apply <branch URI> <stash id>",
+	Short:             "Stage the changes of a stash on a branch, keeping the stash",
+	Long:              stashApplyLong,
+	Example:           "lakectl stash apply " + myRepoExample + "/" + myBranchExample + " ingest-wip",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		applyStash(cmd, args[0], args[1], false)
+	},
+}
+
+var stashPopCmd = &cobra.Command{
+	Use:               "pop <branch URI> <stash id>",
+	Short:             "Stage the changes of a stash on a branch and delete the stash",
+	Long:              stashApplyLong,
+	Example:           "lakectl stash pop " + myRepoExample + "/" + myBranchExample + " ingest-wip",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		applyStash(cmd, args[0], args[1], true)
+	},
+}
+
+func applyStash(cmd *cobra.Command, branchURI, stashID string, drop bool) {
+	u := MustParseBranchURI("branch URI", branchURI)
+	client := getClient()
+	resp, err := client.ApplyStashWithResponse(cmd.Context(), u.Repository, stashID, apigen.ApplyStashJSONRequestBody{
+		Branch: u.Ref,
+		Drop:   apiutil.Ptr(drop),
+	})
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+	fmt.Printf("Stash %s applied on %s\n", stashID, u)
+}
+
+//nolint:gochecknoinits
+func init() {
+	stashCmd.AddCommand(stashApplyCmd)
+	stashCmd.AddCommand(stashPopCmd)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var stashDropCmd = &cobra.Command{
+	Use:               "drop <repository URI> <stash id>",
+	Short:             "Delete a stash, discarding its changes",
+	Example:           "lakectl stash drop " + myRepoExample + " ingest-wip",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		confirmation, err := Confirm(cmd.Flags(), "Are you sure you want to drop stash "+args[1])
+		if err != nil || !confirmation {
+			Die("Drop stash aborted", 1)
+		}
+		u := MustParseRepoURI("repository URI", args[0])
+		client := getClient()
+		resp, err := client.DeleteStashWithResponse(cmd.Context(), u.Repository, args[1])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	AssignAutoConfirmFlag(stashDropCmd.Flags())
+	stashCmd.AddCommand(stashDropCmd)
+}
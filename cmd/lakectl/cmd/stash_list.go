@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var stashListCmd = &cobra.Command{
+	Use:               "list <repository URI>",
+	Short:             "List stashes in a repository",
+	Example:           "lakectl stash list " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.ListStashesWithResponse(cmd.Context(), u.Repository, &apigen.ListStashesParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		stashes := resp.JSON200.Results
+		rows := make([][]interface{}, len(stashes))
+		for i, row := range stashes {
+			rows[i] = []interface{}{row.Id, row.Branch, row.CommitId, time.Unix(row.CreationDate, 0).String(), row.Message}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Stash", "Branch", "Commit ID", "Created At", "Message"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := stashListCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this value (used for pagination)")
+
+	stashCmd.AddCommand(stashListCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var stashPushCmd = &cobra.Command{
+	Use:               "push <branch URI> <stash id>",
+	Short:             "Move the uncommitted changes of a branch into a new stash",
+	Example:           "lakectl stash push " + myRepoExample + "/" + myBranchExample + " ingest-wip -m \"ingestion in progress\"",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		message := Must(cmd.Flags().GetString("message"))
+		u := MustParseBranchURI("branch URI", args[0])
+		client := getClient()
+		resp, err := client.StashBranchWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.StashBranchJSONRequestBody{
+			Id:      args[1],
+			Message: apiutil.Ptr(message),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Uncommitted changes of %s stashed as %s\n", u, resp.JSON201.Id)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	stashPushCmd.Flags().StringP("message", "m", "", "stash message")
+	stashCmd.AddCommand(stashPushCmd)
+}
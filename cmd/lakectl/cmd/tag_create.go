@@ -56,8 +56,8 @@ var tagCreateCmd = &cobra.Command{
 			Die("Bad response from server", 1)
 		}
 
-		commitID := *resp.JSON201
-		fmt.Printf("Created tag '%s' (%s)\n", tagURI.Ref, commitID)
+		tag := resp.JSON201
+		fmt.Printf("Created tag '%s' ({%s %s})\n", tagURI.Ref, tag.CommitId, tag.Id)
 	},
 }
 
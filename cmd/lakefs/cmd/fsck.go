@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the metadata consistency of repositories",
+	Long: `Check the referential integrity of repositories: branches and tags point to existing commits, commits point to
+existing parents and readable trees, and tree entries point to existing physical objects.
+Each issue found is printed with a suggestion for repairing it. The check only reads, and may run while lakeFS is
+running. Exits with status 1 when issues of severity error are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		repositories, err := cmd.Flags().GetStringSlice("repository")
+		if err != nil {
+			fmt.Printf("repository: %s\n", err)
+			os.Exit(1)
+		}
+		allCommits, err := cmd.Flags().GetBool("all-commits")
+		if err != nil {
+			fmt.Printf("all-commits: %s\n", err)
+			os.Exit(1)
+		}
+		skipObjects, err := cmd.Flags().GetBool("skip-objects")
+		if err != nil {
+			fmt.Printf("skip-objects: %s\n", err)
+			os.Exit(1)
+		}
+		reportPath, err := cmd.Flags().GetString("report")
+		if err != nil {
+			fmt.Printf("report: %s\n", err)
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		kvParams, err := kvparams.NewConfig(cfg)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "KV params: %s\n", err)
+			os.Exit(1)
+		}
+		kvStore, err := kv.Open(ctx, kvParams)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to open KV store: %s\n", err)
+			os.Exit(1)
+		}
+		defer kvStore.Close()
+		mustValidateSchemaVersion(ctx, kvStore)
+
+		c, err := catalog.New(ctx, catalog.Config{
+			Config:       cfg,
+			KVStore:      kvStore,
+			PathProvider: upload.DefaultPathProvider,
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to create catalog: %s\n", err)
+			os.Exit(1)
+		}
+		defer func() { _ = c.Close() }()
+
+		report, err := c.Fsck(ctx, catalog.FsckOptions{
+			Repositories: repositories,
+			AllCommits:   allCommits,
+			SkipObjects:  skipObjects,
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Check failed: %s\n", err)
+			os.Exit(1)
+		}
+
+		out := io.Writer(os.Stdout)
+		if reportPath == "-" {
+			// the report replaces the summary on stdout
+			out = os.Stderr
+		}
+		printFsckReport(out, report)
+		if reportPath != "" {
+			if err := writeFsckReport(reportPath, report); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to write report: %s\n", err)
+				os.Exit(1)
+			}
+		}
+		if report.Errors() > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func printFsckReport(w io.Writer, report *catalog.FsckReport) {
+	for _, repo := range report.Repositories {
+		_, _ = fmt.Fprintf(w, "Repository %s: %d branches, %d tags, %d commits, %d trees, %d entries, %d objects checked\n",
+			repo.Repository, repo.Branches, repo.Tags, repo.Commits, repo.TreesChecked, repo.Entries, repo.ObjectsChecked)
+		for _, issue := range repo.Issues {
+			_, _ = fmt.Fprintf(w, "  %s %s", issue.Severity, issue.Type)
+			for _, field := range []struct{ name, value string }{
+				{"branch", issue.Branch},
+				{"tag", issue.Tag},
+				{"commit", issue.CommitID},
+				{"path", issue.Path},
+				{"address", issue.Address},
+			} {
+				if field.value != "" {
+					_, _ = fmt.Fprintf(w, " %s=%s", field.name, field.value)
+				}
+			}
+			_, _ = fmt.Fprintf(w, ": %s\n    repair: %s\n", issue.Message, issue.Repair)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%d repositories checked, %d errors found\n", len(report.Repositories), report.Errors())
+}
+
+func writeFsckReport(path string, report *catalog.FsckReport) error {
+	w := os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+	fsckCmd.Flags().StringSlice("repository", nil, "repositories to check, all repositories by default")
+	fsckCmd.Flags().Bool("all-commits", false, "check the trees of all commits, not only of branch heads and tags")
+	fsckCmd.Flags().Bool("skip-objects", false, "skip checking that the physical objects of entries exist")
+	fsckCmd.Flags().String("report", "", "write a JSON report to this file, \"-\" for stdout")
+}
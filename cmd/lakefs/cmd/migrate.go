@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/auth/model"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
@@ -61,10 +63,157 @@ func mustValidateSchemaVersion(ctx context.Context, kvStore kv.Store) int {
 	return -1
 }
 
+const (
+	batchSizeFlagName     = "batch-size"
+	batchIntervalFlagName = "batch-interval"
+)
+
+// migrationStep migrates the KV schema to version, when the schema version is lower
+type migrationStep struct {
+	version     int
+	description string
+	// partition and prefix of the records the migration reads and rewrites
+	partition string
+	prefix    []byte
+	// estimate is set on online steps, which rewrite records in batches while lakeFS instances keep running
+	estimate func(ctx context.Context, kvStore kv.Store, cfg *config.Config, params migrations.BatchParams) (*migrations.Estimate, error)
+	migrate  func(ctx context.Context, kvStore kv.Store, cfg *config.Config, version int, force bool, params migrations.BatchParams) error
+}
+
+var migrationSteps = []migrationStep{
+	{
+		version:     kv.ACLNoReposMigrateVersion,
+		description: "convert the policies of groups to ACLs",
+		partition:   model.PartitionKey,
+		prefix:      model.GroupPath(""),
+		migrate: func(ctx context.Context, kvStore kv.Store, cfg *config.Config, version int, force bool, _ migrations.BatchParams) error {
+			return migrations.MigrateToACL(ctx, kvStore, cfg, logging.ContextUnavailable(), version, force)
+		},
+	},
+	{
+		version:     kv.ACLImportMigrateVersion,
+		description: "grant fs:Import* to policies allowing import from storage",
+		partition:   model.PartitionKey,
+		prefix:      model.PolicyPath(""),
+		estimate:    migrations.EstimateImportPermissions,
+		migrate: func(ctx context.Context, kvStore kv.Store, cfg *config.Config, _ int, _ bool, params migrations.BatchParams) error {
+			return migrations.MigrateImportPermissions(ctx, kvStore, cfg, params)
+		},
+	},
+}
+
+func getBatchParams(cmd *cobra.Command) migrations.BatchParams {
+	size, _ := cmd.Flags().GetInt(batchSizeFlagName)
+	interval, _ := cmd.Flags().GetDuration(batchIntervalFlagName)
+	if size < 1 {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid value for %s (%d), minimum is 1.\n", batchSizeFlagName, size)
+		os.Exit(1)
+	}
+	return migrations.BatchParams{Size: size, Interval: interval}
+}
+
+func withBatchFlags(cmd *cobra.Command) {
+	_ = cmd.Flags().Int(batchSizeFlagName, migrations.DefaultBatchSize, "number of records an online migration rewrites in a batch")
+	_ = cmd.Flags().Duration(batchIntervalFlagName, migrations.DefaultBatchInterval, "pause between the batches of an online migration")
+}
+
+// pendingMigrations returns the migration steps required to migrate from version to the latest version
+func pendingMigrations(version int) []migrationStep {
+	var steps []migrationStep
+	for _, step := range migrationSteps {
+		if version < step.version {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// countRecords returns the number of records under prefix of partition
+func countRecords(ctx context.Context, kvStore kv.Store, partition string, prefix []byte) (int, error) {
+	it, err := kv.ScanPrefix(ctx, kvStore, []byte(partition), prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count, it.Err()
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Print the migrations required to migrate to the latest version, without applying them",
+	Long: `Print the migrations required to migrate to the latest version, the number of records each one scans, and
+the impact of each one. Online migrations rewrite records in batches while lakeFS instances keep running: each record
+is rewritten by a single conditional write, so only the record being written is locked. Offline migrations rewrite
+related records together, which must not change while they run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		params := getBatchParams(cmd)
+		cfg := loadConfig()
+		kvParams, err := kvparams.NewConfig(cfg)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "KV params: %s\n", err)
+			os.Exit(1)
+		}
+		ctx := cmd.Context()
+		kvStore, err := kv.Open(ctx, kvParams)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to open KV store: %s\n", err)
+			os.Exit(1)
+		}
+		defer kvStore.Close()
+
+		version, err := kv.ValidateSchemaVersion(ctx, kvStore)
+		switch {
+		case err == nil:
+			fmt.Printf("Database schema version: %d\nNo migrations to apply.\n", version)
+			return
+		case errors.Is(err, kv.ErrMigrationRequired):
+		case errors.Is(err, kv.ErrNotFound):
+			_, _ = fmt.Fprintf(os.Stderr, "No version information - KV not initialized.\n")
+			os.Exit(1)
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "Schema version: %d. %s\n", version, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Database schema version: %d, latest version: %d\n", version, kv.NextSchemaVersion-1)
+		fmt.Printf("Pending migrations:\n")
+		for _, step := range pendingMigrations(version) {
+			if step.estimate == nil {
+				count, err := countRecords(ctx, kvStore, step.partition, step.prefix)
+				if err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "Failed to count records of migration to version %d: %s\n", step.version, err)
+					os.Exit(1)
+				}
+				fmt.Printf("  %d: %s (%d records)\n", step.version, step.description, count)
+				fmt.Printf("     offline: rewrites related records together, do not change them while it runs\n")
+				continue
+			}
+			estimate, err := step.estimate(ctx, kvStore, cfg, params)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to estimate migration to version %d: %s\n", step.version, err)
+				os.Exit(1)
+			}
+			fmt.Printf("  %d: %s (%d records)\n", step.version, step.description, estimate.Records)
+			fmt.Printf("     online: rewrites %d records in %d batches of %d, locking only the record being written, estimated %s\n",
+				estimate.Rewrites, estimate.Batches, params.Size, estimate.Duration.Round(time.Millisecond))
+		}
+		if version == kv.ACLMigrateVersion {
+			fmt.Printf("Migrating from the previous version of ACLs requires --force.\n")
+		}
+		fmt.Printf("Run 'lakefs migrate up' to apply.\n")
+	},
+}
+
 var upCmd = &cobra.Command{
-	Use:   "up",
-	Short: "Apply all up migrations",
+	Use:     "up",
+	Aliases: []string{"apply"},
+	Short:   "Apply all up migrations",
 	Run: func(cmd *cobra.Command, args []string) {
+		params := getBatchParams(cmd)
 		cfg := loadConfig()
 		kvParams, err := kvparams.NewConfig(cfg)
 		if err != nil {
@@ -89,7 +238,7 @@ var upCmd = &cobra.Command{
 			_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
 			os.Exit(1)
 		case errors.Is(err, kv.ErrMigrationRequired):
-			err = DoMigration(ctx, kvStore, cfg, force)
+			err = DoMigration(ctx, kvStore, cfg, force, params)
 			if err != nil {
 				_, _ = fmt.Fprintf(os.Stderr, "Migration failed: %s\n", err)
 				os.Exit(1)
@@ -102,7 +251,8 @@ var upCmd = &cobra.Command{
 	},
 }
 
-func DoMigration(ctx context.Context, kvStore kv.Store, cfg *config.Config, force bool) error {
+// DoMigration applies the pending migrations, online migrations in batches configured by params
+func DoMigration(ctx context.Context, kvStore kv.Store, cfg *config.Config, force bool, params migrations.BatchParams) error {
 	var (
 		version int
 		err     error
@@ -112,16 +262,13 @@ func DoMigration(ctx context.Context, kvStore kv.Store, cfg *config.Config, forc
 		if err != nil {
 			return err
 		}
-		switch {
-		case version >= kv.NextSchemaVersion || version < kv.InitialMigrateVersion:
+		if version >= kv.NextSchemaVersion || version < kv.InitialMigrateVersion {
 			return fmt.Errorf("wrong starting version %d: %w", version, kv.ErrMigrationVersion)
-		case version < kv.ACLNoReposMigrateVersion:
-			err = migrations.MigrateToACL(ctx, kvStore, cfg, logging.ContextUnavailable(), version, force)
-		case version < kv.ACLImportMigrateVersion:
-			err = migrations.MigrateImportPermissions(ctx, kvStore, cfg)
 		}
-		if err != nil {
-			return err
+		if steps := pendingMigrations(version); len(steps) > 0 {
+			if err := steps[0].migrate(ctx, kvStore, cfg, version, force, params); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -154,10 +301,13 @@ var gotoCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(migrateCmd)
 	migrateCmd.AddCommand(versionCmd)
+	migrateCmd.AddCommand(planCmd)
 	migrateCmd.AddCommand(upCmd)
 	migrateCmd.AddCommand(gotoCmd)
 	_ = gotoCmd.Flags().Uint("version", 0, "version number")
 	_ = gotoCmd.MarkFlagRequired("version")
 	_ = upCmd.Flags().Bool("force", false, "force migrate, otherwise, migration will fail on warnings ")
+	withBatchFlags(planCmd)
+	withBatchFlags(upCmd)
 	_ = gotoCmd.Flags().Bool("force", false, "force migrate")
 }
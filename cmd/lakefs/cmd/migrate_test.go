@@ -9,6 +9,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/kv/migrations"
 )
 
 func TestDoMigrate(t *testing.T) {
@@ -16,7 +17,7 @@ func TestDoMigrate(t *testing.T) {
 
 	t.Run("not_initialized", func(t *testing.T) {
 		kvStore := kvtest.GetStore(ctx, t)
-		err := cmd.DoMigration(ctx, kvStore, nil, false)
+		err := cmd.DoMigration(ctx, kvStore, nil, false, migrations.DefaultBatchParams())
 		require.ErrorIs(t, err, kv.ErrNotFound)
 		_, err = kv.GetDBSchemaVersion(ctx, kvStore)
 		require.ErrorIs(t, err, kv.ErrNotFound)
@@ -25,7 +26,7 @@ func TestDoMigrate(t *testing.T) {
 	t.Run("not_meets_required_version", func(t *testing.T) {
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, 0))
-		err := cmd.DoMigration(ctx, kvStore, nil, false)
+		err := cmd.DoMigration(ctx, kvStore, nil, false, migrations.DefaultBatchParams())
 		require.ErrorIs(t, err, kv.ErrMigrationVersion)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -38,7 +39,7 @@ func TestDoMigrate(t *testing.T) {
 		cfg.Auth.Encrypt.SecretKey = "test"
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, kv.InitialMigrateVersion))
-		err := cmd.DoMigration(ctx, kvStore, &cfg, false)
+		err := cmd.DoMigration(ctx, kvStore, &cfg, false, migrations.DefaultBatchParams())
 		require.NoError(t, err)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -50,7 +51,7 @@ func TestDoMigrate(t *testing.T) {
 		cfg.Auth.UIConfig.RBAC = config.AuthRBACSimplified
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, kv.ACLMigrateVersion))
-		err := cmd.DoMigration(ctx, kvStore, &cfg, false)
+		err := cmd.DoMigration(ctx, kvStore, &cfg, false, migrations.DefaultBatchParams())
 		require.ErrorIs(t, err, kv.ErrMigrationVersion)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -62,7 +63,7 @@ func TestDoMigrate(t *testing.T) {
 		cfg.Auth.UIConfig.RBAC = config.AuthRBACSimplified
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, kv.ACLNoReposMigrateVersion))
-		err := cmd.DoMigration(ctx, kvStore, &cfg, true)
+		err := cmd.DoMigration(ctx, kvStore, &cfg, true, migrations.DefaultBatchParams())
 		require.NoError(t, err)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -76,7 +77,7 @@ func TestDoMigrate(t *testing.T) {
 		for !kv.IsLatestSchemaVersion(startVer) {
 			kvStore := kvtest.GetStore(ctx, t)
 			require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, uint(startVer)))
-			err := cmd.DoMigration(ctx, kvStore, &cfg, false)
+			err := cmd.DoMigration(ctx, kvStore, &cfg, false, migrations.DefaultBatchParams())
 			require.NoError(t, err)
 			version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 			require.NoError(t, err)
@@ -90,7 +91,7 @@ func TestDoMigrate(t *testing.T) {
 		cfg.Auth.UIConfig.RBAC = config.AuthRBACSimplified
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, kv.NextSchemaVersion-1))
-		err := cmd.DoMigration(ctx, kvStore, &cfg, false)
+		err := cmd.DoMigration(ctx, kvStore, &cfg, false, migrations.DefaultBatchParams())
 		require.NoError(t, err)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -102,7 +103,7 @@ func TestDoMigrate(t *testing.T) {
 		cfg.Auth.UIConfig.RBAC = config.AuthRBACSimplified
 		kvStore := kvtest.GetStore(ctx, t)
 		require.NoError(t, kv.SetDBSchemaVersion(ctx, kvStore, kv.NextSchemaVersion))
-		err := cmd.DoMigration(ctx, kvStore, &cfg, false)
+		err := cmd.DoMigration(ctx, kvStore, &cfg, false, migrations.DefaultBatchParams())
 		require.ErrorIs(t, err, kv.ErrMigrationVersion)
 		version, err := kv.GetDBSchemaVersion(ctx, kvStore)
 		require.NoError(t, err)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -15,7 +16,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
-	"github.com/go-co-op/gocron"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/treeverse/lakefs/pkg/actions"
@@ -23,6 +24,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/auth/crypt"
 	authparams "github.com/treeverse/lakefs/pkg/auth/params"
+	"github.com/treeverse/lakefs/pkg/auth/provisioning"
 	authremote "github.com/treeverse/lakefs/pkg/auth/remoteauthenticator"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/factory"
@@ -30,9 +32,14 @@ import (
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/gateway"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/session"
 	"github.com/treeverse/lakefs/pkg/gateway/sig"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/inventory"
+	"github.com/treeverse/lakefs/pkg/jobs"
 	"github.com/treeverse/lakefs/pkg/kv"
 	_ "github.com/treeverse/lakefs/pkg/kv/cosmosdb"
 	_ "github.com/treeverse/lakefs/pkg/kv/dynamodb"
@@ -41,16 +48,19 @@ import (
 	"github.com/treeverse/lakefs/pkg/kv/mem"
 	_ "github.com/treeverse/lakefs/pkg/kv/postgres"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/notifications"
 	tablediff "github.com/treeverse/lakefs/pkg/plugins/diff"
+	"github.com/treeverse/lakefs/pkg/replication"
+	"github.com/treeverse/lakefs/pkg/search"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/treeverse/lakefs/pkg/version"
 )
 
 const (
-	gracefulShutdownTimeout = 30 * time.Second
-
 	mismatchedReposFlagName = "allow-mismatched-repos"
+	// loginSessionsCleanupInterval is how often the records of expired login sessions are deleted
+	loginSessionsCleanupInterval = time.Hour
 )
 
 type Shutter interface {
@@ -107,6 +117,7 @@ var runCmd = &cobra.Command{
 
 		migrator := kv.NewDatabaseMigrator(kvParams)
 		multipartTracker := multipart.NewTracker(kvStore)
+		gatewaySessions := session.NewSessions(kvStore, cfg.Gateways.S3.Sessions.TTL)
 		actionsStore := actions.NewActionsKVStore(kvStore)
 		authMetadataManager := auth.NewKVMetadataManager(version.Version, cfg.Installation.FixedID, cfg.Database.Type, kvStore)
 		idGen := &actions.DecreasingIDGenerator{}
@@ -143,6 +154,15 @@ var runCmd = &cobra.Command{
 			)
 		}
 
+		// provisioning webhook setup
+		if cfg.Auth.ProvisioningWebhook.Enabled {
+			provisioningHook, err := provisioning.NewHook(provisioning.HookConfig(cfg.Auth.ProvisioningWebhook), logger)
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create provisioning webhook")
+			}
+			authService = provisioning.NewService(authService, provisioningHook)
+		}
+
 		cloudMetadataProvider := stats.BuildMetadataProvider(logger, cfg)
 		blockstoreType := cfg.Blockstore.Type
 		if blockstoreType == "mem" {
@@ -182,12 +202,12 @@ var runCmd = &cobra.Command{
 			usageReporter = ur
 		}
 
-		deleteScheduler := gocron.NewScheduler(time.UTC)
-		err = scheduleCleanupJobs(ctx, deleteScheduler, c)
+		jobsRunner := jobs.NewRunner(kvStore, cfg.Jobs, logger.WithField("service", "jobs"))
+		defer jobsRunner.Stop()
+		err = registerCleanupJobs(jobsRunner, c)
 		if err != nil {
 			logger.WithError(err).Fatal("Failed to schedule cleanup jobs")
 		}
-		deleteScheduler.StartAsync()
 
 		// initial setup - support only when a local database is configured.
 		// local database lock will make sure that only one instance will run the setup.
@@ -216,7 +236,79 @@ var runCmd = &cobra.Command{
 
 		// wire actions into entry catalog
 		defer actionsService.Stop()
-		c.SetHooksHandler(actionsService)
+		var hooksHandler graveler.HooksHandler = actionsService
+		if len(cfg.Notifications.Rules) > 0 {
+			notificationsService, err := notifications.NewService(cfg.Notifications, actionsService, logger.WithField("service", "notifications"))
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create notifications service")
+			}
+			// sends the remaining notifications before post-commit and post-merge actions are stopped
+			defer notificationsService.Stop()
+			hooksHandler = notificationsService
+		}
+		var searchService *search.Service
+		if cfg.Search.Enabled {
+			searchService, err = search.NewService(cfg.Search, hooksHandler, kvStore, c, logger.WithField("service", "search"))
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create search service")
+			}
+			defer searchService.Stop()
+			hooksHandler = searchService
+		}
+		c.SetHooksHandler(hooksHandler)
+
+		if len(cfg.Replication.Repositories) > 0 {
+			destination, err := replication.NewAPIDestination(cfg.Replication.Destination.EndpointURL,
+				cfg.Replication.Destination.AccessKeyID.SecureValue(), cfg.Replication.Destination.SecretAccessKey.SecureValue())
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create replication destination")
+			}
+			replicationService, err := replication.NewService(cfg.Replication, c, destination, logger.WithField("service", "replication"))
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create replication service")
+			}
+			err = jobsRunner.Register(jobs.Job{
+				Name:     "replication",
+				Schedule: cfg.Replication.Interval.String(),
+				Fn:       replicationService.Replicate,
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("failed to schedule replication")
+			}
+		}
+		if len(cfg.Inventory.Reports) > 0 {
+			inventoryService, err := inventory.NewService(cfg.Inventory, c, logger.WithField("service", "inventory"))
+			if err != nil {
+				logger.WithError(err).Fatal("failed to create inventory service")
+			}
+			err = jobsRunner.Register(jobs.Job{
+				Name:     "inventory",
+				Schedule: cfg.Inventory.Interval.String(),
+				Fn:       inventoryService.Generate,
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("failed to schedule inventory reports")
+			}
+		}
+		err = jobsRunner.Register(jobs.Job{
+			Name:     "gateway_sessions_cleanup",
+			Schedule: cfg.Gateways.S3.Sessions.CleanupInterval.String(),
+			Fn:       gatewaySessions.DeleteExpired,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("failed to schedule gateway sessions cleanup")
+		}
+		if sessionManager, ok := authService.(auth.SessionManager); ok {
+			err = jobsRunner.Register(jobs.Job{
+				Name:     "login_sessions_cleanup",
+				Schedule: loginSessionsCleanupInterval.String(),
+				Fn:       sessionManager.DeleteExpiredSessions,
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("failed to schedule login sessions cleanup")
+			}
+		}
+		jobsRunner.Start()
 
 		middlewareAuthenticator := auth.ChainAuthenticator{
 			auth.NewBuiltinAuthenticator(authService),
@@ -253,11 +345,18 @@ var runCmd = &cobra.Command{
 		defer closeOtfService()
 
 		// start API server
+		lockout, err := auth.NewLockout(authparams.Lockout(cfg.Auth.Lockout))
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create authentication lockout")
+		}
+		credentialsUsage := auth.NewCredentialsUsage(authparams.Usage(cfg.Auth.Usage))
 		apiHandler := api.Serve(
 			cfg,
 			c,
 			middlewareAuthenticator,
 			authService,
+			lockout,
+			credentialsUsage,
 			blockStore,
 			authMetadataManager,
 			migrator,
@@ -271,6 +370,8 @@ var runCmd = &cobra.Command{
 			upload.DefaultPathProvider,
 			otfDiffService,
 			usageReporter,
+			jobsRunner,
+			searchService,
 		)
 
 		// init gateway server
@@ -289,6 +390,7 @@ var runCmd = &cobra.Command{
 			logger.WithField("service", "s3_gateway"),
 			middlewareAuthenticator,
 			authService,
+			lockout,
 			&oidcConfig,
 			&cookieAuthConfig,
 		)
@@ -296,12 +398,18 @@ var runCmd = &cobra.Command{
 			logger.WithError(err).Fatal("could not initialize authenticator for S3 gateway")
 		}
 
+		var listCompressionLevel int
+		if cfg.Gateways.S3.CompressListResponses {
+			listCompressionLevel = cfg.HTTP.Compression.Level
+		}
 		s3gatewayHandler := gateway.NewHandler(
 			cfg.Gateways.S3.Region,
 			c,
 			multipartTracker,
 			blockStore,
 			authService,
+			lockout,
+			credentialsUsage,
 			cfg.Gateways.S3.DomainNames,
 			bufferedCollector,
 			upload.DefaultPathProvider,
@@ -309,11 +417,22 @@ var runCmd = &cobra.Command{
 			cfg.Logging.AuditLogLevel,
 			cfg.Logging.TraceRequestHeaders,
 			cfg.Gateways.S3.VerifyUnsupported,
+			operations.UploadLimits{
+				MaxObjectSize: cfg.Gateways.S3.Limits.MaxObjectSize,
+				MaxPartSize:   cfg.Gateways.S3.Limits.MaxPartSize,
+				MaxParts:      cfg.Gateways.S3.Limits.MaxParts,
+			},
+			sig.SigningScope{
+				Regions:  cfg.Gateways.S3.Signing.Regions,
+				Services: cfg.Gateways.S3.Signing.Services,
+			},
+			listCompressionLevel,
+			gatewayOverloadLimits(cfg, kvStore),
+			gatewaySessions,
 		)
 		s3gatewayHandler = apiAuthenticator(s3gatewayHandler)
 
 		bufferedCollector.Start(ctx)
-		defer bufferedCollector.Close()
 
 		bufferedCollector.CollectEvent(stats.Event{Class: "global", Name: "run"})
 
@@ -335,12 +454,26 @@ var runCmd = &cobra.Command{
 			}),
 		}
 
+		challengeServer := configureTLS(ctx, logger, cfg, server, c)
+		err = httputil.ConfigureServer(server, httputil.ServerConfig{
+			TLS:                  cfg.TLS.Enabled,
+			HTTP2Enabled:         cfg.HTTP.HTTP2Enabled,
+			H2CEnabled:           cfg.HTTP.H2CEnabled,
+			IdleTimeout:          cfg.HTTP.IdleTimeout,
+			MaxConcurrentStreams: cfg.HTTP.MaxConcurrentStreams,
+			KeepAlivesEnabled:    cfg.HTTP.KeepAlivesEnabled,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to configure HTTP server")
+		}
+
 		actionsService.SetEndpoint(server)
 
 		go func() {
 			var err error
 			if cfg.TLS.Enabled {
-				err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+				// certificates are served by the server TLS config
+				err = server.ListenAndServeTLS("", "")
 			} else {
 				err = server.ListenAndServe()
 			}
@@ -371,10 +504,103 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		printWelcome(os.Stderr, buf.String())
-		gracefulShutdown(ctx, server)
+		// restore default signal handling once shutting down, a second signal terminates immediately
+		context.AfterFunc(ctx, stop)
+		services := []Shutter{server}
+		if challengeServer != nil {
+			services = append(services, challengeServer)
+		}
+		gracefulShutdown(ctx, cfg.ShutdownTimeout, services...)
+		// flush remaining stats and audit logs of the drained requests
+		bufferedCollector.Close()
+		if err := logging.CloseWriters(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed closing log writers: %s\n", err)
+		}
 	},
 }
 
+// configureTLS sets the TLS config of server serving certificates obtained by ACME or loaded from files, and returns
+// the server of the ACME HTTP-01 challenge if one is configured
+func configureTLS(ctx context.Context, logger logging.Logger, cfg *config.Config, server *http.Server, c *catalog.Catalog) *http.Server {
+	if !cfg.TLS.Enabled {
+		return nil
+	}
+	if !cfg.TLS.ACME.Enabled {
+		reloader, err := httputil.NewCertificateReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load TLS certificate")
+		}
+		server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		}
+		return nil
+	}
+
+	if len(cfg.TLS.ACME.Domains) == 0 {
+		logger.Fatal("ACME requires tls.acme.domains")
+	}
+	cacheDir, err := homedir.Expand(cfg.TLS.ACME.CacheDir)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to resolve ACME cache dir")
+	}
+	acmeConfig := httputil.ACMEConfig{
+		Email:    cfg.TLS.ACME.Email,
+		Domains:  cfg.TLS.ACME.Domains,
+		CacheDir: cacheDir,
+		// certificates of virtual-host style S3 gateway domains are obtained only for existing repositories
+		AllowSubdomain: func(ctx context.Context, subdomain string) bool {
+			_, err := c.GetRepository(ctx, subdomain)
+			return err == nil
+		},
+		DirectoryURL: cfg.TLS.ACME.DirectoryURL,
+	}
+
+	switch cfg.TLS.ACME.Challenge {
+	case config.ACMEChallengeTLSALPN01:
+	case config.ACMEChallengeDNS01:
+		if cfg.TLS.ACME.DNSHook == "" {
+			logger.Fatal("ACME DNS-01 challenge requires tls.acme.dns_hook")
+		}
+		manager, err := httputil.NewDNS01Manager(ctx, httputil.DNS01Config{
+			ACMEConfig:      acmeConfig,
+			Hook:            &httputil.CommandDNSHook{Command: cfg.TLS.ACME.DNSHook},
+			PropagationWait: cfg.TLS.ACME.DNSPropagationWait,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to obtain ACME certificate")
+		}
+		go manager.RenewLoop(ctx)
+		server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: manager.GetCertificate,
+		}
+		return nil
+	default:
+		logger.WithField("challenge", cfg.TLS.ACME.Challenge).Fatal("Unknown ACME challenge in tls.acme.challenge")
+	}
+
+	manager := httputil.NewACMEManager(acmeConfig)
+	server.TLSConfig = manager.TLSConfig()
+	if cfg.TLS.ACME.HTTPChallengeAddress == "" {
+		return nil
+	}
+	challengeServer := &http.Server{
+		Addr:              cfg.TLS.ACME.HTTPChallengeAddress,
+		ReadHeaderTimeout: time.Minute,
+		// serves the challenge, and redirects other requests to HTTPS
+		Handler: manager.HTTPHandler(nil),
+	}
+	go func() {
+		err := challengeServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to listen on %s: %v\n", cfg.TLS.ACME.HTTPChallengeAddress, err)
+			os.Exit(1)
+		}
+	}()
+	return challengeServer
+}
+
 // checkRepos iterating on all repos and validates that their settings are correct.
 func checkRepos(ctx context.Context, logger logging.Logger, authMetadataManager auth.MetadataManager, blockStore block.Adapter, c *catalog.Catalog) {
 	initialized, err := authMetadataManager.IsInitialized(ctx)
@@ -416,10 +642,12 @@ func checkRepos(ctx context.Context, logger logging.Logger, authMetadataManager
 	}
 }
 
-func scheduleCleanupJobs(ctx context.Context, s *gocron.Scheduler, c *catalog.Catalog) error {
+func registerCleanupJobs(runner *jobs.Runner, c *catalog.Catalog) error {
 	const (
-		deleteExpiredLinkAddressesInterval = 3 * ref.LinkAddressTime
-		deleteExpiredTaskInterval          = 24 * time.Hour
+		deleteExpiredLinkAddressesInterval   = 3 * ref.LinkAddressTime
+		deleteExpiredTaskInterval            = 24 * time.Hour
+		deleteExpiredDeletedBranchesInterval = time.Hour
+		applyLifecycleRulesInterval          = time.Hour
 	)
 
 	jobData := []struct {
@@ -428,28 +656,49 @@ func scheduleCleanupJobs(ctx context.Context, s *gocron.Scheduler, c *catalog.Ca
 		fn       func(context.Context)
 	}{
 		{
-			name:     "delete expired link addresses",
+			name:     "delete_expired_link_addresses",
 			interval: deleteExpiredLinkAddressesInterval,
 			fn:       c.DeleteExpiredLinkAddresses,
 		},
 		{
-			name:     "delete expired imports",
+			name:     "delete_expired_imports",
 			interval: ref.ImportExpiryTime,
 			fn:       c.DeleteExpiredImports,
 		},
 		{
-			name:     "delete expired tasks",
+			name:     "delete_expired_deleted_branches",
+			interval: deleteExpiredDeletedBranchesInterval,
+			fn:       c.DeleteExpiredDeletedBranches,
+		},
+		{
+			name:     "delete_expired_tasks",
 			interval: deleteExpiredTaskInterval,
 			fn:       c.DeleteExpiredTasks,
 		},
 	}
 
 	for _, jd := range jobData {
-		job, err := s.Every(jd.interval).Do(jd.fn, ctx)
+		fn := jd.fn
+		err := runner.Register(jobs.Job{
+			Name:     jd.name,
+			Schedule: jd.interval.String(),
+			Fn: func(ctx context.Context) error {
+				fn(ctx)
+				return nil
+			},
+		})
 		if err != nil {
 			return fmt.Errorf("schedule %s failed: %w", jd.name, err)
 		}
-		job.SingletonMode()
+	}
+
+	err := runner.Register(jobs.Job{
+		Name:     "lifecycle",
+		Schedule: applyLifecycleRulesInterval.String(),
+		Fn:       c.ApplyAllLifecycleRules,
+	})
+	if err != nil {
+		return fmt.Errorf("schedule lifecycle failed: %w", err)
 	}
 	return nil
 }
@@ -527,11 +776,14 @@ func printLocalWarning(w io.Writer, msg string) {
 	_, _ = fmt.Fprintf(w, localWarningBanner, msg)
 }
 
-func gracefulShutdown(ctx context.Context, services ...Shutter) {
+// gracefulShutdown waits for ctx to be done and shuts down services, giving them up to timeout to complete
+// in-flight work. Services are shut down in order.
+func gracefulShutdown(ctx context.Context, timeout time.Duration, services ...Shutter) {
 	<-ctx.Done()
 
-	_, _ = fmt.Fprintf(os.Stderr, "Shutting down...\n")
-	ctx, cancel := context.WithTimeout(ctx, gracefulShutdownTimeout)
+	_, _ = fmt.Fprintf(os.Stderr, "Shutting down, waiting up to %s for in-flight requests...\n", timeout)
+	// ctx is already done, the timeout must not inherit its cancellation
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
 	defer cancel()
 	for i, service := range services {
 		if err := service.Shutdown(ctx); err != nil {
@@ -552,6 +804,25 @@ func enableKVParamsMetrics(p kvparams.Config) kvparams.Config {
 	return p
 }
 
+// gatewayOverloadLimits returns the S3 gateway overload limits of cfg, shedding load by the connection pool of kvStore
+func gatewayOverloadLimits(cfg *config.Config, kvStore kv.Store) gateway.OverloadLimits {
+	overload := cfg.Gateways.S3.Overload
+	limits := gateway.OverloadLimits{
+		Deadline:           overload.Deadline,
+		OperationDeadlines: make(map[operations.OperationID]time.Duration, len(overload.OperationDeadlines)),
+		MaxPoolSaturation:  overload.MaxDBPoolSaturation,
+	}
+	for operationID, deadline := range overload.OperationDeadlines {
+		limits.OperationDeadlines[operations.OperationID(operationID)] = deadline
+	}
+	if pool, ok := kv.GetPoolStats(kvStore); ok {
+		limits.Pool = pool
+	} else if overload.MaxDBPoolSaturation > 0 {
+		logging.ContextUnavailable().WithField("database_type", cfg.Database.Type).Warn("Database has no connection pool, S3 gateway load shedding disabled")
+	}
+	return limits
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(runCmd)
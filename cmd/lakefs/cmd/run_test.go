@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracefulShutdownDrainsInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := &http.Server{
+		ReadHeaderTimeout: time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			_, _ = io.WriteString(w, "done")
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			resultCh <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		resultCh <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	// shut down while the request is in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	shutdownDone := make(chan struct{})
+	go func() {
+		gracefulShutdown(ctx, time.Minute, server)
+		close(shutdownDone)
+	}()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("shutdown completed before the in-flight request")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	res := <-resultCh
+	require.NoError(t, res.err)
+	require.Equal(t, "done", res.body)
+	<-shutdownDone
+}
@@ -454,3 +454,29 @@ func TestS3PutObjectTagging(t *testing.T) {
 	require.Equal(t, "ERRLakeFSNotSupported", errResponse.Code)
 	require.Equal(t, "This operation is not supported in LakeFS", errResponse.Message)
 }
+
+func TestS3PutObjectIfNoneMatch(t *testing.T) {
+	ctx, _, repo := setupTest(t)
+	defer tearDownTest(repo)
+
+	client := newMinioClient(t, credentials.NewStaticV4)
+	path := gatewayTestPrefix + "put-if-absent"
+	put := func(content string) error {
+		opts := minio.PutObjectOptions{}
+		opts.SetMatchETagExcept("*")
+		_, err := client.PutObject(ctx, repo, path, strings.NewReader(content), int64(len(content)), opts)
+		return err
+	}
+
+	require.NoError(t, put("first"))
+
+	err := put("second")
+	require.Error(t, err)
+	require.Equal(t, "PreconditionFailed", minio.ToErrorResponse(err).Code)
+
+	obj, err := client.GetObject(ctx, repo, path, minio.GetObjectOptions{})
+	require.NoError(t, err)
+	content, err := io.ReadAll(obj)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(content))
+}
@@ -0,0 +1,468 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// CacheInvalidationHook invalidates the prefixes changed by a commit or a merge in a CDN or cache serving data
+// exported from the branch, so that it does not serve stale data after promotions
+type CacheInvalidationHook struct {
+	HookBase
+	Provider   cacheInvalidationProvider
+	Prefixes   []string
+	PathPrefix string
+	MaxPaths   int
+	Retries    int
+	Timeout    time.Duration
+
+	// cloudfront
+	DistributionID  string
+	AccessKeyID     SecureString
+	SecretAccessKey SecureString
+	// fastly
+	ServiceID string
+	APIToken  SecureString
+	SoftPurge bool
+	// webhook
+	Headers map[string]SecureString
+
+	// URL is the webhook URL, or the API endpoint of the provider
+	URL string
+}
+
+type cacheInvalidationProvider string
+
+const (
+	cacheInvalidationProviderCloudFront cacheInvalidationProvider = "cloudfront"
+	cacheInvalidationProviderFastly     cacheInvalidationProvider = "fastly"
+	cacheInvalidationProviderWebhook    cacheInvalidationProvider = "webhook"
+)
+
+const (
+	cacheInvalidationProviderPropertyKey        = "provider"
+	cacheInvalidationPrefixesPropertyKey        = "prefixes"
+	cacheInvalidationPathPrefixPropertyKey      = "path_prefix"
+	cacheInvalidationMaxPathsPropertyKey        = "max_paths"
+	cacheInvalidationRetriesPropertyKey         = "retries"
+	cacheInvalidationTimeoutPropertyKey         = "timeout"
+	cacheInvalidationURLPropertyKey             = "url"
+	cacheInvalidationDistributionIDPropertyKey  = "distribution_id"
+	cacheInvalidationAccessKeyIDPropertyKey     = "access_key_id"
+	cacheInvalidationSecretAccessKeyPropertyKey = "secret_access_key"
+	cacheInvalidationServiceIDPropertyKey       = "service_id"
+	cacheInvalidationAPITokenPropertyKey        = "api_token"
+	cacheInvalidationSoftPurgePropertyKey       = "soft_purge"
+
+	cacheInvalidationDefaultMaxPaths = 100
+	cacheInvalidationDefaultRetries  = 5
+	cacheInvalidationDefaultTimeout  = 1 * time.Minute
+	cacheInvalidationListAmount      = 1000
+	// fastlyMaxSurrogateKeys is the number of keys Fastly purges in a single request
+	fastlyMaxSurrogateKeys = 256
+
+	cloudFrontDefaultEndpoint = "https://cloudfront.amazonaws.com"
+	cloudFrontAPIVersion      = "2020-05-31"
+	cloudFrontSigningRegion   = "us-east-1"
+	fastlyDefaultEndpoint     = "https://api.fastly.com"
+)
+
+var (
+	errCacheInvalidationWrongFormat   = errors.New("cache invalidation wrong format")
+	errCacheInvalidationRequestFailed = errors.New("cache invalidation request failed")
+)
+
+func NewCacheInvalidationHook(h ActionHook, action *Action, cfg Config, e *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	for event := range action.On {
+		if event != graveler.EventTypePostCommit && event != graveler.EventTypePostMerge {
+			return nil, fmt.Errorf("cache invalidation supports only %s and %s, not %s: %w",
+				graveler.EventTypePostCommit, graveler.EventTypePostMerge, event, errCacheInvalidationWrongFormat)
+		}
+	}
+	hook := &CacheInvalidationHook{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   e,
+		},
+		MaxPaths: cacheInvalidationDefaultMaxPaths,
+		Retries:  cacheInvalidationDefaultRetries,
+		Timeout:  cacheInvalidationDefaultTimeout,
+	}
+	provider, err := h.Properties.getRequiredProperty(cacheInvalidationProviderPropertyKey)
+	if err != nil {
+		return nil, fmt.Errorf("cache invalidation provider property: %w", err)
+	}
+	hook.Provider = cacheInvalidationProvider(provider)
+
+	switch v := h.Properties[cacheInvalidationPrefixesPropertyKey].(type) {
+	case nil:
+	case string:
+		hook.Prefixes = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			prefix, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("prefixes must be strings: %w", errCacheInvalidationWrongFormat)
+			}
+			hook.Prefixes = append(hook.Prefixes, prefix)
+		}
+	default:
+		return nil, fmt.Errorf("prefixes must be a string or a list of strings: %w", errCacheInvalidationWrongFormat)
+	}
+	if v, ok := h.Properties[cacheInvalidationPathPrefixPropertyKey].(string); ok {
+		hook.PathPrefix = v
+	}
+	if v, ok := h.Properties[cacheInvalidationMaxPathsPropertyKey].(int); ok {
+		if v <= 0 {
+			return nil, fmt.Errorf("max_paths must be positive: %w", errCacheInvalidationWrongFormat)
+		}
+		hook.MaxPaths = v
+	}
+	if v, ok := h.Properties[cacheInvalidationRetriesPropertyKey].(int); ok {
+		if v < 0 {
+			return nil, fmt.Errorf("retries must not be negative: %w", errCacheInvalidationWrongFormat)
+		}
+		hook.Retries = v
+	}
+	if v, ok := h.Properties[cacheInvalidationTimeoutPropertyKey].(string); ok && v != "" {
+		hook.Timeout, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("cache invalidation timeout property: %w", err)
+		}
+	}
+	hook.URL, _ = h.Properties[cacheInvalidationURLPropertyKey].(string)
+
+	envGetter := NewEnvironmentVariableGetter(cfg.Env.Enabled, cfg.Env.Prefix)
+	switch hook.Provider {
+	case cacheInvalidationProviderCloudFront:
+		hook.DistributionID, err = h.Properties.getRequiredProperty(cacheInvalidationDistributionIDPropertyKey)
+		if err != nil {
+			return nil, fmt.Errorf("cache invalidation distribution ID property: %w", err)
+		}
+		if hook.URL == "" {
+			hook.URL = cloudFrontDefaultEndpoint
+		}
+		// credentials are optional, the default AWS credentials of lakeFS are used without them
+		if v, ok := h.Properties[cacheInvalidationAccessKeyIDPropertyKey].(string); ok {
+			if hook.AccessKeyID, err = NewSecureString(v, envGetter); err != nil {
+				return nil, fmt.Errorf("cache invalidation access key ID property: %w", err)
+			}
+			secret, err := h.Properties.getRequiredProperty(cacheInvalidationSecretAccessKeyPropertyKey)
+			if err != nil {
+				return nil, fmt.Errorf("cache invalidation secret access key property: %w", err)
+			}
+			if hook.SecretAccessKey, err = NewSecureString(secret, envGetter); err != nil {
+				return nil, fmt.Errorf("cache invalidation secret access key property: %w", err)
+			}
+		}
+	case cacheInvalidationProviderFastly:
+		hook.ServiceID, err = h.Properties.getRequiredProperty(cacheInvalidationServiceIDPropertyKey)
+		if err != nil {
+			return nil, fmt.Errorf("cache invalidation service ID property: %w", err)
+		}
+		token, err := h.Properties.getRequiredProperty(cacheInvalidationAPITokenPropertyKey)
+		if err != nil {
+			return nil, fmt.Errorf("cache invalidation API token property: %w", err)
+		}
+		if hook.APIToken, err = NewSecureString(token, envGetter); err != nil {
+			return nil, fmt.Errorf("cache invalidation API token property: %w", err)
+		}
+		hook.SoftPurge, _ = h.Properties[cacheInvalidationSoftPurgePropertyKey].(bool)
+		if hook.URL == "" {
+			hook.URL = fastlyDefaultEndpoint
+		}
+	case cacheInvalidationProviderWebhook:
+		if hook.URL == "" {
+			return nil, fmt.Errorf("missing url: %w", errCacheInvalidationWrongFormat)
+		}
+		hook.Headers, err = extractHeaders(h.Properties, envGetter)
+		if err != nil {
+			return nil, fmt.Errorf("extracting headers: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown provider %s: %w", provider, errCacheInvalidationWrongFormat)
+	}
+	return hook, nil
+}
+
+func (h *CacheInvalidationHook) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", "cache_invalidation").
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	if record.EventType != graveler.EventTypePostCommit && record.EventType != graveler.EventTypePostMerge {
+		return fmt.Errorf("event %s: %w", record.EventType, errCacheInvalidationWrongFormat)
+	}
+	prefixes, err := h.changedPrefixes(ctx, record)
+	if err != nil {
+		return err
+	}
+	if len(prefixes) == 0 {
+		buf.WriteString("No changed paths to invalidate\n")
+		return nil
+	}
+	for i := range prefixes {
+		prefixes[i] = h.PathPrefix + prefixes[i]
+	}
+	_, _ = fmt.Fprintf(buf, "Invalidating %d prefix(es) with %s:\n%s\n\n", len(prefixes), h.Provider, strings.Join(prefixes, "\n"))
+
+	switch h.Provider {
+	case cacheInvalidationProviderCloudFront:
+		return h.invalidateCloudFront(ctx, record, prefixes, buf)
+	case cacheInvalidationProviderFastly:
+		for len(prefixes) > 0 {
+			n := len(prefixes)
+			if n > fastlyMaxSurrogateKeys {
+				n = fastlyMaxSurrogateKeys
+			}
+			if err := h.purgeFastly(ctx, prefixes[:n], buf); err != nil {
+				return err
+			}
+			prefixes = prefixes[n:]
+		}
+		return nil
+	default:
+		return h.callWebhook(ctx, record, prefixes, buf)
+	}
+}
+
+// changedPrefixes returns the sorted directories, ending with "/", of the paths changed on the branch by the commit
+// of record under the configured prefixes. When there are more than MaxPaths of them, it returns the configured
+// prefixes instead.
+func (h *CacheInvalidationHook) changedPrefixes(ctx context.Context, record graveler.HookRecord) ([]string, error) {
+	configured := h.Prefixes
+	if len(configured) == 0 {
+		configured = []string{""}
+	}
+	// the first commit of a branch has nothing to compare with
+	if record.PreviousCommitID == "" {
+		return configured, nil
+	}
+	if h.Endpoint == nil {
+		return nil, fmt.Errorf("no endpoint configured: %w", errCacheInvalidationWrongFormat)
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &hookClient{
+		ctx:        ctx,
+		user:       user,
+		endpoint:   h.Endpoint,
+		repository: record.RepositoryID.String(),
+	}
+
+	changed := make(map[string]struct{})
+	for _, prefix := range configured {
+		query := url.Values{
+			"prefix": {prefix},
+			"type":   {"two_dot"},
+			"amount": {strconv.Itoa(cacheInvalidationListAmount)},
+		}
+		for {
+			var diff apigen.DiffList
+			if err := c.getJSON(&diff, query, "refs", record.PreviousCommitID.String(), "diff", record.CommitID.String()); err != nil {
+				return nil, err
+			}
+			for _, d := range diff.Results {
+				if d.PathType != "object" {
+					continue
+				}
+				dir := path.Dir(d.Path)
+				if dir == "." {
+					dir = ""
+				} else {
+					dir += "/"
+				}
+				changed[dir] = struct{}{}
+			}
+			if len(changed) > h.MaxPaths {
+				return configured, nil
+			}
+			if !diff.Pagination.HasMore {
+				break
+			}
+			query.Set("after", diff.Pagination.NextOffset)
+		}
+	}
+	prefixes := make([]string, 0, len(changed))
+	for p := range changed {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	return prefixes, nil
+}
+
+// doWithRetries sends the request built by newRequest until it succeeds, retrying failures and responses with
+// status 429 or 5xx with exponential backoff
+func (h *CacheInvalidationHook) doWithRetries(ctx context.Context, buf *bytes.Buffer, newRequest func() (*http.Request, error)) error {
+	bo := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), uint64(h.Retries)), ctx)
+	attempt := 0
+	return backoff.Retry(func() error {
+		attempt++
+		req, err := newRequest()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		_, _ = fmt.Fprintf(buf, "Attempt %d: %s %s\n", attempt, req.Method, req.URL)
+		statusCode, err := doHTTPRequestWithLog(ctx, req, buf, h.Timeout)
+		buf.WriteString("\n")
+		if err != nil {
+			return err
+		}
+		if statusCode >= 200 && statusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("%w (status code: %d)", errCacheInvalidationRequestFailed, statusCode)
+		if statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError {
+			return err
+		}
+		return backoff.Permanent(err)
+	}, bo)
+}
+
+type cloudFrontInvalidationBatch struct {
+	XMLName         xml.Name `xml:"InvalidationBatch"`
+	XMLNS           string   `xml:"xmlns,attr"`
+	CallerReference string   `xml:"CallerReference"`
+	Quantity        int      `xml:"Paths>Quantity"`
+	Paths           []string `xml:"Paths>Items>Path"`
+}
+
+func (h *CacheInvalidationHook) invalidateCloudFront(ctx context.Context, record graveler.HookRecord, prefixes []string, buf *bytes.Buffer) error {
+	paths := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		paths[i] = p + "*"
+	}
+	body, err := xml.Marshal(cloudFrontInvalidationBatch{
+		XMLNS: "http://cloudfront.amazonaws.com/doc/" + cloudFrontAPIVersion + "/",
+		// identical on retries, so CloudFront creates a single invalidation for the run
+		CallerReference: record.RunID + "-" + h.ID,
+		Quantity:        len(paths),
+		Paths:           paths,
+	})
+	if err != nil {
+		return err
+	}
+	creds, err := h.awsCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("cloudfront credentials: %w", err)
+	}
+	reqURL, err := url.JoinPath(h.URL, cloudFrontAPIVersion, "distribution", h.DistributionID, "invalidation")
+	if err != nil {
+		return err
+	}
+	payloadHash := sha256.Sum256(body)
+	signer := v4.NewSigner()
+	return h.doWithRetries(ctx, buf, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/xml")
+		err = signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "cloudfront", cloudFrontSigningRegion, time.Now())
+		return req, err
+	})
+}
+
+func (h *CacheInvalidationHook) awsCredentials(ctx context.Context) (aws.Credentials, error) {
+	if h.AccessKeyID.val != "" {
+		return aws.Credentials{AccessKeyID: h.AccessKeyID.val, SecretAccessKey: h.SecretAccessKey.val}, nil
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	if cfg.Credentials == nil {
+		return aws.Credentials{}, fmt.Errorf("no AWS credentials: %w", errCacheInvalidationWrongFormat)
+	}
+	return cfg.Credentials.Retrieve(ctx)
+}
+
+func (h *CacheInvalidationHook) purgeFastly(ctx context.Context, keys []string, buf *bytes.Buffer) error {
+	reqURL, err := url.JoinPath(h.URL, "service", h.ServiceID, "purge")
+	if err != nil {
+		return err
+	}
+	return h.doWithRetries(ctx, buf, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Fastly-Key", h.APIToken.val)
+		req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+		if h.SoftPurge {
+			req.Header.Set("Fastly-Soft-Purge", "1")
+		}
+		return req, nil
+	})
+}
+
+// cacheInvalidationEvent is the body posted to webhook providers
+type cacheInvalidationEvent struct {
+	EventType        string   `json:"event_type"`
+	ActionName       string   `json:"action_name"`
+	HookID           string   `json:"hook_id"`
+	RepositoryID     string   `json:"repository_id"`
+	BranchID         string   `json:"branch_id"`
+	CommitID         string   `json:"commit_id"`
+	PreviousCommitID string   `json:"previous_commit_id,omitempty"`
+	Prefixes         []string `json:"prefixes"`
+}
+
+func (h *CacheInvalidationHook) callWebhook(ctx context.Context, record graveler.HookRecord, prefixes []string, buf *bytes.Buffer) error {
+	body, err := json.Marshal(cacheInvalidationEvent{
+		EventType:        string(record.EventType),
+		ActionName:       h.ActionName,
+		HookID:           h.ID,
+		RepositoryID:     record.RepositoryID.String(),
+		BranchID:         record.BranchID.String(),
+		CommitID:         record.CommitID.String(),
+		PreviousCommitID: record.PreviousCommitID.String(),
+		Prefixes:         prefixes,
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(buf, "Request Body:\n%s\n\n", body)
+	return h.doWithRetries(ctx, buf, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range h.Headers {
+			req.Header.Set(k, v.val)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
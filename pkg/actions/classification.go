@@ -0,0 +1,442 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// ClassificationHook samples the objects added or changed by a commit, runs detectors of sensitive data on the samples
+// and tags the objects in which they detected data. The tags are committed in a follow-up commit with the results in
+// its metadata, so classification does not depend on producers labeling their data.
+type ClassificationHook struct {
+	HookBase
+	Detectors   []classificationDetector
+	Prefixes    []string
+	SampleBytes int
+	MaxObjects  int
+	TagKey      string
+	Commit      bool
+	Timeout     time.Duration
+}
+
+// classificationDetector detects sensitive data in an object sample, either by matching a regular expression or by
+// calling an external classification service
+type classificationDetector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate rejects matches of Pattern that are not real findings, like numbers failing a checksum
+	Validate func(match []byte) bool
+	URL      string
+	Headers  map[string]SecureString
+}
+
+const (
+	classificationDetectorsPropertyKey   = "detectors"
+	classificationPrefixesPropertyKey    = "prefixes"
+	classificationSampleBytesPropertyKey = "sample_bytes"
+	classificationMaxObjectsPropertyKey  = "max_objects"
+	classificationTagKeyPropertyKey      = "tag_key"
+	classificationCommitPropertyKey      = "commit"
+	classificationTimeoutPropertyKey     = "timeout"
+
+	classificationDefaultSampleBytes = 64 * 1024
+	classificationDefaultMaxObjects  = 1000
+	classificationDefaultTagKey      = "classification"
+	classificationDefaultTimeout     = 30 * time.Second
+	classificationListAmount         = 1000
+
+	// classificationSourceCommitMetadataKey is set on the commit of the tags to the classified commit
+	classificationSourceCommitMetadataKey = "classification.source_commit"
+	// classificationDetectorMetadataKeyPrefix prefixes the number of objects each detector found data in, on the
+	// commit of the tags
+	classificationDetectorMetadataKeyPrefix = "classification.detector."
+)
+
+var (
+	errClassificationWrongFormat = errors.New("classification wrong format")
+	errClassificationDetector    = errors.New("classification detector failed")
+
+	reClassificationDetectorName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]*$`)
+
+	// builtinClassificationDetectors are used by detectors configured with a name and no pattern or url
+	builtinClassificationDetectors = map[string]classificationDetector{
+		"email": {
+			Pattern: regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+		},
+		"credit_card": {
+			Pattern:  regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+			Validate: luhnValid,
+		},
+		"us_ssn": {
+			Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		},
+		"phone": {
+			Pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?\(?\b\d{3}\)?[ .\-]\d{3}[ .\-]\d{4}\b`),
+		},
+		"aws_access_key_id": {
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),
+		},
+	}
+)
+
+func NewClassificationHook(h ActionHook, action *Action, cfg Config, e *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	for event := range action.On {
+		if event != graveler.EventTypePostCommit {
+			return nil, fmt.Errorf("classification supports only %s, not %s: %w", graveler.EventTypePostCommit, event, errClassificationWrongFormat)
+		}
+	}
+	hook := &ClassificationHook{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   e,
+		},
+		SampleBytes: classificationDefaultSampleBytes,
+		MaxObjects:  classificationDefaultMaxObjects,
+		TagKey:      classificationDefaultTagKey,
+		Commit:      true,
+		Timeout:     classificationDefaultTimeout,
+	}
+
+	envGetter := NewEnvironmentVariableGetter(cfg.Env.Enabled, cfg.Env.Prefix)
+	detectors, ok := h.Properties[classificationDetectorsPropertyKey].([]interface{})
+	if !ok || len(detectors) == 0 {
+		return nil, fmt.Errorf("missing detectors: %w", errClassificationWrongFormat)
+	}
+	names := make(map[string]struct{}, len(detectors))
+	for _, d := range detectors {
+		props, ok := d.(Properties)
+		if !ok {
+			return nil, fmt.Errorf("detectors must be maps: %w", errClassificationWrongFormat)
+		}
+		detector, err := newClassificationDetector(props, envGetter)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := names[detector.Name]; ok {
+			return nil, fmt.Errorf("detector %s defined more than once: %w", detector.Name, errClassificationWrongFormat)
+		}
+		names[detector.Name] = struct{}{}
+		hook.Detectors = append(hook.Detectors, detector)
+	}
+
+	switch v := h.Properties[classificationPrefixesPropertyKey].(type) {
+	case nil:
+	case string:
+		hook.Prefixes = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			prefix, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("prefixes must be strings: %w", errClassificationWrongFormat)
+			}
+			hook.Prefixes = append(hook.Prefixes, prefix)
+		}
+	default:
+		return nil, fmt.Errorf("prefixes must be a string or a list of strings: %w", errClassificationWrongFormat)
+	}
+	if v, ok := h.Properties[classificationSampleBytesPropertyKey].(int); ok {
+		if v <= 0 {
+			return nil, fmt.Errorf("sample_bytes must be positive: %w", errClassificationWrongFormat)
+		}
+		hook.SampleBytes = v
+	}
+	if v, ok := h.Properties[classificationMaxObjectsPropertyKey].(int); ok {
+		if v <= 0 {
+			return nil, fmt.Errorf("max_objects must be positive: %w", errClassificationWrongFormat)
+		}
+		hook.MaxObjects = v
+	}
+	if v, ok := h.Properties[classificationTagKeyPropertyKey].(string); ok && v != "" {
+		hook.TagKey = v
+	}
+	if v, ok := h.Properties[classificationCommitPropertyKey].(bool); ok {
+		hook.Commit = v
+	}
+	if v, ok := h.Properties[classificationTimeoutPropertyKey].(string); ok && v != "" {
+		var err error
+		hook.Timeout, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("classification timeout property: %w", err)
+		}
+	}
+	return hook, nil
+}
+
+func newClassificationDetector(props Properties, envGetter EnvGetter) (classificationDetector, error) {
+	name, err := props.getRequiredProperty("name")
+	if err != nil {
+		return classificationDetector{}, fmt.Errorf("detector name property: %w", err)
+	}
+	if !reClassificationDetectorName.MatchString(name) {
+		return classificationDetector{}, fmt.Errorf("detector name %s: %w", name, errClassificationWrongFormat)
+	}
+	pattern, _ := props["pattern"].(string)
+	detectorURL, _ := props["url"].(string)
+	switch {
+	case pattern != "" && detectorURL != "":
+		return classificationDetector{}, fmt.Errorf("detector %s has both pattern and url: %w", name, errClassificationWrongFormat)
+	case pattern != "":
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return classificationDetector{}, fmt.Errorf("detector %s pattern: %w", name, err)
+		}
+		return classificationDetector{Name: name, Pattern: re}, nil
+	case detectorURL != "":
+		headers, err := extractHeaders(props, envGetter)
+		if err != nil {
+			return classificationDetector{}, fmt.Errorf("detector %s headers: %w", name, err)
+		}
+		return classificationDetector{Name: name, URL: detectorURL, Headers: headers}, nil
+	default:
+		builtin, ok := builtinClassificationDetectors[name]
+		if !ok {
+			return classificationDetector{}, fmt.Errorf("detector %s is not built in and has no pattern or url: %w", name, errClassificationWrongFormat)
+		}
+		builtin.Name = name
+		return builtin, nil
+	}
+}
+
+// classificationResponse is the response of classification services to the sample posted to them
+type classificationResponse struct {
+	Detected bool `json:"detected"`
+}
+
+// detect returns whether the detector finds sensitive data in the sample of the object at path
+func (d *classificationDetector) detect(ctx context.Context, repository, path string, sample []byte, timeout time.Duration) (bool, error) {
+	if d.Pattern != nil {
+		for _, match := range d.Pattern.FindAll(sample, -1) {
+			if d.Validate == nil || d.Validate(match) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(sample))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range d.Headers {
+		req.Header.Set(k, v.val)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Lakefs-Repository", repository)
+	req.Header.Set("X-Lakefs-Path", path)
+	// the sample may contain sensitive data, so unlike webhooks the request and response are not logged
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("%w: %s: status code %d", errClassificationDetector, d.Name, resp.StatusCode)
+	}
+	var result classificationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("%w: %s: %s", errClassificationDetector, d.Name, err)
+	}
+	return result.Detected, nil
+}
+
+// luhnValid returns whether the digits of match pass the Luhn checksum of payment card numbers
+func luhnValid(match []byte) bool {
+	sum := 0
+	double := false
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		n := int(c - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func (h *ClassificationHook) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", "classification").
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	if record.EventType != graveler.EventTypePostCommit {
+		return fmt.Errorf("event %s: %w", record.EventType, errClassificationWrongFormat)
+	}
+	if record.PreviousCommitID == "" {
+		buf.WriteString("No previous commit to find added or changed objects\n")
+		return nil
+	}
+	if h.Endpoint == nil {
+		return fmt.Errorf("no endpoint configured: %w", errClassificationWrongFormat)
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	c := &hookClient{
+		ctx:        ctx,
+		user:       user,
+		endpoint:   h.Endpoint,
+		repository: record.RepositoryID.String(),
+	}
+
+	paths, err := h.changedPaths(c, record)
+	if err != nil {
+		return err
+	}
+	if len(paths) > h.MaxObjects {
+		_, _ = fmt.Fprintf(buf, "%d objects added or changed, classifying the first %d\n", len(paths), h.MaxObjects)
+		paths = paths[:h.MaxObjects]
+	}
+
+	counts := make(map[string]int)
+	var tagged []string
+	for _, p := range paths {
+		sample, err := c.readRange(record.CommitID.String(), p, "0-"+strconv.Itoa(h.SampleBytes-1))
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		var detected []string
+		for i := range h.Detectors {
+			d := &h.Detectors[i]
+			found, err := d.detect(ctx, record.RepositoryID.String(), p, sample, h.Timeout)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			if found {
+				detected = append(detected, d.Name)
+				counts[d.Name]++
+			}
+		}
+		if len(detected) == 0 {
+			continue
+		}
+		sort.Strings(detected)
+		value := strings.Join(detected, ",")
+		ok, err := h.tagObject(c, record, p, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if !ok {
+			_, _ = fmt.Fprintf(buf, "%s: %s (changed on %s since the commit, not tagged)\n", p, value, record.BranchID)
+			continue
+		}
+		_, _ = fmt.Fprintf(buf, "%s: %s\n", p, value)
+		tagged = append(tagged, p)
+	}
+	_, _ = fmt.Fprintf(buf, "Classified %d object(s), tagged %d\n", len(paths), len(tagged))
+	if len(tagged) == 0 || !h.Commit {
+		return nil
+	}
+
+	metadata := map[string]string{classificationSourceCommitMetadataKey: record.CommitID.String()}
+	for name, count := range counts {
+		metadata[classificationDetectorMetadataKeyPrefix+name] = strconv.Itoa(count)
+	}
+	var commit apigen.Commit
+	err = c.sendJSON(http.MethodPost, apigen.CommitCreation{
+		Message:  fmt.Sprintf("Classify objects added or changed by commit %s", record.CommitID),
+		Metadata: &apigen.CommitCreation_Metadata{AdditionalProperties: metadata},
+		Paths:    &tagged,
+	}, &commit, nil, "branches", record.BranchID.String(), "commits")
+	if err != nil {
+		return fmt.Errorf("commit tags: %w", err)
+	}
+	_, _ = fmt.Fprintf(buf, "Committed tags in %s\n", commit.Id)
+	return nil
+}
+
+// changedPaths returns the paths of the objects added or changed by the commit of record under the configured prefixes
+func (h *ClassificationHook) changedPaths(c *hookClient, record graveler.HookRecord) ([]string, error) {
+	prefixes := h.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	var paths []string
+	for _, prefix := range prefixes {
+		query := url.Values{
+			"prefix": {prefix},
+			"type":   {"two_dot"},
+			"amount": {strconv.Itoa(classificationListAmount)},
+		}
+		for {
+			var diff apigen.DiffList
+			if err := c.getJSON(&diff, query, "refs", record.PreviousCommitID.String(), "diff", record.CommitID.String()); err != nil {
+				return nil, err
+			}
+			for _, d := range diff.Results {
+				// empty objects have nothing to sample
+				if d.PathType == "object" && (d.Type == "added" || d.Type == "changed") && (d.SizeBytes == nil || *d.SizeBytes > 0) {
+					paths = append(paths, d.Path)
+				}
+			}
+			if !diff.Pagination.HasMore || len(paths) > h.MaxObjects {
+				break
+			}
+			query.Set("after", diff.Pagination.NextOffset)
+		}
+	}
+	return paths, nil
+}
+
+// tagObject stages the classification tag on the object at path on the branch of record. It returns false without
+// tagging if the object on the branch is no longer the one of the classified commit.
+func (h *ClassificationHook) tagObject(c *hookClient, record graveler.HookRecord, path, value string) (bool, error) {
+	var committed, current apigen.ObjectStats
+	if err := c.getJSON(&committed, url.Values{"path": {path}}, "refs", record.CommitID.String(), "objects", "stat"); err != nil {
+		return false, err
+	}
+	err := c.getJSON(&current, url.Values{"path": {path}}, "refs", record.BranchID.String(), "objects", "stat")
+	if errors.Is(err, errHookNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if current.Checksum != committed.Checksum || current.PhysicalAddress != committed.PhysicalAddress {
+		return false, nil
+	}
+	tags := map[string]string{}
+	if current.Tags != nil {
+		for k, v := range current.Tags.AdditionalProperties {
+			tags[k] = v
+		}
+	}
+	tags[h.TagKey] = value
+	err = c.sendJSON(http.MethodPut, apigen.ObjectMetadataUpdate{
+		Tags: &apigen.ObjectTags{AdditionalProperties: tags},
+	}, nil, url.Values{"path": {path}}, "branches", record.BranchID.String(), "objects", "metadata")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
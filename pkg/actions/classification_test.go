@@ -0,0 +1,225 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// classificationEndpoint is a lakeFS API endpoint serving the objects added and changed by the test commit, that records the
+// tags and the commit the hook makes
+type classificationEndpoint struct {
+	*http.Server
+	mu     sync.Mutex
+	tags   map[string]map[string]string
+	commit *apigen.CommitCreation
+}
+
+func newClassificationEndpoint(t *testing.T, objects map[string]string) *classificationEndpoint {
+	t.Helper()
+	e := &classificationEndpoint{tags: make(map[string]map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/repo1/refs/c0/diff/c1", func(w http.ResponseWriter, r *http.Request) {
+		results := []apigen.Diff{
+			{Path: "removed.csv", PathType: "object", Type: "removed"},
+		}
+		for _, p := range []string{"clean.csv", "customers.csv", "replaced.csv"} {
+			results = append(results, apigen.Diff{Path: p, PathType: "object", Type: "added"})
+		}
+		results = append(results, apigen.Diff{Path: "payments.csv", PathType: "object", Type: "changed"})
+		_ = json.NewEncoder(w).Encode(apigen.DiffList{Results: results})
+	})
+	mux.HandleFunc("/api/v1/repositories/repo1/refs/c1/objects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			t.Errorf("object read without range")
+		}
+		_, _ = io.WriteString(w, objects[r.URL.Query().Get("path")])
+	})
+	stat := func(ref string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			p := r.URL.Query().Get("path")
+			checksum := "committed"
+			if ref == "main" && p == "replaced.csv" {
+				checksum = "replaced"
+			}
+			stats := apigen.ObjectStats{Path: p, Checksum: checksum, PhysicalAddress: "s3://bucket/" + p}
+			if ref == "main" && p == "customers.csv" {
+				stats.Tags = &apigen.ObjectTags{AdditionalProperties: map[string]string{"owner": "crm"}}
+			}
+			_ = json.NewEncoder(w).Encode(stats)
+		}
+	}
+	mux.HandleFunc("/api/v1/repositories/repo1/refs/c1/objects/stat", stat("c1"))
+	mux.HandleFunc("/api/v1/repositories/repo1/refs/main/objects/stat", stat("main"))
+	mux.HandleFunc("/api/v1/repositories/repo1/branches/main/objects/metadata", func(w http.ResponseWriter, r *http.Request) {
+		var update apigen.ObjectMetadataUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Errorf("decode metadata update: %s", err)
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.tags[r.URL.Query().Get("path")] = update.Tags.AdditionalProperties
+		_ = json.NewEncoder(w).Encode(apigen.ObjectStats{Path: r.URL.Query().Get("path")})
+	})
+	mux.HandleFunc("/api/v1/repositories/repo1/branches/main/commits", func(w http.ResponseWriter, r *http.Request) {
+		var commit apigen.CommitCreation
+		if err := json.NewDecoder(r.Body).Decode(&commit); err != nil {
+			t.Errorf("decode commit: %s", err)
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.commit = &commit
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(apigen.Commit{Id: "c2"})
+	})
+	e.Server = &http.Server{Handler: mux}
+	return e
+}
+
+func runClassification(t *testing.T, endpoint *http.Server, properties actions.Properties) (string, error) {
+	t.Helper()
+	hook, err := actions.NewClassificationHook(
+		actions.ActionHook{ID: "classify", Type: actions.HookTypeClassification, Properties: properties},
+		&actions.Action{Name: "classify pii", On: map[graveler.EventType]*actions.ActionOn{graveler.EventTypePostCommit: nil}},
+		actions.Config{Enabled: true},
+		endpoint, "", nil)
+	if err != nil {
+		t.Fatalf("new classification hook: %s", err)
+	}
+	ctx := auth.WithUser(context.Background(), &model.User{Username: "user"})
+	var buf bytes.Buffer
+	err = hook.Run(ctx, graveler.HookRecord{
+		RunID:            "run1",
+		EventType:        graveler.EventTypePostCommit,
+		RepositoryID:     "repo1",
+		BranchID:         "main",
+		CommitID:         "c1",
+		PreviousCommitID: "c0",
+	}, &buf)
+	return buf.String(), err
+}
+
+var classificationTestObjects = map[string]string{
+	"clean.csv":     "id,amount\n1,100\n2,4111111111111112\n",
+	"customers.csv": "id,email,ssn\n1,jane@example.com,123-45-6789\n",
+	"payments.csv":  "id,card\n1,4111 1111 1111 1111\n",
+	"replaced.csv":  "id,email\n1,john@example.com\n",
+}
+
+func TestClassificationBuiltinDetectors(t *testing.T) {
+	endpoint := newClassificationEndpoint(t, classificationTestObjects)
+	output, err := runClassification(t, endpoint.Server, actions.Properties{
+		"detectors": []interface{}{
+			actions.Properties{"name": "email"},
+			actions.Properties{"name": "us_ssn"},
+			actions.Properties{"name": "credit_card"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("run: %s\n%s", err, output)
+	}
+	// the number in clean.csv fails the Luhn check, replaced.csv changed on the branch since the commit
+	expectedTags := map[string]map[string]string{
+		"customers.csv": {"owner": "crm", "classification": "email,us_ssn"},
+		"payments.csv":  {"classification": "credit_card"},
+	}
+	if diff := deep.Equal(endpoint.tags, expectedTags); diff != nil {
+		t.Errorf("tags: %s", diff)
+	}
+	if endpoint.commit == nil {
+		t.Fatal("tags not committed")
+	}
+	if diff := deep.Equal(*endpoint.commit.Paths, []string{"customers.csv", "payments.csv"}); diff != nil {
+		t.Errorf("committed paths: %s", diff)
+	}
+	expectedMetadata := map[string]string{
+		"classification.source_commit":        "c1",
+		"classification.detector.email":       "2",
+		"classification.detector.us_ssn":      "1",
+		"classification.detector.credit_card": "1",
+	}
+	if diff := deep.Equal(endpoint.commit.Metadata.AdditionalProperties, expectedMetadata); diff != nil {
+		t.Errorf("commit metadata: %s", diff)
+	}
+}
+
+func TestClassificationServiceDetector(t *testing.T) {
+	var paths []string
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		sample, _ := io.ReadAll(r.Body)
+		paths = append(paths, r.Header.Get("X-Lakefs-Path"))
+		_ = json.NewEncoder(w).Encode(map[string]bool{"detected": bytes.Contains(sample, []byte("jane"))})
+	}))
+	t.Cleanup(service.Close)
+
+	endpoint := newClassificationEndpoint(t, classificationTestObjects)
+	_, err := runClassification(t, endpoint.Server, actions.Properties{
+		"detectors": []interface{}{
+			actions.Properties{"name": "person", "url": service.URL, "headers": actions.Properties{"Authorization": "Bearer token"}},
+		},
+		"tag_key": "pii",
+		"commit":  false,
+	})
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+	if len(paths) != 4 {
+		t.Errorf("service called for %v, expected the 4 added and changed objects", paths)
+	}
+	if diff := deep.Equal(endpoint.tags, map[string]map[string]string{"customers.csv": {"owner": "crm", "pii": "person"}}); diff != nil {
+		t.Errorf("tags: %s", diff)
+	}
+	if endpoint.commit != nil {
+		t.Error("tags committed with commit disabled")
+	}
+}
+
+func TestClassificationInvalidProperties(t *testing.T) {
+	cases := []struct {
+		name       string
+		properties actions.Properties
+	}{
+		{name: "no detectors", properties: actions.Properties{}},
+		{name: "unknown builtin", properties: actions.Properties{"detectors": []interface{}{actions.Properties{"name": "unknown"}}}},
+		{name: "bad pattern", properties: actions.Properties{"detectors": []interface{}{actions.Properties{"name": "id", "pattern": "("}}}},
+		{name: "duplicate", properties: actions.Properties{"detectors": []interface{}{actions.Properties{"name": "email"}, actions.Properties{"name": "email"}}}},
+		{name: "pattern and url", properties: actions.Properties{"detectors": []interface{}{actions.Properties{"name": "id", "pattern": "x", "url": "http://localhost"}}}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := actions.NewClassificationHook(
+				actions.ActionHook{ID: "classify", Type: actions.HookTypeClassification, Properties: tt.properties},
+				&actions.Action{Name: "classify pii", On: map[graveler.EventType]*actions.ActionOn{graveler.EventTypePostCommit: nil}},
+				actions.Config{Enabled: true}, nil, "", nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	_, err := actions.NewClassificationHook(
+		actions.ActionHook{ID: "classify", Type: actions.HookTypeClassification, Properties: actions.Properties{
+			"detectors": []interface{}{actions.Properties{"name": "email"}},
+		}},
+		&actions.Action{Name: "classify pii", On: map[graveler.EventType]*actions.ActionOn{graveler.EventTypePreCommit: nil}},
+		actions.Config{Enabled: true}, nil, "", nil)
+	if err == nil {
+		t.Fatal("expected pre-commit to be rejected")
+	}
+}
@@ -0,0 +1,222 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// DatasetCheckHook fails a commit or a merge that adds or changes dataset descriptor files under the configured
+// prefixes that are not valid, or that lack any of the required fields
+type DatasetCheckHook struct {
+	HookBase
+	Prefixes []string
+	Require  []string
+}
+
+const (
+	datasetCheckPrefixesPropertyKey = "prefixes"
+	datasetCheckRequirePropertyKey  = "require"
+	datasetCheckListAmount          = 1000
+)
+
+var (
+	errDatasetCheckWrongFormat = errors.New("dataset check wrong format")
+	errInvalidDataset          = errors.New("invalid dataset descriptor")
+
+	// datasetCheckOptionalFields are the fields of a dataset descriptor that the hook can require
+	datasetCheckOptionalFields = map[string]func(apigen.Dataset) bool{
+		"description": func(d apigen.Dataset) bool { return d.Description != nil },
+		"schema":      func(d apigen.Dataset) bool { return d.Schema != nil },
+		"sla":         func(d apigen.Dataset) bool { return d.Sla != nil },
+		"tags":        func(d apigen.Dataset) bool { return d.Tags != nil },
+	}
+)
+
+func NewDatasetCheckHook(h ActionHook, action *Action, cfg Config, e *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	for event := range action.On {
+		if event != graveler.EventTypePreCommit && event != graveler.EventTypePreMerge {
+			return nil, fmt.Errorf("dataset check supports only %s and %s, not %s: %w", graveler.EventTypePreCommit, graveler.EventTypePreMerge, event, errDatasetCheckWrongFormat)
+		}
+	}
+	prefixes, err := datasetCheckStrings(h.Properties, datasetCheckPrefixesPropertyKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	require, err := datasetCheckStrings(h.Properties, datasetCheckRequirePropertyKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range require {
+		if _, ok := datasetCheckOptionalFields[field]; !ok {
+			return nil, fmt.Errorf("unknown required field %s: %w", field, errDatasetCheckWrongFormat)
+		}
+	}
+	return &DatasetCheckHook{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   e,
+		},
+		Prefixes: prefixes,
+		Require:  require,
+	}, nil
+}
+
+func datasetCheckStrings(properties Properties, key string) ([]string, error) {
+	switch v := properties[key].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be strings: %w", key, errDatasetCheckWrongFormat)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%s must be a string or a list of strings: %w", key, errDatasetCheckWrongFormat)
+	}
+}
+
+func (h *DatasetCheckHook) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", "dataset_check").
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	if h.Endpoint == nil {
+		return fmt.Errorf("no endpoint configured: %w", errDatasetCheckWrongFormat)
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	c := &hookClient{
+		ctx:        ctx,
+		user:       user,
+		endpoint:   h.Endpoint,
+		repository: record.RepositoryID.String(),
+	}
+
+	var ref string
+	var paths []string
+	for _, prefix := range h.Prefixes {
+		var changed []string
+		switch record.EventType {
+		case graveler.EventTypePreCommit:
+			ref = record.BranchID.String()
+			changed, err = c.uncommittedPaths(ref, prefix)
+		case graveler.EventTypePreMerge:
+			ref = record.SourceRef.String()
+			changed, err = c.changedPaths(record.BranchID.String(), ref, prefix)
+		default:
+			return fmt.Errorf("event %s: %w", record.EventType, errDatasetCheckWrongFormat)
+		}
+		if err != nil {
+			return err
+		}
+		for _, p := range changed {
+			if catalog.IsDatasetDescriptorPath(p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+
+	invalid := 0
+	for _, p := range paths {
+		problem, err := h.check(c, ref, p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if problem != "" {
+			invalid++
+			_, _ = fmt.Fprintf(buf, "%s: %s\n", p, problem)
+			continue
+		}
+		_, _ = fmt.Fprintf(buf, "%s: valid\n", p)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d file(s): %w", invalid, errInvalidDataset)
+	}
+	return nil
+}
+
+// check returns why the dataset descriptor at p on ref is invalid, or an empty string if it is valid
+func (h *DatasetCheckHook) check(c *hookClient, ref, p string) (string, error) {
+	prefix := path.Dir(p) + "/"
+	if prefix == "./" {
+		prefix = ""
+	}
+	rr, err := c.get(url.Values{"prefix": {prefix}}, nil, "refs", ref, "datasets", "descriptor")
+	if err != nil {
+		return "", err
+	}
+	switch rr.Code {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		var e apigen.Error
+		if err := json.Unmarshal(rr.Body.Bytes(), &e); err != nil {
+			return "", err
+		}
+		return e.Message, nil
+	default:
+		return "", fmt.Errorf("%w: get dataset %s: HTTP %d", errHookRequest, prefix, rr.Code)
+	}
+	var dataset apigen.Dataset
+	if err := json.Unmarshal(rr.Body.Bytes(), &dataset); err != nil {
+		return "", err
+	}
+	for _, field := range h.Require {
+		if !datasetCheckOptionalFields[field](dataset) {
+			return fmt.Sprintf("'%s' is required", field), nil
+		}
+	}
+	return "", nil
+}
+
+// uncommittedPaths returns the paths under prefix added or changed on branch and not committed yet
+func (c *hookClient) uncommittedPaths(branch, prefix string) ([]string, error) {
+	var paths []string
+	query := url.Values{
+		"prefix": {prefix},
+		"amount": {strconv.Itoa(datasetCheckListAmount)},
+	}
+	for {
+		var diff apigen.DiffList
+		if err := c.getJSON(&diff, query, "branches", branch, "diff"); err != nil {
+			return nil, err
+		}
+		for _, d := range diff.Results {
+			if d.PathType == "object" && (d.Type == "added" || d.Type == "changed") {
+				paths = append(paths, d.Path)
+			}
+		}
+		if !diff.Pagination.HasMore {
+			return paths, nil
+		}
+		query.Set("after", diff.Pagination.NextOffset)
+	}
+}
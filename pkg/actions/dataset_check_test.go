@@ -0,0 +1,34 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestDatasetCheckProperties(t *testing.T) {
+	cases := []struct {
+		name       string
+		properties actions.Properties
+		event      graveler.EventType
+		expectErr  bool
+	}{
+		{name: "defaults", properties: actions.Properties{}, event: graveler.EventTypePreCommit},
+		{name: "pre merge", properties: actions.Properties{"prefixes": []interface{}{"tables/"}, "require": "schema"}, event: graveler.EventTypePreMerge},
+		{name: "post commit", properties: actions.Properties{}, event: graveler.EventTypePostCommit, expectErr: true},
+		{name: "unknown required field", properties: actions.Properties{"require": []interface{}{"owner_email"}}, event: graveler.EventTypePreCommit, expectErr: true},
+		{name: "bad prefixes", properties: actions.Properties{"prefixes": 3}, event: graveler.EventTypePreCommit, expectErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := actions.NewDatasetCheckHook(
+				actions.ActionHook{ID: "datasets", Type: actions.HookTypeDatasetCheck, Properties: tt.properties},
+				&actions.Action{Name: "check datasets", On: map[graveler.EventType]*actions.ActionOn{tt.event: nil}},
+				actions.Config{Enabled: true}, nil, "", nil)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("NewDatasetCheckHook() error = %v, expected error %t", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -14,9 +14,13 @@ import (
 type HookType string
 
 const (
-	HookTypeWebhook HookType = "webhook"
-	HookTypeAirflow HookType = "airflow"
-	HookTypeLua     HookType = "lua"
+	HookTypeWebhook           HookType = "webhook"
+	HookTypeAirflow           HookType = "airflow"
+	HookTypeLua               HookType = "lua"
+	HookTypeSchemaCheck       HookType = "schema_check"
+	HookTypeCacheInvalidation HookType = "cache_invalidation"
+	HookTypeClassification    HookType = "classification"
+	HookTypeDatasetCheck      HookType = "dataset_check"
 )
 
 // Hook is the abstraction of the basic user-configured runnable building-stone
@@ -34,9 +38,13 @@ type HookBase struct {
 }
 
 var hooks = map[HookType]NewHookFunc{
-	HookTypeWebhook: NewWebhook,
-	HookTypeAirflow: NewAirflowHook,
-	HookTypeLua:     NewLuaHook,
+	HookTypeWebhook:           NewWebhook,
+	HookTypeAirflow:           NewAirflowHook,
+	HookTypeLua:               NewLuaHook,
+	HookTypeSchemaCheck:       NewSchemaCheckHook,
+	HookTypeCacheInvalidation: NewCacheInvalidationHook,
+	HookTypeClassification:    NewClassificationHook,
+	HookTypeDatasetCheck:      NewDatasetCheckHook,
 }
 
 var ErrUnknownHookType = errors.New("unknown hook type")
@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+)
+
+var (
+	errHookRequest  = errors.New("hook request failed")
+	errHookNotFound = fmt.Errorf("%w: not found", errHookRequest)
+)
+
+// hookClient accesses a repository through the lakeFS API, with the permissions of the user running the hook
+type hookClient struct {
+	ctx        context.Context
+	user       *model.User
+	endpoint   *http.Server
+	repository string
+}
+
+func (c *hookClient) get(query url.Values, header http.Header, elem ...string) (*httptest.ResponseRecorder, error) {
+	return c.do(http.MethodGet, query, header, nil, elem...)
+}
+
+func (c *hookClient) do(method string, query url.Values, header http.Header, body []byte, elem ...string) (*httptest.ResponseRecorder, error) {
+	reqURL, err := url.JoinPath(apiutil.BaseURL, append([]string{"repositories", c.repository}, elem...)...)
+	if err != nil {
+		return nil, err
+	}
+	// clear the routing information of the request running the hook, so it does not break routing the sub-request
+	ctx := context.WithValue(c.ctx, chi.RouteCtxKey, nil)
+	req, err := http.NewRequestWithContext(auth.WithUser(ctx, c.user), method, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = query.Encode()
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rr := httptest.NewRecorder()
+	c.endpoint.Handler.ServeHTTP(rr, req)
+	return rr, nil
+}
+
+func (c *hookClient) getJSON(v interface{}, query url.Values, elem ...string) error {
+	rr, err := c.get(query, nil, elem...)
+	if err != nil {
+		return err
+	}
+	switch rr.Code {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errHookNotFound, path.Join(elem...))
+	default:
+		return fmt.Errorf("%w: %s: HTTP %d", errHookRequest, path.Join(elem...), rr.Code)
+	}
+	return json.Unmarshal(rr.Body.Bytes(), v)
+}
+
+// sendJSON sends v as the JSON body of the request and decodes the response into result when it is not nil
+func (c *hookClient) sendJSON(method string, v, result interface{}, query url.Values, elem ...string) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	rr, err := c.do(method, query, http.Header{"Content-Type": {"application/json"}}, body, elem...)
+	if err != nil {
+		return err
+	}
+	if rr.Code < 200 || rr.Code >= 300 {
+		return fmt.Errorf("%w: %s %s: HTTP %d: %s", errHookRequest, method, path.Join(elem...), rr.Code, bytes.TrimSpace(rr.Body.Bytes()))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rr.Body.Bytes(), result)
+}
@@ -0,0 +1,109 @@
+local json = require("encoding/json")
+local regexp = require("regexp")
+local utils = require("lakefs/catalogexport/internal")
+local DEFAULT_PAGE_SIZE = 1000
+local MAX_REPORTED_VIOLATIONS = 20
+
+-- resolve the ref holding the objects changed by a pre-commit or pre-merge event
+local function changes_ref(action_info)
+    local event = action_info.event_type
+    if event == "pre-commit" then
+        return action_info.branch_id
+    elseif event == "pre-merge" then
+        return action_info.source_ref
+    else
+        error("unsupported event type: " .. tostring(action_info.event_type))
+    end
+end
+
+-- iterator over the objects added or changed by a pre-commit or pre-merge event, each result is a diff entry
+local function changed_objects(client, action_info, prefix)
+    local repo_id = action_info.repository_id
+    local ref = changes_ref(action_info)
+    local pager = utils.lakefs_paginiated_api(function(next_offset)
+        if action_info.event_type == "pre-commit" then
+            return client.diff_branch(repo_id, ref, next_offset, DEFAULT_PAGE_SIZE, prefix or "", "")
+        end
+        -- merge changes are the source changes since the merge base with the destination branch
+        return client.diff_refs(repo_id, action_info.branch_id, ref, next_offset, prefix or "", "", DEFAULT_PAGE_SIZE)
+    end, "")
+    local page = {}
+    local idx = 0
+    return function()
+        while true do
+            idx = idx + 1
+            local entry = page[idx]
+            if entry == nil then
+                page = pager()
+                if page == nil then
+                    return nil
+                end
+                idx = 0
+            elseif entry.path_type == "object" and (entry.type == "added" or entry.type == "changed") then
+                return entry
+            end
+        end
+    end
+end
+
+-- return the violations of rules by a changed object
+local function object_violations(client, repo_id, ref, entry, rules)
+    local violations = {}
+    if rules.name_pattern and not regexp.match(rules.name_pattern, entry.path) then
+        table.insert(violations, "path does not match pattern " .. rules.name_pattern)
+    end
+    local size = entry.size_bytes
+    local metadata
+    if rules.required_metadata and #rules.required_metadata > 0 or (rules.max_size_bytes and size == nil) then
+        local code, content = client.stat_object(repo_id, ref, entry.path)
+        if code ~= 200 then
+            error("lakeFS: stat object " .. entry.path .. " returned " .. tostring(code))
+        end
+        local stats = json.unmarshal(content)
+        size = size or stats.size_bytes
+        metadata = stats.metadata or {}
+    end
+    if rules.max_size_bytes and size > rules.max_size_bytes then
+        table.insert(violations, "size " .. tostring(size) .. " bytes exceeds " .. tostring(rules.max_size_bytes) .. " bytes")
+    end
+    for _, key in ipairs(rules.required_metadata or {}) do
+        if metadata[key] == nil or metadata[key] == "" then
+            table.insert(violations, "missing metadata key " .. key)
+        end
+    end
+    return violations
+end
+
+--[[
+    Fail a pre-commit or pre-merge hook when objects it adds or changes break any of the rules:
+    - prefix: validate only objects under this prefix
+    - name_pattern: regular expression the object path must match
+    - max_size_bytes: maximal object size
+    - required_metadata: array of user metadata keys each object must have
+]]
+local function check_objects(client, action_info, rules)
+    local ref = changes_ref(action_info)
+    local violations = {}
+    local count = 0
+    for entry in changed_objects(client, action_info, rules.prefix) do
+        for _, violation in ipairs(object_violations(client, action_info.repository_id, ref, entry, rules)) do
+            count = count + 1
+            if count <= MAX_REPORTED_VIOLATIONS then
+                table.insert(violations, entry.path .. ": " .. violation)
+            end
+        end
+    end
+    if count == 0 then
+        return
+    end
+    local msg = "object validation failed:\n" .. table.concat(violations, "\n")
+    if count > MAX_REPORTED_VIOLATIONS then
+        msg = msg .. "\n... and " .. tostring(count - MAX_REPORTED_VIOLATIONS) .. " more"
+    end
+    error(msg, 0)
+end
+
+return {
+    changed_objects = changed_objects,
+    check_objects = check_objects,
+}
@@ -374,6 +374,11 @@ func TestLuaRunTable(t *testing.T) {
 			Name:  "catalogexport_unity",
 			Input: "testdata/lua/catalogexport_unity.lua",
 		},
+		{
+			Name:   "validation_check_objects",
+			Input:  "testdata/lua/validation_check_objects.lua",
+			Output: "testdata/lua/validation_check_objects.output",
+		},
 	}
 
 	for _, testCase := range tests {
@@ -0,0 +1,307 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+type schemaFormat string
+
+const (
+	schemaFormatParquet schemaFormat = "parquet"
+	schemaFormatAvro    schemaFormat = "avro"
+
+	parquetMagic       = "PAR1"
+	parquetTrailerSize = 8
+)
+
+var (
+	avroMagic = []byte("Obj\x01")
+
+	errInvalidDataFile = errors.New("invalid data file")
+)
+
+// schemaColumn is a column of a data file schema. Nested fields are flattened to dot separated names.
+type schemaColumn struct {
+	Name       string
+	Type       string
+	Optional   bool
+	HasDefault bool
+}
+
+type tableSchema []schemaColumn
+
+// typeWidening lists for each column type the types it can be safely promoted to
+var typeWidening = map[string][]string{
+	"INT32":  {"INT64"},
+	"FLOAT":  {"DOUBLE"},
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// schemaFormatOf returns the format of a data file by its extension, or an empty format for other files
+func schemaFormatOf(p string) schemaFormat {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".parquet":
+		return schemaFormatParquet
+	case ".avro":
+		return schemaFormatAvro
+	default:
+		return ""
+	}
+}
+
+// checkSchemaEvolution returns the changes from the current schema to the next schema that break readers of the
+// current schema: removed columns, type changes other than widening, optional columns that become required and new
+// required columns without a default.
+func checkSchemaEvolution(current, next tableSchema) []string {
+	var problems []string
+	nextColumns := make(map[string]schemaColumn, len(next))
+	for _, col := range next {
+		nextColumns[col.Name] = col
+	}
+	currentColumns := make(map[string]schemaColumn, len(current))
+	for _, col := range current {
+		currentColumns[col.Name] = col
+		nextCol, ok := nextColumns[col.Name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("column %s removed", col.Name))
+		case nextCol.Type != col.Type && !isWidening(col.Type, nextCol.Type):
+			problems = append(problems, fmt.Sprintf("column %s changed type from %s to %s", col.Name, col.Type, nextCol.Type))
+		case col.Optional && !nextCol.Optional:
+			problems = append(problems, fmt.Sprintf("column %s changed from optional to required", col.Name))
+		}
+	}
+	for _, col := range next {
+		if _, ok := currentColumns[col.Name]; !ok && !col.Optional && !col.HasDefault {
+			problems = append(problems, fmt.Sprintf("column %s added as required without a default", col.Name))
+		}
+	}
+	return problems
+}
+
+func isWidening(from, to string) bool {
+	for _, t := range typeWidening[from] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// parseParquetSchema returns the schema of a Parquet file from its footer, followed by the file trailer
+func parseParquetSchema(footer []byte) (tableSchema, error) {
+	if len(footer) < parquetTrailerSize || string(footer[len(footer)-len(parquetMagic):]) != parquetMagic {
+		return nil, fmt.Errorf("%w: missing parquet magic", errInvalidDataFile)
+	}
+	metadata := parquet.NewFileMetaData()
+	transport := thrift.NewStreamTransportR(bytes.NewReader(footer[:len(footer)-parquetTrailerSize]))
+	protocol := thrift.NewTCompactProtocolConf(transport, nil)
+	if err := metadata.Read(context.Background(), protocol); err != nil {
+		return nil, fmt.Errorf("%w: read parquet footer: %s", errInvalidDataFile, err)
+	}
+	elements := metadata.GetSchema()
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("%w: empty parquet schema", errInvalidDataFile)
+	}
+
+	var schema tableSchema
+	var walk func(i int, prefix string, optional bool) (int, error)
+	walk = func(i int, prefix string, optional bool) (int, error) {
+		if i >= len(elements) {
+			return 0, fmt.Errorf("%w: truncated parquet schema", errInvalidDataFile)
+		}
+		el := elements[i]
+		name := el.GetName()
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		optional = optional || el.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL
+		next := i + 1
+		if el.GetNumChildren() == 0 {
+			schema = append(schema, schemaColumn{Name: name, Type: parquetColumnType(el), Optional: optional})
+			return next, nil
+		}
+		for c := int32(0); c < el.GetNumChildren(); c++ {
+			var err error
+			if next, err = walk(next, name, optional); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	}
+	next := 1
+	for c := int32(0); c < elements[0].GetNumChildren(); c++ {
+		var err error
+		if next, err = walk(next, "", false); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+func parquetColumnType(el *parquet.SchemaElement) string {
+	t := el.GetType().String()
+	if el.IsSetConvertedType() {
+		t += "/" + el.GetConvertedType().String()
+	}
+	if el.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		t = "repeated " + t
+	}
+	return t
+}
+
+// parseAvroSchema returns the schema of an Avro object container file from the beginning of the file, which must
+// include the whole file header
+func parseAvroSchema(header []byte) (tableSchema, error) {
+	if !bytes.HasPrefix(header, avroMagic) {
+		return nil, fmt.Errorf("%w: missing avro magic", errInvalidDataFile)
+	}
+	r := bytes.NewReader(header[len(avroMagic):])
+	var schemaJSON []byte
+	for {
+		count, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read avro header: %s", errInvalidDataFile, err)
+		}
+		if count == 0 {
+			break
+		}
+		if count < 0 {
+			// a negative count is followed by the size of the block
+			count = -count
+			if _, err := binary.ReadVarint(r); err != nil {
+				return nil, fmt.Errorf("%w: read avro header: %s", errInvalidDataFile, err)
+			}
+		}
+		for ; count > 0; count-- {
+			key, err := readAvroBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			value, err := readAvroBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			if string(key) == "avro.schema" {
+				schemaJSON = value
+			}
+		}
+	}
+	if schemaJSON == nil {
+		return nil, fmt.Errorf("%w: missing avro.schema metadata", errInvalidDataFile)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(schemaJSON, &record); err != nil {
+		return nil, fmt.Errorf("%w: parse avro schema: %s", errInvalidDataFile, err)
+	}
+	var schema tableSchema
+	avroRecordColumns(&schema, "", record, false)
+	return schema, nil
+}
+
+func readAvroBytes(r *bytes.Reader) ([]byte, error) {
+	size, err := binary.ReadVarint(r)
+	if err != nil || size < 0 || size > int64(r.Len()) {
+		return nil, fmt.Errorf("%w: truncated avro header", errInvalidDataFile)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("%w: truncated avro header", errInvalidDataFile)
+	}
+	return b, nil
+}
+
+// avroRecordColumns appends the fields of an Avro record to schema, flattening nested records
+func avroRecordColumns(schema *tableSchema, prefix string, record map[string]interface{}, optional bool) {
+	fields, _ := record["fields"].([]interface{})
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		t, nullable := avroNonNullType(field["type"])
+		if nested, ok := t.(map[string]interface{}); ok && nested["type"] == "record" {
+			avroRecordColumns(schema, name, nested, optional || nullable)
+			continue
+		}
+		_, hasDefault := field["default"]
+		*schema = append(*schema, schemaColumn{
+			Name:       name,
+			Type:       avroTypeName(t),
+			Optional:   optional || nullable,
+			HasDefault: hasDefault,
+		})
+	}
+}
+
+// avroNonNullType returns the type of a nullable union without its null branch, and whether the type is nullable
+func avroNonNullType(t interface{}) (interface{}, bool) {
+	union, ok := t.([]interface{})
+	if !ok {
+		return t, t == "null"
+	}
+	var types []interface{}
+	nullable := false
+	for _, u := range union {
+		if u == "null" {
+			nullable = true
+		} else {
+			types = append(types, u)
+		}
+	}
+	if len(types) == 1 {
+		return types[0], nullable
+	}
+	return types, nullable
+}
+
+func avroTypeName(t interface{}) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case []interface{}:
+		names := make([]string, len(v))
+		for i, u := range v {
+			names[i] = avroTypeName(u)
+		}
+		return "union<" + strings.Join(names, ",") + ">"
+	case map[string]interface{}:
+		name, _ := v["type"].(string)
+		switch name {
+		case "array":
+			name = "array<" + avroTypeName(v["items"]) + ">"
+		case "map":
+			name = "map<" + avroTypeName(v["values"]) + ">"
+		case "record", "enum", "fixed":
+			if n, ok := v["name"].(string); ok {
+				name += ":" + n
+			}
+		}
+		if logicalType, ok := v["logicalType"].(string); ok {
+			name += "/" + logicalType
+		}
+		return name
+	default:
+		return fmt.Sprint(v)
+	}
+}
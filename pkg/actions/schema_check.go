@@ -0,0 +1,241 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// SchemaCheckHook fails a merge that changes the schema of Parquet or Avro files under the configured prefixes in a
+// way that breaks readers of the destination branch
+type SchemaCheckHook struct {
+	HookBase
+	Prefixes []string
+}
+
+const (
+	schemaCheckPrefixesPropertyKey = "prefixes"
+	schemaCheckListAmount          = 1000
+	// avroHeaderMaxSize is the size read from the beginning of Avro files, it must contain the file header
+	avroHeaderMaxSize = 1024 * 1024
+)
+
+var (
+	errSchemaCheckWrongFormat = errors.New("schema check wrong format")
+	errIncompatibleSchema     = errors.New("incompatible schema evolution")
+)
+
+func NewSchemaCheckHook(h ActionHook, action *Action, cfg Config, e *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	for event := range action.On {
+		if event != graveler.EventTypePreMerge {
+			return nil, fmt.Errorf("schema check supports only %s, not %s: %w", graveler.EventTypePreMerge, event, errSchemaCheckWrongFormat)
+		}
+	}
+	var prefixes []string
+	switch v := h.Properties[schemaCheckPrefixesPropertyKey].(type) {
+	case string:
+		prefixes = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			prefix, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("prefixes must be strings: %w", errSchemaCheckWrongFormat)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("missing prefixes: %w", errSchemaCheckWrongFormat)
+	}
+	return &SchemaCheckHook{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   e,
+		},
+		Prefixes: prefixes,
+	}, nil
+}
+
+func (h *SchemaCheckHook) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", "schema_check").
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	if record.EventType != graveler.EventTypePreMerge {
+		return fmt.Errorf("event %s: %w", record.EventType, errSchemaCheckWrongFormat)
+	}
+	if h.Endpoint == nil {
+		return fmt.Errorf("no endpoint configured: %w", errSchemaCheckWrongFormat)
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	c := &hookClient{
+		ctx:        ctx,
+		user:       user,
+		endpoint:   h.Endpoint,
+		repository: record.RepositoryID.String(),
+	}
+	destination := record.BranchID.String()
+	source := record.SourceRef.String()
+
+	incompatible := 0
+	for _, prefix := range h.Prefixes {
+		paths, err := c.changedPaths(destination, source, prefix)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			format := schemaFormatOf(p)
+			if format == "" {
+				continue
+			}
+			next, err := c.readSchema(source, p, format)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			currentPath, current, err := c.destinationSchema(destination, p, format)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			if current == nil {
+				_, _ = fmt.Fprintf(buf, "%s: no %s file on %s to compare with\n", p, format, destination)
+				continue
+			}
+			problems := checkSchemaEvolution(current, next)
+			if len(problems) == 0 {
+				_, _ = fmt.Fprintf(buf, "%s: compatible with %s\n", p, currentPath)
+				continue
+			}
+			incompatible++
+			_, _ = fmt.Fprintf(buf, "%s: incompatible with %s on %s:\n", p, currentPath, destination)
+			for _, problem := range problems {
+				_, _ = fmt.Fprintf(buf, "  - %s\n", problem)
+			}
+		}
+	}
+	if incompatible > 0 {
+		return fmt.Errorf("%d file(s): %w", incompatible, errIncompatibleSchema)
+	}
+	return nil
+}
+
+// changedPaths returns the paths under prefix added or changed on source since its merge base with destination
+func (c *hookClient) changedPaths(destination, source, prefix string) ([]string, error) {
+	var paths []string
+	query := url.Values{
+		"prefix": {prefix},
+		"amount": {strconv.Itoa(schemaCheckListAmount)},
+	}
+	for {
+		var diff apigen.DiffList
+		if err := c.getJSON(&diff, query, "refs", destination, "diff", source); err != nil {
+			return nil, err
+		}
+		for _, d := range diff.Results {
+			if d.PathType == "object" && (d.Type == "added" || d.Type == "changed") {
+				paths = append(paths, d.Path)
+			}
+		}
+		if !diff.Pagination.HasMore {
+			return paths, nil
+		}
+		query.Set("after", diff.Pagination.NextOffset)
+	}
+}
+
+// destinationSchema returns the schema of the file at p on the destination branch, or of another file of the same
+// format in its directory if it does not exist. It returns a nil schema if there is no such file.
+func (c *hookClient) destinationSchema(destination, p string, format schemaFormat) (string, tableSchema, error) {
+	schema, err := c.readSchema(destination, p, format)
+	if err == nil {
+		return p, schema, nil
+	}
+	if !errors.Is(err, errHookNotFound) {
+		return "", nil, err
+	}
+	dir := path.Dir(p) + "/"
+	if dir == "./" {
+		dir = ""
+	}
+	query := url.Values{
+		"prefix":    {dir},
+		"delimiter": {"/"},
+		"amount":    {strconv.Itoa(schemaCheckListAmount)},
+	}
+	for {
+		var objects apigen.ObjectStatsList
+		if err := c.getJSON(&objects, query, "refs", destination, "objects", "ls"); err != nil {
+			return "", nil, err
+		}
+		for _, o := range objects.Results {
+			if o.PathType == "object" && schemaFormatOf(o.Path) == format {
+				schema, err := c.readSchema(destination, o.Path, format)
+				return o.Path, schema, err
+			}
+		}
+		if !objects.Pagination.HasMore {
+			return "", nil, nil
+		}
+		query.Set("after", objects.Pagination.NextOffset)
+	}
+}
+
+// readRange reads a byte range of the object at p, like the HTTP Range header
+func (c *hookClient) readRange(ref, p, byteRange string) ([]byte, error) {
+	rr, err := c.get(url.Values{"path": {p}}, http.Header{"Range": {"bytes=" + byteRange}}, "refs", ref, "objects")
+	if err != nil {
+		return nil, err
+	}
+	switch rr.Code {
+	case http.StatusOK, http.StatusPartialContent:
+		return rr.Body.Bytes(), nil
+	case http.StatusNotFound:
+		return nil, errHookNotFound
+	default:
+		return nil, fmt.Errorf("%w: read %s: HTTP %d", errHookRequest, p, rr.Code)
+	}
+}
+
+func (c *hookClient) readSchema(ref, p string, format schemaFormat) (tableSchema, error) {
+	switch format {
+	case schemaFormatParquet:
+		trailer, err := c.readRange(ref, p, "-"+strconv.Itoa(parquetTrailerSize))
+		if err != nil {
+			return nil, err
+		}
+		if len(trailer) != parquetTrailerSize {
+			return nil, fmt.Errorf("%w: parquet file too short", errInvalidDataFile)
+		}
+		footerSize := binary.LittleEndian.Uint32(trailer)
+		footer, err := c.readRange(ref, p, "-"+strconv.FormatUint(uint64(footerSize)+parquetTrailerSize, 10))
+		if err != nil {
+			return nil, err
+		}
+		return parseParquetSchema(footer)
+	case schemaFormatAvro:
+		header, err := c.readRange(ref, p, "0-"+strconv.Itoa(avroHeaderMaxSize-1))
+		if err != nil {
+			return nil, err
+		}
+		return parseAvroSchema(header)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %s", errInvalidDataFile, format)
+	}
+}
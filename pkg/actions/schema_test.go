@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type parquetTestRecord struct {
+	ID   int64   `parquet:"name=id, type=INT64"`
+	Name *string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+func TestParseParquetSchema(t *testing.T) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(parquetTestRecord), 1)
+	if err != nil {
+		t.Fatalf("NewParquetWriterFromWriter: %s", err)
+	}
+	name := "a"
+	if err := pw.Write(parquetTestRecord{ID: 1, Name: &name}); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("WriteStop: %s", err)
+	}
+	data := buf.Bytes()
+	footerSize := binary.LittleEndian.Uint32(data[len(data)-parquetTrailerSize:])
+
+	// only the footer and trailer are read from the file
+	schema, err := parseParquetSchema(data[len(data)-parquetTrailerSize-int(footerSize):])
+	if err != nil {
+		t.Fatalf("parseParquetSchema: %s", err)
+	}
+	expected := tableSchema{
+		{Name: "id", Type: "INT64"},
+		{Name: "name", Type: "BYTE_ARRAY/UTF8", Optional: true},
+	}
+	if diff := deep.Equal(schema, expected); diff != nil {
+		t.Fatalf("Schema diff: %s", diff)
+	}
+
+	if _, err := parseParquetSchema([]byte("not parquet")); !errors.Is(err, errInvalidDataFile) {
+		t.Fatalf("parseParquetSchema() err=%v, expected %v", err, errInvalidDataFile)
+	}
+}
+
+// avroHeader returns the header of an Avro object container file with the schema
+func avroHeader(schema string) []byte {
+	var b bytes.Buffer
+	b.Write(avroMagic)
+	writeVarint := func(v int64) {
+		b.Write(binary.AppendVarint(nil, v))
+	}
+	writeBytes := func(s string) {
+		writeVarint(int64(len(s)))
+		b.WriteString(s)
+	}
+	writeVarint(2)
+	writeBytes("avro.codec")
+	writeBytes("null")
+	writeBytes("avro.schema")
+	writeBytes(schema)
+	writeVarint(0)
+	b.WriteString("0123456789abcdef") // sync marker
+	return b.Bytes()
+}
+
+func TestParseAvroSchema(t *testing.T) {
+	header := avroHeader(`{"type": "record", "name": "event", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "ts", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "user", "type": ["null", {"type": "record", "name": "user", "fields": [{"name": "name", "type": "string"}]}]},
+		{"name": "tags", "type": {"type": "array", "items": "string"}, "default": []}
+	]}`)
+	schema, err := parseAvroSchema(header)
+	if err != nil {
+		t.Fatalf("parseAvroSchema: %s", err)
+	}
+	expected := tableSchema{
+		{Name: "id", Type: "long"},
+		{Name: "ts", Type: "long/timestamp-millis"},
+		{Name: "user.name", Type: "string", Optional: true},
+		{Name: "tags", Type: "array<string>", HasDefault: true},
+	}
+	if diff := deep.Equal(schema, expected); diff != nil {
+		t.Fatalf("Schema diff: %s", diff)
+	}
+
+	if _, err := parseAvroSchema(header[:20]); !errors.Is(err, errInvalidDataFile) {
+		t.Fatalf("parseAvroSchema() of truncated header err=%v, expected %v", err, errInvalidDataFile)
+	}
+}
+
+func TestCheckSchemaEvolution(t *testing.T) {
+	current := tableSchema{
+		{Name: "id", Type: "INT32"},
+		{Name: "name", Type: "BYTE_ARRAY/UTF8", Optional: true},
+		{Name: "score", Type: "FLOAT", Optional: true},
+	}
+	tests := []struct {
+		name     string
+		next     tableSchema
+		problems []string
+	}{
+		{
+			name: "unchanged",
+			next: current,
+		},
+		{
+			name: "widening and optional column added",
+			next: tableSchema{
+				{Name: "id", Type: "INT64"},
+				{Name: "name", Type: "BYTE_ARRAY/UTF8", Optional: true},
+				{Name: "score", Type: "DOUBLE", Optional: true},
+				{Name: "email", Type: "BYTE_ARRAY/UTF8", Optional: true},
+			},
+		},
+		{
+			name: "incompatible",
+			next: tableSchema{
+				{Name: "id", Type: "BYTE_ARRAY/UTF8"},
+				{Name: "name", Type: "BYTE_ARRAY/UTF8"},
+				{Name: "age", Type: "INT32"},
+			},
+			problems: []string{
+				"column id changed type from INT32 to BYTE_ARRAY/UTF8",
+				"column name changed from optional to required",
+				"column score removed",
+				"column age added as required without a default",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := checkSchemaEvolution(current, tt.next)
+			if diff := deep.Equal(problems, tt.problems); diff != nil {
+				t.Fatalf("Problems diff: %s", diff)
+			}
+		})
+	}
+}
@@ -32,9 +32,10 @@ const (
 )
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrNilValue      = errors.New("nil value")
-	ErrIfExprNotBool = errors.New("hook 'if' expression should evaluate to a boolean")
+	ErrNotFound         = errors.New("not found")
+	ErrNilValue         = errors.New("nil value")
+	ErrIfExprNotBool    = errors.New("hook 'if' expression should evaluate to a boolean")
+	ErrRunNotRerunnable = errors.New("run cannot be re-run")
 )
 
 type Config struct {
@@ -215,6 +216,7 @@ type Service interface {
 	GetTaskResult(ctx context.Context, repositoryID string, runID string, hookRunID string) (*TaskResult, error)
 	ListRunResults(ctx context.Context, repositoryID string, branchID, commitID string, after string) (RunResultIterator, error)
 	ListRunTaskResults(ctx context.Context, repositoryID string, runID string, after string) (TaskResultIterator, error)
+	Rerun(ctx context.Context, record graveler.HookRecord) (*RunResult, error)
 	graveler.HooksHandler
 }
 
@@ -519,6 +521,28 @@ func (s *StoreService) ListRunTaskResults(ctx context.Context, repositoryID stri
 	return s.Store.ListRunTaskResults(ctx, repositoryID, runID, after)
 }
 
+// Rerun runs the actions of a post-commit or post-merge event again, as a new run. record describes the original
+// event, its run ID is replaced. Returns the result of the new run, also when its hooks fail.
+func (s *StoreService) Rerun(ctx context.Context, record graveler.HookRecord) (*RunResult, error) {
+	if !s.cfg.Enabled {
+		return nil, fmt.Errorf("%w: actions are disabled", ErrRunNotRerunnable)
+	}
+	if record.EventType != graveler.EventTypePostCommit && record.EventType != graveler.EventTypePostMerge {
+		return nil, fmt.Errorf("%w: unsupported event type %s", ErrRunNotRerunnable, record.EventType)
+	}
+	record.RunID = s.NewRunID()
+	runErr := s.Run(ctx, record)
+	result, err := s.GetRunResult(ctx, record.RepositoryID.String(), record.RunID)
+	if err != nil {
+		// run information is saved once the hooks run, report the failure that prevented it
+		if runErr != nil {
+			return nil, runErr
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *StoreService) PreCommitHook(ctx context.Context, record graveler.HookRecord) error {
 	return s.Run(ctx, record)
 }
@@ -581,6 +605,37 @@ func (s *StoreService) PostDeleteBranchHook(ctx context.Context, record graveler
 	s.asyncRun(ctx, record)
 }
 
+// CheckRenameBranch fails when an action of the branch selects it by name and would no longer select it once
+// renamed. Actions are committed with the data of the branch, so a rename cannot update them.
+func (s *StoreService) CheckRenameBranch(ctx context.Context, record graveler.HookRecord, newBranchID graveler.BranchID) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+	actions, err := LoadActions(ctx, s.Source, record)
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		for eventType := range action.On {
+			matched, err := action.Match(MatchSpec{EventType: eventType, BranchID: record.BranchID})
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+			matched, err = action.Match(MatchSpec{EventType: eventType, BranchID: newBranchID})
+			if err != nil {
+				return err
+			}
+			if !matched {
+				return fmt.Errorf("action %s selects branch %s on %s: %w", action.Name, record.BranchID, eventType, graveler.ErrConflictFound)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *StoreService) NewRunID() string {
 	return s.idGen.NewRunID()
 }
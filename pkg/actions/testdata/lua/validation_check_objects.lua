@@ -0,0 +1,63 @@
+local json = require("encoding/json")
+local validation = require("lakefs/validation")
+
+-- lakefs mock package
+
+local diff = {
+    {path = "tables/events/part-0.parquet", path_type = "object", type = "added", size_bytes = 100},
+    {path = "tables/events/part-1.parquet", path_type = "object", type = "changed", size_bytes = 5000},
+    {path = "tables/events/notes.txt", path_type = "object", type = "added", size_bytes = 10},
+    {path = "tables/events/old.parquet", path_type = "object", type = "removed", size_bytes = 10},
+}
+
+local metadata = {
+    ["tables/events/part-0.parquet"] = {owner = "data-team"},
+    ["tables/events/part-1.parquet"] = {owner = "data-team"},
+    ["tables/events/notes.txt"] = {},
+}
+
+local function diff_page(after)
+    local from = 1
+    if after ~= "" then
+        from = tonumber(after)
+    end
+    local results = {}
+    for i = from, math.min(from + 1, #diff) do
+        table.insert(results, diff[i])
+    end
+    return 200, {
+        results = results,
+        pagination = {has_more = from + 2 <= #diff, next_offset = tostring(from + 2)}
+    }
+end
+
+local lakefs = {
+    diff_branch = function(repo_id, branch_id, after, amount, prefix, delimiter)
+        return diff_page(after)
+    end,
+    diff_refs = function(repo_id, left_ref, right_ref, after, prefix, delimiter, amount)
+        return diff_page(after)
+    end,
+    stat_object = function(repo_id, ref_id, path)
+        return 200, json.marshal({path = path, metadata = metadata[path]})
+    end
+}
+
+local pre_commit = {repository_id = "example", branch_id = "main", event_type = "pre-commit"}
+local pre_merge = {repository_id = "example", branch_id = "main", source_ref = "abc123", event_type = "pre-merge"}
+
+for entry in validation.changed_objects(lakefs, pre_merge, "tables/") do
+    print("changed: " .. entry.path)
+end
+
+local ok, err = pcall(validation.check_objects, lakefs, pre_commit, {
+    prefix = "tables/",
+    name_pattern = "\\.parquet$",
+    max_size_bytes = 1024,
+    required_metadata = {"owner"},
+})
+print("pre-commit valid: " .. tostring(ok))
+print(err)
+
+ok = pcall(validation.check_objects, lakefs, pre_merge, {max_size_bytes = 10000})
+print("pre-merge valid: " .. tostring(ok))
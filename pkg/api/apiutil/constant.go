@@ -7,4 +7,6 @@ const LakeFSMetadataPrefix = "::lakefs::"
 const (
 	LakeFSHeaderInternalPrefix = "x-lakefs-internal-"
 	LakeFSHeaderMetadataPrefix = "x-lakefs-meta-"
+	// LakeFSHeaderAuthorizationDenial explains a denied request to users allowed to simulate their own authorization
+	LakeFSHeaderAuthorizationDenial = "x-lakefs-authorization-denial"
 )
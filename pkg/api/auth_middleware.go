@@ -16,6 +16,8 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/auth/model"
 	oidcencoding "github.com/treeverse/lakefs/pkg/auth/oidc/encoding"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
 )
 
@@ -57,7 +59,7 @@ type CookieAuthConfig struct {
 	AuthSource              string
 }
 
-func GenericAuthMiddleware(logger logging.Logger, authenticator auth.Authenticator, authService auth.Service, oidcConfig *OIDCConfig, cookieAuthConfig *CookieAuthConfig) (func(next http.Handler) http.Handler, error) {
+func GenericAuthMiddleware(logger logging.Logger, authenticator auth.Authenticator, authService auth.Service, lockout *auth.Lockout, oidcConfig *OIDCConfig, cookieAuthConfig *CookieAuthConfig) (func(next http.Handler) http.Handler, error) {
 	swagger, err := apigen.GetSwagger()
 	if err != nil {
 		return nil, err
@@ -65,13 +67,17 @@ func GenericAuthMiddleware(logger logging.Logger, authenticator auth.Authenticat
 	sessionStore := sessions.NewCookieStore(authService.SecretStore().SharedSecret())
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			user, err := checkSecurityRequirements(r, swagger.Security, logger, authenticator, authService, sessionStore, oidcConfig, cookieAuthConfig)
+			user, accessToken, err := checkSecurityRequirements(r, swagger.Security, logger, authenticator, authService, lockout, sessionStore, oidcConfig, cookieAuthConfig)
 			if err != nil {
 				writeError(w, r, http.StatusUnauthorized, err)
 				return
 			}
 			if user != nil {
 				ctx := logging.AddFields(r.Context(), logging.Fields{logging.UserFieldKey: user.Username})
+				ctx = graveler.WithCommitAuthor(ctx, user.Username)
+				if accessToken != nil {
+					ctx = auth.WithAccessToken(ctx, accessToken)
+				}
 				r = r.WithContext(auth.WithUser(ctx, user))
 			}
 			next.ServeHTTP(w, r)
@@ -79,7 +85,7 @@ func GenericAuthMiddleware(logger logging.Logger, authenticator auth.Authenticat
 	}, nil
 }
 
-func AuthMiddleware(logger logging.Logger, swagger *openapi3.Swagger, authenticator auth.Authenticator, authService auth.Service, sessionStore sessions.Store, oidcConfig *OIDCConfig, cookieAuthConfig *CookieAuthConfig) func(next http.Handler) http.Handler {
+func AuthMiddleware(logger logging.Logger, swagger *openapi3.Swagger, authenticator auth.Authenticator, authService auth.Service, lockout *auth.Lockout, usage *auth.CredentialsUsage, sessionStore sessions.Store, oidcConfig *OIDCConfig, cookieAuthConfig *CookieAuthConfig) func(next http.Handler) http.Handler {
 	router, err := legacy.NewRouter(swagger)
 	if err != nil {
 		panic(err)
@@ -96,14 +102,23 @@ func AuthMiddleware(logger logging.Logger, swagger *openapi3.Swagger, authentica
 				writeError(w, r, http.StatusBadRequest, err)
 				return
 			}
-			user, err := checkSecurityRequirements(r, securityRequirements, logger, authenticator, authService, sessionStore, oidcConfig, cookieAuthConfig)
+			user, accessToken, err := checkSecurityRequirements(r, securityRequirements, logger, authenticator, authService, lockout, sessionStore, oidcConfig, cookieAuthConfig)
 			if err != nil {
 				writeError(w, r, http.StatusUnauthorized, err)
 				return
 			}
 			if user != nil {
 				ctx := logging.AddFields(r.Context(), logging.Fields{logging.UserFieldKey: user.Username})
+				ctx = graveler.WithCommitAuthor(ctx, user.Username)
+				if accessToken != nil {
+					ctx = auth.WithAccessToken(ctx, accessToken)
+				}
 				r = r.WithContext(auth.WithUser(ctx, user))
+				// basic auth shares the authorization header with tokens, so it authenticated the user
+				if accessKey, _, ok := r.BasicAuth(); ok {
+					usage.Handler(LoggerServiceName, accessKey, user.Username, next).ServeHTTP(w, r)
+					return
+				}
 			}
 			next.ServeHTTP(w, r)
 		})
@@ -112,17 +127,20 @@ func AuthMiddleware(logger logging.Logger, swagger *openapi3.Swagger, authentica
 
 // checkSecurityRequirements goes over the security requirements and check the authentication. returns the user information and error if the security check was required.
 // it will return nil user and error in case of no security checks to match.
+// the returned access token is set when the request was authenticated by a personal access token.
 func checkSecurityRequirements(r *http.Request,
 	securityRequirements openapi3.SecurityRequirements,
 	logger logging.Logger,
 	authenticator auth.Authenticator,
 	authService auth.Service,
+	lockout *auth.Lockout,
 	sessionStore sessions.Store,
 	oidcConfig *OIDCConfig,
 	cookieAuthConfig *CookieAuthConfig,
-) (*model.User, error) {
+) (*model.User, *model.AccessToken, error) {
 	ctx := r.Context()
 	var user *model.User
+	var accessToken *model.AccessToken
 	var err error
 
 	logger = logger.WithContext(ctx)
@@ -141,14 +159,18 @@ func checkSecurityRequirements(r *http.Request,
 					continue
 				}
 				token := parts[1]
-				user, err = userByToken(ctx, logger, authService, token)
+				if auth.IsAccessToken(token) {
+					user, accessToken, err = userByAccessToken(ctx, logger, authService, token)
+				} else {
+					user, err = userByToken(ctx, logger, authService, token, httputil.SourceHost(r))
+				}
 			case "basic_auth":
 				// validate using basic auth
 				accessKey, secretKey, ok := r.BasicAuth()
 				if !ok {
 					continue
 				}
-				user, err = userByAuth(ctx, logger, authenticator, authService, accessKey, secretKey)
+				user, err = userByAuth(ctx, logger, authenticator, authService, lockout, accessKey, secretKey, lockout.SourceIP(r))
 			case "cookie_auth":
 				var internalAuthSession *sessions.Session
 				internalAuthSession, _ = sessionStore.Get(r, InternalAuthSessionName)
@@ -159,36 +181,36 @@ func checkSecurityRequirements(r *http.Request,
 				if token == "" {
 					continue
 				}
-				user, err = userByToken(ctx, logger, authService, token)
+				user, err = userByToken(ctx, logger, authService, token, httputil.SourceHost(r))
 			case "oidc_auth":
 				var oidcSession *sessions.Session
 				oidcSession, err = sessionStore.Get(r, OIDCAuthSessionName)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				user, err = userFromOIDC(ctx, logger, authService, oidcSession, oidcConfig)
 			case "saml_auth":
 				var samlSession *sessions.Session
 				samlSession, err = sessionStore.Get(r, SAMLAuthSessionName)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				user, err = userFromSAML(ctx, logger, authService, samlSession, cookieAuthConfig)
 			default:
 				// unknown security requirement to check
 				logger.WithField("provider", provider).Error("Authentication middleware unknown security requirement provider")
-				return nil, ErrAuthenticatingRequest
+				return nil, nil, ErrAuthenticatingRequest
 			}
 
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if user != nil {
-				return user, nil
+				return user, accessToken, nil
 			}
 		}
 	}
-	return nil, nil
+	return nil, nil, nil
 }
 
 func enhanceWithFriendlyName(user *model.User, friendlyName string) *model.User {
@@ -360,12 +382,19 @@ func userFromOIDC(ctx context.Context, logger logging.Logger, authService auth.S
 	return enhanceWithFriendlyName(&u, friendlyName), nil
 }
 
-func userByToken(ctx context.Context, logger logging.Logger, authService auth.Service, tokenString string) (*model.User, error) {
+func userByToken(ctx context.Context, logger logging.Logger, authService auth.Service, tokenString, sourceIP string) (*model.User, error) {
 	claims, err := auth.VerifyToken(authService.SecretStore().SharedSecret(), tokenString)
 	// make sure no audience is set for login token
 	if err != nil || !claims.VerifyAudience(LoginAudience, false) {
 		return nil, ErrAuthenticatingRequest
 	}
+	// login tokens of a service that does not track sessions cannot be revoked
+	if sessionManager, ok := auth.SessionManagerOf(ctx, authService); ok {
+		if err := sessionManager.UseSession(ctx, claims.Id, sourceIP); err != nil {
+			logger.WithError(err).WithField("token_id", claims.Id).Debug("could not use login session")
+			return nil, ErrAuthenticatingRequest
+		}
+	}
 
 	username := claims.Subject
 	userData, err := authService.GetUser(ctx, username)
@@ -380,17 +409,49 @@ func userByToken(ctx context.Context, logger logging.Logger, authService auth.Se
 	return userData, nil
 }
 
-func userByAuth(ctx context.Context, logger logging.Logger, authenticator auth.Authenticator, authService auth.Service, accessKey string, secretKey string) (*model.User, error) {
+func userByAccessToken(ctx context.Context, logger logging.Logger, authService auth.Service, tokenString string) (*model.User, *model.AccessToken, error) {
+	tokenManager, ok := authService.(auth.AccessTokenManager)
+	if !ok {
+		return nil, nil, ErrAuthenticatingRequest
+	}
+	accessToken, err := tokenManager.AuthenticateAccessToken(ctx, tokenString)
+	if err != nil {
+		logger.WithError(err).Debug("could not authenticate access token")
+		return nil, nil, ErrAuthenticatingRequest
+	}
+	user, err := authService.GetUser(ctx, accessToken.Username)
+	if err != nil {
+		logger.WithFields(logging.Fields{
+			"access_token_id": accessToken.ID,
+			"username":        accessToken.Username,
+		}).Debug("could not find user of access token")
+		return nil, nil, ErrAuthenticatingRequest
+	}
+	return user, accessToken, nil
+}
+
+func userByAuth(ctx context.Context, logger logging.Logger, authenticator auth.Authenticator, authService auth.Service, lockout *auth.Lockout, accessKey, secretKey, sourceIP string) (*model.User, error) {
+	if err := lockout.Check(accessKey, sourceIP); err != nil {
+		logger.WithError(err).WithFields(logging.Fields{"user": accessKey, "source_ip": sourceIP}).Warn("authenticate")
+		return nil, fmt.Errorf("%w: %s", ErrAuthenticatingRequest, err)
+	}
 	// TODO(ariels): Rename keys.
 	username, err := authenticator.AuthenticateUser(ctx, accessKey, secretKey)
 	if err != nil {
 		logger.WithError(err).WithField("user", accessKey).Error("authenticate")
+		lockout.RecordFailure(ctx, LoggerServiceName, accessKey, sourceIP)
 		return nil, ErrAuthenticatingRequest
 	}
+	lockout.RecordSuccess(accessKey)
 	user, err := authService.GetUser(ctx, username)
 	if err != nil {
 		logger.WithError(err).WithFields(logging.Fields{"user_name": username}).Debug("could not find user id by credentials")
 		return nil, ErrAuthenticatingRequest
 	}
+	if sessionManager, ok := auth.SessionManagerOf(ctx, authService); ok {
+		if err := sessionManager.RecordCredentialsUse(ctx, accessKey, sourceIP); err != nil {
+			logger.WithError(err).WithField("user", accessKey).Warn("could not record credentials use")
+		}
+	}
 	return user, nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/ingest/store"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -149,6 +150,53 @@ func TestAuthMiddleware(t *testing.T) {
 	})
 }
 
+// untrackedAuthService is an auth service that does not track sessions, like the external auth API
+type untrackedAuthService struct {
+	auth.Service
+}
+
+func TestAuthMiddleware_UntrackedSessions(t *testing.T) {
+	handler, _ := setupHandlerWithAuth(t, store.NewFactory(nil), func(svc auth.Service) auth.Service {
+		return untrackedAuthService{Service: svc}
+	})
+	server := setupServer(t, handler)
+	apiEndpoint := server.URL + apiutil.BaseURL
+	clt := setupClientByEndpoint(t, server.URL, "", "")
+	cred := createDefaultAdminUser(t, clt)
+	ctx := context.Background()
+
+	// the wrapper hides every manager of the auth service, so call an API that only authenticates
+	t.Run("basic auth", func(t *testing.T) {
+		authClient := setupClientByEndpoint(t, server.URL, cred.AccessKeyID, cred.SecretAccessKey)
+		resp, err := authClient.GetCurrentUserWithResponse(ctx)
+		if err != nil {
+			t.Fatal("GetCurrentUser() should return without error:", err)
+		}
+		if resp.StatusCode() != http.StatusOK {
+			t.Fatalf("unexpected status code %d, expected %d", resp.StatusCode(), http.StatusOK)
+		}
+	})
+
+	t.Run("login token", func(t *testing.T) {
+		apiToken := testGenerateApiToken(ctx, t, clt, cred)
+		authProvider, err := securityprovider.NewSecurityProviderApiKey("header", "Authorization", "Bearer "+apiToken)
+		if err != nil {
+			t.Fatal("bearer security provider", err)
+		}
+		authClient, err := apigen.NewClientWithResponses(apiEndpoint, apigen.WithRequestEditorFn(authProvider.Intercept))
+		if err != nil {
+			t.Fatal("failed to create lakefs api client:", err)
+		}
+		resp, err := authClient.GetCurrentUserWithResponse(ctx)
+		if err != nil {
+			t.Fatal("GetCurrentUser() should return without error:", err)
+		}
+		if resp.StatusCode() != http.StatusOK {
+			t.Fatalf("unexpected status code %d, expected %d", resp.StatusCode(), http.StatusOK)
+		}
+	})
+}
+
 func testGenerateApiToken(ctx context.Context, t testing.TB, clt apigen.ClientWithResponsesInterface, cred *model.BaseCredential) string {
 	t.Helper()
 	loginReq := apigen.LoginJSONRequestBody{
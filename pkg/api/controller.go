@@ -8,11 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/mail"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
@@ -22,7 +25,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-openapi/swag"
+	"github.com/google/uuid"
 	"github.com/gorilla/sessions"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/treeverse/lakefs/pkg/actions"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
@@ -37,11 +42,13 @@ import (
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/jobs"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
 	tablediff "github.com/treeverse/lakefs/pkg/plugins/diff"
 	"github.com/treeverse/lakefs/pkg/samplerepo"
+	"github.com/treeverse/lakefs/pkg/search"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/treeverse/lakefs/pkg/validator"
@@ -53,8 +60,12 @@ const (
 	DefaultMaxPerPage int = 1000
 	// DefaultPerPage is the default number of results returned for paginated queries to the API
 	DefaultPerPage int = 100
+	// DefaultTreeDepth is the default number of directory levels returned by the object tree API
+	DefaultTreeDepth int = 1
+	// DefaultTreeDelimiter is the default delimiter separating directory levels in the object tree API
+	DefaultTreeDelimiter = "/"
 
-	lakeFSPrefix = "symlinks"
+	lakeFSPrefix = catalog.SymlinkManifestsPrefix
 
 	actionStatusCompleted = "completed"
 	actionStatusFailed    = "failed"
@@ -63,6 +74,11 @@ const (
 	entryTypeObject       = "object"
 	entryTypeCommonPrefix = "common_prefix"
 
+	// diffContentMaxSize is the largest object compared by the object content diff API
+	diffContentMaxSize = 1024 * 1024
+	// diffContentDefaultContext is the default number of unchanged lines around each change of a content diff
+	diffContentDefaultContext = 3
+
 	DefaultMaxDeleteObjects = 1000
 
 	// httpStatusClientClosedRequest used as internal status code when request context is cancelled
@@ -76,6 +92,7 @@ type actionsHandler interface {
 	GetTaskResult(ctx context.Context, repositoryID, runID, hookRunID string) (*actions.TaskResult, error)
 	ListRunResults(ctx context.Context, repositoryID, branchID, commitID, after string) (actions.RunResultIterator, error)
 	ListRunTaskResults(ctx context.Context, repositoryID, runID, after string) (actions.TaskResultIterator, error)
+	Rerun(ctx context.Context, record graveler.HookRecord) (*actions.RunResult, error)
 }
 
 type Migrator interface {
@@ -87,6 +104,8 @@ type Controller struct {
 	Catalog               *catalog.Catalog
 	Authenticator         auth.Authenticator
 	Auth                  auth.Service
+	Lockout               *auth.Lockout
+	CredentialsUsage      *auth.CredentialsUsage
 	BlockAdapter          block.Adapter
 	MetadataManager       auth.MetadataManager
 	Migrator              Migrator
@@ -99,6 +118,9 @@ type Controller struct {
 	PathProvider          upload.PathProvider
 	otfDiffService        *tablediff.Service
 	usageReporter         stats.UsageReporterOperations
+	jobsRunner            *jobs.Runner
+	// Search is the object search index, nil when search is disabled
+	Search *search.Service
 }
 
 var usageCounter = stats.NewUsageCounter()
@@ -166,6 +188,10 @@ func (c *Controller) CreatePresignMultipartUpload(w http.ResponseWriter, r *http
 		writeError(w, r, http.StatusBadRequest, "path is required")
 		return
 	}
+	err = c.Catalog.ValidateEntryKey(ctx, repository, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 
 	// check valid number of parts
 	if params.Parts != nil {
@@ -357,6 +383,9 @@ func (c *Controller) CompletePresignMultipartUpload(w http.ResponseWriter, r *ht
 	}
 
 	checksum := httputil.StripQuotesAndSpaces(mpuResp.ETag)
+	if multipartETag, ok := block.MultipartETag(multipartList); ok {
+		checksum = multipartETag
+	}
 	entryBuilder := catalog.NewDBEntryBuilder().
 		CommonLevel(false).
 		Path(params.Path).
@@ -484,9 +513,9 @@ func (c *Controller) DeleteObjects(w http.ResponseWriter, r *http.Request, body
 	delErr := c.Catalog.DeleteEntries(ctx, repository, branch, pathsToDelete, graveler.WithForce(swag.BoolValue(params.Force)))
 	delErrs := graveler.NewMapDeleteErrors(delErr)
 	for _, objectPath := range pathsToDelete {
-		// set err to the specific error when possible
+		// set err to the specific error when possible, paths without one were deleted when others have one
 		err := delErrs[objectPath]
-		if err == nil {
+		if err == nil && len(delErrs) == 0 {
 			err = delErr
 		}
 		lg := c.Logger.WithField("path", objectPath)
@@ -494,7 +523,9 @@ func (c *Controller) DeleteObjects(w http.ResponseWriter, r *http.Request, body
 		case errors.Is(err, graveler.ErrNotFound):
 			lg.WithError(err).Debug("tried to delete a non-existent object")
 		case errors.Is(err, graveler.ErrWriteToProtectedBranch),
-			errors.Is(err, graveler.ErrReadOnlyRepository):
+			errors.Is(err, graveler.ErrBranchLocked),
+			errors.Is(err, graveler.ErrReadOnlyRepository),
+			errors.Is(err, graveler.ErrObjectLocked):
 			errs = append(errs, apigen.ObjectError{
 				Path:       swag.String(objectPath),
 				StatusCode: http.StatusForbidden,
@@ -525,7 +556,7 @@ func (c *Controller) DeleteObjects(w http.ResponseWriter, r *http.Request, body
 
 func (c *Controller) Login(w http.ResponseWriter, r *http.Request, body apigen.LoginJSONRequestBody) {
 	ctx := r.Context()
-	user, err := userByAuth(ctx, c.Logger, c.Authenticator, c.Auth, body.AccessKeyId, body.SecretAccessKey)
+	user, err := userByAuth(ctx, c.Logger, c.Authenticator, c.Auth, c.Lockout, body.AccessKeyId, body.SecretAccessKey, c.Lockout.SourceIP(r))
 	if errors.Is(err, ErrAuthenticatingRequest) {
 		writeResponse(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
 		return
@@ -536,11 +567,27 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request, body apigen.L
 	expires := loginTime.Add(duration)
 	secret := c.Auth.SecretStore().SharedSecret()
 
-	tokenString, err := GenerateJWTLogin(secret, user.Username, loginTime, expires)
+	sessionID := uuid.NewString()
+	tokenString, err := GenerateJWTLoginWithID(secret, sessionID, user.Username, loginTime, expires)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
+	if sessionManager, ok := auth.SessionManagerOf(ctx, c.Auth); ok {
+		err = sessionManager.CreateSession(ctx, &model.Session{
+			ID:         sessionID,
+			Username:   user.Username,
+			CreatedAt:  loginTime,
+			ExpiresAt:  expires,
+			LastUsedAt: loginTime,
+			SourceIP:   httputil.SourceHost(r),
+		})
+		if err != nil {
+			c.Logger.WithError(err).Error("Failed to create login session")
+			writeError(w, r, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+	}
 	internalAuthSession, _ := c.sessionStore.Get(r, InternalAuthSessionName)
 	internalAuthSession.Values[TokenSessionKeyName] = tokenString
 	err = c.sessionStore.Save(r, w, internalAuthSession)
@@ -577,6 +624,10 @@ func (c *Controller) GetPhysicalAddress(w http.ResponseWriter, r *http.Request,
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	err = c.Catalog.ValidateEntryKey(ctx, repository, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 	address := c.PathProvider.NewPath()
 	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, address, block.IdentifierTypeRelative)
 	if err != nil {
@@ -731,14 +782,24 @@ func (c *Controller) ListGroups(w http.ResponseWriter, r *http.Request, params a
 
 	ctx := r.Context()
 	c.LogAction(ctx, "list_groups", r, "", "", "")
-	groups, paginator, err := c.Auth.ListGroups(ctx, &model.PaginationParams{
-		After:  paginationAfter(params.After),
-		Prefix: paginationPrefix(params.Prefix),
-		Amount: paginationAmount(params.Amount),
-	})
+	prefix, visible, err := c.organizationListPrefix(ctx, paginationPrefix(params.Prefix))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	var (
+		groups    []*model.Group
+		paginator = &model.Paginator{}
+	)
+	if visible {
+		groups, paginator, err = c.Auth.ListGroups(ctx, &model.PaginationParams{
+			After:  paginationAfter(params.After),
+			Prefix: prefix,
+			Amount: paginationAmount(params.Amount),
+		})
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
 
 	response := apigen.GroupList{
 		Results: make([]apigen.Group, 0, len(groups)),
@@ -1026,6 +1087,9 @@ func (c *Controller) AddGroupMembership(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	ctx := r.Context()
+	if c.handleAPIError(ctx, w, r, c.checkSameOrganization(ctx, userID)) {
+		return
+	}
 	c.LogAction(ctx, "add_user_to_group", r, "", "", "")
 	err := c.Auth.AddUserToGroup(ctx, userID, groupID)
 	if c.handleAPIError(ctx, w, r, err) {
@@ -1078,9 +1142,10 @@ func serializePolicy(p *model.Policy) apigen.Policy {
 	stmts := make([]apigen.Statement, 0, len(p.Statement))
 	for _, s := range p.Statement {
 		stmts = append(stmts, apigen.Statement{
-			Action:   s.Action,
-			Effect:   s.Effect,
-			Resource: s.Resource,
+			Action:    s.Action,
+			Effect:    s.Effect,
+			Resource:  s.Resource,
+			Condition: serializeStatementCondition(s.Condition),
 		})
 	}
 	createdAt := p.CreatedAt.Unix()
@@ -1091,6 +1156,28 @@ func serializePolicy(p *model.Policy) apigen.Policy {
 	}
 }
 
+func serializeStatementCondition(condition model.Condition) *apigen.Statement_Condition {
+	if len(condition) == 0 {
+		return nil
+	}
+	res := &apigen.Statement_Condition{AdditionalProperties: make(map[string]apigen.StatementConditionKeys, len(condition))}
+	for operator, keys := range condition {
+		res.AdditionalProperties[operator] = apigen.StatementConditionKeys{AdditionalProperties: keys}
+	}
+	return res
+}
+
+func deserializeStatementCondition(condition *apigen.Statement_Condition) model.Condition {
+	if condition == nil || len(condition.AdditionalProperties) == 0 {
+		return nil
+	}
+	res := make(model.Condition, len(condition.AdditionalProperties))
+	for operator, keys := range condition.AdditionalProperties {
+		res[operator] = keys.AdditionalProperties
+	}
+	return res
+}
+
 func (c *Controller) DetachPolicyFromGroup(w http.ResponseWriter, r *http.Request, groupID, policyID string) {
 	if c.Config.IsAuthUISimplified() {
 		writeError(w, r, http.StatusNotImplemented, "Not implemented")
@@ -1152,14 +1239,24 @@ func (c *Controller) ListPolicies(w http.ResponseWriter, r *http.Request, params
 
 	ctx := r.Context()
 	c.LogAction(ctx, "list_policies", r, "", "", "")
-	policies, paginator, err := c.Auth.ListPolicies(ctx, &model.PaginationParams{
-		After:  paginationAfter(params.After),
-		Prefix: paginationPrefix(params.Prefix),
-		Amount: paginationAmount(params.Amount),
-	})
+	prefix, visible, err := c.organizationListPrefix(ctx, paginationPrefix(params.Prefix))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	var (
+		policies  []*model.Policy
+		paginator = &model.Paginator{}
+	)
+	if visible {
+		policies, paginator, err = c.Auth.ListPolicies(ctx, &model.PaginationParams{
+			After:  paginationAfter(params.After),
+			Prefix: prefix,
+			Amount: paginationAmount(params.Amount),
+		})
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
 
 	response := apigen.PolicyList{
 		Results: make([]apigen.Policy, 0, len(policies)),
@@ -1201,9 +1298,10 @@ func (c *Controller) CreatePolicy(w http.ResponseWriter, r *http.Request, body a
 	stmts := make(model.Statements, len(body.Statement))
 	for i, apiStatement := range body.Statement {
 		stmts[i] = model.Statement{
-			Effect:   apiStatement.Effect,
-			Action:   apiStatement.Action,
-			Resource: apiStatement.Resource,
+			Effect:    apiStatement.Effect,
+			Action:    apiStatement.Action,
+			Resource:  apiStatement.Resource,
+			Condition: deserializeStatementCondition(apiStatement.Condition),
 		}
 	}
 
@@ -1300,9 +1398,10 @@ func (c *Controller) UpdatePolicy(w http.ResponseWriter, r *http.Request, body a
 	stmts := make(model.Statements, len(body.Statement))
 	for i, apiStatement := range body.Statement {
 		stmts[i] = model.Statement{
-			Effect:   apiStatement.Effect,
-			Action:   apiStatement.Action,
-			Resource: apiStatement.Resource,
+			Effect:    apiStatement.Effect,
+			Action:    apiStatement.Action,
+			Resource:  apiStatement.Resource,
+			Condition: deserializeStatementCondition(apiStatement.Condition),
 		}
 	}
 
@@ -1330,14 +1429,24 @@ func (c *Controller) ListUsers(w http.ResponseWriter, r *http.Request, params ap
 	}
 	ctx := r.Context()
 	c.LogAction(ctx, "list_users", r, "", "", "")
-	users, paginator, err := c.Auth.ListUsers(ctx, &model.PaginationParams{
-		After:  paginationAfter(params.After),
-		Prefix: paginationPrefix(params.Prefix),
-		Amount: paginationAmount(params.Amount),
-	})
+	prefix, visible, err := c.organizationListPrefix(ctx, paginationPrefix(params.Prefix))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	var (
+		users     []*model.User
+		paginator = &model.Paginator{}
+	)
+	if visible {
+		users, paginator, err = c.Auth.ListUsers(ctx, &model.PaginationParams{
+			After:  paginationAfter(params.After),
+			Prefix: prefix,
+			Amount: paginationAmount(params.Amount),
+		})
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
 
 	response := apigen.UserList{
 		Results: make([]apigen.User, 0, len(users)),
@@ -1453,7 +1562,101 @@ func (c *Controller) GetUser(w http.ResponseWriter, r *http.Request, userID stri
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ListUserCredentials(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserCredentialsParams) {
+func (c *Controller) SimulateAuthorization(w http.ResponseWriter, r *http.Request, body apigen.SimulateAuthorizationJSONRequestBody, userID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SimulateAuthorizationAction,
+			Resource: permissions.UserArn(userID),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "simulate_authorization", r, "", "", "")
+	if err := permissions.IsValidAction(body.Action); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	_, err := c.Auth.GetUser(ctx, userID)
+	if errors.Is(err, auth.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	permission := permissions.Permission{
+		Action:   body.Action,
+		Resource: body.Resource,
+	}
+	if body.ConditionValues != nil {
+		conditionValues := body.ConditionValues.AdditionalProperties
+		permission.ConditionValues = func() (map[string]string, error) {
+			return conditionValues, nil
+		}
+	}
+	resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+		Username:            userID,
+		RequiredPermissions: permissions.Node{Permission: permission},
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.AuthorizationSimulationResult{
+		Allowed: resp.Allowed,
+	}
+	if !resp.Allowed && resp.Denial != nil {
+		response.Denial = &apigen.AuthorizationDenial{
+			Action:   resp.Denial.Action,
+			Reason:   string(resp.Denial.Reason),
+			Resource: resp.Denial.Resource,
+		}
+		if resp.Denial.Policy != "" {
+			response.Denial.Policy = apiutil.Ptr(resp.Denial.Policy)
+		}
+		if resp.Denial.Statement != auth.NoStatement {
+			response.Denial.Statement = apiutil.Ptr(resp.Denial.Statement)
+		}
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func serializeAccessToken(t *model.AccessToken) apigen.AccessToken {
+	token := apigen.AccessToken{
+		Id:             t.ID,
+		CreationDate:   t.CreatedAt.Unix(),
+		ExpirationDate: t.ExpiresAt.Unix(),
+		Scope:          apigen.AccessTokenScope{Actions: t.Actions},
+	}
+	if t.Description != "" {
+		token.Description = apiutil.Ptr(t.Description)
+	}
+	if len(t.Repositories) > 0 {
+		token.Scope.Repositories = apiutil.Ptr(t.Repositories)
+	}
+	if !t.LastUsedAt.IsZero() {
+		token.LastUsedDate = apiutil.Ptr(t.LastUsedAt.Unix())
+	}
+	return token
+}
+
+// accessTokenManager returns the auth service as an AccessTokenManager, or writes Not
+// Implemented if it does not support personal access tokens.
+func (c *Controller) accessTokenManager(w http.ResponseWriter, r *http.Request) (auth.AccessTokenManager, bool) {
+	tokens, ok := c.Auth.(auth.AccessTokenManager)
+	if !ok {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return nil, false
+	}
+	return tokens, true
+}
+
+func (c *Controller) ListUserAccessTokens(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserAccessTokensParams) {
+	tokens, ok := c.accessTokenManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ListCredentialsAction,
@@ -1463,8 +1666,8 @@ func (c *Controller) ListUserCredentials(w http.ResponseWriter, r *http.Request,
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_user_credentials", r, "", "", "")
-	credentials, paginator, err := c.Auth.ListUserCredentials(ctx, userID, &model.PaginationParams{
+	c.LogAction(ctx, "list_user_access_tokens", r, "", "", "")
+	accessTokens, paginator, err := tokens.ListAccessTokens(ctx, userID, &model.PaginationParams{
 		After:  paginationAfter(params.After),
 		Prefix: paginationPrefix(params.Prefix),
 		Amount: paginationAmount(params.Amount),
@@ -1473,24 +1676,25 @@ func (c *Controller) ListUserCredentials(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	response := apigen.CredentialsList{
-		Results: make([]apigen.Credentials, 0, len(credentials)),
+	response := apigen.AccessTokenList{
+		Results: make([]apigen.AccessToken, 0, len(accessTokens)),
 		Pagination: apigen.Pagination{
 			HasMore:    paginator.NextPageToken != "",
 			NextOffset: paginator.NextPageToken,
 			Results:    paginator.Amount,
 		},
 	}
-	for _, c := range credentials {
-		response.Results = append(response.Results, apigen.Credentials{
-			AccessKeyId:  c.AccessKeyID,
-			CreationDate: c.IssuedDate.Unix(),
-		})
+	for _, t := range accessTokens {
+		response.Results = append(response.Results, serializeAccessToken(t))
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateCredentials(w http.ResponseWriter, r *http.Request, userID string) {
+func (c *Controller) CreateUserAccessToken(w http.ResponseWriter, r *http.Request, body apigen.CreateUserAccessTokenJSONRequestBody, userID string) {
+	tokens, ok := c.accessTokenManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.CreateCredentialsAction,
@@ -1500,20 +1704,34 @@ func (c *Controller) CreateCredentials(w http.ResponseWriter, r *http.Request, u
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_credentials", r, "", "", "")
-	credentials, err := c.Auth.CreateCredentials(ctx, userID)
+	// a token creates tokens that grant no more than it, and expire no later
+	if callerToken, ok := auth.GetAccessToken(ctx); ok {
+		if !auth.AccessTokenScopeIncludes(callerToken, body.Scope.Actions, apiutil.Value(body.Scope.Repositories)) ||
+			time.Unix(body.ExpirationDate, 0).After(callerToken.ExpiresAt) {
+			writeError(w, r, http.StatusUnauthorized, auth.ErrInsufficientPermissions)
+			return
+		}
+	}
+	c.LogAction(ctx, "create_user_access_token", r, "", "", "")
+	accessToken, token, err := tokens.CreateAccessToken(ctx, userID,
+		swag.StringValue(body.Description),
+		body.Scope.Actions,
+		apiutil.Value(body.Scope.Repositories),
+		time.Unix(body.ExpirationDate, 0))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.CredentialsWithSecret{
-		AccessKeyId:     credentials.AccessKeyID,
-		SecretAccessKey: credentials.SecretAccessKey,
-		CreationDate:    credentials.IssuedDate.Unix(),
-	}
-	writeResponse(w, r, http.StatusCreated, response)
+	writeResponse(w, r, http.StatusCreated, apigen.AccessTokenWithSecret{
+		AccessToken: serializeAccessToken(accessToken),
+		Token:       token,
+	})
 }
 
-func (c *Controller) DeleteCredentials(w http.ResponseWriter, r *http.Request, userID, accessKeyID string) {
+func (c *Controller) DeleteUserAccessToken(w http.ResponseWriter, r *http.Request, userID, tokenID string) {
+	tokens, ok := c.accessTokenManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.DeleteCredentialsAction,
@@ -1522,1189 +1740,3021 @@ func (c *Controller) DeleteCredentials(w http.ResponseWriter, r *http.Request, u
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_credentials", r, "", "", "")
-	err := c.Auth.DeleteCredentials(ctx, userID, accessKeyID)
-	if errors.Is(err, auth.ErrNotFound) {
-		writeError(w, r, http.StatusNotFound, "credentials not found")
-		return
-	}
+	c.LogAction(ctx, "delete_user_access_token", r, "", "", "")
+	err := tokens.DeleteAccessToken(ctx, userID, tokenID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetCredentials(w http.ResponseWriter, r *http.Request, userID, accessKeyID string) {
+// sessionManager returns the auth service as a SessionManager, or writes Not Implemented if
+// it does not track sessions and credentials use.
+func (c *Controller) sessionManager(w http.ResponseWriter, r *http.Request) (auth.SessionManager, bool) {
+	manager, ok := c.Auth.(auth.SessionManager)
+	if !ok {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return nil, false
+	}
+	return manager, true
+}
+
+func (c *Controller) ListCredentialsActivity(w http.ResponseWriter, r *http.Request, params apigen.ListCredentialsActivityParams) {
+	manager, ok := c.sessionManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadCredentialsAction,
-			Resource: permissions.UserArn(userID),
+			Action:   permissions.ListCredentialsAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_credentials_for_user", r, "", "", "")
-	credentials, err := c.Auth.GetCredentialsForUser(ctx, userID, accessKeyID)
-	if errors.Is(err, auth.ErrNotFound) {
-		writeError(w, r, http.StatusNotFound, "credentials not found")
-		return
-	}
+	c.LogAction(ctx, "list_credentials_activity", r, "", "", "")
+	activities, paginator, err := manager.ListCredentialsActivity(ctx, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Amount: paginationAmount(params.Amount),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	response := apigen.Credentials{
-		AccessKeyId:  credentials.AccessKeyID,
-		CreationDate: credentials.IssuedDate.Unix(),
+	response := apigen.CredentialsActivityList{
+		Results: make([]apigen.CredentialsActivity, 0, len(activities)),
+		Pagination: apigen.Pagination{
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
+		},
+	}
+	for _, a := range activities {
+		activity := apigen.CredentialsActivity{
+			AccessKeyId:  a.AccessKeyID,
+			UserId:       a.Username,
+			CreationDate: a.IssuedDate.Unix(),
+		}
+		if !a.LastUsedAt.IsZero() {
+			activity.LastUsedDate = apiutil.Ptr(a.LastUsedAt.Unix())
+		}
+		if a.SourceIP != "" {
+			activity.LastUsedIp = apiutil.Ptr(a.SourceIP)
+		}
+		response.Results = append(response.Results, activity)
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ListUserGroups(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserGroupsParams) {
+func (c *Controller) ListCredentialsUsage(w http.ResponseWriter, r *http.Request, params apigen.ListCredentialsUsageParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadUserAction,
-			Resource: permissions.UserArn(userID),
+			Action:   permissions.ListCredentialsAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
+	if c.CredentialsUsage == nil {
+		writeError(w, r, http.StatusNotImplemented, "Credentials usage tracking is disabled")
+		return
+	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_user_groups", r, "", "", "")
-	groups, paginator, err := c.Auth.ListUserGroups(ctx, userID, &model.PaginationParams{
+	c.LogAction(ctx, "list_credentials_usage", r, "", "", "")
+	usages, paginator := c.CredentialsUsage.List(&model.PaginationParams{
 		After:  paginationAfter(params.After),
-		Prefix: paginationPrefix(params.Prefix),
 		Amount: paginationAmount(params.Amount),
 	})
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
 
-	response := apigen.GroupList{
-		Results: make([]apigen.Group, 0, len(groups)),
+	response := apigen.CredentialsUsageList{
+		Results: make([]apigen.CredentialsUsage, 0, len(usages)),
 		Pagination: apigen.Pagination{
 			HasMore:    paginator.NextPageToken != "",
 			NextOffset: paginator.NextPageToken,
 			Results:    paginator.Amount,
 		},
 	}
-	for _, g := range groups {
-		response.Results = append(response.Results, apigen.Group{
-			Id:           g.ID,
-			Name:         swag.String(g.DisplayName),
-			CreationDate: g.CreatedAt.Unix(),
+	for _, u := range usages {
+		response.Results = append(response.Results, apigen.CredentialsUsage{
+			AccessKeyId:   u.AccessKeyID,
+			UserId:        u.Username,
+			Requests:      u.Requests,
+			Errors:        u.Errors,
+			BytesReceived: u.BytesReceived,
+			BytesSent:     u.BytesSent,
+			LastUsedDate:  u.LastUsedAt.Unix(),
 		})
 	}
-
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ListUserPolicies(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserPoliciesParams) {
-	if c.Config.IsAuthUISimplified() {
-		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+func (c *Controller) RevokeCredentials(w http.ResponseWriter, r *http.Request, accessKeyID string) {
+	manager, ok := c.sessionManager(w, r)
+	if !ok {
 		return
 	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadUserAction,
-			Resource: permissions.UserArn(userID),
+			Action:   permissions.DeleteCredentialsAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "list_user_policies", r, "", "", "")
-	var listPolicies func(ctx context.Context, username string, params *model.PaginationParams) ([]*model.Policy, *model.Paginator, error)
-	if params.Effective != nil && *params.Effective {
-		listPolicies = c.Auth.ListEffectivePolicies
-	} else {
-		listPolicies = c.Auth.ListUserPolicies
+	c.LogAction(ctx, "revoke_credentials", r, "", "", "")
+	err := manager.RevokeCredentials(ctx, accessKeyID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	policies, paginator, err := listPolicies(ctx, userID, &model.PaginationParams{
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ListLoginSessions(w http.ResponseWriter, r *http.Request, params apigen.ListLoginSessionsParams) {
+	manager, ok := c.sessionManager(w, r)
+	if !ok {
+		return
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListSessionsAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_login_sessions", r, "", "", "")
+	loginSessions, paginator, err := manager.ListSessions(ctx, &model.PaginationParams{
 		After:  paginationAfter(params.After),
-		Prefix: paginationPrefix(params.Prefix),
 		Amount: paginationAmount(params.Amount),
 	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	response := apigen.PolicyList{
+	response := apigen.LoginSessionList{
+		Results: make([]apigen.LoginSession, 0, len(loginSessions)),
 		Pagination: apigen.Pagination{
 			HasMore:    paginator.NextPageToken != "",
 			NextOffset: paginator.NextPageToken,
 			Results:    paginator.Amount,
 		},
-		Results: make([]apigen.Policy, 0, len(policies)),
 	}
-	for _, p := range policies {
-		response.Results = append(response.Results, serializePolicy(p))
+	for _, s := range loginSessions {
+		session := apigen.LoginSession{
+			Id:             s.ID,
+			UserId:         s.Username,
+			CreationDate:   s.CreatedAt.Unix(),
+			ExpirationDate: s.ExpiresAt.Unix(),
+			LastUsedDate:   s.LastUsedAt.Unix(),
+		}
+		if s.SourceIP != "" {
+			session.LastUsedIp = apiutil.Ptr(s.SourceIP)
+		}
+		response.Results = append(response.Results, session)
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) DetachPolicyFromUser(w http.ResponseWriter, r *http.Request, userID, policyID string) {
-	if c.Config.IsAuthUISimplified() {
-		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+func (c *Controller) RevokeLoginSession(w http.ResponseWriter, r *http.Request, sessionID string) {
+	manager, ok := c.sessionManager(w, r)
+	if !ok {
 		return
 	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DetachPolicyAction,
-			Resource: permissions.UserArn(userID),
+			Action:   permissions.RevokeSessionAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "detach_policy_from_user", r, "", "", "")
-	err := c.Auth.DetachPolicyFromUser(ctx, policyID, userID)
+	c.LogAction(ctx, "revoke_login_session", r, "", "", "")
+	err := manager.RevokeSession(ctx, sessionID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) AttachPolicyToUser(w http.ResponseWriter, r *http.Request, userID, policyID string) {
-	if c.Config.IsAuthUISimplified() {
-		writeError(w, r, http.StatusNotImplemented, "Not implemented")
-		return
-	}
+func (c *Controller) ListUserCredentials(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserCredentialsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.AttachPolicyAction,
+			Action:   permissions.ListCredentialsAction,
 			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "attach_policy_to_user", r, "", "", "")
-	err := c.Auth.AttachPolicyToUser(ctx, policyID, userID)
+	c.LogAction(ctx, "list_user_credentials", r, "", "", "")
+	credentials, paginator, err := c.Auth.ListUserCredentials(ctx, userID, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Prefix: paginationPrefix(params.Prefix),
+		Amount: paginationAmount(params.Amount),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusCreated, nil)
-}
 
-func (c *Controller) GetConfig(w http.ResponseWriter, r *http.Request) {
-	_, err := auth.GetUser(r.Context())
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
-		return
+	response := apigen.CredentialsList{
+		Results: make([]apigen.Credentials, 0, len(credentials)),
+		Pagination: apigen.Pagination{
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
+		},
 	}
-	var storageCfg apigen.StorageConfig
-	internalError := false
-	if !c.authorizeCallback(w, r, permissions.Node{
+	for _, c := range credentials {
+		response.Results = append(response.Results, apigen.Credentials{
+			AccessKeyId:  c.AccessKeyID,
+			CreationDate: c.IssuedDate.Unix(),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) CreateCredentials(w http.ResponseWriter, r *http.Request, userID string) {
+	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadConfigAction,
-			Resource: permissions.All,
+			Action:   permissions.CreateCredentialsAction,
+			Resource: permissions.UserArn(userID),
 		},
-	}, func(_ http.ResponseWriter, _ *http.Request, code int, v interface{}) {
-		switch code {
-		case http.StatusInternalServerError:
-			writeError(w, r, code, v)
-			internalError = true
-		case http.StatusUnauthorized:
-			c.Logger.Debug("Unauthorized request to get storage config, returning partial config")
-		}
 	}) {
-		if internalError {
-			return
-		}
-	} else {
-		storageCfg = c.getStorageConfig()
+		return
 	}
-
-	versionConfig := c.getVersionConfig()
-	writeResponse(w, r, http.StatusOK, apigen.Config{StorageConfig: &storageCfg, VersionConfig: &versionConfig})
+	ctx := r.Context()
+	c.LogAction(ctx, "create_credentials", r, "", "", "")
+	credentials, err := c.Auth.CreateCredentials(ctx, userID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.CredentialsWithSecret{
+		AccessKeyId:     credentials.AccessKeyID,
+		SecretAccessKey: credentials.SecretAccessKey,
+		CreationDate:    credentials.IssuedDate.Unix(),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) DeleteCredentials(w http.ResponseWriter, r *http.Request, userID, accessKeyID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadConfigAction,
-			Resource: permissions.All,
+			Action:   permissions.DeleteCredentialsAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 
-	writeResponse(w, r, http.StatusOK, c.getStorageConfig())
-}
-
-func (c *Controller) getStorageConfig() apigen.StorageConfig {
-	info := c.BlockAdapter.GetStorageNamespaceInfo()
-	defaultNamespacePrefix := swag.String(info.DefaultNamespacePrefix)
-	if c.Config.Blockstore.DefaultNamespacePrefix != nil {
-		defaultNamespacePrefix = c.Config.Blockstore.DefaultNamespacePrefix
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_credentials", r, "", "", "")
+	err := c.Auth.DeleteCredentials(ctx, userID, accessKeyID)
+	if errors.Is(err, auth.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "credentials not found")
+		return
 	}
-	return apigen.StorageConfig{
-		BlockstoreType:                   c.Config.Blockstore.Type,
-		BlockstoreNamespaceValidityRegex: info.ValidityRegex,
-		BlockstoreNamespaceExample:       info.Example,
-		DefaultNamespacePrefix:           defaultNamespacePrefix,
-		PreSignSupport:                   info.PreSignSupport,
-		PreSignSupportUi:                 info.PreSignSupportUI,
-		ImportSupport:                    info.ImportSupport,
-		ImportValidityRegex:              info.ImportValidityRegex,
-		PreSignMultipartUpload:           swag.Bool(info.PreSignSupportMultipart),
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-}
-
-func (c *Controller) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) ListRepositories(w http.ResponseWriter, r *http.Request, params apigen.ListRepositoriesParams) {
+func (c *Controller) GetCredentials(w http.ResponseWriter, r *http.Request, userID, accessKeyID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListRepositoriesAction,
-			Resource: permissions.All,
+			Action:   permissions.ReadCredentialsAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_repos", r, "", "", "")
-
-	repos, hasMore, err := c.Catalog.ListRepositories(ctx, paginationAmount(params.Amount), paginationPrefix(params.Prefix), paginationAfter(params.After))
-	if c.handleAPIError(ctx, w, r, err) {
+	c.LogAction(ctx, "get_credentials_for_user", r, "", "", "")
+	credentials, err := c.Auth.GetCredentialsForUser(ctx, userID, accessKeyID)
+	if errors.Is(err, auth.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "credentials not found")
 		return
 	}
-	results := make([]apigen.Repository, 0, len(repos))
-	for _, repo := range repos {
-		creationDate := repo.CreationDate.Unix()
-		r := apigen.Repository{
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-			CreationDate:     creationDate,
-			DefaultBranch:    repo.DefaultBranch,
-			ReadOnly:         swag.Bool(repo.ReadOnly),
-		}
-		results = append(results, r)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	repositoryList := apigen.RepositoryList{
-		Pagination: paginationFor(hasMore, results, "Id"),
-		Results:    results,
+
+	response := apigen.Credentials{
+		AccessKeyId:  credentials.AccessKeyID,
+		CreationDate: credentials.IssuedDate.Unix(),
 	}
-	writeResponse(w, r, http.StatusOK, repositoryList)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, body apigen.CreateRepositoryJSONRequestBody, params apigen.CreateRepositoryParams) {
+func (c *Controller) ListUserGroups(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserGroupsParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateRepositoryAction,
-					Resource: permissions.RepoArn(body.Name),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.AttachStorageNamespaceAction,
-					Resource: permissions.StorageNamespace(body.StorageNamespace),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.ReadUserAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-
-	// Verify first if there is a repository definition.
-	// Return conflict if definition already exists, before
-	// creating the repository itself and ensuring (optional) storage namespace holds an object.
-	// Example will be by restoring a repository from a backup or previous bare repository.
-	_, err := c.Catalog.GetRepository(ctx, body.Name)
-	if err == nil {
-		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", graveler.ErrNotUnique))
+	c.LogAction(ctx, "list_user_groups", r, "", "", "")
+	groups, paginator, err := c.Auth.ListUserGroups(ctx, userID, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Prefix: paginationPrefix(params.Prefix),
+		Amount: paginationAmount(params.Amount),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	sampleData := swag.BoolValue(body.SampleData)
-	c.LogAction(ctx, "create_repo", r, body.Name, "", "")
-	if sampleData {
-		c.LogAction(ctx, "repo_sample_data", r, body.Name, "", "")
-	}
 
-	if err := c.validateStorageNamespace(body.StorageNamespace); err != nil {
-		writeError(w, r, http.StatusBadRequest, err)
-		return
+	response := apigen.GroupList{
+		Results: make([]apigen.Group, 0, len(groups)),
+		Pagination: apigen.Pagination{
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
+		},
 	}
-
-	defaultBranch := swag.StringValue(body.DefaultBranch)
-	if defaultBranch == "" {
-		defaultBranch = "main"
+	for _, g := range groups {
+		response.Results = append(response.Results, apigen.Group{
+			Id:           g.ID,
+			Name:         swag.String(g.DisplayName),
+			CreationDate: g.CreatedAt.Unix(),
+		})
 	}
 
-	if swag.BoolValue(params.Bare) {
-		// create a bare repository. This is useful in conjunction with refs-restore to create a copy
-		// of another repository by e.g. copying the _lakefs/ directory and restoring its refs
-		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, body.StorageNamespace, defaultBranch)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		response := apigen.Repository{
-			CreationDate:     repo.CreationDate.Unix(),
-			DefaultBranch:    repo.DefaultBranch,
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-		}
-		writeResponse(w, r, http.StatusCreated, response)
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListUserPolicies(w http.ResponseWriter, r *http.Request, userID string, params apigen.ListUserPoliciesParams) {
+	if c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
 		return
 	}
-
-	if err := c.ensureStorageNamespace(ctx, body.StorageNamespace); err != nil {
-		var (
-			reason string
-			retErr error
-			urlErr *url.Error
-		)
-		switch {
-		case errors.As(err, &urlErr) && urlErr.Op == "parse":
-			retErr = err
-			reason = "bad_url"
-		case errors.Is(err, block.ErrInvalidAddress):
-			retErr = fmt.Errorf("%w, must match: %s", err, c.BlockAdapter.BlockstoreType())
-			reason = "invalid_namespace"
-		case errors.Is(err, ErrStorageNamespaceInUse):
-			retErr = err
-			reason = "already_in_use"
-		default:
-			retErr = ErrFailedToAccessStorage
-			reason = "unknown"
-		}
-		c.Logger.
-			WithError(err).
-			WithField("storage_namespace", body.StorageNamespace).
-			WithField("reason", reason).
-			Warn("Could not access storage namespace")
-		writeError(w, r, http.StatusBadRequest, fmt.Errorf("failed to create repository: %w", retErr))
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadUserAction,
+			Resource: permissions.UserArn(userID),
+		},
+	}) {
 		return
 	}
 
-	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
-	if err != nil {
-		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", err))
+	ctx := r.Context()
+	c.LogAction(ctx, "list_user_policies", r, "", "", "")
+	var listPolicies func(ctx context.Context, username string, params *model.PaginationParams) ([]*model.Policy, *model.Paginator, error)
+	if params.Effective != nil && *params.Effective {
+		listPolicies = c.Auth.ListEffectivePolicies
+	} else {
+		listPolicies = c.Auth.ListUserPolicies
+	}
+	policies, paginator, err := listPolicies(ctx, userID, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Prefix: paginationPrefix(params.Prefix),
+		Amount: paginationAmount(params.Amount),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	if sampleData {
-		// add sample data, hooks, etc.
-		user, err := auth.GetUser(ctx)
-		if err != nil {
-			writeError(w, r, http.StatusUnauthorized, "missing user")
-			return
-		}
-
-		err = samplerepo.PopulateSampleRepo(ctx, newRepo, c.Catalog, c.PathProvider, c.BlockAdapter, user)
-		if err != nil {
-			c.handleAPIError(ctx, w, r, fmt.Errorf("error populating sample repository: %w", err))
-			return
-		}
-
-		err = samplerepo.AddBranchProtection(ctx, newRepo, c.Catalog)
-		if err != nil {
-			c.handleAPIError(ctx, w, r, fmt.Errorf("error adding branch protection to sample repository: %w", err))
-			return
-		}
-	}
-
-	response := apigen.Repository{
-		CreationDate:     newRepo.CreationDate.Unix(),
-		DefaultBranch:    newRepo.DefaultBranch,
-		Id:               newRepo.Name,
-		StorageNamespace: newRepo.StorageNamespace,
-		ReadOnly:         swag.Bool(newRepo.ReadOnly),
-	}
-	writeResponse(w, r, http.StatusCreated, response)
-}
-
-func (c *Controller) validateStorageNamespace(storageNamespace string) error {
-	validRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
-	storagePrefixRegex, err := regexp.Compile(validRegex)
-	if err != nil {
-		return fmt.Errorf("failed to compile validity regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	response := apigen.PolicyList{
+		Pagination: apigen.Pagination{
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
+		},
+		Results: make([]apigen.Policy, 0, len(policies)),
 	}
-	if !storagePrefixRegex.MatchString(storageNamespace) {
-		return fmt.Errorf("failed to match required regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	for _, p := range policies {
+		response.Results = append(response.Results, serializePolicy(p))
 	}
-	return nil
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespace string) error {
-	const (
-		dummyData    = "this is dummy data - created by lakeFS to check accessibility"
-		dummyObjName = "dummy"
-	)
-	dummyKey := fmt.Sprintf("%s/%s", c.Config.Committed.BlockStoragePrefix, dummyObjName)
-
-	objLen := int64(len(dummyData))
-
-	// check if the dummy file exist in the root of the storage namespace
-	// this serves two purposes, first, we maintain safety check for older lakeFS version.
-	// second, in scenarios where lakeFS shouldn't have access to the root namespace (i.e pre-sign URL only).
-	if c.Config.Graveler.EnsureReadableRootNamespace {
-		rootObj := block.ObjectPointer{
-			StorageNamespace: storageNamespace,
-			IdentifierType:   block.IdentifierTypeRelative,
-			Identifier:       dummyObjName,
-		}
-
-		if s, err := c.BlockAdapter.Get(ctx, rootObj, objLen); err == nil {
-			_ = s.Close()
-			return fmt.Errorf("found lakeFS objects in the storage namespace root(%s): %w",
-				storageNamespace, ErrStorageNamespaceInUse)
-		} else if !errors.Is(err, block.ErrDataNotFound) {
-			return err
-		}
-	}
-
-	// check if the dummy file exists
-	obj := block.ObjectPointer{
-		StorageNamespace: storageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       dummyKey,
-	}
-
-	if s, err := c.BlockAdapter.Get(ctx, obj, objLen); err == nil {
-		_ = s.Close()
-		return fmt.Errorf("found lakeFS objects in the storage namespace(%s) key(%s): %w",
-			storageNamespace, obj.Identifier, ErrStorageNamespaceInUse)
-	} else if !errors.Is(err, block.ErrDataNotFound) {
-		return err
-	}
-
-	if err := c.BlockAdapter.Put(ctx, obj, objLen, strings.NewReader(dummyData), block.PutOpts{}); err != nil {
-		return err
+func (c *Controller) DetachPolicyFromUser(w http.ResponseWriter, r *http.Request, userID, policyID string) {
+	if c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return
 	}
-
-	_, err := c.BlockAdapter.Get(ctx, obj, objLen)
-	return err
-}
-
-func (c *Controller) DeleteRepository(w http.ResponseWriter, r *http.Request, repository string, params apigen.DeleteRepositoryParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DetachPolicyAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_repo", r, repository, "", "")
-	err := c.Catalog.DeleteRepository(ctx, repository, graveler.WithForce(swag.BoolValue(params.Force)))
+	c.LogAction(ctx, "detach_policy_from_user", r, "", "", "")
+	err := c.Auth.DetachPolicyFromUser(ctx, policyID, userID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetRepository(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) AttachPolicyToUser(w http.ResponseWriter, r *http.Request, userID, policyID string) {
+	if c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.AttachPolicyAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "get_repo", r, repository, "", "")
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	switch {
-	case err == nil:
-		response := apigen.Repository{
-			CreationDate:     repo.CreationDate.Unix(),
-			DefaultBranch:    repo.DefaultBranch,
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-			ReadOnly:         swag.Bool(repo.ReadOnly),
-		}
-		writeResponse(w, r, http.StatusOK, response)
-
-	case errors.Is(err, graveler.ErrNotFound):
-		writeError(w, r, http.StatusNotFound, "repository not found")
-
-	case errors.Is(err, graveler.ErrRepositoryInDeletion):
-		writeError(w, r, http.StatusGone, err)
 
-	default:
-		writeError(w, r, http.StatusInternalServerError, err)
+	ctx := r.Context()
+	c.LogAction(ctx, "attach_policy_to_user", r, "", "", "")
+	err := c.Auth.AttachPolicyToUser(ctx, policyID, userID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	writeResponse(w, r, http.StatusCreated, nil)
 }
 
-func (c *Controller) GetRepositoryMetadata(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) GetUserPermissionBoundary(w http.ResponseWriter, r *http.Request, userID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadUserAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_repo_metadata", r, repository, "", "")
-	metadata, err := c.Catalog.GetRepositoryMetadata(ctx, repository)
+	c.LogAction(ctx, "get_user_permission_boundary", r, "", "", "")
+	p, err := boundaries.GetUserPermissionBoundary(ctx, userID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusOK, apigen.RepositoryMetadata{AdditionalProperties: metadata})
+	writeResponse(w, r, http.StatusOK, serializePolicy(p))
 }
 
-func (c *Controller) SetRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.SetRepositoryMetadataJSONRequestBody, repository string) {
+func (c *Controller) SetUserPermissionBoundary(w http.ResponseWriter, r *http.Request, userID, policyID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.UpdateRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.AttachPolicyAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "update_repo_metadata", r, repository, "", "")
-	err := c.Catalog.UpdateRepositoryMetadata(ctx, repository, body.Metadata.AdditionalProperties)
+	c.LogAction(ctx, "set_user_permission_boundary", r, "", "", "")
+	err := boundaries.SetUserPermissionBoundary(ctx, userID, policyID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusCreated, nil)
 }
 
-func (c *Controller) DeleteRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.DeleteRepositoryMetadataJSONRequestBody, repository string) {
+func (c *Controller) DeleteUserPermissionBoundary(w http.ResponseWriter, r *http.Request, userID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.UpdateRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DetachPolicyAction,
+			Resource: permissions.UserArn(userID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_repo_metadata", r, repository, "", "")
-	err := c.Catalog.DeleteRepositoryMetadata(ctx, repository, body.Keys)
+	c.LogAction(ctx, "delete_user_permission_boundary", r, "", "", "")
+	err := boundaries.DeleteUserPermissionBoundary(ctx, userID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) GetGroupPermissionBoundary(w http.ResponseWriter, r *http.Request, groupID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.GetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadGroupAction,
+			Resource: permissions.GroupArn(groupID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	rules, eTag, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	c.LogAction(ctx, "get_group_permission_boundary", r, "", "", "")
+	p, err := boundaries.GetGroupPermissionBoundary(ctx, groupID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	resp := make([]*apigen.BranchProtectionRule, 0, len(rules.BranchPatternToBlockedActions))
-	for pattern := range rules.BranchPatternToBlockedActions {
-		resp = append(resp, &apigen.BranchProtectionRule{
-			Pattern: pattern,
-		})
-	}
-	w.Header().Set("ETag", swag.StringValue(eTag))
-	writeResponse(w, r, http.StatusOK, resp)
+	writeResponse(w, r, http.StatusOK, serializePolicy(p))
 }
 
-func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Request, body apigen.SetBranchProtectionRulesJSONRequestBody, repository string, params apigen.SetBranchProtectionRulesParams) {
+func (c *Controller) SetGroupPermissionBoundary(w http.ResponseWriter, r *http.Request, groupID, policyID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.AttachPolicyAction,
+			Resource: permissions.GroupArn(groupID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
-
-	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
-	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
-
-	rules := &graveler.BranchProtectionRules{
-		BranchPatternToBlockedActions: make(map[string]*graveler.BranchProtectionBlockedActions),
-	}
-	for _, r := range body {
-		rules.BranchPatternToBlockedActions[r.Pattern] = &graveler.BranchProtectionBlockedActions{
-			Value: blockedActions,
-		}
-	}
-	err := c.Catalog.SetBranchProtectionRules(ctx, repository, rules, params.IfMatch)
+	c.LogAction(ctx, "set_group_permission_boundary", r, "", "", "")
+	err := boundaries.SetGroupPermissionBoundary(ctx, groupID, policyID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusCreated, nil)
 }
 
-func (c *Controller) DeleteGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) DeleteGroupPermissionBoundary(w http.ResponseWriter, r *http.Request, groupID string) {
+	boundaries, ok := c.permissionBoundaryManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DetachPolicyAction,
+			Resource: permissions.GroupArn(groupID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, nil)
+	c.LogAction(ctx, "delete_group_permission_boundary", r, "", "", "")
+	err := boundaries.DeleteGroupPermissionBoundary(ctx, groupID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+// permissionBoundaryManager returns the auth service as a PermissionBoundaryManager, or writes
+// Not Implemented if it does not support permission boundaries.
+func (c *Controller) permissionBoundaryManager(w http.ResponseWriter, r *http.Request) (auth.PermissionBoundaryManager, bool) {
+	boundaries, ok := c.Auth.(auth.PermissionBoundaryManager)
+	if !ok || c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return nil, false
+	}
+	return boundaries, true
+}
+
+func (c *Controller) ListOrganizations(w http.ResponseWriter, r *http.Request, params apigen.ListOrganizationsParams) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.GetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ListOrganizationsAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	rules, err := c.Catalog.GetGarbageCollectionRules(ctx, repository)
+	c.LogAction(ctx, "list_organizations", r, "", "", "")
+	orgs, paginator, err := organizations.ListOrganizations(ctx, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Prefix: paginationPrefix(params.Prefix),
+		Amount: paginationAmount(params.Amount),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	resp := apigen.GarbageCollectionRules{}
-	resp.DefaultRetentionDays = int(rules.DefaultRetentionDays)
-	for branchID, retentionDays := range rules.BranchRetentionDays {
-		resp.Branches = append(resp.Branches, apigen.GarbageCollectionRule{BranchId: branchID, RetentionDays: int(retentionDays)})
+
+	response := apigen.OrganizationList{
+		Results: make([]apigen.Organization, 0, len(orgs)),
+		Pagination: apigen.Pagination{
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
+		},
 	}
-	writeResponse(w, r, http.StatusOK, resp)
+	for _, o := range orgs {
+		response.Results = append(response.Results, serializeOrganization(o))
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) SetGCRules(w http.ResponseWriter, r *http.Request, body apigen.SetGCRulesJSONRequestBody, repository string) {
-	if !c.authorize(w, r, permissions.Node{
+func (c *Controller) CreateOrganization(w http.ResponseWriter, r *http.Request, body apigen.CreateOrganizationJSONRequestBody) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
+		return
+	}
+	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.CreateOrganizationAction,
+			Resource: permissions.OrganizationArn(body.Id),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	rules := &graveler.GarbageCollectionRules{
-		DefaultRetentionDays: int32(body.DefaultRetentionDays),
-		BranchRetentionDays:  make(map[string]int32),
-	}
-	for _, rule := range body.Branches {
-		rules.BranchRetentionDays[rule.BranchId] = int32(rule.RetentionDays)
-	}
-	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, rules)
+	c.LogAction(ctx, "create_organization", r, "", "", "")
+	organization, err := organizations.CreateOrganization(ctx, &model.Organization{
+		ID:               body.Id,
+		Description:      swag.StringValue(body.Description),
+		StorageNamespace: swag.StringValue(body.StorageNamespace),
+		MaxRepositories:  swag.IntValue(body.MaxRepositories),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusCreated, serializeOrganization(organization))
 }
 
-func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+func (c *Controller) GetOrganization(w http.ResponseWriter, r *http.Request, organizationID string) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadOrganizationAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
-
-	_, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "get_organization", r, "", "", "")
+	organization, err := organizations.GetOrganization(ctx, organizationID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, serializeOrganization(organization))
+}
 
-	branchName := swag.StringValue(params.Branch)
-	commitID := swag.StringValue(params.Commit)
-	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) UpdateOrganization(w http.ResponseWriter, r *http.Request, body apigen.UpdateOrganizationJSONRequestBody, organizationID string) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
 		return
 	}
-	defer runsIter.Close()
-
-	response := apigen.ActionRunList{
-		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateOrganizationAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
-		Results: make([]apigen.ActionRun, 0),
-	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && runsIter.Next() {
-		val := runsIter.Value()
-		response.Results = append(response.Results, runResultToActionRun(val))
-	}
-	response.Pagination.Results = len(response.Results)
-	if runsIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastRun.RunId
-		}
+	}) {
+		return
 	}
-	if err := runsIter.Err(); err != nil {
-		writeResponse(w, r, http.StatusInternalServerError, err)
+	ctx := r.Context()
+	c.LogAction(ctx, "update_organization", r, "", "", "")
+	organization, err := organizations.UpdateOrganization(ctx, &model.Organization{
+		ID:               organizationID,
+		Description:      swag.StringValue(body.Description),
+		StorageNamespace: swag.StringValue(body.StorageNamespace),
+		MaxRepositories:  swag.IntValue(body.MaxRepositories),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusOK, serializeOrganization(organization))
 }
 
-func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
-	runResult := apigen.ActionRun{
-		Branch:    val.BranchID,
-		CommitId:  val.CommitID,
-		RunId:     val.RunID,
-		StartTime: val.StartTime,
-		EndTime:   &val.EndTime,
-		EventType: val.EventType,
-	}
-	if val.Passed {
-		runResult.Status = actionStatusCompleted
-	} else {
-		runResult.Status = actionStatusFailed
+func (c *Controller) DeleteOrganization(w http.ResponseWriter, r *http.Request, organizationID string) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
+		return
 	}
-	return runResult
-}
-
-func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DeleteOrganizationAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
-	_, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "delete_organization", r, "", "", "")
+	err := organizations.DeleteOrganization(ctx, organizationID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) ListOrganizationMembers(w http.ResponseWriter, r *http.Request, organizationID string, params apigen.ListOrganizationMembersParams) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
 		return
 	}
-
-	var status string
-	if runResult.Passed {
-		status = actionStatusCompleted
-	} else {
-		status = actionStatusFailed
-	}
-	response := apigen.ActionRun{
-		RunId:     runResult.RunID,
-		EventType: runResult.EventType,
-		StartTime: runResult.StartTime,
-		EndTime:   &runResult.EndTime,
-		Status:    status,
-		Branch:    runResult.BranchID,
-		CommitId:  runResult.CommitID,
-	}
-	writeResponse(w, r, http.StatusOK, response)
-}
-
-func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadOrganizationAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-
-	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
+	c.LogAction(ctx, "list_organization_users", r, "", "", "")
+	users, paginator, err := organizations.ListOrganizationMembers(ctx, organizationID, &model.PaginationParams{
+		After:  paginationAfter(params.After),
+		Prefix: paginationPrefix(params.Prefix),
+		Amount: paginationAmount(params.Amount),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer tasksIter.Close()
 
-	response := apigen.HookRunList{
-		Results: make([]apigen.HookRun, 0),
+	response := apigen.UserList{
+		Results: make([]apigen.User, 0, len(users)),
 		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
+			HasMore:    paginator.NextPageToken != "",
+			NextOffset: paginator.NextPageToken,
+			Results:    paginator.Amount,
 		},
 	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && tasksIter.Next() {
-		val := tasksIter.Value()
-		hookRun := apigen.HookRun{
-			HookRunId: val.HookRunID,
-			Action:    val.ActionName,
-			HookId:    val.HookID,
-			StartTime: val.StartTime,
-			EndTime:   &val.EndTime,
-		}
-		switch {
-		case val.Passed:
-			hookRun.Status = actionStatusCompleted
-		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
-			hookRun.Status = actionStatusSkipped
-			hookRun.EndTime = nil
-		default:
-			hookRun.Status = actionStatusFailed
-		}
-		response.Results = append(response.Results, hookRun)
-	}
-	if tasksIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastHookRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastHookRun.HookRunId
-		}
-	}
-	if err := tasksIter.Err(); err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
-		return
+	for _, u := range users {
+		response.Results = append(response.Results, apigen.User{
+			Id:           u.Username,
+			Email:        u.Email,
+			CreationDate: u.CreatedAt.Unix(),
+		})
 	}
-	response.Pagination.Results = len(response.Results)
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
+func (c *Controller) AddOrganizationMember(w http.ResponseWriter, r *http.Request, organizationID, userID string) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
+		return
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.AddOrganizationMemberAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-
-	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
+	c.LogAction(ctx, "add_user_to_organization", r, "", "", "")
+	err := organizations.AddOrganizationMember(ctx, organizationID, userID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusCreated, nil)
+}
 
-	if taskResult.StartTime.IsZero() { // skipped task
-		writeResponse(w, r, http.StatusOK, nil)
-		return
-	}
-
-	logPath := taskResult.LogPath()
-	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       logPath,
-	}, -1)
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) RemoveOrganizationMember(w http.ResponseWriter, r *http.Request, organizationID, userID string) {
+	organizations, ok := c.organizationManager(w, r)
+	if !ok {
 		return
 	}
-	defer func() {
-		_ = reader.Close()
-	}()
-
-	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
-	w.Header().Set("Content-Disposition", cd)
-	w.Header().Set("Content-Type", "application/octet-stream")
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
-	}
-}
-
-func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListBranchesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.RemoveOrganizationMemberAction,
+			Resource: permissions.OrganizationArn(organizationID),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_branches", r, repository, "", "")
-
-	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	c.LogAction(ctx, "remove_user_from_organization", r, "", "", "")
+	err := organizations.RemoveOrganizationMember(ctx, organizationID, userID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	refs := make([]apigen.Ref, 0, len(res))
-	for _, branch := range res {
-		refs = append(refs, apigen.Ref{
-			CommitId: branch.Reference,
-			Id:       branch.Name,
-		})
+func serializeOrganization(o *model.Organization) apigen.Organization {
+	return apigen.Organization{
+		Id:               o.ID,
+		CreationDate:     o.CreatedAt.Unix(),
+		Description:      swag.String(o.Description),
+		StorageNamespace: swag.String(o.StorageNamespace),
+		MaxRepositories:  swag.Int(o.MaxRepositories),
 	}
-	response := apigen.RefList{
-		Results:    refs,
-		Pagination: paginationFor(hasMore, refs, "Id"),
+}
+
+func (c *Controller) organizationManager(w http.ResponseWriter, r *http.Request) (auth.OrganizationManager, bool) {
+	organizations, ok := c.Auth.(auth.OrganizationManager)
+	if !ok || c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return nil, false
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	return organizations, true
 }
 
-func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.CreateBranchAction,
-			Resource: permissions.BranchArn(repository, body.Name),
-		},
-	}) {
-		return
+// userOrganization returns the organization of the user of the request, nil if it is not a
+// member of any organization.  It returns ErrNotImplemented if the auth service does not manage
+// organizations, so that requests are denied rather than escape the isolation of organizations.
+func (c *Controller) userOrganization(ctx context.Context) (*model.Organization, error) {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return nil, err
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
+	return c.getUserOrganization(ctx, user.Username)
+}
 
-	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+// getUserOrganization returns the organization username is a member of, nil if it is not a
+// member of any organization, and ErrNotImplemented if the auth service does not manage
+// organizations
+func (c *Controller) getUserOrganization(ctx context.Context, username string) (*model.Organization, error) {
+	organizations, ok := c.Auth.(auth.OrganizationManager)
+	if !ok {
+		return nil, auth.ErrNotImplemented
 	}
-	w.WriteHeader(http.StatusCreated)
-	_, _ = io.WriteString(w, commitLog.Reference)
+	organization, err := organizations.GetUserOrganization(ctx, username)
+	if errors.Is(err, auth.ErrNotFound) {
+		return nil, nil
+	}
+	return organization, err
 }
 
-func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.DeleteBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
-		},
-	}) {
-		return
+// organizationListPrefix returns the prefix to list the entities matching prefix that the user
+// of the request may see: members of an organization only see the entities of its namespace.
+// It returns false if none of them matches prefix.
+func (c *Controller) organizationListPrefix(ctx context.Context, prefix string) (string, bool, error) {
+	organization, err := c.userOrganization(ctx)
+	if err != nil || organization == nil {
+		return prefix, err == nil, err
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
+	restricted, ok := auth.RestrictToOrganization(organization, prefix)
+	return restricted, ok, nil
+}
 
-	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+// checkSameOrganization returns ErrOrganizationMismatch if the user of the request is a member
+// of an organization and username is not: members may not grant the entities of their
+// organization to other users
+func (c *Controller) checkSameOrganization(ctx context.Context, username string) error {
+	organization, err := c.userOrganization(ctx)
+	if err != nil || organization == nil {
+		return err
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	userOrganization, err := c.getUserOrganization(ctx, username)
+	if err != nil {
+		return err
+	}
+	if userOrganization == nil || userOrganization.ID != organization.ID {
+		return fmt.Errorf("%s: %w", username, auth.ErrOrganizationMismatch)
+	}
+	return nil
 }
 
-func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
-		},
-	}) {
-		return
+// checkRepositoryQuota returns ErrQuotaExceeded if the organization of the namespace of
+// repositoryID has its maximal number of repositories, and ErrNotImplemented if the auth service
+// cannot tell
+func (c *Controller) checkRepositoryQuota(ctx context.Context, repositoryID string) error {
+	organizationID := model.OrganizationIDOfName(repositoryID)
+	if organizationID == "" {
+		return nil
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "get_branch", r, repository, branch, "")
-	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	organizations, ok := c.Auth.(auth.OrganizationManager)
+	if !ok {
+		return auth.ErrNotImplemented
 	}
-	response := apigen.Ref{
-		CommitId: reference,
-		Id:       branch,
+	organization, err := organizations.GetOrganization(ctx, organizationID)
+	if errors.Is(err, auth.ErrNotFound) {
+		return nil
 	}
-	writeResponse(w, r, http.StatusOK, response)
-}
-
-func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, err error, cb func(w http.ResponseWriter, r *http.Request, code int, v interface{})) bool {
-	// verify if request canceled even if there is no error, early exit point
-	if httputil.IsRequestCanceled(r) {
-		cb(w, r, httpStatusClientClosedRequest, httpStatusClientClosedRequestText)
-		return true
+	if err != nil || organization.MaxRepositories == 0 {
+		return err
 	}
-	if err == nil {
-		return false
+	count := 0
+	after := ""
+	for {
+		repos, hasMore, err := c.Catalog.ListRepositories(ctx, -1, model.OrganizationNamePrefix(organizationID), after)
+		if err != nil {
+			return err
+		}
+		count += len(repos)
+		if count >= organization.MaxRepositories {
+			return fmt.Errorf("organization %s has %d repositories: %w", organizationID, organization.MaxRepositories, auth.ErrQuotaExceeded)
+		}
+		if !hasMore {
+			return nil
+		}
+		after = repos[len(repos)-1].Name
 	}
+}
 
-	log := c.Logger.WithContext(ctx).WithError(err)
-
-	// Handle Hook Errors
-	var hookAbortErr *graveler.HookAbortError
-	if errors.As(err, &hookAbortErr) {
-		log.WithField("run_id", hookAbortErr.RunID).Warn("aborted by hooks")
-		cb(w, r, http.StatusPreconditionFailed, err)
-		return true
+func (c *Controller) GetConfig(w http.ResponseWriter, r *http.Request) {
+	_, err := auth.GetUser(r.Context())
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
+		return
 	}
-
-	// order of case is important, more specific errors should be first
-	switch {
-	case errors.Is(err, graveler.ErrLinkAddressNotFound),
-		errors.Is(err, graveler.ErrLinkAddressExpired):
-		log.Debug("Expired or invalid address token")
-		cb(w, r, http.StatusBadRequest, "bad address token (expired or invalid)")
-
-	case errors.Is(err, graveler.ErrNotFound),
-		errors.Is(err, actions.ErrNotFound),
-		errors.Is(err, auth.ErrNotFound),
-		errors.Is(err, kv.ErrNotFound):
-		log.Debug("Not found")
-		cb(w, r, http.StatusNotFound, err)
-
-	case errors.Is(err, block.ErrForbidden),
-		errors.Is(err, graveler.ErrProtectedBranch),
-		errors.Is(err, graveler.ErrReadOnlyRepository):
-		cb(w, r, http.StatusForbidden, err)
-
-	case errors.Is(err, graveler.ErrDirtyBranch),
-		errors.Is(err, graveler.ErrCommitMetaRangeDirtyBranch),
-		errors.Is(err, graveler.ErrInvalidValue),
-		errors.Is(err, validator.ErrInvalidValue),
-		errors.Is(err, catalog.ErrPathRequiredValue),
-		errors.Is(err, graveler.ErrNoChanges),
-		errors.Is(err, permissions.ErrInvalidServiceName),
-		errors.Is(err, permissions.ErrInvalidAction),
-		errors.Is(err, model.ErrValidationError),
-		errors.Is(err, graveler.ErrInvalidRef),
-		errors.Is(err, actions.ErrParamConflict),
-		errors.Is(err, graveler.ErrDereferenceCommitWithStaging),
-		errors.Is(err, graveler.ErrParentOutOfRange),
-		errors.Is(err, graveler.ErrCherryPickMergeNoParent),
-		errors.Is(err, graveler.ErrInvalidMergeStrategy),
-		errors.Is(err, block.ErrInvalidAddress),
-		errors.Is(err, block.ErrOperationNotSupported):
-		log.Debug("Bad request")
-		cb(w, r, http.StatusBadRequest, err)
-
-	case errors.Is(err, graveler.ErrNotUnique),
-		errors.Is(err, graveler.ErrConflictFound),
-		errors.Is(err, graveler.ErrRevertMergeNoParent):
-		log.Debug("Conflict")
-		cb(w, r, http.StatusConflict, err)
-
-	case errors.Is(err, graveler.ErrLockNotAcquired):
-		log.Debug("Lock not acquired")
-		cb(w, r, http.StatusInternalServerError, "branch is currently locked, try again later")
-
-	case errors.Is(err, block.ErrDataNotFound):
-		log.Debug("No data")
-		cb(w, r, http.StatusGone, "No data")
-
-	case errors.Is(err, auth.ErrAlreadyExists):
-		log.Debug("Already exists")
-		cb(w, r, http.StatusConflict, "Already exists")
-
-	case errors.Is(err, graveler.ErrTooManyTries):
-		log.Debug("Retried too many times")
-		cb(w, r, http.StatusLocked, "Too many attempts, try again later")
-	case errors.Is(err, graveler.ErrPreconditionFailed):
-		log.Debug("Precondition failed")
-		cb(w, r, http.StatusPreconditionFailed, "Precondition failed")
-	case err != nil:
-		c.Logger.WithContext(ctx).WithError(err).Error("API call returned status internal server error")
-		cb(w, r, http.StatusInternalServerError, err)
-
-	default:
-		return false
+	var storageCfg apigen.StorageConfig
+	internalError := false
+	if !c.authorizeCallback(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
+		},
+	}, func(_ http.ResponseWriter, _ *http.Request, code int, v interface{}) {
+		switch code {
+		case http.StatusInternalServerError:
+			writeError(w, r, code, v)
+			internalError = true
+		case http.StatusUnauthorized:
+			c.Logger.Debug("Unauthorized request to get storage config, returning partial config")
+		}
+	}) {
+		if internalError {
+			return
+		}
+	} else {
+		storageCfg = c.getStorageConfig()
 	}
 
-	return true
-}
-
-func (c *Controller) handleAPIError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) bool {
-	return c.handleAPIErrorCallback(ctx, w, r, err, writeError)
+	versionConfig := c.getVersionConfig()
+	writeResponse(w, r, http.StatusOK, apigen.Config{StorageConfig: &storageCfg, VersionConfig: &versionConfig})
 }
 
-func (c *Controller) ResetBranch(w http.ResponseWriter, r *http.Request, body apigen.ResetBranchJSONRequestBody, repository, branch string) {
+func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
-	ctx := r.Context()
-
-	c.LogAction(ctx, "reset_branch", r, repository, branch, "")
 
-	var err error
+	writeResponse(w, r, http.StatusOK, c.getStorageConfig())
+}
 
-	switch body.Type {
-	case entryTypeCommonPrefix:
-		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
-	case "reset":
-		err = c.Catalog.ResetBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
-	case entryTypeObject:
-		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
-	default:
-		writeError(w, r, http.StatusBadRequest, "unknown reset type")
-		return
+func (c *Controller) getStorageConfig() apigen.StorageConfig {
+	info := c.BlockAdapter.GetStorageNamespaceInfo()
+	defaultNamespacePrefix := swag.String(info.DefaultNamespacePrefix)
+	if c.Config.Blockstore.DefaultNamespacePrefix != nil {
+		defaultNamespacePrefix = c.Config.Blockstore.DefaultNamespacePrefix
 	}
-
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	return apigen.StorageConfig{
+		BlockstoreType:                   c.Config.Blockstore.Type,
+		BlockstoreNamespaceValidityRegex: info.ValidityRegex,
+		BlockstoreNamespaceExample:       info.Example,
+		DefaultNamespacePrefix:           defaultNamespacePrefix,
+		PreSignSupport:                   info.PreSignSupport,
+		PreSignSupportUi:                 info.PreSignSupportUI,
+		ImportSupport:                    info.ImportSupport,
+		ImportValidityRegex:              info.ImportValidityRegex,
+		PreSignMultipartUpload:           swag.Bool(info.PreSignSupportMultipart),
 	}
+}
+
+func (c *Controller) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) HardResetBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.HardResetBranchParams) {
+func (c *Controller) ListRepositories(w http.ResponseWriter, r *http.Request, params apigen.ListRepositoriesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			// TODO(ozkatz): Can we have another action here?
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.ListRepositoriesAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
+	c.LogAction(ctx, "list_repos", r, "", "", "")
 
-	c.LogAction(ctx, "hard_reset_branch", r, repository, branch, "")
-
-	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref, graveler.WithForce(swag.BoolValue(params.Force)))
+	prefix, visible, err := c.organizationListPrefix(ctx, paginationPrefix(params.Prefix))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	var (
+		repos   []*catalog.Repository
+		hasMore bool
+	)
+	if visible {
+		repos, hasMore, err = c.Catalog.ListRepositories(ctx, paginationAmount(params.Amount), prefix, paginationAfter(params.After))
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
+	results := make([]apigen.Repository, 0, len(repos))
+	for _, repo := range repos {
+		creationDate := repo.CreationDate.Unix()
+		r := apigen.Repository{
+			Id:               repo.Name,
+			StorageNamespace: repo.StorageNamespace,
+			CreationDate:     creationDate,
+			DefaultBranch:    repo.DefaultBranch,
+			ReadOnly:         swag.Bool(repo.ReadOnly),
+		}
+		results = append(results, r)
+	}
+	repositoryList := apigen.RepositoryList{
+		Pagination: paginationFor(hasMore, results, "Id"),
+		Results:    results,
+	}
+	writeResponse(w, r, http.StatusOK, repositoryList)
 }
 
-func (c *Controller) ImportStart(w http.ResponseWriter, r *http.Request, body apigen.ImportStartJSONRequestBody, repository, branch string) {
-	perm := permissions.Node{
+func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, body apigen.CreateRepositoryJSONRequestBody, params apigen.CreateRepositoryParams) {
+	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.WriteObjectAction,
-					Resource: permissions.BranchArn(repository, branch),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.CreateRepositoryAction,
+					Resource: permissions.RepoArn(body.Name),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.BranchArn(repository, branch),
+					Action:   permissions.AttachStorageNamespaceAction,
+					Resource: permissions.StorageNamespace(body.StorageNamespace),
 				},
 			},
 		},
+	}) {
+		return
 	}
-	// Add import permissions per source
-	// Add object permissions per destination
-	for _, source := range body.Paths {
-		perm.Nodes = append(perm.Nodes,
+	ctx := r.Context()
+
+	// Verify first if there is a repository definition.
+	// Return conflict if definition already exists, before
+	// creating the repository itself and ensuring (optional) storage namespace holds an object.
+	// Example will be by restoring a repository from a backup or previous bare repository.
+	_, err := c.Catalog.GetRepository(ctx, body.Name)
+	if err == nil {
+		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", graveler.ErrNotUnique))
+		return
+	}
+	if c.handleAPIError(ctx, w, r, c.checkRepositoryQuota(ctx, body.Name)) {
+		return
+	}
+	sampleData := swag.BoolValue(body.SampleData)
+	c.LogAction(ctx, "create_repo", r, body.Name, "", "")
+	if sampleData {
+		c.LogAction(ctx, "repo_sample_data", r, body.Name, "", "")
+	}
+
+	if err := c.validateStorageNamespace(body.StorageNamespace); err != nil {
+		writeStorageNamespaceError(w, r, err, "invalid_namespace")
+		return
+	}
+	if err := c.checkStorageNamespaceConflicts(ctx, body.StorageNamespace); err != nil {
+		var reason string
+		switch {
+		case errors.Is(err, ErrStorageNamespaceInUse):
+			reason = "already_in_use"
+		case errors.Is(err, ErrStorageNamespaceNested):
+			reason = "nested_namespace"
+		default:
+			c.handleAPIError(ctx, w, r, err)
+			return
+		}
+		c.Logger.
+			WithError(err).
+			WithField("storage_namespace", body.StorageNamespace).
+			WithField("reason", reason).
+			Warn("Storage namespace conflicts with an existing repository")
+		writeStorageNamespaceError(w, r, fmt.Errorf("failed to create repository: %w", err), reason)
+		return
+	}
+
+	defaultBranch := swag.StringValue(body.DefaultBranch)
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+
+	var adapterOverride *graveler.BlockAdapterOverride
+	if body.BlockAdapterOverride != nil {
+		adapterOverride = blockAdapterOverrideFromAPI(body.BlockAdapterOverride)
+		if c.handleAPIError(ctx, w, r, c.Catalog.ValidateBlockAdapterOverride(adapterOverride)) {
+			return
+		}
+		// access the storage namespace the way the repository will
+		ctx = catalog.BlockAdapterOverrideContext(ctx, adapterOverride)
+	}
+
+	if swag.BoolValue(params.Bare) {
+		// create a bare repository. This is useful in conjunction with refs-restore to create a copy
+		// of another repository by e.g. copying the _lakefs/ directory and restoring its refs
+		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, body.StorageNamespace, defaultBranch)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		if adapterOverride != nil {
+			err = c.Catalog.SetBlockAdapterOverride(ctx, body.Name, adapterOverride)
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+		}
+		response := apigen.Repository{
+			CreationDate:     repo.CreationDate.Unix(),
+			DefaultBranch:    repo.DefaultBranch,
+			Id:               repo.Name,
+			StorageNamespace: repo.StorageNamespace,
+		}
+		writeResponse(w, r, http.StatusCreated, response)
+		return
+	}
+
+	if err := c.ensureStorageNamespace(ctx, body.StorageNamespace); err != nil {
+		var (
+			reason string
+			retErr error
+			urlErr *url.Error
+		)
+		switch {
+		case errors.As(err, &urlErr) && urlErr.Op == "parse":
+			retErr = err
+			reason = "bad_url"
+		case errors.Is(err, block.ErrInvalidAddress):
+			retErr = fmt.Errorf("%w, must match: %s", err, c.BlockAdapter.BlockstoreType())
+			reason = "invalid_namespace"
+		case errors.Is(err, ErrStorageNamespaceInUse):
+			retErr = err
+			reason = "already_in_use"
+		default:
+			retErr = ErrFailedToAccessStorage
+			reason = "not_accessible"
+		}
+		c.Logger.
+			WithError(err).
+			WithField("storage_namespace", body.StorageNamespace).
+			WithField("reason", reason).
+			Warn("Could not access storage namespace")
+		writeStorageNamespaceError(w, r, fmt.Errorf("failed to create repository: %w", retErr), reason)
+		return
+	}
+
+	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
+	if err != nil {
+		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", err))
+		return
+	}
+	if adapterOverride != nil {
+		err = c.Catalog.SetBlockAdapterOverride(ctx, body.Name, adapterOverride)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
+
+	if sampleData {
+		// add sample data, hooks, etc.
+		user, err := auth.GetUser(ctx)
+		if err != nil {
+			writeError(w, r, http.StatusUnauthorized, "missing user")
+			return
+		}
+
+		err = samplerepo.PopulateSampleRepo(ctx, newRepo, c.Catalog, c.PathProvider, c.BlockAdapter, user)
+		if err != nil {
+			c.handleAPIError(ctx, w, r, fmt.Errorf("error populating sample repository: %w", err))
+			return
+		}
+
+		err = samplerepo.AddBranchProtection(ctx, newRepo, c.Catalog)
+		if err != nil {
+			c.handleAPIError(ctx, w, r, fmt.Errorf("error adding branch protection to sample repository: %w", err))
+			return
+		}
+	}
+
+	response := apigen.Repository{
+		CreationDate:     newRepo.CreationDate.Unix(),
+		DefaultBranch:    newRepo.DefaultBranch,
+		Id:               newRepo.Name,
+		StorageNamespace: newRepo.StorageNamespace,
+		ReadOnly:         swag.Bool(newRepo.ReadOnly),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) validateStorageNamespace(storageNamespace string) error {
+	validRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
+	storagePrefixRegex, err := regexp.Compile(validRegex)
+	if err != nil {
+		return fmt.Errorf("failed to compile validity regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	}
+	if !storagePrefixRegex.MatchString(storageNamespace) {
+		return fmt.Errorf("failed to match required regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	}
+	return nil
+}
+
+// checkStorageNamespaceConflicts verifies no existing repository uses storageNamespace, or a storage namespace
+// nested with it: one containing it or contained in it
+func (c *Controller) checkStorageNamespaceConflicts(ctx context.Context, storageNamespace string) error {
+	namespace := strings.TrimSuffix(storageNamespace, "/") + "/"
+	var after string
+	for {
+		repos, hasMore, err := c.Catalog.ListRepositories(ctx, -1, "", after)
+		if err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			repoNamespace := strings.TrimSuffix(repo.StorageNamespace, "/") + "/"
+			switch {
+			case repoNamespace == namespace:
+				return fmt.Errorf("storage namespace %s used by repository %s: %w", storageNamespace, repo.Name, ErrStorageNamespaceInUse)
+			case strings.HasPrefix(repoNamespace, namespace), strings.HasPrefix(namespace, repoNamespace):
+				return fmt.Errorf("storage namespace %s and %s of repository %s: %w", storageNamespace, repo.StorageNamespace, repo.Name, ErrStorageNamespaceNested)
+			}
+		}
+		if !hasMore || len(repos) == 0 {
+			return nil
+		}
+		after = repos[len(repos)-1].Name
+	}
+}
+
+func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespace string) error {
+	const (
+		dummyData    = "this is dummy data - created by lakeFS to check accessibility"
+		dummyObjName = "dummy"
+	)
+	dummyKey := fmt.Sprintf("%s/%s", c.Config.Committed.BlockStoragePrefix, dummyObjName)
+
+	objLen := int64(len(dummyData))
+
+	// check if the dummy file exist in the root of the storage namespace
+	// this serves two purposes, first, we maintain safety check for older lakeFS version.
+	// second, in scenarios where lakeFS shouldn't have access to the root namespace (i.e pre-sign URL only).
+	if c.Config.Graveler.EnsureReadableRootNamespace {
+		rootObj := block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       dummyObjName,
+		}
+
+		if s, err := c.BlockAdapter.Get(ctx, rootObj, objLen); err == nil {
+			_ = s.Close()
+			return fmt.Errorf("found lakeFS objects in the storage namespace root(%s): %w",
+				storageNamespace, ErrStorageNamespaceInUse)
+		} else if !errors.Is(err, block.ErrDataNotFound) {
+			return err
+		}
+	}
+
+	// check if the dummy file exists
+	obj := block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       dummyKey,
+	}
+
+	if s, err := c.BlockAdapter.Get(ctx, obj, objLen); err == nil {
+		_ = s.Close()
+		return fmt.Errorf("found lakeFS objects in the storage namespace(%s) key(%s): %w",
+			storageNamespace, obj.Identifier, ErrStorageNamespaceInUse)
+	} else if !errors.Is(err, block.ErrDataNotFound) {
+		return err
+	}
+
+	if err := c.BlockAdapter.Put(ctx, obj, objLen, strings.NewReader(dummyData), block.PutOpts{}); err != nil {
+		return err
+	}
+
+	_, err := c.BlockAdapter.Get(ctx, obj, objLen)
+	return err
+}
+
+func (c *Controller) DeleteRepository(w http.ResponseWriter, r *http.Request, repository string, params apigen.DeleteRepositoryParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_repo", r, repository, "", "")
+	err := c.Catalog.DeleteRepository(ctx, repository, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if c.Search != nil {
+		c.Search.DeleteRepository(graveler.RepositoryID(repository))
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetRepository(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_repo", r, repository, "", "")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	switch {
+	case err == nil:
+		response := apigen.Repository{
+			CreationDate:     repo.CreationDate.Unix(),
+			DefaultBranch:    repo.DefaultBranch,
+			Id:               repo.Name,
+			StorageNamespace: repo.StorageNamespace,
+			ReadOnly:         swag.Bool(repo.ReadOnly),
+		}
+		writeResponse(w, r, http.StatusOK, response)
+
+	case errors.Is(err, graveler.ErrNotFound):
+		writeError(w, r, http.StatusNotFound, "repository not found")
+
+	case errors.Is(err, graveler.ErrRepositoryInDeletion):
+		writeError(w, r, http.StatusGone, err)
+
+	default:
+		writeError(w, r, http.StatusInternalServerError, err)
+	}
+}
+
+func (c *Controller) GetRepositoryMetadata(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_repo_metadata", r, repository, "", "")
+	metadata, err := c.Catalog.GetRepositoryMetadata(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apigen.RepositoryMetadata{AdditionalProperties: metadata})
+}
+
+func (c *Controller) SetRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.SetRepositoryMetadataJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "update_repo_metadata", r, repository, "", "")
+	err := c.Catalog.UpdateRepositoryMetadata(ctx, repository, body.Metadata.AdditionalProperties)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) DeleteRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.DeleteRepositoryMetadataJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_repo_metadata", r, repository, "", "")
+	err := c.Catalog.DeleteRepositoryMetadata(ctx, repository, body.Keys)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, eTag, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := make([]*apigen.BranchProtectionRule, 0, len(rules.BranchPatternToBlockedActions))
+	for pattern := range rules.BranchPatternToBlockedActions {
+		resp = append(resp, &apigen.BranchProtectionRule{
+			Pattern: pattern,
+		})
+	}
+	w.Header().Set("ETag", swag.StringValue(eTag))
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Request, body apigen.SetBranchProtectionRulesJSONRequestBody, repository string, params apigen.SetBranchProtectionRulesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
+
+	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
+	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
+
+	rules := &graveler.BranchProtectionRules{
+		BranchPatternToBlockedActions: make(map[string]*graveler.BranchProtectionBlockedActions),
+	}
+	for _, r := range body {
+		rules.BranchPatternToBlockedActions[r.Pattern] = &graveler.BranchProtectionBlockedActions{
+			Value: blockedActions,
+		}
+	}
+	err := c.Catalog.SetBranchProtectionRules(ctx, repository, rules, params.IfMatch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) DeleteGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, nil)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetLifecycleRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetLifecycleRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, err := c.Catalog.GetLifecycleRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.LifecycleRules{Rules: make([]apigen.LifecycleRule, 0, len(rules.GetRules()))}
+	for _, rule := range rules.GetRules() {
+		resp.Rules = append(resp.Rules, apigen.LifecycleRule{
+			Id:             rule.Id,
+			Branch:         rule.Branch,
+			Prefix:         rule.Prefix,
+			ExpirationDays: int(rule.ExpirationDays),
+			Enabled:        swag.Bool(!rule.Disabled),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetLifecycleRules(w http.ResponseWriter, r *http.Request, body apigen.SetLifecycleRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetLifecycleRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_lifecycle_rules", r, repository, "", "")
+	rules := &graveler.LifecycleRules{Rules: make([]*graveler.LifecycleRule, 0, len(body.Rules))}
+	for _, rule := range body.Rules {
+		rules.Rules = append(rules.Rules, &graveler.LifecycleRule{
+			Id:             rule.Id,
+			Branch:         rule.Branch,
+			Prefix:         rule.Prefix,
+			ExpirationDays: int32(rule.ExpirationDays),
+			Disabled:       rule.Enabled != nil && !*rule.Enabled,
+		})
+	}
+	err := c.Catalog.SetLifecycleRules(ctx, repository, rules)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) DeleteLifecycleRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetLifecycleRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_lifecycle_rules", r, repository, "", "")
+	err := c.Catalog.SetLifecycleRules(ctx, repository, &graveler.LifecycleRules{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetPassThroughMappings(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	mappings, err := c.Catalog.GetPassThroughMappings(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.PassThroughMappings{Mappings: make([]apigen.PassThroughMapping, 0, len(mappings))}
+	for _, m := range mappings {
+		resp.Mappings = append(resp.Mappings, apigen.PassThroughMapping{
+			Prefix:       m.Prefix,
+			Upstream:     m.Upstream,
+			ImportOnRead: swag.Bool(m.ImportOnRead),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetPassThroughMappings(w http.ResponseWriter, r *http.Request, body apigen.SetPassThroughMappingsJSONRequestBody, repository string) {
+	// reading through an upstream location is importing from it
+	perm := permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.UpdateRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}
+	for _, m := range body.Mappings {
+		perm.Nodes = append(perm.Nodes, permissions.Node{Permission: permissions.Permission{
+			Action:   permissions.ImportFromStorageAction,
+			Resource: permissions.StorageNamespace(m.Upstream),
+		}})
+		if swag.BoolValue(m.ImportOnRead) {
+			perm.Nodes = append(perm.Nodes, permissions.Node{Permission: permissions.Permission{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(repository, m.Prefix),
+			}})
+		}
+	}
+	if !c.authorize(w, r, perm) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_pass_through_mappings", r, repository, "", "")
+
+	info := c.BlockAdapter.GetStorageNamespaceInfo()
+	if len(body.Mappings) > 0 && !info.ImportSupport {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("pass-through is not supported by block adapter: %s", c.BlockAdapter.BlockstoreType()))
+		return
+	}
+	uriRegex := info.ImportValidityRegex
+	if uriRegex == "" {
+		uriRegex = info.ValidityRegex
+	}
+	mappings := make([]catalog.PassThroughMapping, 0, len(body.Mappings))
+	for _, m := range body.Mappings {
+		if match, err := regexp.MatchString(uriRegex, m.Upstream); err != nil || !match {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("upstream %s is not valid for block adapter: %s",
+				m.Upstream, c.BlockAdapter.BlockstoreType()))
+			return
+		}
+		mappings = append(mappings, catalog.PassThroughMapping{
+			Prefix:       m.Prefix,
+			Upstream:     m.Upstream,
+			ImportOnRead: swag.BoolValue(m.ImportOnRead),
+		})
+	}
+	err := c.Catalog.SetPassThroughMappings(ctx, repository, mappings)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ListLegalHolds(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetLegalHoldsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	holds, err := c.Catalog.ListLegalHolds(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.LegalHoldList{
+		Results: make([]apigen.LegalHold, 0, len(holds)),
+	}
+	for _, hold := range holds {
+		resp.Results = append(resp.Results, legalHoldToAPI(hold))
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetLegalHold(w http.ResponseWriter, r *http.Request, body apigen.SetLegalHoldJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetLegalHoldsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_legal_hold", r, repository, "", "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	hold, err := c.Catalog.SetLegalHold(ctx, repository, body.Kind, body.Id, body.Reason, user.Username)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, legalHoldToAPI(hold))
+}
+
+func (c *Controller) DeleteLegalHold(w http.ResponseWriter, r *http.Request, repository string, params apigen.DeleteLegalHoldParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetLegalHoldsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_legal_hold", r, repository, "", "")
+
+	err := c.Catalog.DeleteLegalHold(ctx, repository, params.Kind, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetObjectLockConfiguration(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetObjectLockConfigurationAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	config, err := c.Catalog.GetObjectLockConfiguration(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.ObjectLockConfiguration{
+		Enabled: config.Enabled,
+	}
+	if config.DefaultMode != "" {
+		resp.DefaultMode = swag.String(config.DefaultMode)
+	}
+	if config.DefaultRetentionDays > 0 {
+		resp.DefaultRetentionDays = swag.Int(int(config.DefaultRetentionDays))
+	}
+	if config.DefaultRetentionYears > 0 {
+		resp.DefaultRetentionYears = swag.Int(int(config.DefaultRetentionYears))
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetObjectLockConfiguration(w http.ResponseWriter, r *http.Request, body apigen.SetObjectLockConfigurationJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetObjectLockConfigurationAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_object_lock_configuration", r, repository, "", "")
+
+	err := c.Catalog.SetObjectLockConfiguration(ctx, repository, &graveler.ObjectLockConfiguration{
+		Enabled:               body.Enabled,
+		DefaultMode:           swag.StringValue(body.DefaultMode),
+		DefaultRetentionDays:  int32(swag.IntValue(body.DefaultRetentionDays)),
+		DefaultRetentionYears: int32(swag.IntValue(body.DefaultRetentionYears)),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetCostAttribution(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	attribution, err := c.Catalog.GetCostAttribution(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.CostAttribution{
+		RequesterPays: swag.Bool(attribution.RequesterPays),
+	}
+	if len(attribution.Tags) > 0 {
+		resp.Tags = &apigen.CostAttribution_Tags{AdditionalProperties: attribution.Tags}
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetCostAttribution(w http.ResponseWriter, r *http.Request, body apigen.SetCostAttributionJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_cost_attribution", r, repository, "", "")
+
+	attribution := &graveler.CostAttribution{
+		RequesterPays: swag.BoolValue(body.RequesterPays),
+	}
+	if body.Tags != nil {
+		attribution.Tags = body.Tags.AdditionalProperties
+	}
+	err := c.Catalog.SetCostAttribution(ctx, repository, attribution)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetBlockAdapterOverride(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	override, err := c.Catalog.GetBlockAdapterOverride(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.BlockAdapterOverride{
+		UseAccelerate:  swag.Bool(override.UseAccelerate),
+		ForcePathStyle: swag.Bool(override.ForcePathStyle),
+	}
+	if override.Endpoint != "" {
+		resp.Endpoint = swag.String(override.Endpoint)
+	}
+	if override.Region != "" {
+		resp.Region = swag.String(override.Region)
+	}
+	if override.Profile != "" {
+		resp.Profile = swag.String(override.Profile)
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetBlockAdapterOverride(w http.ResponseWriter, r *http.Request, body apigen.SetBlockAdapterOverrideJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_block_adapter_override", r, repository, "", "")
+
+	override := apigen.BlockAdapterOverride(body)
+	err := c.Catalog.SetBlockAdapterOverride(ctx, repository, blockAdapterOverrideFromAPI(&override))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) PromoteReplica(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "promote_replica", r, repository, "", "")
+
+	err := c.Catalog.SetRepositoryReadOnly(ctx, repository, false)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func blockAdapterOverrideFromAPI(override *apigen.BlockAdapterOverride) *graveler.BlockAdapterOverride {
+	return &graveler.BlockAdapterOverride{
+		Endpoint:       swag.StringValue(override.Endpoint),
+		UseAccelerate:  swag.BoolValue(override.UseAccelerate),
+		Region:         swag.StringValue(override.Region),
+		Profile:        swag.StringValue(override.Profile),
+		ForcePathStyle: swag.BoolValue(override.ForcePathStyle),
+	}
+}
+
+func (c *Controller) GetKeyValidationRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, err := c.Catalog.GetKeyValidationRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.KeyValidationRules{}
+	if rules.MaxKeyLength > 0 {
+		resp.MaxKeyLength = swag.Int(int(rules.MaxKeyLength))
+	}
+	if rules.MaxPathDepth > 0 {
+		resp.MaxPathDepth = swag.Int(int(rules.MaxPathDepth))
+	}
+	if rules.ForbiddenCharacters != "" {
+		resp.ForbiddenCharacters = swag.String(rules.ForbiddenCharacters)
+	}
+	if rules.RequiredLayout != "" {
+		resp.RequiredLayout = swag.String(rules.RequiredLayout)
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetKeyValidationRules(w http.ResponseWriter, r *http.Request, body apigen.SetKeyValidationRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_key_validation_rules", r, repository, "", "")
+
+	rules := &graveler.KeyValidationRules{
+		MaxKeyLength:        int32(swag.IntValue(body.MaxKeyLength)),
+		MaxPathDepth:        int32(swag.IntValue(body.MaxPathDepth)),
+		ForbiddenCharacters: swag.StringValue(body.ForbiddenCharacters),
+		RequiredLayout:      swag.StringValue(body.RequiredLayout),
+	}
+	err := c.Catalog.SetKeyValidationRules(ctx, repository, rules)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetCommitTemplate(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	template, err := c.Catalog.GetCommitTemplate(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	resp := apigen.CommitTemplate{
+		RequireMessage: swag.Bool(template.RequireMessage),
+	}
+	if len(template.RequiredMetadataKeys) > 0 {
+		resp.RequiredMetadataKeys = &template.RequiredMetadataKeys
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) SetCommitTemplate(w http.ResponseWriter, r *http.Request, body apigen.SetCommitTemplateJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_commit_template", r, repository, "", "")
+
+	template := &graveler.CommitTemplate{
+		RequireMessage: swag.BoolValue(body.RequireMessage),
+	}
+	if body.RequiredMetadataKeys != nil {
+		template.RequiredMetadataKeys = *body.RequiredMetadataKeys
+	}
+	err := c.Catalog.SetCommitTemplate(ctx, repository, template)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) SetObjectRetention(w http.ResponseWriter, r *http.Request, body apigen.SetObjectRetentionJSONRequestBody, repository, branch string, params apigen.SetObjectRetentionParams) {
+	retention := &catalog.ObjectRetention{
+		Mode:            body.Mode,
+		RetainUntilDate: time.Unix(body.RetainUntilDate, 0),
+	}
+	c.setObjectRetention(w, r, repository, branch, params.Path, retention, swag.BoolValue(params.BypassGovernance))
+}
+
+func (c *Controller) DeleteObjectRetention(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DeleteObjectRetentionParams) {
+	c.setObjectRetention(w, r, repository, branch, params.Path, nil, swag.BoolValue(params.BypassGovernance))
+}
+
+func (c *Controller) setObjectRetention(w http.ResponseWriter, r *http.Request, repository, branch, path string, retention *catalog.ObjectRetention, bypassGovernance bool) {
+	perms := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetObjectRetentionAction,
+			Resource: permissions.ObjectArn(repository, path),
+		},
+	}
+	if bypassGovernance {
+		perms = permissions.Node{
+			Type: permissions.NodeTypeAnd,
+			Nodes: []permissions.Node{
+				perms,
+				{
+					Permission: permissions.Permission{
+						Action:   permissions.BypassGovernanceRetentionAction,
+						Resource: permissions.ObjectArn(repository, path),
+					},
+				},
+			},
+		}
+	}
+	if !c.authorize(w, r, perms) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_object_retention", r, repository, branch, "")
+
+	err := c.Catalog.SetObjectRetention(ctx, repository, branch, path, retention, bypassGovernance)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func objectRetentionToAPI(retention *catalog.ObjectRetention) *apigen.ObjectRetention {
+	if retention == nil {
+		return nil
+	}
+	return &apigen.ObjectRetention{
+		Mode:            retention.Mode,
+		RetainUntilDate: retention.RetainUntilDate.Unix(),
+	}
+}
+
+// storageClassToAPI returns the recorded storage class of an entry, nil if none was recorded
+func storageClassToAPI(storageClass string) *string {
+	if storageClass == "" {
+		return nil
+	}
+	return &storageClass
+}
+
+// objectSourceToAPI returns the source object of an imported entry, nil if the entry was not imported
+func objectSourceToAPI(source *catalog.ObjectSource) *apigen.ObjectSource {
+	if source == nil {
+		return nil
+	}
+	res := &apigen.ObjectSource{Etag: source.ETag}
+	if source.VersionID != "" {
+		res.VersionId = swag.String(source.VersionID)
+	}
+	return res
+}
+
+func legalHoldToAPI(hold *catalog.LegalHold) apigen.LegalHold {
+	return apigen.LegalHold{
+		Kind:         hold.Kind,
+		Id:           hold.ID,
+		Reason:       hold.Reason,
+		CreatedBy:    hold.CreatedBy,
+		CreationDate: hold.CreationDate.Unix(),
+	}
+}
+
+func (c *Controller) GetGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	rules, err := c.Catalog.GetGarbageCollectionRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, gcRulesToAPI(rules))
+}
+
+func gcRulesToAPI(rules *graveler.GarbageCollectionRules) apigen.GarbageCollectionRules {
+	resp := apigen.GarbageCollectionRules{}
+	resp.DefaultRetentionDays = int(rules.DefaultRetentionDays)
+	for branchID, retentionDays := range rules.BranchRetentionDays {
+		resp.Branches = append(resp.Branches, apigen.GarbageCollectionRule{BranchId: branchID, RetentionDays: int(retentionDays)})
+	}
+	return resp
+}
+
+func gcRulesFromAPI(body apigen.GarbageCollectionRules) *graveler.GarbageCollectionRules {
+	rules := &graveler.GarbageCollectionRules{
+		DefaultRetentionDays: int32(body.DefaultRetentionDays),
+		BranchRetentionDays:  make(map[string]int32),
+	}
+	for _, rule := range body.Branches {
+		rules.BranchRetentionDays[rule.BranchId] = int32(rule.RetentionDays)
+	}
+	return rules
+}
+
+func (c *Controller) SetGCRules(w http.ResponseWriter, r *http.Request, body apigen.SetGCRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, gcRulesFromAPI(apigen.GarbageCollectionRules(body)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) PlanGCRules(w http.ResponseWriter, r *http.Request, body apigen.PlanGCRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "plan_gc_rules", r, repository, "", "")
+
+	var rules *graveler.GarbageCollectionRules
+	if body.Rules != nil {
+		rules = gcRulesFromAPI(*body.Rules)
+	}
+	sampleRatio := swag.Float64Value(body.SampleRatio)
+	if body.SampleRatio == nil {
+		sampleRatio = 1
+	}
+	taskID, err := c.Catalog.PlanGarbageCollectionSubmit(ctx, repository, rules, sampleRatio)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, &apigen.TaskInfo{Id: taskID})
+}
+
+func (c *Controller) PlanGCRulesStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.PlanGCRulesStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.PlanGarbageCollectionStatus(ctx, repository, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := &apigen.GarbageCollectionPlanStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if plan := status.Plan; status.Task.Done && plan != nil {
+		apiPlan := &apigen.GarbageCollectionPlan{
+			Rules: gcRulesToAPI(&graveler.GarbageCollectionRules{
+				DefaultRetentionDays: plan.DefaultRetentionDays,
+				BranchRetentionDays:  plan.BranchRetentionDays,
+			}),
+			ActiveCommits:      int(plan.ActiveCommits),
+			ExpiredCommits:     int(plan.ExpiredCommits),
+			ReclaimableObjects: plan.ReclaimableObjects,
+			ReclaimableBytes:   plan.ReclaimableBytes,
+			SampleRatio:        plan.SampleRatio,
+			Branches:           make([]apigen.GarbageCollectionPlanBranch, 0, len(plan.Branches)),
+		}
+		for _, b := range plan.Branches {
+			apiPlan.Branches = append(apiPlan.Branches, apigen.GarbageCollectionPlanBranch{
+				BranchId:        b.BranchId,
+				RetentionDays:   int(b.RetentionDays),
+				RetainedCommits: int(b.RetainedCommits),
+				RetainedObjects: b.RetainedObjects,
+				RetainedBytes:   b.RetainedBytes,
+			})
+		}
+		response.Plan = apiPlan
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
+
+	_, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	branchName := swag.StringValue(params.Branch)
+	commitID := swag.StringValue(params.Commit)
+	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer runsIter.Close()
+
+	response := apigen.ActionRunList{
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
+		},
+		Results: make([]apigen.ActionRun, 0),
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && runsIter.Next() {
+		val := runsIter.Value()
+		response.Results = append(response.Results, runResultToActionRun(val))
+	}
+	response.Pagination.Results = len(response.Results)
+	if runsIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastRun.RunId
+		}
+	}
+	if err := runsIter.Err(); err != nil {
+		writeResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
+	runResult := apigen.ActionRun{
+		Branch:    val.BranchID,
+		CommitId:  val.CommitID,
+		RunId:     val.RunID,
+		StartTime: val.StartTime,
+		EndTime:   &val.EndTime,
+		EventType: val.EventType,
+	}
+	if val.Passed {
+		runResult.Status = actionStatusCompleted
+	} else {
+		runResult.Status = actionStatusFailed
+	}
+	return runResult
+}
+
+func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
+	_, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	var status string
+	if runResult.Passed {
+		status = actionStatusCompleted
+	} else {
+		status = actionStatusFailed
+	}
+	response := apigen.ActionRun{
+		RunId:     runResult.RunID,
+		EventType: runResult.EventType,
+		StartTime: runResult.StartTime,
+		EndTime:   &runResult.EndTime,
+		Status:    status,
+		Branch:    runResult.BranchID,
+		CommitId:  runResult.CommitID,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) RerunRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	// hooks run again with the identity of the user, as if committing to the branch
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(repository, runResult.BranchID),
+		},
+	}) {
+		return
+	}
+	c.LogAction(ctx, "actions_rerun_run", r, repository, runResult.BranchID, "")
+
+	if runResult.Passed {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("run %s passed: %w", runID, actions.ErrRunNotRerunnable))
+		return
+	}
+	record, err := c.Catalog.GetPostHookRecord(ctx, repository, graveler.EventType(runResult.EventType), runResult.BranchID, runResult.CommitID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	newRunResult, err := c.Actions.Rerun(ctx, *record)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, runResultToActionRun(newRunResult))
+}
+
+func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer tasksIter.Close()
+
+	response := apigen.HookRunList{
+		Results: make([]apigen.HookRun, 0),
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
+		},
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && tasksIter.Next() {
+		val := tasksIter.Value()
+		hookRun := apigen.HookRun{
+			HookRunId: val.HookRunID,
+			Action:    val.ActionName,
+			HookId:    val.HookID,
+			StartTime: val.StartTime,
+			EndTime:   &val.EndTime,
+		}
+		switch {
+		case val.Passed:
+			hookRun.Status = actionStatusCompleted
+		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
+			hookRun.Status = actionStatusSkipped
+			hookRun.EndTime = nil
+		default:
+			hookRun.Status = actionStatusFailed
+		}
+		response.Results = append(response.Results, hookRun)
+	}
+	if tasksIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastHookRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastHookRun.HookRunId
+		}
+	}
+	if err := tasksIter.Err(); err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response.Pagination.Results = len(response.Results)
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	if taskResult.StartTime.IsZero() { // skipped task
+		writeResponse(w, r, http.StatusOK, nil)
+		return
+	}
+
+	logPath := taskResult.LogPath()
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       logPath,
+	}, -1)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
+	w.Header().Set("Content-Disposition", cd)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
+	}
+}
+
+func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListBranchesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_branches", r, repository, "", "")
+
+	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	refs := make([]apigen.Ref, 0, len(res))
+	for _, branch := range res {
+		refs = append(refs, apigen.Ref{
+			CommitId: branch.Reference,
+			Id:       branch.Name,
+			Lock:     branchLockToAPI(branch.Lock),
+		})
+	}
+	response := apigen.RefList{
+		Results:    refs,
+		Pagination: paginationFor(hasMore, refs, "Id"),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListDeletedBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListDeletedBranchesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListDeletedBranchesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_deleted_branches", r, repository, "", "")
+
+	res, hasMore, err := c.Catalog.ListDeletedBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	branches := make([]apigen.DeletedBranch, 0, len(res))
+	for _, branch := range res {
+		branches = append(branches, apigen.DeletedBranch{
+			Id:        branch.Name,
+			CommitId:  branch.Reference,
+			DeletedAt: branch.DeletedAt.Unix(),
+			ExpiresAt: branch.ExpiresAt.Unix(),
+		})
+	}
+	response := apigen.DeletedBranchList{
+		Results:    branches,
+		Pagination: paginationFor(hasMore, branches, "Id"),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) RestoreBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_branch", r, repository, branch, "")
+
+	restored, err := c.Catalog.RestoreBranch(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, apigen.Ref{
+		Id:       restored.Name,
+		CommitId: restored.Reference,
+	})
+}
+
+func (c *Controller) StashBranch(w http.ResponseWriter, r *http.Request, body apigen.StashBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateStashAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "stash_branch", r, repository, branch, "")
+
+	stash, err := c.Catalog.StashBranch(ctx, repository, branch, body.Id, swag.StringValue(body.Message))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, stashToAPI(stash))
+}
+
+func (c *Controller) ListStashes(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListStashesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListStashesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_stashes", r, repository, "", "")
+
+	res, hasMore, err := c.Catalog.ListStashes(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	stashes := make([]apigen.Stash, 0, len(res))
+	for _, stash := range res {
+		stashes = append(stashes, stashToAPI(stash))
+	}
+	response := apigen.StashList{
+		Results:    stashes,
+		Pagination: paginationFor(hasMore, stashes, "Id"),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetStash(w http.ResponseWriter, r *http.Request, repository, stash string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadStashAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_stash", r, repository, "", "")
+
+	res, err := c.Catalog.GetStash(ctx, repository, stash)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, stashToAPI(res))
+}
+
+func (c *Controller) DeleteStash(w http.ResponseWriter, r *http.Request, repository, stash string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteStashAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_stash", r, repository, "", "")
+
+	err := c.Catalog.DeleteStash(ctx, repository, stash)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ApplyStash(w http.ResponseWriter, r *http.Request, body apigen.ApplyStashJSONRequestBody, repository, stash string) {
+	drop := swag.BoolValue(body.Drop)
+	nodes := []permissions.Node{
+		{
+			Permission: permissions.Permission{
+				Action:   permissions.ApplyStashAction,
+				Resource: permissions.BranchArn(repository, body.Branch),
+			},
+		},
+	}
+	if drop {
+		nodes = append(nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.DeleteStashAction,
+				Resource: permissions.RepoArn(repository),
+			},
+		})
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Type:  permissions.NodeTypeAnd,
+		Nodes: nodes,
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "apply_stash", r, repository, body.Branch, "")
+
+	err := c.Catalog.ApplyStash(ctx, repository, stash, body.Branch, drop)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func stashToAPI(stash *catalog.Stash) apigen.Stash {
+	return apigen.Stash{
+		Id:           stash.ID,
+		Branch:       stash.Branch,
+		CommitId:     stash.Reference,
+		Message:      stash.Message,
+		CreationDate: stash.CreationDate.Unix(),
+	}
+}
+
+func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateBranchAction,
+			Resource: permissions.BranchArn(repository, body.Name),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
+
+	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)), graveler.WithExistOK(swag.BoolValue(body.ExistOk)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_, _ = io.WriteString(w, commitLog.Reference)
+}
+
+func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
+
+	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) RenameBranch(w http.ResponseWriter, r *http.Request, body apigen.RenameBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.DeleteBranchAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.BranchArn(repository, body.Name),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "rename_branch", r, repository, branch, "")
+
+	err := c.Catalog.RenameBranch(ctx, repository, branch, body.Name, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_branch", r, repository, branch, "")
+	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	lock, err := c.Catalog.GetBranchLock(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.Ref{
+		CommitId: reference,
+		Id:       branch,
+		Lock:     branchLockToAPI(lock),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) LockBranch(w http.ResponseWriter, r *http.Request, body apigen.LockBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.LockBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "lock_branch", r, repository, branch, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	err = c.Catalog.LockBranch(ctx, repository, branch, body.Reason, user.Username)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) UnlockBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.LockBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "unlock_branch", r, repository, branch, "")
+
+	err := c.Catalog.UnlockBranch(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func branchLockToAPI(lock *catalog.BranchLock) *apigen.BranchLock {
+	if lock == nil {
+		return nil
+	}
+	return &apigen.BranchLock{
+		Reason:       lock.Reason,
+		LockedBy:     lock.LockedBy,
+		CreationDate: lock.CreationDate.Unix(),
+	}
+}
+
+func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, err error, cb func(w http.ResponseWriter, r *http.Request, code int, v interface{})) bool {
+	// verify if request canceled even if there is no error, early exit point
+	if httputil.IsRequestCanceled(r) {
+		cb(w, r, httpStatusClientClosedRequest, httpStatusClientClosedRequestText)
+		return true
+	}
+	if err == nil {
+		return false
+	}
+
+	log := c.Logger.WithContext(ctx).WithError(err)
+
+	// Handle Hook Errors
+	var hookAbortErr *graveler.HookAbortError
+	if errors.As(err, &hookAbortErr) {
+		log.WithField("run_id", hookAbortErr.RunID).Warn("aborted by hooks")
+		cb(w, r, http.StatusPreconditionFailed, err)
+		return true
+	}
+
+	// order of case is important, more specific errors should be first
+	switch {
+	case errors.Is(err, graveler.ErrLinkAddressNotFound),
+		errors.Is(err, graveler.ErrLinkAddressExpired):
+		log.Debug("Expired or invalid address token")
+		cb(w, r, http.StatusBadRequest, "bad address token (expired or invalid)")
+
+	case errors.Is(err, graveler.ErrNotFound),
+		errors.Is(err, actions.ErrNotFound),
+		errors.Is(err, auth.ErrNotFound),
+		errors.Is(err, kv.ErrNotFound):
+		log.Debug("Not found")
+		cb(w, r, http.StatusNotFound, err)
+
+	case errors.Is(err, block.ErrForbidden),
+		errors.Is(err, auth.ErrProvisioningDenied),
+		errors.Is(err, auth.ErrQuotaExceeded),
+		errors.Is(err, auth.ErrOrganizationMismatch),
+		errors.Is(err, auth.ErrNotImplemented),
+		errors.Is(err, graveler.ErrProtectedBranch),
+		errors.Is(err, graveler.ErrLegalHold),
+		errors.Is(err, graveler.ErrObjectLocked),
+		errors.Is(err, graveler.ErrBranchLocked),
+		errors.Is(err, graveler.ErrReadOnlyRepository):
+		cb(w, r, http.StatusForbidden, err)
+
+	case errors.Is(err, graveler.ErrDirtyBranch),
+		errors.Is(err, graveler.ErrCommitMetaRangeDirtyBranch),
+		errors.Is(err, graveler.ErrInvalidValue),
+		errors.Is(err, validator.ErrInvalidValue),
+		errors.Is(err, catalog.ErrPathRequiredValue),
+		errors.Is(err, graveler.ErrNoChanges),
+		errors.Is(err, permissions.ErrInvalidServiceName),
+		errors.Is(err, permissions.ErrInvalidAction),
+		errors.Is(err, model.ErrValidationError),
+		errors.Is(err, graveler.ErrInvalidRef),
+		errors.Is(err, actions.ErrParamConflict),
+		errors.Is(err, actions.ErrRunNotRerunnable),
+		errors.Is(err, graveler.ErrDereferenceCommitWithStaging),
+		errors.Is(err, graveler.ErrParentOutOfRange),
+		errors.Is(err, graveler.ErrCherryPickMergeNoParent),
+		errors.Is(err, graveler.ErrInvalidMergeStrategy),
+		errors.Is(err, block.ErrInvalidAddress),
+		errors.Is(err, block.ErrOperationNotSupported):
+		log.Debug("Bad request")
+		cb(w, r, http.StatusBadRequest, err)
+
+	case errors.Is(err, graveler.ErrNotUnique),
+		errors.Is(err, graveler.ErrConflictFound),
+		errors.Is(err, graveler.ErrNotFastForward),
+		errors.Is(err, graveler.ErrRevertMergeNoParent),
+		errors.Is(err, block.ErrObjectArchived):
+		log.Debug("Conflict")
+		cb(w, r, http.StatusConflict, err)
+
+	case errors.Is(err, graveler.ErrLockNotAcquired):
+		log.Debug("Lock not acquired")
+		cb(w, r, http.StatusInternalServerError, "branch is currently locked, try again later")
+
+	case errors.Is(err, block.ErrDataNotFound):
+		log.Debug("No data")
+		cb(w, r, http.StatusGone, "No data")
+
+	case errors.Is(err, auth.ErrAlreadyExists):
+		log.Debug("Already exists")
+		cb(w, r, http.StatusConflict, "Already exists")
+
+	case errors.Is(err, graveler.ErrTooManyTries):
+		log.Debug("Retried too many times")
+		cb(w, r, http.StatusLocked, "Too many attempts, try again later")
+	case errors.Is(err, graveler.ErrPreconditionFailed):
+		log.Debug("Precondition failed")
+		cb(w, r, http.StatusPreconditionFailed, "Precondition failed")
+	case err != nil:
+		c.Logger.WithContext(ctx).WithError(err).Error("API call returned status internal server error")
+		cb(w, r, http.StatusInternalServerError, err)
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (c *Controller) handleAPIError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) bool {
+	return c.handleAPIErrorCallback(ctx, w, r, err, writeError)
+}
+
+func (c *Controller) ResetBranch(w http.ResponseWriter, r *http.Request, body apigen.ResetBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+
+	c.LogAction(ctx, "reset_branch", r, repository, branch, "")
+
+	var err error
+
+	switch body.Type {
+	case entryTypeCommonPrefix:
+		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+	case "reset":
+		err = c.Catalog.ResetBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	case entryTypeObject:
+		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+	default:
+		writeError(w, r, http.StatusBadRequest, "unknown reset type")
+		return
+	}
+
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) HardResetBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.HardResetBranchParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			// TODO(ozkatz): Can we have another action here?
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+
+	c.LogAction(ctx, "hard_reset_branch", r, repository, branch, "")
+
+	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) ImportStart(w http.ResponseWriter, r *http.Request, body apigen.ImportStartJSONRequestBody, repository, branch string) {
+	perm := permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+		},
+	}
+	// Add import permissions per source
+	// Add object permissions per destination
+	for _, source := range body.Paths {
+		perm.Nodes = append(perm.Nodes,
 			permissions.Node{Permission: permissions.Permission{
 				Action:   permissions.ImportFromStorageAction,
 				Resource: permissions.StorageNamespace(source.Path),
@@ -2714,621 +4764,1376 @@ func (c *Controller) ImportStart(w http.ResponseWriter, r *http.Request, body ap
 				Resource: permissions.ObjectArn(repository, source.Destination),
 			}})
 	}
-	if !c.authorize(w, r, perm) {
+	if !c.authorize(w, r, perm) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "import", r, repository, branch, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	metadata := map[string]string{}
+	if body.Commit.Metadata != nil {
+		metadata = body.Commit.Metadata.AdditionalProperties
+	}
+	paths := make([]catalog.ImportPath, 0, len(body.Paths))
+	for _, p := range body.Paths {
+		pathType, err := catalog.GetImportPathType(p.Type)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		paths = append(paths, catalog.ImportPath{
+			Destination: p.Destination,
+			Path:        p.Path,
+			Type:        pathType,
+		})
+	}
+
+	importID, err := c.Catalog.Import(r.Context(), repository, branch, catalog.ImportRequest{
+		Paths: paths,
+		Commit: catalog.ImportCommit{
+			CommitMessage: body.Commit.Message,
+			Committer:     user.Committer(),
+			Metadata:      metadata,
+		},
+		Force: swag.BoolValue(body.Force),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.ImportCreationResponse{
+		Id: importID,
+	})
+}
+
+func importStatusToResponse(status *graveler.ImportStatus) apigen.ImportStatus {
+	resp := apigen.ImportStatus{
+		Completed:       status.Completed,
+		IngestedObjects: &status.Progress,
+		UpdateTime:      status.UpdatedAt,
+	}
+
+	if status.Error != nil {
+		resp.Error = &apigen.Error{Message: status.Error.Error()}
+	}
+	if status.MetaRangeID != "" {
+		metarange := status.MetaRangeID.String()
+		resp.MetarangeId = &metarange
+	}
+
+	commitLog := catalog.CommitRecordToLog(status.Commit)
+	if commitLog != nil {
+		resp.Commit = &apigen.Commit{
+			Committer:    commitLog.Committer,
+			Author:       commitAuthor(commitLog.Author),
+			CreationDate: commitLog.CreationDate.Unix(),
+			Id:           commitLog.Reference,
+			Message:      commitLog.Message,
+			MetaRangeId:  commitLog.MetaRangeID,
+			Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commitLog.Metadata},
+			Parents:      commitLog.Parents,
+			Version:      apiutil.Ptr(int(commitLog.Version)),
+			Generation:   apiutil.Ptr(int64(commitLog.Generation)),
+			Stats:        commitStatsToAPI(commitLog.Stats),
+		}
+	}
+
+	return resp
+}
+
+func (c *Controller) ImportStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.GetImportStatus(ctx, repository, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	resp := importStatusToResponse(status)
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (c *Controller) ImportCancel(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportCancelParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ImportCancelAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "cancel_import", r, repository, branch, "")
+	err := c.Catalog.CancelImport(ctx, repository, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) Commit(w http.ResponseWriter, r *http.Request, body apigen.CommitJSONRequestBody, repository, branch string, params apigen.CommitParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_commit", r, repository, branch, "")
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	var metadata map[string]string
+	if body.Metadata != nil {
+		metadata = body.Metadata.AdditionalProperties
+	}
+
+	var paths, prefixes []string
+	if body.Paths != nil {
+		paths = *body.Paths
+	}
+	if body.Prefixes != nil {
+		prefixes = *body.Prefixes
+	}
+	partial := len(paths) > 0 || len(prefixes) > 0
+
+	var newCommit *catalog.CommitLog
+	switch {
+	case partial && (swag.BoolValue(body.Amend) || params.SourceMetarange != nil):
+		writeError(w, r, http.StatusBadRequest, "partial commit cannot amend or set a source metarange")
+		return
+	case partial:
+		newCommit, err = c.Catalog.PartialCommit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, swag.BoolValue(body.AllowEmpty), paths, prefixes, graveler.WithForce(swag.BoolValue(body.Force)))
+	case swag.BoolValue(body.Amend):
+		if body.Date != nil || params.SourceMetarange != nil {
+			writeError(w, r, http.StatusBadRequest, "amend cannot set the commit date or source metarange")
+			return
+		}
+		newCommit, err = c.Catalog.AmendCommit(ctx, repository, branch, body.Message, user.Committer(), metadata, graveler.WithForce(swag.BoolValue(body.Force)))
+	default:
+		newCommit, err = c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	commitResponse(w, r, newCommit)
+}
+
+func (c *Controller) CommitAsync(w http.ResponseWriter, r *http.Request, body apigen.CommitAsyncJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_commit_async", r, repository, branch, "")
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	if swag.BoolValue(body.Amend) || body.Paths != nil || body.Prefixes != nil {
+		writeError(w, r, http.StatusBadRequest, "commit in the background cannot amend or be partial")
+		return
+	}
+	var metadata map[string]string
+	if body.Metadata != nil {
+		metadata = body.Metadata.AdditionalProperties
+	}
+
+	taskID, err := c.Catalog.CommitAsyncSubmit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) CommitAsyncStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CommitAsyncStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.CommitAsyncStatus(ctx, repository, branch, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := &apigen.CommitAsyncStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if status.CommitId != "" {
+		commit, err := c.Catalog.GetCommit(ctx, repository, status.CommitId)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		response.Commit = apiutil.Ptr(commitLogToAPI(commit))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) CreateCommitRecord(w http.ResponseWriter, r *http.Request, body apigen.CreateCommitRecordJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_commit_record", r, repository, body.CommitId, "")
+	_, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	err = c.Catalog.CreateCommitRecord(ctx, repository, body.CommitId, body.Version, body.Committer, body.Message, body.MetarangeId, &body.CreationDate, body.Parents, body.Metadata.AdditionalProperties, int(body.Generation), graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+// commitAuthor returns the author of a commit response, nil for commits without a recorded author
+func commitAuthor(author string) *string {
+	if author == "" {
+		return nil
+	}
+	return &author
+}
+
+func commitStatsToAPI(stats *catalog.CommitStats) *apigen.CommitStats {
+	if stats == nil {
+		return nil
+	}
+	return &apigen.CommitStats{
+		ObjectCount:      stats.ObjectCount,
+		SizeBytes:        stats.SizeBytes,
+		ObjectCountDelta: stats.ObjectCountDelta,
+		SizeBytesDelta:   stats.SizeBytesDelta,
+	}
+}
+
+func commitResponse(w http.ResponseWriter, r *http.Request, newCommit *catalog.CommitLog) {
+	writeResponse(w, r, http.StatusCreated, commitLogToAPI(newCommit))
+}
+
+func commitLogToAPI(newCommit *catalog.CommitLog) apigen.Commit {
+	return apigen.Commit{
+		Committer:    newCommit.Committer,
+		Author:       commitAuthor(newCommit.Author),
+		CreationDate: newCommit.CreationDate.Unix(),
+		Id:           newCommit.Reference,
+		Message:      newCommit.Message,
+		MetaRangeId:  newCommit.MetaRangeID,
+		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: newCommit.Metadata},
+		Parents:      newCommit.Parents,
+		Version:      apiutil.Ptr(int(newCommit.Version)),
+		Generation:   apiutil.Ptr(int64(newCommit.Generation)),
+		Stats:        commitStatsToAPI(newCommit.Stats),
+	}
+}
+
+func (c *Controller) DiffBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DiffBranchParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "diff_workspace", r, repository, branch, "")
+
+	var (
+		diff    catalog.Differences
+		hasMore bool
+		err     error
+	)
+	if base := swag.StringValue(params.Base); base != "" {
+		// the staging modifier includes the uncommitted changes of the branch
+		diff, hasMore, err = c.Catalog.Diff(ctx, repository, base, branch+string(graveler.RefModTypeDollar), catalog.DiffParams{
+			Limit:     paginationAmount(params.Amount),
+			After:     paginationAfter(params.After),
+			Prefix:    paginationPrefix(params.Prefix),
+			Delimiter: paginationDelimiter(params.Delimiter),
+		})
+	} else {
+		diff, hasMore, err = c.Catalog.DiffUncommitted(
+			ctx,
+			repository,
+			branch,
+			paginationPrefix(params.Prefix),
+			paginationDelimiter(params.Delimiter),
+			paginationAmount(params.Amount),
+			paginationAfter(params.After),
+		)
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	results := make([]apigen.Diff, 0, len(diff))
+	for _, d := range diff {
+		pathType := entryTypeObject
+		if d.CommonLevel {
+			pathType = entryTypeCommonPrefix
+		}
+		diff := apigen.Diff{
+			Path:     d.Path,
+			Type:     transformDifferenceTypeToString(d.Type),
+			PathType: pathType,
+		}
+		if !d.CommonLevel {
+			diff.SizeBytes = swag.Int64(d.Size)
+		}
+		results = append(results, diff)
+	}
+	response := apigen.DiffList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) DeleteObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DeleteObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_object", r, repository, branch, "")
+	err := c.Catalog.DeleteEntry(ctx, repository, branch, params.Path, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) UploadObjectPreflight(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectPreflightParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "put_object_preflight", r, repository, branch, "")
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "put_object", r, repository, branch, "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+		return
+	}
+
+	// validate the key before uploading the body, it is validated again when the object is created
+	err = c.Catalog.ValidateEntryKey(ctx, repository, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// before writing body, ensure preconditions - this means we essentially check for object existence twice:
+	// once before uploading the body to save resources and time,
+	//	and then graveler will check again when passed a SetOptions.
+	allowOverwrite := true
+	if params.IfNoneMatch != nil {
+		if swag.StringValue(params.IfNoneMatch) != "*" {
+			writeError(w, r, http.StatusBadRequest, "Unsupported value for If-None-Match - Only \"*\" is supported")
+			return
+		}
+		// check if exists
+		_, err := c.Catalog.GetEntry(ctx, repo.Name, branch, params.Path, catalog.GetEntryParams{})
+		if err == nil {
+			writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+			return
+		}
+		if !errors.Is(err, graveler.ErrNotFound) {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		allowOverwrite = false
+	}
+
+	// read request body parse multipart for "content" and upload the data
+	contentType := catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
+	mediaType, p, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var blob *upload.Blob
+	if mediaType != "multipart/form-data" {
+		// handle non-multipart, direct content upload
+		address := c.PathProvider.NewPath()
+		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength,
+			block.PutOpts{StorageClass: params.StorageClass})
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		// handle multipart upload
+		boundary, ok := p["boundary"]
+		if !ok {
+			writeError(w, r, http.StatusInternalServerError, http.ErrMissingBoundary)
+			return
+		}
+
+		contentUploaded := false
+		reader := multipart.NewReader(r.Body, boundary)
+		for !contentUploaded {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				writeError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			contentType = part.Header.Get("Content-Type")
+			partName := part.FormName()
+			if partName == "content" {
+				// upload the first "content" and exit the loop
+				address := c.PathProvider.NewPath()
+				blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, part, -1, block.PutOpts{StorageClass: params.StorageClass})
+				if err != nil {
+					_ = part.Close()
+					writeError(w, r, http.StatusInternalServerError, err)
+					return
+				}
+				contentUploaded = true
+			}
+			_ = part.Close()
+		}
+		if !contentUploaded {
+			err := fmt.Errorf("multipart upload missing key 'content': %w", http.ErrMissingFile)
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	// write metadata
+	writeTime := time.Now()
+	entryBuilder := catalog.NewDBEntryBuilder().
+		Path(params.Path).
+		PhysicalAddress(blob.PhysicalAddress).
+		CreationDate(writeTime).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(contentType).
+		StorageClass(swag.StringValue(params.StorageClass))
+	if blob.RelativePath {
+		entryBuilder.AddressType(catalog.AddressTypeRelative)
+	} else {
+		entryBuilder.AddressType(catalog.AddressTypeFull)
+	}
+	meta := extractLakeFSMetadata(r.Header)
+	if len(meta) > 0 {
+		entryBuilder.Metadata(meta)
+	}
+	entry := entryBuilder.Build()
+
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force)))
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+		return
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	identifierType := block.IdentifierTypeFull
+	if blob.RelativePath {
+		identifierType = block.IdentifierTypeRelative
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, identifierType)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := apigen.ObjectStats{
+		Checksum:        blob.Checksum,
+		Mtime:           writeTime.Unix(),
+		Path:            params.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(blob.Size),
+		ContentType:     &contentType,
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
+		StorageClass:    storageClassToAPI(entry.StorageClass),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body apigen.StageObjectJSONRequestBody, repository, branch string, params apigen.StageObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "stage_object", r, repository, branch, "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	// write metadata
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// see what storage type this is and whether it fits our configuration
+	uriRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
+	if match, err := regexp.MatchString(uriRegex, body.PhysicalAddress); err != nil || !match {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("physical address is not valid for block adapter: %s",
+			c.BlockAdapter.BlockstoreType(),
+		))
+		return
+	}
+
+	// take mtime from request, if any
+	writeTime := time.Now()
+	if body.Mtime != nil {
+		writeTime = time.Unix(*body.Mtime, 0)
+	}
+
+	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
+
+	entryBuilder := catalog.NewDBEntryBuilder().
+		CommonLevel(false).
+		Path(params.Path).
+		PhysicalAddress(physicalAddress).
+		AddressType(addressType).
+		CreationDate(writeTime).
+		Size(body.SizeBytes).
+		Checksum(body.Checksum).
+		ContentType(swag.StringValue(body.ContentType))
+	if body.Metadata != nil {
+		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
+	}
+	entry := entryBuilder.Build()
+
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
+	srcPath := body.SrcPath
+	destPath := params.DestPath
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadActionsAction,
+					Resource: permissions.ObjectArn(repository, srcPath),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, destPath),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// verify destination is a branch (and exists)
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+		return
+	}
+
+	// use destination branch as source if not specified
+	srcRef := swag.StringValue(body.SrcRef)
+	if srcRef == "" {
+		srcRef = branch
+	}
+
+	// copy entry
+	entry, err := c.Catalog.CopyEntry(ctx, repository, srcRef, srcPath, repository, branch, destPath, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var metadata map[string]string
+	if entry.Metadata != nil {
+		metadata = entry.Metadata
+	} else {
+		metadata = map[string]string{}
+	}
+
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) AppendObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.AppendObjectParams) {
+	// the appended object contains the data of the object
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:          permissions.ReadObjectAction,
+					Resource:        permissions.ObjectArn(repository, params.Path),
+					ConditionValues: c.objectTagsConditionValues(r.Context(), repository, branch, params.Path),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, params.Path),
+				},
+			},
+		},
+	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "import", r, repository, branch, "")
+	c.LogAction(ctx, "append_object", r, repository, branch, params.Path)
 
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	metadata := map[string]string{}
-	if body.Commit.Metadata != nil {
-		metadata = body.Commit.Metadata.AdditionalProperties
-	}
-	paths := make([]catalog.ImportPath, 0, len(body.Paths))
-	for _, p := range body.Paths {
-		pathType, err := catalog.GetImportPathType(p.Type)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		paths = append(paths, catalog.ImportPath{
-			Destination: p.Destination,
-			Path:        p.Path,
-			Type:        pathType,
-		})
-	}
 
-	importID, err := c.Catalog.Import(r.Context(), repository, branch, catalog.ImportRequest{
-		Paths: paths,
-		Commit: catalog.ImportCommit{
-			CommitMessage: body.Commit.Message,
-			Committer:     user.Committer(),
-			Metadata:      metadata,
-		},
-		Force: swag.BoolValue(body.Force),
+	entry, err := c.Catalog.AppendEntry(ctx, repository, branch, params.Path, r.Body, r.ContentLength, catalog.AppendParams{
+		WriteOffset: params.WriteOffset,
+		ContentType: catalog.ContentTypeOrDefault(r.Header.Get("Content-Type")),
 	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusAccepted, apigen.ImportCreationResponse{
-		Id: importID,
-	})
-}
 
-func importStatusToResponse(status *graveler.ImportStatus) apigen.ImportStatus {
-	resp := apigen.ImportStatus{
-		Completed:       status.Completed,
-		IngestedObjects: &status.Progress,
-		UpdateTime:      status.UpdatedAt,
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
 	}
 
-	if status.Error != nil {
-		resp.Error = &apigen.Error{Message: status.Error.Error()}
-	}
-	if status.MetaRangeID != "" {
-		metarange := status.MetaRangeID.String()
-		resp.MetarangeId = &metarange
+	metadata := entry.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
 	}
-
-	commitLog := catalog.CommitRecordToLog(status.Commit)
-	if commitLog != nil {
-		resp.Commit = &apigen.Commit{
-			Committer:    commitLog.Committer,
-			CreationDate: commitLog.CreationDate.Unix(),
-			Id:           commitLog.Reference,
-			Message:      commitLog.Message,
-			MetaRangeId:  commitLog.MetaRangeID,
-			Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commitLog.Metadata},
-			Parents:      commitLog.Parents,
-			Version:      apiutil.Ptr(int(commitLog.Version)),
-			Generation:   apiutil.Ptr(int64(commitLog.Generation)),
-		}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
 	}
-
-	return resp
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ImportStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportStatusParams) {
+func (c *Controller) UpdateObjectMetadata(w http.ResponseWriter, r *http.Request, body apigen.UpdateObjectMetadataJSONRequestBody, repository, branch string, params apigen.UpdateObjectMetadataParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	status, err := c.Catalog.GetImportStatus(ctx, repository, params.Id)
+	c.LogAction(ctx, "update_object_metadata", r, repository, branch, params.Path)
+
+	if body.UserMetadata == nil && body.ContentType == nil && body.Tags == nil {
+		writeError(w, r, http.StatusBadRequest, "nothing to update: user_metadata, content_type or tags required")
+		return
+	}
+	var metadata catalog.Metadata
+	if body.UserMetadata != nil {
+		metadata = catalog.Metadata(body.UserMetadata.AdditionalProperties)
+		if metadata == nil {
+			metadata = catalog.Metadata{}
+		}
+	}
+	var tags map[string]string
+	if body.Tags != nil {
+		tags = body.Tags.AdditionalProperties
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		// changing the tags of an object may change the policies that apply to it
+		current, err := c.Catalog.GetEntryTags(ctx, repository, branch, params.Path)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		if !maps.Equal(current, tags) && !c.authorize(w, r, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.TagObjectAction,
+				Resource: permissions.ObjectArn(repository, params.Path),
+				ConditionValues: permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+					return current, nil
+				}),
+			},
+		}) {
+			return
+		}
+	}
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, err := c.Catalog.UpdateEntryMetadata(ctx, repository, branch, params.Path, metadata, body.ContentType, tags, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	resp := importStatusToResponse(status)
-	writeResponse(w, r, http.StatusOK, resp)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata},
+		Retention:       objectRetentionToAPI(entry.Retention),
+		StorageClass:    storageClassToAPI(entry.StorageClass),
+		Tags:            objectTagsToAPI(entry.Tags),
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ImportCancel(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportCancelParams) {
+func objectTagsToAPI(tags map[string]string) *apigen.ObjectTags {
+	if len(tags) == 0 {
+		return nil
+	}
+	return &apigen.ObjectTags{AdditionalProperties: tags}
+}
+
+func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ImportCancelAction,
+			Action:   permissions.RevertBranchAction,
 			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "cancel_import", r, repository, branch, "")
-	err := c.Catalog.CancelImport(ctx, repository, params.Id)
+	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "user not found")
+		return
+	}
+	err = c.Catalog.Revert(ctx, repository, branch, catalog.RevertParams{
+		Reference:    body.Ref,
+		Committer:    user.Committer(),
+		ParentNumber: body.ParentNumber,
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) Commit(w http.ResponseWriter, r *http.Request, body apigen.CommitJSONRequestBody, repository, branch string, params apigen.CommitParams) {
+func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.CreateCommitAction,
-			Resource: permissions.BranchArn(repository, branch),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_commit", r, repository, branch, "")
+	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
+
 	user, err := auth.GetUser(ctx)
 	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+		writeError(w, r, http.StatusUnauthorized, "user not found")
 		return
 	}
-	var metadata map[string]string
-	if body.Metadata != nil {
-		metadata = body.Metadata.AdditionalProperties
-	}
-
-	newCommit, err := c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, catalog.CherryPickParams{
+		Reference:    body.Ref,
+		Committer:    user.Committer(),
+		ParentNumber: body.ParentNumber,
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+
 	commitResponse(w, r, newCommit)
 }
 
-func (c *Controller) CreateCommitRecord(w http.ResponseWriter, r *http.Request, body apigen.CreateCommitRecordJSONRequestBody, repository string) {
+func (c *Controller) GetCommit(w http.ResponseWriter, r *http.Request, repository, commitID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.CreateCommitAction,
+			Action:   permissions.ReadCommitAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_commit_record", r, repository, body.CommitId, "")
-	_, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+	c.LogAction(ctx, "get_commit", r, repository, commitID, "")
+	commit, err := c.Catalog.GetCommit(ctx, repository, commitID)
+	if errors.Is(err, graveler.ErrRepositoryNotFound) {
+		writeError(w, r, http.StatusNotFound, "repository not found")
 		return
 	}
-	err = c.Catalog.CreateCommitRecord(ctx, repository, body.CommitId, body.Version, body.Committer, body.Message, body.MetarangeId, &body.CreationDate, body.Parents, body.Metadata.AdditionalProperties, int(body.Generation), graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	if errors.Is(err, graveler.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "commit not found")
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	response := apigen.Commit{
+		Id:           commit.Reference,
+		Committer:    commit.Committer,
+		Author:       commitAuthor(commit.Author),
+		CreationDate: commit.CreationDate.Unix(),
+		Message:      commit.Message,
+		MetaRangeId:  commit.MetaRangeID,
+		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commit.Metadata},
+		Parents:      commit.Parents,
+		Generation:   apiutil.Ptr(int64(commit.Generation)),
+		Stats:        commitStatsToAPI(commit.Stats),
+		Version:      apiutil.Ptr(int(commit.Version)),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	writeResponse(w, r, http.StatusNoContent, nil)
+func (c *Controller) InternalGetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.GetGCRules(w, r, repository)
 }
 
-func commitResponse(w http.ResponseWriter, r *http.Request, newCommit *catalog.CommitLog) {
-	response := apigen.Commit{
-		Committer:    newCommit.Committer,
-		CreationDate: newCommit.CreationDate.Unix(),
-		Id:           newCommit.Reference,
-		Message:      newCommit.Message,
-		MetaRangeId:  newCommit.MetaRangeID,
-		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: newCommit.Metadata},
-		Parents:      newCommit.Parents,
-		Version:      apiutil.Ptr(int(newCommit.Version)),
-		Generation:   apiutil.Ptr(int64(newCommit.Generation)),
+func (c *Controller) SetGarbageCollectionRulesPreflight(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
 	}
-	writeResponse(w, r, http.StatusCreated, response)
+
+	ctx := r.Context()
+	c.LogAction(ctx, "set_gc_collection_rules_preflight", r, repository, "", "")
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) InternalSetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, body apigen.InternalSetGarbageCollectionRulesJSONRequestBody, repository string) {
+	c.SetGCRules(w, r, apigen.SetGCRulesJSONRequestBody(body), repository)
 }
 
-func (c *Controller) DiffBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DiffBranchParams) {
+func (c *Controller) InternalDeleteGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.DeleteGCRules(w, r, repository)
+}
+
+func (c *Controller) PrepareGarbageCollectionCommits(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListObjectsAction,
+			Action:   permissions.PrepareGarbageCollectionCommitsAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "diff_workspace", r, repository, branch, "")
-
-	diff, hasMore, err := c.Catalog.DiffUncommitted(
-		ctx,
-		repository,
-		branch,
-		paginationPrefix(params.Prefix),
-		paginationDelimiter(params.Delimiter),
-		paginationAmount(params.Amount),
-		paginationAfter(params.After),
-	)
+	c.LogAction(ctx, "prepare_garbage_collection_commits", r, repository, "", "")
+	gcRunMetadata, err := c.Catalog.PrepareExpiredCommits(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	results := make([]apigen.Diff, 0, len(diff))
-	for _, d := range diff {
-		pathType := entryTypeObject
-		if d.CommonLevel {
-			pathType = entryTypeCommonPrefix
-		}
-		diff := apigen.Diff{
-			Path:     d.Path,
-			Type:     transformDifferenceTypeToString(d.Type),
-			PathType: pathType,
-		}
-		if !d.CommonLevel {
-			diff.SizeBytes = swag.Int64(d.Size)
-		}
-		results = append(results, diff)
-	}
-	response := apigen.DiffList{
-		Pagination: paginationFor(hasMore, results, "Path"),
-		Results:    results,
+	presignedURL, _, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+		Identifier:     gcRunMetadata.CommitsCSVLocation,
+		IdentifierType: block.IdentifierTypeFull,
+	}, block.PreSignModeRead)
+	if err != nil {
+		c.Logger.WithError(err).Warn("Failed to presign url for GC commits")
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusCreated, apigen.GarbageCollectionPrepareResponse{
+		GcCommitsLocation:     gcRunMetadata.CommitsCSVLocation,
+		GcAddressesLocation:   gcRunMetadata.AddressLocation,
+		RunId:                 gcRunMetadata.RunID,
+		GcCommitsPresignedUrl: &presignedURL,
+	})
 }
 
-func (c *Controller) DeleteObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DeleteObjectParams) {
+func (c *Controller) InternalGetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.GetBranchProtectionRules(w, r, repository)
+}
+
+func (c *Controller) InternalDeleteBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalDeleteBranchProtectionRuleJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_object", r, repository, branch, "")
-	err := c.Catalog.DeleteEntry(ctx, repository, branch, params.Path, graveler.WithForce(swag.BoolValue(params.Force)))
+	c.LogAction(ctx, "delete_branch_protection_rule", r, repository, "", "")
+
+	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	for p := range rules.BranchPatternToBlockedActions {
+		if p == body.Pattern {
+			delete(rules.BranchPatternToBlockedActions, p)
+			err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			writeResponse(w, r, http.StatusNoContent, nil)
+			return
+		}
+	}
+	writeResponse(w, r, http.StatusNotFound, nil)
 }
 
-func (c *Controller) UploadObjectPreflight(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectPreflightParams) {
+func (c *Controller) CreateBranchProtectionRulePreflight(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 
 	ctx := r.Context()
-	c.LogAction(ctx, "put_object_preflight", r, repository, branch, "")
+	c.LogAction(ctx, "create_branch_protection_rule_preflight", r, repository, "", "")
 
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectParams) {
+func (c *Controller) InternalCreateBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalCreateBranchProtectionRuleJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "put_object", r, repository, branch, "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
+	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	if !errors.Is(err, graveler.ErrNotFound) {
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
 	}
-
-	// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
-	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	if rules == nil {
+		rules = &graveler.BranchProtectionRules{}
 	}
-	if !branchExists {
-		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+	if rules.BranchPatternToBlockedActions == nil {
+		rules.BranchPatternToBlockedActions = make(map[string]*graveler.BranchProtectionBlockedActions)
+	}
+	blockedActions := &graveler.BranchProtectionBlockedActions{
+		Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT},
+	}
+	rules.BranchPatternToBlockedActions[body.Pattern] = blockedActions
+	err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	// before writing body, ensure preconditions - this means we essentially check for object existence twice:
-	// once before uploading the body to save resources and time,
-	//	and then graveler will check again when passed a SetOptions.
-	allowOverwrite := true
-	if params.IfNoneMatch != nil {
-		if swag.StringValue(params.IfNoneMatch) != "*" {
-			writeError(w, r, http.StatusBadRequest, "Unsupported value for If-None-Match - Only \"*\" is supported")
-			return
-		}
-		// check if exists
-		_, err := c.Catalog.GetEntry(ctx, repo.Name, branch, params.Path, catalog.GetEntryParams{})
-		if err == nil {
-			writeError(w, r, http.StatusPreconditionFailed, "path already exists")
-			return
-		}
-		if !errors.Is(err, graveler.ErrNotFound) {
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-		allowOverwrite = false
+func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, repository, metaRange string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "metadata_get_metarange", r, repository, "", "")
 
-	// read request body parse multipart for "content" and upload the data
-	contentType := catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
-	mediaType, p, err := mime.ParseMediaType(contentType)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	metarange, err := c.Catalog.GetMetaRange(ctx, repository, metaRange)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	var blob *upload.Blob
-	if mediaType != "multipart/form-data" {
-		// handle non-multipart, direct content upload
-		address := c.PathProvider.NewPath()
-		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength,
-			block.PutOpts{StorageClass: params.StorageClass})
-		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-	} else {
-		// handle multipart upload
-		boundary, ok := p["boundary"]
-		if !ok {
-			writeError(w, r, http.StatusInternalServerError, http.ErrMissingBoundary)
-			return
-		}
-
-		contentUploaded := false
-		reader := multipart.NewReader(r.Body, boundary)
-		for !contentUploaded {
-			part, err := reader.NextPart()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				writeError(w, r, http.StatusInternalServerError, err)
-				return
-			}
-			contentType = part.Header.Get("Content-Type")
-			partName := part.FormName()
-			if partName == "content" {
-				// upload the first "content" and exit the loop
-				address := c.PathProvider.NewPath()
-				blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, part, -1, block.PutOpts{StorageClass: params.StorageClass})
-				if err != nil {
-					_ = part.Close()
-					writeError(w, r, http.StatusInternalServerError, err)
-					return
-				}
-				contentUploaded = true
-			}
-			_ = part.Close()
-		}
-		if !contentUploaded {
-			err := fmt.Errorf("multipart upload missing key 'content': %w", http.ErrMissingFile)
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-	}
-	// write metadata
-	writeTime := time.Now()
-	entryBuilder := catalog.NewDBEntryBuilder().
-		Path(params.Path).
-		PhysicalAddress(blob.PhysicalAddress).
-		CreationDate(writeTime).
-		Size(blob.Size).
-		Checksum(blob.Checksum).
-		ContentType(contentType)
-	if blob.RelativePath {
-		entryBuilder.AddressType(catalog.AddressTypeRelative)
-	} else {
-		entryBuilder.AddressType(catalog.AddressTypeFull)
-	}
-	meta := extractLakeFSMetadata(r.Header)
-	if len(meta) > 0 {
-		entryBuilder.Metadata(meta)
+	response := apigen.StorageURI{
+		Location: string(metarange),
 	}
-	entry := entryBuilder.Build()
+	w.Header().Set("Location", string(metarange))
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force)))
-	if errors.Is(err, graveler.ErrPreconditionFailed) {
-		writeError(w, r, http.StatusPreconditionFailed, "path already exists")
-		return
-	}
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) GetRange(w http.ResponseWriter, r *http.Request, repository, pRange string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
 		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "metadata_get_range", r, repository, "", "")
 
-	identifierType := block.IdentifierTypeFull
-	if blob.RelativePath {
-		identifierType = block.IdentifierTypeRelative
-	}
-
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, identifierType)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	rng, err := c.Catalog.GetRange(ctx, repository, pRange)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	response := apigen.ObjectStats{
-		Checksum:        blob.Checksum,
-		Mtime:           writeTime.Unix(),
-		Path:            params.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(blob.Size),
-		ContentType:     &contentType,
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
+	response := apigen.StorageURI{
+		Location: string(rng),
 	}
-	writeResponse(w, r, http.StatusCreated, response)
+	w.Header().Set("Location", string(rng))
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body apigen.StageObjectJSONRequestBody, repository, branch string, params apigen.StageObjectParams) {
+func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListCommitsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "stage_object", r, repository, branch, "")
+	c.LogAction(ctx, "dump_repository_refs", r, repository, "", "")
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	// write metadata
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
+
+	// dump all types:
+	tagsID, err := c.Catalog.DumpTags(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// see what storage type this is and whether it fits our configuration
-	uriRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
-	if match, err := regexp.MatchString(uriRegex, body.PhysicalAddress); err != nil || !match {
-		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("physical address is not valid for block adapter: %s",
-			c.BlockAdapter.BlockstoreType(),
-		))
+	branchesID, err := c.Catalog.DumpBranches(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// take mtime from request, if any
-	writeTime := time.Now()
-	if body.Mtime != nil {
-		writeTime = time.Unix(*body.Mtime, 0)
+	commitsID, err := c.Catalog.DumpCommits(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
 
-	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
-
-	entryBuilder := catalog.NewDBEntryBuilder().
-		CommonLevel(false).
-		Path(params.Path).
-		PhysicalAddress(physicalAddress).
-		AddressType(addressType).
-		CreationDate(writeTime).
-		Size(body.SizeBytes).
-		Checksum(body.Checksum).
-		ContentType(swag.StringValue(body.ContentType))
-	if body.Metadata != nil {
-		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
+	response := apigen.RefsDump{
+		BranchesMetaRangeId: branchesID,
+		CommitsMetaRangeId:  commitsID,
+		TagsMetaRangeId:     tagsID,
 	}
-	entry := entryBuilder.Build()
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	// write this to the block store
+	manifestBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
+	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       fmt.Sprintf("%s/refs_manifest.json", c.Config.Committed.BlockStoragePrefix),
+	}, int64(len(manifestBytes)), bytes.NewReader(manifestBytes), block.PutOpts{})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
 	}
 	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
-	srcPath := body.SrcPath
-	destPath := params.DestPath
+func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body apigen.RestoreRefsJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadActionsAction,
-					Resource: permissions.ObjectArn(repository, srcPath),
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.WriteObjectAction,
-					Resource: permissions.ObjectArn(repository, destPath),
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
 				},
 			},
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
+	c.LogAction(ctx, "restore_repository_refs", r, repository, "", "")
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// verify destination is a branch (and exists)
-	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-	if !branchExists {
-		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+	// ensure no refs currently found
+	_, _, err = c.Catalog.ListCommits(ctx, repo.Name, repo.DefaultBranch, catalog.LogParams{
+		PathList:      make([]catalog.PathRecord, 0),
+		FromReference: "",
+		Amount:        1,
+	})
+	if !errors.Is(err, graveler.ErrNotFound) {
+		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
 		return
 	}
 
-	// use destination branch as source if not specified
-	srcRef := swag.StringValue(body.SrcRef)
-	if srcRef == "" {
-		srcRef = branch
-	}
-
-	// copy entry
-	entry, err := c.Catalog.CopyEntry(ctx, repository, srcRef, srcPath, repository, branch, destPath, graveler.WithForce(swag.BoolValue(body.Force)))
+	// load commits
+	err = c.Catalog.LoadCommits(ctx, repo.Name, body.CommitsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	var metadata map[string]string
-	if entry.Metadata != nil {
-		metadata = entry.Metadata
-	} else {
-		metadata = map[string]string{}
-	}
-
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
+	err = c.Catalog.LoadTags(ctx, repo.Name, body.TagsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
+func (c *Controller) DumpSubmit(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListCommitsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
+	c.LogAction(ctx, "dump_repository", r, repository, "", "")
 
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "user not found")
-		return
-	}
-	err = c.Catalog.Revert(ctx, repository, branch, catalog.RevertParams{
-		Reference:    body.Ref,
-		Committer:    user.Committer(),
-		ParentNumber: body.ParentNumber,
-	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	taskID, err := c.Catalog.DumpRepositorySubmit(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
 }
 
-func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
+func (c *Controller) DumpStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.DumpStatusParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.BranchArn(repository, branch),
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadCommitAction,
+					Action:   permissions.ListCommitsAction,
 					Resource: permissions.RepoArn(repository),
 				},
 			},
@@ -3336,207 +6141,206 @@ func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body api
 	}) {
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
 
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "user not found")
-		return
-	}
-	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, catalog.CherryPickParams{
-		Reference:    body.Ref,
-		Committer:    user.Committer(),
-		ParentNumber: body.ParentNumber,
-	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	// get the current status
+	ctx := r.Context()
+	status, err := c.Catalog.DumpRepositoryStatus(ctx, repository, params.TaskId)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	commitResponse(w, r, newCommit)
-}
-
-func (c *Controller) GetCommit(w http.ResponseWriter, r *http.Request, repository, commitID string) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadCommitAction,
-			Resource: permissions.RepoArn(repository),
-		},
-	}) {
-		return
-	}
-	ctx := r.Context()
-	c.LogAction(ctx, "get_commit", r, repository, commitID, "")
-	commit, err := c.Catalog.GetCommit(ctx, repository, commitID)
-	if errors.Is(err, graveler.ErrRepositoryNotFound) {
-		writeError(w, r, http.StatusNotFound, "repository not found")
-		return
-	}
-	if errors.Is(err, graveler.ErrNotFound) {
-		writeError(w, r, http.StatusNotFound, "commit not found")
-		return
+	// build response based on status
+	response := &apigen.RepositoryDumpStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
 	}
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
-		return
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
 	}
-	response := apigen.Commit{
-		Id:           commit.Reference,
-		Committer:    commit.Committer,
-		CreationDate: commit.CreationDate.Unix(),
-		Message:      commit.Message,
-		MetaRangeId:  commit.MetaRangeID,
-		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commit.Metadata},
-		Parents:      commit.Parents,
-		Generation:   apiutil.Ptr(int64(commit.Generation)),
-		Version:      apiutil.Ptr(int(commit.Version)),
+	if status.Task.Done && status.Info != nil {
+		response.Refs = &apigen.RefsDump{
+			CommitsMetaRangeId:  status.Info.CommitsMetarangeId,
+			TagsMetaRangeId:     status.Info.TagsMetarangeId,
+			BranchesMetaRangeId: status.Info.BranchesMetarangeId,
+		}
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) InternalGetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.GetGCRules(w, r, repository)
-}
-
-func (c *Controller) SetGarbageCollectionRulesPreflight(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) RestoreSubmit(w http.ResponseWriter, r *http.Request, body apigen.RestoreSubmitJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
 		},
 	}) {
 		return
 	}
 
 	ctx := r.Context()
-	c.LogAction(ctx, "set_gc_collection_rules_preflight", r, repository, "", "")
-
-	writeResponse(w, r, http.StatusNoContent, nil)
-}
-
-func (c *Controller) InternalSetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, body apigen.InternalSetGarbageCollectionRulesJSONRequestBody, repository string) {
-	c.SetGCRules(w, r, apigen.SetGCRulesJSONRequestBody(body), repository)
-}
-
-func (c *Controller) InternalDeleteGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.DeleteGCRules(w, r, repository)
-}
+	c.LogAction(ctx, "restore_repository", r, repository, "", "")
 
-func (c *Controller) PrepareGarbageCollectionCommits(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.PrepareGarbageCollectionCommitsAction,
-			Resource: permissions.RepoArn(repository),
-		},
-	}) {
+	info := &catalog.RepositoryDumpInfo{
+		CommitsMetarangeId:  body.CommitsMetaRangeId,
+		TagsMetarangeId:     body.TagsMetaRangeId,
+		BranchesMetarangeId: body.BranchesMetaRangeId,
+	}
+	taskID, err := c.Catalog.RestoreRepositorySubmit(ctx, repository, info, swag.BoolValue(body.Replace), graveler.WithForce(swag.BoolValue(body.Force)))
+	if errors.Is(err, catalog.ErrNonEmptyRepository) {
+		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository, unless replacing its refs")
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "prepare_garbage_collection_commits", r, repository, "", "")
-	gcRunMetadata, err := c.Catalog.PrepareExpiredCommits(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	presignedURL, _, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-		Identifier:     gcRunMetadata.CommitsCSVLocation,
-		IdentifierType: block.IdentifierTypeFull,
-	}, block.PreSignModeRead)
-	if err != nil {
-		c.Logger.WithError(err).Warn("Failed to presign url for GC commits")
-	}
-	writeResponse(w, r, http.StatusCreated, apigen.GarbageCollectionPrepareResponse{
-		GcCommitsLocation:     gcRunMetadata.CommitsCSVLocation,
-		GcAddressesLocation:   gcRunMetadata.AddressLocation,
-		RunId:                 gcRunMetadata.RunID,
-		GcCommitsPresignedUrl: &presignedURL,
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
 	})
 }
 
-func (c *Controller) InternalGetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.GetBranchProtectionRules(w, r, repository)
-}
-
-func (c *Controller) InternalDeleteBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalDeleteBranchProtectionRuleJSONRequestBody, repository string) {
+func (c *Controller) RestoreStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.RestoreStatusParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
 		},
 	}) {
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "delete_branch_protection_rule", r, repository, "", "")
 
-	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	// get the current status
+	ctx := r.Context()
+	status, err := c.Catalog.RestoreRepositoryStatus(ctx, repository, params.TaskId)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	for p := range rules.BranchPatternToBlockedActions {
-		if p == body.Pattern {
-			delete(rules.BranchPatternToBlockedActions, p)
-			err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
-			if c.handleAPIError(ctx, w, r, err) {
-				return
-			}
-			writeResponse(w, r, http.StatusNoContent, nil)
-			return
-		}
+
+	// build response based on status
+	response := &apigen.RepositoryRestoreStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
 	}
-	writeResponse(w, r, http.StatusNotFound, nil)
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateBranchProtectionRulePreflight(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) CreateSymlinkFile(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CreateSymlinkFileParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, branch),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule_preflight", r, repository, "", "")
-
-	writeResponse(w, r, http.StatusNoContent, nil)
-}
+	c.LogAction(ctx, "create_symlink", r, repository, branch, "")
 
-func (c *Controller) InternalCreateBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalCreateBranchProtectionRuleJSONRequestBody, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
-		},
-	}) {
+	// read repo
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
-	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
-	if !errors.Is(err, graveler.ErrNotFound) {
-		if c.handleAPIError(ctx, w, r, err) {
+
+	// list entries
+	var currentPath string
+	var currentAddresses []string
+	var after string
+	var entries []*catalog.DBEntry
+	hasMore := true
+	for hasMore {
+		entries, hasMore, err = c.Catalog.ListEntries(
+			ctx,
+			repository,
+			branch,
+			swag.StringValue(params.Location),
+			after,
+			"",
+			-1)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		// loop all entries enter to map[path] physicalAddress
+		for _, entry := range entries {
+			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+			if err != nil {
+				writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while resolving address: %s", err))
+				return
+			}
+			idx := strings.LastIndex(entry.Path, "/")
+			var path string
+			if idx != -1 {
+				path = entry.Path[0:idx]
+			}
+			if path != currentPath {
+				// push current
+				err := writeSymlink(ctx, repo, branch, path, currentAddresses, c.BlockAdapter)
+				if err != nil {
+					writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
+					return
+				}
+				currentPath = path
+				currentAddresses = []string{qk.Format()}
+			} else {
+				currentAddresses = append(currentAddresses, qk.Format())
+			}
+		}
+		after = entries[len(entries)-1].Path
+	}
+	if len(currentAddresses) > 0 {
+		err = writeSymlink(ctx, repo, branch, currentPath, currentAddresses, c.BlockAdapter)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
 			return
 		}
 	}
-	if rules == nil {
-		rules = &graveler.BranchProtectionRules{}
-	}
-	if rules.BranchPatternToBlockedActions == nil {
-		rules.BranchPatternToBlockedActions = make(map[string]*graveler.BranchProtectionBlockedActions)
-	}
-	blockedActions := &graveler.BranchProtectionBlockedActions{
-		Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT},
-	}
-	rules.BranchPatternToBlockedActions[body.Pattern] = blockedActions
-	err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	metaLocation := fmt.Sprintf("%s/%s", repo.StorageNamespace, lakeFSPrefix)
+	response := apigen.StorageURI{
+		Location: metaLocation,
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, repository, metaRange string) {
+func (c *Controller) CreateSymlinkManifests(w http.ResponseWriter, r *http.Request, body apigen.CreateSymlinkManifestsJSONRequestBody, repository, ref string) {
+	prefix := swag.StringValue(body.Prefix)
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
@@ -3548,8 +6352,8 @@ func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, reposi
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadRepositoryAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, prefix),
 				},
 			},
 		},
@@ -3557,74 +6361,100 @@ func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, reposi
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "metadata_get_metarange", r, repository, "", "")
+	c.LogAction(ctx, "create_symlink_manifests", r, repository, ref, "")
 
-	metarange, err := c.Catalog.GetMetaRange(ctx, repository, metaRange)
+	manifests, err := c.Catalog.WriteSymlinkManifests(ctx, repository, ref, prefix)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusCreated, apigen.SymlinkManifests{
+		CommitId:       manifests.CommitID,
+		Location:       manifests.Location,
+		ManifestsCount: manifests.Manifests,
+		ObjectsCount:   manifests.Objects,
+	})
+}
 
-	response := apigen.StorageURI{
-		Location: string(metarange),
-	}
-	w.Header().Set("Location", string(metarange))
-	writeResponse(w, r, http.StatusOK, response)
+func writeSymlink(ctx context.Context, repo *catalog.Repository, branch, path string, addresses []string, adapter block.Adapter) error {
+	address := fmt.Sprintf("%s/%s/%s/%s/symlink.txt", lakeFSPrefix, repo.Name, branch, path)
+	data := strings.Join(addresses, "\n")
+	err := adapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
+	return err
 }
 
-func (c *Controller) GetRange(w http.ResponseWriter, r *http.Request, repository, pRange string) {
+func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListObjectsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ReadRepositoryAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "metadata_get_range", r, repository, "", "")
+	c.LogAction(ctx, "diff_refs", r, repository, rightRef, leftRef)
+	diffFunc := c.Catalog.Compare // default diff type is three-dot
+	if params.Type != nil && *params.Type == "two_dot" {
+		diffFunc = c.Catalog.Diff
+	}
 
-	rng, err := c.Catalog.GetRange(ctx, repository, pRange)
+	diff, hasMore, err := diffFunc(ctx, repository, leftRef, rightRef, catalog.DiffParams{
+		Limit:            paginationAmount(params.Amount),
+		After:            paginationAfter(params.After),
+		Prefix:           paginationPrefix(params.Prefix),
+		Delimiter:        paginationDelimiter(params.Delimiter),
+		AdditionalFields: nil,
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.StorageURI{
-		Location: string(rng),
+	results := make([]apigen.Diff, 0, len(diff))
+	for _, d := range diff {
+		pathType := entryTypeObject
+		if d.CommonLevel {
+			pathType = entryTypeCommonPrefix
+		}
+		diff := apigen.Diff{
+			Path:     d.Path,
+			Type:     transformDifferenceTypeToString(d.Type),
+			PathType: pathType,
+		}
+		if !d.CommonLevel {
+			diff.SizeBytes = swag.Int64(d.Size)
+		}
+		results = append(results, diff)
+	}
+	response := apigen.DiffList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
 	}
-	w.Header().Set("Location", string(rng))
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) DiffObjectContent(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffObjectContentParams) {
+	rightPath := swag.StringValue(params.RightPath)
+	if rightPath == "" {
+		rightPath = params.Path
+	}
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
+					Action:          permissions.ReadObjectAction,
+					Resource:        permissions.ObjectArn(repository, params.Path),
+					ConditionValues: c.objectTagsConditionValues(r.Context(), repository, leftRef, params.Path),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
+					Action:          permissions.ReadObjectAction,
+					Resource:        permissions.ObjectArn(repository, rightPath),
+					ConditionValues: c.objectTagsConditionValues(r.Context(), repository, rightRef, rightPath),
 				},
 			},
 		},
@@ -3632,389 +6462,694 @@ func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "dump_repository_refs", r, repository, "", "")
+	c.LogAction(ctx, "diff_object_content", r, repository, rightRef, leftRef)
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// dump all types:
-	tagsID, err := c.Catalog.DumpTags(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	left, leftFound, err := c.readObjectContentForDiff(ctx, repo, leftRef, params.Path)
+	if err != nil {
+		c.handleDiffObjectContentError(ctx, w, r, err)
 		return
 	}
-
-	branchesID, err := c.Catalog.DumpBranches(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	right, rightFound, err := c.readObjectContentForDiff(ctx, repo, rightRef, rightPath)
+	if err != nil {
+		c.handleDiffObjectContentError(ctx, w, r, err)
 		return
 	}
-	commitsID, err := c.Catalog.DumpCommits(ctx, repository)
+	if !leftFound && !rightFound {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("%s and %s: %s", params.Path, rightPath, graveler.ErrNotFound))
+		return
+	}
+
+	// a missing object is compared as an empty file, like diff -N
+	fromFile := path.Join(leftRef, params.Path)
+	if !leftFound {
+		fromFile = os.DevNull
+	}
+	toFile := path.Join(rightRef, rightPath)
+	if !rightFound {
+		toFile = os.DevNull
+	}
+	contextLines := diffContentDefaultContext
+	if params.Context != nil {
+		contextLines = *params.Context
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitDiffLines(left),
+		B:        splitDiffLines(right),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  contextLines,
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, apigen.ObjectContentDiff{Diff: diff})
+}
 
-	response := apigen.RefsDump{
-		BranchesMetaRangeId: branchesID,
-		CommitsMetaRangeId:  commitsID,
-		TagsMetaRangeId:     tagsID,
+// splitDiffLines splits content to lines that keep their line terminator, adding one to the last line if missing
+func splitDiffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
 	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
 
-	// write this to the block store
-	manifestBytes, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+func (c *Controller) handleDiffObjectContentError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrObjectTooLargeToDiff) || errors.Is(err, ErrBinaryObjectDiff) {
+		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
-	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+	c.handleAPIError(ctx, w, r, err)
+}
+
+// readObjectContentForDiff returns the content of a text object, and false if the object does not exist
+func (c *Controller) readObjectContentForDiff(ctx context.Context, repo *catalog.Repository, ref, objectPath string) ([]byte, bool, error) {
+	entry, err := c.Catalog.GetEntry(ctx, repo.Name, ref, objectPath, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if entry.Size > diffContentMaxSize {
+		return nil, false, fmt.Errorf("%s: %d bytes, limit is %d: %w", objectPath, entry.Size, diffContentMaxSize, ErrObjectTooLargeToDiff)
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
 		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       fmt.Sprintf("%s/refs_manifest.json", c.Config.Committed.BlockStoragePrefix),
-	}, int64(len(manifestBytes)), bytes.NewReader(manifestBytes), block.PutOpts{})
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}, entry.Size)
 	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+		return nil, false, err
+	}
+	defer func() { _ = reader.Close() }()
+	content, err := io.ReadAll(io.LimitReader(reader, diffContentMaxSize))
+	if err != nil {
+		return nil, false, err
+	}
+	// the stored content type is often missing or generic, detect it from the data instead
+	if contentType := http.DetectContentType(content); !strings.HasPrefix(contentType, "text/") {
+		return nil, false, fmt.Errorf("%s: %s: %w", objectPath, contentType, ErrBinaryObjectDiff)
+	}
+	return content, true, nil
+}
+
+func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.LogCommitsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, ref),
+		},
+	}) {
 		return
 	}
-	writeResponse(w, r, http.StatusCreated, response)
+	ctx := r.Context()
+	c.LogAction(ctx, "get_branch_commit_log", r, repository, ref, "")
+
+	metadata, err := parseCommitSearchMetadata(params.Metadata)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// get commit log
+	commitLog, hasMore, err := c.Catalog.ListCommits(ctx, repository, ref, catalog.LogParams{
+		PathList:      resolvePathList(params.Objects, params.Prefixes),
+		FromReference: paginationAfter(params.After),
+		Amount:        paginationAmount(params.Amount),
+		Limit:         swag.BoolValue(params.Limit),
+		FirstParent:   swag.BoolValue(params.FirstParent),
+		Since:         params.Since,
+		StopAt:        swag.StringValue(params.StopAt),
+		Filter: catalog.CommitFilter{
+			Committer: swag.StringValue((*string)(params.Committer)),
+			Message:   swag.StringValue((*string)(params.Message)),
+			Metadata:  metadata,
+		},
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	serializedCommits := commitLogsToAPI(commitLog)
+	response := apigen.CommitList{
+		Pagination: paginationFor(hasMore, serializedCommits, "Id"),
+		Results:    serializedCommits,
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body apigen.RestoreRefsJSONRequestBody, repository string) {
+func (c *Controller) SearchCommits(w http.ResponseWriter, r *http.Request, repository string, params apigen.SearchCommitsParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.ListCommitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "search_commits", r, repository, "", "")
+
+	metadata, err := parseCommitSearchMetadata(params.Metadata)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	commits, next, err := c.Catalog.SearchCommits(ctx, repository, catalog.SearchCommitsParams{
+		After:  paginationAfter(params.After),
+		Amount: paginationAmount(params.Amount),
+		Filter: catalog.CommitFilter{
+			Committer: swag.StringValue((*string)(params.Committer)),
+			Message:   swag.StringValue((*string)(params.Message)),
+			Metadata:  metadata,
+			Since:     params.Since,
+			Until:     params.Until,
+		},
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	serializedCommits := commitLogsToAPI(commits)
+	response := apigen.CommitList{
+		Pagination: apigen.Pagination{
+			HasMore:    next != "",
+			NextOffset: next,
+			Results:    len(serializedCommits),
+			MaxPerPage: DefaultMaxPerPage,
+		},
+		Results: serializedCommits,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetCommitGraph(w http.ResponseWriter, r *http.Request, repository string, params apigen.GetCommitGraphParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListCommitsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "restore_repository_refs", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "get_commit_graph", r, repository, "", "")
+
+	var refs []string
+	if params.Refs != nil {
+		refs = *params.Refs
+	}
+	nodes, hasMore, err := c.Catalog.CommitGraph(ctx, repository, catalog.CommitGraphParams{
+		Refs:        refs,
+		After:       paginationAfter(params.After),
+		Amount:      paginationAmount(params.Amount),
+		FirstParent: swag.BoolValue(params.FirstParent),
+		Since:       params.Since,
+		Depth:       swag.IntValue(params.Depth),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// ensure no refs currently found
-	_, _, err = c.Catalog.ListCommits(ctx, repo.Name, repo.DefaultBranch, catalog.LogParams{
-		PathList:      make([]catalog.PathRecord, 0),
-		FromReference: "",
-		Amount:        1,
-	})
-	if !errors.Is(err, graveler.ErrNotFound) {
-		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
-		return
+	results := make([]apigen.CommitGraphNode, 0, len(nodes))
+	for _, node := range nodes {
+		// refs are listed as empty arrays rather than null for commits no ref points at
+		results = append(results, apigen.CommitGraphNode{
+			Id:           node.Reference,
+			Parents:      node.Parents,
+			Committer:    node.Committer,
+			Message:      node.Message,
+			CreationDate: node.CreationDate.Unix(),
+			Branches:     append([]string{}, node.Branches...),
+			Tags:         append([]string{}, node.Tags...),
+		})
+	}
+	response := apigen.CommitGraph{
+		Pagination: paginationFor(hasMore, results, "Id"),
+		Results:    results,
 	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	// load commits
-	err = c.Catalog.LoadCommits(ctx, repo.Name, body.CommitsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+// parseCommitSearchMetadata converts a list of key=value pairs into a metadata map
+func parseCommitSearchMetadata(pairs *apigen.CommitSearchMetadata) (map[string]string, error) {
+	if pairs == nil || len(*pairs) == 0 {
+		return nil, nil
 	}
+	metadata := make(map[string]string, len(*pairs))
+	for _, pair := range *pairs {
+		k, v, found := strings.Cut(pair, "=")
+		if !found || k == "" {
+			return nil, fmt.Errorf("metadata '%s': %w", pair, ErrInvalidKeyValuePair)
+		}
+		metadata[k] = v
+	}
+	return metadata, nil
+}
 
-	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+func commitLogsToAPI(commitLog []*catalog.CommitLog) []apigen.Commit {
+	serializedCommits := make([]apigen.Commit, 0, len(commitLog))
+	for _, commit := range commitLog {
+		metadata := apigen.Commit_Metadata{
+			AdditionalProperties: commit.Metadata,
+		}
+		serializedCommits = append(serializedCommits, apigen.Commit{
+			Committer:    commit.Committer,
+			Author:       commitAuthor(commit.Author),
+			CreationDate: commit.CreationDate.Unix(),
+			Id:           commit.Reference,
+			Message:      commit.Message,
+			Metadata:     &metadata,
+			MetaRangeId:  commit.MetaRangeID,
+			Parents:      commit.Parents,
+			Generation:   apiutil.Ptr(int64(commit.Generation)),
+			Stats:        commitStatsToAPI(commit.Stats),
+			Version:      apiutil.Ptr(int(commit.Version)),
+		})
 	}
+	return serializedCommits
+}
 
-	err = c.Catalog.LoadTags(ctx, repo.Name, body.TagsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+// getEntryOrPassThrough returns the entry of path on ref. Paths missing from ref are read through the pass-through
+// mappings of the repository, as the S3 gateway does.
+func (c *Controller) getEntryOrPassThrough(ctx context.Context, repository, ref, path string) (*catalog.DBEntry, error) {
+	entry, err := c.Catalog.GetEntry(ctx, repository, ref, path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		entry, err = c.Catalog.GetPassThroughEntry(ctx, repository, ref, path)
 	}
+	return entry, err
 }
 
-func (c *Controller) DumpSubmit(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+func (c *Controller) HeadObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.HeadObjectParams) {
+	if !c.authorizeCallback(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, params.Path),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, params.Path),
 		},
+	}, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
+		writeResponse(w, r, code, nil)
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "dump_repository", r, repository, "", "")
+	c.LogAction(ctx, "head_object", r, repository, ref, "")
 
-	taskID, err := c.Catalog.DumpRepositorySubmit(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	// read the FS entry
+	entry, err := c.getEntryOrPassThrough(ctx, repository, ref, params.Path)
+	if err != nil {
+		c.handleAPIErrorCallback(ctx, w, r, err, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
+			writeResponse(w, r, code, nil)
+		})
+		return
+	}
+	if entry.Expired {
+		writeResponse(w, r, http.StatusGone, nil)
 		return
 	}
 
-	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
-		Id: taskID,
-	})
+	etag := httputil.ETag(entry.Checksum)
+	w.Header().Set("ETag", etag)
+	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
+	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Type", entry.ContentType)
+	// for security, make sure the browser and any proxies en route don't cache the response
+	w.Header().Set("Cache-Control", "no-store, must-revalidate")
+	w.Header().Set("Expires", "0")
+
+	// calculate possible byte range, if any.
+	if params.Range != nil {
+		rng, err := httputil.ParseRange(*params.Range, entry.Size)
+		if err != nil {
+			writeResponse(w, r, http.StatusRequestedRangeNotSatisfiable, nil)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
+		writeResponse(w, r, http.StatusPartialContent, nil)
+	} else {
+		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+	}
 }
 
-func (c *Controller) DumpStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.DumpStatusParams) {
+func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, params.Path),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, params.Path),
 		},
 	}) {
 		return
 	}
-
-	// get the current status
 	ctx := r.Context()
-	status, err := c.Catalog.DumpRepositoryStatus(ctx, repository, params.TaskId)
+	c.LogAction(ctx, "get_object", r, repository, ref, "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// build response based on status
-	response := &apigen.RepositoryDumpStatus{
-		Id:         params.TaskId,
-		Done:       status.Task.Done,
-		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	// read the FS entry
+	entry, err := c.getEntryOrPassThrough(ctx, repository, ref, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	if status.Task.Error != "" {
-		response.Error = apiutil.Ptr(status.Task.Error)
+	c.Logger.Tracef("get repo %s ref %s path %s: %+v", repository, ref, params.Path, entry)
+	if entry.Expired {
+		writeError(w, r, http.StatusGone, "resource expired")
+		return
 	}
-	if status.Task.Done && status.Info != nil {
-		response.Refs = &apigen.RefsDump{
-			CommitsMetaRangeId:  status.Info.CommitsMetarangeId,
-			TagsMetaRangeId:     status.Info.TagsMetarangeId,
-			BranchesMetaRangeId: status.Info.BranchesMetarangeId,
+
+	// if pre-sign, return a redirect
+	pointer := block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}
+	if swag.BoolValue(params.Presign) {
+		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
 		}
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusFound)
+		return
 	}
-	writeResponse(w, r, http.StatusOK, response)
-}
 
-func (c *Controller) RestoreSubmit(w http.ResponseWriter, r *http.Request, body apigen.RestoreSubmitJSONRequestBody, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+	// set response headers
+	etag := httputil.ETag(entry.Checksum)
+	w.Header().Set("ETag", etag)
+	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
+	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Content-Type", entry.ContentType)
+	// for security, make sure the browser and any proxies en route don't cache the response
+	w.Header().Set("Cache-Control", "no-store, must-revalidate")
+	w.Header().Set("Expires", "0")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+
+	// handle partial response if byte range supplied
+	var reader io.ReadCloser
+	if params.Range != nil {
+		rng, err := httputil.ParseRange(*params.Range, entry.Size)
+		if err != nil {
+			writeError(w, r, http.StatusRequestedRangeNotSatisfiable, "Requested Range Not Satisfiable")
+			return
+		}
+		reader, err = c.BlockAdapter.GetRange(ctx, pointer, rng.StartOffset, rng.EndOffset)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		reader, err = c.BlockAdapter.Get(ctx, pointer, entry.Size)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+	}
+
+	// copy the content
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.
+			WithError(err).
+			WithFields(logging.Fields{
+				"storage_namespace": repo.StorageNamespace,
+				"physical_address":  entry.PhysicalAddress,
+			}).
+			Debug("GetObject copy content")
+	}
+}
+
+func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "restore_repository", r, repository, "", "")
+	user, _ := auth.GetUser(ctx)
+	c.LogAction(ctx, "list_objects", r, repository, ref, "")
 
-	info := &catalog.RepositoryDumpInfo{
-		CommitsMetarangeId:  body.CommitsMetaRangeId,
-		TagsMetarangeId:     body.TagsMetaRangeId,
-		BranchesMetarangeId: body.BranchesMetaRangeId,
-	}
-	taskID, err := c.Catalog.RestoreRepositorySubmit(ctx, repository, info, graveler.WithForce(swag.BoolValue(body.Force)))
-	if errors.Is(err, catalog.ErrNonEmptyRepository) {
-		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+
+	var (
+		res     []*catalog.DBEntry
+		hasMore bool
+	)
+	if params.StorageClass != nil {
+		res, hasMore, err = c.Catalog.ListEntriesByStorageClass(
+			ctx,
+			repository,
+			ref,
+			paginationPrefix(params.Prefix),
+			paginationAfter(params.After),
+			paginationDelimiter(params.Delimiter),
+			*params.StorageClass,
+			paginationAmount(params.Amount),
+		)
+	} else {
+		res, hasMore, err = c.Catalog.ListEntries(
+			ctx,
+			repository,
+			ref,
+			paginationPrefix(params.Prefix),
+			paginationAfter(params.After),
+			paginationDelimiter(params.Delimiter),
+			paginationAmount(params.Amount),
+		)
+	}
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
-		Id: taskID,
-	})
+
+	objList := make([]apigen.ObjectStats, 0, len(res))
+	for _, entry := range res {
+		qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if entry.CommonLevel {
+			objList = append(objList, apigen.ObjectStats{
+				Path:     entry.Path,
+				PathType: entryTypeCommonPrefix,
+			})
+		} else {
+			var mtime int64
+			if !entry.CreationDate.IsZero() {
+				mtime = entry.CreationDate.Unix()
+			}
+			objStat := apigen.ObjectStats{
+				Checksum:        entry.Checksum,
+				Mtime:           mtime,
+				Path:            entry.Path,
+				PhysicalAddress: qk.Format(),
+				PathType:        entryTypeObject,
+				SizeBytes:       swag.Int64(entry.Size),
+				ContentType:     swag.String(entry.ContentType),
+				Retention:       objectRetentionToAPI(entry.Retention),
+				StorageClass:    storageClassToAPI(entry.StorageClass),
+				Source:          objectSourceToAPI(entry.Source),
+			}
+			if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
+				objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
+			}
+			if swag.BoolValue(params.Presign) {
+				// check if the user has read permissions for this object
+				tags := entry.Tags
+				readPermission := permissions.Node{
+					Permission: permissions.Permission{
+						Action:   permissions.ReadObjectAction,
+						Resource: permissions.ObjectArn(repository, entry.Path),
+						ConditionValues: permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+							return tags, nil
+						}),
+					},
+				}
+				authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+					Username:            user.Username,
+					RequiredPermissions: readPermission,
+				})
+				if c.handleAPIError(ctx, w, r, err) {
+					return
+				}
+				if authResponse.Allowed && withinAccessTokenScope(ctx, readPermission) {
+					var expiry time.Time
+					objStat.PhysicalAddress, expiry, err = c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+						StorageNamespace: repo.StorageNamespace,
+						IdentifierType:   entry.AddressType.ToIdentifierType(),
+						Identifier:       entry.PhysicalAddress,
+					}, block.PreSignModeRead)
+					if c.handleAPIError(ctx, w, r, err) {
+						return
+					}
+					if !expiry.IsZero() {
+						objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+					}
+				}
+			}
+			objList = append(objList, objStat)
+		}
+	}
+	response := apigen.ObjectStatsList{
+		Pagination: apigen.Pagination{
+			HasMore:    hasMore,
+			MaxPerPage: DefaultMaxPerPage,
+			Results:    len(objList),
+		},
+		Results: objList,
+	}
+	if len(objList) > 0 && hasMore {
+		lastObj := objList[len(objList)-1]
+		response.Pagination.NextOffset = lastObj.Path
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) RestoreStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.RestoreStatusParams) {
+func (c *Controller) SampleObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.SampleObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, params.Path),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, params.Path),
 		},
 	}) {
 		return
 	}
-
-	// get the current status
 	ctx := r.Context()
-	status, err := c.Catalog.RestoreRepositoryStatus(ctx, repository, params.TaskId)
+	c.LogAction(ctx, "sample_object", r, repository, ref, "")
+
+	sampleParams := catalog.SampleParams{
+		Format: catalog.SampleFormat(swag.StringValue(params.Format)),
+		Mode:   catalog.SampleModeHead,
+		Limit:  catalog.DefaultSampleLimit,
+	}
+	if params.Mode != nil {
+		sampleParams.Mode = catalog.SampleMode(*params.Mode)
+	}
+	if params.Limit != nil {
+		sampleParams.Limit = *params.Limit
+	}
+	sample, err := c.Catalog.SampleEntry(ctx, repository, ref, params.Path, sampleParams)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// build response based on status
-	response := &apigen.RepositoryRestoreStatus{
-		Id:         params.TaskId,
-		Done:       status.Task.Done,
-		UpdateTime: status.Task.UpdatedAt.AsTime(),
-	}
-	if status.Task.Error != "" {
-		response.Error = apiutil.Ptr(status.Task.Error)
+	rows := make([]apigen.ObjectSampleRow, 0, len(sample.Rows))
+	for _, row := range sample.Rows {
+		rows = append(rows, apigen.ObjectSampleRow{AdditionalProperties: row})
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusOK, apigen.ObjectSample{
+		Format:  string(sample.Format),
+		Columns: sample.Columns,
+		Rows:    rows,
+	})
 }
 
-func (c *Controller) CreateSymlinkFile(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CreateSymlinkFileParams) {
+func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.StatObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, branch),
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, params.Path),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, params.Path),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_symlink", r, repository, branch, "")
+	c.LogAction(ctx, "stat_object", r, repository, ref, "")
 
-	// read repo
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// list entries
-	var currentPath string
-	var currentAddresses []string
-	var after string
-	var entries []*catalog.DBEntry
-	hasMore := true
-	for hasMore {
-		entries, hasMore, err = c.Catalog.ListEntries(
-			ctx,
-			repository,
-			branch,
-			swag.StringValue(params.Location),
-			after,
-			"",
-			-1)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		// loop all entries enter to map[path] physicalAddress
-		for _, entry := range entries {
-			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
-			if err != nil {
-				writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while resolving address: %s", err))
-				return
-			}
-			idx := strings.LastIndex(entry.Path, "/")
-			var path string
-			if idx != -1 {
-				path = entry.Path[0:idx]
-			}
-			if path != currentPath {
-				// push current
-				err := writeSymlink(ctx, repo, branch, path, currentAddresses, c.BlockAdapter)
-				if err != nil {
-					writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
-					return
-				}
-				currentPath = path
-				currentAddresses = []string{qk.Format()}
-			} else {
-				currentAddresses = append(currentAddresses, qk.Format())
-			}
-		}
-		after = entries[len(entries)-1].Path
+	entry, err := c.getEntryOrPassThrough(ctx, repository, ref, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	if len(currentAddresses) > 0 {
-		err = writeSymlink(ctx, repo, branch, currentPath, currentAddresses, c.BlockAdapter)
-		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
-			return
-		}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	metaLocation := fmt.Sprintf("%s/%s", repo.StorageNamespace, lakeFSPrefix)
-	response := apigen.StorageURI{
-		Location: metaLocation,
+
+	objStat := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Retention:       objectRetentionToAPI(entry.Retention),
+		StorageClass:    storageClassToAPI(entry.StorageClass),
+		Tags:            objectTagsToAPI(entry.Tags),
+		Source:          objectSourceToAPI(entry.Source),
 	}
-	writeResponse(w, r, http.StatusCreated, response)
-}
 
-func writeSymlink(ctx context.Context, repo *catalog.Repository, branch, path string, addresses []string, adapter block.Adapter) error {
-	address := fmt.Sprintf("%s/%s/%s/%s/symlink.txt", lakeFSPrefix, repo.Name, branch, path)
-	data := strings.Join(addresses, "\n")
-	err := adapter.Put(ctx, block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       address,
-	}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
-	return err
+	// add metadata if requested
+	var metadata map[string]string
+	if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
+		metadata = entry.Metadata
+	} else {
+		metadata = map[string]string{}
+	}
+	objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: metadata}
+
+	code := http.StatusOK
+	if entry.Expired {
+		code = http.StatusGone
+	} else if swag.BoolValue(params.Presign) {
+		// need to pre-sign the physical address
+		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}, block.PreSignModeRead)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		objStat.PhysicalAddress = preSignedURL
+		if !expiry.IsZero() {
+			objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+		}
+	}
+	writeResponse(w, r, code, objStat)
 }
 
-func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsParams) {
+func (c *Controller) GetObjectTree(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectTreeParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ListObjectsAction,
@@ -4024,266 +7159,326 @@ func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "diff_refs", r, repository, rightRef, leftRef)
-	diffFunc := c.Catalog.Compare // default diff type is three-dot
-	if params.Type != nil && *params.Type == "two_dot" {
-		diffFunc = c.Catalog.Diff
-	}
+	c.LogAction(ctx, "get_object_tree", r, repository, ref, "")
 
-	diff, hasMore, err := diffFunc(ctx, repository, leftRef, rightRef, catalog.DiffParams{
-		Limit:            paginationAmount(params.Amount),
-		After:            paginationAfter(params.After),
-		Prefix:           paginationPrefix(params.Prefix),
-		Delimiter:        paginationDelimiter(params.Delimiter),
-		AdditionalFields: nil,
-	})
-	if c.handleAPIError(ctx, w, r, err) {
+	delimiter := swag.StringValue(params.Delimiter)
+	if delimiter == "" {
+		delimiter = DefaultTreeDelimiter
+	}
+	depth := DefaultTreeDepth
+	if params.Depth != nil {
+		depth = *params.Depth
+	}
+	if depth < 0 {
+		writeError(w, r, http.StatusBadRequest, "depth must not be negative")
 		return
 	}
-	results := make([]apigen.Diff, 0, len(diff))
-	for _, d := range diff {
-		pathType := entryTypeObject
-		if d.CommonLevel {
-			pathType = entryTypeCommonPrefix
-		}
-		diff := apigen.Diff{
-			Path:     d.Path,
-			Type:     transformDifferenceTypeToString(d.Type),
-			PathType: pathType,
-		}
-		if !d.CommonLevel {
-			diff.SizeBytes = swag.Int64(d.Size)
-		}
-		results = append(results, diff)
+	tree, truncated, err := c.Catalog.GetTree(ctx, repository, ref, paginationPrefix(params.Prefix), delimiter, depth)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	response := apigen.DiffList{
-		Pagination: paginationFor(hasMore, results, "Path"),
-		Results:    results,
+	response := treeNodeToAPI(tree)
+	if truncated {
+		response.Truncated = swag.Bool(true)
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.LogCommitsParams) {
+func (c *Controller) ListObjectComments(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectCommentsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, ref),
+			Action:   permissions.ListObjectCommentsAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_branch_commit_log", r, repository, ref, "")
+	c.LogAction(ctx, "list_object_comments", r, repository, ref, "")
 
-	// get commit log
-	commitLog, hasMore, err := c.Catalog.ListCommits(ctx, repository, ref, catalog.LogParams{
-		PathList:      resolvePathList(params.Objects, params.Prefixes),
-		FromReference: paginationAfter(params.After),
-		Amount:        paginationAmount(params.Amount),
-		Limit:         swag.BoolValue(params.Limit),
-		FirstParent:   swag.BoolValue(params.FirstParent),
-		Since:         params.Since,
-		StopAt:        swag.StringValue(params.StopAt),
-	})
+	comments, hasMore, err := c.Catalog.ListObjectComments(ctx, repository, params.Path, paginationAfter(params.After), paginationAmount(params.Amount))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	serializedCommits := make([]apigen.Commit, 0, len(commitLog))
-	for _, commit := range commitLog {
-		metadata := apigen.Commit_Metadata{
-			AdditionalProperties: commit.Metadata,
-		}
-		serializedCommits = append(serializedCommits, apigen.Commit{
-			Committer:    commit.Committer,
-			CreationDate: commit.CreationDate.Unix(),
-			Id:           commit.Reference,
-			Message:      commit.Message,
-			Metadata:     &metadata,
-			MetaRangeId:  commit.MetaRangeID,
-			Parents:      commit.Parents,
-			Generation:   apiutil.Ptr(int64(commit.Generation)),
-			Version:      apiutil.Ptr(int(commit.Version)),
-		})
+	response := apigen.ObjectCommentList{
+		Results: make([]apigen.ObjectComment, 0, len(comments)),
+		Pagination: apigen.Pagination{
+			HasMore: hasMore,
+			Results: len(comments),
+		},
 	}
-
-	response := apigen.CommitList{
-		Pagination: paginationFor(hasMore, serializedCommits, "Id"),
-		Results:    serializedCommits,
+	for _, comment := range comments {
+		response.Results = append(response.Results, serializeObjectComment(comment))
+	}
+	if hasMore {
+		response.Pagination.NextOffset = comments[len(comments)-1].Id
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) HeadObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.HeadObjectParams) {
-	if !c.authorizeCallback(w, r, permissions.Node{
+func (c *Controller) CreateObjectComment(w http.ResponseWriter, r *http.Request, body apigen.CreateObjectCommentJSONRequestBody, repository, ref string, params apigen.CreateObjectCommentParams) {
+	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadObjectAction,
+			Action:   permissions.CreateObjectCommentAction,
 			Resource: permissions.ObjectArn(repository, params.Path),
 		},
-	}, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
-		writeResponse(w, r, code, nil)
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "head_object", r, repository, ref, "")
-
-	// read the FS entry
-	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
+	c.LogAction(ctx, "create_object_comment", r, repository, ref, "")
+	user, err := auth.GetUser(ctx)
 	if err != nil {
-		c.handleAPIErrorCallback(ctx, w, r, err, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
-			writeResponse(w, r, code, nil)
-		})
+		writeError(w, r, http.StatusUnauthorized, "missing user")
 		return
 	}
-	if entry.Expired {
-		writeResponse(w, r, http.StatusGone, nil)
+
+	comment, err := c.Catalog.CreateObjectComment(ctx, repository, ref, params.Path, user.Username, body.Body, swag.StringValue(body.ParentId))
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusCreated, serializeObjectComment(comment))
+}
 
-	etag := httputil.ETag(entry.Checksum)
-	w.Header().Set("ETag", etag)
-	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
-	w.Header().Set("Last-Modified", lastModified)
-	w.Header().Set("Accept-Ranges", "bytes")
-	w.Header().Set("Content-Type", entry.ContentType)
-	// for security, make sure the browser and any proxies en route don't cache the response
-	w.Header().Set("Cache-Control", "no-store, must-revalidate")
-	w.Header().Set("Expires", "0")
-
-	// calculate possible byte range, if any.
-	if params.Range != nil {
-		rng, err := httputil.ParseRange(*params.Range, entry.Size)
-		if err != nil {
-			writeResponse(w, r, http.StatusRequestedRangeNotSatisfiable, nil)
-			return
-		}
-		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
-		writeResponse(w, r, http.StatusPartialContent, nil)
-	} else {
-		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+func serializeObjectComment(comment *catalog.ObjectComment) apigen.ObjectComment {
+	result := apigen.ObjectComment{
+		Id:           comment.Id,
+		Path:         comment.Path,
+		Ref:          comment.Ref,
+		CommitId:     comment.CommitId,
+		Author:       comment.Author,
+		CreationDate: comment.CreationDate.AsTime().Unix(),
+		Body:         comment.Body,
+	}
+	if comment.ParentId != "" {
+		result.ParentId = apiutil.Ptr(comment.ParentId)
 	}
+	return result
 }
 
-func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectParams) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
-		},
-	}) {
+func (c *Controller) SearchObjects(w http.ResponseWriter, r *http.Request, params apigen.SearchObjectsParams) {
+	if c.Search == nil {
+		writeError(w, r, http.StatusNotImplemented, "Search is disabled")
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_object", r, repository, ref, "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return
 	}
-
-	// read the FS entry
-	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	c.LogAction(ctx, "search_objects", r, swag.StringValue(params.Repository), swag.StringValue(params.Branch), "")
+
+	// results are returned only from repositories the user may list objects of
+	allowed := make(map[string]bool)
+	var authErr error
+	docs, next, err := c.Search.Query(ctx, search.QueryParams{
+		Query:      params.Q,
+		Repository: swag.StringValue(params.Repository),
+		Branch:     swag.StringValue(params.Branch),
+		After:      paginationAfter(params.After),
+		Limit:      paginationAmount(params.Amount),
+		Allowed: func(repository string) bool {
+			if ok, found := allowed[repository]; found {
+				return ok
+			}
+			perms := permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			}
+			resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+				Username:            user.Username,
+				RequiredPermissions: perms,
+			})
+			if err != nil {
+				authErr = err
+				return false
+			}
+			ok := resp.Error == nil && resp.Allowed && withinAccessTokenScope(ctx, perms)
+			if ok {
+				// skip the results of repositories deleted since they were indexed
+				_, err := c.Catalog.GetRepository(ctx, repository)
+				ok = err == nil
+			}
+			allowed[repository] = ok
+			return ok
+		},
+	})
+	if err == nil {
+		err = authErr
 	}
-	c.Logger.Tracef("get repo %s ref %s path %s: %+v", repository, ref, params.Path, entry)
-	if entry.Expired {
-		writeError(w, r, http.StatusGone, "resource expired")
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// if pre-sign, return a redirect
-	pointer := block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   entry.AddressType.ToIdentifierType(),
-		Identifier:       entry.PhysicalAddress,
-	}
-	if swag.BoolValue(params.Presign) {
-		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		w.Header().Set("Location", location)
-		w.WriteHeader(http.StatusFound)
-		return
+	response := apigen.SearchResultList{
+		Results: make([]apigen.SearchResult, 0, len(docs)),
+		Pagination: apigen.Pagination{
+			HasMore:    next != "",
+			NextOffset: next,
+			MaxPerPage: DefaultMaxPerPage,
+			Results:    len(docs),
+		},
 	}
-
-	// set response headers
-	etag := httputil.ETag(entry.Checksum)
-	w.Header().Set("ETag", etag)
-	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
-	w.Header().Set("Last-Modified", lastModified)
-	w.Header().Set("Content-Type", entry.ContentType)
-	// for security, make sure the browser and any proxies en route don't cache the response
-	w.Header().Set("Cache-Control", "no-store, must-revalidate")
-	w.Header().Set("Expires", "0")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
-	w.Header().Set("Content-Security-Policy", "default-src 'none'")
-
-	// handle partial response if byte range supplied
-	var reader io.ReadCloser
-	if params.Range != nil {
-		rng, err := httputil.ParseRange(*params.Range, entry.Size)
-		if err != nil {
-			writeError(w, r, http.StatusRequestedRangeNotSatisfiable, "Requested Range Not Satisfiable")
-			return
+	for _, doc := range docs {
+		result := apigen.SearchResult{
+			Repository: doc.Repository,
+			Branch:     doc.Branch,
+			Path:       doc.Path,
+			CommitId:   doc.CommitId,
 		}
-		reader, err = c.BlockAdapter.GetRange(ctx, pointer, rng.StartOffset, rng.EndOffset)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
+		if len(doc.Metadata) > 0 {
+			result.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: doc.Metadata}
 		}
-		defer func() {
-			_ = reader.Close()
-		}()
-		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
-		w.WriteHeader(http.StatusPartialContent)
-	} else {
-		reader, err = c.BlockAdapter.Get(ctx, pointer, entry.Size)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
+		if doc.LastModified != nil {
+			result.LastModified = apiutil.Ptr(doc.LastModified.AsTime().Unix())
 		}
-		defer func() {
-			_ = reader.Close()
-		}()
-		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+		response.Results = append(response.Results, result)
 	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	// copy the content
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		c.Logger.
-			WithError(err).
-			WithFields(logging.Fields{
-				"storage_namespace": repo.StorageNamespace,
-				"physical_address":  entry.PhysicalAddress,
-			}).
-			Debug("GetObject copy content")
+func (c *Controller) ListDatasets(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListDatasetsParams) {
+	prefix := paginationPrefix(params.Prefix)
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, prefix+"*"),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_datasets", r, repository, ref, "")
+
+	datasets, nextOffset, hasMore, err := c.Catalog.ListDatasets(ctx, repository, ref, prefix, paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.DatasetList{
+		Results: make([]apigen.Dataset, 0, len(datasets)),
+		Pagination: apigen.Pagination{
+			HasMore:    hasMore,
+			MaxPerPage: DefaultMaxPerPage,
+			NextOffset: nextOffset,
+			Results:    len(datasets),
+		},
+	}
+	user, _ := auth.GetUser(ctx)
+	for _, dataset := range datasets {
+		// omit datasets whose descriptor the user may not read, as policy conditions on its tags deny it
+		readPermission := permissions.Node{
+			Permission: permissions.Permission{
+				Action:          permissions.ReadObjectAction,
+				Resource:        permissions.ObjectArn(repository, dataset.Path),
+				ConditionValues: c.objectTagsConditionValues(ctx, repository, ref, dataset.Path),
+			},
+		}
+		authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+			Username:            user.Username,
+			RequiredPermissions: readPermission,
+		})
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		if !authResponse.Allowed {
+			continue
+		}
+		response.Results = append(response.Results, serializeDataset(dataset))
 	}
+	response.Pagination.Results = len(response.Results)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectsParams) {
+func (c *Controller) GetDataset(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetDatasetParams) {
+	descriptorPath := params.Prefix + catalog.DatasetDescriptorFilename
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListObjectsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, descriptorPath),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, descriptorPath),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	user, _ := auth.GetUser(ctx)
-	c.LogAction(ctx, "list_objects", r, repository, ref, "")
+	c.LogAction(ctx, "get_dataset", r, repository, ref, "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	dataset, err := c.Catalog.GetDataset(ctx, repository, ref, params.Prefix)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, serializeDataset(dataset))
+}
+
+func serializeDataset(dataset *catalog.DatasetDescriptor) apigen.Dataset {
+	result := apigen.Dataset{
+		Path:   dataset.Path,
+		Prefix: dataset.Prefix,
+	}
+	if dataset.Error != "" {
+		result.Error = apiutil.Ptr(dataset.Error)
+		return result
+	}
+	result.Name = apiutil.Ptr(dataset.Name)
+	result.Owner = apiutil.Ptr(dataset.Owner)
+	if dataset.Description != "" {
+		result.Description = apiutil.Ptr(dataset.Description)
+	}
+	if dataset.Schema != "" {
+		result.Schema = apiutil.Ptr(dataset.Schema)
+	}
+	if dataset.SLA != nil {
+		result.Sla = &apigen.DatasetSLA{}
+		if dataset.SLA.Freshness != "" {
+			result.Sla.Freshness = apiutil.Ptr(dataset.SLA.Freshness)
+		}
+		if dataset.SLA.Contact != "" {
+			result.Sla.Contact = apiutil.Ptr(dataset.SLA.Contact)
+		}
+	}
+	if len(dataset.Tags) > 0 {
+		result.Tags = &apigen.Dataset_Tags{AdditionalProperties: dataset.Tags}
+	}
+	return result
+}
+
+func (c *Controller) AnnotateObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.AnnotateObjectsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadBranchAction,
+					Resource: permissions.BranchArn(repository, ref),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "annotate_objects", r, repository, ref, "")
 
-	res, hasMore, err := c.Catalog.ListEntries(
+	res, hasMore, err := c.Catalog.Annotate(
 		ctx,
 		repository,
 		ref,
@@ -4291,160 +7486,116 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 		paginationAfter(params.After),
 		paginationDelimiter(params.Delimiter),
 		paginationAmount(params.Amount),
+		swag.BoolValue(params.FirstParent),
 	)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	objList := make([]apigen.ObjectStats, 0, len(res))
+	results := make([]apigen.AnnotatedObject, 0, len(res))
 	for _, entry := range res {
-		qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
-		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
+		obj := apigen.AnnotatedObject{
+			Path:     entry.Path,
+			PathType: entryTypeObject,
 		}
-
 		if entry.CommonLevel {
-			objList = append(objList, apigen.ObjectStats{
-				Path:     entry.Path,
-				PathType: entryTypeCommonPrefix,
-			})
-		} else {
-			var mtime int64
-			if !entry.CreationDate.IsZero() {
-				mtime = entry.CreationDate.Unix()
-			}
-			objStat := apigen.ObjectStats{
-				Checksum:        entry.Checksum,
-				Mtime:           mtime,
-				Path:            entry.Path,
-				PhysicalAddress: qk.Format(),
-				PathType:        entryTypeObject,
-				SizeBytes:       swag.Int64(entry.Size),
-				ContentType:     swag.String(entry.ContentType),
-			}
-			if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-				objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
-			}
-			if swag.BoolValue(params.Presign) {
-				// check if the user has read permissions for this object
-				authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
-					Username: user.Username,
-					RequiredPermissions: permissions.Node{
-						Permission: permissions.Permission{
-							Action:   permissions.ReadObjectAction,
-							Resource: permissions.ObjectArn(repository, entry.Path),
-						},
-					},
-				})
-				if c.handleAPIError(ctx, w, r, err) {
-					return
-				}
-				if authResponse.Allowed {
-					var expiry time.Time
-					objStat.PhysicalAddress, expiry, err = c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-						StorageNamespace: repo.StorageNamespace,
-						IdentifierType:   entry.AddressType.ToIdentifierType(),
-						Identifier:       entry.PhysicalAddress,
-					}, block.PreSignModeRead)
-					if c.handleAPIError(ctx, w, r, err) {
-						return
-					}
-					if !expiry.IsZero() {
-						objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
-					}
-				}
-			}
-			objList = append(objList, objStat)
+			obj.PathType = entryTypeCommonPrefix
 		}
+		if entry.Commit != nil {
+			obj.Commit = &commitLogsToAPI([]*catalog.CommitLog{entry.Commit})[0]
+		}
+		results = append(results, obj)
 	}
-	response := apigen.ObjectStatsList{
-		Pagination: apigen.Pagination{
-			HasMore:    hasMore,
-			MaxPerPage: DefaultMaxPerPage,
-			Results:    len(objList),
-		},
-		Results: objList,
-	}
-	if len(objList) > 0 && hasMore {
-		lastObj := objList[len(objList)-1]
-		response.Pagination.NextOffset = lastObj.Path
-	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusOK, apigen.AnnotatedObjectList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
+	})
 }
 
-func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.StatObjectParams) {
+func (c *Controller) GetObjectsRestoreStatus(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectsRestoreStatusParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "stat_object", r, repository, ref, "")
+	c.LogAction(ctx, "get_objects_restore_status", r, repository, ref, "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	res, hasMore, err := c.Catalog.RestoreEntries(ctx, repository, ref, paginationPrefix(params.Prefix), paginationAfter(params.After), paginationAmount(params.Amount), nil)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, objectRestoreListToAPI(res, hasMore))
+}
 
-	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) RestoreObjects(w http.ResponseWriter, r *http.Request, body apigen.RestoreObjectsJSONRequestBody, repository, ref string, params apigen.RestoreObjectsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RestoreObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
 		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_objects", r, repository, ref, "")
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	opts := &block.RestoreOpts{
+		Days: body.Days,
+		Tier: swag.StringValue(body.Tier),
+	}
+	res, hasMore, err := c.Catalog.RestoreEntries(ctx, repository, ref, paginationPrefix(params.Prefix), paginationAfter(params.After), paginationAmount(params.Amount), opts)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, objectRestoreListToAPI(res, hasMore))
+}
 
-	objStat := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
+func objectRestoreListToAPI(res []*catalog.EntryRestore, hasMore bool) apigen.ObjectRestoreList {
+	results := make([]apigen.ObjectRestoreStatus, 0, len(res))
+	for _, entry := range res {
+		status := apigen.ObjectRestoreStatus{
+			Path:         entry.Path,
+			StorageClass: entry.StorageClass,
+			Status:       entry.Status,
+		}
+		if !entry.Expiry.IsZero() {
+			status.Expiry = swag.Int64(entry.Expiry.Unix())
+		}
+		results = append(results, status)
 	}
-
-	// add metadata if requested
-	var metadata map[string]string
-	if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-		metadata = entry.Metadata
-	} else {
-		metadata = map[string]string{}
+	return apigen.ObjectRestoreList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
 	}
-	objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: metadata}
+}
 
-	code := http.StatusOK
-	if entry.Expired {
-		code = http.StatusGone
-	} else if swag.BoolValue(params.Presign) {
-		// need to pre-sign the physical address
-		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-			StorageNamespace: repo.StorageNamespace,
-			IdentifierType:   entry.AddressType.ToIdentifierType(),
-			Identifier:       entry.PhysicalAddress,
-		}, block.PreSignModeRead)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		objStat.PhysicalAddress = preSignedURL
-		if !expiry.IsZero() {
-			objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+func treeNodeToAPI(node *catalog.TreeNode) apigen.ObjectTree {
+	res := apigen.ObjectTree{
+		Path:        node.Path,
+		ChildCount:  node.ChildCount,
+		ObjectCount: node.ObjectCount,
+		SizeBytes:   node.SizeBytes,
+	}
+	if len(node.Children) > 0 {
+		children := make([]apigen.ObjectTree, 0, len(node.Children))
+		for _, child := range node.Children {
+			children = append(children, treeNodeToAPI(child))
 		}
+		res.Children = &children
 	}
-	writeResponse(w, r, code, objStat)
+	return res
 }
 
 func (c *Controller) GetUnderlyingProperties(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetUnderlyingPropertiesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:          permissions.ReadObjectAction,
+			Resource:        permissions.ObjectArn(repository, params.Path),
+			ConditionValues: c.objectTagsConditionValues(r.Context(), repository, ref, params.Path),
 		},
 	}) {
 		return
@@ -4507,7 +7658,8 @@ func (c *Controller) MergeIntoBranch(w http.ResponseWriter, r *http.Request, bod
 		swag.StringValue(body.Message),
 		metadata,
 		swag.StringValue(body.Strategy),
-		graveler.WithForce(swag.BoolValue(body.Force)))
+		graveler.WithForce(swag.BoolValue(body.Force)),
+		graveler.WithFastForwardOnly(swag.BoolValue(body.FastForwardOnly)))
 
 	if errors.Is(err, graveler.ErrConflictFound) {
 		writeResponse(w, r, http.StatusConflict, apigen.MergeResult{
@@ -4546,6 +7698,31 @@ func (c *Controller) FindMergeBase(w http.ResponseWriter, r *http.Request, repos
 	})
 }
 
+func (c *Controller) GetMergeBase(w http.ResponseWriter, r *http.Request, repository string, leftRef string, rightRef string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListCommitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_merge_base", r, repository, leftRef, rightRef)
+
+	mergeBase, err := c.Catalog.GetMergeBase(ctx, repository, leftRef, rightRef)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apigen.MergeBaseResult{
+		LeftCommitId:  mergeBase.LeftCommitID,
+		RightCommitId: mergeBase.RightCommitID,
+		BaseCommitId:  mergeBase.BaseCommitID,
+		Ahead:         mergeBase.Ahead,
+		Behind:        mergeBase.Behind,
+	})
+}
+
 func (c *Controller) ListTags(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListTagsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -5048,6 +8225,15 @@ func writeError(w http.ResponseWriter, r *http.Request, code int, v interface{})
 	writeResponse(w, r, code, apiErr)
 }
 
+// writeStorageNamespaceError writes a bad request response for a storage namespace that cannot be used, with the
+// reason for it
+func writeStorageNamespaceError(w http.ResponseWriter, r *http.Request, err error, reason string) {
+	writeResponse(w, r, http.StatusBadRequest, apigen.StorageNamespaceError{
+		Message: err.Error(),
+		Reason:  apiutil.Ptr(reason),
+	})
+}
+
 func writeResponse(w http.ResponseWriter, r *http.Request, code int, response interface{}) {
 	// check first if the client canceled the request
 	if httputil.IsRequestCanceled(r) {
@@ -5132,12 +8318,14 @@ func resolvePathList(objects, prefixes *[]string) []catalog.PathRecord {
 	return pathRecords
 }
 
-func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, sessionStore sessions.Store, pathProvider upload.PathProvider, otfDiffService *tablediff.Service, usageReporter stats.UsageReporterOperations) *Controller {
+func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, lockout *auth.Lockout, usage *auth.CredentialsUsage, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, sessionStore sessions.Store, pathProvider upload.PathProvider, otfDiffService *tablediff.Service, usageReporter stats.UsageReporterOperations, jobsRunner *jobs.Runner, searchService *search.Service) *Controller {
 	return &Controller{
 		Config:                cfg,
 		Catalog:               catalog,
 		Authenticator:         authenticator,
 		Auth:                  authService,
+		Lockout:               lockout,
+		CredentialsUsage:      usage,
 		BlockAdapter:          blockAdapter,
 		MetadataManager:       metadataManager,
 		Migrator:              migrator,
@@ -5150,6 +8338,8 @@ func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator a
 		PathProvider:          pathProvider,
 		otfDiffService:        otfDiffService,
 		usageReporter:         usageReporter,
+		jobsRunner:            jobsRunner,
+		Search:                searchService,
 	}
 }
 
@@ -5221,6 +8411,10 @@ func (c *Controller) authorizeCallback(w http.ResponseWriter, r *http.Request, p
 		cb(w, r, http.StatusInternalServerError, err)
 		return false
 	}
+	if resp.Error != nil || !resp.Allowed {
+		auth.RecordDenial(ctx, "api", user.Username, resp.Denial)
+		c.explainDenial(w, r, user.Username, resp.Denial)
+	}
 	if resp.Error != nil {
 		cb(w, r, http.StatusUnauthorized, resp.Error)
 		return false
@@ -5229,9 +8423,49 @@ func (c *Controller) authorizeCallback(w http.ResponseWriter, r *http.Request, p
 		cb(w, r, http.StatusInternalServerError, "User does not have the required permissions")
 		return false
 	}
+	if !withinAccessTokenScope(ctx, perms) {
+		cb(w, r, http.StatusUnauthorized, auth.ErrInsufficientPermissions)
+		return false
+	}
 	return true
 }
 
+// explainDenial sets the explanation of the denial of a request of username in a response header, if the user is
+// allowed to simulate their own authorization
+func (c *Controller) explainDenial(w http.ResponseWriter, r *http.Request, username string, denial *auth.Denial) {
+	if denial == nil {
+		return
+	}
+	resp, err := c.Auth.Authorize(r.Context(), &auth.AuthorizationRequest{
+		Username: username,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.SimulateAuthorizationAction,
+				Resource: permissions.UserArn(username),
+			},
+		},
+	})
+	if err != nil || !resp.Allowed {
+		return
+	}
+	w.Header().Set(apiutil.LakeFSHeaderAuthorizationDenial, denial.String())
+}
+
+// withinAccessTokenScope returns false if the request was authenticated by a personal access
+// token whose scope does not include perms
+func withinAccessTokenScope(ctx context.Context, perms permissions.Node) bool {
+	token, ok := auth.GetAccessToken(ctx)
+	return !ok || auth.AccessTokenAllows(ctx, token, perms)
+}
+
+// objectTagsConditionValues returns the condition values of the tags of the object at path on ref, matched by policy
+// conditions on object tags
+func (c *Controller) objectTagsConditionValues(ctx context.Context, repository, ref, path string) func() (map[string]string, error) {
+	return permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+		return c.Catalog.GetEntryTags(ctx, repository, ref, path)
+	})
+}
+
 func (c *Controller) authorize(w http.ResponseWriter, r *http.Request, perms permissions.Node) bool {
 	return c.authorizeCallback(w, r, perms, writeError)
 }
@@ -5293,6 +8527,84 @@ func extractLakeFSMetadata(header http.Header) map[string]string {
 	return meta
 }
 
+func (c *Controller) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_jobs", r, "", "", "")
+	statuses, err := c.jobsRunner.ListJobs(ctx)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := apigen.JobList{Results: make([]apigen.Job, 0, len(statuses))}
+	for _, status := range statuses {
+		job := apigen.Job{
+			Name:     status.Name,
+			Schedule: status.Schedule,
+		}
+		if !status.NextRun.IsZero() {
+			job.NextRun = apiutil.Ptr(status.NextRun.Unix())
+		}
+		if status.LastRun != nil {
+			job.LastRun = apiutil.Ptr(jobRunToAPI(status.LastRun))
+		}
+		response.Results = append(response.Results, job)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListJobRuns(w http.ResponseWriter, r *http.Request, job string, params apigen.ListJobRunsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_job_runs", r, "", "", "")
+	runs, err := c.jobsRunner.ListRuns(ctx, job, paginationAmount(params.Amount))
+	if errors.Is(err, jobs.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := apigen.JobRunList{Results: make([]apigen.JobRun, 0, len(runs))}
+	for _, run := range runs {
+		response.Results = append(response.Results, jobRunToAPI(run))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func jobRunToAPI(run *jobs.Run) apigen.JobRun {
+	jobRun := apigen.JobRun{
+		Id:        run.ID,
+		Job:       run.Job,
+		Owner:     run.Owner,
+		Status:    string(run.Status),
+		Attempts:  run.Attempts,
+		StartTime: run.StartTime.Unix(),
+	}
+	if run.Error != "" {
+		jobRun.Error = apiutil.Ptr(run.Error)
+	}
+	if !run.EndTime.IsZero() {
+		jobRun.EndTime = apiutil.Ptr(run.EndTime.Unix())
+	}
+	return jobRun
+}
+
 func (c *Controller) GetUsageReportSummary(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
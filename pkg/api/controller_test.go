@@ -3,6 +3,7 @@ package api_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,12 +12,15 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"text/template"
 	"time"
@@ -34,12 +38,17 @@ import (
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/provisioning"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/jobs"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/permissions"
 	tablediff "github.com/treeverse/lakefs/pkg/plugins/diff"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
@@ -410,6 +419,7 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 		expectedCommits []string
 		expectedMore    bool
 		stopAt          string
+		message         string
 	}{
 		{
 			name:            "log",
@@ -498,6 +508,26 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 			expectedMore:    false,
 			stopAt:          "main~2",
 		},
+		{
+			name:            "message",
+			message:         "commit1",
+			expectedCommits: []string{"commit10", "commit1"},
+			expectedMore:    false,
+		},
+		{
+			name:            "message_with_objects",
+			message:         "commit1",
+			objects:         []string{"foo/bar1", "foo/bar2"},
+			expectedCommits: []string{"commit1"},
+			expectedMore:    false,
+		},
+		{
+			name:            "message_stop_at",
+			message:         "commit1",
+			expectedCommits: []string{"commit10"},
+			expectedMore:    false,
+			stopAt:          commits[8].Reference,
+		},
 	}
 
 	for _, tt := range tests {
@@ -520,6 +550,9 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 			if tt.stopAt != "" {
 				params.StopAt = &tt.stopAt
 			}
+			if tt.message != "" {
+				params.Message = apiutil.Ptr(apigen.CommitSearchMessage(tt.message))
+			}
 
 			resp, err := clt.LogCommitsWithResponse(ctx, repo, "main", params)
 			verifyResponseOK(t, resp, err)
@@ -536,6 +569,214 @@ func TestController_LogCommitsPredefinedData(t *testing.T) {
 	}
 }
 
+func TestController_SearchCommits(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+
+	const totalCommits = 6
+	for i := 0; i < totalCommits; i++ {
+		n := strconv.Itoa(i + 1)
+		branch := "main"
+		committer := "alice"
+		if i%2 == 1 {
+			branch = "feature"
+			committer = "bob"
+		}
+		p := "foo/bar" + n
+		err := deps.catalog.CreateEntry(ctx, repo, branch, catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
+		testutil.MustDo(t, "create entry "+p, err)
+		metadata := catalog.Metadata{"run_id": "run" + n, "dag": "daily"}
+		_, err = deps.catalog.Commit(ctx, repo, branch, "commit"+n, committer, metadata, nil, nil, false)
+		testutil.MustDo(t, "commit "+p, err)
+	}
+
+	tests := []struct {
+		name             string
+		params           apigen.SearchCommitsParams
+		expectedMessages []string
+		expectedStatus   int
+	}{
+		{
+			name:             "metadata",
+			params:           apigen.SearchCommitsParams{Metadata: &apigen.CommitSearchMetadata{"run_id=run4"}},
+			expectedMessages: []string{"commit4"},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "metadata_multiple_pairs",
+			params:           apigen.SearchCommitsParams{Metadata: &apigen.CommitSearchMetadata{"dag=daily", "run_id=run3"}},
+			expectedMessages: []string{"commit3"},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "committer",
+			params:           apigen.SearchCommitsParams{Committer: apiutil.Ptr(apigen.CommitSearchCommitter("bob"))},
+			expectedMessages: []string{"commit2", "commit4", "commit6"},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "message",
+			params:           apigen.SearchCommitsParams{Message: apiutil.Ptr(apigen.CommitSearchMessage("created"))},
+			expectedMessages: []string{"Repository created"},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "no_match",
+			params:           apigen.SearchCommitsParams{Metadata: &apigen.CommitSearchMetadata{"run_id=missing"}},
+			expectedMessages: []string{},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:           "invalid_metadata",
+			params:         apigen.SearchCommitsParams{Metadata: &apigen.CommitSearchMetadata{"run_id"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := clt.SearchCommitsWithResponse(ctx, repo, &tt.params)
+			testutil.Must(t, err)
+			require.Equal(t, tt.expectedStatus, resp.StatusCode())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			messages := make([]string, 0)
+			for _, commit := range resp.JSON200.Results {
+				messages = append(messages, commit.Message)
+			}
+			// results are ordered by commit ID
+			sort.Strings(messages)
+			require.Equal(t, tt.expectedMessages, messages)
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		var (
+			after    string
+			messages []string
+		)
+		for {
+			resp, err := clt.SearchCommitsWithResponse(ctx, repo, &apigen.SearchCommitsParams{
+				After:    apiutil.Ptr(apigen.PaginationAfter(after)),
+				Amount:   apiutil.Ptr(apigen.PaginationAmount(2)),
+				Metadata: &apigen.CommitSearchMetadata{"dag=daily"},
+			})
+			verifyResponseOK(t, resp, err)
+			for _, commit := range resp.JSON200.Results {
+				messages = append(messages, commit.Message)
+			}
+			if !resp.JSON200.Pagination.HasMore {
+				break
+			}
+			after = resp.JSON200.Pagination.NextOffset
+		}
+		sort.Strings(messages)
+		require.Equal(t, []string{"commit1", "commit2", "commit3", "commit4", "commit5", "commit6"}, messages)
+	})
+}
+
+func TestController_GetCommitGraph(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	commit := func(branch, message string) {
+		t.Helper()
+		_, err := deps.catalog.Commit(ctx, repo, branch, message, "alice", nil, nil, nil, true)
+		testutil.MustDo(t, "commit "+message, err)
+	}
+	commit("main", "m1")
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+	commit("feature", "f1")
+	commit("main", "m2")
+	_, err = deps.catalog.CreateTag(ctx, repo, "v1", "feature")
+	testutil.Must(t, err)
+
+	getGraph := func(params *apigen.GetCommitGraphParams) *apigen.CommitGraph {
+		t.Helper()
+		resp, err := clt.GetCommitGraphWithResponse(ctx, repo, params)
+		verifyResponseOK(t, resp, err)
+		return resp.JSON200
+	}
+	messages := func(nodes []apigen.CommitGraphNode) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Message)
+		}
+		return result
+	}
+
+	t.Run("all_branches", func(t *testing.T) {
+		graph := getGraph(&apigen.GetCommitGraphParams{})
+		require.Equal(t, []string{"m2", "f1", "m1", "Repository created"}, messages(graph.Results))
+		require.False(t, graph.Pagination.HasMore)
+		require.Equal(t, []string{"main"}, graph.Results[0].Branches)
+		require.Equal(t, []string{"feature"}, graph.Results[1].Branches)
+		require.Equal(t, []string{"v1"}, graph.Results[1].Tags)
+		require.Empty(t, graph.Results[2].Branches)
+		require.Equal(t, []string{graph.Results[2].Id}, graph.Results[0].Parents)
+		require.Equal(t, []string{graph.Results[2].Id}, graph.Results[1].Parents)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		graph := getGraph(&apigen.GetCommitGraphParams{Amount: apiutil.Ptr(apigen.PaginationAmount(2))})
+		require.Equal(t, []string{"m2", "f1"}, messages(graph.Results))
+		require.True(t, graph.Pagination.HasMore)
+		graph = getGraph(&apigen.GetCommitGraphParams{
+			Amount: apiutil.Ptr(apigen.PaginationAmount(2)),
+			After:  apiutil.Ptr(apigen.PaginationAfter(graph.Pagination.NextOffset)),
+		})
+		require.Equal(t, []string{"m1", "Repository created"}, messages(graph.Results))
+		require.False(t, graph.Pagination.HasMore)
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		graph := getGraph(&apigen.GetCommitGraphParams{Refs: &[]string{"v1"}})
+		require.Equal(t, []string{"f1", "m1", "Repository created"}, messages(graph.Results))
+	})
+
+	t.Run("unknown_ref", func(t *testing.T) {
+		resp, err := clt.GetCommitGraphWithResponse(ctx, repo, &apigen.GetCommitGraphParams{Refs: &[]string{"missing"}})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("unknown_after", func(t *testing.T) {
+		resp, err := clt.GetCommitGraphWithResponse(ctx, repo, &apigen.GetCommitGraphParams{
+			After: apiutil.Ptr(apigen.PaginationAfter("a1b2c3d4e5f60718293a4b5c6d7e8f9012345678901234567890abcdef123456")),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		graph := getGraph(&apigen.GetCommitGraphParams{Refs: &[]string{"main"}, Depth: apiutil.Ptr(2)})
+		require.Equal(t, []string{"m2", "m1"}, messages(graph.Results))
+		require.False(t, graph.Pagination.HasMore)
+	})
+
+	t.Run("topological_order", func(t *testing.T) {
+		// a commit dated before its parents is still listed before them
+		_, err := deps.catalog.CreateBranch(ctx, repo, "backdated", "main")
+		testutil.Must(t, err)
+		date := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+		_, err = deps.catalog.Commit(ctx, repo, "backdated", "b1", "alice", nil, &date, nil, true)
+		testutil.Must(t, err)
+		graph := getGraph(&apigen.GetCommitGraphParams{Refs: &[]string{"backdated"}})
+		require.Equal(t, []string{"b1", "m2", "m1", "Repository created"}, messages(graph.Results))
+	})
+}
+
 func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -725,6 +966,76 @@ func TestController_CommitsGetBranchCommitLogByPath(t *testing.T) {
 	}
 }
 
+func TestController_CommitAsync(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs"}))
+
+	t.Run("commit", func(t *testing.T) {
+		resp, err := clt.CommitAsyncWithResponse(ctx, repo, "main", apigen.CommitAsyncJSONRequestBody{
+			Message: "async commit",
+		})
+		testutil.MustDo(t, "commit async", err)
+		if resp.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got %s", resp.Status())
+		}
+		// the staging area is sealed on submit, changes written after it are not committed
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/late", PhysicalAddress: "pa2", CreationDate: time.Now(), Size: 1, Checksum: "cs2"}))
+
+		var status *apigen.CommitAsyncStatus
+		started := time.Now()
+		for status == nil && time.Since(started) < 30*time.Second {
+			statusResp, err := clt.CommitAsyncStatusWithResponse(ctx, repo, "main", &apigen.CommitAsyncStatusParams{TaskId: resp.JSON202.Id})
+			verifyResponseOK(t, statusResp, err)
+			if statusResp.JSON200.Done {
+				status = statusResp.JSON200
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if status == nil {
+			t.Fatal("Expected commit to complete (timed-out)")
+		}
+		if status.Error != nil {
+			t.Fatalf("Commit failed: %s", *status.Error)
+		}
+		require.NotNil(t, status.Commit)
+		require.Equal(t, "async commit", status.Commit.Message)
+
+		branchResp, err := clt.GetBranchWithResponse(ctx, repo, "main")
+		verifyResponseOK(t, branchResp, err)
+		require.Equal(t, status.Commit.Id, branchResp.JSON200.CommitId)
+		diffResp, err := clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, diffResp, err)
+		require.Len(t, diffResp.JSON200.Results, 1)
+		require.Equal(t, "foo/late", diffResp.JSON200.Results[0].Path)
+
+		// the status is only found on the committed branch
+		otherResp, err := clt.CommitAsyncStatusWithResponse(ctx, repo, "other", &apigen.CommitAsyncStatusParams{TaskId: resp.JSON202.Id})
+		testutil.MustDo(t, "commit async status", err)
+		require.NotNil(t, otherResp.JSON404, "expected 404, got %s", otherResp.Status())
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		resp, err := clt.CommitAsyncWithResponse(ctx, repo, "main", apigen.CommitAsyncJSONRequestBody{
+			Message:  "async partial",
+			Prefixes: &[]string{"foo/"},
+		})
+		testutil.MustDo(t, "commit async", err)
+		require.NotNil(t, resp.JSON400, "expected 400, got %s", resp.Status())
+	})
+
+	t.Run("invalid task id", func(t *testing.T) {
+		resp, err := clt.CommitAsyncStatusWithResponse(ctx, repo, "main", &apigen.CommitAsyncStatusParams{TaskId: "invalid"})
+		testutil.MustDo(t, "commit async status", err)
+		require.NotNil(t, resp.JSON404, "expected 404, got %s", resp.Status())
+	})
+}
+
 func TestController_GetCommitHandler(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -880,6 +1191,88 @@ func TestController_CommitHandler(t *testing.T) {
 		verifyResponseOK(t, resp, err)
 	})
 
+	t.Run("partial commit", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
+		for _, p := range []string{"raw/a", "raw/b", "reports/c", "reports/d", "other"} {
+			testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: p, CreationDate: time.Now(), Size: 1, Checksum: p}))
+		}
+		_, err = deps.catalog.Commit(ctx, repo, "main", "first", "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", "other"))
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "raw/a", PhysicalAddress: "raw/a2", CreationDate: time.Now(), Size: 2, Checksum: "raw/a2"}))
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "reports/c", PhysicalAddress: "reports/c2", CreationDate: time.Now(), Size: 2, Checksum: "reports/c2"}))
+
+		resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:  "partial",
+			Paths:    &[]string{"other"},
+			Prefixes: &[]string{"raw/"},
+		})
+		verifyResponseOK(t, resp, err)
+
+		// committed changes
+		diffResp, err := clt.DiffRefsWithResponse(ctx, repo, resp.JSON201.Parents[0], resp.JSON201.Id, &apigen.DiffRefsParams{})
+		verifyResponseOK(t, diffResp, err)
+		var committed []string
+		for _, d := range diffResp.JSON200.Results {
+			committed = append(committed, d.Type+" "+d.Path)
+		}
+		require.Equal(t, []string{"removed other", "changed raw/a"}, committed)
+
+		// the other changes stay uncommitted
+		branchResp, err := clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, branchResp, err)
+		var uncommitted []string
+		for _, d := range branchResp.JSON200.Results {
+			uncommitted = append(uncommitted, d.Type+" "+d.Path)
+		}
+		require.Equal(t, []string{"changed reports/c"}, uncommitted)
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", "reports/c", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		require.Equal(t, "reports/c2", entry.PhysicalAddress)
+
+		// no selected changes
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:  "partial",
+			Prefixes: &[]string{"raw/"},
+		})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON400, "expected bad request, got %s", resp.Status())
+
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:  "partial",
+			Prefixes: &[]string{"reports/"},
+			Amend:    swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON400, "expected bad request, got %s", resp.Status())
+	})
+
+	t.Run("commit records authenticated author", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.MustDo(t, fmt.Sprintf("create repo %s", repo), err)
+		testutil.MustDo(t, fmt.Sprintf("commit bar on %s", repo), deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs", Metadata: nil}))
+		resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "some message",
+		})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "admin", apiutil.Value(resp.JSON201.Author))
+
+		getResp, err := clt.GetCommitWithResponse(ctx, repo, resp.JSON201.Id)
+		verifyResponseOK(t, getResp, err)
+		require.Equal(t, "admin", apiutil.Value(getResp.JSON200.Author))
+
+		// commits created without an authenticated user have no author
+		commitLog, err := deps.catalog.Commit(ctx, repo, "main", "no author", "committer", nil, nil, nil, true)
+		testutil.Must(t, err)
+		getResp, err = clt.GetCommitWithResponse(ctx, repo, commitLog.Reference)
+		verifyResponseOK(t, getResp, err)
+		require.Nil(t, getResp.JSON200.Author)
+		require.Equal(t, "committer", getResp.JSON200.Committer)
+	})
+
 	t.Run("commit success with source metarange", func(t *testing.T) {
 		repo := testUniqueRepoName()
 		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
@@ -1004,6 +1397,58 @@ func TestController_CommitHandler(t *testing.T) {
 		})
 		verifyResponseOK(t, resp, err)
 	})
+
+	t.Run("amend", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.MustDo(t, "create repository", err)
+		testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar", PhysicalAddress: "pa", CreationDate: time.Now(), Size: 666, Checksum: "cs"}))
+		resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message:  "some mesage",
+			Metadata: &apigen.CommitCreation_Metadata{AdditionalProperties: map[string]string{"key": "value"}},
+		})
+		verifyResponseOK(t, resp, err)
+		commit := resp.JSON201
+
+		// amend the message only
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "some message",
+			Amend:   swag.Bool(true),
+		})
+		verifyResponseOK(t, resp, err)
+		amended := resp.JSON201
+		require.NotEqual(t, commit.Id, amended.Id)
+		require.Equal(t, "some message", amended.Message)
+		require.Equal(t, commit.Parents, amended.Parents)
+		require.Equal(t, commit.MetaRangeId, amended.MetaRangeId)
+		require.Equal(t, map[string]string{"key": "value"}, amended.Metadata.AdditionalProperties)
+
+		// amend with uncommitted changes, keeping the message
+		testutil.MustDo(t, "create entry", deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/baz", PhysicalAddress: "pa2", CreationDate: time.Now(), Size: 42, Checksum: "cs2"}))
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Amend: swag.Bool(true),
+		})
+		verifyResponseOK(t, resp, err)
+		amended = resp.JSON201
+		require.Equal(t, "some message", amended.Message)
+		require.Equal(t, commit.Parents, amended.Parents)
+		_, err = deps.catalog.GetEntry(ctx, repo, amended.Id, "foo/baz", catalog.GetEntryParams{})
+		require.NoError(t, err)
+		logResp, err := clt.LogCommitsWithResponse(ctx, repo, "main", &apigen.LogCommitsParams{})
+		verifyResponseOK(t, logResp, err)
+		require.Equal(t, amended.Id, logResp.JSON200.Results[0].Id)
+		require.Equal(t, commit.Parents[0], logResp.JSON200.Results[1].Id)
+
+		// commits reachable from other branches can't be amended
+		_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+		testutil.Must(t, err)
+		resp, err = clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "amended again",
+			Amend:   swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusConflict, resp.StatusCode())
+	})
 }
 
 func TestController_CreateRepositoryHandler(t *testing.T) {
@@ -1037,7 +1482,7 @@ func TestController_CreateRepositoryHandler(t *testing.T) {
 			}, apigen.CreateRepositoryJSONRequestBody{
 				DefaultBranch:    apiutil.Ptr("main"),
 				Name:             repoName,
-				StorageNamespace: onBlock(deps, "foo-bucket-1"),
+				StorageNamespace: onBlock(deps, "foo-bucket-bare"),
 			})
 		verifyResponseOK(t, resp, err)
 
@@ -1114,6 +1559,44 @@ func TestController_CreateRepositoryHandler(t *testing.T) {
 		if resp.JSON400 == nil {
 			t.Fatal("expected status code 400 for invalid namespace, got", resp.StatusCode())
 		}
+		require.Equal(t, "invalid_namespace", swag.StringValue(resp.JSON400.Reason))
+	})
+
+	t.Run("create repo with conflicting storage namespace", func(t *testing.T) {
+		existing := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, existing, onBlock(deps, "conflict-bucket/repo"), "main", false)
+		testutil.Must(t, err)
+
+		tests := []struct {
+			name             string
+			storageNamespace string
+			expectedReason   string
+		}{
+			{name: "same", storageNamespace: onBlock(deps, "conflict-bucket/repo"), expectedReason: "already_in_use"},
+			{name: "same with trailing slash", storageNamespace: onBlock(deps, "conflict-bucket/repo/"), expectedReason: "already_in_use"},
+			{name: "nested inside", storageNamespace: onBlock(deps, "conflict-bucket/repo/inner"), expectedReason: "nested_namespace"},
+			{name: "containing", storageNamespace: onBlock(deps, "conflict-bucket"), expectedReason: "nested_namespace"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+					Name:             testUniqueRepoName(),
+					StorageNamespace: tt.storageNamespace,
+				})
+				testutil.Must(t, err)
+				if resp.JSON400 == nil {
+					t.Fatal("expected status code 400 for conflicting namespace, got", resp.StatusCode())
+				}
+				require.Equal(t, tt.expectedReason, swag.StringValue(resp.JSON400.Reason))
+			})
+		}
+
+		// a sibling sharing a name prefix is not nested
+		resp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             testUniqueRepoName(),
+			StorageNamespace: onBlock(deps, "conflict-bucket/repo-sibling"),
+		})
+		verifyResponseOK(t, resp, err)
 	})
 }
 
@@ -1715,6 +2198,36 @@ func TestController_BranchesDiffBranchHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("diff branch with base", func(t *testing.T) {
+		// commit the staged "a/b", then stage "a/c": compared to the first commit both are changes
+		baseRef := "diff-base"
+		_, err := deps.catalog.CreateTag(ctx, repo, baseRef, testBranch)
+		testutil.Must(t, err)
+		_, err = deps.catalog.Commit(ctx, repo, testBranch, "commit a/b", "some_user", nil, nil, nil, false)
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, testBranch, catalog.DBEntry{Path: "a/c"}))
+
+		resp, err := clt.DiffBranchWithResponse(ctx, repo, testBranch, &apigen.DiffBranchParams{Base: swag.String(baseRef)})
+		verifyResponseOK(t, resp, err)
+		paths := make([]string, 0, len(resp.JSON200.Results))
+		for _, d := range resp.JSON200.Results {
+			require.Equal(t, "added", d.Type)
+			paths = append(paths, d.Path)
+		}
+		require.Equal(t, []string{"a/b", "a/c"}, paths)
+
+		resp, err = clt.DiffBranchWithResponse(ctx, repo, testBranch, &apigen.DiffBranchParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "a/c", resp.JSON200.Results[0].Path)
+	})
+
+	t.Run("diff branch with missing base", func(t *testing.T) {
+		resp, err := clt.DiffBranchWithResponse(ctx, repo, testBranch, &apigen.DiffBranchParams{Base: swag.String("missing-ref")})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON404, "expected not found, got %s", resp.Status())
+	})
+
 	t.Run("diff branch that doesn't exist", func(t *testing.T) {
 		resp, err := clt.DiffBranchWithResponse(ctx, repo, "some-other-missing-branch", &apigen.DiffBranchParams{})
 		if err != nil {
@@ -1814,6 +2327,54 @@ func TestController_CreateBranchHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("create branch exist ok", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		testutil.Must(t, err)
+
+		body := apigen.CreateBranchJSONRequestBody{Name: "pr-1", Source: "main", ExistOk: swag.Bool(true)}
+		resp, err := clt.CreateBranchWithResponse(ctx, repo, body)
+		verifyResponseOK(t, resp, err)
+		reference := string(resp.Body)
+
+		// creating again from the same source returns the existing branch
+		resp, err = clt.CreateBranchWithResponse(ctx, repo, body)
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, reference, string(resp.Body))
+
+		// the source moved, the existing branch is not at its commit
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}))
+		_, err = deps.catalog.Commit(ctx, repo, "main", "move main", "test", nil, nil, nil, false)
+		testutil.Must(t, err)
+		resp, err = clt.CreateBranchWithResponse(ctx, repo, body)
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "expected conflict, got %d", resp.StatusCode())
+	})
+
+	t.Run("create branch exist ok concurrently", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
+		testutil.Must(t, err)
+
+		const workers = 10
+		var wg sync.WaitGroup
+		statuses := make([]int, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, err := clt.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{Name: "pr-2", Source: "main", ExistOk: swag.Bool(true)})
+				if err == nil {
+					statuses[i] = resp.StatusCode()
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, status := range statuses {
+			require.Equal(t, http.StatusCreated, status)
+		}
+	})
+
 	t.Run("create branch conflict with tag", func(t *testing.T) {
 		repo := testUniqueRepoName()
 		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "foo1"), "main", false)
@@ -2205,15 +2766,431 @@ func TestController_DeleteBranchHandler(t *testing.T) {
 	})
 }
 
-func TestController_ObjectsStatObjectHandler(t *testing.T) {
+func TestController_RestoreBranchHandler(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "some-bucket"), "main", false)
-	if err != nil {
-		t.Fatal(err)
-	}
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+	reference, err := deps.catalog.GetBranchReference(ctx, repo, "feature")
+	testutil.Must(t, err)
+
+	delResp, err := clt.DeleteBranchWithResponse(ctx, repo, "feature", &apigen.DeleteBranchParams{})
+	verifyResponseOK(t, delResp, err)
+
+	t.Run("list deleted branches", func(t *testing.T) {
+		resp, err := clt.ListDeletedBranchesWithResponse(ctx, repo, &apigen.ListDeletedBranchesParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		deleted := resp.JSON200.Results[0]
+		require.Equal(t, "feature", deleted.Id)
+		require.Equal(t, reference, deleted.CommitId)
+		require.Greater(t, deleted.ExpiresAt, deleted.DeletedAt)
+	})
+
+	t.Run("restore branch", func(t *testing.T) {
+		resp, err := clt.RestoreBranchWithResponse(ctx, repo, "feature")
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, reference, resp.JSON201.CommitId)
+
+		restored, err := deps.catalog.GetBranchReference(ctx, repo, "feature")
+		testutil.Must(t, err)
+		require.Equal(t, reference, restored)
+
+		listResp, err := clt.ListDeletedBranchesWithResponse(ctx, repo, &apigen.ListDeletedBranchesParams{})
+		verifyResponseOK(t, listResp, err)
+		require.Empty(t, listResp.JSON200.Results)
+	})
+
+	t.Run("restore branch not deleted", func(t *testing.T) {
+		resp, err := clt.RestoreBranchWithResponse(ctx, repo, "no-such-branch")
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON404, "restore branch expected not found, got status %d", resp.StatusCode())
+	})
+
+	t.Run("restore existing branch", func(t *testing.T) {
+		delResp, err := clt.DeleteBranchWithResponse(ctx, repo, "feature", &apigen.DeleteBranchParams{})
+		verifyResponseOK(t, delResp, err)
+		_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+		testutil.Must(t, err)
+
+		resp, err := clt.RestoreBranchWithResponse(ctx, repo, "feature")
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "restore branch expected conflict, got status %d", resp.StatusCode())
+	})
+}
+
+func TestController_StashHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/b"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "first commit", "test", nil, nil, nil, false)
+	testutil.Must(t, err)
+	reference, err := deps.catalog.GetBranchReference(ctx, repo, "main")
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "hotfix", "main")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "a/c"}))
+	testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", "a/b"))
+
+	t.Run("stash branch", func(t *testing.T) {
+		resp, err := clt.StashBranchWithResponse(ctx, repo, "main", apigen.StashBranchJSONRequestBody{
+			Id:      "wip",
+			Message: swag.String("ingestion in progress"),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "wip", resp.JSON201.Id)
+		require.Equal(t, "main", resp.JSON201.Branch)
+		require.Equal(t, reference, resp.JSON201.CommitId)
+		require.Equal(t, "ingestion in progress", resp.JSON201.Message)
+
+		// branch is back at its last commit
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "a/c", catalog.GetEntryParams{})
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "a/b", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+	})
+
+	t.Run("stash branch without changes", func(t *testing.T) {
+		resp, err := clt.StashBranchWithResponse(ctx, repo, "main", apigen.StashBranchJSONRequestBody{Id: "empty"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON400, "stash branch expected bad request, got status %d", resp.StatusCode())
+	})
+
+	t.Run("stash existing id", func(t *testing.T) {
+		_, err := deps.catalog.CreateBranch(ctx, repo, "other", "main")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "other", catalog.DBEntry{Path: "a/c"}))
+		resp, err := clt.StashBranchWithResponse(ctx, repo, "other", apigen.StashBranchJSONRequestBody{Id: "wip"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "stash branch expected conflict, got status %d", resp.StatusCode())
+	})
+
+	t.Run("list stashes", func(t *testing.T) {
+		resp, err := clt.ListStashesWithResponse(ctx, repo, &apigen.ListStashesParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "wip", resp.JSON200.Results[0].Id)
+	})
+
+	t.Run("apply on another branch", func(t *testing.T) {
+		resp, err := clt.ApplyStashWithResponse(ctx, repo, "wip", apigen.ApplyStashJSONRequestBody{Branch: "hotfix"})
+		verifyResponseOK(t, resp, err)
+		_, err = deps.catalog.GetEntry(ctx, repo, "hotfix", "a/c", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		_, err = deps.catalog.GetEntry(ctx, repo, "hotfix", "a/b", catalog.GetEntryParams{})
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+
+		getResp, err := clt.GetStashWithResponse(ctx, repo, "wip")
+		verifyResponseOK(t, getResp, err)
+	})
+
+	t.Run("apply with conflict", func(t *testing.T) {
+		resp, err := clt.ApplyStashWithResponse(ctx, repo, "wip", apigen.ApplyStashJSONRequestBody{Branch: "other"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "apply stash expected conflict, got status %d", resp.StatusCode())
+	})
+
+	t.Run("pop stash", func(t *testing.T) {
+		resp, err := clt.ApplyStashWithResponse(ctx, repo, "wip", apigen.ApplyStashJSONRequestBody{
+			Branch: "main",
+			Drop:   swag.Bool(true),
+		})
+		verifyResponseOK(t, resp, err)
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "a/c", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+
+		getResp, err := clt.GetStashWithResponse(ctx, repo, "wip")
+		testutil.Must(t, err)
+		require.NotNil(t, getResp.JSON404, "get stash expected not found, got status %d", getResp.StatusCode())
+	})
+
+	t.Run("delete stash", func(t *testing.T) {
+		stashResp, err := clt.StashBranchWithResponse(ctx, repo, "other", apigen.StashBranchJSONRequestBody{Id: "discard"})
+		verifyResponseOK(t, stashResp, err)
+		resp, err := clt.DeleteStashWithResponse(ctx, repo, "discard")
+		verifyResponseOK(t, resp, err)
+
+		listResp, err := clt.ListStashesWithResponse(ctx, repo, &apigen.ListStashesParams{})
+		verifyResponseOK(t, listResp, err)
+		require.Empty(t, listResp.JSON200.Results)
+	})
+}
+
+func TestController_RenameBranchHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	t.Run("rename default branch", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "master", false)
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "master", catalog.DBEntry{Path: "a/b"}))
+		reference, err := deps.catalog.GetBranchReference(ctx, repo, "master")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.SetBranchProtectionRules(ctx, repo, &graveler.BranchProtectionRules{
+			BranchPatternToBlockedActions: map[string]*graveler.BranchProtectionBlockedActions{
+				"master": {Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_COMMIT}},
+			},
+		}, swag.String("")))
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "master", apigen.RenameBranchJSONRequestBody{Name: "main"})
+		verifyResponseOK(t, resp, err)
+
+		_, err = deps.catalog.GetBranchReference(ctx, repo, "master")
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+		renamed, err := deps.catalog.GetBranchReference(ctx, repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, reference, renamed)
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "a/b", catalog.GetEntryParams{})
+		require.NoError(t, err, "uncommitted entry of the renamed branch")
+
+		repoResp, err := clt.GetRepositoryWithResponse(ctx, repo)
+		verifyResponseOK(t, repoResp, err)
+		require.Equal(t, "main", repoResp.JSON200.DefaultBranch)
+
+		rules, _, err := deps.catalog.GetBranchProtectionRules(ctx, repo)
+		testutil.Must(t, err)
+		require.Contains(t, rules.BranchPatternToBlockedActions, "main")
+		require.NotContains(t, rules.BranchPatternToBlockedActions, "master")
+	})
+
+	t.Run("rename moves lifecycle and gc rules", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		_, err = deps.catalog.CreateBranch(ctx, repo, "ingest", "main")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.SetLifecycleRules(ctx, repo, &graveler.LifecycleRules{
+			Rules: []*graveler.LifecycleRule{
+				{Id: "raw", Branch: "ingest", Prefix: "raw/", ExpirationDays: 7},
+				{Id: "tmp", Branch: "main", Prefix: "tmp/", ExpirationDays: 1},
+			},
+		}))
+		testutil.Must(t, deps.catalog.SetGarbageCollectionRules(ctx, repo, &graveler.GarbageCollectionRules{
+			DefaultRetentionDays: 7,
+			BranchRetentionDays:  map[string]int32{"ingest": 30},
+		}))
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "ingest", apigen.RenameBranchJSONRequestBody{Name: "landing"})
+		verifyResponseOK(t, resp, err)
+
+		lifecycleRules, err := deps.catalog.GetLifecycleRules(ctx, repo)
+		testutil.Must(t, err)
+		require.Len(t, lifecycleRules.Rules, 2)
+		require.Equal(t, "landing", lifecycleRules.Rules[0].Branch)
+		require.Equal(t, "main", lifecycleRules.Rules[1].Branch)
+		gcRules, err := deps.catalog.GetGarbageCollectionRules(ctx, repo)
+		testutil.Must(t, err)
+		require.Equal(t, map[string]int32{"landing": 30}, gcRules.BranchRetentionDays)
+	})
+
+	t.Run("rename locked branch", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		_, err = deps.catalog.CreateBranch(ctx, repo, "frozen", "main")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.LockBranch(ctx, repo, "frozen", "quarter close", "admin"))
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "frozen", apigen.RenameBranchJSONRequestBody{Name: "thawed"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode())
+		_, err = deps.catalog.GetBranchReference(ctx, repo, "frozen")
+		require.NoError(t, err)
+		_, err = deps.catalog.GetBranchReference(ctx, repo, "thawed")
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+	})
+
+	t.Run("rename branch selected by its actions", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		_, err = deps.catalog.CreateBranch(ctx, repo, "release", "main")
+		testutil.Must(t, err)
+		action := `name: invalidate cdn
+on:
+  post-merge:
+    branches:
+      - release
+      - release-*
+hooks:
+  - id: invalidate
+    type: webhook
+    properties:
+      url: http://localhost/invalidate
+`
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, "_lakefs_actions/invalidate.yaml", strings.NewReader(action), repo, "release")
+		verifyResponseOK(t, uploadResp, err)
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "release", apigen.RenameBranchJSONRequestBody{Name: "stable"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "expected conflict, got %d", resp.StatusCode())
+		_, err = deps.catalog.GetBranchReference(ctx, repo, "release")
+		require.NoError(t, err)
+
+		// actions that select the new name too do not block the rename
+		resp, err = clt.RenameBranchWithResponse(ctx, repo, "release", apigen.RenameBranchJSONRequestBody{Name: "release-1"})
+		verifyResponseOK(t, resp, err)
+	})
+
+	t.Run("rename to existing branch", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+		testutil.Must(t, err)
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "feature", apigen.RenameBranchJSONRequestBody{Name: "main"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409, "expected conflict, got %d", resp.StatusCode())
+		_, err = deps.catalog.GetBranchReference(ctx, repo, "feature")
+		require.NoError(t, err)
+	})
+
+	t.Run("rename branch doesnt exist", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+
+		resp, err := clt.RenameBranchWithResponse(ctx, repo, "missing", apigen.RenameBranchJSONRequestBody{Name: "other"})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON404, "expected not found, got %d", resp.StatusCode())
+	})
+}
+
+func TestController_GetMergeBaseHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	base, err := deps.catalog.GetBranchReference(ctx, repo, "main")
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+	commit := func(branch, path string) string {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, branch, catalog.DBEntry{Path: path}))
+		log, err := deps.catalog.Commit(ctx, repo, branch, "commit "+path, "some_user", nil, nil, nil, false)
+		testutil.Must(t, err)
+		return log.Reference
+	}
+	commit("feature", "f1")
+	featureHead := commit("feature", "f2")
+
+	t.Run("fast-forward", func(t *testing.T) {
+		resp, err := clt.GetMergeBaseWithResponse(ctx, repo, "feature", "main")
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, apigen.MergeBaseResult{
+			LeftCommitId:  featureHead,
+			RightCommitId: base,
+			BaseCommitId:  base,
+			Ahead:         2,
+			Behind:        0,
+		}, *resp.JSON200)
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		mainHead := commit("main", "m1")
+		resp, err := clt.GetMergeBaseWithResponse(ctx, repo, "main", "feature")
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, apigen.MergeBaseResult{
+			LeftCommitId:  mainHead,
+			RightCommitId: featureHead,
+			BaseCommitId:  base,
+			Ahead:         1,
+			Behind:        2,
+		}, *resp.JSON200)
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		resp, err := clt.GetMergeBaseWithResponse(ctx, repo, "main", "no-such-branch")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_DiffObjectContentHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	upload := func(branch, path string, content []byte) {
+		resp, err := uploadObjectHelper(t, ctx, clt, path, bytes.NewReader(content), repo, branch)
+		verifyResponseOK(t, resp, err)
+	}
+	upload("main", "conf.yaml", []byte("a: 1\nb: 2\nc: 3\n"))
+	upload("main", "data.bin", []byte{0x00, 0x01, 0x02, 0xff})
+	_, err = deps.catalog.Commit(ctx, repo, "main", "initial", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+	upload("feature", "conf.yaml", []byte("a: 1\nb: 20\nc: 3\n"))
+	upload("feature", "new.json", []byte(`{"key": "value"}`+"\n"))
+
+	t.Run("changed", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "main", "feature", &apigen.DiffObjectContentParams{Path: "conf.yaml"})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "--- main/conf.yaml\n+++ feature/conf.yaml\n@@ -1,3 +1,3 @@\n a: 1\n-b: 2\n+b: 20\n c: 3\n", resp.JSON200.Diff)
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "main", "main", &apigen.DiffObjectContentParams{Path: "conf.yaml"})
+		verifyResponseOK(t, resp, err)
+		require.Empty(t, resp.JSON200.Diff)
+	})
+
+	t.Run("added", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "main", "feature", &apigen.DiffObjectContentParams{Path: "new.json"})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "--- /dev/null\n+++ feature/new.json\n@@ -0,0 +1 @@\n+{\"key\": \"value\"}\n", resp.JSON200.Diff)
+	})
+
+	t.Run("different paths", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "feature", "feature", &apigen.DiffObjectContentParams{
+			Path:      "conf.yaml",
+			RightPath: swag.String("new.json"),
+			Context:   swag.Int(0),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Contains(t, resp.JSON200.Diff, "@@ -1,3 +1 @@\n")
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "main", "feature", &apigen.DiffObjectContentParams{Path: "data.bin"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("missing on both refs", func(t *testing.T) {
+		resp, err := clt.DiffObjectContentWithResponse(ctx, repo, "main", "feature", &apigen.DiffObjectContentParams{Path: "no-such-object"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_ObjectsStatObjectHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "some-bucket"), "main", false)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("object_stat_no_metadata", func(t *testing.T) {
 		const objPath = "foo/bar-no-metadata"
@@ -2236,6 +3213,39 @@ func TestController_ObjectsStatObjectHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("object_stat_imported_source", func(t *testing.T) {
+		const objPath = "foo/bar-imported"
+		entry := catalog.DBEntry{
+			Path:            objPath,
+			PhysicalAddress: "s3://source-bucket/bar",
+			AddressType:     catalog.AddressTypeFull,
+			CreationDate:    time.Now(),
+			Size:            666,
+			Checksum:        "source_etag",
+			Source:          &catalog.ObjectSource{ETag: "source_etag", VersionID: "v1"},
+		}
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", entry))
+
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: objPath})
+		verifyResponseOK(t, resp, err)
+		if resp.JSON200 == nil {
+			t.Fatalf("expected to get back object stats, got status %s", resp.Status())
+		}
+		expected := &apigen.ObjectSource{Etag: "source_etag", VersionId: swag.String("v1")}
+		if diff := deep.Equal(resp.JSON200.Source, expected); diff != nil {
+			t.Fatalf("source of imported object: %s", diff)
+		}
+
+		listResp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{Prefix: apiutil.Ptr(apigen.PaginationPrefix(objPath))})
+		verifyResponseOK(t, listResp, err)
+		if len(listResp.JSON200.Results) != 1 {
+			t.Fatalf("expected a single listed object, got %d", len(listResp.JSON200.Results))
+		}
+		if diff := deep.Equal(listResp.JSON200.Results[0].Source, expected); diff != nil {
+			t.Fatalf("listed source of imported object: %s", diff)
+		}
+	})
+
 	t.Run("get object stats", func(t *testing.T) {
 		entry := catalog.DBEntry{
 			Path:            "foo/bar",
@@ -2397,42 +3407,353 @@ func TestController_ObjectsListObjectsHandler(t *testing.T) {
 	})
 }
 
-func TestController_ObjectsHeadObjectHandler(t *testing.T) {
+func TestController_ObjectsListObjectsByStorageClass(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
-	if err != nil {
-		t.Fatal(err)
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for _, entry := range []catalog.DBEntry{
+		{Path: "data/a", StorageClass: "GLACIER"},
+		{Path: "data/b"},
+		{Path: "data/c", StorageClass: "STANDARD_IA"},
+		{Path: "data/d", StorageClass: "GLACIER"},
+		{Path: "data/sub/e", StorageClass: "STANDARD_IA"},
+	} {
+		entry.PhysicalAddress = entry.Path + "_address"
+		entry.CreationDate = time.Now()
+		entry.Checksum = "checksum"
+		testutil.MustDo(t, "create entry "+entry.Path, deps.catalog.CreateEntry(ctx, repo, "main", entry))
 	}
 
-	expensiveString := "EXPENSIVE"
-
-	buf := new(bytes.Buffer)
-	buf.WriteString("this is file content made up of bytes")
-	address := upload.DefaultPathProvider.NewPath()
-	blob, err := upload.WriteBlob(context.Background(), deps.blocks, onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
-	if err != nil {
-		t.Fatal(err)
-	}
-	entry := catalog.DBEntry{
-		Path:            "foo/bar",
-		PhysicalAddress: blob.PhysicalAddress,
-		CreationDate:    time.Now(),
-		Size:            blob.Size,
-		Checksum:        blob.Checksum,
+	listPaths := func(t *testing.T, storageClass string, delimiter string) []string {
+		t.Helper()
+		var paths []string
+		var after string
+		for {
+			resp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+				Prefix:       apiutil.Ptr(apigen.PaginationPrefix("data/")),
+				Delimiter:    apiutil.Ptr(apigen.PaginationDelimiter(delimiter)),
+				After:        apiutil.Ptr(apigen.PaginationAfter(after)),
+				Amount:       apiutil.Ptr(apigen.PaginationAmount(1)),
+				StorageClass: &storageClass,
+			})
+			verifyResponseOK(t, resp, err)
+			for _, obj := range resp.JSON200.Results {
+				paths = append(paths, obj.Path)
+				if obj.PathType == "object" && !strings.EqualFold(catalog.StorageClassOrDefault(swag.StringValue(obj.StorageClass)), storageClass) {
+					t.Errorf("Listed %s of storage class %v, expected %s", obj.Path, swag.StringValue(obj.StorageClass), storageClass)
+				}
+			}
+			if !resp.JSON200.Pagination.HasMore {
+				return paths
+			}
+			after = resp.JSON200.Pagination.NextOffset
+		}
 	}
-	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", entry))
 
-	expired := catalog.DBEntry{
-		Path:            "foo/expired",
-		PhysicalAddress: "an_expired_physical_address",
-		CreationDate:    time.Now(),
-		Size:            99999,
-		Checksum:        "b10b",
-		Expired:         true,
-	}
+	t.Run("glacier", func(t *testing.T) {
+		paths := listPaths(t, "GLACIER", "")
+		if diff := deep.Equal(paths, []string{"data/a", "data/d"}); diff != nil {
+			t.Fatalf("Listed paths diff: %s", diff)
+		}
+	})
+	t.Run("standard", func(t *testing.T) {
+		paths := listPaths(t, "standard", "")
+		if diff := deep.Equal(paths, []string{"data/b"}); diff != nil {
+			t.Fatalf("Listed paths diff: %s", diff)
+		}
+	})
+	t.Run("common_prefixes", func(t *testing.T) {
+		paths := listPaths(t, "GLACIER", "/")
+		if diff := deep.Equal(paths, []string{"data/a", "data/d", "data/sub/"}); diff != nil {
+			t.Fatalf("Listed paths diff: %s", diff)
+		}
+	})
+	t.Run("stat", func(t *testing.T) {
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "data/c"})
+		verifyResponseOK(t, resp, err)
+		if storageClass := swag.StringValue(resp.JSON200.StorageClass); storageClass != "STANDARD_IA" {
+			t.Fatalf("Stat storage class %s, expected STANDARD_IA", storageClass)
+		}
+	})
+}
+
+func TestController_ObjectsRestore(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for _, p := range []string{"data/a", "data/b", "other/c"} {
+		_, err := uploadObjectHelper(t, ctx, clt, p, strings.NewReader("content"), repo, "main")
+		testutil.MustDo(t, "upload "+p, err)
+	}
+	expectedPaths := []string{"data/a", "data/b"}
+
+	verifyNotArchived := func(t *testing.T, list *apigen.ObjectRestoreList) {
+		t.Helper()
+		var paths []string
+		for _, result := range list.Results {
+			paths = append(paths, result.Path)
+			if result.Status != catalog.RestoreStatusNotArchived {
+				t.Errorf("Object %s restore status %s, expected %s", result.Path, result.Status, catalog.RestoreStatusNotArchived)
+			}
+		}
+		if diff := deep.Equal(paths, expectedPaths); diff != nil {
+			t.Errorf("Restore paths diff: %s", diff)
+		}
+	}
+
+	t.Run("status", func(t *testing.T) {
+		resp, err := clt.GetObjectsRestoreStatusWithResponse(ctx, repo, "main", &apigen.GetObjectsRestoreStatusParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+		})
+		verifyResponseOK(t, resp, err)
+		verifyNotArchived(t, resp.JSON200)
+	})
+	t.Run("restore", func(t *testing.T) {
+		resp, err := clt.RestoreObjectsWithResponse(ctx, repo, "main", &apigen.RestoreObjectsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+		}, apigen.RestoreObjectsJSONRequestBody{Days: 1})
+		verifyResponseOK(t, resp, err)
+		verifyNotArchived(t, resp.JSON200)
+	})
+	t.Run("invalid_days", func(t *testing.T) {
+		resp, err := clt.RestoreObjectsWithResponse(ctx, repo, "main", &apigen.RestoreObjectsParams{}, apigen.RestoreObjectsJSONRequestBody{Days: 0})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusBadRequest {
+			t.Fatalf("Restore with zero days status code %d, expected %d", resp.StatusCode(), http.StatusBadRequest)
+		}
+	})
+}
+
+func TestController_GetObjectTree(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+	for path, size := range map[string]int64{
+		"top":              1,
+		"data/a":           10,
+		"data/b":           20,
+		"data/2023/01/c":   100,
+		"data/2023/02/d":   200,
+		"data/2024/01/e":   1000,
+		"logs/app/01.log":  5,
+		"logs/app/02.log":  5,
+		"logs/system.log":  3,
+		"other_prefix/obj": 7,
+	} {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: path + "_address",
+			CreationDate:    time.Now(),
+			Size:            size,
+			Checksum:        "checksum",
+		}))
+	}
+
+	t.Run("root", func(t *testing.T) {
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		tree := resp.JSON200
+		require.Equal(t, "", tree.Path)
+		require.Equal(t, 4, tree.ChildCount)
+		require.Equal(t, int64(10), tree.ObjectCount)
+		require.Equal(t, int64(1351), tree.SizeBytes)
+		require.NotNil(t, tree.Children)
+		require.Equal(t, []apigen.ObjectTree{
+			{Path: "data/", ChildCount: 4, ObjectCount: 5, SizeBytes: 1330},
+			{Path: "logs/", ChildCount: 2, ObjectCount: 3, SizeBytes: 13},
+			{Path: "other_prefix/", ChildCount: 1, ObjectCount: 1, SizeBytes: 7},
+		}, *tree.Children)
+	})
+
+	t.Run("prefix_depth", func(t *testing.T) {
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+			Depth:  apiutil.Ptr(2),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		tree := resp.JSON200
+		require.Equal(t, "data/", tree.Path)
+		require.NotNil(t, tree.Children)
+		require.Len(t, *tree.Children, 2)
+		year := (*tree.Children)[0]
+		require.Equal(t, "data/2023/", year.Path)
+		require.Equal(t, 2, year.ChildCount)
+		require.Equal(t, int64(300), year.SizeBytes)
+		require.NotNil(t, year.Children)
+		require.Equal(t, []apigen.ObjectTree{
+			{Path: "data/2023/01/", ChildCount: 1, ObjectCount: 1, SizeBytes: 100},
+			{Path: "data/2023/02/", ChildCount: 1, ObjectCount: 1, SizeBytes: 200},
+		}, *year.Children)
+	})
+
+	t.Run("zero_depth", func(t *testing.T) {
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("logs/")),
+			Depth:  apiutil.Ptr(0),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, apigen.ObjectTree{Path: "logs/", ChildCount: 2, ObjectCount: 3, SizeBytes: 13}, *resp.JSON200)
+	})
+
+	t.Run("negative_depth", func(t *testing.T) {
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{
+			Depth: apiutil.Ptr(-1),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		deps.catalog.TreeMaxScanEntries = 3
+		t.Cleanup(func() { deps.catalog.TreeMaxScanEntries = 0 })
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+			Depth:  apiutil.Ptr(0),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, apigen.ObjectTree{Path: "data/", ChildCount: 2, ObjectCount: 3, SizeBytes: 1300, Truncated: apiutil.Ptr(true)}, *resp.JSON200)
+
+		resp, err = clt.GetObjectTreeWithResponse(ctx, repo, "main", &apigen.GetObjectTreeParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("logs/")),
+			Depth:  apiutil.Ptr(0),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Nil(t, resp.JSON200.Truncated)
+	})
+
+	t.Run("missing_ref", func(t *testing.T) {
+		resp, err := clt.GetObjectTreeWithResponse(ctx, repo, "no_such_branch", &apigen.GetObjectTreeParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_AnnotateObjects(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+	commitPaths := func(message string, paths ...string) string {
+		for _, path := range paths {
+			testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+				Path:            path,
+				PhysicalAddress: path + "_" + message,
+				CreationDate:    time.Now(),
+				Size:            1,
+				Checksum:        "checksum_" + message,
+			}))
+		}
+		commit, err := deps.catalog.Commit(ctx, repo, "main", message, "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		return commit.Reference
+	}
+	first := commitPaths("first", "a", "aa", "dir/x", "dir/y", "other/z")
+	second := commitPaths("second", "aa", "dir/y")
+	// uncommitted changes are not attributed to any commit
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+		Path:            "staged",
+		PhysicalAddress: "staged_address",
+		CreationDate:    time.Now(),
+		Checksum:        "checksum",
+	}))
+
+	annotations := func(t *testing.T, params *apigen.AnnotateObjectsParams) map[string]string {
+		t.Helper()
+		resp, err := clt.AnnotateObjectsWithResponse(ctx, repo, "main", params)
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		res := make(map[string]string)
+		for _, obj := range resp.JSON200.Results {
+			res[obj.Path] = ""
+			if obj.Commit != nil {
+				res[obj.Path] = obj.Commit.Id
+			}
+		}
+		return res
+	}
+
+	t.Run("top", func(t *testing.T) {
+		res := annotations(t, &apigen.AnnotateObjectsParams{
+			Delimiter: apiutil.Ptr(apigen.PaginationDelimiter("/")),
+		})
+		require.Equal(t, map[string]string{
+			"a":      first,
+			"aa":     second,
+			"dir/":   second,
+			"other/": first,
+			"staged": "",
+		}, res)
+	})
+
+	t.Run("recursive_prefix", func(t *testing.T) {
+		res := annotations(t, &apigen.AnnotateObjectsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("dir/")),
+		})
+		require.Equal(t, map[string]string{
+			"dir/x": first,
+			"dir/y": second,
+		}, res)
+	})
+
+	t.Run("missing_ref", func(t *testing.T) {
+		resp, err := clt.AnnotateObjectsWithResponse(ctx, repo, "no_such_branch", &apigen.AnnotateObjectsParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_ObjectsHeadObjectHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expensiveString := "EXPENSIVE"
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("this is file content made up of bytes")
+	address := upload.DefaultPathProvider.NewPath()
+	blob, err := upload.WriteBlob(context.Background(), deps.blocks, onBlock(deps, "ns1"), address, buf, 37, block.PutOpts{StorageClass: &expensiveString})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := catalog.DBEntry{
+		Path:            "foo/bar",
+		PhysicalAddress: blob.PhysicalAddress,
+		CreationDate:    time.Now(),
+		Size:            blob.Size,
+		Checksum:        blob.Checksum,
+	}
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", entry))
+
+	expired := catalog.DBEntry{
+		Path:            "foo/expired",
+		PhysicalAddress: "an_expired_physical_address",
+		CreationDate:    time.Now(),
+		Size:            99999,
+		Checksum:        "b10b",
+		Expired:         true,
+	}
 	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", expired))
 
 	t.Run("head object", func(t *testing.T) {
@@ -3310,6 +4631,50 @@ func TestController_CreatePolicyHandler(t *testing.T) {
 		verifyResponseOK(t, resp, err)
 	})
 
+	t.Run("policy_with_condition", func(t *testing.T) {
+		condition := &apigen.Statement_Condition{
+			AdditionalProperties: map[string]apigen.StatementConditionKeys{
+				"StringEquals": {AdditionalProperties: map[string][]string{"lakefs:ObjectTag/pii": {"true"}}},
+			},
+		}
+		resp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+			Id: "ConditionPolicyID",
+			Statement: []apigen.Statement{
+				{
+					Action:    []string{"fs:ReadObject"},
+					Effect:    "deny",
+					Resource:  "*",
+					Condition: condition,
+				},
+			},
+		})
+		verifyResponseOK(t, resp, err)
+
+		getResp, err := clt.GetPolicyWithResponse(ctx, "ConditionPolicyID")
+		verifyResponseOK(t, getResp, err)
+		require.Equal(t, condition, getResp.JSON200.Statement[0].Condition)
+	})
+
+	t.Run("invalid_policy_condition", func(t *testing.T) {
+		resp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+			Id: "InvalidConditionPolicyID",
+			Statement: []apigen.Statement{
+				{
+					Action:   []string{"fs:ReadObject"},
+					Effect:   "deny",
+					Resource: "*",
+					Condition: &apigen.Statement_Condition{
+						AdditionalProperties: map[string]apigen.StatementConditionKeys{
+							"StringEquals": {AdditionalProperties: map[string][]string{"aws:SourceIp": {"10.0.0.1"}}},
+						},
+					},
+				},
+			},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
 	t.Run("invalid_policy_action", func(t *testing.T) {
 		resp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
 			CreationDate: apiutil.Ptr(time.Now().Unix()),
@@ -3547,6 +4912,39 @@ func TestController_SetupLakeFSHandler(t *testing.T) {
 	}
 }
 
+func TestLoginLockout(t *testing.T) {
+	viper.Set("auth.lockout.enabled", true)
+	t.Cleanup(func() { viper.Set("auth.lockout.enabled", false) })
+	handler, _ := setupHandler(t)
+	server := setupServer(t, handler)
+	clt := setupClientByEndpoint(t, server.URL, "", "")
+	cred := createDefaultAdminUser(t, clt)
+
+	// Login responds 401 with a body that is not an apigen.Error, check status codes directly
+	login := func(secretAccessKey string) int {
+		t.Helper()
+		body, err := json.Marshal(apigen.LoginJSONRequestBody{
+			AccessKeyId:     cred.AccessKeyID,
+			SecretAccessKey: secretAccessKey,
+		})
+		testutil.Must(t, err)
+		resp, err := http.Post(server.URL+apiutil.BaseURL+"/auth/login", "application/json", bytes.NewReader(body))
+		testutil.Must(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	maxFailures := viper.GetInt("auth.lockout.max_failures")
+	for i := 0; i < maxFailures; i++ {
+		if code := login("wrong secret"); code != http.StatusUnauthorized {
+			t.Fatalf("Login with wrong secret expected 401, got %d", code)
+		}
+	}
+	if code := login(cred.SecretAccessKey); code != http.StatusUnauthorized {
+		t.Fatalf("Login of locked out access key expected 401, got %d", code)
+	}
+}
+
 func TestLogin(t *testing.T) {
 	const configureDuration = "48h"
 	viper.Set("auth.login_duration", configureDuration)
@@ -3697,6 +5095,100 @@ func TestController_ListRepositoryRuns(t *testing.T) {
 	})
 }
 
+var rerunRunActionTemplate = template.Must(template.New("").Parse(`---
+name: PostCommitAction
+on:
+  post-commit:
+    branches:
+      - "*"
+hooks:
+  - id: hook1
+    type: webhook
+    properties:
+      url: {{.URL}}
+`))
+
+func TestController_RerunRun(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
+	ctx := context.Background()
+	var webhookFails atomic.Bool
+	webhookFails.Store(true)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if webhookFails.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer httpServer.Close()
+
+	repo := testUniqueRepoName()
+	resp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+		DefaultBranch:    apiutil.Ptr("main"),
+		Name:             repo,
+		StorageNamespace: "mem://" + repo,
+	})
+	verifyResponseOK(t, resp, err)
+	var b bytes.Buffer
+	testutil.MustDo(t, "execute action template", rerunRunActionTemplate.Execute(&b, httpServer))
+	uploadResp, err := uploadObjectHelper(t, ctx, clt, "_lakefs_actions/post_commit.yaml", strings.NewReader(b.String()), repo, "main")
+	verifyResponseOK(t, uploadResp, err)
+	commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		Message: "post-commit action",
+	})
+	verifyResponseOK(t, commitResp, err)
+
+	// post-commit hooks run in the background
+	var failedRun apigen.ActionRun
+	require.Eventually(t, func() bool {
+		respList, err := clt.ListRepositoryRunsWithResponse(ctx, repo, &apigen.ListRepositoryRunsParams{})
+		if err != nil || respList.JSON200 == nil || len(respList.JSON200.Results) == 0 {
+			return false
+		}
+		failedRun = respList.JSON200.Results[0]
+		return true
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, "post-commit", failedRun.EventType)
+	require.Equal(t, "failed", failedRun.Status)
+
+	t.Run("failed run", func(t *testing.T) {
+		webhookFails.Store(false)
+		rerunResp, err := clt.RerunRunWithResponse(ctx, repo, failedRun.RunId)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, rerunResp.StatusCode(), "rerun failed: %s", rerunResp.Body)
+		require.NotEqual(t, failedRun.RunId, rerunResp.JSON201.RunId)
+		require.Equal(t, "completed", rerunResp.JSON201.Status)
+		require.Equal(t, failedRun.CommitId, rerunResp.JSON201.CommitId)
+		require.Equal(t, "main", rerunResp.JSON201.Branch)
+
+		// the new run is part of the run history
+		runResp, err := clt.GetRunWithResponse(ctx, repo, rerunResp.JSON201.RunId)
+		verifyResponseOK(t, runResp, err)
+		require.Equal(t, "post-commit", runResp.JSON200.EventType)
+	})
+
+	t.Run("passed run", func(t *testing.T) {
+		respList, err := clt.ListRepositoryRunsWithResponse(ctx, repo, &apigen.ListRepositoryRunsParams{})
+		verifyResponseOK(t, respList, err)
+		var passedRunID string
+		for _, run := range respList.JSON200.Results {
+			if run.Status == "completed" {
+				passedRunID = run.RunId
+			}
+		}
+		require.NotEmpty(t, passedRunID)
+		rerunResp, err := clt.RerunRunWithResponse(ctx, repo, passedRunID)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, rerunResp.StatusCode())
+	})
+
+	t.Run("missing run", func(t *testing.T) {
+		rerunResp, err := clt.RerunRunWithResponse(ctx, repo, "no-such-run")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rerunResp.StatusCode())
+	})
+}
+
 func TestController_MergeInvalidStrategy(t *testing.T) {
 	clt, _ := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -3728,7 +5220,7 @@ func TestController_MergeInvalidStrategy(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, mergeResp.StatusCode())
 }
 
-func TestController_MergeDiffWithParent(t *testing.T) {
+func TestController_MergeStrategy(t *testing.T) {
 	clt, _ := setupClientWithAdmin(t)
 	ctx := context.Background()
 
@@ -3743,44 +5235,452 @@ func TestController_MergeDiffWithParent(t *testing.T) {
 	branchResp, err := clt.CreateBranchWithResponse(ctx, repoName, apigen.CreateBranchJSONRequestBody{Name: "work", Source: "main"})
 	verifyResponseOK(t, branchResp, err)
 
-	const content = "awesome content"
-	resp, err := uploadObjectHelper(t, ctx, clt, "file1", strings.NewReader(content), repoName, "work")
-	verifyResponseOK(t, resp, err)
-
-	commitResp, err := clt.CommitWithResponse(ctx, repoName, "work", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "file 1 commit to work"})
-	verifyResponseOK(t, commitResp, err)
-
-	mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repoName, "work", "main", apigen.MergeIntoBranchJSONRequestBody{
-		Message: apiutil.Ptr("merge work to main"),
-	})
-	verifyResponseOK(t, mergeResp, err)
+	// change the same object differently on both branches
+	for _, branch := range []string{"main", "work"} {
+		resp, err := uploadObjectHelper(t, ctx, clt, "file1", strings.NewReader("content of "+branch), repoName, branch)
+		verifyResponseOK(t, resp, err)
+		commitResp, err := clt.CommitWithResponse(ctx, repoName, branch, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "file 1 commit to " + branch})
+		verifyResponseOK(t, commitResp, err)
+	}
 
-	diffResp, err := clt.DiffRefsWithResponse(ctx, repoName, "main~1", "main", &apigen.DiffRefsParams{})
-	verifyResponseOK(t, diffResp, err)
-	expectedSize := int64(len(content))
-	expectedResults := []apigen.Diff{
-		{Path: "file1", PathType: "object", Type: "added", SizeBytes: &expectedSize},
+	merge := func(strategy string) *apigen.MergeIntoBranchResponse {
+		t.Helper()
+		mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repoName, "work", "main", apigen.MergeIntoBranchJSONRequestBody{
+			Message:  apiutil.Ptr("merge work to main"),
+			Strategy: &strategy,
+		})
+		testutil.Must(t, err)
+		return mergeResp
 	}
-	if diff := deep.Equal(diffResp.JSON200.Results, expectedResults); diff != nil {
-		t.Fatal("Diff results not as expected:", diff)
+	for _, strategy := range []string{"", "fail"} {
+		if mergeResp := merge(strategy); mergeResp.JSON409 == nil {
+			t.Errorf("Merge with strategy %q got status %d, expected conflict", strategy, mergeResp.StatusCode())
+		}
 	}
+
+	mergeResp := merge("source-wins")
+	verifyResponseOK(t, mergeResp, err)
+	objResp, err := clt.GetObjectWithResponse(ctx, repoName, "main", &apigen.GetObjectParams{Path: "file1"})
+	verifyResponseOK(t, objResp, err)
+	require.Equal(t, "content of work", string(objResp.Body))
 }
 
-func TestController_MergeIntoExplicitBranch(t *testing.T) {
-	clt, deps := setupClientWithAdmin(t)
+func TestController_ObjectComments(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
 	ctx := context.Background()
 
-	// setup env
-	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
-	testutil.Must(t, err)
-	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
-	testutil.Must(t, err)
-	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
-	testutil.Must(t, err)
-	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
-	testutil.Must(t, err)
-
+	repoName := testUniqueRepoName()
+	repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+		DefaultBranch:    apiutil.Ptr("main"),
+		Name:             repoName,
+		StorageNamespace: "mem://",
+	})
+	verifyResponseOK(t, repoResp, err)
+	resp, err := uploadObjectHelper(t, ctx, clt, "data/file1", strings.NewReader("content"), repoName, "main")
+	verifyResponseOK(t, resp, err)
+	commitResp, err := clt.CommitWithResponse(ctx, repoName, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "add file1"})
+	verifyResponseOK(t, commitResp, err)
+	commitID := commitResp.JSON201.Id
+
+	commentResp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, "main", &apigen.CreateObjectCommentParams{Path: "data/file1"},
+		apigen.CreateObjectCommentJSONRequestBody{Body: "null counts look off"})
+	verifyResponseOK(t, commentResp, err)
+	comment := commentResp.JSON201
+	require.Equal(t, "main", comment.Ref)
+	require.Equal(t, commitID, comment.CommitId)
+	require.Equal(t, "null counts look off", comment.Body)
+	require.Nil(t, comment.ParentId)
+
+	replyResp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, commitID, &apigen.CreateObjectCommentParams{Path: "data/file1"},
+		apigen.CreateObjectCommentJSONRequestBody{Body: "fixed upstream", ParentId: apiutil.Ptr(comment.Id)})
+	verifyResponseOK(t, replyResp, err)
+
+	// comments on an object are not listed with those of objects under its path
+	resp, err = uploadObjectHelper(t, ctx, clt, "data/file1/nested", strings.NewReader("content"), repoName, "main")
+	verifyResponseOK(t, resp, err)
+	nestedResp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, "main", &apigen.CreateObjectCommentParams{Path: "data/file1/nested"},
+		apigen.CreateObjectCommentJSONRequestBody{Body: "uncommitted object"})
+	verifyResponseOK(t, nestedResp, err)
+
+	t.Run("list", func(t *testing.T) {
+		listResp, err := clt.ListObjectCommentsWithResponse(ctx, repoName, "main", &apigen.ListObjectCommentsParams{Path: "data/file1"})
+		verifyResponseOK(t, listResp, err)
+		require.False(t, listResp.JSON200.Pagination.HasMore)
+		require.Equal(t, []apigen.ObjectComment{*comment, *replyResp.JSON201}, listResp.JSON200.Results)
+
+		listResp, err = clt.ListObjectCommentsWithResponse(ctx, repoName, "main", &apigen.ListObjectCommentsParams{
+			Path:   "data/file1",
+			Amount: apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		verifyResponseOK(t, listResp, err)
+		require.True(t, listResp.JSON200.Pagination.HasMore)
+		require.Equal(t, []apigen.ObjectComment{*comment}, listResp.JSON200.Results)
+
+		listResp, err = clt.ListObjectCommentsWithResponse(ctx, repoName, "main", &apigen.ListObjectCommentsParams{
+			Path:  "data/file1",
+			After: apiutil.Ptr(apigen.PaginationAfter(listResp.JSON200.Pagination.NextOffset)),
+		})
+		verifyResponseOK(t, listResp, err)
+		require.Equal(t, []apigen.ObjectComment{*replyResp.JSON201}, listResp.JSON200.Results)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		resp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, "main", &apigen.CreateObjectCommentParams{Path: "data/no-such-file"},
+			apigen.CreateObjectCommentJSONRequestBody{Body: "comment"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		resp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, "main", &apigen.CreateObjectCommentParams{Path: "data/file1/nested"},
+			apigen.CreateObjectCommentJSONRequestBody{Body: "reply", ParentId: apiutil.Ptr(comment.Id)})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		resp, err := clt.CreateObjectCommentWithResponse(ctx, repoName, "main", &apigen.CreateObjectCommentParams{Path: "data/file1"},
+			apigen.CreateObjectCommentJSONRequestBody{Body: ""})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
+
+func TestController_Datasets(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repoName := testUniqueRepoName()
+	repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+		DefaultBranch:    apiutil.Ptr("main"),
+		Name:             repoName,
+		StorageNamespace: "mem://",
+	})
+	verifyResponseOK(t, repoResp, err)
+	for p, content := range map[string]string{
+		"tables/orders/_dataset.yaml":    "name: orders\nowner: sales\nschema: https://schemas.example.com/orders.avsc\nsla:\n  freshness: 24h\n",
+		"tables/orders/part-0.parquet":   "data",
+		"tables/users/_dataset.yaml":     "name: users\n",
+		"tables/visits/v1/_dataset.yaml": "name: visits\nowner: web\ntags:\n  domain: web\n",
+	} {
+		resp, err := uploadObjectHelper(t, ctx, clt, p, strings.NewReader(content), repoName, "main")
+		verifyResponseOK(t, resp, err)
+	}
+	orders := apigen.Dataset{
+		Path:   "tables/orders/_dataset.yaml",
+		Prefix: "tables/orders/",
+		Name:   apiutil.Ptr("orders"),
+		Owner:  apiutil.Ptr("sales"),
+		Schema: apiutil.Ptr("https://schemas.example.com/orders.avsc"),
+		Sla:    &apigen.DatasetSLA{Freshness: apiutil.Ptr("24h")},
+	}
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := clt.GetDatasetWithResponse(ctx, repoName, "main", &apigen.GetDatasetParams{Prefix: "tables/orders/"})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, orders, *resp.JSON200)
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		resp, err := clt.GetDatasetWithResponse(ctx, repoName, "main", &apigen.GetDatasetParams{Prefix: "tables/users/"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		resp, err := clt.GetDatasetWithResponse(ctx, repoName, "main", &apigen.GetDatasetParams{Prefix: "tables/"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListDatasetsWithResponse(ctx, repoName, "main", &apigen.ListDatasetsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("tables/")),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(2)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.True(t, resp.JSON200.Pagination.HasMore)
+		require.Len(t, resp.JSON200.Results, 2)
+		require.Equal(t, orders, resp.JSON200.Results[0])
+		users := resp.JSON200.Results[1]
+		require.Equal(t, "tables/users/", users.Prefix)
+		require.NotNil(t, users.Error)
+		require.Nil(t, users.Name)
+
+		resp, err = clt.ListDatasetsWithResponse(ctx, repoName, "main", &apigen.ListDatasetsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("tables/")),
+			After:  apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.False(t, resp.JSON200.Pagination.HasMore)
+		require.Equal(t, []apigen.Dataset{{
+			Path:   "tables/visits/v1/_dataset.yaml",
+			Prefix: "tables/visits/v1/",
+			Name:   apiutil.Ptr("visits"),
+			Owner:  apiutil.Ptr("web"),
+			Tags:   &apigen.Dataset_Tags{AdditionalProperties: map[string]string{"domain": "web"}},
+		}}, resp.JSON200.Results)
+	})
+
+	t.Run("bounded scan", func(t *testing.T) {
+		deps.catalog.DatasetsMaxScanEntries = 2
+		t.Cleanup(func() { deps.catalog.DatasetsMaxScanEntries = 0 })
+
+		resp, err := clt.ListDatasetsWithResponse(ctx, repoName, "main", &apigen.ListDatasetsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("tables/")),
+		})
+		verifyResponseOK(t, resp, err)
+		require.True(t, resp.JSON200.Pagination.HasMore)
+		require.Equal(t, []apigen.Dataset{orders}, resp.JSON200.Results)
+		require.Equal(t, "tables/orders/part-0.parquet", resp.JSON200.Pagination.NextOffset)
+
+		resp, err = clt.ListDatasetsWithResponse(ctx, repoName, "main", &apigen.ListDatasetsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("tables/")),
+			After:  apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.False(t, resp.JSON200.Pagination.HasMore)
+		var prefixes []string
+		for _, dataset := range resp.JSON200.Results {
+			prefixes = append(prefixes, dataset.Prefix)
+		}
+		require.Equal(t, []string{"tables/users/", "tables/visits/v1/"}, prefixes)
+	})
+}
+
+func TestController_ObjectTagConditions(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	// the user may read all objects but those tagged pii=true
+	const userID = "tag-reader"
+	createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: userID})
+	verifyResponseOK(t, createUserResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, userID, "FSFullAccess")
+	verifyResponseOK(t, attachResp, err)
+	policyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		Id: "DenyPII",
+		Statement: []apigen.Statement{{
+			Action:   []string{"fs:ReadObject"},
+			Effect:   "deny",
+			Resource: "*",
+			Condition: &apigen.Statement_Condition{AdditionalProperties: map[string]apigen.StatementConditionKeys{
+				"StringEquals": {AdditionalProperties: map[string][]string{"lakefs:ObjectTag/pii": {"true"}}},
+			}},
+		}},
+	})
+	verifyResponseOK(t, policyResp, err)
+	attachResp, err = clt.AttachPolicyToUserWithResponse(ctx, userID, "DenyPII")
+	verifyResponseOK(t, attachResp, err)
+	credsResp, err := clt.CreateCredentialsWithResponse(ctx, userID)
+	verifyResponseOK(t, credsResp, err)
+	userClt := setupClientByEndpoint(t, deps.server.URL, credsResp.JSON201.AccessKeyId, credsResp.JSON201.SecretAccessKey)
+
+	repo := testUniqueRepoName()
+	_, err = deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for p, tags := range map[string]map[string]string{
+		"data/public.csv":              {"team": "data"},
+		"data/pii.csv":                 {"pii": "true"},
+		"datasets/open/_dataset.yaml":  nil,
+		"datasets/users/_dataset.yaml": {"pii": "true"},
+	} {
+		resp, err := uploadObjectHelper(t, ctx, clt, p, strings.NewReader("name: "+p+"\n"), repo, "main")
+		verifyResponseOK(t, resp, err)
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", p, catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		entry.Tags = tags
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", *entry))
+	}
+
+	t.Run("diff_object_content", func(t *testing.T) {
+		resp, err := userClt.DiffObjectContentWithResponse(ctx, repo, "main", "main", &apigen.DiffObjectContentParams{
+			Path:      "data/public.csv",
+			RightPath: apiutil.Ptr("data/pii.csv"),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+
+	t.Run("list_objects_presign", func(t *testing.T) {
+		// presigning fails on the memory block adapter, so the listing succeeds only if no object is presigned
+		resp, err := userClt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix:  apiutil.Ptr(apigen.PaginationPrefix("data/pii")),
+			Presign: swag.Bool(true),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Nil(t, resp.JSON200.Results[0].PhysicalAddressExpiry)
+
+		resp, err = userClt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix:  apiutil.Ptr(apigen.PaginationPrefix("data/public")),
+			Presign: swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.NotEqual(t, http.StatusOK, resp.StatusCode(), "presign of a readable object")
+	})
+
+	t.Run("list_datasets", func(t *testing.T) {
+		resp, err := userClt.ListDatasetsWithResponse(ctx, repo, "main", &apigen.ListDatasetsParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "datasets/open/_dataset.yaml", resp.JSON200.Results[0].Path)
+	})
+
+	t.Run("get_dataset", func(t *testing.T) {
+		resp, err := userClt.GetDatasetWithResponse(ctx, repo, "main", &apigen.GetDatasetParams{Prefix: "datasets/users/"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+
+	t.Run("append_object", func(t *testing.T) {
+		// the appended object contains the data of the object
+		resp, err := userClt.AppendObjectWithBodyWithResponse(ctx, repo, "main", &apigen.AppendObjectParams{
+			Path: "data/pii.csv",
+		}, "text/csv", strings.NewReader("more\n"))
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+		resp, err = userClt.AppendObjectWithBodyWithResponse(ctx, repo, "main", &apigen.AppendObjectParams{
+			Path: "data/public.csv",
+		}, "text/csv", strings.NewReader("more\n"))
+		verifyResponseOK(t, resp, err)
+	})
+}
+
+func TestController_SearchObjects(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for i, p := range []string{"tables/customer_orders/part-0.parquet", "tables/users/part-0.parquet"} {
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            p,
+			PhysicalAddress: onBlock(deps, fmt.Sprintf("addr%d", i)),
+			CreationDate:    time.Now(),
+			Size:            1,
+			Checksum:        "cksum",
+			Metadata:        catalog.Metadata{"owner": "sales"},
+		})
+		testutil.Must(t, err)
+	}
+	commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "add tables"})
+	verifyResponseOK(t, commitResp, err)
+
+	// indexing is asynchronous
+	var results []apigen.SearchResult
+	require.Eventually(t, func() bool {
+		resp, err := clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{Q: "cust ord"})
+		if err != nil || resp.JSON200 == nil {
+			return false
+		}
+		results = resp.JSON200.Results
+		return len(results) > 0
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Len(t, results, 1)
+	require.Equal(t, repo, results[0].Repository)
+	require.Equal(t, "main", results[0].Branch)
+	require.Equal(t, "tables/customer_orders/part-0.parquet", results[0].Path)
+	require.Equal(t, commitResp.JSON201.Id, results[0].CommitId)
+	require.Equal(t, map[string]string{"owner": "sales"}, results[0].Metadata.AdditionalProperties)
+
+	t.Run("metadata", func(t *testing.T) {
+		resp, err := clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{
+			Q:          "sales",
+			Repository: apiutil.Ptr(repo),
+			Amount:     apiutil.Ptr(apigen.PaginationAmount(1)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.True(t, resp.JSON200.Pagination.HasMore)
+		require.Len(t, resp.JSON200.Results, 1)
+
+		resp, err = clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{
+			Q:          "sales",
+			Repository: apiutil.Ptr(repo),
+			After:      apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.False(t, resp.JSON200.Pagination.HasMore)
+		require.Len(t, resp.JSON200.Results, 1)
+	})
+
+	t.Run("other branch", func(t *testing.T) {
+		resp, err := clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{Q: "users", Branch: apiutil.Ptr("dev")})
+		verifyResponseOK(t, resp, err)
+		require.Empty(t, resp.JSON200.Results)
+	})
+
+	t.Run("no words", func(t *testing.T) {
+		resp, err := clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{Q: "/"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("delete repository", func(t *testing.T) {
+		resp, err := clt.DeleteRepositoryWithResponse(ctx, repo, &apigen.DeleteRepositoryParams{})
+		verifyResponseOK(t, resp, err)
+		require.Eventually(t, func() bool {
+			resp, err := clt.SearchObjectsWithResponse(ctx, &apigen.SearchObjectsParams{Q: "sales"})
+			return err == nil && resp.JSON200 != nil && len(resp.JSON200.Results) == 0
+		}, 10*time.Second, 50*time.Millisecond)
+	})
+}
+
+func TestController_MergeDiffWithParent(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repoName := testUniqueRepoName()
+	repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+		DefaultBranch:    apiutil.Ptr("main"),
+		Name:             repoName,
+		StorageNamespace: "mem://",
+	})
+	verifyResponseOK(t, repoResp, err)
+
+	branchResp, err := clt.CreateBranchWithResponse(ctx, repoName, apigen.CreateBranchJSONRequestBody{Name: "work", Source: "main"})
+	verifyResponseOK(t, branchResp, err)
+
+	const content = "awesome content"
+	resp, err := uploadObjectHelper(t, ctx, clt, "file1", strings.NewReader(content), repoName, "work")
+	verifyResponseOK(t, resp, err)
+
+	commitResp, err := clt.CommitWithResponse(ctx, repoName, "work", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{Message: "file 1 commit to work"})
+	verifyResponseOK(t, commitResp, err)
+
+	mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repoName, "work", "main", apigen.MergeIntoBranchJSONRequestBody{
+		Message: apiutil.Ptr("merge work to main"),
+	})
+	verifyResponseOK(t, mergeResp, err)
+
+	diffResp, err := clt.DiffRefsWithResponse(ctx, repoName, "main~1", "main", &apigen.DiffRefsParams{})
+	verifyResponseOK(t, diffResp, err)
+	expectedSize := int64(len(content))
+	expectedResults := []apigen.Diff{
+		{Path: "file1", PathType: "object", Type: "added", SizeBytes: &expectedSize},
+	}
+	if diff := deep.Equal(diffResp.JSON200.Results, expectedResults); diff != nil {
+		t.Fatal("Diff results not as expected:", diff)
+	}
+}
+
+func TestController_MergeIntoExplicitBranch(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	// setup env
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
+	testutil.Must(t, err)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+
 	// test branch with mods
 	table := []struct {
 		Name string
@@ -3826,6 +5726,43 @@ func TestController_MergeDirtyBranch(t *testing.T) {
 	}
 }
 
+func TestController_MergeFastForwardOnly(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"}))
+	commit1, err := deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+		FastForwardOnly: swag.Bool(true),
+	})
+	verifyResponseOK(t, resp, err)
+	require.Equal(t, commit1.Reference, resp.JSON200.Reference, "fast-forward moves the destination to the source commit")
+	reference, err := deps.catalog.GetBranchReference(ctx, repo, "main")
+	testutil.Must(t, err)
+	require.Equal(t, commit1.Reference, reference)
+
+	// main diverged from branch1
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar3", PhysicalAddress: "bar3addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum3"}))
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	resp, err = clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+		FastForwardOnly: swag.Bool(true),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusConflict, resp.StatusCode())
+}
+
 func TestController_CreateTag(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -4357,1171 +6294,3335 @@ func TestController_UpdatePolicy(t *testing.T) {
 	})
 }
 
-func TestController_GetPhysicalAddress(t *testing.T) {
-	clt, deps := setupClientWithAdmin(t)
+func TestController_PermissionBoundary(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
 	ctx := context.Background()
 
-	t.Run("physical_address_format", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		const (
-			ns     = "s3://foo-bucket1"
-			branch = "main"
-		)
-		_, err := deps.catalog.CreateRepository(ctx, repo, ns, branch, false)
-		if err != nil {
-			t.Fatal(err)
+	const (
+		userID   = "boundary-user"
+		groupID  = "boundary-group"
+		policyID = "BoundaryPolicy"
+	)
+	createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: userID})
+	verifyResponseOK(t, createUserResp, err)
+	createGroupResp, err := clt.CreateGroupWithResponse(ctx, apigen.CreateGroupJSONRequestBody{Id: groupID})
+	verifyResponseOK(t, createGroupResp, err)
+	createPolicyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		Id: policyID,
+		Statement: []apigen.Statement{
+			{Action: []string{"fs:*"}, Effect: "allow", Resource: "*"},
+			{Action: []string{"fs:DeleteObject"}, Effect: "deny", Resource: "arn:lakefs:fs:::repository/prod/*"},
+		},
+	})
+	verifyResponseOK(t, createPolicyResp, err)
+
+	t.Run("user", func(t *testing.T) {
+		getResp, err := clt.GetUserPermissionBoundaryWithResponse(ctx, userID)
+		testutil.Must(t, err)
+		if getResp.JSON404 == nil {
+			t.Fatalf("Get permission boundary of user without one should fail with 404: %s", getResp.Status())
 		}
 
-		var prevPartitionTime time.Time
-		const links = 5
-		for i := 0; i < links; i++ {
-			params := &apigen.GetPhysicalAddressParams{
-				Path: "get-path/obj" + strconv.Itoa(i),
-			}
-			resp, err := clt.GetPhysicalAddressWithResponse(ctx, repo, branch, params)
-			if err != nil {
-				t.Fatalf("GetPhysicalAddressWithResponse %s, failed: %s", params.Path, err)
-			}
-			if resp.JSON200 == nil {
-				t.Fatalf("GetPhysicalAddressWithResponse %s, non JSON 200 response: %s", params.Path, resp.Status())
-			}
+		setResp, err := clt.SetUserPermissionBoundaryWithResponse(ctx, userID, policyID)
+		verifyResponseOK(t, setResp, err)
+		getResp, err = clt.GetUserPermissionBoundaryWithResponse(ctx, userID)
+		verifyResponseOK(t, getResp, err)
+		if getResp.JSON200.Id != policyID {
+			t.Errorf("Permission boundary = %s, expected %s", getResp.JSON200.Id, policyID)
+		}
 
-			address := apiutil.Value(resp.JSON200.PhysicalAddress)
-			t.Log(address)
+		deleteResp, err := clt.DeleteUserPermissionBoundaryWithResponse(ctx, userID)
+		verifyResponseOK(t, deleteResp, err)
+		getResp, err = clt.GetUserPermissionBoundaryWithResponse(ctx, userID)
+		testutil.Must(t, err)
+		if getResp.JSON404 == nil {
+			t.Errorf("Get removed permission boundary should fail with 404: %s", getResp.Status())
+		}
+	})
 
-			const expectedPrefix = ns + "/" + upload.DefaultDataPrefix + "/"
-			if !strings.HasPrefix(address, expectedPrefix) {
-				t.Fatalf("GetPhysicalAddressWithResponse address=%s, expected prefix=%s", address, expectedPrefix)
-			}
-			const expectedParts = 3
-			objPath := address[len(ns)+1:]
-			parts := strings.Split(objPath, "/")
-			if len(parts) != expectedParts {
-				t.Fatalf("GetPhysicalAddressWithResponse path=%s, expected %d parts", objPath, expectedParts)
-			}
-			partitionID, err := xid.FromString(parts[1])
-			if err != nil {
-				t.Fatalf("GetPhysicalAddressWithResponse unknown partition format (path=%s): %s", objPath, err)
-			}
-			partitionTime := partitionID.Time()
-			if i > 0 && partitionTime.After(prevPartitionTime) {
-				t.Fatalf("GetPhysicalAddressWithResponse partition time '%s', should be equal or smaller than previous '%s'", partitionTime, prevPartitionTime)
-			}
-			prevPartitionTime = partitionTime
+	t.Run("group", func(t *testing.T) {
+		setResp, err := clt.SetGroupPermissionBoundaryWithResponse(ctx, groupID, policyID)
+		verifyResponseOK(t, setResp, err)
+		getResp, err := clt.GetGroupPermissionBoundaryWithResponse(ctx, groupID)
+		verifyResponseOK(t, getResp, err)
+		if getResp.JSON200.Id != policyID {
+			t.Errorf("Permission boundary = %s, expected %s", getResp.JSON200.Id, policyID)
+		}
+		deleteResp, err := clt.DeleteGroupPermissionBoundaryWithResponse(ctx, groupID)
+		verifyResponseOK(t, deleteResp, err)
+	})
+
+	t.Run("unknown_policy", func(t *testing.T) {
+		setResp, err := clt.SetUserPermissionBoundaryWithResponse(ctx, userID, "UnknownPolicy")
+		testutil.Must(t, err)
+		if setResp.JSON404 == nil {
+			t.Errorf("Set unknown permission boundary policy should fail with 404: %s", setResp.Status())
 		}
 	})
 }
 
-func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
-	clt, deps := setupClientWithAdmin(t)
+func TestController_SimulateAuthorization(t *testing.T) {
+	clt, _ := setupClientWithAdmin(t)
 	ctx := context.Background()
 
-	verifyPrepareGarbageCollection := func(t *testing.T, repo string, expectedCalls int, expectUncommittedData bool) {
-		t.Helper()
-		var (
-			calls int
-			token *string
-		)
-		for {
-			calls++
-			resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, repo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{
-				ContinuationToken: token,
-			})
-			verifyResponseOK(t, resp, err)
-			if resp.JSON201 == nil {
-				t.Fatalf("PrepareGarbageCollectionUncommitted status code:%d, expected 201", resp.StatusCode())
-			}
-			if resp.JSON201.RunId == "" {
-				t.Errorf("PrepareGarbageCollectionUncommitted empty RunID, value expected")
-			}
+	const (
+		userID   = "simulated-user"
+		policyID = "SimulatedPolicy"
+	)
+	createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: userID})
+	verifyResponseOK(t, createUserResp, err)
+	createPolicyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		Id: policyID,
+		Statement: []apigen.Statement{
+			{Action: []string{"fs:*"}, Effect: "allow", Resource: "*"},
+			{Action: []string{"fs:DeleteObject"}, Effect: "deny", Resource: "arn:lakefs:fs:::repository/prod/*"},
+		},
+	})
+	verifyResponseOK(t, createPolicyResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, userID, policyID)
+	verifyResponseOK(t, attachResp, err)
 
-			if expectUncommittedData {
-				if resp.JSON201.GcUncommittedLocation == "" {
-					t.Errorf("PrepareGarbageCollectionUncommitted empty GcUncommittedLocation, value expected")
-				}
-			} else {
-				if resp.JSON201.GcUncommittedLocation != "" {
-					t.Errorf("PrepareGarbageCollectionUncommitted empty GcUncommittedLocation, no value expected")
-				}
-			}
+	t.Run("allowed", func(t *testing.T) {
+		resp, err := clt.SimulateAuthorizationWithResponse(ctx, userID, apigen.SimulateAuthorizationJSONRequestBody{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn("prod", "file"),
+		})
+		verifyResponseOK(t, resp, err)
+		if !resp.JSON200.Allowed || resp.JSON200.Denial != nil {
+			t.Errorf("Simulate allowed read = %+v, expected allowed without denial", resp.JSON200)
+		}
+	})
 
-			token = resp.JSON201.ContinuationToken
-			if token == nil || *token == "" {
-				break
-			}
+	t.Run("explicit_deny", func(t *testing.T) {
+		resp, err := clt.SimulateAuthorizationWithResponse(ctx, userID, apigen.SimulateAuthorizationJSONRequestBody{
+			Action:   permissions.DeleteObjectAction,
+			Resource: permissions.ObjectArn("prod", "file"),
+		})
+		verifyResponseOK(t, resp, err)
+		denial := resp.JSON200.Denial
+		if resp.JSON200.Allowed || denial == nil {
+			t.Fatalf("Simulate denied delete = %+v, expected denial", resp.JSON200)
 		}
-		if calls != expectedCalls {
-			t.Fatalf("PrepareGarbageCollectionUncommitted calls=%d, expected=%d", calls, expectedCalls)
+		if denial.Reason != string(auth.DenialReasonExplicitDeny) || swag.StringValue(denial.Policy) != policyID || swag.IntValue(denial.Statement) != 1 {
+			t.Errorf("Denial = %s by %s statement %d, expected %s by %s statement 1",
+				denial.Reason, swag.StringValue(denial.Policy), swag.IntValue(denial.Statement), auth.DenialReasonExplicitDeny, policyID)
 		}
-	}
+	})
 
-	t.Run("no_repository", func(t *testing.T) {
-		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, "", apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
-		if err != nil {
-			t.Fatalf("PrepareGarbageCollectionUncommitted failed: %s", err)
+	t.Run("no_allow", func(t *testing.T) {
+		resp, err := clt.SimulateAuthorizationWithResponse(ctx, userID, apigen.SimulateAuthorizationJSONRequestBody{
+			Action:   permissions.CreateUserAction,
+			Resource: permissions.UserArn("someone"),
+		})
+		verifyResponseOK(t, resp, err)
+		denial := resp.JSON200.Denial
+		if resp.JSON200.Allowed || denial == nil || denial.Reason != string(auth.DenialReasonNoAllow) || denial.Policy != nil {
+			t.Errorf("Simulate create user = %+v, expected denial without policy", resp.JSON200)
 		}
+	})
+
+	t.Run("invalid_action", func(t *testing.T) {
+		resp, err := clt.SimulateAuthorizationWithResponse(ctx, userID, apigen.SimulateAuthorizationJSONRequestBody{
+			Action:   "ReadObject",
+			Resource: "*",
+		})
+		testutil.Must(t, err)
 		if resp.JSON400 == nil {
-			t.Fatalf("PrepareGarbageCollectionUncommitted expected BadRequest: %+v", resp)
+			t.Errorf("Simulate invalid action expected 400, got %s", resp.Status())
 		}
 	})
 
-	t.Run("repository_not_exists", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, repo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
-		if err != nil {
-			t.Fatalf("PrepareGarbageCollectionUncommitted failed: %s", err)
-		}
+	t.Run("unknown_user", func(t *testing.T) {
+		resp, err := clt.SimulateAuthorizationWithResponse(ctx, "no-such-user", apigen.SimulateAuthorizationJSONRequestBody{
+			Action:   permissions.ReadObjectAction,
+			Resource: "*",
+		})
+		testutil.Must(t, err)
 		if resp.JSON404 == nil {
-			t.Fatalf("PrepareGarbageCollectionUncommitted expected NotFound: %+v", resp)
+			t.Errorf("Simulate unknown user expected 404, got %s", resp.Status())
 		}
 	})
+}
 
-	t.Run("uncommitted_data", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
-		testutil.Must(t, err)
-		const items = 3
-		for i := 0; i < items; i++ {
-			objPath := fmt.Sprintf("uncommitted/obj%d", i)
-			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
-			verifyResponseOK(t, uploadResp, err)
+func TestController_Organizations(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	createOrgResp, err := clt.CreateOrganizationWithResponse(ctx, apigen.CreateOrganizationJSONRequestBody{
+		Id:               "acme",
+		StorageNamespace: swag.String(onBlock(deps, "acme/")),
+		MaxRepositories:  swag.Int(2),
+	})
+	verifyResponseOK(t, createOrgResp, err)
+	createOrgResp, err = clt.CreateOrganizationWithResponse(ctx, apigen.CreateOrganizationJSONRequestBody{Id: "acme-corp"})
+	testutil.Must(t, err)
+	if createOrgResp.JSON400 == nil {
+		t.Errorf("Create organization with invalid ID expected 400, got %s", createOrgResp.Status())
+	}
+
+	// members get the permissions of their policies within the namespace of the organization
+	const memberID = "acme-ops"
+	createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: memberID})
+	verifyResponseOK(t, createUserResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, memberID, "FSFullAccess")
+	verifyResponseOK(t, attachResp, err)
+	attachResp, err = clt.AttachPolicyToUserWithResponse(ctx, memberID, "AuthFullAccess")
+	verifyResponseOK(t, attachResp, err)
+	credsResp, err := clt.CreateCredentialsWithResponse(ctx, memberID)
+	verifyResponseOK(t, credsResp, err)
+	memberClt := setupClientByEndpoint(t, deps.server.URL, credsResp.JSON201.AccessKeyId, credsResp.JSON201.SecretAccessKey)
+
+	for _, repo := range []string{"acme-one", "other"} {
+		repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             repo,
+			StorageNamespace: onBlock(deps, repo),
+		})
+		verifyResponseOK(t, repoResp, err)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		reposResp, err := memberClt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{})
+		verifyResponseOK(t, reposResp, err)
+		if len(reposResp.JSON200.Results) != 1 || reposResp.JSON200.Results[0].Id != "acme-one" {
+			t.Errorf("List repositories of member got %+v, expected acme-one", reposResp.JSON200.Results)
+		}
+		usersResp, err := memberClt.ListUsersWithResponse(ctx, &apigen.ListUsersParams{})
+		verifyResponseOK(t, usersResp, err)
+		if len(usersResp.JSON200.Results) != 1 || usersResp.JSON200.Results[0].Id != memberID {
+			t.Errorf("List users of member got %+v, expected %s", usersResp.JSON200.Results, memberID)
+		}
+		otherPrefix := apigen.PaginationPrefix("oth")
+		reposResp, err = memberClt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{Prefix: &otherPrefix})
+		verifyResponseOK(t, reposResp, err)
+		if len(reposResp.JSON200.Results) != 0 {
+			t.Errorf("List repositories of member out of namespace got %+v, expected none", reposResp.JSON200.Results)
 		}
-		verifyPrepareGarbageCollection(t, repo, 1, true)
 	})
 
-	t.Run("committed_data", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	t.Run("access", func(t *testing.T) {
+		getResp, err := memberClt.GetRepositoryWithResponse(ctx, "other")
 		testutil.Must(t, err)
-		const items = 3
-		for i := 0; i < items; i++ {
-			objPath := fmt.Sprintf("committed/obj%d", i)
-			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
-			verifyResponseOK(t, uploadResp, err)
+		if getResp.JSON401 == nil {
+			t.Errorf("Get repository out of namespace expected 401, got %s", getResp.Status())
 		}
-		if _, err := deps.catalog.Commit(ctx, repo, "main", "committed objects", "some_user", nil, nil, nil, false); err != nil {
-			t.Fatalf("failed to commit objects: %s", err)
+		repoResp, err := memberClt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             "acme-two",
+			StorageNamespace: onBlock(deps, "elsewhere/acme-two"),
+		})
+		testutil.Must(t, err)
+		if repoResp.JSON401 == nil {
+			t.Errorf("Create repository out of storage namespace expected 401, got %s", repoResp.Status())
 		}
-		verifyPrepareGarbageCollection(t, repo, 1, false)
+		repoResp, err = memberClt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             "acme-two",
+			StorageNamespace: onBlock(deps, "acme/two"),
+		})
+		verifyResponseOK(t, repoResp, err)
 	})
 
-	t.Run("uncommitted_copy", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	t.Run("quota", func(t *testing.T) {
+		repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             "acme-three",
+			StorageNamespace: onBlock(deps, "acme/three"),
+		})
 		testutil.Must(t, err)
-		const items = 3
-		for i := 0; i < items; i++ {
-			objPath := fmt.Sprintf("uncommitted/obj%d", i)
-			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
-			verifyResponseOK(t, uploadResp, err)
+		if repoResp.JSON403 == nil {
+			t.Errorf("Create repository over quota expected 403, got %s", repoResp.Status())
+		}
+	})
 
-			copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main",
-				&apigen.CopyObjectParams{DestPath: fmt.Sprintf("copy/obj%d", i)},
-				apigen.CopyObjectJSONRequestBody{
-					SrcPath: objPath,
-				})
-			verifyResponseOK(t, copyResp, err)
+	t.Run("group_membership", func(t *testing.T) {
+		groupResp, err := memberClt.CreateGroupWithResponse(ctx, apigen.CreateGroupJSONRequestBody{Id: "acme-team"})
+		verifyResponseOK(t, groupResp, err)
+		userResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: "outsider"})
+		verifyResponseOK(t, userResp, err)
+		addResp, err := memberClt.AddGroupMembershipWithResponse(ctx, "acme-team", "outsider")
+		testutil.Must(t, err)
+		if addResp.StatusCode() != http.StatusForbidden {
+			t.Errorf("Add user of another organization to group expected 403, got %s", addResp.Status())
 		}
-		verifyPrepareGarbageCollection(t, repo, 1, true)
+		addResp, err = memberClt.AddGroupMembershipWithResponse(ctx, "acme-team", memberID)
+		verifyResponseOK(t, addResp, err)
 	})
-}
 
-func TestController_ClientDisconnect(t *testing.T) {
-	handler, deps := setupHandlerWithWalkerFactory(t, store.NewFactory(nil))
+	t.Run("members", func(t *testing.T) {
+		addResp, err := clt.AddOrganizationMemberWithResponse(ctx, "acme", "outsider")
+		verifyResponseOK(t, addResp, err)
+		membersResp, err := clt.ListOrganizationMembersWithResponse(ctx, "acme", &apigen.ListOrganizationMembersParams{})
+		verifyResponseOK(t, membersResp, err)
+		if len(membersResp.JSON200.Results) != 1 || membersResp.JSON200.Results[0].Id != "outsider" {
+			t.Errorf("List organization members got %+v, expected outsider", membersResp.JSON200.Results)
+		}
+		removeResp, err := clt.RemoveOrganizationMemberWithResponse(ctx, "acme", "outsider")
+		verifyResponseOK(t, removeResp, err)
 
-	// setup lakefs
-	server := setupServer(t, handler)
-	clt := setupClientByEndpoint(t, server.URL, "", "")
-	cred := createDefaultAdminUser(t, clt)
+		// members may not manage organizations
+		orgResp, err := memberClt.GetOrganizationWithResponse(ctx, "acme")
+		testutil.Must(t, err)
+		if orgResp.JSON401 == nil {
+			t.Errorf("Get organization by member expected 401, got %s", orgResp.Status())
+		}
+	})
 
-	// setup repository
+	t.Run("update_delete", func(t *testing.T) {
+		updateResp, err := clt.UpdateOrganizationWithResponse(ctx, "acme", apigen.UpdateOrganizationJSONRequestBody{Description: swag.String("Acme")})
+		verifyResponseOK(t, updateResp, err)
+		if swag.StringValue(updateResp.JSON200.Description) != "Acme" || swag.IntValue(updateResp.JSON200.MaxRepositories) != 0 {
+			t.Errorf("Update organization got %+v", updateResp.JSON200)
+		}
+		deleteResp, err := clt.DeleteOrganizationWithResponse(ctx, "acme")
+		verifyResponseOK(t, deleteResp, err)
+		listResp, err := clt.ListOrganizationsWithResponse(ctx, &apigen.ListOrganizationsParams{})
+		verifyResponseOK(t, listResp, err)
+		if len(listResp.JSON200.Results) != 0 {
+			t.Errorf("List organizations after delete got %+v", listResp.JSON200.Results)
+		}
+	})
+}
+
+func TestController_AccessTokens(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
-	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
-	testutil.Must(t, err)
 
-	// prepare a client that will not wait for a response and timeout
-	dialer := net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			ResponseHeaderTimeout: time.Nanosecond,
-			DialContext:           dialer.DialContext,
-			DisableKeepAlives:     true,
-			IdleConnTimeout:       90 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			ForceAttemptHTTP2:     false,
+	currentUserResp, err := clt.GetCurrentUserWithResponse(ctx)
+	verifyResponseOK(t, currentUserResp, err)
+	userID := currentUserResp.JSON200.User.Id
+
+	createResp, err := clt.CreateUserAccessTokenWithResponse(ctx, userID, apigen.CreateUserAccessTokenJSONRequestBody{
+		Description:    apiutil.Ptr("ci"),
+		ExpirationDate: time.Now().Add(time.Hour).Unix(),
+		Scope: apigen.AccessTokenScope{
+			Actions:      []string{"fs:ReadRepository"},
+			Repositories: &[]string{"allowed-repo"},
 		},
-	}
-	clt = setupClientByEndpoint(t, server.URL, cred.AccessKeyID, cred.SecretAccessKey, apigen.WithHTTPClient(httpClient))
+	})
+	verifyResponseOK(t, createResp, err)
+	tokenID := createResp.JSON201.AccessToken.Id
+	tokenClt := setupClientByEndpoint(t, deps.server.URL, "", "", apigen.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+createResp.JSON201.Token)
+		return nil
+	}))
 
-	// upload request
-	contentType, reader := writeMultipart("content", "file.data", "something special")
-	_, err = clt.UploadObjectWithBodyWithResponse(ctx, repo, "main", &apigen.UploadObjectParams{
-		Path: "test/file.data",
-	}, contentType, reader)
-	if err == nil {
-		t.Fatal("Expected to request complete without error, expected to fail")
-	}
+	t.Run("in_scope", func(t *testing.T) {
+		// the repository does not exist, but the request is authorized
+		resp, err := tokenClt.GetRepositoryWithResponse(ctx, "allowed-repo")
+		testutil.Must(t, err)
+		if resp.JSON404 == nil {
+			t.Errorf("GetRepository in token scope expected 404, got %s", resp.Status())
+		}
+	})
 
-	// wait for the server to identify we left and update the counter
-	time.Sleep(time.Second)
+	t.Run("out_of_scope", func(t *testing.T) {
+		repoResp, err := tokenClt.GetRepositoryWithResponse(ctx, "other-repo")
+		testutil.Must(t, err)
+		if repoResp.JSON401 == nil {
+			t.Errorf("GetRepository out of token scope expected 401, got %s", repoResp.Status())
+		}
+		userResp, err := tokenClt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: "token-user"})
+		testutil.Must(t, err)
+		if userResp.JSON401 == nil {
+			t.Errorf("CreateUser out of token scope expected 401, got %s", userResp.Status())
+		}
+	})
 
-	// request for metrics
-	metricsResp, err := http.Get(server.URL + "/metrics")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		_ = metricsResp.Body.Close()
-	}()
-	body, err := io.ReadAll(metricsResp.Body)
-	if err != nil {
-		t.Fatal(err)
-	}
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListUserAccessTokensWithResponse(ctx, userID, &apigen.ListUserAccessTokensParams{})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 {
+			t.Fatalf("ListUserAccessTokens got %d tokens, expected 1", len(resp.JSON200.Results))
+		}
+		token := resp.JSON200.Results[0]
+		if token.Id != tokenID || token.LastUsedDate == nil {
+			t.Errorf("ListUserAccessTokens got token %s last used %v, expected used token %s", token.Id, token.LastUsedDate, tokenID)
+		}
+	})
 
-	// process relevant metrics
-	const apiReqTotalMetricLabel = `api_requests_total{code="499",method="post"}`
-	var clientRequestClosedCount int
-	for _, line := range strings.Split(string(body), "\n") {
-		if strings.HasPrefix(line, apiReqTotalMetricLabel) {
-			if count, err := strconv.Atoi(line[len(apiReqTotalMetricLabel)+1:]); err == nil {
-				clientRequestClosedCount += count
+	t.Run("created_by_token", func(t *testing.T) {
+		// tokens scoped to repositories do not cover user resources, creating tokens requires a token of all repositories
+		adminTokenResp, err := clt.CreateUserAccessTokenWithResponse(ctx, userID, apigen.CreateUserAccessTokenJSONRequestBody{
+			ExpirationDate: time.Now().Add(time.Hour).Unix(),
+			Scope:          apigen.AccessTokenScope{Actions: []string{"auth:CreateCredentials", "fs:Read*"}},
+		})
+		verifyResponseOK(t, adminTokenResp, err)
+		adminTokenClt := setupClientByEndpoint(t, deps.server.URL, "", "", apigen.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+adminTokenResp.JSON201.Token)
+			return nil
+		}))
+		cases := []struct {
+			name         string
+			actions      []string
+			repositories *[]string
+			expiresIn    time.Duration
+			expected     int
+		}{
+			{name: "narrower", actions: []string{"fs:ReadObject"}, repositories: &[]string{"allowed-repo"}, expiresIn: time.Minute, expected: http.StatusCreated},
+			{name: "wider actions", actions: []string{"fs:*"}, expiresIn: time.Minute, expected: http.StatusUnauthorized},
+			{name: "other actions", actions: []string{"auth:CreateUser"}, expiresIn: time.Minute, expected: http.StatusUnauthorized},
+			{name: "expires later", actions: []string{"fs:ReadObject"}, expiresIn: 2 * time.Hour, expected: http.StatusUnauthorized},
+		}
+		for _, tc := range cases {
+			resp, err := adminTokenClt.CreateUserAccessTokenWithResponse(ctx, userID, apigen.CreateUserAccessTokenJSONRequestBody{
+				ExpirationDate: time.Now().Add(tc.expiresIn).Unix(),
+				Scope:          apigen.AccessTokenScope{Actions: tc.actions, Repositories: tc.repositories},
+			})
+			testutil.Must(t, err)
+			if resp.StatusCode() != tc.expected {
+				t.Errorf("CreateUserAccessToken %s by token got %s, expected %d", tc.name, resp.Status(), tc.expected)
 			}
 		}
-	}
+	})
 
-	const expectedCount = 1
-	if clientRequestClosedCount != expectedCount {
-		t.Fatalf("Metric for client request closed: %d, expected: %d", clientRequestClosedCount, expectedCount)
-	}
+	t.Run("expired", func(t *testing.T) {
+		resp, err := clt.CreateUserAccessTokenWithResponse(ctx, userID, apigen.CreateUserAccessTokenJSONRequestBody{
+			ExpirationDate: time.Now().Add(-time.Hour).Unix(),
+			Scope:          apigen.AccessTokenScope{Actions: []string{"fs:*"}},
+		})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Errorf("CreateUserAccessToken already expired expected 400, got %s", resp.Status())
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		deleteResp, err := clt.DeleteUserAccessTokenWithResponse(ctx, userID, tokenID)
+		verifyResponseOK(t, deleteResp, err)
+		resp, err := tokenClt.GetRepositoryWithResponse(ctx, "allowed-repo")
+		testutil.Must(t, err)
+		if resp.JSON401 == nil {
+			t.Errorf("GetRepository with deleted token expected 401, got %s", resp.Status())
+		}
+	})
 }
 
-func TestController_PostStatsEvents(t *testing.T) {
+// TestController_OrganizationsWithProvisioning verifies that organizations and access tokens keep
+// working when the auth service is wrapped by the provisioning webhook
+func TestController_OrganizationsWithProvisioning(t *testing.T) {
+	approval := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(provisioning.Response{Allowed: true})
+	}))
+	t.Cleanup(approval.Close)
+	viper.Set("auth.provisioning_webhook.enabled", true)
+	viper.Set("auth.provisioning_webhook.endpoint", approval.URL)
+	t.Cleanup(func() { viper.Set("auth.provisioning_webhook.enabled", false) })
+
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
+	if _, ok := deps.authService.(auth.OrganizationManager); !ok {
+		t.Fatal("auth service with provisioning does not manage organizations")
+	}
 
-	type key struct {
-		class string
-		name  string
+	createOrgResp, err := clt.CreateOrganizationWithResponse(ctx, apigen.CreateOrganizationJSONRequestBody{Id: "acme"})
+	verifyResponseOK(t, createOrgResp, err)
+	const memberID = "acme-ops"
+	createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: memberID})
+	verifyResponseOK(t, createUserResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, memberID, "FSFullAccess")
+	verifyResponseOK(t, attachResp, err)
+	credsResp, err := clt.CreateCredentialsWithResponse(ctx, memberID)
+	verifyResponseOK(t, credsResp, err)
+	memberClt := setupClientByEndpoint(t, deps.server.URL, credsResp.JSON201.AccessKeyId, credsResp.JSON201.SecretAccessKey)
+	for _, repo := range []string{"acme-one", "other"} {
+		repoResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:             repo,
+			StorageNamespace: onBlock(deps, repo),
+		})
+		verifyResponseOK(t, repoResp, err)
 	}
 
-	tests := []struct {
-		name                string
-		events              []apigen.StatsEvent
-		expectedEventCounts map[key]int
-		expectedStatusCode  int
-	}{
-		{
-			name: "single_event_count_1",
-			events: []apigen.StatsEvent{
-				{
-					Class: "single_event_count_1",
-					Name:  "name",
-					Count: 1,
+	t.Run("organization_isolation", func(t *testing.T) {
+		reposResp, err := memberClt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{})
+		verifyResponseOK(t, reposResp, err)
+		if len(reposResp.JSON200.Results) != 1 || reposResp.JSON200.Results[0].Id != "acme-one" {
+			t.Errorf("List repositories of member got %+v, expected acme-one", reposResp.JSON200.Results)
+		}
+		getResp, err := memberClt.GetRepositoryWithResponse(ctx, "other")
+		testutil.Must(t, err)
+		if getResp.JSON401 == nil {
+			t.Errorf("Get repository out of namespace expected 401, got %s", getResp.Status())
+		}
+	})
+
+	t.Run("access_tokens", func(t *testing.T) {
+		createResp, err := clt.CreateUserAccessTokenWithResponse(ctx, memberID, apigen.CreateUserAccessTokenJSONRequestBody{
+			ExpirationDate: time.Now().Add(time.Hour).Unix(),
+			Scope:          apigen.AccessTokenScope{Actions: []string{"fs:ReadRepository"}},
+		})
+		verifyResponseOK(t, createResp, err)
+		tokenClt := setupClientByEndpoint(t, deps.server.URL, "", "", apigen.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+createResp.JSON201.Token)
+			return nil
+		}))
+		getResp, err := tokenClt.GetRepositoryWithResponse(ctx, "acme-one")
+		verifyResponseOK(t, getResp, err)
+		getResp, err = tokenClt.GetRepositoryWithResponse(ctx, "other")
+		testutil.Must(t, err)
+		if getResp.JSON401 == nil {
+			t.Errorf("Get repository out of namespace with token expected 401, got %s", getResp.Status())
+		}
+	})
+}
+
+func TestController_ListCredentialsUsage(t *testing.T) {
+	viper.Set("auth.usage.enabled", true)
+	t.Cleanup(func() { viper.Set("auth.usage.enabled", false) })
+	clt, _ := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	currentUserResp, err := clt.GetCurrentUserWithResponse(ctx)
+	verifyResponseOK(t, currentUserResp, err)
+	userID := currentUserResp.JSON200.User.Id
+
+	resp, err := clt.ListCredentialsUsageWithResponse(ctx, &apigen.ListCredentialsUsageParams{})
+	verifyResponseOK(t, resp, err)
+	var before *apigen.CredentialsUsage
+	for i := range resp.JSON200.Results {
+		if resp.JSON200.Results[i].UserId == userID {
+			before = &resp.JSON200.Results[i]
+		}
+	}
+	if before == nil {
+		t.Fatalf("ListCredentialsUsage did not list the credentials of %s", userID)
+	}
+
+	// a failed request counts as an error
+	repoResp, err := clt.GetRepositoryWithResponse(ctx, "no-such-repository")
+	testutil.Must(t, err)
+	if repoResp.JSON404 == nil {
+		t.Fatalf("GetRepository got status %d, expected not found", repoResp.StatusCode())
+	}
+
+	resp, err = clt.ListCredentialsUsageWithResponse(ctx, &apigen.ListCredentialsUsageParams{})
+	verifyResponseOK(t, resp, err)
+	var after *apigen.CredentialsUsage
+	for i := range resp.JSON200.Results {
+		if resp.JSON200.Results[i].AccessKeyId == before.AccessKeyId {
+			after = &resp.JSON200.Results[i]
+		}
+	}
+	if after == nil {
+		t.Fatalf("ListCredentialsUsage did not list the usage of %s", before.AccessKeyId)
+	}
+	// usage is recorded once a request is served: the previous listing and the failed request
+	if after.Requests != before.Requests+2 {
+		t.Errorf("Requests = %d, expected %d", after.Requests, before.Requests+2)
+	}
+	if after.Errors != before.Errors+1 {
+		t.Errorf("Errors = %d, expected %d", after.Errors, before.Errors+1)
+	}
+	if after.BytesSent <= before.BytesSent {
+		t.Errorf("BytesSent = %d, expected more than %d", after.BytesSent, before.BytesSent)
+	}
+}
+
+func TestController_Sessions(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	currentUserResp, err := clt.GetCurrentUserWithResponse(ctx)
+	verifyResponseOK(t, currentUserResp, err)
+	userID := currentUserResp.JSON200.User.Id
+	credsResp, err := clt.CreateCredentialsWithResponse(ctx, userID)
+	verifyResponseOK(t, credsResp, err)
+	creds := credsResp.JSON201
+
+	token := testGenerateApiToken(ctx, t, clt, &model.BaseCredential{
+		AccessKeyID:     creds.AccessKeyId,
+		SecretAccessKey: creds.SecretAccessKey,
+	})
+	tokenClt := setupClientByEndpoint(t, deps.server.URL, "", "", apigen.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}))
+	keyClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyId, creds.SecretAccessKey)
+
+	t.Run("list_credentials", func(t *testing.T) {
+		userResp, err := keyClt.GetCurrentUserWithResponse(ctx)
+		verifyResponseOK(t, userResp, err)
+		resp, err := clt.ListCredentialsActivityWithResponse(ctx, &apigen.ListCredentialsActivityParams{})
+		verifyResponseOK(t, resp, err)
+		found := false
+		for _, c := range resp.JSON200.Results {
+			if c.AccessKeyId != creds.AccessKeyId {
+				continue
+			}
+			found = true
+			if c.UserId != userID || c.LastUsedDate == nil || c.LastUsedIp == nil {
+				t.Errorf("ListCredentialsActivity got %+v, expected credentials of %s used", c, userID)
+			}
+		}
+		if !found {
+			t.Errorf("ListCredentialsActivity did not list credentials %s", creds.AccessKeyId)
+		}
+	})
+
+	t.Run("list_sessions", func(t *testing.T) {
+		userResp, err := tokenClt.GetCurrentUserWithResponse(ctx)
+		verifyResponseOK(t, userResp, err)
+		resp, err := clt.ListLoginSessionsWithResponse(ctx, &apigen.ListLoginSessionsParams{})
+		verifyResponseOK(t, resp, err)
+		if len(resp.JSON200.Results) != 1 || resp.JSON200.Results[0].UserId != userID {
+			t.Fatalf("ListLoginSessions got %+v, expected a session of %s", resp.JSON200.Results, userID)
+		}
+	})
+
+	t.Run("revoke_session", func(t *testing.T) {
+		listResp, err := clt.ListLoginSessionsWithResponse(ctx, &apigen.ListLoginSessionsParams{})
+		verifyResponseOK(t, listResp, err)
+		revokeResp, err := clt.RevokeLoginSessionWithResponse(ctx, listResp.JSON200.Results[0].Id)
+		verifyResponseOK(t, revokeResp, err)
+		resp, err := tokenClt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{})
+		testutil.Must(t, err)
+		if resp.JSON401 == nil {
+			t.Errorf("ListRepositories with revoked session expected 401, got %s", resp.Status())
+		}
+	})
+
+	t.Run("revoke_credentials", func(t *testing.T) {
+		revokeResp, err := clt.RevokeCredentialsWithResponse(ctx, creds.AccessKeyId)
+		verifyResponseOK(t, revokeResp, err)
+		resp, err := keyClt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{})
+		testutil.Must(t, err)
+		if resp.JSON401 == nil {
+			t.Errorf("ListRepositories with revoked credentials expected 401, got %s", resp.Status())
+		}
+		revokeResp, err = clt.RevokeCredentialsWithResponse(ctx, creds.AccessKeyId)
+		testutil.Must(t, err)
+		if revokeResp.JSON404 == nil {
+			t.Errorf("RevokeCredentials of revoked credentials expected 404, got %s", revokeResp.Status())
+		}
+	})
+}
+
+func TestController_GetPhysicalAddress(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	t.Run("physical_address_format", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		const (
+			ns     = "s3://foo-bucket1"
+			branch = "main"
+		)
+		_, err := deps.catalog.CreateRepository(ctx, repo, ns, branch, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var prevPartitionTime time.Time
+		const links = 5
+		for i := 0; i < links; i++ {
+			params := &apigen.GetPhysicalAddressParams{
+				Path: "get-path/obj" + strconv.Itoa(i),
+			}
+			resp, err := clt.GetPhysicalAddressWithResponse(ctx, repo, branch, params)
+			if err != nil {
+				t.Fatalf("GetPhysicalAddressWithResponse %s, failed: %s", params.Path, err)
+			}
+			if resp.JSON200 == nil {
+				t.Fatalf("GetPhysicalAddressWithResponse %s, non JSON 200 response: %s", params.Path, resp.Status())
+			}
+
+			address := apiutil.Value(resp.JSON200.PhysicalAddress)
+			t.Log(address)
+
+			const expectedPrefix = ns + "/" + upload.DefaultDataPrefix + "/"
+			if !strings.HasPrefix(address, expectedPrefix) {
+				t.Fatalf("GetPhysicalAddressWithResponse address=%s, expected prefix=%s", address, expectedPrefix)
+			}
+			const expectedParts = 3
+			objPath := address[len(ns)+1:]
+			parts := strings.Split(objPath, "/")
+			if len(parts) != expectedParts {
+				t.Fatalf("GetPhysicalAddressWithResponse path=%s, expected %d parts", objPath, expectedParts)
+			}
+			partitionID, err := xid.FromString(parts[1])
+			if err != nil {
+				t.Fatalf("GetPhysicalAddressWithResponse unknown partition format (path=%s): %s", objPath, err)
+			}
+			partitionTime := partitionID.Time()
+			if i > 0 && partitionTime.After(prevPartitionTime) {
+				t.Fatalf("GetPhysicalAddressWithResponse partition time '%s', should be equal or smaller than previous '%s'", partitionTime, prevPartitionTime)
+			}
+			prevPartitionTime = partitionTime
+		}
+	})
+}
+
+func TestController_PrepareGarbageCollectionUncommitted(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	verifyPrepareGarbageCollection := func(t *testing.T, repo string, expectedCalls int, expectUncommittedData bool) {
+		t.Helper()
+		var (
+			calls int
+			token *string
+		)
+		for {
+			calls++
+			resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, repo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{
+				ContinuationToken: token,
+			})
+			verifyResponseOK(t, resp, err)
+			if resp.JSON201 == nil {
+				t.Fatalf("PrepareGarbageCollectionUncommitted status code:%d, expected 201", resp.StatusCode())
+			}
+			if resp.JSON201.RunId == "" {
+				t.Errorf("PrepareGarbageCollectionUncommitted empty RunID, value expected")
+			}
+
+			if expectUncommittedData {
+				if resp.JSON201.GcUncommittedLocation == "" {
+					t.Errorf("PrepareGarbageCollectionUncommitted empty GcUncommittedLocation, value expected")
+				}
+			} else {
+				if resp.JSON201.GcUncommittedLocation != "" {
+					t.Errorf("PrepareGarbageCollectionUncommitted empty GcUncommittedLocation, no value expected")
+				}
+			}
+
+			token = resp.JSON201.ContinuationToken
+			if token == nil || *token == "" {
+				break
+			}
+		}
+		if calls != expectedCalls {
+			t.Fatalf("PrepareGarbageCollectionUncommitted calls=%d, expected=%d", calls, expectedCalls)
+		}
+	}
+
+	t.Run("no_repository", func(t *testing.T) {
+		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, "", apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
+		if err != nil {
+			t.Fatalf("PrepareGarbageCollectionUncommitted failed: %s", err)
+		}
+		if resp.JSON400 == nil {
+			t.Fatalf("PrepareGarbageCollectionUncommitted expected BadRequest: %+v", resp)
+		}
+	})
+
+	t.Run("repository_not_exists", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		resp, err := clt.PrepareGarbageCollectionUncommittedWithResponse(ctx, repo, apigen.PrepareGarbageCollectionUncommittedJSONRequestBody{})
+		if err != nil {
+			t.Fatalf("PrepareGarbageCollectionUncommitted failed: %s", err)
+		}
+		if resp.JSON404 == nil {
+			t.Fatalf("PrepareGarbageCollectionUncommitted expected NotFound: %+v", resp)
+		}
+	})
+
+	t.Run("uncommitted_data", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		const items = 3
+		for i := 0; i < items; i++ {
+			objPath := fmt.Sprintf("uncommitted/obj%d", i)
+			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
+			verifyResponseOK(t, uploadResp, err)
+		}
+		verifyPrepareGarbageCollection(t, repo, 1, true)
+	})
+
+	t.Run("committed_data", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		const items = 3
+		for i := 0; i < items; i++ {
+			objPath := fmt.Sprintf("committed/obj%d", i)
+			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
+			verifyResponseOK(t, uploadResp, err)
+		}
+		if _, err := deps.catalog.Commit(ctx, repo, "main", "committed objects", "some_user", nil, nil, nil, false); err != nil {
+			t.Fatalf("failed to commit objects: %s", err)
+		}
+		verifyPrepareGarbageCollection(t, repo, 1, false)
+	})
+
+	t.Run("uncommitted_copy", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		const items = 3
+		for i := 0; i < items; i++ {
+			objPath := fmt.Sprintf("uncommitted/obj%d", i)
+			uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(objPath), repo, "main")
+			verifyResponseOK(t, uploadResp, err)
+
+			copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main",
+				&apigen.CopyObjectParams{DestPath: fmt.Sprintf("copy/obj%d", i)},
+				apigen.CopyObjectJSONRequestBody{
+					SrcPath: objPath,
+				})
+			verifyResponseOK(t, copyResp, err)
+		}
+		verifyPrepareGarbageCollection(t, repo, 1, true)
+	})
+}
+
+func TestController_ClientDisconnect(t *testing.T) {
+	handler, deps := setupHandlerWithWalkerFactory(t, store.NewFactory(nil))
+
+	// setup lakefs
+	server := setupServer(t, handler)
+	clt := setupClientByEndpoint(t, server.URL, "", "")
+	cred := createDefaultAdminUser(t, clt)
+
+	// setup repository
+	ctx := context.Background()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	// prepare a client that will not wait for a response and timeout
+	dialer := net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			ResponseHeaderTimeout: time.Nanosecond,
+			DialContext:           dialer.DialContext,
+			DisableKeepAlives:     true,
+			IdleConnTimeout:       90 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     false,
+		},
+	}
+	clt = setupClientByEndpoint(t, server.URL, cred.AccessKeyID, cred.SecretAccessKey, apigen.WithHTTPClient(httpClient))
+
+	// upload request
+	contentType, reader := writeMultipart("content", "file.data", "something special")
+	_, err = clt.UploadObjectWithBodyWithResponse(ctx, repo, "main", &apigen.UploadObjectParams{
+		Path: "test/file.data",
+	}, contentType, reader)
+	if err == nil {
+		t.Fatal("Expected to request complete without error, expected to fail")
+	}
+
+	// wait for the server to identify we left and update the counter
+	time.Sleep(time.Second)
+
+	// request for metrics
+	metricsResp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = metricsResp.Body.Close()
+	}()
+	body, err := io.ReadAll(metricsResp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// process relevant metrics
+	const apiReqTotalMetricLabel = `api_requests_total{code="499",method="post"}`
+	var clientRequestClosedCount int
+	for _, line := range strings.Split(string(body), "\n") {
+		if strings.HasPrefix(line, apiReqTotalMetricLabel) {
+			if count, err := strconv.Atoi(line[len(apiReqTotalMetricLabel)+1:]); err == nil {
+				clientRequestClosedCount += count
+			}
+		}
+	}
+
+	const expectedCount = 1
+	if clientRequestClosedCount != expectedCount {
+		t.Fatalf("Metric for client request closed: %d, expected: %d", clientRequestClosedCount, expectedCount)
+	}
+}
+
+func TestController_PostStatsEvents(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	type key struct {
+		class string
+		name  string
+	}
+
+	tests := []struct {
+		name                string
+		events              []apigen.StatsEvent
+		expectedEventCounts map[key]int
+		expectedStatusCode  int
+	}{
+		{
+			name: "single_event_count_1",
+			events: []apigen.StatsEvent{
+				{
+					Class: "single_event_count_1",
+					Name:  "name",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "single_event_count_1", name: "name"}: 1,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "single_event_count_gt_1",
+			events: []apigen.StatsEvent{
+				{
+					Class: "single_event_count_gt_1",
+					Name:  "name",
+					Count: 3,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "single_event_count_gt_1", name: "name"}: 3,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "multiple_events",
+			events: []apigen.StatsEvent{
+				{
+					Class: "class_multiple_events_ev_1",
+					Name:  "name1",
+					Count: 1,
+				},
+				{
+					Class: "class_multiple_events_ev_2",
+					Name:  "name2",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "class_multiple_events_ev_1", name: "name1"}: 1,
+				{class: "class_multiple_events_ev_2", name: "name2"}: 1,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "multiple_events_same_class",
+			events: []apigen.StatsEvent{
+				{
+					Class: "class_multiple_events_same_class",
+					Name:  "name1",
+					Count: 1,
+				},
+				{
+					Class: "class_multiple_events_same_class",
+					Name:  "name2",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "class_multiple_events_same_class", name: "name1"}: 1,
+				{class: "class_multiple_events_same_class", name: "name2"}: 1,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "multiple_events_same_name",
+			events: []apigen.StatsEvent{
+				{
+					Class: "multiple_events_same_name_1",
+					Name:  "same_name",
+					Count: 1,
+				},
+				{
+					Class: "multiple_events_same_name_2",
+					Name:  "same_name",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "multiple_events_same_name_1", name: "same_name"}: 1,
+				{class: "multiple_events_same_name_2", name: "same_name"}: 1,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "multiple_events_same_class_same_name",
+			events: []apigen.StatsEvent{
+				{
+					Class: "multiple_events_same_class_same_name",
+					Name:  "same_name",
+					Count: 1,
+				},
+				{
+					Class: "multiple_events_same_class_same_name",
+					Name:  "same_name",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "multiple_events_same_class_same_name", name: "same_name"}: 2,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "empty_usage_class",
+			events: []apigen.StatsEvent{
+				{
+					Class: "",
+					Name:  "name",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "", name: "name"}: 0,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "empty_usage_name",
+			events: []apigen.StatsEvent{
+				{
+					Class: "class_empty_usage_name",
+					Name:  "",
+					Count: 1,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "class_empty_usage_name", name: ""}: 0,
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "zero_usage_count",
+			events: []apigen.StatsEvent{
+				{
+					Class: "class_zero_usage_count",
+					Name:  "name",
+					Count: 0,
+				},
+			},
+			expectedEventCounts: map[key]int{
+				{class: "class_zero_usage_count", name: "name"}: 0,
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name: "negative_usage_count",
+			events: []apigen.StatsEvent{
+				{
+					Class: "class_negative_usage_count",
+					Name:  "name",
+					Count: -23,
 				},
 			},
 			expectedEventCounts: map[key]int{
-				{class: "single_event_count_1", name: "name"}: 1,
+				{class: "class_negative_usage_count", name: "name"}: 0,
 			},
-			expectedStatusCode: http.StatusNoContent,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := clt.PostStatsEventsWithResponse(ctx, apigen.PostStatsEventsJSONRequestBody{
+				Events: tt.events,
+			})
+			if err != nil {
+				t.Fatalf("PostStatsEvents failed: %s", err)
+			}
+
+			if resp.StatusCode() != tt.expectedStatusCode {
+				t.Fatalf("PostStatsEvents status code: %d, expected: %d", resp.StatusCode(), tt.expectedStatusCode)
+			}
+
+			for _, sentEv := range tt.events {
+				collectedEventsToCount := map[key]int{}
+				k := key{class: sentEv.Class, name: sentEv.Name}
+				_, isMapContainKey := collectedEventsToCount[k]
+				if isMapContainKey {
+					continue
+				}
+				for _, collectedMetric := range deps.collector.Metrics {
+					if collectedMetric.Event.Class == sentEv.Class && collectedMetric.Event.Name == sentEv.Name {
+						collectedEventsToCount[k] += int(collectedMetric.Value)
+					}
+				}
+				if collectedEventsToCount[k] != tt.expectedEventCounts[k] {
+					t.Fatalf("PostStatsEvents events for class %s and name: %s, count: %d, expected: %d", sentEv.Class, sentEv.Name, collectedEventsToCount[k], tt.expectedEventCounts[k])
+				}
+			}
+		})
+	}
+}
+
+func TestController_UpdateObjectMetadataHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	uploadResp, err := uploadObjectHelper(t, ctx, clt, "data/file.csv", strings.NewReader("a,b\n"), repo, "main")
+	verifyResponseOK(t, uploadResp, err)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "initial", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	before, err := deps.catalog.GetEntry(ctx, repo, "main", "data/file.csv", catalog.GetEntryParams{})
+	testutil.Must(t, err)
+
+	t.Run("committed object", func(t *testing.T) {
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{
+				UserMetadata: &apigen.ObjectUserMetadata{AdditionalProperties: map[string]string{"owner": "analytics"}},
+				ContentType:  swag.String("text/csv"),
+			})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, map[string]string{"owner": "analytics"}, resp.JSON200.Metadata.AdditionalProperties)
+		require.Equal(t, "text/csv", swag.StringValue(resp.JSON200.ContentType))
+
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", "data/file.csv", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		require.Equal(t, before.PhysicalAddress, entry.PhysicalAddress)
+		require.Equal(t, before.Checksum, entry.Checksum)
+		require.Equal(t, catalog.Metadata{"owner": "analytics"}, entry.Metadata)
+		require.Equal(t, "text/csv", entry.ContentType)
+
+		diffResp, err := clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, diffResp, err)
+		require.Len(t, diffResp.JSON200.Results, 1)
+		require.Equal(t, "changed", diffResp.JSON200.Results[0].Type)
+	})
+
+	t.Run("content type only", func(t *testing.T) {
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{ContentType: swag.String("application/csv")})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, map[string]string{"owner": "analytics"}, resp.JSON200.Metadata.AdditionalProperties)
+		require.Equal(t, "application/csv", swag.StringValue(resp.JSON200.ContentType))
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{Tags: &apigen.ObjectTags{AdditionalProperties: map[string]string{"classification": "email"}}})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, map[string]string{"owner": "analytics"}, resp.JSON200.Metadata.AdditionalProperties)
+		require.Equal(t, map[string]string{"classification": "email"}, resp.JSON200.Tags.AdditionalProperties)
+
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "data/file.csv"})
+		verifyResponseOK(t, statResp, err)
+		require.Equal(t, map[string]string{"classification": "email"}, statResp.JSON200.Tags.AdditionalProperties)
+	})
+
+	t.Run("invalid tags", func(t *testing.T) {
+		tags := make(map[string]string)
+		for i := 0; i <= catalog.MaxCostAttributionTags; i++ {
+			tags[fmt.Sprintf("key%d", i)] = "value"
+		}
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{Tags: &apigen.ObjectTags{AdditionalProperties: tags}})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("tags permission", func(t *testing.T) {
+		// the user may write objects, but not change their tags
+		const userID = "metadata-writer"
+		createUserResp, err := clt.CreateUserWithResponse(ctx, apigen.CreateUserJSONRequestBody{Id: userID})
+		verifyResponseOK(t, createUserResp, err)
+		attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, userID, "FSFullAccess")
+		verifyResponseOK(t, attachResp, err)
+		policyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+			Id: "DenyTagObject",
+			Statement: []apigen.Statement{{
+				Action:   []string{permissions.TagObjectAction},
+				Effect:   "deny",
+				Resource: "*",
+			}},
+		})
+		verifyResponseOK(t, policyResp, err)
+		attachResp, err = clt.AttachPolicyToUserWithResponse(ctx, userID, "DenyTagObject")
+		verifyResponseOK(t, attachResp, err)
+		credsResp, err := clt.CreateCredentialsWithResponse(ctx, userID)
+		verifyResponseOK(t, credsResp, err)
+		userClt := setupClientByEndpoint(t, deps.server.URL, credsResp.JSON201.AccessKeyId, credsResp.JSON201.SecretAccessKey)
+
+		resp, err := userClt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{Tags: &apigen.ObjectTags{AdditionalProperties: map[string]string{}}})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+		// unchanged tags require no tagging permission
+		resp, err = userClt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{
+				ContentType: swag.String("text/csv"),
+				Tags:        &apigen.ObjectTags{AdditionalProperties: map[string]string{"classification": "email"}},
+			})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, map[string]string{"classification": "email"}, resp.JSON200.Tags.AdditionalProperties)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/file.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		resp, err := clt.UpdateObjectMetadataWithResponse(ctx, repo, "main", &apigen.UpdateObjectMetadataParams{Path: "data/missing.csv"},
+			apigen.UpdateObjectMetadataJSONRequestBody{ContentType: swag.String("text/csv")})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_CopyObjectHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	require.NoError(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
+	require.NoError(t, err)
+
+	uploadContent := func(t *testing.T, repository, branch, objPath string) apigen.ObjectStats {
+		t.Helper()
+		const content = "hello world this is my awesome content"
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(content), repository, branch)
+		verifyResponseOK(t, uploadResp, err)
+		require.NotNil(t, uploadResp.JSON201)
+		require.Equal(t, len(content), int(apiutil.Value(uploadResp.JSON201.SizeBytes)))
+		return *uploadResp.JSON201
+	}
+
+	t.Run("same_branch", func(t *testing.T) {
+		const (
+			srcPath  = "foo/bar"
+			destPath = "foo/bar-shallow-copy"
+		)
+		objStat := uploadContent(t, repo, "main", srcPath)
+		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: srcPath,
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		// Verify the creation path, date and physical address are different
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
+		require.Equal(t, destPath, copyStat.Path)
+
+		// get back info
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
+		verifyResponseOK(t, statResp, err)
+		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+	})
+
+	t.Run("different_branch", func(t *testing.T) {
+		const (
+			srcPath  = "foo/bar2"
+			destPath = "foo/bar-full-from-branch"
+		)
+		objStat := uploadContent(t, repo, "alt", srcPath)
+		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: srcPath,
+			SrcRef:  apiutil.Ptr("alt"),
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		// Verify the creation path, date and physical address are different
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.NotEmpty(t, copyStat.PhysicalAddress)
+		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
+		require.Equal(t, destPath, copyStat.Path)
+
+		// Verify all else is equal
+		objStat.Mtime = copyStat.Mtime
+		objStat.Path = copyStat.Path
+		objStat.PhysicalAddress = copyStat.PhysicalAddress
+		require.Nil(t, deep.Equal(objStat, *copyStat))
+
+		// get back info
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
+		verifyResponseOK(t, statResp, err)
+		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+	})
+
+	t.Run("committed", func(t *testing.T) {
+		const (
+			srcPath  = "foo/bar3"
+			destPath = "foo/bar-full-committed"
+		)
+		objStat := uploadContent(t, repo, "main", srcPath)
+		commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "commit bar3",
+		})
+		verifyResponseOK(t, commitResp, err)
+		require.NotNil(t, commitResp.JSON201)
+
+		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: srcPath,
+			SrcRef:  apiutil.Ptr("main"),
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		// Verify the creation path, date and physical address are different
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.NotEmpty(t, copyStat.PhysicalAddress)
+		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
+		require.Equal(t, destPath, copyStat.Path)
+
+		// Verify all else is equal
+		objStat.Mtime = copyStat.Mtime
+		objStat.Path = copyStat.Path
+		objStat.PhysicalAddress = copyStat.PhysicalAddress
+		require.Nil(t, deep.Equal(objStat, *copyStat))
+
+		// get back info
+		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
+		verifyResponseOK(t, statResp, err)
+		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		resp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: "bar/foo",
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: "not/found",
+			SrcRef:  apiutil.Ptr("main"),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON404)
+
+		// without src ref
+		resp, err = clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: "bar/foo",
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: "not/found",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON404)
+	})
+
+	t.Run("empty_destination", func(t *testing.T) {
+		resp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
+			DestPath: "",
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: "foo/bar",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON400)
+	})
+
+	t.Run("read-only repository", func(t *testing.T) {
+		readOnlyRepository := testUniqueRepoName()
+		_, err = deps.catalog.CreateRepository(ctx, readOnlyRepository, onBlock(deps, "bucket/prefix"), "main", true)
+		require.NoError(t, err)
+		_, err = deps.catalog.CreateBranch(ctx, readOnlyRepository, "alt", "main", graveler.WithForce(true))
+		require.NoError(t, err)
+
+		const (
+			srcPath  = "foo/bar"
+			destPath = "foo/bar-shallow-copy"
+		)
+		uploadContentForce := func(t *testing.T, repository, branch, objPath string) apigen.ObjectStats {
+			t.Helper()
+			const content = "hello world this is my awesome content"
+			var b bytes.Buffer
+			w := multipart.NewWriter(&b)
+			contentWriter, err := w.CreateFormFile("content", filepath.Base(objPath))
+			if err != nil {
+				t.Fatal("CreateFormFile:", err)
+			}
+			if _, err := io.Copy(contentWriter, strings.NewReader(content)); err != nil {
+				t.Fatal("CreateFormFile write content:", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Close multipart writer:", err)
+			}
+
+			uploadResp, err := clt.UploadObjectWithBodyWithResponse(ctx, readOnlyRepository, branch, &apigen.UploadObjectParams{
+				Path:  objPath,
+				Force: swag.Bool(true),
+			}, w.FormDataContentType(), &b)
+			verifyResponseOK(t, uploadResp, err)
+			require.NotNil(t, uploadResp.JSON201)
+			require.Equal(t, len(content), int(apiutil.Value(uploadResp.JSON201.SizeBytes)))
+			return *uploadResp.JSON201
+		}
+		objStat := uploadContentForce(t, readOnlyRepository, "main", srcPath)
+		copyResp, err := clt.CopyObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: srcPath,
+		})
+		testutil.Must(t, err)
+		if copyResp.StatusCode() != http.StatusForbidden {
+			t.Fatalf("expected 403 forbidden for CopyObject on read-only repository, got %d instead", copyResp.StatusCode())
+		}
+		copyResp, err = clt.CopyObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.CopyObjectParams{
+			DestPath: destPath,
+		}, apigen.CopyObjectJSONRequestBody{
+			SrcPath: srcPath,
+			Force:   swag.Bool(true),
+		})
+		verifyResponseOK(t, copyResp, err)
+
+		// Verify the creation path, date and physical address are different
+		copyStat := copyResp.JSON201
+		require.NotNil(t, copyStat)
+		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
+		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
+		require.Equal(t, destPath, copyStat.Path)
+
+		// get back info
+		statResp, err := clt.StatObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.StatObjectParams{Path: destPath})
+		verifyResponseOK(t, statResp, err)
+		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+	})
+}
+
+func TestController_AppendObjectHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	require.NoError(t, err)
+
+	appendContent := func(t *testing.T, objPath string, content []byte, writeOffset *int64) *apigen.AppendObjectResponse {
+		t.Helper()
+		resp, err := clt.AppendObjectWithBodyWithResponse(ctx, repo, "main", &apigen.AppendObjectParams{
+			Path:        objPath,
+			WriteOffset: writeOffset,
+		}, "application/octet-stream", bytes.NewReader(content))
+		require.NoError(t, err)
+		return resp
+	}
+	readContent := func(t *testing.T, objPath string) []byte {
+		t.Helper()
+		resp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: objPath})
+		verifyResponseOK(t, resp, err)
+		return resp.Body
+	}
+
+	t.Run("rewrite", func(t *testing.T) {
+		const objPath = "logs/small"
+		resp := appendContent(t, objPath, []byte("hello "), nil)
+		verifyResponseOK(t, resp, nil)
+		require.Equal(t, int64(6), apiutil.Value(resp.JSON200.SizeBytes))
+
+		resp = appendContent(t, objPath, []byte("world"), apiutil.Ptr(int64(6)))
+		verifyResponseOK(t, resp, nil)
+		require.Equal(t, int64(11), apiutil.Value(resp.JSON200.SizeBytes))
+		require.Equal(t, "hello world", string(readContent(t, objPath)))
+	})
+
+	t.Run("compose", func(t *testing.T) {
+		const objPath = "logs/large"
+		data := bytes.Repeat([]byte("a"), catalog.MinAppendCopySize)
+		resp := appendContent(t, objPath, data, nil)
+		verifyResponseOK(t, resp, nil)
+		resp = appendContent(t, objPath, []byte("tail"), apiutil.Ptr(int64(len(data))))
+		verifyResponseOK(t, resp, nil)
+		require.Equal(t, int64(len(data)+4), apiutil.Value(resp.JSON200.SizeBytes))
+		require.Equal(t, append(data, []byte("tail")...), readContent(t, objPath))
+	})
+
+	t.Run("write_offset_mismatch", func(t *testing.T) {
+		const objPath = "logs/mismatch"
+		resp := appendContent(t, objPath, []byte("data"), nil)
+		verifyResponseOK(t, resp, nil)
+		resp = appendContent(t, objPath, []byte("more"), apiutil.Ptr(int64(2)))
+		require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode())
+		require.Equal(t, "data", string(readContent(t, objPath)))
+	})
+
+	t.Run("missing_branch", func(t *testing.T) {
+		resp, err := clt.AppendObjectWithBodyWithResponse(ctx, repo, "no-such-branch", &apigen.AppendObjectParams{
+			Path: "logs/a",
+		}, "application/octet-stream", strings.NewReader("data"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_SampleObjectHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	require.NoError(t, err)
+
+	const data = "id,name\n1,a\n2,b\n3,c\n"
+	uploadResp, err := uploadObjectHelper(t, ctx, clt, "data/table.csv", strings.NewReader(data), repo, "main")
+	verifyResponseOK(t, uploadResp, err)
+	uploadResp, err = uploadObjectHelper(t, ctx, clt, "data/table.bin", strings.NewReader(data), repo, "main")
+	verifyResponseOK(t, uploadResp, err)
+
+	t.Run("head", func(t *testing.T) {
+		resp, err := clt.SampleObjectWithResponse(ctx, repo, "main", &apigen.SampleObjectParams{
+			Path:  "data/table.csv",
+			Limit: apiutil.Ptr(2),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "csv", resp.JSON200.Format)
+		require.Equal(t, []string{"id", "name"}, resp.JSON200.Columns)
+		require.Len(t, resp.JSON200.Rows, 2)
+		require.Equal(t, "b", resp.JSON200.Rows[1].AdditionalProperties["name"])
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		resp, err := clt.SampleObjectWithResponse(ctx, repo, "main", &apigen.SampleObjectParams{
+			Path:  "data/table.csv",
+			Mode:  apiutil.Ptr("tail"),
+			Limit: apiutil.Ptr(1),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Rows, 1)
+		require.Equal(t, "3", resp.JSON200.Rows[0].AdditionalProperties["id"])
+	})
+
+	t.Run("explicit_format", func(t *testing.T) {
+		resp, err := clt.SampleObjectWithResponse(ctx, repo, "main", &apigen.SampleObjectParams{
+			Path:   "data/table.bin",
+			Format: apiutil.Ptr("csv"),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Rows, 3)
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		resp, err := clt.SampleObjectWithResponse(ctx, repo, "main", &apigen.SampleObjectParams{
+			Path: "data/table.bin",
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("missing_object", func(t *testing.T) {
+		resp, err := clt.SampleObjectWithResponse(ctx, repo, "main", &apigen.SampleObjectParams{
+			Path: "data/missing.csv",
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_OtfDiff(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	username := "username"
+	_, _ = clt.CreateUserWithResponse(context.Background(), apigen.CreateUserJSONRequestBody{Id: username})
+	server := deps.server
+	authProvider := generateJWTToken(deps.authService, username)
+	nonExistingUserAuthProvider := generateJWTToken(deps.authService, username+"NE")
+	noCredsClient, _ := apigen.NewClientWithResponses(server.URL+apiutil.BaseURL, apigen.WithRequestEditorFn(authProvider.Intercept))
+	noUserClient, _ := apigen.NewClientWithResponses(server.URL+apiutil.BaseURL, apigen.WithRequestEditorFn(nonExistingUserAuthProvider.Intercept))
+
+	repo := testUniqueRepoName()
+
+	diffParams := apigen.OtfDiffParams{
+		TablePath: "path/to/table",
+		Type:      tablediff.ControllerTestPlugin,
+	}
+	testCases := []struct {
+		clt                apigen.ClientWithResponsesInterface
+		expectedHttpStatus int
+		err                error
+		resultDiffType     string
+		description        string
+	}{
+		{
+			clt:                clt,
+			expectedHttpStatus: http.StatusOK,
+			resultDiffType:     "changed",
+			description:        "success - table changed",
 		},
 		{
-			name: "single_event_count_gt_1",
-			events: []apigen.StatsEvent{
-				{
-					Class: "single_event_count_gt_1",
-					Name:  "name",
-					Count: 3,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "single_event_count_gt_1", name: "name"}: 3,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                clt,
+			expectedHttpStatus: http.StatusOK,
+			resultDiffType:     "dropped",
+			description:        "success - table dropped",
 		},
 		{
-			name: "multiple_events",
-			events: []apigen.StatsEvent{
-				{
-					Class: "class_multiple_events_ev_1",
-					Name:  "name1",
-					Count: 1,
-				},
-				{
-					Class: "class_multiple_events_ev_2",
-					Name:  "name2",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "class_multiple_events_ev_1", name: "name1"}: 1,
-				{class: "class_multiple_events_ev_2", name: "name2"}: 1,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                clt,
+			expectedHttpStatus: http.StatusOK,
+			resultDiffType:     "created",
+			description:        "success - table created",
 		},
 		{
-			name: "multiple_events_same_class",
-			events: []apigen.StatsEvent{
-				{
-					Class: "class_multiple_events_same_class",
-					Name:  "name1",
-					Count: 1,
-				},
-				{
-					Class: "class_multiple_events_same_class",
-					Name:  "name2",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "class_multiple_events_same_class", name: "name1"}: 1,
-				{class: "class_multiple_events_same_class", name: "name2"}: 1,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                clt,
+			expectedHttpStatus: http.StatusNotFound,
+			err:                tablediff.ErrTableNotFound,
+			description:        "failure - table not found",
 		},
 		{
-			name: "multiple_events_same_name",
-			events: []apigen.StatsEvent{
-				{
-					Class: "multiple_events_same_name_1",
-					Name:  "same_name",
-					Count: 1,
-				},
-				{
-					Class: "multiple_events_same_name_2",
-					Name:  "same_name",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "multiple_events_same_name_1", name: "same_name"}: 1,
-				{class: "multiple_events_same_name_2", name: "same_name"}: 1,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                clt,
+			expectedHttpStatus: http.StatusInternalServerError,
+			err:                tablediff.ErrDiffFailed,
+			description:        "failure - internal error",
 		},
 		{
-			name: "multiple_events_same_class_same_name",
-			events: []apigen.StatsEvent{
-				{
-					Class: "multiple_events_same_class_same_name",
-					Name:  "same_name",
-					Count: 1,
-				},
-				{
-					Class: "multiple_events_same_class_same_name",
-					Name:  "same_name",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "multiple_events_same_class_same_name", name: "same_name"}: 2,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                noUserClient,
+			expectedHttpStatus: http.StatusUnauthorized,
+			description:        "failure - non existing user",
+		},
+		{
+			clt:                noCredsClient,
+			expectedHttpStatus: http.StatusPreconditionFailed,
+			description:        "failure - user without credentials",
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			currCtx := context.Background()
+
+			left := "left"
+			right := "right"
+
+			if tc.resultDiffType == "dropped" {
+				left = "dropped"
+			} else if tc.resultDiffType == "created" {
+				right = "created"
+			}
+			if errors.Is(tc.err, tablediff.ErrTableNotFound) {
+				left = "notfound"
+				right = "notfound"
+			} else if errors.Is(tc.err, tablediff.ErrDiffFailed) {
+				diffParams.Type = "nonExistingPlugin"
+			}
+
+			response, err := tc.clt.OtfDiffWithResponse(currCtx, repo, left, right, &diffParams)
+
+			require.NoError(t, err)
+			require.NotNil(t, response)
+			require.NotEmpty(t, response.StatusCode())
+			require.Equal(t, tc.expectedHttpStatus, response.StatusCode())
+			if tc.expectedHttpStatus == http.StatusOK {
+				require.NotNil(t, response.JSON200)
+				require.Equal(t, tc.resultDiffType, *response.JSON200.DiffType)
+				if tc.resultDiffType != "dropped" {
+					require.NotEmpty(t, response.JSON200.Results)
+				} else {
+					require.Empty(t, response.JSON200.Results)
+				}
+			}
+		})
+	}
+}
+
+func generateJWTToken(authService auth.Service, username string) *securityprovider.SecurityProviderApiKey {
+	secret := authService.SecretStore().SharedSecret()
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	apiToken, _ := api.GenerateJWTLogin(secret, username, now, expires)
+	authProvider, _ := securityprovider.NewSecurityProviderApiKey("header", "Authorization", "Bearer "+apiToken)
+	return authProvider
+}
+
+func TestController_LocalAdapter_StageObject(t *testing.T) {
+	p := t.TempDir()
+	forbiddenPath := "local:///not_allowed"
+	blockstoreType := viper.Get(config.BlockstoreTypeKey)
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeLocal)
+	viper.Set("blockstore.local.path", p)
+	t.Cleanup(func() { viper.Set(config.BlockstoreTypeKey, blockstoreType) })
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	require.NoError(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
+	require.NoError(t, err)
+
+	t.Run("stage_forbidden_address", func(t *testing.T) {
+		resp, err := clt.StageObjectWithResponse(ctx, repo, "main", &apigen.StageObjectParams{
+			Path: "some_path",
+		}, apigen.StageObjectJSONRequestBody{
+			PhysicalAddress: forbiddenPath,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON403)
+	})
+}
+
+func TestController_BranchProtectionRules(t *testing.T) {
+	adminClt, deps := setupClientWithAdmin(t)
+	creds := createUserWithDefaultGroup(t, adminClt)
+	regClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyID, creds.SecretAccessKey)
+
+	testCases := []struct {
+		clt                apigen.ClientWithResponsesInterface
+		expectedHttpStatus int
+		err                error
+		description        string
+	}{
 		{
-			name: "empty_usage_class",
-			events: []apigen.StatsEvent{
-				{
-					Class: "",
-					Name:  "name",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "", name: "name"}: 0,
-			},
-			expectedStatusCode: http.StatusBadRequest,
+			clt:                adminClt,
+			expectedHttpStatus: http.StatusNoContent,
+			description:        "success - admin user",
 		},
 		{
-			name: "empty_usage_name",
-			events: []apigen.StatsEvent{
-				{
-					Class: "class_empty_usage_name",
-					Name:  "",
-					Count: 1,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "class_empty_usage_name", name: ""}: 0,
-			},
-			expectedStatusCode: http.StatusBadRequest,
+			clt:                regClt,
+			expectedHttpStatus: http.StatusUnauthorized,
+			description:        "failure - regular user",
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			currCtx := context.Background()
+			repo := testUniqueRepoName()
+			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
+			testutil.MustDo(t, "create repository", err)
+
+			respPreflight, err := tc.clt.CreateBranchProtectionRulePreflightWithResponse(currCtx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if respPreflight == nil {
+				t.Fatal("CreateBranchProtectionRulePreflightWithResponse got no response")
+			}
+			if respPreflight.StatusCode() != tc.expectedHttpStatus {
+				t.Fatalf("CreateBranchProtectionRulePreflightWithResponse expected %d, got %d", tc.expectedHttpStatus, respPreflight.StatusCode())
+			}
+
+			// the result of an actual call to the endpoint should have the same result
+			resp, err := tc.clt.InternalCreateBranchProtectionRuleWithResponse(currCtx, repo, apigen.InternalCreateBranchProtectionRuleJSONRequestBody{
+				Pattern: "main",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp == nil {
+				t.Fatal("InternalCreateBranchProtectionRuleWithResponse got no response")
+			}
+			if resp.StatusCode() != respPreflight.StatusCode() {
+				t.Fatalf("InternalCreateBranchProtectionRuleWithResponse and preflight should return the same unauthorized status expected %d, got %d", respPreflight.StatusCode(), resp.StatusCode())
+			}
+		})
+	}
+}
+
+func TestController_GarbageCollectionRules(t *testing.T) {
+	adminClt, deps := setupClientWithAdmin(t)
+	creds := createUserWithDefaultGroup(t, adminClt)
+	regClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyID, creds.SecretAccessKey)
+
+	testCases := []struct {
+		clt                apigen.ClientWithResponsesInterface
+		expectedHttpStatus int
+		err                error
+		description        string
+	}{
 		{
-			name: "zero_usage_count",
-			events: []apigen.StatsEvent{
-				{
-					Class: "class_zero_usage_count",
-					Name:  "name",
-					Count: 0,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "class_zero_usage_count", name: "name"}: 0,
-			},
-			expectedStatusCode: http.StatusNoContent,
+			clt:                adminClt,
+			expectedHttpStatus: http.StatusNoContent,
+			description:        "success - admin user",
 		},
 		{
-			name: "negative_usage_count",
-			events: []apigen.StatsEvent{
-				{
-					Class: "class_negative_usage_count",
-					Name:  "name",
-					Count: -23,
-				},
-			},
-			expectedEventCounts: map[key]int{
-				{class: "class_negative_usage_count", name: "name"}: 0,
-			},
-			expectedStatusCode: http.StatusBadRequest,
+			clt:                regClt,
+			expectedHttpStatus: http.StatusUnauthorized,
+			description:        "failure - regular user",
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := clt.PostStatsEventsWithResponse(ctx, apigen.PostStatsEventsJSONRequestBody{
-				Events: tt.events,
-			})
-			if err != nil {
-				t.Fatalf("PostStatsEvents failed: %s", err)
-			}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			currCtx := context.Background()
+			repo := testUniqueRepoName()
+			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
+			testutil.MustDo(t, "create repository", err)
+
+			respPreflight, err := tc.clt.SetGarbageCollectionRulesPreflightWithResponse(currCtx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if respPreflight == nil {
+				t.Fatal("SetGarbageCollectionRulesPreflightWithResponse got no response")
+			}
+
+			if respPreflight.StatusCode() != tc.expectedHttpStatus {
+				t.Fatalf("SetGarbageCollectionRulesPreflightWithResponse expected %d, got %d", tc.expectedHttpStatus, respPreflight.StatusCode())
+			}
+
+			// the result of an actual call to the endpoint should have the same result
+			resp, err := tc.clt.SetGCRulesWithResponse(currCtx, repo, apigen.SetGCRulesJSONRequestBody{
+				Branches: []apigen.GarbageCollectionRule{{BranchId: "main", RetentionDays: 1}}, DefaultRetentionDays: 5,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp == nil {
+				t.Fatal("CreateBranchProtectionRuleWithResponse got no response")
+			}
+			if resp.Status() != respPreflight.Status() {
+				t.Fatalf("CreateBranchProtectionRuleWithResponse and preflight should return the same status. expected %d, got %d", respPreflight.StatusCode(), resp.StatusCode())
+			}
+		})
+	}
+}
+
+func TestController_DumpRestoreRepository(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	// setup repository with some commits
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	const commits = 3
+	for i := 0; i < commits; i++ {
+		n := strconv.Itoa(i + 1)
+		p := "foo/bar" + n
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
+		testutil.MustDo(t, "create entry "+p, err)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "tester", nil, nil, nil, false)
+		testutil.MustDo(t, "commit "+p, err)
+	}
+	_, err = deps.catalog.CreateTag(ctx, repo, "v1", "main")
+	testutil.MustDo(t, "create tag", err)
+
+	var dumpStatus *apigen.RepositoryDumpStatus
+
+	t.Run("dump", func(t *testing.T) {
+		dumpResp, err := clt.DumpSubmitWithResponse(ctx, repo)
+		testutil.MustDo(t, "dump submit", err)
+		if dumpResp.JSON202 == nil {
+			t.Fatal("Expected 202 response")
+		}
+
+		taskID := dumpResp.JSON202.Id
+		ticker := time.NewTicker(500 * time.Millisecond)
+		started := time.Now()
+		for range ticker.C {
+			statusResp, err := clt.DumpStatusWithResponse(ctx, repo, &apigen.DumpStatusParams{TaskId: taskID})
+			testutil.MustDo(t, "dump status", err)
+			if statusResp.JSON200 == nil {
+				t.Fatal("Expected 200 response")
+			}
+			if statusResp.JSON200.Done {
+				dumpStatus = statusResp.JSON200
+				break
+			}
+			if time.Since(started) > 30*time.Second {
+				break
+			}
+		}
+		ticker.Stop()
+
+		if dumpStatus == nil {
+			t.Fatal("Expected dump to complete (timed-out)")
+		}
+		if dumpStatus.Error != nil {
+			t.Fatalf("Failed to dump repository refs: %s", *dumpStatus.Error)
+		}
+	})
+
+	t.Run("dump_status_invalid_id", func(t *testing.T) {
+		response, err := clt.DumpStatusWithResponse(ctx, repo, &apigen.DumpStatusParams{TaskId: "invalid"})
+		testutil.MustDo(t, "dump status", err)
+		if response.JSON404 == nil {
+			t.Fatalf("Expected 404 (not found) response, got %s", response.Status())
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		if dumpStatus == nil || dumpStatus.Refs == nil {
+			t.Skip("Skipping restore test, dump failed")
+		}
+
+		newRepo := testUniqueRepoName()
+		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main")
+		testutil.MustDo(t, "create bare repository", err)
+
+		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
+			BranchesMetaRangeId: dumpStatus.Refs.BranchesMetaRangeId,
+			CommitsMetaRangeId:  dumpStatus.Refs.CommitsMetaRangeId,
+			TagsMetaRangeId:     dumpStatus.Refs.TagsMetaRangeId,
+		})
+		testutil.MustDo(t, "restore submit", err)
+		if submitResponse.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
+		}
+
+		restoreStatus := pollRestoreStatus(t, clt, newRepo, submitResponse.JSON202.Id)
+		if restoreStatus == nil {
+			t.Fatal("Expected restore to complete (timed-out)")
+		}
+		if restoreStatus.Error != nil {
+			t.Fatalf("Failed to restore repository refs: %s", *restoreStatus.Error)
+		}
+	})
+
+	t.Run("restore_replace", func(t *testing.T) {
+		if dumpStatus == nil || dumpStatus.Refs == nil {
+			t.Skip("Skipping restore test, dump failed")
+		}
+		mainResp, err := clt.GetBranchWithResponse(ctx, repo, "main")
+		verifyResponseOK(t, mainResp, err)
+		dumpedCommitID := mainResp.JSON200.CommitId
+
+		// change the repository after the dump
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/after", PhysicalAddress: onBlock(deps, "after"), CreationDate: time.Now(), Size: 1, Checksum: "after"}))
+		_, err = deps.catalog.Commit(ctx, repo, "main", "after dump", "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/uncommitted", PhysicalAddress: onBlock(deps, "uncommitted"), CreationDate: time.Now(), Size: 1, Checksum: "uncommitted"}))
+		_, err = deps.catalog.CreateBranch(ctx, repo, "after-dump", "main")
+		testutil.Must(t, err)
+
+		body := apigen.RestoreSubmitJSONRequestBody{
+			BranchesMetaRangeId: dumpStatus.Refs.BranchesMetaRangeId,
+			CommitsMetaRangeId:  dumpStatus.Refs.CommitsMetaRangeId,
+			TagsMetaRangeId:     dumpStatus.Refs.TagsMetaRangeId,
+		}
+		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, repo, body)
+		testutil.MustDo(t, "restore submit", err)
+		if submitResponse.JSON400 == nil {
+			t.Fatalf("Expected 400 response restoring into a repository with commits, got: %s", submitResponse.Status())
+		}
+
+		body.Replace = swag.Bool(true)
+		submitResponse, err = clt.RestoreSubmitWithResponse(ctx, repo, body)
+		testutil.MustDo(t, "restore submit", err)
+		if submitResponse.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
+		}
+		restoreStatus := pollRestoreStatus(t, clt, repo, submitResponse.JSON202.Id)
+		if restoreStatus == nil {
+			t.Fatal("Expected restore to complete (timed-out)")
+		}
+		if restoreStatus.Error != nil {
+			t.Fatalf("Failed to restore repository refs: %s", *restoreStatus.Error)
+		}
+
+		mainResp, err = clt.GetBranchWithResponse(ctx, repo, "main")
+		verifyResponseOK(t, mainResp, err)
+		require.Equal(t, dumpedCommitID, mainResp.JSON200.CommitId)
+		diffResp, err := clt.DiffBranchWithResponse(ctx, repo, "main", &apigen.DiffBranchParams{})
+		verifyResponseOK(t, diffResp, err)
+		require.Empty(t, diffResp.JSON200.Results)
+		branchResp, err := clt.GetBranchWithResponse(ctx, repo, "after-dump")
+		verifyResponseOK(t, branchResp, err)
+	})
+
+	t.Run("restore_replace_conflicting_tag", func(t *testing.T) {
+		if dumpStatus == nil || dumpStatus.Refs == nil {
+			t.Skip("Skipping restore test, dump failed")
+		}
+		// move the dumped tag and main after the dump
+		testutil.Must(t, deps.catalog.DeleteTag(ctx, repo, "v1"))
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/moved", PhysicalAddress: onBlock(deps, "moved"), CreationDate: time.Now(), Size: 1, Checksum: "moved"}))
+		_, err = deps.catalog.Commit(ctx, repo, "main", "move tag", "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		_, err = deps.catalog.CreateTag(ctx, repo, "v1", "main")
+		testutil.Must(t, err)
+		mainResp, err := clt.GetBranchWithResponse(ctx, repo, "main")
+		verifyResponseOK(t, mainResp, err)
+		movedCommitID := mainResp.JSON200.CommitId
+
+		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, repo, apigen.RestoreSubmitJSONRequestBody{
+			BranchesMetaRangeId: dumpStatus.Refs.BranchesMetaRangeId,
+			CommitsMetaRangeId:  dumpStatus.Refs.CommitsMetaRangeId,
+			TagsMetaRangeId:     dumpStatus.Refs.TagsMetaRangeId,
+			Replace:             swag.Bool(true),
+		})
+		testutil.MustDo(t, "restore submit", err)
+		if submitResponse.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
+		}
+		restoreStatus := pollRestoreStatus(t, clt, repo, submitResponse.JSON202.Id)
+		if restoreStatus == nil {
+			t.Fatal("Expected restore to complete (timed-out)")
+		}
+		if restoreStatus.Error == nil {
+			t.Fatal("Expected restore to fail on a conflicting tag")
+		}
+
+		// the restore failed before resetting branches
+		mainResp, err = clt.GetBranchWithResponse(ctx, repo, "main")
+		verifyResponseOK(t, mainResp, err)
+		require.Equal(t, movedCommitID, mainResp.JSON200.CommitId)
+	})
+
+	t.Run("restore_invalid_refs", func(t *testing.T) {
+		// delete and recreate repository as bare for restore
+		newRepo := testUniqueRepoName()
+		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main")
+		testutil.MustDo(t, "create bare repository", err)
+
+		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
+			BranchesMetaRangeId: "invalid",
+			CommitsMetaRangeId:  "invalid",
+			TagsMetaRangeId:     "invalid",
+		})
+		testutil.MustDo(t, "restore submit", err)
+		if submitResponse.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
+		}
+
+		restoreStatus := pollRestoreStatus(t, clt, newRepo, submitResponse.JSON202.Id)
+		if restoreStatus == nil {
+			t.Fatal("Expected restore to complete (timed-out)")
+		}
+		if restoreStatus.Error == nil {
+			t.Fatal("Expected restore to fail, got nil Error")
+		}
+		if !strings.Contains(*restoreStatus.Error, graveler.ErrNotFound.Error()) {
+			t.Fatal("Expected restore to fail with not found error")
+		}
+	})
+
+	t.Run("restore_status_invalid_id", func(t *testing.T) {
+		response, err := clt.RestoreStatusWithResponse(ctx, repo, &apigen.RestoreStatusParams{TaskId: "invalid"})
+		testutil.MustDo(t, "restore status", err)
+		if response.JSON404 == nil {
+			t.Fatalf("Expected 404 (not found) response, got %s", response.Status())
+		}
+	})
+}
+
+func TestController_CreateCommitRecord(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+	// expected commit ID for this commit record
+	expectedCommitID := "2996b23ac5ab6e7267c302cf00c751bbb87afddd81c6d03e95f654f77b0f3ded"
+	body := apigen.CreateCommitRecordJSONRequestBody{
+		CommitId:     expectedCommitID,
+		Committer:    "Committer",
+		CreationDate: 0,
+		Generation:   1,
+		Message:      "message",
+		Metadata:     &apigen.CommitRecordCreation_Metadata{AdditionalProperties: map[string]string{"key": "value"}},
+		MetarangeId:  "metarangeId",
+		Parents:      []string{"parent1", "parent2"},
+		Version:      1,
+	}
+
+	t.Run("create commit record", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
+		testutil.MustDo(t, "create commit record", err)
+		if resp.StatusCode() != http.StatusNoContent {
+			t.Fatalf("Expected 204 (no content) response, got %s", resp.Status())
+		}
+		commitLog, err := deps.catalog.GetCommit(ctx, repo, expectedCommitID)
+		testutil.MustDo(t, "get commit", err)
+		expectedCommitLog := &catalog.CommitLog{
+			Reference:    expectedCommitID,
+			Committer:    body.Committer,
+			Message:      body.Message,
+			CreationDate: time.Unix(0, body.CreationDate).UTC(),
+			Metadata:     body.Metadata.AdditionalProperties,
+			MetaRangeID:  body.MetarangeId,
+			Parents:      body.Parents,
+			Generation:   catalog.CommitGeneration(body.Generation),
+			Version:      catalog.CommitVersion(body.Version),
+		}
+		if diff := deep.Equal(commitLog, expectedCommitLog); diff != nil {
+			t.Fatalf("Diff: %v", diff)
+		}
+	})
+
+	t.Run("create commit record with wrong commitID", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		bodyCpy := body
+		bodyCpy.CommitId = "wrong"
+		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, bodyCpy)
+		testutil.MustDo(t, "create commit record", err)
+		if resp.StatusCode() != http.StatusBadRequest {
+			t.Fatalf("Expected 400 (bad request) response, got %s", resp.Status())
+		}
+	})
+
+	t.Run("read only repository", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+		testutil.Must(t, err)
+		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusForbidden {
+			t.Fatalf("Create commit record in read-only repository should be forbidden (403), got %s", resp.Status())
+		}
+		body.Force = swag.Bool(true)
+		resp, err = clt.CreateCommitRecordWithResponse(ctx, repo, body)
+		testutil.MustDo(t, "create commit record", err)
+		if resp.StatusCode() != http.StatusNoContent {
+			t.Fatalf("Expected 204 response, got: %s", resp.Status())
+		}
+	})
+
+	t.Run("already existing commit", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
+		testutil.MustDo(t, "create commit record", err)
+		if resp.StatusCode() != http.StatusNoContent {
+			t.Fatalf("Expected 204 (no content) response, got %s", resp.Status())
+		}
+		resp, err = clt.CreateCommitRecordWithResponse(ctx, repo, body)
+		if resp.StatusCode() != http.StatusConflict {
+			t.Fatalf("Expected 409 (conflict) response, got %s", resp.Status())
+		}
+	})
+}
+
+// pollRestoreStatus polls the restore status endpoint until the restore is complete or times out.
+// test will fail in case of error.
+// will return nil in case of timeout.
+func pollRestoreStatus(t *testing.T, clt apigen.ClientWithResponsesInterface, repo string, taskID string) *apigen.RepositoryRestoreStatus {
+	t.Helper()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	started := time.Now()
+	for range ticker.C {
+		statusResponse, err := clt.RestoreStatusWithResponse(context.Background(), repo, &apigen.RestoreStatusParams{TaskId: taskID})
+		testutil.MustDo(t, "restore status", err)
+		if statusResponse.JSON200 == nil {
+			t.Fatalf("Expected 200 response, got: %s", statusResponse.Status())
+		}
+		if statusResponse.JSON200.Done {
+			return statusResponse.JSON200
+		}
+		if time.Since(started) > 30*time.Second {
+			break
+		}
+	}
+	return nil
+}
+
+// pollPlanGCRules polls the status of the submitted GC plan until it is done, and returns the plan.
+// test will fail in case of error or timeout.
+func pollPlanGCRules(t *testing.T, clt apigen.ClientWithResponsesInterface, repo string, resp *apigen.PlanGCRulesResponse) *apigen.GarbageCollectionPlan {
+	t.Helper()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode())
+	taskID := resp.JSON202.Id
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	started := time.Now()
+	for range ticker.C {
+		statusResponse, err := clt.PlanGCRulesStatusWithResponse(context.Background(), repo, &apigen.PlanGCRulesStatusParams{TaskId: taskID})
+		testutil.MustDo(t, "plan GC rules status", err)
+		require.Equal(t, http.StatusOK, statusResponse.StatusCode())
+		status := statusResponse.JSON200
+		if status.Done {
+			require.Nil(t, status.Error)
+			require.NotNil(t, status.Plan)
+			return status.Plan
+		}
+		if time.Since(started) > 30*time.Second {
+			break
+		}
+	}
+	t.Fatalf("GC plan task %s not done", taskID)
+	return nil
+}
+
+func TestController_PlanGCRules(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
 
-			if resp.StatusCode() != tt.expectedStatusCode {
-				t.Fatalf("PostStatsEvents status code: %d, expected: %d", resp.StatusCode(), tt.expectedStatusCode)
-			}
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
 
-			for _, sentEv := range tt.events {
-				collectedEventsToCount := map[key]int{}
-				k := key{class: sentEv.Class, name: sentEv.Name}
-				_, isMapContainKey := collectedEventsToCount[k]
-				if isMapContainKey {
-					continue
-				}
-				for _, collectedMetric := range deps.collector.Metrics {
-					if collectedMetric.Event.Class == sentEv.Class && collectedMetric.Event.Name == sentEv.Name {
-						collectedEventsToCount[k] += int(collectedMetric.Value)
-					}
-				}
-				if collectedEventsToCount[k] != tt.expectedEventCounts[k] {
-					t.Fatalf("PostStatsEvents events for class %s and name: %s, count: %d, expected: %d", sentEv.Class, sentEv.Name, collectedEventsToCount[k], tt.expectedEventCounts[k])
-				}
-			}
-		})
+	// each commit replaces the single object of the branch
+	now := time.Now()
+	objects := []struct {
+		path string
+		size int64
+		date time.Time
+	}{
+		{path: "a", size: 10, date: now.AddDate(0, 0, -20)},
+		{path: "b", size: 20, date: now.AddDate(0, 0, -10)},
+		{path: "c", size: 30, date: now},
+	}
+	for i, obj := range objects {
+		if i > 0 {
+			testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", objects[i-1].path))
+		}
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            obj.path,
+			PhysicalAddress: obj.path + "_address",
+			CreationDate:    obj.date,
+			Size:            obj.size,
+			Checksum:        "checksum",
+		}))
+		_, err := deps.catalog.Commit(ctx, repo, "main", "commit "+obj.path, "tester", nil, swag.Int64(obj.date.Unix()), nil, false)
+		testutil.Must(t, err)
 	}
+
+	t.Run("short_retention", func(t *testing.T) {
+		resp, err := clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{
+			Rules: &apigen.GarbageCollectionRules{DefaultRetentionDays: 7, Branches: []apigen.GarbageCollectionRule{}},
+		})
+		testutil.Must(t, err)
+		plan := pollPlanGCRules(t, clt, repo, resp)
+		// the head and the commit of 'b', the first beyond the retention period, are kept
+		require.Equal(t, 2, plan.ActiveCommits)
+		require.Equal(t, 2, plan.ExpiredCommits)
+		require.Equal(t, int64(1), plan.ReclaimableObjects)
+		require.Equal(t, int64(10), plan.ReclaimableBytes)
+		require.Equal(t, 1.0, plan.SampleRatio)
+		require.Equal(t, []apigen.GarbageCollectionPlanBranch{
+			{BranchId: "main", RetentionDays: 7, RetainedCommits: 1, RetainedObjects: 1, RetainedBytes: 20},
+		}, plan.Branches)
+	})
+
+	t.Run("long_retention", func(t *testing.T) {
+		resp, err := clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{
+			Rules: &apigen.GarbageCollectionRules{
+				DefaultRetentionDays: 7,
+				Branches:             []apigen.GarbageCollectionRule{{BranchId: "main", RetentionDays: 30}},
+			},
+		})
+		testutil.Must(t, err)
+		plan := pollPlanGCRules(t, clt, repo, resp)
+		require.Equal(t, 0, plan.ExpiredCommits)
+		require.Equal(t, int64(0), plan.ReclaimableObjects)
+		require.Equal(t, []apigen.GarbageCollectionPlanBranch{
+			{BranchId: "main", RetentionDays: 30, RetainedCommits: 3, RetainedObjects: 2, RetainedBytes: 30},
+		}, plan.Branches)
+	})
+
+	t.Run("repository_rules", func(t *testing.T) {
+		resp, err := clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+		setResp, err := clt.SetGCRulesWithResponse(ctx, repo, apigen.SetGCRulesJSONRequestBody{DefaultRetentionDays: 7, Branches: []apigen.GarbageCollectionRule{}})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+		resp, err = clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{})
+		testutil.Must(t, err)
+		plan := pollPlanGCRules(t, clt, repo, resp)
+		require.Equal(t, 7, plan.Rules.DefaultRetentionDays)
+		require.Equal(t, int64(1), plan.ReclaimableObjects)
+	})
+
+	t.Run("unknown_task", func(t *testing.T) {
+		resp, err := clt.PlanGCRulesStatusWithResponse(ctx, repo, &apigen.PlanGCRulesStatusParams{TaskId: "GPunknown"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("invalid_sample_ratio", func(t *testing.T) {
+		resp, err := clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{
+			SampleRatio: swag.Float64(2),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
 }
 
-func TestController_CopyObjectHandler(t *testing.T) {
+func TestController_LegalHolds(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
 	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
-	require.NoError(t, err)
-	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
-	require.NoError(t, err)
+	testutil.Must(t, err)
 
-	uploadContent := func(t *testing.T, repository, branch, objPath string) apigen.ObjectStats {
+	// each commit replaces the single object of the branch
+	now := time.Now()
+	objects := []struct {
+		path string
+		date time.Time
+	}{
+		{path: "a", date: now.AddDate(0, 0, -20)},
+		{path: "b", date: now.AddDate(0, 0, -10)},
+		{path: "c", date: now},
+	}
+	commitIDs := make([]string, 0, len(objects))
+	for i, obj := range objects {
+		if i > 0 {
+			testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", objects[i-1].path))
+		}
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            obj.path,
+			PhysicalAddress: obj.path + "_address",
+			CreationDate:    obj.date,
+			Size:            10,
+			Checksum:        "checksum",
+		}))
+		commitLog, err := deps.catalog.Commit(ctx, repo, "main", "commit "+obj.path, "tester", nil, swag.Int64(obj.date.Unix()), nil, false)
+		testutil.Must(t, err)
+		commitIDs = append(commitIDs, commitLog.Reference)
+	}
+	_, err = deps.catalog.CreateTag(ctx, repo, "v1", commitIDs[0])
+	testutil.Must(t, err)
+
+	rules := &apigen.GarbageCollectionRules{DefaultRetentionDays: 7, Branches: []apigen.GarbageCollectionRule{}}
+	reclaimable := func(t *testing.T) int64 {
 		t.Helper()
-		const content = "hello world this is my awesome content"
-		uploadResp, err := uploadObjectHelper(t, ctx, clt, objPath, strings.NewReader(content), repository, branch)
-		verifyResponseOK(t, uploadResp, err)
-		require.NotNil(t, uploadResp.JSON201)
-		require.Equal(t, len(content), int(apiutil.Value(uploadResp.JSON201.SizeBytes)))
-		return *uploadResp.JSON201
+		resp, err := clt.PlanGCRulesWithResponse(ctx, repo, apigen.PlanGCRulesJSONRequestBody{Rules: rules})
+		testutil.Must(t, err)
+		return pollPlanGCRules(t, clt, repo, resp).ReclaimableObjects
 	}
+	require.Equal(t, int64(1), reclaimable(t))
 
-	t.Run("same_branch", func(t *testing.T) {
-		const (
-			srcPath  = "foo/bar"
-			destPath = "foo/bar-shallow-copy"
-		)
-		objStat := uploadContent(t, repo, "main", srcPath)
-		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: destPath,
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: srcPath,
-		})
-		verifyResponseOK(t, copyResp, err)
+	t.Run("hold_tag", func(t *testing.T) {
+		resp, err := clt.SetLegalHoldWithResponse(ctx, repo, apigen.SetLegalHoldJSONRequestBody{Kind: "tag", Id: "v1", Reason: "case 1"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, "tag", resp.JSON200.Kind)
+		require.Equal(t, "v1", resp.JSON200.Id)
+		require.NotEmpty(t, resp.JSON200.CreatedBy)
 
-		// Verify the creation path, date and physical address are different
-		copyStat := copyResp.JSON201
-		require.NotNil(t, copyStat)
-		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
-		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
-		require.Equal(t, destPath, copyStat.Path)
+		// objects of the held tag are kept, and the tag cannot be deleted
+		require.Equal(t, int64(0), reclaimable(t))
+		deleteResp, err := clt.DeleteTagWithResponse(ctx, repo, "v1", &apigen.DeleteTagParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, deleteResp.StatusCode())
 
-		// get back info
-		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
-		verifyResponseOK(t, statResp, err)
-		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+		releaseResp, err := clt.DeleteLegalHoldWithResponse(ctx, repo, &apigen.DeleteLegalHoldParams{Kind: "tag", Id: "v1"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, releaseResp.StatusCode())
+		require.Equal(t, int64(1), reclaimable(t))
 	})
 
-	t.Run("different_branch", func(t *testing.T) {
-		const (
-			srcPath  = "foo/bar2"
-			destPath = "foo/bar-full-from-branch"
-		)
-		objStat := uploadContent(t, repo, "alt", srcPath)
-		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: destPath,
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: srcPath,
-			SrcRef:  apiutil.Ptr("alt"),
+	t.Run("hold_commit", func(t *testing.T) {
+		resp, err := clt.SetLegalHoldWithResponse(ctx, repo, apigen.SetLegalHoldJSONRequestBody{Kind: "commit", Id: "main~2", Reason: "case 2"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, commitIDs[0], resp.JSON200.Id)
+		require.Equal(t, int64(0), reclaimable(t))
+
+		listResp, err := clt.ListLegalHoldsWithResponse(ctx, repo)
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, listResp.StatusCode())
+		require.Len(t, listResp.JSON200.Results, 1)
+		require.Equal(t, "case 2", listResp.JSON200.Results[0].Reason)
+
+		releaseResp, err := clt.DeleteLegalHoldWithResponse(ctx, repo, &apigen.DeleteLegalHoldParams{Kind: "commit", Id: commitIDs[0]})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, releaseResp.StatusCode())
+		require.Equal(t, int64(1), reclaimable(t))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		resp, err := clt.SetLegalHoldWithResponse(ctx, repo, apigen.SetLegalHoldJSONRequestBody{Kind: "tag", Id: "missing", Reason: "case 3"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+		releaseResp, err := clt.DeleteLegalHoldWithResponse(ctx, repo, &apigen.DeleteLegalHoldParams{Kind: "tag", Id: "v1"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, releaseResp.StatusCode())
+	})
+}
+
+func TestController_ObjectRetention(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	createRepo := func(t *testing.T) string {
+		t.Helper()
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		return repo
+	}
+	createEntry := func(t *testing.T, repo, path string) {
+		t.Helper()
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: path + "_address",
+			CreationDate:    time.Now(),
+			Size:            10,
+			Checksum:        "checksum",
+		}))
+	}
+	setRetention := func(t *testing.T, repo, path, mode string, until time.Time) int {
+		t.Helper()
+		resp, err := clt.SetObjectRetentionWithResponse(ctx, repo, "main", &apigen.SetObjectRetentionParams{Path: path},
+			apigen.SetObjectRetentionJSONRequestBody{Mode: mode, RetainUntilDate: until.Unix()})
+		testutil.Must(t, err)
+		return resp.StatusCode()
+	}
+	deleteObject := func(t *testing.T, repo, path string) int {
+		t.Helper()
+		resp, err := clt.DeleteObjectWithResponse(ctx, repo, "main", &apigen.DeleteObjectParams{Path: path})
+		testutil.Must(t, err)
+		return resp.StatusCode()
+	}
+
+	t.Run("not_enabled", func(t *testing.T) {
+		repo := createRepo(t)
+		createEntry(t, repo, "a")
+		require.Equal(t, http.StatusBadRequest, setRetention(t, repo, "a", catalog.RetentionModeCompliance, time.Now().Add(time.Hour)))
+		for _, enabled := range []bool{false, false, true} {
+			resp, err := clt.SetObjectLockConfigurationWithResponse(ctx, repo, apigen.SetObjectLockConfigurationJSONRequestBody{Enabled: enabled})
+			testutil.Must(t, err)
+			require.Equal(t, http.StatusNoContent, resp.StatusCode())
+		}
+	})
+
+	repo := createRepo(t)
+	setResp, err := clt.SetObjectLockConfigurationWithResponse(ctx, repo, apigen.SetObjectLockConfigurationJSONRequestBody{Enabled: true})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+	getResp, err := clt.GetObjectLockConfigurationWithResponse(ctx, repo)
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusOK, getResp.StatusCode())
+	require.True(t, getResp.JSON200.Enabled)
+
+	until := time.Now().Add(time.Hour)
+	createEntry(t, repo, "records/compliance")
+	createEntry(t, repo, "records/governance")
+	createEntry(t, repo, "records/free")
+	require.Equal(t, http.StatusNoContent, setRetention(t, repo, "records/compliance", catalog.RetentionModeCompliance, until))
+	require.Equal(t, http.StatusNoContent, setRetention(t, repo, "records/governance", catalog.RetentionModeGovernance, until))
+
+	t.Run("stat", func(t *testing.T) {
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "records/compliance"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, &apigen.ObjectRetention{Mode: catalog.RetentionModeCompliance, RetainUntilDate: until.Unix()}, resp.JSON200.Retention)
+	})
+
+	t.Run("delete_locked", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, deleteObject(t, repo, "records/compliance"))
+		require.Equal(t, http.StatusForbidden, deleteObject(t, repo, "records/governance"))
+	})
+
+	t.Run("overwrite_locked", func(t *testing.T) {
+		resp, err := uploadObjectHelper(t, ctx, clt, "records/compliance", strings.NewReader("new content"), repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode())
+	})
+
+	t.Run("delete_objects", func(t *testing.T) {
+		resp, err := clt.DeleteObjectsWithResponse(ctx, repo, "main", &apigen.DeleteObjectsParams{},
+			apigen.DeleteObjectsJSONRequestBody{Paths: []string{"records/compliance", "records/free"}})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Len(t, resp.JSON200.Errors, 1)
+		require.Equal(t, "records/compliance", swag.StringValue(resp.JSON200.Errors[0].Path))
+		require.Equal(t, http.StatusForbidden, resp.JSON200.Errors[0].StatusCode)
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "records/free", catalog.GetEntryParams{})
+		require.ErrorIs(t, err, graveler.ErrNotFound)
+	})
+
+	t.Run("compliance", func(t *testing.T) {
+		// compliance retention can be extended but never shortened or removed
+		require.Equal(t, http.StatusForbidden, setRetention(t, repo, "records/compliance", catalog.RetentionModeCompliance, until.Add(-time.Minute)))
+		require.Equal(t, http.StatusForbidden, setRetention(t, repo, "records/compliance", catalog.RetentionModeGovernance, until.Add(time.Minute)))
+		resp, err := clt.DeleteObjectRetentionWithResponse(ctx, repo, "main", &apigen.DeleteObjectRetentionParams{Path: "records/compliance", BypassGovernance: swag.Bool(true)})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode())
+		require.Equal(t, http.StatusNoContent, setRetention(t, repo, "records/compliance", catalog.RetentionModeCompliance, until.Add(time.Minute)))
+	})
+
+	t.Run("governance", func(t *testing.T) {
+		resp, err := clt.DeleteObjectRetentionWithResponse(ctx, repo, "main", &apigen.DeleteObjectRetentionParams{Path: "records/governance"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode())
+		resp, err = clt.DeleteObjectRetentionWithResponse(ctx, repo, "main", &apigen.DeleteObjectRetentionParams{Path: "records/governance", BypassGovernance: swag.Bool(true)})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+		require.Equal(t, http.StatusNoContent, deleteObject(t, repo, "records/governance"))
+	})
+
+	t.Run("cannot_disable", func(t *testing.T) {
+		resp, err := clt.SetObjectLockConfigurationWithResponse(ctx, repo, apigen.SetObjectLockConfigurationJSONRequestBody{Enabled: false})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("default_retention", func(t *testing.T) {
+		repo := createRepo(t)
+		resp, err := clt.SetObjectLockConfigurationWithResponse(ctx, repo, apigen.SetObjectLockConfigurationJSONRequestBody{
+			Enabled:              true,
+			DefaultMode:          swag.String(catalog.RetentionModeGovernance),
+			DefaultRetentionDays: swag.Int(1),
 		})
-		verifyResponseOK(t, copyResp, err)
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+		createEntry(t, repo, "a")
+		entry, err := deps.catalog.GetEntry(ctx, repo, "main", "a", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		require.NotNil(t, entry.Retention)
+		require.Equal(t, catalog.RetentionModeGovernance, entry.Retention.Mode)
+		require.WithinDuration(t, time.Now().AddDate(0, 0, 1), entry.Retention.RetainUntilDate, time.Minute)
+		require.Equal(t, http.StatusForbidden, deleteObject(t, repo, "a"))
+	})
 
-		// Verify the creation path, date and physical address are different
-		copyStat := copyResp.JSON201
-		require.NotNil(t, copyStat)
-		require.NotEmpty(t, copyStat.PhysicalAddress)
-		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
-		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
-		require.Equal(t, destPath, copyStat.Path)
+	t.Run("ref_operations", func(t *testing.T) {
+		repo := createRepo(t)
+		resp, err := clt.SetObjectLockConfigurationWithResponse(ctx, repo, apigen.SetObjectLockConfigurationJSONRequestBody{Enabled: true})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+		commit := func(t *testing.T, branch string) string {
+			t.Helper()
+			commitLog, err := deps.catalog.Commit(ctx, repo, branch, "commit", "tester", nil, nil, nil, false)
+			testutil.Must(t, err)
+			return commitLog.Reference
+		}
 
-		// Verify all else is equal
-		objStat.Mtime = copyStat.Mtime
-		objStat.Path = copyStat.Path
-		objStat.PhysicalAddress = copyStat.PhysicalAddress
-		require.Nil(t, deep.Equal(objStat, *copyStat))
+		// 'other' and 'main' both change 'records/a', retained on main
+		createEntry(t, repo, "base")
+		commit(t, "main")
+		_, err = deps.catalog.CreateBranch(ctx, repo, "other", "main")
+		testutil.Must(t, err)
+		createEntry(t, repo, "records/a")
+		require.Equal(t, http.StatusNoContent, setRetention(t, repo, "records/a", catalog.RetentionModeCompliance, time.Now().Add(time.Hour)))
+		lockedCommit := commit(t, "main")
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "other", catalog.DBEntry{
+			Path: "records/a", PhysicalAddress: "other_address", CreationDate: time.Now(), Size: 20, Checksum: "other",
+		}))
+		otherCommit := commit(t, "other")
 
-		// get back info
-		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
-		verifyResponseOK(t, statResp, err)
-		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+		mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "other", "main", apigen.MergeIntoBranchJSONRequestBody{Strategy: swag.String("source-wins")})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, mergeResp.StatusCode())
+
+		cherryPickResp, err := clt.CherryPickWithResponse(ctx, repo, "main", apigen.CherryPickJSONRequestBody{Ref: otherCommit})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, cherryPickResp.StatusCode())
+
+		revertResp, err := clt.RevertBranchWithResponse(ctx, repo, "main", apigen.RevertBranchJSONRequestBody{Ref: lockedCommit})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, revertResp.StatusCode())
+
+		hardResetResp, err := clt.HardResetBranchWithResponse(ctx, repo, "main", &apigen.HardResetBranchParams{Ref: "main~1"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, hardResetResp.StatusCode())
+
+		_, err = deps.catalog.CreateBranch(ctx, repo, "copy", "main")
+		testutil.Must(t, err)
+		deleteResp, err := clt.DeleteBranchWithResponse(ctx, repo, "copy", &apigen.DeleteBranchParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, deleteResp.StatusCode())
+
+		// uncommitted retained objects cannot be reset
+		createEntry(t, repo, "records/b")
+		require.Equal(t, http.StatusNoContent, setRetention(t, repo, "records/b", catalog.RetentionModeCompliance, time.Now().Add(time.Hour)))
+		for _, body := range []apigen.ResetBranchJSONRequestBody{
+			{Type: "object", Path: swag.String("records/b")},
+			{Type: "common_prefix", Path: swag.String("records/")},
+			{Type: "reset"},
+		} {
+			resetResp, err := clt.ResetBranchWithResponse(ctx, repo, "main", body)
+			testutil.Must(t, err)
+			require.Equal(t, http.StatusForbidden, resetResp.StatusCode(), "reset %s", body.Type)
+		}
+		_, err = deps.catalog.GetEntry(ctx, repo, "main", "records/b", catalog.GetEntryParams{})
+		testutil.Must(t, err)
+		stashResp, err := clt.StashBranchWithResponse(ctx, repo, "main", apigen.StashBranchJSONRequestBody{Id: "retained"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, stashResp.StatusCode())
+
+		// the repository cannot be deleted or replaced while it holds retained objects
+		restoreResp, err := clt.RestoreSubmitWithResponse(ctx, repo, apigen.RestoreSubmitJSONRequestBody{
+			CommitsMetaRangeId:  "commits",
+			TagsMetaRangeId:     "tags",
+			BranchesMetaRangeId: "branches",
+			Replace:             swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, restoreResp.StatusCode())
+		deleteRepoResp, err := clt.DeleteRepositoryWithResponse(ctx, repo, &apigen.DeleteRepositoryParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, deleteRepoResp.StatusCode())
+		_, err = deps.catalog.GetRepository(ctx, repo)
+		testutil.Must(t, err)
+
+		// changes that do not touch retained objects are allowed
+		resetResp, err := clt.ResetBranchWithResponse(ctx, repo, "main", apigen.ResetBranchJSONRequestBody{Type: "object", Path: swag.String("base")})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resetResp.StatusCode())
+		deleteResp, err = clt.DeleteBranchWithResponse(ctx, repo, "other", &apigen.DeleteBranchParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, deleteResp.StatusCode())
+	})
+}
+
+func TestController_CostAttribution(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		resp, err := clt.GetCostAttributionWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.Nil(t, resp.JSON200.Tags)
+		require.False(t, swag.BoolValue(resp.JSON200.RequesterPays))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		tags := map[string]string{"team": "analytics", "cost-center": "1234"}
+		setResp, err := clt.SetCostAttributionWithResponse(ctx, repo, apigen.SetCostAttributionJSONRequestBody{
+			Tags:          &apigen.CostAttribution_Tags{AdditionalProperties: tags},
+			RequesterPays: swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+		resp, err := clt.GetCostAttributionWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.NotNil(t, resp.JSON200.Tags)
+		require.Equal(t, tags, resp.JSON200.Tags.AdditionalProperties)
+		require.True(t, swag.BoolValue(resp.JSON200.RequesterPays))
+	})
+
+	t.Run("too_many_tags", func(t *testing.T) {
+		tags := make(map[string]string)
+		for i := 0; i <= catalog.MaxCostAttributionTags; i++ {
+			tags[fmt.Sprintf("tag%d", i)] = "value"
+		}
+		resp, err := clt.SetCostAttributionWithResponse(ctx, repo, apigen.SetCostAttributionJSONRequestBody{
+			Tags: &apigen.CostAttribution_Tags{AdditionalProperties: tags},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("no_repository", func(t *testing.T) {
+		resp, err := clt.GetCostAttributionWithResponse(ctx, "no-such-repo")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
 	})
+}
 
-	t.Run("committed", func(t *testing.T) {
-		const (
-			srcPath  = "foo/bar3"
-			destPath = "foo/bar-full-committed"
-		)
-		objStat := uploadContent(t, repo, "main", srcPath)
-		commitResp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
-			Message: "commit bar3",
-		})
-		verifyResponseOK(t, commitResp, err)
-		require.NotNil(t, commitResp.JSON201)
+func TestController_BlockAdapterOverride(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
 
-		copyResp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: destPath,
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: srcPath,
-			SrcRef:  apiutil.Ptr("main"),
-		})
-		verifyResponseOK(t, copyResp, err)
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	deps.catalog.AdapterOverrideProfiles = []string{"storage-account"}
+	deps.catalog.AdapterOverrideEndpoints = []string{"https://storage.example.com"}
 
-		// Verify the creation path, date and physical address are different
-		copyStat := copyResp.JSON201
-		require.NotNil(t, copyStat)
-		require.NotEmpty(t, copyStat.PhysicalAddress)
-		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
-		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
-		require.Equal(t, destPath, copyStat.Path)
+	t.Run("default", func(t *testing.T) {
+		resp, err := clt.GetBlockAdapterOverrideWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.Nil(t, resp.JSON200.Endpoint)
+		require.Nil(t, resp.JSON200.Profile)
+		require.False(t, swag.BoolValue(resp.JSON200.UseAccelerate))
+	})
 
-		// Verify all else is equal
-		objStat.Mtime = copyStat.Mtime
-		objStat.Path = copyStat.Path
-		objStat.PhysicalAddress = copyStat.PhysicalAddress
-		require.Nil(t, deep.Equal(objStat, *copyStat))
+	t.Run("set", func(t *testing.T) {
+		setResp, err := clt.SetBlockAdapterOverrideWithResponse(ctx, repo, apigen.SetBlockAdapterOverrideJSONRequestBody{
+			Endpoint:       swag.String("https://storage.example.com"),
+			Region:         swag.String("eu-west-1"),
+			Profile:        swag.String("storage-account"),
+			ForcePathStyle: swag.Bool(true),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
 
-		// get back info
-		statResp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: destPath})
-		verifyResponseOK(t, statResp, err)
-		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+		resp, err := clt.GetBlockAdapterOverrideWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "https://storage.example.com", swag.StringValue(resp.JSON200.Endpoint))
+		require.Equal(t, "eu-west-1", swag.StringValue(resp.JSON200.Region))
+		require.True(t, swag.BoolValue(resp.JSON200.ForcePathStyle))
 	})
 
-	t.Run("not_found", func(t *testing.T) {
-		resp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: "bar/foo",
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: "not/found",
-			SrcRef:  apiutil.Ptr("main"),
+	invalid := []struct {
+		name string
+		body apigen.SetBlockAdapterOverrideJSONRequestBody
+	}{
+		{name: "invalid_endpoint", body: apigen.SetBlockAdapterOverrideJSONRequestBody{Endpoint: swag.String("storage.example.com")}},
+		{name: "accelerate_with_endpoint", body: apigen.SetBlockAdapterOverrideJSONRequestBody{Endpoint: swag.String("https://storage.example.com"), UseAccelerate: swag.Bool(true)}},
+		{name: "profile_not_allowed", body: apigen.SetBlockAdapterOverrideJSONRequestBody{Profile: swag.String("other-account")}},
+		{name: "endpoint_not_allowed", body: apigen.SetBlockAdapterOverrideJSONRequestBody{Endpoint: swag.String("http://169.254.169.254"), Profile: swag.String("storage-account")}},
+		{name: "endpoint_without_profile", body: apigen.SetBlockAdapterOverrideJSONRequestBody{Endpoint: swag.String("https://storage.example.com")}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := clt.SetBlockAdapterOverrideWithResponse(ctx, repo, tc.body)
+			testutil.Must(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode())
 		})
-		require.NoError(t, err)
-		require.NotNil(t, resp.JSON404)
+	}
 
-		// without src ref
-		resp, err = clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: "bar/foo",
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: "not/found",
+	t.Run("create_repository", func(t *testing.T) {
+		name := testUniqueRepoName()
+		createResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:                 name,
+			StorageNamespace:     onBlock(deps, name),
+			BlockAdapterOverride: &apigen.BlockAdapterOverride{Region: swag.String("ap-south-1")},
 		})
-		require.NoError(t, err)
-		require.NotNil(t, resp.JSON404)
+		verifyResponseOK(t, createResp, err)
+
+		resp, err := clt.GetBlockAdapterOverrideWithResponse(ctx, name)
+		verifyResponseOK(t, resp, err)
+		require.Equal(t, "ap-south-1", swag.StringValue(resp.JSON200.Region))
 	})
 
-	t.Run("empty_destination", func(t *testing.T) {
-		resp, err := clt.CopyObjectWithResponse(ctx, repo, "main", &apigen.CopyObjectParams{
-			DestPath: "",
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: "foo/bar",
+	t.Run("create_repository_invalid", func(t *testing.T) {
+		name := testUniqueRepoName()
+		createResp, err := clt.CreateRepositoryWithResponse(ctx, &apigen.CreateRepositoryParams{}, apigen.CreateRepositoryJSONRequestBody{
+			Name:                 name,
+			StorageNamespace:     onBlock(deps, name),
+			BlockAdapterOverride: &apigen.BlockAdapterOverride{Profile: swag.String("other-account")},
 		})
-		require.NoError(t, err)
-		require.NotNil(t, resp.JSON400)
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, createResp.StatusCode())
+
+		_, err = deps.catalog.GetRepository(ctx, name)
+		require.ErrorIs(t, err, graveler.ErrNotFound)
 	})
 
-	t.Run("read-only repository", func(t *testing.T) {
-		readOnlyRepository := testUniqueRepoName()
-		_, err = deps.catalog.CreateRepository(ctx, readOnlyRepository, onBlock(deps, "bucket/prefix"), "main", true)
-		require.NoError(t, err)
-		_, err = deps.catalog.CreateBranch(ctx, readOnlyRepository, "alt", "main", graveler.WithForce(true))
-		require.NoError(t, err)
+	t.Run("no_repository", func(t *testing.T) {
+		resp, err := clt.GetBlockAdapterOverrideWithResponse(ctx, "no-such-repo")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
 
-		const (
-			srcPath  = "foo/bar"
-			destPath = "foo/bar-shallow-copy"
-		)
-		uploadContentForce := func(t *testing.T, repository, branch, objPath string) apigen.ObjectStats {
-			t.Helper()
-			const content = "hello world this is my awesome content"
-			var b bytes.Buffer
-			w := multipart.NewWriter(&b)
-			contentWriter, err := w.CreateFormFile("content", filepath.Base(objPath))
-			if err != nil {
-				t.Fatal("CreateFormFile:", err)
-			}
-			if _, err := io.Copy(contentWriter, strings.NewReader(content)); err != nil {
-				t.Fatal("CreateFormFile write content:", err)
-			}
-			if err := w.Close(); err != nil {
-				t.Fatal("Close multipart writer:", err)
-			}
+func TestController_KeyValidationRules(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
 
-			uploadResp, err := clt.UploadObjectWithBodyWithResponse(ctx, readOnlyRepository, branch, &apigen.UploadObjectParams{
-				Path:  objPath,
-				Force: swag.Bool(true),
-			}, w.FormDataContentType(), &b)
-			verifyResponseOK(t, uploadResp, err)
-			require.NotNil(t, uploadResp.JSON201)
-			require.Equal(t, len(content), int(apiutil.Value(uploadResp.JSON201.SizeBytes)))
-			return *uploadResp.JSON201
-		}
-		objStat := uploadContentForce(t, readOnlyRepository, "main", srcPath)
-		copyResp, err := clt.CopyObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.CopyObjectParams{
-			DestPath: destPath,
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: srcPath,
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		resp, err := clt.GetKeyValidationRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.Nil(t, resp.JSON200.MaxKeyLength)
+		require.Nil(t, resp.JSON200.RequiredLayout)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		setResp, err := clt.SetKeyValidationRulesWithResponse(ctx, repo, apigen.SetKeyValidationRulesJSONRequestBody{
+			MaxPathDepth:        swag.Int(4),
+			ForbiddenCharacters: swag.String(" "),
+			RequiredLayout:      swag.String(`tables/[a-z_]+/dt=\d{4}-\d{2}-\d{2}/`),
 		})
 		testutil.Must(t, err)
-		if copyResp.StatusCode() != http.StatusForbidden {
-			t.Fatalf("expected 403 forbidden for CopyObject on read-only repository, got %d instead", copyResp.StatusCode())
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+		resp, err := clt.GetKeyValidationRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.Nil(t, resp.JSON200.MaxKeyLength)
+		require.Equal(t, 4, swag.IntValue(resp.JSON200.MaxPathDepth))
+		require.Equal(t, " ", swag.StringValue(resp.JSON200.ForbiddenCharacters))
+		require.Equal(t, `tables/[a-z_]+/dt=\d{4}-\d{2}-\d{2}/`, swag.StringValue(resp.JSON200.RequiredLayout))
+	})
+
+	t.Run("enforced_on_upload", func(t *testing.T) {
+		resp, err := uploadObjectHelper(t, ctx, clt, "tables/events/dt=2024-01-01/part-0.parquet", strings.NewReader("data"), repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode())
+
+		for _, path := range []string{"tables/events/2024-01-01/part-0.parquet", "tables/events/dt=2024-01-01/part 0.parquet", "tables/events/dt=2024-01-01/h=0/part-0.parquet"} {
+			resp, err := uploadObjectHelper(t, ctx, clt, path, strings.NewReader("data"), repo, "main")
+			testutil.Must(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode(), "upload %s", path)
 		}
-		copyResp, err = clt.CopyObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.CopyObjectParams{
-			DestPath: destPath,
-		}, apigen.CopyObjectJSONRequestBody{
-			SrcPath: srcPath,
-			Force:   swag.Bool(true),
-		})
-		verifyResponseOK(t, copyResp, err)
+	})
 
-		// Verify the creation path, date and physical address are different
-		copyStat := copyResp.JSON201
-		require.NotNil(t, copyStat)
-		require.NotEqual(t, objStat.PhysicalAddress, copyStat.PhysicalAddress)
-		require.GreaterOrEqual(t, copyStat.Mtime, objStat.Mtime)
-		require.Equal(t, destPath, copyStat.Path)
+	t.Run("enforced_on_staging", func(t *testing.T) {
+		resp, err := clt.GetPhysicalAddressWithResponse(ctx, repo, "main", &apigen.GetPhysicalAddressParams{Path: "other/file"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
 
-		// get back info
-		statResp, err := clt.StatObjectWithResponse(ctx, readOnlyRepository, "main", &apigen.StatObjectParams{Path: destPath})
-		verifyResponseOK(t, statResp, err)
-		require.Nil(t, deep.Equal(statResp.JSON200, copyStat))
+	t.Run("invalid_layout", func(t *testing.T) {
+		resp, err := clt.SetKeyValidationRulesWithResponse(ctx, repo, apigen.SetKeyValidationRulesJSONRequestBody{
+			RequiredLayout: swag.String("tables/("),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("no_repository", func(t *testing.T) {
+		resp, err := clt.GetKeyValidationRulesWithResponse(ctx, "no-such-repo")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
 	})
 }
 
-func TestController_OtfDiff(t *testing.T) {
+func TestController_CommitTemplate(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
-	username := "username"
-	_, _ = clt.CreateUserWithResponse(context.Background(), apigen.CreateUserJSONRequestBody{Id: username})
-	server := deps.server
-	authProvider := generateJWTToken(deps.authService, username)
-	nonExistingUserAuthProvider := generateJWTToken(deps.authService, username+"NE")
-	noCredsClient, _ := apigen.NewClientWithResponses(server.URL+apiutil.BaseURL, apigen.WithRequestEditorFn(authProvider.Intercept))
-	noUserClient, _ := apigen.NewClientWithResponses(server.URL+apiutil.BaseURL, apigen.WithRequestEditorFn(nonExistingUserAuthProvider.Intercept))
+	ctx := context.Background()
 
 	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
 
-	diffParams := apigen.OtfDiffParams{
-		TablePath: "path/to/table",
-		Type:      tablediff.ControllerTestPlugin,
-	}
-	testCases := []struct {
-		clt                apigen.ClientWithResponsesInterface
-		expectedHttpStatus int
-		err                error
-		resultDiffType     string
-		description        string
-	}{
-		{
-			clt:                clt,
-			expectedHttpStatus: http.StatusOK,
-			resultDiffType:     "changed",
-			description:        "success - table changed",
-		},
-		{
-			clt:                clt,
-			expectedHttpStatus: http.StatusOK,
-			resultDiffType:     "dropped",
-			description:        "success - table dropped",
-		},
-		{
-			clt:                clt,
-			expectedHttpStatus: http.StatusOK,
-			resultDiffType:     "created",
-			description:        "success - table created",
-		},
-		{
-			clt:                clt,
-			expectedHttpStatus: http.StatusNotFound,
-			err:                tablediff.ErrTableNotFound,
-			description:        "failure - table not found",
-		},
-		{
-			clt:                clt,
-			expectedHttpStatus: http.StatusInternalServerError,
-			err:                tablediff.ErrDiffFailed,
-			description:        "failure - internal error",
-		},
-		{
-			clt:                noUserClient,
-			expectedHttpStatus: http.StatusUnauthorized,
-			description:        "failure - non existing user",
-		},
-		{
-			clt:                noCredsClient,
-			expectedHttpStatus: http.StatusPreconditionFailed,
-			description:        "failure - user without credentials",
-		},
-	}
+	t.Run("default", func(t *testing.T) {
+		resp, err := clt.GetCommitTemplateWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.False(t, swag.BoolValue(resp.JSON200.RequireMessage))
+		require.Nil(t, resp.JSON200.RequiredMetadataKeys)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		setResp, err := clt.SetCommitTemplateWithResponse(ctx, repo, apigen.SetCommitTemplateJSONRequestBody{
+			RequireMessage:       swag.Bool(true),
+			RequiredMetadataKeys: &[]string{"jira-ticket"},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, setResp.StatusCode())
+
+		resp, err := clt.GetCommitTemplateWithResponse(ctx, repo)
+		verifyResponseOK(t, resp, err)
+		require.True(t, swag.BoolValue(resp.JSON200.RequireMessage))
+		require.NotNil(t, resp.JSON200.RequiredMetadataKeys)
+		require.Equal(t, []string{"jira-ticket"}, *resp.JSON200.RequiredMetadataKeys)
+	})
+
+	t.Run("enforced", func(t *testing.T) {
+		testCases := []struct {
+			Name           string
+			Message        string
+			Metadata       map[string]string
+			ExpectedStatus int
+		}{
+			{Name: "no message", Metadata: map[string]string{"jira-ticket": "DATA-1"}, ExpectedStatus: http.StatusBadRequest},
+			{Name: "no metadata", Message: "checkpoint", ExpectedStatus: http.StatusBadRequest},
+			{Name: "empty metadata value", Message: "checkpoint", Metadata: map[string]string{"jira-ticket": ""}, ExpectedStatus: http.StatusBadRequest},
+			{Name: "valid", Message: "checkpoint", Metadata: map[string]string{"jira-ticket": "DATA-1"}, ExpectedStatus: http.StatusCreated},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.Name, func(t *testing.T) {
+				resp, err := clt.CommitWithResponse(ctx, repo, "main", &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+					Message:    tc.Message,
+					Metadata:   &apigen.CommitCreation_Metadata{AdditionalProperties: tc.Metadata},
+					AllowEmpty: swag.Bool(true),
+				})
+				testutil.Must(t, err)
+				require.Equal(t, tc.ExpectedStatus, resp.StatusCode(), "commit response %s", string(resp.Body))
+			})
+		}
+	})
+
+	t.Run("duplicate_key", func(t *testing.T) {
+		resp, err := clt.SetCommitTemplateWithResponse(ctx, repo, apigen.SetCommitTemplateJSONRequestBody{
+			RequiredMetadataKeys: &[]string{"jira-ticket", "jira-ticket"},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
 
-	for _, tc := range testCases {
-		t.Run(tc.description, func(t *testing.T) {
-			currCtx := context.Background()
+func TestController_LockBranch(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
 
-			left := "left"
-			right := "right"
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "feature", "main")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "feature", catalog.DBEntry{Path: "a", PhysicalAddress: "a_address", Checksum: "checksum"}))
+	_, err = deps.catalog.Commit(ctx, repo, "feature", "add a", "tester", nil, nil, nil, false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "other", "main")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "c", PhysicalAddress: "c_address", Checksum: "checksum"}))
+	_, err = deps.catalog.Commit(ctx, repo, "main", "add c", "tester", nil, nil, nil, false)
+	testutil.Must(t, err)
 
-			if tc.resultDiffType == "dropped" {
-				left = "dropped"
-			} else if tc.resultDiffType == "created" {
-				right = "created"
-			}
-			if errors.Is(tc.err, tablediff.ErrTableNotFound) {
-				left = "notfound"
-				right = "notfound"
-			} else if errors.Is(tc.err, tablediff.ErrDiffFailed) {
-				diffParams.Type = "nonExistingPlugin"
-			}
+	lockResp, err := clt.LockBranchWithResponse(ctx, repo, "main", apigen.LockBranchJSONRequestBody{Reason: "quarterly freeze"})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusNoContent, lockResp.StatusCode())
 
-			response, err := tc.clt.OtfDiffWithResponse(currCtx, repo, left, right, &diffParams)
+	t.Run("reported", func(t *testing.T) {
+		resp, err := clt.GetBranchWithResponse(ctx, repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.NotNil(t, resp.JSON200.Lock)
+		require.Equal(t, "quarterly freeze", resp.JSON200.Lock.Reason)
 
-			require.NoError(t, err)
-			require.NotNil(t, response)
-			require.NotEmpty(t, response.StatusCode())
-			require.Equal(t, tc.expectedHttpStatus, response.StatusCode())
-			if tc.expectedHttpStatus == http.StatusOK {
-				require.NotNil(t, response.JSON200)
-				require.Equal(t, tc.resultDiffType, *response.JSON200.DiffType)
-				if tc.resultDiffType != "dropped" {
-					require.NotEmpty(t, response.JSON200.Results)
-				} else {
-					require.Empty(t, response.JSON200.Results)
-				}
-			}
-		})
-	}
-}
+		listResp, err := clt.ListBranchesWithResponse(ctx, repo, &apigen.ListBranchesParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, listResp.StatusCode())
+		for _, ref := range listResp.JSON200.Results {
+			require.Equal(t, ref.Id == "main", ref.Lock != nil, "lock of branch %s", ref.Id)
+		}
+	})
 
-func generateJWTToken(authService auth.Service, username string) *securityprovider.SecurityProviderApiKey {
-	secret := authService.SecretStore().SharedSecret()
-	now := time.Now()
-	expires := now.Add(time.Hour)
-	apiToken, _ := api.GenerateJWTLogin(secret, username, now, expires)
-	authProvider, _ := securityprovider.NewSecurityProviderApiKey("header", "Authorization", "Bearer "+apiToken)
-	return authProvider
+	t.Run("changes_rejected", func(t *testing.T) {
+		mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "feature", "main", apigen.MergeIntoBranchJSONRequestBody{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, mergeResp.StatusCode())
+		require.Contains(t, mergeResp.JSON403.Message, "quarterly freeze")
+
+		err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "b", PhysicalAddress: "b_address", Checksum: "checksum"})
+		require.ErrorIs(t, err, graveler.ErrBranchLocked)
+
+		deleteResp, err := clt.DeleteBranchWithResponse(ctx, repo, "main", &apigen.DeleteBranchParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusForbidden, deleteResp.StatusCode())
+
+		// merging from a locked branch is allowed
+		_, err = deps.catalog.Merge(ctx, repo, "other", "main", "tester", "merge main", nil, "")
+		testutil.Must(t, err)
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		resp, err := clt.UnlockBranchWithResponse(ctx, repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+		mergeResp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "feature", "main", apigen.MergeIntoBranchJSONRequestBody{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, mergeResp.StatusCode())
+
+		resp, err = clt.UnlockBranchWithResponse(ctx, repo, "main")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
 }
 
-func TestController_LocalAdapter_StageObject(t *testing.T) {
-	p := t.TempDir()
-	forbiddenPath := "local:///not_allowed"
-	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeLocal)
-	viper.Set("blockstore.local.path", p)
+func TestController_PassThroughMappings(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
 	repo := testUniqueRepoName()
 	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
-	require.NoError(t, err)
-	_, err = deps.catalog.CreateBranch(ctx, repo, "alt", "main")
-	require.NoError(t, err)
+	testutil.Must(t, err)
 
-	t.Run("stage_forbidden_address", func(t *testing.T) {
-		resp, err := clt.StageObjectWithResponse(ctx, repo, "main", &apigen.StageObjectParams{
-			Path: "some_path",
-		}, apigen.StageObjectJSONRequestBody{
-			PhysicalAddress: forbiddenPath,
+	t.Run("get", func(t *testing.T) {
+		err := deps.catalog.SetPassThroughMappings(ctx, repo, []catalog.PassThroughMapping{
+			{Prefix: "raw/", Upstream: "s3://upstream/raw", ImportOnRead: true},
 		})
-		require.NoError(t, err)
-		require.NotNil(t, resp.JSON403)
+		testutil.Must(t, err)
+
+		resp, err := clt.GetPassThroughMappingsWithResponse(ctx, repo)
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, []apigen.PassThroughMapping{
+			{Prefix: "raw/", Upstream: "s3://upstream/raw/", ImportOnRead: swag.Bool(true)},
+		}, resp.JSON200.Mappings)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := deps.catalog.SetPassThroughMappings(ctx, repo, []catalog.PassThroughMapping{
+			{Prefix: "raw/", Upstream: "s3://upstream/raw/"},
+			{Prefix: "raw/", Upstream: "s3://upstream/other/"},
+		})
+		require.ErrorIs(t, err, graveler.ErrInvalidValue)
+
+		err = deps.catalog.SetPassThroughMappings(ctx, repo, []catalog.PassThroughMapping{
+			{Prefix: "raw/", Upstream: "upstream/raw/"},
+		})
+		require.ErrorIs(t, err, graveler.ErrInvalidValue)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		// the mem block adapter cannot read from upstream locations
+		resp, err := clt.SetPassThroughMappingsWithResponse(ctx, repo, apigen.SetPassThroughMappingsJSONRequestBody{
+			Mappings: []apigen.PassThroughMapping{{Prefix: "raw/", Upstream: "mem://upstream/raw/"}},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+		resp, err = clt.SetPassThroughMappingsWithResponse(ctx, repo, apigen.SetPassThroughMappingsJSONRequestBody{
+			Mappings: []apigen.PassThroughMapping{},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode())
 	})
 }
 
-func TestController_BranchProtectionRules(t *testing.T) {
-	adminClt, deps := setupClientWithAdmin(t)
-	creds := createUserWithDefaultGroup(t, adminClt)
-	regClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyID, creds.SecretAccessKey)
+// upstreamWalkerFactory returns walkers listing objects of an upstream location, sorted by address
+type upstreamWalkerFactory struct {
+	objects []block.ObjectStoreEntry
+}
 
-	testCases := []struct {
-		clt                apigen.ClientWithResponsesInterface
-		expectedHttpStatus int
-		err                error
-		description        string
-	}{
-		{
-			clt:                adminClt,
-			expectedHttpStatus: http.StatusNoContent,
-			description:        "success - admin user",
-		},
-		{
-			clt:                regClt,
-			expectedHttpStatus: http.StatusUnauthorized,
-			description:        "failure - regular user",
-		},
+func (f *upstreamWalkerFactory) GetWalker(_ context.Context, opts store.WalkerOptions) (*store.WalkerWrapper, error) {
+	u, err := url.Parse(opts.StorageURI)
+	if err != nil {
+		return nil, err
 	}
+	return store.NewWrapper(&upstreamWalker{objects: f.objects}, u), nil
+}
 
-	for _, tc := range testCases {
-		t.Run(tc.description, func(t *testing.T) {
-			currCtx := context.Background()
-			repo := testUniqueRepoName()
-			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
-			testutil.MustDo(t, "create repository", err)
-
-			respPreflight, err := tc.clt.CreateBranchProtectionRulePreflightWithResponse(currCtx, repo)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if respPreflight == nil {
-				t.Fatal("CreateBranchProtectionRulePreflightWithResponse got no response")
-			}
-			if respPreflight.StatusCode() != tc.expectedHttpStatus {
-				t.Fatalf("CreateBranchProtectionRulePreflightWithResponse expected %d, got %d", tc.expectedHttpStatus, respPreflight.StatusCode())
-			}
+type upstreamWalker struct {
+	objects []block.ObjectStoreEntry
+}
 
-			// the result of an actual call to the endpoint should have the same result
-			resp, err := tc.clt.InternalCreateBranchProtectionRuleWithResponse(currCtx, repo, apigen.InternalCreateBranchProtectionRuleJSONRequestBody{
-				Pattern: "main",
-			})
-			if err != nil {
-				t.Fatal(err)
-			}
-			if resp == nil {
-				t.Fatal("InternalCreateBranchProtectionRuleWithResponse got no response")
-			}
-			if resp.StatusCode() != respPreflight.StatusCode() {
-				t.Fatalf("InternalCreateBranchProtectionRuleWithResponse and preflight should return the same unauthorized status expected %d, got %d", respPreflight.StatusCode(), resp.StatusCode())
-			}
-		})
+func (w *upstreamWalker) Walk(_ context.Context, storageURI *url.URL, _ block.WalkOptions, walkFn func(e block.ObjectStoreEntry) error) error {
+	for _, e := range w.objects {
+		if !strings.HasPrefix(e.Address, storageURI.String()) {
+			continue
+		}
+		if err := walkFn(e); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func TestController_GarbageCollectionRules(t *testing.T) {
-	adminClt, deps := setupClientWithAdmin(t)
-	creds := createUserWithDefaultGroup(t, adminClt)
-	regClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyID, creds.SecretAccessKey)
+func (w *upstreamWalker) Marker() block.Mark { return block.Mark{} }
 
-	testCases := []struct {
-		clt                apigen.ClientWithResponsesInterface
-		expectedHttpStatus int
-		err                error
-		description        string
-	}{
-		{
-			clt:                adminClt,
-			expectedHttpStatus: http.StatusNoContent,
-			description:        "success - admin user",
-		},
-		{
-			clt:                regClt,
-			expectedHttpStatus: http.StatusUnauthorized,
-			description:        "failure - regular user",
+func (w *upstreamWalker) GetSkippedEntries() []block.ObjectStoreEntry { return nil }
+
+func TestController_PassThroughRead(t *testing.T) {
+	factory := &upstreamWalkerFactory{
+		objects: []block.ObjectStoreEntry{
+			{FullKey: "raw/file", RelativeKey: "file", Address: "mem://upstream/raw/file", ETag: "etag", Mtime: time.Now(), Size: 42},
 		},
 	}
+	// the upstream is on the mem adapter, whatever adapter earlier tests configured
+	blockstoreType := viper.Get(config.BlockstoreTypeKey)
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	t.Cleanup(func() { viper.Set(config.BlockstoreTypeKey, blockstoreType) })
+	clt, deps := setupClientWithAdminAndWalkerFactory(t, factory)
+	ctx := context.Background()
 
-	for _, tc := range testCases {
-		t.Run(tc.description, func(t *testing.T) {
-			currCtx := context.Background()
-			repo := testUniqueRepoName()
-			_, err := deps.catalog.CreateRepository(currCtx, repo, onBlock(deps, repo), "main", false)
-			testutil.MustDo(t, "create repository", err)
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.SetPassThroughMappings(ctx, repo, []catalog.PassThroughMapping{
+		{Prefix: "data/", Upstream: "mem://upstream/raw/"},
+	}))
 
-			respPreflight, err := tc.clt.SetGarbageCollectionRulesPreflightWithResponse(currCtx, repo)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if respPreflight == nil {
-				t.Fatal("SetGarbageCollectionRulesPreflightWithResponse got no response")
-			}
+	t.Run("stat", func(t *testing.T) {
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "data/file"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, "data/file", resp.JSON200.Path)
+		require.Equal(t, int64(42), swag.Int64Value(resp.JSON200.SizeBytes))
+		require.Equal(t, "mem://upstream/raw/file", resp.JSON200.PhysicalAddress)
+	})
 
-			if respPreflight.StatusCode() != tc.expectedHttpStatus {
-				t.Fatalf("SetGarbageCollectionRulesPreflightWithResponse expected %d, got %d", tc.expectedHttpStatus, respPreflight.StatusCode())
-			}
+	t.Run("head", func(t *testing.T) {
+		resp, err := clt.HeadObjectWithResponse(ctx, repo, "main", &apigen.HeadObjectParams{Path: "data/file"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, "42", resp.HTTPResponse.Header.Get("Content-Length"))
+	})
 
-			// the result of an actual call to the endpoint should have the same result
-			resp, err := tc.clt.SetGCRulesWithResponse(currCtx, repo, apigen.SetGCRulesJSONRequestBody{
-				Branches: []apigen.GarbageCollectionRule{{BranchId: "main", RetentionDays: 1}}, DefaultRetentionDays: 5,
-			})
-			if err != nil {
-				t.Fatal(err)
-			}
-			if resp == nil {
-				t.Fatal("CreateBranchProtectionRuleWithResponse got no response")
-			}
-			if resp.Status() != respPreflight.Status() {
-				t.Fatalf("CreateBranchProtectionRuleWithResponse and preflight should return the same status. expected %d, got %d", respPreflight.StatusCode(), resp.StatusCode())
-			}
-		})
+	t.Run("missing_upstream", func(t *testing.T) {
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "data/missing"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("unmapped", func(t *testing.T) {
+		resp, err := clt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "other/file"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_PromoteReplica(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
+	testutil.Must(t, err)
+
+	resp, err := clt.PromoteReplicaWithResponse(ctx, repo)
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	repoResp, err := clt.GetRepositoryWithResponse(ctx, repo)
+	verifyResponseOK(t, repoResp, err)
+	require.False(t, swag.BoolValue(repoResp.JSON200.ReadOnly))
+
+	// writes are accepted once promoted
+	uploadResp, err := uploadObjectHelper(t, ctx, clt, "foo", strings.NewReader("data"), repo, "main")
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, uploadResp.StatusCode())
+
+	t.Run("not_found", func(t *testing.T) {
+		resp, err := clt.PromoteReplicaWithResponse(ctx, "not-a-repo")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_CreateSymlinkManifests(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	storageNamespace := onBlock(deps, repo)
+	_, err := deps.catalog.CreateRepository(ctx, repo, storageNamespace, "main", false)
+	testutil.Must(t, err)
+
+	for _, p := range []string{
+		"tables/events/top.parquet",
+		"tables/events/dt=1/a.parquet",
+		"tables/events/dt=1/_SUCCESS",
+		"tables/events/dt=1/sub/c.parquet",
+		"tables/events/dt=1/z.parquet",
+		"tables/events/dt=2/b.parquet",
+		"tables/events_old/dt=1/old.parquet",
+	} {
+		resp, err := uploadObjectHelper(t, ctx, clt, p, strings.NewReader(p), repo, "main")
+		verifyResponseOK(t, resp, err)
+	}
+	commit, err := deps.catalog.Commit(ctx, repo, "main", "events", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	// uncommitted objects are not listed
+	resp, err := uploadObjectHelper(t, ctx, clt, "tables/events/dt=2/uncommitted.parquet", strings.NewReader("data"), repo, "main")
+	verifyResponseOK(t, resp, err)
+
+	manifestsResp, err := clt.CreateSymlinkManifestsWithResponse(ctx, repo, "main", apigen.CreateSymlinkManifestsJSONRequestBody{
+		Prefix: swag.String("tables/events"),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, manifestsResp.StatusCode())
+	manifests := manifestsResp.JSON201
+	require.Equal(t, commit.Reference, manifests.CommitId)
+	require.Equal(t, storageNamespace+"/symlinks/"+repo+"/"+commit.Reference+"/tables/events", manifests.Location)
+	require.Equal(t, 5, manifests.ObjectsCount)
+	require.Equal(t, 4, manifests.ManifestsCount)
+
+	readManifest := func(dir string) []string {
+		t.Helper()
+		reader, err := deps.blocks.Get(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       "symlinks/" + repo + "/" + commit.Reference + "/" + dir + "/symlink.txt",
+		}, -1)
+		testutil.Must(t, err)
+		defer func() { _ = reader.Close() }()
+		data, err := io.ReadAll(reader)
+		testutil.Must(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
 	}
+	require.Len(t, readManifest("tables/events"), 1)
+	require.Len(t, readManifest("tables/events/dt=1"), 2)
+	require.Len(t, readManifest("tables/events/dt=1/sub"), 1)
+	require.Len(t, readManifest("tables/events/dt=2"), 1)
+
+	t.Run("ref_not_found", func(t *testing.T) {
+		resp, err := clt.CreateSymlinkManifestsWithResponse(ctx, repo, "no-such-ref", apigen.CreateSymlinkManifestsJSONRequestBody{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
 }
 
-func TestController_DumpRestoreRepository(t *testing.T) {
+func TestController_ListJobs(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
 
-	// setup repository with some commits
-	repo := testUniqueRepoName()
-	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	errJob := errors.New("job failed")
+	err := deps.jobsRunner.Register(jobs.Job{
+		Name:     "cleanup",
+		Schedule: "1h",
+		Fn:       func(context.Context) error { return errJob },
+	})
+	testutil.Must(t, err)
+	run, err := deps.jobsRunner.RunJob(ctx, "cleanup")
 	testutil.Must(t, err)
 
-	const commits = 3
-	for i := 0; i < commits; i++ {
-		n := strconv.Itoa(i + 1)
-		p := "foo/bar" + n
-		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, "bar"+n+"addr"), CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n})
-		testutil.MustDo(t, "create entry "+p, err)
-		_, err = deps.catalog.Commit(ctx, repo, "main", "commit"+n, "tester", nil, nil, nil, false)
-		testutil.MustDo(t, "commit "+p, err)
-	}
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListJobsWithResponse(ctx)
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		job := resp.JSON200.Results[0]
+		require.Equal(t, "cleanup", job.Name)
+		require.Equal(t, "1h", job.Schedule)
+		require.NotNil(t, job.LastRun)
+		require.Equal(t, run.ID, job.LastRun.Id)
+		require.Equal(t, "failed", job.LastRun.Status)
+		require.Equal(t, errJob.Error(), swag.StringValue(job.LastRun.Error))
+	})
+
+	t.Run("runs", func(t *testing.T) {
+		resp, err := clt.ListJobRunsWithResponse(ctx, "cleanup", &apigen.ListJobRunsParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, run.ID, resp.JSON200.Results[0].Id)
+		require.NotNil(t, resp.JSON200.Results[0].EndTime)
+	})
 
-	var dumpStatus *apigen.RepositoryDumpStatus
+	t.Run("unknown_job", func(t *testing.T) {
+		resp, err := clt.ListJobRunsWithResponse(ctx, "no-such-job", &apigen.ListJobRunsParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
 
-	t.Run("dump", func(t *testing.T) {
-		dumpResp, err := clt.DumpSubmitWithResponse(ctx, repo)
-		testutil.MustDo(t, "dump submit", err)
-		if dumpResp.JSON202 == nil {
-			t.Fatal("Expected 202 response")
-		}
+func TestController_LifecycleRules(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
 
-		taskID := dumpResp.JSON202.Id
-		ticker := time.NewTicker(500 * time.Millisecond)
-		started := time.Now()
-		for range ticker.C {
-			statusResp, err := clt.DumpStatusWithResponse(ctx, repo, &apigen.DumpStatusParams{TaskId: taskID})
-			testutil.MustDo(t, "dump status", err)
-			if statusResp.JSON200 == nil {
-				t.Fatal("Expected 200 response")
-			}
-			if statusResp.JSON200.Done {
-				dumpStatus = statusResp.JSON200
-				break
-			}
-			if time.Since(started) > 30*time.Second {
-				break
-			}
-		}
-		ticker.Stop()
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
 
-		if dumpStatus == nil {
-			t.Fatal("Expected dump to complete (timed-out)")
-		}
-		if dumpStatus.Error != nil {
-			t.Fatalf("Failed to dump repository refs: %s", *dumpStatus.Error)
-		}
-	})
+	now := time.Now()
+	for _, obj := range []struct {
+		path string
+		date time.Time
+	}{
+		{path: "raw/old", date: now.AddDate(0, 0, -10)},
+		{path: "raw/new", date: now},
+		{path: "curated/old", date: now.AddDate(0, 0, -10)},
+	} {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            obj.path,
+			PhysicalAddress: onBlock(deps, obj.path),
+			CreationDate:    obj.date,
+			Checksum:        "checksum",
+		}))
+	}
+	_, err = deps.catalog.Commit(ctx, repo, "main", "ingest", "tester", nil, nil, nil, false)
+	testutil.Must(t, err)
 
-	t.Run("dump_status_invalid_id", func(t *testing.T) {
-		response, err := clt.DumpStatusWithResponse(ctx, repo, &apigen.DumpStatusParams{TaskId: "invalid"})
-		testutil.MustDo(t, "dump status", err)
-		if response.JSON404 == nil {
-			t.Fatalf("Expected 404 (not found) response, got %s", response.Status())
-		}
+	t.Run("invalid", func(t *testing.T) {
+		resp, err := clt.SetLifecycleRulesWithResponse(ctx, repo, apigen.SetLifecycleRulesJSONRequestBody{
+			Rules: []apigen.LifecycleRule{{Id: "raw", Branch: "main", Prefix: "raw/", ExpirationDays: 0}},
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
 	})
 
-	t.Run("restore", func(t *testing.T) {
-		if dumpStatus == nil || dumpStatus.Refs == nil {
-			t.Skip("Skipping restore test, dump failed")
+	t.Run("set_and_apply", func(t *testing.T) {
+		rules := []apigen.LifecycleRule{
+			{Id: "raw", Branch: "main", Prefix: "raw/", ExpirationDays: 7, Enabled: swag.Bool(true)},
+			{Id: "curated", Branch: "main", Prefix: "curated/", ExpirationDays: 7, Enabled: swag.Bool(false)},
 		}
+		setResp, err := clt.SetLifecycleRulesWithResponse(ctx, repo, apigen.SetLifecycleRulesJSONRequestBody{Rules: rules})
+		verifyResponseOK(t, setResp, err)
+		getResp, err := clt.GetLifecycleRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, getResp, err)
+		require.Equal(t, rules, getResp.JSON200.Rules)
 
-		newRepo := testUniqueRepoName()
-		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main")
-		testutil.MustDo(t, "create bare repository", err)
+		results, err := deps.catalog.ApplyLifecycleRules(ctx, repo, now)
+		testutil.Must(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, 1, results[0].Deleted)
+		require.NotEmpty(t, results[0].CommitID)
 
-		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
-			BranchesMetaRangeId: dumpStatus.Refs.BranchesMetaRangeId,
-			CommitsMetaRangeId:  dumpStatus.Refs.CommitsMetaRangeId,
-			TagsMetaRangeId:     dumpStatus.Refs.TagsMetaRangeId,
-		})
-		testutil.MustDo(t, "restore submit", err)
-		if submitResponse.JSON202 == nil {
-			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
-		}
+		commit, err := deps.catalog.GetCommit(ctx, repo, results[0].CommitID)
+		testutil.Must(t, err)
+		require.Equal(t, catalog.LifecycleCommitter, commit.Committer)
+		require.Equal(t, "raw", commit.Metadata[catalog.LifecycleRuleMetadataKey])
 
-		restoreStatus := pollRestoreStatus(t, clt, newRepo, submitResponse.JSON202.Id)
-		if restoreStatus == nil {
-			t.Fatal("Expected restore to complete (timed-out)")
-		}
-		if restoreStatus.Error != nil {
-			t.Fatalf("Failed to restore repository refs: %s", *restoreStatus.Error)
+		entries, _, err := deps.catalog.ListEntries(ctx, repo, "main", "", "", "", -1)
+		testutil.Must(t, err)
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
 		}
+		require.Equal(t, []string{"curated/old", "raw/new"}, paths)
 	})
 
-	t.Run("restore_invalid_refs", func(t *testing.T) {
-		// delete and recreate repository as bare for restore
-		newRepo := testUniqueRepoName()
-		_, err = deps.catalog.CreateBareRepository(ctx, newRepo, onBlock(deps, repo), "main")
-		testutil.MustDo(t, "create bare repository", err)
-
-		submitResponse, err := clt.RestoreSubmitWithResponse(ctx, newRepo, apigen.RestoreSubmitJSONRequestBody{
-			BranchesMetaRangeId: "invalid",
-			CommitsMetaRangeId:  "invalid",
-			TagsMetaRangeId:     "invalid",
-		})
-		testutil.MustDo(t, "restore submit", err)
-		if submitResponse.JSON202 == nil {
-			t.Fatalf("Expected 202 response, got: %s", submitResponse.Status())
-		}
+	t.Run("uncommitted_changes", func(t *testing.T) {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            "raw/staged",
+			PhysicalAddress: onBlock(deps, "raw/staged"),
+			CreationDate:    now.AddDate(0, 0, -10),
+			Checksum:        "checksum",
+		}))
+		results, err := deps.catalog.ApplyLifecycleRules(ctx, repo, now)
+		testutil.Must(t, err)
+		require.Len(t, results, 1)
+		require.Zero(t, results[0].Deleted)
+		require.NotEmpty(t, results[0].SkipReason)
+	})
 
-		restoreStatus := pollRestoreStatus(t, clt, newRepo, submitResponse.JSON202.Id)
-		if restoreStatus == nil {
-			t.Fatal("Expected restore to complete (timed-out)")
-		}
-		if restoreStatus.Error == nil {
-			t.Fatal("Expected restore to fail, got nil Error")
-		}
-		if !strings.Contains(*restoreStatus.Error, graveler.ErrNotFound.Error()) {
-			t.Fatal("Expected restore to fail with not found error")
-		}
+	t.Run("no_temporary_branches", func(t *testing.T) {
+		branches, _, err := deps.catalog.ListBranches(ctx, repo, "", 10, "")
+		testutil.Must(t, err)
+		require.Len(t, branches, 1)
+		require.Equal(t, "main", branches[0].Name)
 	})
 
-	t.Run("restore_status_invalid_id", func(t *testing.T) {
-		response, err := clt.RestoreStatusWithResponse(ctx, repo, &apigen.RestoreStatusParams{TaskId: "invalid"})
-		testutil.MustDo(t, "restore status", err)
-		if response.JSON404 == nil {
-			t.Fatalf("Expected 404 (not found) response, got %s", response.Status())
-		}
+	t.Run("delete", func(t *testing.T) {
+		delResp, err := clt.DeleteLifecycleRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, delResp, err)
+		getResp, err := clt.GetLifecycleRulesWithResponse(ctx, repo)
+		verifyResponseOK(t, getResp, err)
+		require.Empty(t, getResp.JSON200.Rules)
 	})
 }
 
-func TestController_CreateCommitRecord(t *testing.T) {
-	clt, deps := setupClientWithAdmin(t)
+func TestController_DirectoryStats(t *testing.T) {
+	_, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
-	// expected commit ID for this commit record
-	expectedCommitID := "2996b23ac5ab6e7267c302cf00c751bbb87afddd81c6d03e95f654f77b0f3ded"
-	body := apigen.CreateCommitRecordJSONRequestBody{
-		CommitId:     expectedCommitID,
-		Committer:    "Committer",
-		CreationDate: 0,
-		Generation:   1,
-		Message:      "message",
-		Metadata:     &apigen.CommitRecordCreation_Metadata{AdditionalProperties: map[string]string{"key": "value"}},
-		MetarangeId:  "metarangeId",
-		Parents:      []string{"parent1", "parent2"},
-		Version:      1,
-	}
 
-	t.Run("create commit record", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "bucket/prefix"), "main", false)
+	testutil.Must(t, err)
+
+	createEntry := func(path string, size int64) {
+		t.Helper()
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: onBlock(deps, path),
+			CreationDate:    time.Now(),
+			Size:            size,
+			Checksum:        "checksum-" + path,
+		}))
+	}
+	requireStats := func(ref, prefix string, objectCount, sizeBytes int64) {
+		t.Helper()
+		stats, err := deps.catalog.GetDirectoryStats(ctx, repo, ref, prefix)
 		testutil.Must(t, err)
-		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
-		testutil.MustDo(t, "create commit record", err)
-		if resp.StatusCode() != http.StatusNoContent {
-			t.Fatalf("Expected 204 (no content) response, got %s", resp.Status())
-		}
-		commitLog, err := deps.catalog.GetCommit(ctx, repo, expectedCommitID)
-		testutil.MustDo(t, "get commit", err)
-		expectedCommitLog := &catalog.CommitLog{
-			Reference:    expectedCommitID,
-			Committer:    body.Committer,
-			Message:      body.Message,
-			CreationDate: time.Unix(0, body.CreationDate).UTC(),
-			Metadata:     body.Metadata.AdditionalProperties,
-			MetaRangeID:  body.MetarangeId,
-			Parents:      body.Parents,
-			Generation:   catalog.CommitGeneration(body.Generation),
-			Version:      catalog.CommitVersion(body.Version),
-		}
-		if diff := deep.Equal(commitLog, expectedCommitLog); diff != nil {
-			t.Fatalf("Diff: %v", diff)
-		}
+		require.Equal(t, objectCount, stats.ObjectCount, "object count of %s on %s", prefix, ref)
+		require.Equal(t, sizeBytes, stats.SizeBytes, "size of %s on %s", prefix, ref)
+	}
+
+	createEntry("a/1", 10)
+	createEntry("a/b/2", 20)
+	createEntry("c/3", 30)
+	first, err := deps.catalog.Commit(ctx, repo, "main", "first", "tester", nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	t.Run("scan", func(t *testing.T) {
+		requireStats(first.Reference, "", 3, 60)
+		requireStats(first.Reference, "a/", 2, 30)
+		requireStats(first.Reference, "a/b/", 1, 20)
+		requireStats(first.Reference, "d/", 0, 0)
 	})
 
-	t.Run("create commit record with wrong commitID", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
-		testutil.Must(t, err)
-		bodyCpy := body
-		bodyCpy.CommitId = "wrong"
-		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, bodyCpy)
-		testutil.MustDo(t, "create commit record", err)
-		if resp.StatusCode() != http.StatusBadRequest {
-			t.Fatalf("Expected 400 (bad request) response, got %s", resp.Status())
-		}
+	createEntry("a/b/4", 40)
+	createEntry("a/1", 15)
+	testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "main", "c/3"))
+
+	t.Run("uncommitted", func(t *testing.T) {
+		requireStats("main", "a/", 3, 75)
+		requireStats("main", "", 3, 75)
+		requireStats("main@", "a/", 2, 30)
 	})
 
-	t.Run("read only repository", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
-		testutil.Must(t, err)
-		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
-		testutil.Must(t, err)
-		if resp.StatusCode() != http.StatusForbidden {
-			t.Fatalf("Create commit record in read-only repository should be forbidden (403), got %s", resp.Status())
-		}
-		body.Force = swag.Bool(true)
-		resp, err = clt.CreateCommitRecordWithResponse(ctx, repo, body)
-		testutil.MustDo(t, "create commit record", err)
-		if resp.StatusCode() != http.StatusNoContent {
-			t.Fatalf("Expected 204 response, got: %s", resp.Status())
-		}
+	second, err := deps.catalog.Commit(ctx, repo, "main", "second", "tester", nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	t.Run("from_parent", func(t *testing.T) {
+		requireStats(second.Reference, "a/", 3, 75)
+		requireStats(second.Reference, "a/b/", 2, 60)
+		requireStats(second.Reference, "c/", 0, 0)
+		requireStats(first.Reference, "a/", 2, 30)
 	})
 
-	t.Run("already existing commit", func(t *testing.T) {
-		repo := testUniqueRepoName()
-		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	t.Run("from_ancestor", func(t *testing.T) {
+		// mark the kept stats of the second commit, to tell derived stats from listed stats
+		repository, err := deps.catalog.Store.GetRepository(ctx, graveler.RepositoryID(repo))
 		testutil.Must(t, err)
-		resp, err := clt.CreateCommitRecordWithResponse(ctx, repo, body)
-		testutil.MustDo(t, "create commit record", err)
-		if resp.StatusCode() != http.StatusNoContent {
-			t.Fatalf("Expected 204 (no content) response, got %s", resp.Status())
-		}
-		resp, err = clt.CreateCommitRecordWithResponse(ctx, repo, body)
-		if resp.StatusCode() != http.StatusConflict {
-			t.Fatalf("Expected 409 (conflict) response, got %s", resp.Status())
-		}
-	})
-}
+		testutil.Must(t, kv.SetMsg(ctx, deps.catalog.KVStore, graveler.RepoPartition(repository),
+			[]byte(catalog.DirectoryStatsPath(graveler.CommitID(second.Reference), "a/")),
+			&catalog.DirectoryStats{ObjectCount: 100, SizeBytes: 1000}))
 
-// pollRestoreStatus polls the restore status endpoint until the restore is complete or times out.
-// test will fail in case of error.
-// will return nil in case of timeout.
-func pollRestoreStatus(t *testing.T, clt apigen.ClientWithResponsesInterface, repo string, taskID string) *apigen.RepositoryRestoreStatus {
-	t.Helper()
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-	started := time.Now()
-	for range ticker.C {
-		statusResponse, err := clt.RestoreStatusWithResponse(context.Background(), repo, &apigen.RestoreStatusParams{TaskId: taskID})
-		testutil.MustDo(t, "restore status", err)
-		if statusResponse.JSON200 == nil {
-			t.Fatalf("Expected 200 response, got: %s", statusResponse.Status())
-		}
-		if statusResponse.JSON200.Done {
-			return statusResponse.JSON200
-		}
-		if time.Since(started) > 30*time.Second {
-			break
-		}
-	}
-	return nil
+		createEntry("a/5", 50)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "third", "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		createEntry("c/6", 60)
+		_, err = deps.catalog.Commit(ctx, repo, "main", "fourth", "tester", nil, nil, nil, false)
+		testutil.Must(t, err)
+		requireStats("main", "a/", 101, 1050)
+	})
 }
@@ -5,9 +5,13 @@ import (
 )
 
 var (
-	ErrFailedToAccessStorage = errors.New("failed to access storage")
-	ErrAuthenticatingRequest = errors.New("error authenticating request")
-	ErrInvalidAPIEndpoint    = errors.New("invalid API endpoint")
-	ErrRequestSizeExceeded   = errors.New("request size exceeded")
-	ErrStorageNamespaceInUse = errors.New("storage namespace already in use")
+	ErrFailedToAccessStorage  = errors.New("failed to access storage")
+	ErrAuthenticatingRequest  = errors.New("error authenticating request")
+	ErrInvalidAPIEndpoint     = errors.New("invalid API endpoint")
+	ErrRequestSizeExceeded    = errors.New("request size exceeded")
+	ErrStorageNamespaceInUse  = errors.New("storage namespace already in use")
+	ErrStorageNamespaceNested = errors.New("storage namespace nested with the storage namespace of another repository")
+	ErrInvalidKeyValuePair    = errors.New("invalid key=value pair")
+	ErrObjectTooLargeToDiff   = errors.New("object too large to diff")
+	ErrBinaryObjectDiff       = errors.New("cannot diff binary object")
 )
@@ -2,14 +2,22 @@ package helpers
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/uri"
 	"golang.org/x/sync/errgroup"
 )
@@ -18,6 +26,23 @@ const (
 	MinDownloadPartSize        int64 = 1024 * 64       // 64KB
 	DefaultDownloadPartSize    int64 = 1024 * 1024 * 8 // 8MB
 	DefaultDownloadConcurrency       = 10
+
+	// downloadPartRetries is the number of times a failed part download is retried
+	downloadPartRetries = 3
+
+	mib = 1024 * 1024
+)
+
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrObjectChanged    = errors.New("object changed during download")
+
+	// md5ChecksumRegexp matches checksums that are the MD5 digest of the object content
+	md5ChecksumRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	// commonUploadPartSizes are the part sizes objects are commonly uploaded in: by lakectl, the AWS CLI and SDKs,
+	// and Hadoop S3A
+	commonUploadPartSizes = []int64{MinUploadPartSize, 8 * mib, 16 * mib, 64 * mib, 128 * mib}
 )
 
 type Downloader struct {
@@ -25,6 +50,10 @@ type Downloader struct {
 	PreSign    bool
 	HTTPClient *http.Client
 	PartSize   int64
+	// Concurrency is the number of parts of a single object downloaded at the same time. When more than 1, objects
+	// not downloaded using presigned URLs are also downloaded in parts, using range requests through lakeFS.
+	// Presigned URLs are downloaded in DefaultDownloadConcurrency parts unless it is more than 1.
+	Concurrency int
 }
 
 type downloadPart struct {
@@ -42,10 +71,11 @@ func NewDownloader(client *apigen.ClientWithResponses, preSign bool) *Downloader
 	}
 
 	return &Downloader{
-		Client:     client,
-		PreSign:    preSign,
-		HTTPClient: httpClient,
-		PartSize:   DefaultDownloadPartSize,
+		Client:      client,
+		PreSign:     preSign,
+		HTTPClient:  httpClient,
+		PartSize:    DefaultDownloadPartSize,
+		Concurrency: 1,
 	}
 }
 
@@ -60,6 +90,9 @@ func (d *Downloader) Download(ctx context.Context, src uri.URI, dst string) erro
 	if d.PreSign {
 		// download using presigned multipart download, it will fall back to presign single object download if needed
 		err = d.downloadPresignMultipart(ctx, src, dst)
+	} else if d.Concurrency > 1 {
+		// download using range requests through lakeFS, it will fall back to single object download if needed
+		err = d.downloadMultipart(ctx, src, dst)
 	} else {
 		err = d.downloadObject(ctx, src, dst)
 	}
@@ -90,6 +123,38 @@ func (d *Downloader) downloadPresignMultipart(ctx context.Context, src uri.URI,
 		return d.downloadObject(ctx, src, dst)
 	}
 
+	concurrency := d.Concurrency
+	if concurrency <= 1 {
+		concurrency = DefaultDownloadConcurrency
+	}
+	physicalAddress := statResp.JSON200.PhysicalAddress
+	return d.downloadParts(ctx, dst, sizeBytes, statResp.JSON200.Checksum, concurrency, func(ctx context.Context, part downloadPart, f *os.File, buf []byte) error {
+		return d.downloadPresignedPart(ctx, physicalAddress, part.RangeStart, part.PartSize, part.Number, f, buf)
+	})
+}
+
+func (d *Downloader) downloadMultipart(ctx context.Context, src uri.URI, dst string) error {
+	statResp, err := d.Client.StatObjectWithResponse(ctx, src.Repository, src.Ref, &apigen.StatObjectParams{
+		Path: *src.Path,
+	})
+	if err != nil {
+		return err
+	}
+
+	// fallback to download if missing size or the object is small enough to download in one request
+	if statResp.JSON200 == nil || statResp.JSON200.SizeBytes == nil || *statResp.JSON200.SizeBytes < d.PartSize {
+		return d.downloadObject(ctx, src, dst)
+	}
+
+	checksum := statResp.JSON200.Checksum
+	return d.downloadParts(ctx, dst, *statResp.JSON200.SizeBytes, checksum, d.Concurrency, func(ctx context.Context, part downloadPart, f *os.File, buf []byte) error {
+		return d.downloadObjectPart(ctx, src, checksum, part, f, buf)
+	})
+}
+
+// downloadParts creates dst in the size of the object, and downloads its parts concurrently using download, retrying
+// failed parts. The downloaded file is verified against the object checksum.
+func (d *Downloader) downloadParts(ctx context.Context, dst string, size int64, checksum string, concurrency int, download func(ctx context.Context, part downloadPart, f *os.File, buf []byte) error) error {
 	f, err := os.Create(dst)
 	if err != nil {
 		return err
@@ -99,33 +164,36 @@ func (d *Downloader) downloadPresignMultipart(ctx context.Context, src uri.URI,
 	}()
 
 	// make sure the destination file is in the right size
-	size := swag.Int64Value(statResp.JSON200.SizeBytes)
 	if err := f.Truncate(size); err != nil {
 		return fmt.Errorf("failed to truncate '%s' to size %d: %w", f.Name(), size, err)
 	}
 
-	// download the file using ranges and concurrency
-	physicalAddress := statResp.JSON200.PhysicalAddress
-
-	ch := make(chan downloadPart, DefaultDownloadConcurrency)
+	ch := make(chan downloadPart, concurrency)
 	// start download workers
-	g, grpCtx := errgroup.WithContext(context.Background())
-	for i := 0; i < DefaultDownloadConcurrency; i++ {
+	g, grpCtx := errgroup.WithContext(ctx)
+	for i := 0; i < concurrency; i++ {
 		g.Go(func() error {
 			buf := make([]byte, d.PartSize)
 			for part := range ch {
-				err := d.downloadPresignedPart(grpCtx, physicalAddress, part.RangeStart, part.PartSize, part.Number, f, buf)
-				if err != nil {
+				bo := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), downloadPartRetries), grpCtx)
+				err := backoff.Retry(func() error {
+					err := download(grpCtx, part, f, buf)
+					if errors.Is(err, ErrObjectChanged) {
+						return backoff.Permanent(err)
+					}
 					return err
+				}, bo)
+				if err != nil {
+					return fmt.Errorf("part %d: %w", part.Number, err)
 				}
 			}
 			return nil
 		})
 	}
 
-	// send parts to download to the channel
+	// send parts to download to the channel, until all are sent or a worker failed
 	partNumber := 0
-	for off := int64(0); off < size; off += d.PartSize {
+	for off := int64(0); off < size && grpCtx.Err() == nil; off += d.PartSize {
 		partNumber++ // part numbers start from 1
 		part := downloadPart{
 			Number:     partNumber,
@@ -136,11 +204,79 @@ func (d *Downloader) downloadPresignMultipart(ctx context.Context, src uri.URI,
 		if part.RangeStart+part.PartSize > size {
 			part.PartSize = size - part.RangeStart
 		}
-		ch <- part
+		select {
+		case ch <- part:
+		case <-grpCtx.Done():
+		}
 	}
 	close(ch)
 
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return verifyChecksum(f, size, checksum)
+}
+
+// verifyChecksum compares the content of f with checksum. An MD5 digest is compared with the MD5 of the content. The
+// checksum of an object uploaded in parts, the MD5 of the MD5s of its parts, is compared with the checksum of the
+// content split into parts of each size the object may have been uploaded in. Other checksums are not verified.
+func verifyChecksum(f *os.File, size int64, checksum string) error {
+	if md5ChecksumRegexp.MatchString(checksum) {
+		h := md5.New() //nolint:gosec
+		if _, err := io.Copy(h, io.NewSectionReader(f, 0, size)); err != nil {
+			return err
+		}
+		if digest := hex.EncodeToString(h.Sum(nil)); digest != checksum {
+			return fmt.Errorf("%w: '%s' has MD5 %s, expected %s", ErrChecksumMismatch, f.Name(), digest, checksum)
+		}
+		return nil
+	}
+	parts, ok := block.MultipartETagPartsCount(checksum)
+	if !ok {
+		return nil
+	}
+	partSizes := uploadPartSizes(size, parts)
+	for _, partSize := range partSizes {
+		digest, err := partsChecksum(f, size, partSize)
+		if err != nil {
+			return err
+		}
+		if digest == checksum {
+			return nil
+		}
+	}
+	if len(partSizes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' does not match %s in parts of sizes %v", ErrChecksumMismatch, f.Name(), checksum, partSizes)
+}
+
+// uploadPartSizes returns the part sizes, common ones or the smallest whole MiB, that split size into parts parts
+func uploadPartSizes(size int64, parts int) []int64 {
+	n := int64(parts)
+	smallest := ((size+n-1)/n + mib - 1) / mib * mib
+	var partSizes []int64
+	for _, partSize := range append(slices.Clone(commonUploadPartSizes), smallest) {
+		if (size+partSize-1)/partSize == n && !slices.Contains(partSizes, partSize) {
+			partSizes = append(partSizes, partSize)
+		}
+	}
+	return partSizes
+}
+
+// partsChecksum returns the checksum of the content of f uploaded in parts of partSize: the MD5 of the MD5s of the
+// parts followed by the number of parts
+func partsChecksum(f *os.File, size, partSize int64) (string, error) {
+	var parts []block.MultipartPart
+	for off := int64(0); off < size; off += partSize {
+		h := md5.New() //nolint:gosec
+		if _, err := io.Copy(h, io.NewSectionReader(f, off, min(partSize, size-off))); err != nil {
+			return "", err
+		}
+		parts = append(parts, block.MultipartPart{PartNumber: len(parts) + 1, ETag: hex.EncodeToString(h.Sum(nil))})
+	}
+	etag, _ := block.MultipartETag(parts)
+	return etag, nil
 }
 
 func (d *Downloader) downloadPresignedPart(ctx context.Context, physicalAddress string, rangeStart int64, partSize int64, partNumber int, f *os.File, buf []byte) error {
@@ -183,6 +319,37 @@ func (d *Downloader) downloadPresignedPart(ctx context.Context, physicalAddress
 	return nil
 }
 
+// downloadObjectPart downloads a part of the object through lakeFS using a range request. The part is rejected if the
+// object content no longer matches checksum, since src may be on a branch that changes during the download.
+func (d *Downloader) downloadObjectPart(ctx context.Context, src uri.URI, checksum string, part downloadPart, f *os.File, buf []byte) error {
+	rangeHeader := fmt.Sprintf("bytes=%d-%d", part.RangeStart, part.RangeStart+part.PartSize-1)
+	resp, err := d.Client.GetObject(ctx, src.Repository, src.Ref, &apigen.GetObjectParams{
+		Path:  *src.Path,
+		Range: &rangeHeader,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("%w: %s", ErrRequestFailed, resp.Status)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && etag != httputil.ETag(checksum) {
+		return fmt.Errorf("%w: %s", ErrObjectChanged, src.String())
+	}
+	if resp.ContentLength != part.PartSize {
+		return fmt.Errorf("%w: part %d expected %d bytes, got %d", ErrRequestFailed, part.Number, part.PartSize, resp.ContentLength)
+	}
+
+	buf = buf[:part.PartSize]
+	if _, err := io.ReadFull(resp.Body, buf); err != nil {
+		return err
+	}
+	_, err = f.WriteAt(buf, part.RangeStart)
+	return err
+}
+
 func (d *Downloader) downloadObject(ctx context.Context, src uri.URI, dst string) error {
 	// get object content
 	resp, err := d.Client.GetObject(ctx, src.Repository, src.Ref, &apigen.GetObjectParams{
@@ -0,0 +1,121 @@
+package helpers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+// objectServer serves a single object on the lakeFS stat and get object API paths, counting range requests
+type objectServer struct {
+	content       []byte
+	checksum      string
+	rangeRequests atomic.Int32
+}
+
+func (s *objectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/objects/stat"):
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apigen.ObjectStats{
+			Checksum:  s.checksum,
+			Path:      r.URL.Query().Get("path"),
+			PathType:  "object",
+			SizeBytes: swag.Int64(int64(len(s.content))),
+		})
+	case strings.HasSuffix(r.URL.Path, "/objects"):
+		if r.Header.Get("Range") != "" {
+			s.rangeRequests.Add(1)
+		}
+		w.Header().Set("ETag", httputil.ETag(s.checksum))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(s.content))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// uploadedChecksum returns the checksum of content uploaded in parts of partSize
+func uploadedChecksum(content []byte, partSize int) string {
+	var parts []block.MultipartPart
+	for off := 0; off < len(content); off += partSize {
+		sum := md5.Sum(content[off:min(off+partSize, len(content))]) //nolint:gosec
+		parts = append(parts, block.MultipartPart{PartNumber: len(parts) + 1, ETag: hex.EncodeToString(sum[:])})
+	}
+	etag, _ := block.MultipartETag(parts)
+	return etag
+}
+
+func TestDownloader_Parallel(t *testing.T) {
+	const mib = 1024 * 1024
+	content := make([]byte, 12*mib+1234)
+	_, _ = rand.New(rand.NewSource(1)).Read(content) //nolint:gosec
+	contentMD5 := md5.Sum(content)                   //nolint:gosec
+
+	cases := []struct {
+		name          string
+		checksum      string
+		concurrency   int
+		expectedErr   error
+		expectedRange bool
+	}{
+		{name: "md5", checksum: hex.EncodeToString(contentMD5[:]), concurrency: 4, expectedRange: true},
+		{name: "uploaded in parts", checksum: uploadedChecksum(content, 8*mib), concurrency: 4, expectedRange: true},
+		{name: "uploaded in uncommon parts", checksum: uploadedChecksum(content, 7*mib), concurrency: 4, expectedRange: true},
+		{name: "mismatch", checksum: uploadedChecksum(content[1:], 8*mib), concurrency: 4, expectedErr: helpers.ErrChecksumMismatch, expectedRange: true},
+		{name: "single request", checksum: hex.EncodeToString(contentMD5[:]), concurrency: 1},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &objectServer{content: content, checksum: tt.checksum}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+			client, err := apigen.NewClientWithResponses(ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := helpers.NewDownloader(client, false)
+			d.PartSize = mib
+			if tt.concurrency != 1 {
+				d.Concurrency = tt.concurrency
+			}
+			dst := filepath.Join(t.TempDir(), "object")
+			err = d.Download(context.Background(), uri.URI{Repository: "repo", Ref: "main", Path: swag.String("large")}, dst)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Download() error = %v, expected %v", err, tt.expectedErr)
+			}
+			if ranged := server.rangeRequests.Load() > 0; ranged != tt.expectedRange {
+				t.Errorf("range requests sent %t, expected %t", ranged, tt.expectedRange)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			downloaded, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(downloaded, content) {
+				t.Error("downloaded content differs from the object")
+			}
+		})
+	}
+}
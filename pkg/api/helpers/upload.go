@@ -37,6 +37,13 @@ const DefaultUploadConcurrency = 5
 
 // ClientUpload uploads contents as a file via lakeFS
 func ClientUpload(ctx context.Context, client apigen.ClientWithResponsesInterface, repoID, branchID, objPath string, metadata map[string]string, contentType string, contents io.ReadSeeker) (*apigen.ObjectStats, error) {
+	return ClientUploadWithParams(ctx, client, repoID, branchID, &apigen.UploadObjectParams{Path: objPath}, metadata, contentType, contents)
+}
+
+// ClientUploadWithParams uploads contents as a file via lakeFS, to the path of params. Contents are streamed, and
+// are not read again.
+func ClientUploadWithParams(ctx context.Context, client apigen.ClientWithResponsesInterface, repoID, branchID string, params *apigen.UploadObjectParams, metadata map[string]string, contentType string, contents io.Reader) (*apigen.ObjectStats, error) {
+	objPath := params.Path
 	pr, pw := io.Pipe()
 	defer func() {
 		_ = pr.Close()
@@ -75,9 +82,7 @@ func ClientUpload(ctx context.Context, client apigen.ClientWithResponsesInterfac
 		}
 	}()
 
-	resp, err := client.UploadObjectWithBodyWithResponse(ctx, repoID, branchID, &apigen.UploadObjectParams{
-		Path: objPath,
-	}, mpContentType, pr, func(ctx context.Context, req *http.Request) error {
+	resp, err := client.UploadObjectWithBodyWithResponse(ctx, repoID, branchID, params, mpContentType, pr, func(ctx context.Context, req *http.Request) error {
 		var metaKey string
 		for k, v := range metadata {
 			lowerKey := strings.ToLower(k)
@@ -15,8 +15,13 @@ const (
 // It supports backward compatibility for creating a login jwt. The audience is not set for login token. Any audience will make the token
 // invalid for login. No email is passed to support the ability of login for users via user/access keys which don't have an email yet
 func GenerateJWTLogin(secret []byte, userID string, issuedAt, expiresAt time.Time) (string, error) {
+	return GenerateJWTLoginWithID(secret, uuid.NewString(), userID, issuedAt, expiresAt)
+}
+
+// GenerateJWTLoginWithID creates a login jwt token identified by tokenID, which is also the ID of its login session
+func GenerateJWTLoginWithID(secret []byte, tokenID, userID string, issuedAt, expiresAt time.Time) (string, error) {
 	claims := &jwt.StandardClaims{
-		Id:        uuid.NewString(),
+		Id:        tokenID,
 		Audience:  LoginAudience,
 		Subject:   userID,
 		IssuedAt:  issuedAt.Unix(),
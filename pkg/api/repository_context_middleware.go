@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// RepositoryContextMiddleware applies the settings of the repository of the request to the block adapter: its cost
+// attribution, also added to the audit log of the request, and its block adapter override
+func RepositoryContextMiddleware(swagger *openapi3.Swagger, c *catalog.Catalog) func(http.Handler) http.Handler {
+	router, err := legacy.NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pathParams, err := router.FindRoute(r)
+			repository := pathParams["repository"]
+			if err != nil || repository == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// the request handler reports a missing repository
+			ctx, err := c.WithCostAttribution(r.Context(), repository)
+			if err != nil {
+				logging.FromContext(ctx).WithError(err).WithField("repository", repository).Debug("Failed to get cost attribution")
+			}
+			ctx, err = c.WithBlockAdapterOverride(ctx, repository)
+			if err != nil {
+				logging.FromContext(ctx).WithError(err).WithField("repository", repository).Debug("Failed to get block adapter override")
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
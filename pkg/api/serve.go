@@ -21,8 +21,10 @@ import (
 	"github.com/treeverse/lakefs/pkg/cloud"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/jobs"
 	"github.com/treeverse/lakefs/pkg/logging"
 	tablediff "github.com/treeverse/lakefs/pkg/plugins/diff"
+	"github.com/treeverse/lakefs/pkg/search"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/upload"
 )
@@ -34,7 +36,7 @@ const (
 	extensionValidationExcludeBody = "x-validation-exclude-body"
 )
 
-func Serve(cfg *config.Config, catalog *catalog.Catalog, middlewareAuthenticator auth.Authenticator, authService auth.Service, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, gatewayDomains []string, snippets []params.CodeSnippet, pathProvider upload.PathProvider, otfService *tablediff.Service, usageReporter stats.UsageReporterOperations) http.Handler {
+func Serve(cfg *config.Config, catalog *catalog.Catalog, middlewareAuthenticator auth.Authenticator, authService auth.Service, lockout *auth.Lockout, usage *auth.CredentialsUsage, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, gatewayDomains []string, snippets []params.CodeSnippet, pathProvider upload.PathProvider, otfService *tablediff.Service, usageReporter stats.UsageReporterOperations, jobsRunner *jobs.Runner, searchService *search.Service) http.Handler {
 	logger.Info("initialize OpenAPI server")
 	swagger, err := apigen.GetSwagger()
 	if err != nil {
@@ -44,7 +46,14 @@ func Serve(cfg *config.Config, catalog *catalog.Catalog, middlewareAuthenticator
 	oidcConfig := OIDCConfig(cfg.Auth.OIDC)
 	cookieAuthConfig := CookieAuthConfig(cfg.Auth.CookieAuthVerification)
 	r := chi.NewRouter()
-	apiRouter := r.With(
+	var middlewares []func(http.Handler) http.Handler
+	if cfg.HTTP.Compression.Enabled {
+		middlewares = append(middlewares,
+			httputil.CompressResponseMiddleware(cfg.HTTP.Compression.Level, "application/json"),
+			httputil.DecompressRequestMiddleware(cfg.HTTP.Compression.MaxRequestBodySize, "application/json"),
+		)
+	}
+	apiRouter := r.With(append(middlewares,
 		OapiRequestValidatorWithOptions(swagger, &openapi3filter.Options{
 			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
 		}),
@@ -53,10 +62,11 @@ func Serve(cfg *config.Config, catalog *catalog.Catalog, middlewareAuthenticator
 			logging.Fields{logging.ServiceNameFieldKey: LoggerServiceName},
 			cfg.Logging.AuditLogLevel,
 			cfg.Logging.TraceRequestHeaders),
-		AuthMiddleware(logger, swagger, middlewareAuthenticator, authService, sessionStore, &oidcConfig, &cookieAuthConfig),
+		AuthMiddleware(logger, swagger, middlewareAuthenticator, authService, lockout, usage, sessionStore, &oidcConfig, &cookieAuthConfig),
+		RepositoryContextMiddleware(swagger, catalog),
 		MetricsMiddleware(swagger),
-	)
-	controller := NewController(cfg, catalog, middlewareAuthenticator, authService, blockAdapter, metadataManager, migrator, collector, cloudMetadataProvider, actions, auditChecker, logger, sessionStore, pathProvider, otfService, usageReporter)
+	)...)
+	controller := NewController(cfg, catalog, middlewareAuthenticator, authService, lockout, usage, blockAdapter, metadataManager, migrator, collector, cloudMetadataProvider, actions, auditChecker, logger, sessionStore, pathProvider, otfService, usageReporter, jobsRunner, searchService)
 	apigen.HandlerFromMuxWithBaseURL(controller, apiRouter, apiutil.BaseURL)
 
 	r.Mount("/_health", httputil.ServeHealth())
@@ -22,18 +22,21 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth/crypt"
 	authmodel "github.com/treeverse/lakefs/pkg/auth/model"
 	authparams "github.com/treeverse/lakefs/pkg/auth/params"
+	"github.com/treeverse/lakefs/pkg/auth/provisioning"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/cache"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/graveler/settings"
 	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/jobs"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
 	"github.com/treeverse/lakefs/pkg/kv/kvtest"
 	"github.com/treeverse/lakefs/pkg/kv/mem"
 	"github.com/treeverse/lakefs/pkg/logging"
 	tablediff "github.com/treeverse/lakefs/pkg/plugins/diff"
+	"github.com/treeverse/lakefs/pkg/search"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
@@ -49,6 +52,7 @@ type dependencies struct {
 	catalog     *catalog.Catalog
 	authService auth.Service
 	collector   *memCollector
+	jobsRunner  *jobs.Runner
 	server      *httptest.Server
 }
 
@@ -128,6 +132,12 @@ func createUserWithDefaultGroup(t testing.TB, clt apigen.ClientWithResponsesInte
 }
 
 func setupHandlerWithWalkerFactory(t testing.TB, factory catalog.WalkerFactory) (http.Handler, *dependencies) {
+	t.Helper()
+	return setupHandlerWithAuth(t, factory, nil)
+}
+
+// setupHandlerWithAuth sets up a handler whose auth service is wrapped by wrapAuth, when set
+func setupHandlerWithAuth(t testing.TB, factory catalog.WalkerFactory, wrapAuth func(auth.Service) auth.Service) (http.Handler, *dependencies) {
 	t.Helper()
 	ctx := context.Background()
 
@@ -145,9 +155,17 @@ func setupHandlerWithWalkerFactory(t testing.TB, factory catalog.WalkerFactory)
 	kvStore := kvtest.GetStore(ctx, t)
 	actionsStore := actions.NewActionsKVStore(kvStore)
 	idGen := &actions.DecreasingIDGenerator{}
-	authService := auth.NewAuthService(kvStore, crypt.NewSecretStore([]byte("some secret")), authparams.ServiceCache{
+	var authService auth.Service = auth.NewAuthService(kvStore, crypt.NewSecretStore([]byte("some secret")), authparams.ServiceCache{
 		Enabled: false,
 	}, logging.ContextUnavailable())
+	if cfg.Auth.ProvisioningWebhook.Enabled {
+		provisioningHook, err := provisioning.NewHook(provisioning.HookConfig(cfg.Auth.ProvisioningWebhook), logging.ContextUnavailable())
+		testutil.MustDo(t, "provisioning webhook", err)
+		authService = provisioning.NewService(authService, provisioningHook)
+	}
+	if wrapAuth != nil {
+		authService = wrapAuth(authService)
+	}
 	meta := auth.NewKVMetadataManager("serve_test", cfg.Installation.FixedID, cfg.Database.Type, kvStore)
 
 	// Do not validate invalid config (missing required fields).
@@ -174,7 +192,9 @@ func setupHandlerWithWalkerFactory(t testing.TB, factory catalog.WalkerFactory)
 		"",
 	)
 
-	c.SetHooksHandler(actionsService)
+	searchService, err := search.NewService(cfg.Search, actionsService, kvStore, c, logging.ContextUnavailable())
+	testutil.MustDo(t, "search service", err)
+	c.SetHooksHandler(searchService)
 
 	authenticator := auth.NewBuiltinAuthenticator(authService)
 	kvParams, err := kvparams.NewConfig(cfg)
@@ -182,6 +202,7 @@ func setupHandlerWithWalkerFactory(t testing.TB, factory catalog.WalkerFactory)
 	migrator := kv.NewDatabaseMigrator(kvParams)
 
 	t.Cleanup(func() {
+		searchService.Stop()
 		actionsService.Stop()
 		_ = c.Close()
 	})
@@ -191,13 +212,19 @@ func setupHandlerWithWalkerFactory(t testing.TB, factory catalog.WalkerFactory)
 	otfDiffService := tablediff.NewMockService()
 
 	testutil.Must(t, err)
-	handler := api.Serve(cfg, c, authenticator, authService, c.BlockAdapter, meta, migrator, collector, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, otfDiffService, stats.DefaultUsageReporter)
+	lockout, err := auth.NewLockout(authparams.Lockout(cfg.Auth.Lockout))
+	testutil.Must(t, err)
+	usage := auth.NewCredentialsUsage(authparams.Usage(cfg.Auth.Usage))
+	jobsRunner := jobs.NewRunner(kvStore, cfg.Jobs, logging.ContextUnavailable())
+	t.Cleanup(jobsRunner.Stop)
+	handler := api.Serve(cfg, c, authenticator, authService, lockout, usage, c.BlockAdapter, meta, migrator, collector, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, otfDiffService, stats.DefaultUsageReporter, jobsRunner, searchService)
 
 	return handler, &dependencies{
 		blocks:      c.BlockAdapter,
 		authService: authService,
 		catalog:     c,
 		collector:   collector,
+		jobsRunner:  jobsRunner,
 	}
 }
 
@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth/keys"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	// AccessTokenPrefix starts every personal access token, telling it apart from login tokens
+	AccessTokenPrefix = "lakefs_pat_"
+
+	accessTokenIDBytes     = 8
+	accessTokenSecretBytes = 24
+
+	// accessTokenLastUsedResolution limits how often using a token updates its last used time
+	accessTokenLastUsedResolution = time.Minute
+)
+
+// AccessTokenManager manages scoped personal access tokens of users.  Unlike access keys, a
+// token only allows the actions and repositories in its scope, and expires.
+type AccessTokenManager interface {
+	// CreateAccessToken creates a token for username and returns it with the token string,
+	// which is not stored and cannot be retrieved later.
+	CreateAccessToken(ctx context.Context, username, description string, actions, repositories []string, expiresAt time.Time) (*model.AccessToken, string, error)
+	ListAccessTokens(ctx context.Context, username string, params *model.PaginationParams) ([]*model.AccessToken, *model.Paginator, error)
+	DeleteAccessToken(ctx context.Context, username, tokenID string) error
+	// AuthenticateAccessToken returns the unexpired token matching the token string and
+	// updates its last used time.
+	AuthenticateAccessToken(ctx context.Context, token string) (*model.AccessToken, error)
+}
+
+// IsAccessToken returns true if token is a personal access token
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, AccessTokenPrefix)
+}
+
+// AccessTokenAllows returns true if the scope of token includes the required permissions
+func AccessTokenAllows(ctx context.Context, token *model.AccessToken, node permissions.Node) bool {
+	return checkPermissions(ctx, node, token.Username, []*model.Policy{accessTokenScopePolicy(token)}) == CheckAllow
+}
+
+// AccessTokenScopeIncludes returns true if the scope of token includes every action and repository
+// of a token scope, and so a token of that scope grants no more than token.  No repositories
+// scope all repositories.
+func AccessTokenScopeIncludes(token *model.AccessToken, actions, repositories []string) bool {
+	for _, action := range actions {
+		if !scopeIncludes(token.Actions, action) {
+			return false
+		}
+	}
+	if len(token.Repositories) == 0 {
+		return true
+	}
+	if len(repositories) == 0 {
+		return false
+	}
+	for _, repo := range repositories {
+		if !scopeIncludes(token.Repositories, repo) {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeIncludes returns true if one of patterns matches everything that pattern matches: an
+// identical pattern, a pattern matching it when it has no wildcards, or a trailing '*' pattern
+// whose prefix it starts with.
+func scopeIncludes(patterns []string, pattern string) bool {
+	hasWildcards := strings.ContainsAny(pattern, "*?")
+	for _, p := range patterns {
+		if p == pattern || (!hasWildcards && wildcard.Match(p, pattern)) {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && !strings.ContainsAny(prefix, "*?") && strings.HasPrefix(pattern, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessTokenScopePolicy returns a policy allowing the actions of token on its repositories
+func accessTokenScopePolicy(token *model.AccessToken) *model.Policy {
+	if len(token.Repositories) == 0 {
+		return &model.Policy{
+			Statement: model.Statements{
+				{Effect: model.StatementEffectAllow, Action: token.Actions, Resource: "*"},
+			},
+		}
+	}
+	statements := make(model.Statements, 0, 2*len(token.Repositories))
+	for _, repo := range token.Repositories {
+		statements = append(statements,
+			model.Statement{Effect: model.StatementEffectAllow, Action: token.Actions, Resource: permissions.RepoArn(repo)},
+			model.Statement{Effect: model.StatementEffectAllow, Action: token.Actions, Resource: permissions.RepoArn(repo) + "/*"},
+		)
+	}
+	return &model.Policy{Statement: statements}
+}
+
+func hashAccessTokenSecret(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+func (s *AuthService) CreateAccessToken(ctx context.Context, username, description string, actions, repositories []string, expiresAt time.Time) (*model.AccessToken, string, error) {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return nil, "", err
+	}
+	if len(actions) == 0 {
+		return nil, "", fmt.Errorf("access token without actions: %w", model.ErrValidationError)
+	}
+	for _, action := range actions {
+		if err := model.ValidateActionName(action); err != nil {
+			return nil, "", err
+		}
+	}
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("access token expires in the past: %w", model.ErrValidationError)
+	}
+
+	tokenID := strings.ToLower(keys.HexStringGenerator(accessTokenIDBytes))
+	secret := strings.ToLower(keys.HexStringGenerator(accessTokenSecretBytes))
+	token := &model.AccessToken{
+		ID:           tokenID,
+		Username:     username,
+		Description:  description,
+		SecretHash:   hashAccessTokenSecret(secret),
+		Actions:      actions,
+		Repositories: repositories,
+		CreatedAt:    now,
+		ExpiresAt:    expiresAt,
+	}
+	tokenPath := model.AccessTokenPath(tokenID)
+	err := kv.SetMsgIf(ctx, s.store, model.PartitionKey, tokenPath, model.ProtoFromAccessToken(token), nil)
+	if err != nil {
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			err = ErrAlreadyExists
+		}
+		return nil, "", fmt.Errorf("save access token (tokenKey %s): %w", tokenPath, err)
+	}
+	userTokenPath := model.UserAccessTokenPath(username, tokenID)
+	err = kv.SetMsg(ctx, s.store, model.PartitionKey, userTokenPath, &kv.SecondaryIndex{PrimaryKey: tokenPath})
+	if err != nil {
+		return nil, "", fmt.Errorf("save user access token (key %s): %w", userTokenPath, err)
+	}
+	return token, AccessTokenPrefix + tokenID + "_" + secret, nil
+}
+
+func (s *AuthService) ListAccessTokens(ctx context.Context, username string, params *model.PaginationParams) ([]*model.AccessToken, *model.Paginator, error) {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return nil, nil, err
+	}
+	var token model.AccessTokenData
+	msgs, paginator, err := s.ListKVPaged(ctx, (&token).ProtoReflect().Type(), params, model.UserAccessTokenPath(username, params.Prefix), true)
+	if msgs == nil {
+		return nil, paginator, err
+	}
+	return model.ConvertAccessTokenDataList(msgs), paginator, err
+}
+
+func (s *AuthService) DeleteAccessToken(ctx context.Context, username, tokenID string) error {
+	token, err := s.getAccessToken(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if token.Username != username {
+		return fmt.Errorf("access token %s: %w", tokenID, ErrNotFound)
+	}
+	return s.deleteAccessTokenNoValidation(ctx, username, tokenID)
+}
+
+func (s *AuthService) deleteAccessTokenNoValidation(ctx context.Context, username, tokenID string) error {
+	userTokenPath := model.UserAccessTokenPath(username, tokenID)
+	if err := s.store.Delete(ctx, []byte(model.PartitionKey), userTokenPath); err != nil {
+		return fmt.Errorf("delete user access token (key %s): %w", userTokenPath, err)
+	}
+	tokenPath := model.AccessTokenPath(tokenID)
+	if err := s.store.Delete(ctx, []byte(model.PartitionKey), tokenPath); err != nil {
+		return fmt.Errorf("delete access token (tokenKey %s): %w", tokenPath, err)
+	}
+	return nil
+}
+
+// deleteUserAccessTokens deletes all access tokens of username
+func (s *AuthService) deleteUserAccessTokens(ctx context.Context, username string) error {
+	it, err := kv.NewSecondaryIterator(ctx, s.store, (&model.AccessTokenData{}).ProtoReflect().Type(), model.PartitionKey, model.UserAccessTokenPath(username, ""), []byte(""))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		token := it.Entry().Value.(*model.AccessTokenData)
+		if err := s.deleteAccessTokenNoValidation(ctx, username, token.TokenId); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (s *AuthService) getAccessToken(ctx context.Context, tokenID string) (*model.AccessToken, error) {
+	tokenPath := model.AccessTokenPath(tokenID)
+	m := model.AccessTokenData{}
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, tokenPath, &m)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			err = ErrNotFound
+		}
+		return nil, fmt.Errorf("access token %s: %w", tokenID, err)
+	}
+	return model.AccessTokenFromProto(&m), nil
+}
+
+func (s *AuthService) AuthenticateAccessToken(ctx context.Context, token string) (*model.AccessToken, error) {
+	tokenID, secret, found := strings.Cut(strings.TrimPrefix(token, AccessTokenPrefix), "_")
+	if !IsAccessToken(token) || !found {
+		return nil, ErrInvalidToken
+	}
+	tokenPath := model.AccessTokenPath(tokenID)
+	m := model.AccessTokenData{}
+	pred, err := kv.GetMsg(ctx, s.store, model.PartitionKey, tokenPath, &m)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("access token %s: %w", tokenID, err)
+	}
+	accessToken := model.AccessTokenFromProto(&m)
+	if subtle.ConstantTimeCompare(accessToken.SecretHash, hashAccessTokenSecret(secret)) != 1 {
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	if now.After(accessToken.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	if now.Sub(accessToken.LastUsedAt) >= accessTokenLastUsedResolution {
+		accessToken.LastUsedAt = now
+		// a concurrent request already updated the token, or deleted it
+		err := kv.SetMsgIf(ctx, s.store, model.PartitionKey, tokenPath, model.ProtoFromAccessToken(accessToken), pred)
+		if err != nil && !errors.Is(err, kv.ErrPredicateFailed) {
+			return nil, fmt.Errorf("update access token last used: %w", err)
+		}
+	}
+	return accessToken, nil
+}
@@ -30,7 +30,9 @@ var statementByName = map[string]model.Statement{
 			"fs:Read*",
 			"fs:List*",
 			permissions.WriteObjectAction,
+			permissions.TagObjectAction,
 			permissions.DeleteObjectAction,
+			permissions.RestoreObjectsAction,
 			permissions.RevertBranchAction,
 			permissions.CreateBranchAction,
 			permissions.CreateTagAction,
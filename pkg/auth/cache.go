@@ -22,12 +22,17 @@ type Cache interface {
 	GetCredential(accessKeyID string, setFn CredentialSetFn) (*model.Credential, error)
 	GetUser(key userKey, setFn UserSetFn) (*model.User, error)
 	GetUserPolicies(userID string, setFn UserPoliciesSetFn) ([]*model.Policy, error)
+	GetUserPermissionBoundaries(userID string, setFn UserPoliciesSetFn) ([]*model.Policy, error)
+	// InvalidatePermissionBoundaries drops the cached boundaries of all users, following a change to boundaries,
+	// to their policies or to group memberships
+	InvalidatePermissionBoundaries()
 }
 
 type LRUCache struct {
 	credentialsCache cache.Cache
 	userCache        cache.Cache
 	policyCache      cache.Cache
+	boundaryCache    *cache.GetSetCache
 }
 
 func NewLRUCache(size int, expiry, jitter time.Duration) *LRUCache {
@@ -36,6 +41,7 @@ func NewLRUCache(size int, expiry, jitter time.Duration) *LRUCache {
 		credentialsCache: cache.NewCache(size, expiry, jitterFn),
 		userCache:        cache.NewCache(size, expiry, jitterFn),
 		policyCache:      cache.NewCache(size, expiry, jitterFn),
+		boundaryCache:    cache.NewCache(size, expiry, jitterFn),
 	}
 }
 
@@ -63,6 +69,18 @@ func (c *LRUCache) GetUserPolicies(userID string, setFn UserPoliciesSetFn) ([]*m
 	return v.([]*model.Policy), nil
 }
 
+func (c *LRUCache) GetUserPermissionBoundaries(userID string, setFn UserPoliciesSetFn) ([]*model.Policy, error) {
+	v, err := c.boundaryCache.GetOrSet(userID, func() (interface{}, error) { return setFn() })
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*model.Policy), nil
+}
+
+func (c *LRUCache) InvalidatePermissionBoundaries() {
+	c.boundaryCache.Purge()
+}
+
 // DummyCache dummy cache that doesn't cache
 type DummyCache struct{}
 
@@ -77,3 +95,9 @@ func (d *DummyCache) GetUser(_ userKey, setFn UserSetFn) (*model.User, error) {
 func (d *DummyCache) GetUserPolicies(_ string, setFn UserPoliciesSetFn) ([]*model.Policy, error) {
 	return setFn()
 }
+
+func (d *DummyCache) GetUserPermissionBoundaries(_ string, setFn UserPoliciesSetFn) ([]*model.Policy, error) {
+	return setFn()
+}
+
+func (d *DummyCache) InvalidatePermissionBoundaries() {}
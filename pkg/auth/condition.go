@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+)
+
+// conditionMatch returns true if the condition values returned by getValues match condition. Condition keys missing
+// from the values match only negated operators.
+func conditionMatch(condition model.Condition, getValues func() (map[string]string, error)) (bool, error) {
+	var values map[string]string
+	if getValues != nil {
+		var err error
+		values, err = getValues()
+		if err != nil {
+			return false, err
+		}
+	}
+	for operator, keys := range condition {
+		for key, patterns := range keys {
+			value, ok := values[key]
+			if !conditionKeyMatch(operator, value, ok, patterns) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func conditionKeyMatch(operator, value string, ok bool, patterns []string) bool {
+	like := func(pattern string) bool { return wildcard.Match(pattern, value) }
+	switch operator {
+	case model.ConditionStringEquals:
+		return ok && slices.Contains(patterns, value)
+	case model.ConditionStringNotEquals:
+		return !ok || !slices.Contains(patterns, value)
+	case model.ConditionStringLike:
+		return ok && slices.ContainsFunc(patterns, like)
+	case model.ConditionStringNotLike:
+		return !ok || !slices.ContainsFunc(patterns, like)
+	default:
+		// unknown operators are rejected when writing policies
+		return false
+	}
+}
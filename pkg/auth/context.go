@@ -9,7 +9,8 @@ import (
 type contextKey string
 
 const (
-	userContextKey contextKey = "user"
+	userContextKey        contextKey = "user"
+	accessTokenContextKey contextKey = "access_token"
 )
 
 func GetUser(ctx context.Context) (*model.User, error) {
@@ -23,3 +24,13 @@ func GetUser(ctx context.Context) (*model.User, error) {
 func WithUser(ctx context.Context, user *model.User) context.Context {
 	return context.WithValue(ctx, userContextKey, user)
 }
+
+// GetAccessToken returns the access token that authenticated the request, if any
+func GetAccessToken(ctx context.Context) (*model.AccessToken, bool) {
+	token, ok := ctx.Value(accessTokenContextKey).(*model.AccessToken)
+	return token, ok
+}
+
+func WithAccessToken(ctx context.Context, token *model.AccessToken) context.Context {
+	return context.WithValue(ctx, accessTokenContextKey, token)
+}
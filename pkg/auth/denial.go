@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// DenialReason is the reason code of an authorization denial
+type DenialReason string
+
+const (
+	// DenialReasonExplicitDeny a statement of a policy denies the permission
+	DenialReasonExplicitDeny DenialReason = "explicit_deny"
+	// DenialReasonNoAllow no statement of the policies of the user allows the permission
+	DenialReasonNoAllow DenialReason = "no_allow"
+	// DenialReasonConditionError the condition of a statement could not be evaluated, denying the permission
+	DenialReasonConditionError DenialReason = "condition_error"
+	// DenialReasonPermissionBoundary a permission boundary of the user does not allow the permission
+	DenialReasonPermissionBoundary DenialReason = "permission_boundary"
+)
+
+// NoStatement is the Statement of a Denial that no single statement caused
+const NoStatement = -1
+
+// Denial explains why an authorization request was denied: the permission denied, and the policy and statement
+// that caused it
+type Denial struct {
+	Reason   DenialReason
+	Action   string
+	Resource string
+	// Policy is the display name of the policy or the permission boundary that caused the denial, empty for
+	// DenialReasonNoAllow
+	Policy string
+	// Statement is the index of the statement of Policy that caused the denial, or NoStatement
+	Statement int
+}
+
+func (d *Denial) String() string {
+	s := fmt.Sprintf("%s: %s on %s", d.Reason, d.Action, d.Resource)
+	if d.Policy != "" {
+		s += " by policy " + d.Policy
+	}
+	if d.Statement != NoStatement {
+		s += fmt.Sprintf(" statement %d", d.Statement)
+	}
+	return s
+}
+
+var denialsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "auth_denials_total",
+		Help: "Authorization denials of requests, by action and reason",
+	},
+	[]string{"service", "action", "reason"})
+
+// RecordDenial audits the denial of a request of username to service, logging the policy and statement that caused
+// it.  denial may be nil if the authorization service did not explain it.
+func RecordDenial(ctx context.Context, service, username string, denial *Denial) {
+	log := logging.FromContext(ctx).WithFields(logging.Fields{
+		"service": service,
+		"user":    username,
+	})
+	if denial == nil {
+		denialsCounter.WithLabelValues(service, "", "").Inc()
+		log.Warn("Authorization denied")
+		return
+	}
+	denialsCounter.WithLabelValues(service, denial.Action, string(denial.Reason)).Inc()
+	log.WithFields(logging.Fields{
+		"action":    denial.Action,
+		"resource":  denial.Resource,
+		"reason":    denial.Reason,
+		"policy":    denial.Policy,
+		"statement": denial.Statement,
+	}).Warn("Authorization denied")
+}
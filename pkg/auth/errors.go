@@ -20,4 +20,8 @@ var (
 	ErrInvalidRequest          = errors.New("invalid request")
 	ErrUserNotFound            = errors.New("user not found")
 	ErrInvalidResponse         = errors.New("invalid response")
+	ErrProvisioningDenied      = errors.New("provisioning denied")
+	ErrQuotaExceeded           = errors.New("organization quota exceeded")
+	ErrOrganizationMismatch    = errors.New("user is not a member of the organization")
+	ErrNotImplemented          = errors.New("not implemented by the auth service")
 )
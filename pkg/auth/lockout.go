@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	lru "github.com/hnlq715/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/treeverse/lakefs/pkg/auth/params"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// lockoutSize bounds the number of access keys and source IPs tracked for failed authentications
+const lockoutSize = 100_000
+
+var ErrLockedOut = errors.New("too many failed authentications")
+
+var (
+	authFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_failures_total",
+		Help: "The total number of failed authentications with access keys.",
+	}, []string{"service"})
+
+	authLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_lockouts_total",
+		Help: "The total number of access keys and source IPs locked out after failed authentications.",
+	}, []string{"service", "kind"})
+)
+
+// Lockout tracks consecutive failed authentications per access key and per source IP, and
+// locks them out for a period that doubles with every further failure.  Failures are tracked
+// in-memory by each lakeFS instance, and forgotten after the maximal lockout duration passes
+// without failures.  A nil Lockout never locks out.
+type Lockout struct {
+	params         params.Lockout
+	trustedProxies []netip.Prefix
+
+	mu      sync.Mutex
+	entries *lru.Cache
+}
+
+type lockoutEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+func NewLockout(params params.Lockout) (*Lockout, error) {
+	if !params.Enabled {
+		return nil, nil
+	}
+	trustedProxies, err := httputil.ParseTrustedProxies(params.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	entries, _ := lru.New(lockoutSize)
+	return &Lockout{
+		params:         params,
+		trustedProxies: trustedProxies,
+		entries:        entries,
+	}, nil
+}
+
+// SourceIP returns the source IP of r tracked for its failed authentications
+func (l *Lockout) SourceIP(r *http.Request) string {
+	if l == nil {
+		return httputil.SourceHost(r)
+	}
+	return httputil.ClientIP(r, l.trustedProxies)
+}
+
+func accessKeyLockoutKey(accessKeyID string) string {
+	return "key:" + accessKeyID
+}
+
+func sourceIPLockoutKey(sourceIP string) string {
+	return "ip:" + sourceIP
+}
+
+// Check returns ErrLockedOut if accessKeyID or sourceIP is locked out
+func (l *Lockout) Check(accessKeyID, sourceIP string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for _, key := range []string{accessKeyLockoutKey(accessKeyID), sourceIPLockoutKey(sourceIP)} {
+		v, ok := l.entries.Get(key)
+		if !ok {
+			continue
+		}
+		if lockedUntil := v.(*lockoutEntry).lockedUntil; now.Before(lockedUntil) {
+			return fmt.Errorf("%w: locked until %s", ErrLockedOut, lockedUntil.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// RecordFailure records a failed authentication of accessKeyID from sourceIP by service, and
+// locks them out once they reach their maximal failures.
+func (l *Lockout) RecordFailure(ctx context.Context, service, accessKeyID, sourceIP string) {
+	if l == nil {
+		return
+	}
+	authFailures.WithLabelValues(service).Inc()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	keyLockedUntil := l.recordFailure(now, accessKeyLockoutKey(accessKeyID), l.params.MaxFailures)
+	ipLockedUntil := l.recordFailure(now, sourceIPLockoutKey(sourceIP), l.params.MaxSourceIPFailures)
+
+	log := logging.FromContext(ctx).WithFields(logging.Fields{
+		"service":       service,
+		"access_key_id": accessKeyID,
+		"source_ip":     sourceIP,
+	})
+	if !keyLockedUntil.IsZero() {
+		authLockouts.WithLabelValues(service, "access_key").Inc()
+		log = log.WithField("access_key_locked_until", keyLockedUntil)
+	}
+	if !ipLockedUntil.IsZero() {
+		authLockouts.WithLabelValues(service, "source_ip").Inc()
+		log = log.WithField("source_ip_locked_until", ipLockedUntil)
+	}
+	log.Warn("Failed authentication")
+}
+
+// recordFailure counts a failure of key and returns when its new lockout ends, or zero if the
+// failure did not lock it out.
+func (l *Lockout) recordFailure(now time.Time, key string, maxFailures int) time.Time {
+	entry := &lockoutEntry{}
+	if v, ok := l.entries.Get(key); ok {
+		entry = v.(*lockoutEntry)
+	}
+	if now.Sub(entry.lastFailure) > l.params.MaxDuration {
+		entry.failures = 0
+	}
+	entry.failures++
+	entry.lastFailure = now
+	l.entries.Add(key, entry)
+	if maxFailures <= 0 || entry.failures < maxFailures {
+		return time.Time{}
+	}
+	duration := l.params.Duration
+	for i := maxFailures; i < entry.failures && duration < l.params.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > l.params.MaxDuration {
+		duration = l.params.MaxDuration
+	}
+	entry.lockedUntil = now.Add(duration)
+	return entry.lockedUntil
+}
+
+// RecordSuccess forgets the failed authentications of accessKeyID.  Failures of its source IP
+// are kept, so that a client holding one valid access key cannot reset the failures of guessing
+// others; they are forgotten after the maximal lockout duration passes without failures.
+func (l *Lockout) RecordSuccess(accessKeyID string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries.Remove(accessKeyLockoutKey(accessKeyID))
+}
@@ -0,0 +1,127 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/params"
+)
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	lockout, err := auth.NewLockout(params.Lockout{
+		Enabled:             true,
+		MaxFailures:         3,
+		MaxSourceIPFailures: 5,
+		Duration:            time.Hour,
+		MaxDuration:         2 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewLockout: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		lockout.RecordFailure(ctx, "test", "key1", "10.0.0.1")
+	}
+	if err := lockout.Check("key1", "10.0.0.1"); err != nil {
+		t.Fatalf("Check after 2 failures: %s", err)
+	}
+	lockout.RecordFailure(ctx, "test", "key1", "10.0.0.1")
+	if err := lockout.Check("key1", "10.0.0.2"); !errors.Is(err, auth.ErrLockedOut) {
+		t.Fatalf("Check key after 3 failures got %v, expected %s", err, auth.ErrLockedOut)
+	}
+	if err := lockout.Check("key2", "10.0.0.1"); err != nil {
+		t.Fatalf("Check source IP after 3 failures: %s", err)
+	}
+
+	lockout.RecordFailure(ctx, "test", "key2", "10.0.0.1")
+	lockout.RecordFailure(ctx, "test", "key3", "10.0.0.1")
+	if err := lockout.Check("key4", "10.0.0.1"); !errors.Is(err, auth.ErrLockedOut) {
+		t.Fatalf("Check source IP after 5 failures got %v, expected %s", err, auth.ErrLockedOut)
+	}
+
+	// success forgets failures of the access key but not of its source IP
+	lockout.RecordSuccess("key1")
+	if err := lockout.Check("key1", "10.0.0.2"); err != nil {
+		t.Fatalf("Check after success: %s", err)
+	}
+	if err := lockout.Check("key1", "10.0.0.1"); !errors.Is(err, auth.ErrLockedOut) {
+		t.Fatalf("Check source IP after success got %v, expected %s", err, auth.ErrLockedOut)
+	}
+}
+
+func TestLockoutSuccessKeepsSourceIPFailures(t *testing.T) {
+	ctx := context.Background()
+	lockout, err := auth.NewLockout(params.Lockout{
+		Enabled:             true,
+		MaxFailures:         3,
+		MaxSourceIPFailures: 3,
+		Duration:            time.Hour,
+		MaxDuration:         time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewLockout: %s", err)
+	}
+	// a client holding a valid access key guesses other keys between its successful requests
+	for i := 0; i < 2; i++ {
+		lockout.RecordFailure(ctx, "test", "guessed", "10.0.0.1")
+		lockout.RecordSuccess("valid")
+	}
+	if err := lockout.Check("valid", "10.0.0.1"); err != nil {
+		t.Fatalf("Check after 2 failures: %s", err)
+	}
+	lockout.RecordFailure(ctx, "test", "guessed", "10.0.0.1")
+	lockout.RecordSuccess("valid")
+	if err := lockout.Check("valid", "10.0.0.1"); !errors.Is(err, auth.ErrLockedOut) {
+		t.Fatalf("Check source IP after 3 failures between successes got %v, expected %s", err, auth.ErrLockedOut)
+	}
+	if err := lockout.Check("valid", "10.0.0.2"); err != nil {
+		t.Fatalf("Check valid access key from another source IP: %s", err)
+	}
+}
+
+func TestLockoutSourceIP(t *testing.T) {
+	lockout, err := auth.NewLockout(params.Lockout{
+		Enabled:        true,
+		TrustedProxies: []string{"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatalf("NewLockout: %s", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = "10.0.0.5:4321"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.7")
+	if ip := lockout.SourceIP(r); ip != "198.51.100.7" {
+		t.Errorf("SourceIP behind trusted proxy = %s, expected 198.51.100.7", ip)
+	}
+	r.RemoteAddr = "203.0.113.9:4321"
+	if ip := lockout.SourceIP(r); ip != "203.0.113.9" {
+		t.Errorf("SourceIP of untrusted client = %s, expected 203.0.113.9", ip)
+	}
+
+	_, err = auth.NewLockout(params.Lockout{Enabled: true, TrustedProxies: []string{"not-an-ip"}})
+	if err == nil {
+		t.Error("NewLockout with invalid trusted proxy expected an error")
+	}
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	ctx := context.Background()
+	lockout, err := auth.NewLockout(params.Lockout{
+		MaxFailures: 1,
+		Duration:    time.Hour,
+		MaxDuration: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewLockout: %s", err)
+	}
+	lockout.RecordFailure(ctx, "test", "key1", "10.0.0.1")
+	if err := lockout.Check("key1", "10.0.0.1"); err != nil {
+		t.Fatalf("Check with disabled lockout: %s", err)
+	}
+}
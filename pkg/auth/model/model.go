@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-openapi/swag"
@@ -18,19 +20,29 @@ import (
 )
 
 const (
-	StatementEffectAllow   = "allow"
-	StatementEffectDeny    = "deny"
-	PartitionKey           = "auth"
-	groupsPrefix           = "groups"
-	groupsUsersPrefix      = "gUsers"
-	groupsPoliciesPrefix   = "gPolicies"
-	usersPrefix            = "users"
-	policiesPrefix         = "policies"
-	usersPoliciesPrefix    = "uPolicies"
-	usersCredentialsPrefix = "uCredentials" // #nosec G101 -- False positive: this is only a kv key prefix
-	credentialsPrefix      = "credentials"
-	expiredTokensPrefix    = "expiredTokens"
-	metadataPrefix         = "installation_metadata"
+	StatementEffectAllow      = "allow"
+	StatementEffectDeny       = "deny"
+	PartitionKey              = "auth"
+	groupsPrefix              = "groups"
+	groupsUsersPrefix         = "gUsers"
+	groupsPoliciesPrefix      = "gPolicies"
+	usersPrefix               = "users"
+	policiesPrefix            = "policies"
+	usersPoliciesPrefix       = "uPolicies"
+	usersBoundaryPrefix       = "uBoundary"
+	groupsBoundaryPrefix      = "gBoundary"
+	organizationsPrefix       = "organizations"
+	organizationsUsersPrefix  = "oUsers"
+	usersOrganizationPrefix   = "uOrganization"
+	usersCredentialsPrefix    = "uCredentials" // #nosec G101 -- False positive: this is only a kv key prefix
+	credentialsPrefix         = "credentials"
+	expiredTokensPrefix       = "expiredTokens"
+	accessTokensPrefix        = "accessTokens"
+	usersAccessTokensPrefix   = "uAccessTokens"
+	sessionsPrefix            = "sessions"
+	credentialsActivityPrefix = "credentialsActivity"
+	revokedPrefix             = "revoked"
+	metadataPrefix            = "installation_metadata"
 )
 
 //nolint:gochecknoinits
@@ -42,7 +54,17 @@ func init() {
 	kv.MustRegisterType("auth", kv.FormatPath("gUsers", "*", "users"), (&kv.SecondaryIndex{}).ProtoReflect().Type())
 	kv.MustRegisterType("auth", kv.FormatPath("gPolicies", "*", "policies"), (&kv.SecondaryIndex{}).ProtoReflect().Type())
 	kv.MustRegisterType("auth", kv.FormatPath("uPolicies", "*", "policies"), (&kv.SecondaryIndex{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "uBoundary", (&kv.SecondaryIndex{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "gBoundary", (&kv.SecondaryIndex{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "organizations", (&OrganizationData{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", kv.FormatPath("oUsers", "*", "users"), (&kv.SecondaryIndex{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "uOrganization", (&kv.SecondaryIndex{}).ProtoReflect().Type())
 	kv.MustRegisterType("auth", "expiredTokens", (&TokenData{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "accessTokens", (&AccessTokenData{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", kv.FormatPath("uAccessTokens", "*", "accessTokens"), (&kv.SecondaryIndex{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "sessions", (&SessionData{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "credentialsActivity", (&CredentialsActivityData{}).ProtoReflect().Type())
+	kv.MustRegisterType("auth", "revoked", (&RevocationData{}).ProtoReflect().Type())
 	kv.MustRegisterType("auth", "installation_metadata", nil)
 }
 
@@ -62,6 +84,11 @@ func CredentialPath(userName string, accessKeyID string) []byte {
 	return []byte(kv.FormatPath(usersCredentialsPrefix, userName, credentialsPrefix, accessKeyID))
 }
 
+// AllCredentialsPath is the prefix of the credentials of all users
+func AllCredentialsPath() []byte {
+	return []byte(usersCredentialsPrefix + kv.PathDelimiter)
+}
+
 func GroupUserPath(groupDisplayName string, userName string) []byte {
 	return []byte(kv.FormatPath(groupsUsersPrefix, groupDisplayName, usersPrefix, userName))
 }
@@ -74,6 +101,30 @@ func GroupPolicyPath(groupDisplayName string, policyDisplayName string) []byte {
 	return []byte(kv.FormatPath(groupsPoliciesPrefix, groupDisplayName, policiesPrefix, policyDisplayName))
 }
 
+// UserBoundaryPath is the key of the permission boundary policy of a user
+func UserBoundaryPath(userName string) []byte {
+	return []byte(kv.FormatPath(usersBoundaryPrefix, userName))
+}
+
+// GroupBoundaryPath is the key of the permission boundary policy of a group
+func GroupBoundaryPath(groupDisplayName string) []byte {
+	return []byte(kv.FormatPath(groupsBoundaryPrefix, groupDisplayName))
+}
+
+func OrganizationPath(organizationID string) []byte {
+	return []byte(kv.FormatPath(organizationsPrefix, organizationID))
+}
+
+// OrganizationUserPath is the key of the membership of a user in an organization
+func OrganizationUserPath(organizationID string, userName string) []byte {
+	return []byte(kv.FormatPath(organizationsUsersPrefix, organizationID, usersPrefix, userName))
+}
+
+// UserOrganizationPath is the key of the organization a user is a member of
+func UserOrganizationPath(userName string) []byte {
+	return []byte(kv.FormatPath(usersOrganizationPrefix, userName))
+}
+
 func ExpiredTokenPath(tokenID string) []byte {
 	return []byte(kv.FormatPath(expiredTokensPrefix, tokenID))
 }
@@ -82,6 +133,35 @@ func ExpiredTokensPath() []byte {
 	return ExpiredTokenPath("")
 }
 
+func AccessTokenPath(tokenID string) []byte {
+	return []byte(kv.FormatPath(accessTokensPrefix, tokenID))
+}
+
+func UserAccessTokenPath(userName string, tokenID string) []byte {
+	return []byte(kv.FormatPath(usersAccessTokensPrefix, userName, accessTokensPrefix, tokenID))
+}
+
+func SessionPath(sessionID string) []byte {
+	return []byte(kv.FormatPath(sessionsPrefix, sessionID))
+}
+
+func CredentialsActivityPath(accessKeyID string) []byte {
+	return []byte(kv.FormatPath(credentialsActivityPrefix, accessKeyID))
+}
+
+// RevokedPrefixPath is the prefix of all revocations of credentials and sessions
+func RevokedPrefixPath() []byte {
+	return []byte(revokedPrefix + kv.PathDelimiter)
+}
+
+func RevokedCredentialsPath(accessKeyID string) []byte {
+	return []byte(kv.FormatPath(revokedPrefix, credentialsPrefix, accessKeyID))
+}
+
+func RevokedSessionPath(sessionID string) []byte {
+	return []byte(kv.FormatPath(revokedPrefix, sessionsPrefix, sessionID))
+}
+
 func MetadataKeyPath(key string) string {
 	return kv.FormatPath(metadataPrefix, key)
 }
@@ -150,6 +230,36 @@ type DBGroup struct {
 	Group
 }
 
+// Organization is a tenant of lakeFS. Its repositories, users, groups and policies are named with its ID followed by
+// OrganizationNameSeparator, and its members can only access them.
+type Organization struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"creation_date"`
+	Description string    `json:"description,omitempty"`
+	// StorageNamespace is the prefix of the storage namespaces of repositories members may create, none if empty
+	StorageNamespace string `json:"storage_namespace,omitempty"`
+	// MaxRepositories is the number of repositories of the organization, unlimited if 0
+	MaxRepositories int `json:"max_repositories,omitempty"`
+}
+
+// OrganizationNameSeparator separates the ID of an organization from the rest of the names of its entities
+const OrganizationNameSeparator = "-"
+
+// OrganizationNamePrefix returns the prefix of the names of the entities of the organization
+func OrganizationNamePrefix(organizationID string) string {
+	return organizationID + OrganizationNameSeparator
+}
+
+// OrganizationIDOfName returns the ID of the organization whose entities are named like name, empty if name does not
+// belong to an organization namespace
+func OrganizationIDOfName(name string) string {
+	id, _, found := strings.Cut(name, OrganizationNameSeparator)
+	if !found || ValidateOrganizationID(id) != nil {
+		return ""
+	}
+	return id
+}
+
 type ACLPermission string
 
 type ACL struct {
@@ -172,8 +282,23 @@ type Statement struct {
 	Effect   string   `json:"Effect"`
 	Action   []string `json:"Action"`
 	Resource string   `json:"Resource"`
+	// Condition limits the requests the statement applies to, nil to apply to all requests
+	Condition Condition `json:"Condition,omitempty"`
 }
 
+// Condition operators, matching the values of condition keys
+const (
+	ConditionStringEquals    = "StringEquals"
+	ConditionStringNotEquals = "StringNotEquals"
+	ConditionStringLike      = "StringLike"
+	ConditionStringNotLike   = "StringNotLike"
+)
+
+// Condition maps condition operators to the condition keys they check, and each key to the values it matches. A
+// statement applies to a request if all its operators match, an operator matches if all its keys match, and a key
+// matches if one of its values does.
+type Condition map[string]map[string][]string
+
 type Statements []Statement
 
 type BaseCredential struct {
@@ -193,6 +318,40 @@ type DBCredential struct {
 	BaseCredential
 }
 
+// AccessToken is a personal access token used by automation to authenticate API requests as
+// a user.  Requests using the token are limited to its actions and repositories.
+type AccessToken struct {
+	ID           string
+	Username     string
+	Description  string
+	SecretHash   []byte `json:"-"`
+	Actions      []string
+	Repositories []string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	LastUsedAt   time.Time
+}
+
+// Session is a login session of a user, identified by the ID of its login token
+type Session struct {
+	ID         string
+	Username   string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	SourceIP   string
+}
+
+// CredentialsActivity is the last use of access key credentials.  LastUsedAt is zero if the
+// credentials were not used since they were issued.
+type CredentialsActivity struct {
+	AccessKeyID string
+	Username    string
+	IssuedDate  time.Time
+	LastUsedAt  time.Time
+	SourceIP    string
+}
+
 // CredentialKeys - For JSON serialization:
 type CredentialKeys struct {
 	AccessKeyID     string `json:"access_key_id"`
@@ -275,6 +434,26 @@ func ProtoFromGroup(g *Group) *GroupData {
 	}
 }
 
+func OrganizationFromProto(pb *OrganizationData) *Organization {
+	return &Organization{
+		ID:               pb.Id,
+		CreatedAt:        pb.CreatedAt.AsTime(),
+		Description:      pb.Description,
+		StorageNamespace: pb.StorageNamespace,
+		MaxRepositories:  int(pb.MaxRepositories),
+	}
+}
+
+func ProtoFromOrganization(o *Organization) *OrganizationData {
+	return &OrganizationData{
+		Id:               o.ID,
+		CreatedAt:        timestamppb.New(o.CreatedAt),
+		Description:      o.Description,
+		StorageNamespace: o.StorageNamespace,
+		MaxRepositories:  int32(o.MaxRepositories),
+	}
+}
+
 func PolicyFromProto(pb *PolicyData) *Policy {
 	policy := &Policy{
 		CreatedAt:   pb.CreatedAt.AsTime(),
@@ -325,20 +504,108 @@ func ProtoFromCredential(c *Credential) *CredentialData {
 	}
 }
 
+func AccessTokenFromProto(pb *AccessTokenData) *AccessToken {
+	t := &AccessToken{
+		ID:           pb.TokenId,
+		Username:     pb.Username,
+		Description:  pb.Description,
+		SecretHash:   pb.SecretHash,
+		Actions:      pb.Actions,
+		Repositories: pb.Repositories,
+		CreatedAt:    pb.CreatedAt.AsTime(),
+		ExpiresAt:    pb.ExpiresAt.AsTime(),
+	}
+	if pb.LastUsedAt != nil {
+		t.LastUsedAt = pb.LastUsedAt.AsTime()
+	}
+	return t
+}
+
+func ProtoFromAccessToken(t *AccessToken) *AccessTokenData {
+	pb := &AccessTokenData{
+		TokenId:      t.ID,
+		Username:     t.Username,
+		Description:  t.Description,
+		SecretHash:   t.SecretHash,
+		Actions:      t.Actions,
+		Repositories: t.Repositories,
+		CreatedAt:    timestamppb.New(t.CreatedAt),
+		ExpiresAt:    timestamppb.New(t.ExpiresAt),
+	}
+	if !t.LastUsedAt.IsZero() {
+		pb.LastUsedAt = timestamppb.New(t.LastUsedAt)
+	}
+	return pb
+}
+
+func SessionFromProto(pb *SessionData) *Session {
+	return &Session{
+		ID:         pb.SessionId,
+		Username:   pb.Username,
+		CreatedAt:  pb.CreatedAt.AsTime(),
+		ExpiresAt:  pb.ExpiresAt.AsTime(),
+		LastUsedAt: pb.LastUsedAt.AsTime(),
+		SourceIP:   pb.SourceIp,
+	}
+}
+
+func ProtoFromSession(s *Session) *SessionData {
+	return &SessionData{
+		SessionId:  s.ID,
+		Username:   s.Username,
+		CreatedAt:  timestamppb.New(s.CreatedAt),
+		ExpiresAt:  timestamppb.New(s.ExpiresAt),
+		LastUsedAt: timestamppb.New(s.LastUsedAt),
+		SourceIp:   s.SourceIP,
+	}
+}
+
 func statementFromProto(pb *StatementData) *Statement {
+	var condition Condition
+	for _, c := range pb.Condition {
+		if condition == nil {
+			condition = make(Condition)
+		}
+		if condition[c.Operator] == nil {
+			condition[c.Operator] = make(map[string][]string)
+		}
+		condition[c.Operator][c.Key] = c.Values
+	}
 	return &Statement{
-		Effect:   pb.Effect,
-		Action:   pb.Action,
-		Resource: pb.Resource,
+		Effect:    pb.Effect,
+		Action:    pb.Action,
+		Resource:  pb.Resource,
+		Condition: condition,
 	}
 }
 
 func protoFromStatement(s *Statement) *StatementData {
+	var condition []*StatementConditionData
+	for _, operator := range sortedKeys(s.Condition) {
+		keys := s.Condition[operator]
+		for _, key := range sortedKeys(keys) {
+			condition = append(condition, &StatementConditionData{
+				Operator: operator,
+				Key:      key,
+				Values:   keys[key],
+			})
+		}
+	}
 	return &StatementData{
-		Effect:   s.Effect,
-		Action:   s.Action,
-		Resource: s.Resource,
+		Effect:    s.Effect,
+		Action:    s.Action,
+		Resource:  s.Resource,
+		Condition: condition,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
 }
 
 func statementsFromProto(pb []*StatementData) *Statements {
@@ -376,6 +643,14 @@ func ConvertGroupDataList(group []proto.Message) []*Group {
 	return res
 }
 
+func ConvertOrganizationDataList(organizations []proto.Message) []*Organization {
+	res := make([]*Organization, 0, len(organizations))
+	for _, o := range organizations {
+		res = append(res, OrganizationFromProto(o.(*OrganizationData)))
+	}
+	return res
+}
+
 func ConvertPolicyDataList(policies []proto.Message) []*Policy {
 	res := make([]*Policy, 0, len(policies))
 	for _, p := range policies {
@@ -399,6 +674,14 @@ func ConvertCredDataList(s crypt.SecretStore, creds []proto.Message) ([]*Credent
 	return res, nil
 }
 
+func ConvertAccessTokenDataList(tokens []proto.Message) []*AccessToken {
+	res := make([]*AccessToken, 0, len(tokens))
+	for _, t := range tokens {
+		res = append(res, AccessTokenFromProto(t.(*AccessTokenData)))
+	}
+	return res
+}
+
 func DecryptSecret(s crypt.SecretStore, value []byte) (string, error) {
 	decrypted, err := s.Decrypt(value)
 	if err != nil {
@@ -3,6 +3,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
@@ -22,6 +23,17 @@ func ValidateAuthEntityID(name string) error {
 	return nil
 }
 
+// organizationIDRegexp matches organization IDs, which prefix repository names so must be valid in them, and cannot
+// contain OrganizationNameSeparator
+var organizationIDRegexp = regexp.MustCompile(`^[a-z0-9]{2,20}$`)
+
+func ValidateOrganizationID(id string) error {
+	if !organizationIDRegexp.MatchString(id) {
+		return fmt.Errorf("%w: organization ID '%s' must be 2 to 20 lowercase letters and digits", ErrValidationError, id)
+	}
+	return nil
+}
+
 func ValidateActionName(name string) error {
 	return permissions.IsValidAction(name)
 }
@@ -39,3 +51,22 @@ func ValidateStatementEffect(effect string) error {
 	}
 	return nil
 }
+
+func ValidateStatementCondition(condition Condition) error {
+	for operator, keys := range condition {
+		switch operator {
+		case ConditionStringEquals, ConditionStringNotEquals, ConditionStringLike, ConditionStringNotLike:
+		default:
+			return fmt.Errorf("%w: condition operator '%s'", ErrValidationError, operator)
+		}
+		for key, values := range keys {
+			if !strings.HasPrefix(key, permissions.ObjectTagConditionKeyPrefix) || len(key) == len(permissions.ObjectTagConditionKeyPrefix) {
+				return fmt.Errorf("%w: condition key '%s'", ErrValidationError, key)
+			}
+			if len(values) == 0 {
+				return fmt.Errorf("%w: condition key '%s' has no values", ErrValidationError, key)
+			}
+		}
+	}
+	return nil
+}
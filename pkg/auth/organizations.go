@@ -0,0 +1,314 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// OrganizationManager manages organizations, the tenants of a lakeFS installation.  The
+// repositories, users, groups and policies of an organization are named with its ID followed by
+// model.OrganizationNameSeparator.  Members of an organization, users added to it and users named
+// in its namespace, can only access the entities of their organization, whatever their policies
+// allow.  A user is a member of at most one organization.
+type OrganizationManager interface {
+	CreateOrganization(ctx context.Context, organization *model.Organization) (*model.Organization, error)
+	GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error)
+	ListOrganizations(ctx context.Context, params *model.PaginationParams) ([]*model.Organization, *model.Paginator, error)
+	UpdateOrganization(ctx context.Context, organization *model.Organization) (*model.Organization, error)
+	DeleteOrganization(ctx context.Context, organizationID string) error
+
+	AddOrganizationMember(ctx context.Context, organizationID, username string) error
+	RemoveOrganizationMember(ctx context.Context, organizationID, username string) error
+	ListOrganizationMembers(ctx context.Context, organizationID string, params *model.PaginationParams) ([]*model.User, *model.Paginator, error)
+	// GetUserOrganization returns the organization username is a member of, ErrNotFound if it
+	// is not a member of any organization
+	GetUserOrganization(ctx context.Context, username string) (*model.Organization, error)
+}
+
+func (s *AuthService) CreateOrganization(ctx context.Context, organization *model.Organization) (*model.Organization, error) {
+	if err := model.ValidateOrganizationID(organization.ID); err != nil {
+		return nil, err
+	}
+	if organization.MaxRepositories < 0 {
+		return nil, fmt.Errorf("%w: max repositories must not be negative", model.ErrValidationError)
+	}
+	if organization.CreatedAt.IsZero() {
+		organization.CreatedAt = time.Now().UTC()
+	}
+	organizationKey := model.OrganizationPath(organization.ID)
+	err := kv.SetMsgIf(ctx, s.store, model.PartitionKey, organizationKey, model.ProtoFromOrganization(organization), nil)
+	if err != nil {
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			err = ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("save organization (organizationKey %s): %w", organizationKey, err)
+	}
+	return organization, nil
+}
+
+func (s *AuthService) GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error) {
+	organizationKey := model.OrganizationPath(organizationID)
+	m := model.OrganizationData{}
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, organizationKey, &m)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			err = ErrNotFound
+		}
+		return nil, fmt.Errorf("organization %s: %w", organizationID, err)
+	}
+	return model.OrganizationFromProto(&m), nil
+}
+
+func (s *AuthService) ListOrganizations(ctx context.Context, params *model.PaginationParams) ([]*model.Organization, *model.Paginator, error) {
+	var organization model.OrganizationData
+	organizationKey := model.OrganizationPath(params.Prefix)
+
+	msgs, paginator, err := s.ListKVPaged(ctx, (&organization).ProtoReflect().Type(), params, organizationKey, false)
+	if msgs == nil {
+		return nil, paginator, err
+	}
+	return model.ConvertOrganizationDataList(msgs), paginator, err
+}
+
+// UpdateOrganization replaces the description and limits of an existing organization
+func (s *AuthService) UpdateOrganization(ctx context.Context, organization *model.Organization) (*model.Organization, error) {
+	if organization.MaxRepositories < 0 {
+		return nil, fmt.Errorf("%w: max repositories must not be negative", model.ErrValidationError)
+	}
+	current, err := s.GetOrganization(ctx, organization.ID)
+	if err != nil {
+		return nil, err
+	}
+	organization.CreatedAt = current.CreatedAt
+	organizationKey := model.OrganizationPath(organization.ID)
+	err = kv.SetMsgIf(ctx, s.store, model.PartitionKey, organizationKey, model.ProtoFromOrganization(organization), kv.PrecondConditionalExists)
+	if err != nil {
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			err = ErrNotFound
+		}
+		return nil, fmt.Errorf("update organization (organizationKey %s): %w", organizationKey, err)
+	}
+	return organization, nil
+}
+
+// DeleteOrganization deletes the organization and the memberships of the users added to it.
+// The entities named in its namespace are not deleted.
+func (s *AuthService) DeleteOrganization(ctx context.Context, organizationID string) error {
+	if _, err := s.GetOrganization(ctx, organizationID); err != nil {
+		return err
+	}
+	membersKey := model.OrganizationUserPath(organizationID, "")
+	it, err := kv.NewSecondaryIterator(ctx, s.store, (&model.UserData{}).ProtoReflect().Type(), model.PartitionKey, membersKey, []byte(""))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		user := it.Entry().Value.(*model.UserData)
+		if err := s.removeOrganizationMemberNoValidation(ctx, organizationID, user.Username); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	organizationKey := model.OrganizationPath(organizationID)
+	if err := s.store.Delete(ctx, []byte(model.PartitionKey), organizationKey); err != nil {
+		return fmt.Errorf("delete organization (organizationKey %s): %w", organizationKey, err)
+	}
+	return nil
+}
+
+func (s *AuthService) AddOrganizationMember(ctx context.Context, organizationID, username string) error {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return err
+	}
+	if _, err := s.GetOrganization(ctx, organizationID); err != nil {
+		return err
+	}
+	// users named in the namespace of another organization are members of it
+	if id := model.OrganizationIDOfName(username); id != "" && id != organizationID {
+		if _, err := s.GetOrganization(ctx, id); err == nil {
+			return fmt.Errorf("user %s is a member of organization %s: %w", username, id, ErrAlreadyExists)
+		}
+	}
+
+	userOrganizationKey := model.UserOrganizationPath(username)
+	err := kv.SetMsgIf(ctx, s.store, model.PartitionKey, userOrganizationKey, &kv.SecondaryIndex{PrimaryKey: model.OrganizationPath(organizationID)}, nil)
+	if err != nil {
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			err = ErrAlreadyExists
+		}
+		return fmt.Errorf("add user to organization: (key %s): %w", userOrganizationKey, err)
+	}
+	organizationUserKey := model.OrganizationUserPath(organizationID, username)
+	err = kv.SetMsg(ctx, s.store, model.PartitionKey, organizationUserKey, &kv.SecondaryIndex{PrimaryKey: model.UserPath(username)})
+	if err != nil {
+		return fmt.Errorf("add user to organization: (key %s): %w", organizationUserKey, err)
+	}
+	return nil
+}
+
+func (s *AuthService) RemoveOrganizationMember(ctx context.Context, organizationID, username string) error {
+	if _, err := s.GetOrganization(ctx, organizationID); err != nil {
+		return err
+	}
+	index := kv.SecondaryIndex{}
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, model.OrganizationUserPath(organizationID, username), &index)
+	if errors.Is(err, kv.ErrNotFound) {
+		return fmt.Errorf("user %s in organization %s: %w", username, organizationID, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	return s.removeOrganizationMemberNoValidation(ctx, organizationID, username)
+}
+
+func (s *AuthService) removeOrganizationMemberNoValidation(ctx context.Context, organizationID, username string) error {
+	for _, key := range [][]byte{model.UserOrganizationPath(username), model.OrganizationUserPath(organizationID, username)} {
+		if err := s.store.Delete(ctx, []byte(model.PartitionKey), key); err != nil {
+			return fmt.Errorf("remove user from organization: (key %s): %w", key, err)
+		}
+	}
+	return nil
+}
+
+// deleteUserOrganizationMembership removes username from the organization it was added to, if any
+func (s *AuthService) deleteUserOrganizationMembership(ctx context.Context, username string) error {
+	organization, err := s.getAddedOrganization(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.removeOrganizationMemberNoValidation(ctx, organization.ID, username)
+}
+
+// ListOrganizationMembers lists the users added to the organization.  Users named in its
+// namespace are members without being added, and are listed by listing users with the prefix
+// of the organization.
+func (s *AuthService) ListOrganizationMembers(ctx context.Context, organizationID string, params *model.PaginationParams) ([]*model.User, *model.Paginator, error) {
+	if _, err := s.GetOrganization(ctx, organizationID); err != nil {
+		return nil, nil, err
+	}
+	var user model.UserData
+	membersKey := model.OrganizationUserPath(organizationID, params.Prefix)
+
+	msgs, paginator, err := s.ListKVPaged(ctx, (&user).ProtoReflect().Type(), params, membersKey, true)
+	if msgs == nil {
+		return nil, paginator, err
+	}
+	return model.ConvertUsersDataList(msgs), paginator, err
+}
+
+func (s *AuthService) GetUserOrganization(ctx context.Context, username string) (*model.Organization, error) {
+	organization, err := s.getAddedOrganization(ctx, username)
+	if !errors.Is(err, ErrNotFound) {
+		return organization, err
+	}
+	if id := model.OrganizationIDOfName(username); id != "" {
+		return s.GetOrganization(ctx, id)
+	}
+	return nil, fmt.Errorf("organization of user %s: %w", username, ErrNotFound)
+}
+
+// getAddedOrganization returns the organization username was added to, ErrNotFound if none
+func (s *AuthService) getAddedOrganization(ctx context.Context, username string) (*model.Organization, error) {
+	index := kv.SecondaryIndex{}
+	userOrganizationKey := model.UserOrganizationPath(username)
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, userOrganizationKey, &index)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("organization of user %s: %w", username, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("organization of user (key %s): %w", userOrganizationKey, err)
+	}
+	m := model.OrganizationData{}
+	_, err = kv.GetMsg(ctx, s.store, model.PartitionKey, index.PrimaryKey, &m)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("organization of user %s: %w", username, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("organization (key %s): %w", index.PrimaryKey, err)
+	}
+	return model.OrganizationFromProto(&m), nil
+}
+
+// OrganizationBoundary returns the permission boundary of the members of organization: they
+// may only access the repositories, users, groups and policies of its namespace, and list and
+// read their own user
+func OrganizationBoundary(organization *model.Organization) *model.Policy {
+	prefix := model.OrganizationNamePrefix(organization.ID)
+	statements := model.Statements{
+		{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{"fs:*", "retention:*", "branches:*", "ci:*"},
+			Resource: permissions.RepoArn(prefix + "*"),
+		},
+		{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{"auth:*"},
+			Resource: permissions.UserArn(prefix + "*"),
+		},
+		{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{"auth:*"},
+			Resource: permissions.UserArn("${user}"),
+		},
+		{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{"auth:*"},
+			Resource: permissions.GroupArn(prefix + "*"),
+		},
+		{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{"auth:*"},
+			Resource: permissions.PolicyArn(prefix + "*"),
+		},
+		{
+			Effect: model.StatementEffectAllow,
+			Action: []string{
+				permissions.ListRepositoriesAction,
+				permissions.ReadConfigAction,
+				permissions.ListUsersAction,
+				permissions.ListGroupsAction,
+				permissions.ListPoliciesAction,
+			},
+			Resource: permissions.All,
+		},
+	}
+	if organization.StorageNamespace != "" {
+		statements = append(statements, model.Statement{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{permissions.AttachStorageNamespaceAction},
+			Resource: permissions.StorageNamespace(organization.StorageNamespace + "*"),
+		})
+	}
+	return &model.Policy{
+		DisplayName: "organization:" + organization.ID,
+		Statement:   statements,
+	}
+}
+
+// RestrictToOrganization returns the prefix to list entities matching prefix in the namespace
+// of organization.  It returns false if no entity of the namespace matches prefix.
+func RestrictToOrganization(organization *model.Organization, prefix string) (string, bool) {
+	namespace := model.OrganizationNamePrefix(organization.ID)
+	switch {
+	case strings.HasPrefix(prefix, namespace):
+		return prefix, true
+	case strings.HasPrefix(namespace, prefix):
+		return namespace, true
+	default:
+		return "", false
+	}
+}
@@ -7,4 +7,26 @@ type ServiceCache struct {
 	Size    int
 	TTL     time.Duration
 	Jitter  time.Duration
+	// RevocationTTL is how long revoked credentials and sessions may still be accepted
+	RevocationTTL time.Duration
+}
+
+type Lockout struct {
+	Enabled bool
+	// MaxFailures consecutive failed authentications of an access key lock it out
+	MaxFailures int
+	// MaxSourceIPFailures consecutive failed authentications from a source IP lock it out
+	MaxSourceIPFailures int
+	// Duration is the first lockout period, doubled by every further failure
+	Duration    time.Duration
+	MaxDuration time.Duration
+	// TrustedProxies are the addresses and CIDR ranges of proxies trusted to set X-Forwarded-For
+	TrustedProxies []string
+}
+
+type Usage struct {
+	Enabled bool
+	// MaxLabeledAccessKeys is the number of access keys with their own label on usage metrics, usage of
+	// further access keys is labeled "other"
+	MaxLabeledAccessKeys int
 }
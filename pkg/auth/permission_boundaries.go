@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// PermissionBoundaryManager manages permission boundaries of users and groups.  A permission
+// boundary is a policy that limits the permissions a user gets from its policies: a request is
+// allowed only if it is also allowed by the boundary of the user and by the boundaries of all
+// of its groups.  A user or a group has at most one boundary.
+type PermissionBoundaryManager interface {
+	SetUserPermissionBoundary(ctx context.Context, username, policyDisplayName string) error
+	GetUserPermissionBoundary(ctx context.Context, username string) (*model.Policy, error)
+	DeleteUserPermissionBoundary(ctx context.Context, username string) error
+
+	SetGroupPermissionBoundary(ctx context.Context, groupDisplayName, policyDisplayName string) error
+	GetGroupPermissionBoundary(ctx context.Context, groupDisplayName string) (*model.Policy, error)
+	DeleteGroupPermissionBoundary(ctx context.Context, groupDisplayName string) error
+}
+
+func (s *AuthService) SetUserPermissionBoundary(ctx context.Context, username, policyDisplayName string) error {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return err
+	}
+	return s.setPermissionBoundary(ctx, model.UserBoundaryPath(username), policyDisplayName)
+}
+
+func (s *AuthService) GetUserPermissionBoundary(ctx context.Context, username string) (*model.Policy, error) {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return nil, err
+	}
+	return s.getPermissionBoundary(ctx, model.UserBoundaryPath(username))
+}
+
+func (s *AuthService) DeleteUserPermissionBoundary(ctx context.Context, username string) error {
+	if _, err := s.GetUser(ctx, username); err != nil {
+		return err
+	}
+	return s.deletePermissionBoundary(ctx, model.UserBoundaryPath(username))
+}
+
+func (s *AuthService) SetGroupPermissionBoundary(ctx context.Context, groupDisplayName, policyDisplayName string) error {
+	if _, err := s.GetGroup(ctx, groupDisplayName); err != nil {
+		return err
+	}
+	return s.setPermissionBoundary(ctx, model.GroupBoundaryPath(groupDisplayName), policyDisplayName)
+}
+
+func (s *AuthService) GetGroupPermissionBoundary(ctx context.Context, groupDisplayName string) (*model.Policy, error) {
+	if _, err := s.GetGroup(ctx, groupDisplayName); err != nil {
+		return nil, err
+	}
+	return s.getPermissionBoundary(ctx, model.GroupBoundaryPath(groupDisplayName))
+}
+
+func (s *AuthService) DeleteGroupPermissionBoundary(ctx context.Context, groupDisplayName string) error {
+	if _, err := s.GetGroup(ctx, groupDisplayName); err != nil {
+		return err
+	}
+	return s.deletePermissionBoundary(ctx, model.GroupBoundaryPath(groupDisplayName))
+}
+
+func (s *AuthService) setPermissionBoundary(ctx context.Context, boundaryKey []byte, policyDisplayName string) error {
+	if _, err := s.GetPolicy(ctx, policyDisplayName); err != nil {
+		return err
+	}
+	err := kv.SetMsg(ctx, s.store, model.PartitionKey, boundaryKey, &kv.SecondaryIndex{PrimaryKey: model.PolicyPath(policyDisplayName)})
+	if err != nil {
+		return fmt.Errorf("set permission boundary (key %s): %w", boundaryKey, err)
+	}
+	s.cache.InvalidatePermissionBoundaries()
+	return nil
+}
+
+// getPermissionBoundary returns the boundary policy stored under boundaryKey, ErrNotFound if
+// there is no boundary.  A boundary whose policy was deleted is returned as a policy without
+// statements, which allows nothing.
+func (s *AuthService) getPermissionBoundary(ctx context.Context, boundaryKey []byte) (*model.Policy, error) {
+	index := kv.SecondaryIndex{}
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, boundaryKey, &index)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			err = ErrNotFound
+		}
+		return nil, fmt.Errorf("permission boundary (key %s): %w", boundaryKey, err)
+	}
+	p := model.PolicyData{}
+	_, err = kv.GetMsg(ctx, s.store, model.PartitionKey, index.PrimaryKey, &p)
+	if errors.Is(err, kv.ErrNotFound) {
+		return &model.Policy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("permission boundary policy (key %s): %w", index.PrimaryKey, err)
+	}
+	return model.PolicyFromProto(&p), nil
+}
+
+func (s *AuthService) deletePermissionBoundary(ctx context.Context, boundaryKey []byte) error {
+	err := s.store.Delete(ctx, []byte(model.PartitionKey), boundaryKey)
+	if err != nil {
+		return fmt.Errorf("delete permission boundary (key %s): %w", boundaryKey, err)
+	}
+	s.cache.InvalidatePermissionBoundaries()
+	return nil
+}
+
+// getPermissionBoundaries returns the boundaries of username, of all its groups and of its
+// organization
+func (s *AuthService) getPermissionBoundaries(ctx context.Context, username string) ([]*model.Policy, error) {
+	var boundaries []*model.Policy
+	addBoundary := func(boundaryKey []byte) error {
+		boundary, err := s.getPermissionBoundary(ctx, boundaryKey)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		boundaries = append(boundaries, boundary)
+		return nil
+	}
+
+	if err := addBoundary(model.UserBoundaryPath(username)); err != nil {
+		return nil, err
+	}
+	after := ""
+	for {
+		groups, paginator, err := s.ListUserGroups(ctx, username, &model.PaginationParams{
+			After:  after,
+			Amount: maxPage,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list user groups: %w", err)
+		}
+		for _, group := range groups {
+			if err := addBoundary(model.GroupBoundaryPath(group.DisplayName)); err != nil {
+				return nil, err
+			}
+		}
+		if paginator.NextPageToken == "" {
+			break
+		}
+		after = paginator.NextPageToken
+	}
+
+	organization, err := s.GetUserOrganization(ctx, username)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("user organization: %w", err)
+	}
+	if organization != nil {
+		boundaries = append(boundaries, OrganizationBoundary(organization))
+	}
+	return boundaries, nil
+}
+
+// permissionBoundariesDenial returns the denial of node by the first boundary of username that does not allow it,
+// or nil if every boundary allows node
+func (s *AuthService) permissionBoundariesDenial(ctx context.Context, node permissions.Node, username string) (*Denial, error) {
+	boundaries, err := s.cache.GetUserPermissionBoundaries(username, func() ([]*model.Policy, error) {
+		return s.getPermissionBoundaries(ctx, username)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, boundary := range boundaries {
+		if result, denial := explainPermissions(ctx, node, username, []*model.Policy{boundary}); result != CheckAllow {
+			denial.Reason = DenialReasonPermissionBoundary
+			denial.Policy = boundary.DisplayName
+			return denial, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,140 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const provisioningHookSource = "provisioning_webhook"
+
+const (
+	ActionCreateUser        = "create_user"
+	ActionInviteUser        = "invite_user"
+	ActionCreateCredentials = "create_credentials"
+)
+
+var ErrBadConfig = errors.New("invalid configuration")
+
+// HookConfig holds the provisioning webhook configuration.
+type HookConfig struct {
+	// Enabled if set true will call the webhook before users or credentials are created
+	Enabled bool
+	// Endpoint URL of the approval service (e.g. https://my-governance.example.com/lakefs/approve)
+	Endpoint string
+	// RequestTimeout timeout for approval requests
+	RequestTimeout time.Duration
+}
+
+// Request is the request object that will be sent to the approval service as JSON payload in a POST request
+type Request struct {
+	Action       string  `json:"action"`
+	Username     string  `json:"username"`
+	Email        *string `json:"email,omitempty"`
+	FriendlyName *string `json:"friendly_name,omitempty"`
+	ExternalID   *string `json:"external_id,omitempty"`
+	Source       string  `json:"source,omitempty"`
+	AccessKeyID  string  `json:"access_key_id,omitempty"`
+}
+
+// Response is the expected response from the approval service
+type Response struct {
+	// Allowed must be set to true for the operation to take effect
+	Allowed bool `json:"allowed"`
+	// Message is an optional explanation, returned to the caller when the operation is denied
+	Message string `json:"message,omitempty"`
+}
+
+// Hook calls an external approval service before user provisioning operations take effect
+type Hook struct {
+	Logger logging.Logger
+	Config HookConfig
+	client *http.Client
+}
+
+func NewHook(conf HookConfig, logger logging.Logger) (*Hook, error) {
+	if conf.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is empty: %w", ErrBadConfig)
+	}
+
+	httpClient := &http.Client{Timeout: conf.RequestTimeout}
+
+	log := logger.WithField("service_name", provisioningHookSource)
+
+	log.WithFields(logging.Fields{
+		"hook_url":        conf.Endpoint,
+		"request_timeout": httpClient.Timeout,
+	}).Info("initializing provisioning webhook")
+
+	return &Hook{
+		Logger: log,
+		Config: conf,
+		client: httpClient,
+	}, nil
+}
+
+// Approve sends the request to the approval service. It returns nil only if the service explicitly allowed the
+// operation, and auth.ErrProvisioningDenied if the service denied it.
+func (h *Hook) Approve(ctx context.Context, request *Request) error {
+	log := h.Logger.WithContext(ctx).WithFields(logging.Fields{
+		"action":   request.Action,
+		"username": request.Username,
+	})
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed marshaling request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Config.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed creating request to provisioning webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Trace("starting http request to provisioning webhook")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed sending request to provisioning webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	log = log.WithField("status_code", resp.StatusCode)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= 300 {
+		return fmt.Errorf("bad status code %d: %w", resp.StatusCode, auth.ErrUnexpectedStatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed reading response body: %w", err)
+	}
+
+	var res Response
+	if err := json.Unmarshal(body, &res); err != nil {
+		return fmt.Errorf("unmarshaling provisioning webhook response: %w", err)
+	}
+	if !res.Allowed {
+		log.WithField("message", res.Message).Info("provisioning webhook denied the operation")
+		if res.Message != "" {
+			return fmt.Errorf("%s: %w", res.Message, auth.ErrProvisioningDenied)
+		}
+		return auth.ErrProvisioningDenied
+	}
+
+	log.Debug("provisioning webhook approved the operation")
+	return nil
+}
+
+func (h *Hook) String() string {
+	return provisioningHookSource
+}
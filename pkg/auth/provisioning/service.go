@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+)
+
+// Service wraps an auth.Service and calls the provisioning Hook before users or credentials are created.
+// Any other call is passed as is to the wrapped service.
+type Service struct {
+	auth.Service
+	hook *Hook
+}
+
+// managers is implemented by services that manage login sessions, access tokens, organizations and permission
+// boundaries.  These calls are passed as is to the wrapped service.
+type managers interface {
+	auth.SessionManager
+	auth.AccessTokenManager
+	auth.OrganizationManager
+	auth.PermissionBoundaryManager
+}
+
+// inviterService is a Service that also wraps the email invitation of the underlying service
+type inviterService struct {
+	*Service
+	inviter auth.EmailInviter
+}
+
+// managerService is a Service that also forwards the managers of the underlying service
+type managerService struct {
+	*Service
+	managers
+}
+
+// managerInviterService is an inviterService that also forwards the managers of the underlying service
+type managerInviterService struct {
+	*inviterService
+	managers
+}
+
+// NewService returns an auth.Service that consults hook on user provisioning. The returned service implements
+// auth.EmailInviter and the session, access token, organization and permission boundary managers only if the
+// wrapped service does, so that callers checking for them behave the same with or without provisioning.
+func NewService(svc auth.Service, hook *Hook) auth.Service {
+	s := &Service{
+		Service: svc,
+		hook:    hook,
+	}
+	m, isManager := svc.(managers)
+	inviter, isInviter := svc.(auth.EmailInviter)
+	switch {
+	case isManager && isInviter:
+		return &managerInviterService{inviterService: &inviterService{Service: s, inviter: inviter}, managers: m}
+	case isManager:
+		return &managerService{Service: s, managers: m}
+	case isInviter:
+		return &inviterService{Service: s, inviter: inviter}
+	default:
+		return s
+	}
+}
+
+func (s *Service) CreateUser(ctx context.Context, user *model.User) (string, error) {
+	err := s.hook.Approve(ctx, &Request{
+		Action:       ActionCreateUser,
+		Username:     user.Username,
+		Email:        user.Email,
+		FriendlyName: user.FriendlyName,
+		ExternalID:   user.ExternalID,
+		Source:       user.Source,
+	})
+	if err != nil {
+		return auth.InvalidUserID, err
+	}
+	return s.Service.CreateUser(ctx, user)
+}
+
+func (s *Service) CreateCredentials(ctx context.Context, username string) (*model.Credential, error) {
+	err := s.hook.Approve(ctx, &Request{
+		Action:   ActionCreateCredentials,
+		Username: username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Service.CreateCredentials(ctx, username)
+}
+
+func (s *Service) AddCredentials(ctx context.Context, username, accessKeyID, secretAccessKey string) (*model.Credential, error) {
+	err := s.hook.Approve(ctx, &Request{
+		Action:      ActionCreateCredentials,
+		Username:    username,
+		AccessKeyID: accessKeyID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Service.AddCredentials(ctx, username, accessKeyID, secretAccessKey)
+}
+
+func (s *inviterService) InviteUser(ctx context.Context, email string) error {
+	err := s.hook.Approve(ctx, &Request{
+		Action:   ActionInviteUser,
+		Username: email,
+		Email:    &email,
+	})
+	if err != nil {
+		return err
+	}
+	return s.inviter.InviteUser(ctx, email)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth/model"
 	"github.com/treeverse/lakefs/pkg/auth/params"
 	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+	"github.com/treeverse/lakefs/pkg/cache"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
@@ -41,6 +42,8 @@ type AuthorizationRequest struct {
 type AuthorizationResponse struct {
 	Allowed bool
 	Error   error
+	// Denial explains why the request was not allowed, nil if it was allowed
+	Denial *Denial
 }
 
 // CheckResult - the final result for the authorization is accepted only if it's CheckAllow
@@ -185,6 +188,10 @@ type AuthService struct {
 	secretStore crypt.SecretStore
 	cache       Cache
 	log         logging.Logger
+	revocations *revocationCache
+	lastUsed    cache.Cache
+	// credentialsRevocationPeriod is how long deleted credentials may remain in the cache
+	credentialsRevocationPeriod time.Duration
 }
 
 func NewAuthService(store kv.Store, secretStore crypt.SecretStore, cacheConf params.ServiceCache, logger logging.Logger) *AuthService {
@@ -196,10 +203,13 @@ func NewAuthService(store kv.Store, secretStore crypt.SecretStore, cacheConf par
 		cache = &DummyCache{}
 	}
 	res := &AuthService{
-		store:       store,
-		secretStore: secretStore,
-		cache:       cache,
-		log:         logger,
+		store:                       store,
+		secretStore:                 secretStore,
+		cache:                       cache,
+		log:                         logger,
+		revocations:                 newRevocationCache(store, cacheConf.RevocationTTL),
+		lastUsed:                    newLastUsedCache(),
+		credentialsRevocationPeriod: cacheConf.TTL + cacheConf.Jitter + cacheConf.RevocationTTL,
 	}
 	return res
 }
@@ -270,6 +280,16 @@ func (s *AuthService) DeleteUser(ctx context.Context, username string) error {
 		return err
 	}
 
+	if err = s.deletePermissionBoundary(ctx, model.UserBoundaryPath(username)); err != nil {
+		return err
+	}
+	if err = s.deleteUserOrganizationMembership(ctx, username); err != nil {
+		return err
+	}
+	if err = s.deleteUserAccessTokens(ctx, username); err != nil {
+		return err
+	}
+
 	// delete user
 	err = s.store.Delete(ctx, []byte(model.PartitionKey), userPath)
 	if err != nil {
@@ -592,6 +612,10 @@ func (s *AuthService) DeleteGroup(ctx context.Context, groupID string) error {
 		return err
 	}
 
+	if err = s.deletePermissionBoundary(ctx, model.GroupBoundaryPath(groupID)); err != nil {
+		return err
+	}
+
 	// delete group
 	groupPath := model.GroupPath(groupID)
 	err = s.store.Delete(ctx, []byte(model.PartitionKey), groupPath)
@@ -642,6 +666,8 @@ func (s *AuthService) AddUserToGroup(ctx context.Context, username, groupDisplay
 		}
 		return fmt.Errorf("add user to group: (key %s): %w", gu, err)
 	}
+	// the user is now limited by the boundary of the group
+	s.cache.InvalidatePermissionBoundaries()
 	return nil
 }
 
@@ -651,6 +677,7 @@ func (s *AuthService) removeUserFromGroupNoValidation(ctx context.Context, usern
 	if err != nil {
 		return fmt.Errorf("remove user from group: (key %s): %w", gu, err)
 	}
+	s.cache.InvalidatePermissionBoundaries()
 	return nil
 }
 
@@ -739,6 +766,9 @@ func ValidatePolicy(policy *model.Policy) error {
 		if err := model.ValidateStatementEffect(stmt.Effect); err != nil {
 			return err
 		}
+		if err := model.ValidateStatementCondition(stmt.Condition); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -758,6 +788,8 @@ func (s *AuthService) WritePolicy(ctx context.Context, policy *model.Policy, upd
 			}
 			return err
 		}
+		// the policy may be a permission boundary
+		s.cache.InvalidatePermissionBoundaries()
 		return nil
 	}
 
@@ -826,6 +858,8 @@ func (s *AuthService) DeletePolicy(ctx context.Context, policyDisplayName string
 	if err != nil {
 		return fmt.Errorf("delete policy (policyKey %s): %w", policyPath, err)
 	}
+	// a boundary whose policy was deleted allows nothing
+	s.cache.InvalidatePermissionBoundaries()
 	return nil
 }
 
@@ -902,7 +936,13 @@ func (s *AuthService) DeleteCredentials(ctx context.Context, username, accessKey
 	if err != nil {
 		return fmt.Errorf("delete credentials (credentialsKey %s): %w", credPath, err)
 	}
-	return nil
+	activityPath := model.CredentialsActivityPath(accessKeyID)
+	err = s.store.Delete(ctx, []byte(model.PartitionKey), activityPath)
+	if err != nil {
+		return fmt.Errorf("delete credentials activity (key %s): %w", activityPath, err)
+	}
+	// reject the credentials on all instances until they expire from their cache
+	return s.revoke(ctx, model.RevokedCredentialsPath(accessKeyID), accessKeyID, time.Now().Add(s.credentialsRevocationPeriod))
 }
 
 func (s *AuthService) AttachPolicyToGroup(ctx context.Context, policyDisplayName, groupDisplayName string) error {
@@ -968,6 +1008,13 @@ func (s *AuthService) GetCredentialsForUser(ctx context.Context, username, acces
 }
 
 func (s *AuthService) GetCredentials(ctx context.Context, accessKeyID string) (*model.Credential, error) {
+	revoked, err := s.revocations.IsRevoked(ctx, model.RevokedCredentialsPath(accessKeyID))
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, fmt.Errorf("credentials %w", ErrNotFound)
+	}
 	return s.cache.GetCredential(accessKeyID, func() (*model.Credential, error) {
 		m := &model.UserData{}
 		itr, err := kv.NewPrimaryIterator(ctx, s.store, m.ProtoReflect().Type(), model.PartitionKey, model.UserPath(""), kv.IteratorOptionsAfter([]byte("")))
@@ -1004,12 +1051,18 @@ func interpolateUser(resource string, username string) string {
 }
 
 func checkPermissions(ctx context.Context, node permissions.Node, username string, policies []*model.Policy) CheckResult {
-	allowed := CheckNeutral
+	result, _ := explainPermissions(ctx, node, username, policies)
+	return result
+}
+
+// explainPermissions checks node like checkPermissions, returning the denial of a result other than CheckAllow
+func explainPermissions(ctx context.Context, node permissions.Node, username string, policies []*model.Policy) (CheckResult, *Denial) {
 	switch node.Type {
 	case permissions.NodeTypeNode:
 		// check whether the permission is allowed, denied or natural (not allowed and not denied)
+		allowed := false
 		for _, policy := range policies {
-			for _, stmt := range policy.Statement {
+			for i, stmt := range policy.Statement {
 				resource := interpolateUser(stmt.Resource, username)
 				if !ArnMatch(resource, node.Permission.Resource) {
 					continue
@@ -1018,31 +1071,55 @@ func checkPermissions(ctx context.Context, node permissions.Node, username strin
 					if !wildcard.Match(action, node.Permission.Action) {
 						continue // not a matching action
 					}
+					if len(stmt.Condition) > 0 {
+						match, err := conditionMatch(stmt.Condition, node.Permission.ConditionValues)
+						if err != nil {
+							// cannot tell whether a deny applies
+							logging.FromContext(ctx).WithError(err).Error("failed to get condition values")
+							return CheckDeny, newDenial(DenialReasonConditionError, node.Permission, policy.DisplayName, i)
+						}
+						if !match {
+							continue // statement does not apply to this request
+						}
+					}
 
 					if stmt.Effect == model.StatementEffectDeny {
 						// this is a "Deny" and it takes precedence
-						return CheckDeny
+						return CheckDeny, newDenial(DenialReasonExplicitDeny, node.Permission, policy.DisplayName, i)
 					}
 
-					allowed = CheckAllow
+					allowed = true
 				}
 			}
 		}
+		if !allowed {
+			return CheckNeutral, newDenial(DenialReasonNoAllow, node.Permission, "", NoStatement)
+		}
+		return CheckAllow, nil
 
 	case permissions.NodeTypeOr:
 		// returns:
 		// Allowed - at least one of the permissions is allowed and no one is denied
 		// Denied - one of the permissions is Deny
 		// Natural - otherwise
+		allowed := CheckNeutral
+		var denial *Denial
 		for _, node := range node.Nodes {
-			result := checkPermissions(ctx, node, username, policies)
+			result, nodeDenial := explainPermissions(ctx, node, username, policies)
 			if result == CheckDeny {
-				return CheckDeny
+				return CheckDeny, nodeDenial
 			}
 			if allowed != CheckAllow {
 				allowed = result
+				if denial == nil {
+					denial = nodeDenial
+				}
 			}
 		}
+		if allowed == CheckAllow {
+			return CheckAllow, nil
+		}
+		return allowed, denial
 
 	case permissions.NodeTypeAnd:
 		// returns:
@@ -1050,18 +1127,27 @@ func checkPermissions(ctx context.Context, node permissions.Node, username strin
 		// Denied - one of the permissions is Deny
 		// Natural - otherwise
 		for _, node := range node.Nodes {
-			result := checkPermissions(ctx, node, username, policies)
+			result, denial := explainPermissions(ctx, node, username, policies)
 			if result == CheckNeutral || result == CheckDeny {
-				return result
+				return result, denial
 			}
 		}
-		return CheckAllow
+		return CheckAllow, nil
 
 	default:
 		logging.FromContext(ctx).Error("unknown permission node type")
-		return CheckDeny
+		return CheckDeny, newDenial(DenialReasonNoAllow, node.Permission, "", NoStatement)
+	}
+}
+
+func newDenial(reason DenialReason, permission permissions.Permission, policy string, statement int) *Denial {
+	return &Denial{
+		Reason:    reason,
+		Action:    permission.Action,
+		Resource:  permission.Resource,
+		Policy:    policy,
+		Statement: statement,
 	}
-	return allowed
 }
 
 func (s *AuthService) Authorize(ctx context.Context, req *AuthorizationRequest) (*AuthorizationResponse, error) {
@@ -1073,12 +1159,23 @@ func (s *AuthService) Authorize(ctx context.Context, req *AuthorizationRequest)
 		return nil, err
 	}
 
-	allowed := checkPermissions(ctx, req.RequiredPermissions, req.Username, policies)
+	allowed, denial := explainPermissions(ctx, req.RequiredPermissions, req.Username, policies)
+	if allowed == CheckAllow {
+		// permission boundaries can only limit what the policies allow
+		denial, err = s.permissionBoundariesDenial(ctx, req.RequiredPermissions, req.Username)
+		if err != nil {
+			return nil, err
+		}
+		if denial != nil {
+			allowed = CheckNeutral
+		}
+	}
 
 	if allowed != CheckAllow {
 		return &AuthorizationResponse{
 			Allowed: false,
 			Error:   ErrInsufficientPermissions,
+			Denial:  denial,
 		}, nil
 	}
 
@@ -1514,9 +1611,10 @@ func (a *APIAuthService) WritePolicy(ctx context.Context, policy *model.Policy,
 	stmts := make([]Statement, len(policy.Statement))
 	for i, s := range policy.Statement {
 		stmts[i] = Statement{
-			Action:   s.Action,
-			Effect:   s.Effect,
-			Resource: s.Resource,
+			Action:    s.Action,
+			Effect:    s.Effect,
+			Resource:  s.Resource,
+			Condition: serializeStatementCondition(s.Condition),
 		}
 	}
 	createdAt := policy.CreatedAt.Unix()
@@ -1547,13 +1645,36 @@ func (a *APIAuthService) WritePolicy(ctx context.Context, policy *model.Policy,
 	return a.validateResponse(resp, http.StatusCreated)
 }
 
+func serializeStatementCondition(condition model.Condition) *Statement_Condition {
+	if len(condition) == 0 {
+		return nil
+	}
+	res := &Statement_Condition{AdditionalProperties: make(map[string]StatementConditionKeys, len(condition))}
+	for operator, keys := range condition {
+		res.AdditionalProperties[operator] = StatementConditionKeys{AdditionalProperties: keys}
+	}
+	return res
+}
+
+func deserializeStatementCondition(condition *Statement_Condition) model.Condition {
+	if condition == nil || len(condition.AdditionalProperties) == 0 {
+		return nil
+	}
+	res := make(model.Condition, len(condition.AdditionalProperties))
+	for operator, keys := range condition.AdditionalProperties {
+		res[operator] = keys.AdditionalProperties
+	}
+	return res
+}
+
 func serializePolicyToModalPolicy(p Policy) *model.Policy {
 	stmts := make(model.Statements, len(p.Statement))
 	for i, apiStatement := range p.Statement {
 		stmts[i] = model.Statement{
-			Effect:   apiStatement.Effect,
-			Action:   apiStatement.Action,
-			Resource: apiStatement.Resource,
+			Effect:    apiStatement.Effect,
+			Action:    apiStatement.Action,
+			Resource:  apiStatement.Resource,
+			Condition: deserializeStatementCondition(apiStatement.Condition),
 		}
 	}
 	var creationTime time.Time
@@ -1873,12 +1994,13 @@ func (a *APIAuthService) Authorize(ctx context.Context, req *AuthorizationReques
 		return nil, err
 	}
 
-	allowed := checkPermissions(ctx, req.RequiredPermissions, req.Username, policies)
+	allowed, denial := explainPermissions(ctx, req.RequiredPermissions, req.Username, policies)
 
 	if allowed != CheckAllow {
 		return &AuthorizationResponse{
 			Allowed: false,
 			Error:   ErrInsufficientPermissions,
+			Denial:  denial,
 		}, nil
 	}
 
@@ -23,6 +23,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth/model"
 	authparams "github.com/treeverse/lakefs/pkg/auth/params"
 	authtestutil "github.com/treeverse/lakefs/pkg/auth/testutil"
+	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvtest"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
@@ -584,6 +585,589 @@ func describeAllowed(allowed bool) string {
 	return "forbidden"
 }
 
+func TestAuthService_PermissionBoundaries(t *testing.T) {
+	for _, cacheEnabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("cache_enabled_%t", cacheEnabled), func(t *testing.T) {
+			testPermissionBoundaries(t, cacheEnabled)
+		})
+	}
+}
+
+// testPermissionBoundaries verifies that changes to boundaries apply immediately, also when boundaries are cached
+func testPermissionBoundaries(t *testing.T, cacheEnabled bool) {
+	ctx := context.Background()
+	authService := auth.NewAuthService(kvtest.GetStore(ctx, t), crypt.NewSecretStore(someSecret), authparams.ServiceCache{
+		Enabled: cacheEnabled,
+		Size:    100,
+		TTL:     time.Hour,
+	}, logging.ContextUnavailable())
+
+	allowAll := &model.Policy{
+		DisplayName: "AllowAll",
+		Statement: model.Statements{
+			{Action: []string{"fs:*"}, Resource: "*", Effect: model.StatementEffectAllow},
+		},
+	}
+	username := userWithPolicies(t, authService, []*model.Policy{allowAll})
+	noProdDelete := &model.Policy{
+		DisplayName: "NoProdDelete",
+		Statement: model.Statements{
+			{Action: []string{"fs:*", "auth:*"}, Resource: "*", Effect: model.StatementEffectAllow},
+			{Action: []string{"fs:DeleteObject"}, Resource: "arn:lakefs:fs:::repository/prod/*", Effect: model.StatementEffectDeny},
+		},
+	}
+	require.NoError(t, authService.WritePolicy(ctx, noProdDelete, false))
+	readOnly := &model.Policy{
+		DisplayName: "ReadOnly",
+		Statement: model.Statements{
+			{Action: []string{"fs:Read*"}, Resource: "*", Effect: model.StatementEffectAllow},
+		},
+	}
+	require.NoError(t, authService.WritePolicy(ctx, readOnly, false))
+	_, err := authService.CreateGroup(ctx, &model.Group{DisplayName: "readers"})
+	require.NoError(t, err)
+	require.NoError(t, authService.AddUserToGroup(ctx, username, "readers"))
+
+	isAllowed := func(action, resource string) bool {
+		t.Helper()
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: username,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{Action: action, Resource: resource},
+			},
+		})
+		require.NoError(t, err)
+		return resp.Allowed
+	}
+	prodObject := permissions.ObjectArn("prod", "file")
+	devObject := permissions.ObjectArn("dev", "file")
+
+	_, err = authService.GetUserPermissionBoundary(ctx, username)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	require.True(t, isAllowed(permissions.DeleteObjectAction, prodObject))
+
+	// user boundary limits the user's policies
+	require.NoError(t, authService.SetUserPermissionBoundary(ctx, username, noProdDelete.DisplayName))
+	boundary, err := authService.GetUserPermissionBoundary(ctx, username)
+	require.NoError(t, err)
+	require.Equal(t, noProdDelete.DisplayName, boundary.DisplayName)
+	require.False(t, isAllowed(permissions.DeleteObjectAction, prodObject))
+	require.True(t, isAllowed(permissions.DeleteObjectAction, devObject))
+	require.True(t, isAllowed(permissions.ReadObjectAction, prodObject))
+	// a boundary does not grant permissions by itself
+	require.False(t, isAllowed(permissions.CreateUserAction, permissions.UserArn("someone")))
+
+	// group boundaries apply to members as well
+	require.NoError(t, authService.SetGroupPermissionBoundary(ctx, "readers", readOnly.DisplayName))
+	require.False(t, isAllowed(permissions.DeleteObjectAction, devObject))
+	require.True(t, isAllowed(permissions.ReadObjectAction, prodObject))
+
+	require.NoError(t, authService.DeleteGroupPermissionBoundary(ctx, "readers"))
+	_, err = authService.GetGroupPermissionBoundary(ctx, "readers")
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	require.True(t, isAllowed(permissions.DeleteObjectAction, devObject))
+
+	// a boundary whose policy was deleted allows nothing
+	require.NoError(t, authService.DeletePolicy(ctx, noProdDelete.DisplayName))
+	require.False(t, isAllowed(permissions.ReadObjectAction, devObject))
+
+	require.NoError(t, authService.DeleteUserPermissionBoundary(ctx, username))
+	require.True(t, isAllowed(permissions.DeleteObjectAction, prodObject))
+
+	// group memberships and boundary policy updates apply as well
+	require.NoError(t, authService.SetGroupPermissionBoundary(ctx, "readers", readOnly.DisplayName))
+	require.False(t, isAllowed(permissions.DeleteObjectAction, devObject))
+	require.NoError(t, authService.RemoveUserFromGroup(ctx, username, "readers"))
+	require.True(t, isAllowed(permissions.DeleteObjectAction, devObject))
+	require.NoError(t, authService.AddUserToGroup(ctx, username, "readers"))
+	require.False(t, isAllowed(permissions.DeleteObjectAction, devObject))
+	readOnly.Statement = append(readOnly.Statement, model.Statement{Action: []string{"fs:DeleteObject"}, Resource: "*", Effect: model.StatementEffectAllow})
+	require.NoError(t, authService.WritePolicy(ctx, readOnly, true))
+	require.True(t, isAllowed(permissions.DeleteObjectAction, devObject))
+
+	require.ErrorIs(t, authService.SetUserPermissionBoundary(ctx, username, "no-such-policy"), auth.ErrNotFound)
+	require.ErrorIs(t, authService.SetUserPermissionBoundary(ctx, "no-such-user", readOnly.DisplayName), auth.ErrNotFound)
+}
+
+func TestAuthService_AuthorizeDenial(t *testing.T) {
+	ctx := context.Background()
+	authService, _ := authtestutil.SetupService(t, ctx, someSecret)
+
+	noProdDelete := &model.Policy{
+		DisplayName: "NoProdDelete",
+		Statement: model.Statements{
+			{Action: []string{"fs:*"}, Resource: "*", Effect: model.StatementEffectAllow},
+			{Action: []string{"fs:DeleteObject"}, Resource: "arn:lakefs:fs:::repository/prod/*", Effect: model.StatementEffectDeny},
+		},
+	}
+	username := userWithPolicies(t, authService, []*model.Policy{noProdDelete})
+	readOnly := &model.Policy{
+		DisplayName: "ReadOnly",
+		Statement: model.Statements{
+			{Action: []string{"fs:Read*"}, Resource: "*", Effect: model.StatementEffectAllow},
+		},
+	}
+	require.NoError(t, authService.WritePolicy(ctx, readOnly, false))
+
+	authorize := func(node permissions.Node) *auth.AuthorizationResponse {
+		t.Helper()
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username:            username,
+			RequiredPermissions: node,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	node := func(action, resource string) permissions.Node {
+		return permissions.Node{Permission: permissions.Permission{Action: action, Resource: resource}}
+	}
+	prodObject := permissions.ObjectArn("prod", "file")
+	devObject := permissions.ObjectArn("dev", "file")
+
+	resp := authorize(node(permissions.ReadObjectAction, prodObject))
+	require.True(t, resp.Allowed)
+	require.Nil(t, resp.Denial)
+
+	resp = authorize(node(permissions.DeleteObjectAction, prodObject))
+	require.False(t, resp.Allowed)
+	require.Equal(t, &auth.Denial{
+		Reason:    auth.DenialReasonExplicitDeny,
+		Action:    permissions.DeleteObjectAction,
+		Resource:  prodObject,
+		Policy:    noProdDelete.DisplayName,
+		Statement: 1,
+	}, resp.Denial)
+
+	resp = authorize(node(permissions.CreateUserAction, permissions.UserArn("someone")))
+	require.False(t, resp.Allowed)
+	require.Equal(t, &auth.Denial{
+		Reason:    auth.DenialReasonNoAllow,
+		Action:    permissions.CreateUserAction,
+		Resource:  permissions.UserArn("someone"),
+		Statement: auth.NoStatement,
+	}, resp.Denial)
+
+	// the denial of an "and" node is the denial of the permission that was not allowed
+	resp = authorize(permissions.Node{
+		Type:  permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{node(permissions.ReadObjectAction, devObject), node(permissions.DeleteObjectAction, prodObject)},
+	})
+	require.False(t, resp.Allowed)
+	require.Equal(t, permissions.DeleteObjectAction, resp.Denial.Action)
+	require.Equal(t, auth.DenialReasonExplicitDeny, resp.Denial.Reason)
+
+	require.NoError(t, authService.SetUserPermissionBoundary(ctx, username, readOnly.DisplayName))
+	resp = authorize(node(permissions.WriteObjectAction, devObject))
+	require.False(t, resp.Allowed)
+	require.Equal(t, &auth.Denial{
+		Reason:    auth.DenialReasonPermissionBoundary,
+		Action:    permissions.WriteObjectAction,
+		Resource:  devObject,
+		Policy:    readOnly.DisplayName,
+		Statement: auth.NoStatement,
+	}, resp.Denial)
+	require.Equal(t, "permission_boundary: fs:WriteObject on "+devObject+" by policy ReadOnly", resp.Denial.String())
+}
+
+func TestAuthService_Organizations(t *testing.T) {
+	ctx := context.Background()
+	authService, _ := authtestutil.SetupService(t, ctx, someSecret)
+
+	_, err := authService.CreateOrganization(ctx, &model.Organization{ID: "Acme-Corp"})
+	require.ErrorIs(t, err, model.ErrValidationError)
+	_, err = authService.CreateOrganization(ctx, &model.Organization{ID: "acme", StorageNamespace: "s3://bucket/acme/", MaxRepositories: 2})
+	require.NoError(t, err)
+	_, err = authService.CreateOrganization(ctx, &model.Organization{ID: "acme"})
+	require.ErrorIs(t, err, auth.ErrAlreadyExists)
+	_, err = authService.CreateOrganization(ctx, &model.Organization{ID: "globex"})
+	require.NoError(t, err)
+
+	organization, err := authService.UpdateOrganization(ctx, &model.Organization{ID: "acme", Description: "Acme", StorageNamespace: "s3://bucket/acme/"})
+	require.NoError(t, err)
+	require.False(t, organization.CreatedAt.IsZero())
+	organization, err = authService.GetOrganization(ctx, "acme")
+	require.NoError(t, err)
+	require.Equal(t, "Acme", organization.Description)
+	require.Equal(t, 0, organization.MaxRepositories)
+	organizations, _, err := authService.ListOrganizations(ctx, &model.PaginationParams{Amount: -1})
+	require.NoError(t, err)
+	require.Len(t, organizations, 2)
+
+	allowAll := &model.Policy{
+		DisplayName: "AllowAll",
+		Statement: model.Statements{
+			{Action: []string{"fs:*", "auth:*"}, Resource: "*", Effect: model.StatementEffectAllow},
+		},
+	}
+	username := userWithPolicies(t, authService, []*model.Policy{allowAll})
+	isAllowed := func(username, action, resource string) bool {
+		t.Helper()
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: username,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{Action: action, Resource: resource},
+			},
+		})
+		require.NoError(t, err)
+		return resp.Allowed
+	}
+	require.True(t, isAllowed(username, permissions.ReadObjectAction, permissions.ObjectArn("globex-data", "file")))
+
+	// members are limited to the namespace of their organization
+	require.NoError(t, authService.AddOrganizationMember(ctx, "acme", username))
+	require.ErrorIs(t, authService.AddOrganizationMember(ctx, "globex", username), auth.ErrAlreadyExists)
+	organization, err = authService.GetUserOrganization(ctx, username)
+	require.NoError(t, err)
+	require.Equal(t, "acme", organization.ID)
+	require.True(t, isAllowed(username, permissions.ReadObjectAction, permissions.ObjectArn("acme-data", "file")))
+	require.False(t, isAllowed(username, permissions.ReadObjectAction, permissions.ObjectArn("globex-data", "file")))
+	require.True(t, isAllowed(username, permissions.CreateUserAction, permissions.UserArn("acme-bob")))
+	require.False(t, isAllowed(username, permissions.CreateUserAction, permissions.UserArn("bob")))
+	require.True(t, isAllowed(username, permissions.ListRepositoriesAction, permissions.All))
+	require.True(t, isAllowed(username, permissions.AttachStorageNamespaceAction, permissions.StorageNamespace("s3://bucket/acme/data")))
+	require.False(t, isAllowed(username, permissions.AttachStorageNamespaceAction, permissions.StorageNamespace("s3://bucket/globex/data")))
+	// the boundary does not grant permissions by itself
+	require.False(t, isAllowed(username, permissions.SetLegalHoldsAction, permissions.RepoArn("acme-data")))
+
+	// users named in the namespace of an organization are its members
+	namespaced, err := authService.CreateUser(ctx, &model.User{Username: "globex-admin"})
+	require.NoError(t, err)
+	require.NoError(t, authService.AttachPolicyToUser(ctx, allowAll.DisplayName, namespaced))
+	require.False(t, isAllowed(namespaced, permissions.ReadObjectAction, permissions.ObjectArn("acme-data", "file")))
+	require.True(t, isAllowed(namespaced, permissions.ReadObjectAction, permissions.ObjectArn("globex-data", "file")))
+	require.ErrorIs(t, authService.AddOrganizationMember(ctx, "acme", namespaced), auth.ErrAlreadyExists)
+
+	members, _, err := authService.ListOrganizationMembers(ctx, "acme", &model.PaginationParams{Amount: -1})
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, username, members[0].Username)
+
+	require.NoError(t, authService.RemoveOrganizationMember(ctx, "acme", username))
+	require.ErrorIs(t, authService.RemoveOrganizationMember(ctx, "acme", username), auth.ErrNotFound)
+	_, err = authService.GetUserOrganization(ctx, username)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	require.True(t, isAllowed(username, permissions.ReadObjectAction, permissions.ObjectArn("globex-data", "file")))
+
+	// deleting the organization removes its members
+	require.NoError(t, authService.AddOrganizationMember(ctx, "acme", username))
+	require.NoError(t, authService.DeleteOrganization(ctx, "acme"))
+	_, err = authService.GetUserOrganization(ctx, username)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	_, err = authService.GetOrganization(ctx, "acme")
+	require.ErrorIs(t, err, auth.ErrNotFound)
+}
+
+func TestRestrictToOrganization(t *testing.T) {
+	organization := &model.Organization{ID: "acme"}
+	cases := []struct {
+		prefix   string
+		expected string
+		visible  bool
+	}{
+		{prefix: "", expected: "acme-", visible: true},
+		{prefix: "ac", expected: "acme-", visible: true},
+		{prefix: "acme-", expected: "acme-", visible: true},
+		{prefix: "acme-data", expected: "acme-data", visible: true},
+		{prefix: "acmex", visible: false},
+		{prefix: "globex-", visible: false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.prefix, func(t *testing.T) {
+			prefix, visible := auth.RestrictToOrganization(organization, tt.prefix)
+			require.Equal(t, tt.visible, visible)
+			if visible {
+				require.Equal(t, tt.expected, prefix)
+			}
+		})
+	}
+}
+
+func TestAuthService_ObjectTagConditions(t *testing.T) {
+	ctx := context.Background()
+	authService, _ := authtestutil.SetupService(t, ctx, someSecret)
+
+	allowAll := &model.Policy{
+		DisplayName: "AllowAll",
+		Statement: model.Statements{
+			{Action: []string{"fs:*"}, Resource: "*", Effect: model.StatementEffectAllow},
+		},
+	}
+	denyPII := &model.Policy{
+		DisplayName: "DenyPII",
+		Statement: model.Statements{
+			{
+				Action:    []string{permissions.ReadObjectAction},
+				Resource:  "*",
+				Effect:    model.StatementEffectDeny,
+				Condition: model.Condition{model.ConditionStringEquals: {"lakefs:ObjectTag/pii": {"true"}}},
+			},
+		},
+	}
+	allowPublic := &model.Policy{
+		DisplayName: "AllowPublic",
+		Statement: model.Statements{
+			{
+				Action:    []string{permissions.ReadObjectAction},
+				Resource:  "*",
+				Effect:    model.StatementEffectAllow,
+				Condition: model.Condition{model.ConditionStringLike: {"lakefs:ObjectTag/classification": {"public*"}}},
+			},
+		},
+	}
+	analyst := userWithPolicies(t, authService, []*model.Policy{allowAll, denyPII})
+	privileged := userWithPolicies(t, authService, nil)
+	require.NoError(t, authService.AttachPolicyToUser(ctx, allowAll.DisplayName, privileged))
+	reader := userWithPolicies(t, authService, []*model.Policy{allowPublic})
+
+	isAllowed := func(username string, tags map[string]string) bool {
+		t.Helper()
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: username,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn("repo", "file"),
+					ConditionValues: permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+						return tags, nil
+					}),
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp.Allowed
+	}
+	pii := map[string]string{"pii": "true"}
+	require.False(t, isAllowed(analyst, pii))
+	require.True(t, isAllowed(analyst, map[string]string{"pii": "false"}))
+	require.True(t, isAllowed(analyst, nil))
+	require.True(t, isAllowed(privileged, pii))
+	require.True(t, isAllowed(reader, map[string]string{"classification": "public-2024"}))
+	require.False(t, isAllowed(reader, map[string]string{"classification": "internal"}))
+	require.False(t, isAllowed(reader, nil))
+
+	t.Run("tags not read without conditions", func(t *testing.T) {
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: privileged,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn("repo", "file"),
+					ConditionValues: func() (map[string]string, error) {
+						return nil, errors.New("unexpected read of condition values")
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+	})
+
+	t.Run("failed to read tags", func(t *testing.T) {
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: analyst,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn("repo", "file"),
+					ConditionValues: func() (map[string]string, error) {
+						return nil, errors.New("failed")
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, resp.Allowed)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		err := authService.WritePolicy(ctx, &model.Policy{
+			DisplayName: "InvalidCondition",
+			Statement: model.Statements{
+				{
+					Action:    []string{permissions.ReadObjectAction},
+					Resource:  "*",
+					Effect:    model.StatementEffectDeny,
+					Condition: model.Condition{"NumericEquals": {"lakefs:ObjectTag/size": {"1"}}},
+				},
+			},
+		}, false)
+		require.ErrorIs(t, err, model.ErrValidationError)
+	})
+}
+
+func TestAuthService_AccessTokens(t *testing.T) {
+	ctx := context.Background()
+	authService, _ := authtestutil.SetupService(t, ctx, someSecret)
+	username := userWithPolicies(t, authService, nil)
+
+	_, _, err := authService.CreateAccessToken(ctx, username, "", nil, nil, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, model.ErrValidationError)
+	_, _, err = authService.CreateAccessToken(ctx, username, "", []string{"fs:ReadObject"}, nil, time.Now().Add(-time.Hour))
+	require.ErrorIs(t, err, model.ErrValidationError)
+
+	accessToken, token, err := authService.CreateAccessToken(ctx, username, "ci", []string{"fs:Read*"}, []string{"repo1"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, auth.IsAccessToken(token))
+
+	authenticated, err := authService.AuthenticateAccessToken(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, accessToken.ID, authenticated.ID)
+	require.Equal(t, username, authenticated.Username)
+	require.False(t, authenticated.LastUsedAt.IsZero())
+
+	_, err = authService.AuthenticateAccessToken(ctx, token+"0")
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+	_, err = authService.AuthenticateAccessToken(ctx, auth.AccessTokenPrefix+"nosuchtoken_secret")
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	isAllowed := func(action, resource string) bool {
+		return auth.AccessTokenAllows(ctx, authenticated, permissions.Node{
+			Permission: permissions.Permission{Action: action, Resource: resource},
+		})
+	}
+	require.True(t, isAllowed(permissions.ReadObjectAction, permissions.ObjectArn("repo1", "file")))
+	require.True(t, isAllowed(permissions.ReadRepositoryAction, permissions.RepoArn("repo1")))
+	require.False(t, isAllowed(permissions.WriteObjectAction, permissions.ObjectArn("repo1", "file")))
+	require.False(t, isAllowed(permissions.ReadObjectAction, permissions.ObjectArn("repo10", "file")))
+	require.False(t, isAllowed(permissions.ReadUserAction, permissions.UserArn(username)))
+
+	tokens, _, err := authService.ListAccessTokens(ctx, username, &model.PaginationParams{Amount: 100})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, accessToken.ID, tokens[0].ID)
+
+	require.ErrorIs(t, authService.DeleteAccessToken(ctx, "someone-else", accessToken.ID), auth.ErrNotFound)
+	require.NoError(t, authService.DeleteAccessToken(ctx, username, accessToken.ID))
+	_, err = authService.AuthenticateAccessToken(ctx, token)
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// deleting a user deletes its tokens
+	_, token, err = authService.CreateAccessToken(ctx, username, "", []string{"fs:*"}, nil, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, authService.DeleteUser(ctx, username))
+	_, err = authService.AuthenticateAccessToken(ctx, token)
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestAccessTokenScopeIncludes(t *testing.T) {
+	token := &model.AccessToken{Actions: []string{"fs:Read*", "auth:ReadUser"}, Repositories: []string{"repo1", "data-*"}}
+	cases := []struct {
+		name         string
+		actions      []string
+		repositories []string
+		expected     bool
+	}{
+		{name: "same", actions: []string{"fs:Read*", "auth:ReadUser"}, repositories: []string{"repo1", "data-*"}, expected: true},
+		{name: "narrower", actions: []string{"fs:ReadObject", "fs:Read*Repo*"}, repositories: []string{"data-1", "data-raw-*"}, expected: true},
+		{name: "other action", actions: []string{"fs:WriteObject"}, repositories: []string{"repo1"}},
+		{name: "wider action", actions: []string{"fs:*"}, repositories: []string{"repo1"}},
+		{name: "action wildcard", actions: []string{"auth:Read*"}, repositories: []string{"repo1"}},
+		{name: "other repository", actions: []string{"fs:ReadObject"}, repositories: []string{"repo10"}},
+		{name: "repository wildcard", actions: []string{"fs:ReadObject"}, repositories: []string{"repo*"}},
+		{name: "all repositories", actions: []string{"fs:ReadObject"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, auth.AccessTokenScopeIncludes(token, tc.actions, tc.repositories))
+		})
+	}
+	require.True(t, auth.AccessTokenScopeIncludes(&model.AccessToken{Actions: []string{"*"}}, []string{"fs:*"}, nil))
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	ctx := context.Background()
+	authService, store := authtestutil.SetupService(t, ctx, someSecret)
+	username := userWithPolicies(t, authService, nil)
+
+	now := time.Now()
+	expiredSessionID := uuid.NewString()
+	session := &model.Session{
+		ID:         uuid.NewString(),
+		Username:   username,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+		LastUsedAt: now,
+	}
+	require.NoError(t, authService.CreateSession(ctx, session))
+	require.NoError(t, authService.CreateSession(ctx, &model.Session{
+		ID:        expiredSessionID,
+		Username:  username,
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	}))
+	require.NoError(t, authService.UseSession(ctx, session.ID, "10.0.0.1"))
+	// sessions created before they were tracked are accepted
+	require.NoError(t, authService.UseSession(ctx, uuid.NewString(), "10.0.0.1"))
+
+	sessions, _, err := authService.ListSessions(ctx, &model.PaginationParams{Amount: 100})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "expired sessions are not listed")
+	require.Equal(t, session.ID, sessions[0].ID)
+	require.Equal(t, "10.0.0.1", sessions[0].SourceIP)
+
+	require.NoError(t, authService.DeleteExpiredSessions(ctx))
+	_, err = store.Get(ctx, []byte(model.PartitionKey), model.SessionPath(expiredSessionID))
+	require.ErrorIs(t, err, kv.ErrNotFound, "expired session deleted")
+	_, err = store.Get(ctx, []byte(model.PartitionKey), model.SessionPath(session.ID))
+	require.NoError(t, err, "unexpired session kept")
+
+	require.ErrorIs(t, authService.RevokeSession(ctx, uuid.NewString()), auth.ErrNotFound)
+	require.NoError(t, authService.RevokeSession(ctx, session.ID))
+	require.ErrorIs(t, authService.UseSession(ctx, session.ID, "10.0.0.1"), auth.ErrInvalidToken)
+	sessions, _, err = authService.ListSessions(ctx, &model.PaginationParams{Amount: 100})
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestAuthService_RevokeCredentials(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	cacheParams := authparams.ServiceCache{
+		Enabled: true,
+		Size:    1024,
+		TTL:     time.Hour,
+	}
+	// credentials stay in the cache of this service after another service revokes them
+	cachedService := auth.NewAuthService(kvStore, crypt.NewSecretStore(someSecret), cacheParams, logging.ContextUnavailable())
+	authService := auth.NewAuthService(kvStore, crypt.NewSecretStore(someSecret), cacheParams, logging.ContextUnavailable())
+	username := userWithPolicies(t, authService, nil)
+
+	used, err := authService.CreateCredentials(ctx, username)
+	require.NoError(t, err)
+	unused, err := authService.CreateCredentials(ctx, username)
+	require.NoError(t, err)
+	require.NoError(t, authService.RecordCredentialsUse(ctx, used.AccessKeyID, "10.0.0.2"))
+	// access keys of external authenticators are not recorded
+	require.NoError(t, authService.RecordCredentialsUse(ctx, "AKIAJNOSUCHKEY", "10.0.0.2"))
+
+	activities, _, err := authService.ListCredentialsActivity(ctx, &model.PaginationParams{Amount: 100})
+	require.NoError(t, err)
+	require.Len(t, activities, 2)
+	for _, activity := range activities {
+		require.Equal(t, username, activity.Username)
+		switch activity.AccessKeyID {
+		case used.AccessKeyID:
+			require.False(t, activity.LastUsedAt.IsZero())
+			require.Equal(t, "10.0.0.2", activity.SourceIP)
+		case unused.AccessKeyID:
+			require.True(t, activity.LastUsedAt.IsZero())
+		default:
+			t.Fatalf("unexpected credentials %s", activity.AccessKeyID)
+		}
+	}
+
+	_, err = cachedService.GetCredentials(ctx, used.AccessKeyID)
+	require.NoError(t, err)
+	require.ErrorIs(t, authService.RevokeCredentials(ctx, "AKIAJNOSUCHKEY"), auth.ErrNotFound)
+	require.NoError(t, authService.RevokeCredentials(ctx, used.AccessKeyID))
+	_, err = authService.GetCredentials(ctx, used.AccessKeyID)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	_, err = cachedService.GetCredentials(ctx, used.AccessKeyID)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+	_, err = cachedService.GetCredentials(ctx, unused.AccessKeyID)
+	require.NoError(t, err)
+}
+
 func TestACL(t *testing.T) {
 	hierarchy := []model.ACLPermission{acl.ReadPermission, acl.WritePermission, acl.SuperPermission, acl.AdminPermission}
 
@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// lastUsedResolution limits how often using a session or credentials updates its last use
+	lastUsedResolution = time.Minute
+	lastUsedCacheSize  = 10000
+)
+
+// SessionManager tracks the login sessions and the use of access key credentials, and
+// revokes them.  A revoked session or access key is rejected by all lakeFS instances at most
+// the revocation TTL of the auth cache after it was revoked.
+type SessionManager interface {
+	// CreateSession records a new login session
+	CreateSession(ctx context.Context, session *model.Session) error
+	// UseSession updates the last use of a session, and returns ErrInvalidToken if it was
+	// revoked.  Sessions created before they were tracked are accepted.
+	UseSession(ctx context.Context, sessionID, sourceIP string) error
+	// ListSessions lists the unexpired login sessions of all users
+	ListSessions(ctx context.Context, params *model.PaginationParams) ([]*model.Session, *model.Paginator, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	// DeleteExpiredSessions deletes the records of expired login sessions
+	DeleteExpiredSessions(ctx context.Context) error
+
+	// RecordCredentialsUse updates the last use of access key credentials
+	RecordCredentialsUse(ctx context.Context, accessKeyID, sourceIP string) error
+	// ListCredentialsActivity lists the access key credentials of all users with their last use
+	ListCredentialsActivity(ctx context.Context, params *model.PaginationParams) ([]*model.CredentialsActivity, *model.Paginator, error)
+	// RevokeCredentials deletes access key credentials regardless of the user that owns them
+	RevokeCredentials(ctx context.Context, accessKeyID string) error
+}
+
+var untrackedSessionsWarning sync.Once
+
+// SessionManagerOf returns svc as a SessionManager.  Services that do not track sessions, such as
+// the external auth API, neither record the use of credentials nor revoke login sessions: callers
+// skip both, and the first call logs it.
+func SessionManagerOf(ctx context.Context, svc any) (SessionManager, bool) {
+	sessionManager, ok := svc.(SessionManager)
+	if !ok {
+		untrackedSessionsWarning.Do(func() {
+			logging.FromContext(ctx).Warn("Auth service does not track sessions, credentials use is not recorded and login sessions are not revocable")
+		})
+	}
+	return sessionManager, ok
+}
+
+// revocationCache holds the unexpired revocations of all lakeFS instances, reloading them at
+// most once every ttl.  It lets instances reject revoked credentials that are still held in
+// their credentials cache.
+type revocationCache struct {
+	store kv.Store
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	revoked  map[string]time.Time
+}
+
+func newRevocationCache(store kv.Store, ttl time.Duration) *revocationCache {
+	return &revocationCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// IsRevoked returns true if there is an unexpired revocation stored under key
+func (c *revocationCache) IsRevoked(ctx context.Context, key []byte) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if c.revoked == nil || now.Sub(c.loadedAt) >= c.ttl {
+		if err := c.load(ctx, now); err != nil {
+			return false, err
+		}
+	}
+	expiresAt, ok := c.revoked[string(key)]
+	return ok && now.Before(expiresAt), nil
+}
+
+// Add makes a revocation stored by this instance effective immediately
+func (c *revocationCache) Add(key []byte, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.revoked != nil {
+		c.revoked[string(key)] = expiresAt
+	}
+}
+
+func (c *revocationCache) load(ctx context.Context, now time.Time) error {
+	it, err := kv.NewPrimaryIterator(ctx, c.store, (&model.RevocationData{}).ProtoReflect().Type(), model.PartitionKey, model.RevokedPrefixPath(), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return fmt.Errorf("scan revocations: %w", err)
+	}
+	defer it.Close()
+	revoked := make(map[string]time.Time)
+	var expired [][]byte
+	for it.Next() {
+		entry := it.Entry()
+		expiresAt := entry.Value.(*model.RevocationData).ExpiresAt.AsTime()
+		if now.After(expiresAt) {
+			expired = append(expired, entry.Key)
+			continue
+		}
+		revoked[string(entry.Key)] = expiresAt
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("scan revocations: %w", err)
+	}
+	// expired revocations are no longer needed, any instance may delete them
+	for _, key := range expired {
+		_ = c.store.Delete(ctx, []byte(model.PartitionKey), key)
+	}
+	c.revoked = revoked
+	c.loadedAt = now
+	return nil
+}
+
+func newLastUsedCache() cache.Cache {
+	return cache.NewCache(lastUsedCacheSize, lastUsedResolution, cache.NewJitterFn(0))
+}
+
+// revoke stores a revocation of id under key that expires at expiresAt
+func (s *AuthService) revoke(ctx context.Context, key []byte, id string, expiresAt time.Time) error {
+	err := kv.SetMsg(ctx, s.store, model.PartitionKey, key, &model.RevocationData{
+		Id:        id,
+		ExpiresAt: timestamppb.New(expiresAt),
+	})
+	if err != nil {
+		return fmt.Errorf("save revocation (key %s): %w", key, err)
+	}
+	s.revocations.Add(key, expiresAt)
+	return nil
+}
+
+func (s *AuthService) CreateSession(ctx context.Context, session *model.Session) error {
+	sessionPath := model.SessionPath(session.ID)
+	err := kv.SetMsgIf(ctx, s.store, model.PartitionKey, sessionPath, model.ProtoFromSession(session), nil)
+	if err != nil {
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			err = ErrAlreadyExists
+		}
+		return fmt.Errorf("save session (sessionKey %s): %w", sessionPath, err)
+	}
+	return nil
+}
+
+func (s *AuthService) UseSession(ctx context.Context, sessionID, sourceIP string) error {
+	revoked, err := s.revocations.IsRevoked(ctx, model.RevokedSessionPath(sessionID))
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrInvalidToken
+	}
+	sessionPath := model.SessionPath(sessionID)
+	_, err = s.lastUsed.GetOrSet(string(sessionPath), func() (interface{}, error) {
+		m := model.SessionData{}
+		pred, err := kv.GetMsg(ctx, s.store, model.PartitionKey, sessionPath, &m)
+		if errors.Is(err, kv.ErrNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", sessionID, err)
+		}
+		m.LastUsedAt = timestamppb.Now()
+		m.SourceIp = sourceIP
+		// a concurrent request already updated the session, or revoked it
+		err = kv.SetMsgIf(ctx, s.store, model.PartitionKey, sessionPath, &m, pred)
+		if err != nil && !errors.Is(err, kv.ErrPredicateFailed) {
+			return nil, fmt.Errorf("update session last used: %w", err)
+		}
+		return true, nil
+	})
+	return err
+}
+
+func (s *AuthService) ListSessions(ctx context.Context, params *model.PaginationParams) ([]*model.Session, *model.Paginator, error) {
+	var session model.SessionData
+	msgs, paginator, err := s.ListKVPaged(ctx, (&session).ProtoReflect().Type(), params, model.SessionPath(params.Prefix), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	sessions := make([]*model.Session, 0, len(msgs))
+	for _, msg := range msgs {
+		m := model.SessionFromProto(msg.(*model.SessionData))
+		// expired sessions are deleted by DeleteExpiredSessions
+		if now.After(m.ExpiresAt) {
+			continue
+		}
+		sessions = append(sessions, m)
+	}
+	return sessions, paginator, nil
+}
+
+func (s *AuthService) DeleteExpiredSessions(ctx context.Context) error {
+	it, err := kv.NewPrimaryIterator(ctx, s.store, (&model.SessionData{}).ProtoReflect().Type(), model.PartitionKey, model.SessionPath(""), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return fmt.Errorf("scan sessions: %w", err)
+	}
+	defer it.Close()
+	now := time.Now()
+	for it.Next() {
+		entry := it.Entry()
+		if now.Before(entry.Value.(*model.SessionData).ExpiresAt.AsTime()) {
+			continue
+		}
+		if err := s.store.Delete(ctx, []byte(model.PartitionKey), entry.Key); err != nil {
+			return fmt.Errorf("delete expired session (sessionKey %s): %w", entry.Key, err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("scan sessions: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, sessionID string) error {
+	sessionPath := model.SessionPath(sessionID)
+	m := model.SessionData{}
+	_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, sessionPath, &m)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			err = ErrNotFound
+		}
+		return fmt.Errorf("session %s: %w", sessionID, err)
+	}
+	// the login token of the session is valid until it expires
+	if err := s.revoke(ctx, model.RevokedSessionPath(sessionID), sessionID, m.ExpiresAt.AsTime()); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, []byte(model.PartitionKey), sessionPath); err != nil {
+		return fmt.Errorf("delete session (sessionKey %s): %w", sessionPath, err)
+	}
+	return nil
+}
+
+func (s *AuthService) RecordCredentialsUse(ctx context.Context, accessKeyID, sourceIP string) error {
+	activityPath := model.CredentialsActivityPath(accessKeyID)
+	_, err := s.lastUsed.GetOrSet(string(activityPath), func() (interface{}, error) {
+		// external authenticators pass user names that are not access keys
+		if _, err := s.GetCredentials(ctx, accessKeyID); errors.Is(err, ErrNotFound) {
+			return true, nil
+		} else if err != nil {
+			return nil, err
+		}
+		err := kv.SetMsg(ctx, s.store, model.PartitionKey, activityPath, &model.CredentialsActivityData{
+			AccessKeyId: accessKeyID,
+			LastUsedAt:  timestamppb.Now(),
+			SourceIp:    sourceIP,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("save credentials activity (key %s): %w", activityPath, err)
+		}
+		return true, nil
+	})
+	return err
+}
+
+func (s *AuthService) ListCredentialsActivity(ctx context.Context, params *model.PaginationParams) ([]*model.CredentialsActivity, *model.Paginator, error) {
+	var credential model.CredentialData
+	msgs, paginator, err := s.ListKVPaged(ctx, (&credential).ProtoReflect().Type(), params, model.AllCredentialsPath(), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	activities := make([]*model.CredentialsActivity, 0, len(msgs))
+	for _, msg := range msgs {
+		c := msg.(*model.CredentialData)
+		activity := &model.CredentialsActivity{
+			AccessKeyID: c.AccessKeyId,
+			Username:    string(c.UserId),
+			IssuedDate:  c.IssuedDate.AsTime(),
+		}
+		m := model.CredentialsActivityData{}
+		_, err := kv.GetMsg(ctx, s.store, model.PartitionKey, model.CredentialsActivityPath(c.AccessKeyId), &m)
+		if err != nil && !errors.Is(err, kv.ErrNotFound) {
+			return nil, nil, fmt.Errorf("credentials activity %s: %w", c.AccessKeyId, err)
+		}
+		if err == nil {
+			activity.LastUsedAt = m.LastUsedAt.AsTime()
+			activity.SourceIP = m.SourceIp
+		}
+		activities = append(activities, activity)
+	}
+	return activities, paginator, nil
+}
+
+func (s *AuthService) RevokeCredentials(ctx context.Context, accessKeyID string) error {
+	credentials, err := s.GetCredentials(ctx, accessKeyID)
+	if err != nil {
+		return err
+	}
+	return s.DeleteCredentials(ctx, credentials.Username, accessKeyID)
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hnlq715/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/params"
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+// usageSize bounds the number of access keys tracked for usage
+const usageSize = 100_000
+
+// UsageOtherAccessKeyLabel labels the usage metrics of access keys beyond the maximal number of labeled access keys
+const UsageOtherAccessKeyLabel = "other"
+
+// usageLabelLength is the number of hex digits of the hash of an access key ID that label its usage metrics
+const usageLabelLength = 16
+
+var (
+	usageRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "access_key_requests_total",
+		Help: "The total number of requests authenticated by access keys.",
+	}, []string{"service", "access_key"})
+
+	usageErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "access_key_request_errors_total",
+		Help: "The total number of requests authenticated by access keys that failed with an error status.",
+	}, []string{"service", "access_key"})
+
+	usageBytesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "access_key_received_bytes_total",
+		Help: "The total number of request body bytes received on requests authenticated by access keys.",
+	}, []string{"service", "access_key"})
+
+	usageBytesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "access_key_sent_bytes_total",
+		Help: "The total number of response body bytes sent on requests authenticated by access keys.",
+	}, []string{"service", "access_key"})
+)
+
+// AccessKeyUsage is the usage of an access key since a lakeFS instance started tracking it
+type AccessKeyUsage struct {
+	AccessKeyID   string
+	Username      string
+	Requests      int64
+	Errors        int64
+	BytesReceived int64
+	BytesSent     int64
+	LastUsedAt    time.Time
+}
+
+// CredentialsUsage tracks requests, bytes received and sent, and error responses of requests
+// authenticated by each access key, so that heavy users of the service can be identified.
+// Usage is tracked in-memory by each lakeFS instance.  The first MaxLabeledAccessKeys access
+// keys get their own label on usage metrics, the UsageAccessKeyLabel of their ID, and the others
+// share UsageOtherAccessKeyLabel, to bound metrics cardinality.  A nil CredentialsUsage tracks
+// nothing.
+type CredentialsUsage struct {
+	params params.Usage
+
+	mu      sync.Mutex
+	entries *lru.Cache
+	labeled map[string]struct{}
+}
+
+func NewCredentialsUsage(params params.Usage) *CredentialsUsage {
+	if !params.Enabled {
+		return nil
+	}
+	entries, _ := lru.New(usageSize)
+	return &CredentialsUsage{
+		params:  params,
+		entries: entries,
+		labeled: make(map[string]struct{}),
+	}
+}
+
+// Handler returns a handler that serves the request of accessKeyID of user username by next,
+// and records its usage by service.
+func (u *CredentialsUsage) Handler(service, accessKeyID, username string, next http.Handler) http.Handler {
+	if u == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &countingReadCloser{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		mrw := httputil.NewMetricResponseWriter(w)
+		next.ServeHTTP(mrw, r)
+		u.Record(service, accessKeyID, username, mrw.StatusCode, body.n, mrw.BytesWritten)
+	})
+}
+
+// Record records a request of accessKeyID of user username to service, that received
+// bytesReceived and sent bytesSent with a response of statusCode.
+func (u *CredentialsUsage) Record(service, accessKeyID, username string, statusCode int, bytesReceived, bytesSent int64) {
+	if u == nil {
+		return
+	}
+	isError := statusCode >= http.StatusBadRequest
+
+	u.mu.Lock()
+	entry := &AccessKeyUsage{AccessKeyID: accessKeyID}
+	if v, ok := u.entries.Get(accessKeyID); ok {
+		entry = v.(*AccessKeyUsage)
+	}
+	entry.Username = username
+	entry.Requests++
+	if isError {
+		entry.Errors++
+	}
+	entry.BytesReceived += bytesReceived
+	entry.BytesSent += bytesSent
+	entry.LastUsedAt = time.Now()
+	u.entries.Add(accessKeyID, entry)
+	label := u.metricsLabel(accessKeyID)
+	u.mu.Unlock()
+
+	usageRequests.WithLabelValues(service, label).Inc()
+	if isError {
+		usageErrors.WithLabelValues(service, label).Inc()
+	}
+	usageBytesReceived.WithLabelValues(service, label).Add(float64(bytesReceived))
+	usageBytesSent.WithLabelValues(service, label).Add(float64(bytesSent))
+}
+
+// UsageAccessKeyLabel returns the access_key label of the usage metrics of accessKeyID: a prefix
+// of the hex SHA-256 of the ID, so that access key IDs are not published on the metrics endpoint
+func UsageAccessKeyLabel(accessKeyID string) string {
+	sum := sha256.Sum256([]byte(accessKeyID))
+	return hex.EncodeToString(sum[:])[:usageLabelLength]
+}
+
+// metricsLabel returns the access_key label of usage metrics of accessKeyID.  Called with mu held.
+func (u *CredentialsUsage) metricsLabel(accessKeyID string) string {
+	if _, ok := u.labeled[accessKeyID]; ok {
+		return UsageAccessKeyLabel(accessKeyID)
+	}
+	if len(u.labeled) < u.params.MaxLabeledAccessKeys {
+		u.labeled[accessKeyID] = struct{}{}
+		return UsageAccessKeyLabel(accessKeyID)
+	}
+	return UsageOtherAccessKeyLabel
+}
+
+// List returns the usage of access keys ordered by access key ID
+func (u *CredentialsUsage) List(params *model.PaginationParams) ([]*AccessKeyUsage, *model.Paginator) {
+	paginator := &model.Paginator{}
+	if u == nil {
+		return nil, paginator
+	}
+	u.mu.Lock()
+	usages := make([]*AccessKeyUsage, 0, u.entries.Len())
+	for _, key := range u.entries.Keys() {
+		v, ok := u.entries.Peek(key)
+		if !ok || key.(string) <= params.After {
+			continue
+		}
+		// copy, entries keep changing after they are returned
+		usage := *v.(*AccessKeyUsage)
+		usages = append(usages, &usage)
+	}
+	u.mu.Unlock()
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].AccessKeyID < usages[j].AccessKeyID
+	})
+	if amount := params.Amount; amount >= 0 && len(usages) > amount {
+		usages = usages[:amount]
+		if amount > 0 {
+			paginator.NextPageToken = usages[amount-1].AccessKeyID
+		}
+	}
+	paginator.Amount = len(usages)
+	return usages, paginator
+}
+
+// countingReadCloser counts the bytes read through it
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
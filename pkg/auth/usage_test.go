@@ -0,0 +1,85 @@
+package auth_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/params"
+)
+
+func TestCredentialsUsage(t *testing.T) {
+	usage := auth.NewCredentialsUsage(params.Usage{Enabled: true, MaxLabeledAccessKeys: 1})
+
+	handler := func(status int, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	serve := func(accessKeyID string, status int, requestBody, responseBody string) {
+		r := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(requestBody))
+		usage.Handler("test", accessKeyID, "user-"+accessKeyID, handler(status, responseBody)).ServeHTTP(httptest.NewRecorder(), r)
+	}
+	serve("key2", http.StatusOK, "hello", "")
+	serve("key2", http.StatusNotFound, "", "not found")
+	serve("key1", http.StatusOK, "", "world")
+	serve("key3", http.StatusInternalServerError, "", "")
+
+	usages, paginator := usage.List(&model.PaginationParams{Amount: 2})
+	require.Equal(t, "key2", paginator.NextPageToken)
+	require.Len(t, usages, 2)
+	require.Equal(t, "key1", usages[0].AccessKeyID)
+	require.Equal(t, int64(1), usages[0].Requests)
+	require.Equal(t, int64(5), usages[0].BytesSent)
+	require.Equal(t, "key2", usages[1].AccessKeyID)
+	require.Equal(t, "user-key2", usages[1].Username)
+	require.Equal(t, int64(2), usages[1].Requests)
+	require.Equal(t, int64(1), usages[1].Errors)
+	require.Equal(t, int64(5), usages[1].BytesReceived)
+	require.Equal(t, int64(9), usages[1].BytesSent)
+
+	usages, paginator = usage.List(&model.PaginationParams{After: paginator.NextPageToken, Amount: 2})
+	require.Empty(t, paginator.NextPageToken)
+	require.Len(t, usages, 1)
+	require.Equal(t, "key3", usages[0].AccessKeyID)
+	require.Equal(t, int64(1), usages[0].Errors)
+
+	// metrics are labeled by the hash of the first access key, and "other"
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	labels := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "access_key_requests_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "access_key" {
+					labels[label.GetValue()] += m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	require.Equal(t, map[string]float64{
+		auth.UsageAccessKeyLabel("key2"): 2,
+		auth.UsageOtherAccessKeyLabel:    2,
+	}, labels)
+}
+
+func TestCredentialsUsageDisabled(t *testing.T) {
+	usage := auth.NewCredentialsUsage(params.Usage{Enabled: false})
+	served := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { served = true })
+	usage.Handler("test", "key1", "user1", next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.True(t, served)
+	usages, _ := usage.List(&model.PaginationParams{Amount: 10})
+	require.Empty(t, usages)
+}
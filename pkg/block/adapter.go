@@ -127,6 +127,20 @@ type CreateMultiPartUploadOpts struct {
 // actually reported.
 type Properties struct {
 	StorageClass *string
+	// Archived is set when the object is in an archive storage class, and must be restored before it can be read
+	Archived bool
+	// RestoreOngoing is set while a restore of an archived object is in progress
+	RestoreOngoing bool
+	// RestoreExpiry is when the restored copy of an archived object expires, zero if it has no restored copy
+	RestoreExpiry time.Time
+}
+
+// RestoreOpts contains the arguments of restoring an archived object
+type RestoreOpts struct {
+	// Days is the number of days the restored copy is kept before it expires
+	Days int
+	// Tier is the retrieval tier of the restore, the default tier of the underlying storage if empty
+	Tier string
 }
 
 type Adapter interface {
@@ -143,6 +157,8 @@ type Adapter interface {
 	Exists(ctx context.Context, obj ObjectPointer) (bool, error)
 	GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error)
 	GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error)
+	// RestoreObject initiates a restore of the archived obj, readable once the restore completes
+	RestoreObject(ctx context.Context, obj ObjectPointer, opts RestoreOpts) error
 	Remove(ctx context.Context, obj ObjectPointer) error
 	Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error
 	CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error)
@@ -0,0 +1,25 @@
+package block
+
+import "context"
+
+// CostAttribution is propagated to the underlying object store on requests made with a context carrying it, so
+// storage and egress costs of a repository can be charged back. Adapters that cannot propagate it ignore it.
+type CostAttribution struct {
+	// Tags are set as cost allocation tags on objects written
+	Tags map[string]string
+	// RequesterPays sends requests as a requester, required for requester pays buckets
+	RequesterPays bool
+}
+
+type costAttributionContextKey struct{}
+
+// WithCostAttribution returns a context carrying the cost attribution, nil removes any attribution carried by ctx
+func WithCostAttribution(ctx context.Context, attribution *CostAttribution) context.Context {
+	return context.WithValue(ctx, costAttributionContextKey{}, attribution)
+}
+
+// CostAttributionFromContext returns the cost attribution carried by ctx, nil if none
+func CostAttributionFromContext(ctx context.Context) *CostAttribution {
+	attribution, _ := ctx.Value(costAttributionContextKey{}).(*CostAttribution)
+	return attribution
+}
@@ -303,6 +303,9 @@ func (a *Adapter) Download(ctx context.Context, obj block.ObjectPointer, offset,
 	if bloberror.HasCode(err, bloberror.BlobNotFound) {
 		return nil, block.ErrDataNotFound
 	}
+	if bloberror.HasCode(err, bloberror.BlobArchived) {
+		return nil, block.ErrObjectArchived
+	}
 	if err != nil {
 		a.log(ctx).WithError(err).Errorf("failed to get azure blob from container %s key %s", container, blobURL)
 		return nil, err
@@ -355,7 +358,46 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 	if err != nil {
 		return block.Properties{}, err
 	}
-	return block.Properties{StorageClass: props.AccessTier}, nil
+	return block.Properties{
+		StorageClass:   props.AccessTier,
+		Archived:       props.AccessTier != nil && *props.AccessTier == string(blob.AccessTierArchive),
+		RestoreOngoing: props.ArchiveStatus != nil,
+	}, nil
+}
+
+// RestoreObject rehydrates an archived blob to the hot tier, where it stays, so opts.Days is ignored. opts.Tier is
+// the rehydrate priority.
+func (a *Adapter) RestoreObject(ctx context.Context, obj block.ObjectPointer, opts block.RestoreOpts) error {
+	var err error
+	defer reportMetrics("RestoreObject", time.Now(), nil, &err)
+
+	qualifiedKey, err := resolveBlobURLInfo(obj)
+	if err != nil {
+		return err
+	}
+	containerClient, err := a.clientCache.NewContainerClient(qualifiedKey.StorageAccountName, qualifiedKey.ContainerName)
+	if err != nil {
+		return err
+	}
+	blobURL := containerClient.NewBlobClient(qualifiedKey.BlobURL)
+	props, err := blobURL.GetProperties(ctx, nil)
+	switch {
+	case bloberror.HasCode(err, bloberror.BlobNotFound):
+		return block.ErrDataNotFound
+	case err != nil:
+		return err
+	case props.AccessTier == nil || *props.AccessTier != string(blob.AccessTierArchive):
+		return block.ErrObjectNotArchived
+	case props.ArchiveStatus != nil:
+		return block.ErrRestoreInProgress
+	}
+
+	var setTierOptions *blob.SetTierOptions
+	if opts.Tier != "" {
+		setTierOptions = &blob.SetTierOptions{RehydratePriority: to.Ptr(blob.RehydratePriority(opts.Tier))}
+	}
+	_, err = blobURL.SetTier(ctx, blob.AccessTierHot, setTierOptions)
+	return err
 }
 
 func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/pkg/block"
 )
 
@@ -104,6 +105,7 @@ func (a *BlobWalker) Walk(ctx context.Context, storageURI *url.URL, op block.Wal
 				ETag:        extractBlobItemEtag(blobInfo),
 				Mtime:       *blobInfo.Properties.LastModified,
 				Size:        *blobInfo.Properties.ContentLength,
+				VersionID:   swag.StringValue(blobInfo.VersionID),
 			}); err != nil {
 				return err
 			}
@@ -227,6 +229,7 @@ func (a *DataLakeWalker) Walk(ctx context.Context, storageURI *url.URL, op block
 				ETag:        extractBlobItemEtag(blobInfo),
 				Mtime:       *blobInfo.Properties.LastModified,
 				Size:        *blobInfo.Properties.ContentLength,
+				VersionID:   swag.StringValue(blobInfo.VersionID),
 			}
 			if a.skipOutOfOrder && strings.Compare(prev, *blobInfo.Name) > 0 { // skip out of order
 				a.skipped = append(a.skipped, entry)
@@ -10,4 +10,7 @@ var (
 	ErrForbidden             = errors.New("forbidden")
 	ErrInvalidAddress        = errors.New("invalid address")
 	ErrInvalidNamespace      = errors.New("invalid namespace")
+	ErrObjectArchived        = errors.New("object is archived")
+	ErrObjectNotArchived     = errors.New("object is not archived")
+	ErrRestoreInProgress     = errors.New("object restore is in progress")
 )
@@ -0,0 +1,44 @@
+package block
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// MultipartETag returns the ETag S3 assigns to an object completed from parts: the MD5 of the concatenated MD5s of
+// the parts followed by the number of parts. It returns false if a part ETag is not an MD5, as some block adapters
+// use other part ETags.
+func MultipartETag(parts []MultipartPart) (string, bool) {
+	if len(parts) == 0 {
+		return "", false
+	}
+	h := md5.New() //nolint:gosec
+	for _, p := range parts {
+		partMD5, err := hex.DecodeString(strings.Trim(p.ETag, `" `))
+		if err != nil || len(partMD5) != md5.Size {
+			return "", false
+		}
+		_, _ = h.Write(partMD5)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts)), true
+}
+
+// MultipartETagPartsCount returns the number of parts of an object by its ETag, if it is a multipart ETag as
+// returned by MultipartETag
+func MultipartETagPartsCount(etag string) (int, bool) {
+	etag = strings.Trim(etag, `" `)
+	checksum, count, ok := strings.Cut(etag, "-")
+	if !ok || len(checksum) != 2*md5.Size {
+		return 0, false
+	}
+	if _, err := hex.DecodeString(checksum); err != nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,58 @@
+package block_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+const PartsNo = 30
+
+func TestMultipartETag(t *testing.T) {
+	var base [16]byte
+	b := base[:]
+	parts := make([]block.MultipartPart, PartsNo)
+	for i := 0; i < PartsNo; i++ {
+		for j := 0; j < len(b); j++ {
+			b[j] = byte(32 + i + j)
+		}
+		parts[i].PartNumber = i + 1
+		parts[i].ETag = hex.EncodeToString(b)
+	}
+	// part ETags may be quoted
+	parts[0].ETag = `"` + parts[0].ETag + `"`
+	etag, ok := block.MultipartETag(parts)
+	if !ok || etag != "9cae1a3b7e97542c261cf2e1b50ba482-30" {
+		t.Fatalf("ETag value '%s' (ok=%t) not as expected", etag, ok)
+	}
+
+	// part ETags of adapters that do not use MD5
+	parts[1].ETag = "CJiG6/Tw0/0CEAE="
+	if etag, ok := block.MultipartETag(parts); ok {
+		t.Fatalf("ETag value '%s' of non MD5 parts, expected none", etag)
+	}
+}
+
+func TestMultipartETagPartsCount(t *testing.T) {
+	tt := []struct {
+		etag          string
+		expectedCount int
+		expectedOK    bool
+	}{
+		{etag: "9cae1a3b7e97542c261cf2e1b50ba482-30", expectedCount: 30, expectedOK: true},
+		{etag: `"9cae1a3b7e97542c261cf2e1b50ba482-2"`, expectedCount: 2, expectedOK: true},
+		{etag: "9cae1a3b7e97542c261cf2e1b50ba482"},
+		{etag: "9cae1a3b7e97542c261cf2e1b50ba482-0"},
+		{etag: "9cae1a3b7e97542c261cf2e1b50ba482-x"},
+		{etag: "CJiG6/Tw0/0CEAE=-3"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.etag, func(t *testing.T) {
+			count, ok := block.MultipartETagPartsCount(tc.etag)
+			if count != tc.expectedCount || ok != tc.expectedOK {
+				t.Fatalf("MultipartETagPartsCount() = %d, %t, expected %d, %t", count, ok, tc.expectedCount, tc.expectedOK)
+			}
+		})
+	}
+}
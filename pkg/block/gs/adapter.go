@@ -213,6 +213,11 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 	return props, nil
 }
 
+func (a *Adapter) RestoreObject(_ context.Context, _ block.ObjectPointer, _ block.RestoreOpts) error {
+	// objects in the archive storage class are readable without restoring them first
+	return block.ErrObjectNotArchived
+}
+
 func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
 	var err error
 	defer reportMetrics("Remove", time.Now(), nil, &err)
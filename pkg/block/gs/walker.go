@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -60,6 +61,7 @@ func (w *GCSWalker) Walk(ctx context.Context, storageURI *url.URL, op block.Walk
 			ETag:        hex.EncodeToString(attrs.MD5),
 			Mtime:       attrs.Updated,
 			Size:        attrs.Size,
+			VersionID:   strconv.FormatInt(attrs.Generation, 10),
 		}); err != nil {
 			return err
 		}
@@ -2,7 +2,6 @@ package local
 
 import (
 	"context"
-	"crypto/md5" //nolint:gosec
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -13,7 +12,6 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +34,7 @@ var (
 	ErrPathNotWritable       = errors.New("path provided is not writable")
 	ErrInvalidUploadIDFormat = errors.New("invalid upload id format")
 	ErrBadPath               = errors.New("bad path traversal blocked")
+	ErrInvalidPartETag       = errors.New("invalid part ETag")
 )
 
 type QualifiedKey struct {
@@ -169,6 +168,10 @@ func (l *Adapter) Put(_ context.Context, obj block.ObjectPointer, _ int64, reade
 	return err
 }
 
+func (l *Adapter) RestoreObject(_ context.Context, _ block.ObjectPointer, _ block.RestoreOpts) error {
+	return block.ErrObjectNotArchived
+}
+
 func (l *Adapter) Remove(_ context.Context, obj block.ObjectPointer) error {
 	p, err := l.extractParamsFromObj(obj)
 	if err != nil {
@@ -420,7 +423,10 @@ func (l *Adapter) CompleteMultiPartUpload(_ context.Context, obj block.ObjectPoi
 	if err := isValidUploadID(uploadID); err != nil {
 		return nil, err
 	}
-	etag := computeETag(multipartList.Part) + "-" + strconv.Itoa(len(multipartList.Part))
+	etag, ok := block.MultipartETag(multipartList.Part)
+	if !ok {
+		return nil, fmt.Errorf("multipart upload %s: %w", uploadID, ErrInvalidPartETag)
+	}
 	partFiles, err := l.getPartFiles(uploadID, obj)
 	if err != nil {
 		return nil, fmt.Errorf("part files not found for %s: %w", uploadID, err)
@@ -438,19 +444,6 @@ func (l *Adapter) CompleteMultiPartUpload(_ context.Context, obj block.ObjectPoi
 	}, nil
 }
 
-func computeETag(parts []block.MultipartPart) string {
-	var etagHex []string
-	for _, p := range parts {
-		e := strings.Trim(p.ETag, `"`)
-		etagHex = append(etagHex, e)
-	}
-	s := strings.Join(etagHex, "")
-	b, _ := hex.DecodeString(s)
-	md5res := md5.Sum(b) //nolint:gosec
-	csm := hex.EncodeToString(md5res[:])
-	return csm
-}
-
 func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, filenames []string) (int64, error) {
 	p, err := l.extractParamsFromObj(identifier)
 	if err != nil {
@@ -40,10 +40,11 @@ func newMPU() *mpu {
 
 func (m *mpu) get() []byte {
 	buf := bytes.NewBuffer(nil)
-	keys := make([]int, len(m.parts))
-	sort.Slice(keys, func(i, j int) bool {
-		return keys[i] < keys[j]
-	})
+	keys := make([]int, 0, len(m.parts))
+	for part := range m.parts {
+		keys = append(keys, part)
+	}
+	sort.Ints(keys)
 	for _, part := range keys {
 		buf.Write(m.parts[part])
 	}
@@ -90,7 +91,7 @@ func (a *Adapter) Put(_ context.Context, obj block.ObjectPointer, _ int64, reade
 	}
 	key := getKey(obj)
 	a.data[key] = data
-	a.properties[key] = block.Properties(opts)
+	a.properties[key] = block.Properties{StorageClass: opts.StorageClass}
 	return nil
 }
 
@@ -167,6 +168,13 @@ func (a *Adapter) GetProperties(_ context.Context, obj block.ObjectPointer) (blo
 	return props, nil
 }
 
+func (a *Adapter) RestoreObject(_ context.Context, obj block.ObjectPointer, _ block.RestoreOpts) error {
+	if err := verifyObjectPointer(obj); err != nil {
+		return err
+	}
+	return block.ErrObjectNotArchived
+}
+
 func (a *Adapter) Remove(_ context.Context, obj block.ObjectPointer) error {
 	if err := verifyObjectPointer(obj); err != nil {
 		return err
@@ -193,7 +201,7 @@ func (a *Adapter) Copy(_ context.Context, sourceObj, destinationObj block.Object
 	return nil
 }
 
-func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, _ block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+func (a *Adapter) UploadCopyPart(_ context.Context, sourceObj, _ block.ObjectPointer, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
 	if err := verifyObjectPointer(sourceObj); err != nil {
 		return nil, err
 	}
@@ -203,16 +211,12 @@ func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, _ block.ObjectP
 	if !ok {
 		return nil, ErrMultiPartNotFound
 	}
-	entry, err := a.Get(ctx, sourceObj, 0)
-	if err != nil {
-		return nil, err
-	}
-	data, err := io.ReadAll(entry)
-	if err != nil {
-		return nil, err
+	data, ok := a.data[getKey(sourceObj)]
+	if !ok {
+		return nil, ErrNoDataForKey
 	}
 	h := sha256.New()
-	_, err = h.Write(data)
+	_, err := h.Write(data)
 	if err != nil {
 		return nil, err
 	}
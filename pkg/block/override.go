@@ -0,0 +1,48 @@
+package block
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// AdapterOverride overrides the global configuration of the block adapter for requests made with a context carrying
+// it, so that one installation can manage repositories whose storage namespaces are in different accounts and regions.
+// Adapters that cannot apply it ignore it.
+type AdapterOverride struct {
+	// Endpoint is the URL of the object store endpoint
+	Endpoint string
+	// UseAccelerate sends requests to the S3 Transfer Acceleration endpoint of the bucket
+	UseAccelerate bool
+	// Region is the region of the storage namespace
+	Region string
+	// Profile is the name of the shared configuration profile holding the credentials
+	Profile string
+	// ForcePathStyle addresses buckets as a path of the endpoint
+	ForcePathStyle bool
+}
+
+type adapterOverrideContextKey struct{}
+
+// WithAdapterOverride returns a context carrying the adapter override, nil removes any override carried by ctx
+func WithAdapterOverride(ctx context.Context, override *AdapterOverride) context.Context {
+	return context.WithValue(ctx, adapterOverrideContextKey{}, override)
+}
+
+// AdapterOverrideFromContext returns the adapter override carried by ctx, nil if none
+func AdapterOverrideFromContext(ctx context.Context) *AdapterOverride {
+	override, _ := ctx.Value(adapterOverrideContextKey{}).(*AdapterOverride)
+	return override
+}
+
+// OverrideEndpointAllowed returns true if endpoint is one of the allowed endpoints. Overrides may only send requests to
+// endpoints chosen by the administrator of the installation.
+func OverrideEndpointAllowed(endpoint string, allowed []string) bool {
+	normalize := func(endpoint string) string {
+		return strings.ToLower(strings.TrimSuffix(endpoint, "/"))
+	}
+	endpoint = normalize(endpoint)
+	return slices.ContainsFunc(allowed, func(a string) bool {
+		return normalize(a) == endpoint
+	})
+}
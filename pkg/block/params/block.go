@@ -60,6 +60,8 @@ type S3 struct {
 	ClientLogRetries              bool
 	ClientLogRequest              bool
 	WebIdentity                   *S3WebIdentity
+	// AllowedOverrideEndpoints are the endpoints that block adapter overrides of repositories may use
+	AllowedOverrideEndpoints []string
 }
 
 type GS struct {
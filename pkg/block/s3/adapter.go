@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/middleware"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/treeverse/lakefs/pkg/block"
@@ -323,6 +324,20 @@ func isErrNotFound(err error) bool {
 	return errors.As(err, &errNoSuchKey) || errors.As(err, &errNotFound)
 }
 
+func isErrInvalidObjectState(err error) bool {
+	var errInvalidObjectState *types.InvalidObjectState
+	return errors.As(err, &errInvalidObjectState)
+}
+
+// s3ErrorCode returns the S3 error code of err, empty if it is not an S3 API error
+func s3ErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
 func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io.ReadCloser, error) {
 	var err error
 	var sizeBytes int64
@@ -342,6 +357,9 @@ func (a *Adapter) Get(ctx context.Context, obj block.ObjectPointer, _ int64) (io
 	if isErrNotFound(err) {
 		return nil, block.ErrDataNotFound
 	}
+	if isErrInvalidObjectState(err) {
+		return nil, block.ErrObjectArchived
+	}
 	if err != nil {
 		log.WithError(err).Errorf("failed to get S3 object bucket %s key %s", qualifiedKey.GetStorageNamespace(), qualifiedKey.GetKey())
 		return nil, err
@@ -390,6 +408,7 @@ func (a *Adapter) GetPreSignedURL(ctx context.Context, obj block.ObjectPointer,
 	}
 
 	client := a.clients.Get(ctx, bucket)
+	ctx = withPresign(ctx)
 	presigner := s3.NewPresignClient(client,
 		func(options *s3.PresignOptions) {
 			options.Expires = a.preSignedExpiry
@@ -448,6 +467,7 @@ func (a *Adapter) GetPresignUploadPartURL(ctx context.Context, obj block.ObjectP
 	}
 
 	client := a.clients.Get(ctx, bucket)
+	ctx = withPresign(ctx)
 	presigner := s3.NewPresignClient(client,
 		func(options *s3.PresignOptions) {
 			options.Expires = a.preSignedExpiry
@@ -511,6 +531,9 @@ func (a *Adapter) GetRange(ctx context.Context, obj block.ObjectPointer, startPo
 	if isErrNotFound(err) {
 		return nil, block.ErrDataNotFound
 	}
+	if isErrInvalidObjectState(err) {
+		return nil, block.ErrObjectArchived
+	}
 	if err != nil {
 		log.WithError(err).WithFields(logging.Fields{
 			"start_position": startPosition,
@@ -539,9 +562,68 @@ func (a *Adapter) GetProperties(ctx context.Context, obj block.ObjectPointer) (b
 	if err != nil {
 		return block.Properties{}, err
 	}
-	return block.Properties{
+	props := block.Properties{
 		StorageClass: aws.String(string(s3Props.StorageClass)),
-	}, nil
+		Archived: s3Props.StorageClass == types.StorageClassGlacier ||
+			s3Props.StorageClass == types.StorageClassDeepArchive ||
+			s3Props.ArchiveStatus != "",
+	}
+	if s3Props.Restore != nil {
+		props.RestoreOngoing, props.RestoreExpiry = parseRestoreHeader(aws.ToString(s3Props.Restore))
+	}
+	return props, nil
+}
+
+// parseRestoreHeader parses the restore status of an archived object, returned by S3 in the x-amz-restore header as
+// 'ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"'
+func parseRestoreHeader(header string) (bool, time.Time) {
+	var (
+		ongoing bool
+		expiry  time.Time
+	)
+	for header != "" {
+		var field, value string
+		field, header, _ = strings.Cut(strings.TrimLeft(header, ", "), "=")
+		value, header, _ = strings.Cut(strings.TrimPrefix(header, `"`), `"`)
+		switch strings.TrimSpace(field) {
+		case "ongoing-request":
+			ongoing = value == "true"
+		case "expiry-date":
+			expiry, _ = time.Parse(http.TimeFormat, value)
+		}
+	}
+	return ongoing, expiry
+}
+
+func (a *Adapter) RestoreObject(ctx context.Context, obj block.ObjectPointer, opts block.RestoreOpts) error {
+	var err error
+	defer reportMetrics("RestoreObject", time.Now(), nil, &err)
+	bucket, key, _, err := a.extractParamsFromObj(obj)
+	if err != nil {
+		return err
+	}
+
+	restoreRequest := &types.RestoreRequest{
+		Days: aws.Int32(int32(opts.Days)),
+	}
+	if opts.Tier != "" {
+		restoreRequest.GlacierJobParameters = &types.GlacierJobParameters{Tier: types.Tier(opts.Tier)}
+	}
+	client := a.clients.Get(ctx, bucket)
+	_, err = client.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket:         aws.String(bucket),
+		Key:            aws.String(key),
+		RestoreRequest: restoreRequest,
+	})
+	switch {
+	case isErrNotFound(err):
+		return block.ErrDataNotFound
+	case isErrInvalidObjectState(err):
+		return block.ErrObjectNotArchived
+	case s3ErrorCode(err) == "RestoreAlreadyInProgress":
+		return block.ErrRestoreInProgress
+	}
+	return err
 }
 
 func (a *Adapter) Remove(ctx context.Context, obj block.ObjectPointer) error {
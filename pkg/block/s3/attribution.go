@@ -0,0 +1,72 @@
+package s3
+
+import (
+	"context"
+	"net/url"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+const (
+	requestPayerHeader     = "X-Amz-Request-Payer"
+	requestPayerQueryParam = "x-amz-request-payer"
+	requestPayerRequester  = "requester"
+	taggingHeader          = "X-Amz-Tagging"
+	taggingDirectiveHeader = "X-Amz-Tagging-Directive"
+)
+
+// taggedOperations are the operations that write objects, on which cost allocation tags are set
+var taggedOperations = map[string]struct{}{
+	"PutObject":             {},
+	"CreateMultipartUpload": {},
+	"CopyObject":            {},
+}
+
+type presignContextKey struct{}
+
+// withPresign marks ctx as presigning a request, which the client of the presigned URL sends
+func withPresign(ctx context.Context) context.Context {
+	return context.WithValue(ctx, presignContextKey{}, true)
+}
+
+// withCostAttribution registers a middleware propagating the cost attribution carried by the request context to S3
+func withCostAttribution() func(options *s3.Options) {
+	return s3.WithAPIOptions(func(stack *middleware.Stack) error {
+		return stack.Build.Add(middleware.BuildMiddlewareFunc("CostAttribution", costAttributionBuildMiddleware), middleware.After)
+	})
+}
+
+func costAttributionBuildMiddleware(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+	attribution := block.CostAttributionFromContext(ctx)
+	req, ok := in.Request.(*smithyhttp.Request)
+	if attribution == nil || !ok {
+		return next.HandleBuild(ctx, in)
+	}
+	presign, _ := ctx.Value(presignContextKey{}).(bool)
+	if attribution.RequesterPays {
+		if presign {
+			// signed headers must be sent by the client of a presigned URL, S3 accepts the request payer as a query
+			// parameter instead
+			query := req.URL.Query()
+			query.Set(requestPayerQueryParam, requestPayerRequester)
+			req.URL.RawQuery = query.Encode()
+		} else {
+			req.Header.Set(requestPayerHeader, requestPayerRequester)
+		}
+	}
+	if _, tagged := taggedOperations[awsmiddleware.GetOperationName(ctx)]; tagged && len(attribution.Tags) > 0 && !presign {
+		tags := url.Values{}
+		for k, v := range attribution.Tags {
+			tags.Set(k, v)
+		}
+		req.Header.Set(taggingHeader, tags.Encode())
+		if awsmiddleware.GetOperationName(ctx) == "CopyObject" {
+			req.Header.Set(taggingDirectiveHeader, "REPLACE")
+		}
+	}
+	return next.HandleBuild(ctx, in)
+}
@@ -2,42 +2,60 @@ package s3
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/params"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/stats"
 )
 
+var (
+	ErrOverrideEndpointNotAllowed = errors.New("endpoint not allowed for block adapter overrides")
+	ErrOverrideProfileRequired    = errors.New("block adapter override endpoint requires a profile")
+)
+
 type (
 	clientFactory  func(region string) *s3.Client
 	s3RegionGetter func(ctx context.Context, bucket string) (string, error)
 )
 
+// overrideClientKey identifies the client of an adapter override in a region
+type overrideClientKey struct {
+	override block.AdapterOverride
+	region   string
+}
+
 type ClientCache struct {
-	mu             sync.Mutex
-	regionClient   map[string]*s3.Client
-	bucketRegion   map[string]string
-	awsConfig      aws.Config
-	defaultClient  *s3.Client
-	clientFactory  clientFactory
-	s3RegionGetter s3RegionGetter
-	collector      stats.Collector
+	mu              sync.Mutex
+	regionClient    map[string]*s3.Client
+	bucketRegion    map[string]string
+	overrideClients map[overrideClientKey]*s3.Client
+	awsConfig       aws.Config
+	params          params.S3
+	defaultClient   *s3.Client
+	clientFactory   clientFactory
+	s3RegionGetter  s3RegionGetter
+	collector       stats.Collector
 }
 
 func NewClientCache(awsConfig aws.Config, params params.S3) *ClientCache {
-	clientFactory := newClientFactory(awsConfig, WithClientParams(params))
+	clientFactory := newClientFactory(awsConfig, WithClientParams(params), withCostAttribution())
 	defaultClient := clientFactory(awsConfig.Region)
 	clientCache := &ClientCache{
-		regionClient:  make(map[string]*s3.Client),
-		bucketRegion:  make(map[string]string),
-		awsConfig:     awsConfig,
-		defaultClient: defaultClient,
-		clientFactory: clientFactory,
-		collector:     &stats.NullCollector{},
+		regionClient:    make(map[string]*s3.Client),
+		bucketRegion:    make(map[string]string),
+		overrideClients: make(map[overrideClientKey]*s3.Client),
+		awsConfig:       awsConfig,
+		params:          params,
+		defaultClient:   defaultClient,
+		clientFactory:   clientFactory,
+		collector:       &stats.NullCollector{},
 	}
 	clientCache.DiscoverBucketRegion(true)
 	return clientCache
@@ -86,6 +104,9 @@ func (c *ClientCache) getBucketRegionDefault(_ context.Context, _ string) (strin
 }
 
 func (c *ClientCache) Get(ctx context.Context, bucket string) *s3.Client {
+	if override := block.AdapterOverrideFromContext(ctx); override != nil {
+		return c.getOverride(ctx, bucket, *override)
+	}
 	client, region := c.cachedClientByBucket(bucket)
 	if client != nil {
 		return client
@@ -124,6 +145,107 @@ func (c *ClientCache) Get(ctx context.Context, bucket string) *s3.Client {
 	return client
 }
 
+// getOverride returns the client of the adapter override for the bucket. The region of the bucket is discovered as
+// usual unless the override sets the region or an endpoint, where discovery may not be possible.
+func (c *ClientCache) getOverride(ctx context.Context, bucket string, override block.AdapterOverride) *s3.Client {
+	region := override.Region
+	if region == "" && override.Endpoint == "" {
+		if _, cachedRegion := c.cachedClientByBucket(bucket); cachedRegion != "" {
+			region = cachedRegion
+		} else {
+			region = c.refreshBucketRegion(ctx, bucket)
+		}
+	}
+	if region == "" {
+		region = c.awsConfig.Region
+	}
+	key := overrideClientKey{override: override, region: region}
+	c.mu.Lock()
+	client, ok := c.overrideClients[key]
+	c.mu.Unlock()
+	if ok {
+		return client
+	}
+
+	awsConfig := c.awsConfig
+	cacheClient := true
+	switch {
+	case override.Endpoint != "" && !block.OverrideEndpointAllowed(override.Endpoint, c.params.AllowedOverrideEndpoints):
+		// the endpoint may have been removed from the configuration after the override was set
+		logging.FromContext(ctx).
+			WithField("endpoint", override.Endpoint).
+			Error("Endpoint of block adapter override is not allowed")
+		awsConfig = failingCredentialsConfig(awsConfig, fmt.Errorf("endpoint %s: %w", override.Endpoint, ErrOverrideEndpointNotAllowed))
+	case override.Endpoint != "" && override.Profile == "":
+		// never send the global credentials to another endpoint
+		awsConfig = failingCredentialsConfig(awsConfig, fmt.Errorf("endpoint %s: %w", override.Endpoint, ErrOverrideProfileRequired))
+	case override.Profile != "":
+		var err error
+		awsConfig, err = c.loadProfileConfig(ctx, override.Profile)
+		if err != nil {
+			logging.FromContext(ctx).
+				WithError(err).
+				WithField("profile", override.Profile).
+				Error("Failed to load profile of block adapter override")
+			// fail requests rather than sending them with the credentials of another account, and retry loading
+			// the profile on the next request
+			awsConfig = failingCredentialsConfig(c.awsConfig, fmt.Errorf("profile %s: %w", override.Profile, err))
+			cacheClient = false
+		}
+	}
+	logging.FromContext(ctx).WithFields(logging.Fields{
+		"region":     region,
+		"endpoint":   override.Endpoint,
+		"accelerate": override.UseAccelerate,
+		"profile":    override.Profile,
+	}).Debug("creating client for block adapter override")
+	client = s3.NewFromConfig(awsConfig, WithClientParams(c.params), withCostAttribution(), func(options *s3.Options) {
+		options.Region = region
+		if override.Endpoint != "" {
+			options.BaseEndpoint = aws.String(override.Endpoint)
+		}
+		if override.ForcePathStyle {
+			options.UsePathStyle = true
+		}
+		if override.UseAccelerate {
+			// acceleration uses the AWS accelerate endpoint of the bucket, addressed by its host name
+			options.UseAccelerate = true
+			options.BaseEndpoint = nil
+			options.UsePathStyle = false
+		}
+	})
+	if !cacheClient {
+		return client
+	}
+
+	c.mu.Lock()
+	if existingClient, existingFound := c.overrideClients[key]; existingFound {
+		client = existingClient
+	} else {
+		c.overrideClients[key] = client
+	}
+	c.mu.Unlock()
+	return client
+}
+
+// failingCredentialsConfig returns a copy of awsConfig whose credentials fail with err, so that its requests fail
+// rather than being sent with the credentials of awsConfig
+func failingCredentialsConfig(awsConfig aws.Config, err error) aws.Config {
+	awsConfig = awsConfig.Copy()
+	awsConfig.Credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, err
+	})
+	return awsConfig
+}
+
+// loadProfileConfig loads the AWS configuration of the global parameters with the credentials of profile
+func (c *ClientCache) loadProfileConfig(ctx context.Context, profile string) (aws.Config, error) {
+	p := c.params
+	p.Profile = profile
+	p.Credentials = params.S3Credentials{}
+	return LoadConfig(ctx, p)
+}
+
 func (c *ClientCache) cachedClientByBucket(bucket string) (*s3.Client, string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -3,11 +3,15 @@ package s3_test
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/params"
 	"github.com/treeverse/lakefs/pkg/block/s3"
 	"github.com/treeverse/lakefs/pkg/testutil"
@@ -111,3 +115,98 @@ func TestClientCache(t *testing.T) {
 		})
 	}
 }
+
+func TestClientCacheOverride(t *testing.T) {
+	const defaultRegion = "us-west-2"
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(defaultRegion))
+	testutil.Must(t, err)
+
+	const endpoint = "https://storage.example.com"
+	c := s3.NewClientCache(cfg, params.S3{AllowedOverrideEndpoints: []string{endpoint}})
+	c.SetS3RegionGetter(func(ctx context.Context, bucket string) (string, error) {
+		return "eu-west-1", nil
+	})
+	defaultClient := c.Get(ctx, "bucket")
+
+	accelerateCtx := block.WithAdapterOverride(ctx, &block.AdapterOverride{UseAccelerate: true})
+	accelerateClient := c.Get(accelerateCtx, "bucket")
+	if accelerateClient == defaultClient {
+		t.Fatal("override used the default client")
+	}
+	if c.Get(accelerateCtx, "bucket") != accelerateClient {
+		t.Error("override client created more than once")
+	}
+	options := accelerateClient.Options()
+	if !options.UseAccelerate {
+		t.Error("override client does not use acceleration")
+	}
+	if options.Region != "eu-west-1" {
+		t.Errorf("override client region = %s, expected the bucket region eu-west-1", options.Region)
+	}
+
+	endpointCtx := block.WithAdapterOverride(ctx, &block.AdapterOverride{Endpoint: endpoint, ForcePathStyle: true})
+	options = c.Get(endpointCtx, "bucket").Options()
+	// an endpoint without a profile must not receive the global credentials
+	if _, err := options.Credentials.Retrieve(ctx); !errors.Is(err, s3.ErrOverrideProfileRequired) {
+		t.Errorf("override client credentials error = %v, expected %s", err, s3.ErrOverrideProfileRequired)
+	}
+	if options.BaseEndpoint == nil || *options.BaseEndpoint != endpoint {
+		t.Errorf("override client endpoint = %v, expected %s", options.BaseEndpoint, endpoint)
+	}
+	if !options.UsePathStyle {
+		t.Error("override client does not use path style")
+	}
+	if options.Region != defaultRegion {
+		t.Errorf("override client region = %s, expected the default region %s", options.Region, defaultRegion)
+	}
+
+	regionCtx := block.WithAdapterOverride(ctx, &block.AdapterOverride{Region: "ap-south-1"})
+	if region := c.Get(regionCtx, "bucket").Options().Region; region != "ap-south-1" {
+		t.Errorf("override client region = %s, expected ap-south-1", region)
+	}
+}
+
+func TestClientCacheOverrideEndpointCredentials(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	err := os.WriteFile(configFile, []byte("[profile other-account]\naws_access_key_id = AKIAOTHERACCOUNT\naws_secret_access_key = secret\n"), 0o600)
+	testutil.Must(t, err)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-west-2"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("AKIAGLOBAL", "global-secret", "")))
+	testutil.Must(t, err)
+	const endpoint = "https://storage.example.com"
+	c := s3.NewClientCache(cfg, params.S3{AllowedOverrideEndpoints: []string{endpoint + "/"}})
+	c.DiscoverBucketRegion(false)
+
+	tests := []struct {
+		name              string
+		override          block.AdapterOverride
+		expectedAccessKey string
+		expectedErr       error
+	}{
+		{name: "allowed_with_profile", override: block.AdapterOverride{Endpoint: endpoint, Profile: "other-account"}, expectedAccessKey: "AKIAOTHERACCOUNT"},
+		{name: "allowed_without_profile", override: block.AdapterOverride{Endpoint: endpoint}, expectedErr: s3.ErrOverrideProfileRequired},
+		{name: "not_allowed", override: block.AdapterOverride{Endpoint: "https://attacker.example.com", Profile: "other-account"}, expectedErr: s3.ErrOverrideEndpointNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrideCtx := block.WithAdapterOverride(ctx, &tt.override)
+			creds, err := c.Get(overrideCtx, "bucket").Options().Credentials.Retrieve(ctx)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("credentials error = %v, expected %s", err, tt.expectedErr)
+				}
+				return
+			}
+			testutil.Must(t, err)
+			if creds.AccessKeyID != tt.expectedAccessKey {
+				t.Errorf("credentials access key = %s, expected %s", creds.AccessKeyID, tt.expectedAccessKey)
+			}
+		})
+	}
+}
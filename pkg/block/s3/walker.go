@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/treeverse/lakefs/pkg/block"
 )
 
@@ -45,6 +46,9 @@ func (s *Walker) Walk(ctx context.Context, storageURI *url.URL, op block.WalkOpt
 		basePath = prefix[:idx+1]
 	}
 	bucket := storageURI.Host
+	if s.versioned(ctx, bucket) {
+		return s.walkVersions(ctx, bucket, prefix, basePath, op, walkFn)
+	}
 	for {
 		result, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 			Bucket:            aws.String(bucket),
@@ -88,6 +92,68 @@ func (s *Walker) Walk(ctx context.Context, storageURI *url.URL, op block.WalkOpt
 	return nil
 }
 
+// versioned returns true if versioning of bucket was ever enabled, so its objects have version IDs. It returns false
+// if the versioning state of bucket cannot be read, so the walk lists objects without their versions.
+func (s *Walker) versioned(ctx context.Context, bucket string) bool {
+	versioning, err := s.client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return false
+	}
+	return versioning.Status == types.BucketVersioningStatusEnabled || versioning.Status == types.BucketVersioningStatusSuspended
+}
+
+// walkVersions walks the current versions of the objects of a versioned bucket, setting their version IDs. Objects
+// whose current version is a delete marker are skipped.
+func (s *Walker) walkVersions(ctx context.Context, bucket, prefix, basePath string, op block.WalkOptions, walkFn func(e block.ObjectStoreEntry) error) error {
+	const maxKeys = 1000
+	keyMarker := aws.String(op.After)
+	var versionIDMarker *string
+	for {
+		result, err := s.client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+			Bucket:          aws.String(bucket),
+			KeyMarker:       keyMarker,
+			MaxKeys:         aws.Int32(maxKeys),
+			Prefix:          aws.String(prefix),
+			VersionIdMarker: versionIDMarker,
+		})
+		if err != nil {
+			return err
+		}
+		for _, record := range result.Versions {
+			if !aws.ToBool(record.IsLatest) {
+				continue
+			}
+			key := aws.ToString(record.Key)
+			ent := block.ObjectStoreEntry{
+				FullKey:     key,
+				RelativeKey: strings.TrimPrefix(key, basePath),
+				Address:     fmt.Sprintf("s3://%s/%s", bucket, key),
+				ETag:        strings.Trim(aws.ToString(record.ETag), "\""),
+				Mtime:       aws.ToTime(record.LastModified),
+				Size:        aws.ToInt64(record.Size),
+				VersionID:   aws.ToString(record.VersionId),
+			}
+			s.mark.LastKey = key
+			err := walkFn(ent)
+			if err != nil {
+				return err
+			}
+		}
+		if !aws.ToBool(result.IsTruncated) {
+			break
+		}
+		keyMarker = result.NextKeyMarker
+		versionIDMarker = result.NextVersionIdMarker
+	}
+	s.mark = block.Mark{
+		LastKey: "",
+		HasMore: false,
+	}
+	return nil
+}
+
 func (s *Walker) Marker() block.Mark {
 	return s.mark
 }
@@ -60,6 +60,10 @@ func (a *Adapter) GetProperties(_ context.Context, _ block.ObjectPointer) (block
 	return block.Properties{}, nil
 }
 
+func (a *Adapter) RestoreObject(_ context.Context, _ block.ObjectPointer, _ block.RestoreOpts) error {
+	return block.ErrObjectNotArchived
+}
+
 func (a *Adapter) Remove(_ context.Context, _ block.ObjectPointer) error {
 	return nil
 }
@@ -27,6 +27,8 @@ type ObjectStoreEntry struct {
 	Mtime time.Time `json:"mtime,omitempty"`
 	// Size in bytes
 	Size int64 `json:"size"`
+	// VersionID is the version of the entry, empty if the object store listing does not provide it
+	VersionID string `json:"version_id,omitempty"`
 }
 
 type WalkOptions struct {
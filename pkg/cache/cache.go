@@ -18,6 +18,8 @@ type SetFnWithExpiry func() (v interface{}, expiry time.Duration, err error)
 type Cache interface {
 	GetOrSet(k interface{}, setFn SetFn) (v interface{}, err error)
 	GetOrSetWithExpiry(k interface{}, setFn SetFnWithExpiry) (v interface{}, err error)
+	// Remove removes k from the cache, the next get computes its value again
+	Remove(k interface{})
 }
 
 type GetSetCache struct {
@@ -62,6 +64,15 @@ func (c *GetSetCache) GetOrSetWithExpiry(k interface{}, setFn SetFnWithExpiry) (
 	})
 }
 
+func (c *GetSetCache) Remove(k interface{}) {
+	c.lru.Remove(k)
+}
+
+// Purge removes all keys from the cache
+func (c *GetSetCache) Purge() {
+	c.lru.Purge()
+}
+
 func NewJitterFn(jitter time.Duration) JitterFn {
 	if jitter <= 0 {
 		return func() time.Duration {
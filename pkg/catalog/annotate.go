@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// AnnotatedEntry is an entry listed under a prefix, with the latest commit that modified it
+type AnnotatedEntry struct {
+	DBEntry
+	// Commit is the latest non-merge commit that modified the entry, or any object under a common prefix entry.
+	// It is nil when no such commit is found.
+	Commit *CommitLog
+}
+
+// Annotate lists the entries under prefix of reference, like ListEntries, and annotates each with the latest commit
+// that modified it. All entries are annotated by a single walk of the commit log, diffing each non-merge commit with
+// its parent, which ends as soon as every entry is annotated.
+func (c *Catalog) Annotate(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int, firstParent bool) ([]*AnnotatedEntry, bool, error) {
+	entries, hasMore, err := c.ListEntries(ctx, repositoryID, reference, prefix, after, delimiter, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	annotated := make([]*AnnotatedEntry, len(entries))
+	pending := make(map[string]*AnnotatedEntry, len(entries))
+	for i, entry := range entries {
+		annotated[i] = &AnnotatedEntry{DBEntry: *entry}
+		pending[entry.Path] = annotated[i]
+	}
+	if len(pending) == 0 {
+		return annotated, hasMore, nil
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(reference))
+	if err != nil {
+		return nil, false, err
+	}
+	it, err := c.Store.Log(ctx, repository, commitID, firstParent, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	for len(pending) > 0 && it.Next() {
+		commitRecord := it.Value()
+		if len(commitRecord.Parents) != NumberOfParentsOfNonMergeCommit {
+			continue
+		}
+		modified, err := c.annotationPathsInCommit(ctx, repository, commitRecord, pending, prefix, delimiter)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(modified) == 0 {
+			continue
+		}
+		commitLog := CommitRecordToLog(commitRecord)
+		for _, p := range modified {
+			pending[p].Commit = commitLog
+			delete(pending, p)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return annotated, hasMore, nil
+}
+
+// annotationPathsInCommit returns the pending entry paths modified by commit. The diff of the commit with its parent
+// is only read around the pending paths, seeking past any key that does not belong to one of them.
+func (c *Catalog) annotationPathsInCommit(ctx context.Context, repository *graveler.RepositoryRecord, commit *graveler.CommitRecord, pending map[string]*AnnotatedEntry, prefix, delimiter string) ([]string, error) {
+	paths := make([]string, 0, len(pending))
+	for p := range pending {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	diffIt, err := c.Store.Diff(ctx, repository, graveler.Ref(commit.Parents[0]), graveler.Ref(commit.CommitID))
+	if err != nil {
+		return nil, err
+	}
+	defer diffIt.Close()
+
+	var modified []string
+	diffIt.SeekGE(graveler.Key(paths[0]))
+	for diffIt.Next() {
+		key := diffIt.Value().Key.String()
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		entryPath := annotationEntryPath(key, prefix, delimiter)
+		// entry paths are ordered like the keys they hold, skip to the first pending path past this one
+		next := sort.SearchStrings(paths, entryPath)
+		if next < len(paths) && paths[next] == entryPath {
+			modified = append(modified, entryPath)
+			next++
+		}
+		if next == len(paths) {
+			break
+		}
+		diffIt.SeekGE(graveler.Key(paths[next]))
+	}
+	if err := diffIt.Err(); err != nil {
+		return nil, err
+	}
+	return modified, nil
+}
+
+// annotationEntryPath returns the path of the entry listed under prefix that holds key: the common prefix up to the
+// first delimiter after prefix, or key itself
+func annotationEntryPath(key, prefix, delimiter string) string {
+	if delimiter == "" {
+		return key
+	}
+	rest := key[len(prefix):]
+	if i := strings.Index(rest, delimiter); i >= 0 {
+		return prefix + rest[:i+len(delimiter)]
+	}
+	return key
+}
@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// MinAppendCopySize is the size of the smallest object whose data an append copies on the underlying storage, as the
+// first part of a multipart upload. Multipart uploads require all parts but the last to be at least this large, so
+// the data of smaller objects is rewritten.
+const MinAppendCopySize = 5 * 1024 * 1024
+
+// MaxAppendCopyPartSize is the size of the largest part an append copies on the underlying storage. The data of
+// larger objects is copied in several parts.
+const MaxAppendCopyPartSize = 5 * 1024 * 1024 * 1024
+
+// AppendParams control an append to an object
+type AppendParams struct {
+	// WriteOffset set makes the append fail with ErrWriteOffsetMismatch unless it is the size of the object
+	WriteOffset *int64
+	// ContentType is the content type of an object created by the append
+	ContentType string
+}
+
+// AppendEntry appends size bytes of reader, -1 if unknown, to the object at path on branch, creating the object if
+// it does not exist. The appended object is a new version made of the data of the object followed by the appended
+// data. The append fails with graveler.ErrPreconditionFailed if the object changes while appending.
+func (c *Catalog) AppendEntry(ctx context.Context, repositoryID string, branch string, path string, reader io.Reader, size int64, params AppendParams) (*DBEntry, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the branch exists before reading the object, a missing object is created by the append
+	if _, err := c.Store.GetBranch(ctx, repository, branchID); err != nil {
+		return nil, err
+	}
+
+	// the object is replaced only if it did not change since it was read
+	var (
+		current *DBEntry
+		setOpt  graveler.SetOptionsFunc
+	)
+	value, err := c.Store.Get(ctx, repository, graveler.Ref(branchID), graveler.Key(path))
+	switch {
+	case errors.Is(err, graveler.ErrNotFound):
+		setOpt = graveler.WithIfAbsent(true)
+	case err != nil:
+		return nil, err
+	default:
+		ent, err := ValueToEntry(value)
+		if err != nil {
+			return nil, err
+		}
+		entry := newCatalogEntryFromEntry(false, path, ent)
+		current = &entry
+		setOpt = graveler.WithIfMatch(value.Identity)
+	}
+	var currentSize int64
+	if current != nil {
+		currentSize = current.Size
+	}
+	if params.WriteOffset != nil && *params.WriteOffset != currentSize {
+		return nil, fmt.Errorf("%w: offset %d of object of %d bytes", ErrWriteOffsetMismatch, *params.WriteOffset, currentSize)
+	}
+
+	entry := DBEntry{
+		Path:            path,
+		PhysicalAddress: c.PathProvider.NewPath(),
+		CreationDate:    time.Now(),
+		AddressType:     AddressTypeRelative,
+		ContentType:     params.ContentType,
+	}
+	if current != nil {
+		entry.ContentType = current.ContentType
+		entry.Metadata = current.Metadata
+		entry.Tags = current.Tags
+	}
+	storageNamespace := repository.StorageNamespace.String()
+	dst := block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}
+	var src *block.ObjectPointer
+	if current != nil {
+		src = &block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   current.AddressType.ToIdentifierType(),
+			Identifier:       current.PhysicalAddress,
+		}
+	}
+
+	if currentSize >= MinAppendCopySize && size >= 0 {
+		entry.Checksum, err = c.composeAppend(ctx, *src, currentSize, dst, reader, size)
+		entry.Size = currentSize + size
+	} else {
+		entry.Checksum, entry.Size, err = c.rewriteAppend(ctx, src, currentSize, dst, reader, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.CreateEntry(ctx, repositoryID, branch, entry, setOpt); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// composeAppend writes dst by a multipart upload of the srcSize bytes of src followed by size bytes of reader, without
+// reading the data of src. It returns the checksum of dst.
+func (c *Catalog) composeAppend(ctx context.Context, src block.ObjectPointer, srcSize int64, dst block.ObjectPointer, reader io.Reader, size int64) (string, error) {
+	mpu, err := c.BlockAdapter.CreateMultiPartUpload(ctx, dst, nil, block.CreateMultiPartUploadOpts{})
+	if err != nil {
+		return "", err
+	}
+	completed := false
+	defer func() {
+		if !completed {
+			_ = c.BlockAdapter.AbortMultiPartUpload(ctx, dst, mpu.UploadID)
+		}
+	}()
+
+	ranges := appendCopyRanges(srcSize, MaxAppendCopyPartSize)
+	parts := make([]block.MultipartPart, 0, len(ranges)+1)
+	for i, r := range ranges {
+		partNumber := i + 1
+		copied, err := c.BlockAdapter.UploadCopyPartRange(ctx, src, dst, mpu.UploadID, partNumber, r.start, r.end)
+		if err != nil {
+			return "", fmt.Errorf("copy object data: %w", err)
+		}
+		parts = append(parts, block.MultipartPart{ETag: copied.ETag, PartNumber: partNumber})
+	}
+	if size > 0 {
+		partNumber := len(parts) + 1
+		appended, err := c.BlockAdapter.UploadPart(ctx, dst, size, reader, mpu.UploadID, partNumber)
+		if err != nil {
+			return "", fmt.Errorf("upload appended data: %w", err)
+		}
+		parts = append(parts, block.MultipartPart{ETag: appended.ETag, PartNumber: partNumber})
+	}
+	resp, err := c.BlockAdapter.CompleteMultiPartUpload(ctx, dst, mpu.UploadID, &block.MultipartUploadCompletion{Part: parts})
+	if err != nil {
+		return "", err
+	}
+	completed = true
+	return resp.ETag, nil
+}
+
+// copyRange is an inclusive range of bytes of an object
+type copyRange struct {
+	start, end int64
+}
+
+// appendCopyRanges splits size bytes into the fewest ranges of at most maxPartSize bytes, of equal size but for the
+// last one. Equal ranges keep all of them at least MinAppendCopySize bytes for sizes of at least MinAppendCopySize.
+func appendCopyRanges(size, maxPartSize int64) []copyRange {
+	count := (size + maxPartSize - 1) / maxPartSize
+	partSize := (size + count - 1) / count
+	ranges := make([]copyRange, 0, count)
+	for start := int64(0); start < size; start += partSize {
+		ranges = append(ranges, copyRange{start: start, end: min(start+partSize, size) - 1})
+	}
+	return ranges
+}
+
+// rewriteAppend writes dst with the srcSize bytes of src, if set, followed by size bytes of reader. It returns the
+// checksum and size of dst.
+func (c *Catalog) rewriteAppend(ctx context.Context, src *block.ObjectPointer, srcSize int64, dst block.ObjectPointer, reader io.Reader, size int64) (string, int64, error) {
+	body := reader
+	if src != nil && srcSize > 0 {
+		data, err := c.BlockAdapter.Get(ctx, *src, srcSize)
+		if err != nil {
+			return "", 0, fmt.Errorf("read object data: %w", err)
+		}
+		defer func() { _ = data.Close() }()
+		body = io.MultiReader(data, reader)
+		if size >= 0 {
+			size += srcSize
+		}
+	}
+	blob, err := upload.WriteBlob(ctx, c.BlockAdapter, dst.StorageNamespace, dst.Identifier, body, size, block.PutOpts{})
+	if err != nil {
+		return "", 0, err
+	}
+	return blob.Checksum, blob.Size, nil
+}
@@ -0,0 +1,35 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendCopyRanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        int64
+		maxPartSize int64
+		expected    []copyRange
+	}{
+		{name: "single", size: 10, maxPartSize: 10, expected: []copyRange{{0, 9}}},
+		{name: "split evenly", size: 11, maxPartSize: 10, expected: []copyRange{{0, 5}, {6, 10}}},
+		{name: "three", size: 25, maxPartSize: 10, expected: []copyRange{{0, 8}, {9, 17}, {18, 24}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, appendCopyRanges(tt.size, tt.maxPartSize))
+		})
+	}
+
+	// parts of the largest sizes stay within the limit and above the minimal part size
+	const size = 5*MaxAppendCopyPartSize + 1
+	ranges := appendCopyRanges(size, MaxAppendCopyPartSize)
+	require.Len(t, ranges, 6)
+	require.Equal(t, int64(size-1), ranges[len(ranges)-1].end)
+	for _, r := range ranges {
+		require.LessOrEqual(t, r.end-r.start+1, int64(MaxAppendCopyPartSize))
+		require.GreaterOrEqual(t, r.end-r.start+1, int64(MinAppendCopySize))
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,10 +25,21 @@ import (
 	"github.com/treeverse/lakefs/pkg/batch"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/factory"
+	"github.com/treeverse/lakefs/pkg/cache"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/adapteroverride"
 	"github.com/treeverse/lakefs/pkg/graveler/branch"
+	"github.com/treeverse/lakefs/pkg/graveler/bucketnotification"
 	"github.com/treeverse/lakefs/pkg/graveler/committed"
+	"github.com/treeverse/lakefs/pkg/graveler/committemplate"
+	"github.com/treeverse/lakefs/pkg/graveler/cors"
+	"github.com/treeverse/lakefs/pkg/graveler/costattribution"
+	"github.com/treeverse/lakefs/pkg/graveler/keyvalidation"
+	"github.com/treeverse/lakefs/pkg/graveler/legalhold"
+	"github.com/treeverse/lakefs/pkg/graveler/lifecycle"
+	"github.com/treeverse/lakefs/pkg/graveler/objectlock"
+	"github.com/treeverse/lakefs/pkg/graveler/passthrough"
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
 	"github.com/treeverse/lakefs/pkg/graveler/retention"
 	"github.com/treeverse/lakefs/pkg/graveler/settings"
@@ -35,6 +48,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/ident"
 	"github.com/treeverse/lakefs/pkg/ingest/store"
 	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/lease"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/pyramid"
 	pyramidparams "github.com/treeverse/lakefs/pkg/pyramid/params"
@@ -42,6 +56,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/validator"
 	"go.uber.org/atomic"
 	"go.uber.org/ratelimit"
+	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -68,6 +83,8 @@ const (
 
 	DumpRefsTaskIDPrefix    = "DR"
 	RestoreRefsTaskIDPrefix = "RR"
+	CommitAsyncTaskIDPrefix = "CA"
+	GCPlanTaskIDPrefix      = "GP"
 
 	TaskExpiryTime = 24 * time.Hour
 )
@@ -169,6 +186,55 @@ type LogParams struct {
 	FirstParent   bool
 	Since         *time.Time
 	StopAt        string
+	Filter        CommitFilter
+}
+
+// CommitFilter selects commits by their attributes. Zero value fields match any commit.
+type CommitFilter struct {
+	// Committer matches commits with this exact committer
+	Committer string
+	// Message matches commits whose message contains this substring
+	Message string
+	// Metadata matches commits that include all the key/value pairs
+	Metadata map[string]string
+	// Since matches commits created at or after this time
+	Since *time.Time
+	// Until matches commits created at or before this time
+	Until *time.Time
+}
+
+// Match returns true if the commit record passes all the filter conditions
+func (f CommitFilter) Match(commit *graveler.CommitRecord) bool {
+	if f.Committer != "" && commit.Committer != f.Committer {
+		return false
+	}
+	if f.Message != "" && !strings.Contains(commit.Message, f.Message) {
+		return false
+	}
+	for k, v := range f.Metadata {
+		if value, ok := commit.Metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	if f.Since != nil && !f.Since.IsZero() && commit.CreationDate.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && !f.Until.IsZero() && commit.CreationDate.After(*f.Until) {
+		return false
+	}
+	return true
+}
+
+// DefaultSearchCommitsScanLimit is the number of commits a search scans for a single page of results
+const DefaultSearchCommitsScanLimit = 10_000
+
+type SearchCommitsParams struct {
+	After  string
+	Amount int
+	Filter CommitFilter
+	// ScanLimit bounds the commits scanned for the page, DefaultSearchCommitsScanLimit when zero. A page that
+	// reaches it may hold fewer than Amount commits and continues after the last commit scanned.
+	ScanLimit int
 }
 
 type ExpireResult struct {
@@ -223,6 +289,19 @@ type Catalog struct {
 	addressProvider       *ident.HexAddressProvider
 	UGCPrepareMaxFileSize int64
 	UGCPrepareInterval    time.Duration
+	TreeMaxDepth          int
+	// TreeMaxScanEntries is the number of objects scanned by a call to get a directory tree
+	TreeMaxScanEntries int
+	// DirectoryListingCache keeps the single level listings of directories of commits, nil disables it
+	DirectoryListingCache cache.Cache
+	// DirectoryListingMaxChildren is the number of children of the largest directory kept in DirectoryListingCache
+	DirectoryListingMaxChildren int
+	// DatasetsMaxScanEntries is the number of objects scanned by a call to list datasets
+	DatasetsMaxScanEntries int
+	// AdapterOverrideProfiles are the shared configuration profiles block adapter overrides of repositories may use
+	AdapterOverrideProfiles []string
+	// AdapterOverrideEndpoints are the endpoints block adapter overrides of repositories may use
+	AdapterOverrideEndpoints []string
 }
 
 const (
@@ -231,9 +310,23 @@ const (
 	ListTagsLimitMax         = 1000
 	DiffLimitMax             = 1000
 	ListEntriesLimitMax      = 10000
-	sharedWorkers            = 30
-	pendingTasksPerWorker    = 3
-	workersMaxDrainDuration  = 5 * time.Second
+	DefaultTreeMaxDepth      = 10
+	// DefaultTreeMaxScanEntries is the number of objects scanned by default by a call to get a directory tree
+	DefaultTreeMaxScanEntries = 100 * ListEntriesLimitMax
+	// DefaultDirectoryListingMaxChildren is the number of children of the largest directory listing kept by default
+	DefaultDirectoryListingMaxChildren = 1000
+	// DefaultDatasetsMaxScanEntries is the number of objects scanned by default by a call to list datasets
+	DefaultDatasetsMaxScanEntries = 10 * ListEntriesLimitMax
+	// leaseTTL is the time to live of the leases coordinating imports and garbage collection across lakeFS
+	// instances, renewed while the work is running
+	leaseTTL                = 30 * time.Second
+	sharedWorkers           = 30
+	pendingTasksPerWorker   = 3
+	workersMaxDrainDuration = 5 * time.Second
+	// MaxCostAttributionTags is the maximal number of cost attribution tags, the maximal number of S3 object tags
+	MaxCostAttributionTags           = 10
+	maxCostAttributionTagKeyLength   = 128
+	maxCostAttributionTagValueLength = 256
 )
 
 type ImportPathType string
@@ -364,25 +457,53 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 	}
 
 	protectedBranchesManager := branch.NewProtectionManager(settingManager)
+	legalHoldManager := legalhold.NewManager(settingManager)
+	lockedBranchesManager := branch.NewLockManager(settingManager)
+	passThroughManager := passthrough.NewManager(settingManager)
+	corsManager := cors.NewManager(settingManager)
+	objectLockManager := objectlock.NewManager(settingManager)
+	costAttributionManager := costattribution.NewManager(settingManager)
+	keyValidationManager := keyvalidation.NewManager(settingManager)
+	commitTemplateManager := committemplate.NewManager(settingManager)
+	bucketNotificationManager := bucketnotification.NewManager(settingManager)
+	blockAdapterOverrideManager := adapteroverride.NewManager(settingManager)
+	lifecycleRulesManager := lifecycle.NewManager(settingManager)
 	stagingManager := staging.NewManager(ctx, cfg.KVStore, storeLimiter, cfg.Config.Graveler.BatchDBIOTransactionMarkers, executor)
-	gStore := graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager)
-
+	gStore := graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager, legalHoldManager, lockedBranchesManager, passThroughManager, corsManager, objectLockManager, costAttributionManager, keyValidationManager, commitTemplateManager, bucketNotificationManager, blockAdapterOverrideManager, lifecycleRulesManager)
 	// The size of the workPool is determined by the number of workers and the number of desired pending tasks for each worker.
 	workPool := pond.New(sharedWorkers, sharedWorkers*pendingTasksPerWorker, pond.Context(ctx))
 
+	gStore.ValueSize = valueSize
+	gStore.SubmitBackground = workPool.TrySubmit
+	gStore.DeletedBranchRetention = cfg.Config.Graveler.DeletedBranches.Retention
+
+	var adapterOverrideProfiles, adapterOverrideEndpoints []string
+	if cfg.Config.Blockstore.S3 != nil {
+		adapterOverrideProfiles = cfg.Config.Blockstore.S3.AllowedOverrideProfiles
+		adapterOverrideEndpoints = cfg.Config.Blockstore.S3.AllowedOverrideEndpoints
+	}
+	var directoryListingCache cache.Cache
+	if listingCacheCfg := cfg.Config.Graveler.DirectoryListingCache; listingCacheCfg.Enabled && listingCacheCfg.Size > 0 {
+		directoryListingCache = cache.NewCache(listingCacheCfg.Size, listingCacheCfg.Expiry, cache.NewJitterFn(listingCacheCfg.Jitter))
+	}
 	return &Catalog{
-		BlockAdapter:          tierFSParams.Adapter,
-		Store:                 gStore,
-		UGCPrepareMaxFileSize: cfg.Config.UGC.PrepareMaxFileSize,
-		UGCPrepareInterval:    cfg.Config.UGC.PrepareInterval,
-		PathProvider:          cfg.PathProvider,
-		BackgroundLimiter:     limiter,
-		walkerFactory:         cfg.WalkerFactory,
-		workPool:              workPool,
-		KVStore:               cfg.KVStore,
-		managers:              []io.Closer{sstableManager, sstableMetaManager, &ctxCloser{cancelFn}},
-		KVStoreLimited:        storeLimiter,
-		addressProvider:       addressProvider,
+		BlockAdapter:                tierFSParams.Adapter,
+		Store:                       gStore,
+		UGCPrepareMaxFileSize:       cfg.Config.UGC.PrepareMaxFileSize,
+		TreeMaxDepth:                cfg.Config.Graveler.Tree.MaxDepth,
+		DirectoryListingCache:       directoryListingCache,
+		DirectoryListingMaxChildren: cfg.Config.Graveler.DirectoryListingCache.MaxChildren,
+		UGCPrepareInterval:          cfg.Config.UGC.PrepareInterval,
+		PathProvider:                cfg.PathProvider,
+		BackgroundLimiter:           limiter,
+		walkerFactory:               cfg.WalkerFactory,
+		workPool:                    workPool,
+		KVStore:                     cfg.KVStore,
+		managers:                    []io.Closer{sstableManager, sstableMetaManager, &ctxCloser{cancelFn}},
+		KVStoreLimited:              storeLimiter,
+		addressProvider:             addressProvider,
+		AdapterOverrideProfiles:     adapterOverrideProfiles,
+		AdapterOverrideEndpoints:    adapterOverrideEndpoints,
 	}, nil
 }
 
@@ -490,6 +611,17 @@ func (c *Catalog) DeleteRepository(ctx context.Context, repository string, opts
 	}); err != nil {
 		return err
 	}
+	repo, err := c.Store.GetRepository(ctx, repositoryID)
+	switch {
+	case errors.Is(err, graveler.ErrRepositoryInDeletion):
+		// object lock was enforced when the deletion started
+	case err != nil:
+		return err
+	default:
+		if err := c.checkObjectLockRepository(ctx, repo); err != nil {
+			return err
+		}
+	}
 	return c.Store.DeleteRepository(ctx, repositoryID, opts...)
 }
 
@@ -541,6 +673,16 @@ func (c *Catalog) DeleteRepositoryMetadata(ctx context.Context, repository strin
 	})
 }
 
+// SetRepositoryReadOnly sets whether repository rejects writes that are not forced, as a replica repository does
+// until it is promoted
+func (c *Catalog) SetRepositoryReadOnly(ctx context.Context, repository string, readOnly bool) error {
+	r, err := c.getRepository(ctx, repository)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetRepositoryReadOnly(ctx, r, readOnly)
+}
+
 // ListRepositories list repository information, the bool returned is true when more repositories can be listed.
 // In this case, pass the last repository name as 'after' on the next call to ListRepositories
 func (c *Catalog) ListRepositories(ctx context.Context, limit int, prefix, after string) ([]*Repository, bool, error) {
@@ -660,10 +802,12 @@ func (c *Catalog) CreateBranch(ctx context.Context, repositoryID string, branch
 	catalogCommitLog := &CommitLog{
 		Reference:  newBranch.CommitID.String(),
 		Committer:  commit.Committer,
+		Author:     commit.Author,
 		Message:    commit.Message,
 		Metadata:   Metadata(commit.Metadata),
 		Version:    CommitVersion(commit.Version),
 		Generation: CommitGeneration(commit.Generation),
+		Stats:      commitStatsFromGraveler(commit.Stats),
 	}
 	for _, parent := range commit.Parents {
 		catalogCommitLog.Parents = append(catalogCommitLog.Parents, string(parent))
@@ -683,9 +827,41 @@ func (c *Catalog) DeleteBranch(ctx context.Context, repositoryID string, branch
 	if err != nil {
 		return err
 	}
+	if err := c.checkObjectLockPrefix(ctx, repository, branchID, nil); err != nil {
+		return err
+	}
 	return c.Store.DeleteBranch(ctx, repository, branchID, opts...)
 }
 
+// RenameBranch renames the branch to newBranch, keeping its commit and uncommitted changes
+func (c *Catalog) RenameBranch(ctx context.Context, repositoryID string, branch string, newBranch string, opts ...graveler.SetOptionsFunc) error {
+	branchID := graveler.BranchID(branch)
+	newBranchID := graveler.BranchID(newBranch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "name", Value: newBranchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		if errors.Is(err, graveler.ErrInvalidBranchID) {
+			return fmt.Errorf("%w: branch id must consist of letters, digits, underscores and dashes, and cannot start with a dash", err)
+		}
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if err := c.checkCommitIDDuplication(ctx, repository, graveler.CommitID(newBranchID)); err != nil {
+		return err
+	}
+	if _, err := c.Store.GetTag(ctx, repository, graveler.TagID(newBranchID)); err == nil {
+		return fmt.Errorf("tag ID %s: %w", newBranchID, graveler.ErrConflictFound)
+	} else if !errors.Is(err, graveler.ErrNotFound) {
+		return err
+	}
+	return c.Store.RenameBranch(ctx, repository, branchID, newBranchID, opts...)
+}
+
 func (c *Catalog) ListBranches(ctx context.Context, repositoryID string, prefix string, limit int, after string) ([]*Branch, bool, error) {
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -707,6 +883,11 @@ func (c *Catalog) ListBranches(ctx context.Context, repositoryID string, prefix
 	}
 	defer it.Close()
 
+	locks, err := c.Store.GetBranchLocks(ctx, repository)
+	if err != nil {
+		return nil, false, err
+	}
+
 	afterBranch := graveler.BranchID(after)
 	prefixBranch := graveler.BranchID(prefix)
 	if afterBranch < prefixBranch {
@@ -729,6 +910,9 @@ func (c *Catalog) ListBranches(ctx context.Context, repositoryID string, prefix
 			Name:      v.BranchID.String(),
 			Reference: v.CommitID.String(),
 		}
+		if lock, ok := locks.Branches[branchID]; ok {
+			b.Lock = newBranchLock(lock)
+		}
 		branches = append(branches, b)
 		if len(branches) >= limit+1 {
 			break
@@ -746,6 +930,95 @@ func (c *Catalog) ListBranches(ctx context.Context, repositoryID string, prefix
 	return branches, hasMore, nil
 }
 
+// ListDeletedBranches lists the deleted branches of a repository that can still be restored
+func (c *Catalog) ListDeletedBranches(ctx context.Context, repositoryID string, prefix string, limit int, after string) ([]*DeletedBranch, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// normalize limit
+	if limit < 0 || limit > ListBranchesLimitMax {
+		limit = ListBranchesLimitMax
+	}
+	it, err := c.Store.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+
+	afterBranch := graveler.BranchID(after)
+	prefixBranch := graveler.BranchID(prefix)
+	if afterBranch < prefixBranch {
+		it.SeekGE(prefixBranch)
+	} else {
+		it.SeekGE(afterBranch)
+	}
+	var branches []*DeletedBranch
+	for it.Next() {
+		v := it.Value()
+		if v.BranchID == afterBranch {
+			continue
+		}
+		// break in case we got to a branch outside our prefix
+		if !strings.HasPrefix(v.BranchID.String(), prefix) {
+			break
+		}
+		branches = append(branches, &DeletedBranch{
+			Name:      v.BranchID.String(),
+			Reference: v.CommitID.String(),
+			DeletedAt: v.DeletedAt,
+			ExpiresAt: v.ExpiresAt,
+		})
+		if len(branches) >= limit+1 {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	// return results (optionally trimmed) and hasMore
+	hasMore := false
+	if len(branches) > limit {
+		hasMore = true
+		branches = branches[:limit]
+	}
+	return branches, hasMore, nil
+}
+
+// RestoreBranch re-creates a deleted branch at the commit it pointed at when it was deleted
+func (c *Catalog) RestoreBranch(ctx context.Context, repositoryID string, branch string, opts ...graveler.SetOptionsFunc) (*Branch, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Store.GetTag(ctx, repository, graveler.TagID(branchID)); err == nil {
+		return nil, fmt.Errorf("tag ID %s: %w", branchID, graveler.ErrConflictFound)
+	} else if !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	restored, err := c.Store.RestoreBranch(ctx, repository, branchID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Branch{
+		Name:      branch,
+		Reference: restored.CommitID.String(),
+	}, nil
+}
+
 func (c *Catalog) BranchExists(ctx context.Context, repositoryID string, branch string) (bool, error) {
 	branchID := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -787,6 +1060,73 @@ func (c *Catalog) GetBranchReference(ctx context.Context, repositoryID string, b
 	return string(b.CommitID), nil
 }
 
+// GetBranchLock returns the lock of the branch, or nil if the branch is not locked
+func (c *Catalog) GetBranchLock(ctx context.Context, repositoryID string, branch string) (*BranchLock, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	locks, err := c.Store.GetBranchLocks(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	lock, ok := locks.Branches[branch]
+	if !ok {
+		return nil, nil
+	}
+	return newBranchLock(lock), nil
+}
+
+// LockBranch locks the branch, preventing any change to it until it is unlocked
+func (c *Catalog) LockBranch(ctx context.Context, repositoryID, branch, reason, lockedBy string) error {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.LockBranch(ctx, repository, branchID, &graveler.BranchLock{
+		Reason:       reason,
+		LockedBy:     lockedBy,
+		CreationDate: timestamppb.Now(),
+	})
+}
+
+func (c *Catalog) UnlockBranch(ctx context.Context, repositoryID, branch string) error {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.UnlockBranch(ctx, repository, branchID)
+}
+
+func newBranchLock(lock *graveler.BranchLock) *BranchLock {
+	return &BranchLock{
+		Reason:       lock.Reason,
+		LockedBy:     lock.LockedBy,
+		CreationDate: lock.CreationDate.AsTime(),
+	}
+}
+
 func (c *Catalog) HardResetBranch(ctx context.Context, repositoryID, branch, refExpr string, opts ...graveler.SetOptionsFunc) error {
 	branchID := graveler.BranchID(branch)
 	ref := graveler.Ref(refExpr)
@@ -801,6 +1141,12 @@ func (c *Catalog) HardResetBranch(ctx context.Context, repositoryID, branch, ref
 	if err != nil {
 		return err
 	}
+	if err := c.checkObjectLockUncommitted(ctx, repository, branchID, nil); err != nil {
+		return err
+	}
+	if err := c.checkObjectLockChanges(ctx, repository, branchID, graveler.Ref(branchID), ref); err != nil {
+		return err
+	}
 	return c.Store.ResetHard(ctx, repository, branchID, ref, opts...)
 }
 
@@ -816,6 +1162,9 @@ func (c *Catalog) ResetBranch(ctx context.Context, repositoryID string, branch s
 	if err != nil {
 		return err
 	}
+	if err := c.checkObjectLockUncommitted(ctx, repository, branchID, nil); err != nil {
+		return err
+	}
 	return c.Store.Reset(ctx, repository, branchID, opts...)
 }
 
@@ -943,6 +1292,33 @@ func (c *Catalog) GetTag(ctx context.Context, repositoryID string, tagID string)
 // GetEntry returns the current entry for a path in repository branch reference.  Returns
 // the entry with ExpiredError if it has expired from underlying storage.
 func (c *Catalog) GetEntry(ctx context.Context, repositoryID string, reference string, path string, params GetEntryParams) (*DBEntry, error) {
+	return c.getEntry(ctx, repositoryID, reference, path, graveler.WithStageOnly(params.StageOnly))
+}
+
+// GetEntryTags returns the tags of the object at path on reference, nil if there is no such object
+func (c *Catalog) GetEntryTags(ctx context.Context, repositoryID string, reference string, path string) (map[string]string, error) {
+	entry, err := c.getEntry(ctx, repositoryID, reference, path)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry.Tags, nil
+}
+
+// GetEntryWithCommit returns the entry of path at reference, together with the ID of the commit it was read from.
+// The commit ID is empty when the entry is uncommitted on a branch.
+func (c *Catalog) GetEntryWithCommit(ctx context.Context, repositoryID string, reference string, path string) (*DBEntry, string, error) {
+	var commitID graveler.CommitID
+	entry, err := c.getEntry(ctx, repositoryID, reference, path, graveler.WithResolvedCommitID(&commitID))
+	if err != nil {
+		return nil, "", err
+	}
+	return entry, commitID.String(), nil
+}
+
+func (c *Catalog) getEntry(ctx context.Context, repositoryID string, reference string, path string, opts ...graveler.GetOptionsFunc) (*DBEntry, error) {
 	refToGet := graveler.Ref(reference)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -955,7 +1331,7 @@ func (c *Catalog) GetEntry(ctx context.Context, repositoryID string, reference s
 	if err != nil {
 		return nil, err
 	}
-	val, err := c.Store.Get(ctx, repository, refToGet, graveler.Key(path), graveler.WithStageOnly(params.StageOnly))
+	val, err := c.Store.Get(ctx, repository, refToGet, graveler.Key(path), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -976,6 +1352,20 @@ func newEntryFromCatalogEntry(entry DBEntry) *Entry {
 		ETag:         entry.Checksum,
 		Size:         entry.Size,
 		ContentType:  ContentTypeOrDefault(entry.ContentType),
+		StorageClass: entry.StorageClass,
+		Tags:         entry.Tags,
+	}
+	if entry.Retention != nil {
+		ent.Retention = &EntryRetention{
+			Mode:            entry.Retention.Mode,
+			RetainUntilDate: timestamppb.New(entry.Retention.RetainUntilDate),
+		}
+	}
+	if entry.Source != nil {
+		ent.Source = &EntrySource{
+			ETag:      entry.Source.ETag,
+			VersionId: entry.Source.VersionID,
+		}
 	}
 	return ent
 }
@@ -1008,7 +1398,6 @@ func addressTypeToCatalog(t Entry_AddressType) AddressType {
 
 func (c *Catalog) CreateEntry(ctx context.Context, repositoryID string, branch string, entry DBEntry, opts ...graveler.SetOptionsFunc) error {
 	branchID := graveler.BranchID(branch)
-	ent := newEntryFromCatalogEntry(entry)
 	path := Path(entry.Path)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -1018,11 +1407,18 @@ func (c *Catalog) CreateEntry(ctx context.Context, repositoryID string, branch s
 		return err
 	}
 	key := graveler.Key(path)
-	value, err := EntryToValue(ent)
+	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
 		return err
 	}
-	repository, err := c.getRepository(ctx, repositoryID)
+	if err := c.validateKey(ctx, repository, key); err != nil {
+		return err
+	}
+	entry.Retention, err = c.objectLockWriteRetention(ctx, repository, branchID, key, entry.Retention)
+	if err != nil {
+		return err
+	}
+	value, err := EntryToValue(newEntryFromCatalogEntry(entry))
 	if err != nil {
 		return err
 	}
@@ -1044,6 +1440,9 @@ func (c *Catalog) DeleteEntry(ctx context.Context, repositoryID string, branch s
 		return err
 	}
 	key := graveler.Key(p)
+	if err := c.checkObjectLockDelete(ctx, repository, branchID, key); err != nil {
+		return err
+	}
 	return c.Store.Delete(ctx, repository, branchID, key, opts...)
 }
 
@@ -1068,14 +1467,49 @@ func (c *Catalog) DeleteEntries(ctx context.Context, repositoryID string, branch
 		return err
 	}
 
-	keys := make([]graveler.Key, len(paths))
-	for i := range paths {
-		keys[i] = graveler.Key(paths[i])
+	keys := make([]graveler.Key, 0, len(paths))
+	var lockErr *multierror.Error
+	for _, path := range paths {
+		key := graveler.Key(path)
+		err := c.checkObjectLockDelete(ctx, repository, branchID, key)
+		switch {
+		case errors.Is(err, graveler.ErrObjectLocked):
+			lockErr = multierror.Append(lockErr, &graveler.DeleteError{Key: key, Err: err})
+		case err != nil:
+			return err
+		default:
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return lockErr.ErrorOrNil()
 	}
-	return c.Store.DeleteBatch(ctx, repository, branchID, keys, opts...)
+	err = c.Store.DeleteBatch(ctx, repository, branchID, keys, opts...)
+	if lockErr == nil {
+		return err
+	}
+	var batchErr *multierror.Error
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
+	}
+	return multierror.Append(lockErr, batchErr.WrappedErrors()...)
 }
 
 func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int) ([]*DBEntry, bool, error) {
+	return c.listEntries(ctx, repositoryID, reference, prefix, after, delimiter, limit, nil)
+}
+
+// ListEntriesByStorageClass lists the entries like ListEntries, keeping only objects of storageClass. Objects
+// written without a storage class are of DefaultStorageClass. Common prefixes are listed regardless of the storage
+// class of the objects under them.
+func (c *Catalog) ListEntriesByStorageClass(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, storageClass string, limit int) ([]*DBEntry, bool, error) {
+	return c.listEntries(ctx, repositoryID, reference, prefix, after, delimiter, limit, func(entry *DBEntry) bool {
+		return entry.CommonLevel || strings.EqualFold(StorageClassOrDefault(entry.StorageClass), storageClass)
+	})
+}
+
+// listEntries lists up to limit entries under prefix of reference, keeping only entries matching filter if set
+func (c *Catalog) listEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int, filter func(*DBEntry) bool) ([]*DBEntry, bool, error) {
 	// normalize limit
 	if limit < 0 || limit > ListEntriesLimitMax {
 		limit = ListEntriesLimitMax
@@ -1096,6 +1530,18 @@ func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, referenc
 	if err != nil {
 		return nil, false, err
 	}
+	// single level listings of directories are served from the directory listing cache, without seeking past
+	// every child in committed data
+	if c.DirectoryListingCache != nil && delimiter == DefaultPathDelimiter && isDirectoryPrefix(prefix) {
+		listing, ok, err := c.getDirectoryListing(ctx, repository, refToList, prefix)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			entries, hasMore := listing.page(after, limit, filter)
+			return entries, hasMore, nil
+		}
+	}
 	iter, err := c.Store.List(ctx, repository, refToList, limit+1)
 	if err != nil {
 		return nil, false, err
@@ -1114,6 +1560,9 @@ func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, referenc
 			continue
 		}
 		entry := newCatalogEntryFromEntry(v.CommonPrefix, v.Path.String(), v.Entry)
+		if filter != nil && !filter(&entry) {
+			continue
+		}
 		entries = append(entries, &entry)
 		if len(entries) >= limit+1 {
 			break
@@ -1131,43 +1580,160 @@ func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, referenc
 	return entries, hasMore, nil
 }
 
-func (c *Catalog) ResetEntry(ctx context.Context, repositoryID string, branch string, path string, opts ...graveler.SetOptionsFunc) error {
-	branchID := graveler.BranchID(branch)
-	entryPath := Path(path)
-	if err := validator.Validate([]validator.ValidateArg{
-		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
-		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
-		{Name: "path", Value: entryPath, Fn: ValidatePath},
-	}); err != nil {
-		return err
+// GetTree returns the directory tree under prefix of reference, separated by delimiter, down to depth levels
+// (normalized to the configured maximal depth). Each directory holds its number of children and the aggregate
+// count and size of the objects under it, at any depth. It scans at most TreeMaxScanEntries objects, and reports
+// whether more objects under prefix were left out of the tree.
+func (c *Catalog) GetTree(ctx context.Context, repositoryID string, reference string, prefix string, delimiter string, depth int) (*TreeNode, bool, error) {
+	maxDepth := c.TreeMaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultTreeMaxDepth
 	}
-	repository, err := c.getRepository(ctx, repositoryID)
-	if err != nil {
-		return err
+	if depth < 0 || depth > maxDepth {
+		depth = maxDepth
 	}
-	key := graveler.Key(entryPath)
-	return c.Store.ResetKey(ctx, repository, branchID, key, opts...)
-}
-
-func (c *Catalog) ResetEntries(ctx context.Context, repositoryID string, branch string, prefix string, opts ...graveler.SetOptionsFunc) error {
-	branchID := graveler.BranchID(branch)
 	prefixPath := Path(prefix)
+	refToList := graveler.Ref(reference)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
-		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "ref", Value: refToList, Fn: graveler.ValidateRef},
+		{Name: "prefix", Value: prefixPath, Fn: ValidatePathOptional},
+		{Name: "delimiter", Value: Path(delimiter), Fn: ValidatePath},
 	}); err != nil {
-		return err
+		return nil, false, err
 	}
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
-	keyPrefix := graveler.Key(prefixPath)
-	return c.Store.ResetPrefix(ctx, repository, branchID, keyPrefix, opts...)
-}
-
-func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, sourceMetarange *string, allowEmpty bool, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
-	branchID := graveler.BranchID(branch)
+	iter, err := c.Store.List(ctx, repository, refToList, ListEntriesLimitMax)
+	if err != nil {
+		return nil, false, err
+	}
+	it := NewEntryListingIterator(NewValueToEntryIterator(iter), prefixPath, "")
+	defer it.Close()
+
+	scanLeft := c.TreeMaxScanEntries
+	if scanLeft <= 0 {
+		scanLeft = DefaultTreeMaxScanEntries
+	}
+	// directories are built one level below depth, to count the children of the deepest directories
+	root := newTreeBuilder(prefix)
+	for it.Next() {
+		if scanLeft == 0 {
+			return root.build(depth), true, nil
+		}
+		scanLeft--
+		v := it.Value()
+		size := v.Entry.Size
+		node := root
+		node.add(size)
+		rest := strings.TrimPrefix(v.Path.String(), prefix)
+		for level := 0; level <= depth; level++ {
+			name, remainder, isDir := strings.Cut(rest, delimiter)
+			if !isDir {
+				node.objects++
+				break
+			}
+			node = node.child(name, delimiter)
+			node.add(size)
+			rest = remainder
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return root.build(depth), false, nil
+}
+
+type treeBuilder struct {
+	path        string
+	objects     int
+	objectCount int64
+	sizeBytes   int64
+	children    map[string]*treeBuilder
+}
+
+func newTreeBuilder(path string) *treeBuilder {
+	return &treeBuilder{path: path, children: make(map[string]*treeBuilder)}
+}
+
+func (t *treeBuilder) add(size int64) {
+	t.objectCount++
+	t.sizeBytes += size
+}
+
+func (t *treeBuilder) child(name, delimiter string) *treeBuilder {
+	c, ok := t.children[name]
+	if !ok {
+		c = newTreeBuilder(t.path + name + delimiter)
+		t.children[name] = c
+	}
+	return c
+}
+
+func (t *treeBuilder) build(depth int) *TreeNode {
+	node := &TreeNode{
+		Path:        t.path,
+		ChildCount:  t.objects + len(t.children),
+		ObjectCount: t.objectCount,
+		SizeBytes:   t.sizeBytes,
+	}
+	if depth == 0 {
+		return node
+	}
+	names := maps.Keys(t.children)
+	sort.Strings(names)
+	node.Children = make([]*TreeNode, 0, len(names))
+	for _, name := range names {
+		node.Children = append(node.Children, t.children[name].build(depth-1))
+	}
+	return node
+}
+
+func (c *Catalog) ResetEntry(ctx context.Context, repositoryID string, branch string, path string, opts ...graveler.SetOptionsFunc) error {
+	branchID := graveler.BranchID(branch)
+	entryPath := Path(path)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: entryPath, Fn: ValidatePath},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	key := graveler.Key(entryPath)
+	if err := c.checkObjectLockDelete(ctx, repository, branchID, key); err != nil {
+		return err
+	}
+	return c.Store.ResetKey(ctx, repository, branchID, key, opts...)
+}
+
+func (c *Catalog) ResetEntries(ctx context.Context, repositoryID string, branch string, prefix string, opts ...graveler.SetOptionsFunc) error {
+	branchID := graveler.BranchID(branch)
+	prefixPath := Path(prefix)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	keyPrefix := graveler.Key(prefixPath)
+	if err := c.checkObjectLockUncommitted(ctx, repository, branchID, keyPrefix); err != nil {
+		return err
+	}
+	return c.Store.ResetPrefix(ctx, repository, branchID, keyPrefix, opts...)
+}
+
+func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, sourceMetarange *string, allowEmpty bool, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
+	branchID := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
 		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
@@ -1179,6 +1745,9 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 	if err != nil {
 		return nil, err
 	}
+	if err := c.validateCommitTemplate(ctx, repository, message, metadata, false); err != nil {
+		return nil, err
+	}
 
 	p := graveler.CommitParams{
 		Committer:  committer,
@@ -1191,15 +1760,133 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 		x := graveler.MetaRangeID(*sourceMetarange)
 		p.SourceMetaRange = &x
 	}
+	return c.commit(ctx, repository, branchID, p, opts...)
+}
+
+// PartialCommit commits only the uncommitted changes of branch to one of paths or under one of prefixes, keeping the
+// other changes uncommitted.
+func (c *Catalog) PartialCommit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, allowEmpty bool, paths, prefixes []string, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 && len(prefixes) == 0 {
+		return nil, fmt.Errorf("paths or prefixes: %w", graveler.ErrRequiredValue)
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validateCommitTemplate(ctx, repository, message, metadata, false); err != nil {
+		return nil, err
+	}
+
+	filter := &graveler.CommitFilter{}
+	for _, path := range paths {
+		filter.Paths = append(filter.Paths, graveler.Key(path))
+	}
+	for _, prefix := range prefixes {
+		filter.Prefixes = append(filter.Prefixes, graveler.Key(prefix))
+	}
+	return c.commit(ctx, repository, branchID, graveler.CommitParams{
+		Committer:  committer,
+		Message:    message,
+		Date:       date,
+		Metadata:   map[string]string(metadata),
+		AllowEmpty: allowEmpty,
+		Filter:     filter,
+	}, opts...)
+}
+
+// CommitAsyncSubmit seals the staging area of branch and commits it in the background, returning the ID of the commit
+// task. Changes written after it returns are left uncommitted. The task runs in this lakeFS instance only: if it stops
+// before the task is done, the task is lost and its status is never done, and the sealed changes are committed by the
+// next commit of the branch.
+func (c *Catalog) CommitAsyncSubmit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, date *int64, allowEmpty bool, opts ...graveler.SetOptionsFunc) (string, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return "", err
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if err := c.validateCommitTemplate(ctx, repository, message, metadata, false); err != nil {
+		return "", err
+	}
+	if err := c.Store.SealStagingToken(ctx, repository, branchID, opts...); err != nil {
+		return "", err
+	}
+
+	// the task runs after the request is done, keep the author of the commit
+	author := graveler.CommitAuthorFromContext(ctx)
+	taskStatus := &CommitAsyncStatus{Branch: branch}
+	taskSteps := []taskStep{
+		{
+			Name: "commit",
+			Func: func(ctx context.Context) error {
+				commitID, err := c.Store.Commit(graveler.WithCommitAuthor(ctx, author), repository, branchID, graveler.CommitParams{
+					Committer:  committer,
+					Message:    message,
+					Date:       date,
+					Metadata:   map[string]string(metadata),
+					AllowEmpty: allowEmpty,
+					Sealed:     true,
+				}, opts...)
+				if err != nil {
+					return err
+				}
+				taskStatus.CommitId = commitID.String()
+				return nil
+			},
+		},
+	}
+	taskID := NewTaskID(CommitAsyncTaskIDPrefix)
+	if err := c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+// CommitAsyncStatus returns the status of a commit task started by CommitAsyncSubmit on branch
+func (c *Catalog) CommitAsyncStatus(ctx context.Context, repositoryID, branch, id string) (*CommitAsyncStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(CommitAsyncTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+
+	var status CommitAsyncStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status.Branch != branch {
+		return nil, graveler.ErrNotFound
+	}
+	return &status, nil
+}
+
+func (c *Catalog) commit(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, p graveler.CommitParams, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
 	commitID, err := c.Store.Commit(ctx, repository, branchID, p, opts...)
 	if err != nil {
 		return nil, err
 	}
 	catalogCommitLog := &CommitLog{
 		Reference: commitID.String(),
-		Committer: committer,
-		Message:   message,
-		Metadata:  metadata,
+		Committer: p.Committer,
+		Message:   p.Message,
+		Metadata:  Metadata(p.Metadata),
 	}
 	// in order to return commit log we need the commit creation time and parents
 	commit, err := c.Store.GetCommit(ctx, repository, commitID)
@@ -1209,13 +1896,116 @@ func (c *Catalog) Commit(ctx context.Context, repositoryID, branch, message, com
 	for _, parent := range commit.Parents {
 		catalogCommitLog.Parents = append(catalogCommitLog.Parents, parent.String())
 	}
+	catalogCommitLog.Author = commit.Author
 	catalogCommitLog.CreationDate = commit.CreationDate.UTC()
 	catalogCommitLog.MetaRangeID = string(commit.MetaRangeID)
 	catalogCommitLog.Version = CommitVersion(commit.Version)
 	catalogCommitLog.Generation = CommitGeneration(commit.Generation)
+	catalogCommitLog.Stats = commitStatsFromGraveler(commit.Stats)
 	return catalogCommitLog, nil
 }
 
+// AmendCommit replaces the head commit of branch with a commit of its changes together with the uncommitted changes
+// of the branch. An empty message or nil metadata keep those of the head commit.
+func (c *Catalog) AmendCommit(ctx context.Context, repositoryID, branch, message, committer string, metadata Metadata, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validateCommitTemplate(ctx, repository, message, metadata, true); err != nil {
+		return nil, err
+	}
+
+	commitID, err := c.Store.Commit(ctx, repository, branchID, graveler.CommitParams{
+		Committer: committer,
+		Message:   message,
+		Metadata:  map[string]string(metadata),
+		Amend:     true,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCommit(ctx, repositoryID, commitID.String())
+}
+
+// validateCommitTemplate verifies that a commit with message and metadata follows the commit template of the
+// repository. An amended commit keeps the message or metadata of the head commit when they are not set, so only
+// the values it sets are validated.
+func (c *Catalog) validateCommitTemplate(ctx context.Context, repository *graveler.RepositoryRecord, message string, metadata Metadata, amend bool) error {
+	template, err := c.Store.GetCommitTemplate(ctx, repository)
+	if err != nil {
+		return err
+	}
+	if template.RequireMessage && strings.TrimSpace(message) == "" && !amend {
+		return fmt.Errorf("commit message is required: %w", graveler.ErrCommitTemplate)
+	}
+	if amend && metadata == nil {
+		return nil
+	}
+	var missing []string
+	for _, key := range template.RequiredMetadataKeys {
+		if metadata[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("commit metadata keys %s are required: %w", strings.Join(missing, ", "), graveler.ErrCommitTemplate)
+	}
+	return nil
+}
+
+func (c *Catalog) GetCommitTemplate(ctx context.Context, repositoryID string) (*graveler.CommitTemplate, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetCommitTemplate(ctx, repository)
+}
+
+// SetCommitTemplate replaces the commit template of the repository. The template applies to commits created through
+// the commit API, other commits such as merges, reverts and imports are not validated.
+func (c *Catalog) SetCommitTemplate(ctx context.Context, repositoryID string, template *graveler.CommitTemplate) error {
+	keys := make(map[string]struct{}, len(template.RequiredMetadataKeys))
+	for _, key := range template.RequiredMetadataKeys {
+		if key == "" {
+			return fmt.Errorf("empty required metadata key: %w", graveler.ErrInvalidValue)
+		}
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("duplicate required metadata key %s: %w", key, graveler.ErrInvalidValue)
+		}
+		keys[key] = struct{}{}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetCommitTemplate(ctx, repository, template)
+}
+
+func (c *Catalog) GetBucketNotifications(ctx context.Context, repositoryID string) (*graveler.BucketNotifications, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetBucketNotifications(ctx, repository)
+}
+
+func (c *Catalog) SetBucketNotifications(ctx context.Context, repositoryID string, notifications *graveler.BucketNotifications) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetBucketNotifications(ctx, repository, notifications)
+}
+
 func (c *Catalog) CreateCommitRecord(ctx context.Context, repositoryID string, commitID string, version int, committer string, message string, metaRangeID string, creationDate *int64, parents []string, metadata map[string]string, generation int, opts ...graveler.SetOptionsFunc) error {
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
@@ -1259,11 +2049,13 @@ func (c *Catalog) GetCommit(ctx context.Context, repositoryID string, reference
 	catalogCommitLog := &CommitLog{
 		Reference:    commitID.String(),
 		Committer:    commit.Committer,
+		Author:       commit.Author,
 		Message:      commit.Message,
 		CreationDate: commit.CreationDate,
 		MetaRangeID:  string(commit.MetaRangeID),
 		Metadata:     Metadata(commit.Metadata),
 		Generation:   CommitGeneration(commit.Generation),
+		Stats:        commitStatsFromGraveler(commit.Stats),
 		Version:      CommitVersion(commit.Version),
 		Parents:      []string{},
 	}
@@ -1273,6 +2065,34 @@ func (c *Catalog) GetCommit(ctx context.Context, repositoryID string, reference
 	return catalogCommitLog, nil
 }
 
+// GetPostHookRecord returns the hook record of the post-commit or post-merge event that created commitID on
+// branchID, as passed to the hooks when the event occurred
+func (c *Catalog) GetPostHookRecord(ctx context.Context, repositoryID string, eventType graveler.EventType, branchID string, commitID string) (*graveler.HookRecord, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: graveler.BranchID(branchID), Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := c.Store.GetCommit(ctx, repository, graveler.CommitID(commitID))
+	if err != nil {
+		return nil, err
+	}
+	return &graveler.HookRecord{
+		EventType:        eventType,
+		RepositoryID:     repository.RepositoryID,
+		StorageNamespace: repository.StorageNamespace,
+		SourceRef:        graveler.CommitID(commitID).Ref(),
+		BranchID:         graveler.BranchID(branchID),
+		Commit:           *commit,
+		CommitID:         graveler.CommitID(commitID),
+	}, nil
+}
+
 func (c *Catalog) ListCommits(ctx context.Context, repositoryID string, branch string, params LogParams) ([]*CommitLog, bool, error) {
 	branchRef := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -1329,6 +2149,62 @@ func (c *Catalog) ListCommits(ctx context.Context, repositoryID string, branch s
 	return c.listCommitsWithPaths(ctx, repository, it, params)
 }
 
+// SearchCommits returns the commits of the repository that match the filter of params, ordered by commit ID, and
+// the commit ID the next page starts after, empty after the last page
+func (c *Catalog) SearchCommits(ctx context.Context, repositoryID string, params SearchCommitsParams) ([]*CommitLog, string, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, "", err
+	}
+	it, err := c.Store.ListCommits(ctx, repository)
+	if err != nil {
+		return nil, "", err
+	}
+	defer it.Close()
+
+	scanLimit := params.ScanLimit
+	if scanLimit <= 0 {
+		scanLimit = DefaultSearchCommitsScanLimit
+	}
+	after := graveler.CommitID(params.After)
+	if after != "" {
+		it.SeekGE(after)
+	}
+	var (
+		commits     = make([]*CommitLog, 0)
+		scanned     int
+		lastScanned graveler.CommitID
+	)
+	for len(commits) < params.Amount && scanned < scanLimit && it.Next() {
+		val := it.Value()
+		if val.CommitID == after {
+			continue
+		}
+		scanned++
+		lastScanned = val.CommitID
+		if params.Filter.Match(val) {
+			commits = append(commits, CommitRecordToLog(val))
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+	// the page ends after the last commit scanned, which the next page continues from if more commits follow
+	var next string
+	if (len(commits) == params.Amount || scanned == scanLimit) && it.Next() {
+		next = lastScanned.String()
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+	return commits, next, nil
+}
+
 func (c *Catalog) listCommitsWithPaths(ctx context.Context, repository *graveler.RepositoryRecord, it graveler.CommitIterator, params LogParams) ([]*CommitLog, bool, error) {
 	// verify we are not listing commits without any paths
 	if len(params.PathList) == 0 {
@@ -1372,6 +2248,10 @@ func (c *Catalog) listCommitsWithPaths(ctx context.Context, repository *graveler
 			if len(commitRecord.Parents) != NumberOfParentsOfNonMergeCommit {
 				continue
 			}
+			// skip commits that do not match the filter
+			if !params.Filter.Match(commitRecord) {
+				continue
+			}
 
 			// submit work to the pool
 			commitOrder := current
@@ -1454,6 +2334,12 @@ func listCommitsWithoutPaths(it graveler.CommitIterator, params LogParams) ([]*C
 	var commits []*CommitLog
 	for it.Next() {
 		val := it.Value()
+		if !params.Filter.Match(val) {
+			if val.CommitID.String() == params.StopAt {
+				break
+			}
+			continue
+		}
 
 		commits = append(commits, CommitRecordToLog(val))
 		if foundAllCommits(params, commits) {
@@ -1475,6 +2361,18 @@ func foundAllCommits(params LogParams, commits []*CommitLog) bool {
 		len(commits) >= params.Amount+1 || (len(commits) > 0 && commits[len(commits)-1].Reference == params.StopAt)
 }
 
+func commitStatsFromGraveler(stats *graveler.CommitStats) *CommitStats {
+	if stats == nil {
+		return nil
+	}
+	return &CommitStats{
+		ObjectCount:      stats.ObjectCount,
+		SizeBytes:        stats.SizeBytes,
+		ObjectCountDelta: stats.ObjectCountDelta,
+		SizeBytesDelta:   stats.SizeBytesDelta,
+	}
+}
+
 func CommitRecordToLog(val *graveler.CommitRecord) *CommitLog {
 	if val == nil {
 		return nil
@@ -1482,6 +2380,7 @@ func CommitRecordToLog(val *graveler.CommitRecord) *CommitLog {
 	commit := &CommitLog{
 		Reference:    val.CommitID.String(),
 		Committer:    val.Committer,
+		Author:       val.Author,
 		Message:      val.Message,
 		CreationDate: val.CreationDate,
 		Metadata:     map[string]string(val.Metadata),
@@ -1489,6 +2388,7 @@ func CommitRecordToLog(val *graveler.CommitRecord) *CommitLog {
 		Parents:      make([]string, 0, len(val.Parents)),
 		Version:      CommitVersion(val.Version),
 		Generation:   CommitGeneration(val.Generation),
+		Stats:        commitStatsFromGraveler(val.Stats),
 	}
 	for _, parent := range val.Parents {
 		commit.Parents = append(commit.Parents, parent.String())
@@ -1656,10 +2556,21 @@ func (c *Catalog) Revert(ctx context.Context, repositoryID string, branch string
 	if err != nil {
 		return err
 	}
+	if err := c.checkObjectLockChanges(ctx, repository, branchID, reference, parentRef(reference, parentNumber)); err != nil {
+		return err
+	}
 	_, err = c.Store.Revert(ctx, repository, branchID, reference, parentNumber, commitParams, opts...)
 	return err
 }
 
+// parentRef returns the ref of parent number parentNumber of reference, its first parent if parentNumber is 0
+func parentRef(reference graveler.Ref, parentNumber int) graveler.Ref {
+	if parentNumber < 1 {
+		parentNumber = 1
+	}
+	return graveler.Ref(fmt.Sprintf("%s^%d", reference, parentNumber))
+}
+
 func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch string, params CherryPickParams, opts ...graveler.SetOptionsFunc) (*CommitLog, error) {
 	branchID := graveler.BranchID(branch)
 	reference := graveler.Ref(params.Reference)
@@ -1678,6 +2589,13 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 		return nil, err
 	}
 
+	cherryPickParent := 1
+	if parentNumber != nil {
+		cherryPickParent = *parentNumber
+	}
+	if err := c.checkObjectLockChanges(ctx, repository, branchID, parentRef(reference, cherryPickParent), reference); err != nil {
+		return nil, err
+	}
 	commitID, err := c.Store.CherryPick(ctx, repository, branchID, reference, parentNumber, params.Committer, opts...)
 	if err != nil {
 		return nil, err
@@ -1692,12 +2610,14 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 	catalogCommitLog := &CommitLog{
 		Reference:    commitID.String(),
 		Committer:    params.Committer,
+		Author:       commit.Author,
 		Message:      commit.Message,
 		CreationDate: commit.CreationDate.UTC(),
 		MetaRangeID:  string(commit.MetaRangeID),
 		Metadata:     Metadata(commit.Metadata),
 		Version:      CommitVersion(commit.Version),
 		Generation:   CommitGeneration(commit.Generation),
+		Stats:        commitStatsFromGraveler(commit.Stats),
 	}
 	for _, parent := range commit.Parents {
 		catalogCommitLog.Parents = append(catalogCommitLog.Parents, parent.String())
@@ -1896,6 +2816,9 @@ func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBra
 		return "", err
 	}
 
+	if err := c.checkObjectLockChanges(ctx, repository, destination, graveler.Ref(destination), source); err != nil {
+		return "", err
+	}
 	commitID, err := c.Store.Merge(ctx, repository, destination, source, commitParams, strategy, opts...)
 	if err != nil {
 		return "", err
@@ -1926,6 +2849,41 @@ func (c *Catalog) FindMergeBase(ctx context.Context, repositoryID string, destin
 	return fromCommit.CommitID.String(), toCommit.CommitID.String(), c.addressProvider.ContentAddress(baseCommit), nil
 }
 
+// GetMergeBase returns the merge base of the left and right references, and the number of commits each one has and the
+// other does not
+func (c *Catalog) GetMergeBase(ctx context.Context, repositoryID string, leftRef string, rightRef string) (*MergeBase, error) {
+	left := graveler.Ref(leftRef)
+	right := graveler.Ref(rightRef)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "left", Value: left, Fn: graveler.ValidateRef},
+		{Name: "right", Value: right, Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, err
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	leftCommit, rightCommit, baseCommit, err := c.Store.FindMergeBase(ctx, repository, left, right)
+	if err != nil {
+		return nil, err
+	}
+	ahead, behind, err := c.Store.CountDivergence(ctx, repository, leftCommit.CommitID, rightCommit.CommitID)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeBase{
+		LeftCommitID:  leftCommit.CommitID.String(),
+		RightCommitID: rightCommit.CommitID.String(),
+		BaseCommitID:  c.addressProvider.ContentAddress(baseCommit),
+		Ahead:         ahead,
+		Behind:        behind,
+	}, nil
+}
+
 func (c *Catalog) DumpRepositorySubmit(ctx context.Context, repositoryID string) (string, error) {
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
@@ -1997,19 +2955,29 @@ func (c *Catalog) DumpRepositoryStatus(ctx context.Context, repositoryID string,
 	return &taskStatus, nil
 }
 
-func (c *Catalog) RestoreRepositorySubmit(ctx context.Context, repositoryID string, info *RepositoryDumpInfo, opts ...graveler.SetOptionsFunc) (string, error) {
+// RestoreRepositorySubmit starts restoring the refs of a repository dump. Unless replace is set, the repository must
+// be bare. With replace, a repository with commits is rolled back to the dump: its branches and tags in the dump are
+// reset, and its other branches and tags are kept. Tags are loaded before branches, so a tag of the repository that
+// conflicts with the dump fails the restore before any branch is reset.
+func (c *Catalog) RestoreRepositorySubmit(ctx context.Context, repositoryID string, info *RepositoryDumpInfo, replace bool, opts ...graveler.SetOptionsFunc) (string, error) {
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
 		return "", err
 	}
 
 	// verify bare repository - no commits
-	_, _, err = c.ListCommits(ctx, repository.RepositoryID.String(), repository.DefaultBranchID.String(), LogParams{
-		Amount: 1,
-		Limit:  true,
-	})
-	if !errors.Is(err, graveler.ErrNotFound) {
-		return "", ErrNonEmptyRepository
+	if replace {
+		if err := c.checkObjectLockRepository(ctx, repository); err != nil {
+			return "", err
+		}
+	} else {
+		_, _, err = c.ListCommits(ctx, repository.RepositoryID.String(), repository.DefaultBranchID.String(), LogParams{
+			Amount: 1,
+			Limit:  true,
+		})
+		if !errors.Is(err, graveler.ErrNotFound) {
+			return "", ErrNonEmptyRepository
+		}
 	}
 
 	// create refs restore task and update initial status
@@ -2022,15 +2990,15 @@ func (c *Catalog) RestoreRepositorySubmit(ctx context.Context, repositoryID stri
 			},
 		},
 		{
-			Name: "load branches",
+			Name: "load tags",
 			Func: func(ctx context.Context) error {
-				return c.Store.LoadBranches(ctx, repository, graveler.MetaRangeID(info.BranchesMetarangeId), opts...)
+				return c.Store.LoadTags(ctx, repository, graveler.MetaRangeID(info.TagsMetarangeId), opts...)
 			},
 		},
 		{
-			Name: "load tags",
+			Name: "load branches",
 			Func: func(ctx context.Context) error {
-				return c.Store.LoadTags(ctx, repository, graveler.MetaRangeID(info.TagsMetarangeId), opts...)
+				return c.Store.LoadBranches(ctx, repository, graveler.MetaRangeID(info.BranchesMetarangeId), opts...)
 			},
 		},
 	}
@@ -2072,7 +3040,10 @@ func (c *Catalog) runBackgroundTaskSteps(repository *graveler.RepositoryRecord,
 	reflect.ValueOf(taskStatus).Elem().FieldByName("Task").Set(reflect.ValueOf(task))
 
 	// make sure we use background context as soon as we submit the task the request is done
-	ctx := context.Background()
+	ctx, err := c.withBlockAdapterOverride(context.Background(), repository)
+	if err != nil {
+		return err
+	}
 
 	// initial task update done before we run each step in the background task
 	if err := UpdateTaskStatus(ctx, c.KVStore, repository, taskID, taskStatus); err != nil {
@@ -2216,9 +3187,19 @@ func (c *Catalog) GetRange(ctx context.Context, repositoryID, rangeID string) (g
 	return c.Store.GetRange(ctx, repository, graveler.RangeID(rangeID))
 }
 
-func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, importID string, params ImportRequest, logger logging.Logger) error {
-	ctx, cancel := context.WithCancel(context.Background()) // Need a new context for the async operations
+func (c *Catalog) importAsync(importLease *lease.Lease, repository *graveler.RepositoryRecord, branchID, importID string, params ImportRequest, author string, logger logging.Logger) error {
+	// Need a new context for the async operations, canceled if the import lease of the branch is lost
+	ctx, cancel := context.WithCancel(importLease.Keep(graveler.WithCommitAuthor(context.Background(), author)))
 	defer cancel()
+	ctx, err := c.withBlockAdapterOverride(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := importLease.Release(context.Background()); err != nil {
+			logger.WithError(err).Warn("Failed to release import lease")
+		}
+	}()
 
 	importManager, err := NewImport(ctx, cancel, logger, c.KVStore, repository, importID)
 	if err != nil {
@@ -2273,6 +3254,11 @@ func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, i
 		ranges = append(ranges, rangeInfo)
 		// Check if operation was canceled
 		if ctx.Err() != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, lease.ErrLeaseLost) {
+				importError := fmt.Errorf("import: %w", cause)
+				importManager.SetError(importError)
+				return importError
+			}
 			return nil
 		}
 	}
@@ -2293,6 +3279,12 @@ func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, i
 	if params.Commit.CommitMessage == "" {
 		params.Commit.CommitMessage = "Import objects"
 	}
+	// Fence the branch update: another instance that took over the import lease has a newer token
+	if err := importLease.Check(ctx); err != nil {
+		importError := fmt.Errorf("import: %w", err)
+		importManager.SetError(importError)
+		return importError
+	}
 	commitID, err := c.Store.Import(ctx, repository, graveler.BranchID(branchID), metarange.ID, graveler.CommitParams{
 		Committer: params.Commit.Committer,
 		Message:   params.Commit.CommitMessage,
@@ -2334,12 +3326,26 @@ func (c *Catalog) Import(ctx context.Context, repositoryID, branchID string, par
 	if err != nil {
 		return "", err
 	}
+	// import replaces the objects of the branch under the destinations of its paths
+	for _, p := range params.Paths {
+		if err := c.checkObjectLockPrefix(ctx, repository, graveler.BranchID(branchID), graveler.Key(p.Destination)); err != nil {
+			return "", err
+		}
+	}
 
 	id := xid.New().String()
+	// Only one import runs on a branch at a time, across all lakeFS instances
+	importLease, err := lease.Acquire(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(graveler.ImportLeasePath(graveler.BranchID(branchID))), "import "+id, leaseTTL)
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		return "", fmt.Errorf("import into branch %s: %w: %s", branchID, graveler.ErrConflictFound, err)
+	}
+	if err != nil {
+		return "", err
+	}
 	// Run import
 	go func() {
 		logger := c.log(ctx).WithField("import_id", id)
-		err = c.importAsync(repository, branchID, id, params, logger)
+		err = c.importAsync(importLease, repository, branchID, id, params, graveler.CommitAuthorFromContext(ctx), logger)
 		if err != nil {
 			logger.WithError(err).Error("import failure")
 		}
@@ -2353,140 +3359,708 @@ func (c *Catalog) CancelImport(ctx context.Context, repositoryID, importID strin
 		return err
 	}
 
-	importStatus, err := c.getImportStatus(ctx, repository, importID)
+	importStatus, err := c.getImportStatus(ctx, repository, importID)
+	if err != nil {
+		return err
+	}
+	if importStatus.Completed {
+		c.log(ctx).WithFields(logging.Fields{
+			"import_id": importID,
+			"completed": importStatus.Completed,
+			"error":     importStatus.Error,
+		}).Warning("Not canceling import - already completed")
+		return graveler.ErrConflictFound
+	}
+	importStatus.Error = ImportCanceled
+	importStatus.UpdatedAt = timestamppb.Now()
+	return kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(graveler.ImportsPath(importID)), importStatus)
+}
+
+func (c *Catalog) getImportStatus(ctx context.Context, repository *graveler.RepositoryRecord, importID string) (*graveler.ImportStatusData, error) {
+	repoPartition := graveler.RepoPartition(repository)
+	data := &graveler.ImportStatusData{}
+	_, err := kv.GetMsg(ctx, c.KVStore, repoPartition, []byte(graveler.ImportsPath(importID)), data)
+	if err != nil {
+		if errors.Is(err, kv.ErrNotFound) {
+			return nil, graveler.ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (c *Catalog) GetImportStatus(ctx context.Context, repositoryID, importID string) (*graveler.ImportStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.getImportStatus(ctx, repository, importID)
+	if err != nil {
+		return nil, err
+	}
+	return graveler.ImportStatusFromProto(data), nil
+}
+
+func (c *Catalog) WriteRange(ctx context.Context, repositoryID string, params WriteRangeRequest, opts ...graveler.SetOptionsFunc) (*graveler.RangeInfo, *Mark, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// TODO (niro): Need to handle this at some point (use adapter GetWalker)
+	walker, err := c.walkerFactory.GetWalker(ctx, store.WalkerOptions{StorageURI: params.SourceURI, SkipOutOfOrder: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating object-store walker: %w", err)
+	}
+
+	it, err := NewWalkEntryIterator(ctx, walker, ImportPathTypePrefix, params.Prepend, params.After, params.ContinuationToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating walk iterator: %w", err)
+	}
+	defer it.Close()
+
+	rangeInfo, err := c.Store.WriteRange(ctx, repository, NewEntryToValueIterator(it), opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("writing range from entry iterator: %w", err)
+	}
+
+	stagingToken := params.StagingToken
+	skipped := it.GetSkippedEntries()
+	if len(skipped) > 0 {
+		c.log(ctx).Warning("Skipped count:", len(skipped))
+		if stagingToken == "" {
+			stagingToken = graveler.GenerateStagingToken("import", "ingest_range").String()
+		}
+
+		for _, obj := range skipped {
+			p := params.Prepend + obj.RelativeKey
+			entryRecord := objectStoreEntryToEntryRecord(obj, p)
+			entry, err := EntryToValue(entryRecord.Entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing entry: %w", err)
+			}
+			if err := c.Store.StageObject(ctx, stagingToken, graveler.ValueRecord{
+				Key:   graveler.Key(entryRecord.Path),
+				Value: entry,
+			}); err != nil {
+				return nil, nil, fmt.Errorf("staging skipped keys: %w", err)
+			}
+		}
+	}
+	mark := it.Marker()
+	mark.StagingToken = stagingToken
+
+	return rangeInfo, &mark, nil
+}
+
+func (c *Catalog) WriteMetaRange(ctx context.Context, repositoryID string, ranges []*graveler.RangeInfo, opts ...graveler.SetOptionsFunc) (*graveler.MetaRangeInfo, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.WriteMetaRange(ctx, repository, ranges, opts...)
+}
+
+func (c *Catalog) GetGarbageCollectionRules(ctx context.Context, repositoryID string) (*graveler.GarbageCollectionRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetGarbageCollectionRules(ctx, repository)
+}
+
+func (c *Catalog) SetGarbageCollectionRules(ctx context.Context, repositoryID string, rules *graveler.GarbageCollectionRules) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetGarbageCollectionRules(ctx, repository, rules)
+}
+
+func (c *Catalog) GetCORSRules(ctx context.Context, repositoryID string) (*graveler.CORSRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetCORSRules(ctx, repository)
+}
+
+func (c *Catalog) SetCORSRules(ctx context.Context, repositoryID string, rules *graveler.CORSRules) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetCORSRules(ctx, repository, rules)
+}
+
+func (c *Catalog) GetObjectLockConfiguration(ctx context.Context, repositoryID string) (*graveler.ObjectLockConfiguration, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetObjectLockConfiguration(ctx, repository)
+}
+
+func (c *Catalog) SetObjectLockConfiguration(ctx context.Context, repositoryID string, config *graveler.ObjectLockConfiguration) error {
+	if config.DefaultMode != "" {
+		if err := validateRetentionMode(config.DefaultMode); err != nil {
+			return err
+		}
+		if (config.DefaultRetentionDays > 0) == (config.DefaultRetentionYears > 0) {
+			return fmt.Errorf("default retention requires either days or years: %w", graveler.ErrInvalidValue)
+		}
+	}
+	if config.DefaultRetentionDays < 0 || config.DefaultRetentionYears < 0 {
+		return fmt.Errorf("negative default retention: %w", graveler.ErrInvalidValue)
+	}
+	if config.DefaultMode != "" && !config.Enabled {
+		return graveler.ErrObjectLockNotEnabled
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetObjectLockConfiguration(ctx, repository, config)
+}
+
+func (c *Catalog) GetCostAttribution(ctx context.Context, repositoryID string) (*graveler.CostAttribution, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetCostAttribution(ctx, repository)
+}
+
+// SetCostAttribution replaces the cost attribution of the repository. Tags follow the limits of S3 object tags.
+func (c *Catalog) SetCostAttribution(ctx context.Context, repositoryID string, attribution *graveler.CostAttribution) error {
+	if len(attribution.Tags) > MaxCostAttributionTags {
+		return fmt.Errorf("%d cost attribution tags, up to %d allowed: %w", len(attribution.Tags), MaxCostAttributionTags, graveler.ErrInvalidValue)
+	}
+	for k, v := range attribution.Tags {
+		if k == "" || len(k) > maxCostAttributionTagKeyLength {
+			return fmt.Errorf("cost attribution tag key %q must be 1 to %d characters: %w", k, maxCostAttributionTagKeyLength, graveler.ErrInvalidValue)
+		}
+		if len(v) > maxCostAttributionTagValueLength {
+			return fmt.Errorf("cost attribution tag %s value must be up to %d characters: %w", k, maxCostAttributionTagValueLength, graveler.ErrInvalidValue)
+		}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetCostAttribution(ctx, repository, attribution)
+}
+
+func (c *Catalog) GetKeyValidationRules(ctx context.Context, repositoryID string) (*graveler.KeyValidationRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetKeyValidationRules(ctx, repository)
+}
+
+// SetKeyValidationRules replaces the key validation rules of the repository. Rules apply to keys written after they
+// are set, existing keys are not validated.
+func (c *Catalog) SetKeyValidationRules(ctx context.Context, repositoryID string, rules *graveler.KeyValidationRules) error {
+	if err := keyvalidation.ValidateRules(rules); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetKeyValidationRules(ctx, repository, rules)
+}
+
+// ValidateEntryKey verifies that path follows the key validation rules of the repository, so that writes can fail
+// before uploading any data. Entries are validated again when created.
+func (c *Catalog) ValidateEntryKey(ctx context.Context, repositoryID string, path string) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.validateKey(ctx, repository, graveler.Key(path))
+}
+
+func (c *Catalog) validateKey(ctx context.Context, repository *graveler.RepositoryRecord, key graveler.Key) error {
+	rules, err := c.Store.GetKeyValidationRules(ctx, repository)
+	if err != nil {
+		return err
+	}
+	return keyvalidation.Validate(rules, key)
+}
+
+func (c *Catalog) GetBlockAdapterOverride(ctx context.Context, repositoryID string) (*graveler.BlockAdapterOverride, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetBlockAdapterOverride(ctx, repository)
+}
+
+// ValidateBlockAdapterOverride verifies that the override can be applied. Profiles and endpoints must be allowed by
+// the configuration, as profiles hold credentials of the lakeFS installation and endpoints receive its requests. An
+// endpoint requires a profile, so that the global credentials are never sent to it.
+func (c *Catalog) ValidateBlockAdapterOverride(override *graveler.BlockAdapterOverride) error {
+	if override.Endpoint != "" {
+		u, err := url.Parse(override.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint %s must be an http or https URL: %w", override.Endpoint, graveler.ErrInvalidValue)
+		}
+		if !block.OverrideEndpointAllowed(override.Endpoint, c.AdapterOverrideEndpoints) {
+			return fmt.Errorf("endpoint %s is not allowed for overrides: %w", override.Endpoint, graveler.ErrInvalidValue)
+		}
+		if override.Profile == "" {
+			return fmt.Errorf("endpoint %s requires a profile: %w", override.Endpoint, graveler.ErrInvalidValue)
+		}
+	}
+	if override.UseAccelerate && (override.Endpoint != "" || override.ForcePathStyle) {
+		return fmt.Errorf("transfer acceleration cannot be used with an endpoint or path style: %w", graveler.ErrInvalidValue)
+	}
+	if override.Profile != "" && !slices.Contains(c.AdapterOverrideProfiles, override.Profile) {
+		return fmt.Errorf("profile %s is not allowed for overrides: %w", override.Profile, graveler.ErrInvalidValue)
+	}
+	return nil
+}
+
+// SetBlockAdapterOverride replaces the settings overriding the global block adapter configuration for the repository.
+func (c *Catalog) SetBlockAdapterOverride(ctx context.Context, repositoryID string, override *graveler.BlockAdapterOverride) error {
+	if err := c.ValidateBlockAdapterOverride(override); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetBlockAdapterOverride(ctx, repository, override)
+}
+
+// WithBlockAdapterOverride returns a context applying the block adapter override of the repository to the block
+// adapter
+func (c *Catalog) WithBlockAdapterOverride(ctx context.Context, repositoryID string) (context.Context, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return ctx, err
+	}
+	return c.withBlockAdapterOverride(ctx, repository)
+}
+
+func (c *Catalog) withBlockAdapterOverride(ctx context.Context, repository *graveler.RepositoryRecord) (context.Context, error) {
+	override, err := c.Store.GetBlockAdapterOverride(ctx, repository)
+	if err != nil {
+		return ctx, err
+	}
+	return BlockAdapterOverrideContext(ctx, override), nil
+}
+
+// BlockAdapterOverrideContext returns a context applying override to the block adapter
+func BlockAdapterOverrideContext(ctx context.Context, override *graveler.BlockAdapterOverride) context.Context {
+	if override.Endpoint == "" && !override.UseAccelerate && override.Region == "" && override.Profile == "" && !override.ForcePathStyle {
+		return ctx
+	}
+	return block.WithAdapterOverride(ctx, &block.AdapterOverride{
+		Endpoint:       override.Endpoint,
+		UseAccelerate:  override.UseAccelerate,
+		Region:         override.Region,
+		Profile:        override.Profile,
+		ForcePathStyle: override.ForcePathStyle,
+	})
+}
+
+// WithCostAttribution returns a context propagating the cost attribution of the repository to the block adapter,
+// and adding it to the request log fields audited on completion of the request.
+func (c *Catalog) WithCostAttribution(ctx context.Context, repositoryID string) (context.Context, error) {
+	attribution, err := c.GetCostAttribution(ctx, repositoryID)
+	if err != nil {
+		return ctx, err
+	}
+	if len(attribution.Tags) == 0 && !attribution.RequesterPays {
+		return ctx, nil
+	}
+	fields := logging.Fields{}
+	if len(attribution.Tags) > 0 {
+		fields["cost_attribution"] = attribution.Tags
+	}
+	if attribution.RequesterPays {
+		fields["requester_pays"] = true
+	}
+	ctx = logging.AddFields(ctx, fields)
+	return block.WithCostAttribution(ctx, &block.CostAttribution{
+		Tags:          attribution.Tags,
+		RequesterPays: attribution.RequesterPays,
+	}), nil
+}
+
+// SetObjectRetention replaces the retention of an object on a branch, nil retention removes it. Compliance
+// retention can only be extended. Governance retention can be shortened or removed only with bypassGovernance.
+func (c *Catalog) SetObjectRetention(ctx context.Context, repositoryID string, branch string, path string, retention *ObjectRetention, bypassGovernance bool) error {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return err
+	}
+	now := time.Now()
+	if retention != nil {
+		if err := validateObjectRetention(retention, now); err != nil {
+			return err
+		}
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	config, err := c.Store.GetObjectLockConfiguration(ctx, repository)
+	if err != nil {
+		return err
+	}
+	if !config.Enabled {
+		return graveler.ErrObjectLockNotEnabled
+	}
+	key := graveler.Key(path)
+	val, err := c.Store.Get(ctx, repository, graveler.Ref(branchID), key)
+	if err != nil {
+		return err
+	}
+	ent, err := ValueToEntry(val)
+	if err != nil {
+		return err
+	}
+	current := newCatalogEntryFromEntry(false, path, ent).Retention
+	if current.Active(now) {
+		shortened := retention == nil || retention.RetainUntilDate.Before(current.RetainUntilDate)
+		switch {
+		case current.Mode == RetentionModeCompliance && (shortened || retention.Mode != RetentionModeCompliance):
+			return fmt.Errorf("%s: compliance retention can only be extended: %w", path, graveler.ErrObjectLocked)
+		case current.Mode == RetentionModeGovernance && shortened && !bypassGovernance:
+			return fmt.Errorf("%s: shortening governance retention requires bypass: %w", path, graveler.ErrObjectLocked)
+		}
+	}
+	ent.Retention = nil
+	if retention != nil {
+		ent.Retention = &EntryRetention{
+			Mode:            retention.Mode,
+			RetainUntilDate: timestamppb.New(retention.RetainUntilDate),
+		}
+	}
+	value, err := EntryToValue(ent)
+	if err != nil {
+		return err
+	}
+	return c.Store.Set(ctx, repository, branchID, key, *value)
+}
+
+// objectLockWriteRetention enforces object lock on writing key to a branch. It fails when the current object is
+// under retention, and returns the retention of the written object: the requested retention or the default
+// retention of the repository.
+func (c *Catalog) objectLockWriteRetention(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, key graveler.Key, retention *ObjectRetention) (*ObjectRetention, error) {
+	config, err := c.Store.GetObjectLockConfiguration(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		if retention != nil {
+			return nil, graveler.ErrObjectLockNotEnabled
+		}
+		return nil, nil
+	}
+	now := time.Now()
+	if err := c.checkObjectNotLocked(ctx, repository, branchID, key, now); err != nil {
+		return nil, err
+	}
+	if retention != nil {
+		return retention, validateObjectRetention(retention, now)
+	}
+	if config.DefaultMode == "" {
+		return nil, nil
+	}
+	return &ObjectRetention{
+		Mode:            config.DefaultMode,
+		RetainUntilDate: now.AddDate(int(config.DefaultRetentionYears), 0, int(config.DefaultRetentionDays)),
+	}, nil
+}
+
+// checkObjectLockDelete fails with ErrObjectLocked when key is under retention on the branch
+func (c *Catalog) checkObjectLockDelete(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, key graveler.Key) error {
+	config, err := c.Store.GetObjectLockConfiguration(ctx, repository)
+	if err != nil {
+		return err
+	}
+	if !config.Enabled {
+		return nil
+	}
+	return c.checkObjectNotLocked(ctx, repository, branchID, key, time.Now())
+}
+
+func (c *Catalog) checkObjectNotLocked(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, key graveler.Key, now time.Time) error {
+	val, err := c.Store.Get(ctx, repository, graveler.Ref(branchID), key)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return checkValueNotLocked(key, val, now)
+}
+
+func checkValueNotLocked(key graveler.Key, val *graveler.Value, now time.Time) error {
+	ent, err := ValueToEntry(val)
 	if err != nil {
 		return err
 	}
-	if importStatus.Completed {
-		c.log(ctx).WithFields(logging.Fields{
-			"import_id": importID,
-			"completed": importStatus.Completed,
-			"error":     importStatus.Error,
-		}).Warning("Not canceling import - already completed")
-		return graveler.ErrConflictFound
+	if ent.Retention != nil && now.Before(ent.Retention.RetainUntilDate.AsTime()) {
+		return fmt.Errorf("%s: retained until %s: %w", key, ent.Retention.RetainUntilDate.AsTime().Format(time.RFC3339), graveler.ErrObjectLocked)
 	}
-	importStatus.Error = ImportCanceled
-	importStatus.UpdatedAt = timestamppb.Now()
-	return kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(graveler.ImportsPath(importID)), importStatus)
+	return nil
 }
 
-func (c *Catalog) getImportStatus(ctx context.Context, repository *graveler.RepositoryRecord, importID string) (*graveler.ImportStatusData, error) {
-	repoPartition := graveler.RepoPartition(repository)
-	data := &graveler.ImportStatusData{}
-	_, err := kv.GetMsg(ctx, c.KVStore, repoPartition, []byte(graveler.ImportsPath(importID)), data)
+func (c *Catalog) objectLockEnabled(ctx context.Context, repository *graveler.RepositoryRecord) (bool, error) {
+	config, err := c.Store.GetObjectLockConfiguration(ctx, repository)
 	if err != nil {
-		if errors.Is(err, kv.ErrNotFound) {
-			return nil, graveler.ErrNotFound
-		}
-		return nil, err
+		return false, err
 	}
-	return data, nil
+	return config.Enabled, nil
 }
 
-func (c *Catalog) GetImportStatus(ctx context.Context, repositoryID, importID string) (*graveler.ImportStatus, error) {
-	repository, err := c.getRepository(ctx, repositoryID)
-	if err != nil {
-		return nil, err
+// checkObjectLockChanges enforces object lock on operations that replace the committed content of a branch (merge,
+// revert, cherry-pick and hard reset). Such an operation changes only keys that differ between left and right, so it
+// fails with ErrObjectLocked if one of these keys is under retention on the branch.
+func (c *Catalog) checkObjectLockChanges(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, left, right graveler.Ref) error {
+	enabled, err := c.objectLockEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return err
 	}
-
-	data, err := c.getImportStatus(ctx, repository, importID)
+	diffs, err := c.Store.Diff(ctx, repository, left, right)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return graveler.ImportStatusFromProto(data), nil
+	defer diffs.Close()
+	now := time.Now()
+	for diffs.Next() {
+		if err := c.checkObjectNotLocked(ctx, repository, branchID, diffs.Value().Key, now); err != nil {
+			return err
+		}
+	}
+	return diffs.Err()
 }
 
-func (c *Catalog) WriteRange(ctx context.Context, repositoryID string, params WriteRangeRequest, opts ...graveler.SetOptionsFunc) (*graveler.RangeInfo, *Mark, error) {
-	repository, err := c.getRepository(ctx, repositoryID)
-	if err != nil {
-		return nil, nil, err
+// checkObjectLockUncommitted enforces object lock on operations that drop uncommitted changes of a branch under
+// prefix: they fail with ErrObjectLocked if an uncommitted object under retention would be dropped.
+func (c *Catalog) checkObjectLockUncommitted(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, prefix graveler.Key) error {
+	enabled, err := c.objectLockEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return err
 	}
-
-	// TODO (niro): Need to handle this at some point (use adapter GetWalker)
-	walker, err := c.walkerFactory.GetWalker(ctx, store.WalkerOptions{StorageURI: params.SourceURI, SkipOutOfOrder: true})
+	diffs, err := c.Store.DiffUncommitted(ctx, repository, branchID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("creating object-store walker: %w", err)
+		return err
+	}
+	defer diffs.Close()
+	diffs.SeekGE(prefix)
+	now := time.Now()
+	for diffs.Next() {
+		d := diffs.Value()
+		if !bytes.HasPrefix(d.Key, prefix) {
+			break
+		}
+		if d.Type == graveler.DiffTypeRemoved {
+			continue
+		}
+		if err := checkValueNotLocked(d.Key, d.Value, now); err != nil {
+			return err
+		}
 	}
+	return diffs.Err()
+}
 
-	it, err := NewWalkEntryIterator(ctx, walker, ImportPathTypePrefix, params.Prepend, params.After, params.ContinuationToken)
+// checkObjectLockPrefix enforces object lock on operations that replace or drop all the objects of a branch under
+// prefix (import and branch deletion): they fail with ErrObjectLocked if the branch holds an object under retention
+// under prefix.
+func (c *Catalog) checkObjectLockPrefix(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, prefix graveler.Key) error {
+	enabled, err := c.objectLockEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return err
+	}
+	values, err := c.Store.List(ctx, repository, graveler.Ref(branchID), ListEntriesLimitMax)
 	if err != nil {
-		return nil, nil, fmt.Errorf("creating walk iterator: %w", err)
+		return err
 	}
-	defer it.Close()
+	defer values.Close()
+	values.SeekGE(prefix)
+	now := time.Now()
+	for values.Next() {
+		v := values.Value()
+		if !bytes.HasPrefix(v.Key, prefix) {
+			break
+		}
+		if err := checkValueNotLocked(v.Key, v.Value, now); err != nil {
+			return err
+		}
+	}
+	return values.Err()
+}
 
-	rangeInfo, err := c.Store.WriteRange(ctx, repository, NewEntryToValueIterator(it), opts...)
+// checkObjectLockRepository enforces object lock on operations that drop the objects of all the branches of a
+// repository (repository deletion and restoring its refs in place): they fail with ErrObjectLocked if a branch holds
+// an object under retention.
+func (c *Catalog) checkObjectLockRepository(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	enabled, err := c.objectLockEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return err
+	}
+	branches, err := c.Store.ListBranches(ctx, repository)
 	if err != nil {
-		return nil, nil, fmt.Errorf("writing range from entry iterator: %w", err)
+		return err
 	}
-
-	stagingToken := params.StagingToken
-	skipped := it.GetSkippedEntries()
-	if len(skipped) > 0 {
-		c.log(ctx).Warning("Skipped count:", len(skipped))
-		if stagingToken == "" {
-			stagingToken = graveler.GenerateStagingToken("import", "ingest_range").String()
+	defer branches.Close()
+	for branches.Next() {
+		if err := c.checkObjectLockPrefix(ctx, repository, branches.Value().BranchID, nil); err != nil {
+			return err
 		}
+	}
+	return branches.Err()
+}
 
-		for _, obj := range skipped {
-			p := params.Prepend + obj.RelativeKey
-			entryRecord := objectStoreEntryToEntryRecord(obj, p)
-			entry, err := EntryToValue(entryRecord.Entry)
-			if err != nil {
-				return nil, nil, fmt.Errorf("parsing entry: %w", err)
-			}
-			if err := c.Store.StageObject(ctx, stagingToken, graveler.ValueRecord{
-				Key:   graveler.Key(entryRecord.Path),
-				Value: entry,
-			}); err != nil {
-				return nil, nil, fmt.Errorf("staging skipped keys: %w", err)
-			}
-		}
+func validateRetentionMode(mode string) error {
+	if mode != RetentionModeGovernance && mode != RetentionModeCompliance {
+		return fmt.Errorf("retention mode '%s': %w", mode, graveler.ErrInvalidValue)
 	}
-	mark := it.Marker()
-	mark.StagingToken = stagingToken
+	return nil
+}
 
-	return rangeInfo, &mark, nil
+func validateObjectRetention(retention *ObjectRetention, now time.Time) error {
+	if err := validateRetentionMode(retention.Mode); err != nil {
+		return err
+	}
+	if !retention.RetainUntilDate.After(now) {
+		return fmt.Errorf("retain until date must be in the future: %w", graveler.ErrInvalidValue)
+	}
+	return nil
 }
 
-func (c *Catalog) WriteMetaRange(ctx context.Context, repositoryID string, ranges []*graveler.RangeInfo, opts ...graveler.SetOptionsFunc) (*graveler.MetaRangeInfo, error) {
+func (c *Catalog) GetBranchProtectionRules(ctx context.Context, repositoryID string) (*graveler.BranchProtectionRules, *string, error) {
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return c.Store.WriteMetaRange(ctx, repository, ranges, opts...)
+
+	return c.Store.GetBranchProtectionRules(ctx, repository)
 }
 
-func (c *Catalog) GetGarbageCollectionRules(ctx context.Context, repositoryID string) (*graveler.GarbageCollectionRules, error) {
+func (c *Catalog) SetBranchProtectionRules(ctx context.Context, repositoryID string, rules *graveler.BranchProtectionRules, lastKnownChecksum *string) error {
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return c.Store.GetGarbageCollectionRules(ctx, repository)
+	return c.Store.SetBranchProtectionRules(ctx, repository, rules, lastKnownChecksum)
 }
 
-func (c *Catalog) SetGarbageCollectionRules(ctx context.Context, repositoryID string, rules *graveler.GarbageCollectionRules) error {
+func (c *Catalog) ListLegalHolds(ctx context.Context, repositoryID string) ([]*LegalHold, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return c.Store.SetGarbageCollectionRules(ctx, repository, rules)
+	holds, err := c.Store.GetLegalHolds(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*LegalHold, 0, len(holds.Commits)+len(holds.Tags))
+	for id, hold := range holds.Commits {
+		result = append(result, newLegalHold(LegalHoldKindCommit, id, hold))
+	}
+	for id, hold := range holds.Tags {
+		result = append(result, newLegalHold(LegalHoldKindTag, id, hold))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
 }
 
-func (c *Catalog) GetBranchProtectionRules(ctx context.Context, repositoryID string) (*graveler.BranchProtectionRules, *string, error) {
+func newLegalHold(kind, id string, hold *graveler.LegalHold) *LegalHold {
+	return &LegalHold{
+		Kind:         kind,
+		ID:           id,
+		Reason:       hold.Reason,
+		CreatedBy:    hold.CreatedBy,
+		CreationDate: hold.CreationDate.AsTime(),
+	}
+}
+
+// SetLegalHold places a legal hold on a commit, given by any reference resolving to it, or on a tag
+func (c *Catalog) SetLegalHold(ctx context.Context, repositoryID, kind, id, reason, createdBy string) (*LegalHold, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	hold := &graveler.LegalHold{
+		Reason:       reason,
+		CreatedBy:    createdBy,
+		CreationDate: timestamppb.Now(),
+	}
+	switch kind {
+	case LegalHoldKindCommit:
+		commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(id))
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Store.SetCommitLegalHold(ctx, repository, commitID, hold); err != nil {
+			return nil, err
+		}
+		return newLegalHold(kind, commitID.String(), hold), nil
+	case LegalHoldKindTag:
+		tagID := graveler.TagID(id)
+		if err := graveler.ValidateTagID(tagID); err != nil {
+			return nil, err
+		}
+		if err := c.Store.SetTagLegalHold(ctx, repository, tagID, hold); err != nil {
+			return nil, err
+		}
+		return newLegalHold(kind, id, hold), nil
+	default:
+		return nil, fmt.Errorf("legal hold kind %s: %w", kind, graveler.ErrInvalidValue)
 	}
-
-	return c.Store.GetBranchProtectionRules(ctx, repository)
 }
 
-func (c *Catalog) SetBranchProtectionRules(ctx context.Context, repositoryID string, rules *graveler.BranchProtectionRules, lastKnownChecksum *string) error {
+// DeleteLegalHold releases the legal hold of a commit or a tag
+func (c *Catalog) DeleteLegalHold(ctx context.Context, repositoryID, kind, id string) error {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return err
+	}
 	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
 		return err
 	}
-	return c.Store.SetBranchProtectionRules(ctx, repository, rules, lastKnownChecksum)
+	switch kind {
+	case LegalHoldKindCommit:
+		commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(id))
+		if err != nil {
+			return err
+		}
+		return c.Store.DeleteCommitLegalHold(ctx, repository, commitID)
+	case LegalHoldKindTag:
+		return c.Store.DeleteTagLegalHold(ctx, repository, graveler.TagID(id))
+	default:
+		return fmt.Errorf("legal hold kind %s: %w", kind, graveler.ErrInvalidValue)
+	}
 }
 
 func (c *Catalog) PrepareExpiredCommits(ctx context.Context, repositoryID string) (*graveler.GarbageCollectionRunMetadata, error) {
@@ -2499,15 +4073,55 @@ func (c *Catalog) PrepareExpiredCommits(ctx context.Context, repositoryID string
 	if err != nil {
 		return nil, err
 	}
-	return c.Store.SaveGarbageCollectionCommits(ctx, repository)
+	gcLease, err := c.acquireGCLease(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer c.releaseLease(ctx, gcLease)
+	runMetadata, err := c.Store.SaveGarbageCollectionCommits(gcLease.Keep(ctx), repository)
+	if err != nil {
+		return nil, err
+	}
+	// Fence the result: a run prepared while another instance held the lease must not be used
+	if err := gcLease.Check(ctx); err != nil {
+		return nil, fmt.Errorf("prepare garbage collection: %w", err)
+	}
+	return runMetadata, nil
+}
+
+// acquireGCLease acquires the lease preparing garbage collection of repository, held by one lakeFS instance at a time
+func (c *Catalog) acquireGCLease(ctx context.Context, repository *graveler.RepositoryRecord) (*lease.Lease, error) {
+	gcLease, err := lease.Acquire(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(graveler.GCLeasePath()), "gc "+xid.New().String(), leaseTTL)
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		return nil, fmt.Errorf("prepare garbage collection: %w: %s", graveler.ErrConflictFound, err)
+	}
+	return gcLease, err
+}
+
+// AcquireReplicationLease acquires the lease replicating repository, held by one lakeFS instance at a time. The
+// lease must be released by the caller.
+func (c *Catalog) AcquireReplicationLease(ctx context.Context, repositoryID string) (*lease.Lease, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return lease.Acquire(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(graveler.ReplicationLeasePath()), "replication "+xid.New().String(), leaseTTL)
+}
+
+func (c *Catalog) releaseLease(ctx context.Context, l *lease.Lease) {
+	if err := l.Release(context.WithoutCancel(ctx)); err != nil {
+		c.log(ctx).WithError(err).Warn("Failed to release lease")
+	}
 }
 
 // GCUncommittedMark Marks the *next* item to be scanned by the paginated call to PrepareGCUncommitted
 type GCUncommittedMark struct {
 	BranchID graveler.BranchID `json:"branch"`
-	Path     Path              `json:"path"`
-	RunID    string            `json:"run_id"`
-	Key      string            `json:"key"`
+	// StashID is set once all branches are scanned, while scanning stashes
+	StashID graveler.StashID `json:"stash,omitempty"`
+	Path    Path             `json:"path"`
+	RunID   string           `json:"run_id"`
+	Key     string           `json:"key"`
 }
 
 type PrepareGCUncommittedInfo struct {
@@ -2556,6 +4170,12 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 	if err != nil {
 		return nil, err
 	}
+	gcLease, err := c.acquireGCLease(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer c.releaseLease(ctx, gcLease)
+	ctx = gcLease.Keep(ctx)
 
 	var runID string
 	if mark == nil {
@@ -2594,6 +4214,11 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 			return nil, err
 		}
 
+		// Fence the upload: another instance that took over the GC lease has a newer token
+		if err := gcLease.Check(ctx); err != nil {
+			return nil, fmt.Errorf("prepare uncommitted garbage collection: %w", err)
+		}
+
 		name, err = c.uploadFile(ctx, repository.StorageNamespace, uncommittedLocation, fd, uw.Size())
 		if err != nil {
 			return nil, err
@@ -2608,6 +4233,70 @@ func (c *Catalog) PrepareGCUncommitted(ctx context.Context, repositoryID string,
 	}, nil
 }
 
+// UpdateEntryMetadata stages an entry with the user metadata and content type of an object on a branch replaced,
+// pointing at the same physical address. A nil metadata or contentType keeps the current value.
+func (c *Catalog) UpdateEntryMetadata(ctx context.Context, repositoryID string, branch string, path string, metadata Metadata, contentType *string, tags map[string]string, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	if err := validateEntryTags(tags); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	key := graveler.Key(path)
+	val, err := c.Store.Get(ctx, repository, graveler.Ref(branchID), key)
+	if err != nil {
+		return nil, err
+	}
+	ent, err := ValueToEntry(val)
+	if err != nil {
+		return nil, err
+	}
+	if metadata != nil {
+		ent.Metadata = metadata
+	}
+	if contentType != nil {
+		ent.ContentType = ContentTypeOrDefault(*contentType)
+	}
+	if tags != nil {
+		ent.Tags = tags
+	}
+	ent.LastModified = timestamppb.Now()
+	value, err := EntryToValue(ent)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Store.Set(ctx, repository, branchID, key, *value, opts...); err != nil {
+		return nil, err
+	}
+	entry := newCatalogEntryFromEntry(false, path, ent)
+	return &entry, nil
+}
+
+// validateEntryTags checks that object tags follow the limits of S3 object tags
+func validateEntryTags(tags map[string]string) error {
+	if len(tags) > MaxCostAttributionTags {
+		return fmt.Errorf("%d object tags, up to %d allowed: %w", len(tags), MaxCostAttributionTags, graveler.ErrInvalidValue)
+	}
+	for k, v := range tags {
+		if k == "" || len(k) > maxCostAttributionTagKeyLength {
+			return fmt.Errorf("object tag key %q must be 1 to %d characters: %w", k, maxCostAttributionTagKeyLength, graveler.ErrInvalidValue)
+		}
+		if len(v) > maxCostAttributionTagValueLength {
+			return fmt.Errorf("object tag %s value must be up to %d characters: %w", k, maxCostAttributionTagValueLength, graveler.ErrInvalidValue)
+		}
+	}
+	return nil
+}
+
 // CopyEntry copy entry information by using the block adapter to make a copy of the data to a new physical address.
 func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath, destRepository, destBranch, destPath string, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
 	// copyObjectFull copy data from srcEntry's physical address (if set) or srcPath into destPath
@@ -2637,6 +4326,12 @@ func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath,
 	dstEntry.Path = destPath
 	dstEntry.AddressType = AddressTypeRelative
 	dstEntry.PhysicalAddress = c.PathProvider.NewPath()
+	// the copy gets the default retention of the destination, as a new object
+	dstEntry.Retention = nil
+	// the data is copied in the default storage class of the underlying storage
+	dstEntry.StorageClass = ""
+	// the copy is not the imported object
+	dstEntry.Source = nil
 	srcObject := block.ObjectPointer{
 		StorageNamespace: srcRepo.StorageNamespace,
 		IdentifierType:   srcEntry.AddressType.ToIdentifierType(),
@@ -2707,6 +4402,21 @@ func (c *Catalog) DeleteExpiredImports(ctx context.Context) {
 	}
 }
 
+func (c *Catalog) DeleteExpiredDeletedBranches(ctx context.Context) {
+	repos, err := c.listRepositoriesHelper(ctx)
+	if err != nil {
+		c.log(ctx).WithError(err).Warn("Delete expired deleted branches: failed to list repositories")
+		return
+	}
+
+	for _, repo := range repos {
+		err = c.Store.DeleteExpiredDeletedBranches(ctx, repo)
+		if err != nil {
+			c.log(ctx).WithError(err).WithField("repository", repo.RepositoryID).Warn("Delete expired deleted branches failed")
+		}
+	}
+}
+
 func (c *Catalog) DeleteExpiredTasks(ctx context.Context) {
 	repos, err := c.listRepositoriesHelper(ctx)
 	if err != nil {
@@ -2740,6 +4450,8 @@ func (c *Catalog) listRepositoriesHelper(ctx context.Context) ([]*graveler.Repos
 }
 
 func (c *Catalog) Close() error {
+	// background tasks use the managers
+	c.workPool.StopAndWaitFor(workersMaxDrainDuration)
 	var errs error
 	for _, manager := range c.managers {
 		err := manager.Close()
@@ -2747,7 +4459,6 @@ func (c *Catalog) Close() error {
 			_ = multierror.Append(errs, err)
 		}
 	}
-	c.workPool.StopAndWaitFor(workersMaxDrainDuration)
 	return errs
 }
 
@@ -2792,6 +4503,20 @@ func newCatalogEntryFromEntry(commonPrefix bool, path string, ent *Entry) DBEntr
 		b.Expired(false)
 		b.AddressType(addressTypeToCatalog(ent.AddressType))
 		b.ContentType(ContentTypeOrDefault(ent.ContentType))
+		b.StorageClass(ent.StorageClass)
+		b.Tags(ent.Tags)
+		if ent.Retention != nil {
+			b.Retention(&ObjectRetention{
+				Mode:            ent.Retention.Mode,
+				RetainUntilDate: ent.Retention.RetainUntilDate.AsTime(),
+			})
+		}
+		if ent.Source != nil {
+			b.Source(&ObjectSource{
+				ETag:      ent.Source.ETag,
+				VersionID: ent.Source.VersionId,
+			})
+		}
 	}
 	return b.Build()
 }
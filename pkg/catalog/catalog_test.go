@@ -14,10 +14,12 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/cache"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	cUtils "github.com/treeverse/lakefs/pkg/catalog/testutils"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/xitongsys/parquet-go-source/buffer"
 	"github.com/xitongsys/parquet-go/reader"
@@ -168,6 +170,67 @@ func TestCatalog_ListRepositories(t *testing.T) {
 	}
 }
 
+func TestCatalog_SearchCommits(t *testing.T) {
+	// commits c00..c19, every fifth made by bob
+	var records []*graveler.CommitRecord
+	for i := 0; i < 20; i++ {
+		committer := "alice"
+		if i%5 == 0 {
+			committer = "bob"
+		}
+		records = append(records, &graveler.CommitRecord{
+			CommitID: graveler.CommitID(fmt.Sprintf("c%02d", i)),
+			Commit:   &graveler.Commit{Committer: committer},
+		})
+	}
+	c := &catalog.Catalog{
+		Store: &catalog.FakeGraveler{
+			CommitIteratorFactory: func() graveler.CommitIterator { return gUtils.NewFakeCommitIterator(records) },
+		},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		amount    int
+		scanLimit int
+		wantPages [][]string
+	}{
+		// a full page is followed by a page of the commits left to scan, even if none of them match
+		{name: "unbounded", amount: 2, wantPages: [][]string{{"c00", "c05"}, {"c10", "c15"}, {}}},
+		{name: "single_page", amount: 10, wantPages: [][]string{{"c00", "c05", "c10", "c15"}}},
+		{name: "bounded_scan", amount: 10, scanLimit: 7, wantPages: [][]string{{"c00", "c05"}, {"c10"}, {"c15"}}},
+		{name: "empty_pages", amount: 10, scanLimit: 3, wantPages: [][]string{{"c00"}, {"c05"}, {}, {"c10"}, {}, {"c15"}, {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				after string
+				pages [][]string
+			)
+			for {
+				commits, next, err := c.SearchCommits(ctx, "repo", catalog.SearchCommitsParams{
+					After:     after,
+					Amount:    tt.amount,
+					Filter:    catalog.CommitFilter{Committer: "bob"},
+					ScanLimit: tt.scanLimit,
+				})
+				require.NoError(t, err)
+				page := make([]string, 0, len(commits))
+				for _, commit := range commits {
+					page = append(page, commit.Reference)
+				}
+				pages = append(pages, page)
+				if next == "" {
+					break
+				}
+				after = next
+			}
+			require.Equal(t, tt.wantPages, pages)
+		})
+	}
+}
+
 func TestCatalog_BranchExists(t *testing.T) {
 	// prepare branch data
 	gravelerData := []*graveler.BranchRecord{
@@ -525,11 +588,169 @@ func TestCatalog_ListEntries(t *testing.T) {
 	}
 }
 
+func TestCatalog_ListEntriesDirectoryListingCache(t *testing.T) {
+	now := time.Now()
+	gravelerData := []*graveler.ValueRecord{
+		{Key: graveler.Key("file1"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "file1", Size: 1, ETag: "01"})},
+		{Key: graveler.Key("h/file1"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "h/file1", LastModified: timestamppb.New(now), Size: 1, ETag: "01"})},
+		{Key: graveler.Key("h/file2"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "h/file2", LastModified: timestamppb.New(now), Size: 2, ETag: "02"})},
+		{Key: graveler.Key("h/i/file1"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "h/i/file1", Size: 1, ETag: "01"})},
+		{Key: graveler.Key("h/i/file2"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "h/i/file2", Size: 2, ETag: "02"})},
+		{Key: graveler.Key("j/file1"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "j/file1", Size: 1, ETag: "01"})},
+	}
+	stagingData := map[graveler.StagingToken][]*graveler.ValueRecord{
+		"token-dirty": {
+			{Key: graveler.Key("h/i/file3"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "h/i/file3", Size: 3, ETag: "03"})},
+		},
+	}
+	branchData := []*graveler.BranchRecord{
+		{BranchID: "clean", Branch: &graveler.Branch{CommitID: "commit1", StagingToken: "token-clean"}},
+		{BranchID: "dirty", Branch: &graveler.Branch{CommitID: "commit1", StagingToken: "token-dirty"}},
+	}
+	newCatalog := func(maxChildren int) (*catalog.Catalog, *int) {
+		var lists int
+		c := &catalog.Catalog{
+			Store: &catalog.FakeGraveler{
+				ListIteratorFactory: func() graveler.ValueIterator {
+					lists++
+					return catalog.NewFakeValueIterator(gravelerData)
+				},
+				ListStagingIteratorFactory: func(token graveler.StagingToken) graveler.ValueIterator {
+					return catalog.NewFakeValueIterator(stagingData[token])
+				},
+				BranchIteratorFactory: gUtils.NewFakeBranchIteratorFactory(branchData),
+			},
+			DirectoryListingCache:       cache.NewCache(100, time.Hour, cache.NewJitterFn(time.Millisecond)),
+			DirectoryListingMaxChildren: maxChildren,
+		}
+		return c, &lists
+	}
+	ctx := context.Background()
+
+	t.Run("cached", func(t *testing.T) {
+		c, lists := newCatalog(0)
+		want := []*catalog.DBEntry{
+			{Path: "h/file1", PhysicalAddress: "h/file1", CreationDate: now, Size: 1, Checksum: "01", ContentType: "application/octet-stream"},
+			{Path: "h/file2", PhysicalAddress: "h/file2", CreationDate: now, Size: 2, Checksum: "02", ContentType: "application/octet-stream"},
+			{Path: "h/i/", CommonLevel: true},
+		}
+		for _, ref := range []string{"commit1", "clean", "commit1"} {
+			got, hasMore, err := c.ListEntries(ctx, "repo", ref, "h/", "", "/", -1)
+			require.NoError(t, err)
+			require.False(t, hasMore)
+			if diff := deep.Equal(got, want); diff != nil {
+				t.Errorf("ListEntries(%s) diff found %s", ref, diff)
+			}
+		}
+		require.Equal(t, 1, *lists, "listings of the same commit should be served from the cache")
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		c, _ := newCatalog(0)
+		got, hasMore, err := c.ListEntries(ctx, "repo", "commit1", "", "", "/", 1)
+		require.NoError(t, err)
+		require.True(t, hasMore)
+		require.Equal(t, []string{"file1"}, entriesPaths(got))
+
+		got, hasMore, err = c.ListEntries(ctx, "repo", "commit1", "", "file1", "/", 1)
+		require.NoError(t, err)
+		require.True(t, hasMore)
+		require.Equal(t, []string{"h/"}, entriesPaths(got))
+
+		got, hasMore, err = c.ListEntries(ctx, "repo", "commit1", "", "h/", "/", 1)
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Equal(t, []string{"j/"}, entriesPaths(got))
+	})
+
+	t.Run("staged changes", func(t *testing.T) {
+		c, lists := newCatalog(0)
+		for i := 0; i < 2; i++ {
+			_, _, err := c.ListEntries(ctx, "repo", "dirty", "h/i/", "", "/", -1)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, *lists, "listings with staged changes should not be served from the cache")
+
+		// staged changes elsewhere on the branch leave the directory cached
+		for i := 0; i < 2; i++ {
+			_, _, err := c.ListEntries(ctx, "repo", "dirty", "j/", "", "/", -1)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 3, *lists)
+	})
+
+	t.Run("too many children", func(t *testing.T) {
+		c, lists := newCatalog(2)
+		for i := 0; i < 2; i++ {
+			got, _, err := c.ListEntries(ctx, "repo", "commit1", "h/", "", "/", -1)
+			require.NoError(t, err)
+			require.Equal(t, []string{"h/file1", "h/file2", "h/i/"}, entriesPaths(got))
+		}
+		// one scan to fill the cache, then every listing falls back to listing the commit
+		require.Equal(t, 3, *lists)
+	})
+}
+
+func entriesPaths(entries []*catalog.DBEntry) []string {
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+// BenchmarkCatalog_ListEntries lists a partition of a Hive style table, the way partition discovery does
+func BenchmarkCatalog_ListEntries(b *testing.B) {
+	const (
+		partitions        = 1000
+		filesPerPartition = 10
+	)
+	var gravelerData []*graveler.ValueRecord
+	for p := 0; p < partitions; p++ {
+		for f := 0; f < filesPerPartition; f++ {
+			key := fmt.Sprintf("table/date=%04d/part-%03d.parquet", p, f)
+			gravelerData = append(gravelerData, &graveler.ValueRecord{
+				Key:   graveler.Key(key),
+				Value: catalog.MustEntryToValue(&catalog.Entry{Address: key, Size: 1, ETag: "01"}),
+			})
+		}
+	}
+	for _, bm := range []struct {
+		name  string
+		cache cache.Cache
+	}{
+		{name: "no cache"},
+		{name: "directory listing cache", cache: cache.NewCache(partitions, time.Hour, cache.NewJitterFn(time.Millisecond))},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			c := &catalog.Catalog{
+				Store: &catalog.FakeGraveler{
+					ListIteratorFactory: catalog.NewFakeValueIteratorFactory(gravelerData),
+				},
+				DirectoryListingCache: bm.cache,
+			}
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				prefix := fmt.Sprintf("table/date=%04d/", i%partitions)
+				entries, _, err := c.ListEntries(ctx, "repo", "commit1", prefix, "", "/", -1)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(entries) != filesPerPartition {
+					b.Fatalf("listed %d entries, expected %d", len(entries), filesPerPartition)
+				}
+			}
+		})
+	}
+}
+
 func TestCatalog_PrepareGCUncommitted(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
 		name                   string
 		numBranch              int
+		numStashes             int
 		numRecords             int
 		expectedCalls          int
 		expectedForUncommitted int
@@ -552,6 +773,13 @@ func TestCatalog_PrepareGCUncommitted(t *testing.T) {
 			numRecords:    3,
 			expectedCalls: 1,
 		},
+		{
+			name:          "stashes",
+			numBranch:     2,
+			numStashes:    3,
+			numRecords:    3,
+			expectedCalls: 1,
+		},
 		{
 			name:          "tokenized",
 			numBranch:     500,
@@ -562,13 +790,13 @@ func TestCatalog_PrepareGCUncommitted(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			const repositoryID = "repo1"
-			g, expectedRecords := createPrepareUncommittedTestScenario(t, repositoryID, tt.numBranch, tt.numRecords, tt.expectedCalls)
+			g, expectedRecords := createPrepareUncommittedTestScenario(t, repositoryID, tt.numBranch, tt.numStashes, tt.numRecords, tt.expectedCalls)
 			blockAdapter := testutil.NewBlockAdapterByType(t, block.BlockstoreTypeMem)
 			c := &catalog.Catalog{
 				Store:                 g.Sut,
 				BlockAdapter:          blockAdapter,
 				UGCPrepareMaxFileSize: 500 * 1024,
-				KVStore:               g.KVStore,
+				KVStore:               kvtest.GetStore(ctx, t),
 			}
 
 			var (
@@ -614,17 +842,29 @@ func TestCatalog_PrepareGCUncommitted(t *testing.T) {
 	}
 }
 
-func createPrepareUncommittedTestScenario(t *testing.T, repositoryID string, numBranches, numRecords, expectedCalls int) (*gUtils.GravelerTest, []string) {
+func createPrepareUncommittedTestScenario(t *testing.T, repositoryID string, numBranches, numStashes, numRecords, expectedCalls int) (*gUtils.GravelerTest, []string) {
 	t.Helper()
 
 	test := gUtils.InitGravelerTest(t)
-	records := make([][]*graveler.ValueRecord, numBranches)
+	records := make([][]*graveler.ValueRecord, numBranches+numStashes)
+	tokens := make([]graveler.StagingToken, 0, numBranches+numStashes)
 	var branches []*graveler.BranchRecord
+	var stashes []*graveler.StashRecord
 	var expectedRecords []string
-	for i := 0; i < numBranches; i++ {
+	for i := 0; i < numBranches+numStashes; i++ {
+		// uncommitted changes of stashes are written after those of branches, use the same naming for both
 		branchID := graveler.BranchID(fmt.Sprintf("branch%04d", i))
 		token := graveler.StagingToken(fmt.Sprintf("%s_st%04d", branchID, i))
-		branches = append(branches, &graveler.BranchRecord{BranchID: branchID, Branch: &graveler.Branch{StagingToken: token}})
+		tokens = append(tokens, token)
+		if i < numBranches {
+			branches = append(branches, &graveler.BranchRecord{BranchID: branchID, Branch: &graveler.Branch{StagingToken: token}})
+		} else {
+			stashes = append(stashes, &graveler.StashRecord{
+				StashID:       graveler.StashID(fmt.Sprintf("stash%04d", i)),
+				BranchID:      "main",
+				StagingTokens: []graveler.StagingToken{token},
+			})
+		}
 
 		records[i] = make([]*graveler.ValueRecord, 0, numRecords)
 		for j := 0; j < numRecords; j++ {
@@ -703,11 +943,14 @@ func createPrepareUncommittedTestScenario(t *testing.T, repositoryID string, num
 
 	// expect tracked addresses does not list branches, so remove one and keep at least the first
 	test.RefManager.EXPECT().ListBranches(gomock.Any(), gomock.Any()).Times(expectedCalls).Return(gUtils.NewFakeBranchIterator(branches), nil)
-	for i := 0; i < len(branches); i++ {
+	test.RefManager.EXPECT().ListStashes(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(context.Context, *graveler.RepositoryRecord) (graveler.StashIterator, error) {
+		return gUtils.NewFakeStashIterator(stashes), nil
+	})
+	for i := 0; i < len(tokens); i++ {
 		sort.Slice(records[i], func(ii, jj int) bool {
 			return bytes.Compare(records[i][ii].Key, records[i][jj].Key) < 0
 		})
-		test.StagingManager.EXPECT().List(gomock.Any(), branches[i].StagingToken, gomock.Any()).AnyTimes().Return(cUtils.NewFakeValueIterator(records[i]))
+		test.StagingManager.EXPECT().List(gomock.Any(), tokens[i], gomock.Any()).AnyTimes().Return(cUtils.NewFakeValueIterator(records[i]))
 	}
 
 	if numRecords > 0 {
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	commentsPrefix = "comments"
+
+	// MaxObjectCommentLength is the maximal length of the body of an object comment
+	MaxObjectCommentLength = 16 * 1024
+)
+
+var ErrInvalidObjectComment = fmt.Errorf("object comment: %w", graveler.ErrInvalidValue)
+
+// ObjectCommentsPath is the KV path of the comments on the object at path, or of a comment on it when id is set.
+// The path is escaped so the comments of an object are not listed with those of the objects under it. Comment IDs
+// are ordered by creation time, so the comments of an object are listed in the order they were made.
+func ObjectCommentsPath(path, id string) string {
+	return kv.FormatPath(commentsPrefix, url.QueryEscape(path), id)
+}
+
+// CreateObjectComment comments on the object at path of reference by author, replying to the comment parentID
+// unless it is empty. The comment records the commit reference resolved to, and is listed with the comments on
+// the path at any reference.
+func (c *Catalog) CreateObjectComment(ctx context.Context, repositoryID, reference, path, author, body, parentID string) (*ObjectComment, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(reference), Fn: graveler.ValidateRef},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	if body == "" || len(body) > MaxObjectCommentLength {
+		return nil, fmt.Errorf("%w: body must be 1 to %d bytes long", ErrInvalidObjectComment, MaxObjectCommentLength)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	_, commitID, err := c.GetEntryWithCommit(ctx, repositoryID, reference, path)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != "" {
+		if _, err := c.getObjectComment(ctx, repository, path, parentID); err != nil {
+			return nil, fmt.Errorf("parent comment %s: %w", parentID, err)
+		}
+	}
+
+	comment := &ObjectComment{
+		Id:           xid.New().String(),
+		Path:         path,
+		Ref:          reference,
+		CommitId:     commitID,
+		Author:       author,
+		CreationDate: timestamppb.Now(),
+		Body:         body,
+		ParentId:     parentID,
+	}
+	err = kv.SetMsgIf(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(ObjectCommentsPath(path, comment.Id)), comment, nil)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListObjectComments lists up to limit comments on the object at path after the comment ID after, in the order
+// they were made, and reports whether there are more
+func (c *Catalog) ListObjectComments(ctx context.Context, repositoryID, path, after string, limit int) ([]*ObjectComment, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListEntriesLimitMax {
+		limit = ListEntriesLimitMax
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&ObjectComment{}).ProtoReflect().Type(),
+		graveler.RepoPartition(repository), []byte(ObjectCommentsPath(path, "")), kv.IteratorOptionsAfter([]byte(ObjectCommentsPath(path, after))))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+
+	var comments []*ObjectComment
+	for it.Next() {
+		if len(comments) == limit {
+			return comments, true, nil
+		}
+		comments = append(comments, it.Entry().Value.(*ObjectComment))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return comments, false, nil
+}
+
+func (c *Catalog) getObjectComment(ctx context.Context, repository *graveler.RepositoryRecord, path, id string) (*ObjectComment, error) {
+	comment := &ObjectComment{}
+	_, err := kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(ObjectCommentsPath(path, id)), comment)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, graveler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
@@ -0,0 +1,225 @@
+package catalog
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// DefaultCommitGraphDepth is the number of commit generations listed below the newest reference of a commit graph
+	DefaultCommitGraphDepth = 1000
+	// MaxCommitGraphDepth bounds the commit generations a commit graph request traverses
+	MaxCommitGraphDepth = 10000
+)
+
+// CommitGraphParams selects the commits of a commit graph
+type CommitGraphParams struct {
+	// Refs are the references the graph is drawn from, all the branches of the repository when empty
+	Refs []string
+	// After is the ID of the last commit of the previous page
+	After       string
+	Amount      int
+	FirstParent bool
+	Since       *time.Time
+	// Depth is the number of commit generations listed below the newest reference, DefaultCommitGraphDepth when
+	// zero and at most MaxCommitGraphDepth
+	Depth int
+}
+
+// CommitGraphNode is a commit of a commit graph, with the branches and tags pointing at it
+type CommitGraphNode struct {
+	*CommitLog
+	Branches []string
+	Tags     []string
+}
+
+// CommitGraph returns the commits reachable from the refs of params, each commit once, in topological order:
+// commits are listed by descending generation, so every commit is listed before its parents regardless of the
+// creation dates of the commits. Commits of the same generation are listed newest first. The order depends only on
+// the commits, so a page continues after the commit of params.After even if branches moved since the previous page.
+// Branches and tags of the repository are listed on the commits they point at.
+func (c *Catalog) CommitGraph(ctx context.Context, repositoryID string, params CommitGraphParams) ([]*CommitGraphNode, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, false, err
+	}
+	// disabling batching for this flow, like listing commits
+	ctx = context.WithValue(ctx, batch.SkipBatchContextKey, struct{}{})
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var after *graveler.CommitRecord
+	if params.After != "" {
+		after, err = c.getCommitRecord(ctx, repository, graveler.CommitID(params.After))
+		if errors.Is(err, graveler.ErrNotFound) {
+			return nil, false, fmt.Errorf("after commit %s: %w", params.After, graveler.ErrInvalidValue)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	branches, err := c.branchesByCommit(ctx, repository)
+	if err != nil {
+		return nil, false, err
+	}
+	tags, err := c.tagsByCommit(ctx, repository)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var heads []graveler.CommitID
+	if len(params.Refs) == 0 {
+		for commitID := range branches {
+			heads = append(heads, commitID)
+		}
+	}
+	for _, ref := range params.Refs {
+		commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(ref))
+		if err != nil {
+			return nil, false, err
+		}
+		heads = append(heads, commitID)
+	}
+
+	// commits are popped from a queue ordered by generation. Parents are of a lower generation than their children,
+	// so commits are popped in topological order, and the commits a page skips are those before its cursor.
+	queue := &commitGraphQueue{}
+	seen := make(map[graveler.CommitID]struct{})
+	var newestGeneration graveler.CommitGeneration
+	for _, head := range heads {
+		if _, ok := seen[head]; ok {
+			continue
+		}
+		seen[head] = struct{}{}
+		record, err := c.getCommitRecord(ctx, repository, head)
+		if err != nil {
+			return nil, false, err
+		}
+		newestGeneration = max(newestGeneration, record.Generation)
+		heap.Push(queue, record)
+	}
+	depth := params.Depth
+	if depth <= 0 {
+		depth = DefaultCommitGraphDepth
+	}
+	depth = min(depth, MaxCommitGraphDepth)
+	oldestGeneration := newestGeneration - graveler.CommitGeneration(depth) + 1
+
+	var nodes []*CommitGraphNode
+	for queue.Len() > 0 && len(nodes) <= params.Amount {
+		record := heap.Pop(queue).(*graveler.CommitRecord)
+		if params.Since != nil && record.CreationDate.Before(*params.Since) {
+			continue
+		}
+		if after == nil || commitGraphBefore(after, record) {
+			nodes = append(nodes, &CommitGraphNode{
+				CommitLog: CommitRecordToLog(record),
+				Branches:  branches[record.CommitID],
+				Tags:      tags[record.CommitID],
+			})
+		}
+		parents := record.Parents
+		if params.FirstParent && len(parents) > 1 {
+			parents = parents[:1]
+		}
+		for _, parentID := range parents {
+			if _, ok := seen[parentID]; ok {
+				continue
+			}
+			seen[parentID] = struct{}{}
+			parent, err := c.getCommitRecord(ctx, repository, parentID)
+			if err != nil {
+				return nil, false, err
+			}
+			if parent.Generation < oldestGeneration {
+				continue
+			}
+			heap.Push(queue, parent)
+		}
+	}
+
+	hasMore := false
+	if len(nodes) > params.Amount {
+		hasMore = true
+		nodes = nodes[:params.Amount]
+	}
+	return nodes, hasMore, nil
+}
+
+func (c *Catalog) getCommitRecord(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) (*graveler.CommitRecord, error) {
+	commit, err := c.Store.GetCommit(ctx, repository, commitID)
+	if err != nil {
+		return nil, err
+	}
+	return &graveler.CommitRecord{CommitID: commitID, Commit: commit}, nil
+}
+
+// commitGraphBefore reports whether a is listed before b by a commit graph: by descending generation, then creation
+// date and commit ID
+func commitGraphBefore(a, b *graveler.CommitRecord) bool {
+	if a.Generation != b.Generation {
+		return a.Generation > b.Generation
+	}
+	if !a.CreationDate.Equal(b.CreationDate) {
+		return a.CreationDate.After(b.CreationDate)
+	}
+	return a.CommitID > b.CommitID
+}
+
+// commitGraphQueue implements heap.Interface such that the commit listed first by a commit graph is at the root
+type commitGraphQueue []*graveler.CommitRecord
+
+func (q commitGraphQueue) Len() int           { return len(q) }
+func (q commitGraphQueue) Less(i, j int) bool { return commitGraphBefore(q[i], q[j]) }
+func (q commitGraphQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *commitGraphQueue) Push(x interface{}) {
+	*q = append(*q, x.(*graveler.CommitRecord))
+}
+
+func (q *commitGraphQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	item := old[n]
+	*q = old[:n]
+	return item
+}
+
+func (c *Catalog) branchesByCommit(ctx context.Context, repository *graveler.RepositoryRecord) (map[graveler.CommitID][]string, error) {
+	it, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	branches := make(map[graveler.CommitID][]string)
+	for it.Next() {
+		branch := it.Value()
+		branches[branch.CommitID] = append(branches[branch.CommitID], branch.BranchID.String())
+	}
+	return branches, it.Err()
+}
+
+func (c *Catalog) tagsByCommit(ctx context.Context, repository *graveler.RepositoryRecord) (map[graveler.CommitID][]string, error) {
+	it, err := c.Store.ListTags(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	tags := make(map[graveler.CommitID][]string)
+	for it.Next() {
+		tag := it.Value()
+		tags[tag.CommitID] = append(tags[tag.CommitID], tag.TagID.String())
+	}
+	return tags, it.Err()
+}
@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DatasetDescriptorFilename is the name of the file describing the dataset under its prefix
+	DatasetDescriptorFilename = "_dataset.yaml"
+
+	// MaxDatasetDescriptorSize is the maximal size of a dataset descriptor file
+	MaxDatasetDescriptorSize = 64 * 1024
+)
+
+var ErrInvalidDatasetDescriptor = fmt.Errorf("dataset descriptor: %w", graveler.ErrInvalidValue)
+
+// DatasetDescriptor describes the dataset stored under Prefix, as read from its DatasetDescriptorFilename
+type DatasetDescriptor struct {
+	// Path is the path of the descriptor file
+	Path string `yaml:"-"`
+	// Prefix is the prefix of the dataset, the directory of the descriptor file
+	Prefix string `yaml:"-"`
+	// Error is set on listed descriptors that failed to parse, and then only Path and Prefix are set
+	Error string `yaml:"-"`
+
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Owner       string            `yaml:"owner"`
+	Schema      string            `yaml:"schema"`
+	SLA         *DatasetSLA       `yaml:"sla"`
+	Tags        map[string]string `yaml:"tags"`
+}
+
+// DatasetSLA is the service level a dataset owner commits to
+type DatasetSLA struct {
+	// Freshness is the maximal delay of the dataset, as a duration like "24h"
+	Freshness string `yaml:"freshness"`
+	// Contact is where to report breaches of the SLA
+	Contact string `yaml:"contact"`
+}
+
+// IsDatasetDescriptorPath reports whether path is the path of a dataset descriptor file
+func IsDatasetDescriptorPath(path string) bool {
+	return path == DatasetDescriptorFilename || strings.HasSuffix(path, DefaultPathDelimiter+DatasetDescriptorFilename)
+}
+
+// ParseDatasetDescriptor parses and validates the content of a dataset descriptor file. Unknown fields are rejected,
+// so a misspelled field does not silently go missing from discovery.
+func ParseDatasetDescriptor(data []byte) (*DatasetDescriptor, error) {
+	if len(data) > MaxDatasetDescriptorSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidDatasetDescriptor, MaxDatasetDescriptorSize)
+	}
+	var descriptor DatasetDescriptor
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&descriptor); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty", ErrInvalidDatasetDescriptor)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDatasetDescriptor, err)
+	}
+	if err := descriptor.validate(); err != nil {
+		return nil, err
+	}
+	return &descriptor, nil
+}
+
+func (d *DatasetDescriptor) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: 'name' is required", ErrInvalidDatasetDescriptor)
+	}
+	if d.Owner == "" {
+		return fmt.Errorf("%w: 'owner' is required", ErrInvalidDatasetDescriptor)
+	}
+	if d.Schema != "" {
+		u, err := url.Parse(d.Schema)
+		if err != nil || u.Scheme == "" {
+			return fmt.Errorf("%w: 'schema' must be an absolute URL", ErrInvalidDatasetDescriptor)
+		}
+	}
+	if d.SLA != nil && d.SLA.Freshness != "" {
+		freshness, err := time.ParseDuration(d.SLA.Freshness)
+		if err != nil || freshness <= 0 {
+			return fmt.Errorf("%w: 'sla.freshness' must be a positive duration", ErrInvalidDatasetDescriptor)
+		}
+	}
+	return nil
+}
+
+// GetDataset returns the dataset descriptor of prefix at reference. prefix is empty or ends with a path delimiter.
+func (c *Catalog) GetDataset(ctx context.Context, repositoryID, reference, prefix string) (*DatasetDescriptor, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, DefaultPathDelimiter) {
+		return nil, fmt.Errorf("prefix must end with %q: %w", DefaultPathDelimiter, graveler.ErrInvalidValue)
+	}
+	p := prefix + DatasetDescriptorFilename
+	entry, err := c.GetEntry(ctx, repositoryID, reference, p, GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.readDatasetDescriptor(ctx, repository, entry)
+}
+
+// ListDatasets lists up to limit dataset descriptors found under prefix at reference, ordered by path, after the
+// descriptor path after. Descriptors that fail to parse are listed with their Error set. A call scans at most
+// DatasetsMaxScanEntries objects under prefix: it returns the path to continue listing after when there are more
+// datasets or more objects to scan, so a page may hold fewer than limit datasets, even none, and still have more.
+func (c *Catalog) ListDatasets(ctx context.Context, repositoryID, reference, prefix, after string, limit int) ([]*DatasetDescriptor, string, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(reference), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, "", false, err
+	}
+	if limit < 0 || limit > ListEntriesLimitMax {
+		limit = ListEntriesLimitMax
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, "", false, err
+	}
+	scanLeft := c.DatasetsMaxScanEntries
+	if scanLeft <= 0 {
+		scanLeft = DefaultDatasetsMaxScanEntries
+	}
+
+	var datasets []*DatasetDescriptor
+	for {
+		entries, hasMore, err := c.ListEntries(ctx, repositoryID, reference, prefix, after, "", min(scanLeft, ListEntriesLimitMax))
+		if err != nil {
+			return nil, "", false, err
+		}
+		for _, entry := range entries {
+			if !IsDatasetDescriptorPath(entry.Path) {
+				continue
+			}
+			if len(datasets) == limit {
+				return datasets, datasets[len(datasets)-1].Path, true, nil
+			}
+			descriptor, err := c.readDatasetDescriptor(ctx, repository, entry)
+			if errors.Is(err, ErrInvalidDatasetDescriptor) {
+				descriptor = &DatasetDescriptor{
+					Path:   entry.Path,
+					Prefix: strings.TrimSuffix(entry.Path, DatasetDescriptorFilename),
+					Error:  err.Error(),
+				}
+			} else if err != nil {
+				return nil, "", false, err
+			}
+			datasets = append(datasets, descriptor)
+		}
+		if !hasMore || len(entries) == 0 {
+			return datasets, "", false, nil
+		}
+		after = entries[len(entries)-1].Path
+		scanLeft -= len(entries)
+		if scanLeft <= 0 {
+			return datasets, after, true, nil
+		}
+	}
+}
+
+func (c *Catalog) readDatasetDescriptor(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry) (*DatasetDescriptor, error) {
+	if entry.Size > MaxDatasetDescriptorSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidDatasetDescriptor, MaxDatasetDescriptorSize)
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}, entry.Size)
+	if err != nil {
+		return nil, fmt.Errorf("read dataset descriptor %s: %w", entry.Path, err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(io.LimitReader(reader, MaxDatasetDescriptorSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read dataset descriptor %s: %w", entry.Path, err)
+	}
+	descriptor, err := ParseDatasetDescriptor(data)
+	if err != nil {
+		return nil, err
+	}
+	descriptor.Path = entry.Path
+	descriptor.Prefix = strings.TrimSuffix(entry.Path, DatasetDescriptorFilename)
+	return descriptor, nil
+}
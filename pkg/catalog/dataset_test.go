@@ -0,0 +1,81 @@
+package catalog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/catalog"
+)
+
+func TestParseDatasetDescriptor(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected *catalog.DatasetDescriptor
+	}{
+		{
+			name: "full",
+			data: `name: orders
+description: customer orders
+owner: data-eng@example.com
+schema: https://schemas.example.com/orders.avsc
+sla:
+  freshness: 24h
+  contact: "#data-eng"
+tags:
+  domain: sales
+`,
+			expected: &catalog.DatasetDescriptor{
+				Name:        "orders",
+				Description: "customer orders",
+				Owner:       "data-eng@example.com",
+				Schema:      "https://schemas.example.com/orders.avsc",
+				SLA:         &catalog.DatasetSLA{Freshness: "24h", Contact: "#data-eng"},
+				Tags:        map[string]string{"domain": "sales"},
+			},
+		},
+		{
+			name:     "minimal",
+			data:     "name: orders\nowner: data-eng\n",
+			expected: &catalog.DatasetDescriptor{Name: "orders", Owner: "data-eng"},
+		},
+		{name: "empty", data: ""},
+		{name: "not yaml", data: "name: [orders"},
+		{name: "missing name", data: "owner: data-eng\n"},
+		{name: "missing owner", data: "name: orders\n"},
+		{name: "unknown field", data: "name: orders\nowner: data-eng\nowners: [a]\n"},
+		{name: "relative schema", data: "name: orders\nowner: data-eng\nschema: orders.avsc\n"},
+		{name: "bad freshness", data: "name: orders\nowner: data-eng\nsla:\n  freshness: daily\n"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			descriptor, err := catalog.ParseDatasetDescriptor([]byte(tt.data))
+			if tt.expected == nil {
+				if !errors.Is(err, catalog.ErrInvalidDatasetDescriptor) {
+					t.Fatalf("ParseDatasetDescriptor() error = %v, expected %s", err, catalog.ErrInvalidDatasetDescriptor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDatasetDescriptor() error = %v", err)
+			}
+			if diff := deep.Equal(descriptor, tt.expected); diff != nil {
+				t.Error("ParseDatasetDescriptor() diff", diff)
+			}
+		})
+	}
+}
+
+func TestIsDatasetDescriptorPath(t *testing.T) {
+	for p, expected := range map[string]bool{
+		"_dataset.yaml":               true,
+		"tables/orders/_dataset.yaml": true,
+		"tables/orders_dataset.yaml":  false,
+		"tables/_dataset.yaml.bak":    false,
+	} {
+		if got := catalog.IsDatasetDescriptorPath(p); got != expected {
+			t.Errorf("IsDatasetDescriptorPath(%s) = %t, expected %t", p, got, expected)
+		}
+	}
+}
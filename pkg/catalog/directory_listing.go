@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// directoryListingKey identifies the single level listing of a directory of a commit
+type directoryListingKey struct {
+	repositoryID graveler.RepositoryID
+	commitID     graveler.CommitID
+	prefix       string
+}
+
+// directoryListing holds the children of a directory of a commit, sorted by path. A nil listing marks a directory
+// with too many children to keep.
+type directoryListing []*DBEntry
+
+// isDirectoryPrefix reports whether prefix lists a directory: the root or a path ending with the delimiter
+func isDirectoryPrefix(prefix string) bool {
+	return prefix == "" || strings.HasSuffix(prefix, DefaultPathDelimiter)
+}
+
+// getDirectoryListing returns the children of the directory prefix of reference, from the directory listing cache.
+// Directories are listed from committed data, so it returns false when reference is a branch with uncommitted
+// changes under prefix, or when the directory has too many children to keep.
+func (c *Catalog) getDirectoryListing(ctx context.Context, repository *graveler.RepositoryRecord, reference graveler.Ref, prefix string) (directoryListing, bool, error) {
+	resolved, err := c.Store.Dereference(ctx, repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if resolved.CommitID == "" {
+		return nil, false, nil
+	}
+	if resolved.Type == graveler.ReferenceTypeBranch && resolved.ResolvedBranchModifier != graveler.ResolvedBranchModifierCommitted {
+		staged, err := c.hasStagedChanges(ctx, resolved.Branch, prefix)
+		if err != nil || staged {
+			return nil, false, err
+		}
+	}
+
+	key := directoryListingKey{repositoryID: repository.RepositoryID, commitID: resolved.CommitID, prefix: prefix}
+	v, err := c.DirectoryListingCache.GetOrSet(key, func() (interface{}, error) {
+		return c.scanDirectoryListing(ctx, repository, resolved.CommitID, prefix)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	listing := v.(directoryListing)
+	return listing, listing != nil, nil
+}
+
+// hasStagedChanges reports whether the staging area of branch holds changes under prefix, even ones that leave
+// the committed value in place
+func (c *Catalog) hasStagedChanges(ctx context.Context, branch *graveler.Branch, prefix string) (bool, error) {
+	it, err := c.Store.ListStaging(ctx, branch, 1)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	it.SeekGE(graveler.Key(prefix))
+	if it.Next() {
+		return bytes.HasPrefix(it.Value().Key, []byte(prefix)), nil
+	}
+	return false, it.Err()
+}
+
+// scanDirectoryListing lists the children of the directory prefix of a commit, or returns a nil listing if it
+// has more than DirectoryListingMaxChildren children. Children are listed from the tree of the commit: ranges of
+// committed data that hold a single child directory are not read.
+func (c *Catalog) scanDirectoryListing(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string) (directoryListing, error) {
+	maxChildren := c.DirectoryListingMaxChildren
+	if maxChildren <= 0 {
+		maxChildren = DefaultDirectoryListingMaxChildren
+	}
+	it, err := c.Store.ListDirectory(ctx, repository, commitID, graveler.Key(prefix), graveler.Key(DefaultPathDelimiter))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	listing := directoryListing{}
+	for it.Next() {
+		if len(listing) == maxChildren {
+			return nil, nil
+		}
+		v := it.Value()
+		var entry DBEntry
+		if v.Value == nil {
+			entry = newCatalogEntryFromEntry(true, v.Key.String(), nil)
+		} else {
+			ent, err := ValueToEntry(v.Value)
+			if err != nil {
+				return nil, err
+			}
+			entry = newCatalogEntryFromEntry(false, v.Key.String(), ent)
+		}
+		listing = append(listing, &entry)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// page returns up to limit children after the path after, keeping only children matching filter if set
+func (l directoryListing) page(after string, limit int, filter func(*DBEntry) bool) ([]*DBEntry, bool) {
+	i := sort.Search(len(l), func(i int) bool { return l[i].Path > after })
+	var entries []*DBEntry
+	for ; i < len(l); i++ {
+		// copy, callers may change the entries they get
+		entry := *l[i]
+		if filter != nil && !filter(&entry) {
+			continue
+		}
+		if len(entries) == limit {
+			return entries, true
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, false
+}
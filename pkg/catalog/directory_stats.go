@@ -0,0 +1,217 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	directoryStatsPrefix = "dirstats"
+	// directoryStatsMaxAncestors bounds the first-parent ancestors searched for kept aggregates to derive the
+	// aggregates of a commit from
+	directoryStatsMaxAncestors = 100
+	// directoryStatsMaxScanObjects bounds the objects listed to compute the aggregates of a directory that cannot be
+	// derived from an ancestor
+	directoryStatsMaxScanObjects = 100_000
+)
+
+// ErrDirectoryStatsTooLarge is returned when the aggregates of a directory would require listing too many objects
+var ErrDirectoryStatsTooLarge = errors.New("too many objects to compute directory stats")
+
+// DirectoryStatsPath is the KV path of the aggregates of a directory of a commit. The aggregates of a commit never
+// change, so they are kept until the repository is deleted.
+func DirectoryStatsPath(commitID graveler.CommitID, prefix string) string {
+	return kv.FormatPath(directoryStatsPrefix, commitID.String(), prefix)
+}
+
+// GetDirectoryStats returns the number and total size of the objects under prefix on ref, at any depth. The
+// aggregates of committed data are kept per commit and directory: they are derived from the aggregates of the nearest
+// first-parent ancestor that has them and the changes since that ancestor, and by listing the directory otherwise.
+// Listing fails with ErrDirectoryStatsTooLarge once it reaches directoryStatsMaxScanObjects objects. The uncommitted
+// changes of a branch are added on top.
+func (c *Catalog) GetDirectoryStats(ctx context.Context, repositoryID, reference, prefix string) (*DirectoryStats, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(reference), Fn: graveler.ValidateRef},
+		{Name: "prefix", Value: Path(prefix), Fn: ValidatePathOptional},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := c.Store.Dereference(ctx, repository, graveler.Ref(reference))
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.committedDirectoryStats(ctx, repository, resolved.CommitID, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.Type != graveler.ReferenceTypeBranch || resolved.ResolvedBranchModifier == graveler.ResolvedBranchModifierCommitted {
+		return stats, nil
+	}
+	it, err := c.Store.DiffUncommitted(ctx, repository, resolved.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	if err := c.addDiffToDirectoryStats(ctx, repository, resolved.CommitID, it, prefix, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c *Catalog) committedDirectoryStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string) (*DirectoryStats, error) {
+	stats, err := c.getDirectoryStats(ctx, repository, commitID, prefix)
+	if err == nil || !errors.Is(err, graveler.ErrNotFound) {
+		return stats, err
+	}
+
+	ancestorID, stats, err := c.ancestorDirectoryStats(ctx, repository, commitID, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if stats != nil {
+		it, err := c.Store.Diff(ctx, repository, graveler.Ref(ancestorID), graveler.Ref(commitID))
+		if err != nil {
+			return nil, err
+		}
+		err = c.addDiffToDirectoryStats(ctx, repository, ancestorID, it, prefix, stats)
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		stats, err = c.scanDirectoryStats(ctx, repository, commitID, prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(DirectoryStatsPath(commitID, prefix)), stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ancestorDirectoryStats returns the nearest first-parent ancestor of commitID with kept aggregates of prefix, and
+// these aggregates. It returns nil aggregates if none of the directoryStatsMaxAncestors nearest ancestors has them.
+func (c *Catalog) ancestorDirectoryStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string) (graveler.CommitID, *DirectoryStats, error) {
+	for i := 0; i < directoryStatsMaxAncestors; i++ {
+		commit, err := c.Store.GetCommit(ctx, repository, commitID)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(commit.Parents) == 0 {
+			return "", nil, nil
+		}
+		commitID = commit.Parents[0]
+		stats, err := c.getDirectoryStats(ctx, repository, commitID, prefix)
+		if err == nil {
+			return commitID, stats, nil
+		}
+		if !errors.Is(err, graveler.ErrNotFound) {
+			return "", nil, err
+		}
+	}
+	return "", nil, nil
+}
+
+func (c *Catalog) getDirectoryStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string) (*DirectoryStats, error) {
+	stats := &DirectoryStats{}
+	_, err := kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(DirectoryStatsPath(commitID, prefix)), stats)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, graveler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c *Catalog) scanDirectoryStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string) (*DirectoryStats, error) {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(commitID), ListEntriesLimitMax)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	stats := &DirectoryStats{}
+	it.SeekGE(graveler.Key(prefix))
+	for it.Next() {
+		v := it.Value()
+		if !bytes.HasPrefix(v.Key, []byte(prefix)) {
+			break
+		}
+		entry, err := ValueToEntry(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.Key, err)
+		}
+		if stats.ObjectCount >= directoryStatsMaxScanObjects {
+			return nil, fmt.Errorf("%s: %w", prefix, ErrDirectoryStatsTooLarge)
+		}
+		stats.ObjectCount++
+		stats.SizeBytes += entry.Size
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// addDiffToDirectoryStats adds the changes under prefix of it, a diff from baseCommitID, to stats
+func (c *Catalog) addDiffToDirectoryStats(ctx context.Context, repository *graveler.RepositoryRecord, baseCommitID graveler.CommitID, it graveler.DiffIterator, prefix string, stats *DirectoryStats) error {
+	baseSize := func(key graveler.Key) (int64, error) {
+		value, err := c.Store.GetByCommitID(ctx, repository, baseCommitID, key)
+		if err != nil {
+			return 0, err
+		}
+		entry, err := ValueToEntry(value)
+		if err != nil {
+			return 0, err
+		}
+		return entry.Size, nil
+	}
+	it.SeekGE(graveler.Key(prefix))
+	for it.Next() {
+		d := it.Value()
+		if !bytes.HasPrefix(d.Key, []byte(prefix)) {
+			break
+		}
+		switch d.Type {
+		case graveler.DiffTypeAdded:
+			entry, err := ValueToEntry(d.Value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", d.Key, err)
+			}
+			stats.ObjectCount++
+			stats.SizeBytes += entry.Size
+		case graveler.DiffTypeRemoved:
+			size, err := baseSize(d.Key)
+			if err != nil {
+				return fmt.Errorf("%s: %w", d.Key, err)
+			}
+			stats.ObjectCount--
+			stats.SizeBytes -= size
+		case graveler.DiffTypeChanged:
+			entry, err := ValueToEntry(d.Value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", d.Key, err)
+			}
+			size, err := baseSize(d.Key)
+			if err != nil {
+				return fmt.Errorf("%s: %w", d.Key, err)
+			}
+			stats.SizeBytes += entry.Size - size
+		}
+	}
+	return it.Err()
+}
@@ -18,6 +18,15 @@ func ValueToEntry(value *graveler.Value) (*Entry, error) {
 	return &ent, nil
 }
 
+// valueSize returns the size of the object of an entry value
+func valueSize(value *graveler.Value) (int64, error) {
+	entry, err := ValueToEntry(value)
+	if err != nil {
+		return 0, err
+	}
+	return entry.GetSize(), nil
+}
+
 func EntryToValue(entry *Entry) (*graveler.Value, error) {
 	// marshal data using pb
 	data, err := proto.Marshal(entry)
@@ -25,14 +34,18 @@ func EntryToValue(entry *Entry) (*graveler.Value, error) {
 		return nil, err
 	}
 	// calculate entry identity
-	checksum := ident.NewAddressWriter().
+	w := ident.NewAddressWriter().
 		MarshalInt64(entry.Size).
 		MarshalString(entry.ETag).
 		MarshalStringMap(entry.Metadata).
-		MarshalStringOpt(entry.ContentType). // optional in order to keep identity of old entries without content-type
-		Identity()
+		MarshalStringOpt(entry.ContentType) // optional in order to keep identity of old entries without content-type
+	if entry.Retention != nil {
+		// only entries with retention include it, in order to keep identity of entries without retention
+		w.MarshalString(entry.Retention.Mode).
+			MarshalInt64(entry.Retention.RetainUntilDate.AsTime().Unix())
+	}
 	return &graveler.Value{
-		Identity: checksum,
+		Identity: w.Identity(),
 		Data:     data,
 	}, nil
 }
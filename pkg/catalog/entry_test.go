@@ -17,6 +17,7 @@ func TestEntryToValueAndBack(t *testing.T) {
 		Size:         99,
 		ETag:         "123456789",
 		Metadata:     map[string]string{"key9": "value9", "key1": "value1"},
+		Source:       &EntrySource{ETag: "123456789", VersionId: "v1"},
 	}
 	val, err := EntryToValue(entry)
 	if err != nil {
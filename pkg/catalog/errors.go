@@ -19,4 +19,5 @@ var (
 
 	ErrFeatureNotSupported = errors.New("feature not supported")
 	ErrNonEmptyRepository  = errors.New("non empty repository")
+	ErrWriteOffsetMismatch = fmt.Errorf("write offset mismatch: %w", graveler.ErrPreconditionFailed)
 )
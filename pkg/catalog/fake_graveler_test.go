@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/committed"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
 )
 
 type FakeGraveler struct {
@@ -20,6 +22,7 @@ type FakeGraveler struct {
 	BranchIteratorFactory      func() graveler.BranchIterator
 	TagIteratorFactory         func() graveler.TagIterator
 	LinkAddressIteratorFactory func() graveler.LinkAddressIterator
+	CommitIteratorFactory      func() graveler.CommitIterator
 	hooks                      graveler.HooksHandler
 }
 
@@ -44,6 +47,14 @@ func (g *FakeGraveler) SaveGarbageCollectionCommits(_ context.Context, _ *gravel
 	panic("implement me")
 }
 
+func (g *FakeGraveler) GetGarbageCollectionCommits(_ context.Context, _ *graveler.RepositoryRecord, _ *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) GetBranchLocks(_ context.Context, _ *graveler.RepositoryRecord) (*graveler.BranchLocks, error) {
+	return &graveler.BranchLocks{}, nil
+}
+
 func (g *FakeGraveler) GetGarbageCollectionRules(_ context.Context, _ *graveler.RepositoryRecord) (*graveler.GarbageCollectionRules, error) {
 	panic("implement me")
 }
@@ -88,6 +99,14 @@ func (g *FakeGraveler) GetRange(_ context.Context, _ *graveler.RepositoryRecord,
 	panic("implement me")
 }
 
+func (g *FakeGraveler) ListRanges(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) ListRange(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.RangeID) (graveler.ValueIterator, error) {
+	panic("implement me")
+}
+
 func fakeGravelerBuildKey(repositoryID graveler.RepositoryID, ref graveler.Ref, key graveler.Key) string {
 	return strings.Join([]string{repositoryID.String(), ref.String(), key.String()}, "/")
 }
@@ -139,6 +158,28 @@ func (g *FakeGraveler) List(_ context.Context, _ *graveler.RepositoryRecord, _ g
 	return g.ListIteratorFactory(), nil
 }
 
+// ListDirectory lists the directory from the values of ListIteratorFactory, held in a single range
+func (g *FakeGraveler) ListDirectory(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.CommitID, prefix, delimiter graveler.Key) (graveler.ValueIterator, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	values := g.ListIteratorFactory()
+	defer values.Close()
+	var records []*graveler.ValueRecord
+	for values.Next() {
+		records = append(records, values.Value())
+	}
+	if err := values.Err(); err != nil {
+		return nil, err
+	}
+	it := testutil.NewFakeIterator()
+	if len(records) > 0 {
+		it.AddRange(&committed.Range{ID: "range", MinKey: committed.Key(records[0].Key), MaxKey: committed.Key(records[len(records)-1].Key)}).
+			AddValueRecords(records...)
+	}
+	return committed.NewDirectoryIterator(it, prefix, delimiter), nil
+}
+
 func (g *FakeGraveler) GetRepository(ctx context.Context, repositoryID graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
 	return &graveler.RepositoryRecord{RepositoryID: repositoryID}, nil
 }
@@ -209,6 +250,13 @@ func (g *FakeGraveler) Log(ctx context.Context, repository *graveler.RepositoryR
 	panic("implement me")
 }
 
+func (g *FakeGraveler) ListCommits(_ context.Context, _ *graveler.RepositoryRecord) (graveler.CommitIterator, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	return g.CommitIteratorFactory(), nil
+}
+
 func (g *FakeGraveler) ListBranches(_ context.Context, _ *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
 	if g.Err != nil {
 		return nil, g.Err
@@ -224,6 +272,10 @@ func (g *FakeGraveler) Commit(ctx context.Context, repository *graveler.Reposito
 	panic("implement me")
 }
 
+func (g *FakeGraveler) SealStagingToken(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, _ ...graveler.SetOptionsFunc) error {
+	panic("implement me")
+}
+
 func (g *FakeGraveler) CreateCommitRecord(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, commit graveler.Commit, opts ...graveler.SetOptionsFunc) error {
 	panic("implement me")
 }
@@ -241,7 +293,23 @@ func (g *FakeGraveler) GetCommit(ctx context.Context, repository *graveler.Repos
 }
 
 func (g *FakeGraveler) Dereference(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref) (*graveler.ResolvedRef, error) {
-	panic("implement me")
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	if g.BranchIteratorFactory != nil {
+		branch, err := g.GetBranch(ctx, repository, graveler.BranchID(ref))
+		if err == nil {
+			return &graveler.ResolvedRef{
+				Type:         graveler.ReferenceTypeBranch,
+				BranchRecord: graveler.BranchRecord{BranchID: graveler.BranchID(ref), Branch: branch},
+			}, nil
+		}
+	}
+	// any other reference is a commit
+	return &graveler.ResolvedRef{
+		Type:         graveler.ReferenceTypeCommit,
+		BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: graveler.CommitID(ref)}},
+	}, nil
 }
 
 func (g *FakeGraveler) Reset(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, _ ...graveler.SetOptionsFunc) error {
@@ -268,6 +336,10 @@ func (g *FakeGraveler) FindMergeBase(ctx context.Context, repository *graveler.R
 	panic("implement me")
 }
 
+func (g *FakeGraveler) CountDivergence(_ context.Context, _ *graveler.RepositoryRecord, _, _ graveler.CommitID) (int, int, error) {
+	panic("implement me")
+}
+
 func (g *FakeGraveler) DiffUncommitted(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (graveler.DiffIterator, error) {
 	if g.Err != nil {
 		return nil, g.Err
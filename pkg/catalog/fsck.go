@@ -0,0 +1,358 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+type FsckIssueType string
+
+const (
+	FsckIssueMissingDefaultBranch FsckIssueType = "missing_default_branch"
+	FsckIssueDanglingBranch       FsckIssueType = "dangling_branch"
+	FsckIssueDanglingTag          FsckIssueType = "dangling_tag"
+	FsckIssueDanglingParent       FsckIssueType = "dangling_parent"
+	FsckIssueOrphanCommit         FsckIssueType = "orphan_commit"
+	FsckIssueUnreadableTree       FsckIssueType = "unreadable_tree"
+	FsckIssueMissingObject        FsckIssueType = "missing_object"
+)
+
+type FsckSeverity string
+
+const (
+	FsckSeverityError   FsckSeverity = "error"
+	FsckSeverityWarning FsckSeverity = "warning"
+)
+
+// FsckOptions select what Fsck checks
+type FsckOptions struct {
+	// Repositories to check, all repositories if empty
+	Repositories []string
+	// AllCommits checks the trees of all commits, rather than those of branch heads and tags only
+	AllCommits bool
+	// SkipObjects skips checking that the physical objects of entries exist
+	SkipObjects bool
+}
+
+// FsckIssue is an integrity problem found by Fsck, with a suggestion for repairing it
+type FsckIssue struct {
+	Type     FsckIssueType `json:"type"`
+	Severity FsckSeverity  `json:"severity"`
+	Branch   string        `json:"branch,omitempty"`
+	Tag      string        `json:"tag,omitempty"`
+	CommitID string        `json:"commit_id,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Address  string        `json:"physical_address,omitempty"`
+	Message  string        `json:"message"`
+	Repair   string        `json:"repair"`
+}
+
+// FsckRepositoryReport is the result of checking a single repository
+type FsckRepositoryReport struct {
+	Repository     string      `json:"repository"`
+	Branches       int         `json:"branches"`
+	Tags           int         `json:"tags"`
+	Commits        int         `json:"commits"`
+	TreesChecked   int         `json:"trees_checked"`
+	Entries        int         `json:"entries"`
+	ObjectsChecked int         `json:"objects_checked"`
+	Issues         []FsckIssue `json:"issues"`
+}
+
+// FsckReport is the result of Fsck
+type FsckReport struct {
+	StartTime    time.Time               `json:"start_time"`
+	EndTime      time.Time               `json:"end_time"`
+	Repositories []*FsckRepositoryReport `json:"repositories"`
+}
+
+// Errors returns the number of issues of severity error in the report
+func (r *FsckReport) Errors() int {
+	count := 0
+	for _, repo := range r.Repositories {
+		for _, issue := range repo.Issues {
+			if issue.Severity == FsckSeverityError {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Fsck validates the referential integrity of repositories: branches and tags point to existing commits, commits
+// point to existing parents and readable trees, and the entries of the trees point to existing physical objects.
+// Problems are reported as issues rather than errors; an error is returned only when the check cannot proceed.
+func (c *Catalog) Fsck(ctx context.Context, opts FsckOptions) (*FsckReport, error) {
+	report := &FsckReport{StartTime: time.Now(), Repositories: []*FsckRepositoryReport{}}
+	repositories, err := c.fsckRepositories(ctx, opts.Repositories)
+	if err != nil {
+		return nil, err
+	}
+	for _, repository := range repositories {
+		repoReport, err := c.fsckRepository(ctx, repository, opts)
+		if err != nil {
+			return nil, fmt.Errorf("check repository %s: %w", repository.RepositoryID, err)
+		}
+		report.Repositories = append(report.Repositories, repoReport)
+	}
+	report.EndTime = time.Now()
+	return report, nil
+}
+
+func (c *Catalog) fsckRepositories(ctx context.Context, names []string) ([]*graveler.RepositoryRecord, error) {
+	var repositories []*graveler.RepositoryRecord
+	if len(names) > 0 {
+		for _, name := range names {
+			repository, err := c.getRepository(ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("repository %s: %w", name, err)
+			}
+			repositories = append(repositories, repository)
+		}
+		return repositories, nil
+	}
+	it, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.Next() {
+		repository := it.Value()
+		if repository.State != graveler.RepositoryState_ACTIVE {
+			continue
+		}
+		repositories = append(repositories, repository)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return repositories, nil
+}
+
+func (c *Catalog) fsckRepository(ctx context.Context, repository *graveler.RepositoryRecord, opts FsckOptions) (*FsckRepositoryReport, error) {
+	report := &FsckRepositoryReport{Repository: repository.RepositoryID.String(), Issues: []FsckIssue{}}
+	ctx, err := c.withBlockAdapterOverride(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	// commits
+	commits := make(map[graveler.CommitID]*graveler.Commit)
+	var commitIDs []graveler.CommitID
+	commitsIt, err := c.Store.ListCommits(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for commitsIt.Next() {
+		record := commitsIt.Value()
+		commits[record.CommitID] = record.Commit
+		commitIDs = append(commitIDs, record.CommitID)
+	}
+	err = commitsIt.Err()
+	commitsIt.Close()
+	if err != nil {
+		return nil, err
+	}
+	report.Commits = len(commits)
+
+	// branches and tags, the roots of reachable commits
+	var roots []graveler.CommitID
+	hasDefaultBranch := false
+	branchesIt, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for branchesIt.Next() {
+		branch := branchesIt.Value()
+		report.Branches++
+		if branch.BranchID == repository.DefaultBranchID {
+			hasDefaultBranch = true
+		}
+		if _, ok := commits[branch.CommitID]; !ok {
+			report.Issues = append(report.Issues, FsckIssue{
+				Type:     FsckIssueDanglingBranch,
+				Severity: FsckSeverityError,
+				Branch:   branch.BranchID.String(),
+				CommitID: branch.CommitID.String(),
+				Message:  "branch head commit not found",
+				Repair:   "restore the commit record from a backup of the KV store, or delete the branch and recreate it from an existing commit",
+			})
+			continue
+		}
+		roots = append(roots, branch.CommitID)
+	}
+	err = branchesIt.Err()
+	branchesIt.Close()
+	if err != nil {
+		return nil, err
+	}
+	if !hasDefaultBranch {
+		report.Issues = append(report.Issues, FsckIssue{
+			Type:     FsckIssueMissingDefaultBranch,
+			Severity: FsckSeverityError,
+			Branch:   repository.DefaultBranchID.String(),
+			Message:  "default branch not found",
+			Repair:   "restore the branch record from a backup of the KV store, or create the branch from an existing commit",
+		})
+	}
+	tagsIt, err := c.Store.ListTags(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for tagsIt.Next() {
+		tag := tagsIt.Value()
+		report.Tags++
+		if _, ok := commits[tag.CommitID]; !ok {
+			report.Issues = append(report.Issues, FsckIssue{
+				Type:     FsckIssueDanglingTag,
+				Severity: FsckSeverityError,
+				Tag:      tag.TagID.String(),
+				CommitID: tag.CommitID.String(),
+				Message:  "tagged commit not found",
+				Repair:   "restore the commit record from a backup of the KV store, or delete the tag",
+			})
+			continue
+		}
+		roots = append(roots, tag.CommitID)
+	}
+	err = tagsIt.Err()
+	tagsIt.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// parents and reachability, including the commits of deleted branches that may still be restored
+	queue := append([]graveler.CommitID(nil), roots...)
+	deletedIt, err := c.Store.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for deletedIt.Next() {
+		queue = append(queue, deletedIt.Value().CommitID)
+	}
+	err = deletedIt.Err()
+	deletedIt.Close()
+	if err != nil {
+		return nil, err
+	}
+	reachable := make(map[graveler.CommitID]struct{})
+	for len(queue) > 0 {
+		commitID := queue[0]
+		queue = queue[1:]
+		commit, ok := commits[commitID]
+		if !ok {
+			continue
+		}
+		if _, ok := reachable[commitID]; ok {
+			continue
+		}
+		reachable[commitID] = struct{}{}
+		queue = append(queue, commit.Parents...)
+	}
+	for _, commitID := range commitIDs {
+		commit := commits[commitID]
+		for _, parent := range commit.Parents {
+			if _, ok := commits[parent]; !ok {
+				report.Issues = append(report.Issues, FsckIssue{
+					Type:     FsckIssueDanglingParent,
+					Severity: FsckSeverityError,
+					CommitID: commitID.String(),
+					Message:  fmt.Sprintf("parent commit %s not found", parent),
+					Repair:   "restore the parent commit record from a backup of the KV store; until then the history of the commit ends at it",
+				})
+			}
+		}
+		if _, ok := reachable[commitID]; !ok {
+			report.Issues = append(report.Issues, FsckIssue{
+				Type:     FsckIssueOrphanCommit,
+				Severity: FsckSeverityWarning,
+				CommitID: commitID.String(),
+				Message:  "commit not reachable from any branch or tag",
+				Repair:   "expected after deleting branches, removed by garbage collection; create a branch or a tag on the commit to keep it",
+			})
+		}
+	}
+
+	// trees and objects
+	treeCommits := roots
+	if opts.AllCommits {
+		treeCommits = commitIDs
+	}
+	checkedTrees := make(map[graveler.MetaRangeID]struct{})
+	checkedObjects := make(map[string]struct{})
+	for _, commitID := range treeCommits {
+		metaRangeID := commits[commitID].MetaRangeID
+		if _, ok := checkedTrees[metaRangeID]; ok {
+			continue
+		}
+		checkedTrees[metaRangeID] = struct{}{}
+		if err := c.fsckTree(ctx, repository, commitID, opts, checkedObjects, report); err != nil {
+			return nil, err
+		}
+		report.TreesChecked++
+	}
+	return report, nil
+}
+
+// fsckTree checks that the tree of commitID is readable, and unless skipped, that the physical objects of its entries
+// exist. Objects in checkedObjects are not checked again.
+func (c *Catalog) fsckTree(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, opts FsckOptions, checkedObjects map[string]struct{}, report *FsckRepositoryReport) error {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(commitID), ListEntriesLimitMax)
+	if err == nil {
+		defer it.Close()
+		for it.Next() {
+			value := it.Value()
+			report.Entries++
+			if opts.SkipObjects {
+				continue
+			}
+			entry, err := ValueToEntry(value.Value)
+			if err != nil {
+				return fmt.Errorf("decode entry %s: %w", value.Key, err)
+			}
+			if _, ok := checkedObjects[entry.Address]; ok {
+				continue
+			}
+			checkedObjects[entry.Address] = struct{}{}
+			report.ObjectsChecked++
+			exists, err := c.BlockAdapter.Exists(ctx, block.ObjectPointer{
+				StorageNamespace: repository.StorageNamespace.String(),
+				IdentifierType:   addressTypeToCatalog(entry.AddressType).ToIdentifierType(),
+				Identifier:       entry.Address,
+			})
+			if err != nil && !errors.Is(err, block.ErrDataNotFound) {
+				return fmt.Errorf("check object %s: %w", entry.Address, err)
+			}
+			if !exists {
+				report.Issues = append(report.Issues, FsckIssue{
+					Type:     FsckIssueMissingObject,
+					Severity: FsckSeverityError,
+					CommitID: commitID.String(),
+					Path:     string(value.Key),
+					Address:  entry.Address,
+					Message:  "physical object not found",
+					Repair:   "restore the object from a backup of the storage namespace, or delete the path and commit",
+				})
+			}
+		}
+		err = it.Err()
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		report.Issues = append(report.Issues, FsckIssue{
+			Type:     FsckIssueUnreadableTree,
+			Severity: FsckSeverityError,
+			CommitID: commitID.String(),
+			Message:  err.Error(),
+			Repair:   "restore the metarange and range files under _lakefs/ of the storage namespace from a backup",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package catalog_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+func TestCatalog_Fsck(t *testing.T) {
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	cfg, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       cfg,
+		KVStore:      kvtest.GetStore(ctx, t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	const (
+		repoName         = "repo"
+		storageNamespace = "mem://repo"
+	)
+	_, err = c.CreateRepository(ctx, repoName, storageNamespace, "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	// an object with data, and one whose data is missing
+	data := "data"
+	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       "data/present",
+	}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
+	testutil.MustDo(t, "put object", err)
+	for path, address := range map[string]string{"present": "data/present", "missing": "data/missing"} {
+		err = c.CreateEntry(ctx, repoName, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: address,
+			AddressType:     catalog.AddressTypeRelative,
+			Size:            int64(len(data)),
+			Checksum:        address,
+			CreationDate:    time.Now(),
+		})
+		testutil.MustDo(t, "create entry", err)
+	}
+	commit, err := c.Commit(ctx, repoName, "main", "objects", "tester", nil, nil, nil, false)
+	testutil.MustDo(t, "commit", err)
+	_, err = c.CreateTag(ctx, repoName, "v1", commit.Reference)
+	testutil.MustDo(t, "create tag", err)
+
+	t.Run("healthy", func(t *testing.T) {
+		report, err := c.Fsck(ctx, catalog.FsckOptions{SkipObjects: true})
+		require.NoError(t, err)
+		require.Len(t, report.Repositories, 1)
+		repoReport := report.Repositories[0]
+		require.Equal(t, repoName, repoReport.Repository)
+		require.Equal(t, 1, repoReport.Branches)
+		require.Equal(t, 1, repoReport.Tags)
+		require.Equal(t, 2, repoReport.Commits)
+		require.Equal(t, 1, repoReport.TreesChecked)
+		require.Equal(t, 2, repoReport.Entries)
+		require.Empty(t, repoReport.Issues)
+		require.Zero(t, report.Errors())
+	})
+
+	t.Run("missing_object", func(t *testing.T) {
+		report, err := c.Fsck(ctx, catalog.FsckOptions{Repositories: []string{repoName}})
+		require.NoError(t, err)
+		repoReport := report.Repositories[0]
+		require.Equal(t, 2, repoReport.ObjectsChecked)
+		require.Len(t, repoReport.Issues, 1)
+		issue := repoReport.Issues[0]
+		require.Equal(t, catalog.FsckIssueMissingObject, issue.Type)
+		require.Equal(t, "missing", issue.Path)
+		require.Equal(t, "data/missing", issue.Address)
+		require.Equal(t, 1, report.Errors())
+	})
+
+	t.Run("dangling_parent", func(t *testing.T) {
+		repository, err := c.Store.GetRepository(ctx, repoName)
+		testutil.MustDo(t, "get repository", err)
+		orphan := graveler.Commit{
+			Version:      graveler.CurrentCommitVersion,
+			Committer:    "tester",
+			Message:      "orphan",
+			CreationDate: time.Now(),
+			Parents:      graveler.CommitParents{"fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"},
+		}
+		orphanID := ident.NewHexAddressProvider().ContentAddress(orphan)
+		err = c.Store.CreateCommitRecord(ctx, repository, graveler.CommitID(orphanID), orphan)
+		testutil.MustDo(t, "create commit record", err)
+
+		report, err := c.Fsck(ctx, catalog.FsckOptions{SkipObjects: true, AllCommits: true})
+		require.NoError(t, err)
+		repoReport := report.Repositories[0]
+		require.Equal(t, 3, repoReport.Commits)
+		types := make(map[catalog.FsckIssueType]string)
+		for _, issue := range repoReport.Issues {
+			types[issue.Type] = issue.CommitID
+		}
+		require.Equal(t, map[catalog.FsckIssueType]string{
+			catalog.FsckIssueDanglingParent: orphanID,
+			catalog.FsckIssueOrphanCommit:   orphanID,
+		}, types)
+		require.Equal(t, 1, report.Errors())
+	})
+}
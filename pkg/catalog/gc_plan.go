@@ -0,0 +1,285 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// gcPlanAddresses holds the sizes of sampled object addresses, and the metaranges and ranges scanned into it. Commits
+// share most of their ranges, so each is scanned once, like garbage collection does.
+type gcPlanAddresses struct {
+	sizes      map[string]int64
+	metaRanges map[graveler.MetaRangeID]struct{}
+	ranges     map[graveler.RangeID]struct{}
+}
+
+func newGCPlanAddresses() *gcPlanAddresses {
+	return &gcPlanAddresses{
+		sizes:      make(map[string]int64),
+		metaRanges: make(map[graveler.MetaRangeID]struct{}),
+		ranges:     make(map[graveler.RangeID]struct{}),
+	}
+}
+
+func (a *gcPlanAddresses) estimate(sampleRatio float64) (int64, int64) {
+	var bytes int64
+	for _, size := range a.sizes {
+		bytes += size
+	}
+	return int64(math.Round(float64(len(a.sizes)) / sampleRatio)), int64(math.Round(float64(bytes) / sampleRatio))
+}
+
+// PlanGarbageCollectionSubmit starts estimating the objects and bytes that garbage collection would reclaim under
+// rules (the repository rules if nil), and what each branch retains, in the background. Only committed data is
+// considered. When sampleRatio is below 1, only that ratio of object addresses is examined and the estimates are
+// scaled accordingly. Returns the ID of the task, whose status holds the plan once done.
+func (c *Catalog) PlanGarbageCollectionSubmit(ctx context.Context, repositoryID string, rules *graveler.GarbageCollectionRules, sampleRatio float64) (string, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return "", err
+	}
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		return "", fmt.Errorf("sample ratio %f: %w", sampleRatio, graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if rules == nil {
+		rules, err = c.Store.GetGarbageCollectionRules(ctx, repository)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	taskStatus := &GarbageCollectionPlanStatus{}
+	taskSteps := []taskStep{
+		{
+			Name: "plan garbage collection",
+			Func: func(ctx context.Context) error {
+				plan, err := c.planGarbageCollection(ctx, repository, rules, sampleRatio)
+				if err != nil {
+					return err
+				}
+				taskStatus.Plan = plan
+				return nil
+			},
+		},
+	}
+	taskID := NewTaskID(GCPlanTaskIDPrefix)
+	if err := c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+// PlanGarbageCollectionStatus returns the status of a task started by PlanGarbageCollectionSubmit
+func (c *Catalog) PlanGarbageCollectionStatus(ctx context.Context, repositoryID string, id string) (*GarbageCollectionPlanStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(GCPlanTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+
+	var status GarbageCollectionPlanStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Catalog) planGarbageCollection(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules, sampleRatio float64) (*GarbageCollectionPlan, error) {
+	active, err := c.Store.GetGarbageCollectionCommits(ctx, repository, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make(map[graveler.CommitID]*graveler.Commit)
+	commitIt, err := c.Store.ListCommits(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for commitIt.Next() {
+		rec := commitIt.Value()
+		commits[rec.CommitID] = rec.Commit
+	}
+	err = commitIt.Err()
+	commitIt.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []*graveler.BranchRecord
+	branchIt, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for branchIt.Next() {
+		branches = append(branches, branchIt.Value())
+	}
+	err = branchIt.Err()
+	branchIt.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := &gcPlanScanner{
+		catalog:     c,
+		repository:  repository,
+		sampleRatio: sampleRatio,
+	}
+
+	// objects referenced by branch heads are never reclaimed, nor attributed to retained history
+	heads := make(map[graveler.CommitID]struct{})
+	headAddresses := newGCPlanAddresses()
+	for _, b := range branches {
+		heads[b.CommitID] = struct{}{}
+		commit, ok := commits[b.CommitID]
+		if !ok {
+			return nil, fmt.Errorf("branch %s commit %s: %w", b.BranchID, b.CommitID, graveler.ErrCommitNotFound)
+		}
+		if err := scanner.scanInto(ctx, commit.MetaRangeID, headAddresses, nil); err != nil {
+			return nil, err
+		}
+	}
+	activeAddresses := newGCPlanAddresses()
+	for _, metaRangeID := range active {
+		if err := scanner.scanInto(ctx, metaRangeID, activeAddresses, nil); err != nil {
+			return nil, err
+		}
+	}
+	reclaimable := newGCPlanAddresses()
+	for commitID, commit := range commits {
+		if _, ok := active[commitID]; ok {
+			continue
+		}
+		if err := scanner.scanInto(ctx, commit.MetaRangeID, reclaimable, activeAddresses); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &GarbageCollectionPlan{
+		DefaultRetentionDays: rules.DefaultRetentionDays,
+		BranchRetentionDays:  rules.BranchRetentionDays,
+		ActiveCommits:        int64(len(active)),
+		ExpiredCommits:       int64(len(commits) - len(active)),
+		SampleRatio:          sampleRatio,
+		Branches:             make([]*GarbageCollectionPlanBranch, 0, len(branches)),
+	}
+	plan.ReclaimableObjects, plan.ReclaimableBytes = reclaimable.estimate(sampleRatio)
+
+	for _, b := range branches {
+		retentionDays, ok := rules.BranchRetentionDays[b.BranchID.String()]
+		if !ok {
+			retentionDays = rules.DefaultRetentionDays
+		}
+		planBranch := &GarbageCollectionPlanBranch{
+			BranchId:      b.BranchID.String(),
+			RetentionDays: retentionDays,
+		}
+		// follow the main ancestry of the branch while its commits are kept
+		retained := newGCPlanAddresses()
+		commitID := gcPlanMainParent(commits[b.CommitID])
+		for commitID != "" {
+			metaRangeID, ok := active[commitID]
+			if !ok {
+				break
+			}
+			if _, isHead := heads[commitID]; !isHead {
+				planBranch.RetainedCommits++
+				if err := scanner.scanInto(ctx, metaRangeID, retained, headAddresses); err != nil {
+					return nil, err
+				}
+			}
+			commitID = gcPlanMainParent(commits[commitID])
+		}
+		planBranch.RetainedObjects, planBranch.RetainedBytes = retained.estimate(sampleRatio)
+		plan.Branches = append(plan.Branches, planBranch)
+	}
+	sort.Slice(plan.Branches, func(i, j int) bool {
+		return plan.Branches[i].BranchId < plan.Branches[j].BranchId
+	})
+	return plan, nil
+}
+
+// gcPlanMainParent returns the parent on the main ancestry of commit, the one retained by garbage collection
+func gcPlanMainParent(commit *graveler.Commit) graveler.CommitID {
+	if commit == nil || len(commit.Parents) == 0 {
+		return ""
+	}
+	if commit.Version < graveler.CommitVersionParentSwitch {
+		return commit.Parents[len(commit.Parents)-1]
+	}
+	return commit.Parents[0]
+}
+
+// gcPlanScanner reads the sampled object addresses of commits
+type gcPlanScanner struct {
+	catalog     *Catalog
+	repository  *graveler.RepositoryRecord
+	sampleRatio float64
+}
+
+func (s *gcPlanScanner) sampled(address string) bool {
+	if s.sampleRatio >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(address))
+	return float64(h.Sum32()) < s.sampleRatio*math.MaxUint32
+}
+
+// scanInto adds the sampled addresses of metaRangeID to dst, skipping those found in exclude. Metaranges and ranges
+// already scanned into dst are skipped.
+func (s *gcPlanScanner) scanInto(ctx context.Context, metaRangeID graveler.MetaRangeID, dst, exclude *gcPlanAddresses) error {
+	if _, ok := dst.metaRanges[metaRangeID]; ok {
+		return nil
+	}
+	dst.metaRanges[metaRangeID] = struct{}{}
+	ranges, err := s.catalog.Store.ListRanges(ctx, s.repository, metaRangeID)
+	if err != nil {
+		return err
+	}
+	for _, rng := range ranges {
+		if _, ok := dst.ranges[rng.ID]; ok {
+			continue
+		}
+		dst.ranges[rng.ID] = struct{}{}
+		if err := s.scanRangeInto(ctx, rng.ID, dst, exclude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *gcPlanScanner) scanRangeInto(ctx context.Context, rangeID graveler.RangeID, dst, exclude *gcPlanAddresses) error {
+	it, err := s.catalog.Store.ListRange(ctx, s.repository, rangeID)
+	if err != nil {
+		return err
+	}
+	entries := NewValueToEntryIterator(it)
+	defer entries.Close()
+	for entries.Next() {
+		ent := entries.Value().Entry
+		if !s.sampled(ent.Address) {
+			continue
+		}
+		if exclude != nil {
+			if _, ok := exclude.sizes[ent.Address]; ok {
+				continue
+			}
+		}
+		dst.sizes[ent.Address] = ent.Size
+	}
+	return entries.Err()
+}
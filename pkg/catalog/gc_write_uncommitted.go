@@ -17,74 +17,158 @@ func gcWriteUncommitted(ctx context.Context, store Store, repository *graveler.R
 	}
 	pw.CompressionType = parquet.CompressionCodec_GZIP
 
-	// write uncommitted data from branches
+	normalizedStorageNamespace := string(repository.StorageNamespace)
+	if !strings.HasSuffix(normalizedStorageNamespace, DefaultPathDelimiter) {
+		normalizedStorageNamespace += DefaultPathDelimiter
+	}
+	uw := &gcUncommittedParquetWriter{
+		pw:                         pw,
+		w:                          w,
+		normalizedStorageNamespace: normalizedStorageNamespace,
+		maxFileSize:                maxFileSize,
+		prepareDuration:            prepareDuration,
+		startTime:                  time.Now(),
+	}
+
+	// write uncommitted data from branches, then from stashes
+	var nextMark *GCUncommittedMark
+	if mark == nil || mark.StashID == "" {
+		nextMark, err = gcWriteBranchesUncommitted(ctx, store, repository, uw, mark, runID)
+		if err != nil {
+			return nil, false, err
+		}
+		mark = nil
+	}
+	if nextMark == nil {
+		nextMark, err = gcWriteStashesUncommitted(ctx, store, repository, uw, mark, runID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	// stop writer before we return
+	if err := pw.WriteStop(); err != nil {
+		return nil, false, err
+	}
+
+	// Finished reading all staging area - return marker to switch processing tracked physical addresses
+	hasData := uw.count > 0
+	return nextMark, hasData, nil
+}
+
+func gcWriteBranchesUncommitted(ctx context.Context, store Store, repository *graveler.RepositoryRecord, uw *gcUncommittedParquetWriter, mark *GCUncommittedMark, runID string) (*GCUncommittedMark, error) {
 	it, err := NewUncommittedIterator(ctx, store, repository)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	defer it.Close()
 
 	if mark != nil {
 		it.SeekGE(mark.BranchID, mark.Path)
 	}
-
-	normalizedStorageNamespace := string(repository.StorageNamespace)
-	if !strings.HasSuffix(normalizedStorageNamespace, DefaultPathDelimiter) {
-		normalizedStorageNamespace += DefaultPathDelimiter
-	}
-
-	count := 0
-	startTime := time.Now()
-	var nextMark *GCUncommittedMark
 	for it.Next() {
 		entry := it.Value()
-		// Skip if entry is tombstone
-		if entry.Entry == nil {
-			continue
-		}
-		// Skip non-relative that address outside the storage namespace
-		entryAddress := entry.Address
-		if entry.Entry.AddressType != Entry_RELATIVE {
-			if !strings.HasPrefix(entry.Address, normalizedStorageNamespace) {
-				continue
-			}
-			entryAddress = entryAddress[len(normalizedStorageNamespace):]
-		}
-
-		count += 1
-		if count%gcPeriodicCheckSize == 0 {
-			if err := pw.Flush(true); err != nil {
-				return nil, false, err
-			}
+		done, err := uw.write(entry.EntryRecord)
+		if err != nil {
+			return nil, err
 		}
-		// check if we need to stop - based on max file size or prepare duration.
-		// prepare duration is optional, if 0 it will be ignored.
-		// prepare duration is used to stop the process in cases we scan a lot of data, and we want to stop
-		// so the api call will not time out.
-		if w.Size() > maxFileSize || (prepareDuration > 0 && time.Since(startTime) > prepareDuration) {
-			nextMark = &GCUncommittedMark{
+		if done {
+			return &GCUncommittedMark{
 				RunID:    runID,
 				BranchID: entry.branchID,
 				Path:     entry.Path,
-			}
-			break
+			}, nil
 		}
-		if err = pw.Write(UncommittedParquetObject{
-			PhysicalAddress: entryAddress,
-			CreationDate:    entry.LastModified.AsTime().Unix(),
-		}); err != nil {
-			return nil, false, err
+	}
+	return nil, it.Err()
+}
+
+// gcWriteStashesUncommitted writes the uncommitted data set aside in stashes, which can still be applied to a branch
+func gcWriteStashesUncommitted(ctx context.Context, store Store, repository *graveler.RepositoryRecord, uw *gcUncommittedParquetWriter, mark *GCUncommittedMark, runID string) (*GCUncommittedMark, error) {
+	stashes, err := store.ListStashes(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer stashes.Close()
+	if mark != nil {
+		stashes.SeekGE(mark.StashID)
+	}
+	for stashes.Next() {
+		stash := stashes.Value()
+		valueIt, err := store.ListStaging(ctx, stash.Branch(), 0)
+		if err != nil {
+			return nil, err
+		}
+		it := NewValueToEntryIterator(valueIt)
+		if mark != nil && mark.StashID == stash.StashID {
+			it.SeekGE(mark.Path)
+		}
+		nextMark, err := gcWriteStashUncommitted(it, uw, stash.StashID, runID)
+		it.Close()
+		if err != nil || nextMark != nil {
+			return nextMark, err
 		}
 	}
-	if err := it.Err(); err != nil {
-		return nil, false, err
+	return nil, stashes.Err()
+}
+
+func gcWriteStashUncommitted(it EntryIterator, uw *gcUncommittedParquetWriter, stashID graveler.StashID, runID string) (*GCUncommittedMark, error) {
+	for it.Next() {
+		entry := it.Value()
+		done, err := uw.write(entry)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return &GCUncommittedMark{
+				RunID:   runID,
+				StashID: stashID,
+				Path:    entry.Path,
+			}, nil
+		}
 	}
-	// stop writer before we return
-	if err := pw.WriteStop(); err != nil {
-		return nil, false, err
+	return nil, it.Err()
+}
+
+type gcUncommittedParquetWriter struct {
+	pw                         *writer.ParquetWriter
+	w                          *UncommittedWriter
+	normalizedStorageNamespace string
+	maxFileSize                int64
+	prepareDuration            time.Duration
+	startTime                  time.Time
+	count                      int
+}
+
+// write writes the physical address of entry, returning true without writing it when the file is complete
+func (u *gcUncommittedParquetWriter) write(entry *EntryRecord) (bool, error) {
+	// Skip if entry is tombstone
+	if entry.Entry == nil {
+		return false, nil
+	}
+	// Skip non-relative that address outside the storage namespace
+	entryAddress := entry.Address
+	if entry.Entry.AddressType != Entry_RELATIVE {
+		if !strings.HasPrefix(entry.Address, u.normalizedStorageNamespace) {
+			return false, nil
+		}
+		entryAddress = entryAddress[len(u.normalizedStorageNamespace):]
 	}
 
-	// Finished reading all staging area - return marker to switch processing tracked physical addresses
-	hasData := count > 0
-	return nextMark, hasData, nil
+	u.count += 1
+	if u.count%gcPeriodicCheckSize == 0 {
+		if err := u.pw.Flush(true); err != nil {
+			return false, err
+		}
+	}
+	// check if we need to stop - based on max file size or prepare duration.
+	// prepare duration is optional, if 0 it will be ignored.
+	// prepare duration is used to stop the process in cases we scan a lot of data, and we want to stop
+	// so the api call will not time out.
+	if u.w.Size() > u.maxFileSize || (u.prepareDuration > 0 && time.Since(u.startTime) > u.prepareDuration) {
+		return true, nil
+	}
+	return false, u.pw.Write(UncommittedParquetObject{
+		PhysicalAddress: entryAddress,
+		CreationDate:    entry.LastModified.AsTime().Unix(),
+	})
 }
@@ -0,0 +1,244 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// LifecycleCommitter is the author of the commits deleting expired objects
+	LifecycleCommitter = "lakefs-lifecycle"
+	// LifecycleRuleMetadataKey is the commit metadata key holding the ID of the rule that made the commit
+	LifecycleRuleMetadataKey = "::lakefs::lifecycle_rule"
+
+	// lifecycleMaxDeletesPerRule limits the objects a rule deletes on each run, the rest are deleted by later runs
+	lifecycleMaxDeletesPerRule = 100_000
+	lifecycleDeleteBatchSize   = 1000
+	// lifecycleBranchPrefix prefixes the temporary branches on which rules stage and commit their deletions
+	lifecycleBranchPrefix = "_lifecycle_"
+)
+
+// LifecycleRuleResult is the outcome of applying a single lifecycle rule
+type LifecycleRuleResult struct {
+	RuleID string
+	Branch string
+	// Deleted is the number of expired objects deleted by the rule
+	Deleted int
+	// Locked is the number of expired objects kept as they are under retention
+	Locked int
+	// CommitID is the commit deleting the objects, empty when no objects were deleted
+	CommitID string
+	// SkipReason explains why the rule was not applied, empty when it was
+	SkipReason string
+}
+
+func (c *Catalog) GetLifecycleRules(ctx context.Context, repositoryID string) (*graveler.LifecycleRules, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Store.GetLifecycleRules(ctx, repository)
+}
+
+// SetLifecycleRules replaces the lifecycle rules of the repository. Rules must have unique IDs and a positive
+// expiration.
+func (c *Catalog) SetLifecycleRules(ctx context.Context, repositoryID string, rules *graveler.LifecycleRules) error {
+	if err := validateLifecycleRules(rules); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.SetLifecycleRules(ctx, repository, rules)
+}
+
+func validateLifecycleRules(rules *graveler.LifecycleRules) error {
+	ids := make(map[string]struct{}, len(rules.GetRules()))
+	for _, rule := range rules.GetRules() {
+		if rule.Id == "" {
+			return fmt.Errorf("rule id: %w", graveler.ErrRequiredValue)
+		}
+		if _, ok := ids[rule.Id]; ok {
+			return fmt.Errorf("rule %s: duplicate id: %w", rule.Id, graveler.ErrInvalidValue)
+		}
+		ids[rule.Id] = struct{}{}
+		if err := graveler.ValidateBranchID(graveler.BranchID(rule.Branch)); err != nil {
+			return fmt.Errorf("rule %s: branch: %w", rule.Id, err)
+		}
+		if rule.ExpirationDays <= 0 {
+			return fmt.Errorf("rule %s: expiration days %d: %w", rule.Id, rule.ExpirationDays, graveler.ErrInvalidValue)
+		}
+	}
+	return nil
+}
+
+// ApplyLifecycleRules deletes the objects expired by the enabled lifecycle rules of the repository, as of now. Each
+// rule commits the deletions of objects under its prefix last modified more than its expiration days ago as
+// LifecycleCommitter. Rules of branches with uncommitted changes are skipped, so that changes staged by users are never
+// committed on their behalf. Objects under retention are kept.
+func (c *Catalog) ApplyLifecycleRules(ctx context.Context, repositoryID string, now time.Time) ([]LifecycleRuleResult, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := c.Store.GetLifecycleRules(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results []LifecycleRuleResult
+		errs    error
+	)
+	for _, rule := range rules.GetRules() {
+		if rule.Disabled {
+			continue
+		}
+		result := LifecycleRuleResult{RuleID: rule.Id, Branch: rule.Branch}
+		switch {
+		case repository.ReadOnly:
+			result.SkipReason = "read-only repository"
+		default:
+			err := c.applyLifecycleRule(ctx, repositoryID, rule, now, &result)
+			if errors.Is(err, graveler.ErrBranchNotFound) {
+				result.SkipReason = "branch not found"
+			} else if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("rule %s: %w", rule.Id, err))
+			}
+		}
+		results = append(results, result)
+	}
+	return results, errs
+}
+
+// ApplyAllLifecycleRules applies the lifecycle rules of every repository, as of the current time
+func (c *Catalog) ApplyAllLifecycleRules(ctx context.Context) error {
+	it, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	now := time.Now()
+	var errs error
+	for it.Next() {
+		repositoryID := it.Value().RepositoryID.String()
+		if _, err := c.ApplyLifecycleRules(ctx, repositoryID, now); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("repository %s: %w", repositoryID, err))
+		}
+	}
+	return errors.Join(errs, it.Err())
+}
+
+// applyLifecycleRule stages the deletions of the rule on a temporary branch created from the head of the rule's
+// branch, commits them there and fast-forwards the rule's branch to that commit. The fast-forward fails if changes
+// were staged on or committed to the branch meanwhile, so the rule never commits them, and a run that fails leaves the
+// branch untouched.
+func (c *Catalog) applyLifecycleRule(ctx context.Context, repositoryID string, rule *graveler.LifecycleRule, now time.Time, result *LifecycleRuleResult) error {
+	changes, _, err := c.DiffUncommitted(ctx, repositoryID, rule.Branch, "", "", 1, "")
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		result.SkipReason = "branch has uncommitted changes"
+		return nil
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	head, err := c.Store.GetBranch(ctx, repository, graveler.BranchID(rule.Branch))
+	if err != nil {
+		return err
+	}
+	lifecycleBranch := lifecycleBranchPrefix + xid.New().String()
+	if _, err := c.Store.CreateBranch(ctx, repository, graveler.BranchID(lifecycleBranch), head.CommitID.Ref()); err != nil {
+		return fmt.Errorf("create branch %s: %w", lifecycleBranch, err)
+	}
+	defer func() {
+		if err := c.Store.DeleteBranch(context.WithoutCancel(ctx), repository, graveler.BranchID(lifecycleBranch)); err != nil {
+			c.log(ctx).WithError(err).WithFields(logging.Fields{
+				"repository": repositoryID,
+				"branch":     lifecycleBranch,
+			}).Warn("Failed to delete lifecycle branch")
+		}
+	}()
+
+	expiry := now.AddDate(0, 0, -int(rule.ExpirationDays))
+	after := ""
+	for result.Deleted+result.Locked < lifecycleMaxDeletesPerRule {
+		entries, hasMore, err := c.ListEntries(ctx, repositoryID, lifecycleBranch, rule.Prefix, after, "", lifecycleDeleteBatchSize)
+		if err != nil {
+			return err
+		}
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if entry.CreationDate.Before(expiry) {
+				paths = append(paths, entry.Path)
+			}
+		}
+		if len(paths) > 0 {
+			locked, err := c.deleteExpiredEntries(ctx, repositoryID, lifecycleBranch, paths)
+			if err != nil {
+				return err
+			}
+			result.Locked += locked
+			result.Deleted += len(paths) - locked
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	if result.Deleted == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("Lifecycle rule %s: delete %d objects under %q older than %d days", rule.Id, result.Deleted, rule.Prefix, rule.ExpirationDays)
+	commitLog, err := c.Commit(ctx, repositoryID, lifecycleBranch, message, LifecycleCommitter,
+		Metadata{LifecycleRuleMetadataKey: rule.Id}, nil, nil, false)
+	if err != nil {
+		result.Deleted = 0
+		return fmt.Errorf("commit: %w", err)
+	}
+	// fails if the branch moved since the rule started, the next run applies the rule to its new head
+	if _, err := c.Merge(ctx, repositoryID, rule.Branch, commitLog.Reference, LifecycleCommitter, message,
+		Metadata{LifecycleRuleMetadataKey: rule.Id}, "", graveler.WithFastForwardOnly(true)); err != nil {
+		result.Deleted = 0
+		return fmt.Errorf("merge: %w", err)
+	}
+	result.CommitID = commitLog.Reference
+	c.log(ctx).WithFields(logging.Fields{
+		"repository": repositoryID,
+		"branch":     rule.Branch,
+		"rule":       rule.Id,
+		"deleted":    result.Deleted,
+		"locked":     result.Locked,
+		"commit_id":  result.CommitID,
+	}).Info("Lifecycle rule deleted expired objects")
+	return nil
+}
+
+// deleteExpiredEntries stages the deletion of paths, returning the number of paths kept as they are under retention
+func (c *Catalog) deleteExpiredEntries(ctx context.Context, repositoryID, branch string, paths []string) (int, error) {
+	err := c.DeleteEntries(ctx, repositoryID, branch, paths)
+	var deleteErrs *multierror.Error
+	if err == nil || !errors.As(err, &deleteErrs) {
+		return 0, err
+	}
+	locked := 0
+	for _, e := range deleteErrs.WrappedErrors() {
+		if !errors.Is(e, graveler.ErrObjectLocked) {
+			return 0, err
+		}
+		locked++
+	}
+	return locked, nil
+}
@@ -11,6 +11,8 @@ import (
 
 const (
 	DefaultContentType = "application/octet-stream"
+	// DefaultStorageClass is the storage class of objects written without one
+	DefaultStorageClass = "STANDARD"
 )
 
 type Metadata map[string]string
@@ -36,11 +38,60 @@ type DBEntry struct {
 	Expired         bool
 	AddressType     AddressType
 	ContentType     string
+	// Retention protects the object from being deleted or overwritten, nil when the object has no retention
+	Retention *ObjectRetention
+	// StorageClass is the storage class of the physical object when it was written, empty if unknown
+	StorageClass string
+	// Tags are the tags of the object, matched by policy conditions
+	Tags map[string]string
+	// Source is the object in the source object store the object was imported from, nil if it was not imported
+	Source *ObjectSource
+}
+
+const (
+	// RetentionModeGovernance allows users with permission to bypass governance retention to shorten or remove
+	// the retention
+	RetentionModeGovernance = "GOVERNANCE"
+	// RetentionModeCompliance never allows shortening or removing the retention, by any user
+	RetentionModeCompliance = "COMPLIANCE"
+)
+
+// ObjectRetention is the retention period of an object under object lock
+type ObjectRetention struct {
+	Mode            string
+	RetainUntilDate time.Time
+}
+
+// Active reports whether the retention still protects the object at time t
+func (r *ObjectRetention) Active(t time.Time) bool {
+	return r != nil && t.Before(r.RetainUntilDate)
+}
+
+// ObjectSource is the object an imported object was imported from, for reconciliation with the source object store
+type ObjectSource struct {
+	ETag string
+	// VersionID is empty if the listing of the source object store does not provide it
+	VersionID string
+}
+
+// TreeNode is a directory in a bounded depth tree listing
+type TreeNode struct {
+	Path string
+	// ChildCount is the number of objects and directories directly under the directory
+	ChildCount int
+	// ObjectCount is the number of objects under the directory, at any depth
+	ObjectCount int64
+	// SizeBytes is the aggregate size of the objects under the directory, at any depth
+	SizeBytes int64
+	// Children are the directories directly under the directory, empty at the maximal depth
+	Children []*TreeNode
 }
 
 type CommitLog struct {
-	Reference    string
-	Committer    string
+	Reference string
+	Committer string
+	// Author is the authenticated user that created the commit
+	Author       string
 	Message      string
 	CreationDate time.Time
 	Metadata     Metadata
@@ -48,11 +99,58 @@ type CommitLog struct {
 	Parents      []string
 	Generation   CommitGeneration
 	Version      CommitVersion
+	// Stats aggregates the objects of the commit, nil for commits created before stats were recorded
+	Stats *CommitStats
+}
+
+// CommitStats aggregates the objects of a commit, and their change relative to its first parent
+type CommitStats struct {
+	ObjectCount      int64
+	SizeBytes        int64
+	ObjectCountDelta int64
+	SizeBytesDelta   int64
 }
 
 type Branch struct {
 	Name      string
 	Reference string
+	// Lock is set when the branch is locked
+	Lock *BranchLock
+}
+
+// DeletedBranch is the head of a deleted branch, which can be restored until ExpiresAt
+type DeletedBranch struct {
+	Name      string
+	Reference string
+	DeletedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Stash is uncommitted changes set aside from a branch, which can be applied to any branch
+type Stash struct {
+	ID           string
+	Branch       string
+	Reference    string
+	Message      string
+	CreationDate time.Time
+}
+
+// BranchLock prevents any change to a branch, including commits and merges into it
+type BranchLock struct {
+	Reason       string
+	LockedBy     string
+	CreationDate time.Time
+}
+
+// MergeBase is the common ancestor of two references, and their divergence from each other
+type MergeBase struct {
+	LeftCommitID  string
+	RightCommitID string
+	BaseCommitID  string
+	// Ahead is the number of commits of left that are not in right
+	Ahead int
+	// Behind is the number of commits of right that are not in left
+	Behind int
 }
 
 type Tag struct {
@@ -60,6 +158,21 @@ type Tag struct {
 	CommitID string
 }
 
+const (
+	LegalHoldKindCommit = "commit"
+	LegalHoldKindTag    = "tag"
+)
+
+// LegalHold pins a commit or a tag: objects reachable from it are kept by garbage collection, and a held tag
+// cannot be deleted
+type LegalHold struct {
+	Kind         string
+	ID           string
+	Reason       string
+	CreatedBy    string
+	CreationDate time.Time
+}
+
 // AddressType is the type of an entry address
 type AddressType int32
 
@@ -109,6 +222,14 @@ func (j *Metadata) Scan(src interface{}) error {
 	return json.Unmarshal(data, j)
 }
 
+// StorageClassOrDefault returns the storage class of an entry, DefaultStorageClass if it has none recorded
+func StorageClassOrDefault(storageClass string) string {
+	if storageClass == "" {
+		return DefaultStorageClass
+	}
+	return storageClass
+}
+
 func ContentTypeOrDefault(ct string) string {
 	if ct == "" {
 		return DefaultContentType
@@ -184,6 +305,26 @@ func (b *DBEntryBuilder) ContentType(contentType string) *DBEntryBuilder {
 	return b
 }
 
+func (b *DBEntryBuilder) Retention(retention *ObjectRetention) *DBEntryBuilder {
+	b.dbEntry.Retention = retention
+	return b
+}
+
+func (b *DBEntryBuilder) Source(source *ObjectSource) *DBEntryBuilder {
+	b.dbEntry.Source = source
+	return b
+}
+
+func (b *DBEntryBuilder) StorageClass(storageClass string) *DBEntryBuilder {
+	b.dbEntry.StorageClass = storageClass
+	return b
+}
+
+func (b *DBEntryBuilder) Tags(tags map[string]string) *DBEntryBuilder {
+	b.dbEntry.Tags = tags
+	return b
+}
+
 func (b *DBEntryBuilder) Build() DBEntry {
 	if !b.dbEntry.CommonLevel && b.dbEntry.ContentType == "" {
 		b.dbEntry.ContentType = DefaultContentType
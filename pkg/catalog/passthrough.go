@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// errStopWalk stops walking an upstream location once its first object is found
+var errStopWalk = errors.New("stop walk")
+
+// PassThroughMapping maps a path prefix of a repository to an upstream location. Objects under the prefix missing from
+// the repository are read from the same relative path under the upstream location.
+type PassThroughMapping struct {
+	Prefix       string
+	Upstream     string
+	ImportOnRead bool
+}
+
+func (c *Catalog) GetPassThroughMappings(ctx context.Context, repositoryID string) ([]PassThroughMapping, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := c.Store.GetPassThroughMappings(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PassThroughMapping, 0, len(mappings.Mappings))
+	for _, m := range mappings.Mappings {
+		result = append(result, PassThroughMapping{
+			Prefix:       m.Prefix,
+			Upstream:     m.Upstream,
+			ImportOnRead: m.ImportOnRead,
+		})
+	}
+	return result, nil
+}
+
+// SetPassThroughMappings replaces the pass-through mappings of the repository. Prefixes must be unique, and upstream
+// locations are stored as prefixes, ending with a slash.
+func (c *Catalog) SetPassThroughMappings(ctx context.Context, repositoryID string, mappings []PassThroughMapping) error {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	prefixes := make(map[string]struct{}, len(mappings))
+	msg := &graveler.PassThroughMappings{}
+	for _, m := range mappings {
+		if _, ok := prefixes[m.Prefix]; ok {
+			return fmt.Errorf("duplicate prefix %s: %w", m.Prefix, graveler.ErrInvalidValue)
+		}
+		prefixes[m.Prefix] = struct{}{}
+		u, err := url.Parse(m.Upstream)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("upstream %s: %w", m.Upstream, graveler.ErrInvalidValue)
+		}
+		upstream := m.Upstream
+		if !strings.HasSuffix(upstream, "/") {
+			upstream += "/"
+		}
+		msg.Mappings = append(msg.Mappings, &graveler.PassThroughMapping{
+			Prefix:       m.Prefix,
+			Upstream:     upstream,
+			ImportOnRead: m.ImportOnRead,
+		})
+	}
+	return c.Store.SetPassThroughMappings(ctx, repository, msg)
+}
+
+// GetPassThroughEntry returns the entry of path read from the upstream location of the longest pass-through prefix
+// matching it. It is used for paths missing from reference, and returns graveler.ErrNotFound when no prefix matches
+// or the upstream object does not exist.
+// If the mapping imports on read and reference is a branch, the upstream object is also added to the branch.
+func (c *Catalog) GetPassThroughEntry(ctx context.Context, repositoryID string, reference string, path string) (*DBEntry, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(reference), Fn: graveler.ValidateRef},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := c.Store.GetPassThroughMappings(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	mapping := matchPassThroughMapping(mappings.Mappings, path)
+	if mapping == nil {
+		return nil, graveler.ErrNotFound
+	}
+
+	address := mapping.Upstream + strings.TrimPrefix(path, mapping.Prefix)
+	object, err := c.statUpstream(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	entryRecord := objectStoreEntryToEntryRecord(*object, path)
+	entry := newCatalogEntryFromEntry(false, path, entryRecord.Entry)
+
+	if mapping.ImportOnRead {
+		c.importOnRead(ctx, repository, graveler.BranchID(reference), entry)
+	}
+	return &entry, nil
+}
+
+// matchPassThroughMapping returns the mapping with the longest prefix of path, or nil if none matches
+func matchPassThroughMapping(mappings []*graveler.PassThroughMapping, path string) *graveler.PassThroughMapping {
+	var match *graveler.PassThroughMapping
+	for _, m := range mappings {
+		if strings.HasPrefix(path, m.Prefix) && (match == nil || len(m.Prefix) > len(match.Prefix)) {
+			match = m
+		}
+	}
+	return match
+}
+
+// statUpstream returns the object at address of an upstream location. Listing from address returns the object
+// first if it exists, as every other object listed has it as a prefix.
+func (c *Catalog) statUpstream(ctx context.Context, address string) (*block.ObjectStoreEntry, error) {
+	walker, err := c.walkerFactory.GetWalker(ctx, store.WalkerOptions{StorageURI: address})
+	if err != nil {
+		return nil, fmt.Errorf("creating object-store walker: %w", err)
+	}
+	var object *block.ObjectStoreEntry
+	err = walker.Walk(ctx, block.WalkOptions{}, func(e block.ObjectStoreEntry) error {
+		if e.Address == address {
+			object = &e
+		}
+		return errStopWalk
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return nil, err
+	}
+	if object == nil {
+		return nil, graveler.ErrNotFound
+	}
+	return object, nil
+}
+
+// importOnRead adds entry read from upstream to branch, unless the reference read through is not a branch or the
+// path was added concurrently. Failures are logged and do not fail the read.
+func (c *Catalog) importOnRead(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, entry DBEntry) {
+	log := c.log(ctx).WithFields(logging.Fields{"branch": branchID, "path": entry.Path})
+	if _, err := c.Store.GetBranch(ctx, repository, branchID); err != nil {
+		if !errors.Is(err, graveler.ErrNotFound) && !errors.Is(err, graveler.ErrInvalidValue) {
+			log.WithError(err).Warn("Failed to get branch to import read-through object")
+		}
+		return
+	}
+	value, err := EntryToValue(newEntryFromCatalogEntry(entry))
+	if err != nil {
+		log.WithError(err).Warn("Failed to import read-through object")
+		return
+	}
+	err = c.Store.Set(ctx, repository, branchID, graveler.Key(entry.Path), *value, graveler.WithIfAbsent(true))
+	if err != nil && !errors.Is(err, graveler.ErrPreconditionFailed) {
+		log.WithError(err).Warn("Failed to import read-through object")
+	}
+}
@@ -0,0 +1,38 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestMatchPassThroughMapping(t *testing.T) {
+	mappings := []*graveler.PassThroughMapping{
+		{Prefix: "data/", Upstream: "s3://bucket/data/"},
+		{Prefix: "data/raw/", Upstream: "s3://raw-bucket/"},
+		{Prefix: "logs/", Upstream: "s3://bucket/logs/"},
+	}
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "data/file", expected: "data/"},
+		{path: "data/raw/file", expected: "data/raw/"},
+		{path: "data/rawfile", expected: "data/"},
+		{path: "logs/2023/file", expected: "logs/"},
+		{path: "other/file", expected: ""},
+		{path: "data", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			m := matchPassThroughMapping(mappings, tt.path)
+			prefix := ""
+			if m != nil {
+				prefix = m.Prefix
+			}
+			if prefix != tt.expected {
+				t.Fatalf("match %s prefix=%q, expected %q", tt.path, prefix, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// Restore statuses of objects on the underlying storage
+const (
+	// RestoreStatusNotArchived objects are readable without a restore
+	RestoreStatusNotArchived = "not_archived"
+	// RestoreStatusArchived objects are unreadable until restored
+	RestoreStatusArchived = "archived"
+	// RestoreStatusInProgress objects are being restored
+	RestoreStatusInProgress = "in_progress"
+	// RestoreStatusRestored objects are archived with a readable restored copy
+	RestoreStatusRestored = "restored"
+)
+
+// EntryRestore is the restore status of an object on the underlying storage
+type EntryRestore struct {
+	Path         string
+	StorageClass *string
+	Status       string
+	// Expiry is when the restored copy of the object expires, zero if unknown or not restored
+	Expiry time.Time
+}
+
+// RestoreEntries returns the restore status of up to limit objects under prefix of reference, listed after the
+// after path. With opts it also initiates a restore of each listed object that is archived, and neither has a
+// restored copy nor is already being restored.
+func (c *Catalog) RestoreEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, limit int, opts *block.RestoreOpts) ([]*EntryRestore, bool, error) {
+	if opts != nil && opts.Days <= 0 {
+		return nil, false, fmt.Errorf("restore days must be positive: %w", graveler.ErrInvalidValue)
+	}
+	repository, err := c.GetRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	entries, hasMore, err := c.ListEntries(ctx, repositoryID, reference, prefix, after, "", limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	results := make([]*EntryRestore, 0, len(entries))
+	for _, entry := range entries {
+		obj := block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}
+		props, err := c.BlockAdapter.GetProperties(ctx, obj)
+		if err != nil {
+			return nil, false, fmt.Errorf("object %s properties: %w", entry.Path, err)
+		}
+		result := &EntryRestore{
+			Path:         entry.Path,
+			StorageClass: props.StorageClass,
+			Status:       restoreStatus(props),
+			Expiry:       props.RestoreExpiry,
+		}
+		if result.Status == RestoreStatusArchived && opts != nil {
+			err := c.BlockAdapter.RestoreObject(ctx, obj, *opts)
+			switch {
+			case err == nil, errors.Is(err, block.ErrRestoreInProgress):
+				result.Status = RestoreStatusInProgress
+			case errors.Is(err, block.ErrObjectNotArchived):
+				result.Status = RestoreStatusNotArchived
+			default:
+				return nil, false, fmt.Errorf("restore object %s: %w", entry.Path, err)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, hasMore, nil
+}
+
+func restoreStatus(props block.Properties) string {
+	switch {
+	case !props.Archived:
+		return RestoreStatusNotArchived
+	case props.RestoreOngoing:
+		return RestoreStatusInProgress
+	case !props.RestoreExpiry.IsZero():
+		return RestoreStatusRestored
+	default:
+		return RestoreStatusArchived
+	}
+}
@@ -0,0 +1,754 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// SampleFormat is the format of the records of a sampled object
+type SampleFormat string
+
+const (
+	SampleFormatCSV     SampleFormat = "csv"
+	SampleFormatJSONL   SampleFormat = "jsonl"
+	SampleFormatParquet SampleFormat = "parquet"
+)
+
+// SampleMode selects the records of a sample
+type SampleMode string
+
+const (
+	// SampleModeHead samples the first records of an object
+	SampleModeHead SampleMode = "head"
+	// SampleModeTail samples the last records of an object
+	SampleModeTail SampleMode = "tail"
+	// SampleModeRandom samples records at random positions of an object
+	SampleModeRandom SampleMode = "random"
+)
+
+const (
+	// DefaultSampleLimit is the number of records of a sample when no limit is requested
+	DefaultSampleLimit = 20
+	// MaxSampleLimit is the largest number of records of a sample
+	MaxSampleLimit = 1000
+
+	// sampleWindowSize is the number of bytes read at each position of a text object sampled at random, longer
+	// records are not sampled
+	sampleWindowSize = 64 * 1024
+	// sampleMaxRecordSize is the size of the longest record of a text object read from its head
+	sampleMaxRecordSize = 1024 * 1024
+	// sampleRandomTries is the number of positions tried for each record of a random sample of a text object
+	sampleRandomTries = 2
+	// sampleMaxParquetFooterSize is the size of the largest footer of a sampled Parquet object
+	sampleMaxParquetFooterSize = 16 * 1024 * 1024
+
+	// parquetMagic starts and ends Parquet objects, parquetTrailerSize is the size of the length of the footer and
+	// the magic number that end them
+	parquetMagic       = "PAR1"
+	parquetTrailerSize = 8
+)
+
+var (
+	ErrUnsupportedSampleFormat = fmt.Errorf("unsupported sample format: %w", graveler.ErrInvalidValue)
+	ErrInvalidSampleRecord     = fmt.Errorf("invalid sample record: %w", graveler.ErrInvalidValue)
+)
+
+// SampleParams select the records of a sample
+type SampleParams struct {
+	// Format is the format of the object, by its extension when empty
+	Format SampleFormat
+	Mode   SampleMode
+	Limit  int
+}
+
+// Sample is a sample of the records of an object
+type Sample struct {
+	Format SampleFormat
+	// Columns are the columns of the records, in the order of the object
+	Columns []string
+	// Rows are the sampled records, in the order of the object, by column
+	Rows []map[string]any
+}
+
+// SampleFormatOf returns the format of the object at p by its extension, or an empty format if it is not supported
+func SampleFormatOf(p string) SampleFormat {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".csv":
+		return SampleFormatCSV
+	case ".jsonl", ".ndjson", ".json":
+		return SampleFormatJSONL
+	case ".parquet":
+		return SampleFormatParquet
+	default:
+		return ""
+	}
+}
+
+// SampleEntry returns a sample of the records of the object at path of reference. Only the parts of the object that
+// hold the sampled records are read.
+func (c *Catalog) SampleEntry(ctx context.Context, repositoryID string, reference string, path string, params SampleParams) (*Sample, error) {
+	if params.Format == "" {
+		params.Format = SampleFormatOf(path)
+	}
+	if params.Format == "" {
+		return nil, fmt.Errorf("%w: object %s", ErrUnsupportedSampleFormat, path)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := c.getEntry(ctx, repositoryID, reference, path)
+	if err != nil {
+		return nil, err
+	}
+	r := &blockReaderAt{
+		ctx:     ctx,
+		adapter: c.BlockAdapter,
+		obj: block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace.String(),
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		},
+	}
+	return SampleRecords(r, entry.Size, params)
+}
+
+// SampleRecords returns a sample of the records of the size bytes of r
+func SampleRecords(r io.ReaderAt, size int64, params SampleParams) (*Sample, error) {
+	if params.Limit <= 0 || params.Limit > MaxSampleLimit {
+		return nil, fmt.Errorf("sample limit %d not between 1 and %d: %w", params.Limit, MaxSampleLimit, graveler.ErrInvalidValue)
+	}
+	switch params.Mode {
+	case SampleModeHead, SampleModeTail, SampleModeRandom:
+	default:
+		return nil, fmt.Errorf("sample mode '%s': %w", params.Mode, graveler.ErrInvalidValue)
+	}
+
+	switch params.Format {
+	case SampleFormatCSV:
+		return sampleCSV(r, size, params)
+	case SampleFormatJSONL:
+		return sampleJSONL(r, size, params)
+	case SampleFormatParquet:
+		return sampleParquet(r, size, params)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSampleFormat, params.Format)
+	}
+}
+
+// sampleCSV samples the records of a CSV object, its first record is its header. Quoted fields of records may span
+// lines: tail and random samples parse records from line starts and skip the line starts inside quoted fields, from
+// which records do not parse.
+func sampleCSV(r io.ReaderAt, size int64, params SampleParams) (*Sample, error) {
+	sample := &Sample{Format: params.Format, Rows: make([]map[string]any, 0, params.Limit)}
+	records := newSampleCSVReader(io.NewSectionReader(r, 0, size))
+	header, err := records.read()
+	if errors.Is(err, io.EOF) {
+		return sample, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sample.Columns = header
+	start := records.InputOffset()
+
+	var rows [][]string
+	switch params.Mode {
+	case SampleModeHead:
+		rows, err = records.readN(params.Limit)
+	case SampleModeTail:
+		rows, err = tailCSVRecords(r, start, size, params.Limit)
+	case SampleModeRandom:
+		rows, err = randomCSVRecords(r, start, size, params.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, values := range rows {
+		row := make(map[string]any, len(values))
+		for i, value := range values {
+			if i < len(sample.Columns) {
+				row[sample.Columns[i]] = value
+			}
+		}
+		sample.Rows = append(sample.Rows, row)
+	}
+	return sample, nil
+}
+
+// sampleCSVReader reads the records of a CSV object, failing on records longer than sampleMaxRecordSize
+type sampleCSVReader struct {
+	*csv.Reader
+	src *recordSizeReader
+}
+
+// newSampleCSVReader returns a reader of the CSV records of r, records may have any number of fields
+func newSampleCSVReader(r io.Reader) *sampleCSVReader {
+	src := &recordSizeReader{r: r}
+	records := csv.NewReader(src)
+	records.FieldsPerRecord = -1
+	return &sampleCSVReader{Reader: records, src: src}
+}
+
+func (r *sampleCSVReader) read() ([]string, error) {
+	record, err := r.Read()
+	r.src.recordStart = r.InputOffset()
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, ErrInvalidSampleRecord):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+	}
+	return record, nil
+}
+
+// readN returns up to n next records
+func (r *sampleCSVReader) readN(n int) ([][]string, error) {
+	var records [][]string
+	for len(records) < n {
+		record, err := r.read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readAll returns all the records
+func (r *sampleCSVReader) readAll() ([][]string, error) {
+	return r.readN(math.MaxInt)
+}
+
+// recordSizeReader fails reads more than sampleMaxRecordSize bytes past the start of the record being read
+type recordSizeReader struct {
+	r           io.Reader
+	read        int64
+	recordStart int64
+}
+
+func (r *recordSizeReader) Read(p []byte) (int, error) {
+	if r.read-r.recordStart > sampleMaxRecordSize {
+		return 0, fmt.Errorf("%w: longer than %d bytes", ErrInvalidSampleRecord, sampleMaxRecordSize)
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+// tailCSVRecords returns the last limit records of the bytes of r between start and end, reading a growing suffix of
+// them until it holds enough records. Records are parsed from the first line start of the suffix from which all the
+// suffix parses.
+func tailCSVRecords(r io.ReaderAt, start, end int64, limit int) ([][]string, error) {
+	window := int64(sampleWindowSize)
+	for {
+		from := max(end-window, start)
+		data := make([]byte, end-from)
+		if _, err := r.ReadAt(data, from); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		var (
+			records [][]string
+			err     error
+		)
+		if from == start {
+			records, err = newSampleCSVReader(bytes.NewReader(data)).readAll()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// the suffix starts inside a line, or inside a record spanning lines
+			for i := bytes.IndexByte(data, '\n'); i >= 0; {
+				data = data[i+1:]
+				records, err = newSampleCSVReader(bytes.NewReader(data)).readAll()
+				if err == nil {
+					break
+				}
+				records = nil
+				i = bytes.IndexByte(data, '\n')
+			}
+		}
+		if len(records) >= limit || from == start {
+			if len(records) > limit {
+				records = records[len(records)-limit:]
+			}
+			return records, nil
+		}
+		window *= 2
+	}
+}
+
+// randomCSVRecords returns up to limit records of the bytes of r between start and end, at random positions
+func randomCSVRecords(r io.ReaderAt, start, end int64, limit int) ([][]string, error) {
+	return randomRecords(r, start, end, limit, func(data []byte, atEnd bool) ([]string, bool) {
+		records := newSampleCSVReader(bytes.NewReader(data))
+		record, err := records.read()
+		if err != nil {
+			// not the start of a record, or a record longer than the window
+			return nil, false
+		}
+		if !atEnd && data[records.InputOffset()-1] != '\n' {
+			// the record may continue past the window
+			return nil, false
+		}
+		return record, true
+	})
+}
+
+// sampleJSONL samples the lines of a JSON lines object
+func sampleJSONL(r io.ReaderAt, size int64, params SampleParams) (*Sample, error) {
+	sample := &Sample{Format: params.Format, Rows: make([]map[string]any, 0, params.Limit)}
+	lines := bufio.NewReaderSize(io.NewSectionReader(r, 0, size), sampleWindowSize)
+
+	var records [][]byte
+	var err error
+	switch params.Mode {
+	case SampleModeHead:
+		records, err = headLines(lines, params.Limit)
+	case SampleModeTail:
+		records, err = tailLines(r, 0, size, params.Limit)
+	case SampleModeRandom:
+		records, err = randomLines(r, 0, size, params.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		row, err := sample.parseJSONRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		sample.Rows = append(sample.Rows, row)
+	}
+	return sample, nil
+}
+
+func (s *Sample) parseJSONRecord(record []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	decoder.UseNumber()
+	var row map[string]any
+	if err := decoder.Decode(&row); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+	}
+	// the columns of JSON lines are the keys of the sampled records, the new keys of each record sorted
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		if !slices.Contains(s.Columns, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	s.Columns = append(s.Columns, keys...)
+	return row, nil
+}
+
+// readSampleLine returns the next line of r, including its line terminator
+func readSampleLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > sampleMaxRecordSize {
+			return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidSampleRecord, sampleMaxRecordSize)
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, err
+		}
+	}
+}
+
+// isBlankLine reports whether line holds no record
+func isBlankLine(line []byte) bool {
+	return len(bytes.TrimSpace(line)) == 0
+}
+
+func headLines(r *bufio.Reader, limit int) ([][]byte, error) {
+	var lines [][]byte
+	for len(lines) < limit {
+		line, err := readSampleLine(r)
+		if !isBlankLine(line) {
+			lines = append(lines, line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// tailLines returns the last limit lines of the bytes of r between start and end, reading a growing suffix of them
+// until it holds enough lines
+func tailLines(r io.ReaderAt, start, end int64, limit int) ([][]byte, error) {
+	window := int64(sampleWindowSize)
+	for {
+		from := end - window
+		if from < start {
+			from = start
+		}
+		data := make([]byte, end-from)
+		if _, err := r.ReadAt(data, from); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if from > start {
+			// the first line of the suffix may be partial, unless it follows a line terminator
+			prev := make([]byte, 1)
+			if _, err := r.ReadAt(prev, from-1); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			if prev[0] != '\n' {
+				i := bytes.IndexByte(data, '\n')
+				if i < 0 {
+					data = nil
+				} else {
+					data = data[i+1:]
+				}
+			}
+		}
+		lines := splitLines(data)
+		if len(lines) >= limit || from == start {
+			if len(lines) > limit {
+				lines = lines[len(lines)-limit:]
+			}
+			return lines, nil
+		}
+		window *= 2
+	}
+}
+
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		line := data
+		if i >= 0 {
+			line, data = data[:i+1], data[i+1:]
+		} else {
+			data = nil
+		}
+		if !isBlankLine(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// randomLines returns up to limit lines of the bytes of r between start and end, at random positions
+func randomLines(r io.ReaderAt, start, end int64, limit int) ([][]byte, error) {
+	return randomRecords(r, start, end, limit, func(data []byte, atEnd bool) ([]byte, bool) {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 && !atEnd {
+			// the line is longer than the window
+			return nil, false
+		}
+		if i >= 0 {
+			data = data[:i+1]
+		}
+		return data, !isBlankLine(data)
+	})
+}
+
+// randomRecords returns up to limit records of the bytes of r between start and end, at random positions, in the
+// order of the object. Each position samples the record parse returns from the window of the bytes that follow the
+// first line start after it, so the line that holds it is not sampled. parse also gets whether the window reaches end,
+// and returns false if the window does not start with a whole record.
+func randomRecords[T any](r io.ReaderAt, start, end int64, limit int, parse func(data []byte, atEnd bool) (T, bool)) ([]T, error) {
+	if end <= start {
+		return nil, nil
+	}
+	positions := make([]int64, 0, limit*sampleRandomTries)
+	for i := 0; i < limit*sampleRandomTries; i++ {
+		// start-1 stands for the record at start, which no position inside the object precedes
+		positions = append(positions, start-1+rand.Int63n(end-start+1)) //nolint:gosec
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	var (
+		records []T
+		sampled = make(map[int64]struct{})
+	)
+	for _, pos := range positions {
+		lineStart := max(pos, start)
+		windowEnd := min(lineStart+sampleWindowSize, end)
+		data := make([]byte, windowEnd-lineStart)
+		if _, err := r.ReadAt(data, lineStart); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if pos >= start {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				continue
+			}
+			lineStart += int64(i + 1)
+			data = data[i+1:]
+		}
+		if _, ok := sampled[lineStart]; ok || len(data) == 0 {
+			continue
+		}
+		record, ok := parse(data, windowEnd == end)
+		if !ok {
+			continue
+		}
+		sampled[lineStart] = struct{}{}
+		records = append(records, record)
+	}
+	if len(records) > limit {
+		// keep a random subset, in the order of the object
+		keep := rand.Perm(len(records))[:limit] //nolint:gosec
+		sort.Ints(keep)
+		subset := make([]T, 0, limit)
+		for _, i := range keep {
+			subset = append(subset, records[i])
+		}
+		records = subset
+	}
+	return records, nil
+}
+
+// sampleParquet samples the rows of a Parquet object, reading its footer and, for each sampled row, the pages of its
+// row group up to the row
+func sampleParquet(r io.ReaderAt, size int64, params SampleParams) (*Sample, error) {
+	footer, err := readParquetFooter(r, size)
+	if err != nil {
+		return nil, err
+	}
+	r = &footerReaderAt{r: r, footer: footer, footerOffset: size - int64(len(footer))}
+	pr, err := reader.NewParquetReader(&parquetReaderAtFile{SectionReader: io.NewSectionReader(r, 0, size), r: r, size: size}, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+	}
+	defer pr.ReadStop()
+
+	sample := &Sample{Format: params.Format, Columns: parquetColumns(pr), Rows: make([]map[string]any, 0, params.Limit)}
+	numRows := pr.GetNumRows()
+	limit := int64(params.Limit)
+	if limit > numRows {
+		limit = numRows
+	}
+	var indices []int64
+	switch params.Mode {
+	case SampleModeHead:
+		for i := int64(0); i < limit; i++ {
+			indices = append(indices, i)
+		}
+	case SampleModeTail:
+		for i := numRows - limit; i < numRows; i++ {
+			indices = append(indices, i)
+		}
+	case SampleModeRandom:
+		// select limit distinct rows without listing all the rows (Floyd's algorithm)
+		selected := make(map[int64]struct{}, limit)
+		for j := numRows - limit; j < numRows; j++ {
+			i := rand.Int63n(j + 1) //nolint:gosec
+			if _, ok := selected[i]; ok {
+				i = j
+			}
+			selected[i] = struct{}{}
+			indices = append(indices, i)
+		}
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	}
+
+	// rowGroup is the row group the reader is at and groupStart its first row, next is the row the reader reads next
+	var (
+		rowGroups  = pr.Footer.GetRowGroups()
+		rowGroup   = 0
+		groupStart int64
+		next       int64
+	)
+	for _, i := range indices {
+		// rows of other row groups are read from the start of their row group, without reading the row groups between
+		g, start := rowGroup, groupStart
+		for g < len(rowGroups)-1 && i >= start+rowGroups[g].GetNumRows() {
+			start += rowGroups[g].GetNumRows()
+			g++
+		}
+		if g != rowGroup {
+			if err := seekParquetRowGroup(pr, g); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+			}
+			rowGroup, groupStart, next = g, start, start
+		}
+		if err := pr.SkipRows(i - next); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+		}
+		rows, err := pr.ReadByNumber(1)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSampleRecord, err)
+		}
+		next = i + 1
+		for _, row := range rows {
+			sample.Rows = append(sample.Rows, parquetRow(row, sample.Columns))
+		}
+	}
+	return sample, nil
+}
+
+// seekParquetRowGroup positions the column buffers of pr at the start of row group rowGroup
+func seekParquetRowGroup(pr *reader.ParquetReader, rowGroup int) error {
+	for _, buffer := range pr.ColumnBuffers {
+		// NextRowGroup moves to the row group following RowGroupIndex
+		buffer.RowGroupIndex = int64(rowGroup)
+		buffer.DataTable = nil
+		buffer.DataTableNumRows = -1
+		if err := buffer.NextRowGroup(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readParquetFooter returns the footer of the size bytes Parquet object of r followed by its length and magic number.
+// It fails on footers larger than sampleMaxParquetFooterSize.
+func readParquetFooter(r io.ReaderAt, size int64) ([]byte, error) {
+	if size < int64(len(parquetMagic)+parquetTrailerSize) {
+		return nil, fmt.Errorf("%w: not a Parquet object", ErrInvalidSampleRecord)
+	}
+	trailer := make([]byte, parquetTrailerSize)
+	if _, err := r.ReadAt(trailer, size-parquetTrailerSize); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if string(trailer[4:]) != parquetMagic {
+		return nil, fmt.Errorf("%w: not a Parquet object", ErrInvalidSampleRecord)
+	}
+	footerSize := int64(binary.LittleEndian.Uint32(trailer))
+	if footerSize > sampleMaxParquetFooterSize {
+		return nil, fmt.Errorf("%w: Parquet footer of %d bytes larger than %d bytes", ErrInvalidSampleRecord, footerSize, sampleMaxParquetFooterSize)
+	}
+	if footerSize > size-int64(len(parquetMagic)+parquetTrailerSize) {
+		return nil, fmt.Errorf("%w: Parquet footer of %d bytes larger than the object", ErrInvalidSampleRecord, footerSize)
+	}
+	footer := make([]byte, footerSize+parquetTrailerSize)
+	if _, err := r.ReadAt(footer, size-int64(len(footer))); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return footer, nil
+}
+
+// footerReaderAt reads the footer of an object, from footerOffset, from memory and the rest of the object from r
+type footerReaderAt struct {
+	r            io.ReaderAt
+	footer       []byte
+	footerOffset int64
+}
+
+func (f *footerReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < f.footerOffset {
+		return f.r.ReadAt(p, off)
+	}
+	if off-f.footerOffset >= int64(len(f.footer)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.footer[off-f.footerOffset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// parquetColumns returns the names of the top level columns of the schema of pr
+func parquetColumns(pr *reader.ParquetReader) []string {
+	elements := pr.SchemaHandler.SchemaElements
+	infos := pr.SchemaHandler.Infos
+	var columns []string
+	// skip returns the index of the element following the subtree of element i
+	var skip func(i int) int
+	skip = func(i int) int {
+		next := i + 1
+		for c := int32(0); c < elements[i].GetNumChildren(); c++ {
+			next = skip(next)
+		}
+		return next
+	}
+	i := 1
+	for c := int32(0); c < elements[0].GetNumChildren() && i < len(elements); c++ {
+		columns = append(columns, infos[i].ExName)
+		i = skip(i)
+	}
+	return columns
+}
+
+// parquetRow returns the row read by a reader without a schema object, a struct with a field per top level column
+func parquetRow(row any, columns []string) map[string]any {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	result := make(map[string]any, len(columns))
+	if v.Kind() != reflect.Struct {
+		return result
+	}
+	for i := 0; i < v.NumField() && i < len(columns); i++ {
+		result[columns[i]] = v.Field(i).Interface()
+	}
+	return result
+}
+
+// blockReaderAt reads an object of the underlying storage by ranges
+type blockReaderAt struct {
+	ctx     context.Context
+	adapter block.Adapter
+	obj     block.ObjectPointer
+}
+
+func (b *blockReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	rc, err := b.adapter.GetRange(b.ctx, b.obj, off, off+int64(len(p))-1)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadFull(rc, p)
+}
+
+// parquetReaderAtFile is a read only Parquet file over a reader at
+type parquetReaderAtFile struct {
+	*io.SectionReader
+	r    io.ReaderAt
+	size int64
+}
+
+var errParquetFileReadOnly = errors.New("parquet file is read only")
+
+func (f *parquetReaderAtFile) Open(string) (source.ParquetFile, error) {
+	return &parquetReaderAtFile{SectionReader: io.NewSectionReader(f.r, 0, f.size), r: f.r, size: f.size}, nil
+}
+
+func (f *parquetReaderAtFile) Create(string) (source.ParquetFile, error) {
+	return nil, errParquetFileReadOnly
+}
+
+func (f *parquetReaderAtFile) Write([]byte) (int, error) {
+	return 0, errParquetFileReadOnly
+}
+
+func (f *parquetReaderAtFile) Close() error {
+	return nil
+}
@@ -0,0 +1,201 @@
+package catalog_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func sampleCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "%d,name %d\n", i, i)
+	}
+	return b.String()
+}
+
+func sampleJSONL(rows int) string {
+	var b strings.Builder
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "{\"id\":%d,\"name\":\"name %d\"}\n", i, i)
+	}
+	return b.String()
+}
+
+type sampleParquetRow struct {
+	ID   int64  `parquet:"name=id, type=INT64"`
+	Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+func sampleParquet(t *testing.T, rows int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(sampleParquetRow), 1)
+	require.NoError(t, err)
+	pw.RowGroupSize = 64
+	for i := 0; i < rows; i++ {
+		require.NoError(t, pw.Write(sampleParquetRow{ID: int64(i), Name: fmt.Sprintf("name %d", i)}))
+	}
+	require.NoError(t, pw.WriteStop())
+	return buf.String()
+}
+
+// sampleIDs returns the ids of the rows of sample as strings
+func sampleIDs(sample *catalog.Sample) []string {
+	ids := make([]string, 0, len(sample.Rows))
+	for _, row := range sample.Rows {
+		ids = append(ids, fmt.Sprint(row["id"]))
+	}
+	return ids
+}
+
+func TestSampleRecords(t *testing.T) {
+	const rows = 1000
+	objects := map[catalog.SampleFormat]string{
+		catalog.SampleFormatCSV:     sampleCSV(rows),
+		catalog.SampleFormatJSONL:   sampleJSONL(rows),
+		catalog.SampleFormatParquet: sampleParquet(t, rows),
+	}
+	for format, data := range objects {
+		t.Run(string(format), func(t *testing.T) {
+			sample := func(mode catalog.SampleMode, limit int) *catalog.Sample {
+				t.Helper()
+				s, err := catalog.SampleRecords(strings.NewReader(data), int64(len(data)), catalog.SampleParams{Format: format, Mode: mode, Limit: limit})
+				require.NoError(t, err)
+				require.Equal(t, []string{"id", "name"}, s.Columns)
+				return s
+			}
+
+			head := sample(catalog.SampleModeHead, 3)
+			require.Equal(t, []string{"0", "1", "2"}, sampleIDs(head))
+			require.Equal(t, "name 1", head.Rows[1]["name"])
+
+			tail := sample(catalog.SampleModeTail, 3)
+			require.Equal(t, []string{"997", "998", "999"}, sampleIDs(tail))
+
+			all := sample(catalog.SampleModeTail, rows)
+			require.Len(t, all.Rows, rows)
+
+			random := sample(catalog.SampleModeRandom, 50)
+			require.NotEmpty(t, random.Rows)
+			require.LessOrEqual(t, len(random.Rows), 50)
+			seen := make(map[string]struct{})
+			var last int
+			for i, row := range random.Rows {
+				id := fmt.Sprint(row["id"])
+				require.NotContains(t, seen, id, "row sampled twice")
+				seen[id] = struct{}{}
+				var n int
+				_, err := fmt.Sscan(id, &n)
+				require.NoError(t, err)
+				require.Equal(t, fmt.Sprintf("name %d", n), row["name"], "partial record %v", row)
+				if i > 0 {
+					require.Greater(t, n, last, "rows not in the order of the object")
+				}
+				last = n
+			}
+		})
+	}
+}
+
+func TestSampleRecords_Invalid(t *testing.T) {
+	data := sampleJSONL(3)
+	tests := []struct {
+		name   string
+		data   string
+		params catalog.SampleParams
+	}{
+		{name: "zero_limit", data: data, params: catalog.SampleParams{Format: catalog.SampleFormatJSONL, Mode: catalog.SampleModeHead}},
+		{name: "large_limit", data: data, params: catalog.SampleParams{Format: catalog.SampleFormatJSONL, Mode: catalog.SampleModeHead, Limit: catalog.MaxSampleLimit + 1}},
+		{name: "mode", data: data, params: catalog.SampleParams{Format: catalog.SampleFormatJSONL, Mode: "middle", Limit: 1}},
+		{name: "format", data: data, params: catalog.SampleParams{Format: "avro", Mode: catalog.SampleModeHead, Limit: 1}},
+		{name: "not_json", data: "id,name\n", params: catalog.SampleParams{Format: catalog.SampleFormatJSONL, Mode: catalog.SampleModeHead, Limit: 1}},
+		{name: "not_parquet", data: data, params: catalog.SampleParams{Format: catalog.SampleFormatParquet, Mode: catalog.SampleModeHead, Limit: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := catalog.SampleRecords(strings.NewReader(tt.data), int64(len(tt.data)), tt.params)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSampleRecords_CSVQuotedLines(t *testing.T) {
+	const rows = 1000
+	var b strings.Builder
+	b.WriteString("id,\"note\nof the row\"\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "%d,\"line %d\nname, \"\"quoted\"\"\"\n", i, i)
+	}
+	data := b.String()
+	for _, mode := range []catalog.SampleMode{catalog.SampleModeHead, catalog.SampleModeTail, catalog.SampleModeRandom} {
+		t.Run(string(mode), func(t *testing.T) {
+			sample, err := catalog.SampleRecords(strings.NewReader(data), int64(len(data)), catalog.SampleParams{Format: catalog.SampleFormatCSV, Mode: mode, Limit: 20})
+			require.NoError(t, err)
+			require.Equal(t, []string{"id", "note\nof the row"}, sample.Columns)
+			require.NotEmpty(t, sample.Rows)
+			for _, row := range sample.Rows {
+				require.Equal(t, fmt.Sprintf("line %s\nname, \"quoted\"", row["id"]), row["note\nof the row"])
+			}
+			if mode == catalog.SampleModeTail {
+				require.Equal(t, "999", sample.Rows[len(sample.Rows)-1]["id"])
+			}
+		})
+	}
+}
+
+// countingReaderAt counts the bytes read from a reader at
+type countingReaderAt struct {
+	r    io.ReaderAt
+	read int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.read += int64(n)
+	return n, err
+}
+
+func TestSampleRecords_ParquetRowGroups(t *testing.T) {
+	data := sampleParquet(t, 10000)
+	r := &countingReaderAt{r: strings.NewReader(data)}
+	sample, err := catalog.SampleRecords(r, int64(len(data)), catalog.SampleParams{Format: catalog.SampleFormatParquet, Mode: catalog.SampleModeTail, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"9998", "9999"}, sampleIDs(sample))
+	// the last row group is read without the row groups before it
+	require.Less(t, r.read, int64(len(data)/2), "read %d bytes of %d", r.read, len(data))
+}
+
+func TestSampleRecords_ParquetFooterSize(t *testing.T) {
+	trailer := make([]byte, 8)
+	binary.LittleEndian.PutUint32(trailer, 1<<30)
+	copy(trailer[4:], "PAR1")
+	data := "PAR1" + strings.Repeat("x", 100) + string(trailer)
+	_, err := catalog.SampleRecords(strings.NewReader(data), int64(len(data)), catalog.SampleParams{Format: catalog.SampleFormatParquet, Mode: catalog.SampleModeHead, Limit: 1})
+	require.ErrorIs(t, err, catalog.ErrInvalidSampleRecord)
+}
+
+func TestSampleRecords_JSONLColumns(t *testing.T) {
+	data := "{\"b\":1,\"a\":2}\n\n{\"c\":{\"d\":true},\"a\":3}\n"
+	sample, err := catalog.SampleRecords(strings.NewReader(data), int64(len(data)), catalog.SampleParams{Format: catalog.SampleFormatJSONL, Mode: catalog.SampleModeHead, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, sample.Columns)
+	require.Len(t, sample.Rows, 2)
+	require.Equal(t, json.Number("3"), sample.Rows[1]["a"])
+}
+
+func TestSampleFormatOf(t *testing.T) {
+	require.Equal(t, catalog.SampleFormatCSV, catalog.SampleFormatOf("a/b.CSV"))
+	require.Equal(t, catalog.SampleFormatJSONL, catalog.SampleFormatOf("a/b.ndjson"))
+	require.Equal(t, catalog.SampleFormatParquet, catalog.SampleFormatOf("a/b.parquet"))
+	require.Equal(t, catalog.SampleFormat(""), catalog.SampleFormatOf("a/b.avro"))
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// ListStashesLimitMax is the maximal number of stashes returned by a single listing
+const ListStashesLimitMax = 1000
+
+func stashFromGraveler(s *graveler.StashRecord) *Stash {
+	return &Stash{
+		ID:           s.StashID.String(),
+		Branch:       s.BranchID.String(),
+		Reference:    s.CommitID.String(),
+		Message:      s.Message,
+		CreationDate: s.CreationDate,
+	}
+}
+
+// StashBranch sets the uncommitted changes of branch aside as stashID, leaving the branch with no uncommitted changes
+func (c *Catalog) StashBranch(ctx context.Context, repositoryID, branch, stashID, message string, opts ...graveler.SetOptionsFunc) (*Stash, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "stash", Value: graveler.StashID(stashID), Fn: graveler.ValidateStashID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	// stashed objects are dropped from the branch
+	if err := c.checkObjectLockUncommitted(ctx, repository, branchID, nil); err != nil {
+		return nil, err
+	}
+	stash, err := c.Store.StashBranch(ctx, repository, branchID, graveler.StashID(stashID), message, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stashFromGraveler(stash), nil
+}
+
+// ApplyStash stages the changes of stashID on branch, deleting the stash once applied when drop is set
+func (c *Catalog) ApplyStash(ctx context.Context, repositoryID, stashID, branch string, drop bool, opts ...graveler.SetOptionsFunc) error {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "stash", Value: graveler.StashID(stashID), Fn: graveler.ValidateStashID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if err := c.Store.ApplyStash(ctx, repository, graveler.StashID(stashID), branchID, opts...); err != nil {
+		return err
+	}
+	if !drop {
+		return nil
+	}
+	return c.Store.DeleteStash(ctx, repository, graveler.StashID(stashID))
+}
+
+func (c *Catalog) GetStash(ctx context.Context, repositoryID, stashID string) (*Stash, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "stash", Value: graveler.StashID(stashID), Fn: graveler.ValidateStashID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	stash, err := c.Store.GetStash(ctx, repository, graveler.StashID(stashID))
+	if err != nil {
+		return nil, err
+	}
+	return stashFromGraveler(stash), nil
+}
+
+func (c *Catalog) ListStashes(ctx context.Context, repositoryID string, prefix string, limit int, after string) ([]*Stash, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// normalize limit
+	if limit < 0 || limit > ListStashesLimitMax {
+		limit = ListStashesLimitMax
+	}
+	it, err := c.Store.ListStashes(ctx, repository)
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+
+	afterStash := graveler.StashID(after)
+	prefixStash := graveler.StashID(prefix)
+	if afterStash < prefixStash {
+		it.SeekGE(prefixStash)
+	} else {
+		it.SeekGE(afterStash)
+	}
+	var stashes []*Stash
+	for it.Next() {
+		v := it.Value()
+		if v.StashID == afterStash {
+			continue
+		}
+		// break in case we got to a stash outside our prefix
+		if !strings.HasPrefix(v.StashID.String(), prefix) {
+			break
+		}
+		stashes = append(stashes, stashFromGraveler(v))
+		if len(stashes) >= limit+1 {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	// return results (optionally trimmed) and hasMore
+	hasMore := false
+	if len(stashes) > limit {
+		hasMore = true
+		stashes = stashes[:limit]
+	}
+	return stashes, hasMore, nil
+}
+
+func (c *Catalog) DeleteStash(ctx context.Context, repositoryID, stashID string) error {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "stash", Value: graveler.StashID(stashID), Fn: graveler.ValidateStashID},
+	}); err != nil {
+		return err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	return c.Store.DeleteStash(ctx, repository, graveler.StashID(stashID))
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+const (
+	// SymlinkManifestsPrefix is the prefix of the storage namespace symlink manifests are written under
+	SymlinkManifestsPrefix = "symlinks"
+	// SymlinkManifestName is the name of the manifest written in every directory holding objects
+	SymlinkManifestName = "symlink.txt"
+)
+
+// SymlinkManifests describes the symlink manifests written for a prefix at a commit
+type SymlinkManifests struct {
+	CommitID string
+	// Location is the URI of the manifests of the prefix, the location of an external table reading it
+	Location  string
+	Manifests int
+	Objects   int
+}
+
+// symlinkManifest collects the addresses of the objects of a directory
+type symlinkManifest struct {
+	dir       string
+	addresses []string
+}
+
+// isHiddenObject reports whether name is skipped by Hive readers, such as _SUCCESS markers and .crc files
+func isHiddenObject(name string) bool {
+	return name == "" || strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")
+}
+
+// WriteSymlinkManifests writes a Hive symlink manifest for every directory under prefix at the commit of ref,
+// listing the physical addresses of the objects of the directory. Manifests are written to the storage namespace
+// of the repository, under a location unique to the commit, so that external tables using SymlinkTextInputFormat
+// read the exact version of the data without copying it. Uncommitted changes of a branch are not included.
+func (c *Catalog) WriteSymlinkManifests(ctx context.Context, repositoryID, ref, prefix string) (*SymlinkManifests, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = c.withBlockAdapterOverride(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := c.GetCommit(ctx, repositoryID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	storageNamespace := repository.StorageNamespace.String()
+	manifestsPath := path.Join(SymlinkManifestsPrefix, repositoryID, commit.Reference)
+	result := &SymlinkManifests{
+		CommitID: commit.Reference,
+		Location: strings.TrimSuffix(storageNamespace, "/") + "/" + path.Join(manifestsPath, prefix),
+	}
+
+	write := func(m *symlinkManifest) error {
+		data := strings.Join(m.addresses, "\n") + "\n"
+		err := c.BlockAdapter.Put(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       path.Join(manifestsPath, m.dir, SymlinkManifestName),
+		}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
+		if err != nil {
+			return fmt.Errorf("write manifest of %s: %w", m.dir, err)
+		}
+		result.Manifests++
+		return nil
+	}
+
+	// entries are listed in lexicographic order, so the objects of a directory may be interleaved with those of
+	// its subdirectories, but not with those of other directories: open manifests are those of the ancestors of
+	// the current directory, written once it leaves their subtree
+	var open []*symlinkManifest
+	after := ""
+	for {
+		entries, hasMore, err := c.ListEntries(ctx, repositoryID, commit.Reference, prefix, after, "", ListEntriesLimitMax)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			dir, name := path.Split(entry.Path)
+			if isHiddenObject(name) {
+				continue
+			}
+			for len(open) > 0 && !strings.HasPrefix(dir, open[len(open)-1].dir) {
+				if err := write(open[len(open)-1]); err != nil {
+					return nil, err
+				}
+				open = open[:len(open)-1]
+			}
+			if len(open) == 0 || open[len(open)-1].dir != dir {
+				open = append(open, &symlinkManifest{dir: dir})
+			}
+			qk, err := c.BlockAdapter.ResolveNamespace(storageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+			if err != nil {
+				return nil, fmt.Errorf("resolve address of %s: %w", entry.Path, err)
+			}
+			current := open[len(open)-1]
+			current.addresses = append(current.addresses, qk.Format())
+			result.Objects++
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		if err := write(open[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
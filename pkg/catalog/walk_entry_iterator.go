@@ -157,6 +157,10 @@ func objectStoreEntryToEntryRecord(e block.ObjectStoreEntry, path string) EntryR
 			Size:         e.Size,
 			ETag:         e.ETag,
 			AddressType:  Entry_FULL,
+			Source: &EntrySource{
+				ETag:      e.ETag,
+				VersionId: e.VersionID,
+			},
 		},
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 var (
 	ErrBadConfiguration    = errors.New("bad configuration")
 	ErrBadDomainNames      = fmt.Errorf("%w: domain names are prefixes", ErrBadConfiguration)
+	ErrBadGatewayOperation = fmt.Errorf("%w: unknown S3 gateway operation", ErrBadConfiguration)
 	ErrMissingRequiredKeys = fmt.Errorf("%w: missing required keys", ErrBadConfiguration)
 )
 
@@ -29,6 +31,12 @@ const (
 	QuickstartConfiguration = "quickstart"
 )
 
+// ACME challenges used to obtain certificates
+const (
+	ACMEChallengeTLSALPN01 = "tls-alpn-01"
+	ACMEChallengeDNS01     = "dns-01"
+)
+
 type OIDC struct {
 	// configure how users are handled on the lakeFS side:
 	ValidateIDTokenClaims  map[string]string `mapstructure:"validate_id_token_claims"`
@@ -87,16 +95,143 @@ type DiffProps struct {
 	Delta DeltaDiffPlugin `mapstructure:"delta"`
 }
 
+// NotificationRule selects the commits and merges notified to its Slack webhook and email addresses
+type NotificationRule struct {
+	Name string `mapstructure:"name"`
+	// Repositories and Branches are glob patterns, any repository or branch matches when empty
+	Repositories []string `mapstructure:"repositories"`
+	Branches     []string `mapstructure:"branches"`
+	// Events are the notified event types: commit and merge. All of them when empty.
+	Events          []string `mapstructure:"events"`
+	SlackWebhookURL string   `mapstructure:"slack_webhook_url"`
+	Emails          []string `mapstructure:"emails"`
+}
+
+// Notifications holds the rules of notifications on branch activity, sent batched into digests
+type Notifications struct {
+	// DigestInterval is the time events are batched before a digest of them is sent
+	DigestInterval time.Duration `mapstructure:"digest_interval"`
+	// MaxDigestEvents limits the events listed in a single digest, the rest are only counted
+	MaxDigestEvents int `mapstructure:"max_digest_events"`
+	SMTP            struct {
+		Host     string       `mapstructure:"host"`
+		Port     int          `mapstructure:"port"`
+		Username string       `mapstructure:"username"`
+		Password SecureString `mapstructure:"password"`
+		From     string       `mapstructure:"from"`
+	} `mapstructure:"smtp"`
+	Rules []NotificationRule `mapstructure:"rules"`
+}
+
+// Search holds the index of the paths and user metadata of the objects committed on branches
+type Search struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Repositories are glob patterns of the indexed repositories, all repositories when empty
+	Repositories []string `mapstructure:"repositories"`
+	// Branches are glob patterns of the indexed branches, all branches when empty
+	Branches []string `mapstructure:"branches"`
+	// QueueSize bounds the events waiting to be indexed. The next commit of the branch of a dropped event is indexed
+	// by the diff from its last indexed commit.
+	QueueSize int `mapstructure:"queue_size"`
+	// MaxScan bounds the index entries scanned by a single query
+	MaxScan int `mapstructure:"max_scan"`
+}
+
+// InventoryReport is an S3 Inventory report of the objects of a branch
+type InventoryReport struct {
+	// ID is the inventory configuration ID, part of the path of the report files
+	ID         string `mapstructure:"id"`
+	Repository string `mapstructure:"repository"`
+	Branch     string `mapstructure:"branch"`
+	// Destination is the storage URI the report files are written under
+	Destination string `mapstructure:"destination"`
+	// Format is the format of the data files of the report: CSV or Parquet
+	Format string `mapstructure:"format"`
+}
+
+// Inventory holds the S3 Inventory reports generated for branches, consumed by tooling built for S3 Inventory
+type Inventory struct {
+	// Interval is the time between generations of the reports
+	Interval time.Duration `mapstructure:"interval"`
+	// MaxFileObjects bounds the objects listed in a single data file of a report
+	MaxFileObjects int               `mapstructure:"max_file_objects"`
+	Reports        []InventoryReport `mapstructure:"reports"`
+}
+
+// Replication holds the replication of repositories to a lakeFS installation in another region
+type Replication struct {
+	// Repositories are the IDs of the replicated repositories. Replication is disabled when empty.
+	Repositories Strings `mapstructure:"repositories"`
+	// Interval is the time between checks of the replicated branches for new commits
+	Interval time.Duration `mapstructure:"interval"`
+	// MaxLag is the replication lag above which replication is reported as breaching its recovery point objective
+	MaxLag time.Duration `mapstructure:"max_lag"`
+	// MaxCommits bounds the commits replayed one by one on a branch, more are replicated as a single commit
+	MaxCommits  int `mapstructure:"max_commits"`
+	Destination struct {
+		EndpointURL     string       `mapstructure:"endpoint_url"`
+		AccessKeyID     SecureString `mapstructure:"access_key_id"`
+		SecretAccessKey SecureString `mapstructure:"secret_access_key"`
+	} `mapstructure:"destination"`
+}
+
+// Jobs holds the background jobs run by lakeFS
+type Jobs struct {
+	// Schedules override the schedules of jobs by job name, as an interval (e.g. "1h") or a cron expression
+	Schedules map[string]string `mapstructure:"schedules"`
+	// Retries is the number of times a failed run of a job is retried
+	Retries int `mapstructure:"retries"`
+	// RetryInterval is the time between retries of a failed run
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	// History is the number of runs kept for every job
+	History int `mapstructure:"history"`
+}
+
 // Config - Output struct of configuration, used to validate.  If you read a key using a viper accessor
 // rather than accessing a field of this struct, that key will *not* be validated.  So don't
 // do that.
 type Config struct {
 	ListenAddress string `mapstructure:"listen_address"`
-	TLS           struct {
+	// ShutdownTimeout bounds the time given to in-flight requests to complete when the server shuts down
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	TLS             struct {
 		Enabled  bool   `mapstructure:"enabled"`
 		CertFile string `mapstructure:"cert_file"`
 		KeyFile  string `mapstructure:"key_file"`
+		// ACME obtains certificates from an ACME certificate authority instead of loading them from files
+		ACME struct {
+			Enabled bool    `mapstructure:"enabled"`
+			Email   string  `mapstructure:"email"`
+			Domains Strings `mapstructure:"domains"`
+			// CacheDir stores obtained certificates and the account key across restarts
+			CacheDir     string `mapstructure:"cache_dir"`
+			DirectoryURL string `mapstructure:"directory_url"`
+			// HTTPChallengeAddress serves the HTTP-01 challenge, port 80 of the domains must reach it
+			HTTPChallengeAddress string `mapstructure:"http_challenge_address"`
+			// Challenge is the ACME challenge used to obtain certificates, ACMEChallengeTLSALPN01 or ACMEChallengeDNS01
+			Challenge string `mapstructure:"challenge"`
+			// DNSHook is the command presenting and cleaning up the TXT records of the DNS-01 challenge
+			DNSHook            string        `mapstructure:"dns_hook"`
+			DNSPropagationWait time.Duration `mapstructure:"dns_propagation_wait"`
+		} `mapstructure:"acme"`
 	} `mapstructure:"tls"`
+	// HTTP configures the connections of the HTTP server serving the API and the S3 gateway
+	HTTP struct {
+		HTTP2Enabled bool `mapstructure:"http2_enabled"`
+		// H2CEnabled serves HTTP/2 over cleartext connections, when TLS is not enabled
+		H2CEnabled bool `mapstructure:"h2c_enabled"`
+		// IdleTimeout is the time an idle keep-alive connection is kept open before the server closes it
+		IdleTimeout          time.Duration `mapstructure:"idle_timeout"`
+		MaxConcurrentStreams uint32        `mapstructure:"max_concurrent_streams"`
+		KeepAlivesEnabled    bool          `mapstructure:"keep_alives_enabled"`
+		// Compression of API responses and request bodies, for clients that accept or send gzip or deflate encoding
+		Compression struct {
+			Enabled bool `mapstructure:"enabled"`
+			Level   int  `mapstructure:"level"`
+			// MaxRequestBodySize bounds the decoded size of compressed request bodies
+			MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+		} `mapstructure:"compression"`
+	} `mapstructure:"http"`
 
 	Actions struct {
 		// ActionsEnabled set to false will block any hook execution
@@ -194,7 +329,27 @@ type Config struct {
 			Size    int           `mapstructure:"size"`
 			TTL     time.Duration `mapstructure:"ttl"`
 			Jitter  time.Duration `mapstructure:"jitter"`
+			// RevocationTTL is how long revoked credentials and sessions may still be accepted
+			RevocationTTL time.Duration `mapstructure:"revocation_ttl"`
 		} `mapstructure:"cache"`
+		Lockout struct {
+			Enabled bool `mapstructure:"enabled"`
+			// MaxFailures consecutive failed authentications of an access key lock it out
+			MaxFailures int `mapstructure:"max_failures"`
+			// MaxSourceIPFailures consecutive failed authentications from a source IP lock it out
+			MaxSourceIPFailures int `mapstructure:"max_source_ip_failures"`
+			// Duration is the first lockout period, doubled by every further failure
+			Duration    time.Duration `mapstructure:"duration"`
+			MaxDuration time.Duration `mapstructure:"max_duration"`
+			// TrustedProxies are the addresses and CIDR ranges of proxies trusted to set X-Forwarded-For
+			TrustedProxies []string `mapstructure:"trusted_proxies"`
+		} `mapstructure:"lockout"`
+		Usage struct {
+			Enabled bool `mapstructure:"enabled"`
+			// MaxLabeledAccessKeys is the number of access keys with their own label on usage metrics, usage
+			// of further access keys is labeled "other"
+			MaxLabeledAccessKeys int `mapstructure:"max_labeled_access_keys"`
+		} `mapstructure:"usage"`
 		Encrypt struct {
 			SecretKey SecureString `mapstructure:"secret_key" validate:"required"`
 		} `mapstructure:"encrypt"`
@@ -215,6 +370,14 @@ type Config struct {
 			// RequestTimeout timeout for remote authentication requests
 			RequestTimeout time.Duration `mapstructure:"request_timeout"`
 		} `mapstructure:"remote_authenticator"`
+		ProvisioningWebhook struct {
+			// Enabled if set true will require approval of the webhook before creating users and credentials
+			Enabled bool `mapstructure:"enabled"`
+			// Endpoint URL of the approval service (e.g. https://my-governance.example.com/lakefs/approve)
+			Endpoint string `mapstructure:"endpoint"`
+			// RequestTimeout timeout for approval requests
+			RequestTimeout time.Duration `mapstructure:"request_timeout"`
+		} `mapstructure:"provisioning_webhook"`
 		OIDC                   OIDC                   `mapstructure:"oidc"`
 		CookieAuthVerification CookieAuthVerification `mapstructure:"cookie_auth_verification"`
 		// LogoutRedirectURL is the URL on which to mount the
@@ -260,6 +423,11 @@ type Config struct {
 				SessionDuration     time.Duration `mapstructure:"session_duration"`
 				SessionExpiryWindow time.Duration `mapstructure:"session_expiry_window"`
 			} `mapstructure:"web_identity"`
+			// AllowedOverrideProfiles are the shared configuration profiles that block adapter overrides of
+			// repositories may use
+			AllowedOverrideProfiles Strings `mapstructure:"allowed_override_profiles"`
+			// AllowedOverrideEndpoints are the endpoints that block adapter overrides of repositories may use
+			AllowedOverrideEndpoints Strings `mapstructure:"allowed_override_endpoints"`
 		} `mapstructure:"s3"`
 		Azure *struct {
 			TryTimeout       time.Duration `mapstructure:"try_timeout"`
@@ -322,6 +490,20 @@ type Config struct {
 		Background struct {
 			RateLimit int `mapstructure:"rate_limit"`
 		} `mapstructure:"background"`
+		Tree struct {
+			MaxDepth int `mapstructure:"max_depth"`
+		} `mapstructure:"tree"`
+		DirectoryListingCache struct {
+			Enabled     bool          `mapstructure:"enabled"`
+			Size        int           `mapstructure:"size"`
+			Expiry      time.Duration `mapstructure:"expiry"`
+			Jitter      time.Duration `mapstructure:"jitter"`
+			MaxChildren int           `mapstructure:"max_children"`
+		} `mapstructure:"directory_listing_cache"`
+		// DeletedBranches keeps the heads of deleted branches for Retention, zero disables restoring deleted branches
+		DeletedBranches struct {
+			Retention time.Duration `mapstructure:"retention"`
+		} `mapstructure:"deleted_branches"`
 	} `mapstructure:"graveler"`
 	Gateways struct {
 		S3 struct {
@@ -329,6 +511,34 @@ type Config struct {
 			Region            string  `mapstructure:"region"`
 			FallbackURL       string  `mapstructure:"fallback_url"`
 			VerifyUnsupported bool    `mapstructure:"verify_unsupported"`
+			// CompressListResponses compresses the XML responses of listings, at the level of http.compression
+			CompressListResponses bool `mapstructure:"compress_list_responses"`
+			// Limits bound the size of uploads, zero disables a limit
+			Limits struct {
+				MaxObjectSize int64 `mapstructure:"max_object_size"`
+				MaxPartSize   int64 `mapstructure:"max_part_size"`
+				MaxParts      int   `mapstructure:"max_parts"`
+			} `mapstructure:"limits"`
+			// Signing restricts the regions and services that requests may be signed for
+			Signing struct {
+				Regions  Strings `mapstructure:"regions"`
+				Services Strings `mapstructure:"services"`
+			} `mapstructure:"signing"`
+			// Overload bounds the latency of operations and sheds load while the database is saturated
+			Overload struct {
+				Deadline           time.Duration            `mapstructure:"deadline"`
+				OperationDeadlines map[string]time.Duration `mapstructure:"operation_deadlines"`
+				// MaxDBPoolSaturation is the fraction of database connections in use from which requests are
+				// rejected, zero disables load shedding
+				MaxDBPoolSaturation float64 `mapstructure:"max_db_pool_saturation"`
+			} `mapstructure:"overload"`
+			// Sessions pin the branches read through session references "<branch>@<token>"
+			Sessions struct {
+				// TTL is how long a session reads the commits it pinned, from its first read of a branch
+				TTL time.Duration `mapstructure:"ttl"`
+				// CleanupInterval is the interval of deleting the pins of expired sessions
+				CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+			} `mapstructure:"sessions"`
 		} `mapstructure:"s3"`
 	}
 	Stats struct {
@@ -367,6 +577,11 @@ type Config struct {
 		Enabled       bool          `mapstructure:"enabled"`
 		FlushInterval time.Duration `mapstructure:"flush_interval"`
 	} `mapstructure:"usage_report"`
+	Notifications Notifications `mapstructure:"notifications"`
+	Search        Search        `mapstructure:"search"`
+	Replication   Replication   `mapstructure:"replication"`
+	Inventory     Inventory     `mapstructure:"inventory"`
+	Jobs          Jobs          `mapstructure:"jobs"`
 }
 
 func NewConfig(cfgType string) (*Config, error) {
@@ -394,6 +609,11 @@ func newConfig(cfgType string) (*Config, error) {
 		return nil, err
 	}
 
+	err = c.validateGatewayOperationDeadlines()
+	if err != nil {
+		return nil, err
+	}
+
 	// setup logging package
 	logging.SetOutputFormat(c.Logging.Format)
 	err = logging.SetOutputs(c.Logging.Output, c.Logging.FileMaxSizeMB, c.Logging.FilesKeep)
@@ -439,6 +659,31 @@ func (c *Config) validateDomainNames() error {
 	return nil
 }
 
+// gatewayOperations are the S3 gateway operations that may have a latency budget
+var gatewayOperations = []string{
+	"delete_bucket_cors",
+	"delete_bucket_policy",
+	"delete_object",
+	"delete_objects",
+	"get_object",
+	"head_bucket",
+	"head_object",
+	"list_buckets",
+	"list_objects",
+	"post_object",
+	"put_bucket",
+	"put_object",
+}
+
+func (c *Config) validateGatewayOperationDeadlines() error {
+	for operation := range c.Gateways.S3.Overload.OperationDeadlines {
+		if !slices.Contains(gatewayOperations, operation) {
+			return fmt.Errorf("%w: gateways.s3.overload.operation_deadlines.%s", ErrBadGatewayOperation, operation)
+		}
+	}
+	return nil
+}
+
 func (c *Config) Validate() error {
 	missingKeys := ValidateMissingRequiredKeys(c, "mapstructure", "squash")
 	if len(missingKeys) > 0 {
@@ -486,6 +731,7 @@ func (c *Config) BlockstoreS3Params() (blockparams.S3, error) {
 		ClientLogRetries:              c.Blockstore.S3.ClientLogRetries,
 		ClientLogRequest:              c.Blockstore.S3.ClientLogRequest,
 		WebIdentity:                   webIdentity,
+		AllowedOverrideEndpoints:      c.Blockstore.S3.AllowedOverrideEndpoints,
 	}, nil
 }
 
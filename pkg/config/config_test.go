@@ -106,6 +106,13 @@ func TestConfig_DomainNamePrefix(t *testing.T) {
 	}
 }
 
+func TestConfig_UnknownGatewayOperation(t *testing.T) {
+	_, err := newConfigFromFile("testdata/unknown_gateway_operation.yaml")
+	if !errors.Is(err, config.ErrBadGatewayOperation) {
+		t.Errorf("got error %s not %s", err, config.ErrBadGatewayOperation)
+	}
+}
+
 func TestConfig_BuildBlockAdapter(t *testing.T) {
 	ctx := context.Background()
 	t.Run("local block adapter", func(t *testing.T) {
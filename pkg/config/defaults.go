@@ -34,6 +34,20 @@ func setDefaults(cfgType string) {
 	}
 
 	viper.SetDefault("listen_address", DefaultListenAddress)
+	viper.SetDefault("shutdown_timeout", 30*time.Second)
+
+	viper.SetDefault("tls.acme.cache_dir", "~/lakefs/data/acme")
+	viper.SetDefault("tls.acme.challenge", ACMEChallengeTLSALPN01)
+	viper.SetDefault("tls.acme.dns_propagation_wait", time.Minute)
+
+	viper.SetDefault("http.http2_enabled", true)
+	viper.SetDefault("http.h2c_enabled", false)
+	viper.SetDefault("http.idle_timeout", 5*time.Minute)
+	viper.SetDefault("http.max_concurrent_streams", 250)
+	viper.SetDefault("http.keep_alives_enabled", true)
+	viper.SetDefault("http.compression.enabled", true)
+	viper.SetDefault("http.compression.level", 5)                            //nolint:gomnd
+	viper.SetDefault("http.compression.max_request_body_size", 64*1024*1024) //nolint:gomnd
 
 	viper.SetDefault("logging.format", "text")
 	viper.SetDefault("logging.level", DefaultLoggingLevel)
@@ -52,6 +66,15 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("auth.cache.size", 1024)
 	viper.SetDefault("auth.cache.ttl", 20*time.Second)
 	viper.SetDefault("auth.cache.jitter", 3*time.Second)
+	viper.SetDefault("auth.cache.revocation_ttl", 5*time.Second)
+
+	viper.SetDefault("auth.lockout.enabled", false)
+	viper.SetDefault("auth.lockout.max_failures", 5)
+	viper.SetDefault("auth.lockout.max_source_ip_failures", 20)
+	viper.SetDefault("auth.lockout.duration", 5*time.Second)
+	viper.SetDefault("auth.lockout.max_duration", 15*time.Minute)
+	viper.SetDefault("auth.usage.enabled", false)
+	viper.SetDefault("auth.usage.max_labeled_access_keys", 50)
 
 	viper.SetDefault("auth.logout_redirect_url", "/auth/login")
 	viper.SetDefault("auth.login_duration", 7*24*time.Hour)
@@ -62,6 +85,7 @@ func setDefaults(cfgType string) {
 
 	viper.SetDefault("auth.remote_authenticator.default_user_group", "Viewers")
 	viper.SetDefault("auth.remote_authenticator.request_timeout", 10*time.Second)
+	viper.SetDefault("auth.provisioning_webhook.request_timeout", 10*time.Second)
 
 	viper.SetDefault("auth.api.health_check_timeout", DefaultAuthAPIHealthCheckTimeout)
 
@@ -88,6 +112,14 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("gateways.s3.domain_name", "s3.local.lakefs.io")
 	viper.SetDefault("gateways.s3.region", "us-east-1")
 	viper.SetDefault("gateways.s3.verify_unsupported", true)
+	// the limits of S3: 5 TiB objects, made of up to 10,000 parts of up to 5 GiB
+	viper.SetDefault("gateways.s3.limits.max_object_size", 5*1024*1024*1024*1024) //nolint:gomnd
+	viper.SetDefault("gateways.s3.limits.max_part_size", 5*1024*1024*1024)        //nolint:gomnd
+	viper.SetDefault("gateways.s3.limits.max_parts", 10_000)                      //nolint:gomnd
+	viper.SetDefault("gateways.s3.signing.regions", []string{"*"})
+	viper.SetDefault("gateways.s3.signing.services", []string{"*"})
+	viper.SetDefault("gateways.s3.sessions.ttl", 12*time.Hour)
+	viper.SetDefault("gateways.s3.sessions.cleanup_interval", time.Hour)
 
 	viper.SetDefault("blockstore.gs.s3_endpoint", "https://storage.googleapis.com")
 	viper.SetDefault("blockstore.gs.pre_signed_expiry", 15*time.Minute)
@@ -130,6 +162,13 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("graveler.commit_cache.size", 50_000)
 	viper.SetDefault("graveler.commit_cache.expiry", 10*time.Minute)
 	viper.SetDefault("graveler.commit_cache.jitter", 2*time.Second)
+	viper.SetDefault("graveler.tree.max_depth", 10)
+	viper.SetDefault("graveler.directory_listing_cache.enabled", false)
+	viper.SetDefault("graveler.directory_listing_cache.size", 1000)
+	viper.SetDefault("graveler.directory_listing_cache.expiry", 10*time.Minute)
+	viper.SetDefault("graveler.directory_listing_cache.jitter", 2*time.Second)
+	viper.SetDefault("graveler.directory_listing_cache.max_children", 1000)
+	viper.SetDefault("graveler.deleted_branches.retention", 7*24*time.Hour)
 
 	viper.SetDefault("plugins.default_path", "~/.lakefs/plugins")
 
@@ -137,4 +176,22 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("ugc.prepare_max_file_size", 20*1024*1024)
 
 	viper.SetDefault("usage_report.flush_interval", 5*time.Minute)
+
+	viper.SetDefault("notifications.digest_interval", 15*time.Minute)
+	viper.SetDefault("notifications.max_digest_events", 50)
+	viper.SetDefault("notifications.smtp.port", 587)
+
+	viper.SetDefault("search.queue_size", 1000)
+	viper.SetDefault("search.max_scan", 100_000)
+
+	viper.SetDefault("replication.interval", time.Minute)
+	viper.SetDefault("replication.max_lag", 15*time.Minute)
+	viper.SetDefault("replication.max_commits", 100)
+
+	viper.SetDefault("inventory.interval", 24*time.Hour)
+	viper.SetDefault("inventory.max_file_objects", 1_000_000)
+
+	viper.SetDefault("jobs.retries", 2)
+	viper.SetDefault("jobs.retry_interval", time.Minute)
+	viper.SetDefault("jobs.history", 20)
 }
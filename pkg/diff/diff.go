@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-openapi/swag"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -13,7 +14,11 @@ import (
 	"github.com/treeverse/lakefs/pkg/uri"
 )
 
-const diffTypeTwoDot = "two_dot"
+const (
+	diffTypeTwoDot = "two_dot"
+
+	prefixDiffPageSize = 1000
+)
 
 // StreamRepositoryDiffs asynchronously fetches differences between 'left' and 'right' references, assumes both are in the same repository
 func StreamRepositoryDiffs(ctx context.Context, client apigen.ClientWithResponsesInterface, left, right *uri.URI, prefix string, diffs chan<- apigen.Diff, twoDot bool) error {
@@ -50,6 +55,108 @@ func StreamRepositoryDiffs(ctx context.Context, client apigen.ClientWithResponse
 	return nil
 }
 
+// StreamPrefixDiffs asynchronously fetches differences between the objects under the path of 'left' and the objects
+// under the path of 'right', each path a directory at its own reference. Objects are matched by their path relative to
+// the directory and compared by checksum, the paths of the differences are relative to the directories.
+func StreamPrefixDiffs(ctx context.Context, client apigen.ClientWithResponsesInterface, left, right *uri.URI, diffs chan<- apigen.Diff) error {
+	defer func() {
+		close(diffs)
+	}()
+	leftLister := newObjectLister(ctx, client, left)
+	rightLister := newObjectLister(ctx, client, right)
+	leftObj, err := leftLister.next()
+	if err != nil {
+		return err
+	}
+	rightObj, err := rightLister.next()
+	if err != nil {
+		return err
+	}
+	for leftObj != nil || rightObj != nil {
+		var leftPath, rightPath string
+		if leftObj != nil {
+			leftPath = strings.TrimPrefix(leftObj.Path, leftLister.prefix)
+		}
+		if rightObj != nil {
+			rightPath = strings.TrimPrefix(rightObj.Path, rightLister.prefix)
+		}
+		switch {
+		case rightObj == nil || (leftObj != nil && leftPath < rightPath):
+			diffs <- apigen.Diff{Path: leftPath, PathType: leftObj.PathType, SizeBytes: leftObj.SizeBytes, Type: "removed"}
+			if leftObj, err = leftLister.next(); err != nil {
+				return err
+			}
+		case leftObj == nil || rightPath < leftPath:
+			diffs <- apigen.Diff{Path: rightPath, PathType: rightObj.PathType, SizeBytes: rightObj.SizeBytes, Type: "added"}
+			if rightObj, err = rightLister.next(); err != nil {
+				return err
+			}
+		default:
+			if leftObj.Checksum != rightObj.Checksum {
+				diffs <- apigen.Diff{Path: rightPath, PathType: rightObj.PathType, SizeBytes: rightObj.SizeBytes, Type: "changed"}
+			}
+			if leftObj, err = leftLister.next(); err != nil {
+				return err
+			}
+			if rightObj, err = rightLister.next(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// objectLister lists the objects under the path of a URI, a directory at its reference, page by page
+type objectLister struct {
+	ctx     context.Context
+	client  apigen.ClientWithResponsesInterface
+	uri     *uri.URI
+	prefix  string
+	page    []apigen.ObjectStats
+	after   string
+	hasMore bool
+}
+
+func newObjectLister(ctx context.Context, client apigen.ClientWithResponsesInterface, u *uri.URI) *objectLister {
+	prefix := u.GetPath()
+	if prefix != "" && !strings.HasSuffix(prefix, uri.PathSeparator) {
+		prefix += uri.PathSeparator
+	}
+	return &objectLister{
+		ctx:     ctx,
+		client:  client,
+		uri:     u,
+		prefix:  prefix,
+		hasMore: true,
+	}
+}
+
+// next returns the next object, or nil after the last object
+func (l *objectLister) next() (*apigen.ObjectStats, error) {
+	for len(l.page) == 0 {
+		if !l.hasMore {
+			return nil, nil
+		}
+		resp, err := l.client.ListObjectsWithResponse(l.ctx, l.uri.Repository, l.uri.Ref, &apigen.ListObjectsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(l.after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(prefixDiffPageSize)),
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix(l.prefix)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("list remote failed. HTTP %d: %w", resp.StatusCode(), local.ErrRemoteFailure)
+		}
+		l.page = resp.JSON200.Results
+		l.hasMore = resp.JSON200.Pagination.HasMore
+		l.after = resp.JSON200.Pagination.NextOffset
+	}
+	obj := l.page[0]
+	l.page = l.page[1:]
+	return &obj, nil
+}
+
 func Fmt(change string) (string, text.Color) {
 	var color text.Color
 	var action string
@@ -0,0 +1,75 @@
+package diff_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/diff"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+// listObjectsClient lists objects by checksum per path, one object per page
+type listObjectsClient struct {
+	apigen.ClientWithResponsesInterface
+	objects map[string]map[string]string
+}
+
+func (c *listObjectsClient) ListObjectsWithResponse(_ context.Context, _ string, ref string, params *apigen.ListObjectsParams, _ ...apigen.RequestEditorFn) (*apigen.ListObjectsResponse, error) {
+	after := string(apiutil.Value(params.After))
+	prefix := string(apiutil.Value(params.Prefix))
+	var paths []string
+	for p := range c.objects[ref] {
+		if strings.HasPrefix(p, prefix) && p > after {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	list := &apigen.ObjectStatsList{Results: []apigen.ObjectStats{}}
+	if len(paths) > 0 {
+		list.Results = append(list.Results, apigen.ObjectStats{Path: paths[0], PathType: "object", Checksum: c.objects[ref][paths[0]]})
+		list.Pagination = apigen.Pagination{HasMore: len(paths) > 1, NextOffset: paths[0]}
+	}
+	return &apigen.ListObjectsResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      list,
+	}, nil
+}
+
+func TestStreamPrefixDiffs(t *testing.T) {
+	client := &listObjectsClient{objects: map[string]map[string]string{
+		"main": {
+			"events/a":        "1",
+			"events/b":        "2",
+			"events/c/d":      "3",
+			"events-old/e":    "4",
+			"backfill/events": "5",
+		},
+		"dev": {
+			"backfill/events/a":   "1",
+			"backfill/events/b":   "changed",
+			"backfill/events/c/f": "6",
+		},
+	}}
+	diffs := make(chan apigen.Diff, 10)
+	err := diff.StreamPrefixDiffs(context.Background(), client,
+		uri.Must(uri.Parse("lakefs://repo/main/events")),
+		uri.Must(uri.Parse("lakefs://repo/dev/backfill/events/")),
+		diffs)
+	require.NoError(t, err)
+
+	var results []string
+	for d := range diffs {
+		results = append(results, d.Type+" "+d.Path)
+	}
+	require.Equal(t, []string{
+		"changed b",
+		"removed c/d",
+		"added c/f",
+	}, results)
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	corsOriginHeader        = "Origin"
+	corsRequestMethodHeader = "Access-Control-Request-Method"
+	corsRequestHeaders      = "Access-Control-Request-Headers"
+)
+
+// CORSHandler applies the CORS rules of the repository to cross-origin requests. It answers preflight requests
+// itself, before authentication, as browsers send them without credentials.
+func CORSHandler(c *catalog.Catalog, bareDomains []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get(corsOriginHeader)
+		if origin == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ctx := req.Context()
+		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
+		preflight := req.Method == http.MethodOptions
+
+		var rules []*graveler.CORSRule
+		parts := ParseRequestParts(req.Host, req.URL.Path, bareDomains)
+		if parts.Repository != "" {
+			corsRules, err := c.GetCORSRules(ctx, parts.Repository)
+			switch {
+			case errors.Is(err, graveler.ErrNotFound), errors.Is(err, graveler.ErrInvalidValue):
+			case err != nil:
+				o.Log(req).WithError(err).Error("failed to get CORS rules")
+				_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+				return
+			default:
+				rules = corsRules.Rules
+			}
+		}
+
+		if !preflight {
+			if rule := matchCORSRule(rules, origin, req.Method, nil); rule != nil {
+				writeCORSHeaders(w, rule, origin)
+			}
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		var requestHeaders []string
+		for _, header := range strings.Split(req.Header.Get(corsRequestHeaders), ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				requestHeaders = append(requestHeaders, header)
+			}
+		}
+		rule := matchCORSRule(rules, origin, req.Header.Get(corsRequestMethodHeader), requestHeaders)
+		if rule == nil {
+			_ = o.EncodeError(w, req, nil, gatewayerrors.ErrCORSForbidden.ToAPIErr())
+			return
+		}
+		writeCORSHeaders(w, rule, origin)
+		h := w.Header()
+		h.Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+		if len(requestHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(requestHeaders, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(rule.MaxAgeSeconds)))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// matchCORSRule returns the first rule allowing origin to send a request with method and headers, or nil if none
+// does
+func matchCORSRule(rules []*graveler.CORSRule, origin, method string, headers []string) *graveler.CORSRule {
+	for _, rule := range rules {
+		if !matchCORSPatterns(rule.AllowedOrigins, origin, false) {
+			continue
+		}
+		if !matchCORSPatterns(rule.AllowedMethods, method, false) {
+			continue
+		}
+		allowed := true
+		for _, header := range headers {
+			if !matchCORSPatterns(rule.AllowedHeaders, header, true) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matchCORSPatterns reports whether value matches any of patterns, which may contain a single '*' wildcard
+func matchCORSPatterns(patterns []string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if pattern == value {
+				return true
+			}
+			continue
+		}
+		if len(value) >= len(prefix)+len(suffix) && strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeCORSHeaders(w http.ResponseWriter, rule *graveler.CORSRule, origin string) {
+	h := w.Header()
+	if slices.Contains(rule.AllowedOrigins, "*") {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(rule.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+	}
+	h.Add("Vary", "Origin, Access-Control-Request-Headers, Access-Control-Request-Method")
+}
@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestMatchCORSRule(t *testing.T) {
+	rules := []*graveler.CORSRule{
+		{Id: "app", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"GET", "PUT"}, AllowedHeaders: []string{"Content-*", "x-amz-date"}},
+		{Id: "public", AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+	}
+	tt := []struct {
+		name       string
+		origin     string
+		method     string
+		headers    []string
+		expectedID string
+	}{
+		{name: "origin wildcard", origin: "https://app.example.com", method: "PUT", expectedID: "app"},
+		{name: "headers case insensitive", origin: "https://app.example.com", method: "PUT", headers: []string{"content-type", "X-Amz-Date"}, expectedID: "app"},
+		{name: "header not allowed", origin: "https://app.example.com", method: "PUT", headers: []string{"authorization"}},
+		{name: "fallback rule", origin: "https://other.org", method: "GET", expectedID: "public"},
+		{name: "method not allowed", origin: "https://other.org", method: "PUT"},
+		{name: "origin suffix mismatch", origin: "https://.example.com.evil.org", method: "PUT"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := matchCORSRule(rules, tc.origin, tc.method, tc.headers)
+			var id string
+			if rule != nil {
+				id = rule.Id
+			}
+			if id != tc.expectedID {
+				t.Errorf("matchCORSRule() = %q, expected %q", id, tc.expectedID)
+			}
+		})
+	}
+}
@@ -54,15 +54,18 @@ const (
 	ErrEntityTooSmall
 	ErrEntityTooLarge
 	ErrPolicyTooLarge
+	ErrInvalidTag
 	ErrIncompleteBody
 	ErrInternalError
 	ErrInvalidAccessKeyID
 	ErrInvalidBucketName
 	ErrInvalidDigest
 	ErrInvalidRange
+	ErrInvalidWriteOffset
 	ErrInvalidCopyPartRange
 	ErrInvalidCopyPartRangeSource
 	ErrInvalidMaxKeys
+	ErrInvalidMaxBuckets
 	ErrInvalidEncodingMethod
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
@@ -80,6 +83,9 @@ const (
 	ErrNoSuchBucket
 	ErrNoSuchBucketPolicy
 	ErrNoSuchBucketLifecycle
+	ErrNoSuchCORSConfiguration
+	ErrNoSuchObjectLockConfiguration
+	ErrNoSuchObjectRetention
 	ErrNoSuchKey
 	ErrNoSuchUpload
 	ErrNoSuchVersion
@@ -97,6 +103,11 @@ const (
 	ErrBucketNotEmpty
 	ErrAllAccessDisabled
 	ErrMalformedPolicy
+	ErrCORSForbidden
+	ErrObjectLocked
+	ErrObjectLockNotEnabled
+	ErrInvalidObjectLock
+	ErrInvalidObjectKey
 	ErrMissingFields
 	ErrMissingCredTag
 	ErrCredMalformed
@@ -129,6 +140,9 @@ const (
 	ErrBadRequest
 	ErrKeyTooLongError
 	ErrInvalidAPIVersion
+	ErrInvalidChecksum
+	ErrInvalidObjectAttributes
+	ErrInvalidPartNumber
 	// Add new error codes here.
 
 	// SSE-S3 related API errors
@@ -171,7 +185,10 @@ const (
 	ERRLakeFSNotSupported
 	ERRLakeFSWrongEndpoint
 	ErrWriteToProtectedBranch
+	ErrWriteToLockedBranch
 	ErrReadOnlyRepository
+	ErrWriteToSessionRef
+	ErrExpiredSession
 )
 
 type errorCodeMap map[APIErrorCode]APIError
@@ -225,6 +242,11 @@ var Codes = errorCodeMap{
 		Description:    "Argument maxKeys must be an integer between 0 and 2147483647",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidMaxBuckets: {
+		Code:           "InvalidArgument",
+		Description:    "Argument max-buckets must be an integer between 1 and 10000",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidEncodingMethod: {
 		Code:           "InvalidArgument",
 		Description:    "Invalid Encoding Method specified in Request",
@@ -270,6 +292,11 @@ var Codes = errorCodeMap{
 		Description:    "Policy exceeds the maximum allowed document size.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The tag provided was not a valid tag.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrIncompleteBody: {
 		Code:           "IncompleteBody",
 		Description:    "You did not provide the number of bytes specified by the Content-Length HTTP header.",
@@ -300,6 +327,11 @@ var Codes = errorCodeMap{
 		Description:    "The requested range is not satisfiable",
 		HTTPStatusCode: http.StatusRequestedRangeNotSatisfiable,
 	},
+	ErrInvalidWriteOffset: {
+		Code:           "InvalidWriteOffset",
+		Description:    "The write offset value that you specified does not match the current object size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrMalformedXML: {
 		Code:           "MalformedXML",
 		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
@@ -335,6 +367,21 @@ var Codes = errorCodeMap{
 		Description:    "The bucket lifecycle configuration does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchObjectLockConfiguration: {
+		Code:           "ObjectLockConfigurationNotFoundError",
+		Description:    "Object Lock configuration does not exist for this bucket",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchObjectRetention: {
+		Code:           "NoSuchObjectLockConfiguration",
+		Description:    "The specified object does not have a ObjectLock configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrNoSuchKey: {
 		Code:           "NoSuchKey",
 		Description:    "The specified key does not exist.",
@@ -430,6 +477,31 @@ var Codes = errorCodeMap{
 		Description:    "Policy has invalid resource.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrCORSForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. The origin, request method or request headers are not allowed by the CORS configuration of the bucket.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrObjectLocked: {
+		Code:           "AccessDenied",
+		Description:    "Access Denied because object protected by object lock.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrObjectLockNotEnabled: {
+		Code:           "InvalidRequest",
+		Description:    "Bucket is missing Object Lock Configuration",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectLock: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid object lock configuration or retention",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectKey: {
+		Code:           "InvalidArgument",
+		Description:    "Object key does not follow the key validation rules of the bucket.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrMissingFields: {
 		Code:           "MissingFields",
 		Description:    "Missing fields in request.",
@@ -742,6 +814,21 @@ var Codes = errorCodeMap{
 		Description:    "Invalid version found in the request",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrInvalidChecksum: {
+		Code:           "InvalidRequest",
+		Description:    "Value for the x-amz-checksum header is invalid or uses an unsupported algorithm.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectAttributes: {
+		Code:           "InvalidArgument",
+		Description:    "The x-amz-object-attributes header specifying the attributes to be retrieved is either missing, empty or invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidPartNumber: {
+		Code:           "InvalidArgument",
+		Description:    "Part number must be an integer between 1 and 10000, inclusive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	// LakeFS errors
 	ERRLakeFSNotSupported: {
@@ -759,9 +846,24 @@ var Codes = errorCodeMap{
 		Description:    "Attempted to write to a protected branch",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrWriteToLockedBranch: {
+		Code:           "ErrWriteToLockedBranch",
+		Description:    "Attempted to write to a locked branch",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrReadOnlyRepository: {
 		Code:           "ErrReadOnlyRepository",
 		Description:    "Attempted to write to a read-only repository",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrWriteToSessionRef: {
+		Code:           "ErrWriteToSessionRef",
+		Description:    "Attempted to write to a session reference, sessions read a pinned commit",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrExpiredSession: {
+		Code:           "ErrExpiredSession",
+		Description:    "The session of the reference expired, start a new session with another token",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 }
@@ -14,6 +14,7 @@ import (
 	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
 	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/session"
 	"github.com/treeverse/lakefs/pkg/gateway/sig"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
@@ -40,6 +41,8 @@ var commaSeparator = regexp.MustCompile(`,\s*`)
 const (
 	contentTypeApplicationXML = "application/xml"
 	contentTypeTextXML        = "text/xml"
+
+	gatewayServiceName = "s3_gateway"
 )
 
 var usageCounter = stats.NewUsageCounter()
@@ -60,9 +63,11 @@ type ServerContext struct {
 	stats             stats.Collector
 	pathProvider      upload.PathProvider
 	verifyUnsupported bool
+	limits            operations.UploadLimits
+	sessions          session.Sessions
 }
 
-func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multipart.Tracker, blockStore block.Adapter, authService auth.GatewayService, bareDomains []string, stats stats.Collector, pathProvider upload.PathProvider, fallbackURL *url.URL, auditLogLevel string, traceRequestHeaders bool, verifyUnsupported bool) http.Handler {
+func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multipart.Tracker, blockStore block.Adapter, authService auth.GatewayService, lockout *auth.Lockout, usage *auth.CredentialsUsage, bareDomains []string, stats stats.Collector, pathProvider upload.PathProvider, fallbackURL *url.URL, auditLogLevel string, traceRequestHeaders bool, verifyUnsupported bool, limits operations.UploadLimits, signingScope sig.SigningScope, listCompressionLevel int, overload OverloadLimits, sessions session.Sessions) http.Handler {
 	var fallbackHandler http.Handler
 	if fallbackURL != nil {
 		fallbackProxy := gohttputil.NewSingleHostReverseProxy(fallbackURL)
@@ -87,6 +92,8 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 		stats:             stats,
 		pathProvider:      pathProvider,
 		verifyUnsupported: verifyUnsupported,
+		limits:            limits,
+		sessions:          sessions,
 	}
 
 	// setup routes
@@ -95,14 +102,16 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 		sc:                 sc,
 		ServerErrorHandler: nil,
 		operationHandlers: map[operations.OperationID]http.Handler{
+			operations.OperationIDDeleteBucketPolicy:   RepoOperationHandler(sc, &operations.DeleteBucketPolicy{}),
+			operations.OperationIDDeleteBucketCors:     RepoOperationHandler(sc, &operations.DeleteBucketCors{}),
 			operations.OperationIDDeleteObject:         PathOperationHandler(sc, &operations.DeleteObject{}),
 			operations.OperationIDDeleteObjects:        RepoOperationHandler(sc, &operations.DeleteObjects{}),
 			operations.OperationIDGetObject:            PathOperationHandler(sc, &operations.GetObject{}),
 			operations.OperationIDPutBucket:            RepoOperationHandler(sc, &operations.PutBucket{}),
 			operations.OperationIDHeadBucket:           RepoOperationHandler(sc, &operations.HeadBucket{}),
 			operations.OperationIDHeadObject:           PathOperationHandler(sc, &operations.HeadObject{}),
-			operations.OperationIDListBuckets:          OperationHandler(sc, &operations.ListBuckets{}),
-			operations.OperationIDListObjects:          RepoOperationHandler(sc, &operations.ListObjects{}),
+			operations.OperationIDListBuckets:          compressListHandler(listCompressionLevel, OperationHandler(sc, &operations.ListBuckets{})),
+			operations.OperationIDListObjects:          compressListHandler(listCompressionLevel, RepoOperationHandler(sc, &operations.ListObjects{})),
 			operations.OperationIDPostObject:           PathOperationHandler(sc, &operations.PostObject{}),
 			operations.OperationIDPutObject:            PathOperationHandler(sc, &operations.PutObject{}),
 			operations.OperationIDUnsupportedOperation: unsupportedOperationHandler(),
@@ -110,7 +119,7 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 	}
 	loggingMiddleware := httputil.LoggingMiddleware(
 		"X-Amz-Request-Id",
-		logging.Fields{"service_name": "s3_gateway"},
+		logging.Fields{"service_name": gatewayServiceName},
 		auditLogLevel,
 		traceRequestHeaders)
 
@@ -118,10 +127,12 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 
 	h = EnrichWithOperation(sc,
 		DurationHandler(
-			AuthenticationHandler(authService, EnrichWithParts(bareDomains,
-				EnrichWithRepositoryOrFallback(catalog, authService, fallbackHandler,
-					OperationLookupHandler(
-						h))))))
+			OverloadHandler(overload,
+				CORSHandler(catalog, bareDomains,
+					AuthenticationHandler(authService, lockout, usage, signingScope, EnrichWithParts(bareDomains,
+						EnrichWithRepositoryOrFallback(catalog, authService, fallbackHandler,
+							OperationLookupHandler(
+								h))))))))
 	logging.ContextUnavailable().WithFields(logging.Fields{
 		"s3_bare_domain": bareDomains,
 		"s3_region":      region,
@@ -129,6 +140,14 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 	return h
 }
 
+// compressListHandler compresses the XML responses of a listing handler at level, unless level is zero
+func compressListHandler(level int, h http.Handler) http.Handler {
+	if level == 0 {
+		return h
+	}
+	return httputil.CompressResponseMiddleware(level, contentTypeApplicationXML, contentTypeTextXML)(h)
+}
+
 func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	setDefaultContentType(w, req)
 	o := req.Context().Value(ContextKeyOperation).(*operations.Operation)
@@ -204,6 +223,25 @@ func PathOperationHandler(sc *ServerContext, handler operations.PathOperationHan
 		path := ctx.Value(ContextKeyPath).(string)
 		matchedHost := ctx.Value(ContextKeyMatchedHost).(bool)
 		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
+		if isWriteOperation(o.OperationID) {
+			// session references read a pinned commit
+			isSession, err := o.IsSessionRef(ctx, repo.Name, refID)
+			if err != nil {
+				_ = o.EncodeError(w, req, err, operations.ResolveRefAPIErr(err))
+				return
+			}
+			if isSession {
+				_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrWriteToSessionRef))
+				return
+			}
+		} else {
+			var err error
+			refID, err = o.ResolveRef(ctx, repo.Name, refID)
+			if err != nil {
+				_ = o.EncodeError(w, req, err, operations.ResolveRefAPIErr(err))
+				return
+			}
+		}
 		perms, err := handler.RequiredPermissions(req, repo.Name, refID, path)
 		if err != nil {
 			if errors.Is(err, gatewayerrors.ErrInvalidCopySource) {
@@ -213,6 +251,22 @@ func PathOperationHandler(sc *ServerContext, handler operations.PathOperationHan
 			}
 			return
 		}
+		// policy conditions on object tags match the tags of the object the permission applies to, read only if such a
+		// condition applies. The source of a copy is read on its own reference.
+		if copySource := req.Header.Get(operations.CopySourceHeader); copySource != "" {
+			if source, err := operations.CopySourcePath(copySource); err == nil {
+				perms = perms.WithActionConditionValues(permissions.ReadObjectAction, permissions.ObjectArn(source.Repo, source.Path), permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+					sourceRef, err := o.ResolveRef(ctx, source.Repo, source.Reference)
+					if err != nil {
+						return nil, err
+					}
+					return sc.catalog.GetEntryTags(ctx, source.Repo, sourceRef, source.Path)
+				}))
+			}
+		}
+		perms = perms.WithConditionValues(permissions.ObjectArn(repo.Name, path), permissions.ObjectTagsConditionValues(func() (map[string]string, error) {
+			return sc.catalog.GetEntryTags(ctx, repo.Name, refID, path)
+		}))
 
 		authOp := authorize(w, req, sc.authService, perms)
 		if authOp == nil {
@@ -241,6 +295,18 @@ func PathOperationHandler(sc *ServerContext, handler operations.PathOperationHan
 	})
 }
 
+// isWriteOperation reports whether the operation writes to its reference: uploads and copies, including the create,
+// upload part, upload part copy, complete and abort calls of multipart uploads, and deletes. DeleteObjects checks the reference of
+// each of its keys.
+func isWriteOperation(operationID operations.OperationID) bool {
+	switch operationID {
+	case operations.OperationIDPutObject, operations.OperationIDPostObject, operations.OperationIDDeleteObject:
+		return true
+	default:
+		return false
+	}
+}
+
 func authorize(w http.ResponseWriter, req *http.Request, authService auth.GatewayService, perms permissions.Node) *operations.AuthorizedOperation {
 	ctx := req.Context()
 	o := ctx.Value(ContextKeyOperation).(*operations.Operation)
@@ -274,11 +340,11 @@ func authorize(w http.ResponseWriter, req *http.Request, authService auth.Gatewa
 		return nil
 	}
 	if authResp.Error != nil || !authResp.Allowed {
-		l := o.Log(req).WithError(authResp.Error)
+		logCtx := ctx
 		if accessKeyID != "" {
-			l = l.WithField("key", accessKeyID)
+			logCtx = logging.AddFields(ctx, logging.Fields{"key": accessKeyID})
 		}
-		l.Warn("no permission")
+		auth.RecordDenial(logCtx, gatewayServiceName, username, authResp.Denial)
 		_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 		return nil
 	}
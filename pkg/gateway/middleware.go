@@ -22,7 +22,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/stats"
 )
 
-func AuthenticationHandler(authService auth.GatewayService, next http.Handler) http.Handler {
+func AuthenticationHandler(authService auth.GatewayService, lockout *auth.Lockout, usage *auth.CredentialsUsage, signingScope sig.SigningScope, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		user, err := auth.GetUser(ctx)
@@ -35,6 +35,7 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
 		authenticator := sig.ChainedAuthenticator(
 			sig.NewV4Authenticator(req),
+			sig.NewV4aAuthenticator(req),
 			sig.NewV2SigAuthenticator(req, o.FQDN),
 		)
 		authContext, err := authenticator.Parse()
@@ -43,15 +44,27 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrAccessDenied))
 			return
 		}
+		if err := signingScope.Verify(authContext); err != nil {
+			o.Log(req).WithError(err).Warn("signature scope not accepted")
+			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrAccessDenied))
+			return
+		}
 		accessKeyID := authContext.GetAccessKeyID()
-		creds, err := authService.GetCredentials(ctx, accessKeyID)
+		sourceIP := lockout.SourceIP(req)
 		logger := o.Log(req)
+		if err := lockout.Check(accessKeyID, sourceIP); err != nil {
+			logger.WithError(err).Warn("access key or source locked out")
+			_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
+			return
+		}
+		creds, err := authService.GetCredentials(ctx, accessKeyID)
 		if err != nil {
 			if !errors.Is(err, auth.ErrNotFound) {
 				logger.WithError(err).Warn("error getting access key")
 				_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
 			} else {
 				logger.WithError(err).Warn("could not find access key")
+				lockout.RecordFailure(ctx, gatewayServiceName, accessKeyID, sourceIP)
 				_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			}
 			return
@@ -59,9 +72,13 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 		err = authenticator.Verify(creds)
 		if err != nil {
 			logger.WithError(err).Warn("error verifying credentials for key")
+			if !isClockSkewError(err) {
+				lockout.RecordFailure(ctx, gatewayServiceName, accessKeyID, sourceIP)
+			}
 			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrAccessDenied))
 			return
 		}
+		lockout.RecordSuccess(accessKeyID)
 
 		user, err = authService.GetUser(ctx, creds.Username)
 		if err != nil {
@@ -69,14 +86,27 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 			_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			return
 		}
+		if sessionManager, ok := auth.SessionManagerOf(ctx, authService); ok {
+			if err := sessionManager.RecordCredentialsUse(ctx, accessKeyID, sourceIP); err != nil {
+				logger.WithError(err).Warn("could not record credentials use")
+			}
+		}
 		ctx = logging.AddFields(ctx, logging.Fields{logging.UserFieldKey: user.Username})
 		ctx = auth.WithUser(ctx, user)
 		ctx = context.WithValue(ctx, ContextKeyAuthContext, authContext)
 		req = req.WithContext(ctx)
-		next.ServeHTTP(w, req)
+		usage.Handler(gatewayServiceName, accessKeyID, user.Username, next).ServeHTTP(w, req)
 	})
 }
 
+// isClockSkewError returns true if err rejects the time of a request rather than its signature.  Clients
+// with a skewed clock hold valid credentials, and are not locked out.
+func isClockSkewError(err error) bool {
+	return errors.Is(err, gatewayerrors.ErrRequestTimeTooSkewed) ||
+		errors.Is(err, gatewayerrors.ErrExpiredPresignRequest) ||
+		errors.Is(err, gatewayerrors.ErrMalformedCredentialDate)
+}
+
 func EnrichWithParts(bareDomains []string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
@@ -119,6 +149,8 @@ func EnrichWithOperation(sc *ServerContext, next http.Handler) http.Handler {
 			BlockStore:        sc.blockStore,
 			Auth:              sc.authService,
 			VerifyUnsupported: sc.verifyUnsupported,
+			Limits:            sc.limits,
+			Sessions:          sc.sessions,
 			Incr: func(action, userID, repository, ref string) {
 				logging.FromContext(ctx).
 					WithFields(logging.Fields{
@@ -190,6 +222,16 @@ func EnrichWithRepositoryOrFallback(c *catalog.Catalog, authService auth.Gateway
 			_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
 			return
 		}
+		ctx, err = c.WithCostAttribution(ctx, repoID)
+		if err != nil {
+			_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
+		ctx, err = c.WithBlockAdapterOverride(ctx, repoID)
+		if err != nil {
+			_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
 		req = req.WithContext(context.WithValue(ctx, ContextKeyRepository, repo))
 		next.ServeHTTP(w, req)
 	})
@@ -210,7 +252,7 @@ func OperationLookupHandler(next http.Handler) http.Handler {
 		case ref != "" && pth != "":
 			o.OperationID = pathBasedOperationID(req.Method)
 		case ref == "" && pth == "":
-			o.OperationID = repositoryBasedOperationID(req.Method)
+			o.OperationID = repositoryBasedOperationID(req)
 		default:
 			o.OperationID = operations.OperationIDOperationNotFound
 		}
@@ -313,9 +355,15 @@ func pathBasedOperationID(method string) operations.OperationID {
 	}
 }
 
-func repositoryBasedOperationID(method string) operations.OperationID {
-	switch method {
+func repositoryBasedOperationID(req *http.Request) operations.OperationID {
+	switch req.Method {
 	case http.MethodDelete:
+		if req.URL.Query().Has("policy") {
+			return operations.OperationIDDeleteBucketPolicy
+		}
+		if req.URL.Query().Has("cors") {
+			return operations.OperationIDDeleteBucketCors
+		}
 		return operations.OperationIDUnsupportedOperation
 	case http.MethodPut:
 		return operations.OperationIDPutBucket
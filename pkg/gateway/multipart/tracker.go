@@ -7,10 +7,15 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/kv"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-const storePartitionKey = "multiparts"
+const (
+	storePartitionKey = "multiparts"
+	// partsPartitionKey holds the checksums of the parts of uploads, keyed by upload ID and part number
+	partsPartitionKey = "multipart_parts"
+)
 
 type Metadata map[string]string
 
@@ -27,12 +32,32 @@ type Upload struct {
 	Metadata Metadata `db:"metadata"`
 	// ContentType Original file's content-type
 	ContentType string `db:"content_type"`
+	// StorageClass Storage class the upload was created with, empty for the default
+	StorageClass string `db:"storage_class"`
+	// Tags Object tags the upload was created with
+	Tags map[string]string `db:"tags"`
+}
+
+// PartChecksum is the additional checksum of an uploaded part
+type PartChecksum struct {
+	PartNumber int
+	// ETag of the part, identifying the upload of the part the checksum belongs to
+	ETag      string
+	Algorithm string
+	// Checksum base64 encoded value of the checksum
+	Checksum string
 }
 
 type Tracker interface {
 	Create(ctx context.Context, multipart Upload) error
 	Get(ctx context.Context, uploadID string) (*Upload, error)
+	// Delete deletes the upload and the checksums of its parts
 	Delete(ctx context.Context, uploadID string) error
+	// SetPartChecksum records the checksum of a part of the upload, replacing the checksum of a previous upload of
+	// the part
+	SetPartChecksum(ctx context.Context, uploadID string, checksum PartChecksum) error
+	// ListPartChecksums returns the recorded checksums of the parts of the upload, by part number
+	ListPartChecksums(ctx context.Context, uploadID string) (map[int]PartChecksum, error)
 }
 
 type tracker struct {
@@ -58,6 +83,8 @@ func multipartFromProto(pb *UploadData) *Upload {
 		PhysicalAddress: pb.PhysicalAddress,
 		Metadata:        pb.Metadata,
 		ContentType:     pb.ContentType,
+		StorageClass:    pb.StorageClass,
+		Tags:            pb.Tags,
 	}
 }
 
@@ -69,6 +96,8 @@ func protoFromMultipart(m *Upload) *UploadData {
 		PhysicalAddress: m.PhysicalAddress,
 		Metadata:        m.Metadata,
 		ContentType:     m.ContentType,
+		StorageClass:    m.StorageClass,
+		Tags:            m.Tags,
 	}
 }
 
@@ -103,5 +132,76 @@ func (m *tracker) Delete(ctx context.Context, uploadID string) error {
 		return err
 	}
 
+	if err := m.deletePartChecksums(ctx, uploadID); err != nil {
+		return err
+	}
 	return m.store.Delete(ctx, []byte(storePartitionKey), key)
 }
+
+func partsPrefix(uploadID string) []byte {
+	return []byte(uploadID + "/")
+}
+
+func partKey(uploadID string, partNumber int) []byte {
+	// zero padded to list the parts in order
+	return []byte(fmt.Sprintf("%s/%05d", uploadID, partNumber))
+}
+
+func (m *tracker) SetPartChecksum(ctx context.Context, uploadID string, checksum PartChecksum) error {
+	if uploadID == "" {
+		return ErrInvalidUploadID
+	}
+	return kv.SetMsg(ctx, m.store, partsPartitionKey, partKey(uploadID, checksum.PartNumber), &PartChecksumData{
+		PartNumber: int32(checksum.PartNumber), //nolint:gosec
+		Etag:       checksum.ETag,
+		Algorithm:  checksum.Algorithm,
+		Checksum:   checksum.Checksum,
+	})
+}
+
+func (m *tracker) ListPartChecksums(ctx context.Context, uploadID string) (map[int]PartChecksum, error) {
+	if uploadID == "" {
+		return nil, ErrInvalidUploadID
+	}
+	it, err := kv.ScanPrefix(ctx, m.store, []byte(partsPartitionKey), partsPrefix(uploadID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	checksums := make(map[int]PartChecksum)
+	for it.Next() {
+		var data PartChecksumData
+		if err := proto.Unmarshal(it.Entry().Value, &data); err != nil {
+			return nil, err
+		}
+		checksums[int(data.PartNumber)] = PartChecksum{
+			PartNumber: int(data.PartNumber),
+			ETag:       data.Etag,
+			Algorithm:  data.Algorithm,
+			Checksum:   data.Checksum,
+		}
+	}
+	return checksums, it.Err()
+}
+
+func (m *tracker) deletePartChecksums(ctx context.Context, uploadID string) error {
+	it, err := kv.ScanPrefix(ctx, m.store, []byte(partsPartitionKey), partsPrefix(uploadID), nil)
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, it.Entry().Key)
+	}
+	err = it.Err()
+	it.Close()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := m.store.Delete(ctx, []byte(partsPartitionKey), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
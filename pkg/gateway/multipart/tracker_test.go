@@ -0,0 +1,65 @@
+package multipart_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func TestTracker_PartChecksums(t *testing.T) {
+	ctx := context.Background()
+	tracker := multipart.NewTracker(kvtest.GetStore(ctx, t))
+	for _, uploadID := range []string{"upload", "upload2"} {
+		if err := tracker.Create(ctx, multipart.Upload{UploadID: uploadID, Path: "path", CreationDate: time.Now()}); err != nil {
+			t.Fatalf("Create(%s): %s", uploadID, err)
+		}
+	}
+
+	part1 := multipart.PartChecksum{PartNumber: 1, ETag: "etag1", Algorithm: "CRC32", Checksum: "DUoRhQ=="}
+	part2 := multipart.PartChecksum{PartNumber: 2, ETag: "etag2", Algorithm: "CRC32", Checksum: "AAAAAA=="}
+	// the checksum of an upload of part 2 again replaces it
+	part2Again := multipart.PartChecksum{PartNumber: 2, ETag: "etag3", Algorithm: "SHA1", Checksum: "Kq5sNclPz7QV2+lfQIuc6R7oRu0="}
+	for _, c := range []multipart.PartChecksum{part1, part2, part2Again} {
+		if err := tracker.SetPartChecksum(ctx, "upload", c); err != nil {
+			t.Fatalf("SetPartChecksum(%d): %s", c.PartNumber, err)
+		}
+	}
+	if err := tracker.SetPartChecksum(ctx, "upload2", part1); err != nil {
+		t.Fatalf("SetPartChecksum(upload2): %s", err)
+	}
+
+	checksums, err := tracker.ListPartChecksums(ctx, "upload")
+	if err != nil {
+		t.Fatalf("ListPartChecksums: %s", err)
+	}
+	if diff := deep.Equal(checksums, map[int]multipart.PartChecksum{1: part1, 2: part2Again}); diff != nil {
+		t.Errorf("ListPartChecksums diff: %s", diff)
+	}
+
+	if err := tracker.Delete(ctx, "upload"); err != nil {
+		t.Fatalf("Delete: %s", err)
+	}
+	checksums, err = tracker.ListPartChecksums(ctx, "upload")
+	if err != nil {
+		t.Fatalf("ListPartChecksums after delete: %s", err)
+	}
+	if len(checksums) != 0 {
+		t.Errorf("ListPartChecksums after delete returned %d checksums, expected none", len(checksums))
+	}
+	if _, err := tracker.Get(ctx, "upload"); !errors.Is(err, kv.ErrNotFound) {
+		t.Errorf("Get after delete error = %v, expected %v", err, kv.ErrNotFound)
+	}
+	checksums, err = tracker.ListPartChecksums(ctx, "upload2")
+	if err != nil {
+		t.Fatalf("ListPartChecksums(upload2): %s", err)
+	}
+	if diff := deep.Equal(checksums, map[int]multipart.PartChecksum{1: part1}); diff != nil {
+		t.Errorf("ListPartChecksums(upload2) diff: %s", diff)
+	}
+}
@@ -2,11 +2,13 @@ package operations
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
 
 	"github.com/treeverse/lakefs/pkg/auth"
@@ -15,6 +17,9 @@ import (
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gwerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/gateway/session"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
@@ -22,21 +27,33 @@ import (
 	"github.com/treeverse/lakefs/pkg/upload"
 )
 
-const StorageClassHeader = "x-amz-storage-class"
+const (
+	StorageClassHeader = "x-amz-storage-class"
+	TaggingHeader      = "x-amz-tagging"
+
+	// maxObjectTags, maxTagKeyLength and maxTagValueLength are the limits S3 sets on object tags
+	maxObjectTags     = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
 
 type OperationID string
 
 const (
-	OperationIDDeleteObject  OperationID = "delete_object"
-	OperationIDDeleteObjects OperationID = "delete_objects"
-	OperationIDGetObject     OperationID = "get_object"
-	OperationIDHeadBucket    OperationID = "head_bucket"
-	OperationIDHeadObject    OperationID = "head_object"
-	OperationIDListBuckets   OperationID = "list_buckets"
-	OperationIDListObjects   OperationID = "list_objects"
-	OperationIDPostObject    OperationID = "post_object"
-	OperationIDPutObject     OperationID = "put_object"
-	OperationIDPutBucket     OperationID = "put_bucket"
+	OperationIDDeleteBucketPolicy OperationID = "delete_bucket_policy"
+	OperationIDDeleteBucketCors   OperationID = "delete_bucket_cors"
+	OperationIDDeleteObject       OperationID = "delete_object"
+	OperationIDDeleteObjects      OperationID = "delete_objects"
+	OperationIDGetObject          OperationID = "get_object"
+	OperationIDHeadBucket         OperationID = "head_bucket"
+	OperationIDHeadObject         OperationID = "head_object"
+	OperationIDListBuckets        OperationID = "list_buckets"
+	OperationIDListObjects        OperationID = "list_objects"
+	OperationIDPostObject         OperationID = "post_object"
+	OperationIDPutObject          OperationID = "put_object"
+	OperationIDPutBucket          OperationID = "put_bucket"
 
 	OperationIDUnsupportedOperation OperationID = "unsupported"
 	OperationIDOperationNotFound    OperationID = "not_found"
@@ -56,6 +73,8 @@ type Operation struct {
 	MatchedHost       bool
 	PathProvider      upload.PathProvider
 	VerifyUnsupported bool
+	Limits            UploadLimits
+	Sessions          session.Sessions
 }
 
 func StorageClassFromHeader(header http.Header) *string {
@@ -66,6 +85,107 @@ func StorageClassFromHeader(header http.Header) *string {
 	return &storageClass
 }
 
+// TagsFromHeader returns the object tags set by the URL encoded tagging header, nil if it is not set
+func TagsFromHeader(header http.Header) (map[string]string, error) {
+	tagging := header.Get(TaggingHeader)
+	if tagging == "" {
+		return nil, nil
+	}
+	query, err := url.ParseQuery(tagging)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTag, err)
+	}
+	if len(query) > maxObjectTags {
+		return nil, fmt.Errorf("%w: more than %d tags", ErrInvalidTag, maxObjectTags)
+	}
+	tags := make(map[string]string, len(query))
+	for key, values := range query {
+		if key == "" || len(key) > maxTagKeyLength {
+			return nil, fmt.Errorf("%w: key '%s'", ErrInvalidTag, key)
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%w: key '%s' set more than once", ErrInvalidTag, key)
+		}
+		if len(values[0]) > maxTagValueLength {
+			return nil, fmt.Errorf("%w: value of key '%s'", ErrInvalidTag, key)
+		}
+		tags[key] = values[0]
+	}
+	return tags, nil
+}
+
+// ResolveRef returns the commit a session reference "<branch>@<token>" of repository is pinned to, and any other
+// reference unchanged. A tag of the form of a session reference resolves to its commit.
+func (o *Operation) ResolveRef(ctx context.Context, repository, ref string) (string, error) {
+	branch, token, ok := session.ParseRef(ref)
+	if !ok || o.Sessions == nil {
+		return ref, nil
+	}
+	commitID, isTag, err := o.resolveTag(ctx, repository, ref)
+	if err != nil || isTag {
+		return commitID, err
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	return o.Sessions.Resolve(ctx, repository, user.Username, branch, token, func() (string, error) {
+		return o.Catalog.GetBranchReference(ctx, repository, branch)
+	})
+}
+
+// IsSessionRef reports whether ref of repository is a session reference "<branch>@<token>"
+func (o *Operation) IsSessionRef(ctx context.Context, repository, ref string) (bool, error) {
+	if _, _, ok := session.ParseRef(ref); !ok || o.Sessions == nil {
+		return false, nil
+	}
+	_, isTag, err := o.resolveTag(ctx, repository, ref)
+	if err != nil {
+		return false, err
+	}
+	return !isTag, nil
+}
+
+// resolveTag returns the commit of tag ref of repository and whether the tag exists
+func (o *Operation) resolveTag(ctx context.Context, repository, ref string) (string, bool, error) {
+	commitID, err := o.Catalog.GetTag(ctx, repository, ref)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return commitID, true, nil
+}
+
+// ResolveCopySource returns the object the copy source header refers to, read from the commit its reference is
+// pinned to if it is a session reference
+func (o *Operation) ResolveCopySource(ctx context.Context, copySource string) (path.ResolvedAbsolutePath, error) {
+	p, err := CopySourcePath(copySource)
+	if err != nil {
+		return path.ResolvedAbsolutePath{}, err
+	}
+	p.Reference, err = o.ResolveRef(ctx, p.Repo, p.Reference)
+	if err != nil {
+		return path.ResolvedAbsolutePath{}, err
+	}
+	return p, nil
+}
+
+// ResolveRefAPIErr returns the API error of a failure to resolve a reference by ResolveRef
+func ResolveRefAPIErr(err error) gwerrors.APIError {
+	switch {
+	case errors.Is(err, session.ErrExpired):
+		return gwerrors.Codes.ToAPIErr(gwerrors.ErrExpiredSession)
+	case errors.Is(err, graveler.ErrNotFound):
+		return gwerrors.Codes.ToAPIErr(gwerrors.ErrNoSuchKey)
+	case errors.Is(err, graveler.ErrInvalid):
+		return gwerrors.Codes.ToAPIErr(gwerrors.ErrBadRequest)
+	default:
+		return gwerrors.Codes.ToAPIErr(gwerrors.ErrInternalError)
+	}
+}
+
 func (o *Operation) Log(req *http.Request) logging.Logger {
 	return logging.FromContext(req.Context())
 }
@@ -149,10 +269,7 @@ func (o *Operation) SetHeaders(w http.ResponseWriter, headers http.Header) {
 }
 
 func (o *Operation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
-	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
-		err = gwerrors.ErrSlowDown.ToAPIErr()
-	}
+	err := slowDownOrDefault(req, originalError, fallbackError)
 	req, rid := httputil.RequestID(req)
 	writeErr := EncodeResponse(w, gwerrors.APIErrorResponse{
 		Code:       err.Code,
@@ -170,6 +287,15 @@ func (o *Operation) EncodeError(w http.ResponseWriter, req *http.Request, origin
 	return req
 }
 
+// slowDownOrDefault returns SlowDown for errors of an overloaded lakeFS: a throttled KV store or an operation that
+// exceeded its latency budget. It returns fallbackError for other errors.
+func slowDownOrDefault(req *http.Request, originalError error, fallbackError gwerrors.APIError) gwerrors.APIError {
+	if errors.Is(originalError, kv.ErrSlowDown) || latencyBudgetExceeded(req) {
+		return gwerrors.ErrSlowDown.ToAPIErr()
+	}
+	return fallbackError
+}
+
 func generateHostID() string {
 	const generatedHostIDLength = 8
 	return keys.HexStringGenerator(generatedHostIDLength)
@@ -180,6 +306,42 @@ type AuthorizedOperation struct {
 	Principal string
 }
 
+// isAllowed reports whether the user of the operation is allowed to perform action on resource
+func (o *AuthorizedOperation) isAllowed(ctx context.Context, action, resource string) (bool, error) {
+	resp, err := o.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: o.Principal,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{
+				Action:   action,
+				Resource: resource,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Allowed, nil
+}
+
+// organizationListPrefix returns the prefix to list the repositories matching prefix that the user
+// of the operation may see: members of an organization only see the repositories of its
+// namespace.  It returns false if none of them matches prefix.
+func (o *AuthorizedOperation) organizationListPrefix(ctx context.Context, prefix string) (string, bool, error) {
+	organizations, ok := o.Auth.(auth.OrganizationManager)
+	if !ok {
+		return "", false, auth.ErrNotImplemented
+	}
+	organization, err := organizations.GetUserOrganization(ctx, o.Principal)
+	if errors.Is(err, auth.ErrNotFound) {
+		return prefix, true, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	restricted, ok := auth.RestrictToOrganization(organization, prefix)
+	return restricted, ok, nil
+}
+
 type RepoOperation struct {
 	*AuthorizedOperation
 	Repository  *catalog.Repository
@@ -187,10 +349,7 @@ type RepoOperation struct {
 }
 
 func (o *RepoOperation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
-	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
-		err = gwerrors.ErrSlowDown.ToAPIErr()
-	}
+	err := slowDownOrDefault(req, originalError, fallbackError)
 	req, rid := httputil.RequestID(req)
 	writeErr := EncodeResponse(w, gwerrors.APIErrorResponse{
 		Code:       err.Code,
@@ -219,10 +378,7 @@ type PathOperation struct {
 }
 
 func (o *PathOperation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
-	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
-		err = gwerrors.ErrSlowDown.ToAPIErr()
-	}
+	err := slowDownOrDefault(req, originalError, fallbackError)
 	req, rid := httputil.RequestID(req)
 	writeErr := EncodeResponse(w, gwerrors.APIErrorResponse{
 		Code:       err.Code,
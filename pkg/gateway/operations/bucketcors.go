@@ -0,0 +1,156 @@
+package operations
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	bucketCorsQueryParam = "cors"
+	// maxCORSRules is the maximal number of rules in a CORS configuration, same as S3
+	maxCORSRules = 100
+	// maxCORSConfigurationSize is the maximal size of a CORS configuration document, same as S3
+	maxCORSConfigurationSize = 64 * 1024
+)
+
+var ErrMalformedCORSConfiguration = errors.New("malformed CORS configuration")
+
+// corsAllowedMethods are the methods CORS rules may allow, same as S3
+var corsAllowedMethods = []string{http.MethodGet, http.MethodPut, http.MethodHead, http.MethodPost, http.MethodDelete}
+
+func bucketCorsRequiredPermissions(repoID, action string) permissions.Node {
+	return permissions.Node{
+		Permission: permissions.Permission{
+			Action:   action,
+			Resource: permissions.RepoArn(repoID),
+		},
+	}
+}
+
+// CORSRulesFromConfiguration validates a CORS configuration document and returns its rules
+func CORSRulesFromConfiguration(config *serde.CORSConfiguration) (*graveler.CORSRules, error) {
+	if len(config.CORSRules) == 0 {
+		return nil, fmt.Errorf("%w: no rules", ErrMalformedCORSConfiguration)
+	}
+	if len(config.CORSRules) > maxCORSRules {
+		return nil, fmt.Errorf("%w: more than %d rules", ErrMalformedCORSConfiguration, maxCORSRules)
+	}
+	rules := &graveler.CORSRules{}
+	for i, rule := range config.CORSRules {
+		if len(rule.AllowedOrigins) == 0 {
+			return nil, fmt.Errorf("%w: rule #%d has no allowed origin", ErrMalformedCORSConfiguration, i)
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if strings.Count(origin, "*") > 1 {
+				return nil, fmt.Errorf("%w: allowed origin '%s' can have at most one wildcard", ErrMalformedCORSConfiguration, origin)
+			}
+		}
+		if len(rule.AllowedMethods) == 0 {
+			return nil, fmt.Errorf("%w: rule #%d has no allowed method", ErrMalformedCORSConfiguration, i)
+		}
+		for _, method := range rule.AllowedMethods {
+			if !slices.Contains(corsAllowedMethods, method) {
+				return nil, fmt.Errorf("%w: unsupported method '%s'", ErrMalformedCORSConfiguration, method)
+			}
+		}
+		for _, header := range rule.AllowedHeaders {
+			if strings.Count(header, "*") > 1 {
+				return nil, fmt.Errorf("%w: allowed header '%s' can have at most one wildcard", ErrMalformedCORSConfiguration, header)
+			}
+		}
+		if rule.MaxAgeSeconds < 0 {
+			return nil, fmt.Errorf("%w: negative max age", ErrMalformedCORSConfiguration)
+		}
+		rules.Rules = append(rules.Rules, &graveler.CORSRule{
+			Id:             rule.ID,
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  rule.MaxAgeSeconds,
+		})
+	}
+	return rules, nil
+}
+
+func corsRulesToConfiguration(rules *graveler.CORSRules) *serde.CORSConfiguration {
+	config := &serde.CORSConfiguration{}
+	for _, rule := range rules.Rules {
+		config.CORSRules = append(config.CORSRules, serde.CORSRule{
+			ID:             rule.Id,
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  rule.MaxAgeSeconds,
+		})
+	}
+	return config
+}
+
+// handlePutBucketCors replaces the CORS rules of the repository
+func handlePutBucketCors(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("put_bucket_cors", o.Principal, o.Repository.Name, "")
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxCORSConfigurationSize+1))
+	if err != nil {
+		o.EncodeError(w, req, err, gatewayerrors.ErrIncompleteBody.ToAPIErr())
+		return
+	}
+	if len(body) > maxCORSConfigurationSize {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrEntityTooLarge.ToAPIErr())
+		return
+	}
+	var config serde.CORSConfiguration
+	if err := xml.Unmarshal(body, &config); err != nil {
+		o.EncodeError(w, req, err, gatewayerrors.ErrMalformedXML.ToAPIErr())
+		return
+	}
+	rules, err := CORSRulesFromConfiguration(&config)
+	if err != nil {
+		apiErr := gatewayerrors.ErrMalformedXML.ToAPIErr()
+		apiErr.Description = err.Error()
+		o.EncodeError(w, req, err, apiErr)
+		return
+	}
+	if err := o.Catalog.SetCORSRules(req.Context(), o.Repository.Name, rules); err != nil {
+		o.Log(req).WithError(err).Error("failed to set CORS rules")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func handleGetBucketCors(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("get_bucket_cors", o.Principal, o.Repository.Name, "")
+	rules, err := o.Catalog.GetCORSRules(req.Context(), o.Repository.Name)
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to get CORS rules")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	if len(rules.Rules) == 0 {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrNoSuchCORSConfiguration.ToAPIErr())
+		return
+	}
+	o.EncodeResponse(w, req, corsRulesToConfiguration(rules), http.StatusOK)
+}
+
+func handleDeleteBucketCors(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("delete_bucket_cors", o.Principal, o.Repository.Name, "")
+	if err := o.Catalog.SetCORSRules(req.Context(), o.Repository.Name, &graveler.CORSRules{}); err != nil {
+		o.Log(req).WithError(err).Error("failed to delete CORS rules")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,73 @@
+package operations_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+func TestCORSRulesFromConfiguration(t *testing.T) {
+	tt := []struct {
+		name        string
+		rules       []serde.CORSRule
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			rules: []serde.CORSRule{
+				{ID: "web", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"GET", "PUT"}, AllowedHeaders: []string{"*"}, MaxAgeSeconds: 3000},
+				{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"HEAD"}},
+			},
+		},
+		{
+			name:        "no rules",
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+		{
+			name:        "no origin",
+			rules:       []serde.CORSRule{{AllowedMethods: []string{"GET"}}},
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+		{
+			name:        "no method",
+			rules:       []serde.CORSRule{{AllowedOrigins: []string{"*"}}},
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+		{
+			name:        "unsupported method",
+			rules:       []serde.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}},
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+		{
+			name:        "origin with two wildcards",
+			rules:       []serde.CORSRule{{AllowedOrigins: []string{"https://*.example.*"}, AllowedMethods: []string{"GET"}}},
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+		{
+			name:        "negative max age",
+			rules:       []serde.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, MaxAgeSeconds: -1}},
+			expectedErr: operations.ErrMalformedCORSConfiguration,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := operations.CORSRulesFromConfiguration(&serde.CORSConfiguration{CORSRules: tc.rules})
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("CORSRulesFromConfiguration() error = %v, expected %v", err, tc.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(rules.Rules) != len(tc.rules) {
+				t.Fatalf("CORSRulesFromConfiguration() got %d rules, expected %d", len(rules.Rules), len(tc.rules))
+			}
+			for i, rule := range rules.Rules {
+				if rule.Id != tc.rules[i].ID || rule.MaxAgeSeconds != tc.rules[i].MaxAgeSeconds {
+					t.Errorf("rule #%d = %v, expected %v", i, rule, tc.rules[i])
+				}
+			}
+		})
+	}
+}
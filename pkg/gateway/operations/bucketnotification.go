@@ -0,0 +1,285 @@
+package operations
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	bucketNotificationQueryParam = "notification"
+	// maxNotificationConfigurationSize is the maximal size of a notification configuration document
+	maxNotificationConfigurationSize = 64 * 1024
+	// maxNotificationFilterValueLength is the maximal length of a filter rule value, same as S3
+	maxNotificationFilterValueLength = 1024
+
+	// ActionNotificationIDPrefix prefixes the IDs of the notification configurations mapped from lakeFS actions.
+	// They are derived from the actions of the repository, and are ignored when put.
+	ActionNotificationIDPrefix = "lakefs-actions/"
+
+	notificationDestinationTopic         = "Topic"
+	notificationDestinationQueue         = "Queue"
+	notificationDestinationCloudFunction = "CloudFunction"
+
+	notificationFilterPrefix = "prefix"
+	notificationFilterSuffix = "suffix"
+)
+
+var (
+	ErrUnsupportedNotificationEvent       = errors.New("unsupported notification event")
+	ErrInvalidNotificationARN             = errors.New("invalid notification destination ARN")
+	ErrInvalidNotificationFilter          = errors.New("invalid notification filter")
+	ErrUnsupportedNotificationDestination = errors.New("unsupported notification destination")
+)
+
+// notificationEvents are the S3 events that notification configurations may select, the events of writing and
+// deleting objects
+var notificationEvents = []string{
+	"s3:ObjectCreated:*",
+	"s3:ObjectCreated:Put",
+	"s3:ObjectCreated:Post",
+	"s3:ObjectCreated:Copy",
+	"s3:ObjectCreated:CompleteMultipartUpload",
+	"s3:ObjectRemoved:*",
+	"s3:ObjectRemoved:Delete",
+	"s3:ObjectRemoved:DeleteMarkerCreated",
+}
+
+// actionNotificationEvents are the S3 events of the lakeFS events that actions mapped to notification
+// configurations run on
+var actionNotificationEvents = map[graveler.EventType][]string{
+	graveler.EventTypePostCommit: {"s3:ObjectCreated:*", "s3:ObjectRemoved:*"},
+	graveler.EventTypePostMerge:  {"s3:ObjectCreated:*", "s3:ObjectRemoved:*"},
+}
+
+func bucketNotificationRequiredPermissions(repoID, action string) permissions.Node {
+	return permissions.Node{
+		Permission: permissions.Permission{
+			Action:   action,
+			Resource: permissions.RepoArn(repoID),
+		},
+	}
+}
+
+func notificationFromConfiguration(id, destinationType, arn string, events []string, filter *serde.NotificationFilter) (*graveler.BucketNotification, error) {
+	if parts := strings.SplitN(arn, ":", 6); len(parts) != 6 || parts[0] != "arn" {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidNotificationARN, arn)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: configuration '%s' has no event", ErrUnsupportedNotificationEvent, id)
+	}
+	for _, event := range events {
+		if !slices.Contains(notificationEvents, event) {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnsupportedNotificationEvent, event)
+		}
+	}
+	notification := &graveler.BucketNotification{
+		Id:              id,
+		DestinationType: destinationType,
+		DestinationArn:  arn,
+		Events:          events,
+	}
+	if notification.Id == "" {
+		notification.Id = uuid.NewString()
+	}
+	if filter == nil {
+		return notification, nil
+	}
+	var hasPrefix, hasSuffix bool
+	for _, rule := range filter.FilterRules {
+		if len(rule.Value) > maxNotificationFilterValueLength {
+			return nil, fmt.Errorf("%w: value longer than %d bytes", ErrInvalidNotificationFilter, maxNotificationFilterValueLength)
+		}
+		switch strings.ToLower(rule.Name) {
+		case notificationFilterPrefix:
+			if hasPrefix {
+				return nil, fmt.Errorf("%w: more than one prefix rule", ErrInvalidNotificationFilter)
+			}
+			hasPrefix = true
+			notification.FilterPrefix = rule.Value
+		case notificationFilterSuffix:
+			if hasSuffix {
+				return nil, fmt.Errorf("%w: more than one suffix rule", ErrInvalidNotificationFilter)
+			}
+			hasSuffix = true
+			notification.FilterSuffix = rule.Value
+		default:
+			return nil, fmt.Errorf("%w: rule name '%s' must be prefix or suffix", ErrInvalidNotificationFilter, rule.Name)
+		}
+	}
+	return notification, nil
+}
+
+// NotificationsFromConfiguration validates a notification configuration document and returns its notifications.
+// Configurations mapped from lakeFS actions are skipped, so that a configuration read can be put back.
+func NotificationsFromConfiguration(config *serde.NotificationConfiguration) (*graveler.BucketNotifications, error) {
+	if config.EventBridgeConfiguration != nil {
+		return nil, fmt.Errorf("%w: EventBridge", ErrUnsupportedNotificationDestination)
+	}
+	notifications := &graveler.BucketNotifications{}
+	add := func(id, destinationType, arn string, events []string, filter *serde.NotificationFilter) error {
+		if strings.HasPrefix(id, ActionNotificationIDPrefix) {
+			return nil
+		}
+		notification, err := notificationFromConfiguration(id, destinationType, arn, events, filter)
+		if err != nil {
+			return err
+		}
+		notifications.Notifications = append(notifications.Notifications, notification)
+		return nil
+	}
+	for _, c := range config.TopicConfigurations {
+		if err := add(c.ID, notificationDestinationTopic, c.Topic, c.Events, c.Filter); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range config.QueueConfigurations {
+		if err := add(c.ID, notificationDestinationQueue, c.Queue, c.Events, c.Filter); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range config.CloudFunctionConfigurations {
+		if err := add(c.ID, notificationDestinationCloudFunction, c.CloudFunction, c.Events, c.Filter); err != nil {
+			return nil, err
+		}
+	}
+	return notifications, nil
+}
+
+func notificationFilter(notification *graveler.BucketNotification) *serde.NotificationFilter {
+	var rules []serde.FilterRule
+	if notification.FilterPrefix != "" {
+		rules = append(rules, serde.FilterRule{Name: notificationFilterPrefix, Value: notification.FilterPrefix})
+	}
+	if notification.FilterSuffix != "" {
+		rules = append(rules, serde.FilterRule{Name: notificationFilterSuffix, Value: notification.FilterSuffix})
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return &serde.NotificationFilter{FilterRules: rules}
+}
+
+func notificationsToConfiguration(notifications *graveler.BucketNotifications) *serde.NotificationConfiguration {
+	config := &serde.NotificationConfiguration{}
+	for _, n := range notifications.Notifications {
+		filter := notificationFilter(n)
+		switch n.DestinationType {
+		case notificationDestinationTopic:
+			config.TopicConfigurations = append(config.TopicConfigurations, serde.TopicConfiguration{ID: n.Id, Topic: n.DestinationArn, Events: n.Events, Filter: filter})
+		case notificationDestinationQueue:
+			config.QueueConfigurations = append(config.QueueConfigurations, serde.QueueConfiguration{ID: n.Id, Queue: n.DestinationArn, Events: n.Events, Filter: filter})
+		case notificationDestinationCloudFunction:
+			config.CloudFunctionConfigurations = append(config.CloudFunctionConfigurations, serde.CloudFunctionConfiguration{ID: n.Id, CloudFunction: n.DestinationArn, Events: n.Events, Filter: filter})
+		}
+	}
+	return config
+}
+
+// ActionsToNotificationConfigurations maps the hooks of actions running after commits and merges, the event sinks
+// of lakeFS, to topic configurations of the repository
+func ActionsToNotificationConfigurations(repositoryID string, actionList []*actions.Action) []serde.TopicConfiguration {
+	var configs []serde.TopicConfiguration
+	for _, action := range actionList {
+		var events []string
+		for eventType := range action.On {
+			for _, event := range actionNotificationEvents[eventType] {
+				if !slices.Contains(events, event) {
+					events = append(events, event)
+				}
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+		slices.Sort(events)
+		for _, hook := range action.Hooks {
+			configs = append(configs, serde.TopicConfiguration{
+				ID:     ActionNotificationIDPrefix + action.Name + "/" + hook.ID,
+				Topic:  fmt.Sprintf("arn:lakefs:actions:::repository/%s/action/%s/hook/%s", repositoryID, action.Name, hook.ID),
+				Events: events,
+			})
+		}
+	}
+	return configs
+}
+
+// handlePutBucketNotification replaces the notification configurations of the repository. Notifications are not
+// delivered: the configuration is kept for tools that configure it and read it back.
+func handlePutBucketNotification(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("put_bucket_notification", o.Principal, o.Repository.Name, "")
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxNotificationConfigurationSize+1))
+	if err != nil {
+		o.EncodeError(w, req, err, gatewayerrors.ErrIncompleteBody.ToAPIErr())
+		return
+	}
+	if len(body) > maxNotificationConfigurationSize {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrEntityTooLarge.ToAPIErr())
+		return
+	}
+	var config serde.NotificationConfiguration
+	if err := xml.Unmarshal(body, &config); err != nil {
+		o.EncodeError(w, req, err, gatewayerrors.ErrMalformedXML.ToAPIErr())
+		return
+	}
+	notifications, err := NotificationsFromConfiguration(&config)
+	if err != nil {
+		var apiErr gatewayerrors.APIError
+		switch {
+		case errors.Is(err, ErrUnsupportedNotificationEvent):
+			apiErr = gatewayerrors.ErrEventNotification.ToAPIErr()
+		case errors.Is(err, ErrInvalidNotificationARN):
+			apiErr = gatewayerrors.ErrARNNotification.ToAPIErr()
+		case errors.Is(err, ErrInvalidNotificationFilter):
+			apiErr = gatewayerrors.ErrFilterNameInvalid.ToAPIErr()
+		default:
+			apiErr = gatewayerrors.ErrUnsupportedNotification.ToAPIErr()
+		}
+		apiErr.Description = err.Error()
+		o.EncodeError(w, req, err, apiErr)
+		return
+	}
+	if err := o.Catalog.SetBucketNotifications(req.Context(), o.Repository.Name, notifications); err != nil {
+		o.Log(req).WithError(err).Error("failed to set bucket notifications")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGetBucketNotification returns the notification configurations put to the repository, together with the
+// configurations mapped from the actions on its default branch
+func handleGetBucketNotification(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("get_bucket_notification", o.Principal, o.Repository.Name, "")
+	ctx := req.Context()
+	notifications, err := o.Catalog.GetBucketNotifications(ctx, o.Repository.Name)
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to get bucket notifications")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	config := notificationsToConfiguration(notifications)
+
+	actionList, err := actions.LoadActions(ctx, catalog.NewActionsSource(o.Catalog), graveler.HookRecord{
+		RepositoryID: graveler.RepositoryID(o.Repository.Name),
+		SourceRef:    graveler.Ref(o.Repository.DefaultBranch),
+	})
+	if err != nil {
+		// invalid actions fail the hooks that load them, not reading the configuration
+		o.Log(req).WithError(err).Warn("failed to load actions for bucket notifications")
+	}
+	config.TopicConfigurations = append(config.TopicConfigurations, ActionsToNotificationConfigurations(o.Repository.Name, actionList)...)
+	o.EncodeResponse(w, req, config, http.StatusOK)
+}
@@ -0,0 +1,142 @@
+package operations_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestNotificationsFromConfiguration(t *testing.T) {
+	const topicARN = "arn:aws:sns:us-east-1:123456789012:events"
+	tt := []struct {
+		name          string
+		config        serde.NotificationConfiguration
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			name: "valid",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{
+					{ID: "created", Topic: topicARN, Events: []string{"s3:ObjectCreated:*"}, Filter: &serde.NotificationFilter{
+						FilterRules: []serde.FilterRule{{Name: "Prefix", Value: "tables/"}, {Name: "suffix", Value: ".parquet"}},
+					}},
+				},
+				QueueConfigurations: []serde.QueueConfiguration{
+					{Queue: "arn:aws:sqs:us-east-1:123456789012:queue", Events: []string{"s3:ObjectRemoved:Delete"}},
+				},
+			},
+			expectedCount: 2,
+		},
+		{
+			name:          "empty",
+			expectedCount: 0,
+		},
+		{
+			name: "actions skipped",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{
+					{ID: operations.ActionNotificationIDPrefix + "action/hook", Topic: "arn:lakefs:actions:::repository/repo/action/action/hook/hook", Events: []string{"s3:ObjectCreated:*"}},
+				},
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "unsupported event",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{{Topic: topicARN, Events: []string{"s3:ObjectRestore:Post"}}},
+			},
+			expectedErr: operations.ErrUnsupportedNotificationEvent,
+		},
+		{
+			name: "no event",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{{Topic: topicARN}},
+			},
+			expectedErr: operations.ErrUnsupportedNotificationEvent,
+		},
+		{
+			name: "invalid arn",
+			config: serde.NotificationConfiguration{
+				CloudFunctionConfigurations: []serde.CloudFunctionConfiguration{{CloudFunction: "function", Events: []string{"s3:ObjectCreated:Put"}}},
+			},
+			expectedErr: operations.ErrInvalidNotificationARN,
+		},
+		{
+			name: "invalid filter name",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{{Topic: topicARN, Events: []string{"s3:ObjectCreated:*"}, Filter: &serde.NotificationFilter{
+					FilterRules: []serde.FilterRule{{Name: "infix", Value: "x"}},
+				}}},
+			},
+			expectedErr: operations.ErrInvalidNotificationFilter,
+		},
+		{
+			name: "two prefix rules",
+			config: serde.NotificationConfiguration{
+				TopicConfigurations: []serde.TopicConfiguration{{Topic: topicARN, Events: []string{"s3:ObjectCreated:*"}, Filter: &serde.NotificationFilter{
+					FilterRules: []serde.FilterRule{{Name: "prefix", Value: "a/"}, {Name: "prefix", Value: "b/"}},
+				}}},
+			},
+			expectedErr: operations.ErrInvalidNotificationFilter,
+		},
+		{
+			name:        "eventbridge",
+			config:      serde.NotificationConfiguration{EventBridgeConfiguration: &struct{}{}},
+			expectedErr: operations.ErrUnsupportedNotificationDestination,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications, err := operations.NotificationsFromConfiguration(&tc.config)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("NotificationsFromConfiguration() error = %v, expected %v", err, tc.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(notifications.Notifications) != tc.expectedCount {
+				t.Fatalf("NotificationsFromConfiguration() got %d notifications, expected %d", len(notifications.Notifications), tc.expectedCount)
+			}
+			for i, notification := range notifications.Notifications {
+				if notification.Id == "" {
+					t.Errorf("notification #%d has no ID", i)
+				}
+			}
+		})
+	}
+}
+
+func TestActionsToNotificationConfigurations(t *testing.T) {
+	actionList := []*actions.Action{
+		{
+			Name:  "notify",
+			On:    map[graveler.EventType]*actions.ActionOn{graveler.EventTypePostCommit: {}, graveler.EventTypePostMerge: {}},
+			Hooks: []actions.ActionHook{{ID: "webhook"}, {ID: "airflow"}},
+		},
+		{
+			Name:  "check",
+			On:    map[graveler.EventType]*actions.ActionOn{graveler.EventTypePreCommit: {}},
+			Hooks: []actions.ActionHook{{ID: "format"}},
+		},
+	}
+	configs := operations.ActionsToNotificationConfigurations("repo", actionList)
+	if len(configs) != 2 {
+		t.Fatalf("ActionsToNotificationConfigurations() got %d configurations, expected 2", len(configs))
+	}
+	const expectedID = operations.ActionNotificationIDPrefix + "notify/webhook"
+	if configs[0].ID != expectedID {
+		t.Errorf("configuration ID = %s, expected %s", configs[0].ID, expectedID)
+	}
+	const expectedTopic = "arn:lakefs:actions:::repository/repo/action/notify/hook/webhook"
+	if configs[0].Topic != expectedTopic {
+		t.Errorf("configuration topic = %s, expected %s", configs[0].Topic, expectedTopic)
+	}
+	if len(configs[0].Events) != 2 {
+		t.Errorf("configuration events = %v, expected created and removed", configs[0].Events)
+	}
+}
@@ -0,0 +1,485 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	bucketPolicyQueryParam = "policy"
+	// bucketPolicyNamePrefix prefixes the name of the lakeFS policy created for a bucket policy
+	bucketPolicyNamePrefix = "S3BucketPolicy-"
+	// maxBucketPolicySize is the maximal size of a bucket policy document, same as S3
+	maxBucketPolicySize = 20 * 1024
+
+	s3ArnPrefix     = "arn:aws:s3:::"
+	iamUserArnToken = ":user/"
+)
+
+var (
+	ErrMalformedBucketPolicy   = errors.New("malformed bucket policy")
+	ErrUnsupportedBucketPolicy = errors.New("unsupported bucket policy")
+)
+
+// s3ToLakeFSActions maps the S3 actions supported in bucket policies to the lakeFS actions they grant on the bucket
+// and on the objects in it. S3 ignores the actions of a statement that do not apply to its resources.
+var s3ToLakeFSActions = map[string]struct {
+	bucketActions []string
+	objectActions []string
+}{
+	"s3:*": {
+		bucketActions: []string{"fs:*"},
+		objectActions: []string{permissions.ReadObjectAction, permissions.WriteObjectAction, permissions.DeleteObjectAction},
+	},
+	"s3:GetObject":                  {objectActions: []string{permissions.ReadObjectAction}},
+	"s3:GetObjectVersion":           {objectActions: []string{permissions.ReadObjectAction}},
+	"s3:PutObject":                  {objectActions: []string{permissions.WriteObjectAction}},
+	"s3:AbortMultipartUpload":       {objectActions: []string{permissions.WriteObjectAction}},
+	"s3:DeleteObject":               {objectActions: []string{permissions.DeleteObjectAction}},
+	"s3:ListBucket":                 {bucketActions: []string{permissions.ListObjectsAction}},
+	"s3:ListBucketVersions":         {bucketActions: []string{permissions.ListObjectsAction}},
+	"s3:ListBucketMultipartUploads": {bucketActions: []string{permissions.ListObjectsAction}},
+	"s3:GetBucketLocation":          {bucketActions: []string{permissions.ReadRepositoryAction}},
+}
+
+// stringOrSlice accepts a JSON string or an array of strings, as used by IAM policy documents
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*s = multi
+	return nil
+}
+
+// bucketPolicyPrincipal accepts "*" or {"AWS": ...}
+type bucketPolicyPrincipal struct {
+	Any bool
+	AWS stringOrSlice
+}
+
+func (p *bucketPolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single != "*" {
+			return fmt.Errorf("principal '%s': %w", single, ErrMalformedBucketPolicy)
+		}
+		p.Any = true
+		return nil
+	}
+	var principals map[string]stringOrSlice
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return err
+	}
+	for k, v := range principals {
+		if k != "AWS" {
+			return fmt.Errorf("principal type '%s': %w", k, ErrUnsupportedBucketPolicy)
+		}
+		p.AWS = v
+	}
+	return nil
+}
+
+type bucketPolicyStatement struct {
+	Sid          string                 `json:"Sid"`
+	Effect       string                 `json:"Effect"`
+	Principal    *bucketPolicyPrincipal `json:"Principal"`
+	NotPrincipal json.RawMessage        `json:"NotPrincipal"`
+	Action       stringOrSlice          `json:"Action"`
+	NotAction    json.RawMessage        `json:"NotAction"`
+	Resource     stringOrSlice          `json:"Resource"`
+	NotResource  json.RawMessage        `json:"NotResource"`
+	Condition    json.RawMessage        `json:"Condition"`
+}
+
+type bucketPolicyDocument struct {
+	Version   string                  `json:"Version"`
+	ID        string                  `json:"Id"`
+	Statement []bucketPolicyStatement `json:"Statement"`
+}
+
+// BucketPolicyTranslation is the lakeFS equivalent of a bucket policy: a policy and the users to attach it to
+type BucketPolicyTranslation struct {
+	Policy *model.Policy
+	Users  []string
+}
+
+// BucketPolicyName returns the name of the lakeFS policy holding the translated bucket policy of repository
+func BucketPolicyName(repository string) string {
+	return bucketPolicyNamePrefix + repository
+}
+
+// TranslateBucketPolicy translates an S3 bucket policy document of the bucket matching repository into a lakeFS
+// policy. Statements that cannot be expressed by lakeFS policies are rejected and reported together in the
+// returned error, which wraps ErrUnsupportedBucketPolicy.
+func TranslateBucketPolicy(repository string, document []byte) (*BucketPolicyTranslation, error) {
+	var doc bucketPolicyDocument
+	if err := json.Unmarshal(document, &doc); err != nil {
+		if errors.Is(err, ErrUnsupportedBucketPolicy) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrMalformedBucketPolicy, err)
+	}
+	if len(doc.Statement) == 0 {
+		return nil, fmt.Errorf("%w: no statements", ErrMalformedBucketPolicy)
+	}
+
+	var (
+		statements  model.Statements
+		users       []string
+		seenUsers   = make(map[string]struct{})
+		unsupported []string
+	)
+	for i, stmt := range doc.Statement {
+		name := stmt.Sid
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		translated, principals, err := translateBucketPolicyStatement(repository, stmt)
+		if err != nil {
+			unsupported = append(unsupported, fmt.Sprintf("statement %s: %s", name, err))
+			continue
+		}
+		statements = append(statements, translated...)
+		for _, u := range principals {
+			if _, ok := seenUsers[u]; !ok {
+				seenUsers[u] = struct{}{}
+				users = append(users, u)
+			}
+		}
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBucketPolicy, strings.Join(unsupported, "; "))
+	}
+	return &BucketPolicyTranslation{
+		Policy: &model.Policy{
+			CreatedAt:   time.Now().UTC(),
+			DisplayName: BucketPolicyName(repository),
+			Statement:   statements,
+		},
+		Users: users,
+	}, nil
+}
+
+func translateBucketPolicyStatement(repository string, stmt bucketPolicyStatement) (model.Statements, []string, error) {
+	switch {
+	case stmt.NotPrincipal != nil:
+		return nil, nil, errors.New("NotPrincipal is not supported")
+	case stmt.NotAction != nil:
+		return nil, nil, errors.New("NotAction is not supported")
+	case stmt.NotResource != nil:
+		return nil, nil, errors.New("NotResource is not supported")
+	case stmt.Condition != nil:
+		return nil, nil, errors.New("Condition is not supported")
+	}
+
+	var effect string
+	switch stmt.Effect {
+	case "Allow":
+		effect = model.StatementEffectAllow
+	case "Deny":
+		effect = model.StatementEffectDeny
+	default:
+		return nil, nil, fmt.Errorf("invalid effect '%s'", stmt.Effect)
+	}
+
+	users, err := bucketPolicyUsers(stmt.Principal)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stmt.Action) == 0 {
+		return nil, nil, errors.New("missing action")
+	}
+	if len(stmt.Resource) == 0 {
+		return nil, nil, errors.New("missing resource")
+	}
+
+	var (
+		bucketActions []string
+		objectActions []string
+	)
+	for _, action := range stmt.Action {
+		mapping, ok := s3ToLakeFSActions[action]
+		if !ok {
+			return nil, nil, fmt.Errorf("action '%s' is not supported", action)
+		}
+		bucketActions = append(bucketActions, mapping.bucketActions...)
+		objectActions = append(objectActions, mapping.objectActions...)
+	}
+
+	var statements model.Statements
+	for _, resource := range stmt.Resource {
+		arn, isBucket, err := bucketPolicyResourceArn(repository, resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		actions := objectActions
+		if isBucket {
+			actions = bucketActions
+		}
+		if len(actions) == 0 {
+			// S3 ignores actions that do not apply to the resource type
+			continue
+		}
+		statements = append(statements, model.Statement{
+			Effect:   effect,
+			Action:   actions,
+			Resource: arn,
+		})
+	}
+	if len(statements) == 0 {
+		return nil, nil, errors.New("no action applies to the resources")
+	}
+	return statements, users, nil
+}
+
+// bucketPolicyUsers returns the lakeFS users matching the statement principal. IAM user ARNs are matched by user
+// name, any other value is taken as a lakeFS user ID.
+func bucketPolicyUsers(principal *bucketPolicyPrincipal) ([]string, error) {
+	if principal == nil {
+		return nil, errors.New("missing principal")
+	}
+	if principal.Any {
+		return nil, errors.New("anonymous principal '*' is not supported")
+	}
+	users := make([]string, 0, len(principal.AWS))
+	for _, p := range principal.AWS {
+		switch {
+		case p == "*":
+			return nil, errors.New("anonymous principal '*' is not supported")
+		case strings.HasPrefix(p, "arn:"):
+			idx := strings.Index(p, iamUserArnToken)
+			if idx == -1 {
+				return nil, fmt.Errorf("principal '%s' is not an IAM user", p)
+			}
+			users = append(users, p[idx+len(iamUserArnToken):])
+		default:
+			users = append(users, p)
+		}
+	}
+	if len(users) == 0 {
+		return nil, errors.New("missing principal")
+	}
+	return users, nil
+}
+
+// bucketPolicyResourceArn translates an S3 resource of the bucket to a lakeFS resource, and reports whether it
+// refers to the bucket itself. Object keys in the gateway start with the ref, which lakeFS object resources do
+// not include - so only resources that match any ref are supported.
+func bucketPolicyResourceArn(repository, resource string) (string, bool, error) {
+	rest, ok := strings.CutPrefix(resource, s3ArnPrefix)
+	if !ok {
+		return "", false, fmt.Errorf("resource '%s' is not an S3 resource", resource)
+	}
+	bucket, key, hasKey := strings.Cut(rest, "/")
+	if bucket != repository {
+		return "", false, fmt.Errorf("resource '%s' does not belong to bucket '%s'", resource, repository)
+	}
+	if !hasKey {
+		return permissions.RepoArn(repository), true, nil
+	}
+	if key == "*" {
+		return permissions.ObjectArn(repository, "*"), false, nil
+	}
+	ref, path, _ := strings.Cut(key, "/")
+	if ref != "*" {
+		return "", false, fmt.Errorf("resource '%s' is limited to ref '%s', only '*' is supported as the ref part of the key", resource, ref)
+	}
+	return permissions.ObjectArn(repository, path), false, nil
+}
+
+// bucketPolicyManager is implemented by auth services that can manage policies
+type bucketPolicyManager interface {
+	WritePolicy(ctx context.Context, policy *model.Policy, update bool) error
+	DeletePolicy(ctx context.Context, policyDisplayName string) error
+	AttachPolicyToUser(ctx context.Context, policyDisplayName, username string) error
+	DetachPolicyFromUser(ctx context.Context, policyDisplayName, username string) error
+	ListUsers(ctx context.Context, params *model.PaginationParams) ([]*model.User, *model.Paginator, error)
+	ListUserPolicies(ctx context.Context, username string, params *model.PaginationParams) ([]*model.Policy, *model.Paginator, error)
+}
+
+// bucketPolicyUsersPageSize is the page size of listing users when looking up the users attached to a bucket policy
+const bucketPolicyUsersPageSize = 1000
+
+// bucketPolicyAttachedUsers returns the users the policy is attached to
+func bucketPolicyAttachedUsers(ctx context.Context, manager bucketPolicyManager, policyDisplayName string) (map[string]struct{}, error) {
+	attached := make(map[string]struct{})
+	after := ""
+	for {
+		users, paginator, err := manager.ListUsers(ctx, &model.PaginationParams{After: after, Amount: bucketPolicyUsersPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		for _, user := range users {
+			// the policy sorts first among the policies it prefixes
+			policies, _, err := manager.ListUserPolicies(ctx, user.Username, &model.PaginationParams{Prefix: policyDisplayName, Amount: 1})
+			if err != nil {
+				return nil, fmt.Errorf("list policies of user %s: %w", user.Username, err)
+			}
+			if len(policies) > 0 && policies[0].DisplayName == policyDisplayName {
+				attached[user.Username] = struct{}{}
+			}
+		}
+		if paginator == nil || paginator.NextPageToken == "" {
+			return attached, nil
+		}
+		after = paginator.NextPageToken
+	}
+}
+
+func bucketPolicyRequiredPermissions(repoID string, actions ...string) permissions.Node {
+	nodes := make([]permissions.Node, 0, len(actions))
+	for _, action := range actions {
+		nodes = append(nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   action,
+				Resource: permissions.PolicyArn(BucketPolicyName(repoID)),
+			},
+		})
+	}
+	return permissions.Node{
+		Type:  permissions.NodeTypeAnd,
+		Nodes: nodes,
+	}
+}
+
+func encodeBucketPolicyError(w http.ResponseWriter, req *http.Request, o *RepoOperation, err error) {
+	var apiErr gatewayerrors.APIError
+	switch {
+	case errors.Is(err, ErrMalformedBucketPolicy):
+		apiErr = gatewayerrors.ErrMalformedPolicy.ToAPIErr()
+	case errors.Is(err, ErrUnsupportedBucketPolicy):
+		apiErr = gatewayerrors.ErrInvalidPolicyDocument.ToAPIErr()
+	default:
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	apiErr.Description = err.Error()
+	o.EncodeError(w, req, err, apiErr)
+}
+
+// handlePutBucketPolicy replaces the lakeFS policy of the bucket with the translated policy document, attached
+// to the users of the statements principals.
+func handlePutBucketPolicy(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	manager, ok := o.Auth.(bucketPolicyManager)
+	if !ok {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrNotImplemented.ToAPIErr())
+		return
+	}
+	o.Incr("put_bucket_policy", o.Principal, o.Repository.Name, "")
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBucketPolicySize+1))
+	if err != nil {
+		o.EncodeError(w, req, err, gatewayerrors.ErrIncompleteBody.ToAPIErr())
+		return
+	}
+	if len(body) > maxBucketPolicySize {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrPolicyTooLarge.ToAPIErr())
+		return
+	}
+	translation, err := TranslateBucketPolicy(o.Repository.Name, body)
+	if err != nil {
+		encodeBucketPolicyError(w, req, o, err)
+		return
+	}
+
+	// attaching the policy requires permission on each of the users
+	ctx := req.Context()
+	for _, username := range translation.Users {
+		authResp, err := o.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: o.Principal,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.AttachPolicyAction,
+					Resource: permissions.UserArn(username),
+				},
+			},
+		})
+		if err != nil {
+			o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
+		if authResp.Error != nil || !authResp.Allowed {
+			o.EncodeError(w, req, authResp.Error, gatewayerrors.ErrAccessDenied.ToAPIErr())
+			return
+		}
+		if _, err := o.Auth.GetUser(ctx, username); err != nil {
+			if errors.Is(err, auth.ErrNotFound) {
+				encodeBucketPolicyError(w, req, o, fmt.Errorf("%w: principal user '%s' not found", ErrMalformedBucketPolicy, username))
+				return
+			}
+			o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
+	}
+
+	// replace the previous policy in place, so that its users keep their access throughout
+	policyName := translation.Policy.DisplayName
+	attached := make(map[string]struct{})
+	err = manager.WritePolicy(ctx, translation.Policy, true)
+	if errors.Is(err, auth.ErrNotFound) {
+		err = manager.WritePolicy(ctx, translation.Policy, false)
+	} else if err == nil {
+		attached, err = bucketPolicyAttachedUsers(ctx, manager, policyName)
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to write bucket policy")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	// attach the new users before detaching the removed ones, a failure leaves the previous users attached
+	for _, username := range translation.Users {
+		if _, ok := attached[username]; ok {
+			delete(attached, username)
+			continue
+		}
+		if err := manager.AttachPolicyToUser(ctx, policyName, username); err != nil && !errors.Is(err, auth.ErrAlreadyExists) {
+			o.Log(req).WithError(err).WithField("user", username).Error("failed to attach bucket policy")
+			o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
+	}
+	for username := range attached {
+		if err := manager.DetachPolicyFromUser(ctx, policyName, username); err != nil && !errors.Is(err, auth.ErrNotFound) {
+			o.Log(req).WithError(err).WithField("user", username).Error("failed to detach bucket policy")
+			o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteBucketPolicy deletes the lakeFS policy of the bucket and its attachments
+func handleDeleteBucketPolicy(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	manager, ok := o.Auth.(bucketPolicyManager)
+	if !ok {
+		o.EncodeError(w, req, nil, gatewayerrors.ErrNotImplemented.ToAPIErr())
+		return
+	}
+	o.Incr("delete_bucket_policy", o.Principal, o.Repository.Name, "")
+	err := manager.DeletePolicy(req.Context(), BucketPolicyName(o.Repository.Name))
+	if err != nil && !errors.Is(err, auth.ErrNotFound) {
+		o.Log(req).WithError(err).Error("failed to delete bucket policy")
+		o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
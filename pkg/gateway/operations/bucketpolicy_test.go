@@ -0,0 +1,143 @@
+package operations_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestTranslateBucketPolicy(t *testing.T) {
+	tt := []struct {
+		name               string
+		document           string
+		expectedStatements model.Statements
+		expectedUsers      []string
+		expectedErr        error
+	}{
+		{
+			name: "read objects",
+			document: `{"Version": "2012-10-17", "Statement": [{
+				"Effect": "Allow",
+				"Principal": {"AWS": ["arn:aws:iam::123456789012:user/alice", "bob"]},
+				"Action": ["s3:GetObject", "s3:ListBucket"],
+				"Resource": ["arn:aws:s3:::test", "arn:aws:s3:::test/*"]
+			}]}`,
+			expectedStatements: model.Statements{
+				{Effect: model.StatementEffectAllow, Action: []string{permissions.ListObjectsAction}, Resource: permissions.RepoArn(bucketName)},
+				{Effect: model.StatementEffectAllow, Action: []string{permissions.ReadObjectAction}, Resource: permissions.ObjectArn(bucketName, "*")},
+			},
+			expectedUsers: []string{"alice", "bob"},
+		},
+		{
+			name: "all actions",
+			document: `{"Statement": [{
+				"Effect": "Deny",
+				"Principal": {"AWS": "alice"},
+				"Action": "s3:*",
+				"Resource": ["arn:aws:s3:::test", "arn:aws:s3:::test/*"]
+			}]}`,
+			expectedStatements: model.Statements{
+				{Effect: model.StatementEffectDeny, Action: []string{"fs:*"}, Resource: permissions.RepoArn(bucketName)},
+				{
+					Effect:   model.StatementEffectDeny,
+					Action:   []string{permissions.ReadObjectAction, permissions.WriteObjectAction, permissions.DeleteObjectAction},
+					Resource: permissions.ObjectArn(bucketName, "*"),
+				},
+			},
+			expectedUsers: []string{"alice"},
+		},
+		{
+			name: "statement not a list",
+			document: `{"Statement": {
+				"Sid": "NoWrites",
+				"Effect": "Deny",
+				"Principal": {"AWS": "alice"},
+				"Action": "s3:PutObject",
+				"Resource": "arn:aws:s3:::test/*/raw/*"
+			}}`,
+			expectedErr: operations.ErrMalformedBucketPolicy,
+		},
+		{
+			name: "deny writes under prefix of any ref",
+			document: `{"Statement": [{
+				"Sid": "NoWrites",
+				"Effect": "Deny",
+				"Principal": {"AWS": "alice"},
+				"Action": "s3:PutObject",
+				"Resource": "arn:aws:s3:::test/*/raw/*"
+			}]}`,
+			expectedStatements: model.Statements{
+				{Effect: model.StatementEffectDeny, Action: []string{permissions.WriteObjectAction}, Resource: permissions.ObjectArn(bucketName, "raw/*")},
+			},
+			expectedUsers: []string{"alice"},
+		},
+		{
+			name:        "malformed",
+			document:    `{"Statement": [`,
+			expectedErr: operations.ErrMalformedBucketPolicy,
+		},
+		{
+			name: "anonymous principal",
+			document: `{"Statement": [{
+				"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::test/*"
+			}]}`,
+			expectedErr: operations.ErrUnsupportedBucketPolicy,
+		},
+		{
+			name: "condition",
+			document: `{"Statement": [{
+				"Effect": "Allow", "Principal": {"AWS": "alice"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::test/*",
+				"Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}
+			}]}`,
+			expectedErr: operations.ErrUnsupportedBucketPolicy,
+		},
+		{
+			name: "unsupported action",
+			document: `{"Statement": [{
+				"Effect": "Allow", "Principal": {"AWS": "alice"}, "Action": "s3:PutBucketTagging", "Resource": "arn:aws:s3:::test"
+			}]}`,
+			expectedErr: operations.ErrUnsupportedBucketPolicy,
+		},
+		{
+			name: "other bucket",
+			document: `{"Statement": [{
+				"Effect": "Allow", "Principal": {"AWS": "alice"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::other/*"
+			}]}`,
+			expectedErr: operations.ErrUnsupportedBucketPolicy,
+		},
+		{
+			name: "specific ref",
+			document: `{"Statement": [{
+				"Effect": "Allow", "Principal": {"AWS": "alice"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::test/main/*"
+			}]}`,
+			expectedErr: operations.ErrUnsupportedBucketPolicy,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			translation, err := operations.TranslateBucketPolicy(bucketName, []byte(tc.document))
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("TranslateBucketPolicy() err=%v, expected %v", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TranslateBucketPolicy() unexpected err: %v", err)
+			}
+			if translation.Policy.DisplayName != operations.BucketPolicyName(bucketName) {
+				t.Errorf("policy name %s, expected %s", translation.Policy.DisplayName, operations.BucketPolicyName(bucketName))
+			}
+			if diff := deep.Equal(translation.Policy.Statement, tc.expectedStatements); diff != nil {
+				t.Errorf("statements diff: %s", diff)
+			}
+			if diff := deep.Equal(translation.Users, tc.expectedUsers); diff != nil {
+				t.Errorf("users diff: %s", diff)
+			}
+		})
+	}
+}
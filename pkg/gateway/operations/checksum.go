@@ -0,0 +1,235 @@
+package operations
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayErrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+const (
+	// checksumHeaderPrefix prefixes the headers, trailers and entry metadata keys of the additional checksums of
+	// an object, e.g. X-Amz-Checksum-Crc32c
+	checksumHeaderPrefix = "X-Amz-Checksum-"
+	// checksumModeHeader enables returning the checksums of an object from GetObject and HeadObject
+	checksumModeHeader  = "X-Amz-Checksum-Mode"
+	checksumModeEnabled = "ENABLED"
+	// trailerHeader names the trailing header of an aws-chunked body holding its checksum
+	trailerHeader = "X-Amz-Trailer"
+)
+
+var (
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrPartChecksumMismatch = errors.New("part checksum mismatch")
+	ErrChecksumAlgorithm    = errors.New("unsupported checksum algorithm")
+	errChecksumNotPresent   = errors.New("checksum value not received")
+)
+
+// crc64NVMETable is the table of the CRC-64/NVME checksum used by S3
+var crc64NVMETable = crc64.MakeTable(0x9a6c9329ac4bc9b5) //nolint:gomnd
+
+// checksumAlgorithms are the additional checksum algorithms supported on upload, by their S3 names
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"CRC32":     func() hash.Hash { return crc32.NewIEEE() },
+	"CRC32C":    func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) },
+	"CRC64NVME": func() hash.Hash { return crc64.New(crc64NVMETable) },
+	"SHA1":      sha1.New,
+	"SHA256":    sha256.New,
+}
+
+// checksumHeader returns the header holding the checksum of algorithm, e.g. X-Amz-Checksum-Crc32c
+func checksumHeader(algorithm string) string {
+	return http.CanonicalHeaderKey(checksumHeaderPrefix + strings.ToLower(algorithm))
+}
+
+// ChecksumVerifier computes the additional checksum of a request body while it is read, and verifies it against
+// the value sent in a header or a trailer of the request
+type ChecksumVerifier struct {
+	algorithm string
+	header    string
+	hash      hash.Hash
+	body      io.Reader
+}
+
+// NewChecksumVerifier returns a verifier of the additional checksum of the request body, or nil if the request
+// has none
+func NewChecksumVerifier(req *http.Request) (*ChecksumVerifier, error) {
+	var algorithm string
+	if trailer := http.CanonicalHeaderKey(req.Header.Get(trailerHeader)); strings.HasPrefix(trailer, checksumHeaderPrefix) {
+		algorithm = strings.ToUpper(strings.TrimPrefix(trailer, checksumHeaderPrefix))
+	} else {
+		for a := range checksumAlgorithms {
+			if req.Header.Get(checksumHeader(a)) != "" {
+				algorithm = a
+				break
+			}
+		}
+	}
+	if algorithm == "" {
+		return nil, nil
+	}
+	newHash, ok := checksumAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", algorithm, ErrChecksumAlgorithm)
+	}
+	header := checksumHeader(algorithm)
+	v := &ChecksumVerifier{
+		algorithm: algorithm,
+		header:    header,
+		hash:      newHash(),
+	}
+	v.body = io.TeeReader(req.Body, v.hash)
+	return v, nil
+}
+
+// Reader returns the request body, computing its checksum while it is read
+func (v *ChecksumVerifier) Reader() io.Reader {
+	return v.body
+}
+
+// Verify reads the rest of the request body and returns its checksum, or ErrChecksumMismatch if it differs from
+// the value sent with the request. Trailing values are only available once the body is read to its end.
+func (v *ChecksumVerifier) Verify(req *http.Request) (string, error) {
+	if _, err := io.Copy(io.Discard, v.body); err != nil {
+		return "", err
+	}
+	expected := req.Header.Get(v.header)
+	if expected == "" && req.Trailer != nil {
+		expected = req.Trailer.Get(v.header)
+	}
+	if expected == "" {
+		return "", fmt.Errorf("%s: %w", v.header, errChecksumNotPresent)
+	}
+	actual := base64.StdEncoding.EncodeToString(v.hash.Sum(nil))
+	if actual != expected {
+		return "", fmt.Errorf("%s %s, computed %s: %w", v.algorithm, expected, actual, ErrChecksumMismatch)
+	}
+	return actual, nil
+}
+
+// checksumAPIErr returns the S3 error describing a failure to verify the checksum of a request body
+func checksumAPIErr(v *ChecksumVerifier, err error) gatewayErrors.APIError {
+	if !errors.Is(err, ErrChecksumMismatch) {
+		return gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidChecksum)
+	}
+	apiErr := gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrBadDigest)
+	apiErr.Description = fmt.Sprintf("The %s you specified did not match the calculated checksum.", v.algorithm)
+	return apiErr
+}
+
+// entryChecksums returns the additional checksums stored in the metadata of entry, by their headers
+func entryChecksums(entry *catalog.DBEntry) map[string]string {
+	checksums := make(map[string]string)
+	for algorithm := range checksumAlgorithms {
+		header := checksumHeader(algorithm)
+		if value, ok := entry.Metadata[header]; ok {
+			checksums[header] = value
+		}
+	}
+	return checksums
+}
+
+// checksumWriteHeaders sets the additional checksums of entry on http response, if requested by the checksum mode
+func (o *PathOperation) checksumWriteHeaders(w http.ResponseWriter, req *http.Request, entry *catalog.DBEntry) {
+	if !strings.EqualFold(req.Header.Get(checksumModeHeader), checksumModeEnabled) {
+		return
+	}
+	for header, value := range entryChecksums(entry) {
+		o.SetHeader(w, header, value)
+	}
+}
+
+// completedPartChecksum returns the algorithm and value of the additional checksum sent for part on completion of a
+// multipart upload, if any
+func completedPartChecksum(part serde.CompleteMultipartUploadPart) (string, string) {
+	for algorithm, value := range map[string]string{
+		"CRC32":     part.ChecksumCRC32,
+		"CRC32C":    part.ChecksumCRC32C,
+		"CRC64NVME": part.ChecksumCRC64NVME,
+		"SHA1":      part.ChecksumSHA1,
+		"SHA256":    part.ChecksumSHA256,
+	} {
+		if value != "" {
+			return algorithm, value
+		}
+	}
+	return "", ""
+}
+
+// MultipartChecksum verifies the checksums sent on completion of a multipart upload against the checksums verified
+// on upload of its parts. It returns the header and the value of the composite checksum of the object, the checksum
+// of the concatenated checksums of its parts, or empty values if not every part was uploaded with a checksum of the
+// same algorithm.
+func MultipartChecksum(req *http.Request, parts []serde.CompleteMultipartUploadPart, partChecksums map[int]multipart.PartChecksum) (string, string, error) {
+	var (
+		algorithm string
+		sums      []byte
+	)
+	composite := true
+	for _, part := range parts {
+		checksum, ok := partChecksums[part.PartNumber]
+		// a checksum of an earlier upload of the part does not apply
+		ok = ok && checksum.ETag == strings.Trim(part.ETag, `"`)
+		if sentAlgorithm, sentValue := completedPartChecksum(part); sentAlgorithm != "" {
+			if !ok || checksum.Algorithm != sentAlgorithm || checksum.Checksum != sentValue {
+				return "", "", fmt.Errorf("part %d %s %s: %w", part.PartNumber, sentAlgorithm, sentValue, ErrPartChecksumMismatch)
+			}
+		}
+		if !ok || (algorithm != "" && checksum.Algorithm != algorithm) {
+			composite = false
+			continue
+		}
+		algorithm = checksum.Algorithm
+		sum, err := base64.StdEncoding.DecodeString(checksum.Checksum)
+		if err != nil {
+			composite = false
+			continue
+		}
+		sums = append(sums, sum...)
+	}
+	var header, value string
+	if composite && algorithm != "" {
+		h := checksumAlgorithms[algorithm]()
+		_, _ = h.Write(sums)
+		header = checksumHeader(algorithm)
+		value = base64.StdEncoding.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+	}
+
+	// a checksum of the object sent on completion must match the composite checksum
+	for a := range checksumAlgorithms {
+		expected := req.Header.Get(checksumHeader(a))
+		if expected == "" {
+			continue
+		}
+		if checksumHeader(a) != header || (expected != value && expected+"-"+strconv.Itoa(len(parts)) != value) {
+			return "", "", fmt.Errorf("%s %s, computed %s: %w", a, expected, value, ErrChecksumMismatch)
+		}
+	}
+	return header, value, nil
+}
+
+// multipartChecksumAPIErr returns the S3 error describing a failure to verify the checksums of a multipart upload
+func multipartChecksumAPIErr(err error) gatewayErrors.APIError {
+	if errors.Is(err, ErrPartChecksumMismatch) {
+		return gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidPart)
+	}
+	if !errors.Is(err, ErrChecksumMismatch) {
+		return gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError)
+	}
+	apiErr := gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrBadDigest)
+	apiErr.Description = "The checksum you specified did not match the calculated checksum."
+	return apiErr
+}
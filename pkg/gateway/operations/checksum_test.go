@@ -0,0 +1,178 @@
+package operations_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+)
+
+func TestChecksumVerifier(t *testing.T) {
+	tt := []struct {
+		name             string
+		header           http.Header
+		trailer          http.Header
+		expectedChecksum string
+		expectedErr      error
+	}{
+		{
+			name: "no checksum",
+		},
+		{
+			name:             "crc32c header",
+			header:           http.Header{"X-Amz-Checksum-Crc32c": []string{"yZRlqg=="}},
+			expectedChecksum: "yZRlqg==",
+		},
+		{
+			name:             "sha256 header",
+			header:           http.Header{"X-Amz-Checksum-Sha256": []string{"uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="}},
+			expectedChecksum: "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=",
+		},
+		{
+			name:             "crc64nvme header",
+			header:           http.Header{"X-Amz-Checksum-Crc64nvme": []string{"jSnVw/bqjr4="}},
+			expectedChecksum: "jSnVw/bqjr4=",
+		},
+		{
+			name:             "crc32 trailer",
+			header:           http.Header{"X-Amz-Trailer": []string{"x-amz-checksum-crc32"}},
+			trailer:          http.Header{"X-Amz-Checksum-Crc32": []string{"DUoRhQ=="}},
+			expectedChecksum: "DUoRhQ==",
+		},
+		{
+			name:        "mismatch",
+			header:      http.Header{"X-Amz-Checksum-Crc32": []string{"AAAAAA=="}},
+			expectedErr: operations.ErrChecksumMismatch,
+		},
+		{
+			name:        "unsupported algorithm",
+			header:      http.Header{"X-Amz-Trailer": []string{"x-amz-checksum-md4"}},
+			expectedErr: operations.ErrChecksumAlgorithm,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "https://example.test/repo/main/hello", strings.NewReader("hello world"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.header != nil {
+				req.Header = tc.header
+			}
+			req.Trailer = tc.trailer
+			verifier, err := operations.NewChecksumVerifier(req)
+			if err == nil && verifier != nil {
+				// read part of the body, as an adapter might, Verify reads the rest
+				_, err = io.ReadFull(verifier.Reader(), make([]byte, 5))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var checksum string
+				checksum, err = verifier.Verify(req)
+				if checksum != tc.expectedChecksum {
+					t.Errorf("Verify() checksum = %s, expected %s", checksum, tc.expectedChecksum)
+				}
+			}
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("checksum verification error = %v, expected %v", err, tc.expectedErr)
+			}
+			if verifier == nil && tc.expectedErr == nil && tc.expectedChecksum != "" {
+				t.Fatal("expected a checksum verifier")
+			}
+		})
+	}
+}
+
+func TestMultipartChecksum(t *testing.T) {
+	crc32c := func(s string) []byte {
+		return binary.BigEndian.AppendUint32(nil, crc32.Checksum([]byte(s), crc32.MakeTable(crc32.Castagnoli)))
+	}
+	encode := base64.StdEncoding.EncodeToString
+	part1, part2 := encode(crc32c("hello")), encode(crc32c(" world"))
+	composite := encode(crc32c(string(crc32c("hello"))+string(crc32c(" world")))) + "-2"
+	partChecksums := map[int]multipart.PartChecksum{
+		1: {PartNumber: 1, ETag: "etag1", Algorithm: "CRC32C", Checksum: part1},
+		2: {PartNumber: 2, ETag: "etag2", Algorithm: "CRC32C", Checksum: part2},
+	}
+	parts := []serde.CompleteMultipartUploadPart{{PartNumber: 1, ETag: `"etag1"`}, {PartNumber: 2, ETag: `"etag2"`}}
+
+	tt := []struct {
+		name          string
+		parts         []serde.CompleteMultipartUploadPart
+		header        http.Header
+		expectedValue string
+		expectedErr   error
+	}{
+		{
+			name:          "composite",
+			parts:         parts,
+			expectedValue: composite,
+		},
+		{
+			name:          "part checksums sent",
+			parts:         []serde.CompleteMultipartUploadPart{{PartNumber: 1, ETag: "etag1", ChecksumCRC32C: part1}, {PartNumber: 2, ETag: "etag2", ChecksumCRC32C: part2}},
+			expectedValue: composite,
+		},
+		{
+			name:        "part checksum mismatch",
+			parts:       []serde.CompleteMultipartUploadPart{{PartNumber: 1, ETag: "etag1", ChecksumCRC32C: part2}, {PartNumber: 2, ETag: "etag2"}},
+			expectedErr: operations.ErrPartChecksumMismatch,
+		},
+		{
+			name:        "part checksum of another upload of the part",
+			parts:       []serde.CompleteMultipartUploadPart{{PartNumber: 1, ETag: "etag3", ChecksumCRC32C: part1}, {PartNumber: 2, ETag: "etag2"}},
+			expectedErr: operations.ErrPartChecksumMismatch,
+		},
+		{
+			name:  "part without checksum",
+			parts: []serde.CompleteMultipartUploadPart{{PartNumber: 1, ETag: "etag1"}, {PartNumber: 3, ETag: "etag3"}},
+		},
+		{
+			name:          "object checksum",
+			parts:         parts,
+			header:        http.Header{"X-Amz-Checksum-Crc32c": []string{strings.TrimSuffix(composite, "-2")}},
+			expectedValue: composite,
+		},
+		{
+			name:        "object checksum mismatch",
+			parts:       parts,
+			header:      http.Header{"X-Amz-Checksum-Crc32c": []string{part1}},
+			expectedErr: operations.ErrChecksumMismatch,
+		},
+		{
+			name:        "object checksum of another algorithm",
+			parts:       parts,
+			header:      http.Header{"X-Amz-Checksum-Sha256": []string{composite}},
+			expectedErr: operations.ErrChecksumMismatch,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://example.test/repo/main/hello?uploadId=1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.header != nil {
+				req.Header = tc.header
+			}
+			header, value, err := operations.MultipartChecksum(req, tc.parts, partChecksums)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("MultipartChecksum() error = %v, expected %v", err, tc.expectedErr)
+			}
+			if value != tc.expectedValue {
+				t.Errorf("MultipartChecksum() value = %s, expected %s", value, tc.expectedValue)
+			}
+			if value != "" && header != "X-Amz-Checksum-Crc32c" {
+				t.Errorf("MultipartChecksum() header = %s, expected X-Amz-Checksum-Crc32c", header)
+			}
+		})
+	}
+}
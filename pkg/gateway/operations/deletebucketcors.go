@@ -0,0 +1,18 @@
+package operations
+
+import (
+	"net/http"
+
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// DeleteBucketCors handles S3 Delete Bucket CORS operations by removing the CORS rules of the repository.
+type DeleteBucketCors struct{}
+
+func (controller *DeleteBucketCors) RequiredPermissions(_ *http.Request, repoID string) (permissions.Node, error) {
+	return bucketCorsRequiredPermissions(repoID, permissions.UpdateRepositoryAction), nil
+}
+
+func (controller *DeleteBucketCors) Handle(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	handleDeleteBucketCors(w, req, o)
+}
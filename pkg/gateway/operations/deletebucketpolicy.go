@@ -0,0 +1,19 @@
+package operations
+
+import (
+	"net/http"
+
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// DeleteBucketPolicy handles S3 Delete Bucket Policy operations by deleting the lakeFS policy that was created
+// for the bucket by PutBucket.
+type DeleteBucketPolicy struct{}
+
+func (controller *DeleteBucketPolicy) RequiredPermissions(_ *http.Request, repoID string) (permissions.Node, error) {
+	return bucketPolicyRequiredPermissions(repoID, permissions.DeletePolicyAction), nil
+}
+
+func (controller *DeleteBucketPolicy) Handle(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	handleDeleteBucketPolicy(w, req, o)
+}
@@ -41,7 +41,9 @@ func (controller *DeleteObject) HandleAbortMultipartUpload(w http.ResponseWriter
 	}
 
 	req = req.WithContext(logging.AddFields(ctx, logging.Fields{logging.UploadIDFieldKey: uploadID}))
-	err = o.BlockStore.AbortMultiPartUpload(ctx, block.ObjectPointer{
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	err = o.BlockStore.AbortMultiPartUpload(storageCtx, block.ObjectPointer{
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       mpu.PhysicalAddress,
@@ -78,9 +80,15 @@ func (controller *DeleteObject) Handle(w http.ResponseWriter, req *http.Request,
 	case errors.Is(err, graveler.ErrWriteToProtectedBranch):
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrWriteToProtectedBranch))
 		return
+	case errors.Is(err, graveler.ErrBranchLocked):
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrWriteToLockedBranch))
+		return
 	case errors.Is(err, graveler.ErrReadOnlyRepository):
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrReadOnlyRepository))
 		return
+	case errors.Is(err, graveler.ErrObjectLocked):
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrObjectLocked))
+		return
 	case err != nil:
 		lg.WithError(err).Error("could not delete object")
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
@@ -64,6 +64,19 @@ func (controller *DeleteObjects) Handle(w http.ResponseWriter, req *http.Request
 			})
 			continue
 		}
+		// session references read a pinned commit
+		if isSession, err := o.IsSessionRef(req.Context(), o.Repository.Name, resolvedPath.Ref); err != nil || isSession {
+			apiErr := gerrors.Codes.ToAPIErr(gerrors.ErrWriteToSessionRef)
+			if err != nil {
+				apiErr = ResolveRefAPIErr(err)
+			}
+			errs = append(errs, serde.DeleteError{
+				Code:    apiErr.Code,
+				Key:     obj.Key,
+				Message: apiErr.Description,
+			})
+			continue
+		}
 		// authorize this object deletion
 		authResp, err := o.Auth.Authorize(req.Context(), &auth.AuthorizationRequest{
 			Username: o.Principal,
@@ -133,9 +146,9 @@ func (controller *DeleteObjects) batchDelete(ctx context.Context, log logging.Lo
 	batchErr := o.Catalog.DeleteEntries(ctx, o.Repository.Name, ref, pathsToDelete)
 	deleteErrs := graveler.NewMapDeleteErrors(batchErr)
 	for _, key := range keysToDelete {
-		// err will set to the specific error if possible, fallback to the batch delete error
+		// err will set to the specific error if possible, fallback to the batch delete error when no key has one
 		err := deleteErrs[key]
-		if err == nil {
+		if err == nil && len(deleteErrs) == 0 {
 			err = batchErr
 		}
 		updateDeleteResult(&result, quiet, log, key, err)
@@ -164,6 +177,13 @@ func checkForDeleteError(log logging.Logger, key string, err error) *serde.Delet
 			Key:     key,
 			Message: fmt.Sprintf("error deleting object: %s", apiErr.Description),
 		}
+	case errors.Is(err, graveler.ErrBranchLocked):
+		apiErr := gerrors.Codes.ToAPIErr(gerrors.ErrWriteToLockedBranch)
+		return &serde.DeleteError{
+			Code:    apiErr.Code,
+			Key:     key,
+			Message: fmt.Sprintf("error deleting object: %s", apiErr.Description),
+		}
 	case errors.Is(err, graveler.ErrReadOnlyRepository):
 		apiErr := gerrors.Codes.ToAPIErr(gerrors.ErrReadOnlyRepository)
 		return &serde.DeleteError{
@@ -171,6 +191,13 @@ func checkForDeleteError(log logging.Logger, key string, err error) *serde.Delet
 			Key:     key,
 			Message: fmt.Sprintf("error deleting object: %s", apiErr.Description),
 		}
+	case errors.Is(err, graveler.ErrObjectLocked):
+		apiErr := gerrors.Codes.ToAPIErr(gerrors.ErrObjectLocked)
+		return &serde.DeleteError{
+			Code:    apiErr.Code,
+			Key:     key,
+			Message: fmt.Sprintf("error deleting object: %s", apiErr.Description),
+		}
 	case errors.Is(err, catalog.ErrPathRequiredValue):
 		// issue #1706 - https://github.com/treeverse/lakeFS/issues/1706
 		// Spark trying to delete the path "main/", which we map to branch "main" with an empty path.
@@ -1,10 +1,12 @@
 package operations
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/block"
@@ -14,6 +16,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"golang.org/x/exp/maps"
 )
 
 type GetObject struct{}
@@ -28,7 +31,15 @@ func (controller *GetObject) RequiredPermissions(_ *http.Request, repoID, _, pat
 }
 
 func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o *PathOperation) {
-	if o.HandleUnsupported(w, req, "torrent", "acl", "retention", "legal-hold", "lambdaArn") {
+	if o.HandleUnsupported(w, req, "torrent", "acl", "legal-hold", "lambdaArn") {
+		return
+	}
+	if req.URL.Query().Has(objectRetentionQueryParam) {
+		handleGetObjectRetention(w, req, o)
+		return
+	}
+	if req.URL.Query().Has(objectAttributesQueryParam) {
+		handleGetObjectAttributes(w, req, o)
 		return
 	}
 	o.Incr("get_object", o.Principal, o.Repository.Name, o.Reference)
@@ -40,12 +51,12 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	}
 
 	if _, exists := query["tagging"]; exists {
-		o.EncodeResponse(w, req, serde.Tagging{}, http.StatusOK)
+		handleGetObjectTagging(w, req, o)
 		return
 	}
 
 	beforeMeta := time.Now()
-	entry, err := o.Catalog.GetEntry(ctx, o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	entry, commitID, err := o.getEntry(ctx)
 	metaTook := time.Since(beforeMeta)
 	o.Log(req).
 		WithField("took", metaTook).
@@ -93,14 +104,23 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		Identifier:       entry.PhysicalAddress,
 	}
 
+	// the object data is read while the response is written, it is not bounded by the latency budget
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
 	if rangeSpec == "" || err != nil {
 		// assemble a response body (range-less query)
-		data, err = o.BlockStore.Get(ctx, objectPointer, entry.Size)
+		data, err = o.BlockStore.Get(storageCtx, objectPointer, entry.Size)
 	} else {
 		contentLength = rng.Size()
 		contentRange = fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size)
 		statusCode = http.StatusPartialContent
-		data, err = o.BlockStore.GetRange(ctx, objectPointer, rng.StartOffset, rng.EndOffset)
+		data, err = o.BlockStore.GetRange(storageCtx, objectPointer, rng.StartOffset, rng.EndOffset)
+	}
+	if errors.Is(err, block.ErrObjectArchived) {
+		apiErr := gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidObjectState)
+		apiErr.Description = archivedObjectDescription(storageCtx, o, objectPointer)
+		_ = o.EncodeError(w, req, err, apiErr)
+		return
 	}
 	if err != nil {
 		code := gatewayerrors.ErrInternalError
@@ -114,9 +134,14 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader(w, "ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader(w, "Content-Type", entry.ContentType)
+	o.lakeFSWriteHeaders(w, entry, commitID)
+	o.objectLockWriteHeaders(w, entry)
+	o.storageClassWriteHeader(w, entry)
 	o.SetHeader(w, "Accept-Ranges", "bytes")
 	if contentRange != "" {
 		o.SetHeader(w, "Content-Range", contentRange)
+	} else {
+		o.checksumWriteHeaders(w, req, entry)
 	}
 	o.SetHeader(w, "Content-Length", fmt.Sprintf("%d", contentLength))
 	o.SetHeader(w, "X-Content-Type-Options", "nosniff")
@@ -133,3 +158,39 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		o.Log(req).WithError(err).Error("could not write response body for object")
 	}
 }
+
+// archivedObjectDescription describes the restore status of an archived object, to explain why it cannot be read
+func archivedObjectDescription(ctx context.Context, o *PathOperation, obj block.ObjectPointer) string {
+	props, err := o.BlockStore.GetProperties(ctx, obj)
+	if err != nil {
+		return "The object is archived and must be restored before it can be read."
+	}
+	var storageClass string
+	if props.StorageClass != nil {
+		storageClass = " in " + *props.StorageClass
+	}
+	restore := "No restore is in progress."
+	if props.RestoreOngoing {
+		restore = "A restore is in progress."
+	}
+	return fmt.Sprintf("The object is archived%s and must be restored before it can be read. %s", storageClass, restore)
+}
+
+func handleGetObjectTagging(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	entry, _, err := o.getEntry(req.Context())
+	if errors.Is(err, graveler.ErrNotFound) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+		return
+	}
+	if err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	tagging := serde.Tagging{TagSet: serde.TagSet{Tag: make([]serde.Tag, 0, len(entry.Tags))}}
+	keys := maps.Keys(entry.Tags)
+	slices.Sort(keys)
+	for _, key := range keys {
+		tagging.TagSet.Tag = append(tagging.TagSet.Tag, serde.Tag{Key: key, Value: entry.Tags[key]})
+	}
+	o.EncodeResponse(w, req, tagging, http.StatusOK)
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
@@ -25,7 +24,7 @@ func (controller *HeadObject) RequiredPermissions(_ *http.Request, repoID, _, pa
 
 func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	o.Incr("stat_object", o.Principal, o.Repository.Name, o.Reference)
-	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	entry, commitID, err := o.getEntry(req.Context())
 	if errors.Is(err, graveler.ErrNotFound) {
 		// TODO: create distinction between missing repo & missing key
 		o.Log(req).Debug("path not found")
@@ -63,6 +62,13 @@ func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader(w, "ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader(w, "Content-Type", entry.ContentType)
+	o.lakeFSWriteHeaders(w, entry, commitID)
+	o.objectLockWriteHeaders(w, entry)
+	o.storageClassWriteHeader(w, entry)
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	o.restoreWriteHeader(storageCtx, w, entry)
+	o.checksumWriteHeaders(w, req, entry)
 
 	amzMetaWriteHeaders(w, entry.Metadata)
 	if rangeSpec != "" && rngErr == nil {
@@ -0,0 +1,41 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/keyvalidation"
+)
+
+// encodeKeyValidationError encodes the error of a write rejected by the key validation rules of the repository,
+// returns false if err is not one
+func encodeKeyValidationError(w http.ResponseWriter, req *http.Request, o errorEncoder, err error) bool {
+	var validationErr *graveler.KeyValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	apiErr := gatewayerrors.ErrInvalidObjectKey.ToAPIErr()
+	if validationErr.Rule == keyvalidation.RuleMaxKeyLength {
+		apiErr = gatewayerrors.ErrKeyTooLongError.ToAPIErr()
+	}
+	apiErr.Description = err.Error()
+	_ = o.EncodeError(w, req, err, apiErr)
+	return true
+}
+
+// validateKey verifies the key of the operation follows the key validation rules of the repository before any data
+// is written. It encodes the error and returns false when it does not.
+func (o *PathOperation) validateKey(w http.ResponseWriter, req *http.Request) bool {
+	err := o.Catalog.ValidateEntryKey(req.Context(), o.Repository.Name, o.Path)
+	if encodeKeyValidationError(w, req, o, err) {
+		return false
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not validate key")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return false
+	}
+	return true
+}
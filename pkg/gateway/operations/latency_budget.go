@@ -0,0 +1,79 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type latencyBudgetContextKey struct{}
+
+// latencyBudget is the latency budget of the metadata and index calls of a request
+type latencyBudget struct {
+	// parent is the request context before the budget, canceled when the request is
+	parent   context.Context
+	duration time.Duration
+	exceeded *atomic.Bool
+}
+
+// WithLatencyBudget returns a request context whose calls to the metadata store and the index are canceled once
+// budget elapses. Operations transfer object data with a context that is not bounded by the budget, and restart the
+// budget for the metadata calls that follow a transfer.
+func WithLatencyBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, latencyBudgetContextKey{}, latencyBudget{
+		parent:   ctx,
+		duration: budget,
+		exceeded: &atomic.Bool{},
+	})
+	return context.WithTimeout(ctx, budget)
+}
+
+// LatencyBudgetExceeded reports whether the request of ctx failed with SlowDown for exceeding its latency budget
+func LatencyBudgetExceeded(ctx context.Context) bool {
+	budget, ok := ctx.Value(latencyBudgetContextKey{}).(latencyBudget)
+	return ok && budget.exceeded.Load()
+}
+
+// storageContext returns the context of the calls of req transferring object data: it is canceled with the request,
+// but not by the latency budget of the request
+func storageContext(req *http.Request) (context.Context, context.CancelFunc) {
+	ctx := req.Context()
+	budget, ok := ctx.Value(latencyBudgetContextKey{}).(latencyBudget)
+	if !ok {
+		return ctx, func() {}
+	}
+	storageCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(budget.parent, cancel)
+	return storageCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// restartLatencyBudget returns req with a new latency budget, for the metadata calls that follow a transfer of
+// object data
+func restartLatencyBudget(req *http.Request) (*http.Request, context.CancelFunc) {
+	budget, ok := req.Context().Value(latencyBudgetContextKey{}).(latencyBudget)
+	if !ok {
+		return req, func() {}
+	}
+	storageCtx, cancelStorage := storageContext(req)
+	ctx, cancel := context.WithTimeout(storageCtx, budget.duration)
+	return req.WithContext(ctx), func() {
+		cancel()
+		cancelStorage()
+	}
+}
+
+// latencyBudgetExceeded reports whether req exceeded its latency budget, and records it for LatencyBudgetExceeded
+func latencyBudgetExceeded(req *http.Request) bool {
+	ctx := req.Context()
+	budget, ok := ctx.Value(latencyBudgetContextKey{}).(latencyBudget)
+	if !ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	budget.exceeded.Store(true)
+	return true
+}
@@ -0,0 +1,63 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLatencyBudget(t *testing.T) {
+	parent, cancelRequest := context.WithCancel(context.Background())
+	defer cancelRequest()
+	ctx, cancel := WithLatencyBudget(parent, 10*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/repo/main/a", nil).WithContext(ctx)
+	storageCtx, cancelStorage := storageContext(req)
+	defer cancelStorage()
+
+	<-ctx.Done()
+	if !latencyBudgetExceeded(req) {
+		t.Fatal("expected the latency budget to be exceeded")
+	}
+	if !LatencyBudgetExceeded(ctx) {
+		t.Fatal("expected the exceeded latency budget to be recorded")
+	}
+	if err := storageCtx.Err(); err != nil {
+		t.Fatalf("storage context canceled by the latency budget: %s", err)
+	}
+
+	// metadata calls after a transfer of object data get a new budget
+	restarted, cancelRestarted := restartLatencyBudget(req)
+	defer cancelRestarted()
+	if err := restarted.Context().Err(); err != nil {
+		t.Fatalf("restarted latency budget exceeded: %s", err)
+	}
+	if _, ok := restarted.Context().Deadline(); !ok {
+		t.Fatal("expected the restarted latency budget to have a deadline")
+	}
+
+	// the storage context is canceled with the request
+	cancelRequest()
+	select {
+	case <-storageCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("storage context not canceled with the request")
+	}
+}
+
+func TestLatencyBudget_None(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/repo/main/a", nil)
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	if storageCtx != req.Context() {
+		t.Fatal("expected the request context without a latency budget")
+	}
+	if restarted, _ := restartLatencyBudget(req); restarted != req {
+		t.Fatal("expected the request without a latency budget")
+	}
+	if latencyBudgetExceeded(req) {
+		t.Fatal("expected no latency budget to be exceeded")
+	}
+}
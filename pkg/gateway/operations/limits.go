@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	gatewayErrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+)
+
+// ErrEntityTooLarge is returned by readers of request bodies longer than the upload limits
+var ErrEntityTooLarge = errors.New("entity too large")
+
+// UploadLimits bound the size of objects and multipart upload parts written through the gateway, protecting the
+// metadata store and the backing storage from runaway writers. A zero value disables a limit.
+type UploadLimits struct {
+	MaxObjectSize int64
+	MaxPartSize   int64
+	MaxParts      int
+}
+
+// exceedsLimit returns whether size is over limit, unknown (negative) sizes are checked while reading
+func exceedsLimit(size, limit int64) bool {
+	return limit > 0 && size > limit
+}
+
+// limitedReader returns ErrEntityTooLarge once more than limit bytes are read from r
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// limitReader returns a reader of r failing with ErrEntityTooLarge when r is longer than limit. A zero limit does
+// not limit r.
+func limitReader(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, remaining: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrEntityTooLarge
+	}
+	// read one byte past the limit to find bodies longer than it
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ErrEntityTooLarge
+	}
+	return n, err
+}
+
+// validPartNumber returns whether partNumber is within the part numbers allowed by the limits
+func (l UploadLimits) validPartNumber(partNumber int) bool {
+	return partNumber >= 1 && (l.MaxParts <= 0 || partNumber <= l.MaxParts)
+}
+
+// invalidPartNumberAPIErr returns the S3 error of a part number out of the range allowed by the limits
+func (l UploadLimits) invalidPartNumberAPIErr() gatewayErrors.APIError {
+	apiErr := gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidPartNumber)
+	if l.MaxParts > 0 {
+		apiErr.Description = fmt.Sprintf("Part number must be an integer between 1 and %d, inclusive.", l.MaxParts)
+	} else {
+		apiErr.Description = "Part number must be a positive integer."
+	}
+	return apiErr
+}
@@ -0,0 +1,47 @@
+package operations
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLimitReader(t *testing.T) {
+	tt := []struct {
+		name        string
+		size        int
+		limit       int64
+		expectedErr error
+	}{
+		{name: "no limit", size: 100},
+		{name: "under limit", size: 99, limit: 100},
+		{name: "at limit", size: 100, limit: 100},
+		{name: "over limit", size: 101, limit: 100, expectedErr: ErrEntityTooLarge},
+		{name: "far over limit", size: 100000, limit: 100, expectedErr: ErrEntityTooLarge},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("a"), tc.size)
+			read, err := io.ReadAll(limitReader(bytes.NewReader(data), tc.limit))
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("ReadAll() error = %v, expected %v", err, tc.expectedErr)
+			}
+			if tc.expectedErr == nil && !bytes.Equal(read, data) {
+				t.Fatalf("ReadAll() read %d bytes, expected %d", len(read), len(data))
+			}
+		})
+	}
+}
+
+func TestUploadLimits_ValidPartNumber(t *testing.T) {
+	limits := UploadLimits{MaxParts: 3}
+	for partNumber, expected := range map[int]bool{0: false, 1: true, 3: true, 4: false} {
+		if valid := limits.validPartNumber(partNumber); valid != expected {
+			t.Errorf("validPartNumber(%d) = %t, expected %t", partNumber, valid, expected)
+		}
+	}
+	if !(UploadLimits{}).validPartNumber(20000) {
+		t.Error("validPartNumber(20000) without a parts limit = false, expected true")
+	}
+}
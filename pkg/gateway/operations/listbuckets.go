@@ -2,19 +2,29 @@ package operations
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/gateway/serde"
 	"github.com/treeverse/lakefs/pkg/permissions"
 )
 
+const (
+	// ListBucketsMaxBuckets is the maximal value of max-buckets, as in S3
+	ListBucketsMaxBuckets = 10000
+
+	listBucketsPageSize = 1000
+)
+
 type ListBuckets struct{}
 
+// RequiredPermissions requires the permission to list repositories, the listing includes only the repositories the
+// user can read
 func (controller *ListBuckets) RequiredPermissions(_ *http.Request) (permissions.Node, error) {
 	return permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ListRepositoriesAction,
-			Resource: "*",
+			Resource: permissions.All,
 		},
 	}, nil
 }
@@ -27,31 +37,73 @@ func (controller *ListBuckets) Handle(w http.ResponseWriter, req *http.Request,
 
 	o.Incr("list_repos", o.Principal, "", "")
 
+	query := req.URL.Query()
+	prefix := query.Get("prefix")
+	after := query.Get("continuation-token")
+	maxBuckets := -1
+	if v := query.Get("max-buckets"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > ListBucketsMaxBuckets {
+			_ = o.EncodeError(w, req, err, errors.Codes.ToAPIErr(errors.ErrInvalidMaxBuckets))
+			return
+		}
+		maxBuckets = n
+	}
+
+	ctx := req.Context()
+	listPrefix, visible, err := o.organizationListPrefix(ctx, prefix)
+	if err != nil {
+		_ = o.EncodeError(w, req, err, errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
+	// users allowed to read all repositories skip the check of each repository
+	readAll, err := o.isAllowed(ctx, permissions.ReadRepositoryAction, permissions.RepoArn("*"))
+	if err != nil {
+		_ = o.EncodeError(w, req, err, errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
+
 	buckets := make([]serde.Bucket, 0)
-	var after string
-	for {
-		// list repositories
-		repos, hasMore, err := o.Catalog.ListRepositories(req.Context(), -1, "", after)
+	var continuationToken string
+	for visible {
+		repos, hasMore, err := o.Catalog.ListRepositories(ctx, listBucketsPageSize, listPrefix, after)
 		if err != nil {
 			_ = o.EncodeError(w, req, err, errors.Codes.ToAPIErr(errors.ErrInternalError))
 			return
 		}
 
-		// collect repositories
-		for _, repo := range repos {
+		for i, repo := range repos {
+			if !readAll {
+				allowed, err := o.isAllowed(ctx, permissions.ReadRepositoryAction, permissions.RepoArn(repo.Name))
+				if err != nil {
+					_ = o.EncodeError(w, req, err, errors.Codes.ToAPIErr(errors.ErrInternalError))
+					return
+				}
+				if !allowed {
+					continue
+				}
+			}
 			buckets = append(buckets, serde.Bucket{
 				CreationDate: serde.Timestamp(repo.CreationDate),
 				Name:         repo.Name,
 			})
+			if len(buckets) == maxBuckets {
+				if hasMore || i < len(repos)-1 {
+					continuationToken = repo.Name
+				}
+				break
+			}
 		}
 
-		if !hasMore || len(repos) == 0 {
+		if !hasMore || len(repos) == 0 || len(buckets) == maxBuckets {
 			break
 		}
 		after = repos[len(repos)-1].Name
 	}
 	// write response
 	o.EncodeResponse(w, req, serde.ListAllMyBucketsResult{
-		Buckets: serde.Buckets{Bucket: buckets},
+		Buckets:           serde.Buckets{Bucket: buckets},
+		ContinuationToken: continuationToken,
+		Prefix:            prefix,
 	}, http.StatusOK)
 }
@@ -0,0 +1,162 @@
+package operations_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+// policyAuth allows the permissions of its statements, matching resources like lakeFS policies do.  Its user is
+// not a member of any organization.
+type policyAuth struct {
+	auth.OrganizationManager
+	statements model.Statements
+}
+
+func (a *policyAuth) GetUserOrganization(context.Context, string) (*model.Organization, error) {
+	return nil, auth.ErrNotFound
+}
+
+func (a *policyAuth) GetCredentials(context.Context, string) (*model.Credential, error) {
+	return nil, auth.ErrNotFound
+}
+
+func (a *policyAuth) GetUser(context.Context, string) (*model.User, error) {
+	return &model.User{Username: "user"}, nil
+}
+
+func (a *policyAuth) Authorize(_ context.Context, req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	perm := req.RequiredPermissions.Permission
+	for _, s := range a.statements {
+		if s.Action[0] == perm.Action && auth.ArnMatch(s.Resource, perm.Resource) {
+			return &auth.AuthorizationResponse{Allowed: true}, nil
+		}
+	}
+	return &auth.AuthorizationResponse{Allowed: false}, nil
+}
+
+func TestListBuckets(t *testing.T) {
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	cfg, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       cfg,
+		KVStore:      kvtest.GetStore(ctx, t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	for _, repo := range []string{"alpha", "beta", "gamma", "delta"} {
+		_, err := c.CreateRepository(ctx, repo, "mem://"+repo, "main", false)
+		testutil.MustDo(t, "create repository "+repo, err)
+	}
+
+	listRepos := model.Statement{Action: []string{permissions.ListRepositoriesAction}, Resource: permissions.All}
+	readRepo := func(repo string) model.Statement {
+		return model.Statement{Action: []string{permissions.ReadRepositoryAction}, Resource: permissions.RepoArn(repo)}
+	}
+	tests := []struct {
+		name              string
+		statements        model.Statements
+		query             string
+		expectedBuckets   []string
+		continuationToken string
+	}{
+		{
+			name:            "read_all",
+			statements:      model.Statements{listRepos, readRepo("*")},
+			expectedBuckets: []string{"alpha", "beta", "delta", "gamma"},
+		},
+		{
+			name:            "read_some",
+			statements:      model.Statements{listRepos, readRepo("alpha"), readRepo("gamma")},
+			expectedBuckets: []string{"alpha", "gamma"},
+		},
+		{
+			name:            "read_none",
+			statements:      model.Statements{listRepos},
+			expectedBuckets: []string{},
+		},
+		{
+			name:            "prefix",
+			statements:      model.Statements{listRepos, readRepo("*")},
+			query:           "prefix=de",
+			expectedBuckets: []string{"delta"},
+		},
+		{
+			name:              "max_buckets",
+			statements:        model.Statements{listRepos, readRepo("beta"), readRepo("delta"), readRepo("gamma")},
+			query:             "max-buckets=2",
+			expectedBuckets:   []string{"beta", "delta"},
+			continuationToken: "delta",
+		},
+		{
+			name:            "continuation_token",
+			statements:      model.Statements{listRepos, readRepo("beta"), readRepo("delta"), readRepo("gamma")},
+			query:           "max-buckets=2&continuation-token=delta",
+			expectedBuckets: []string{"gamma"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &operations.AuthorizedOperation{
+				Operation: &operations.Operation{
+					Catalog: c,
+					Auth:    &policyAuth{statements: tt.statements},
+					Incr:    func(_, _, _, _ string) {},
+				},
+				Principal: "user",
+			}
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			(&operations.ListBuckets{}).Handle(rr, req, o)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("ListBuckets status %d, expected %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+			}
+			var result serde.ListAllMyBucketsResult
+			if err := xml.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unmarshal ListBuckets result: %s", err)
+			}
+			buckets := make([]string, 0, len(result.Buckets.Bucket))
+			for _, b := range result.Buckets.Bucket {
+				buckets = append(buckets, b.Name)
+			}
+			if diff := deep.Equal(buckets, tt.expectedBuckets); diff != nil {
+				t.Errorf("buckets diff: %s", diff)
+			}
+			if result.ContinuationToken != tt.continuationToken {
+				t.Errorf("continuation token %q, expected %q", result.ContinuationToken, tt.continuationToken)
+			}
+		})
+	}
+}
+
+func TestListBuckets_RequiredPermissions(t *testing.T) {
+	perms, err := (&operations.ListBuckets{}).RequiredPermissions(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("RequiredPermissions: %s", err)
+	}
+	expected := permissions.Node{
+		Permission: permissions.Permission{Action: permissions.ListRepositoriesAction, Resource: permissions.All},
+	}
+	if diff := deep.Equal(perms, expected); diff != nil {
+		t.Errorf("required permissions diff: %s", diff)
+	}
+}
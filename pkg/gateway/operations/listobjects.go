@@ -19,6 +19,9 @@ import (
 const (
 	ListObjectMaxKeys = 1000
 
+	// PrefixStatsHeader requests the object count and size of the common prefixes of a listing
+	PrefixStatsHeader = "X-LakeFS-Prefix-Stats"
+
 	// defaultBucketLocation used to identify if we need to specify the location constraint
 	defaultBucketLocation = "us-east-1"
 )
@@ -28,6 +31,15 @@ type ListObjects struct{}
 func (controller *ListObjects) RequiredPermissions(req *http.Request, repoID string) (permissions.Node, error) {
 	// check if we're listing files in a branch, or listing branches
 	params := req.URL.Query()
+	if params.Has(bucketCorsQueryParam) {
+		return bucketCorsRequiredPermissions(repoID, permissions.ReadRepositoryAction), nil
+	}
+	if params.Has(bucketObjectLockQueryParam) {
+		return bucketObjectLockRequiredPermissions(repoID, permissions.GetObjectLockConfigurationAction), nil
+	}
+	if params.Has(bucketNotificationQueryParam) {
+		return bucketNotificationRequiredPermissions(repoID, permissions.ReadRepositoryAction), nil
+	}
 	delimiter := params.Get("delimiter")
 	prefix := params.Get("prefix")
 	if delimiter == "/" && !strings.Contains(prefix, "/") {
@@ -75,13 +87,50 @@ func (controller *ListObjects) serializeEntries(ref string, entries []*catalog.D
 				LastModified: serde.Timestamp(entry.CreationDate),
 				ETag:         httputil.ETag(entry.Checksum),
 				Size:         entry.Size,
-				StorageClass: "STANDARD",
+				StorageClass: catalog.StorageClassOrDefault(entry.StorageClass),
 			})
 		}
 	}
 	return dirs, files, lastKey
 }
 
+func wantPrefixStats(req *http.Request) bool {
+	want, _ := strconv.ParseBool(req.Header.Get(PrefixStatsHeader))
+	return want
+}
+
+// setPrefixStats sets the aggregates of the objects under prefix on ref to dir. The aggregates are omitted when
+// they cannot be computed, as the listing itself is valid.
+func setPrefixStats(req *http.Request, o *RepoOperation, ref, prefix string, dir *serde.CommonPrefixes) {
+	stats, err := o.Catalog.GetDirectoryStats(req.Context(), o.Repository.Name, ref, prefix)
+	if errors.Is(err, catalog.ErrDirectoryStatsTooLarge) {
+		o.Log(req).WithError(err).WithFields(logging.Fields{"ref": ref, "prefix": prefix}).Debug("prefix stats omitted")
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).WithFields(logging.Fields{"ref": ref, "prefix": prefix}).Warn("could not get prefix stats")
+		return
+	}
+	dir.ObjectCount = &stats.ObjectCount
+	dir.Size = &stats.SizeBytes
+}
+
+func addEntriesPrefixStats(req *http.Request, o *RepoOperation, ref string, entries []*catalog.DBEntry, dirs []serde.CommonPrefixes) {
+	i := 0
+	for _, entry := range entries {
+		if entry.CommonLevel {
+			setPrefixStats(req, o, ref, entry.Path, &dirs[i])
+			i++
+		}
+	}
+}
+
+func addBranchesPrefixStats(req *http.Request, o *RepoOperation, branches []*catalog.Branch, dirs []serde.CommonPrefixes) {
+	for i, branch := range branches {
+		setPrefixStats(req, o, branch.Name, "", &dirs[i])
+	}
+}
+
 func (controller *ListObjects) serializeBranches(branches []*catalog.Branch) ([]serde.CommonPrefixes, string) {
 	dirs := make([]serde.CommonPrefixes, 0)
 	var lastKey string
@@ -115,7 +164,8 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 
 	var results []*catalog.DBEntry
 	var hasMore bool
-	var ref string
+	// listRef is the commit a session reference is pinned to, keys are listed under ref
+	var ref, listRef string
 	// should we list branches?
 	prefix, err := path.ResolvePath(params.Get("prefix"))
 	if err != nil {
@@ -140,6 +190,9 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 		}
 		// return branch response
 		dirs, lastKey := controller.serializeBranches(branches)
+		if wantPrefixStats(req) {
+			addBranchesPrefixStats(req, o, branches, dirs)
+		}
 		resp := serde.ListObjectsV2Output{
 			Name:           o.Repository.Name,
 			Prefix:         params.Get("prefix"),
@@ -164,6 +217,12 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 	} else {
 		// list objects then.
 		ref = prefix.Ref
+		listRef, err = o.ResolveRef(req.Context(), o.Repository.Name, ref)
+		if err != nil {
+			o.Log(req).WithError(err).WithField("ref", ref).Error("could not resolve ref")
+			_ = o.EncodeError(w, req, err, ResolveRefAPIErr(err))
+			return
+		}
 		if len(fromStr) > 0 {
 			from, err = path.ResolvePath(fromStr)
 			if err != nil || !strings.EqualFold(from.Ref, prefix.Ref) {
@@ -180,7 +239,7 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 		results, hasMore, err = o.Catalog.ListEntries(
 			req.Context(),
 			o.Repository.Name,
-			prefix.Ref,
+			listRef,
 			prefix.Path,
 			from.Path,
 			delimiter,
@@ -200,6 +259,9 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 	}
 
 	dirs, files, lastKey := controller.serializeEntries(ref, results)
+	if wantPrefixStats(req) {
+		addEntriesPrefixStats(req, o, listRef, results, dirs)
+	}
 	resp := serde.ListObjectsV2Output{
 		Name:           o.Repository.Name,
 		Prefix:         params.Get("prefix"),
@@ -239,7 +301,7 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 	var results []*catalog.DBEntry
 	hasMore := false
 
-	var ref string
+	var ref, listRef string
 	// should we list branches?
 	prefix, err := path.ResolvePath(params.Get("prefix"))
 	if err != nil {
@@ -262,6 +324,9 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 		}
 		// return branch response
 		dirs, lastKey := controller.serializeBranches(branches)
+		if wantPrefixStats(req) {
+			addBranchesPrefixStats(req, o, branches, dirs)
+		}
 		resp := serde.ListBucketResult{
 			Name:           o.Repository.Name,
 			Prefix:         params.Get("prefix"),
@@ -291,6 +356,12 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 			return
 		}
 		ref = prefix.Ref
+		listRef, err = o.ResolveRef(req.Context(), o.Repository.Name, ref)
+		if err != nil {
+			o.Log(req).WithError(err).WithField("ref", ref).Error("could not resolve ref")
+			_ = o.EncodeError(w, req, err, ResolveRefAPIErr(err))
+			return
+		}
 		// see if we have a continuation token in the request to pick up from
 		var marker path.ResolvedPath
 		// strip the branch from the marker
@@ -309,7 +380,7 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 		results, hasMore, err = o.Catalog.ListEntries(
 			req.Context(),
 			o.Repository.Name,
-			prefix.Ref,
+			listRef,
 			prefix.Path,
 			marker.Path,
 			delimiter,
@@ -329,6 +400,9 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 
 	// build a response
 	dirs, files, lastKey := controller.serializeEntries(ref, results)
+	if wantPrefixStats(req) {
+		addEntriesPrefixStats(req, o, listRef, results, dirs)
+	}
 	resp := serde.ListBucketResult{
 		Name:           o.Repository.Name,
 		Prefix:         params.Get("prefix"),
@@ -353,13 +427,28 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 
 func (controller *ListObjects) Handle(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
 	if o.HandleUnsupported(w, req, "inventory", "metrics", "publicAccessBlock", "ownershipControls",
-		"intelligent-tiering", "analytics", "policy", "lifecycle", "encryption", "object-lock", "replication",
-		"notification", "events", "acl", "cors", "website", "accelerate",
+		"intelligent-tiering", "analytics", "policy", "lifecycle", "encryption", "replication",
+		"events", "acl", "website", "accelerate",
 		"requestPayment", "logging", "tagging", "uploads", "versions", "policyStatus") {
 		return
 	}
 	query := req.URL.Query()
 
+	if query.Has(bucketCorsQueryParam) {
+		handleGetBucketCors(w, req, o)
+		return
+	}
+
+	if query.Has(bucketObjectLockQueryParam) {
+		handleGetBucketObjectLock(w, req, o)
+		return
+	}
+
+	if query.Has(bucketNotificationQueryParam) {
+		handleGetBucketNotification(w, req, o)
+		return
+	}
+
 	// getbucketlocation support
 	if query.Has("location") {
 		o.Incr("get_bucket_location", o.Principal, o.Repository.Name, "")
@@ -99,6 +99,10 @@ func (a *mockAdapter) UploadCopyPartRange(_ context.Context, _, _ block.ObjectPo
 	panic("try to upload copy part range in mock adapter")
 }
 
+func (a *mockAdapter) RestoreObject(_ context.Context, _ block.ObjectPointer, _ block.RestoreOpts) error {
+	panic("try to restore object in mock adapter")
+}
+
 func (a *mockAdapter) BlockstoreType() string {
 	return "s3"
 }
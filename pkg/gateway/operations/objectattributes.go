@@ -0,0 +1,139 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+const (
+	objectAttributesQueryParam = "attributes"
+	objectAttributesHeader     = "X-Amz-Object-Attributes"
+	maxPartsHeader             = "X-Amz-Max-Parts"
+	partNumberMarkerHeader     = "X-Amz-Part-Number-Marker"
+
+	defaultMaxParts = 1000
+
+	// checksumTypeFullObject is the type of checksums computed over the whole object content
+	checksumTypeFullObject = "FULL_OBJECT"
+	// checksumTypeComposite is the type of checksums of multipart uploads, computed over the checksums of their parts
+	checksumTypeComposite = "COMPOSITE"
+)
+
+// objectAttributes are the attributes that may be requested by GetObjectAttributes
+var objectAttributes = []string{"ETag", "Checksum", "ObjectParts", "StorageClass", "ObjectSize"}
+
+// handleGetObjectAttributes returns the attributes of the object requested by the object attributes header
+func handleGetObjectAttributes(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("get_object_attributes", o.Principal, o.Repository.Name, o.Reference)
+	var requested []string
+	for _, attribute := range strings.Split(strings.Join(req.Header.Values(objectAttributesHeader), ","), ",") {
+		attribute = strings.TrimSpace(attribute)
+		if !slices.Contains(objectAttributes, attribute) {
+			_ = o.EncodeError(w, req, nil, gatewayerrors.ErrInvalidObjectAttributes.ToAPIErr())
+			return
+		}
+		requested = append(requested, attribute)
+	}
+	maxParts := defaultMaxParts
+	if v := req.Header.Get(maxPartsHeader); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			_ = o.EncodeError(w, req, err, gatewayerrors.ErrInvalidMaxParts.ToAPIErr())
+			return
+		}
+		maxParts = n
+	}
+	var partNumberMarker int
+	if v := req.Header.Get(partNumberMarkerHeader); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			_ = o.EncodeError(w, req, err, gatewayerrors.ErrInvalidPartNumberMarker.ToAPIErr())
+			return
+		}
+		partNumberMarker = n
+	}
+
+	entry, commitID, err := o.getEntry(req.Context())
+	if errors.Is(err, graveler.ErrNotFound) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrNoSuchKey.ToAPIErr())
+		return
+	}
+	if errors.Is(err, catalog.ErrExpired) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrNoSuchVersion.ToAPIErr())
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to get object attributes")
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+
+	var attributes serde.GetObjectAttributesResponse
+	for _, attribute := range requested {
+		switch attribute {
+		case "ETag":
+			attributes.ETag = entry.Checksum
+		case "Checksum":
+			attributes.Checksum = objectChecksum(entry)
+		case "ObjectParts":
+			attributes.ObjectParts = objectParts(entry, maxParts, partNumberMarker)
+		case "StorageClass":
+			attributes.StorageClass = catalog.StorageClassOrDefault(entry.StorageClass)
+		case "ObjectSize":
+			size := entry.Size
+			attributes.ObjectSize = &size
+		}
+	}
+	o.SetHeader(w, "Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
+	o.lakeFSWriteHeaders(w, entry, commitID)
+	o.EncodeResponse(w, req, attributes, http.StatusOK)
+}
+
+// objectParts returns the parts of entry, or nil if it was not written by a multipart upload. The number of parts
+// is known from the multipart ETag of the object, the parts themselves are not recorded and never listed, as S3 does
+// for objects uploaded without part checksums.
+func objectParts(entry *catalog.DBEntry, maxParts, partNumberMarker int) *serde.ObjectParts {
+	count, ok := block.MultipartETagPartsCount(entry.Checksum)
+	if !ok {
+		return nil
+	}
+	return &serde.ObjectParts{
+		PartsCount:           count,
+		PartNumberMarker:     partNumberMarker,
+		NextPartNumberMarker: partNumberMarker,
+		MaxParts:             maxParts,
+	}
+}
+
+// objectChecksum returns the additional checksums of entry, or nil if it has none
+func objectChecksum(entry *catalog.DBEntry) *serde.ObjectChecksum {
+	checksums := entryChecksums(entry)
+	if len(checksums) == 0 {
+		return nil
+	}
+	checksumType := checksumTypeFullObject
+	for _, value := range checksums {
+		// composite checksums end with the number of parts
+		if strings.Contains(value, "-") {
+			checksumType = checksumTypeComposite
+		}
+	}
+	return &serde.ObjectChecksum{
+		ChecksumCRC32:     checksums[checksumHeader("CRC32")],
+		ChecksumCRC32C:    checksums[checksumHeader("CRC32C")],
+		ChecksumCRC64NVME: checksums[checksumHeader("CRC64NVME")],
+		ChecksumSHA1:      checksums[checksumHeader("SHA1")],
+		ChecksumSHA256:    checksums[checksumHeader("SHA256")],
+		ChecksumType:      checksumType,
+	}
+}
@@ -0,0 +1,241 @@
+package operations
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/serde"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	bucketObjectLockQueryParam = "object-lock"
+	objectRetentionQueryParam  = "retention"
+
+	objectLockModeHeader            = "X-Amz-Object-Lock-Mode"
+	objectLockRetainUntilDateHeader = "X-Amz-Object-Lock-Retain-Until-Date"
+	bypassGovernanceRetentionHeader = "X-Amz-Bypass-Governance-Retention"
+
+	objectLockEnabled = "Enabled"
+	// maxObjectLockDocumentSize is the maximal size of an object lock configuration or retention document
+	maxObjectLockDocumentSize = 64 * 1024
+)
+
+var ErrMalformedRetention = errors.New("malformed retention")
+
+// errorEncoder encodes S3 errors of an operation
+type errorEncoder interface {
+	EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gatewayerrors.APIError) *http.Request
+}
+
+func bucketObjectLockRequiredPermissions(repoID, action string) permissions.Node {
+	return permissions.Node{
+		Permission: permissions.Permission{
+			Action:   action,
+			Resource: permissions.RepoArn(repoID),
+		},
+	}
+}
+
+// objectRetentionRequiredPermissions returns the permissions to set the retention of an object, which include
+// bypassing governance retention when the request asks for it
+func objectRetentionRequiredPermissions(req *http.Request, repoID, path string) permissions.Node {
+	node := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetObjectRetentionAction,
+			Resource: permissions.ObjectArn(repoID, path),
+		},
+	}
+	if !bypassGovernanceRetention(req) {
+		return node
+	}
+	return permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			node,
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.BypassGovernanceRetentionAction,
+					Resource: permissions.ObjectArn(repoID, path),
+				},
+			},
+		},
+	}
+}
+
+func bypassGovernanceRetention(req *http.Request) bool {
+	bypass, _ := strconv.ParseBool(req.Header.Get(bypassGovernanceRetentionHeader))
+	return bypass
+}
+
+// parseRetention returns the retention of mode until retainUntilDate, nil if both are empty
+func parseRetention(mode, retainUntilDate string) (*catalog.ObjectRetention, error) {
+	if mode == "" && retainUntilDate == "" {
+		return nil, nil
+	}
+	if mode == "" || retainUntilDate == "" {
+		return nil, fmt.Errorf("%w: both mode and retain until date are required", ErrMalformedRetention)
+	}
+	until, err := time.Parse(time.RFC3339, retainUntilDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: retain until date: %s", ErrMalformedRetention, err)
+	}
+	return &catalog.ObjectRetention{Mode: mode, RetainUntilDate: until}, nil
+}
+
+// retentionFromHeaders returns the retention requested by the object lock headers of an upload, nil if none
+func retentionFromHeaders(req *http.Request) (*catalog.ObjectRetention, error) {
+	return parseRetention(req.Header.Get(objectLockModeHeader), req.Header.Get(objectLockRetainUntilDateHeader))
+}
+
+// objectLockWriteHeaders sets the object lock headers of entry on http response
+func (o *PathOperation) objectLockWriteHeaders(w http.ResponseWriter, entry *catalog.DBEntry) {
+	if entry.Retention == nil {
+		return
+	}
+	o.SetHeader(w, objectLockModeHeader, entry.Retention.Mode)
+	o.SetHeader(w, objectLockRetainUntilDateHeader, serde.Timestamp(entry.Retention.RetainUntilDate))
+}
+
+// encodeObjectLockError encodes the error of a write rejected by object lock, returns false if err is not one
+func encodeObjectLockError(w http.ResponseWriter, req *http.Request, o errorEncoder, err error) bool {
+	switch {
+	case errors.Is(err, graveler.ErrObjectLocked):
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrObjectLocked.ToAPIErr())
+	case errors.Is(err, graveler.ErrObjectLockNotEnabled):
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrObjectLockNotEnabled.ToAPIErr())
+	case errors.Is(err, graveler.ErrInvalidValue), errors.Is(err, ErrMalformedRetention):
+		apiErr := gatewayerrors.ErrInvalidObjectLock.ToAPIErr()
+		apiErr.Description = err.Error()
+		_ = o.EncodeError(w, req, err, apiErr)
+	default:
+		return false
+	}
+	return true
+}
+
+func readObjectLockDocument(w http.ResponseWriter, req *http.Request, o errorEncoder, v interface{}) bool {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxObjectLockDocumentSize+1))
+	if err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrIncompleteBody.ToAPIErr())
+		return false
+	}
+	if len(body) > maxObjectLockDocumentSize {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.ErrEntityTooLarge.ToAPIErr())
+		return false
+	}
+	if err := xml.Unmarshal(body, v); err != nil {
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrMalformedXML.ToAPIErr())
+		return false
+	}
+	return true
+}
+
+// handlePutBucketObjectLock replaces the object lock configuration of the repository
+func handlePutBucketObjectLock(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("put_bucket_object_lock", o.Principal, o.Repository.Name, "")
+	var doc serde.ObjectLockConfiguration
+	if !readObjectLockDocument(w, req, o, &doc) {
+		return
+	}
+	if doc.ObjectLockEnabled != objectLockEnabled {
+		apiErr := gatewayerrors.ErrMalformedXML.ToAPIErr()
+		apiErr.Description = "ObjectLockEnabled must be Enabled"
+		_ = o.EncodeError(w, req, nil, apiErr)
+		return
+	}
+	config := &graveler.ObjectLockConfiguration{Enabled: true}
+	if doc.Rule != nil {
+		config.DefaultMode = doc.Rule.DefaultRetention.Mode
+		config.DefaultRetentionDays = doc.Rule.DefaultRetention.Days
+		config.DefaultRetentionYears = doc.Rule.DefaultRetention.Years
+	}
+	err := o.Catalog.SetObjectLockConfiguration(req.Context(), o.Repository.Name, config)
+	if encodeObjectLockError(w, req, o, err) {
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to set object lock configuration")
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func handleGetBucketObjectLock(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
+	o.Incr("get_bucket_object_lock", o.Principal, o.Repository.Name, "")
+	config, err := o.Catalog.GetObjectLockConfiguration(req.Context(), o.Repository.Name)
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to get object lock configuration")
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	if !config.Enabled {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.ErrNoSuchObjectLockConfiguration.ToAPIErr())
+		return
+	}
+	doc := serde.ObjectLockConfiguration{ObjectLockEnabled: objectLockEnabled}
+	if config.DefaultMode != "" {
+		doc.Rule = &serde.ObjectLockRule{
+			DefaultRetention: serde.DefaultRetention{
+				Mode:  config.DefaultMode,
+				Days:  config.DefaultRetentionDays,
+				Years: config.DefaultRetentionYears,
+			},
+		}
+	}
+	o.EncodeResponse(w, req, doc, http.StatusOK)
+}
+
+// handlePutObjectRetention replaces the retention of the object, an empty retention removes it
+func handlePutObjectRetention(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("put_object_retention", o.Principal, o.Repository.Name, o.Reference)
+	var doc serde.Retention
+	if !readObjectLockDocument(w, req, o, &doc) {
+		return
+	}
+	retention, err := parseRetention(doc.Mode, doc.RetainUntilDate)
+	if err == nil {
+		err = o.Catalog.SetObjectRetention(req.Context(), o.Repository.Name, o.Reference, o.Path, retention, bypassGovernanceRetention(req))
+	}
+	switch {
+	case encodeObjectLockError(w, req, o, err):
+	case errors.Is(err, graveler.ErrNotFound):
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrNoSuchKey.ToAPIErr())
+	case err != nil:
+		o.Log(req).WithError(err).Error("failed to set object retention")
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func handleGetObjectRetention(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("get_object_retention", o.Principal, o.Repository.Name, o.Reference)
+	entry, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrNoSuchKey.ToAPIErr())
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to get object retention")
+		_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
+		return
+	}
+	if entry.Retention == nil {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.ErrNoSuchObjectRetention.ToAPIErr())
+		return
+	}
+	o.EncodeResponse(w, req, serde.Retention{
+		Mode:            entry.Retention.Mode,
+		RetainUntilDate: serde.Timestamp(entry.Retention.RetainUntilDate),
+	}, http.StatusOK)
+}
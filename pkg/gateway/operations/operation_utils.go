@@ -1,15 +1,33 @@
 package operations
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/logging"
 )
 
-const amzMetaHeaderPrefix = "X-Amz-Meta-"
+const (
+	amzMetaHeaderPrefix = "X-Amz-Meta-"
+
+	// lakeFSCommitIDHeader is the commit an object was read from. It is omitted for uncommitted objects.
+	lakeFSCommitIDHeader = "X-LakeFS-Commit-Id"
+	// lakeFSChecksumHeader is a hash of the physical address of an object, identifying its stored version
+	lakeFSChecksumHeader = "X-LakeFS-Checksum"
+
+	// restoreHeader is the restore status of an archived object
+	restoreHeader = "X-Amz-Restore"
+)
 
 // amzMetaAsMetadata prepare metadata based on amazon user metadata request headers
 func amzMetaAsMetadata(req *http.Request) catalog.Metadata {
@@ -32,7 +50,59 @@ func amzMetaWriteHeaders(w http.ResponseWriter, metadata catalog.Metadata) {
 	}
 }
 
-func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddress string, size int64, relative bool, metadata map[string]string, contentType string) error {
+// lakeFSWriteHeaders set the headers identifying the version of entry read from commitID on http response
+func (o *PathOperation) lakeFSWriteHeaders(w http.ResponseWriter, entry *catalog.DBEntry, commitID string) {
+	if commitID != "" {
+		o.SetHeader(w, lakeFSCommitIDHeader, commitID)
+	}
+	addressHash := sha256.Sum256([]byte(entry.PhysicalAddress))
+	o.SetHeader(w, lakeFSChecksumHeader, hex.EncodeToString(addressHash[:]))
+}
+
+// storageClassWriteHeader sets the storage class header of entry on http response, S3 omits it for the default class
+func (o *PathOperation) storageClassWriteHeader(w http.ResponseWriter, entry *catalog.DBEntry) {
+	if storageClass := catalog.StorageClassOrDefault(entry.StorageClass); storageClass != catalog.DefaultStorageClass {
+		o.SetHeader(w, StorageClassHeader, storageClass)
+	}
+}
+
+// archiveStorageClasses are the storage classes of objects that must be restored before they can be read
+var archiveStorageClasses = []string{"GLACIER", "DEEP_ARCHIVE", "Archive"}
+
+// restoreWriteHeader sets the restore status header of entry on http response, if it was written in an archive
+// storage class
+func (o *PathOperation) restoreWriteHeader(ctx context.Context, w http.ResponseWriter, entry *catalog.DBEntry) {
+	if !slices.Contains(archiveStorageClasses, entry.StorageClass) {
+		return
+	}
+	props, err := o.BlockStore.GetProperties(ctx, block.ObjectPointer{
+		StorageNamespace: o.Repository.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Warn("failed to get archived object restore status")
+		return
+	}
+	switch {
+	case props.RestoreOngoing:
+		o.SetHeader(w, restoreHeader, `ongoing-request="true"`)
+	case !props.RestoreExpiry.IsZero():
+		o.SetHeader(w, restoreHeader, fmt.Sprintf(`ongoing-request="false", expiry-date="%s"`, props.RestoreExpiry.UTC().Format(http.TimeFormat)))
+	}
+}
+
+// getEntry returns the entry of the operation path and the commit it was read from. Paths missing from the
+// reference are read through the pass-through mappings of the repository.
+func (o *PathOperation) getEntry(ctx context.Context) (*catalog.DBEntry, string, error) {
+	entry, commitID, err := o.Catalog.GetEntryWithCommit(ctx, o.Repository.Name, o.Reference, o.Path)
+	if errors.Is(err, graveler.ErrNotFound) {
+		entry, err = o.Catalog.GetPassThroughEntry(ctx, o.Repository.Name, o.Reference, o.Path)
+	}
+	return entry, commitID, err
+}
+
+func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddress string, size int64, relative bool, metadata map[string]string, contentType string, storageClass string, tags map[string]string, retention *catalog.ObjectRetention, opts ...graveler.SetOptionsFunc) error {
 	// write metadata
 	writeTime := time.Now()
 	entry := catalog.NewDBEntryBuilder().
@@ -44,9 +114,12 @@ func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddres
 		Size(size).
 		CreationDate(writeTime).
 		ContentType(contentType).
+		StorageClass(storageClass).
+		Tags(tags).
+		Retention(retention).
 		Build()
 
-	err := o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry)
+	err := o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry, opts...)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not update metadata")
 		return err
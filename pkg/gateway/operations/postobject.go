@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -49,10 +50,21 @@ func (controller *PostObject) HandleCreateMultipartUpload(w http.ResponseWriter,
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNoSuchBucket))
 		return
 	}
+	if !o.validateKey(w, req) {
+		return
+	}
+	tags, err := TagsFromHeader(req.Header)
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid tagging header")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidTag))
+		return
+	}
 	address := o.PathProvider.NewPath()
 	storageClass := StorageClassFromHeader(req.Header)
 	opts := block.CreateMultiPartUploadOpts{StorageClass: storageClass}
-	resp, err := o.BlockStore.CreateMultiPartUpload(req.Context(), block.ObjectPointer{
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	resp, err := o.BlockStore.CreateMultiPartUpload(storageCtx, block.ObjectPointer{
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       address,
@@ -69,6 +81,8 @@ func (controller *PostObject) HandleCreateMultipartUpload(w http.ResponseWriter,
 		PhysicalAddress: address,
 		Metadata:        map[string]string(amzMetaAsMetadata(req)),
 		ContentType:     req.Header.Get("Content-Type"),
+		StorageClass:    req.Header.Get(StorageClassHeader),
+		Tags:            tags,
 	}
 	err = o.MultipartTracker.Create(req.Context(), mpu)
 	if err != nil {
@@ -110,25 +124,63 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		return
 	}
 	normalizeMultipartUploadCompletion(&multipartList)
-	resp, err := o.BlockStore.CompleteMultiPartUpload(req.Context(),
-		block.ObjectPointer{
-			StorageNamespace: o.Repository.StorageNamespace,
-			IdentifierType:   block.IdentifierTypeRelative,
-			Identifier:       objName,
-		},
-		uploadID,
-		&multipartList)
+	for _, part := range multipartList.Part {
+		if !o.Limits.validPartNumber(part.PartNumber) {
+			_ = o.EncodeError(w, req, nil, o.Limits.invalidPartNumberAPIErr())
+			return
+		}
+	}
+	metadata, err := o.multipartChecksumMetadata(req, uploadID, xmlMultipartComplete, multiPart.Metadata)
+	if err != nil {
+		o.Log(req).WithError(err).Warn("could not verify multipart upload checksum")
+		_ = o.EncodeError(w, req, err, multipartChecksumAPIErr(err))
+		return
+	}
+	obj := block.ObjectPointer{
+		StorageNamespace: o.Repository.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       objName,
+	}
+	storageCtx, cancelStorage := storageContext(req)
+	defer cancelStorage()
+	resp, err := o.BlockStore.CompleteMultiPartUpload(storageCtx, obj, uploadID, &multipartList)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not complete multipart upload")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
-	checksum := strings.Split(resp.ETag, "-")[0]
-	err = o.finishUpload(req, checksum, objName, resp.ContentLength, true, multiPart.Metadata, multiPart.ContentType)
+	req, cancel := restartLatencyBudget(req)
+	defer cancel()
+	// the size of the object is only known once its parts are combined
+	if exceedsLimit(resp.ContentLength, o.Limits.MaxObjectSize) {
+		o.Log(req).WithField("size", resp.ContentLength).Warn("completed multipart upload exceeds the maximal object size")
+		if err := o.BlockStore.Remove(storageCtx, obj); err != nil {
+			o.Log(req).WithError(err).Warn("could not remove completed multipart upload exceeding the maximal object size")
+		}
+		// the upload was completed, it cannot be completed again
+		if err := o.MultipartTracker.Delete(req.Context(), uploadID); err != nil {
+			o.Log(req).WithError(err).Warn("could not delete multipart record")
+		}
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
+	// keep the ETag of the completed object like S3 does, so it is returned consistently by head, get and list
+	etag := httputil.StripQuotesAndSpaces(resp.ETag)
+	if multipartETag, ok := block.MultipartETag(multipartList.Part); ok {
+		etag = multipartETag
+	}
+	err = o.finishUpload(req, etag, objName, resp.ContentLength, true, metadata, multiPart.ContentType, multiPart.StorageClass, multiPart.Tags, nil)
+	if encodeKeyValidationError(w, req, o, err) || encodeObjectLockError(w, req, o, err) {
+		return
+	}
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
 		return
 	}
+	if errors.Is(err, graveler.ErrBranchLocked) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToLockedBranch))
+		return
+	}
 	if errors.Is(err, graveler.ErrReadOnlyRepository) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
 		return
@@ -154,10 +206,33 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		Location: location,
 		Bucket:   o.Repository.Name,
 		Key:      path.WithRef(o.Path, o.Reference),
-		ETag:     httputil.ETag(resp.ETag),
+		ETag:     httputil.ETag(etag),
 	}, http.StatusOK)
 }
 
+// multipartChecksumMetadata verifies the checksums sent on completion of a multipart upload, and returns the
+// metadata of the object with its composite checksum, if its parts were uploaded with checksums
+func (o *PathOperation) multipartChecksumMetadata(req *http.Request, uploadID string, xmlMultipartComplete []byte, metadata multipart.Metadata) (multipart.Metadata, error) {
+	var completion serde.CompleteMultipartUpload
+	if err := xml.Unmarshal(xmlMultipartComplete, &completion); err != nil {
+		return nil, err
+	}
+	partChecksums, err := o.MultipartTracker.ListPartChecksums(req.Context(), uploadID)
+	if err != nil {
+		return nil, err
+	}
+	header, value, err := MultipartChecksum(req, completion.Part, partChecksums)
+	if err != nil || header == "" {
+		return metadata, err
+	}
+	result := maps.Clone(metadata)
+	if result == nil {
+		result = make(multipart.Metadata)
+	}
+	result[header] = value
+	return result, nil
+}
+
 // normalizeMultipartUploadCompletion normalization incoming multipart upload completion list.
 // we make sure that each part's ETag will be without the wrapping quotes
 func normalizeMultipartUploadCompletion(list *block.MultipartUploadCompletion) {
@@ -10,9 +10,24 @@ import (
 // PutBucket handles S3 Create Bucket operations.  It does *not* actually
 // create new repos (there is not enough information in the S3 request to
 // create a new repo), but *does* detect whether the repo already exists.
+// PutBucket also handles S3 Put Bucket Policy operations, translating the
+// bucket policy into a lakeFS policy, and S3 Put Bucket CORS, Put Object Lock Configuration and
+// Put Bucket Notification Configuration operations.
 type PutBucket struct{}
 
-func (controller *PutBucket) RequiredPermissions(_ *http.Request, repoID string) (permissions.Node, error) {
+func (controller *PutBucket) RequiredPermissions(req *http.Request, repoID string) (permissions.Node, error) {
+	if req.URL.Query().Has(bucketPolicyQueryParam) {
+		return bucketPolicyRequiredPermissions(repoID, permissions.CreatePolicyAction, permissions.DeletePolicyAction), nil
+	}
+	if req.URL.Query().Has(bucketCorsQueryParam) {
+		return bucketCorsRequiredPermissions(repoID, permissions.UpdateRepositoryAction), nil
+	}
+	if req.URL.Query().Has(bucketObjectLockQueryParam) {
+		return bucketObjectLockRequiredPermissions(repoID, permissions.SetObjectLockConfigurationAction), nil
+	}
+	if req.URL.Query().Has(bucketNotificationQueryParam) {
+		return bucketNotificationRequiredPermissions(repoID, permissions.UpdateRepositoryAction), nil
+	}
 	return permissions.Node{
 		Permission: permissions.Permission{
 			// Mimic S3, which requires s3:CreateBucket to call
@@ -25,9 +40,25 @@ func (controller *PutBucket) RequiredPermissions(_ *http.Request, repoID string)
 }
 
 func (controller *PutBucket) Handle(w http.ResponseWriter, req *http.Request, o *RepoOperation) {
-	if o.HandleUnsupported(w, req, "cors", "metrics", "website", "logging", "accelerate",
+	if req.URL.Query().Has(bucketPolicyQueryParam) {
+		handlePutBucketPolicy(w, req, o)
+		return
+	}
+	if req.URL.Query().Has(bucketCorsQueryParam) {
+		handlePutBucketCors(w, req, o)
+		return
+	}
+	if req.URL.Query().Has(bucketObjectLockQueryParam) {
+		handlePutBucketObjectLock(w, req, o)
+		return
+	}
+	if req.URL.Query().Has(bucketNotificationQueryParam) {
+		handlePutBucketNotification(w, req, o)
+		return
+	}
+	if o.HandleUnsupported(w, req, "metrics", "website", "logging", "accelerate",
 		"requestPayment", "acl", "publicAccessBlock", "ownershipControls", "intelligent-tiering", "analytics",
-		"lifecycle", "replication", "encryption", "policy", "object-lock", "tagging", "versioning") {
+		"lifecycle", "replication", "encryption", "tagging", "versioning") {
 		return
 	}
 
@@ -2,14 +2,17 @@ package operations
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayErrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/multipart"
 	"github.com/treeverse/lakefs/pkg/gateway/path"
 	"github.com/treeverse/lakefs/pkg/gateway/serde"
 	"github.com/treeverse/lakefs/pkg/graveler"
@@ -24,23 +27,49 @@ const (
 	CopySourceRangeHeader = "x-amz-copy-source-range"
 	QueryParamUploadID    = "uploadId"
 	QueryParamPartNumber  = "partNumber"
+	IfNoneMatchHeader     = "If-None-Match"
+	// WriteOffsetHeader makes a put append its body to the object, whose size must be the value of the header
+	WriteOffsetHeader = "x-amz-write-offset-bytes"
 )
 
 type PutObject struct{}
 
 func (controller *PutObject) RequiredPermissions(req *http.Request, repoID, _, destPath string) (permissions.Node, error) {
+	if req.URL.Query().Has(objectRetentionQueryParam) {
+		return objectRetentionRequiredPermissions(req, repoID, destPath), nil
+	}
 	copySource := req.Header.Get(CopySourceHeader)
 
 	if len(copySource) == 0 {
-		return permissions.Node{
+		writeNode := permissions.Node{
 			Permission: permissions.Permission{
 				Action:   permissions.WriteObjectAction,
 				Resource: permissions.ObjectArn(repoID, destPath),
 			},
+		}
+		if req.Header.Get(WriteOffsetHeader) != "" {
+			// the appended object contains the data of the object
+			return permissions.Node{
+				Type: permissions.NodeTypeAnd,
+				Nodes: []permissions.Node{writeNode, {
+					Permission: permissions.Permission{
+						Action:   permissions.ReadObjectAction,
+						Resource: permissions.ObjectArn(repoID, destPath),
+					},
+				}},
+			}, nil
+		}
+		if req.Header.Get(objectLockModeHeader) == "" {
+			return writeNode, nil
+		}
+		// setting retention on upload requires the permission to set it
+		return permissions.Node{
+			Type:  permissions.NodeTypeAnd,
+			Nodes: []permissions.Node{writeNode, objectRetentionRequiredPermissions(req, repoID, destPath)},
 		}, nil
 	}
 	// this is a copy operation
-	p, err := getPathFromSource(copySource)
+	p, err := CopySourcePath(copySource)
 	if err != nil {
 		logging.FromContext(req.Context()).WithError(err).Error("could not parse copy source path")
 		return permissions.Node{}, gatewayErrors.ErrInvalidCopySource
@@ -67,10 +96,9 @@ func (controller *PutObject) RequiredPermissions(req *http.Request, repoID, _, d
 
 // extractEntryFromCopyReq: get metadata from source file
 func extractEntryFromCopyReq(w http.ResponseWriter, req *http.Request, o *PathOperation, copySource string) *catalog.DBEntry {
-	p, err := getPathFromSource(copySource)
+	p, err := o.ResolveCopySource(req.Context(), copySource)
 	if err != nil {
-		o.Log(req).WithError(err).Error("could not parse copy source path")
-		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidCopySource))
+		encodeCopySourceError(w, req, o, err)
 		return nil
 	}
 	ent, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, p.Reference, p.Path, catalog.GetEntryParams{})
@@ -82,7 +110,8 @@ func extractEntryFromCopyReq(w http.ResponseWriter, req *http.Request, o *PathOp
 	return ent
 }
 
-func getPathFromSource(copySource string) (path.ResolvedAbsolutePath, error) {
+// CopySourcePath returns the object the copy source header refers to
+func CopySourcePath(copySource string) (path.ResolvedAbsolutePath, error) {
 	copySourceDecoded, err := url.QueryUnescape(copySource)
 	if err != nil {
 		copySourceDecoded = copySource
@@ -94,19 +123,43 @@ func getPathFromSource(copySource string) (path.ResolvedAbsolutePath, error) {
 	return p, nil
 }
 
+// encodeCopySourceError encodes the failure of ResolveCopySource
+func encodeCopySourceError(w http.ResponseWriter, req *http.Request, o *PathOperation, err error) {
+	if errors.Is(err, gatewayErrors.ErrInvalidCopySource) {
+		o.Log(req).WithError(err).Error("could not parse copy source path")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidCopySource))
+		return
+	}
+	o.Log(req).WithError(err).Error("could not resolve copy source reference")
+	_ = o.EncodeError(w, req, err, ResolveRefAPIErr(err))
+}
+
 func handleCopy(w http.ResponseWriter, req *http.Request, o *PathOperation, copySource string) {
 	repository := o.Repository.Name
 	branch := o.Reference
 	o.Incr("copy_object", o.Principal, repository, branch)
-	srcPath, err := getPathFromSource(copySource)
+	srcPath, err := o.ResolveCopySource(req.Context(), copySource)
 	if err != nil {
-		o.Log(req).WithError(err).Error("could not parse copy source path")
-		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidCopySource))
+		encodeCopySourceError(w, req, o, err)
 		return
 	}
 
 	ctx := req.Context()
-	entry, err := o.Catalog.CopyEntry(ctx, srcPath.Repo, srcPath.Reference, srcPath.Path, repository, branch, o.Path)
+	if o.Limits.MaxObjectSize > 0 {
+		// a missing source fails the copy below
+		srcEntry, err := o.Catalog.GetEntry(ctx, srcPath.Repo, srcPath.Reference, srcPath.Path, catalog.GetEntryParams{})
+		if err == nil && exceedsLimit(srcEntry.Size, o.Limits.MaxObjectSize) {
+			_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+			return
+		}
+	}
+	// the copy transfers object data, it is not bounded by the latency budget
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	entry, err := o.Catalog.CopyEntry(storageCtx, srcPath.Repo, srcPath.Reference, srcPath.Path, repository, branch, o.Path)
+	if encodeKeyValidationError(w, req, o, err) || encodeObjectLockError(w, req, o, err) {
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could create a copy")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidCopyDest))
@@ -133,6 +186,10 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 	} else {
 		partNumber = int(n)
 	}
+	if !o.Limits.validPartNumber(partNumber) {
+		_ = o.EncodeError(w, req, nil, o.Limits.invalidPartNumberAPIErr())
+		return
+	}
 
 	req = req.WithContext(logging.AddFields(req.Context(), logging.Fields{
 		logging.PartNumberFieldKey: partNumber,
@@ -168,19 +225,33 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 			Identifier:       multiPart.PhysicalAddress,
 		}
 
+		storageCtx, cancel := storageContext(req)
+		defer cancel()
 		var resp *block.UploadPartResponse
 		if rang := req.Header.Get(CopySourceRangeHeader); rang != "" {
 			// if this is a copy part with a byte range:
 			parsedRange, parseErr := httputil.ParseRange(rang, ent.Size)
 			if parseErr != nil {
 				// invalid range will silently fall back to copying the entire object. ¯\_(ツ)_/¯
-				resp, err = o.BlockStore.UploadCopyPart(req.Context(), src, dst, uploadID, partNumber)
+				if exceedsLimit(ent.Size, o.Limits.MaxPartSize) {
+					_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+					return
+				}
+				resp, err = o.BlockStore.UploadCopyPart(storageCtx, src, dst, uploadID, partNumber)
 			} else {
-				resp, err = o.BlockStore.UploadCopyPartRange(req.Context(), src, dst, uploadID, partNumber, parsedRange.StartOffset, parsedRange.EndOffset)
+				if exceedsLimit(parsedRange.EndOffset-parsedRange.StartOffset+1, o.Limits.MaxPartSize) {
+					_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+					return
+				}
+				resp, err = o.BlockStore.UploadCopyPartRange(storageCtx, src, dst, uploadID, partNumber, parsedRange.StartOffset, parsedRange.EndOffset)
 			}
 		} else {
+			if exceedsLimit(ent.Size, o.Limits.MaxPartSize) {
+				_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+				return
+			}
 			// normal copy part that accepts another object and no byte range:
-			resp, err = o.BlockStore.UploadCopyPart(req.Context(), src, dst, uploadID, partNumber)
+			resp, err = o.BlockStore.UploadCopyPart(storageCtx, src, dst, uploadID, partNumber)
 		}
 
 		if err != nil {
@@ -196,18 +267,63 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 		return
 	}
 
+	verifier, err := NewChecksumVerifier(req)
+	if err != nil {
+		_ = o.EncodeError(w, req, err, checksumAPIErr(verifier, err))
+		return
+	}
+	var body io.Reader = req.Body
+	if verifier != nil {
+		body = verifier.Reader()
+	}
 	byteSize := req.ContentLength
-	resp, err := o.BlockStore.UploadPart(req.Context(), block.ObjectPointer{
+	if exceedsLimit(byteSize, o.Limits.MaxPartSize) {
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
+	body = limitReader(body, o.Limits.MaxPartSize)
+	storageCtx, cancelStorage := storageContext(req)
+	defer cancelStorage()
+	resp, err := o.BlockStore.UploadPart(storageCtx, block.ObjectPointer{
 		StorageNamespace: o.Repository.StorageNamespace,
 		IdentifierType:   block.IdentifierTypeRelative,
 		Identifier:       multiPart.PhysicalAddress,
 	},
-		byteSize, req.Body, uploadID, partNumber)
+		byteSize, body, uploadID, partNumber)
+	if errors.Is(err, ErrEntityTooLarge) {
+		o.Log(req).WithError(err).Warn("part " + partNumberStr + " exceeds the maximal part size")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("part " + partNumberStr + " upload failed")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
+	req, cancel := restartLatencyBudget(req)
+	defer cancel()
+	if verifier != nil {
+		// a part failing verification is not acknowledged, it can only be completed once uploaded again
+		checksum, err := verifier.Verify(req)
+		if err != nil {
+			o.Log(req).WithError(err).Warn("could not verify part " + partNumberStr + " checksum")
+			_ = o.EncodeError(w, req, err, checksumAPIErr(verifier, err))
+			return
+		}
+		// recorded to compute the checksum of the object on completion
+		err = o.MultipartTracker.SetPartChecksum(req.Context(), uploadID, multipart.PartChecksum{
+			PartNumber: partNumber,
+			ETag:       strings.Trim(resp.ETag, `"`),
+			Algorithm:  verifier.algorithm,
+			Checksum:   checksum,
+		})
+		if err != nil {
+			o.Log(req).WithError(err).Error("could not record part " + partNumberStr + " checksum")
+			_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+			return
+		}
+		o.SetHeader(w, verifier.header, checksum)
+	}
 	o.SetHeaders(w, resp.ServerSideHeader)
 	o.SetHeader(w, "ETag", httputil.ETag(resp.ETag))
 	w.WriteHeader(http.StatusOK)
@@ -233,12 +349,21 @@ func (controller *PutObject) Handle(w http.ResponseWriter, req *http.Request, o
 
 	query := req.URL.Query()
 
+	if query.Has(objectRetentionQueryParam) {
+		handlePutObjectRetention(w, req, o)
+		return
+	}
+
 	// check if this is a multipart upload creation call
 	if query.Has(QueryParamUploadID) {
 		handleUploadPart(w, req, o)
 		return
 	}
 
+	if !o.validateKey(w, req) {
+		return
+	}
+
 	// check if this is a copy operation (i.e. https://docs.aws.amazon.com/AmazonS3/latest/API/API_CopyObject.html)
 	// A copy operation is identified by the existence of an "x-amz-copy-source" header
 	copySource := req.Header.Get(CopySourceHeader)
@@ -258,16 +383,138 @@ func (controller *PutObject) Handle(w http.ResponseWriter, req *http.Request, o
 		return
 	}
 
+	if req.Header.Get(WriteOffsetHeader) != "" {
+		handleAppend(w, req, o)
+		return
+	}
+
 	// handle the upload itself
 	handlePut(w, req, o)
 }
 
+// handleAppend appends the request body to the object, whose size must be the write offset of the request
+func handleAppend(w http.ResponseWriter, req *http.Request, o *PathOperation) {
+	o.Incr("append_object", o.Principal, o.Repository.Name, o.Reference)
+	offset, err := strconv.ParseInt(req.Header.Get(WriteOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidWriteOffset))
+		return
+	}
+	// the appended object is the offset bytes of the object followed by the body
+	if exceedsLimit(offset+max(req.ContentLength, 0), o.Limits.MaxObjectSize) {
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
+	var body io.Reader = req.Body
+	if o.Limits.MaxObjectSize > 0 {
+		body = &limitedReader{r: req.Body, remaining: o.Limits.MaxObjectSize - offset}
+	}
+	// the append transfers object data, it is not bounded by the latency budget
+	storageCtx, cancel := storageContext(req)
+	defer cancel()
+	entry, err := o.Catalog.AppendEntry(storageCtx, o.Repository.Name, o.Reference, o.Path, body, req.ContentLength, catalog.AppendParams{
+		WriteOffset: &offset,
+		ContentType: req.Header.Get("Content-Type"),
+	})
+	if encodeKeyValidationError(w, req, o, err) || encodeObjectLockError(w, req, o, err) {
+		return
+	}
+	switch {
+	case err == nil:
+	case errors.Is(err, catalog.ErrWriteOffsetMismatch):
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidWriteOffset))
+		return
+	case errors.Is(err, ErrEntityTooLarge):
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	case errors.Is(err, graveler.ErrPreconditionFailed):
+		// the object changed while appending
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return
+	case errors.Is(err, graveler.ErrWriteToProtectedBranch):
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
+		return
+	case errors.Is(err, graveler.ErrBranchLocked):
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToLockedBranch))
+		return
+	case errors.Is(err, graveler.ErrReadOnlyRepository):
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
+		return
+	default:
+		o.Log(req).WithError(err).Error("could not append to object")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	o.SetHeader(w, "ETag", httputil.ETag(entry.Checksum))
+	w.WriteHeader(http.StatusOK)
+}
+
+// putIfAbsent reports whether the request is a conditional put that must fail when the key exists, as set by an
+// If-None-Match: * header, and whether the request may proceed. The key is checked here, before the body is
+// uploaded, and again when the entry is written.
+func putIfAbsent(w http.ResponseWriter, req *http.Request, o *PathOperation) (bool, bool) {
+	ifNoneMatch := req.Header.Get(IfNoneMatchHeader)
+	if ifNoneMatch == "" {
+		return false, true
+	}
+	if strings.Trim(ifNoneMatch, `"`) != "*" {
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrNotImplemented))
+		return false, false
+	}
+	_, err := o.Catalog.GetEntry(req.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	switch {
+	case err == nil:
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return false, false
+	case !errors.Is(err, graveler.ErrNotFound):
+		o.Log(req).WithError(err).Error("could not check if object exists")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return false, false
+	}
+	return true, true
+}
+
 func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	o.Incr("put_object", o.Principal, o.Repository.Name, o.Reference)
+	retention, err := retentionFromHeaders(req)
+	if encodeObjectLockError(w, req, o, err) {
+		return
+	}
+	tags, err := TagsFromHeader(req.Header)
+	if err != nil {
+		o.Log(req).WithError(err).Debug("invalid tagging header")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidTag))
+		return
+	}
+	ifAbsent, ok := putIfAbsent(w, req, o)
+	if !ok {
+		return
+	}
+	if exceedsLimit(req.ContentLength, o.Limits.MaxObjectSize) {
+		_ = o.EncodeError(w, req, nil, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
+	verifier, err := NewChecksumVerifier(req)
+	if err != nil {
+		_ = o.EncodeError(w, req, err, checksumAPIErr(verifier, err))
+		return
+	}
+	var body io.Reader = req.Body
+	if verifier != nil {
+		body = verifier.Reader()
+	}
+	body = limitReader(body, o.Limits.MaxObjectSize)
 	storageClass := StorageClassFromHeader(req.Header)
 	opts := block.PutOpts{StorageClass: storageClass}
 	address := o.PathProvider.NewPath()
-	blob, err := upload.WriteBlob(req.Context(), o.BlockStore, o.Repository.StorageNamespace, address, req.Body, req.ContentLength, opts)
+	storageCtx, cancelStorage := storageContext(req)
+	defer cancelStorage()
+	blob, err := upload.WriteBlob(storageCtx, o.BlockStore, o.Repository.StorageNamespace, address, body, req.ContentLength, opts)
+	if errors.Is(err, ErrEntityTooLarge) {
+		o.Log(req).WithError(err).Warn("request body exceeds the maximal object size")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrEntityTooLarge))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not write request body to block adapter")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
@@ -275,13 +522,53 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	}
 
 	// write metadata
+	req, cancel := restartLatencyBudget(req)
+	defer cancel()
 	metadata := amzMetaAsMetadata(req)
+	var checksum string
+	if verifier != nil {
+		checksum, err = verifier.Verify(req)
+		if err != nil {
+			o.Log(req).WithError(err).Warn("could not verify request body checksum")
+			removeErr := o.BlockStore.Remove(storageCtx, block.ObjectPointer{
+				StorageNamespace: o.Repository.StorageNamespace,
+				IdentifierType:   block.IdentifierTypeRelative,
+				Identifier:       blob.PhysicalAddress,
+			})
+			if removeErr != nil {
+				o.Log(req).WithError(removeErr).Warn("could not remove object with invalid checksum")
+			}
+			_ = o.EncodeError(w, req, err, checksumAPIErr(verifier, err))
+			return
+		}
+		metadata[verifier.header] = checksum
+	}
 	contentType := req.Header.Get("Content-Type")
-	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata, contentType)
+	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata, contentType, req.Header.Get(StorageClassHeader), tags, retention, graveler.WithIfAbsent(ifAbsent))
+	if encodeKeyValidationError(w, req, o, err) || encodeObjectLockError(w, req, o, err) {
+		return
+	}
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		// the key was written after it was checked, the uploaded data is not referenced
+		removeErr := o.BlockStore.Remove(storageCtx, block.ObjectPointer{
+			StorageNamespace: o.Repository.StorageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       blob.PhysicalAddress,
+		})
+		if removeErr != nil {
+			o.Log(req).WithError(removeErr).Warn("could not remove object of failed conditional put")
+		}
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrPreconditionFailed))
+		return
+	}
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
 		return
 	}
+	if errors.Is(err, graveler.ErrBranchLocked) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToLockedBranch))
+		return
+	}
 	if errors.Is(err, graveler.ErrReadOnlyRepository) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
 		return
@@ -291,5 +578,8 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 		return
 	}
 	o.SetHeader(w, "ETag", httputil.ETag(blob.Checksum))
+	if verifier != nil {
+		o.SetHeader(w, verifier.header, checksum)
+	}
 	w.WriteHeader(http.StatusOK)
 }
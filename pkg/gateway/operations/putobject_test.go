@@ -6,10 +6,20 @@ import (
 	"crypto/md5" //nolint:gosec
 	"crypto/rand"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
 )
 
@@ -78,3 +88,55 @@ func TestReadBlob(t *testing.T) {
 		})
 	}
 }
+
+func TestPutObject_AppendMaxObjectSize(t *testing.T) {
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	cfg, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       cfg,
+		KVStore:      kvtest.GetStore(ctx, t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	repo, err := c.CreateRepository(ctx, "repo", "mem://repo", "main", false)
+	testutil.MustDo(t, "create repository", err)
+
+	o := &operations.PathOperation{
+		RefOperation: &operations.RefOperation{
+			RepoOperation: &operations.RepoOperation{
+				AuthorizedOperation: &operations.AuthorizedOperation{
+					Operation: &operations.Operation{
+						Catalog: c,
+						Incr:    func(string, string, string, string) {},
+						Limits:  operations.UploadLimits{MaxObjectSize: 10},
+					},
+				},
+				Repository: repo,
+			},
+			Reference: "main",
+		},
+		Path: "a",
+	}
+	appendData := func(offset int, data string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/repo/main/a", strings.NewReader(data))
+		req.Header.Set(operations.WriteOffsetHeader, strconv.Itoa(offset))
+		rr := httptest.NewRecorder()
+		(&operations.PutObject{}).Handle(rr, req, o)
+		return rr
+	}
+
+	if rr := appendData(0, "12345678"); rr.Code != http.StatusOK {
+		t.Fatalf("append: status %d, body %s", rr.Code, rr.Body.String())
+	}
+	// the appended data is within the limit, the appended object is not
+	if rr := appendData(8, "data"); rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "EntityTooLarge") {
+		t.Fatalf("append past the object size limit: status %d, body %s", rr.Code, rr.Body.String())
+	}
+	if rr := appendData(8, "ab"); rr.Code != http.StatusOK {
+		t.Fatalf("append up to the object size limit: status %d, body %s", rr.Code, rr.Body.String())
+	}
+}
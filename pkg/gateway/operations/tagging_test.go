@@ -0,0 +1,51 @@
+package operations_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+)
+
+func TestTagsFromHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagging  string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{name: "no header"},
+		{name: "single", tagging: "pii=true", expected: map[string]string{"pii": "true"}},
+		{name: "multiple", tagging: "pii=true&team=data%20eng", expected: map[string]string{"pii": "true", "team": "data eng"}},
+		{name: "empty value", tagging: "pii=", expected: map[string]string{"pii": ""}},
+		{name: "empty key", tagging: "=true", wantErr: true},
+		{name: "repeated key", tagging: "pii=true&pii=false", wantErr: true},
+		{name: "too many", tagging: "a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8&i=9&j=10&k=11", wantErr: true},
+		{name: "long key", tagging: strings.Repeat("k", 129) + "=v", wantErr: true},
+		{name: "long value", tagging: "k=" + strings.Repeat("v", 257), wantErr: true},
+		{name: "malformed", tagging: "pii=%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.tagging != "" {
+				header.Set(operations.TaggingHeader, tt.tagging)
+			}
+			tags, err := operations.TagsFromHeader(header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TagsFromHeader(%s) expected error, got tags %v", tt.tagging, tags)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TagsFromHeader(%s) unexpected error: %s", tt.tagging, err)
+			}
+			if diff := deep.Equal(tags, tt.expected); diff != nil {
+				t.Error("TagsFromHeader() diff found", diff)
+			}
+		})
+	}
+}
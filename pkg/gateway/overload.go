@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"net/http"
+	"time"
+
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+// OverloadLimits bound the latency of gateway operations and shed load while the database is saturated, so that an
+// overloaded lakeFS answers SlowDown instead of queueing more requests
+type OverloadLimits struct {
+	// Deadline is the latency budget of operations without one in OperationDeadlines, zero for no budget
+	Deadline time.Duration
+	// OperationDeadlines are the latency budgets of specific operations, zero for no budget
+	OperationDeadlines map[operations.OperationID]time.Duration
+	// Pool reports the saturation of the database connection pool, nil disables load shedding
+	Pool kv.PoolStats
+	// MaxPoolSaturation is the pool saturation from which requests are rejected, zero disables load shedding
+	MaxPoolSaturation float64
+}
+
+func (l OverloadLimits) deadline(operationID operations.OperationID) time.Duration {
+	if deadline, ok := l.OperationDeadlines[operationID]; ok {
+		return deadline
+	}
+	return l.Deadline
+}
+
+func (l OverloadLimits) saturated() (float64, bool) {
+	if l.Pool == nil || l.MaxPoolSaturation <= 0 {
+		return 0, false
+	}
+	saturation := l.Pool.PoolSaturation()
+	return saturation, saturation >= l.MaxPoolSaturation
+}
+
+// OverloadHandler rejects requests with SlowDown while the database pool is saturated, and cancels the metadata and
+// index calls of operations that exceed their latency budget.  Transfers of object data are not bounded by the budget.
+// Operations encode errors of a request past its budget as SlowDown.
+func OverloadHandler(limits OverloadLimits, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
+		if saturation, ok := limits.saturated(); ok {
+			shedRequests.WithLabelValues(string(o.OperationID), "db_pool_saturation").Inc()
+			o.Log(req).WithField("db_pool_saturation", saturation).Warn("database pool saturated, shedding request")
+			_ = o.EncodeError(w, req, kv.ErrSlowDown, gatewayerrors.ErrSlowDown.ToAPIErr())
+			return
+		}
+		deadline := limits.deadline(o.OperationID)
+		if deadline <= 0 {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ctx, cancel := operations.WithLatencyBudget(ctx, deadline)
+		defer cancel()
+		next.ServeHTTP(w, req.WithContext(ctx))
+		if operations.LatencyBudgetExceeded(ctx) {
+			shedRequests.WithLabelValues(string(o.OperationID), "deadline").Inc()
+			o.Log(req).WithField("deadline", deadline).Warn("operation exceeded its latency budget")
+		}
+	})
+}
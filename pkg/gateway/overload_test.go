@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+)
+
+type poolStats float64
+
+func (p poolStats) PoolSaturation() float64 { return float64(p) }
+
+func TestOverloadHandler(t *testing.T) {
+	// slow waits for the request to be canceled or for a second, and fails like an operation failing a downstream call
+	slow := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		o := req.Context().Value(ContextKeyOperation).(*operations.Operation)
+		select {
+		case <-req.Context().Done():
+			_ = o.EncodeError(w, req, req.Context().Err(), gatewayerrors.ErrInternalError.ToAPIErr())
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	tt := []struct {
+		name         string
+		limits       OverloadLimits
+		expectedCode int
+	}{
+		{name: "no limits", expectedCode: http.StatusOK},
+		{name: "deadline", limits: OverloadLimits{Deadline: 10 * time.Millisecond}, expectedCode: http.StatusServiceUnavailable},
+		{name: "operation deadline", limits: OverloadLimits{Deadline: 10 * time.Millisecond, OperationDeadlines: map[operations.OperationID]time.Duration{operations.OperationIDGetObject: 0}}, expectedCode: http.StatusOK},
+		{name: "pool saturated", limits: OverloadLimits{Pool: poolStats(0.95), MaxPoolSaturation: 0.9}, expectedCode: http.StatusServiceUnavailable},
+		{name: "pool not saturated", limits: OverloadLimits{Pool: poolStats(0.5), MaxPoolSaturation: 0.9}, expectedCode: http.StatusOK},
+		{name: "shedding disabled", limits: OverloadLimits{Pool: poolStats(1)}, expectedCode: http.StatusOK},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &operations.Operation{OperationID: operations.OperationIDGetObject}
+			req := httptest.NewRequest(http.MethodGet, "/repo/main/a", nil)
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyOperation, o))
+			rr := httptest.NewRecorder()
+			OverloadHandler(tc.limits, slow).ServeHTTP(rr, req)
+			if rr.Code != tc.expectedCode {
+				t.Fatalf("status code %d, expected %d", rr.Code, tc.expectedCode)
+			}
+			if tc.expectedCode == http.StatusServiceUnavailable && !strings.Contains(rr.Body.String(), "<Code>SlowDown</Code>") {
+				t.Fatalf("expected SlowDown error, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestOverloadLimits_Deadline(t *testing.T) {
+	limits := OverloadLimits{
+		Deadline:           time.Second,
+		OperationDeadlines: map[operations.OperationID]time.Duration{operations.OperationIDListObjects: time.Minute},
+	}
+	if d := limits.deadline(operations.OperationIDListObjects); d != time.Minute {
+		t.Errorf("list_objects deadline %s, expected 1m", d)
+	}
+	if d := limits.deadline(operations.OperationIDHeadObject); d != time.Second {
+		t.Errorf("head_object deadline %s, expected 1s", d)
+	}
+}
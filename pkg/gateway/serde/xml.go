@@ -29,8 +29,10 @@ type Bucket struct {
 }
 
 type ListAllMyBucketsResult struct {
-	Buckets Buckets `xml:"Buckets"`
-	Owner   Owner   `xml:"Owner"`
+	Buckets           Buckets `xml:"Buckets"`
+	Owner             Owner   `xml:"Owner"`
+	ContinuationToken string  `xml:"ContinuationToken,omitempty"`
+	Prefix            string  `xml:"Prefix,omitempty"`
 }
 
 type CreateBucketConfiguration struct {
@@ -47,6 +49,10 @@ type Contents struct {
 
 type CommonPrefixes struct {
 	Prefix string `xml:"Prefix"`
+	// ObjectCount and Size aggregate the objects under the prefix, at any depth. They are lakeFS extensions, set only
+	// when requested.
+	ObjectCount *int64 `xml:"LakeFSObjectCount,omitempty"`
+	Size        *int64 `xml:"LakeFSSize,omitempty"`
 }
 
 type ListObjectsV2Output struct {
@@ -136,8 +142,13 @@ type InitiateMultipartUploadResult struct {
 }
 
 type CompleteMultipartUploadPart struct {
-	PartNumber int    `xml:"PartNumber"`
-	ETag       string `xml:"ETag"`
+	PartNumber        int    `xml:"PartNumber"`
+	ETag              string `xml:"ETag"`
+	ChecksumCRC32     string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C    string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumCRC64NVME string `xml:"ChecksumCRC64NVME,omitempty"`
+	ChecksumSHA1      string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256    string `xml:"ChecksumSHA256,omitempty"`
 }
 
 type CompleteMultipartUpload struct {
@@ -173,3 +184,107 @@ type LocationResponse struct {
 	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
 	Location string   `xml:",chardata"`
 }
+
+// CORSConfiguration is accepted with or without the S3 namespace
+type CORSConfiguration struct {
+	XMLName   xml.Name   `xml:"CORSConfiguration"`
+	CORSRules []CORSRule `xml:"CORSRule"`
+}
+
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int32    `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// ObjectLockConfiguration is accepted with or without the S3 namespace
+type ObjectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *ObjectLockRule `xml:"Rule,omitempty"`
+}
+
+type ObjectLockRule struct {
+	DefaultRetention DefaultRetention `xml:"DefaultRetention"`
+}
+
+type DefaultRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int32  `xml:"Days,omitempty"`
+	Years int32  `xml:"Years,omitempty"`
+}
+
+// Retention is accepted with or without the S3 namespace
+type Retention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Mode            string   `xml:"Mode,omitempty"`
+	RetainUntilDate string   `xml:"RetainUntilDate,omitempty"`
+}
+
+// NotificationConfiguration is accepted with or without the S3 namespace
+type NotificationConfiguration struct {
+	XMLName                     xml.Name                     `xml:"NotificationConfiguration"`
+	TopicConfigurations         []TopicConfiguration         `xml:"TopicConfiguration,omitempty"`
+	QueueConfigurations         []QueueConfiguration         `xml:"QueueConfiguration,omitempty"`
+	CloudFunctionConfigurations []CloudFunctionConfiguration `xml:"CloudFunctionConfiguration,omitempty"`
+	EventBridgeConfiguration    *struct{}                    `xml:"EventBridgeConfiguration,omitempty"`
+}
+
+type TopicConfiguration struct {
+	ID     string              `xml:"Id,omitempty"`
+	Topic  string              `xml:"Topic"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type QueueConfiguration struct {
+	ID     string              `xml:"Id,omitempty"`
+	Queue  string              `xml:"Queue"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type CloudFunctionConfiguration struct {
+	ID            string              `xml:"Id,omitempty"`
+	CloudFunction string              `xml:"CloudFunction"`
+	Events        []string            `xml:"Event"`
+	Filter        *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type NotificationFilter struct {
+	FilterRules []FilterRule `xml:"S3Key>FilterRule"`
+}
+
+type FilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+type GetObjectAttributesResponse struct {
+	XMLName      xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAttributesResponse"`
+	ETag         string          `xml:"ETag,omitempty"`
+	Checksum     *ObjectChecksum `xml:"Checksum,omitempty"`
+	ObjectParts  *ObjectParts    `xml:"ObjectParts,omitempty"`
+	StorageClass string          `xml:"StorageClass,omitempty"`
+	ObjectSize   *int64          `xml:"ObjectSize,omitempty"`
+}
+
+type ObjectChecksum struct {
+	ChecksumCRC32     string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C    string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumCRC64NVME string `xml:"ChecksumCRC64NVME,omitempty"`
+	ChecksumSHA1      string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256    string `xml:"ChecksumSHA256,omitempty"`
+	ChecksumType      string `xml:"ChecksumType,omitempty"`
+}
+
+type ObjectParts struct {
+	PartsCount           int  `xml:"PartsCount"`
+	PartNumberMarker     int  `xml:"PartNumberMarker"`
+	NextPartNumberMarker int  `xml:"NextPartNumberMarker"`
+	MaxParts             int  `xml:"MaxParts"`
+	IsTruncated          bool `xml:"IsTruncated"`
+}
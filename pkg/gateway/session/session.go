@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const storePartitionKey = "gateway-sessions"
+
+// RefSeparator separates the branch and the token of a session reference, "<branch>@<token>"
+const RefSeparator = "@"
+
+var (
+	ErrExpired      = errors.New("session expired")
+	ErrInvalidToken = errors.New("invalid session token")
+	ErrInvalidPin   = errors.New("invalid session pin")
+)
+
+var tokenRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// ParseRef splits a session reference "<branch>@<token>" into its branch and token. It returns false for other
+// references, including the "<ref>@" modifier of committed data. A tag may also have this form, callers resolve the
+// full reference first.
+func ParseRef(ref string) (string, string, bool) {
+	i := strings.LastIndex(ref, RefSeparator)
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+	branch, token := ref[:i], ref[i+1:]
+	if strings.ContainsAny(branch, "@~^$") || !tokenRegexp.MatchString(token) {
+		return "", "", false
+	}
+	return branch, token, true
+}
+
+// Sessions pin the branches read by the requests of a client session to the commit they pointed at when the session
+// first read them, so that all the requests of the session read the same data while the branch advances. A session is
+// identified by its token together with the user, the same token of another user is another session.
+type Sessions interface {
+	// Resolve returns the commit ID branch of repository is pinned to by the session of token of user. The first
+	// resolve of a branch by a session pins it to the commit returned by head.
+	Resolve(ctx context.Context, repository, user, branch, token string, head func() (string, error)) (string, error)
+	// DeleteExpired deletes the pins of sessions started more than the session TTL ago
+	DeleteExpired(ctx context.Context) error
+}
+
+// pinsCacheSize bounds the number of pins cached by an instance, evicted pins are read again from the store
+const pinsCacheSize = 10000
+
+type sessions struct {
+	store kv.Store
+	ttl   time.Duration
+	now   func() time.Time
+	// pins caches the stored pins read or made by this instance, by key, until their session expires
+	pins cache.Cache
+}
+
+// NewSessions returns sessions kept in store for ttl from their first read of a branch
+func NewSessions(store kv.Store, ttl time.Duration) Sessions {
+	return &sessions{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		pins:  cache.NewCache(pinsCacheSize, ttl, cache.NewJitterFn(0)),
+	}
+}
+
+func pinKey(repository, user, token, branch string) []byte {
+	// user names may contain the path separator
+	return []byte(kv.FormatPath(repository, url.PathEscape(user), token, branch))
+}
+
+func (s *sessions) Resolve(ctx context.Context, repository, user, branch, token string, head func() (string, error)) (string, error) {
+	if !tokenRegexp.MatchString(token) {
+		return "", ErrInvalidToken
+	}
+	key := pinKey(repository, user, token, branch)
+	pin, err := s.getPin(ctx, key, head)
+	if err != nil {
+		return "", err
+	}
+	if s.expired(pin) {
+		// the session may have expired while its pin was cached, and its stored pin deleted since
+		s.pins.Remove(string(key))
+		if pin, err = s.getPin(ctx, key, head); err != nil {
+			return "", err
+		}
+	}
+	return s.pinnedCommit(pin)
+}
+
+// getPin returns the pin of key, cached until its session expires. A branch that is not pinned yet is pinned to the
+// commit returned by head.
+func (s *sessions) getPin(ctx context.Context, key []byte, head func() (string, error)) (*PinData, error) {
+	v, err := s.pins.GetOrSetWithExpiry(string(key), func() (interface{}, time.Duration, error) {
+		pin, err := s.loadOrStorePin(ctx, key, head)
+		if err != nil {
+			return nil, 0, err
+		}
+		remaining := s.ttl - s.now().Sub(pin.CreationDate.AsTime())
+		return pin, max(remaining, time.Nanosecond), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*PinData), nil
+}
+
+// loadOrStorePin returns the stored pin of key, or stores a pin of key to the commit returned by head. The pin is
+// stored before it is read, so that requests of the session served by all instances read the same commit: an
+// instance that loses the race to store the pin returns the pin stored by the winner.
+func (s *sessions) loadOrStorePin(ctx context.Context, key []byte, head func() (string, error)) (*PinData, error) {
+	pin := &PinData{}
+	_, err := kv.GetMsg(ctx, s.store, storePartitionKey, key, pin)
+	if err == nil {
+		return pin, nil
+	}
+	if !errors.Is(err, kv.ErrNotFound) {
+		return nil, err
+	}
+	commitID, err := head()
+	if err != nil {
+		return nil, err
+	}
+	pin = &PinData{CommitId: commitID, CreationDate: timestamppb.New(s.now())}
+	err = kv.SetMsgIf(ctx, s.store, storePartitionKey, key, pin, nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		stored := &PinData{}
+		if _, err := kv.GetMsg(ctx, s.store, storePartitionKey, key, stored); err != nil {
+			return nil, err
+		}
+		return stored, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pin, nil
+}
+
+func (s *sessions) pinnedCommit(pin *PinData) (string, error) {
+	if s.expired(pin) {
+		return "", fmt.Errorf("%w: started at %s", ErrExpired, pin.CreationDate.AsTime())
+	}
+	return pin.CommitId, nil
+}
+
+func (s *sessions) expired(pin *PinData) bool {
+	return s.now().Sub(pin.CreationDate.AsTime()) > s.ttl
+}
+
+func (s *sessions) DeleteExpired(ctx context.Context) error {
+	it, err := kv.NewPrimaryIterator(ctx, s.store, (&PinData{}).ProtoReflect().Type(), storePartitionKey, []byte(""), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		entry := it.Entry()
+		pin, ok := entry.Value.(*PinData)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidPin, entry.Key)
+		}
+		if !s.expired(pin) {
+			continue
+		}
+		if err := s.store.Delete(ctx, []byte(storePartitionKey), entry.Key); err != nil {
+			return err
+		}
+		s.pins.Remove(string(entry.Key))
+	}
+	return it.Err()
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	_ "github.com/treeverse/lakefs/pkg/kv/mem"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		ref    string
+		branch string
+		token  string
+		ok     bool
+	}{
+		{ref: "main@consistent-token", branch: "main", token: "consistent-token", ok: true},
+		{ref: "feature_1@a1", branch: "feature_1", token: "a1", ok: true},
+		{ref: "main", ok: false},
+		{ref: "main@", ok: false},
+		{ref: "@token", ok: false},
+		{ref: "main~1@token", ok: false},
+		{ref: "main@token.1", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			branch, token, ok := ParseRef(tt.ref)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.branch, branch)
+			require.Equal(t, tt.token, token)
+		})
+	}
+}
+
+func TestSessions_Resolve(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := kvtest.GetStore(ctx, t)
+	newSessions := func() *sessions {
+		s := NewSessions(store, time.Hour).(*sessions)
+		s.now = func() time.Time { return now }
+		return s
+	}
+	s := newSessions()
+
+	head := "c1"
+	headFn := func() (string, error) { return head, nil }
+	commitID, err := s.Resolve(ctx, "repo", "user", "main", "token", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c1", commitID)
+
+	// the branch advances, the session keeps reading the commit it pinned
+	head = "c2"
+	commitID, err = s.Resolve(ctx, "repo", "user", "main", "token", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c1", commitID)
+
+	// requests of the session served by other instances read the stored pin
+	commitID, err = newSessions().Resolve(ctx, "repo", "user", "main", "token", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c1", commitID)
+
+	// other sessions pin the current head, the token of another user is another session
+	commitID, err = s.Resolve(ctx, "repo", "user", "main", "other", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c2", commitID)
+	commitID, err = s.Resolve(ctx, "repo", "other-user", "main", "token", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c2", commitID)
+
+	now = now.Add(2 * time.Hour)
+	_, err = s.Resolve(ctx, "repo", "user", "main", "token", headFn)
+	require.True(t, errors.Is(err, ErrExpired), "expected ErrExpired, got %v", err)
+
+	require.NoError(t, s.DeleteExpired(ctx))
+	// the token starts a new session once its pins were deleted
+	commitID, err = s.Resolve(ctx, "repo", "user", "main", "token", headFn)
+	require.NoError(t, err)
+	require.Equal(t, "c2", commitID)
+}
+
+func TestSessions_ResolveConcurrentInstances(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	first := NewSessions(store, time.Hour)
+	second := NewSessions(store, time.Hour)
+
+	// the first instance pins the branch while the second reads its head, the second reads the pin of the first
+	commitID, err := second.Resolve(ctx, "repo", "user", "main", "token", func() (string, error) {
+		commitID, err := first.Resolve(ctx, "repo", "user", "main", "token", func() (string, error) { return "c1", nil })
+		require.NoError(t, err)
+		require.Equal(t, "c1", commitID)
+		return "c2", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "c1", commitID)
+
+	// both instances keep reading the same pin
+	for _, s := range []Sessions{first, second} {
+		commitID, err = s.Resolve(ctx, "repo", "user", "main", "token", func() (string, error) { return "c3", nil })
+		require.NoError(t, err)
+		require.Equal(t, "c1", commitID)
+	}
+}
@@ -0,0 +1,52 @@
+package sig
+
+import (
+	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+	"github.com/treeverse/lakefs/pkg/gateway/errors"
+)
+
+// SigningScope restricts the regions and services that requests may be signed for.  Each
+// entry is a wildcard pattern, "*" accepts any region or service.  Requests signed with
+// SigV2 carry no scope and are always accepted.
+type SigningScope struct {
+	Regions  []string
+	Services []string
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if wildcard.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRegionSet reports whether a SigV4A region set, whose entries may themselves be
+// wildcards, covers one of the configured regions.
+func matchRegionSet(patterns, regionSet []string) bool {
+	for _, region := range regionSet {
+		for _, pattern := range patterns {
+			if wildcard.Match(pattern, region) || wildcard.Match(region, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Verify returns ErrAuthorizationHeaderMalformed if sigContext is signed for a region or
+// service outside the scope.
+func (s SigningScope) Verify(sigContext SigContext) error {
+	switch a := sigContext.(type) {
+	case V4Auth:
+		if !matchAny(s.Regions, a.Region) || !matchAny(s.Services, a.Service) {
+			return errors.ErrAuthorizationHeaderMalformed
+		}
+	case V4aAuth:
+		if !matchRegionSet(s.Regions, a.RegionSet) || !matchAny(s.Services, a.Service) {
+			return errors.ErrAuthorizationHeaderMalformed
+		}
+	}
+	return nil
+}
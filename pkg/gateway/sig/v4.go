@@ -33,6 +33,12 @@ const (
 	v4SignatureHeader       = "X-Amz-Signature"
 )
 
+// Streaming payloads followed by trailing headers, sent by SDKs computing additional checksums while uploading
+const (
+	v4StreamingTrailerPayloadHash         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	v4StreamingUnsignedTrailerPayloadHash = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+)
+
 var (
 	V4AuthHeaderRegexp      = regexp.MustCompile(`AWS4-HMAC-SHA256 Credential=(?P<AccessKeyId>.{3,20})/(?P<Date>\d{8})/(?P<Region>[\w\-]+)/(?P<Service>[\w\-]+)/aws4_request,\s*SignedHeaders=(?P<SignatureHeaders>[\w\-\;]+),\s*Signature=(?P<Signature>[abcdef0123456789]{64})`)
 	V4CredentialScopeRegexp = regexp.MustCompile(`(?P<AccessKeyId>.{3,20})/(?P<Date>\d{8})/(?P<Region>[\w\-]+)/(?P<Service>[\w\-]+)/aws4_request`)
@@ -219,10 +225,11 @@ func (ctx *verificationCtx) trimAll(str string) string {
 	return buf.String()
 }
 
+// getInsensitiveHeader returns the values of a header, comma separated as they are signed
 func getInsensitiveHeader(r *http.Request, headerName string) string {
 	for k, v := range r.Header {
 		if strings.EqualFold(k, headerName) {
-			return v[0]
+			return strings.Join(v, ",")
 		}
 	}
 	return ""
@@ -304,13 +311,17 @@ func createSignature(key, dateStamp, region, service string) []byte {
 
 func (ctx *verificationCtx) buildSignedString(canonicalRequest string) (string, error) {
 	// Step 2: Create string to sign
-	algorithm := V4authHeaderPrefix
 	credentialScope := strings.Join([]string{
 		ctx.AuthValue.Date,
 		ctx.AuthValue.Region,
 		ctx.AuthValue.Service,
 		v4scopeTerminator,
 	}, "/")
+	return ctx.buildStringToSign(V4authHeaderPrefix, credentialScope, canonicalRequest)
+}
+
+// buildStringToSign builds the string signed by algorithm for canonicalRequest in credentialScope
+func (ctx *verificationCtx) buildStringToSign(algorithm, credentialScope, canonicalRequest string) (string, error) {
 	amzDate, err := ctx.getAmzDate()
 	if err != nil {
 		return "", err
@@ -328,7 +339,16 @@ func (ctx *verificationCtx) buildSignedString(canonicalRequest string) (string,
 
 func (ctx *verificationCtx) isStreaming() bool {
 	payloadHash := ctx.payloadHash()
-	return strings.EqualFold(payloadHash, v4StreamingPayloadHash)
+	return strings.EqualFold(payloadHash, v4StreamingPayloadHash) ||
+		strings.EqualFold(payloadHash, v4StreamingTrailerPayloadHash) ||
+		strings.EqualFold(payloadHash, v4StreamingUnsignedTrailerPayloadHash)
+}
+
+// hasTrailer returns true if trailing headers follow the streaming payload
+func (ctx *verificationCtx) hasTrailer() bool {
+	payloadHash := ctx.payloadHash()
+	return strings.EqualFold(payloadHash, v4StreamingTrailerPayloadHash) ||
+		strings.EqualFold(payloadHash, v4StreamingUnsignedTrailerPayloadHash)
 }
 
 func (ctx *verificationCtx) isUnsigned() bool {
@@ -362,6 +382,14 @@ func (ctx *verificationCtx) reader(reader io.ReadCloser, creds *model.Credential
 		if err != nil {
 			return nil, err
 		}
+		chunkReader.signed = !strings.EqualFold(ctx.payloadHash(), v4StreamingUnsignedTrailerPayloadHash)
+		if ctx.hasTrailer() {
+			// trailing headers are set on the request once its body is read, like HTTP trailers
+			if ctx.Request.Trailer == nil {
+				ctx.Request.Trailer = make(http.Header)
+			}
+			chunkReader.trailer = ctx.Request.Trailer
+		}
 		return chunkReader, nil
 	}
 
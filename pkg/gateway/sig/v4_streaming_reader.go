@@ -26,6 +26,7 @@ import (
 	"errors"
 	"hash"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -37,6 +38,8 @@ import (
 const (
 	emptySHA256            = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" //nolint:gosec
 	signV4ChunkedAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"                                         //nolint:gosec
+	signV4TrailerAlgorithm = "AWS4-HMAC-SHA256-TRAILER"                                         //nolint:gosec
+	amzTrailerSignature    = "x-amz-trailer-signature"
 	SlashSeparator         = "/"
 )
 
@@ -70,6 +73,17 @@ func getChunkSignature(cred *model.Credential, seedSignature string, region stri
 	return newSignature
 }
 
+// getTrailerSignature - get the signature of the trailing headers.
+func getTrailerSignature(cred *model.Credential, seedSignature string, region string, service string, date time.Time, hashedTrailer string) string {
+	stringToSign := signV4TrailerAlgorithm + "\n" +
+		date.Format(v4timeFormat) + "\n" +
+		getScope(date, region, service) + "\n" +
+		seedSignature + "\n" +
+		hashedTrailer
+	signingKey := createSignature(cred.SecretAccessKey, date.Format(v4shortTimeFormat), region, service)
+	return hex.EncodeToString(sign(signingKey, stringToSign))
+}
+
 const maxLineLength = 4 * 1024
 
 var (
@@ -89,7 +103,7 @@ var (
 //
 // NewChunkedReader is not needed by normal applications. The http package
 // automatically decodes chunking when reading response bodies.
-func newSignV4ChunkedReader(reader *bufio.Reader, amzDate string, auth V4Auth, creds *model.Credential) (io.ReadCloser, error) {
+func newSignV4ChunkedReader(reader *bufio.Reader, amzDate string, auth V4Auth, creds *model.Credential) (*s3ChunkedReader, error) {
 	seedDate, err := time.Parse(v4timeFormat, amzDate)
 	if err != nil {
 		return nil, err
@@ -103,6 +117,7 @@ func newSignV4ChunkedReader(reader *bufio.Reader, amzDate string, auth V4Auth, c
 		service:           auth.Service,
 		chunkSHA256Writer: sha256.New(),
 		state:             readChunkHeader,
+		signed:            true,
 	}, nil
 }
 
@@ -121,6 +136,10 @@ type s3ChunkedReader struct {
 	chunkSHA256Writer hash.Hash // Calculates sha256 of chunk data.
 	n                 uint64    // Unread bytes in chunk
 	err               error
+	// signed is false for unsigned streaming payloads, whose chunks carry no signature
+	signed bool
+	// trailer receives the trailing headers following the last chunk, nil if the payload has none
+	trailer http.Header
 }
 
 // Read chunk reads the chunk token signature portion.
@@ -150,6 +169,7 @@ const (
 	readChunkTrailer
 	readChunk
 	verifyChunk
+	readTrailer
 	eofChunk
 )
 
@@ -164,6 +184,8 @@ func (cs chunkState) String() string {
 		stateString = "readChunk"
 	case verifyChunk:
 		stateString = "verifyChunk"
+	case readTrailer:
+		stateString = "readTrailer"
 	case eofChunk:
 		stateString = "eofChunk"
 	}
@@ -184,6 +206,10 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 			// If we're at the end of a chunk.
 			if cr.n == 0 && cr.err == io.EOF {
 				cr.state = readChunkTrailer
+				if cr.trailer != nil {
+					// trailing headers follow the last chunk header
+					cr.state = verifyChunk
+				}
 				cr.lastChunk = true
 				continue
 			}
@@ -234,28 +260,84 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 				continue
 			}
 		case verifyChunk:
-			// Calculate the hashed chunk.
-			hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
-			// Calculate the chunk signature.
-			newSignature := getChunkSignature(cr.cred, cr.seedSignature, cr.region, cr.service, cr.seedDate, hashedChunk)
-			if !Equal([]byte(cr.chunkSignature), []byte(newSignature)) {
-				return 0, gwerrors.ErrSignatureDoesNotMatch
+			if cr.signed {
+				// Calculate the hashed chunk.
+				hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
+				// Calculate the chunk signature.
+				newSignature := getChunkSignature(cr.cred, cr.seedSignature, cr.region, cr.service, cr.seedDate, hashedChunk)
+				if !Equal([]byte(cr.chunkSignature), []byte(newSignature)) {
+					return 0, gwerrors.ErrSignatureDoesNotMatch
+				}
+				// Newly calculated signature becomes the seed for the next chunk
+				// this follows the chaining.
+				cr.seedSignature = newSignature
 			}
-			// Newly calculated signature becomes the seed for the next chunk
-			// this follows the chaining.
-			cr.seedSignature = newSignature
 			cr.chunkSHA256Writer.Reset()
-			if cr.lastChunk {
+			switch {
+			case cr.lastChunk && cr.trailer != nil:
+				cr.state = readTrailer
+			case cr.lastChunk:
 				cr.state = eofChunk
-			} else {
+			default:
 				cr.state = readChunkHeader
 			}
+		case readTrailer:
+			cr.err = cr.readTrailer()
+			if cr.err != nil {
+				return 0, cr.err
+			}
+			cr.state = eofChunk
 		case eofChunk:
 			return n, io.EOF
 		}
 	}
 }
 
+// readTrailer reads the trailing headers following the last chunk, up to an empty line, and verifies their
+// signature on signed payloads.
+func (cr *s3ChunkedReader) readTrailer() error {
+	var canonicalTrailer strings.Builder
+	var trailerSignature string
+	for {
+		line, err := cr.reader.ReadSlice('\n')
+		if errors.Is(err, io.EOF) && len(trimTrailingWhitespace(line)) == 0 {
+			// tolerate a missing empty line at the end of the trailer
+			break
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return errLineTooLong
+		}
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		line = trimTrailingWhitespace(line)
+		if len(line) == 0 {
+			break
+		}
+		name, value, ok := strings.Cut(string(line), ":")
+		if !ok {
+			return errMalformedEncoding
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if name == amzTrailerSignature {
+			trailerSignature = value
+			continue
+		}
+		cr.trailer.Set(name, value)
+		canonicalTrailer.WriteString(name + ":" + value + "\n")
+	}
+	if !cr.signed {
+		return nil
+	}
+	hashedTrailer := sha256.Sum256([]byte(canonicalTrailer.String()))
+	newSignature := getTrailerSignature(cr.cred, cr.seedSignature, cr.region, cr.service, cr.seedDate, hex.EncodeToString(hashedTrailer[:]))
+	if !Equal([]byte(trailerSignature), []byte(newSignature)) {
+		return gwerrors.ErrSignatureDoesNotMatch
+	}
+	return nil
+}
+
 // readCRLF - check if reader only has '\r\n' CRLF character.
 // returns malformed encoding if it doesn't.
 func readCRLF(reader io.Reader) error {
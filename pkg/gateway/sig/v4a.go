@@ -0,0 +1,233 @@
+package sig
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/gateway/errors"
+)
+
+// SigV4A signs requests with an ECDSA P-256 key derived from the secret access key, for a set
+// of regions instead of a single region.  It is used by S3 Multi-Region Access Point clients.
+const (
+	V4aAuthHeaderPrefix = "AWS4-ECDSA-P256-SHA256"
+	V4aRegionSetHeader  = "X-Amz-Region-Set"
+
+	v4aStreamingPayloadHash        = "STREAMING-AWS4-ECDSA-P256-SHA256-PAYLOAD"
+	v4aStreamingTrailerPayloadHash = "STREAMING-AWS4-ECDSA-P256-SHA256-PAYLOAD-TRAILER"
+
+	// v4aKeyPrefix prefixes the secret access key when deriving the signing key
+	v4aKeyPrefix = "AWS4A"
+)
+
+var (
+	V4aAuthHeaderRegexp      = regexp.MustCompile(`AWS4-ECDSA-P256-SHA256 Credential=(?P<AccessKeyId>.{3,20})/(?P<Date>\d{8})/(?P<Service>[\w\-]+)/aws4_request,\s*SignedHeaders=(?P<SignatureHeaders>[\w\-\;]+),\s*Signature=(?P<Signature>[abcdef0123456789]+)`)
+	V4aCredentialScopeRegexp = regexp.MustCompile(`(?P<AccessKeyId>.{3,20})/(?P<Date>\d{8})/(?P<Service>[\w\-]+)/aws4_request`)
+
+	ErrV4aKeyDerivation = fmt.Errorf("derive SigV4A key: %w", errors.ErrInternalError)
+)
+
+type V4aAuth struct {
+	V4Auth
+	// RegionSet is the regions the request is signed for, each possibly a wildcard pattern
+	RegionSet []string
+}
+
+func parseRegionSet(regionSet string) []string {
+	regions := strings.Split(regionSet, ",")
+	for i := range regions {
+		regions[i] = strings.TrimSpace(regions[i])
+	}
+	return regions
+}
+
+func ParseV4aAuthContext(r *http.Request) (V4aAuth, error) {
+	var ctx V4aAuth
+
+	// start by trying to extract the data from the Authorization header
+	headerValue := r.Header.Get(V4authHeaderName)
+	if len(headerValue) > 0 {
+		match := V4aAuthHeaderRegexp.FindStringSubmatch(headerValue)
+		if len(match) == 0 {
+			return ctx, ErrHeaderMalformed
+		}
+		result := make(map[string]string)
+		for i, name := range V4aAuthHeaderRegexp.SubexpNames() {
+			if i != 0 && name != "" {
+				result[name] = match[i]
+			}
+		}
+		regionSet := r.Header.Get(V4aRegionSetHeader)
+		if regionSet == "" {
+			return ctx, ErrHeaderMalformed
+		}
+		ctx.AccessKeyID = result["AccessKeyId"]
+		ctx.Date = result["Date"]
+		ctx.Service = result["Service"]
+		ctx.Signature = result["Signature"]
+		ctx.RegionSet = parseRegionSet(regionSet)
+
+		signatureHeaders := result["SignatureHeaders"]
+		ctx.SignedHeaders = splitHeaders(signatureHeaders)
+		ctx.SignedHeadersString = signatureHeaders
+		return ctx, nil
+	}
+
+	// otherwise, see if we have all the required query parameters
+	query := r.URL.Query()
+	algorithm := query.Get("X-Amz-Algorithm")
+	if len(algorithm) == 0 || !strings.EqualFold(algorithm, V4aAuthHeaderPrefix) {
+		return ctx, errors.ErrInvalidQuerySignatureAlgo
+	}
+	credentialScope := query.Get("X-Amz-Credential")
+	if len(credentialScope) == 0 {
+		return ctx, errors.ErrMissingCredTag
+	}
+	credsMatch := V4aCredentialScopeRegexp.FindStringSubmatch(credentialScope)
+	if len(credsMatch) == 0 {
+		return ctx, errors.ErrCredMalformed
+	}
+	credsResult := make(map[string]string)
+	for i, name := range V4aCredentialScopeRegexp.SubexpNames() {
+		if i != 0 && name != "" {
+			credsResult[name] = credsMatch[i]
+		}
+	}
+	regionSet := query.Get(V4aRegionSetHeader)
+	if regionSet == "" {
+		return ctx, errors.ErrCredMalformed
+	}
+	ctx.AccessKeyID = credsResult["AccessKeyId"]
+	ctx.Date = credsResult["Date"]
+	ctx.Service = credsResult["Service"]
+	ctx.RegionSet = parseRegionSet(regionSet)
+
+	ctx.SignedHeadersString = query.Get("X-Amz-SignedHeaders")
+	ctx.SignedHeaders = splitHeaders(ctx.SignedHeadersString)
+	ctx.Signature = query.Get(v4SignatureHeader)
+	return ctx, nil
+}
+
+// deriveV4aKey derives the ECDSA P-256 key pair that SigV4A signs with from the credentials,
+// following FIPS 186-4 appendix B.4.2 with a NIST SP 800-108 counter mode HMAC-SHA256 KDF, as
+// AWS SDKs do.
+func deriveV4aKey(accessKeyID, secretAccessKey string) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	nMinusTwo := new(big.Int).Sub(curve.Params().N, big.NewInt(2)).FillBytes(make([]byte, 32))
+	inputKey := []byte(v4aKeyPrefix + secretAccessKey)
+	for counter := 1; counter <= 0xff; counter++ {
+		key := v4aKDF(inputKey, []byte(V4aAuthHeaderPrefix), append([]byte(accessKeyID), byte(counter)), curve.Params().BitSize)
+		if bytes.Compare(key, nMinusTwo) < 0 {
+			d := new(big.Int).SetBytes(key)
+			d.Add(d, big.NewInt(1))
+			x, y := curve.ScalarBaseMult(d.Bytes())
+			return &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: curve, X: x, Y: y}, D: d}, nil
+		}
+	}
+	return nil, ErrV4aKeyDerivation
+}
+
+// v4aKDF is the NIST SP 800-108 KDF in counter mode with HMAC-SHA256
+func v4aKDF(key, label, context []byte, bitLen int) []byte {
+	var fixedInput bytes.Buffer
+	fixedInput.Write(label)
+	fixedInput.WriteByte(0)
+	fixedInput.Write(context)
+	_ = binary.Write(&fixedInput, binary.BigEndian, int32(bitLen))
+
+	var output []byte
+	h := hmac.New(sha256.New, key)
+	for i := int32(1); len(output) < bitLen/8; i++ {
+		h.Reset()
+		_ = binary.Write(h, binary.BigEndian, i)
+		h.Write(fixedInput.Bytes())
+		output = h.Sum(output)
+	}
+	return output[:bitLen/8]
+}
+
+func V4aVerify(auth V4aAuth, credentials *model.Credential, r *http.Request) error {
+	ctx := &verificationCtx{
+		Request:   r,
+		Query:     r.URL.Query(),
+		AuthValue: auth.V4Auth,
+	}
+	payloadHash := ctx.payloadHash()
+	if strings.EqualFold(payloadHash, v4aStreamingPayloadHash) || strings.EqualFold(payloadHash, v4aStreamingTrailerPayloadHash) {
+		return errors.ErrNotImplemented
+	}
+
+	canonicalRequest := ctx.buildCanonicalRequest()
+	credentialScope := strings.Join([]string{auth.Date, auth.Service, v4scopeTerminator}, "/")
+	stringToSign, err := ctx.buildStringToSign(V4aAuthHeaderPrefix, credentialScope, canonicalRequest)
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(auth.Signature)
+	if err != nil {
+		return errors.ErrSignatureDoesNotMatch
+	}
+	key, err := deriveV4aKey(credentials.AccessKeyID, credentials.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(stringToSign))
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], signature) {
+		return errors.ErrSignatureDoesNotMatch
+	}
+
+	// wrap body with verifier
+	reader, err := ctx.reader(r.Body, credentials)
+	if err != nil {
+		return err
+	}
+	r.Body = reader
+
+	// update to decoded content length
+	contentLength, err := ctx.contentLength()
+	if err != nil {
+		return err
+	}
+	r.ContentLength = contentLength
+	return nil
+}
+
+type V4aAuthenticator struct {
+	request *http.Request
+	sigCtx  V4aAuth
+}
+
+func (a *V4aAuthenticator) Parse() (SigContext, error) {
+	sigCtx, err := ParseV4aAuthContext(a.request)
+	if err != nil {
+		return nil, err
+	}
+	a.sigCtx = sigCtx
+	return a.sigCtx, nil
+}
+
+func (a *V4aAuthenticator) String() string {
+	return "sigv4a"
+}
+
+func (a *V4aAuthenticator) Verify(creds *model.Credential) error {
+	return V4aVerify(a.sigCtx, creds, a.request)
+}
+
+func NewV4aAuthenticator(r *http.Request) *V4aAuthenticator {
+	return &V4aAuthenticator{
+		request: r,
+		sigCtx:  V4aAuth{},
+	}
+}
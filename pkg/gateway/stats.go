@@ -11,3 +11,10 @@ var requestHistograms = promauto.NewHistogramVec(
 		Help: "request durations for lakeFS storage gateway",
 	},
 	[]string{"operation", "code"})
+
+var shedRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_shed_requests_total",
+		Help: "requests rejected with SlowDown by lakeFS storage gateway load shedding",
+	},
+	[]string{"operation", "reason"})
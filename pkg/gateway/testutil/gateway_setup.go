@@ -15,6 +15,9 @@ import (
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/gateway"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/gateway/session"
+	"github.com/treeverse/lakefs/pkg/gateway/sig"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
 	_ "github.com/treeverse/lakefs/pkg/kv/mem"
@@ -63,7 +66,11 @@ func GetBasicHandler(t *testing.T, authService *FakeAuthService, repoName string
 	_, err = c.CreateRepository(ctx, repoName, storageNamespace, "main", false)
 	testutil.Must(t, err)
 
-	handler := gateway.NewHandler(authService.Region, c, multipartTracker, blockAdapter, authService, []string{authService.BareDomain}, &stats.NullCollector{}, upload.DefaultPathProvider, nil, config.DefaultLoggingAuditLogLevel, true, false)
+	var gatewayAuth auth.GatewayService = authService
+	if authService.UntrackedSessions {
+		gatewayAuth = untrackedAuthService{GatewayService: authService}
+	}
+	handler := gateway.NewHandler(authService.Region, c, multipartTracker, blockAdapter, gatewayAuth, nil, nil, []string{authService.BareDomain}, &stats.NullCollector{}, upload.DefaultPathProvider, nil, config.DefaultLoggingAuditLogLevel, true, false, operations.UploadLimits{}, sig.SigningScope{Regions: []string{"*"}, Services: []string{"*"}}, 0, gateway.OverloadLimits{}, session.NewSessions(store, time.Hour))
 
 	return handler, &Dependencies{
 		blocks:  blockAdapter,
@@ -72,12 +79,29 @@ func GetBasicHandler(t *testing.T, authService *FakeAuthService, repoName string
 	}
 }
 
+// Catalog returns the catalog of the handler
+func (d *Dependencies) Catalog() *catalog.Catalog {
+	return d.catalog
+}
+
+// FakeAuthService authenticates a single access key. Of the session manager it only records credentials use.
 type FakeAuthService struct {
+	auth.SessionManager `json:"-"`
+
 	BareDomain      string `json:"bare_domain"`
 	AccessKeyID     string `json:"access_key_id"`
 	SecretAccessKey string `json:"access_secret_key"`
 	UserID          string `json:"user_id"`
 	Region          string `json:"region"`
+	// AuthorizeFn authorizes requests when set, all requests are allowed otherwise
+	AuthorizeFn func(ctx context.Context, req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) `json:"-"`
+	// UntrackedSessions serves the gateway with an auth service that does not track sessions, like the external auth API
+	UntrackedSessions bool `json:"-"`
+}
+
+// untrackedAuthService is a gateway auth service that is not a session manager
+type untrackedAuthService struct {
+	auth.GatewayService
 }
 
 func (m *FakeAuthService) GetCredentials(_ context.Context, accessKey string) (*model.Credential, error) {
@@ -91,6 +115,10 @@ func (m *FakeAuthService) GetCredentials(_ context.Context, accessKey string) (*
 	return aCred, nil
 }
 
+func (m *FakeAuthService) RecordCredentialsUse(context.Context, string, string) error {
+	return nil
+}
+
 func (m *FakeAuthService) GetUser(_ context.Context, _ string) (*model.User, error) {
 	return &model.User{
 		CreatedAt: time.Now(),
@@ -98,6 +126,9 @@ func (m *FakeAuthService) GetUser(_ context.Context, _ string) (*model.User, err
 	}, nil
 }
 
-func (m *FakeAuthService) Authorize(_ context.Context, _ *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+func (m *FakeAuthService) Authorize(ctx context.Context, req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	if m.AuthorizeFn != nil {
+		return m.AuthorizeFn(ctx, req)
+	}
 	return &auth.AuthorizationResponse{Allowed: true}, nil
 }
@@ -0,0 +1,37 @@
+package adapteroverride
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "block_adapter_override"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetOverride returns the block adapter override of the repository. The override is read from the settings cache,
+// as it is consulted on every request for the repository.
+func (m *Manager) GetOverride(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BlockAdapterOverride, error) {
+	override := &graveler.BlockAdapterOverride{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, override)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return override, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+func (m *Manager) SetOverride(ctx context.Context, repository *graveler.RepositoryRecord, override *graveler.BlockAdapterOverride) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, override, nil)
+}
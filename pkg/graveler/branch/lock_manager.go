@@ -0,0 +1,84 @@
+package branch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const LockSettingKey = "locked_branches"
+
+// maxLockUpdateTries is the number of attempts to update the branch locks when they are concurrently modified
+const maxLockUpdateTries = 5
+
+type LockManager struct {
+	settingManager *settings.Manager
+}
+
+func NewLockManager(settingManager *settings.Manager) *LockManager {
+	return &LockManager{settingManager: settingManager}
+}
+
+func (m *LockManager) GetLocks(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BranchLocks, error) {
+	locks := &graveler.BranchLocks{}
+	err := m.settingManager.Get(ctx, repository, LockSettingKey, locks)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return locks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return locks, nil
+}
+
+func (m *LockManager) GetLock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchLock, error) {
+	locks, err := m.GetLocks(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	return locks.Branches[branchID.String()], nil
+}
+
+func (m *LockManager) Lock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, lock *graveler.BranchLock) error {
+	return m.update(ctx, repository, func(locks *graveler.BranchLocks) error {
+		if locks.Branches == nil {
+			locks.Branches = make(map[string]*graveler.BranchLock)
+		}
+		locks.Branches[branchID.String()] = lock
+		return nil
+	})
+}
+
+func (m *LockManager) Unlock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	return m.update(ctx, repository, func(locks *graveler.BranchLocks) error {
+		if _, ok := locks.Branches[branchID.String()]; !ok {
+			return graveler.ErrBranchLockNotFound
+		}
+		delete(locks.Branches, branchID.String())
+		return nil
+	})
+}
+
+// update applies fn to the latest branch locks of the repository and saves the result, retrying when the locks
+// were modified concurrently. Saved locks count their revision: empty locks would have the checksum of no locks,
+// that the settings manager only saves when no locks were ever saved.
+func (m *LockManager) update(ctx context.Context, repository *graveler.RepositoryRecord, fn func(locks *graveler.BranchLocks) error) error {
+	for try := 0; try < maxLockUpdateTries; try++ {
+		locks := &graveler.BranchLocks{}
+		checksum, err := m.settingManager.GetLatest(ctx, repository, LockSettingKey, locks)
+		if err != nil {
+			return err
+		}
+		if err := fn(locks); err != nil {
+			return err
+		}
+		locks.Revision++
+		err = m.settingManager.Save(ctx, repository, LockSettingKey, locks, checksum)
+		if !errors.Is(err, graveler.ErrPreconditionFailed) {
+			return err
+		}
+	}
+	return graveler.ErrTooManyTries
+}
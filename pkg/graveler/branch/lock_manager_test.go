@@ -0,0 +1,60 @@
+package branch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/branch"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func TestLockAndUnlock(t *testing.T) {
+	ctx := context.Background()
+	m := prepareLockTest(t, ctx)
+
+	lock, err := m.GetLock(ctx, repository, "main")
+	require.NoError(t, err)
+	require.Nil(t, lock)
+
+	err = m.Lock(ctx, repository, "main", &graveler.BranchLock{Reason: "freeze", LockedBy: "admin"})
+	require.NoError(t, err)
+	lock, err = m.GetLock(ctx, repository, "main")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	require.Equal(t, "freeze", lock.GetReason())
+	require.Equal(t, "admin", lock.GetLockedBy())
+	lock, err = m.GetLock(ctx, repository, "dev")
+	require.NoError(t, err)
+	require.Nil(t, lock)
+
+	err = m.Unlock(ctx, repository, "main")
+	require.NoError(t, err)
+	lock, err = m.GetLock(ctx, repository, "main")
+	require.NoError(t, err)
+	require.Nil(t, lock)
+
+	err = m.Unlock(ctx, repository, "main")
+	require.ErrorIs(t, err, graveler.ErrBranchLockNotFound)
+
+	// releasing the last lock leaves no locks, which are locked again
+	err = m.Lock(ctx, repository, "main", &graveler.BranchLock{Reason: "freeze again", LockedBy: "admin"})
+	require.NoError(t, err)
+	lock, err = m.GetLock(ctx, repository, "main")
+	require.NoError(t, err)
+	require.Equal(t, "freeze again", lock.GetReason())
+}
+
+func prepareLockTest(t *testing.T, ctx context.Context) *branch.LockManager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(repository, nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	m := settings.NewManager(refManager, kvStore, settings.WithCache(cache.NoCache))
+	return branch.NewLockManager(m)
+}
@@ -0,0 +1,36 @@
+package bucketnotification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "bucket_notifications"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetNotifications returns the S3 bucket notification configurations of the repository, empty if none were set.
+func (m *Manager) GetNotifications(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BucketNotifications, error) {
+	notifications := &graveler.BucketNotifications{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, notifications)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return notifications, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (m *Manager) SetNotifications(ctx context.Context, repository *graveler.RepositoryRecord, notifications *graveler.BucketNotifications) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, notifications, nil)
+}
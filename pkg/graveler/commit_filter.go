@@ -0,0 +1,83 @@
+package graveler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+// CommitFilter selects the uncommitted changes included in a partial commit: changes to one of Paths, or to a key
+// under one of Prefixes.
+type CommitFilter struct {
+	Paths    []Key
+	Prefixes []Key
+}
+
+// Match returns true if the change to key is selected by the filter
+func (f *CommitFilter) Match(key Key) bool {
+	for _, path := range f.Paths {
+		if bytes.Equal(key, path) {
+			return true
+		}
+	}
+	for _, prefix := range f.Prefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// commitFilterIterator iterates over the values of a ValueIterator selected by a CommitFilter
+type commitFilterIterator struct {
+	ValueIterator
+	filter *CommitFilter
+}
+
+func newCommitFilterIterator(it ValueIterator, filter *CommitFilter) ValueIterator {
+	return &commitFilterIterator{ValueIterator: it, filter: filter}
+}
+
+func (it *commitFilterIterator) Next() bool {
+	for it.ValueIterator.Next() {
+		if it.filter.Match(it.ValueIterator.Value().Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// restageUnselected writes the changes in the sealed tokens of branch that filter does not select to its staging
+// token, so that they stay uncommitted once the sealed tokens are dropped. Changes staged after the tokens were
+// sealed are newer, and are kept.
+func (g *Graveler) restageUnselected(ctx context.Context, branch *Branch, filter *CommitFilter) error {
+	it, err := g.sealedTokensIterator(ctx, branch, 0)
+	if errors.Is(err, ErrNoChanges) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		record := it.Value()
+		if filter.Match(record.Key) {
+			continue
+		}
+		value := record.Value
+		if value == nil {
+			// tombstone
+			value = new(Value)
+		}
+		err := g.StagingManager.Update(ctx, branch.StagingToken, record.Key, func(currentValue *Value) (*Value, error) {
+			if currentValue != nil {
+				return nil, ErrSkipValueUpdate
+			}
+			return value, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
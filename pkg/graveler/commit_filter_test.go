@@ -0,0 +1,33 @@
+package graveler
+
+import (
+	"testing"
+)
+
+func TestCommitFilter_Match(t *testing.T) {
+	filter := &CommitFilter{
+		Paths:    []Key{Key("a/b")},
+		Prefixes: []Key{Key("c/"), Key("d")},
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "a/b", want: true},
+		{key: "a/bc", want: false},
+		{key: "a/b/c", want: false},
+		{key: "c/", want: true},
+		{key: "c/x/y", want: true},
+		{key: "c", want: false},
+		{key: "d", want: true},
+		{key: "dd/x", want: true},
+		{key: "e", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := filter.Match(Key(tt.key)); got != tt.want {
+				t.Errorf("Match(%s) = %t, want %t", tt.key, got, tt.want)
+			}
+		})
+	}
+}
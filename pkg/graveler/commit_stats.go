@@ -0,0 +1,182 @@
+package graveler
+
+import (
+	"context"
+	"fmt"
+)
+
+// CommitStats aggregates the entries of a commit. Deltas are relative to the first parent of the commit, or to
+// an empty commit when it has no parents.
+type CommitStats struct {
+	ObjectCount      int64
+	SizeBytes        int64
+	ObjectCountDelta int64
+	SizeBytesDelta   int64
+}
+
+// ValueSizeFunc returns the size in bytes of the object described by value
+type ValueSizeFunc func(value *Value) (int64, error)
+
+func CommitStatsFromProto(pb *CommitStatsData) *CommitStats {
+	if pb == nil {
+		return nil
+	}
+	return &CommitStats{
+		ObjectCount:      pb.ObjectCount,
+		SizeBytes:        pb.SizeBytes,
+		ObjectCountDelta: pb.ObjectCountDelta,
+		SizeBytesDelta:   pb.SizeBytesDelta,
+	}
+}
+
+func ProtoFromCommitStats(s *CommitStats) *CommitStatsData {
+	if s == nil {
+		return nil
+	}
+	return &CommitStatsData{
+		ObjectCount:      s.ObjectCount,
+		SizeBytes:        s.SizeBytes,
+		ObjectCountDelta: s.ObjectCountDelta,
+		SizeBytesDelta:   s.SizeBytesDelta,
+	}
+}
+
+// recordCommitStats computes the stats of the new commit commitID in the background, outside the branch update that
+// added it, and records them on the commit. Deltas are computed from the difference between the meta-ranges of the
+// commit and its first parent, and totals are added to those of the parent. Totals are computed by listing all
+// entries of the commit only when its parent has no stats.
+// Failing to compute stats is logged and leaves the commit without stats, as does a restart before they are
+// recorded or background workers too busy to accept the task.
+func (g *Graveler) recordCommitStats(ctx context.Context, repository *RepositoryRecord, commitID CommitID) {
+	if g.ValueSize == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	log := g.log(ctx).WithField("repository", repository.RepositoryID).WithField("commit_id", commitID)
+	task := func() {
+		commit, err := g.RefManager.GetCommit(ctx, repository, commitID)
+		if err != nil {
+			log.WithError(err).Warn("Failed to get commit to compute its stats")
+			return
+		}
+		if commit.Stats != nil {
+			// an existing commit, e.g. the source of a fast-forward merge
+			return
+		}
+		stats, err := g.commitStats(ctx, repository, commit)
+		if err != nil {
+			log.WithError(err).Warn("Failed to compute commit stats")
+			return
+		}
+		if err := g.RefManager.SetCommitStats(ctx, repository, commitID, *stats); err != nil {
+			log.WithError(err).Warn("Failed to record commit stats")
+		}
+	}
+	if g.SubmitBackground == nil {
+		go task()
+		return
+	}
+	if !g.SubmitBackground(task) {
+		log.Warn("Background workers busy, commit left without stats")
+	}
+}
+
+func (g *Graveler) commitStats(ctx context.Context, repository *RepositoryRecord, commit *Commit) (*CommitStats, error) {
+	stats := &CommitStats{}
+	var parent *Commit
+	if len(commit.Parents) > 0 {
+		var err error
+		parent, err = g.RefManager.GetCommit(ctx, repository, commit.Parents[0])
+		if err != nil {
+			return nil, fmt.Errorf("get parent commit: %w", err)
+		}
+		stats.ObjectCountDelta, stats.SizeBytesDelta, err = g.metaRangeDelta(ctx, repository.StorageNamespace, parent.MetaRangeID, commit.MetaRangeID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Stats != nil {
+			stats.ObjectCount = parent.Stats.ObjectCount + stats.ObjectCountDelta
+			stats.SizeBytes = parent.Stats.SizeBytes + stats.SizeBytesDelta
+			return stats, nil
+		}
+	}
+
+	var err error
+	stats.ObjectCount, stats.SizeBytes, err = g.metaRangeTotals(ctx, repository.StorageNamespace, commit.MetaRangeID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		stats.ObjectCountDelta = stats.ObjectCount
+		stats.SizeBytesDelta = stats.SizeBytes
+	}
+	return stats, nil
+}
+
+// metaRangeTotals returns the number and total size of the entries of a meta-range
+func (g *Graveler) metaRangeTotals(ctx context.Context, ns StorageNamespace, metaRangeID MetaRangeID) (int64, int64, error) {
+	if metaRangeID == "" {
+		return 0, 0, nil
+	}
+	it, err := g.CommittedManager.List(ctx, ns, metaRangeID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list meta-range %s: %w", metaRangeID, err)
+	}
+	defer it.Close()
+	var count, size int64
+	for it.Next() {
+		n, err := g.ValueSize(it.Value().Value)
+		if err != nil {
+			return 0, 0, err
+		}
+		count++
+		size += n
+	}
+	return count, size, it.Err()
+}
+
+// metaRangeDelta returns the change in the number and total size of entries from meta-range left to right
+func (g *Graveler) metaRangeDelta(ctx context.Context, ns StorageNamespace, left, right MetaRangeID) (int64, int64, error) {
+	switch {
+	case left == right:
+		return 0, 0, nil
+	case left == "":
+		return g.metaRangeTotals(ctx, ns, right)
+	case right == "":
+		count, size, err := g.metaRangeTotals(ctx, ns, left)
+		return -count, -size, err
+	}
+	it, err := g.CommittedManager.Diff(ctx, ns, left, right)
+	if err != nil {
+		return 0, 0, fmt.Errorf("diff meta-ranges: %w", err)
+	}
+	defer it.Close()
+	var count, size int64
+	for it.Next() {
+		d := it.Value()
+		n, err := g.ValueSize(d.Value)
+		if err != nil {
+			return 0, 0, err
+		}
+		switch d.Type {
+		case DiffTypeAdded:
+			count++
+			size += n
+		case DiffTypeRemoved:
+			count--
+			size -= n
+		case DiffTypeChanged:
+			// the diff holds only the value on the right, get the replaced value from the left
+			leftValue, err := g.CommittedManager.Get(ctx, ns, left, d.Key)
+			if err != nil {
+				return 0, 0, fmt.Errorf("get %s: %w", d.Key, err)
+			}
+			leftSize, err := g.ValueSize(leftValue)
+			if err != nil {
+				return 0, 0, err
+			}
+			size += n - leftSize
+		}
+	}
+	return count, size, it.Err()
+}
@@ -0,0 +1,106 @@
+package committed
+
+import (
+	"bytes"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// directoryIterator iterates over the children of a directory of a metarange: the values directly under its prefix,
+// and a record without a value for each common prefix of the keys under it, up to and including the delimiter. A
+// range whose keys all share one common prefix is listed from its header in the metarange, without reading it.
+type directoryIterator struct {
+	it        Iterator
+	prefix    graveler.Key
+	delimiter graveler.Key
+	value     *graveler.ValueRecord
+	done      bool
+}
+
+// NewDirectoryIterator returns an iterator over the children of the directory prefix of it, separated by delimiter
+func NewDirectoryIterator(it Iterator, prefix, delimiter graveler.Key) graveler.ValueIterator {
+	it.SeekGE(prefix)
+	return &directoryIterator{
+		it:        it,
+		prefix:    prefix,
+		delimiter: delimiter,
+	}
+}
+
+// commonPrefix returns the common prefix of key, a key under the directory, nil if key is directly under it
+func (d *directoryIterator) commonPrefix(key graveler.Key) graveler.Key {
+	i := bytes.Index(key[len(d.prefix):], d.delimiter)
+	if i < 0 {
+		return nil
+	}
+	return bytes.Clone(key[:len(d.prefix)+i+len(d.delimiter)])
+}
+
+// afterDirectory returns true if key is past the keys of the directory
+func (d *directoryIterator) afterDirectory(key graveler.Key) bool {
+	return !bytes.HasPrefix(key, d.prefix) && bytes.Compare(key, d.prefix) > 0
+}
+
+// setCommonPrefix makes commonPrefix the current value, and skips the keys under it
+func (d *directoryIterator) setCommonPrefix(commonPrefix graveler.Key) {
+	d.value = &graveler.ValueRecord{Key: commonPrefix}
+	upperBound := graveler.UpperBoundForPrefix(commonPrefix)
+	if upperBound == nil {
+		d.done = true
+		return
+	}
+	d.it.SeekGE(upperBound)
+}
+
+func (d *directoryIterator) Next() bool {
+	for !d.done && d.it.Next() {
+		record, rng := d.it.Value()
+		if record == nil {
+			minKey := graveler.Key(rng.MinKey)
+			if d.afterDirectory(minKey) {
+				break
+			}
+			// a range inside a single child directory is listed without reading it
+			if bytes.HasPrefix(minKey, d.prefix) {
+				if commonPrefix := d.commonPrefix(minKey); commonPrefix != nil && bytes.HasPrefix(rng.MaxKey, commonPrefix) {
+					d.setCommonPrefix(commonPrefix)
+					return true
+				}
+			}
+			continue
+		}
+		if !bytes.HasPrefix(record.Key, d.prefix) {
+			if d.afterDirectory(record.Key) {
+				break
+			}
+			continue
+		}
+		if commonPrefix := d.commonPrefix(record.Key); commonPrefix != nil {
+			d.setCommonPrefix(commonPrefix)
+			return true
+		}
+		d.value = record
+		return true
+	}
+	d.done = true
+	d.value = nil
+	return false
+}
+
+func (d *directoryIterator) SeekGE(id graveler.Key) {
+	d.done = false
+	d.value = nil
+	d.it.SeekGE(id)
+}
+
+func (d *directoryIterator) Value() *graveler.ValueRecord {
+	return d.value
+}
+
+func (d *directoryIterator) Err() error {
+	return d.it.Err()
+}
+
+func (d *directoryIterator) Close() {
+	d.it.Close()
+}
@@ -0,0 +1,107 @@
+package committed_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/committed"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+)
+
+// readsIterator counts the values read from each range through it
+type readsIterator struct {
+	committed.Iterator
+	reads map[committed.ID]int
+}
+
+func (r *readsIterator) Next() bool {
+	if !r.Iterator.Next() {
+		return false
+	}
+	if v, rng := r.Iterator.Value(); v != nil {
+		r.reads[rng.ID]++
+	}
+	return true
+}
+
+func makeDirectoryIterator() *readsIterator {
+	it := testutil.NewFakeIterator()
+	for _, rng := range []rangeKeys{
+		{Name: "mixed", Keys: makeKeys("a", "t/file", "t/x/1")},
+		{Name: "continued", Keys: makeKeys("t/x/2", "t/x/3")},
+		{Name: "single", Keys: makeKeys("t/y/1", "t/y/2", "t/y/3")},
+		{Name: "last", Keys: makeKeys("t/z", "u/1")},
+	} {
+		r := makeRange(rng)
+		r.ID = rng.Name
+		it.AddRange(&r)
+		for _, key := range rng.Keys {
+			it.AddValueRecords(&graveler.ValueRecord{Key: key, Value: &graveler.Value{Identity: key}})
+		}
+	}
+	return &readsIterator{Iterator: it, reads: make(map[committed.ID]int)}
+}
+
+func listChildren(t *testing.T, it graveler.ValueIterator) []string {
+	t.Helper()
+	var children []string
+	for it.Next() {
+		v := it.Value()
+		child := v.Key.String()
+		if v.Value == nil {
+			child += " (prefix)"
+		}
+		children = append(children, child)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return children
+}
+
+func TestDirectoryIterator(t *testing.T) {
+	t.Run("children", func(t *testing.T) {
+		reads := makeDirectoryIterator()
+		it := committed.NewDirectoryIterator(reads, graveler.Key("t/"), graveler.Key("/"))
+		defer it.Close()
+
+		children := listChildren(t, it)
+		if diff := deep.Equal(children, []string{"t/file", "t/x/ (prefix)", "t/y/ (prefix)", "t/z"}); diff != nil {
+			t.Errorf("children diff %s", diff)
+		}
+		if diff := deep.Equal(reads.reads, map[committed.ID]int{"mixed": 2, "last": 2}); diff != nil {
+			t.Errorf("values read by range diff %s", diff)
+		}
+	})
+
+	t.Run("root", func(t *testing.T) {
+		it := committed.NewDirectoryIterator(makeDirectoryIterator(), graveler.Key(""), graveler.Key("/"))
+		defer it.Close()
+
+		children := listChildren(t, it)
+		if diff := deep.Equal(children, []string{"a", "t/ (prefix)", "u/ (prefix)"}); diff != nil {
+			t.Errorf("children diff %s", diff)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		it := committed.NewDirectoryIterator(makeDirectoryIterator(), graveler.Key("t/"), graveler.Key("/"))
+		defer it.Close()
+
+		it.SeekGE(graveler.Key("t/y0"))
+		children := listChildren(t, it)
+		if diff := deep.Equal(children, []string{"t/z"}); diff != nil {
+			t.Errorf("children diff %s", diff)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		it := committed.NewDirectoryIterator(makeDirectoryIterator(), graveler.Key("m/"), graveler.Key("/"))
+		defer it.Close()
+
+		if children := listChildren(t, it); len(children) != 0 {
+			t.Errorf("listed %v in a missing directory", children)
+		}
+	})
+}
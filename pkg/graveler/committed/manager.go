@@ -61,6 +61,14 @@ func (c *committedManager) List(ctx context.Context, ns graveler.StorageNamespac
 	return NewValueIterator(it), nil
 }
 
+func (c *committedManager) ListDirectory(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID, prefix, delimiter graveler.Key) (graveler.ValueIterator, error) {
+	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, rangeID)
+	if err != nil {
+		return nil, err
+	}
+	return NewDirectoryIterator(it, prefix, delimiter), nil
+}
+
 func (c *committedManager) WriteRange(ctx context.Context, ns graveler.StorageNamespace, it graveler.ValueIterator) (*graveler.RangeInfo, error) {
 	writer, err := c.RangeManager.GetWriter(ctx, Namespace(ns), nil)
 	if err != nil {
@@ -334,6 +342,37 @@ func (c *committedManager) GetRange(ctx context.Context, ns graveler.StorageName
 	return graveler.RangeAddress(uri), err
 }
 
+func (c *committedManager) ListRanges(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var ranges []*graveler.RangeInfo
+	for it.NextRange() {
+		_, rng := it.Value()
+		ranges = append(ranges, &graveler.RangeInfo{
+			ID:                      graveler.RangeID(rng.ID),
+			MinKey:                  graveler.Key(rng.MinKey),
+			MaxKey:                  graveler.Key(rng.MaxKey),
+			Count:                   int(rng.Count),
+			EstimatedRangeSizeBytes: rng.EstimatedSize,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("list ranges of metarange %s: %w", id, err)
+	}
+	return ranges, nil
+}
+
+func (c *committedManager) ListRange(ctx context.Context, ns graveler.StorageNamespace, id graveler.RangeID) (graveler.ValueIterator, error) {
+	it, err := c.RangeManager.NewRangeIterator(ctx, Namespace(ns), ID(id))
+	if err != nil {
+		return nil, fmt.Errorf("open range %s: %w", id, err)
+	}
+	return NewUnmarshalIterator(it), nil
+}
+
 func (c *committedManager) GetRangeIDByKey(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID, key graveler.Key) (graveler.RangeID, error) {
 	if id == "" {
 		return "", graveler.ErrNotFound
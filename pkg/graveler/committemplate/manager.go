@@ -0,0 +1,37 @@
+package committemplate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "commit_template"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetTemplate returns the commit template of the repository. The template is read from the settings cache, as it is
+// consulted on every commit to the repository.
+func (m *Manager) GetTemplate(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CommitTemplate, error) {
+	template := &graveler.CommitTemplate{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, template)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return template, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (m *Manager) SetTemplate(ctx context.Context, repository *graveler.RepositoryRecord, template *graveler.CommitTemplate) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, template, nil)
+}
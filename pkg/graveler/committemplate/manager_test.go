@@ -0,0 +1,59 @@
+package committemplate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/committemplate"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func newRepository(id graveler.RepositoryID) *graveler.RepositoryRecord {
+	return &graveler.RepositoryRecord{
+		RepositoryID: id,
+		Repository: &graveler.Repository{
+			StorageNamespace: "mem://my-storage",
+			DefaultBranchID:  "main",
+		},
+	}
+}
+
+func TestGetTemplateNotSet(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	template, err := m.GetTemplate(ctx, newRepository("example-repo"))
+	require.NoError(t, err)
+	require.False(t, template.GetRequireMessage())
+	require.Empty(t, template.GetRequiredMetadataKeys())
+}
+
+func TestSetTemplate(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+	repository := newRepository("example-repo")
+
+	err := m.SetTemplate(ctx, repository, &graveler.CommitTemplate{
+		RequireMessage:       true,
+		RequiredMetadataKeys: []string{"jira-ticket", "pipeline-run"},
+	})
+	require.NoError(t, err)
+
+	template, err := m.GetTemplate(ctx, repository)
+	require.NoError(t, err)
+	require.True(t, template.GetRequireMessage())
+	require.Equal(t, []string{"jira-ticket", "pipeline-run"}, template.GetRequiredMetadataKeys())
+}
+
+func prepareTest(t *testing.T, ctx context.Context) *committemplate.Manager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(newRepository("example-repo"), nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	return committemplate.NewManager(settings.NewManager(refManager, kvStore))
+}
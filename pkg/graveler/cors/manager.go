@@ -0,0 +1,37 @@
+package cors
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "cors"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetRules returns the CORS rules of the repository. Rules are read from the settings cache, as they are consulted
+// on every cross-origin request.
+func (m *Manager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CORSRules, error) {
+	rules := &graveler.CORSRules{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, rules)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (m *Manager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.CORSRules) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, rules, nil)
+}
@@ -0,0 +1,63 @@
+package cors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/cors"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func newRepository(id graveler.RepositoryID) *graveler.RepositoryRecord {
+	return &graveler.RepositoryRecord{
+		RepositoryID: id,
+		Repository: &graveler.Repository{
+			StorageNamespace: "mem://my-storage",
+			DefaultBranchID:  "main",
+		},
+	}
+}
+
+func TestGetRulesNotSet(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	rules, err := m.GetRules(ctx, newRepository("example-repo"))
+	require.NoError(t, err)
+	require.Empty(t, rules.Rules)
+}
+
+func TestSetRules(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+	repository := newRepository("example-repo")
+
+	err := m.SetRules(ctx, repository, &graveler.CORSRules{
+		Rules: []*graveler.CORSRule{
+			{Id: "web", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"GET", "HEAD"}, MaxAgeSeconds: 600},
+			{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+		},
+	})
+	require.NoError(t, err)
+
+	rules, err := m.GetRules(ctx, repository)
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 2)
+	require.Equal(t, "web", rules.Rules[0].GetId())
+	require.Equal(t, []string{"GET", "HEAD"}, rules.Rules[0].GetAllowedMethods())
+	require.Equal(t, int32(600), rules.Rules[0].GetMaxAgeSeconds())
+
+}
+
+func prepareTest(t *testing.T, ctx context.Context) *cors.Manager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(newRepository("example-repo"), nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	return cors.NewManager(settings.NewManager(refManager, kvStore))
+}
@@ -0,0 +1,37 @@
+package costattribution
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "cost_attribution"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetAttribution returns the cost attribution of the repository. The attribution is read from the settings cache,
+// as it is consulted on every request for the repository.
+func (m *Manager) GetAttribution(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CostAttribution, error) {
+	attribution := &graveler.CostAttribution{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, attribution)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return attribution, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attribution, nil
+}
+
+func (m *Manager) SetAttribution(ctx context.Context, repository *graveler.RepositoryRecord, attribution *graveler.CostAttribution) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, attribution, nil)
+}
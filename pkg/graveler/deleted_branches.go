@@ -0,0 +1,77 @@
+package graveler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListDeletedBranches lists the deleted branches of a repository that did not expire yet
+func (g *Graveler) ListDeletedBranches(ctx context.Context, repository *RepositoryRecord) (DeletedBranchIterator, error) {
+	it, err := g.RefManager.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	return &unexpiredDeletedBranchIterator{DeletedBranchIterator: it, now: time.Now()}, nil
+}
+
+// RestoreBranch creates branchID at the head it had when it was deleted, and forgets the deleted branch.
+// It fails with ErrBranchExists if a branch by the same name was created since.
+func (g *Graveler) RestoreBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) (*Branch, error) {
+	deletedBranch, err := g.RefManager.GetDeletedBranch(ctx, repository, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if deletedBranch.ExpiresAt.Before(time.Now()) {
+		return nil, ErrDeletedBranchNotFound
+	}
+	branch, err := g.CreateBranch(ctx, repository, branchID, Ref(deletedBranch.CommitID), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.RefManager.RemoveDeletedBranch(ctx, repository, branchID); err != nil {
+		// the record expires eventually, restoring the branch again fails as it exists
+		g.log(ctx).WithError(err).
+			WithField("repository", repository.RepositoryID).
+			WithField("branch", branchID).
+			Warn("Failed to remove restored deleted branch")
+	}
+	return branch, nil
+}
+
+func (g *Graveler) DeleteExpiredDeletedBranches(ctx context.Context, repository *RepositoryRecord) error {
+	return g.RefManager.DeleteExpiredDeletedBranches(ctx, repository)
+}
+
+// deletedBranchCommits returns the heads of the deleted branches that can still be restored, so that garbage
+// collection keeps their objects
+func (g *Graveler) deletedBranchCommits(ctx context.Context, repository *RepositoryRecord) ([]CommitID, error) {
+	it, err := g.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var commits []CommitID
+	for it.Next() {
+		commits = append(commits, it.Value().CommitID)
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("list deleted branches: %w", err)
+	}
+	return commits, nil
+}
+
+// unexpiredDeletedBranchIterator skips deleted branches that expired by now
+type unexpiredDeletedBranchIterator struct {
+	DeletedBranchIterator
+	now time.Time
+}
+
+func (i *unexpiredDeletedBranchIterator) Next() bool {
+	for i.DeletedBranchIterator.Next() {
+		if !i.Value().ExpiresAt.Before(i.now) {
+			return true
+		}
+	}
+	return false
+}
@@ -28,6 +28,7 @@ var (
 	ErrInvalidCommitID              = fmt.Errorf("commit id: %w", ErrInvalidValue)
 	ErrInvalidBranchID              = fmt.Errorf("branch id: %w", ErrInvalidValue)
 	ErrInvalidTagID                 = fmt.Errorf("tag id: %w", ErrInvalidValue)
+	ErrInvalidStashID               = fmt.Errorf("stash id: %w", ErrInvalidValue)
 	ErrInvalid                      = errors.New("validation error")
 	ErrInvalidType                  = fmt.Errorf("invalid type: %w", ErrInvalid)
 	ErrInvalidRepositoryID          = fmt.Errorf("repository id: %w", ErrInvalidValue)
@@ -38,10 +39,14 @@ var (
 	ErrRepositoryInDeletion         = errors.New("repository in deletion")
 	ErrBranchNotFound               = fmt.Errorf("branch %w", ErrNotFound)
 	ErrTagNotFound                  = fmt.Errorf("tag %w", ErrNotFound)
+	ErrDeletedBranchNotFound        = fmt.Errorf("deleted branch %w", ErrNotFound)
+	ErrStashNotFound                = fmt.Errorf("stash %w", ErrNotFound)
 	ErrNoChanges                    = wrapError(ErrUserVisible, "no changes")
 	ErrConflictFound                = wrapError(ErrUserVisible, "conflict found")
+	ErrNotFastForward               = wrapError(ErrUserVisible, "not a fast-forward")
 	ErrBranchExists                 = fmt.Errorf("branch already exists: %w", ErrNotUnique)
 	ErrTagAlreadyExists             = fmt.Errorf("tag already exists: %w", ErrNotUnique)
+	ErrStashExists                  = fmt.Errorf("stash already exists: %w", ErrNotUnique)
 	ErrLinkAddressAlreadyExists     = fmt.Errorf("address token already exists: %w", ErrNotUnique)
 	ErrCommitAlreadyExists          = fmt.Errorf("commit already exists: %w", ErrNotUnique)
 	ErrLinkAddressNotFound          = fmt.Errorf("address token %w", ErrNotFound)
@@ -61,6 +66,16 @@ var (
 	ErrSkipValueUpdate              = errors.New("skip value update")
 	ErrImport                       = wrapError(ErrUserVisible, "import error")
 	ErrReadOnlyRepository           = wrapError(ErrUserVisible, "read-only repository")
+	ErrLegalHold                    = wrapError(ErrUserVisible, "under legal hold")
+	ErrBranchLocked                 = wrapError(ErrUserVisible, "locked branch")
+	ErrBranchLockNotFound           = fmt.Errorf("branch lock %w", ErrNotFound)
+	ErrLegalHoldNotFound            = fmt.Errorf("legal hold %w", ErrNotFound)
+	ErrAmendCommitHasChildren       = wrapError(ErrConflictFound, "cannot amend a commit with children")
+	ErrObjectLocked                 = wrapError(ErrUserVisible, "object is locked by its retention")
+	ErrObjectLockNotEnabled         = fmt.Errorf("object lock is not enabled on repository: %w", ErrInvalidValue)
+	ErrObjectLockCannotBeDisabled   = fmt.Errorf("object lock cannot be disabled once enabled: %w", ErrInvalidValue)
+	ErrInvalidKey                   = fmt.Errorf("key: %w", ErrInvalidValue)
+	ErrCommitTemplate               = fmt.Errorf("commit template: %w", ErrInvalidValue)
 )
 
 // wrappedError is an error for wrapping another error while ignoring its message.
@@ -98,6 +113,21 @@ func (e *HookAbortError) Unwrap() error {
 	return e.Err
 }
 
+// KeyValidationError reports a key breaking one of the key validation rules of its repository
+type KeyValidationError struct {
+	Key    Key
+	Rule   string
+	Reason string
+}
+
+func (e *KeyValidationError) Error() string {
+	return fmt.Sprintf("key '%s' breaks %s rule: %s", e.Key, e.Rule, e.Reason)
+}
+
+func (e *KeyValidationError) Unwrap() error {
+	return ErrInvalidKey
+}
+
 // DeleteError single delete error used by DeleteBatch's multierror.Error to report each key that failed
 type DeleteError struct {
 	Key Key
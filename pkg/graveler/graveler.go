@@ -127,6 +127,8 @@ const (
 	MergeStrategyNoneStr     = "default"
 	MergeStrategyDestWinsStr = "dest-wins"
 	MergeStrategySrcWinsStr  = "source-wins"
+	// MergeStrategyFailStr explicitly requests MergeStrategyNone, failing the merge on conflicts
+	MergeStrategyFailStr = "fail"
 
 	MergeStrategyMetadataKey = ".lakefs.merge.strategy"
 )
@@ -174,6 +176,9 @@ type MetaRangeInfo struct {
 type GetOptions struct {
 	// StageOnly fetch key from stage area only. Default (false) will lookup stage and committed data.
 	StageOnly bool
+	// ResolvedCommitID when set, receives the ID of the commit the value was read from. It is left empty when
+	// the value was read from the staging area.
+	ResolvedCommitID *CommitID
 }
 
 type GetOptionsFunc func(opts *GetOptions)
@@ -184,13 +189,28 @@ func WithStageOnly(v bool) GetOptionsFunc {
 	}
 }
 
+func WithResolvedCommitID(dst *CommitID) GetOptionsFunc {
+	return func(opts *GetOptions) {
+		opts.ResolvedCommitID = dst
+	}
+}
+
 type SetOptions struct {
 	IfAbsent bool
+	// IfMatch set makes the operation fail with ErrPreconditionFailed unless the current value of the key has this
+	// identity
+	IfMatch []byte
 	// MaxTries set number of times we try to perform the operation before we fail with BranchWriteMaxTries.
 	// By default, 0 - we try BranchWriteMaxTries
 	MaxTries int
 	// Force set to true will bypass repository read-only protection.
 	Force bool
+	// FastForwardOnly set to true makes a merge move the destination branch to the source commit, without creating a
+	// merge commit. The merge fails unless the destination is an ancestor of the source.
+	FastForwardOnly bool
+	// ExistOK set to true makes creating a branch that already exists at the commit of the source succeed, returning
+	// the existing branch.
+	ExistOK bool
 }
 
 type SetOptionsFunc func(opts *SetOptions)
@@ -201,12 +221,30 @@ func WithIfAbsent(v bool) SetOptionsFunc {
 	}
 }
 
+func WithIfMatch(identity []byte) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.IfMatch = identity
+	}
+}
+
 func WithForce(v bool) SetOptionsFunc {
 	return func(opts *SetOptions) {
 		opts.Force = v
 	}
 }
 
+func WithFastForwardOnly(v bool) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.FastForwardOnly = v
+	}
+}
+
+func WithExistOK(v bool) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.ExistOK = v
+	}
+}
+
 // function/methods receiving the following basic types could assume they passed validation
 
 // StorageNamespace is the URI to the storage location
@@ -232,6 +270,9 @@ type CommitParents []CommitID
 // BranchID is an identifier for a branch
 type BranchID string
 
+// StashID is an identifier for uncommitted changes set aside from a branch
+type StashID string
+
 // CommitID is a content addressable hash representing a Commit object
 type CommitID string
 
@@ -359,14 +400,19 @@ const (
 
 // Commit represents commit metadata (author, time, MetaRangeID)
 type Commit struct {
-	Version      CommitVersion
-	Committer    string
+	Version   CommitVersion
+	Committer string
+	// Author is the authenticated user that created the commit, empty if the commit was not created by a user
+	Author       string
 	Message      string
 	MetaRangeID  MetaRangeID
 	CreationDate time.Time
 	Parents      CommitParents
 	Metadata     Metadata
 	Generation   CommitGeneration
+	// Stats aggregates the entries of the commit, nil for commits created before stats were recorded. Like the
+	// generation, it is not part of the identity of the commit.
+	Stats *CommitStats
 }
 
 func NewCommit() Commit {
@@ -385,9 +431,27 @@ func (c Commit) Identity() []byte {
 	b.MarshalInt64(c.CreationDate.Unix())
 	b.MarshalStringMap(c.Metadata)
 	b.MarshalIdentifiable(c.Parents)
+	if c.Author != "" {
+		// keeps the identity of commits without an author, the author is part of the identity of other commits so
+		// it cannot be changed without changing the commit ID
+		b.MarshalString(c.Author)
+	}
 	return b.Identity()
 }
 
+type commitAuthorContextKey struct{}
+
+// WithCommitAuthor returns a context whose commits record author as their Author
+func WithCommitAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, commitAuthorContextKey{}, author)
+}
+
+// CommitAuthorFromContext returns the author of commits created with ctx, empty if not set
+func CommitAuthorFromContext(ctx context.Context) string {
+	author, _ := ctx.Value(commitAuthorContextKey{}).(string)
+	return author
+}
+
 // CommitRecord holds CommitID with the associated Commit data
 type CommitRecord struct {
 	CommitID CommitID `db:"id"`
@@ -420,6 +484,26 @@ type TagRecord struct {
 	CommitID CommitID
 }
 
+// DeletedBranchRecord holds the head of a deleted branch, which can be restored until it expires
+type DeletedBranchRecord struct {
+	BranchID  BranchID
+	CommitID  CommitID
+	DeletedAt time.Time
+	ExpiresAt time.Time
+}
+
+// StashRecord holds uncommitted changes set aside from a branch, which can be applied to any branch
+type StashRecord struct {
+	StashID StashID
+	// BranchID and CommitID are the branch the changes were set aside from and its head at the time
+	BranchID BranchID
+	CommitID CommitID
+	// StagingTokens hold the changes, newest first
+	StagingTokens []StagingToken
+	Message       string
+	CreationDate  time.Time
+}
+
 // Diff represents a change in value based on key
 type Diff struct {
 	Type         DiffType
@@ -451,6 +535,15 @@ type CommitParams struct {
 	// SourceMetaRange - If exists, use it directly. Fail if branch has uncommitted changes
 	SourceMetaRange *MetaRangeID
 	AllowEmpty      bool
+	// Amend - replace the branch head commit instead of committing on top of it. The head commit must have no
+	// children. Its message and metadata are kept unless set.
+	Amend bool
+	// Filter - commit only the uncommitted changes it selects, keeping the other changes uncommitted. All changes are
+	// committed when nil. Cannot be used with SourceMetaRange.
+	Filter *CommitFilter
+	// Sealed - commit only the changes of the staging tokens already sealed by SealStagingToken, keeping changes
+	// staged after it uncommitted
+	Sealed bool
 }
 
 type GarbageCollectionRunMetadata struct {
@@ -486,6 +579,10 @@ type KeyValueStore interface {
 	// List lists values on repository / ref
 	List(ctx context.Context, repository *RepositoryRecord, ref Ref, batchSize int) (ValueIterator, error)
 
+	// ListDirectory lists the children of the directory prefix of repository / commit, separated by delimiter.
+	// Common prefixes are returned as records with a nil value.
+	ListDirectory(ctx context.Context, repository *RepositoryRecord, commitID CommitID, prefix, delimiter Key) (ValueIterator, error)
+
 	// ListStaging returns ValueIterator for branch staging area. Exposed to be used by X in PrepareGCUncommitted
 	ListStaging(ctx context.Context, branch *Branch, batchSize int) (ValueIterator, error)
 }
@@ -512,6 +609,9 @@ type VersionController interface {
 	// SetRepositoryMetadata sets repository user metadata
 	SetRepositoryMetadata(ctx context.Context, repository *RepositoryRecord, updateFunc RepoMetadataUpdateFunc) error
 
+	// SetRepositoryReadOnly sets whether the repository rejects writes that are not forced
+	SetRepositoryReadOnly(ctx context.Context, repository *RepositoryRecord, readOnly bool) error
+
 	// CreateBranch creates branch on repository pointing to ref
 	CreateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) (*Branch, error)
 
@@ -536,16 +636,50 @@ type VersionController interface {
 	// Log returns an iterator starting at commit ID up to repository root
 	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error)
 
+	// ListCommits returns an iterator over all known commits in the repository, ordered by their commit ID
+	ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error)
+
 	// ListBranches lists branches on repositories
 	ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
 
 	// DeleteBranch deletes branch from repository
 	DeleteBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error
 
+	// ListDeletedBranches lists the deleted branches of a repository that can still be restored
+	ListDeletedBranches(ctx context.Context, repository *RepositoryRecord) (DeletedBranchIterator, error)
+
+	// RestoreBranch re-creates a deleted branch pointing at the commit it pointed at when it was deleted.
+	// Uncommitted changes of the deleted branch are not restored.
+	RestoreBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) (*Branch, error)
+
+	// RenameBranch renames the branch to newBranchID, keeping its commit and uncommitted changes
+	RenameBranch(ctx context.Context, repository *RepositoryRecord, branchID, newBranchID BranchID, opts ...SetOptionsFunc) error
+
+	// StashBranch sets the uncommitted changes of branchID aside as stashID, leaving the branch with no uncommitted
+	// changes. It fails with ErrNoChanges if the branch has no uncommitted changes.
+	StashBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, stashID StashID, message string, opts ...SetOptionsFunc) (*StashRecord, error)
+
+	// ApplyStash stages the changes of stashID on branchID. It fails with ErrConflictFound if the branch has
+	// uncommitted changes to any of the stashed keys. The stash is kept.
+	ApplyStash(ctx context.Context, repository *RepositoryRecord, stashID StashID, branchID BranchID, opts ...SetOptionsFunc) error
+
+	// GetStash returns the stash stashID
+	GetStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) (*StashRecord, error)
+
+	// ListStashes lists the stashes of a repository
+	ListStashes(ctx context.Context, repository *RepositoryRecord) (StashIterator, error)
+
+	// DeleteStash deletes stashID and its changes
+	DeleteStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) error
+
 	// Commit the staged data and returns a commit ID that references that change
 	//   ErrNothingToCommit in case there is no data in stage
 	Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, commitParams CommitParams, opts ...SetOptionsFunc) (CommitID, error)
 
+	// SealStagingToken seals the staging token of the branch, so that a later commit with CommitParams.Sealed
+	// commits the changes staged until now, and changes staged after it are kept uncommitted
+	SealStagingToken(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error
+
 	// CreateCommitRecord creates a commit record in the repository.
 	CreateCommitRecord(ctx context.Context, repository *RepositoryRecord, commitID CommitID, commit Commit, opts ...SetOptionsFunc) error
 
@@ -608,6 +742,10 @@ type VersionController interface {
 	// FindMergeBase returns the 'from' commit, the 'to' commit and the merge base commit of 'from' and 'to' commits.
 	FindMergeBase(ctx context.Context, repository *RepositoryRecord, from Ref, to Ref) (*CommitRecord, *CommitRecord, *Commit, error)
 
+	// CountDivergence returns the number of commits reachable from 'left' and not from 'right', and the number of
+	// commits reachable from 'right' and not from 'left'.
+	CountDivergence(ctx context.Context, repository *RepositoryRecord, left, right CommitID) (int, int, error)
+
 	// SetHooksHandler set handler for all graveler hooks
 	SetHooksHandler(handler HooksHandler)
 
@@ -628,6 +766,10 @@ type VersionController interface {
 	// Note: Ancestors of previously expired commits may still be considered if they can be reached from a non-expired commit.
 	SaveGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord) (garbageCollectionRunMetadata *GarbageCollectionRunMetadata, err error)
 
+	// GetGarbageCollectionCommits returns the commits that remain active under the given garbage collection rules,
+	// and their metarange IDs. Commits of the repository missing from the result are expired.
+	GetGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (map[CommitID]MetaRangeID, error)
+
 	// GCGetUncommittedLocation returns full uri of the storage location of saved uncommitted files per runID
 	GCGetUncommittedLocation(repository *RepositoryRecord, runID string) (string, error)
 
@@ -643,6 +785,90 @@ type VersionController interface {
 	// If lastKnownChecksum is nil, the update is performed unconditionally.
 	SetBranchProtectionRules(ctx context.Context, repository *RepositoryRecord, rules *BranchProtectionRules, lastKnownChecksum *string) error
 
+	// GetBranchLocks returns the locks of the branches of the repository.
+	GetBranchLocks(ctx context.Context, repository *RepositoryRecord) (*BranchLocks, error)
+
+	// LockBranch locks the branch: any change to it, including commits and merges into it, fails with ErrBranchLocked
+	// until it is unlocked.
+	LockBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, lock *BranchLock) error
+
+	// UnlockBranch removes the lock of the branch.
+	UnlockBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error
+
+	// GetPassThroughMappings returns the mappings of repository path prefixes to upstream locations, from which
+	// objects missing from the repository are read.
+	GetPassThroughMappings(ctx context.Context, repository *RepositoryRecord) (*PassThroughMappings, error)
+
+	// SetPassThroughMappings replaces the pass-through mappings of the repository.
+	SetPassThroughMappings(ctx context.Context, repository *RepositoryRecord, mappings *PassThroughMappings) error
+
+	// GetCORSRules returns the rules allowing cross-origin requests to the repository.
+	GetCORSRules(ctx context.Context, repository *RepositoryRecord) (*CORSRules, error)
+
+	// SetCORSRules replaces the CORS rules of the repository.
+	SetCORSRules(ctx context.Context, repository *RepositoryRecord, rules *CORSRules) error
+
+	// GetObjectLockConfiguration returns the object lock configuration of the repository.
+	GetObjectLockConfiguration(ctx context.Context, repository *RepositoryRecord) (*ObjectLockConfiguration, error)
+
+	// SetObjectLockConfiguration replaces the object lock configuration of the repository. Object lock cannot be
+	// disabled once enabled.
+	SetObjectLockConfiguration(ctx context.Context, repository *RepositoryRecord, config *ObjectLockConfiguration) error
+
+	// GetCostAttribution returns the cost attribution propagated to the underlying object store on requests for
+	// the repository.
+	GetCostAttribution(ctx context.Context, repository *RepositoryRecord) (*CostAttribution, error)
+
+	// SetCostAttribution replaces the cost attribution of the repository.
+	SetCostAttribution(ctx context.Context, repository *RepositoryRecord, attribution *CostAttribution) error
+
+	// GetKeyValidationRules returns the rules keys written to the repository must follow.
+	GetKeyValidationRules(ctx context.Context, repository *RepositoryRecord) (*KeyValidationRules, error)
+
+	// SetKeyValidationRules replaces the key validation rules of the repository.
+	SetKeyValidationRules(ctx context.Context, repository *RepositoryRecord, rules *KeyValidationRules) error
+
+	// GetCommitTemplate returns the template commits to the repository must follow.
+	GetCommitTemplate(ctx context.Context, repository *RepositoryRecord) (*CommitTemplate, error)
+
+	// SetCommitTemplate replaces the commit template of the repository.
+	SetCommitTemplate(ctx context.Context, repository *RepositoryRecord, template *CommitTemplate) error
+
+	// GetBucketNotifications returns the S3 bucket notification configurations accepted for the repository.
+	GetBucketNotifications(ctx context.Context, repository *RepositoryRecord) (*BucketNotifications, error)
+
+	// SetBucketNotifications replaces the S3 bucket notification configurations of the repository.
+	SetBucketNotifications(ctx context.Context, repository *RepositoryRecord, notifications *BucketNotifications) error
+
+	// GetBlockAdapterOverride returns the settings overriding the global block adapter configuration for the repository.
+	GetBlockAdapterOverride(ctx context.Context, repository *RepositoryRecord) (*BlockAdapterOverride, error)
+
+	// SetBlockAdapterOverride replaces the settings overriding the global block adapter configuration for the repository.
+	SetBlockAdapterOverride(ctx context.Context, repository *RepositoryRecord, override *BlockAdapterOverride) error
+
+	// GetLifecycleRules returns the lifecycle rules of the repository
+	GetLifecycleRules(ctx context.Context, repository *RepositoryRecord) (*LifecycleRules, error)
+
+	// SetLifecycleRules replaces the lifecycle rules of the repository
+	SetLifecycleRules(ctx context.Context, repository *RepositoryRecord, rules *LifecycleRules) error
+
+	// GetLegalHolds returns the legal holds of the repository.
+	GetLegalHolds(ctx context.Context, repository *RepositoryRecord) (*LegalHolds, error)
+
+	// SetCommitLegalHold places a legal hold on a commit. Objects reachable from a held commit are never
+	// reclaimed by garbage collection.
+	SetCommitLegalHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID, hold *LegalHold) error
+
+	// DeleteCommitLegalHold releases the legal hold of a commit.
+	DeleteCommitLegalHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID) error
+
+	// SetTagLegalHold places a legal hold on a tag. A held tag cannot be deleted, and objects reachable from
+	// its commit are never reclaimed by garbage collection.
+	SetTagLegalHold(ctx context.Context, repository *RepositoryRecord, tagID TagID, hold *LegalHold) error
+
+	// DeleteTagLegalHold releases the legal hold of a tag.
+	DeleteTagLegalHold(ctx context.Context, repository *RepositoryRecord, tagID TagID) error
+
 	// SetLinkAddress saves the address for linking under the repository.
 	// It returns ErrLinkAddressAlreadyExists if the address already saved.
 	SetLinkAddress(ctx context.Context, repository *RepositoryRecord, physicalAddress string) error
@@ -661,6 +887,9 @@ type VersionController interface {
 
 	// DeleteExpiredImports deletes expired imports on a given repository
 	DeleteExpiredImports(ctx context.Context, repository *RepositoryRecord) error
+
+	// DeleteExpiredDeletedBranches deletes the records of deleted branches that can no longer be restored
+	DeleteExpiredDeletedBranches(ctx context.Context, repository *RepositoryRecord) error
 }
 
 // Plumbing includes commands for fiddling more directly with graveler implementation
@@ -670,6 +899,10 @@ type Plumbing interface {
 	GetMetaRange(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) (MetaRangeAddress, error)
 	// GetRange returns information where rangeID is stored.
 	GetRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (RangeAddress, error)
+	// ListRanges returns the ranges of metaRangeID, ordered by key.
+	ListRanges(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) ([]*RangeInfo, error)
+	// ListRange lists the values of rangeID, ordered by key.
+	ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error)
 	// WriteRange creates a new Range from the iterator values.
 	// Keeps Range closing logic, so might not flush all values to the range.
 	// Returns the created range info and in addition a list of records which were skipped due to out of order listing
@@ -760,6 +993,22 @@ type TagIterator interface {
 	Close()
 }
 
+type DeletedBranchIterator interface {
+	Next() bool
+	SeekGE(id BranchID)
+	Value() *DeletedBranchRecord
+	Err() error
+	Close()
+}
+
+type StashIterator interface {
+	Next() bool
+	SeekGE(id StashID)
+	Value() *StashRecord
+	Err() error
+	Close()
+}
+
 type CommitIterator interface {
 	Next() bool
 	SeekGE(id CommitID)
@@ -802,6 +1051,12 @@ type RefManager interface {
 	// SetRepositoryMetadata updates repository user metadata using the updateFunc
 	SetRepositoryMetadata(ctx context.Context, repository *RepositoryRecord, updateFunc RepoMetadataUpdateFunc) error
 
+	// SetRepositoryReadOnly sets whether the repository rejects writes that are not forced
+	SetRepositoryReadOnly(ctx context.Context, repository *RepositoryRecord, readOnly bool) error
+
+	// SetDefaultBranch sets the default branch of the repository
+	SetDefaultBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error
+
 	// ParseRef returns parsed 'ref' information as RawRef
 	ParseRef(ref Ref) (RawRef, error)
 
@@ -826,6 +1081,33 @@ type RefManager interface {
 	// ListBranches lists branches
 	ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
 
+	// SetDeletedBranch records the head of a deleted branch, replacing an earlier record of a branch by the same name
+	SetDeletedBranch(ctx context.Context, repository *RepositoryRecord, deletedBranch *DeletedBranchRecord) error
+
+	// GetDeletedBranch returns the record of a deleted branch
+	GetDeletedBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*DeletedBranchRecord, error)
+
+	// RemoveDeletedBranch removes the record of a deleted branch
+	RemoveDeletedBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error
+
+	// ListDeletedBranches lists the records of deleted branches, including expired ones
+	ListDeletedBranches(ctx context.Context, repository *RepositoryRecord) (DeletedBranchIterator, error)
+
+	// DeleteExpiredDeletedBranches removes the expired records of deleted branches
+	DeleteExpiredDeletedBranches(ctx context.Context, repository *RepositoryRecord) error
+
+	// CreateStash records a stash, failing with ErrStashExists if a stash by the same name exists
+	CreateStash(ctx context.Context, repository *RepositoryRecord, stash *StashRecord) error
+
+	// GetStash returns the record of a stash
+	GetStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) (*StashRecord, error)
+
+	// DeleteStash removes the record of a stash
+	DeleteStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) error
+
+	// ListStashes lists the records of stashes
+	ListStashes(ctx context.Context, repository *RepositoryRecord) (StashIterator, error)
+
 	// GCBranchIterator TODO (niro): Remove when DB implementation is deleted
 	// GCBranchIterator temporary WA to support both DB and KV GC BranchIterator, which iterates over branches by order of commit ID
 	GCBranchIterator(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
@@ -855,6 +1137,9 @@ type RefManager interface {
 	// CreateCommitRecord stores the Commit object
 	CreateCommitRecord(ctx context.Context, repository *RepositoryRecord, commitID CommitID, commit Commit) error
 
+	// SetCommitStats sets the stats of an existing commit
+	SetCommitStats(ctx context.Context, repository *RepositoryRecord, commitID CommitID, stats CommitStats) error
+
 	// RemoveCommit deletes commit from store - used for repository cleanup
 	RemoveCommit(ctx context.Context, repository *RepositoryRecord, commitID CommitID) error
 
@@ -863,6 +1148,10 @@ type RefManager interface {
 	// and internally: https://github.com/treeverse/lakeFS/blob/09954804baeb36ada74fa17d8fdc13a38552394e/index/dag/commits.go
 	FindMergeBase(ctx context.Context, repository *RepositoryRecord, commitIDs ...CommitID) (*Commit, error)
 
+	// CountDivergence returns the number of commits reachable from left and not from right, and the number of commits
+	// reachable from right and not from left, like 'git rev-list --left-right --count left...right'
+	CountDivergence(ctx context.Context, repository *RepositoryRecord, left, right CommitID) (int, int, error)
+
 	// Log returns an iterator starting at commit ID up to repository root
 	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error)
 
@@ -914,6 +1203,11 @@ type CommittedManager interface {
 	// List takes a given tree and returns an ValueIterator
 	List(ctx context.Context, ns StorageNamespace, rangeID MetaRangeID) (ValueIterator, error)
 
+	// ListDirectory returns a ValueIterator over the children of the directory prefix of a given tree: the values
+	// directly under prefix, and a record with a nil value for each common prefix of the keys under it, up to and
+	// including delimiter. Ranges whose keys all share a common prefix are listed without reading them.
+	ListDirectory(ctx context.Context, ns StorageNamespace, rangeID MetaRangeID, prefix, delimiter Key) (ValueIterator, error)
+
 	// Diff receives two metaRanges and returns a DiffIterator describing all differences between them.
 	// This is similar to a two-dot diff in git (left..right)
 	Diff(ctx context.Context, ns StorageNamespace, left, right MetaRangeID) (DiffIterator, error)
@@ -941,6 +1235,12 @@ type CommittedManager interface {
 	// GetRange returns information where rangeID is stored.
 	GetRange(ctx context.Context, ns StorageNamespace, rangeID RangeID) (RangeAddress, error)
 
+	// ListRanges returns the ranges of metaRangeID, ordered by key.
+	ListRanges(ctx context.Context, ns StorageNamespace, metaRangeID MetaRangeID) ([]*RangeInfo, error)
+
+	// ListRange lists the values of rangeID, ordered by key.
+	ListRange(ctx context.Context, ns StorageNamespace, rangeID RangeID) (ValueIterator, error)
+
 	// GetRangeIDByKey returns the RangeID that contains the given key.
 	GetRangeIDByKey(ctx context.Context, ns StorageNamespace, id MetaRangeID, key Key) (RangeID, error)
 }
@@ -1000,6 +1300,10 @@ func (id BranchID) Ref() Ref {
 	return Ref(id)
 }
 
+func (id StashID) String() string {
+	return string(id)
+}
+
 func (id Ref) String() string {
 	return string(id)
 }
@@ -1039,32 +1343,63 @@ func (id ImportID) String() string {
 }
 
 type Graveler struct {
-	hooks                    HooksHandler
-	CommittedManager         CommittedManager
-	RefManager               RefManager
-	StagingManager           StagingManager
-	protectedBranchesManager ProtectedBranchesManager
-	garbageCollectionManager GarbageCollectionManager
+	hooks                       HooksHandler
+	CommittedManager            CommittedManager
+	RefManager                  RefManager
+	StagingManager              StagingManager
+	protectedBranchesManager    ProtectedBranchesManager
+	garbageCollectionManager    GarbageCollectionManager
+	legalHoldManager            LegalHoldManager
+	lockedBranchesManager       LockedBranchesManager
+	passThroughManager          PassThroughManager
+	corsManager                 CORSManager
+	objectLockManager           ObjectLockManager
+	costAttributionManager      CostAttributionManager
+	keyValidationManager        KeyValidationManager
+	commitTemplateManager       CommitTemplateManager
+	bucketNotificationManager   BucketNotificationManager
+	blockAdapterOverrideManager BlockAdapterOverrideManager
+	lifecycleRulesManager       LifecycleRulesManager
 	// logger *without context* to be used for logging.  It should be
 	// avoided in favour of g.log(ctx) in any operation where context is
 	// available.
 	logger              logging.Logger
 	BranchUpdateBackOff backoff.BackOff
-}
-
-func NewGraveler(committedManager CommittedManager, stagingManager StagingManager, refManager RefManager, gcManager GarbageCollectionManager, protectedBranchesManager ProtectedBranchesManager) *Graveler {
+	// ValueSize returns the size of the objects of values, used to compute commit stats in the background once
+	// commits are created. Commits have no stats when it is nil.
+	ValueSize ValueSizeFunc
+	// SubmitBackground runs background tasks, such as computing commit stats, and returns false if the task was not
+	// accepted. Background tasks run in their own goroutine when it is nil.
+	SubmitBackground func(task func()) bool
+	// DeletedBranchRetention is the period during which a deleted branch can be restored. Deleted branches are
+	// not kept when it is zero.
+	DeletedBranchRetention time.Duration
+}
+
+func NewGraveler(committedManager CommittedManager, stagingManager StagingManager, refManager RefManager, gcManager GarbageCollectionManager, protectedBranchesManager ProtectedBranchesManager, legalHoldManager LegalHoldManager, lockedBranchesManager LockedBranchesManager, passThroughManager PassThroughManager, corsManager CORSManager, objectLockManager ObjectLockManager, costAttributionManager CostAttributionManager, keyValidationManager KeyValidationManager, commitTemplateManager CommitTemplateManager, bucketNotificationManager BucketNotificationManager, blockAdapterOverrideManager BlockAdapterOverrideManager, lifecycleRulesManager LifecycleRulesManager) *Graveler {
 	branchUpdateBackOff := backoff.NewExponentialBackOff()
 	branchUpdateBackOff.MaxInterval = BranchUpdateMaxInterval
 
 	return &Graveler{
-		hooks:                    &HooksNoOp{},
-		CommittedManager:         committedManager,
-		RefManager:               refManager,
-		StagingManager:           stagingManager,
-		BranchUpdateBackOff:      branchUpdateBackOff,
-		protectedBranchesManager: protectedBranchesManager,
-		garbageCollectionManager: gcManager,
-		logger:                   logging.ContextUnavailable().WithField("service_name", "graveler_graveler"),
+		hooks:                       &HooksNoOp{},
+		CommittedManager:            committedManager,
+		RefManager:                  refManager,
+		StagingManager:              stagingManager,
+		BranchUpdateBackOff:         branchUpdateBackOff,
+		protectedBranchesManager:    protectedBranchesManager,
+		garbageCollectionManager:    gcManager,
+		legalHoldManager:            legalHoldManager,
+		lockedBranchesManager:       lockedBranchesManager,
+		passThroughManager:          passThroughManager,
+		corsManager:                 corsManager,
+		objectLockManager:           objectLockManager,
+		costAttributionManager:      costAttributionManager,
+		keyValidationManager:        keyValidationManager,
+		commitTemplateManager:       commitTemplateManager,
+		bucketNotificationManager:   bucketNotificationManager,
+		blockAdapterOverrideManager: blockAdapterOverrideManager,
+		lifecycleRulesManager:       lifecycleRulesManager,
+		logger:                      logging.ContextUnavailable().WithField("service_name", "graveler_graveler"),
 	}
 }
 
@@ -1120,6 +1455,10 @@ func (g *Graveler) SetRepositoryMetadata(ctx context.Context, repository *Reposi
 	return g.RefManager.SetRepositoryMetadata(ctx, repository, updateFunc)
 }
 
+func (g *Graveler) SetRepositoryReadOnly(ctx context.Context, repository *RepositoryRecord, readOnly bool) error {
+	return g.RefManager.SetRepositoryReadOnly(ctx, repository, readOnly)
+}
+
 func (g *Graveler) WriteRange(ctx context.Context, repository *RepositoryRecord, it ValueIterator, opts ...SetOptionsFunc) (*RangeInfo, error) {
 	options := &SetOptions{}
 	for _, opt := range opts {
@@ -1182,9 +1521,9 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 		return nil, fmt.Errorf("source reference '%s': %w", ref, ErrCreateBranchNoCommit)
 	}
 
-	_, err = g.RefManager.GetBranch(ctx, repository, branchID)
+	existing, err := g.RefManager.GetBranch(ctx, repository, branchID)
 	if err == nil {
-		return nil, ErrBranchExists
+		return existingBranch(branchID, existing, reference.CommitID, options.ExistOK)
 	}
 	if !errors.Is(err, ErrBranchNotFound) {
 		return nil, err
@@ -1215,6 +1554,14 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 	}
 
 	err = g.RefManager.CreateBranch(ctx, repository, branchID, newBranch)
+	if errors.Is(err, ErrBranchExists) && options.ExistOK {
+		// created concurrently since it was checked
+		existing, err := g.RefManager.GetBranch(ctx, repository, branchID)
+		if err != nil {
+			return nil, err
+		}
+		return existingBranch(branchID, existing, reference.CommitID, true)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("set branch '%s' to '%v': %w", branchID, newBranch, err)
 	}
@@ -1234,6 +1581,18 @@ func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecor
 	return &newBranch, nil
 }
 
+// existingBranch returns the result of creating a branch that exists: the existing branch when existOK and it is at
+// commitID, the commit of the requested source, and ErrBranchExists otherwise.
+func existingBranch(branchID BranchID, existing *Branch, commitID CommitID, existOK bool) (*Branch, error) {
+	if !existOK {
+		return nil, ErrBranchExists
+	}
+	if existing.CommitID != commitID {
+		return nil, fmt.Errorf("branch '%s' is at commit %s, not at the source commit %s: %w", branchID, existing.CommitID, commitID, ErrBranchExists)
+	}
+	return existing, nil
+}
+
 func (g *Graveler) UpdateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) (*Branch, error) {
 	options := &SetOptions{}
 	for _, opt := range opts {
@@ -1242,6 +1601,9 @@ func (g *Graveler) UpdateBranch(ctx context.Context, repository *RepositoryRecor
 	if repository.ReadOnly && !options.Force {
 		return nil, ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return nil, err
+	}
 	reference, err := g.Dereference(ctx, repository, ref)
 	if err != nil {
 		return nil, err
@@ -1402,6 +1764,13 @@ func (g *Graveler) DeleteTag(ctx context.Context, repository *RepositoryRecord,
 	if err != nil {
 		return err
 	}
+	isHeld, err := g.legalHoldManager.IsTagHeld(ctx, repository, tagID)
+	if err != nil {
+		return err
+	}
+	if isHeld {
+		return fmt.Errorf("tag %s: %w", tagID, ErrLegalHold)
+	}
 
 	preRunID := g.hooks.NewRunID()
 	err = g.hooks.PreDeleteTagHook(ctx, HookRecord{
@@ -1464,6 +1833,10 @@ func (g *Graveler) Log(ctx context.Context, repository *RepositoryRecord, commit
 	return g.RefManager.Log(ctx, repository, commitID, firstParent, since)
 }
 
+func (g *Graveler) ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error) {
+	return g.RefManager.ListCommits(ctx, repository)
+}
+
 func (g *Graveler) ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error) {
 	return g.RefManager.ListBranches(ctx, repository)
 }
@@ -1476,6 +1849,9 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 	if repository.DefaultBranchID == branchID {
 		return ErrDeleteDefaultBranch
 	}
@@ -1504,6 +1880,19 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 		}
 	}
 
+	if g.DeletedBranchRetention > 0 {
+		deletedAt := time.Now()
+		err = g.RefManager.SetDeletedBranch(ctx, repository, &DeletedBranchRecord{
+			BranchID:  branchID,
+			CommitID:  commitID,
+			DeletedAt: deletedAt,
+			ExpiresAt: deletedAt.Add(g.DeletedBranchRetention),
+		})
+		if err != nil {
+			return fmt.Errorf("keep deleted branch: %w", err)
+		}
+	}
+
 	// Delete branch first - afterwards remove tokens
 	err = g.RefManager.DeleteBranch(ctx, repository, branchID)
 	if err != nil { // Don't perform post action hook if operation finished with error
@@ -1528,6 +1917,173 @@ func (g *Graveler) DeleteBranch(ctx context.Context, repository *RepositoryRecor
 	return nil
 }
 
+// RenameBranch renames the branch to newBranchID, keeping its commit and uncommitted changes. Branch protection rules
+// of the branch name are moved to the new name, and the repository default branch is updated if it is renamed. The
+// rename fails with ErrConflictFound if the branch changes while it is renamed, leaving the branch as it was.
+func (g *Graveler) RenameBranch(ctx context.Context, repository *RepositoryRecord, branchID, newBranchID BranchID, opts ...SetOptionsFunc) error {
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if repository.ReadOnly && !options.Force {
+		return ErrReadOnlyRepository
+	}
+	if branchID == newBranchID {
+		return nil
+	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
+	err := g.hooks.CheckRenameBranch(ctx, HookRecord{
+		StorageNamespace: repository.StorageNamespace,
+		SourceRef:        Ref(branchID),
+		RepositoryID:     repository.RepositoryID,
+		BranchID:         branchID,
+	}, newBranchID)
+	if err != nil {
+		return err
+	}
+	// The whole rename runs under a single update of the branch: the branch is copied and its references are moved
+	// from the branch read by the update, which fails if the branch changed meanwhile. Any failure undoes the copy.
+	created := false
+	var undoReferences func()
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(current *Branch) (*Branch, error) {
+		// the new branch shares the staging tokens of the branch, only one of them may remain
+		if err := g.RefManager.CreateBranch(ctx, repository, newBranchID, *current); err != nil {
+			return nil, err
+		}
+		created = true
+		var err error
+		undoReferences, err = g.moveBranchReferences(ctx, repository, branchID, newBranchID)
+		if err != nil {
+			return nil, err
+		}
+		// rewriting the branch as read fails the update if it changed since
+		return current, nil
+	})
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		err = fmt.Errorf("branch %s changed during rename: %w", branchID, ErrConflictFound)
+	}
+	if err != nil {
+		if undoReferences != nil {
+			undoReferences()
+		}
+		if created {
+			if rollbackErr := g.RefManager.DeleteBranch(ctx, repository, newBranchID); rollbackErr != nil {
+				g.log(ctx).WithError(rollbackErr).WithField("branch", newBranchID).Error("Failed to remove branch of a failed rename")
+			}
+		}
+		return err
+	}
+	return g.RefManager.DeleteBranch(ctx, repository, branchID)
+}
+
+// moveBranchReferences moves the default branch, the protection rules, the lifecycle rules and the garbage collection
+// retention of branchID to newBranchID. A branch locked since the rename started is not renamed. It returns a function
+// undoing the moves, for a rename that fails after moving them.
+func (g *Graveler) moveBranchReferences(ctx context.Context, repository *RepositoryRecord, branchID, newBranchID BranchID) (func(), error) {
+	var undo []func() error
+	undoAll := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				g.log(ctx).WithError(err).WithField("branch", branchID).Error("Failed to restore references of a failed rename")
+			}
+		}
+	}
+
+	// locks are not moved: a locked branch may not change, including its name
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return nil, err
+	}
+
+	rules, checksum, err := g.protectedBranchesManager.GetRules(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	if blockedActions, ok := rules.GetBranchPatternToBlockedActions()[string(branchID)]; ok {
+		rules.BranchPatternToBlockedActions[string(newBranchID)] = blockedActions
+		delete(rules.BranchPatternToBlockedActions, string(branchID))
+		if err := g.protectedBranchesManager.SetRules(ctx, repository, rules, checksum); err != nil {
+			return nil, err
+		}
+		undo = append(undo, func() error {
+			rules, checksum, err := g.protectedBranchesManager.GetRules(ctx, repository)
+			if err != nil {
+				return err
+			}
+			rules.BranchPatternToBlockedActions[string(branchID)] = blockedActions
+			delete(rules.BranchPatternToBlockedActions, string(newBranchID))
+			return g.protectedBranchesManager.SetRules(ctx, repository, rules, checksum)
+		})
+	}
+
+	lifecycleRules, err := g.lifecycleRulesManager.GetRules(ctx, repository)
+	if err != nil {
+		undoAll()
+		return nil, err
+	}
+	if renameLifecycleRules(lifecycleRules, branchID, newBranchID) {
+		if err := g.lifecycleRulesManager.SetRules(ctx, repository, lifecycleRules); err != nil {
+			undoAll()
+			return nil, err
+		}
+		undo = append(undo, func() error {
+			rules, err := g.lifecycleRulesManager.GetRules(ctx, repository)
+			if err != nil {
+				return err
+			}
+			renameLifecycleRules(rules, newBranchID, branchID)
+			return g.lifecycleRulesManager.SetRules(ctx, repository, rules)
+		})
+	}
+
+	gcRules, err := g.garbageCollectionManager.GetRules(ctx, repository.StorageNamespace)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		undoAll()
+		return nil, err
+	}
+	if retentionDays, ok := gcRules.GetBranchRetentionDays()[string(branchID)]; ok {
+		gcRules.BranchRetentionDays[string(newBranchID)] = retentionDays
+		delete(gcRules.BranchRetentionDays, string(branchID))
+		if err := g.garbageCollectionManager.SaveRules(ctx, repository.StorageNamespace, gcRules); err != nil {
+			undoAll()
+			return nil, err
+		}
+		undo = append(undo, func() error {
+			rules, err := g.garbageCollectionManager.GetRules(ctx, repository.StorageNamespace)
+			if err != nil {
+				return err
+			}
+			rules.BranchRetentionDays[string(branchID)] = retentionDays
+			delete(rules.BranchRetentionDays, string(newBranchID))
+			return g.garbageCollectionManager.SaveRules(ctx, repository.StorageNamespace, rules)
+		})
+	}
+
+	if repository.DefaultBranchID == branchID {
+		if err := g.RefManager.SetDefaultBranch(ctx, repository, newBranchID); err != nil {
+			undoAll()
+			return nil, err
+		}
+		undo = append(undo, func() error {
+			return g.RefManager.SetDefaultBranch(ctx, repository, branchID)
+		})
+	}
+	return undoAll, nil
+}
+
+// renameLifecycleRules moves the lifecycle rules of branchID to newBranchID, and returns whether any rule moved
+func renameLifecycleRules(rules *LifecycleRules, branchID, newBranchID BranchID) bool {
+	renamed := false
+	for _, rule := range rules.GetRules() {
+		if rule.Branch == string(branchID) {
+			rule.Branch = string(newBranchID)
+			renamed = true
+		}
+	}
+	return renamed
+}
+
 func (g *Graveler) GetStagingToken(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*StagingToken, error) {
 	branch, err := g.RefManager.GetBranch(ctx, repository, branchID)
 	if err != nil {
@@ -1554,7 +2110,17 @@ func (g *Graveler) SaveGarbageCollectionCommits(ctx context.Context, repository
 		return nil, fmt.Errorf("get gc rules: %w", err)
 	}
 
-	runID, err := g.garbageCollectionManager.SaveGarbageCollectionCommits(ctx, repository, rules)
+	heldCommits, err := g.legalHeldCommits(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("get legal holds: %w", err)
+	}
+	deletedBranchCommits, err := g.deletedBranchCommits(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("get deleted branches: %w", err)
+	}
+	heldCommits = append(heldCommits, deletedBranchCommits...)
+
+	runID, err := g.garbageCollectionManager.SaveGarbageCollectionCommits(ctx, repository, rules, heldCommits)
 	if err != nil {
 		return nil, fmt.Errorf("save garbage collection commits: %w", err)
 	}
@@ -1574,6 +2140,42 @@ func (g *Graveler) SaveGarbageCollectionCommits(ctx context.Context, repository
 	}, err
 }
 
+func (g *Graveler) GetGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (map[CommitID]MetaRangeID, error) {
+	heldCommits, err := g.legalHeldCommits(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("get legal holds: %w", err)
+	}
+	deletedBranchCommits, err := g.deletedBranchCommits(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("get deleted branches: %w", err)
+	}
+	heldCommits = append(heldCommits, deletedBranchCommits...)
+	return g.garbageCollectionManager.GetGarbageCollectionCommits(ctx, repository, rules, heldCommits)
+}
+
+// legalHeldCommits returns the commits under legal hold, directly or through a held tag
+func (g *Graveler) legalHeldCommits(ctx context.Context, repository *RepositoryRecord) ([]CommitID, error) {
+	holds, err := g.legalHoldManager.GetHolds(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]CommitID, 0, len(holds.Commits)+len(holds.Tags))
+	for commitID := range holds.Commits {
+		commits = append(commits, CommitID(commitID))
+	}
+	for tagID := range holds.Tags {
+		commitID, err := g.RefManager.GetTag(ctx, repository, TagID(tagID))
+		if errors.Is(err, ErrTagNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, *commitID)
+	}
+	return commits, nil
+}
+
 func (g *Graveler) GCGetUncommittedLocation(repository *RepositoryRecord, runID string) (string, error) {
 	return g.garbageCollectionManager.GetUncommittedLocation(runID, repository.StorageNamespace)
 }
@@ -1590,6 +2192,131 @@ func (g *Graveler) SetBranchProtectionRules(ctx context.Context, repository *Rep
 	return g.protectedBranchesManager.SetRules(ctx, repository, rules, lastKnownChecksum)
 }
 
+func (g *Graveler) GetBranchLocks(ctx context.Context, repository *RepositoryRecord) (*BranchLocks, error) {
+	return g.lockedBranchesManager.GetLocks(ctx, repository)
+}
+
+func (g *Graveler) LockBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, lock *BranchLock) error {
+	if _, err := g.RefManager.GetBranch(ctx, repository, branchID); err != nil {
+		return err
+	}
+	return g.lockedBranchesManager.Lock(ctx, repository, branchID, lock)
+}
+
+func (g *Graveler) UnlockBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error {
+	return g.lockedBranchesManager.Unlock(ctx, repository, branchID)
+}
+
+// checkBranchLocked returns an error wrapping ErrBranchLocked, describing the lock, if the branch is locked
+func (g *Graveler) checkBranchLocked(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error {
+	lock, err := g.lockedBranchesManager.GetLock(ctx, repository, branchID)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return nil
+	}
+	return fmt.Errorf("%w %s (locked by %s: %s)", ErrBranchLocked, branchID, lock.LockedBy, lock.Reason)
+}
+
+func (g *Graveler) GetPassThroughMappings(ctx context.Context, repository *RepositoryRecord) (*PassThroughMappings, error) {
+	return g.passThroughManager.GetMappings(ctx, repository)
+}
+
+func (g *Graveler) SetPassThroughMappings(ctx context.Context, repository *RepositoryRecord, mappings *PassThroughMappings) error {
+	return g.passThroughManager.SetMappings(ctx, repository, mappings)
+}
+
+func (g *Graveler) GetCORSRules(ctx context.Context, repository *RepositoryRecord) (*CORSRules, error) {
+	return g.corsManager.GetRules(ctx, repository)
+}
+
+func (g *Graveler) SetCORSRules(ctx context.Context, repository *RepositoryRecord, rules *CORSRules) error {
+	return g.corsManager.SetRules(ctx, repository, rules)
+}
+
+func (g *Graveler) GetObjectLockConfiguration(ctx context.Context, repository *RepositoryRecord) (*ObjectLockConfiguration, error) {
+	return g.objectLockManager.GetConfiguration(ctx, repository)
+}
+
+func (g *Graveler) SetObjectLockConfiguration(ctx context.Context, repository *RepositoryRecord, config *ObjectLockConfiguration) error {
+	return g.objectLockManager.SetConfiguration(ctx, repository, config)
+}
+
+func (g *Graveler) GetCostAttribution(ctx context.Context, repository *RepositoryRecord) (*CostAttribution, error) {
+	return g.costAttributionManager.GetAttribution(ctx, repository)
+}
+
+func (g *Graveler) SetCostAttribution(ctx context.Context, repository *RepositoryRecord, attribution *CostAttribution) error {
+	return g.costAttributionManager.SetAttribution(ctx, repository, attribution)
+}
+
+func (g *Graveler) GetKeyValidationRules(ctx context.Context, repository *RepositoryRecord) (*KeyValidationRules, error) {
+	return g.keyValidationManager.GetRules(ctx, repository)
+}
+
+func (g *Graveler) SetKeyValidationRules(ctx context.Context, repository *RepositoryRecord, rules *KeyValidationRules) error {
+	return g.keyValidationManager.SetRules(ctx, repository, rules)
+}
+
+func (g *Graveler) GetCommitTemplate(ctx context.Context, repository *RepositoryRecord) (*CommitTemplate, error) {
+	return g.commitTemplateManager.GetTemplate(ctx, repository)
+}
+
+func (g *Graveler) SetCommitTemplate(ctx context.Context, repository *RepositoryRecord, template *CommitTemplate) error {
+	return g.commitTemplateManager.SetTemplate(ctx, repository, template)
+}
+
+func (g *Graveler) GetBucketNotifications(ctx context.Context, repository *RepositoryRecord) (*BucketNotifications, error) {
+	return g.bucketNotificationManager.GetNotifications(ctx, repository)
+}
+
+func (g *Graveler) SetBucketNotifications(ctx context.Context, repository *RepositoryRecord, notifications *BucketNotifications) error {
+	return g.bucketNotificationManager.SetNotifications(ctx, repository, notifications)
+}
+
+func (g *Graveler) GetBlockAdapterOverride(ctx context.Context, repository *RepositoryRecord) (*BlockAdapterOverride, error) {
+	return g.blockAdapterOverrideManager.GetOverride(ctx, repository)
+}
+
+func (g *Graveler) SetBlockAdapterOverride(ctx context.Context, repository *RepositoryRecord, override *BlockAdapterOverride) error {
+	return g.blockAdapterOverrideManager.SetOverride(ctx, repository, override)
+}
+
+func (g *Graveler) GetLifecycleRules(ctx context.Context, repository *RepositoryRecord) (*LifecycleRules, error) {
+	return g.lifecycleRulesManager.GetRules(ctx, repository)
+}
+
+func (g *Graveler) SetLifecycleRules(ctx context.Context, repository *RepositoryRecord, rules *LifecycleRules) error {
+	return g.lifecycleRulesManager.SetRules(ctx, repository, rules)
+}
+
+func (g *Graveler) GetLegalHolds(ctx context.Context, repository *RepositoryRecord) (*LegalHolds, error) {
+	return g.legalHoldManager.GetHolds(ctx, repository)
+}
+
+func (g *Graveler) SetCommitLegalHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID, hold *LegalHold) error {
+	if _, err := g.RefManager.GetCommit(ctx, repository, commitID); err != nil {
+		return err
+	}
+	return g.legalHoldManager.SetCommitHold(ctx, repository, commitID, hold)
+}
+
+func (g *Graveler) DeleteCommitLegalHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID) error {
+	return g.legalHoldManager.DeleteCommitHold(ctx, repository, commitID)
+}
+
+func (g *Graveler) SetTagLegalHold(ctx context.Context, repository *RepositoryRecord, tagID TagID, hold *LegalHold) error {
+	if _, err := g.RefManager.GetTag(ctx, repository, tagID); err != nil {
+		return err
+	}
+	return g.legalHoldManager.SetTagHold(ctx, repository, tagID, hold)
+}
+
+func (g *Graveler) DeleteTagLegalHold(ctx context.Context, repository *RepositoryRecord, tagID TagID) error {
+	return g.legalHoldManager.DeleteTagHold(ctx, repository, tagID)
+}
+
 // getFromStagingArea returns the most updated value of a given key in a branch staging area.
 // Iterate over all tokens - staging + sealed in order of last modified. First appearance of key represents the latest update
 // TODO: in most cases it is used by Get flow, assuming that usually the key will be found in committed we need to parallelize the get from tokens
@@ -1618,6 +2345,11 @@ func (g *Graveler) Get(ctx context.Context, repository *RepositoryRecord, ref Re
 		return nil, err
 	}
 
+	var options GetOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if reference.StagingToken != "" {
 		// try to get from staging, if not found proceed to committed
 		value, err := g.getFromStagingArea(ctx, reference.Branch, key)
@@ -1633,13 +2365,12 @@ func (g *Graveler) Get(ctx context.Context, repository *RepositoryRecord, ref Re
 		}
 	}
 
-	var options GetOptions
-	for _, opt := range opts {
-		opt(&options)
-	}
 	if options.StageOnly {
 		return nil, ErrNotFound
 	}
+	if options.ResolvedCommitID != nil {
+		*options.ResolvedCommitID = reference.CommitID
+	}
 
 	// If key is not found in staging area (or reference is not a branch), return the key from committed
 	commitID := reference.CommitID
@@ -1683,16 +2414,25 @@ func (g *Graveler) Set(ctx context.Context, repository *RepositoryRecord, branch
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	log := g.log(ctx).WithFields(logging.Fields{"key": key, "operation": "set"})
 	err = g.safeBranchWrite(ctx, log, repository, branchID, safeBranchWriteOptions{MaxTries: options.MaxTries}, func(branch *Branch) error {
+		if options.IfMatch != nil {
+			return g.setIfMatch(ctx, repository, branchID, branch, key, value, options.IfMatch)
+		}
 		if !options.IfAbsent {
 			return g.StagingManager.Set(ctx, branch.StagingToken, key, &value, false)
 		}
 
 		// verify the key not found
 		_, err := g.Get(ctx, repository, Ref(branchID), key)
-		if err == nil || !errors.Is(err, ErrNotFound) {
+		if err == nil {
+			return ErrPreconditionFailed
+		}
+		if !errors.Is(err, ErrNotFound) {
 			return err
 		}
 
@@ -1701,12 +2441,34 @@ func (g *Graveler) Set(ctx context.Context, repository *RepositoryRecord, branch
 			if currentValue == nil || currentValue.Identity == nil {
 				return &value, nil
 			}
-			return nil, ErrSkipValueUpdate
+			return nil, ErrPreconditionFailed
 		})
 	}, "set")
 	return err
 }
 
+// setIfMatch stages value of key on branch only if the current value of key has identity
+func (g *Graveler) setIfMatch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch *Branch, key Key, value Value, identity []byte) error {
+	current, err := g.Get(ctx, repository, Ref(branchID), key)
+	if errors.Is(err, ErrNotFound) {
+		return ErrPreconditionFailed
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current.Identity, identity) {
+		return ErrPreconditionFailed
+	}
+
+	// the value read may not be staged on the staging token, a value staged on it since must have identity too
+	return g.StagingManager.Update(ctx, branch.StagingToken, key, func(currentValue *Value) (*Value, error) {
+		if currentValue == nil || bytes.Equal(currentValue.Identity, identity) {
+			return &value, nil
+		}
+		return nil, ErrPreconditionFailed
+	})
+}
+
 // safeBranchWrite repeatedly attempts to perform stagingOperation, retrying
 // if the staging token changes during the write.  It never backs off.  It
 // returns the number of times it tried -- between 1 and options.MaxTries.
@@ -1776,6 +2538,9 @@ func (g *Graveler) Delete(ctx context.Context, repository *RepositoryRecord, bra
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	log := g.log(ctx).WithFields(logging.Fields{"key": key, "operation": "delete"})
 	err = g.safeBranchWrite(ctx, log, repository, branchID,
@@ -1803,6 +2568,9 @@ func (g *Graveler) DeleteBatch(ctx context.Context, repository *RepositoryRecord
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	if len(keys) > DeleteKeysMaxSize {
 		return fmt.Errorf("keys length (%d) passed the maximum allowed(%d): %w", len(keys), DeleteKeysMaxSize, ErrInvalidValue)
@@ -1938,19 +2706,22 @@ func (g *Graveler) List(ctx context.Context, repository *RepositoryRecord, ref R
 	return listing, nil
 }
 
-func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, params CommitParams, opts ...SetOptionsFunc) (CommitID, error) {
-	var preRunID string
-	var commit Commit
-	var newCommitID CommitID
-	var storageNamespace StorageNamespace
-	var sealedToDrop []StagingToken
+func (g *Graveler) ListDirectory(ctx context.Context, repository *RepositoryRecord, commitID CommitID, prefix, delimiter Key) (ValueIterator, error) {
+	commit, err := g.RefManager.GetCommit(ctx, repository, commitID)
+	if err != nil {
+		return nil, err
+	}
+	return g.CommittedManager.ListDirectory(ctx, repository.StorageNamespace, commit.MetaRangeID, prefix, delimiter)
+}
 
+// checkCommitAllowed returns an error if commits to the branch are blocked
+func (g *Graveler) checkCommitAllowed(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error {
 	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_COMMIT)
 	if err != nil {
-		return "", err
+		return err
 	}
 	if isProtected {
-		return "", ErrCommitToProtectedBranch
+		return ErrCommitToProtectedBranch
 	}
 
 	options := &SetOptions{}
@@ -1958,29 +2729,65 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 		opt(options)
 	}
 	if repository.ReadOnly && !options.Force {
-		return "", ErrReadOnlyRepository
+		return ErrReadOnlyRepository
 	}
-	storageNamespace = repository.StorageNamespace
+	return g.checkBranchLocked(ctx, repository, branchID)
+}
 
-	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
-		if params.SourceMetaRange != nil {
-			empty, err := g.isStagingEmpty(ctx, repository, branch)
-			if err != nil {
-				return nil, fmt.Errorf("checking empty branch: %w", err)
-			}
-			if !empty {
-				return nil, ErrCommitMetaRangeDirtyBranch
-			}
-		}
+func (g *Graveler) SealStagingToken(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error {
+	if err := g.checkCommitAllowed(ctx, repository, branchID, opts...); err != nil {
+		return err
+	}
+	return g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
 		branch.SealedTokens = append([]StagingToken{branch.StagingToken}, branch.SealedTokens...)
 		branch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
 		return branch, nil
 	})
+}
+
+func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, params CommitParams, opts ...SetOptionsFunc) (CommitID, error) {
+	var preRunID string
+	var commit Commit
+	var newCommitID CommitID
+	var storageNamespace StorageNamespace
+	var sealedToDrop []StagingToken
+
+	if params.Filter != nil && params.SourceMetaRange != nil {
+		return "", fmt.Errorf("partial commit with source metarange: %w", ErrInvalidValue)
+	}
+	if params.Sealed && (params.Filter != nil || params.SourceMetaRange != nil) {
+		return "", fmt.Errorf("commit of sealed changes with filter or source metarange: %w", ErrInvalidValue)
+	}
+	err := g.checkCommitAllowed(ctx, repository, branchID, opts...)
 	if err != nil {
 		return "", err
 	}
+	storageNamespace = repository.StorageNamespace
 
+	// a commit of sealed changes commits the staging tokens sealed before, not the current one
+	if !params.Sealed {
+		err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+			if params.SourceMetaRange != nil {
+				empty, err := g.isStagingEmpty(ctx, repository, branch)
+				if err != nil {
+					return nil, fmt.Errorf("checking empty branch: %w", err)
+				}
+				if !empty {
+					return nil, ErrCommitMetaRangeDirtyBranch
+				}
+			}
+			branch.SealedTokens = append([]StagingToken{branch.StagingToken}, branch.SealedTokens...)
+			branch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
+			return branch, nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var previousCommitID CommitID
 	err = g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		previousCommitID = branch.CommitID
 		// fill commit information - use for pre-commit and after adding the commit information used by commit
 		commit = NewCommit()
 
@@ -1988,9 +2795,23 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 			commit.CreationDate = time.Unix(*params.Date, 0)
 		}
 		commit.Committer = params.Committer
+		commit.Author = CommitAuthorFromContext(ctx)
 		commit.Message = params.Message
 		commit.Metadata = params.Metadata
-		if branch.CommitID != "" {
+		var amendedCommit *Commit
+		if params.Amend {
+			amendedCommit, err = g.amendableCommit(ctx, repository, branchID, branch.CommitID)
+			if err != nil {
+				return nil, err
+			}
+			if commit.Message == "" {
+				commit.Message = amendedCommit.Message
+			}
+			if commit.Metadata == nil {
+				commit.Metadata = amendedCommit.Metadata
+			}
+			commit.Parents = amendedCommit.Parents
+		} else if branch.CommitID != "" {
 			commit.Parents = CommitParents{branch.CommitID}
 		}
 
@@ -2014,7 +2835,12 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 
 		var branchMetaRangeID MetaRangeID
 		var parentGeneration int
-		if branch.CommitID != "" {
+		switch {
+		case amendedCommit != nil:
+			// changes apply on top of the amended commit, which the new commit replaces
+			branchMetaRangeID = amendedCommit.MetaRangeID
+			parentGeneration = int(amendedCommit.Generation) - 1
+		case branch.CommitID != "":
 			branchCommit, err := g.RefManager.GetCommit(ctx, repository, branch.CommitID)
 			if err != nil {
 				return nil, fmt.Errorf("get commit: %w", err)
@@ -2038,11 +2864,19 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 				return nil, err
 			}
 			defer changes.Close()
-			// returns err if the commit is empty (no changes)
-			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, storageNamespace, branchMetaRangeID, changes, params.AllowEmpty)
+			if params.Filter != nil {
+				changes = newCommitFilterIterator(changes, params.Filter)
+			}
+			// returns err if the commit is empty (no changes), amending may only change the commit message and metadata
+			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, storageNamespace, branchMetaRangeID, changes, params.AllowEmpty || params.Amend)
 			if err != nil {
 				return nil, fmt.Errorf("commit: %w", err)
 			}
+			if params.Filter != nil {
+				if err := g.restageUnselected(ctx, branch, params.Filter); err != nil {
+					return nil, fmt.Errorf("restage uncommitted changes: %w", err)
+				}
+			}
 		}
 		sealedToDrop = branch.SealedTokens
 
@@ -2061,6 +2895,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 	}
 
 	g.dropTokens(ctx, sealedToDrop...)
+	g.recordCommitStats(ctx, repository, newCommitID)
 
 	postRunID := g.hooks.NewRunID()
 	err = g.hooks.PostCommitHook(ctx, HookRecord{
@@ -2073,6 +2908,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 		Commit:           commit,
 		CommitID:         newCommitID,
 		PreRunID:         preRunID,
+		PreviousCommitID: previousCommitID,
 	})
 	if err != nil {
 		g.log(ctx).WithError(err).
@@ -2083,6 +2919,50 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 	return newCommitID, nil
 }
 
+// amendableCommit returns the head commit of branchID for amending it. Commits reachable from other branches
+// can't be amended, as amending replaces the commit only on branchID.
+func (g *Graveler) amendableCommit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, commitID CommitID) (*Commit, error) {
+	if commitID == "" {
+		return nil, fmt.Errorf("amend: %w", ErrCommitNotFound)
+	}
+	commit, err := g.RefManager.GetCommit(ctx, repository, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get commit: %w", err)
+	}
+	branches, err := g.RefManager.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer branches.Close()
+	for branches.Next() {
+		b := branches.Value()
+		if b.BranchID == branchID {
+			continue
+		}
+		// children are created after their parents, no need to look further back in the log
+		commits, err := g.RefManager.Log(ctx, repository, b.CommitID, false, &commit.CreationDate)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for !found && commits.Next() {
+			found = commits.Value().CommitID == commitID
+		}
+		err = commits.Err()
+		commits.Close()
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return nil, fmt.Errorf("commit %s is reachable from branch %s: %w", commitID, b.BranchID, ErrAmendCommitHasChildren)
+		}
+	}
+	if err := branches.Err(); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
 func (g *Graveler) CreateCommitRecord(ctx context.Context, repository *RepositoryRecord, commitID CommitID, commit Commit, opts ...SetOptionsFunc) error {
 	options := &SetOptions{}
 	for _, opt := range opts {
@@ -2202,6 +3082,7 @@ func (g *Graveler) addCommitNoLock(ctx context.Context, repository *RepositoryRe
 	if err != nil {
 		return "", fmt.Errorf("add commit: %w", err)
 	}
+	g.recordCommitStats(ctx, repository, commitID)
 	return commitID, nil
 }
 
@@ -2280,6 +3161,9 @@ func (g *Graveler) ResetHard(ctx context.Context, repository *RepositoryRecord,
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	// TODO(ariels): up to here.  Verify staging is empty!
 	err = g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
@@ -2336,6 +3220,9 @@ func (g *Graveler) Reset(ctx context.Context, repository *RepositoryRecord, bran
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	tokensToDrop := make([]StagingToken, 0)
 	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
@@ -2399,6 +3286,9 @@ func (g *Graveler) ResetKey(ctx context.Context, repository *RepositoryRecord, b
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	branch, err := g.RefManager.GetBranch(ctx, repository, branchID)
 	if err != nil {
@@ -2443,6 +3333,9 @@ func (g *Graveler) ResetPrefix(ctx context.Context, repository *RepositoryRecord
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
 
 	// New sealed tokens list after change includes current staging token
 	newSealedTokens := make([]StagingToken, 0)
@@ -2508,6 +3401,9 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 	if repository.ReadOnly && !options.Force {
 		return "", ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return "", err
+	}
 	commitRecord, err := g.dereferenceCommit(ctx, repository, ref)
 	if err != nil {
 		return "", fmt.Errorf("get commit from ref %s: %w", ref, err)
@@ -2559,6 +3455,7 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 		}
 		commit := NewCommit()
 		commit.Committer = commitParams.Committer
+		commit.Author = CommitAuthorFromContext(ctx)
 		commit.Message = commitParams.Message
 		commit.MetaRangeID = metaRangeID
 		commit.Parents = []CommitID{branch.CommitID}
@@ -2579,6 +3476,7 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 	}
 
 	g.dropTokens(ctx, tokensToDrop...)
+	g.recordCommitStats(ctx, repository, commitID)
 	return commitID, nil
 }
 
@@ -2592,6 +3490,9 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 	if repository.ReadOnly && !options.Force {
 		return "", ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return "", err
+	}
 
 	commitRecord, err := g.dereferenceCommit(ctx, repository, ref)
 	if err != nil {
@@ -2649,6 +3550,7 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 		}
 		commit := NewCommit()
 		commit.Committer = committer
+		commit.Author = CommitAuthorFromContext(ctx)
 		commit.Message = commitRecord.Message
 		commit.MetaRangeID = metaRangeID
 		commit.Parents = []CommitID{branch.CommitID}
@@ -2676,6 +3578,7 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 	}
 
 	g.dropTokens(ctx, tokensToDrop...)
+	g.recordCommitStats(ctx, repository, commitID)
 	return commitID, nil
 }
 
@@ -2687,6 +3590,9 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 	if repository.ReadOnly && !options.Force {
 		return "", ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, destination); err != nil {
+		return "", err
+	}
 
 	var (
 		preRunID string
@@ -2711,13 +3617,16 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		"source":      source,
 		"destination": destination,
 		"strategy":    strategy,
+		"ff_only":     options.FastForwardOnly,
 	})
 
 	var tokensToDrop []StagingToken
 	// No retries on any failure during the merge. If the branch changed, it's either that commit is in progress, commit occurred,
 	// or some other branch changing operation. If commit is in-progress, then staging area wasn't empty after we checked so not retrying is ok.
 	// If another commit/merge succeeded, then the user should decide whether to retry the merge.
+	var previousCommitID CommitID
 	err = g.retryBranchUpdate(ctx, repository, destination, func(branch *Branch) (*Branch, error) {
+		previousCommitID = branch.CommitID
 		empty, err := g.isSealedEmpty(ctx, repository, branch)
 		if err != nil {
 			return nil, fmt.Errorf("check if staging empty: %w", err)
@@ -2741,31 +3650,42 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 			mergeStrategy = MergeStrategyDest
 		case MergeStrategySrcWinsStr:
 			mergeStrategy = MergeStrategySrc
-		case "":
+		case "", MergeStrategyFailStr:
 			mergeStrategy = MergeStrategyNone
 		default:
 			return nil, ErrInvalidMergeStrategy
 		}
 
-		metaRangeID, err := g.CommittedManager.Merge(ctx, storageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID, mergeStrategy)
-		if err != nil {
-			if !errors.Is(err, ErrUserVisible) {
-				err = fmt.Errorf("merge in CommitManager: %w", err)
+		if options.FastForwardOnly {
+			// the destination is an ancestor of the source when it is their merge base
+			baseCommitID := CommitID(ident.NewHexAddressProvider().ContentAddress(baseCommit))
+			if baseCommitID != toCommit.CommitID {
+				return nil, fmt.Errorf("%s is not an ancestor of %s: %w", destination, source, ErrNotFastForward)
 			}
-			return nil, err
-		}
-		commit = NewCommit()
-		commit.Committer = commitParams.Committer
-		commit.Message = commitParams.Message
-		commit.MetaRangeID = metaRangeID
-		commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
-		if toCommit.Generation > fromCommit.Generation {
-			commit.Generation = toCommit.Generation + 1
+			commit = *fromCommit.Commit
+			commitID = fromCommit.CommitID
 		} else {
-			commit.Generation = fromCommit.Generation + 1
+			metaRangeID, err := g.CommittedManager.Merge(ctx, storageNamespace, toCommit.MetaRangeID, fromCommit.MetaRangeID, baseCommit.MetaRangeID, mergeStrategy)
+			if err != nil {
+				if !errors.Is(err, ErrUserVisible) {
+					err = fmt.Errorf("merge in CommitManager: %w", err)
+				}
+				return nil, err
+			}
+			commit = NewCommit()
+			commit.Committer = commitParams.Committer
+			commit.Author = CommitAuthorFromContext(ctx)
+			commit.Message = commitParams.Message
+			commit.MetaRangeID = metaRangeID
+			commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
+			if toCommit.Generation > fromCommit.Generation {
+				commit.Generation = toCommit.Generation + 1
+			} else {
+				commit.Generation = fromCommit.Generation + 1
+			}
+			metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
+			commit.Metadata = metadata
 		}
-		metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
-		commit.Metadata = metadata
 		preRunID = g.hooks.NewRunID()
 		err = g.hooks.PreMergeHook(ctx, HookRecord{
 			EventType:        EventTypePreMerge,
@@ -2798,6 +3718,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 	}
 
 	g.dropTokens(ctx, tokensToDrop...)
+	g.recordCommitStats(ctx, repository, commitID)
 	postRunID := g.hooks.NewRunID()
 	err = g.hooks.PostMergeHook(ctx, HookRecord{
 		EventType:        EventTypePostMerge,
@@ -2806,10 +3727,11 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		StorageNamespace: storageNamespace,
 		BranchID:         destination,
 
-		SourceRef: commitID.Ref(),
-		Commit:    commit,
-		CommitID:  commitID,
-		PreRunID:  preRunID,
+		SourceRef:        commitID.Ref(),
+		Commit:           commit,
+		CommitID:         commitID,
+		PreRunID:         preRunID,
+		PreviousCommitID: previousCommitID,
 	})
 	if err != nil {
 		g.log(ctx).
@@ -2856,6 +3778,9 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 	if repository.ReadOnly && !options.Force {
 		return "", ErrReadOnlyRepository
 	}
+	if err := g.checkBranchLocked(ctx, repository, destination); err != nil {
+		return "", err
+	}
 
 	var (
 		preRunID string
@@ -2873,7 +3798,9 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 	// No retries on any failure during the merge. If the branch changed, it's either that commit is in progress, commit occurred,
 	// or some other branch changing operation. If commit is in-progress, then staging area wasn't empty after we checked so not retrying is ok.
 	// If another commit/merge succeeded, then the user should decide whether to retry the merge.
+	var previousCommitID CommitID
 	err = g.retryBranchUpdate(ctx, repository, destination, func(branch *Branch) (*Branch, error) {
+		previousCommitID = branch.CommitID
 		empty, err := g.isSealedEmpty(ctx, repository, branch)
 		if err != nil {
 			return nil, fmt.Errorf("is staging empty %s: %w", destination, err)
@@ -2902,6 +3829,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 		}
 		commit = NewCommit()
 		commit.Committer = commitParams.Committer
+		commit.Author = CommitAuthorFromContext(ctx)
 		commit.Message = commitParams.Message
 		commit.MetaRangeID = metaRangeID
 		commit.Parents = []CommitID{toCommit.CommitID}
@@ -2941,6 +3869,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 	}
 
 	g.dropTokens(ctx, tokensToDrop...)
+	g.recordCommitStats(ctx, repository, commitID)
 	postRunID := g.hooks.NewRunID()
 	err = g.hooks.PostCommitHook(ctx, HookRecord{
 		EventType:        EventTypePostCommit,
@@ -2952,6 +3881,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 		Commit:           commit,
 		CommitID:         commitID,
 		PreRunID:         preRunID,
+		PreviousCommitID: previousCommitID,
 	})
 	if err != nil {
 		g.log(ctx).WithError(err).
@@ -3078,6 +4008,14 @@ func (g *Graveler) FindMergeBase(ctx context.Context, repository *RepositoryReco
 	return fromCommit, toCommit, baseCommit, nil
 }
 
+func (g *Graveler) CountDivergence(ctx context.Context, repository *RepositoryRecord, left, right CommitID) (int, int, error) {
+	ahead, behind, err := g.RefManager.CountDivergence(ctx, repository, left, right)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count divergence: %w", err)
+	}
+	return ahead, behind, nil
+}
+
 func (g *Graveler) Compare(ctx context.Context, repository *RepositoryRecord, left, right Ref) (DiffIterator, error) {
 	fromCommit, toCommit, baseCommit, err := g.FindMergeBase(ctx, repository, right, left)
 	if err != nil {
@@ -3125,12 +4063,14 @@ func (g *Graveler) LoadCommits(ctx context.Context, repository *RepositoryRecord
 		commitID, err := g.RefManager.AddCommit(ctx, repository, Commit{
 			Version:      CommitVersion(commit.Version),
 			Committer:    commit.GetCommitter(),
+			Author:       commit.GetAuthor(),
 			Message:      commit.GetMessage(),
 			MetaRangeID:  MetaRangeID(commit.GetMetaRangeId()),
 			CreationDate: commit.GetCreationDate().AsTime(),
 			Parents:      parents,
 			Metadata:     commit.GetMetadata(),
 			Generation:   CommitGeneration(commit.GetGeneration()),
+			Stats:        CommitStatsFromProto(commit.GetStats()),
 		})
 		if err != nil {
 			return err
@@ -3167,14 +4107,29 @@ func (g *Graveler) LoadBranches(ctx context.Context, repository *RepositoryRecor
 			return err
 		}
 		branchID := BranchID(branch.Id)
-		err = g.RefManager.SetBranch(ctx, repository, branchID, Branch{
-			CommitID:     CommitID(branch.CommitId),
-			StagingToken: GenerateStagingToken(repository.RepositoryID, branchID),
-			SealedTokens: make([]StagingToken, 0),
-		})
-		if err != nil {
+		// restoring into a repository with the branch discards its uncommitted changes. The branch is reset by a
+		// conditional update, like Reset, so writes to its staging token racing the restore are retried on the new
+		// token or dropped with it.
+		var tokensToDrop []StagingToken
+		err = g.retryBranchUpdate(ctx, repository, branchID, func(existing *Branch) (*Branch, error) {
+			tokensToDrop = append([]StagingToken{existing.StagingToken}, existing.SealedTokens...)
+			return &Branch{
+				CommitID:     CommitID(branch.CommitId),
+				StagingToken: GenerateStagingToken(repository.RepositoryID, branchID),
+				SealedTokens: make([]StagingToken, 0),
+			}, nil
+		}, "load_branches")
+		if errors.Is(err, ErrBranchNotFound) {
+			err = g.RefManager.SetBranch(ctx, repository, branchID, Branch{
+				CommitID:     CommitID(branch.CommitId),
+				StagingToken: GenerateStagingToken(repository.RepositoryID, branchID),
+				SealedTokens: make([]StagingToken, 0),
+			})
+		}
+		if err != nil { // Branch update failed, don't drop staging tokens
 			return err
 		}
+		g.dropTokens(ctx, tokensToDrop...)
 	}
 	if iter.Err() != nil {
 		return iter.Err()
@@ -3190,25 +4145,56 @@ func (g *Graveler) LoadTags(ctx context.Context, repository *RepositoryRecord, m
 	if repository.ReadOnly && !options.Force {
 		return ErrReadOnlyRepository
 	}
-	iter, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	// restoring into a repository with a tag succeeds only if the tag is unchanged. All tags are validated before any
+	// is created, so a conflicting tag fails the restore without changing the repository.
+	tags, err := g.listTagData(ctx, repository, metaRangeID)
 	if err != nil {
 		return err
 	}
-	defer iter.Close()
-	for iter.Next() {
-		rawValue := iter.Value()
-		tag := &TagData{}
-		err := proto.Unmarshal(rawValue.Data, tag)
+	existing := make(map[TagID]bool)
+	for _, tag := range tags {
+		tagID := TagID(tag.Id)
+		commitID, err := g.RefManager.GetTag(ctx, repository, tagID)
+		if errors.Is(err, ErrTagNotFound) {
+			continue
+		}
 		if err != nil {
 			return err
 		}
+		if *commitID != CommitID(tag.CommitId) {
+			return fmt.Errorf("tag %s: %w", tagID, ErrTagAlreadyExists)
+		}
+		existing[tagID] = true
+	}
+	for _, tag := range tags {
 		tagID := TagID(tag.Id)
+		if existing[tagID] {
+			continue
+		}
 		err = g.RefManager.CreateTag(ctx, repository, tagID, CommitID(tag.CommitId))
 		if err != nil {
-			return err
+			return fmt.Errorf("tag %s: %w", tagID, err)
 		}
 	}
-	return iter.Err()
+	return nil
+}
+
+// listTagData reads the tags of a tags dump metarange
+func (g *Graveler) listTagData(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) ([]*TagData, error) {
+	iter, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	var tags []*TagData
+	for iter.Next() {
+		tag := &TagData{}
+		if err := proto.Unmarshal(iter.Value().Data, tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, iter.Err()
 }
 
 func (g *Graveler) GetMetaRange(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) (MetaRangeAddress, error) {
@@ -3219,6 +4205,14 @@ func (g *Graveler) GetRange(ctx context.Context, repository *RepositoryRecord, r
 	return g.CommittedManager.GetRange(ctx, repository.StorageNamespace, rangeID)
 }
 
+func (g *Graveler) ListRanges(ctx context.Context, repository *RepositoryRecord, metaRangeID MetaRangeID) ([]*RangeInfo, error) {
+	return g.CommittedManager.ListRanges(ctx, repository.StorageNamespace, metaRangeID)
+}
+
+func (g *Graveler) ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error) {
+	return g.CommittedManager.ListRange(ctx, repository.StorageNamespace, rangeID)
+}
+
 func (g *Graveler) DumpCommits(ctx context.Context, repository *RepositoryRecord) (*MetaRangeID, error) {
 	iter, err := g.RefManager.ListCommits(ctx, repository)
 	if err != nil {
@@ -3457,12 +4451,14 @@ func (c *commitValueIterator) setValue() bool {
 		Version:      int32(commit.Version),
 		Id:           string(commit.CommitID),
 		Committer:    commit.Committer,
+		Author:       commit.Author,
 		Message:      commit.Message,
 		CreationDate: timestamppb.New(commit.CreationDate),
 		MetaRangeId:  string(commit.MetaRangeID),
 		Metadata:     commit.Metadata,
 		Parents:      commit.Parents.AsStringSlice(),
 		Generation:   int32(commit.Generation),
+		Stats:        ProtoFromCommitStats(commit.Stats),
 	})
 	if err != nil {
 		c.err = err
@@ -3503,7 +4499,9 @@ type GarbageCollectionManager interface {
 	GetRules(ctx context.Context, storageNamespace StorageNamespace) (*GarbageCollectionRules, error)
 	SaveRules(ctx context.Context, storageNamespace StorageNamespace, rules *GarbageCollectionRules) error
 
-	SaveGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules) (string, error)
+	// SaveGarbageCollectionCommits and GetGarbageCollectionCommits consider heldCommits active regardless of the rules
+	SaveGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules, heldCommits []CommitID) (string, error)
+	GetGarbageCollectionCommits(ctx context.Context, repository *RepositoryRecord, rules *GarbageCollectionRules, heldCommits []CommitID) (map[CommitID]MetaRangeID, error)
 	GetCommitsCSVLocation(runID string, sn StorageNamespace) (string, error)
 	SaveGarbageCollectionUncommitted(ctx context.Context, repository *RepositoryRecord, filename, runID string) error
 	GetUncommittedLocation(runID string, sn StorageNamespace) (string, error)
@@ -3524,6 +4522,96 @@ type ProtectedBranchesManager interface {
 	IsBlocked(ctx context.Context, repository *RepositoryRecord, branchID BranchID, action BranchProtectionBlockedAction) (bool, error)
 }
 
+type LockedBranchesManager interface {
+	// GetLocks returns the locks of the branches of the repository.
+	GetLocks(ctx context.Context, repository *RepositoryRecord) (*BranchLocks, error)
+	// GetLock returns the lock of the branch, or nil if the branch is not locked.
+	GetLock(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*BranchLock, error)
+	// Lock locks the branch, replacing its current lock.
+	Lock(ctx context.Context, repository *RepositoryRecord, branchID BranchID, lock *BranchLock) error
+	// Unlock removes the lock of the branch, returns ErrBranchLockNotFound if it is not locked.
+	Unlock(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error
+}
+
+type PassThroughManager interface {
+	// GetMappings returns the pass-through mappings of the repository.
+	GetMappings(ctx context.Context, repository *RepositoryRecord) (*PassThroughMappings, error)
+	// SetMappings replaces the pass-through mappings of the repository.
+	SetMappings(ctx context.Context, repository *RepositoryRecord, mappings *PassThroughMappings) error
+}
+
+type CORSManager interface {
+	// GetRules returns the CORS rules of the repository.
+	GetRules(ctx context.Context, repository *RepositoryRecord) (*CORSRules, error)
+	// SetRules replaces the CORS rules of the repository.
+	SetRules(ctx context.Context, repository *RepositoryRecord, rules *CORSRules) error
+}
+
+type ObjectLockManager interface {
+	// GetConfiguration returns the object lock configuration of the repository.
+	GetConfiguration(ctx context.Context, repository *RepositoryRecord) (*ObjectLockConfiguration, error)
+	// SetConfiguration replaces the object lock configuration of the repository, returns
+	// ErrObjectLockCannotBeDisabled when disabling an enabled object lock.
+	SetConfiguration(ctx context.Context, repository *RepositoryRecord, config *ObjectLockConfiguration) error
+}
+
+type CostAttributionManager interface {
+	// GetAttribution returns the cost attribution of the repository.
+	GetAttribution(ctx context.Context, repository *RepositoryRecord) (*CostAttribution, error)
+	// SetAttribution replaces the cost attribution of the repository.
+	SetAttribution(ctx context.Context, repository *RepositoryRecord, attribution *CostAttribution) error
+}
+
+type KeyValidationManager interface {
+	// GetRules returns the key validation rules of the repository.
+	GetRules(ctx context.Context, repository *RepositoryRecord) (*KeyValidationRules, error)
+	// SetRules replaces the key validation rules of the repository.
+	SetRules(ctx context.Context, repository *RepositoryRecord, rules *KeyValidationRules) error
+}
+
+type CommitTemplateManager interface {
+	// GetTemplate returns the commit template of the repository.
+	GetTemplate(ctx context.Context, repository *RepositoryRecord) (*CommitTemplate, error)
+	// SetTemplate replaces the commit template of the repository.
+	SetTemplate(ctx context.Context, repository *RepositoryRecord, template *CommitTemplate) error
+}
+
+type BucketNotificationManager interface {
+	// GetNotifications returns the S3 bucket notification configurations of the repository.
+	GetNotifications(ctx context.Context, repository *RepositoryRecord) (*BucketNotifications, error)
+	// SetNotifications replaces the S3 bucket notification configurations of the repository.
+	SetNotifications(ctx context.Context, repository *RepositoryRecord, notifications *BucketNotifications) error
+}
+
+type BlockAdapterOverrideManager interface {
+	// GetOverride returns the block adapter override of the repository.
+	GetOverride(ctx context.Context, repository *RepositoryRecord) (*BlockAdapterOverride, error)
+	// SetOverride replaces the block adapter override of the repository.
+	SetOverride(ctx context.Context, repository *RepositoryRecord, override *BlockAdapterOverride) error
+}
+
+type LifecycleRulesManager interface {
+	// GetRules returns the lifecycle rules of the repository
+	GetRules(ctx context.Context, repository *RepositoryRecord) (*LifecycleRules, error)
+	// SetRules replaces the lifecycle rules of the repository
+	SetRules(ctx context.Context, repository *RepositoryRecord, rules *LifecycleRules) error
+}
+
+type LegalHoldManager interface {
+	// GetHolds returns the legal holds of the repository.
+	GetHolds(ctx context.Context, repository *RepositoryRecord) (*LegalHolds, error)
+	// SetCommitHold places or replaces the legal hold of a commit.
+	SetCommitHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID, hold *LegalHold) error
+	// DeleteCommitHold releases the legal hold of a commit, returns ErrLegalHoldNotFound if it is not held.
+	DeleteCommitHold(ctx context.Context, repository *RepositoryRecord, commitID CommitID) error
+	// SetTagHold places or replaces the legal hold of a tag.
+	SetTagHold(ctx context.Context, repository *RepositoryRecord, tagID TagID, hold *LegalHold) error
+	// DeleteTagHold releases the legal hold of a tag, returns ErrLegalHoldNotFound if it is not held.
+	DeleteTagHold(ctx context.Context, repository *RepositoryRecord, tagID TagID) error
+	// IsTagHeld returns whether the tag is under legal hold.
+	IsTagHeld(ctx context.Context, repository *RepositoryRecord, tagID TagID) (bool, error)
+}
+
 // NewRepoInstanceID Returns a new unique identifier for the repository instance
 func NewRepoInstanceID() string {
 	tm := time.Now().UTC()
@@ -138,6 +138,14 @@ func (h *Hooks) PostDeleteBranchHook(_ context.Context, record graveler.HookReco
 	h.BranchID = record.BranchID
 }
 
+func (h *Hooks) CheckRenameBranch(_ context.Context, record graveler.HookRecord, _ graveler.BranchID) error {
+	h.Called = true
+	h.StorageNamespace = record.StorageNamespace
+	h.RepositoryID = record.RepositoryID
+	h.BranchID = record.BranchID
+	return h.Err
+}
+
 func (h *Hooks) NewRunID() string {
 	return ""
 }
@@ -148,7 +156,7 @@ func newGraveler(t *testing.T, committedManager graveler.CommittedManager, stagi
 ) catalog.Store {
 	t.Helper()
 
-	return graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager)
+	return graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager, testutil.NewLegalHoldManagerFake(), testutil.NewLockedBranchesManagerFake(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
 }
 
 func TestGraveler_List(t *testing.T) {
@@ -284,6 +292,41 @@ func TestGraveler_Get(t *testing.T) {
 	}
 }
 
+func TestGraveler_GetResolvedCommitID(t *testing.T) {
+	const commitID = graveler.CommitID("c1")
+	tests := []struct {
+		name             string
+		staging          *testutil.StagingFake
+		expectedCommitID graveler.CommitID
+	}{
+		{
+			name:             "committed",
+			staging:          &testutil.StagingFake{Err: graveler.ErrNotFound},
+			expectedCommitID: commitID,
+		},
+		{
+			name:             "staged",
+			staging:          &testutil.StagingFake{Value: &graveler.Value{Identity: []byte("staged")}},
+			expectedCommitID: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newGraveler(t, &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"key": {Identity: []byte("committed")}}}, tt.staging,
+				&testutil.RefsFake{RefType: graveler.ReferenceTypeBranch, StagingToken: "token1", CommitID: commitID, Commits: map[graveler.CommitID]*graveler.Commit{commitID: {}}}, nil, testutil.NewProtectedBranchesManagerFake(),
+			)
+			var resolved graveler.CommitID
+			_, err := r.Get(context.Background(), repository, "", []byte("key"), graveler.WithResolvedCommitID(&resolved))
+			if err != nil {
+				t.Fatalf("Get failed: %s", err)
+			}
+			if resolved != tt.expectedCommitID {
+				t.Errorf("wrong resolved commit ID, expected:%s got:%s", tt.expectedCommitID, resolved)
+			}
+		})
+	}
+}
+
 func TestGraveler_Set(t *testing.T) {
 	newSetVal := &graveler.ValueRecord{Key: []byte("key"), Value: &graveler.Value{Data: []byte("newValue"), Identity: []byte("newIdentity")}}
 	sampleVal := &graveler.Value{Identity: []byte("sampleIdentity"), Data: []byte("sampleValue")}
@@ -324,6 +367,7 @@ func TestGraveler_Set(t *testing.T) {
 			stagingMgr:          &testutil.StagingFake{},
 			refMgr:              &testutil.RefsFake{Branch: &graveler.Branch{CommitID: "bla"}, Commits: map[graveler.CommitID]*graveler.Commit{"": {}}},
 			expectedValueResult: nil,
+			expectedErr:         graveler.ErrPreconditionFailed,
 			ifAbsent:            true,
 		},
 		{
@@ -332,6 +376,7 @@ func TestGraveler_Set(t *testing.T) {
 			stagingMgr:          &testutil.StagingFake{Values: map[string]map[string]*graveler.Value{"st": {"key": sampleVal}}, LastSetValueRecord: &graveler.ValueRecord{Key: []byte("key"), Value: sampleVal}},
 			refMgr:              &testutil.RefsFake{Branch: &graveler.Branch{CommitID: "bla", StagingToken: "st"}, Commits: map[graveler.CommitID]*graveler.Commit{"": {}}},
 			expectedValueResult: &graveler.ValueRecord{Key: []byte("key"), Value: sampleVal},
+			expectedErr:         graveler.ErrPreconditionFailed,
 			ifAbsent:            true,
 		},
 		{
@@ -361,6 +406,67 @@ func TestGraveler_Set(t *testing.T) {
 	}
 }
 
+func TestGraveler_SetIfMatch(t *testing.T) {
+	newSetVal := &graveler.ValueRecord{Key: []byte("path"), Value: &graveler.Value{Data: []byte("newValue"), Identity: []byte("newIdentity")}}
+	committedVal := &graveler.Value{Identity: []byte("committedIdentity"), Data: []byte("committedValue")}
+	stagedVal := &graveler.Value{Identity: []byte("stagedIdentity"), Data: []byte("stagedValue")}
+	tests := []struct {
+		name         string
+		ifMatch      string
+		committedMgr *testutil.CommittedFake
+		stagingMgr   *testutil.StagingFake
+		expectedErr  error
+	}{
+		{
+			name:         "committed match",
+			ifMatch:      "committedIdentity",
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"path": committedVal}},
+			stagingMgr:   &testutil.StagingFake{},
+		},
+		{
+			name:         "committed mismatch",
+			ifMatch:      "otherIdentity",
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"path": committedVal}},
+			stagingMgr:   &testutil.StagingFake{},
+			expectedErr:  graveler.ErrPreconditionFailed,
+		},
+		{
+			name:         "staged match",
+			ifMatch:      "stagedIdentity",
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"path": committedVal}},
+			stagingMgr:   &testutil.StagingFake{Values: map[string]map[string]*graveler.Value{"st": {"path": stagedVal}}},
+		},
+		{
+			name:         "staged over committed match",
+			ifMatch:      "committedIdentity",
+			committedMgr: &testutil.CommittedFake{ValuesByKey: map[string]*graveler.Value{"path": committedVal}},
+			stagingMgr:   &testutil.StagingFake{Values: map[string]map[string]*graveler.Value{"st": {"path": stagedVal}}},
+			expectedErr:  graveler.ErrPreconditionFailed,
+		},
+		{
+			name:         "not found",
+			ifMatch:      "committedIdentity",
+			committedMgr: &testutil.CommittedFake{Err: graveler.ErrNotFound},
+			stagingMgr:   &testutil.StagingFake{},
+			expectedErr:  graveler.ErrPreconditionFailed,
+		},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refMgr := &testutil.RefsFake{Branch: &graveler.Branch{CommitID: "bla", StagingToken: "st"}, RefType: graveler.ReferenceTypeBranch, StagingToken: "st", Commits: map[graveler.CommitID]*graveler.Commit{"": {}}}
+			store := newGraveler(t, tt.committedMgr, tt.stagingMgr, refMgr, nil, testutil.NewProtectedBranchesManagerFake())
+			err := store.Set(ctx, repository, "branch-1", newSetVal.Key, *newSetVal.Value, graveler.WithIfMatch([]byte(tt.ifMatch)))
+			require.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, newSetVal, tt.stagingMgr.LastSetValueRecord)
+			} else {
+				require.Nil(t, tt.stagingMgr.LastSetValueRecord)
+			}
+		})
+	}
+}
+
 func TestGravelerSet_Advanced(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	ctx := context.Background()
@@ -1352,6 +1458,79 @@ func TestGravelerCommit(t *testing.T) {
 	}
 }
 
+func TestGravelerCommit_Stats(t *testing.T) {
+	const (
+		parentCommitID    = graveler.CommitID("parent")
+		parentMetaRangeID = graveler.MetaRangeID("parentMetaRange")
+		newMetaRangeID    = graveler.MetaRangeID("newMetaRange")
+	)
+	value := func(size int) *graveler.Value {
+		return &graveler.Value{Identity: []byte(strconv.Itoa(size)), Data: make([]byte, size)}
+	}
+	diffs := []graveler.Diff{
+		{Type: graveler.DiffTypeAdded, Key: graveler.Key("added"), Value: value(5)},
+		{Type: graveler.DiffTypeChanged, Key: graveler.Key("changed"), Value: value(10)},
+		{Type: graveler.DiffTypeRemoved, Key: graveler.Key("removed"), Value: value(3)},
+	}
+	tests := []struct {
+		name        string
+		parentStats *graveler.CommitStats
+		expected    *graveler.CommitStats
+	}{
+		{
+			name:        "parent with stats",
+			parentStats: &graveler.CommitStats{ObjectCount: 10, SizeBytes: 1000},
+			expected:    &graveler.CommitStats{ObjectCount: 10, SizeBytes: 1008, ObjectCountDelta: 0, SizeBytesDelta: 8},
+		},
+		{
+			name:     "parent without stats",
+			expected: &graveler.CommitStats{ObjectCount: 3, SizeBytes: 17, ObjectCountDelta: 0, SizeBytesDelta: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			committedManager := &testutil.CommittedFake{
+				MetaRangeID:  newMetaRangeID,
+				DiffIterator: testutil.NewDiffIter(diffs),
+				ValuesByKey:  map[string]*graveler.Value{"changed": value(4)},
+				Values: map[string]graveler.ValueIterator{
+					newMetaRangeID.String(): testutil.NewValueIteratorFake([]graveler.ValueRecord{
+						{Key: graveler.Key("added"), Value: value(5)},
+						{Key: graveler.Key("changed"), Value: value(10)},
+						{Key: graveler.Key("kept"), Value: value(2)},
+					}),
+				},
+			}
+			refManager := &testutil.RefsFake{
+				CommitID: "new",
+				Branch:   &graveler.Branch{CommitID: parentCommitID},
+				Commits: map[graveler.CommitID]*graveler.Commit{
+					parentCommitID: {MetaRangeID: parentMetaRangeID, Stats: tt.parentStats},
+					// read back to compute its stats once added
+					"new": {MetaRangeID: newMetaRangeID, Parents: graveler.CommitParents{parentCommitID}},
+				},
+			}
+			stagingManager := &testutil.StagingFake{ValueIterator: testutil.NewValueIteratorFake([]graveler.ValueRecord{})}
+			g := graveler.NewGraveler(committedManager, stagingManager, refManager, nil, testutil.NewProtectedBranchesManagerFake(), testutil.NewLegalHoldManagerFake(), testutil.NewLockedBranchesManagerFake(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			g.ValueSize = func(value *graveler.Value) (int64, error) {
+				return int64(len(value.Data)), nil
+			}
+
+			commitID, err := g.Commit(context.Background(), repository, "branch", graveler.CommitParams{Committer: "committer", Message: "message"})
+			require.NoError(t, err)
+			// stats are computed in the background, after the commit is added
+			require.Nil(t, refManager.AddedCommit.Stats)
+			var stats graveler.CommitStats
+			require.Eventually(t, func() bool {
+				var ok bool
+				stats, ok = refManager.GetCommitStats(commitID)
+				return ok
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(t, *tt.expected, stats)
+		})
+	}
+}
+
 // TestGraveler_MergeInvalidRef test merge with invalid source reference in order
 func TestGraveler_MergeInvalidRef(t *testing.T) {
 	// prepare graveler
@@ -2093,6 +2272,31 @@ func TestGraveler_PreDeleteTagHook(t *testing.T) {
 	}
 }
 
+func TestGraveler_DeleteTagLegalHold(t *testing.T) {
+	const expectedCommitID = graveler.CommitID("expectedCommitID")
+	const heldTagID = graveler.TagID("heldTagID")
+	ctx := context.Background()
+	expected := expectedCommitID
+	refManager := &testutil.RefsFake{TagCommitID: &expected}
+	g := graveler.NewGraveler(&testutil.CommittedFake{}, &testutil.StagingFake{}, refManager, nil,
+		testutil.NewProtectedBranchesManagerFake(), testutil.NewLegalHoldManagerFake(heldTagID), testutil.NewLockedBranchesManagerFake(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	h := &Hooks{}
+	g.SetHooksHandler(h)
+
+	err := g.DeleteTag(ctx, repository, heldTagID)
+	if !errors.Is(err, graveler.ErrLegalHold) {
+		t.Fatalf("Delete tag err=%v, expected=%v", err, graveler.ErrLegalHold)
+	}
+	if h.Called {
+		t.Fatal("Pre delete tag hook called for a tag under legal hold")
+	}
+
+	err = g.DeleteTag(ctx, repository, "otherTagID")
+	if err != nil {
+		t.Fatalf("Delete tag err=%v, expected no error", err)
+	}
+}
+
 func TestGraveler_PreCreateBranchHook(t *testing.T) {
 	const expectedRangeID = graveler.MetaRangeID("expectedRangeID")
 	const sourceCommitID = graveler.CommitID("sourceCommitID")
@@ -2181,6 +2385,58 @@ func TestGraveler_PreCreateBranchHook(t *testing.T) {
 	}
 }
 
+func TestGraveler_DeleteBranchKeepsHead(t *testing.T) {
+	ctx := context.Background()
+	const commitID = graveler.CommitID("commitID")
+	refManager := &testutil.RefsFake{
+		Branch:       &graveler.Branch{CommitID: commitID, StagingToken: "token"},
+		StagingToken: "token",
+	}
+	g := graveler.NewGraveler(&testutil.CommittedFake{}, &testutil.StagingFake{}, refManager, nil, testutil.NewProtectedBranchesManagerFake(), testutil.NewLegalHoldManagerFake(), testutil.NewLockedBranchesManagerFake(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	g.DeletedBranchRetention = time.Hour
+
+	if err := g.DeleteBranch(ctx, repository, "feature"); err != nil {
+		t.Fatalf("Delete branch: %s", err)
+	}
+	deletedBranch, ok := refManager.DeletedBranches["feature"]
+	if !ok {
+		t.Fatal("Deleted branch not kept")
+	}
+	if deletedBranch.CommitID != commitID {
+		t.Errorf("Deleted branch commit ID = %s, expected %s", deletedBranch.CommitID, commitID)
+	}
+	if retention := deletedBranch.ExpiresAt.Sub(deletedBranch.DeletedAt); retention != time.Hour {
+		t.Errorf("Deleted branch kept for %s, expected %s", retention, time.Hour)
+	}
+
+	// expired deleted branches are not listed
+	refManager.DeletedBranches["expired"] = &graveler.DeletedBranchRecord{
+		BranchID:  "expired",
+		CommitID:  commitID,
+		DeletedAt: time.Now().Add(-2 * time.Hour),
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+	it, err := g.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		t.Fatalf("List deleted branches: %s", err)
+	}
+	defer it.Close()
+	var branchIDs []graveler.BranchID
+	for it.Next() {
+		branchIDs = append(branchIDs, it.Value().BranchID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iterate deleted branches: %s", err)
+	}
+	if diff := deep.Equal(branchIDs, []graveler.BranchID{"feature"}); diff != nil {
+		t.Errorf("Unexpected deleted branches: %s", diff)
+	}
+	_, err = g.RestoreBranch(ctx, repository, "expired")
+	if !errors.Is(err, graveler.ErrDeletedBranchNotFound) {
+		t.Errorf("Restore expired branch err=%v, expected=%v", err, graveler.ErrDeletedBranchNotFound)
+	}
+}
+
 func TestGraveler_PreDeleteBranchHook(t *testing.T) {
 	// prepare graveler
 	const expectedRangeID = graveler.MetaRangeID("expectedRangeID")
@@ -188,6 +188,7 @@ func TestGravelerMerge(t *testing.T) {
 	}
 	t.Run("merge successful", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
@@ -226,6 +227,7 @@ func TestGravelerMerge(t *testing.T) {
 
 	t.Run("merge dirty destination while updating tokens", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
 			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
 				branchTest := branch1
@@ -257,6 +259,7 @@ func TestGravelerMerge(t *testing.T) {
 
 	t.Run("merge successful with branchUpdate retry", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 
 		// upgrade graveler branch update back-off to shorten test duration
 		const updateRetryDuration = 200 * time.Millisecond
@@ -304,6 +307,7 @@ func TestGravelerMerge(t *testing.T) {
 
 	t.Run("merge fails due to BranchUpdate retries exhaustion", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 1)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
@@ -318,6 +322,17 @@ func TestGravelerMerge(t *testing.T) {
 		require.ErrorIs(t, err, graveler.ErrTooManyTries)
 		require.Empty(t, val)
 	})
+
+	t.Run("merge into locked branch", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(&graveler.BranchLock{Reason: "freeze", LockedBy: "admin"}, nil)
+
+		val, err := test.Sut.Merge(ctx, repository, branch1ID, graveler.Ref(branch2ID), graveler.CommitParams{Metadata: graveler.Metadata{}}, "")
+
+		require.ErrorIs(t, err, graveler.ErrBranchLocked)
+		require.ErrorContains(t, err, "freeze")
+		require.Empty(t, val)
+	})
 }
 
 func TestGravelerRevert(t *testing.T) {
@@ -356,6 +371,7 @@ func TestGravelerRevert(t *testing.T) {
 	}
 	t.Run("revert successful", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
@@ -396,6 +412,7 @@ func TestGravelerRevert(t *testing.T) {
 
 	t.Run("revert dirty branch after token update", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 1)
 		dirtyStagingTokenCombo(test)
@@ -449,6 +466,7 @@ func TestGravelerCherryPick(t *testing.T) {
 	}
 	t.Run("cherry-pick successful", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
@@ -493,6 +511,7 @@ func TestGravelerCommit_v2(t *testing.T) {
 
 	t.Run("commit with sealed tokens", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		var updatedSealedBranch graveler.Branch
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
@@ -535,6 +554,7 @@ func TestGravelerCommit_v2(t *testing.T) {
 
 	t.Run("commit no changes", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		var updatedSealedBranch graveler.Branch
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
@@ -574,6 +594,7 @@ func TestGravelerCommit_v2(t *testing.T) {
 
 	t.Run("commit failed retryUpdateBranch", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
 
 		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
@@ -646,6 +667,7 @@ func TestGravelerImport(t *testing.T) {
 
 	t.Run("import successful", func(t *testing.T) {
 		test := testutil.InitGravelerTest(t)
+		test.LockedBranchesManager.EXPECT().GetLock(ctx, repository, branch1ID).Return(nil, nil)
 		firstUpdateBranch(test)
 		emptyStagingTokenCombo(test, 2)
 		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(3).Return(&commit1, nil)
@@ -45,6 +45,8 @@ type HookRecord struct {
 	CommitID CommitID
 	// Exists only in post actions. Contains the ID of the pre-action associated with this post-action
 	PreRunID string
+	// Exists only in post-commit and post-merge actions. The commit the branch pointed to before the operation
+	PreviousCommitID CommitID
 	// Exists only in tag actions.
 	TagID TagID
 }
@@ -62,6 +64,9 @@ type HooksHandler interface {
 	PostCreateBranchHook(ctx context.Context, record HookRecord)
 	PreDeleteBranchHook(ctx context.Context, record HookRecord) error
 	PostDeleteBranchHook(ctx context.Context, record HookRecord)
+	// CheckRenameBranch fails with ErrConflictFound if hooks of the branch of record select it by name, and would no
+	// longer select it once renamed to newBranchID
+	CheckRenameBranch(ctx context.Context, record HookRecord, newBranchID BranchID) error
 	// NewRunID TODO (niro): WA for now until KV feature complete
 	NewRunID() string
 }
@@ -112,6 +117,10 @@ func (h *HooksNoOp) PreDeleteBranchHook(context.Context, HookRecord) error {
 func (h *HooksNoOp) PostDeleteBranchHook(context.Context, HookRecord) {
 }
 
+func (h *HooksNoOp) CheckRenameBranch(context.Context, HookRecord, BranchID) error {
+	return nil
+}
+
 func (h *HooksNoOp) NewRunID() string {
 	return NewRunID()
 }
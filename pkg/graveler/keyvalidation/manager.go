@@ -0,0 +1,118 @@
+package keyvalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "key_validation"
+
+// Names of the rules reported by graveler.KeyValidationError
+const (
+	RuleMaxKeyLength        = "max_key_length"
+	RuleMaxPathDepth        = "max_path_depth"
+	RuleForbiddenCharacters = "forbidden_characters"
+	RuleRequiredLayout      = "required_layout"
+)
+
+const pathSeparator = "/"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetRules returns the key validation rules of the repository. Rules are read from the settings cache, as they are
+// consulted on every write to the repository.
+func (m *Manager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.KeyValidationRules, error) {
+	rules := &graveler.KeyValidationRules{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, rules)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (m *Manager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.KeyValidationRules) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, rules, nil)
+}
+
+// compileLayout compiles the required layout of rules, anchored to the start of the key
+func compileLayout(layout string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + layout + ")")
+}
+
+// ValidateRules verifies that rules can be enforced
+func ValidateRules(rules *graveler.KeyValidationRules) error {
+	if rules.MaxKeyLength < 0 {
+		return fmt.Errorf("max key length %d: %w", rules.MaxKeyLength, graveler.ErrInvalidValue)
+	}
+	if rules.MaxPathDepth < 0 {
+		return fmt.Errorf("max path depth %d: %w", rules.MaxPathDepth, graveler.ErrInvalidValue)
+	}
+	if rules.RequiredLayout != "" {
+		if _, err := compileLayout(rules.RequiredLayout); err != nil {
+			return fmt.Errorf("required layout: %s: %w", err, graveler.ErrInvalidValue)
+		}
+	}
+	return nil
+}
+
+// Validate returns a *graveler.KeyValidationError when key breaks one of rules
+func Validate(rules *graveler.KeyValidationRules, key graveler.Key) error {
+	k := key.String()
+	if rules.MaxKeyLength > 0 && len(k) > int(rules.MaxKeyLength) {
+		return &graveler.KeyValidationError{
+			Key:    key,
+			Rule:   RuleMaxKeyLength,
+			Reason: fmt.Sprintf("%d bytes long, up to %d allowed", len(k), rules.MaxKeyLength),
+		}
+	}
+	if rules.MaxPathDepth > 0 {
+		// a trailing separator marks a directory, and does not add a component
+		depth := strings.Count(strings.TrimSuffix(k, pathSeparator), pathSeparator) + 1
+		if depth > int(rules.MaxPathDepth) {
+			return &graveler.KeyValidationError{
+				Key:    key,
+				Rule:   RuleMaxPathDepth,
+				Reason: fmt.Sprintf("%d path components, up to %d allowed", depth, rules.MaxPathDepth),
+			}
+		}
+	}
+	if rules.ForbiddenCharacters != "" {
+		if i := strings.IndexAny(k, rules.ForbiddenCharacters); i >= 0 {
+			r := []rune(k[i:])[0]
+			return &graveler.KeyValidationError{
+				Key:    key,
+				Rule:   RuleForbiddenCharacters,
+				Reason: fmt.Sprintf("contains forbidden character %q", r),
+			}
+		}
+	}
+	if rules.RequiredLayout != "" {
+		layout, err := compileLayout(rules.RequiredLayout)
+		if err != nil {
+			return fmt.Errorf("required layout: %w", err)
+		}
+		if !layout.MatchString(k) {
+			return &graveler.KeyValidationError{
+				Key:    key,
+				Rule:   RuleRequiredLayout,
+				Reason: fmt.Sprintf("does not match layout %s", rules.RequiredLayout),
+			}
+		}
+	}
+	return nil
+}
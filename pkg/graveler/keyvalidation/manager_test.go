@@ -0,0 +1,109 @@
+package keyvalidation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/keyvalidation"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func newRepository(id graveler.RepositoryID) *graveler.RepositoryRecord {
+	return &graveler.RepositoryRecord{
+		RepositoryID: id,
+		Repository: &graveler.Repository{
+			StorageNamespace: "mem://my-storage",
+			DefaultBranchID:  "main",
+		},
+	}
+}
+
+func TestGetRulesNotSet(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	rules, err := m.GetRules(ctx, newRepository("example-repo"))
+	require.NoError(t, err)
+	require.Zero(t, rules.GetMaxKeyLength())
+	require.Empty(t, rules.GetRequiredLayout())
+}
+
+func TestSetRules(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+	repository := newRepository("example-repo")
+
+	err := m.SetRules(ctx, repository, &graveler.KeyValidationRules{
+		MaxKeyLength:        256,
+		MaxPathDepth:        4,
+		ForbiddenCharacters: " \\",
+		RequiredLayout:      `tables/[a-z_]+/dt=\d{4}-\d{2}-\d{2}/`,
+	})
+	require.NoError(t, err)
+
+	rules, err := m.GetRules(ctx, repository)
+	require.NoError(t, err)
+	require.Equal(t, int32(256), rules.GetMaxKeyLength())
+	require.Equal(t, int32(4), rules.GetMaxPathDepth())
+	require.Equal(t, " \\", rules.GetForbiddenCharacters())
+	require.Equal(t, `tables/[a-z_]+/dt=\d{4}-\d{2}-\d{2}/`, rules.GetRequiredLayout())
+}
+
+func TestValidate(t *testing.T) {
+	rules := &graveler.KeyValidationRules{
+		MaxKeyLength:        48,
+		MaxPathDepth:        4,
+		ForbiddenCharacters: " :",
+		RequiredLayout:      `tables/[a-z_]+/dt=\d{4}-\d{2}-\d{2}/`,
+	}
+	testCases := []struct {
+		Name string
+		Key  string
+		Rule string
+	}{
+		{Name: "valid", Key: "tables/events/dt=2024-01-01/part-0.parquet"},
+		{Name: "valid directory", Key: "tables/events/dt=2024-01-01/"},
+		{Name: "too long", Key: "tables/events/dt=2024-01-01/part-00000000000000000.parquet", Rule: keyvalidation.RuleMaxKeyLength},
+		{Name: "too deep", Key: "tables/events/dt=2024-01-01/h=1/part-0", Rule: keyvalidation.RuleMaxPathDepth},
+		{Name: "forbidden character", Key: "tables/events/dt=2024-01-01/a:b", Rule: keyvalidation.RuleForbiddenCharacters},
+		{Name: "layout", Key: "tables/events/2024-01-01/part-0", Rule: keyvalidation.RuleRequiredLayout},
+		{Name: "layout not at start", Key: "x/tables/events/dt=2024-01-01/", Rule: keyvalidation.RuleRequiredLayout},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := keyvalidation.Validate(rules, graveler.Key(tc.Key))
+			if tc.Rule == "" {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *graveler.KeyValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a key validation error, got %v", err)
+			require.Equal(t, tc.Rule, validationErr.Rule)
+			require.ErrorIs(t, err, graveler.ErrInvalidKey)
+		})
+	}
+}
+
+func TestValidateNoRules(t *testing.T) {
+	require.NoError(t, keyvalidation.Validate(&graveler.KeyValidationRules{}, graveler.Key("any/key with spaces")))
+}
+
+func TestValidateRules(t *testing.T) {
+	require.NoError(t, keyvalidation.ValidateRules(&graveler.KeyValidationRules{RequiredLayout: `data/\w+/`}))
+	require.ErrorIs(t, keyvalidation.ValidateRules(&graveler.KeyValidationRules{MaxKeyLength: -1}), graveler.ErrInvalidValue)
+	require.ErrorIs(t, keyvalidation.ValidateRules(&graveler.KeyValidationRules{RequiredLayout: "data/("}), graveler.ErrInvalidValue)
+}
+
+func prepareTest(t *testing.T, ctx context.Context) *keyvalidation.Manager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(newRepository("example-repo"), nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	return keyvalidation.NewManager(settings.NewManager(refManager, kvStore))
+}
@@ -0,0 +1,102 @@
+package legalhold
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "legal_holds"
+
+// maxUpdateTries is the number of attempts to update the legal holds when they are concurrently modified
+const maxUpdateTries = 5
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+func (m *Manager) GetHolds(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LegalHolds, error) {
+	holds := &graveler.LegalHolds{}
+	_, err := m.settingManager.GetLatest(ctx, repository, SettingKey, holds)
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+func (m *Manager) SetCommitHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, hold *graveler.LegalHold) error {
+	return m.update(ctx, repository, func(holds *graveler.LegalHolds) error {
+		if holds.Commits == nil {
+			holds.Commits = make(map[string]*graveler.LegalHold)
+		}
+		holds.Commits[commitID.String()] = hold
+		return nil
+	})
+}
+
+func (m *Manager) DeleteCommitHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) error {
+	return m.update(ctx, repository, func(holds *graveler.LegalHolds) error {
+		if _, ok := holds.Commits[commitID.String()]; !ok {
+			return graveler.ErrLegalHoldNotFound
+		}
+		delete(holds.Commits, commitID.String())
+		return nil
+	})
+}
+
+func (m *Manager) SetTagHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, hold *graveler.LegalHold) error {
+	return m.update(ctx, repository, func(holds *graveler.LegalHolds) error {
+		if holds.Tags == nil {
+			holds.Tags = make(map[string]*graveler.LegalHold)
+		}
+		holds.Tags[tagID.String()] = hold
+		return nil
+	})
+}
+
+func (m *Manager) DeleteTagHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) error {
+	return m.update(ctx, repository, func(holds *graveler.LegalHolds) error {
+		if _, ok := holds.Tags[tagID.String()]; !ok {
+			return graveler.ErrLegalHoldNotFound
+		}
+		delete(holds.Tags, tagID.String())
+		return nil
+	})
+}
+
+func (m *Manager) IsTagHeld(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (bool, error) {
+	holds, err := m.GetHolds(ctx, repository)
+	if err != nil {
+		return false, err
+	}
+	_, ok := holds.Tags[tagID.String()]
+	return ok, nil
+}
+
+// update applies fn to the latest legal holds of the repository and saves the result, retrying when the holds
+// were modified concurrently. Saved holds count their revision: empty holds would have the checksum of no holds,
+// that the settings manager only saves when no holds were ever saved.
+func (m *Manager) update(ctx context.Context, repository *graveler.RepositoryRecord, fn func(holds *graveler.LegalHolds) error) error {
+	for try := 0; try < maxUpdateTries; try++ {
+		holds := &graveler.LegalHolds{}
+		checksum, err := m.settingManager.GetLatest(ctx, repository, SettingKey, holds)
+		if err != nil {
+			return err
+		}
+		if err := fn(holds); err != nil {
+			return err
+		}
+		holds.Revision++
+		err = m.settingManager.Save(ctx, repository, SettingKey, holds, checksum)
+		if !errors.Is(err, graveler.ErrPreconditionFailed) {
+			return err
+		}
+	}
+	return graveler.ErrTooManyTries
+}
@@ -0,0 +1,103 @@
+package legalhold_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/legalhold"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var repository = &graveler.RepositoryRecord{
+	RepositoryID: "example-repo",
+	Repository: &graveler.Repository{
+		StorageNamespace: "mem://my-storage",
+		DefaultBranchID:  "main",
+	},
+}
+
+func TestSetAndDeleteHolds(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	holds, err := m.GetHolds(ctx, repository)
+	require.NoError(t, err)
+	require.Empty(t, holds.Commits)
+	require.Empty(t, holds.Tags)
+
+	hold := &graveler.LegalHold{Reason: "case 1", CreatedBy: "admin", CreationDate: timestamppb.Now()}
+	require.NoError(t, m.SetCommitHold(ctx, repository, "c1", hold))
+	require.NoError(t, m.SetTagHold(ctx, repository, "v1", hold))
+	require.NoError(t, m.SetTagHold(ctx, repository, "v2", hold))
+
+	holds, err = m.GetHolds(ctx, repository)
+	require.NoError(t, err)
+	require.Len(t, holds.Commits, 1)
+	require.Equal(t, "case 1", holds.Commits["c1"].GetReason())
+	require.Len(t, holds.Tags, 2)
+
+	held, err := m.IsTagHeld(ctx, repository, "v1")
+	require.NoError(t, err)
+	require.True(t, held)
+	held, err = m.IsTagHeld(ctx, repository, "v3")
+	require.NoError(t, err)
+	require.False(t, held)
+
+	require.NoError(t, m.DeleteTagHold(ctx, repository, "v1"))
+	held, err = m.IsTagHeld(ctx, repository, "v1")
+	require.NoError(t, err)
+	require.False(t, held)
+
+	require.NoError(t, m.DeleteCommitHold(ctx, repository, "c1"))
+	holds, err = m.GetHolds(ctx, repository)
+	require.NoError(t, err)
+	require.Empty(t, holds.Commits)
+	require.Len(t, holds.Tags, 1)
+}
+
+func TestHoldAfterReleasingAll(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	hold := &graveler.LegalHold{Reason: "case 1", CreatedBy: "admin", CreationDate: timestamppb.Now()}
+	require.NoError(t, m.SetTagHold(ctx, repository, "v1", hold))
+	require.NoError(t, m.DeleteTagHold(ctx, repository, "v1"))
+	holds, err := m.GetHolds(ctx, repository)
+	require.NoError(t, err)
+	require.Empty(t, holds.Tags)
+
+	// releasing the last hold leaves no holds, which are held again
+	require.NoError(t, m.SetCommitHold(ctx, repository, "c1", hold))
+	holds, err = m.GetHolds(ctx, repository)
+	require.NoError(t, err)
+	require.Len(t, holds.Commits, 1)
+}
+
+func TestDeleteMissingHold(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	err := m.DeleteCommitHold(ctx, repository, "c1")
+	if !errors.Is(err, graveler.ErrLegalHoldNotFound) {
+		t.Fatalf("DeleteCommitHold err=%v, expected %v", err, graveler.ErrLegalHoldNotFound)
+	}
+	err = m.DeleteTagHold(ctx, repository, "v1")
+	if !errors.Is(err, graveler.ErrLegalHoldNotFound) {
+		t.Fatalf("DeleteTagHold err=%v, expected %v", err, graveler.ErrLegalHoldNotFound)
+	}
+}
+
+func prepareTest(t *testing.T, ctx context.Context) *legalhold.Manager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(repository, nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	return legalhold.NewManager(settings.NewManager(refManager, kvStore))
+}
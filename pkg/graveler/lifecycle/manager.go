@@ -0,0 +1,36 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "lifecycle_rules"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetRules returns the lifecycle rules of the repository, no rules when none were set.
+func (m *Manager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LifecycleRules, error) {
+	rules := &graveler.LifecycleRules{}
+	_, err := m.settingManager.GetLatest(ctx, repository, SettingKey, rules)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (m *Manager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.LifecycleRules) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, rules, nil)
+}
@@ -139,6 +139,21 @@ func (mr *MockKeyValueStoreMockRecorder) List(ctx, repository, ref, batchSize in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockKeyValueStore)(nil).List), ctx, repository, ref, batchSize)
 }
 
+// ListDirectory mocks base method.
+func (m *MockKeyValueStore) ListDirectory(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix, delimiter graveler.Key) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDirectory", ctx, repository, commitID, prefix, delimiter)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDirectory indicates an expected call of ListDirectory.
+func (mr *MockKeyValueStoreMockRecorder) ListDirectory(ctx, repository, commitID, prefix, delimiter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDirectory", reflect.TypeOf((*MockKeyValueStore)(nil).ListDirectory), ctx, repository, commitID, prefix, delimiter)
+}
+
 // ListStaging mocks base method.
 func (m *MockKeyValueStore) ListStaging(ctx context.Context, branch *graveler.Branch, batchSize int) (graveler.ValueIterator, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +231,25 @@ func (mr *MockVersionControllerMockRecorder) AddCommit(ctx, repository, commit i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommit", reflect.TypeOf((*MockVersionController)(nil).AddCommit), varargs...)
 }
 
+// ApplyStash mocks base method.
+func (m *MockVersionController) ApplyStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, stashID, branchID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApplyStash", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyStash indicates an expected call of ApplyStash.
+func (mr *MockVersionControllerMockRecorder) ApplyStash(ctx, repository, stashID, branchID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, stashID, branchID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStash", reflect.TypeOf((*MockVersionController)(nil).ApplyStash), varargs...)
+}
+
 // CherryPick mocks base method.
 func (m *MockVersionController) CherryPick(ctx context.Context, repository *graveler.RepositoryRecord, id graveler.BranchID, reference graveler.Ref, number *int, committer string, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -271,6 +305,22 @@ func (mr *MockVersionControllerMockRecorder) Compare(ctx, repository, left, righ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockVersionController)(nil).Compare), ctx, repository, left, right)
 }
 
+// CountDivergence mocks base method.
+func (m *MockVersionController) CountDivergence(ctx context.Context, repository *graveler.RepositoryRecord, left, right graveler.CommitID) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDivergence", ctx, repository, left, right)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountDivergence indicates an expected call of CountDivergence.
+func (mr *MockVersionControllerMockRecorder) CountDivergence(ctx, repository, left, right interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDivergence", reflect.TypeOf((*MockVersionController)(nil).CountDivergence), ctx, repository, left, right)
+}
+
 // CreateBareRepository mocks base method.
 func (m *MockVersionController) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageNamespace graveler.StorageNamespace, defaultBranchID graveler.BranchID) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
@@ -378,6 +428,34 @@ func (mr *MockVersionControllerMockRecorder) DeleteBranch(ctx, repository, branc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBranch", reflect.TypeOf((*MockVersionController)(nil).DeleteBranch), varargs...)
 }
 
+// DeleteCommitLegalHold mocks base method.
+func (m *MockVersionController) DeleteCommitLegalHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCommitLegalHold", ctx, repository, commitID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCommitLegalHold indicates an expected call of DeleteCommitLegalHold.
+func (mr *MockVersionControllerMockRecorder) DeleteCommitLegalHold(ctx, repository, commitID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommitLegalHold", reflect.TypeOf((*MockVersionController)(nil).DeleteCommitLegalHold), ctx, repository, commitID)
+}
+
+// DeleteExpiredDeletedBranches mocks base method.
+func (m *MockVersionController) DeleteExpiredDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredDeletedBranches", ctx, repository)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredDeletedBranches indicates an expected call of DeleteExpiredDeletedBranches.
+func (mr *MockVersionControllerMockRecorder) DeleteExpiredDeletedBranches(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredDeletedBranches", reflect.TypeOf((*MockVersionController)(nil).DeleteExpiredDeletedBranches), ctx, repository)
+}
+
 // DeleteExpiredImports mocks base method.
 func (m *MockVersionController) DeleteExpiredImports(ctx context.Context, repository *graveler.RepositoryRecord) error {
 	m.ctrl.T.Helper()
@@ -425,6 +503,20 @@ func (mr *MockVersionControllerMockRecorder) DeleteRepository(ctx, repositoryID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepository", reflect.TypeOf((*MockVersionController)(nil).DeleteRepository), varargs...)
 }
 
+// DeleteStash mocks base method.
+func (m *MockVersionController) DeleteStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStash", ctx, repository, stashID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStash indicates an expected call of DeleteStash.
+func (mr *MockVersionControllerMockRecorder) DeleteStash(ctx, repository, stashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStash", reflect.TypeOf((*MockVersionController)(nil).DeleteStash), ctx, repository, stashID)
+}
+
 // DeleteTag mocks base method.
 func (m *MockVersionController) DeleteTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, opts ...graveler.SetOptionsFunc) error {
 	m.ctrl.T.Helper()
@@ -444,6 +536,20 @@ func (mr *MockVersionControllerMockRecorder) DeleteTag(ctx, repository, tagID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockVersionController)(nil).DeleteTag), varargs...)
 }
 
+// DeleteTagLegalHold mocks base method.
+func (m *MockVersionController) DeleteTagLegalHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagLegalHold", ctx, repository, tagID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagLegalHold indicates an expected call of DeleteTagLegalHold.
+func (mr *MockVersionControllerMockRecorder) DeleteTagLegalHold(ctx, repository, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagLegalHold", reflect.TypeOf((*MockVersionController)(nil).DeleteTagLegalHold), ctx, repository, tagID)
+}
+
 // Dereference mocks base method.
 func (m *MockVersionController) Dereference(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref) (*graveler.ResolvedRef, error) {
 	m.ctrl.T.Helper()
@@ -535,6 +641,21 @@ func (mr *MockVersionControllerMockRecorder) GCNewRunID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GCNewRunID", reflect.TypeOf((*MockVersionController)(nil).GCNewRunID))
 }
 
+// GetBlockAdapterOverride mocks base method.
+func (m *MockVersionController) GetBlockAdapterOverride(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BlockAdapterOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockAdapterOverride", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BlockAdapterOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockAdapterOverride indicates an expected call of GetBlockAdapterOverride.
+func (mr *MockVersionControllerMockRecorder) GetBlockAdapterOverride(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockAdapterOverride", reflect.TypeOf((*MockVersionController)(nil).GetBlockAdapterOverride), ctx, repository)
+}
+
 // GetBranch mocks base method.
 func (m *MockVersionController) GetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.Branch, error) {
 	m.ctrl.T.Helper()
@@ -550,6 +671,21 @@ func (mr *MockVersionControllerMockRecorder) GetBranch(ctx, repository, branchID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranch", reflect.TypeOf((*MockVersionController)(nil).GetBranch), ctx, repository, branchID)
 }
 
+// GetBranchLocks mocks base method.
+func (m *MockVersionController) GetBranchLocks(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BranchLocks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchLocks", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BranchLocks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchLocks indicates an expected call of GetBranchLocks.
+func (mr *MockVersionControllerMockRecorder) GetBranchLocks(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchLocks", reflect.TypeOf((*MockVersionController)(nil).GetBranchLocks), ctx, repository)
+}
+
 // GetBranchProtectionRules mocks base method.
 func (m *MockVersionController) GetBranchProtectionRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BranchProtectionRules, *string, error) {
 	m.ctrl.T.Helper()
@@ -566,6 +702,36 @@ func (mr *MockVersionControllerMockRecorder) GetBranchProtectionRules(ctx, repos
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionRules", reflect.TypeOf((*MockVersionController)(nil).GetBranchProtectionRules), ctx, repository)
 }
 
+// GetBucketNotifications mocks base method.
+func (m *MockVersionController) GetBucketNotifications(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BucketNotifications, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketNotifications", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BucketNotifications)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketNotifications indicates an expected call of GetBucketNotifications.
+func (mr *MockVersionControllerMockRecorder) GetBucketNotifications(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketNotifications", reflect.TypeOf((*MockVersionController)(nil).GetBucketNotifications), ctx, repository)
+}
+
+// GetCORSRules mocks base method.
+func (m *MockVersionController) GetCORSRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CORSRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCORSRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CORSRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCORSRules indicates an expected call of GetCORSRules.
+func (mr *MockVersionControllerMockRecorder) GetCORSRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCORSRules", reflect.TypeOf((*MockVersionController)(nil).GetCORSRules), ctx, repository)
+}
+
 // GetCommit mocks base method.
 func (m *MockVersionController) GetCommit(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) (*graveler.Commit, error) {
 	m.ctrl.T.Helper()
@@ -581,6 +747,51 @@ func (mr *MockVersionControllerMockRecorder) GetCommit(ctx, repository, commitID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockVersionController)(nil).GetCommit), ctx, repository, commitID)
 }
 
+// GetCommitTemplate mocks base method.
+func (m *MockVersionController) GetCommitTemplate(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CommitTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommitTemplate", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CommitTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommitTemplate indicates an expected call of GetCommitTemplate.
+func (mr *MockVersionControllerMockRecorder) GetCommitTemplate(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitTemplate", reflect.TypeOf((*MockVersionController)(nil).GetCommitTemplate), ctx, repository)
+}
+
+// GetCostAttribution mocks base method.
+func (m *MockVersionController) GetCostAttribution(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CostAttribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostAttribution", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CostAttribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostAttribution indicates an expected call of GetCostAttribution.
+func (mr *MockVersionControllerMockRecorder) GetCostAttribution(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostAttribution", reflect.TypeOf((*MockVersionController)(nil).GetCostAttribution), ctx, repository)
+}
+
+// GetGarbageCollectionCommits mocks base method.
+func (m *MockVersionController) GetGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGarbageCollectionCommits", ctx, repository, rules)
+	ret0, _ := ret[0].(map[graveler.CommitID]graveler.MetaRangeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGarbageCollectionCommits indicates an expected call of GetGarbageCollectionCommits.
+func (mr *MockVersionControllerMockRecorder) GetGarbageCollectionCommits(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGarbageCollectionCommits", reflect.TypeOf((*MockVersionController)(nil).GetGarbageCollectionCommits), ctx, repository, rules)
+}
+
 // GetGarbageCollectionRules mocks base method.
 func (m *MockVersionController) GetGarbageCollectionRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.GarbageCollectionRules, error) {
 	m.ctrl.T.Helper()
@@ -596,6 +807,81 @@ func (mr *MockVersionControllerMockRecorder) GetGarbageCollectionRules(ctx, repo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGarbageCollectionRules", reflect.TypeOf((*MockVersionController)(nil).GetGarbageCollectionRules), ctx, repository)
 }
 
+// GetKeyValidationRules mocks base method.
+func (m *MockVersionController) GetKeyValidationRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.KeyValidationRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeyValidationRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.KeyValidationRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyValidationRules indicates an expected call of GetKeyValidationRules.
+func (mr *MockVersionControllerMockRecorder) GetKeyValidationRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyValidationRules", reflect.TypeOf((*MockVersionController)(nil).GetKeyValidationRules), ctx, repository)
+}
+
+// GetLegalHolds mocks base method.
+func (m *MockVersionController) GetLegalHolds(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LegalHolds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLegalHolds", ctx, repository)
+	ret0, _ := ret[0].(*graveler.LegalHolds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLegalHolds indicates an expected call of GetLegalHolds.
+func (mr *MockVersionControllerMockRecorder) GetLegalHolds(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLegalHolds", reflect.TypeOf((*MockVersionController)(nil).GetLegalHolds), ctx, repository)
+}
+
+// GetLifecycleRules mocks base method.
+func (m *MockVersionController) GetLifecycleRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LifecycleRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLifecycleRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.LifecycleRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLifecycleRules indicates an expected call of GetLifecycleRules.
+func (mr *MockVersionControllerMockRecorder) GetLifecycleRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifecycleRules", reflect.TypeOf((*MockVersionController)(nil).GetLifecycleRules), ctx, repository)
+}
+
+// GetObjectLockConfiguration mocks base method.
+func (m *MockVersionController) GetObjectLockConfiguration(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.ObjectLockConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectLockConfiguration", ctx, repository)
+	ret0, _ := ret[0].(*graveler.ObjectLockConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectLockConfiguration indicates an expected call of GetObjectLockConfiguration.
+func (mr *MockVersionControllerMockRecorder) GetObjectLockConfiguration(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectLockConfiguration", reflect.TypeOf((*MockVersionController)(nil).GetObjectLockConfiguration), ctx, repository)
+}
+
+// GetPassThroughMappings mocks base method.
+func (m *MockVersionController) GetPassThroughMappings(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.PassThroughMappings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPassThroughMappings", ctx, repository)
+	ret0, _ := ret[0].(*graveler.PassThroughMappings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPassThroughMappings indicates an expected call of GetPassThroughMappings.
+func (mr *MockVersionControllerMockRecorder) GetPassThroughMappings(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassThroughMappings", reflect.TypeOf((*MockVersionController)(nil).GetPassThroughMappings), ctx, repository)
+}
+
 // GetRepository mocks base method.
 func (m *MockVersionController) GetRepository(ctx context.Context, repositoryID graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
@@ -641,6 +927,21 @@ func (mr *MockVersionControllerMockRecorder) GetStagingToken(ctx, repository, br
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStagingToken", reflect.TypeOf((*MockVersionController)(nil).GetStagingToken), ctx, repository, branchID)
 }
 
+// GetStash mocks base method.
+func (m *MockVersionController) GetStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) (*graveler.StashRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStash", ctx, repository, stashID)
+	ret0, _ := ret[0].(*graveler.StashRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStash indicates an expected call of GetStash.
+func (mr *MockVersionControllerMockRecorder) GetStash(ctx, repository, stashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStash", reflect.TypeOf((*MockVersionController)(nil).GetStash), ctx, repository, stashID)
+}
+
 // GetTag mocks base method.
 func (m *MockVersionController) GetTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (*graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -706,6 +1007,36 @@ func (mr *MockVersionControllerMockRecorder) ListBranches(ctx, repository interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBranches", reflect.TypeOf((*MockVersionController)(nil).ListBranches), ctx, repository)
 }
 
+// ListCommits mocks base method.
+func (m *MockVersionController) ListCommits(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.CommitIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", ctx, repository)
+	ret0, _ := ret[0].(graveler.CommitIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockVersionControllerMockRecorder) ListCommits(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockVersionController)(nil).ListCommits), ctx, repository)
+}
+
+// ListDeletedBranches mocks base method.
+func (m *MockVersionController) ListDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.DeletedBranchIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBranches", ctx, repository)
+	ret0, _ := ret[0].(graveler.DeletedBranchIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedBranches indicates an expected call of ListDeletedBranches.
+func (mr *MockVersionControllerMockRecorder) ListDeletedBranches(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBranches", reflect.TypeOf((*MockVersionController)(nil).ListDeletedBranches), ctx, repository)
+}
+
 // ListLinkAddresses mocks base method.
 func (m *MockVersionController) ListLinkAddresses(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.LinkAddressIterator, error) {
 	m.ctrl.T.Helper()
@@ -736,6 +1067,21 @@ func (mr *MockVersionControllerMockRecorder) ListRepositories(ctx interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepositories", reflect.TypeOf((*MockVersionController)(nil).ListRepositories), ctx)
 }
 
+// ListStashes mocks base method.
+func (m *MockVersionController) ListStashes(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.StashIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStashes", ctx, repository)
+	ret0, _ := ret[0].(graveler.StashIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStashes indicates an expected call of ListStashes.
+func (mr *MockVersionControllerMockRecorder) ListStashes(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStashes", reflect.TypeOf((*MockVersionController)(nil).ListStashes), ctx, repository)
+}
+
 // ListTags mocks base method.
 func (m *MockVersionController) ListTags(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.TagIterator, error) {
 	m.ctrl.T.Helper()
@@ -751,6 +1097,20 @@ func (mr *MockVersionControllerMockRecorder) ListTags(ctx, repository interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockVersionController)(nil).ListTags), ctx, repository)
 }
 
+// LockBranch mocks base method.
+func (m *MockVersionController) LockBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, lock *graveler.BranchLock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBranch", ctx, repository, branchID, lock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockBranch indicates an expected call of LockBranch.
+func (mr *MockVersionControllerMockRecorder) LockBranch(ctx, repository, branchID, lock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBranch", reflect.TypeOf((*MockVersionController)(nil).LockBranch), ctx, repository, branchID, lock)
+}
+
 // Log mocks base method.
 func (m *MockVersionController) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
 	m.ctrl.T.Helper()
@@ -801,6 +1161,25 @@ func (mr *MockVersionControllerMockRecorder) ParseRef(ref interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseRef", reflect.TypeOf((*MockVersionController)(nil).ParseRef), ref)
 }
 
+// RenameBranch mocks base method.
+func (m *MockVersionController) RenameBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID, newBranchID graveler.BranchID, opts ...graveler.SetOptionsFunc) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, newBranchID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenameBranch", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameBranch indicates an expected call of RenameBranch.
+func (mr *MockVersionControllerMockRecorder) RenameBranch(ctx, repository, branchID, newBranchID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, newBranchID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameBranch", reflect.TypeOf((*MockVersionController)(nil).RenameBranch), varargs...)
+}
+
 // Reset mocks base method.
 func (m *MockVersionController) Reset(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) error {
 	m.ctrl.T.Helper()
@@ -892,6 +1271,26 @@ func (mr *MockVersionControllerMockRecorder) ResolveRawRef(ctx, repository, rawR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRawRef", reflect.TypeOf((*MockVersionController)(nil).ResolveRawRef), ctx, repository, rawRef)
 }
 
+// RestoreBranch mocks base method.
+func (m *MockVersionController) RestoreBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreBranch", varargs...)
+	ret0, _ := ret[0].(*graveler.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBranch indicates an expected call of RestoreBranch.
+func (mr *MockVersionControllerMockRecorder) RestoreBranch(ctx, repository, branchID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBranch", reflect.TypeOf((*MockVersionController)(nil).RestoreBranch), varargs...)
+}
+
 // Revert mocks base method.
 func (m *MockVersionController) Revert(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, parentNumber int, commitParams graveler.CommitParams, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -927,6 +1326,39 @@ func (mr *MockVersionControllerMockRecorder) SaveGarbageCollectionCommits(ctx, r
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGarbageCollectionCommits", reflect.TypeOf((*MockVersionController)(nil).SaveGarbageCollectionCommits), ctx, repository)
 }
 
+// SealStagingToken mocks base method.
+func (m *MockVersionController) SealStagingToken(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SealStagingToken", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SealStagingToken indicates an expected call of SealStagingToken.
+func (mr *MockVersionControllerMockRecorder) SealStagingToken(ctx, repository, branchID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealStagingToken", reflect.TypeOf((*MockVersionController)(nil).SealStagingToken), varargs...)
+}
+
+// SetBlockAdapterOverride mocks base method.
+func (m *MockVersionController) SetBlockAdapterOverride(ctx context.Context, repository *graveler.RepositoryRecord, override *graveler.BlockAdapterOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBlockAdapterOverride", ctx, repository, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBlockAdapterOverride indicates an expected call of SetBlockAdapterOverride.
+func (mr *MockVersionControllerMockRecorder) SetBlockAdapterOverride(ctx, repository, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlockAdapterOverride", reflect.TypeOf((*MockVersionController)(nil).SetBlockAdapterOverride), ctx, repository, override)
+}
+
 // SetBranchProtectionRules mocks base method.
 func (m *MockVersionController) SetBranchProtectionRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.BranchProtectionRules, lastKnownChecksum *string) error {
 	m.ctrl.T.Helper()
@@ -941,25 +1373,95 @@ func (mr *MockVersionControllerMockRecorder) SetBranchProtectionRules(ctx, repos
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBranchProtectionRules", reflect.TypeOf((*MockVersionController)(nil).SetBranchProtectionRules), ctx, repository, rules, lastKnownChecksum)
 }
 
-// SetGarbageCollectionRules mocks base method.
-func (m *MockVersionController) SetGarbageCollectionRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) error {
+// SetBucketNotifications mocks base method.
+func (m *MockVersionController) SetBucketNotifications(ctx context.Context, repository *graveler.RepositoryRecord, notifications *graveler.BucketNotifications) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetGarbageCollectionRules", ctx, repository, rules)
+	ret := m.ctrl.Call(m, "SetBucketNotifications", ctx, repository, notifications)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetGarbageCollectionRules indicates an expected call of SetGarbageCollectionRules.
-func (mr *MockVersionControllerMockRecorder) SetGarbageCollectionRules(ctx, repository, rules interface{}) *gomock.Call {
+// SetBucketNotifications indicates an expected call of SetBucketNotifications.
+func (mr *MockVersionControllerMockRecorder) SetBucketNotifications(ctx, repository, notifications interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGarbageCollectionRules", reflect.TypeOf((*MockVersionController)(nil).SetGarbageCollectionRules), ctx, repository, rules)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBucketNotifications", reflect.TypeOf((*MockVersionController)(nil).SetBucketNotifications), ctx, repository, notifications)
 }
 
-// SetHooksHandler mocks base method.
-func (m *MockVersionController) SetHooksHandler(handler graveler.HooksHandler) {
+// SetCORSRules mocks base method.
+func (m *MockVersionController) SetCORSRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.CORSRules) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SetHooksHandler", handler)
-}
+	ret := m.ctrl.Call(m, "SetCORSRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCORSRules indicates an expected call of SetCORSRules.
+func (mr *MockVersionControllerMockRecorder) SetCORSRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCORSRules", reflect.TypeOf((*MockVersionController)(nil).SetCORSRules), ctx, repository, rules)
+}
+
+// SetCommitLegalHold mocks base method.
+func (m *MockVersionController) SetCommitLegalHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, hold *graveler.LegalHold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommitLegalHold", ctx, repository, commitID, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommitLegalHold indicates an expected call of SetCommitLegalHold.
+func (mr *MockVersionControllerMockRecorder) SetCommitLegalHold(ctx, repository, commitID, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommitLegalHold", reflect.TypeOf((*MockVersionController)(nil).SetCommitLegalHold), ctx, repository, commitID, hold)
+}
+
+// SetCommitTemplate mocks base method.
+func (m *MockVersionController) SetCommitTemplate(ctx context.Context, repository *graveler.RepositoryRecord, template *graveler.CommitTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommitTemplate", ctx, repository, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommitTemplate indicates an expected call of SetCommitTemplate.
+func (mr *MockVersionControllerMockRecorder) SetCommitTemplate(ctx, repository, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommitTemplate", reflect.TypeOf((*MockVersionController)(nil).SetCommitTemplate), ctx, repository, template)
+}
+
+// SetCostAttribution mocks base method.
+func (m *MockVersionController) SetCostAttribution(ctx context.Context, repository *graveler.RepositoryRecord, attribution *graveler.CostAttribution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCostAttribution", ctx, repository, attribution)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCostAttribution indicates an expected call of SetCostAttribution.
+func (mr *MockVersionControllerMockRecorder) SetCostAttribution(ctx, repository, attribution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCostAttribution", reflect.TypeOf((*MockVersionController)(nil).SetCostAttribution), ctx, repository, attribution)
+}
+
+// SetGarbageCollectionRules mocks base method.
+func (m *MockVersionController) SetGarbageCollectionRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGarbageCollectionRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGarbageCollectionRules indicates an expected call of SetGarbageCollectionRules.
+func (mr *MockVersionControllerMockRecorder) SetGarbageCollectionRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGarbageCollectionRules", reflect.TypeOf((*MockVersionController)(nil).SetGarbageCollectionRules), ctx, repository, rules)
+}
+
+// SetHooksHandler mocks base method.
+func (m *MockVersionController) SetHooksHandler(handler graveler.HooksHandler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetHooksHandler", handler)
+}
 
 // SetHooksHandler indicates an expected call of SetHooksHandler.
 func (mr *MockVersionControllerMockRecorder) SetHooksHandler(handler interface{}) *gomock.Call {
@@ -967,6 +1469,34 @@ func (mr *MockVersionControllerMockRecorder) SetHooksHandler(handler interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHooksHandler", reflect.TypeOf((*MockVersionController)(nil).SetHooksHandler), handler)
 }
 
+// SetKeyValidationRules mocks base method.
+func (m *MockVersionController) SetKeyValidationRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.KeyValidationRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyValidationRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKeyValidationRules indicates an expected call of SetKeyValidationRules.
+func (mr *MockVersionControllerMockRecorder) SetKeyValidationRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyValidationRules", reflect.TypeOf((*MockVersionController)(nil).SetKeyValidationRules), ctx, repository, rules)
+}
+
+// SetLifecycleRules mocks base method.
+func (m *MockVersionController) SetLifecycleRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.LifecycleRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLifecycleRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLifecycleRules indicates an expected call of SetLifecycleRules.
+func (mr *MockVersionControllerMockRecorder) SetLifecycleRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLifecycleRules", reflect.TypeOf((*MockVersionController)(nil).SetLifecycleRules), ctx, repository, rules)
+}
+
 // SetLinkAddress mocks base method.
 func (m *MockVersionController) SetLinkAddress(ctx context.Context, repository *graveler.RepositoryRecord, physicalAddress string) error {
 	m.ctrl.T.Helper()
@@ -981,6 +1511,34 @@ func (mr *MockVersionControllerMockRecorder) SetLinkAddress(ctx, repository, phy
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkAddress", reflect.TypeOf((*MockVersionController)(nil).SetLinkAddress), ctx, repository, physicalAddress)
 }
 
+// SetObjectLockConfiguration mocks base method.
+func (m *MockVersionController) SetObjectLockConfiguration(ctx context.Context, repository *graveler.RepositoryRecord, config *graveler.ObjectLockConfiguration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetObjectLockConfiguration", ctx, repository, config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetObjectLockConfiguration indicates an expected call of SetObjectLockConfiguration.
+func (mr *MockVersionControllerMockRecorder) SetObjectLockConfiguration(ctx, repository, config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetObjectLockConfiguration", reflect.TypeOf((*MockVersionController)(nil).SetObjectLockConfiguration), ctx, repository, config)
+}
+
+// SetPassThroughMappings mocks base method.
+func (m *MockVersionController) SetPassThroughMappings(ctx context.Context, repository *graveler.RepositoryRecord, mappings *graveler.PassThroughMappings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassThroughMappings", ctx, repository, mappings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassThroughMappings indicates an expected call of SetPassThroughMappings.
+func (mr *MockVersionControllerMockRecorder) SetPassThroughMappings(ctx, repository, mappings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassThroughMappings", reflect.TypeOf((*MockVersionController)(nil).SetPassThroughMappings), ctx, repository, mappings)
+}
+
 // SetRepositoryMetadata mocks base method.
 func (m *MockVersionController) SetRepositoryMetadata(ctx context.Context, repository *graveler.RepositoryRecord, updateFunc graveler.RepoMetadataUpdateFunc) error {
 	m.ctrl.T.Helper()
@@ -995,6 +1553,68 @@ func (mr *MockVersionControllerMockRecorder) SetRepositoryMetadata(ctx, reposito
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryMetadata", reflect.TypeOf((*MockVersionController)(nil).SetRepositoryMetadata), ctx, repository, updateFunc)
 }
 
+// SetRepositoryReadOnly mocks base method.
+func (m *MockVersionController) SetRepositoryReadOnly(ctx context.Context, repository *graveler.RepositoryRecord, readOnly bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRepositoryReadOnly", ctx, repository, readOnly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRepositoryReadOnly indicates an expected call of SetRepositoryReadOnly.
+func (mr *MockVersionControllerMockRecorder) SetRepositoryReadOnly(ctx, repository, readOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryReadOnly", reflect.TypeOf((*MockVersionController)(nil).SetRepositoryReadOnly), ctx, repository, readOnly)
+}
+
+// SetTagLegalHold mocks base method.
+func (m *MockVersionController) SetTagLegalHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, hold *graveler.LegalHold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTagLegalHold", ctx, repository, tagID, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTagLegalHold indicates an expected call of SetTagLegalHold.
+func (mr *MockVersionControllerMockRecorder) SetTagLegalHold(ctx, repository, tagID, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTagLegalHold", reflect.TypeOf((*MockVersionController)(nil).SetTagLegalHold), ctx, repository, tagID, hold)
+}
+
+// StashBranch mocks base method.
+func (m *MockVersionController) StashBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, stashID graveler.StashID, message string, opts ...graveler.SetOptionsFunc) (*graveler.StashRecord, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, stashID, message}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StashBranch", varargs...)
+	ret0, _ := ret[0].(*graveler.StashRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StashBranch indicates an expected call of StashBranch.
+func (mr *MockVersionControllerMockRecorder) StashBranch(ctx, repository, branchID, stashID, message interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, stashID, message}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StashBranch", reflect.TypeOf((*MockVersionController)(nil).StashBranch), varargs...)
+}
+
+// UnlockBranch mocks base method.
+func (m *MockVersionController) UnlockBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockBranch", ctx, repository, branchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockBranch indicates an expected call of UnlockBranch.
+func (mr *MockVersionControllerMockRecorder) UnlockBranch(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockBranch", reflect.TypeOf((*MockVersionController)(nil).UnlockBranch), ctx, repository, branchID)
+}
+
 // UpdateBranch mocks base method.
 func (m *MockVersionController) UpdateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
 	m.ctrl.T.Helper()
@@ -1102,6 +1722,36 @@ func (mr *MockPlumbingMockRecorder) GetRange(ctx, repository, rangeID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockPlumbing)(nil).GetRange), ctx, repository, rangeID)
 }
 
+// ListRange mocks base method.
+func (m *MockPlumbing) ListRange(ctx context.Context, repository *graveler.RepositoryRecord, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, repository, rangeID)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockPlumbingMockRecorder) ListRange(ctx, repository, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockPlumbing)(nil).ListRange), ctx, repository, rangeID)
+}
+
+// ListRanges mocks base method.
+func (m *MockPlumbing) ListRanges(ctx context.Context, repository *graveler.RepositoryRecord, metaRangeID graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, repository, metaRangeID)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockPlumbingMockRecorder) ListRanges(ctx, repository, metaRangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockPlumbing)(nil).ListRanges), ctx, repository, metaRangeID)
+}
+
 // StageObject mocks base method.
 func (m *MockPlumbing) StageObject(ctx context.Context, stagingToken string, object graveler.ValueRecord) error {
 	m.ctrl.T.Helper()
@@ -1749,43 +2399,43 @@ func (mr *MockTagIteratorMockRecorder) Value() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockTagIterator)(nil).Value))
 }
 
-// MockCommitIterator is a mock of CommitIterator interface.
-type MockCommitIterator struct {
+// MockDeletedBranchIterator is a mock of DeletedBranchIterator interface.
+type MockDeletedBranchIterator struct {
 	ctrl     *gomock.Controller
-	recorder *MockCommitIteratorMockRecorder
+	recorder *MockDeletedBranchIteratorMockRecorder
 }
 
-// MockCommitIteratorMockRecorder is the mock recorder for MockCommitIterator.
-type MockCommitIteratorMockRecorder struct {
-	mock *MockCommitIterator
+// MockDeletedBranchIteratorMockRecorder is the mock recorder for MockDeletedBranchIterator.
+type MockDeletedBranchIteratorMockRecorder struct {
+	mock *MockDeletedBranchIterator
 }
 
-// NewMockCommitIterator creates a new mock instance.
-func NewMockCommitIterator(ctrl *gomock.Controller) *MockCommitIterator {
-	mock := &MockCommitIterator{ctrl: ctrl}
-	mock.recorder = &MockCommitIteratorMockRecorder{mock}
+// NewMockDeletedBranchIterator creates a new mock instance.
+func NewMockDeletedBranchIterator(ctrl *gomock.Controller) *MockDeletedBranchIterator {
+	mock := &MockDeletedBranchIterator{ctrl: ctrl}
+	mock.recorder = &MockDeletedBranchIteratorMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCommitIterator) EXPECT() *MockCommitIteratorMockRecorder {
+func (m *MockDeletedBranchIterator) EXPECT() *MockDeletedBranchIteratorMockRecorder {
 	return m.recorder
 }
 
 // Close mocks base method.
-func (m *MockCommitIterator) Close() {
+func (m *MockDeletedBranchIterator) Close() {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "Close")
 }
 
 // Close indicates an expected call of Close.
-func (mr *MockCommitIteratorMockRecorder) Close() *gomock.Call {
+func (mr *MockDeletedBranchIteratorMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCommitIterator)(nil).Close))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDeletedBranchIterator)(nil).Close))
 }
 
 // Err mocks base method.
-func (m *MockCommitIterator) Err() error {
+func (m *MockDeletedBranchIterator) Err() error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Err")
 	ret0, _ := ret[0].(error)
@@ -1793,13 +2443,13 @@ func (m *MockCommitIterator) Err() error {
 }
 
 // Err indicates an expected call of Err.
-func (mr *MockCommitIteratorMockRecorder) Err() *gomock.Call {
+func (mr *MockDeletedBranchIteratorMockRecorder) Err() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockCommitIterator)(nil).Err))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockDeletedBranchIterator)(nil).Err))
 }
 
 // Next mocks base method.
-func (m *MockCommitIterator) Next() bool {
+func (m *MockDeletedBranchIterator) Next() bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Next")
 	ret0, _ := ret[0].(bool)
@@ -1807,74 +2457,74 @@ func (m *MockCommitIterator) Next() bool {
 }
 
 // Next indicates an expected call of Next.
-func (mr *MockCommitIteratorMockRecorder) Next() *gomock.Call {
+func (mr *MockDeletedBranchIteratorMockRecorder) Next() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockCommitIterator)(nil).Next))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockDeletedBranchIterator)(nil).Next))
 }
 
 // SeekGE mocks base method.
-func (m *MockCommitIterator) SeekGE(id graveler.CommitID) {
+func (m *MockDeletedBranchIterator) SeekGE(id graveler.BranchID) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SeekGE", id)
 }
 
 // SeekGE indicates an expected call of SeekGE.
-func (mr *MockCommitIteratorMockRecorder) SeekGE(id interface{}) *gomock.Call {
+func (mr *MockDeletedBranchIteratorMockRecorder) SeekGE(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockCommitIterator)(nil).SeekGE), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockDeletedBranchIterator)(nil).SeekGE), id)
 }
 
 // Value mocks base method.
-func (m *MockCommitIterator) Value() *graveler.CommitRecord {
+func (m *MockDeletedBranchIterator) Value() *graveler.DeletedBranchRecord {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Value")
-	ret0, _ := ret[0].(*graveler.CommitRecord)
+	ret0, _ := ret[0].(*graveler.DeletedBranchRecord)
 	return ret0
 }
 
 // Value indicates an expected call of Value.
-func (mr *MockCommitIteratorMockRecorder) Value() *gomock.Call {
+func (mr *MockDeletedBranchIteratorMockRecorder) Value() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockCommitIterator)(nil).Value))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockDeletedBranchIterator)(nil).Value))
 }
 
-// MockLinkAddressIterator is a mock of LinkAddressIterator interface.
-type MockLinkAddressIterator struct {
+// MockStashIterator is a mock of StashIterator interface.
+type MockStashIterator struct {
 	ctrl     *gomock.Controller
-	recorder *MockLinkAddressIteratorMockRecorder
+	recorder *MockStashIteratorMockRecorder
 }
 
-// MockLinkAddressIteratorMockRecorder is the mock recorder for MockLinkAddressIterator.
-type MockLinkAddressIteratorMockRecorder struct {
-	mock *MockLinkAddressIterator
+// MockStashIteratorMockRecorder is the mock recorder for MockStashIterator.
+type MockStashIteratorMockRecorder struct {
+	mock *MockStashIterator
 }
 
-// NewMockLinkAddressIterator creates a new mock instance.
-func NewMockLinkAddressIterator(ctrl *gomock.Controller) *MockLinkAddressIterator {
-	mock := &MockLinkAddressIterator{ctrl: ctrl}
-	mock.recorder = &MockLinkAddressIteratorMockRecorder{mock}
+// NewMockStashIterator creates a new mock instance.
+func NewMockStashIterator(ctrl *gomock.Controller) *MockStashIterator {
+	mock := &MockStashIterator{ctrl: ctrl}
+	mock.recorder = &MockStashIteratorMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockLinkAddressIterator) EXPECT() *MockLinkAddressIteratorMockRecorder {
+func (m *MockStashIterator) EXPECT() *MockStashIteratorMockRecorder {
 	return m.recorder
 }
 
 // Close mocks base method.
-func (m *MockLinkAddressIterator) Close() {
+func (m *MockStashIterator) Close() {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "Close")
 }
 
 // Close indicates an expected call of Close.
-func (mr *MockLinkAddressIteratorMockRecorder) Close() *gomock.Call {
+func (mr *MockStashIteratorMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockLinkAddressIterator)(nil).Close))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStashIterator)(nil).Close))
 }
 
 // Err mocks base method.
-func (m *MockLinkAddressIterator) Err() error {
+func (m *MockStashIterator) Err() error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Err")
 	ret0, _ := ret[0].(error)
@@ -1882,13 +2532,13 @@ func (m *MockLinkAddressIterator) Err() error {
 }
 
 // Err indicates an expected call of Err.
-func (mr *MockLinkAddressIteratorMockRecorder) Err() *gomock.Call {
+func (mr *MockStashIteratorMockRecorder) Err() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockLinkAddressIterator)(nil).Err))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockStashIterator)(nil).Err))
 }
 
 // Next mocks base method.
-func (m *MockLinkAddressIterator) Next() bool {
+func (m *MockStashIterator) Next() bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Next")
 	ret0, _ := ret[0].(bool)
@@ -1896,102 +2546,296 @@ func (m *MockLinkAddressIterator) Next() bool {
 }
 
 // Next indicates an expected call of Next.
-func (mr *MockLinkAddressIteratorMockRecorder) Next() *gomock.Call {
+func (mr *MockStashIteratorMockRecorder) Next() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockLinkAddressIterator)(nil).Next))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockStashIterator)(nil).Next))
 }
 
 // SeekGE mocks base method.
-func (m *MockLinkAddressIterator) SeekGE(address string) {
+func (m *MockStashIterator) SeekGE(id graveler.StashID) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SeekGE", address)
+	m.ctrl.Call(m, "SeekGE", id)
 }
 
 // SeekGE indicates an expected call of SeekGE.
-func (mr *MockLinkAddressIteratorMockRecorder) SeekGE(address interface{}) *gomock.Call {
+func (mr *MockStashIteratorMockRecorder) SeekGE(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockLinkAddressIterator)(nil).SeekGE), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockStashIterator)(nil).SeekGE), id)
 }
 
 // Value mocks base method.
-func (m *MockLinkAddressIterator) Value() *graveler.LinkAddressData {
+func (m *MockStashIterator) Value() *graveler.StashRecord {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Value")
-	ret0, _ := ret[0].(*graveler.LinkAddressData)
+	ret0, _ := ret[0].(*graveler.StashRecord)
 	return ret0
 }
 
 // Value indicates an expected call of Value.
-func (mr *MockLinkAddressIteratorMockRecorder) Value() *gomock.Call {
+func (mr *MockStashIteratorMockRecorder) Value() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockLinkAddressIterator)(nil).Value))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockStashIterator)(nil).Value))
 }
 
-// MockRefManager is a mock of RefManager interface.
-type MockRefManager struct {
+// MockCommitIterator is a mock of CommitIterator interface.
+type MockCommitIterator struct {
 	ctrl     *gomock.Controller
-	recorder *MockRefManagerMockRecorder
+	recorder *MockCommitIteratorMockRecorder
 }
 
-// MockRefManagerMockRecorder is the mock recorder for MockRefManager.
-type MockRefManagerMockRecorder struct {
-	mock *MockRefManager
+// MockCommitIteratorMockRecorder is the mock recorder for MockCommitIterator.
+type MockCommitIteratorMockRecorder struct {
+	mock *MockCommitIterator
 }
 
-// NewMockRefManager creates a new mock instance.
-func NewMockRefManager(ctrl *gomock.Controller) *MockRefManager {
-	mock := &MockRefManager{ctrl: ctrl}
-	mock.recorder = &MockRefManagerMockRecorder{mock}
+// NewMockCommitIterator creates a new mock instance.
+func NewMockCommitIterator(ctrl *gomock.Controller) *MockCommitIterator {
+	mock := &MockCommitIterator{ctrl: ctrl}
+	mock.recorder = &MockCommitIteratorMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockRefManager) EXPECT() *MockRefManagerMockRecorder {
+func (m *MockCommitIterator) EXPECT() *MockCommitIteratorMockRecorder {
 	return m.recorder
 }
 
-// AddCommit mocks base method.
-func (m *MockRefManager) AddCommit(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
+// Close mocks base method.
+func (m *MockCommitIterator) Close() {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddCommit", ctx, repository, commit)
-	ret0, _ := ret[0].(graveler.CommitID)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	m.ctrl.Call(m, "Close")
 }
 
-// AddCommit indicates an expected call of AddCommit.
-func (mr *MockRefManagerMockRecorder) AddCommit(ctx, repository, commit interface{}) *gomock.Call {
+// Close indicates an expected call of Close.
+func (mr *MockCommitIteratorMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommit", reflect.TypeOf((*MockRefManager)(nil).AddCommit), ctx, repository, commit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCommitIterator)(nil).Close))
 }
 
-// BranchUpdate mocks base method.
-func (m *MockRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
+// Err mocks base method.
+func (m *MockCommitIterator) Err() error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BranchUpdate", ctx, repository, branchID, f)
+	ret := m.ctrl.Call(m, "Err")
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// BranchUpdate indicates an expected call of BranchUpdate.
-func (mr *MockRefManagerMockRecorder) BranchUpdate(ctx, repository, branchID, f interface{}) *gomock.Call {
+// Err indicates an expected call of Err.
+func (mr *MockCommitIteratorMockRecorder) Err() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchUpdate", reflect.TypeOf((*MockRefManager)(nil).BranchUpdate), ctx, repository, branchID, f)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockCommitIterator)(nil).Err))
 }
 
-// CreateBareRepository mocks base method.
-func (m *MockRefManager) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, repository graveler.Repository) (*graveler.RepositoryRecord, error) {
+// Next mocks base method.
+func (m *MockCommitIterator) Next() bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBareRepository", ctx, repositoryID, repository)
-	ret0, _ := ret[0].(*graveler.RepositoryRecord)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// CreateBareRepository indicates an expected call of CreateBareRepository.
-func (mr *MockRefManagerMockRecorder) CreateBareRepository(ctx, repositoryID, repository interface{}) *gomock.Call {
+// Next indicates an expected call of Next.
+func (mr *MockCommitIteratorMockRecorder) Next() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBareRepository", reflect.TypeOf((*MockRefManager)(nil).CreateBareRepository), ctx, repositoryID, repository)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockCommitIterator)(nil).Next))
+}
+
+// SeekGE mocks base method.
+func (m *MockCommitIterator) SeekGE(id graveler.CommitID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SeekGE", id)
+}
+
+// SeekGE indicates an expected call of SeekGE.
+func (mr *MockCommitIteratorMockRecorder) SeekGE(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockCommitIterator)(nil).SeekGE), id)
+}
+
+// Value mocks base method.
+func (m *MockCommitIterator) Value() *graveler.CommitRecord {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Value")
+	ret0, _ := ret[0].(*graveler.CommitRecord)
+	return ret0
+}
+
+// Value indicates an expected call of Value.
+func (mr *MockCommitIteratorMockRecorder) Value() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockCommitIterator)(nil).Value))
+}
+
+// MockLinkAddressIterator is a mock of LinkAddressIterator interface.
+type MockLinkAddressIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkAddressIteratorMockRecorder
+}
+
+// MockLinkAddressIteratorMockRecorder is the mock recorder for MockLinkAddressIterator.
+type MockLinkAddressIteratorMockRecorder struct {
+	mock *MockLinkAddressIterator
+}
+
+// NewMockLinkAddressIterator creates a new mock instance.
+func NewMockLinkAddressIterator(ctrl *gomock.Controller) *MockLinkAddressIterator {
+	mock := &MockLinkAddressIterator{ctrl: ctrl}
+	mock.recorder = &MockLinkAddressIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinkAddressIterator) EXPECT() *MockLinkAddressIteratorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockLinkAddressIterator) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockLinkAddressIteratorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockLinkAddressIterator)(nil).Close))
+}
+
+// Err mocks base method.
+func (m *MockLinkAddressIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockLinkAddressIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockLinkAddressIterator)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockLinkAddressIterator) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockLinkAddressIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockLinkAddressIterator)(nil).Next))
+}
+
+// SeekGE mocks base method.
+func (m *MockLinkAddressIterator) SeekGE(address string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SeekGE", address)
+}
+
+// SeekGE indicates an expected call of SeekGE.
+func (mr *MockLinkAddressIteratorMockRecorder) SeekGE(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeekGE", reflect.TypeOf((*MockLinkAddressIterator)(nil).SeekGE), address)
+}
+
+// Value mocks base method.
+func (m *MockLinkAddressIterator) Value() *graveler.LinkAddressData {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Value")
+	ret0, _ := ret[0].(*graveler.LinkAddressData)
+	return ret0
+}
+
+// Value indicates an expected call of Value.
+func (mr *MockLinkAddressIteratorMockRecorder) Value() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockLinkAddressIterator)(nil).Value))
+}
+
+// MockRefManager is a mock of RefManager interface.
+type MockRefManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefManagerMockRecorder
+}
+
+// MockRefManagerMockRecorder is the mock recorder for MockRefManager.
+type MockRefManagerMockRecorder struct {
+	mock *MockRefManager
+}
+
+// NewMockRefManager creates a new mock instance.
+func NewMockRefManager(ctrl *gomock.Controller) *MockRefManager {
+	mock := &MockRefManager{ctrl: ctrl}
+	mock.recorder = &MockRefManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefManager) EXPECT() *MockRefManagerMockRecorder {
+	return m.recorder
+}
+
+// AddCommit mocks base method.
+func (m *MockRefManager) AddCommit(ctx context.Context, repository *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCommit", ctx, repository, commit)
+	ret0, _ := ret[0].(graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCommit indicates an expected call of AddCommit.
+func (mr *MockRefManagerMockRecorder) AddCommit(ctx, repository, commit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommit", reflect.TypeOf((*MockRefManager)(nil).AddCommit), ctx, repository, commit)
+}
+
+// BranchUpdate mocks base method.
+func (m *MockRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BranchUpdate", ctx, repository, branchID, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BranchUpdate indicates an expected call of BranchUpdate.
+func (mr *MockRefManagerMockRecorder) BranchUpdate(ctx, repository, branchID, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchUpdate", reflect.TypeOf((*MockRefManager)(nil).BranchUpdate), ctx, repository, branchID, f)
+}
+
+// CountDivergence mocks base method.
+func (m *MockRefManager) CountDivergence(ctx context.Context, repository *graveler.RepositoryRecord, left, right graveler.CommitID) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDivergence", ctx, repository, left, right)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountDivergence indicates an expected call of CountDivergence.
+func (mr *MockRefManagerMockRecorder) CountDivergence(ctx, repository, left, right interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDivergence", reflect.TypeOf((*MockRefManager)(nil).CountDivergence), ctx, repository, left, right)
+}
+
+// CreateBareRepository mocks base method.
+func (m *MockRefManager) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, repository graveler.Repository) (*graveler.RepositoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBareRepository", ctx, repositoryID, repository)
+	ret0, _ := ret[0].(*graveler.RepositoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBareRepository indicates an expected call of CreateBareRepository.
+func (mr *MockRefManagerMockRecorder) CreateBareRepository(ctx, repositoryID, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBareRepository", reflect.TypeOf((*MockRefManager)(nil).CreateBareRepository), ctx, repositoryID, repository)
 }
 
 // CreateBranch mocks base method.
@@ -2037,6 +2881,20 @@ func (mr *MockRefManagerMockRecorder) CreateRepository(ctx, repositoryID, reposi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRepository", reflect.TypeOf((*MockRefManager)(nil).CreateRepository), ctx, repositoryID, repository)
 }
 
+// CreateStash mocks base method.
+func (m *MockRefManager) CreateStash(ctx context.Context, repository *graveler.RepositoryRecord, stash *graveler.StashRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStash", ctx, repository, stash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStash indicates an expected call of CreateStash.
+func (mr *MockRefManagerMockRecorder) CreateStash(ctx, repository, stash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStash", reflect.TypeOf((*MockRefManager)(nil).CreateStash), ctx, repository, stash)
+}
+
 // CreateTag mocks base method.
 func (m *MockRefManager) CreateTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, commitID graveler.CommitID) error {
 	m.ctrl.T.Helper()
@@ -2065,6 +2923,20 @@ func (mr *MockRefManagerMockRecorder) DeleteBranch(ctx, repository, branchID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBranch", reflect.TypeOf((*MockRefManager)(nil).DeleteBranch), ctx, repository, branchID)
 }
 
+// DeleteExpiredDeletedBranches mocks base method.
+func (m *MockRefManager) DeleteExpiredDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredDeletedBranches", ctx, repository)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredDeletedBranches indicates an expected call of DeleteExpiredDeletedBranches.
+func (mr *MockRefManagerMockRecorder) DeleteExpiredDeletedBranches(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredDeletedBranches", reflect.TypeOf((*MockRefManager)(nil).DeleteExpiredDeletedBranches), ctx, repository)
+}
+
 // DeleteExpiredImports mocks base method.
 func (m *MockRefManager) DeleteExpiredImports(ctx context.Context, repository *graveler.RepositoryRecord) error {
 	m.ctrl.T.Helper()
@@ -2112,6 +2984,20 @@ func (mr *MockRefManagerMockRecorder) DeleteRepository(ctx, repositoryID interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepository", reflect.TypeOf((*MockRefManager)(nil).DeleteRepository), varargs...)
 }
 
+// DeleteStash mocks base method.
+func (m *MockRefManager) DeleteStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStash", ctx, repository, stashID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStash indicates an expected call of DeleteStash.
+func (mr *MockRefManagerMockRecorder) DeleteStash(ctx, repository, stashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStash", reflect.TypeOf((*MockRefManager)(nil).DeleteStash), ctx, repository, stashID)
+}
+
 // DeleteTag mocks base method.
 func (m *MockRefManager) DeleteTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) error {
 	m.ctrl.T.Helper()
@@ -2221,6 +3107,21 @@ func (mr *MockRefManagerMockRecorder) GetCommitByPrefix(ctx, repository, prefix
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitByPrefix", reflect.TypeOf((*MockRefManager)(nil).GetCommitByPrefix), ctx, repository, prefix)
 }
 
+// GetDeletedBranch mocks base method.
+func (m *MockRefManager) GetDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.DeletedBranchRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBranch", ctx, repository, branchID)
+	ret0, _ := ret[0].(*graveler.DeletedBranchRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBranch indicates an expected call of GetDeletedBranch.
+func (mr *MockRefManagerMockRecorder) GetDeletedBranch(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBranch", reflect.TypeOf((*MockRefManager)(nil).GetDeletedBranch), ctx, repository, branchID)
+}
+
 // GetRepository mocks base method.
 func (m *MockRefManager) GetRepository(ctx context.Context, repositoryID graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
 	m.ctrl.T.Helper()
@@ -2251,6 +3152,21 @@ func (mr *MockRefManagerMockRecorder) GetRepositoryMetadata(ctx, repositoryID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepositoryMetadata", reflect.TypeOf((*MockRefManager)(nil).GetRepositoryMetadata), ctx, repositoryID)
 }
 
+// GetStash mocks base method.
+func (m *MockRefManager) GetStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) (*graveler.StashRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStash", ctx, repository, stashID)
+	ret0, _ := ret[0].(*graveler.StashRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStash indicates an expected call of GetStash.
+func (mr *MockRefManagerMockRecorder) GetStash(ctx, repository, stashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStash", reflect.TypeOf((*MockRefManager)(nil).GetStash), ctx, repository, stashID)
+}
+
 // GetTag mocks base method.
 func (m *MockRefManager) GetTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (*graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -2311,6 +3227,21 @@ func (mr *MockRefManagerMockRecorder) ListCommits(ctx, repository interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockRefManager)(nil).ListCommits), ctx, repository)
 }
 
+// ListDeletedBranches mocks base method.
+func (m *MockRefManager) ListDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.DeletedBranchIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBranches", ctx, repository)
+	ret0, _ := ret[0].(graveler.DeletedBranchIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedBranches indicates an expected call of ListDeletedBranches.
+func (mr *MockRefManagerMockRecorder) ListDeletedBranches(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBranches", reflect.TypeOf((*MockRefManager)(nil).ListDeletedBranches), ctx, repository)
+}
+
 // ListLinkAddresses mocks base method.
 func (m *MockRefManager) ListLinkAddresses(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.LinkAddressIterator, error) {
 	m.ctrl.T.Helper()
@@ -2341,6 +3272,21 @@ func (mr *MockRefManagerMockRecorder) ListRepositories(ctx interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepositories", reflect.TypeOf((*MockRefManager)(nil).ListRepositories), ctx)
 }
 
+// ListStashes mocks base method.
+func (m *MockRefManager) ListStashes(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.StashIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStashes", ctx, repository)
+	ret0, _ := ret[0].(graveler.StashIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStashes indicates an expected call of ListStashes.
+func (mr *MockRefManagerMockRecorder) ListStashes(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStashes", reflect.TypeOf((*MockRefManager)(nil).ListStashes), ctx, repository)
+}
+
 // ListTags mocks base method.
 func (m *MockRefManager) ListTags(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.TagIterator, error) {
 	m.ctrl.T.Helper()
@@ -2400,6 +3346,20 @@ func (mr *MockRefManagerMockRecorder) RemoveCommit(ctx, repository, commitID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCommit", reflect.TypeOf((*MockRefManager)(nil).RemoveCommit), ctx, repository, commitID)
 }
 
+// RemoveDeletedBranch mocks base method.
+func (m *MockRefManager) RemoveDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDeletedBranch", ctx, repository, branchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDeletedBranch indicates an expected call of RemoveDeletedBranch.
+func (mr *MockRefManagerMockRecorder) RemoveDeletedBranch(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDeletedBranch", reflect.TypeOf((*MockRefManager)(nil).RemoveDeletedBranch), ctx, repository, branchID)
+}
+
 // ResolveRawRef mocks base method.
 func (m *MockRefManager) ResolveRawRef(ctx context.Context, repository *graveler.RepositoryRecord, rawRef graveler.RawRef) (*graveler.ResolvedRef, error) {
 	m.ctrl.T.Helper()
@@ -2429,8 +3389,50 @@ func (mr *MockRefManagerMockRecorder) SetBranch(ctx, repository, branchID, branc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBranch", reflect.TypeOf((*MockRefManager)(nil).SetBranch), ctx, repository, branchID, branch)
 }
 
-// SetLinkAddress mocks base method.
-func (m *MockRefManager) SetLinkAddress(ctx context.Context, repository *graveler.RepositoryRecord, physicalAddress string) error {
+// SetCommitStats mocks base method.
+func (m *MockRefManager) SetCommitStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, stats graveler.CommitStats) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommitStats", ctx, repository, commitID, stats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommitStats indicates an expected call of SetCommitStats.
+func (mr *MockRefManagerMockRecorder) SetCommitStats(ctx, repository, commitID, stats interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommitStats", reflect.TypeOf((*MockRefManager)(nil).SetCommitStats), ctx, repository, commitID, stats)
+}
+
+// SetDefaultBranch mocks base method.
+func (m *MockRefManager) SetDefaultBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultBranch", ctx, repository, branchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultBranch indicates an expected call of SetDefaultBranch.
+func (mr *MockRefManagerMockRecorder) SetDefaultBranch(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultBranch", reflect.TypeOf((*MockRefManager)(nil).SetDefaultBranch), ctx, repository, branchID)
+}
+
+// SetDeletedBranch mocks base method.
+func (m *MockRefManager) SetDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, deletedBranch *graveler.DeletedBranchRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeletedBranch", ctx, repository, deletedBranch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeletedBranch indicates an expected call of SetDeletedBranch.
+func (mr *MockRefManagerMockRecorder) SetDeletedBranch(ctx, repository, deletedBranch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeletedBranch", reflect.TypeOf((*MockRefManager)(nil).SetDeletedBranch), ctx, repository, deletedBranch)
+}
+
+// SetLinkAddress mocks base method.
+func (m *MockRefManager) SetLinkAddress(ctx context.Context, repository *graveler.RepositoryRecord, physicalAddress string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetLinkAddress", ctx, repository, physicalAddress)
 	ret0, _ := ret[0].(error)
@@ -2457,6 +3459,20 @@ func (mr *MockRefManagerMockRecorder) SetRepositoryMetadata(ctx, repository, upd
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryMetadata", reflect.TypeOf((*MockRefManager)(nil).SetRepositoryMetadata), ctx, repository, updateFunc)
 }
 
+// SetRepositoryReadOnly mocks base method.
+func (m *MockRefManager) SetRepositoryReadOnly(ctx context.Context, repository *graveler.RepositoryRecord, readOnly bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRepositoryReadOnly", ctx, repository, readOnly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRepositoryReadOnly indicates an expected call of SetRepositoryReadOnly.
+func (mr *MockRefManagerMockRecorder) SetRepositoryReadOnly(ctx, repository, readOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryReadOnly", reflect.TypeOf((*MockRefManager)(nil).SetRepositoryReadOnly), ctx, repository, readOnly)
+}
+
 // VerifyLinkAddress mocks base method.
 func (m *MockRefManager) VerifyLinkAddress(ctx context.Context, repository *graveler.RepositoryRecord, physicalAddress string) error {
 	m.ctrl.T.Helper()
@@ -2655,6 +3671,51 @@ func (mr *MockCommittedManagerMockRecorder) List(ctx, ns, rangeID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommittedManager)(nil).List), ctx, ns, rangeID)
 }
 
+// ListDirectory mocks base method.
+func (m *MockCommittedManager) ListDirectory(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID, prefix, delimiter graveler.Key) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDirectory", ctx, ns, rangeID, prefix, delimiter)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDirectory indicates an expected call of ListDirectory.
+func (mr *MockCommittedManagerMockRecorder) ListDirectory(ctx, ns, rangeID, prefix, delimiter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDirectory", reflect.TypeOf((*MockCommittedManager)(nil).ListDirectory), ctx, ns, rangeID, prefix, delimiter)
+}
+
+// ListRange mocks base method.
+func (m *MockCommittedManager) ListRange(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, ns, rangeID)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockCommittedManagerMockRecorder) ListRange(ctx, ns, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockCommittedManager)(nil).ListRange), ctx, ns, rangeID)
+}
+
+// ListRanges mocks base method.
+func (m *MockCommittedManager) ListRanges(ctx context.Context, ns graveler.StorageNamespace, metaRangeID graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, ns, metaRangeID)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockCommittedManagerMockRecorder) ListRanges(ctx, ns, metaRangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockCommittedManager)(nil).ListRanges), ctx, ns, metaRangeID)
+}
+
 // Merge mocks base method.
 func (m *MockCommittedManager) Merge(ctx context.Context, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID, strategy graveler.MergeStrategy, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
@@ -2962,6 +4023,21 @@ func (mr *MockGarbageCollectionManagerMockRecorder) GetCommitsCSVLocation(runID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommitsCSVLocation", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetCommitsCSVLocation), runID, sn)
 }
 
+// GetGarbageCollectionCommits mocks base method.
+func (m *MockGarbageCollectionManager) GetGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules, heldCommits []graveler.CommitID) (map[graveler.CommitID]graveler.MetaRangeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGarbageCollectionCommits", ctx, repository, rules, heldCommits)
+	ret0, _ := ret[0].(map[graveler.CommitID]graveler.MetaRangeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGarbageCollectionCommits indicates an expected call of GetGarbageCollectionCommits.
+func (mr *MockGarbageCollectionManagerMockRecorder) GetGarbageCollectionCommits(ctx, repository, rules, heldCommits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGarbageCollectionCommits", reflect.TypeOf((*MockGarbageCollectionManager)(nil).GetGarbageCollectionCommits), ctx, repository, rules, heldCommits)
+}
+
 // GetRules mocks base method.
 func (m *MockGarbageCollectionManager) GetRules(ctx context.Context, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
 	m.ctrl.T.Helper()
@@ -3007,18 +4083,18 @@ func (mr *MockGarbageCollectionManagerMockRecorder) NewID() *gomock.Call {
 }
 
 // SaveGarbageCollectionCommits mocks base method.
-func (m *MockGarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (string, error) {
+func (m *MockGarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules, heldCommits []graveler.CommitID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveGarbageCollectionCommits", ctx, repository, rules)
+	ret := m.ctrl.Call(m, "SaveGarbageCollectionCommits", ctx, repository, rules, heldCommits)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveGarbageCollectionCommits indicates an expected call of SaveGarbageCollectionCommits.
-func (mr *MockGarbageCollectionManagerMockRecorder) SaveGarbageCollectionCommits(ctx, repository, rules interface{}) *gomock.Call {
+func (mr *MockGarbageCollectionManagerMockRecorder) SaveGarbageCollectionCommits(ctx, repository, rules, heldCommits interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGarbageCollectionCommits", reflect.TypeOf((*MockGarbageCollectionManager)(nil).SaveGarbageCollectionCommits), ctx, repository, rules)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGarbageCollectionCommits", reflect.TypeOf((*MockGarbageCollectionManager)(nil).SaveGarbageCollectionCommits), ctx, repository, rules, heldCommits)
 }
 
 // SaveGarbageCollectionUncommitted mocks base method.
@@ -3116,3 +4192,661 @@ func (mr *MockProtectedBranchesManagerMockRecorder) SetRules(ctx, repository, ru
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRules", reflect.TypeOf((*MockProtectedBranchesManager)(nil).SetRules), ctx, repository, rules, lastKnownChecksum)
 }
+
+// MockLockedBranchesManager is a mock of LockedBranchesManager interface.
+type MockLockedBranchesManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockedBranchesManagerMockRecorder
+}
+
+// MockLockedBranchesManagerMockRecorder is the mock recorder for MockLockedBranchesManager.
+type MockLockedBranchesManagerMockRecorder struct {
+	mock *MockLockedBranchesManager
+}
+
+// NewMockLockedBranchesManager creates a new mock instance.
+func NewMockLockedBranchesManager(ctrl *gomock.Controller) *MockLockedBranchesManager {
+	mock := &MockLockedBranchesManager{ctrl: ctrl}
+	mock.recorder = &MockLockedBranchesManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockedBranchesManager) EXPECT() *MockLockedBranchesManagerMockRecorder {
+	return m.recorder
+}
+
+// GetLock mocks base method.
+func (m *MockLockedBranchesManager) GetLock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLock", ctx, repository, branchID)
+	ret0, _ := ret[0].(*graveler.BranchLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLock indicates an expected call of GetLock.
+func (mr *MockLockedBranchesManagerMockRecorder) GetLock(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLock", reflect.TypeOf((*MockLockedBranchesManager)(nil).GetLock), ctx, repository, branchID)
+}
+
+// GetLocks mocks base method.
+func (m *MockLockedBranchesManager) GetLocks(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BranchLocks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocks", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BranchLocks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocks indicates an expected call of GetLocks.
+func (mr *MockLockedBranchesManagerMockRecorder) GetLocks(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocks", reflect.TypeOf((*MockLockedBranchesManager)(nil).GetLocks), ctx, repository)
+}
+
+// Lock mocks base method.
+func (m *MockLockedBranchesManager) Lock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, lock *graveler.BranchLock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, repository, branchID, lock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLockedBranchesManagerMockRecorder) Lock(ctx, repository, branchID, lock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLockedBranchesManager)(nil).Lock), ctx, repository, branchID, lock)
+}
+
+// Unlock mocks base method.
+func (m *MockLockedBranchesManager) Unlock(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, repository, branchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLockedBranchesManagerMockRecorder) Unlock(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLockedBranchesManager)(nil).Unlock), ctx, repository, branchID)
+}
+
+// MockPassThroughManager is a mock of PassThroughManager interface.
+type MockPassThroughManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockPassThroughManagerMockRecorder
+}
+
+// MockPassThroughManagerMockRecorder is the mock recorder for MockPassThroughManager.
+type MockPassThroughManagerMockRecorder struct {
+	mock *MockPassThroughManager
+}
+
+// NewMockPassThroughManager creates a new mock instance.
+func NewMockPassThroughManager(ctrl *gomock.Controller) *MockPassThroughManager {
+	mock := &MockPassThroughManager{ctrl: ctrl}
+	mock.recorder = &MockPassThroughManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPassThroughManager) EXPECT() *MockPassThroughManagerMockRecorder {
+	return m.recorder
+}
+
+// GetMappings mocks base method.
+func (m *MockPassThroughManager) GetMappings(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.PassThroughMappings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMappings", ctx, repository)
+	ret0, _ := ret[0].(*graveler.PassThroughMappings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMappings indicates an expected call of GetMappings.
+func (mr *MockPassThroughManagerMockRecorder) GetMappings(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMappings", reflect.TypeOf((*MockPassThroughManager)(nil).GetMappings), ctx, repository)
+}
+
+// SetMappings mocks base method.
+func (m *MockPassThroughManager) SetMappings(ctx context.Context, repository *graveler.RepositoryRecord, mappings *graveler.PassThroughMappings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMappings", ctx, repository, mappings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMappings indicates an expected call of SetMappings.
+func (mr *MockPassThroughManagerMockRecorder) SetMappings(ctx, repository, mappings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMappings", reflect.TypeOf((*MockPassThroughManager)(nil).SetMappings), ctx, repository, mappings)
+}
+
+// MockCORSManager is a mock of CORSManager interface.
+type MockCORSManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockCORSManagerMockRecorder
+}
+
+// MockCORSManagerMockRecorder is the mock recorder for MockCORSManager.
+type MockCORSManagerMockRecorder struct {
+	mock *MockCORSManager
+}
+
+// NewMockCORSManager creates a new mock instance.
+func NewMockCORSManager(ctrl *gomock.Controller) *MockCORSManager {
+	mock := &MockCORSManager{ctrl: ctrl}
+	mock.recorder = &MockCORSManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCORSManager) EXPECT() *MockCORSManagerMockRecorder {
+	return m.recorder
+}
+
+// GetRules mocks base method.
+func (m *MockCORSManager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CORSRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CORSRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockCORSManagerMockRecorder) GetRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockCORSManager)(nil).GetRules), ctx, repository)
+}
+
+// SetRules mocks base method.
+func (m *MockCORSManager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.CORSRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRules indicates an expected call of SetRules.
+func (mr *MockCORSManagerMockRecorder) SetRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRules", reflect.TypeOf((*MockCORSManager)(nil).SetRules), ctx, repository, rules)
+}
+
+// MockObjectLockManager is a mock of ObjectLockManager interface.
+type MockObjectLockManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectLockManagerMockRecorder
+}
+
+// MockObjectLockManagerMockRecorder is the mock recorder for MockObjectLockManager.
+type MockObjectLockManagerMockRecorder struct {
+	mock *MockObjectLockManager
+}
+
+// NewMockObjectLockManager creates a new mock instance.
+func NewMockObjectLockManager(ctrl *gomock.Controller) *MockObjectLockManager {
+	mock := &MockObjectLockManager{ctrl: ctrl}
+	mock.recorder = &MockObjectLockManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectLockManager) EXPECT() *MockObjectLockManagerMockRecorder {
+	return m.recorder
+}
+
+// GetConfiguration mocks base method.
+func (m *MockObjectLockManager) GetConfiguration(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.ObjectLockConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfiguration", ctx, repository)
+	ret0, _ := ret[0].(*graveler.ObjectLockConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfiguration indicates an expected call of GetConfiguration.
+func (mr *MockObjectLockManagerMockRecorder) GetConfiguration(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfiguration", reflect.TypeOf((*MockObjectLockManager)(nil).GetConfiguration), ctx, repository)
+}
+
+// SetConfiguration mocks base method.
+func (m *MockObjectLockManager) SetConfiguration(ctx context.Context, repository *graveler.RepositoryRecord, config *graveler.ObjectLockConfiguration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConfiguration", ctx, repository, config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetConfiguration indicates an expected call of SetConfiguration.
+func (mr *MockObjectLockManagerMockRecorder) SetConfiguration(ctx, repository, config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfiguration", reflect.TypeOf((*MockObjectLockManager)(nil).SetConfiguration), ctx, repository, config)
+}
+
+// MockCostAttributionManager is a mock of CostAttributionManager interface.
+type MockCostAttributionManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockCostAttributionManagerMockRecorder
+}
+
+// MockCostAttributionManagerMockRecorder is the mock recorder for MockCostAttributionManager.
+type MockCostAttributionManagerMockRecorder struct {
+	mock *MockCostAttributionManager
+}
+
+// NewMockCostAttributionManager creates a new mock instance.
+func NewMockCostAttributionManager(ctrl *gomock.Controller) *MockCostAttributionManager {
+	mock := &MockCostAttributionManager{ctrl: ctrl}
+	mock.recorder = &MockCostAttributionManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCostAttributionManager) EXPECT() *MockCostAttributionManagerMockRecorder {
+	return m.recorder
+}
+
+// GetAttribution mocks base method.
+func (m *MockCostAttributionManager) GetAttribution(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CostAttribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttribution", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CostAttribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttribution indicates an expected call of GetAttribution.
+func (mr *MockCostAttributionManagerMockRecorder) GetAttribution(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttribution", reflect.TypeOf((*MockCostAttributionManager)(nil).GetAttribution), ctx, repository)
+}
+
+// SetAttribution mocks base method.
+func (m *MockCostAttributionManager) SetAttribution(ctx context.Context, repository *graveler.RepositoryRecord, attribution *graveler.CostAttribution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAttribution", ctx, repository, attribution)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAttribution indicates an expected call of SetAttribution.
+func (mr *MockCostAttributionManagerMockRecorder) SetAttribution(ctx, repository, attribution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAttribution", reflect.TypeOf((*MockCostAttributionManager)(nil).SetAttribution), ctx, repository, attribution)
+}
+
+// MockKeyValidationManager is a mock of KeyValidationManager interface.
+type MockKeyValidationManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyValidationManagerMockRecorder
+}
+
+// MockKeyValidationManagerMockRecorder is the mock recorder for MockKeyValidationManager.
+type MockKeyValidationManagerMockRecorder struct {
+	mock *MockKeyValidationManager
+}
+
+// NewMockKeyValidationManager creates a new mock instance.
+func NewMockKeyValidationManager(ctrl *gomock.Controller) *MockKeyValidationManager {
+	mock := &MockKeyValidationManager{ctrl: ctrl}
+	mock.recorder = &MockKeyValidationManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyValidationManager) EXPECT() *MockKeyValidationManagerMockRecorder {
+	return m.recorder
+}
+
+// GetRules mocks base method.
+func (m *MockKeyValidationManager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.KeyValidationRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.KeyValidationRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockKeyValidationManagerMockRecorder) GetRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockKeyValidationManager)(nil).GetRules), ctx, repository)
+}
+
+// SetRules mocks base method.
+func (m *MockKeyValidationManager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.KeyValidationRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRules indicates an expected call of SetRules.
+func (mr *MockKeyValidationManagerMockRecorder) SetRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRules", reflect.TypeOf((*MockKeyValidationManager)(nil).SetRules), ctx, repository, rules)
+}
+
+// MockCommitTemplateManager is a mock of CommitTemplateManager interface.
+type MockCommitTemplateManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommitTemplateManagerMockRecorder
+}
+
+// MockCommitTemplateManagerMockRecorder is the mock recorder for MockCommitTemplateManager.
+type MockCommitTemplateManagerMockRecorder struct {
+	mock *MockCommitTemplateManager
+}
+
+// NewMockCommitTemplateManager creates a new mock instance.
+func NewMockCommitTemplateManager(ctrl *gomock.Controller) *MockCommitTemplateManager {
+	mock := &MockCommitTemplateManager{ctrl: ctrl}
+	mock.recorder = &MockCommitTemplateManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommitTemplateManager) EXPECT() *MockCommitTemplateManagerMockRecorder {
+	return m.recorder
+}
+
+// GetTemplate mocks base method.
+func (m *MockCommitTemplateManager) GetTemplate(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.CommitTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, repository)
+	ret0, _ := ret[0].(*graveler.CommitTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockCommitTemplateManagerMockRecorder) GetTemplate(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockCommitTemplateManager)(nil).GetTemplate), ctx, repository)
+}
+
+// SetTemplate mocks base method.
+func (m *MockCommitTemplateManager) SetTemplate(ctx context.Context, repository *graveler.RepositoryRecord, template *graveler.CommitTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTemplate", ctx, repository, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTemplate indicates an expected call of SetTemplate.
+func (mr *MockCommitTemplateManagerMockRecorder) SetTemplate(ctx, repository, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTemplate", reflect.TypeOf((*MockCommitTemplateManager)(nil).SetTemplate), ctx, repository, template)
+}
+
+// MockBucketNotificationManager is a mock of BucketNotificationManager interface.
+type MockBucketNotificationManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockBucketNotificationManagerMockRecorder
+}
+
+// MockBucketNotificationManagerMockRecorder is the mock recorder for MockBucketNotificationManager.
+type MockBucketNotificationManagerMockRecorder struct {
+	mock *MockBucketNotificationManager
+}
+
+// NewMockBucketNotificationManager creates a new mock instance.
+func NewMockBucketNotificationManager(ctrl *gomock.Controller) *MockBucketNotificationManager {
+	mock := &MockBucketNotificationManager{ctrl: ctrl}
+	mock.recorder = &MockBucketNotificationManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBucketNotificationManager) EXPECT() *MockBucketNotificationManagerMockRecorder {
+	return m.recorder
+}
+
+// GetNotifications mocks base method.
+func (m *MockBucketNotificationManager) GetNotifications(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BucketNotifications, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BucketNotifications)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockBucketNotificationManagerMockRecorder) GetNotifications(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockBucketNotificationManager)(nil).GetNotifications), ctx, repository)
+}
+
+// SetNotifications mocks base method.
+func (m *MockBucketNotificationManager) SetNotifications(ctx context.Context, repository *graveler.RepositoryRecord, notifications *graveler.BucketNotifications) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotifications", ctx, repository, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotifications indicates an expected call of SetNotifications.
+func (mr *MockBucketNotificationManagerMockRecorder) SetNotifications(ctx, repository, notifications interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotifications", reflect.TypeOf((*MockBucketNotificationManager)(nil).SetNotifications), ctx, repository, notifications)
+}
+
+// MockBlockAdapterOverrideManager is a mock of BlockAdapterOverrideManager interface.
+type MockBlockAdapterOverrideManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockAdapterOverrideManagerMockRecorder
+}
+
+// MockBlockAdapterOverrideManagerMockRecorder is the mock recorder for MockBlockAdapterOverrideManager.
+type MockBlockAdapterOverrideManagerMockRecorder struct {
+	mock *MockBlockAdapterOverrideManager
+}
+
+// NewMockBlockAdapterOverrideManager creates a new mock instance.
+func NewMockBlockAdapterOverrideManager(ctrl *gomock.Controller) *MockBlockAdapterOverrideManager {
+	mock := &MockBlockAdapterOverrideManager{ctrl: ctrl}
+	mock.recorder = &MockBlockAdapterOverrideManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockAdapterOverrideManager) EXPECT() *MockBlockAdapterOverrideManagerMockRecorder {
+	return m.recorder
+}
+
+// GetOverride mocks base method.
+func (m *MockBlockAdapterOverrideManager) GetOverride(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BlockAdapterOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverride", ctx, repository)
+	ret0, _ := ret[0].(*graveler.BlockAdapterOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverride indicates an expected call of GetOverride.
+func (mr *MockBlockAdapterOverrideManagerMockRecorder) GetOverride(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverride", reflect.TypeOf((*MockBlockAdapterOverrideManager)(nil).GetOverride), ctx, repository)
+}
+
+// SetOverride mocks base method.
+func (m *MockBlockAdapterOverrideManager) SetOverride(ctx context.Context, repository *graveler.RepositoryRecord, override *graveler.BlockAdapterOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverride", ctx, repository, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverride indicates an expected call of SetOverride.
+func (mr *MockBlockAdapterOverrideManagerMockRecorder) SetOverride(ctx, repository, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverride", reflect.TypeOf((*MockBlockAdapterOverrideManager)(nil).SetOverride), ctx, repository, override)
+}
+
+// MockLifecycleRulesManager is a mock of LifecycleRulesManager interface.
+type MockLifecycleRulesManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLifecycleRulesManagerMockRecorder
+}
+
+// MockLifecycleRulesManagerMockRecorder is the mock recorder for MockLifecycleRulesManager.
+type MockLifecycleRulesManagerMockRecorder struct {
+	mock *MockLifecycleRulesManager
+}
+
+// NewMockLifecycleRulesManager creates a new mock instance.
+func NewMockLifecycleRulesManager(ctrl *gomock.Controller) *MockLifecycleRulesManager {
+	mock := &MockLifecycleRulesManager{ctrl: ctrl}
+	mock.recorder = &MockLifecycleRulesManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLifecycleRulesManager) EXPECT() *MockLifecycleRulesManagerMockRecorder {
+	return m.recorder
+}
+
+// GetRules mocks base method.
+func (m *MockLifecycleRulesManager) GetRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LifecycleRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", ctx, repository)
+	ret0, _ := ret[0].(*graveler.LifecycleRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockLifecycleRulesManagerMockRecorder) GetRules(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockLifecycleRulesManager)(nil).GetRules), ctx, repository)
+}
+
+// SetRules mocks base method.
+func (m *MockLifecycleRulesManager) SetRules(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.LifecycleRules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRules", ctx, repository, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRules indicates an expected call of SetRules.
+func (mr *MockLifecycleRulesManagerMockRecorder) SetRules(ctx, repository, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRules", reflect.TypeOf((*MockLifecycleRulesManager)(nil).SetRules), ctx, repository, rules)
+}
+
+// MockLegalHoldManager is a mock of LegalHoldManager interface.
+type MockLegalHoldManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLegalHoldManagerMockRecorder
+}
+
+// MockLegalHoldManagerMockRecorder is the mock recorder for MockLegalHoldManager.
+type MockLegalHoldManagerMockRecorder struct {
+	mock *MockLegalHoldManager
+}
+
+// NewMockLegalHoldManager creates a new mock instance.
+func NewMockLegalHoldManager(ctrl *gomock.Controller) *MockLegalHoldManager {
+	mock := &MockLegalHoldManager{ctrl: ctrl}
+	mock.recorder = &MockLegalHoldManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLegalHoldManager) EXPECT() *MockLegalHoldManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteCommitHold mocks base method.
+func (m *MockLegalHoldManager) DeleteCommitHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCommitHold", ctx, repository, commitID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCommitHold indicates an expected call of DeleteCommitHold.
+func (mr *MockLegalHoldManagerMockRecorder) DeleteCommitHold(ctx, repository, commitID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommitHold", reflect.TypeOf((*MockLegalHoldManager)(nil).DeleteCommitHold), ctx, repository, commitID)
+}
+
+// DeleteTagHold mocks base method.
+func (m *MockLegalHoldManager) DeleteTagHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagHold", ctx, repository, tagID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagHold indicates an expected call of DeleteTagHold.
+func (mr *MockLegalHoldManagerMockRecorder) DeleteTagHold(ctx, repository, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagHold", reflect.TypeOf((*MockLegalHoldManager)(nil).DeleteTagHold), ctx, repository, tagID)
+}
+
+// GetHolds mocks base method.
+func (m *MockLegalHoldManager) GetHolds(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.LegalHolds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolds", ctx, repository)
+	ret0, _ := ret[0].(*graveler.LegalHolds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolds indicates an expected call of GetHolds.
+func (mr *MockLegalHoldManagerMockRecorder) GetHolds(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolds", reflect.TypeOf((*MockLegalHoldManager)(nil).GetHolds), ctx, repository)
+}
+
+// IsTagHeld mocks base method.
+func (m *MockLegalHoldManager) IsTagHeld(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTagHeld", ctx, repository, tagID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTagHeld indicates an expected call of IsTagHeld.
+func (mr *MockLegalHoldManagerMockRecorder) IsTagHeld(ctx, repository, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTagHeld", reflect.TypeOf((*MockLegalHoldManager)(nil).IsTagHeld), ctx, repository, tagID)
+}
+
+// SetCommitHold mocks base method.
+func (m *MockLegalHoldManager) SetCommitHold(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, hold *graveler.LegalHold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommitHold", ctx, repository, commitID, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommitHold indicates an expected call of SetCommitHold.
+func (mr *MockLegalHoldManagerMockRecorder) SetCommitHold(ctx, repository, commitID, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommitHold", reflect.TypeOf((*MockLegalHoldManager)(nil).SetCommitHold), ctx, repository, commitID, hold)
+}
+
+// SetTagHold mocks base method.
+func (m *MockLegalHoldManager) SetTagHold(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, hold *graveler.LegalHold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTagHold", ctx, repository, tagID, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTagHold indicates an expected call of SetTagHold.
+func (mr *MockLegalHoldManagerMockRecorder) SetTagHold(ctx, repository, tagID, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTagHold", reflect.TypeOf((*MockLegalHoldManager)(nil).SetTagHold), ctx, repository, tagID, hold)
+}
@@ -18,6 +18,9 @@ const (
 	addressesPrefix        = "link-addresses"
 	importsPrefix          = "imports"
 	repoMetadataPrefix     = "repo-metadata"
+	leasesPrefix           = "leases"
+	deletedBranchesPrefix  = "deleted-branches"
+	stashesPrefix          = "stashes"
 )
 
 //nolint:gochecknoinits
@@ -26,6 +29,8 @@ func init() {
 	kv.MustRegisterType("*", "branches", (&BranchData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "commits", (&CommitData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "tags", (&TagData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", "deleted-branches", (&DeletedBranchData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", "stashes", (&StashData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "*", (&StagedEntryData{}).ProtoReflect().Type())
 }
 
@@ -61,6 +66,16 @@ func BranchPath(branchID BranchID) string {
 	return kv.FormatPath(branchesPrefix, branchID.String())
 }
 
+// DeletedBranchPath is the key of the head of a deleted branch
+func DeletedBranchPath(branchID BranchID) string {
+	return kv.FormatPath(deletedBranchesPrefix, branchID.String())
+}
+
+// StashPath is the key of a stash
+func StashPath(stashID StashID) string {
+	return kv.FormatPath(stashesPrefix, stashID.String())
+}
+
 func CommitPath(commitID CommitID) string {
 	return kv.FormatPath(commitsPrefix, commitID.String())
 }
@@ -77,6 +92,21 @@ func ImportsPath(key string) string {
 	return kv.FormatPath(importsPrefix, key)
 }
 
+// ImportLeasePath is the key of the lease held while importing into branchID
+func ImportLeasePath(branchID BranchID) string {
+	return kv.FormatPath(leasesPrefix, importsPrefix, branchID.String())
+}
+
+// ReplicationLeasePath is the key of the lease held while replicating the repository
+func ReplicationLeasePath() string {
+	return kv.FormatPath(leasesPrefix, "replication")
+}
+
+// GCLeasePath is the key of the lease held while preparing garbage collection
+func GCLeasePath() string {
+	return kv.FormatPath(leasesPrefix, "gc")
+}
+
 func RepoMetadataPath() string {
 	return repoMetadataPrefix
 }
@@ -90,12 +120,14 @@ func CommitFromProto(pb *CommitData) *Commit {
 	return &Commit{
 		Version:      CommitVersion(pb.Version),
 		Committer:    pb.Committer,
+		Author:       pb.Author,
 		Message:      pb.Message,
 		MetaRangeID:  MetaRangeID(pb.MetaRangeId),
 		CreationDate: pb.CreationDate.AsTime(),
 		Parents:      parents,
 		Metadata:     pb.Metadata,
 		Generation:   CommitGeneration(pb.Generation),
+		Stats:        CommitStatsFromProto(pb.Stats),
 	}
 }
 
@@ -109,6 +141,7 @@ func ProtoFromCommit(commitID CommitID, c *Commit) *CommitData {
 	return &CommitData{
 		Id:           string(commitID),
 		Committer:    c.Committer,
+		Author:       c.Author,
 		Message:      c.Message,
 		CreationDate: timestamppb.New(c.CreationDate),
 		MetaRangeId:  string(c.MetaRangeID),
@@ -116,6 +149,7 @@ func ProtoFromCommit(commitID CommitID, c *Commit) *CommitData {
 		Parents:      parents,
 		Version:      int32(c.Version),
 		Generation:   int32(c.Generation),
+		Stats:        ProtoFromCommitStats(c.Stats),
 	}
 }
 
@@ -167,6 +201,54 @@ func TagFromProto(pb *TagData) *TagRecord {
 	}
 }
 
+func DeletedBranchFromProto(pb *DeletedBranchData) *DeletedBranchRecord {
+	return &DeletedBranchRecord{
+		BranchID:  BranchID(pb.Id),
+		CommitID:  CommitID(pb.CommitId),
+		DeletedAt: pb.DeletedAt.AsTime(),
+		ExpiresAt: pb.ExpiresAt.AsTime(),
+	}
+}
+
+func ProtoFromDeletedBranch(b *DeletedBranchRecord) *DeletedBranchData {
+	return &DeletedBranchData{
+		Id:        b.BranchID.String(),
+		CommitId:  b.CommitID.String(),
+		DeletedAt: timestamppb.New(b.DeletedAt),
+		ExpiresAt: timestamppb.New(b.ExpiresAt),
+	}
+}
+
+func StashFromProto(pb *StashData) *StashRecord {
+	tokens := make([]StagingToken, len(pb.StagingTokens))
+	for i, token := range pb.StagingTokens {
+		tokens[i] = StagingToken(token)
+	}
+	return &StashRecord{
+		StashID:       StashID(pb.Id),
+		BranchID:      BranchID(pb.BranchId),
+		CommitID:      CommitID(pb.CommitId),
+		StagingTokens: tokens,
+		Message:       pb.Message,
+		CreationDate:  pb.CreationDate.AsTime(),
+	}
+}
+
+func ProtoFromStash(s *StashRecord) *StashData {
+	tokens := make([]string, len(s.StagingTokens))
+	for i, token := range s.StagingTokens {
+		tokens[i] = token.String()
+	}
+	return &StashData{
+		Id:            s.StashID.String(),
+		BranchId:      s.BranchID.String(),
+		CommitId:      s.CommitID.String(),
+		StagingTokens: tokens,
+		Message:       s.Message,
+		CreationDate:  timestamppb.New(s.CreationDate),
+	}
+}
+
 func ImportStatusFromProto(pb *ImportStatusData) *ImportStatus {
 	var commit *CommitRecord
 	if pb.Commit != nil {
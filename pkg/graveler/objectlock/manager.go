@@ -0,0 +1,49 @@
+package objectlock
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "object_lock"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetConfiguration returns the object lock configuration of the repository. The configuration is read from the
+// settings cache, as it is consulted on every object write and delete.
+func (m *Manager) GetConfiguration(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.ObjectLockConfiguration, error) {
+	config := &graveler.ObjectLockConfiguration{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, config)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SetConfiguration replaces the object lock configuration of the repository. Once object lock is enabled it cannot
+// be disabled. The saved configuration counts its revision, as the settings manager only saves a configuration
+// with the checksum of an empty one when none was saved.
+func (m *Manager) SetConfiguration(ctx context.Context, repository *graveler.RepositoryRecord, config *graveler.ObjectLockConfiguration) error {
+	current := &graveler.ObjectLockConfiguration{}
+	checksum, err := m.settingManager.GetLatest(ctx, repository, SettingKey, current)
+	if err != nil {
+		return err
+	}
+	if current.Enabled && !config.Enabled {
+		return graveler.ErrObjectLockCannotBeDisabled
+	}
+	config.Revision = current.Revision + 1
+	return m.settingManager.Save(ctx, repository, SettingKey, config, checksum)
+}
@@ -0,0 +1,37 @@
+package passthrough
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+)
+
+const SettingKey = "pass_through"
+
+type Manager struct {
+	settingManager *settings.Manager
+}
+
+func NewManager(settingManager *settings.Manager) *Manager {
+	return &Manager{settingManager: settingManager}
+}
+
+// GetMappings returns the pass-through mappings of the repository. Mappings are read from the settings cache, as
+// they are consulted on reads of missing objects.
+func (m *Manager) GetMappings(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.PassThroughMappings, error) {
+	mappings := &graveler.PassThroughMappings{}
+	err := m.settingManager.Get(ctx, repository, SettingKey, mappings)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return mappings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (m *Manager) SetMappings(ctx context.Context, repository *graveler.RepositoryRecord, mappings *graveler.PassThroughMappings) error {
+	return m.settingManager.Save(ctx, repository, SettingKey, mappings, nil)
+}
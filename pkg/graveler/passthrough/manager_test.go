@@ -0,0 +1,62 @@
+package passthrough_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/mock"
+	"github.com/treeverse/lakefs/pkg/graveler/passthrough"
+	"github.com/treeverse/lakefs/pkg/graveler/settings"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func newRepository(id graveler.RepositoryID) *graveler.RepositoryRecord {
+	return &graveler.RepositoryRecord{
+		RepositoryID: id,
+		Repository: &graveler.Repository{
+			StorageNamespace: "mem://my-storage",
+			DefaultBranchID:  "main",
+		},
+	}
+}
+
+func TestGetMappingsNotSet(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+
+	mappings, err := m.GetMappings(ctx, newRepository("example-repo"))
+	require.NoError(t, err)
+	require.Empty(t, mappings.Mappings)
+}
+
+func TestSetMappings(t *testing.T) {
+	ctx := context.Background()
+	m := prepareTest(t, ctx)
+	repository := newRepository("example-repo")
+
+	err := m.SetMappings(ctx, repository, &graveler.PassThroughMappings{
+		Mappings: []*graveler.PassThroughMapping{
+			{Prefix: "raw/", Upstream: "s3://bucket/raw/"},
+			{Prefix: "events/", Upstream: "s3://bucket/events/", ImportOnRead: true},
+		},
+	})
+	require.NoError(t, err)
+
+	mappings, err := m.GetMappings(ctx, repository)
+	require.NoError(t, err)
+	require.Len(t, mappings.Mappings, 2)
+	require.Equal(t, "raw/", mappings.Mappings[0].GetPrefix())
+	require.Equal(t, "s3://bucket/events/", mappings.Mappings[1].GetUpstream())
+	require.True(t, mappings.Mappings[1].GetImportOnRead())
+}
+
+func prepareTest(t *testing.T, ctx context.Context) *passthrough.Manager {
+	ctrl := gomock.NewController(t)
+	refManager := mock.NewMockRefManager(ctrl)
+	refManager.EXPECT().GetRepository(ctx, gomock.Any()).AnyTimes().Return(newRepository("example-repo"), nil)
+	kvStore := kvtest.GetStore(ctx, t)
+	return passthrough.NewManager(settings.NewManager(refManager, kvStore))
+}
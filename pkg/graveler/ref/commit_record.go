@@ -14,6 +14,7 @@ func CommitDataToCommitRecord(c *graveler.CommitData) *graveler.CommitRecord {
 		CommitID: graveler.CommitID(c.Id),
 		Commit: &graveler.Commit{
 			Committer:    c.Committer,
+			Author:       c.Author,
 			Message:      c.Message,
 			CreationDate: c.CreationDate.AsTime(),
 			MetaRangeID:  graveler.MetaRangeID(c.MetaRangeId),
@@ -21,6 +22,7 @@ func CommitDataToCommitRecord(c *graveler.CommitData) *graveler.CommitRecord {
 			Parents:      parents,
 			Version:      graveler.CommitVersion(c.Version),
 			Generation:   graveler.CommitGeneration(c.Generation),
+			Stats:        graveler.CommitStatsFromProto(c.Stats),
 		},
 	}
 }
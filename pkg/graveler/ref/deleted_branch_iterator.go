@@ -0,0 +1,96 @@
+package ref
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+type DeletedBranchIterator struct {
+	ctx           context.Context
+	it            kv.MessageIterator
+	err           error
+	value         *graveler.DeletedBranchRecord
+	repoPartition string
+	store         kv.Store
+	closed        bool
+}
+
+func NewDeletedBranchIterator(ctx context.Context, store kv.Store, repo *graveler.RepositoryRecord) (*DeletedBranchIterator, error) {
+	repoPartition := graveler.RepoPartition(repo)
+	it, err := kv.NewPrimaryIterator(ctx, store, (&graveler.DeletedBranchData{}).ProtoReflect().Type(),
+		graveler.RepoPartition(repo),
+		[]byte(graveler.DeletedBranchPath("")), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return nil, err
+	}
+	return &DeletedBranchIterator{
+		ctx:           ctx,
+		it:            it,
+		store:         store,
+		repoPartition: repoPartition,
+		closed:        false,
+	}, nil
+}
+
+func (i *DeletedBranchIterator) Next() bool {
+	if i.Err() != nil || i.closed {
+		return false
+	}
+	if !i.it.Next() {
+		i.value = nil
+		return false
+	}
+	e := i.it.Entry()
+	if e == nil {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	deletedBranch, ok := e.Value.(*graveler.DeletedBranchData)
+	if !ok {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	i.value = graveler.DeletedBranchFromProto(deletedBranch)
+	return true
+}
+
+func (i *DeletedBranchIterator) SeekGE(id graveler.BranchID) {
+	if i.Err() != nil {
+		return
+	}
+	i.Close()
+	it, err := kv.NewPrimaryIterator(i.ctx, i.store, (&graveler.DeletedBranchData{}).ProtoReflect().Type(),
+		i.repoPartition,
+		[]byte(graveler.DeletedBranchPath("")), kv.IteratorOptionsFrom([]byte(graveler.DeletedBranchPath(id))))
+	i.it = it
+	i.err = err
+	i.value = nil
+	i.closed = err != nil
+}
+
+func (i *DeletedBranchIterator) Value() *graveler.DeletedBranchRecord {
+	if i.Err() != nil {
+		return nil
+	}
+	return i.value
+}
+
+func (i *DeletedBranchIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	if !i.closed {
+		return i.it.Err()
+	}
+	return nil
+}
+
+func (i *DeletedBranchIterator) Close() {
+	if i.closed {
+		return
+	}
+	i.it.Close()
+	i.closed = true
+}
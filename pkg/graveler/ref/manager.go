@@ -191,6 +191,27 @@ func (m *Manager) CreateRepository(ctx context.Context, repositoryID graveler.Re
 	return repo, nil
 }
 
+func (m *Manager) SetDefaultBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	data := graveler.RepositoryData{}
+	pred, err := kv.GetMsg(ctx, m.kvStore, graveler.RepositoriesPartition(), []byte(graveler.RepoPath(repository.RepositoryID)), &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return graveler.ErrRepositoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	data.DefaultBranchId = string(branchID)
+	err = kv.SetMsgIf(ctx, m.kvStore, graveler.RepositoriesPartition(), []byte(graveler.RepoPath(repository.RepositoryID)), &data, pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return graveler.ErrPreconditionFailed
+	}
+	if err != nil {
+		return err
+	}
+	repository.DefaultBranchID = branchID
+	return nil
+}
+
 func (m *Manager) CreateBareRepository(ctx context.Context, repositoryID graveler.RepositoryID, repository graveler.Repository) (*graveler.RepositoryRecord, error) {
 	return m.createBareRepository(ctx, repositoryID, repository)
 }
@@ -236,6 +257,38 @@ func (m *Manager) deleteRepositoryTags(ctx context.Context, repository *graveler
 	return wg.Wait().ErrorOrNil()
 }
 
+func (m *Manager) deleteRepositoryDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	itr, err := m.ListDeletedBranches(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	var wg multierror.Group
+	for itr.Next() {
+		b := itr.Value()
+		wg.Go(func() error {
+			return m.RemoveDeletedBranch(ctx, repository, b.BranchID)
+		})
+	}
+	return wg.Wait().ErrorOrNil()
+}
+
+func (m *Manager) deleteRepositoryStashes(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	itr, err := m.ListStashes(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	var wg multierror.Group
+	for itr.Next() {
+		s := itr.Value()
+		wg.Go(func() error {
+			return m.DeleteStash(ctx, repository, s.StashID)
+		})
+	}
+	return wg.Wait().ErrorOrNil()
+}
+
 func (m *Manager) deleteRepositoryCommits(ctx context.Context, repository *graveler.RepositoryRecord) error {
 	itr, err := m.ListCommits(ctx, repository)
 	if err != nil {
@@ -265,6 +318,12 @@ func (m *Manager) deleteRepository(ctx context.Context, repo *graveler.Repositor
 	wg.Go(func() error {
 		return m.deleteRepositoryTags(ctx, repo)
 	})
+	wg.Go(func() error {
+		return m.deleteRepositoryDeletedBranches(ctx, repo)
+	})
+	wg.Go(func() error {
+		return m.deleteRepositoryStashes(ctx, repo)
+	})
 	wg.Go(func() error {
 		return m.deleteRepositoryCommits(ctx, repo)
 	})
@@ -349,6 +408,35 @@ func (m *Manager) SetRepositoryMetadata(ctx context.Context, repo *graveler.Repo
 	return kv.SetMsgIf(ctx, m.kvStore, graveler.RepoPartition(repo), []byte(graveler.RepoMetadataPath()), graveler.ProtoFromRepositoryMetadata(newMetadata), pred)
 }
 
+// SetRepositoryReadOnly sets whether the repository rejects writes that are not forced. Cached repositories, of this
+// and other lakeFS instances, observe the change once their cache entry expires.
+func (m *Manager) SetRepositoryReadOnly(ctx context.Context, repo *graveler.RepositoryRecord, readOnly bool) error {
+	data := graveler.RepositoryData{}
+	pred, err := kv.GetMsg(ctx, m.kvStore, graveler.RepositoriesPartition(), []byte(graveler.RepoPath(repo.RepositoryID)), &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return graveler.ErrRepositoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if data.State != graveler.RepositoryState_ACTIVE {
+		return graveler.ErrRepositoryInDeletion
+	}
+	if data.ReadOnly == readOnly {
+		return nil
+	}
+	data.ReadOnly = readOnly
+	err = kv.SetMsgIf(ctx, m.kvStore, graveler.RepositoriesPartition(), []byte(graveler.RepoPath(repo.RepositoryID)), &data, pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return graveler.ErrConflictFound
+	}
+	if err != nil {
+		return err
+	}
+	repo.ReadOnly = readOnly
+	return nil
+}
+
 func (m *Manager) ParseRef(ref graveler.Ref) (graveler.RawRef, error) {
 	return ParseRef(ref)
 }
@@ -428,6 +516,85 @@ func (m *Manager) ListBranches(ctx context.Context, repository *graveler.Reposit
 	return NewBranchSimpleIterator(ctx, m.kvStore, repository)
 }
 
+func (m *Manager) SetDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, deletedBranch *graveler.DeletedBranchRecord) error {
+	return kv.SetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.DeletedBranchPath(deletedBranch.BranchID)), graveler.ProtoFromDeletedBranch(deletedBranch))
+}
+
+func (m *Manager) GetDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.DeletedBranchRecord, error) {
+	data := graveler.DeletedBranchData{}
+	_, err := kv.GetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.DeletedBranchPath(branchID)), &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		err = graveler.ErrDeletedBranchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return graveler.DeletedBranchFromProto(&data), nil
+}
+
+func (m *Manager) RemoveDeletedBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(graveler.DeletedBranchPath(branchID)))
+}
+
+func (m *Manager) ListDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.DeletedBranchIterator, error) {
+	return NewDeletedBranchIterator(ctx, m.kvStore, repository)
+}
+
+// DeleteExpiredDeletedBranches removes the records of deleted branches past their expiry time. This call uses
+// limiter to access the kv store.
+func (m *Manager) DeleteExpiredDeletedBranches(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	itr, err := NewDeletedBranchIterator(ctx, m.kvStoreLimited, repository)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	now := time.Now()
+	repoPartition := []byte(graveler.RepoPartition(repository))
+	var errs multierror.Error
+	for itr.Next() {
+		deletedBranch := itr.Value()
+		if !deletedBranch.ExpiresAt.Before(now) {
+			continue
+		}
+		err := m.kvStoreLimited.Delete(ctx, repoPartition, []byte(graveler.DeletedBranchPath(deletedBranch.BranchID)))
+		if err != nil {
+			errs.Errors = append(errs.Errors, fmt.Errorf("delete failed for deleted branch %s: %w", deletedBranch.BranchID, err))
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return err
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m *Manager) CreateStash(ctx context.Context, repository *graveler.RepositoryRecord, stash *graveler.StashRecord) error {
+	err := kv.SetMsgIf(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.StashPath(stash.StashID)), graveler.ProtoFromStash(stash), nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		err = graveler.ErrStashExists
+	}
+	return err
+}
+
+func (m *Manager) GetStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) (*graveler.StashRecord, error) {
+	data := graveler.StashData{}
+	_, err := kv.GetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.StashPath(stashID)), &data)
+	if errors.Is(err, kv.ErrNotFound) {
+		err = graveler.ErrStashNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return graveler.StashFromProto(&data), nil
+}
+
+func (m *Manager) DeleteStash(ctx context.Context, repository *graveler.RepositoryRecord, stashID graveler.StashID) error {
+	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(graveler.StashPath(stashID)))
+}
+
+func (m *Manager) ListStashes(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.StashIterator, error) {
+	return NewStashIterator(ctx, m.kvStore, repository)
+}
+
 func (m *Manager) GCBranchIterator(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
 	return NewBranchByCommitIterator(ctx, m.kvStore, repository)
 }
@@ -585,6 +752,26 @@ func (m *Manager) CreateCommitRecord(ctx context.Context, repository *graveler.R
 	return nil
 }
 
+func (m *Manager) SetCommitStats(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, stats graveler.CommitStats) error {
+	commitKey := []byte(graveler.CommitPath(commitID))
+	partition := graveler.RepoPartition(repository)
+	c := &graveler.CommitData{}
+	pred, err := kv.GetMsg(ctx, m.kvStore, partition, commitKey, c)
+	if errors.Is(err, kv.ErrNotFound) {
+		return graveler.ErrCommitNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// stats are not part of the identity of the commit, setting them keeps its ID
+	c.Stats = graveler.ProtoFromCommitStats(&stats)
+	if err := kv.SetMsgIf(ctx, m.kvStore, partition, commitKey, c, pred); err != nil {
+		return err
+	}
+	m.commitCache.Remove(fmt.Sprintf("%s:%s", repository.RepositoryID, commitID))
+	return nil
+}
+
 func (m *Manager) RemoveCommit(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) error {
 	commitKey := graveler.CommitPath(commitID)
 	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(commitKey))
@@ -598,6 +785,10 @@ func (m *Manager) FindMergeBase(ctx context.Context, repository *graveler.Reposi
 	return FindMergeBase(ctx, m, repository, commitIDs[0], commitIDs[1])
 }
 
+func (m *Manager) CountDivergence(ctx context.Context, repository *graveler.RepositoryRecord, left, right graveler.CommitID) (int, int, error) {
+	return CountDivergence(ctx, m, repository, left, right)
+}
+
 func (m *Manager) Log(ctx context.Context, repository *graveler.RepositoryRecord, from graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
 	return NewCommitIterator(ctx, &CommitIteratorConfig{
 		repository:  repository,
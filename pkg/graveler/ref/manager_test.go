@@ -716,6 +716,47 @@ func TestManager_AddCommit(t *testing.T) {
 	}
 }
 
+func TestManager_SetCommitStats(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	cid, err := r.AddCommit(ctx, repository, graveler.Commit{
+		Committer:   "user1",
+		Message:     "message1",
+		MetaRangeID: "deadbeef123",
+		Parents:     graveler.CommitParents{"deadbeef1"},
+	})
+	testutil.Must(t, err)
+	// cache the commit without stats
+	commit, err := r.GetCommit(ctx, repository, cid)
+	testutil.Must(t, err)
+	if commit.Stats != nil {
+		t.Fatalf("new commit stats %+v, expected none", commit.Stats)
+	}
+
+	stats := graveler.CommitStats{ObjectCount: 3, SizeBytes: 300, ObjectCountDelta: 1, SizeBytesDelta: 100}
+	testutil.Must(t, r.SetCommitStats(ctx, repository, cid, stats))
+	commit, err = r.GetCommit(ctx, repository, cid)
+	testutil.Must(t, err)
+	if commit.Stats == nil || *commit.Stats != stats {
+		t.Fatalf("commit stats %+v, expected %+v", commit.Stats, stats)
+	}
+	if commit.Message != "message1" {
+		t.Fatalf("commit message '%s', expected message1", commit.Message)
+	}
+
+	err = r.SetCommitStats(ctx, repository, "deadbeef", stats)
+	if !errors.Is(err, graveler.ErrCommitNotFound) {
+		t.Fatalf("SetCommitStats of a missing commit error %v, expected %v", err, graveler.ErrCommitNotFound)
+	}
+}
+
 func TestManager_Log(t *testing.T) {
 	r, _ := testRefManager(t)
 	ctx := context.Background()
@@ -1274,6 +1315,95 @@ func TestManager_DeleteExpiredImports(t *testing.T) {
 	require.Equal(t, 2, count)
 }
 
+func TestManager_DeletedBranches(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	deletedBranches := []*graveler.DeletedBranchRecord{
+		{BranchID: "expired", CommitID: "c1", DeletedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		{BranchID: "feature", CommitID: "c2", DeletedAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+	for _, b := range deletedBranches {
+		testutil.MustDo(t, "set deleted branch "+b.BranchID.String(), r.SetDeletedBranch(ctx, repository, b))
+	}
+
+	deletedBranch, err := r.GetDeletedBranch(ctx, repository, "feature")
+	testutil.MustDo(t, "get deleted branch feature", err)
+	require.Equal(t, "c2", deletedBranch.CommitID.String())
+	require.True(t, deletedBranch.ExpiresAt.Equal(now.Add(time.Hour)), "expires at %s", deletedBranch.ExpiresAt)
+
+	_, err = r.GetDeletedBranch(ctx, repository, "main")
+	require.ErrorIs(t, err, graveler.ErrDeletedBranchNotFound)
+
+	testutil.MustDo(t, "delete expired deleted branches", r.DeleteExpiredDeletedBranches(ctx, repository))
+	it, err := r.ListDeletedBranches(ctx, repository)
+	testutil.MustDo(t, "list deleted branches", err)
+	var branchIDs []graveler.BranchID
+	for it.Next() {
+		branchIDs = append(branchIDs, it.Value().BranchID)
+	}
+	testutil.MustDo(t, "iterate deleted branches", it.Err())
+	it.Close()
+	require.Equal(t, []graveler.BranchID{"feature"}, branchIDs)
+
+	testutil.MustDo(t, "remove deleted branch feature", r.RemoveDeletedBranch(ctx, repository, "feature"))
+	_, err = r.GetDeletedBranch(ctx, repository, "feature")
+	require.ErrorIs(t, err, graveler.ErrDeletedBranchNotFound)
+}
+
+func TestManager_Stashes(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	stash := &graveler.StashRecord{
+		StashID:       "wip",
+		BranchID:      "main",
+		CommitID:      "c1",
+		StagingTokens: []graveler.StagingToken{"st2", "st1"},
+		Message:       "ingestion in progress",
+		CreationDate:  now,
+	}
+	testutil.MustDo(t, "create stash", r.CreateStash(ctx, repository, stash))
+	err = r.CreateStash(ctx, repository, &graveler.StashRecord{StashID: "wip", BranchID: "dev", StagingTokens: []graveler.StagingToken{"st3"}})
+	require.ErrorIs(t, err, graveler.ErrStashExists)
+	testutil.MustDo(t, "create stash other", r.CreateStash(ctx, repository, &graveler.StashRecord{StashID: "other", BranchID: "dev", StagingTokens: []graveler.StagingToken{"st3"}}))
+
+	got, err := r.GetStash(ctx, repository, "wip")
+	testutil.MustDo(t, "get stash", err)
+	require.Equal(t, graveler.BranchID("main"), got.BranchID)
+	require.Equal(t, []graveler.StagingToken{"st2", "st1"}, got.StagingTokens)
+	require.Equal(t, "ingestion in progress", got.Message)
+	require.True(t, got.CreationDate.Equal(now), "creation date %s", got.CreationDate)
+
+	it, err := r.ListStashes(ctx, repository)
+	testutil.MustDo(t, "list stashes", err)
+	var stashIDs []graveler.StashID
+	for it.Next() {
+		stashIDs = append(stashIDs, it.Value().StashID)
+	}
+	testutil.MustDo(t, "iterate stashes", it.Err())
+	it.Close()
+	require.Equal(t, []graveler.StashID{"other", "wip"}, stashIDs)
+
+	testutil.MustDo(t, "delete stash", r.DeleteStash(ctx, repository, "wip"))
+	_, err = r.GetStash(ctx, repository, "wip")
+	require.ErrorIs(t, err, graveler.ErrStashNotFound)
+}
+
 func TestManager_GetRepositoryMetadata(t *testing.T) {
 	ctx := context.Background()
 	r, _ := testRefManager(t)
@@ -1361,3 +1491,26 @@ func TestManager_SetRepositoryMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_SetRepositoryReadOnly(t *testing.T) {
+	ctx := context.Background()
+	r, _ := testRefManager(t)
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+		ReadOnly:         true,
+	})
+	testutil.Must(t, err)
+
+	require.NoError(t, r.SetRepositoryReadOnly(ctx, repository, false))
+	require.False(t, repository.ReadOnly)
+	// wait for the cached repository to expire
+	time.Sleep(testRepoCacheConfig.Expiry * 2)
+	repository, err = r.GetRepository(ctx, "repo1")
+	require.NoError(t, err)
+	require.False(t, repository.ReadOnly)
+
+	err = r.SetRepositoryReadOnly(ctx, &graveler.RepositoryRecord{RepositoryID: "missing", Repository: &graveler.Repository{}}, false)
+	require.ErrorIs(t, err, graveler.ErrRepositoryNotFound)
+}
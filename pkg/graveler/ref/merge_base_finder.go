@@ -74,3 +74,54 @@ func getCommitAndEnqueue(ctx context.Context, getter CommitGetter, queue *Commit
 	heap.Push(queue, &graveler.CommitRecord{CommitID: commitID, Commit: commit})
 	return commit, nil
 }
+
+// CountDivergence returns the number of commits reachable from the left commit and not from the right commit (ahead),
+// and the number of commits reachable from the right commit and not from the left commit (behind).
+// The commits are visited by descending generation, so the flags of a commit are final once it is popped. The walk
+// stops once all queued commits are reachable from both sides, as are all their ancestors.
+func CountDivergence(ctx context.Context, getter CommitGetter, repository *graveler.RepositoryRecord, leftID, rightID graveler.CommitID) (int, int, error) {
+	const fromBoth = fromLeft | fromRight
+	if leftID == rightID {
+		return 0, 0, nil
+	}
+	queue := NewCommitsGenerationPriorityQueue()
+	reached := map[graveler.CommitID]reachedFlags{leftID: fromLeft, rightID: fromRight}
+	popped := make(map[graveler.CommitID]bool)
+	for _, commitID := range []graveler.CommitID{leftID, rightID} {
+		if _, err := getCommitAndEnqueue(ctx, getter, &queue, repository, commitID); err != nil {
+			return 0, 0, err
+		}
+	}
+	// pending counts the queued commits not reachable from both sides
+	pending := 2
+	var ahead, behind int
+	for pending > 0 {
+		cr := heap.Pop(&queue).(*graveler.CommitRecord)
+		popped[cr.CommitID] = true
+		commitFlags := reached[cr.CommitID]
+		switch commitFlags {
+		case fromLeft:
+			ahead++
+		case fromRight:
+			behind++
+		}
+		if commitFlags != fromBoth {
+			pending--
+		}
+		for _, parent := range cr.Parents {
+			parentFlags, exist := reached[parent]
+			if !exist {
+				if _, err := getCommitAndEnqueue(ctx, getter, &queue, repository, parent); err != nil {
+					return 0, 0, err
+				}
+				if commitFlags != fromBoth {
+					pending++
+				}
+			} else if !popped[parent] && parentFlags != fromBoth && parentFlags|commitFlags == fromBoth {
+				pending--
+			}
+			reached[parent] = parentFlags | commitFlags
+		}
+	}
+	return ahead, behind, nil
+}
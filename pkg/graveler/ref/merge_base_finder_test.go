@@ -393,6 +393,45 @@ func TestGrid(t *testing.T) {
 	verifyResult(t, c, []string{"6-6"}, getter.visited)
 }
 
+func TestCountDivergence(t *testing.T) {
+	// grid of commits where (i,j) has parents (i-1,j) and (i,j-1), so its ancestors are all (a,b) with a<=i and b<=j
+	kv := make(map[graveler.CommitID]*graveler.Commit)
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			parents := make([]graveler.CommitID, 0, 2)
+			if i > 0 {
+				parents = append(parents, graveler.CommitID(fmt.Sprintf("%d-%d", i-1, j)))
+			}
+			if j > 0 {
+				parents = append(parents, graveler.CommitID(fmt.Sprintf("%d-%d", i, j-1)))
+			}
+			kv[graveler.CommitID(fmt.Sprintf("%d-%d", i, j))] = &graveler.Commit{Message: fmt.Sprintf("%d-%d", i, j), Parents: parents}
+		}
+	}
+	repository := &graveler.RepositoryRecord{RepositoryID: "ref-test-repo"}
+	getter := newReader(kv)
+	cases := []struct {
+		Left, Right   graveler.CommitID
+		Ahead, Behind int
+	}{
+		{Left: "7-4", Right: "5-6", Ahead: 10, Behind: 12},
+		{Left: "5-6", Right: "7-4", Ahead: 12, Behind: 10},
+		{Left: "3-3", Right: "3-3", Ahead: 0, Behind: 0},
+		{Left: "3-3", Right: "1-1", Ahead: 12, Behind: 0},
+		{Left: "1-1", Right: "3-3", Ahead: 0, Behind: 12},
+		{Left: "0-9", Right: "9-0", Ahead: 9, Behind: 9},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%s_%s", cas.Left, cas.Right), func(t *testing.T) {
+			ahead, behind, err := ref.CountDivergence(context.Background(), getter, repository, cas.Left, cas.Right)
+			testutil.Must(t, err)
+			if ahead != cas.Ahead || behind != cas.Behind {
+				t.Fatalf("CountDivergence() ahead=%d behind=%d, expected ahead=%d behind=%d", ahead, behind, cas.Ahead, cas.Behind)
+			}
+		})
+	}
+}
+
 func verifyResult(t *testing.T, base *graveler.Commit, expected []string, visited map[graveler.CommitID]int) {
 	if base == nil {
 		if len(expected) != 0 {
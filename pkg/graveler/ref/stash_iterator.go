@@ -0,0 +1,96 @@
+package ref
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+type StashIterator struct {
+	ctx           context.Context
+	it            kv.MessageIterator
+	err           error
+	value         *graveler.StashRecord
+	repoPartition string
+	store         kv.Store
+	closed        bool
+}
+
+func NewStashIterator(ctx context.Context, store kv.Store, repo *graveler.RepositoryRecord) (*StashIterator, error) {
+	repoPartition := graveler.RepoPartition(repo)
+	it, err := kv.NewPrimaryIterator(ctx, store, (&graveler.StashData{}).ProtoReflect().Type(),
+		graveler.RepoPartition(repo),
+		[]byte(graveler.StashPath("")), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return nil, err
+	}
+	return &StashIterator{
+		ctx:           ctx,
+		it:            it,
+		store:         store,
+		repoPartition: repoPartition,
+		closed:        false,
+	}, nil
+}
+
+func (i *StashIterator) Next() bool {
+	if i.Err() != nil || i.closed {
+		return false
+	}
+	if !i.it.Next() {
+		i.value = nil
+		return false
+	}
+	e := i.it.Entry()
+	if e == nil {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	stash, ok := e.Value.(*graveler.StashData)
+	if !ok {
+		i.err = graveler.ErrReadingFromStore
+		return false
+	}
+	i.value = graveler.StashFromProto(stash)
+	return true
+}
+
+func (i *StashIterator) SeekGE(id graveler.StashID) {
+	if i.Err() != nil {
+		return
+	}
+	i.Close()
+	it, err := kv.NewPrimaryIterator(i.ctx, i.store, (&graveler.StashData{}).ProtoReflect().Type(),
+		i.repoPartition,
+		[]byte(graveler.StashPath("")), kv.IteratorOptionsFrom([]byte(graveler.StashPath(id))))
+	i.it = it
+	i.err = err
+	i.value = nil
+	i.closed = err != nil
+}
+
+func (i *StashIterator) Value() *graveler.StashRecord {
+	if i.Err() != nil {
+		return nil
+	}
+	return i.value
+}
+
+func (i *StashIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	if !i.closed {
+		return i.it.Err()
+	}
+	return nil
+}
+
+func (i *StashIterator) Close() {
+	if i.closed {
+		return
+	}
+	i.it.Close()
+	i.closed = true
+}
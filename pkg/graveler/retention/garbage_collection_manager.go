@@ -148,27 +148,49 @@ func (m *GarbageCollectionManager) SaveRules(ctx context.Context, storageNamespa
 	}, int64(len(rulesBytes)), bytes.NewReader(rulesBytes), block.PutOpts{})
 }
 
-func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules) (string, error) {
+// GetGarbageCollectionCommits returns the active commits of the repository according to rules, and their metarange IDs
+func (m *GarbageCollectionManager) GetGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules, heldCommits []graveler.CommitID) (map[graveler.CommitID]graveler.MetaRangeID, error) {
 	commitGetter := &RepositoryCommitGetter{
 		refManager: m.refManager,
 		repository: repository,
 	}
 	branchIterator, err := m.refManager.GCBranchIterator(ctx, repository)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer branchIterator.Close()
 	// get all commits that are not the first parent of any commit:
 	commitIterator, err := m.refManager.GCCommitIterator(ctx, repository)
 	if err != nil {
-		return "", fmt.Errorf("create kv orderd commit iterator commits: %w", err)
+		return nil, fmt.Errorf("create kv orderd commit iterator commits: %w", err)
 	}
 	defer commitIterator.Close()
 	startingPointIterator := NewGCStartingPointIterator(commitIterator, branchIterator)
 	defer startingPointIterator.Close()
 	gcCommits, err := GetGarbageCollectionCommits(ctx, startingPointIterator, commitGetter, rules)
 	if err != nil {
-		return "", fmt.Errorf("find expired commits: %w", err)
+		return nil, fmt.Errorf("find expired commits: %w", err)
+	}
+	for _, commitID := range heldCommits {
+		if _, ok := gcCommits[commitID]; ok {
+			continue
+		}
+		commit, err := m.refManager.GetCommit(ctx, repository, commitID)
+		if errors.Is(err, graveler.ErrCommitNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get held commit %s: %w", commitID, err)
+		}
+		gcCommits[commitID] = commit.MetaRangeID
+	}
+	return gcCommits, nil
+}
+
+func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Context, repository *graveler.RepositoryRecord, rules *graveler.GarbageCollectionRules, heldCommits []graveler.CommitID) (string, error) {
+	gcCommits, err := m.GetGarbageCollectionCommits(ctx, repository, rules, heldCommits)
+	if err != nil {
+		return "", err
 	}
 	b := &strings.Builder{}
 	csvWriter := csv.NewWriter(b)
@@ -0,0 +1,179 @@
+package graveler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// StashBranch sets the uncommitted changes of branchID aside as stashID. The staging tokens of the branch move to
+// the stash as they are, and the branch gets a new empty staging token.
+func (g *Graveler) StashBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, stashID StashID, message string, opts ...SetOptionsFunc) (*StashRecord, error) {
+	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_STAGING_WRITE)
+	if err != nil {
+		return nil, err
+	}
+	if isProtected {
+		return nil, ErrWriteToProtectedBranch
+	}
+
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if repository.ReadOnly && !options.Force {
+		return nil, ErrReadOnlyRepository
+	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return nil, err
+	}
+
+	var stash *StashRecord
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		empty, err := g.isStagingEmpty(ctx, repository, branch)
+		if err != nil {
+			return nil, fmt.Errorf("checking empty branch: %w", err)
+		}
+		if empty {
+			return nil, ErrNoChanges
+		}
+		stash = &StashRecord{
+			StashID:       stashID,
+			BranchID:      branchID,
+			CommitID:      branch.CommitID,
+			StagingTokens: append([]StagingToken{branch.StagingToken}, branch.SealedTokens...),
+			Message:       message,
+			CreationDate:  time.Now(),
+		}
+		// record the stash first, so that the changes are never lost
+		if err := g.RefManager.CreateStash(ctx, repository, stash); err != nil {
+			stash = nil
+			return nil, err
+		}
+		branch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
+		branch.SealedTokens = make([]StagingToken, 0)
+		return branch, nil
+	})
+	if err != nil {
+		if stash != nil {
+			// the branch kept its changes
+			if err := g.RefManager.DeleteStash(ctx, repository, stashID); err != nil {
+				g.log(ctx).WithError(err).WithField("stash", stashID).Warn("Failed to remove stash of failed branch update")
+			}
+		}
+		return nil, err
+	}
+	return stash, nil
+}
+
+// ApplyStash stages the changes of stashID on branchID. Stashed changes replace the values of keys as they were
+// when stashed: the branch must have no uncommitted changes to the stashed keys, but may have commits changing them.
+func (g *Graveler) ApplyStash(ctx context.Context, repository *RepositoryRecord, stashID StashID, branchID BranchID, opts ...SetOptionsFunc) error {
+	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_STAGING_WRITE)
+	if err != nil {
+		return err
+	}
+	if isProtected {
+		return ErrWriteToProtectedBranch
+	}
+
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if repository.ReadOnly && !options.Force {
+		return ErrReadOnlyRepository
+	}
+	if err := g.checkBranchLocked(ctx, repository, branchID); err != nil {
+		return err
+	}
+	stash, err := g.RefManager.GetStash(ctx, repository, stashID)
+	if err != nil {
+		return err
+	}
+
+	branch, err := g.GetBranch(ctx, repository, branchID)
+	if err != nil {
+		return err
+	}
+	if err := g.checkStashConflicts(ctx, stash, branch); err != nil {
+		return err
+	}
+
+	log := g.log(ctx).WithFields(logging.Fields{"stash": stashID, "operation": "apply_stash"})
+	return g.safeBranchWrite(ctx, log, repository, branchID, safeBranchWriteOptions{MaxTries: options.MaxTries}, func(branch *Branch) error {
+		it, err := g.listStagingArea(ctx, stash.Branch(), 0)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for it.Next() {
+			record := it.Value()
+			if err := g.StagingManager.Set(ctx, branch.StagingToken, record.Key, record.Value, false); err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	}, "apply_stash")
+}
+
+// checkStashConflicts fails with ErrConflictFound if branch has uncommitted changes to a key changed by stash
+func (g *Graveler) checkStashConflicts(ctx context.Context, stash *StashRecord, branch *Branch) error {
+	stashIt, err := g.listStagingArea(ctx, stash.Branch(), 0)
+	if err != nil {
+		return err
+	}
+	defer stashIt.Close()
+	branchIt, err := g.listStagingArea(ctx, branch, 0)
+	if err != nil {
+		return err
+	}
+	defer branchIt.Close()
+
+	hasBranchValue := branchIt.Next()
+	for hasBranchValue && stashIt.Next() {
+		key := stashIt.Value().Key
+		for hasBranchValue && bytes.Compare(branchIt.Value().Key, key) < 0 {
+			hasBranchValue = branchIt.Next()
+		}
+		if hasBranchValue && bytes.Equal(branchIt.Value().Key, key) {
+			return fmt.Errorf("uncommitted change to %s: %w", key, ErrConflictFound)
+		}
+	}
+	if err := stashIt.Err(); err != nil {
+		return err
+	}
+	return branchIt.Err()
+}
+
+func (g *Graveler) GetStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) (*StashRecord, error) {
+	return g.RefManager.GetStash(ctx, repository, stashID)
+}
+
+func (g *Graveler) ListStashes(ctx context.Context, repository *RepositoryRecord) (StashIterator, error) {
+	return g.RefManager.ListStashes(ctx, repository)
+}
+
+func (g *Graveler) DeleteStash(ctx context.Context, repository *RepositoryRecord, stashID StashID) error {
+	stash, err := g.RefManager.GetStash(ctx, repository, stashID)
+	if err != nil {
+		return err
+	}
+	if err := g.RefManager.DeleteStash(ctx, repository, stashID); err != nil {
+		return err
+	}
+	g.dropTokens(ctx, stash.StagingTokens...)
+	return nil
+}
+
+// Branch returns a branch whose staging area holds the changes of the stash, for listing them
+func (s *StashRecord) Branch() *Branch {
+	return &Branch{
+		CommitID:     s.CommitID,
+		StagingToken: s.StagingTokens[0],
+		SealedTokens: s.StagingTokens[1:],
+	}
+}
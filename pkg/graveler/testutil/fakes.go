@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
@@ -66,6 +67,10 @@ func (c *CommittedFake) List(_ context.Context, _ graveler.StorageNamespace, mr
 	return c.ValueIterator, nil
 }
 
+func (c *CommittedFake) ListDirectory(context.Context, graveler.StorageNamespace, graveler.MetaRangeID, graveler.Key, graveler.Key) (graveler.ValueIterator, error) {
+	panic("implement me")
+}
+
 func (c *CommittedFake) Diff(context.Context, graveler.StorageNamespace, graveler.MetaRangeID, graveler.MetaRangeID) (graveler.DiffIterator, error) {
 	if c.Err != nil {
 		return nil, c.Err
@@ -126,6 +131,14 @@ func (c *CommittedFake) GetRange(_ context.Context, _ graveler.StorageNamespace,
 	return graveler.RangeAddress(fmt.Sprintf("fake://prefix/%s(range)", rangeID)), nil
 }
 
+func (c *CommittedFake) ListRanges(context.Context, graveler.StorageNamespace, graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	panic("implement me")
+}
+
+func (c *CommittedFake) ListRange(context.Context, graveler.StorageNamespace, graveler.RangeID) (graveler.ValueIterator, error) {
+	panic("implement me")
+}
+
 // Backwards compatibility for test pre-KV
 const defaultKey = "key"
 
@@ -232,6 +245,7 @@ type AddedCommitData struct {
 	MetaRangeID graveler.MetaRangeID
 	Parents     graveler.CommitParents
 	Metadata    graveler.Metadata
+	Stats       *graveler.CommitStats
 }
 
 type RefsFake struct {
@@ -253,6 +267,10 @@ type RefsFake struct {
 	Commits             map[graveler.CommitID]*graveler.Commit
 	StagingToken        graveler.StagingToken
 	SealedTokens        []graveler.StagingToken
+	DeletedBranches     map[graveler.BranchID]*graveler.DeletedBranchRecord
+	Stashes             map[graveler.StashID]*graveler.StashRecord
+	CommitStats         map[graveler.CommitID]graveler.CommitStats
+	statsMu             sync.Mutex
 }
 
 func (m *RefsFake) CreateBranch(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, branch graveler.Branch) error {
@@ -367,6 +385,73 @@ func (m *RefsFake) ListBranches(context.Context, *graveler.RepositoryRecord) (gr
 	return m.ListBranchesRes, nil
 }
 
+func (m *RefsFake) SetDeletedBranch(_ context.Context, _ *graveler.RepositoryRecord, deletedBranch *graveler.DeletedBranchRecord) error {
+	if m.DeletedBranches == nil {
+		m.DeletedBranches = make(map[graveler.BranchID]*graveler.DeletedBranchRecord)
+	}
+	m.DeletedBranches[deletedBranch.BranchID] = deletedBranch
+	return nil
+}
+
+func (m *RefsFake) GetDeletedBranch(_ context.Context, _ *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.DeletedBranchRecord, error) {
+	deletedBranch, ok := m.DeletedBranches[branchID]
+	if !ok {
+		return nil, graveler.ErrDeletedBranchNotFound
+	}
+	return deletedBranch, nil
+}
+
+func (m *RefsFake) RemoveDeletedBranch(_ context.Context, _ *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	delete(m.DeletedBranches, branchID)
+	return nil
+}
+
+func (m *RefsFake) ListDeletedBranches(context.Context, *graveler.RepositoryRecord) (graveler.DeletedBranchIterator, error) {
+	data := make([]*graveler.DeletedBranchRecord, 0, len(m.DeletedBranches))
+	for _, deletedBranch := range m.DeletedBranches {
+		data = append(data, deletedBranch)
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].BranchID < data[j].BranchID })
+	return NewFakeDeletedBranchIterator(data), nil
+}
+
+func (m *RefsFake) DeleteExpiredDeletedBranches(context.Context, *graveler.RepositoryRecord) error {
+	return nil
+}
+
+func (m *RefsFake) CreateStash(_ context.Context, _ *graveler.RepositoryRecord, stash *graveler.StashRecord) error {
+	if m.Stashes == nil {
+		m.Stashes = make(map[graveler.StashID]*graveler.StashRecord)
+	}
+	if _, ok := m.Stashes[stash.StashID]; ok {
+		return graveler.ErrStashExists
+	}
+	m.Stashes[stash.StashID] = stash
+	return nil
+}
+
+func (m *RefsFake) GetStash(_ context.Context, _ *graveler.RepositoryRecord, stashID graveler.StashID) (*graveler.StashRecord, error) {
+	stash, ok := m.Stashes[stashID]
+	if !ok {
+		return nil, graveler.ErrStashNotFound
+	}
+	return stash, nil
+}
+
+func (m *RefsFake) DeleteStash(_ context.Context, _ *graveler.RepositoryRecord, stashID graveler.StashID) error {
+	delete(m.Stashes, stashID)
+	return nil
+}
+
+func (m *RefsFake) ListStashes(context.Context, *graveler.RepositoryRecord) (graveler.StashIterator, error) {
+	data := make([]*graveler.StashRecord, 0, len(m.Stashes))
+	for _, stash := range m.Stashes {
+		data = append(data, stash)
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].StashID < data[j].StashID })
+	return NewFakeStashIterator(data), nil
+}
+
 func (m *RefsFake) GCBranchIterator(context.Context, *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
 	return m.ListBranchesRes, nil
 }
@@ -399,6 +484,24 @@ func (m *RefsFake) GetCommitByPrefix(_ context.Context, _ *graveler.RepositoryRe
 	return &graveler.Commit{}, nil
 }
 
+func (m *RefsFake) SetCommitStats(_ context.Context, _ *graveler.RepositoryRecord, commitID graveler.CommitID, stats graveler.CommitStats) error {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	if m.CommitStats == nil {
+		m.CommitStats = make(map[graveler.CommitID]graveler.CommitStats)
+	}
+	m.CommitStats[commitID] = stats
+	return nil
+}
+
+// GetCommitStats returns the stats set on commitID, they are set in the background once the commit is added
+func (m *RefsFake) GetCommitStats(commitID graveler.CommitID) (graveler.CommitStats, bool) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	stats, ok := m.CommitStats[commitID]
+	return stats, ok
+}
+
 func (m *RefsFake) AddCommit(_ context.Context, _ *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
 	if m.CommitErr != nil {
 		return "", m.CommitErr
@@ -409,6 +512,7 @@ func (m *RefsFake) AddCommit(_ context.Context, _ *graveler.RepositoryRecord, co
 		MetaRangeID: commit.MetaRangeID,
 		Parents:     commit.Parents,
 		Metadata:    commit.Metadata,
+		Stats:       commit.Stats,
 	}
 	return m.CommitID, nil
 }
@@ -422,6 +526,10 @@ func (m *RefsFake) FindMergeBase(context.Context, *graveler.RepositoryRecord, ..
 	return &graveler.Commit{}, nil
 }
 
+func (m *RefsFake) CountDivergence(context.Context, *graveler.RepositoryRecord, graveler.CommitID, graveler.CommitID) (int, int, error) {
+	return 0, 0, nil
+}
+
 func (m *RefsFake) Log(context.Context, *graveler.RepositoryRecord, graveler.CommitID, bool, *time.Time) (graveler.CommitIterator, error) {
 	return m.CommitIter, nil
 }
@@ -829,6 +937,80 @@ func (m *FakeBranchIterator) Err() error {
 
 func (m *FakeBranchIterator) Close() {}
 
+type FakeDeletedBranchIterator struct {
+	Data  []*graveler.DeletedBranchRecord
+	Index int
+}
+
+func NewFakeDeletedBranchIterator(data []*graveler.DeletedBranchRecord) *FakeDeletedBranchIterator {
+	return &FakeDeletedBranchIterator{Data: data, Index: -1}
+}
+
+func (m *FakeDeletedBranchIterator) Next() bool {
+	if m.Index >= len(m.Data) {
+		return false
+	}
+	m.Index++
+	return m.Index < len(m.Data)
+}
+
+func (m *FakeDeletedBranchIterator) SeekGE(id graveler.BranchID) {
+	m.Index = len(m.Data)
+	for i, item := range m.Data {
+		if item.BranchID >= id {
+			m.Index = i - 1
+			return
+		}
+	}
+}
+
+func (m *FakeDeletedBranchIterator) Value() *graveler.DeletedBranchRecord {
+	return m.Data[m.Index]
+}
+
+func (m *FakeDeletedBranchIterator) Err() error {
+	return nil
+}
+
+func (m *FakeDeletedBranchIterator) Close() {}
+
+type FakeStashIterator struct {
+	Data  []*graveler.StashRecord
+	Index int
+}
+
+func NewFakeStashIterator(data []*graveler.StashRecord) *FakeStashIterator {
+	return &FakeStashIterator{Data: data, Index: -1}
+}
+
+func (m *FakeStashIterator) Next() bool {
+	if m.Index >= len(m.Data) {
+		return false
+	}
+	m.Index++
+	return m.Index < len(m.Data)
+}
+
+func (m *FakeStashIterator) SeekGE(id graveler.StashID) {
+	m.Index = len(m.Data)
+	for i, item := range m.Data {
+		if item.StashID >= id {
+			m.Index = i - 1
+			return
+		}
+	}
+}
+
+func (m *FakeStashIterator) Value() *graveler.StashRecord {
+	return m.Data[m.Index]
+}
+
+func (m *FakeStashIterator) Err() error {
+	return nil
+}
+
+func (m *FakeStashIterator) Close() {}
+
 type FakeCommitIterator struct {
 	Data  []*graveler.CommitRecord
 	Index int
@@ -884,6 +1066,42 @@ func (p ProtectedBranchesManagerFake) IsBlocked(_ context.Context, _ *graveler.R
 	return false, nil
 }
 
+type LockedBranchesManagerFake struct {
+	graveler.LockedBranchesManager
+	lockedBranches []graveler.BranchID
+}
+
+func NewLockedBranchesManagerFake(lockedBranches ...graveler.BranchID) *LockedBranchesManagerFake {
+	return &LockedBranchesManagerFake{lockedBranches: lockedBranches}
+}
+
+func (l LockedBranchesManagerFake) GetLock(_ context.Context, _ *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchLock, error) {
+	for _, branch := range l.lockedBranches {
+		if branch == branchID {
+			return &graveler.BranchLock{Reason: "locked", LockedBy: "tester"}, nil
+		}
+	}
+	return nil, nil
+}
+
+type LegalHoldManagerFake struct {
+	graveler.LegalHoldManager
+	heldTags []graveler.TagID
+}
+
+func NewLegalHoldManagerFake(heldTags ...graveler.TagID) *LegalHoldManagerFake {
+	return &LegalHoldManagerFake{heldTags: heldTags}
+}
+
+func (l LegalHoldManagerFake) IsTagHeld(_ context.Context, _ *graveler.RepositoryRecord, tagID graveler.TagID) (bool, error) {
+	for _, tag := range l.heldTags {
+		if tag == tagID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (m *RefsFake) GetRepositoryMetadata(_ context.Context, _ graveler.RepositoryID) (graveler.RepositoryMetadata, error) {
 	// TODO implement me
 	panic("implement me")
@@ -894,6 +1112,16 @@ func (m *RefsFake) SetRepositoryMetadata(_ context.Context, _ *graveler.Reposito
 	panic("implement me")
 }
 
+func (m *RefsFake) SetRepositoryReadOnly(_ context.Context, repository *graveler.RepositoryRecord, readOnly bool) error {
+	repository.ReadOnly = readOnly
+	return nil
+}
+
+func (m *RefsFake) SetDefaultBranch(_ context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	repository.DefaultBranchID = branchID
+	return nil
+}
+
 func (m *RefsFake) CreateCommitRecord(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.CommitID, _ graveler.Commit) error {
 	// TODO implement me
 	panic("implement me")
@@ -10,30 +10,52 @@ import (
 )
 
 type GravelerTest struct {
-	Controller               *gomock.Controller
-	CommittedManager         *mock.MockCommittedManager
-	RefManager               *mock.MockRefManager
-	StagingManager           *mock.MockStagingManager
-	ProtectedBranchesManager *mock.MockProtectedBranchesManager
-	GarbageCollectionManager *mock.MockGarbageCollectionManager
-	KVStore                  *kvmock.MockStore
-	Sut                      *graveler.Graveler
+	Controller                  *gomock.Controller
+	CommittedManager            *mock.MockCommittedManager
+	RefManager                  *mock.MockRefManager
+	StagingManager              *mock.MockStagingManager
+	ProtectedBranchesManager    *mock.MockProtectedBranchesManager
+	GarbageCollectionManager    *mock.MockGarbageCollectionManager
+	LegalHoldManager            *mock.MockLegalHoldManager
+	LockedBranchesManager       *mock.MockLockedBranchesManager
+	PassThroughManager          *mock.MockPassThroughManager
+	CORSManager                 *mock.MockCORSManager
+	ObjectLockManager           *mock.MockObjectLockManager
+	CostAttributionManager      *mock.MockCostAttributionManager
+	KeyValidationManager        *mock.MockKeyValidationManager
+	CommitTemplateManager       *mock.MockCommitTemplateManager
+	BucketNotificationManager   *mock.MockBucketNotificationManager
+	BlockAdapterOverrideManager *mock.MockBlockAdapterOverrideManager
+	LifecycleRulesManager       *mock.MockLifecycleRulesManager
+	KVStore                     *kvmock.MockStore
+	Sut                         *graveler.Graveler
 }
 
 func InitGravelerTest(t *testing.T) *GravelerTest {
 	ctrl := gomock.NewController(t)
 
 	test := &GravelerTest{
-		Controller:               ctrl,
-		CommittedManager:         mock.NewMockCommittedManager(ctrl),
-		StagingManager:           mock.NewMockStagingManager(ctrl),
-		RefManager:               mock.NewMockRefManager(ctrl),
-		GarbageCollectionManager: mock.NewMockGarbageCollectionManager(ctrl),
-		ProtectedBranchesManager: mock.NewMockProtectedBranchesManager(ctrl),
-		KVStore:                  kvmock.NewMockStore(ctrl),
+		Controller:                  ctrl,
+		CommittedManager:            mock.NewMockCommittedManager(ctrl),
+		StagingManager:              mock.NewMockStagingManager(ctrl),
+		RefManager:                  mock.NewMockRefManager(ctrl),
+		GarbageCollectionManager:    mock.NewMockGarbageCollectionManager(ctrl),
+		ProtectedBranchesManager:    mock.NewMockProtectedBranchesManager(ctrl),
+		LegalHoldManager:            mock.NewMockLegalHoldManager(ctrl),
+		LockedBranchesManager:       mock.NewMockLockedBranchesManager(ctrl),
+		PassThroughManager:          mock.NewMockPassThroughManager(ctrl),
+		CORSManager:                 mock.NewMockCORSManager(ctrl),
+		ObjectLockManager:           mock.NewMockObjectLockManager(ctrl),
+		CostAttributionManager:      mock.NewMockCostAttributionManager(ctrl),
+		KeyValidationManager:        mock.NewMockKeyValidationManager(ctrl),
+		CommitTemplateManager:       mock.NewMockCommitTemplateManager(ctrl),
+		BucketNotificationManager:   mock.NewMockBucketNotificationManager(ctrl),
+		BlockAdapterOverrideManager: mock.NewMockBlockAdapterOverrideManager(ctrl),
+		LifecycleRulesManager:       mock.NewMockLifecycleRulesManager(ctrl),
+		KVStore:                     kvmock.NewMockStore(ctrl),
 	}
 
-	test.Sut = graveler.NewGraveler(test.CommittedManager, test.StagingManager, test.RefManager, test.GarbageCollectionManager, test.ProtectedBranchesManager)
+	test.Sut = graveler.NewGraveler(test.CommittedManager, test.StagingManager, test.RefManager, test.GarbageCollectionManager, test.ProtectedBranchesManager, test.LegalHoldManager, test.LockedBranchesManager, test.PassThroughManager, test.CORSManager, test.ObjectLockManager, test.CostAttributionManager, test.KeyValidationManager, test.CommitTemplateManager, test.BucketNotificationManager, test.BlockAdapterOverrideManager, test.LifecycleRulesManager)
 
 	return test
 }
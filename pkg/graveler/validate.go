@@ -46,6 +46,20 @@ func ValidateBranchID(v interface{}) error {
 	return nil
 }
 
+func ValidateStashID(v interface{}) error {
+	s, ok := v.(StashID)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	if len(s) == 0 {
+		return ErrRequiredValue
+	}
+	if !validator.ReValidBranchID.MatchString(s.String()) {
+		return ErrInvalidStashID
+	}
+	return nil
+}
+
 func ValidateTagID(v interface{}) error {
 	s, ok := v.(TagID)
 	if !ok {
@@ -109,8 +123,10 @@ func ValidateRequiredStrategy(v interface{}) error {
 		panic(ErrInvalidType)
 	}
 
-	if s != "dest-wins" && s != "source-wins" && s != "" {
+	switch s {
+	case MergeStrategyDestWinsStr, MergeStrategySrcWinsStr, MergeStrategyFailStr, "":
+		return nil
+	default:
 		return ErrInvalidValue
 	}
-	return nil
 }
@@ -0,0 +1,304 @@
+package httputil
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/logging"
+	"golang.org/x/crypto/acme"
+)
+
+var (
+	ErrACMEChallengeNotOffered = errors.New("ACME challenge not offered")
+	ErrInvalidPEM              = errors.New("invalid PEM")
+)
+
+const (
+	// dns01RenewBefore is the time before expiry a certificate obtained with the DNS-01 challenge is renewed
+	dns01RenewBefore = 30 * 24 * time.Hour
+	// dns01RenewCheckInterval is the interval between checks whether the certificate should be renewed
+	dns01RenewCheckInterval = 12 * time.Hour
+	dns01CertFile           = "dns01.pem"
+	dns01AccountKeyFile     = "dns01_account.key"
+)
+
+// DNSHook presents and cleans up the TXT records of DNS-01 challenges
+type DNSHook interface {
+	Present(ctx context.Context, fqdn, value string) error
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// CommandDNSHook runs Command with the arguments "present" or "cleanup", the record name and the record value, to
+// manage challenge records with the API of any DNS provider
+type CommandDNSHook struct {
+	Command string
+}
+
+func (h *CommandDNSHook) Present(ctx context.Context, fqdn, value string) error {
+	return h.run(ctx, "present", fqdn, value)
+}
+
+func (h *CommandDNSHook) CleanUp(ctx context.Context, fqdn, value string) error {
+	return h.run(ctx, "cleanup", fqdn, value)
+}
+
+func (h *CommandDNSHook) run(ctx context.Context, action, fqdn, value string) error {
+	out, err := exec.CommandContext(ctx, h.Command, action, fqdn, value).CombinedOutput() //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("DNS hook %s %s: %w: %s", action, fqdn, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DNS01Config configures obtaining a certificate with the ACME DNS-01 challenge
+type DNS01Config struct {
+	ACMEConfig
+	Hook DNSHook
+	// PropagationWait is the time to wait after presenting challenge records, before the certificate authority
+	// validates them
+	PropagationWait time.Duration
+}
+
+// DNS01Manager obtains a single certificate for all its domains with the ACME DNS-01 challenge, and renews it before
+// it expires. Unlike the TLS-ALPN-01 and HTTP-01 challenges, DNS-01 issues wildcard certificates and does not require
+// the domains to reach lakeFS.
+type DNS01Manager struct {
+	cfg    DNS01Config
+	client *acme.Client
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewDNS01Manager returns a manager serving the certificate cached from a previous run, or a newly obtained one
+func NewDNS01Manager(ctx context.Context, cfg DNS01Config) (*DNS01Manager, error) {
+	if err := os.MkdirAll(cfg.CacheDir, 0o700); err != nil { //nolint:gomnd
+		return nil, err
+	}
+	accountKey, err := loadOrCreateKey(filepath.Join(cfg.CacheDir, dns01AccountKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	m := &DNS01Manager{
+		cfg:    cfg,
+		client: &acme.Client{Key: accountKey, DirectoryURL: cfg.DirectoryURL},
+	}
+	if m.client.DirectoryURL == "" {
+		m.client.DirectoryURL = acme.LetsEncryptURL
+	}
+	if cert, err := loadCertificate(filepath.Join(cfg.CacheDir, dns01CertFile)); err == nil && !m.shouldRenew(cert) {
+		m.cert = cert
+		return m, nil
+	}
+	if err := m.renew(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetCertificate returns the certificate to serve, it is used as tls.Config GetCertificate
+func (m *DNS01Manager) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cert, nil
+}
+
+// RenewLoop renews the certificate before it expires, until ctx is done
+func (m *DNS01Manager) RenewLoop(ctx context.Context) {
+	ticker := time.NewTicker(dns01RenewCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.RLock()
+			renew := m.shouldRenew(m.cert)
+			m.mu.RUnlock()
+			if !renew {
+				continue
+			}
+			if err := m.renew(ctx); err != nil {
+				// keep serving the current certificate and retry on the next check
+				logging.FromContext(ctx).WithError(err).Warn("Failed to renew ACME certificate")
+			}
+		}
+	}
+}
+
+func (m *DNS01Manager) shouldRenew(cert *tls.Certificate) bool {
+	return cert.Leaf == nil || time.Until(cert.Leaf.NotAfter) < dns01RenewBefore
+}
+
+// renew obtains a new certificate for the domains and caches it
+func (m *DNS01Manager) renew(ctx context.Context) error {
+	_, err := m.client.Register(ctx, &acme.Account{Contact: contact(m.cfg.Email)}, acme.AcceptTOS)
+	if err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("register ACME account: %w", err)
+	}
+	order, err := m.client.AuthorizeOrder(ctx, acme.DomainIDs(m.cfg.Domains...))
+	if err != nil {
+		return fmt.Errorf("authorize order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := m.authorize(ctx, authzURL); err != nil {
+			return err
+		}
+	}
+	order, err = m.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return fmt.Errorf("wait order: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: m.cfg.Domains}, key)
+	if err != nil {
+		return err
+	}
+	der, _, err := m.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("create certificate: %w", err)
+	}
+	cert, err := saveCertificate(filepath.Join(m.cfg.CacheDir, dns01CertFile), der, key)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.cert = cert
+	m.mu.Unlock()
+	return nil
+}
+
+// authorize fulfills the DNS-01 challenge of the authorization at authzURL
+func (m *DNS01Manager) authorize(ctx context.Context, authzURL string) error {
+	authz, err := m.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("%w: dns-01 for %s", ErrACMEChallengeNotOffered, authz.Identifier.Value)
+	}
+	value, err := m.client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+	// the record of a wildcard domain is on its parent domain
+	fqdn := "_acme-challenge." + strings.TrimPrefix(authz.Identifier.Value, "*.")
+	if err := m.cfg.Hook.Present(ctx, fqdn, value); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.cfg.Hook.CleanUp(context.WithoutCancel(ctx), fqdn, value); err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("record", fqdn).Warn("Failed to clean up ACME challenge record")
+		}
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(m.cfg.PropagationWait):
+	}
+	if _, err := m.client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("accept challenge: %w", err)
+	}
+	if _, err := m.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("wait authorization of %s: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+func contact(email string) []string {
+	if email == "" {
+		return nil
+	}
+	return []string{"mailto:" + email}
+}
+
+func loadOrCreateKey(name string) (crypto.Signer, error) {
+	data, err := os.ReadFile(name)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s: %w", name, ErrInvalidPEM)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(name, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil { //nolint:gomnd
+		return nil, err
+	}
+	return key, nil
+}
+
+// saveCertificate writes the key and the certificate chain der to name, and returns the certificate
+func saveCertificate(name string, der [][]byte, key *ecdsa.PrivateKey) (*tls.Certificate, error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	for _, b := range der {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b})...)
+	}
+	if err := os.WriteFile(name, data, 0o600); err != nil { //nolint:gomnd
+		return nil, err
+	}
+	return parseCertificate(data)
+}
+
+func loadCertificate(name string) (*tls.Certificate, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(data)
+}
+
+// parseCertificate parses the PEM key and certificate chain in data, setting the leaf of the certificate
+func parseCertificate(data []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
@@ -0,0 +1,107 @@
+package httputil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// createCertificate returns a self-signed certificate for commonName valid until notAfter, and its key
+func createCertificate(t *testing.T, commonName string, notAfter time.Time) ([][]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %s", err)
+	}
+	return [][]byte{der}, key
+}
+
+func TestDNS01ManagerCachedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	der, key := createCertificate(t, "cached", time.Now().AddDate(0, 0, 60))
+	if _, err := saveCertificate(filepath.Join(dir, dns01CertFile), der, key); err != nil {
+		t.Fatalf("save certificate: %s", err)
+	}
+
+	// a cached certificate far from expiry is served without contacting the certificate authority
+	m, err := NewDNS01Manager(context.Background(), DNS01Config{
+		ACMEConfig: ACMEConfig{Domains: []string{"*.s3.example.com"}, CacheDir: dir, DirectoryURL: "http://127.0.0.1:0/directory"},
+	})
+	if err != nil {
+		t.Fatalf("NewDNS01Manager: %s", err)
+	}
+	cert, err := m.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate: %s", err)
+	}
+	if cn := cert.Leaf.Subject.CommonName; cn != "cached" {
+		t.Fatalf("Served %s, expected cached", cn)
+	}
+	if _, err := os.Stat(filepath.Join(dir, dns01AccountKeyFile)); err != nil {
+		t.Fatalf("Account key not created: %s", err)
+	}
+}
+
+func TestDNS01ManagerRenewExpiring(t *testing.T) {
+	dir := t.TempDir()
+	der, key := createCertificate(t, "expiring", time.Now().AddDate(0, 0, 10))
+	if _, err := saveCertificate(filepath.Join(dir, dns01CertFile), der, key); err != nil {
+		t.Fatalf("save certificate: %s", err)
+	}
+
+	// a certificate about to expire is renewed, failing here as the certificate authority is unreachable
+	_, err := NewDNS01Manager(context.Background(), DNS01Config{
+		ACMEConfig: ACMEConfig{Domains: []string{"*.s3.example.com"}, CacheDir: dir, DirectoryURL: "http://127.0.0.1:0/directory"},
+	})
+	if err == nil {
+		t.Fatal("Expected renewal of an expiring certificate to fail without a certificate authority")
+	}
+}
+
+func TestCommandDNSHook(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "hook.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$1 $2 $3\" >> "+out+"\n"), 0o700); err != nil {
+		t.Fatalf("write hook: %s", err)
+	}
+	hook := &CommandDNSHook{Command: script}
+	ctx := context.Background()
+	if err := hook.Present(ctx, "_acme-challenge.example.com", "value"); err != nil {
+		t.Fatalf("Present: %s", err)
+	}
+	if err := hook.CleanUp(ctx, "_acme-challenge.example.com", "value"); err != nil {
+		t.Fatalf("CleanUp: %s", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read hook output: %s", err)
+	}
+	expected := "present _acme-challenge.example.com value\ncleanup _acme-challenge.example.com value\n"
+	if string(data) != expected {
+		t.Fatalf("Hook called with %q, expected %q", data, expected)
+	}
+
+	failing := &CommandDNSHook{Command: filepath.Join(dir, "missing")}
+	if err := failing.Present(ctx, "_acme-challenge.example.com", "value"); err == nil {
+		t.Fatal("Expected missing hook command to fail")
+	}
+}
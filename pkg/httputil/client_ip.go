@@ -0,0 +1,64 @@
+package httputil
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the address of the client of r without its port.  Requests from one of
+// trustedProxies are attributed to the last address of their X-Forwarded-For header that is not
+// a trusted proxy, as earlier addresses are set by the client and cannot be trusted.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host := SourceHost(r)
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if addr == "" {
+			continue
+		}
+		host = addr
+		if !isTrustedProxy(addr, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+func isTrustedProxy(host string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses the addresses and CIDR ranges of trusted proxies
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,56 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+func TestClientIP(t *testing.T) {
+	trustedProxies, err := httputil.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("parse trusted proxies: %s", err)
+	}
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+		trustProxies bool
+	}{
+		{name: "direct", remoteAddr: "203.0.113.7:1234", expectedIP: "203.0.113.7", trustProxies: true},
+		{name: "untrusted_forwarded", remoteAddr: "203.0.113.7:1234", forwardedFor: []string{"198.51.100.1"}, expectedIP: "203.0.113.7", trustProxies: true},
+		{name: "trusted_proxy", remoteAddr: "10.1.2.3:1234", forwardedFor: []string{"198.51.100.1"}, expectedIP: "198.51.100.1", trustProxies: true},
+		{name: "spoofed_by_client", remoteAddr: "10.1.2.3:1234", forwardedFor: []string{"1.1.1.1, 198.51.100.1"}, expectedIP: "198.51.100.1", trustProxies: true},
+		{name: "proxy_chain", remoteAddr: "10.1.2.3:1234", forwardedFor: []string{"198.51.100.1", "192.168.1.1"}, expectedIP: "198.51.100.1", trustProxies: true},
+		{name: "only_proxies", remoteAddr: "10.1.2.3:1234", forwardedFor: []string{"10.0.0.1"}, expectedIP: "10.0.0.1", trustProxies: true},
+		{name: "no_header", remoteAddr: "10.1.2.3:1234", expectedIP: "10.1.2.3", trustProxies: true},
+		{name: "no_trusted_proxies", remoteAddr: "10.1.2.3:1234", forwardedFor: []string{"198.51.100.1"}, expectedIP: "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			proxies := trustedProxies
+			if !tt.trustProxies {
+				proxies = nil
+			}
+			if ip := httputil.ClientIP(r, proxies); ip != tt.expectedIP {
+				t.Errorf("ClientIP() = %s, expected %s", ip, tt.expectedIP)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	for _, proxy := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := httputil.ParseTrustedProxies([]string{proxy}); err == nil {
+			t.Errorf("ParseTrustedProxies(%s) expected an error", proxy)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// CompressResponseMiddleware compresses the responses of contentTypes with gzip or deflate, as accepted by the
+// Accept-Encoding header of the request. Responses of other content types, such as object data, are passed as is.
+func CompressResponseMiddleware(level int, contentTypes ...string) func(http.Handler) http.Handler {
+	return middleware.Compress(level, contentTypes...)
+}
+
+// DecompressRequestMiddleware decodes the bodies of requests of contentTypes encoded with gzip or deflate, as set by
+// their Content-Encoding header. Decoded bodies are limited to maxSize bytes. Requests of contentTypes with another
+// encoding are rejected, requests of other content types are passed as is.
+func DecompressRequestMiddleware(maxSize int64, contentTypes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if !slices.Contains(contentTypes, contentType) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var (
+				body io.ReadCloser
+				err  error
+			)
+			switch encoding {
+			case "gzip", "x-gzip":
+				body, err = gzip.NewReader(r.Body)
+			case "deflate":
+				body, err = zlib.NewReader(r.Body)
+			default:
+				http.Error(w, "unsupported content encoding "+encoding, http.StatusUnsupportedMediaType)
+				return
+			}
+			if err != nil {
+				http.Error(w, "bad "+encoding+" request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer func() { _ = body.Close() }()
+			r.Body = http.MaxBytesReader(w, body, maxSize)
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
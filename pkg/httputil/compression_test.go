@@ -0,0 +1,112 @@
+package httputil_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+func TestCompressResponseMiddleware(t *testing.T) {
+	body := strings.Repeat(`{"path":"data/object"}`, 100)
+	handler := httputil.CompressResponseMiddleware(5, "application/json")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		_, _ = io.WriteString(w, body)
+	}))
+
+	cases := []struct {
+		Name             string
+		ContentType      string
+		AcceptEncoding   string
+		ExpectedEncoding string
+	}{
+		{"gzip", "application/json", "gzip", "gzip"},
+		{"deflate", "application/json", "deflate", "deflate"},
+		{"not_accepted", "application/json", "", ""},
+		{"not_compressible", "application/octet-stream", "gzip", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?type="+tc.ContentType, nil)
+			if tc.AcceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.AcceptEncoding)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			encoding := rr.Header().Get("Content-Encoding")
+			if encoding != tc.ExpectedEncoding {
+				t.Fatalf("Content-Encoding %q, expected %q", encoding, tc.ExpectedEncoding)
+			}
+			if encoding == "" && rr.Body.String() != body {
+				t.Fatalf("got uncompressed body %q, expected %q", rr.Body.String(), body)
+			}
+			if encoding != "" && rr.Body.Len() >= len(body) {
+				t.Fatalf("got %d bytes compressed from %d", rr.Body.Len(), len(body))
+			}
+		})
+	}
+}
+
+func TestDecompressRequestMiddleware(t *testing.T) {
+	const body = `{"message":"compressed"}`
+	handler := httputil.DecompressRequestMiddleware(1024, "application/json")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+
+	var gzipped, deflated bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = io.WriteString(gz, body)
+	_ = gz.Close()
+	zw := zlib.NewWriter(&deflated)
+	_, _ = io.WriteString(zw, body)
+	_ = zw.Close()
+	var large bytes.Buffer
+	gz = gzip.NewWriter(&large)
+	_, _ = io.WriteString(gz, strings.Repeat(" ", 2048))
+	_ = gz.Close()
+
+	cases := []struct {
+		Name           string
+		ContentType    string
+		Encoding       string
+		Body           []byte
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		{"gzip", "application/json", "gzip", gzipped.Bytes(), http.StatusOK, body},
+		{"deflate", "application/json; charset=utf-8", "deflate", deflated.Bytes(), http.StatusOK, body},
+		{"identity", "application/json", "", []byte(body), http.StatusOK, body},
+		{"other_content_type", "application/octet-stream", "gzip", gzipped.Bytes(), http.StatusOK, gzipped.String()},
+		{"unsupported", "application/json", "br", []byte(body), http.StatusUnsupportedMediaType, ""},
+		{"corrupt", "application/json", "gzip", []byte(body), http.StatusBadRequest, ""},
+		{"too_large", "application/json", "gzip", large.Bytes(), http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tc.Body))
+			req.Header.Set("Content-Type", tc.ContentType)
+			if tc.Encoding != "" {
+				req.Header.Set("Content-Encoding", tc.Encoding)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tc.ExpectedStatus {
+				t.Fatalf("status %d, expected %d: %s", rr.Code, tc.ExpectedStatus, rr.Body.String())
+			}
+			if tc.ExpectedBody != "" && rr.Body.String() != tc.ExpectedBody {
+				t.Fatalf("body %q, expected %q", rr.Body.String(), tc.ExpectedBody)
+			}
+		})
+	}
+}
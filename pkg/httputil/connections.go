@@ -0,0 +1,140 @@
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+var (
+	connectionsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "http_connections_total",
+		Help: "Number of connections accepted by the HTTP server",
+	})
+	openConnectionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_open_connections",
+		Help: "Number of open HTTP server connections by state",
+	}, []string{"state"})
+	connectionRequestsHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "http_connection_requests",
+		Help:    "Number of requests served on each closed HTTP server connection, low counts indicate little connection reuse",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8), //nolint:gomnd
+	})
+)
+
+// ServerConfig configures the connections of an HTTP server
+type ServerConfig struct {
+	// TLS is set when the server serves TLS connections, on which HTTP/2 is negotiated
+	TLS          bool
+	HTTP2Enabled bool
+	// H2CEnabled serves HTTP/2 over cleartext (h2c) when the server does not serve TLS
+	H2CEnabled bool
+	// IdleTimeout is the time an idle keep-alive connection is kept open, no timeout if zero
+	IdleTimeout          time.Duration
+	MaxConcurrentStreams uint32
+	KeepAlivesEnabled    bool
+}
+
+type connectionStatsContextKey struct{}
+
+// connectionStats tracks a connection of the server
+type connectionStats struct {
+	state    http.ConnState
+	requests atomic.Int64
+}
+
+// connectionTracker reports the metrics of the connections of a server
+type connectionTracker struct {
+	mu          sync.Mutex
+	connections map[net.Conn]*connectionStats
+}
+
+// ConfigureServer applies cfg to the connections of server, and reports metrics of its connections. Call it after
+// setting the server handler and before the server starts serving.
+func ConfigureServer(server *http.Server, cfg ServerConfig) error {
+	server.IdleTimeout = cfg.IdleTimeout
+	server.SetKeepAlivesEnabled(cfg.KeepAlivesEnabled)
+
+	tracker := &connectionTracker{connections: make(map[net.Conn]*connectionStats)}
+	server.ConnContext = tracker.connContext
+	server.ConnState = tracker.connState
+	server.Handler = countRequests(server.Handler)
+
+	if !cfg.HTTP2Enabled {
+		// a non-nil empty map disables HTTP/2 negotiation on TLS connections
+		server.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+		if server.TLSConfig != nil {
+			server.TLSConfig.NextProtos = slices.DeleteFunc(slices.Clone(server.TLSConfig.NextProtos), func(proto string) bool {
+				return proto == http2.NextProtoTLS
+			})
+		}
+		return nil
+	}
+	h2Server := &http2.Server{
+		MaxConcurrentStreams: cfg.MaxConcurrentStreams,
+		IdleTimeout:          cfg.IdleTimeout,
+	}
+	if cfg.TLS {
+		return http2.ConfigureServer(server, h2Server)
+	}
+	if cfg.H2CEnabled {
+		server.Handler = h2c.NewHandler(server.Handler, h2Server)
+	}
+	return nil
+}
+
+func (t *connectionTracker) connContext(ctx context.Context, conn net.Conn) context.Context {
+	stats := &connectionStats{state: http.StateNew}
+	t.mu.Lock()
+	t.connections[conn] = stats
+	t.mu.Unlock()
+	return context.WithValue(ctx, connectionStatsContextKey{}, stats)
+}
+
+func (t *connectionTracker) connState(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	stats, ok := t.connections[conn]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	previous := stats.state
+	stats.state = state
+	closed := state == http.StateClosed || state == http.StateHijacked
+	if closed {
+		delete(t.connections, conn)
+	}
+	t.mu.Unlock()
+
+	if state == http.StateNew {
+		connectionsCounter.Inc()
+	} else {
+		openConnectionsGauge.WithLabelValues(previous.String()).Dec()
+	}
+	switch {
+	case state == http.StateClosed:
+		connectionRequestsHistogram.Observe(float64(stats.requests.Load()))
+	case !closed:
+		openConnectionsGauge.WithLabelValues(state.String()).Inc()
+	}
+}
+
+// countRequests counts the requests served on each connection, including the streams of HTTP/2 connections
+func countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stats, ok := r.Context().Value(connectionStatsContextKey{}).(*connectionStats); ok {
+			stats.requests.Add(1)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,107 @@
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+)
+
+func newTestServer(t *testing.T, cfg ServerConfig) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	if err := ConfigureServer(srv.Config, cfg); err != nil {
+		t.Fatalf("ConfigureServer: %s", err)
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getProto(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %s", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %s", err)
+	}
+	return string(body)
+}
+
+func TestConfigureServer(t *testing.T) {
+	h2cClient := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	t.Run("h2c", func(t *testing.T) {
+		srv := newTestServer(t, ServerConfig{HTTP2Enabled: true, H2CEnabled: true, KeepAlivesEnabled: true})
+		if proto := getProto(t, h2cClient, srv.URL); proto != "HTTP/2.0" {
+			t.Fatalf("Served %s, expected HTTP/2.0", proto)
+		}
+		if proto := getProto(t, srv.Client(), srv.URL); proto != "HTTP/1.1" {
+			t.Fatalf("Served %s, expected HTTP/1.1", proto)
+		}
+	})
+
+	t.Run("h2c_disabled", func(t *testing.T) {
+		srv := newTestServer(t, ServerConfig{HTTP2Enabled: true, KeepAlivesEnabled: true})
+		if _, err := h2cClient.Get(srv.URL); err == nil {
+			t.Fatal("Expected cleartext HTTP/2 request to fail with h2c disabled")
+		}
+		if proto := getProto(t, srv.Client(), srv.URL); proto != "HTTP/1.1" {
+			t.Fatalf("Served %s, expected HTTP/1.1", proto)
+		}
+	})
+
+	t.Run("http2_disabled", func(t *testing.T) {
+		srv := newTestServer(t, ServerConfig{KeepAlivesEnabled: true})
+		if _, err := h2cClient.Get(srv.URL); err == nil {
+			t.Fatal("Expected HTTP/2 request to fail with HTTP/2 disabled")
+		}
+	})
+
+	t.Run("requests_per_connection", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		if err := ConfigureServer(srv.Config, ServerConfig{KeepAlivesEnabled: true}); err != nil {
+			t.Fatalf("ConfigureServer: %s", err)
+		}
+		var conns []*connectionStats
+		connContext := srv.Config.ConnContext
+		srv.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			ctx = connContext(ctx, c)
+			conns = append(conns, ctx.Value(connectionStatsContextKey{}).(*connectionStats))
+			return ctx
+		}
+		srv.Start()
+		defer srv.Close()
+
+		const requests = 3
+		client := srv.Client()
+		for i := 0; i < requests; i++ {
+			getProto(t, client, srv.URL)
+		}
+		if len(conns) != 1 {
+			t.Fatalf("Opened %d connections, expected 1", len(conns))
+		}
+		if n := conns[0].requests.Load(); n != requests {
+			t.Fatalf("Counted %d requests on the connection, expected %d", n, requests)
+		}
+	})
+}
@@ -5,6 +5,8 @@ import "net/http"
 type MetricResponseWriter struct {
 	http.ResponseWriter
 	StatusCode int
+	// BytesWritten is the size of the response body written
+	BytesWritten int64
 }
 
 func NewMetricResponseWriter(w http.ResponseWriter) *MetricResponseWriter {
@@ -15,3 +17,9 @@ func (mrw *MetricResponseWriter) WriteHeader(code int) {
 	mrw.StatusCode = code
 	mrw.ResponseWriter.WriteHeader(code)
 }
+
+func (mrw *MetricResponseWriter) Write(b []byte) (int, error) {
+	n, err := mrw.ResponseWriter.Write(b)
+	mrw.BytesWritten += int64(n)
+	return n, err
+}
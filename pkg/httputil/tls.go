@@ -0,0 +1,141 @@
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/logging"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var ErrACMEHostNotAllowed = errors.New("host not allowed by ACME domains")
+
+// certificateCheckInterval is the minimal time between checks of the certificate files for changes
+const certificateCheckInterval = 10 * time.Second
+
+// CertificateReloader serves a certificate loaded from files, and reloads it once the files change so that rotated
+// certificates are served without a restart.
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the certificate to serve, it is used as tls.Config GetCertificate
+func (r *CertificateReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.lastCheck) < certificateCheckInterval {
+		return r.cert, nil
+	}
+	r.lastCheck = time.Now()
+	modTime, err := r.filesModTime()
+	if err == nil && !modTime.Equal(r.modTime) {
+		err = r.load(modTime)
+	}
+	if err != nil {
+		// rotation may replace the certificate and key files one at a time, keep serving the loaded certificate
+		// and retry on a later check
+		logging.ContextUnavailable().WithError(err).WithField("cert_file", r.certFile).Warn("Failed to reload TLS certificate")
+	}
+	return r.cert, nil
+}
+
+func (r *CertificateReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate %s: %w", r.certFile, err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// filesModTime returns the latest modification time of the certificate and key files
+func (r *CertificateReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// ACMEConfig configures obtaining certificates from an ACME certificate authority, such as Let's Encrypt
+type ACMEConfig struct {
+	Email string
+	// Domains are the host names certificates are obtained for. A "*." domain allows its direct subdomains that
+	// AllowSubdomain accepts, each with a certificate of its own.
+	Domains []string
+	// AllowSubdomain reports whether a certificate may be obtained for a subdomain of a "*." domain, such as the
+	// name of an existing repository. No subdomain is allowed if it is nil.
+	AllowSubdomain func(ctx context.Context, subdomain string) bool
+	CacheDir       string
+	// DirectoryURL of the ACME certificate authority, Let's Encrypt if empty
+	DirectoryURL string
+}
+
+// NewACMEManager returns a manager obtaining and renewing certificates on demand, using the TLS-ALPN-01 challenge
+// on the TLS listener, or the HTTP-01 challenge when its HTTPHandler is served on port 80.
+func NewACMEManager(cfg ACMEConfig) *autocert.Manager {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cfg.CacheDir),
+		HostPolicy: acmeHostPolicy(cfg.Domains, cfg.AllowSubdomain),
+		Email:      cfg.Email,
+	}
+	if cfg.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	return manager
+}
+
+// acmeHostPolicy allows only the hosts of domains, so that requests to other host names cannot make the manager
+// obtain certificates for them
+func acmeHostPolicy(domains []string, allowSubdomain func(ctx context.Context, subdomain string) bool) autocert.HostPolicy {
+	return func(ctx context.Context, host string) error {
+		for _, domain := range domains {
+			if strings.EqualFold(host, domain) {
+				return nil
+			}
+			parent, isWildcard := strings.CutPrefix(domain, "*.")
+			if !isWildcard || allowSubdomain == nil || len(host) <= len(parent)+1 {
+				continue
+			}
+			sub, suffix := host[:len(host)-len(parent)-1], host[len(host)-len(parent)-1:]
+			if strings.EqualFold(suffix, "."+parent) && !strings.Contains(sub, ".") && allowSubdomain(ctx, strings.ToLower(sub)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: host %s", ErrACMEHostNotAllowed, host)
+	}
+}
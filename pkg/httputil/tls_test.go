@@ -0,0 +1,133 @@
+package httputil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate for commonName and its key to certFile and keyFile
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %s", err)
+	}
+	for name, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(name, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("write %s: %s", name, err)
+		}
+		if err := os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatalf("chtimes %s: %s", name, err)
+		}
+	}
+}
+
+func servedCommonName(t *testing.T, r *CertificateReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %s", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	now := time.Now()
+	writeCertificate(t, certFile, keyFile, "first", now.Add(-time.Minute))
+
+	r, err := NewCertificateReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewCertificateReloader: %s", err)
+	}
+	if cn := servedCommonName(t, r); cn != "first" {
+		t.Fatalf("Served %s, expected first", cn)
+	}
+
+	writeCertificate(t, certFile, keyFile, "second", now)
+	if cn := servedCommonName(t, r); cn != "first" {
+		t.Fatalf("Served %s before the check interval passed, expected first", cn)
+	}
+	r.lastCheck = time.Time{}
+	if cn := servedCommonName(t, r); cn != "second" {
+		t.Fatalf("Served %s after rotation, expected second", cn)
+	}
+
+	// a partially written rotation keeps serving the loaded certificate
+	if err := os.WriteFile(certFile, []byte("partial"), 0o600); err != nil {
+		t.Fatalf("write %s: %s", certFile, err)
+	}
+	r.lastCheck = time.Time{}
+	if cn := servedCommonName(t, r); cn != "second" {
+		t.Fatalf("Served %s after a failed reload, expected second", cn)
+	}
+}
+
+func TestACMEHostPolicy(t *testing.T) {
+	repositories := map[string]bool{"repo": true}
+	policy := acmeHostPolicy([]string{"lakefs.example.com", "*.s3.example.com"}, func(_ context.Context, subdomain string) bool {
+		return repositories[subdomain]
+	})
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{host: "lakefs.example.com", allowed: true},
+		{host: "LAKEFS.example.com", allowed: true},
+		{host: "repo.s3.example.com", allowed: true},
+		{host: "REPO.s3.example.com", allowed: true},
+		{host: "missing.s3.example.com", allowed: false},
+		{host: "s3.example.com", allowed: false},
+		{host: "a.repo.s3.example.com", allowed: false},
+		{host: "reposs3.example.com", allowed: false},
+		{host: "other.example.com", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := policy(context.Background(), tt.host)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Fatalf("Host %s allowed %t, expected %t (err=%v)", tt.host, allowed, tt.allowed, err)
+			}
+		})
+	}
+}
+
+func TestACMEHostPolicyNoSubdomains(t *testing.T) {
+	policy := acmeHostPolicy([]string{"*.s3.example.com"}, nil)
+	if err := policy(context.Background(), "repo.s3.example.com"); err == nil {
+		t.Fatal("Expected subdomain not to be allowed without AllowSubdomain")
+	}
+}
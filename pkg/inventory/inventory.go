@@ -0,0 +1,404 @@
+// Package inventory generates S3 Inventory reports of the objects of branches, so that tooling built for S3
+// Inventory (reconciliation, cost analysis) consumes lakeFS repositories unchanged. Reports describe branches as seen
+// through the S3 gateway: the repository is the bucket and objects are keyed by the branch followed by their path.
+package inventory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	FormatCSV     = "CSV"
+	FormatParquet = "Parquet"
+
+	// ManifestVersion is the S3 Inventory manifest version generated
+	ManifestVersion = "2016-11-30"
+
+	csvFileSchema     = "Bucket, Key, Size, LastModifiedDate, ETag, StorageClass"
+	parquetFileSchema = "message s3.inventory { required binary bucket (UTF8); required binary key (UTF8); " +
+		"optional int64 size; optional int64 last_modified_date (TIMESTAMP_MILLIS); " +
+		"optional binary e_tag (UTF8); optional binary storage_class (UTF8); }"
+
+	manifestTimeLayout = "2006-01-02T15-04Z"
+	hiveTimeLayout     = "2006-01-02-15-04"
+)
+
+var ErrBadConfig = errors.New("invalid inventory configuration")
+
+// Manifest is the manifest.json of an S3 Inventory report
+type Manifest struct {
+	SourceBucket      string         `json:"sourceBucket"`
+	DestinationBucket string         `json:"destinationBucket"`
+	Version           string         `json:"version"`
+	CreationTimestamp string         `json:"creationTimestamp"`
+	FileFormat        string         `json:"fileFormat"`
+	FileSchema        string         `json:"fileSchema"`
+	Files             []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a data file listed by a Manifest, its key relative to the destination bucket
+type ManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// Service generates the configured reports, every configured interval as a background job
+type Service struct {
+	cfg     config.Inventory
+	catalog *catalog.Catalog
+	logger  logging.Logger
+}
+
+func NewService(cfg config.Inventory, c *catalog.Catalog, logger logging.Logger) (*Service, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return &Service{
+		cfg:     cfg,
+		catalog: c,
+		logger:  logger,
+	}, nil
+}
+
+func validateConfig(cfg config.Inventory) error {
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval must be positive: %w", ErrBadConfig)
+	}
+	if cfg.MaxFileObjects <= 0 {
+		return fmt.Errorf("max file objects must be positive: %w", ErrBadConfig)
+	}
+	ids := make(map[string]struct{}, len(cfg.Reports))
+	for _, report := range cfg.Reports {
+		if report.ID == "" || report.Repository == "" || report.Branch == "" {
+			return fmt.Errorf("report must have an id, a repository and a branch: %w", ErrBadConfig)
+		}
+		key := report.Repository + "/" + report.ID
+		if _, ok := ids[key]; ok {
+			return fmt.Errorf("report %s: duplicate id for repository %s: %w", report.ID, report.Repository, ErrBadConfig)
+		}
+		ids[key] = struct{}{}
+		if _, _, err := parseDestination(report.Destination); err != nil {
+			return fmt.Errorf("report %s: %w", report.ID, err)
+		}
+		if _, err := formatOf(report); err != nil {
+			return fmt.Errorf("report %s: %w", report.ID, err)
+		}
+	}
+	return nil
+}
+
+// parseDestination returns the bucket and the key prefix of a destination URI
+func parseDestination(destination string) (string, string, error) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("destination %q must be a storage URI: %w", destination, ErrBadConfig)
+	}
+	return u.Host, strings.Trim(u.Path, "/"), nil
+}
+
+// formatOf returns the data files format of report, CSV by default
+func formatOf(report config.InventoryReport) (string, error) {
+	switch {
+	case report.Format == "", strings.EqualFold(report.Format, FormatCSV):
+		return FormatCSV, nil
+	case strings.EqualFold(report.Format, FormatParquet):
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: %w", report.Format, ErrBadConfig)
+	}
+}
+
+// Generate generates the configured reports, returning the errors of the reports that failed
+func (s *Service) Generate(ctx context.Context) error {
+	var errs []error
+	now := time.Now().UTC()
+	for _, report := range s.cfg.Reports {
+		if _, err := s.GenerateReport(ctx, report, now); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.WithError(err).WithFields(logging.Fields{
+				"report":     report.ID,
+				"repository": report.Repository,
+				"branch":     report.Branch,
+			}).Error("Failed to generate inventory report")
+			errs = append(errs, fmt.Errorf("report %s of repository %s: %w", report.ID, report.Repository, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GenerateReport writes the data files of a report of the objects of the branch, then its manifest as of now.
+// Consumers only read data files listed by a manifest, so a report failing midway leaves no partial report.
+func (s *Service) GenerateReport(ctx context.Context, report config.InventoryReport, now time.Time) (*Manifest, error) {
+	format, err := formatOf(report)
+	if err != nil {
+		return nil, err
+	}
+	destinationBucket, destinationPrefix, err := parseDestination(report.Destination)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.catalog.WithBlockAdapterOverride(ctx, report.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	// files are written to <destination>/<repository>/<id>/, like S3 Inventory does for the source bucket
+	reportPath := path.Join(report.Repository, report.ID)
+	manifest := &Manifest{
+		SourceBucket:      report.Repository,
+		DestinationBucket: "arn:aws:s3:::" + destinationBucket,
+		Version:           ManifestVersion,
+		CreationTimestamp: strconv.FormatInt(now.UnixMilli(), 10),
+		FileFormat:        format,
+		FileSchema:        csvFileSchema,
+		Files:             []ManifestFile{},
+	}
+	if format == FormatParquet {
+		manifest.FileSchema = parquetFileSchema
+	}
+
+	var (
+		w     dataFileWriter
+		count int
+	)
+	flush := func() error {
+		data, err := w.Close()
+		if err != nil {
+			return err
+		}
+		identifier := path.Join(reportPath, "data", uuid.NewString()+w.Extension())
+		if err := s.put(ctx, report.Destination, identifier, data); err != nil {
+			return err
+		}
+		checksum := md5.Sum(data) //nolint:gosec
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Key:         path.Join(destinationPrefix, identifier),
+			Size:        int64(len(data)),
+			MD5Checksum: hex.EncodeToString(checksum[:]),
+		})
+		w = nil
+		count = 0
+		return nil
+	}
+
+	var after string
+	for {
+		entries, hasMore, err := s.catalog.ListEntries(ctx, report.Repository, report.Branch, "", after, "", catalog.ListEntriesLimitMax)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if w == nil {
+				if w, err = newDataFileWriter(format); err != nil {
+					return nil, err
+				}
+			}
+			err := w.Write(&Row{
+				Bucket:           report.Repository,
+				Key:              report.Branch + "/" + entry.Path,
+				Size:             entry.Size,
+				LastModifiedDate: entry.CreationDate,
+				ETag:             entry.Checksum,
+				StorageClass:     catalog.StorageClassOrDefault(entry.StorageClass),
+			})
+			if err != nil {
+				return nil, err
+			}
+			count++
+			if count >= s.cfg.MaxFileObjects {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	if w != nil {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	manifestPath := path.Join(reportPath, now.Format(manifestTimeLayout))
+	if err := s.put(ctx, report.Destination, path.Join(manifestPath, "manifest.json"), manifestData); err != nil {
+		return nil, err
+	}
+	manifestChecksum := md5.Sum(manifestData) //nolint:gosec
+	if err := s.put(ctx, report.Destination, path.Join(manifestPath, "manifest.checksum"), []byte(hex.EncodeToString(manifestChecksum[:]))); err != nil {
+		return nil, err
+	}
+
+	// the symlink.txt of the Hive-compatible layout lists the data files for Athena and Hive
+	var symlink strings.Builder
+	for _, file := range manifest.Files {
+		symlink.WriteString("s3://" + destinationBucket + "/" + file.Key + "\n")
+	}
+	hivePath := path.Join(reportPath, "hive", "dt="+now.Format(hiveTimeLayout), "symlink.txt")
+	if err := s.put(ctx, report.Destination, hivePath, []byte(symlink.String())); err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(logging.Fields{
+		"report":     report.ID,
+		"repository": report.Repository,
+		"branch":     report.Branch,
+		"files":      len(manifest.Files),
+	}).Info("Generated inventory report")
+	return manifest, nil
+}
+
+func (s *Service) put(ctx context.Context, destination, identifier string, data []byte) error {
+	return s.catalog.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: destination,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       identifier,
+	}, int64(len(data)), bytes.NewReader(data), block.PutOpts{})
+}
+
+// Row is an object listed by a report
+type Row struct {
+	Bucket           string
+	Key              string
+	Size             int64
+	LastModifiedDate time.Time
+	ETag             string
+	StorageClass     string
+}
+
+type dataFileWriter interface {
+	Write(row *Row) error
+	// Close returns the content of the data file
+	Close() ([]byte, error)
+	Extension() string
+}
+
+func newDataFileWriter(format string) (dataFileWriter, error) {
+	if format == FormatParquet {
+		return newParquetWriter()
+	}
+	return newCSVWriter(), nil
+}
+
+// csvWriter writes gzipped CSV data files, all fields quoted and keys URL-encoded like S3 Inventory does
+type csvWriter struct {
+	buf bytes.Buffer
+	gz  *gzip.Writer
+}
+
+func newCSVWriter() *csvWriter {
+	w := &csvWriter{}
+	w.gz = gzip.NewWriter(&w.buf)
+	return w
+}
+
+func (w *csvWriter) Write(row *Row) error {
+	fields := []string{
+		row.Bucket,
+		encodeKey(row.Key),
+		strconv.FormatInt(row.Size, 10),
+		row.LastModifiedDate.UTC().Format("2006-01-02T15:04:05.000Z"),
+		row.ETag,
+		row.StorageClass,
+	}
+	for i, field := range fields {
+		fields[i] = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+	}
+	_, err := w.gz.Write([]byte(strings.Join(fields, ",") + "\n"))
+	return err
+}
+
+func (w *csvWriter) Close() ([]byte, error) {
+	if err := w.gz.Close(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+func (w *csvWriter) Extension() string {
+	return ".csv.gz"
+}
+
+// encodeKey URL-encodes the segments of key, keeping its separators
+func encodeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.QueryEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+type parquetRow struct {
+	Bucket           string `parquet:"name=bucket, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Key              string `parquet:"name=key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Size             int64  `parquet:"name=size, type=INT64"`
+	LastModifiedDate int64  `parquet:"name=last_modified_date, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	ETag             string `parquet:"name=e_tag, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StorageClass     string `parquet:"name=storage_class, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+}
+
+type parquetWriter struct {
+	buf bytes.Buffer
+	pw  *writer.ParquetWriter
+}
+
+func newParquetWriter() (*parquetWriter, error) {
+	w := &parquetWriter{}
+	pw, err := writer.NewParquetWriterFromWriter(&w.buf, new(parquetRow), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	w.pw = pw
+	return w, nil
+}
+
+func (w *parquetWriter) Write(row *Row) error {
+	return w.pw.Write(parquetRow{
+		Bucket:           row.Bucket,
+		Key:              row.Key,
+		Size:             row.Size,
+		LastModifiedDate: row.LastModifiedDate.UnixMilli(),
+		ETag:             row.ETag,
+		StorageClass:     row.StorageClass,
+	})
+}
+
+func (w *parquetWriter) Close() ([]byte, error) {
+	if err := w.pw.WriteStop(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+func (w *parquetWriter) Extension() string {
+	return ".parquet"
+}
@@ -0,0 +1,153 @@
+package inventory
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+const (
+	repoName    = "repo"
+	destination = "mem://inventory/reports"
+)
+
+func setupCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	cfg, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       cfg,
+		KVStore:      kvtest.GetStore(ctx, t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	_, err = c.CreateRepository(ctx, repoName, "mem://repo", "main", false)
+	testutil.MustDo(t, "create repository", err)
+	return c
+}
+
+func readObject(t *testing.T, c *catalog.Catalog, identifier string) []byte {
+	t.Helper()
+	r, err := c.BlockAdapter.Get(context.Background(), block.ObjectPointer{
+		StorageNamespace: destination,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       identifier,
+	}, 0)
+	testutil.MustDo(t, "get "+identifier, err)
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(r)
+	testutil.MustDo(t, "read "+identifier, err)
+	return data
+}
+
+func TestNewService(t *testing.T) {
+	report := config.InventoryReport{ID: "daily", Repository: repoName, Branch: "main", Destination: destination}
+	tests := []struct {
+		name    string
+		reports []config.InventoryReport
+		wantErr bool
+	}{
+		{name: "valid", reports: []config.InventoryReport{report}},
+		{name: "missing_branch", reports: []config.InventoryReport{{ID: "daily", Repository: repoName, Destination: destination}}, wantErr: true},
+		{name: "duplicate_id", reports: []config.InventoryReport{report, report}, wantErr: true},
+		{name: "bad_destination", reports: []config.InventoryReport{{ID: "daily", Repository: repoName, Branch: "main", Destination: "reports"}}, wantErr: true},
+		{name: "bad_format", reports: []config.InventoryReport{{ID: "daily", Repository: repoName, Branch: "main", Destination: destination, Format: "ORC"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewService(config.Inventory{Interval: time.Hour, MaxFileObjects: 10, Reports: tt.reports}, nil, logging.ContextUnavailable())
+			if tt.wantErr {
+				require.True(t, errors.Is(err, ErrBadConfig), "expected ErrBadConfig, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_GenerateReport(t *testing.T) {
+	ctx := context.Background()
+	c := setupCatalog(t)
+	for _, p := range []string{"a", "b c", "d/e", "f"} {
+		err := c.CreateEntry(ctx, repoName, "main", catalog.DBEntry{Path: p, PhysicalAddress: "addr/" + p, Size: 3, Checksum: "cs"})
+		testutil.MustDo(t, "create entry "+p, err)
+	}
+
+	report := config.InventoryReport{ID: "daily", Repository: repoName, Branch: "main", Destination: destination}
+	svc, err := NewService(config.Inventory{Interval: time.Hour, MaxFileObjects: 3, Reports: []config.InventoryReport{report}}, c, logging.ContextUnavailable())
+	testutil.MustDo(t, "new service", err)
+	now := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	manifest, err := svc.GenerateReport(ctx, report, now)
+	testutil.MustDo(t, "generate report", err)
+
+	require.Equal(t, repoName, manifest.SourceBucket)
+	require.Equal(t, "arn:aws:s3:::inventory", manifest.DestinationBucket)
+	require.Equal(t, FormatCSV, manifest.FileFormat)
+	require.Equal(t, "1704164640000", manifest.CreationTimestamp)
+	require.Len(t, manifest.Files, 2, "4 objects with at most 3 objects per file")
+
+	var rows []string
+	for _, file := range manifest.Files {
+		require.True(t, strings.HasPrefix(file.Key, "reports/repo/daily/data/"), "data file key %s", file.Key)
+		data := readObject(t, c, strings.TrimPrefix(file.Key, "reports/"))
+		require.EqualValues(t, len(data), file.Size)
+		checksum := md5.Sum(data) //nolint:gosec
+		require.Equal(t, hex.EncodeToString(checksum[:]), file.MD5Checksum)
+		gz, err := gzip.NewReader(strings.NewReader(string(data)))
+		testutil.MustDo(t, "gzip reader", err)
+		content, err := io.ReadAll(gz)
+		testutil.MustDo(t, "read gzip", err)
+		rows = append(rows, strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")...)
+	}
+	require.Len(t, rows, 4)
+	require.True(t, strings.HasPrefix(rows[0], `"repo","main/a","3","`), "row %s", rows[0])
+	require.True(t, strings.HasPrefix(rows[1], `"repo","main/b+c","3","`), "row %s", rows[1])
+	require.True(t, strings.HasSuffix(rows[2], `,"cs","STANDARD"`), "row %s", rows[2])
+
+	manifestData := readObject(t, c, "repo/daily/2024-01-02T03-04Z/manifest.json")
+	var stored Manifest
+	testutil.MustDo(t, "unmarshal manifest", json.Unmarshal(manifestData, &stored))
+	require.Equal(t, *manifest, stored)
+	checksum := md5.Sum(manifestData) //nolint:gosec
+	require.Equal(t, hex.EncodeToString(checksum[:]), string(readObject(t, c, "repo/daily/2024-01-02T03-04Z/manifest.checksum")))
+	symlink := readObject(t, c, "repo/daily/hive/dt=2024-01-02-03-04/symlink.txt")
+	require.Equal(t, "s3://inventory/"+manifest.Files[0].Key+"\ns3://inventory/"+manifest.Files[1].Key+"\n", string(symlink))
+}
+
+func TestService_GenerateReportParquet(t *testing.T) {
+	ctx := context.Background()
+	c := setupCatalog(t)
+	err := c.CreateEntry(ctx, repoName, "main", catalog.DBEntry{Path: "a", PhysicalAddress: "addr/a", Size: 3, Checksum: "cs"})
+	testutil.MustDo(t, "create entry", err)
+
+	report := config.InventoryReport{ID: "daily", Repository: repoName, Branch: "main", Destination: destination, Format: "parquet"}
+	svc, err := NewService(config.Inventory{Interval: time.Hour, MaxFileObjects: 10, Reports: []config.InventoryReport{report}}, c, logging.ContextUnavailable())
+	testutil.MustDo(t, "new service", err)
+	manifest, err := svc.GenerateReport(ctx, report, time.Now())
+	testutil.MustDo(t, "generate report", err)
+	require.Equal(t, FormatParquet, manifest.FileFormat)
+	require.Len(t, manifest.Files, 1)
+	require.True(t, strings.HasSuffix(manifest.Files[0].Key, ".parquet"))
+	data := readObject(t, c, strings.TrimPrefix(manifest.Files[0].Key, "reports/"))
+	require.Equal(t, "PAR1", string(data[:4]))
+}
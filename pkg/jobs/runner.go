@@ -0,0 +1,452 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/lease"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	jobsPartition = "jobs"
+	runsPrefix    = "runs"
+	leasesPrefix  = "leases"
+	leaderKey     = "leader"
+	// leaseTTL is the time to live of the lease electing the instance running a job, renewed while the job runs
+	leaseTTL = 30 * time.Second
+	// leaderTTL is the time to live of the lease electing the instance scheduling jobs, renewed while it runs
+	leaderTTL = 30 * time.Second
+)
+
+var (
+	ErrNotFound    = errors.New("job not found")
+	ErrJobExists   = errors.New("job already registered")
+	ErrBadSchedule = errors.New("bad schedule")
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(jobsPartition, runsPrefix, (&RunData{}).ProtoReflect().Type())
+}
+
+// Func is the work of a job. It should return once ctx is canceled.
+type Func func(ctx context.Context) error
+
+// Job is a background job, run on its schedule by a single lakeFS instance at a time
+type Job struct {
+	// Name identifies the job, and is the key of its schedule in the configuration
+	Name string
+	// Schedule is an interval (e.g. "1h") or a cron expression (e.g. "0 3 * * *")
+	Schedule string
+	Fn       Func
+}
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run is a run of a job
+type Run struct {
+	ID     string
+	Job    string
+	Owner  string
+	Status RunStatus
+	Error  string
+	// Attempts is the number of times the job was tried during the run
+	Attempts  int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Status is the status of a registered job
+type Status struct {
+	Name     string
+	Schedule string
+	// NextRun is the next time the job is scheduled on this instance
+	NextRun time.Time
+	// LastRun is the latest run of the job by any instance, nil if it never ran
+	LastRun *Run
+}
+
+type registeredJob struct {
+	Job
+	scheduled *gocron.Job
+}
+
+// Runner runs background jobs on their schedules. Instances sharing a KV store elect a leader instance that runs the
+// scheduled jobs, and a single instance to run each run of a job. The runner retries failed runs and keeps the history
+// of the runs in the KV store.
+type Runner struct {
+	store     kv.Store
+	cfg       config.Jobs
+	owner     string
+	logger    logging.Logger
+	scheduler *gocron.Scheduler
+
+	mu      sync.Mutex
+	jobs    map[string]*registeredJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+
+	leaderMu  sync.Mutex
+	leader    *lease.Lease
+	leaderCtx context.Context
+}
+
+func NewRunner(store kv.Store, cfg config.Jobs, logger logging.Logger) *Runner {
+	owner := xid.New().String()
+	if hostname, err := os.Hostname(); err == nil {
+		owner = hostname + "-" + owner
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		store:     store,
+		cfg:       cfg,
+		owner:     owner,
+		logger:    logger,
+		scheduler: gocron.NewScheduler(time.UTC),
+		jobs:      make(map[string]*registeredJob),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Register schedules job, using the schedule of the configuration if set. Jobs run once the runner starts.
+func (r *Runner) Register(job Job) error {
+	if schedule, ok := r.cfg.Schedules[job.Name]; ok {
+		job.Schedule = schedule
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.jobs[job.Name]; ok {
+		return fmt.Errorf("%s: %w", job.Name, ErrJobExists)
+	}
+	var s *gocron.Scheduler
+	if interval, err := time.ParseDuration(job.Schedule); err == nil {
+		if interval <= 0 {
+			return fmt.Errorf("%s: interval %s must be positive: %w", job.Name, job.Schedule, ErrBadSchedule)
+		}
+		s = r.scheduler.Every(interval)
+	} else {
+		s = r.scheduler.Cron(job.Schedule)
+	}
+	name := job.Name
+	scheduled, err := s.Do(func() {
+		leader, err := r.isLeader()
+		if err != nil {
+			if r.ctx.Err() == nil {
+				r.logger.WithError(err).WithField("job", name).Error("Failed to elect the jobs leader")
+			}
+			return
+		}
+		if !leader {
+			r.logger.WithField("job", name).Debug("Job scheduled by the leader instance")
+			return
+		}
+		if _, err := r.RunJob(r.ctx, name); err != nil && r.ctx.Err() == nil {
+			r.logger.WithError(err).WithField("job", name).Error("Failed to run job")
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: schedule %s: %w: %s", job.Name, job.Schedule, ErrBadSchedule, err)
+	}
+	scheduled.SingletonMode()
+	r.jobs[job.Name] = &registeredJob{Job: job, scheduled: scheduled}
+	return nil
+}
+
+// Start starts running the registered jobs on their schedules
+func (r *Runner) Start() {
+	r.scheduler.StartAsync()
+}
+
+// Stop stops scheduling jobs, cancels the running jobs and waits for them to return
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	r.scheduler.Stop()
+	r.cancel()
+	r.wg.Wait()
+
+	r.leaderMu.Lock()
+	defer r.leaderMu.Unlock()
+	if r.leader != nil {
+		if err := r.leader.Release(context.Background()); err != nil && !errors.Is(err, lease.ErrLeaseLost) {
+			r.logger.WithError(err).Warn("Failed to release jobs leader lease")
+		}
+		r.leader = nil
+	}
+}
+
+// isLeader returns true if this instance is the leader running scheduled jobs, trying to become the leader if no
+// instance is. Only the leader runs scheduled jobs, so that each scheduled run happens once across all instances.
+func (r *Runner) isLeader() (bool, error) {
+	r.leaderMu.Lock()
+	defer r.leaderMu.Unlock()
+	if r.leader != nil && r.leaderCtx.Err() == nil {
+		return true, nil
+	}
+	r.leader = nil
+	leader, err := lease.Acquire(r.ctx, r.store, jobsPartition, []byte(leaderKey), r.owner, leaderTTL)
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	r.leader = leader
+	r.leaderCtx = leader.Keep(r.ctx)
+	r.logger.WithField("owner", r.owner).Info("Elected jobs leader")
+	return true, nil
+}
+
+// RunJob runs the job name now, unless another instance is running it, retrying it if it fails. It returns the run,
+// or nil if the job was not run. It runs the job on non-leader instances too.
+func (r *Runner) RunJob(ctx context.Context, name string) (*Run, error) {
+	r.mu.Lock()
+	job, ok := r.jobs[name]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, nil
+	}
+	r.wg.Add(1)
+	r.mu.Unlock()
+	defer r.wg.Done()
+
+	jobLease, err := lease.Acquire(ctx, r.store, jobsPartition, []byte(leasePath(name)), r.owner, leaseTTL)
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		r.logger.WithField("job", name).WithError(err).Debug("Job run by another instance")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := jobLease.Release(context.WithoutCancel(ctx)); err != nil {
+			r.logger.WithError(err).WithField("job", name).Warn("Failed to release job lease")
+		}
+	}()
+	ctx = jobLease.Keep(ctx)
+
+	// a run left running holds no lease, its instance stopped while running it
+	if err := r.failInterruptedRuns(ctx, name); err != nil {
+		return nil, err
+	}
+	run := &Run{
+		ID:        xid.New().String(),
+		Job:       name,
+		Owner:     r.owner,
+		Status:    RunStatusRunning,
+		StartTime: time.Now(),
+	}
+	if err := r.setRun(ctx, run); err != nil {
+		return nil, err
+	}
+	log := r.logger.WithFields(logging.Fields{"job": name, "run_id": run.ID})
+	log.Info("Job run started")
+
+	var runErr error
+	for {
+		run.Attempts++
+		runErr = job.Fn(ctx)
+		if runErr == nil || run.Attempts > r.cfg.Retries || ctx.Err() != nil {
+			break
+		}
+		log.WithError(runErr).WithField("attempt", run.Attempts).Warn("Job run failed, retrying")
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.cfg.RetryInterval):
+		}
+	}
+	if runErr == nil && ctx.Err() != nil {
+		runErr = context.Cause(ctx)
+	}
+	run.EndTime = time.Now()
+	if runErr != nil {
+		run.Status = RunStatusFailed
+		run.Error = runErr.Error()
+		log.WithError(runErr).Error("Job run failed")
+	} else {
+		run.Status = RunStatusCompleted
+		log.WithField("duration", run.EndTime.Sub(run.StartTime)).Info("Job run completed")
+	}
+	// record the end of the run even if it was canceled
+	ctx = context.WithoutCancel(ctx)
+	if err := r.setRun(ctx, run); err != nil {
+		return run, err
+	}
+	if err := r.trimRuns(ctx, name); err != nil {
+		log.WithError(err).Warn("Failed to delete old job runs")
+	}
+	return run, nil
+}
+
+// ListJobs returns the status of the registered jobs, ordered by name
+func (r *Runner) ListJobs(ctx context.Context) ([]*Status, error) {
+	r.mu.Lock()
+	jobs := make([]*registeredJob, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	r.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	statuses := make([]*Status, 0, len(jobs))
+	for _, job := range jobs {
+		runs, err := r.ListRuns(ctx, job.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+		status := &Status{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			NextRun:  job.scheduled.NextRun(),
+		}
+		if len(runs) > 0 {
+			status.LastRun = runs[0]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// ListRuns returns up to amount latest runs of the job name, newest first. All kept runs are returned if amount is
+// not positive.
+func (r *Runner) ListRuns(ctx context.Context, name string, amount int) ([]*Run, error) {
+	r.mu.Lock()
+	_, ok := r.jobs[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, r.store, (&RunData{}).ProtoReflect().Type(), jobsPartition, []byte(runsPath(name)), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var runs []*Run
+	for it.Next() {
+		runs = append(runs, runFromData(it.Entry().Value.(*RunData)))
+		if amount > 0 && len(runs) >= amount {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *Runner) failInterruptedRuns(ctx context.Context, name string) error {
+	runs, err := r.ListRuns(ctx, name, 1)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if run.Status != RunStatusRunning {
+			continue
+		}
+		run.Status = RunStatusFailed
+		run.Error = "interrupted"
+		run.EndTime = time.Now()
+		if err := r.setRun(ctx, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimRuns deletes the runs of the job name beyond the configured history, keeping all runs if it is not positive
+func (r *Runner) trimRuns(ctx context.Context, name string) error {
+	if r.cfg.History <= 0 {
+		return nil
+	}
+	it, err := kv.ScanPrefix(ctx, r.store, []byte(jobsPartition), []byte(runsPath(name)), nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	count := 0
+	for it.Next() {
+		count++
+		if count <= r.cfg.History {
+			continue
+		}
+		if err := r.store.Delete(ctx, []byte(jobsPartition), it.Entry().Key); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (r *Runner) setRun(ctx context.Context, run *Run) error {
+	return kv.SetMsg(ctx, r.store, jobsPartition, []byte(runPath(run)), runToData(run))
+}
+
+func leasePath(name string) string {
+	return kv.FormatPath(leasesPrefix, name)
+}
+
+func runsPath(name string) string {
+	return kv.FormatPath(runsPrefix, name) + kv.PathDelimiter
+}
+
+// runPath orders the runs of a job newest first
+func runPath(run *Run) string {
+	return runsPath(run.Job) + fmt.Sprintf("%019d_%s", math.MaxInt64-run.StartTime.UnixNano(), run.ID)
+}
+
+func runToData(run *Run) *RunData {
+	data := &RunData{
+		Id:        run.ID,
+		Job:       run.Job,
+		Owner:     run.Owner,
+		Status:    string(run.Status),
+		Error:     run.Error,
+		Attempts:  int32(run.Attempts),
+		StartTime: timestamppb.New(run.StartTime),
+	}
+	if !run.EndTime.IsZero() {
+		data.EndTime = timestamppb.New(run.EndTime)
+	}
+	return data
+}
+
+func runFromData(data *RunData) *Run {
+	run := &Run{
+		ID:        data.Id,
+		Job:       data.Job,
+		Owner:     data.Owner,
+		Status:    RunStatus(data.Status),
+		Error:     data.Error,
+		Attempts:  int(data.Attempts),
+		StartTime: data.StartTime.AsTime(),
+	}
+	if data.EndTime != nil {
+		run.EndTime = data.EndTime.AsTime()
+	}
+	return run
+}
@@ -0,0 +1,194 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/jobs"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/kv/lease"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+var errJob = errors.New("job failed")
+
+func newRunner(t *testing.T, cfg config.Jobs) *jobs.Runner {
+	t.Helper()
+	runner := jobs.NewRunner(kvtest.GetStore(context.Background(), t), cfg, logging.ContextUnavailable())
+	t.Cleanup(runner.Stop)
+	return runner
+}
+
+func TestRunner_RunJob(t *testing.T) {
+	ctx := context.Background()
+	runner := newRunner(t, config.Jobs{Retries: 2, History: 2})
+
+	calls := 0
+	require.NoError(t, runner.Register(jobs.Job{
+		Name:     "flaky",
+		Schedule: "1h",
+		Fn: func(context.Context) error {
+			calls++
+			if calls%2 == 1 {
+				return errJob
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, runner.Register(jobs.Job{
+		Name:     "failing",
+		Schedule: "0 3 * * *",
+		Fn:       func(context.Context) error { return errJob },
+	}))
+
+	run, err := runner.RunJob(ctx, "flaky")
+	require.NoError(t, err)
+	require.Equal(t, jobs.RunStatusCompleted, run.Status)
+	require.Equal(t, 2, run.Attempts)
+
+	run, err = runner.RunJob(ctx, "failing")
+	require.NoError(t, err)
+	require.Equal(t, jobs.RunStatusFailed, run.Status)
+	require.Equal(t, 3, run.Attempts)
+	require.Equal(t, errJob.Error(), run.Error)
+
+	// history keeps the latest runs, newest first
+	var ids []string
+	for i := 0; i < 3; i++ {
+		run, err := runner.RunJob(ctx, "flaky")
+		require.NoError(t, err)
+		ids = append(ids, run.ID)
+	}
+	runs, err := runner.ListRuns(ctx, "flaky", 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, ids[2], runs[0].ID)
+	require.Equal(t, ids[1], runs[1].ID)
+
+	statuses, err := runner.ListJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, "failing", statuses[0].Name)
+	require.Equal(t, jobs.RunStatusFailed, statuses[0].LastRun.Status)
+	require.Equal(t, "flaky", statuses[1].Name)
+	require.Equal(t, ids[2], statuses[1].LastRun.ID)
+
+	_, err = runner.RunJob(ctx, "missing")
+	require.ErrorIs(t, err, jobs.ErrNotFound)
+	_, err = runner.ListRuns(ctx, "missing", 0)
+	require.ErrorIs(t, err, jobs.ErrNotFound)
+}
+
+func TestRunner_Register(t *testing.T) {
+	runner := newRunner(t, config.Jobs{Schedules: map[string]string{"overridden": "@daily"}})
+	fn := func(context.Context) error { return nil }
+
+	require.NoError(t, runner.Register(jobs.Job{Name: "job", Schedule: "10m", Fn: fn}))
+	require.ErrorIs(t, runner.Register(jobs.Job{Name: "job", Schedule: "10m", Fn: fn}), jobs.ErrJobExists)
+	require.ErrorIs(t, runner.Register(jobs.Job{Name: "bad", Schedule: "every now and then", Fn: fn}), jobs.ErrBadSchedule)
+	require.ErrorIs(t, runner.Register(jobs.Job{Name: "negative", Schedule: "-1m", Fn: fn}), jobs.ErrBadSchedule)
+	require.NoError(t, runner.Register(jobs.Job{Name: "overridden", Schedule: "10m", Fn: fn}))
+
+	statuses, err := runner.ListJobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, "overridden", statuses[1].Name)
+	require.Equal(t, "@daily", statuses[1].Schedule)
+	require.Nil(t, statuses[1].LastRun)
+}
+
+func TestRunner_LeaderElection(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	runner := jobs.NewRunner(store, config.Jobs{}, logging.ContextUnavailable())
+	t.Cleanup(runner.Stop)
+	calls := 0
+	require.NoError(t, runner.Register(jobs.Job{
+		Name:     "job",
+		Schedule: "1h",
+		Fn: func(context.Context) error {
+			calls++
+			return nil
+		},
+	}))
+
+	// another instance is running the job
+	other, err := lease.Acquire(ctx, store, "jobs", []byte("leases/job"), "other", time.Minute)
+	require.NoError(t, err)
+	run, err := runner.RunJob(ctx, "job")
+	require.NoError(t, err)
+	require.Nil(t, run)
+	require.Zero(t, calls)
+
+	require.NoError(t, other.Release(ctx))
+	run, err = runner.RunJob(ctx, "job")
+	require.NoError(t, err)
+	require.Equal(t, jobs.RunStatusCompleted, run.Status)
+	require.Equal(t, 1, calls)
+}
+
+func TestRunner_Schedule(t *testing.T) {
+	runner := newRunner(t, config.Jobs{History: 10})
+	done := make(chan struct{}, 1)
+	require.NoError(t, runner.Register(jobs.Job{
+		Name:     "job",
+		Schedule: "1h",
+		Fn: func(context.Context) error {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}))
+	runner.Start()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not run on start")
+	}
+	require.Eventually(t, func() bool {
+		runs, err := runner.ListRuns(context.Background(), "job", 0)
+		return err == nil && len(runs) == 1 && runs[0].Status == jobs.RunStatusCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestRunner_ScheduleLeader(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	runner := jobs.NewRunner(store, config.Jobs{}, logging.ContextUnavailable())
+	t.Cleanup(runner.Stop)
+	done := make(chan struct{}, 1)
+	require.NoError(t, runner.Register(jobs.Job{
+		Name:     "job",
+		Schedule: "100ms",
+		Fn: func(context.Context) error {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}))
+
+	// another instance is the leader
+	other, err := lease.Acquire(ctx, store, "jobs", []byte("leader"), "other", time.Minute)
+	require.NoError(t, err)
+	runner.Start()
+	select {
+	case <-done:
+		t.Fatal("job scheduled on an instance that is not the leader")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	require.NoError(t, other.Release(ctx))
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not run once the instance became the leader")
+	}
+}
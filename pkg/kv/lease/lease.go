@@ -0,0 +1,171 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	ErrLeaseHeld = errors.New("lease held")
+	ErrLeaseLost = errors.New("lease lost")
+)
+
+// renewalsPerTTL is the number of times a kept lease is renewed during its time to live
+const renewalsPerTTL = 3
+
+// Lease is an exclusive lock on a key of a KV store, held for a bounded time. Leases coordinate work across lakeFS
+// instances sharing the same KV store: a lease held by one instance cannot be acquired by another until it is
+// released or expires.
+// Every acquisition of a key increases its fencing token. Holders call Check before each write made under the lease:
+// it fails once the stored token no longer matches, i.e. after another owner acquired the key.
+type Lease struct {
+	store     kv.Store
+	partition string
+	key       []byte
+	owner     string
+	token     int64
+	ttl       time.Duration
+
+	mu   sync.Mutex
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// Acquire acquires the lease on key of partition for owner, for ttl. It fails with ErrLeaseHeld if another owner
+// holds an unexpired lease on the key.
+func Acquire(ctx context.Context, store kv.Store, partition string, key []byte, owner string, ttl time.Duration) (*Lease, error) {
+	data := &LeaseData{}
+	predicate, err := kv.GetMsg(ctx, store, partition, key, data)
+	switch {
+	case errors.Is(err, kv.ErrNotFound):
+		predicate = nil
+	case err != nil:
+		return nil, err
+	case time.Now().Before(data.ExpiresAt.AsTime()):
+		return nil, fmt.Errorf("%w by %s until %s", ErrLeaseHeld, data.Owner, data.ExpiresAt.AsTime())
+	}
+
+	l := &Lease{
+		store:     store,
+		partition: partition,
+		key:       key,
+		owner:     owner,
+		token:     data.Token + 1,
+		ttl:       ttl,
+	}
+	err = kv.SetMsgIf(ctx, store, partition, key, l.data(time.Now().Add(ttl)), predicate)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		// acquired concurrently by another owner
+		return nil, fmt.Errorf("%w: %s", ErrLeaseHeld, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Token returns the fencing token of the lease
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Check verifies the lease is still held with its fencing token, returning ErrLeaseLost if it expired or was acquired
+// by another owner
+func (l *Lease) Check(ctx context.Context) error {
+	_, err := l.current(ctx)
+	return err
+}
+
+// Renew extends the lease by its time to live, returning ErrLeaseLost if it is no longer held
+func (l *Lease) Renew(ctx context.Context) error {
+	predicate, err := l.current(ctx)
+	if err != nil {
+		return err
+	}
+	return l.set(ctx, time.Now().Add(l.ttl), predicate)
+}
+
+// Keep renews the lease in the background until it is released. The returned context is canceled with cause
+// ErrLeaseLost if the lease cannot be renewed.
+func (l *Lease) Keep(ctx context.Context) context.Context {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	renewCtx, stop := context.WithCancel(ctx)
+	l.stop = stop
+	l.done = make(chan struct{})
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.ttl / renewalsPerTTL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Renew(renewCtx); err != nil {
+					if renewCtx.Err() == nil {
+						cancel(fmt.Errorf("%w: %s", ErrLeaseLost, err))
+					}
+					return
+				}
+			}
+		}
+	}()
+	return leaseCtx
+}
+
+// Release stops renewing the lease and releases it. The key keeps its fencing token for the next acquisition.
+func (l *Lease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.stop != nil {
+		l.stop()
+		<-l.done
+		l.stop = nil
+	}
+	l.mu.Unlock()
+
+	predicate, err := l.current(ctx)
+	if err != nil {
+		return err
+	}
+	return l.set(ctx, time.Now(), predicate)
+}
+
+// current returns the predicate of the lease data if the lease is still held
+func (l *Lease) current(ctx context.Context) (kv.Predicate, error) {
+	data := &LeaseData{}
+	predicate, err := kv.GetMsg(ctx, l.store, l.partition, l.key, data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, ErrLeaseLost
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data.Token != l.token || data.Owner != l.owner || !time.Now().Before(data.ExpiresAt.AsTime()) {
+		return nil, ErrLeaseLost
+	}
+	return predicate, nil
+}
+
+func (l *Lease) set(ctx context.Context, expiresAt time.Time, predicate kv.Predicate) error {
+	err := kv.SetMsgIf(ctx, l.store, l.partition, l.key, l.data(expiresAt), predicate)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return ErrLeaseLost
+	}
+	return err
+}
+
+func (l *Lease) data(expiresAt time.Time) *LeaseData {
+	return &LeaseData{
+		Owner:     l.owner,
+		Token:     l.token,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: kv/lease/lease.proto
+
+package lease
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// message data model for a lease held on a key
+type LeaseData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// token is the fencing token of the lease, increased by every acquisition
+	Token     int64                  `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *LeaseData) Reset() {
+	*x = LeaseData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kv_lease_lease_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaseData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseData) ProtoMessage() {}
+
+func (x *LeaseData) ProtoReflect() protoreflect.Message {
+	mi := &file_kv_lease_lease_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseData.ProtoReflect.Descriptor instead.
+func (*LeaseData) Descriptor() ([]byte, []int) {
+	return file_kv_lease_lease_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LeaseData) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *LeaseData) GetToken() int64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *LeaseData) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+var File_kv_lease_lease_proto protoreflect.FileDescriptor
+
+var file_kv_lease_lease_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x6b, 0x76, 0x2f, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2f, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1c, 0x69, 0x6f, 0x2e, 0x74, 0x72, 0x65, 0x65, 0x76,
+	0x65, 0x72, 0x73, 0x65, 0x2e, 0x6c, 0x61, 0x6b, 0x65, 0x66, 0x73, 0x2e, 0x6b, 0x76, 0x2e, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x72, 0x0a, 0x09, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x39,
+	0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x65, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x2f, 0x6c, 0x61, 0x6b, 0x65, 0x66, 0x73, 0x2f, 0x6b, 0x76, 0x2f, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_kv_lease_lease_proto_rawDescOnce sync.Once
+	file_kv_lease_lease_proto_rawDescData = file_kv_lease_lease_proto_rawDesc
+)
+
+func file_kv_lease_lease_proto_rawDescGZIP() []byte {
+	file_kv_lease_lease_proto_rawDescOnce.Do(func() {
+		file_kv_lease_lease_proto_rawDescData = protoimpl.X.CompressGZIP(file_kv_lease_lease_proto_rawDescData)
+	})
+	return file_kv_lease_lease_proto_rawDescData
+}
+
+var file_kv_lease_lease_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_kv_lease_lease_proto_goTypes = []interface{}{
+	(*LeaseData)(nil),             // 0: io.treeverse.lakefs.kv.lease.LeaseData
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_kv_lease_lease_proto_depIdxs = []int32{
+	1, // 0: io.treeverse.lakefs.kv.lease.LeaseData.expires_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_kv_lease_lease_proto_init() }
+func file_kv_lease_lease_proto_init() {
+	if File_kv_lease_lease_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_kv_lease_lease_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_kv_lease_lease_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_kv_lease_lease_proto_goTypes,
+		DependencyIndexes: file_kv_lease_lease_proto_depIdxs,
+		MessageInfos:      file_kv_lease_lease_proto_msgTypes,
+	}.Build()
+	File_kv_lease_lease_proto = out.File
+	file_kv_lease_lease_proto_rawDesc = nil
+	file_kv_lease_lease_proto_goTypes = nil
+	file_kv_lease_lease_proto_depIdxs = nil
+}
@@ -0,0 +1,114 @@
+package lease_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/kv/lease"
+)
+
+const partition = "leases"
+
+func TestAcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	key := []byte("gc")
+
+	first, err := lease.Acquire(ctx, store, partition, key, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, first.Check(ctx))
+
+	_, err = lease.Acquire(ctx, store, partition, key, "instance-2", time.Minute)
+	require.ErrorIs(t, err, lease.ErrLeaseHeld)
+
+	require.NoError(t, first.Renew(ctx))
+	require.NoError(t, first.Release(ctx))
+	require.ErrorIs(t, first.Check(ctx), lease.ErrLeaseLost)
+
+	second, err := lease.Acquire(ctx, store, partition, key, "instance-2", time.Minute)
+	require.NoError(t, err)
+	require.Greater(t, second.Token(), first.Token())
+}
+
+func TestExpiredLeaseIsFenced(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	key := []byte("import")
+
+	first, err := lease.Acquire(ctx, store, partition, key, "instance-1", 10*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	second, err := lease.Acquire(ctx, store, partition, key, "instance-2", time.Minute)
+	require.NoError(t, err)
+	require.Greater(t, second.Token(), first.Token())
+
+	// the first owner can no longer act under its lease, nor take it back
+	require.ErrorIs(t, first.Check(ctx), lease.ErrLeaseLost)
+	require.ErrorIs(t, first.Renew(ctx), lease.ErrLeaseLost)
+	require.ErrorIs(t, first.Release(ctx), lease.ErrLeaseLost)
+	require.NoError(t, second.Check(ctx))
+}
+
+func TestKeep(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	key := []byte("kept")
+
+	l, err := lease.Acquire(ctx, store, partition, key, "instance-1", 30*time.Millisecond)
+	require.NoError(t, err)
+	leaseCtx := l.Keep(ctx)
+
+	// renewals keep the lease past its time to live
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, leaseCtx.Err())
+	require.NoError(t, l.Check(ctx))
+	_, err = lease.Acquire(ctx, store, partition, key, "instance-2", time.Minute)
+	require.ErrorIs(t, err, lease.ErrLeaseHeld)
+
+	require.NoError(t, l.Release(ctx))
+	require.NoError(t, leaseCtx.Err())
+}
+
+func TestKeepCancelsOnLoss(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	key := []byte("lost")
+
+	l, err := lease.Acquire(ctx, store, partition, key, "instance-1", 30*time.Millisecond)
+	require.NoError(t, err)
+	// the lease expires and another owner takes over the key
+	time.Sleep(40 * time.Millisecond)
+	_, err = lease.Acquire(ctx, store, partition, key, "instance-2", time.Minute)
+	require.NoError(t, err)
+
+	leaseCtx := l.Keep(ctx)
+	select {
+	case <-leaseCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("lease context not canceled after losing the lease")
+	}
+	if cause := context.Cause(leaseCtx); !errors.Is(cause, lease.ErrLeaseLost) {
+		t.Fatalf("lease context cause %v, expected %v", cause, lease.ErrLeaseLost)
+	}
+}
+
+func TestCheckFencesStaleToken(t *testing.T) {
+	ctx := context.Background()
+	store := kvtest.GetStore(ctx, t)
+	key := []byte("fenced")
+
+	first, err := lease.Acquire(ctx, store, partition, key, "instance-1", 10*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// the same owner acquires the key again: writes under the first acquisition are still fenced by its token
+	second, err := lease.Acquire(ctx, store, partition, key, "instance-1", time.Minute)
+	require.NoError(t, err)
+	require.ErrorIs(t, first.Check(ctx), lease.ErrLeaseLost)
+	require.NoError(t, second.Check(ctx))
+}
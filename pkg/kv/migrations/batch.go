@@ -0,0 +1,136 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultBatchSize     = 100
+	DefaultBatchInterval = 100 * time.Millisecond
+)
+
+// BatchParams configures a migration that rewrites records online, while lakeFS instances keep serving requests
+type BatchParams struct {
+	// Size is the number of records rewritten in a batch
+	Size int
+	// Interval is the pause between batches, bounding the load the migration adds to the KV store
+	Interval time.Duration
+}
+
+func DefaultBatchParams() BatchParams {
+	return BatchParams{Size: DefaultBatchSize, Interval: DefaultBatchInterval}
+}
+
+// Estimate is the impact of a migration rewriting records in batches. Each record is rewritten by a single
+// conditional write, so a record is locked only while it is written, one record at a time, and no partition or
+// table is locked.
+type Estimate struct {
+	// Records is the number of records the migration scans
+	Records int
+	// Rewrites is the number of records the migration rewrites
+	Rewrites int
+	// Batches is the number of batches the rewrites are written in
+	Batches int
+	// Duration estimates the time the migration takes: scanning the records, writing the rewrites at the rate the
+	// records were scanned, and pausing between batches
+	Duration time.Duration
+}
+
+// rewriteFunc changes msg in place to its migrated form. It returns false if msg is already migrated.
+type rewriteFunc func(msg protoreflect.ProtoMessage) bool
+
+// rewriteRecords rewrites the records of msgType under prefix of partition, in batches of params.Size separated by
+// params.Interval. A record is written only if it did not change since it was read, otherwise it is read and
+// rewritten again, so changes written by running lakeFS instances are kept. Records already migrated are not
+// written, so an interrupted migration can run again. It returns the number of records rewritten.
+func rewriteRecords(ctx context.Context, store kv.Store, msgType protoreflect.MessageType, partition string, prefix []byte, params BatchParams, rewrite rewriteFunc) (int, error) {
+	it, err := kv.NewPrimaryIterator(ctx, store, msgType, partition, prefix, kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	rewrites := 0
+	for it.Next() {
+		entry := it.Entry()
+		if !rewrite(proto.Clone(entry.Value)) {
+			continue
+		}
+		if rewrites > 0 && params.Size > 0 && rewrites%params.Size == 0 {
+			select {
+			case <-ctx.Done():
+				return rewrites, ctx.Err()
+			case <-time.After(params.Interval):
+			}
+		}
+		rewritten, err := rewriteRecord(ctx, store, msgType, partition, entry.Key, rewrite)
+		if err != nil {
+			return rewrites, err
+		}
+		if rewritten {
+			rewrites++
+		}
+	}
+	return rewrites, it.Err()
+}
+
+// rewriteRecord reads the record at key and writes its rewrite, unless it is already migrated or was deleted
+func rewriteRecord(ctx context.Context, store kv.Store, msgType protoreflect.MessageType, partition string, key []byte, rewrite rewriteFunc) (bool, error) {
+	for {
+		msg := msgType.New().Interface()
+		predicate, err := kv.GetMsg(ctx, store, partition, key, msg)
+		if errors.Is(err, kv.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !rewrite(msg) {
+			return false, nil
+		}
+		err = kv.SetMsgIf(ctx, store, partition, key, msg, predicate)
+		if errors.Is(err, kv.ErrPredicateFailed) {
+			// changed by a running lakeFS instance since it was read
+			continue
+		}
+		return err == nil, err
+	}
+}
+
+// estimateRewrite scans the records rewriteRecords rewrites, without writing them, and estimates its impact
+func estimateRewrite(ctx context.Context, store kv.Store, msgType protoreflect.MessageType, partition string, prefix []byte, params BatchParams, rewrite rewriteFunc) (*Estimate, error) {
+	started := time.Now()
+	it, err := kv.NewPrimaryIterator(ctx, store, msgType, partition, prefix, kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	estimate := &Estimate{}
+	for it.Next() {
+		estimate.Records++
+		if rewrite(it.Entry().Value) {
+			estimate.Rewrites++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	scan := time.Since(started)
+
+	if estimate.Rewrites > 0 {
+		estimate.Batches = 1
+		if params.Size > 0 {
+			estimate.Batches = (estimate.Rewrites + params.Size - 1) / params.Size
+		}
+		estimate.Duration = scan + scan*time.Duration(estimate.Rewrites)/time.Duration(estimate.Records) +
+			params.Interval*time.Duration(estimate.Batches-1)
+	}
+	return estimate, nil
+}
@@ -9,45 +9,51 @@ import (
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/permissions"
 	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func MigrateImportPermissions(ctx context.Context, kvStore kv.Store, cfg *config.Config) error {
+const importAction = "fs:Import*"
+
+// MigrateImportPermissions grants fs:Import* to policies allowing import from storage. It rewrites policies online,
+// in batches configured by params.
+func MigrateImportPermissions(ctx context.Context, kvStore kv.Store, cfg *config.Config, params BatchParams) error {
 	// skip migrate for users with External authorizations
 	if !cfg.IsAuthUISimplified() {
 		fmt.Println("skipping ACL migration - external Authorization")
 		return updateKVSchemaVersion(ctx, kvStore, kv.ACLImportMigrateVersion)
 	}
 
-	const action = "fs:Import*"
-	it, err := kv.NewPrimaryIterator(ctx, kvStore, (&model.PolicyData{}).ProtoReflect().Type(), model.PartitionKey, model.PolicyPath(""), kv.IteratorOptionsFrom([]byte("")))
+	_, err := rewriteRecords(ctx, kvStore, (&model.PolicyData{}).ProtoReflect().Type(), model.PartitionKey, model.PolicyPath(""), params, rewriteImportPermissions)
 	if err != nil {
 		return err
 	}
-	defer it.Close()
+	return updateKVSchemaVersion(ctx, kvStore, kv.ACLImportMigrateVersion)
+}
 
-	for it.Next() {
-		update := false
-		entry := it.Entry()
-		policy := entry.Value.(*model.PolicyData)
-		for _, statement := range policy.Statements {
-			if slices.Contains(statement.Action, action) { // Avoid duplication
-				continue
-			}
-			idx := slices.Index(statement.Action, permissions.ImportFromStorageAction)
-			if idx >= 0 {
-				statement.Action[idx] = action
-				update = true
-			}
-		}
+// EstimateImportPermissions estimates the impact of MigrateImportPermissions with params
+func EstimateImportPermissions(ctx context.Context, kvStore kv.Store, cfg *config.Config, params BatchParams) (*Estimate, error) {
+	if !cfg.IsAuthUISimplified() {
+		return &Estimate{}, nil
+	}
+	return estimateRewrite(ctx, kvStore, (&model.PolicyData{}).ProtoReflect().Type(), model.PartitionKey, model.PolicyPath(""), params, rewriteImportPermissions)
+}
 
-		if update {
-			policy.CreatedAt = timestamppb.Now()
-			if err = kv.SetMsg(ctx, kvStore, model.PartitionKey, entry.Key, policy); err != nil {
-				return err
-			}
+func rewriteImportPermissions(msg protoreflect.ProtoMessage) bool {
+	update := false
+	policy := msg.(*model.PolicyData)
+	for _, statement := range policy.Statements {
+		if slices.Contains(statement.Action, importAction) { // Avoid duplication
+			continue
+		}
+		idx := slices.Index(statement.Action, permissions.ImportFromStorageAction)
+		if idx >= 0 {
+			statement.Action[idx] = importAction
+			update = true
 		}
 	}
-
-	return updateKVSchemaVersion(ctx, kvStore, kv.ACLImportMigrateVersion)
+	if update {
+		policy.CreatedAt = timestamppb.Now()
+	}
+	return update
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth/setup"
 	authtestutil "github.com/treeverse/lakefs/pkg/auth/testutil"
 	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/migrations"
 	"github.com/treeverse/lakefs/pkg/permissions"
 	"github.com/treeverse/lakefs/pkg/testutil"
@@ -338,7 +339,7 @@ func TestMigrateImportPermissions(t *testing.T) {
 			}
 			// Run migrate
 			cfg.Auth.UIConfig.RBAC = tt.uiAuthType
-			testutil.MustDo(t, "migrate", migrations.MigrateImportPermissions(ctx, store, &cfg))
+			testutil.MustDo(t, "migrate", migrations.MigrateImportPermissions(ctx, store, &cfg, migrations.BatchParams{Size: 1}))
 
 			// Verify
 			verifyMigration(t, ctx, authService, tt.policies, cfg)
@@ -346,6 +347,69 @@ func TestMigrateImportPermissions(t *testing.T) {
 	}
 }
 
+// concurrentStore changes the first policy the migration rewrites just before it is written, as a running lakeFS
+// instance would
+type concurrentStore struct {
+	kv.Store
+	changed bool
+}
+
+func (s *concurrentStore) SetIf(ctx context.Context, partitionKey, key, value []byte, valuePredicate kv.Predicate) error {
+	if !s.changed {
+		s.changed = true
+		policy := &model.PolicyData{}
+		if _, err := kv.GetMsg(ctx, s.Store, string(partitionKey), key, policy); err != nil {
+			return err
+		}
+		policy.Statements[0].Resource = "changed"
+		if err := kv.SetMsg(ctx, s.Store, string(partitionKey), key, policy); err != nil {
+			return err
+		}
+	}
+	return s.Store.SetIf(ctx, partitionKey, key, value, valuePredicate)
+}
+
+func TestMigrateImportPermissions_Online(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Config{}
+	cfg.Auth.UIConfig.RBAC = config.AuthRBACSimplified
+	authService, store := authtestutil.SetupService(t, ctx, []byte("some secret"))
+	actions := []string{permissions.CreateBranchAction}
+	for i := 0; i < 6; i++ {
+		if i > 0 {
+			actions = []string{permissions.ImportFromStorageAction}
+		}
+		policy := model.Policy{
+			CreatedAt:   time.Now().Add(-time.Hour).UTC(),
+			DisplayName: fmt.Sprintf("policy%d", i),
+			Statement:   []model.Statement{{Effect: "allow", Action: actions, Resource: createARN("import")}},
+		}
+		testutil.MustDo(t, "create Policy", authService.WritePolicy(ctx, &policy, false))
+	}
+	params := migrations.BatchParams{Size: 2, Interval: time.Millisecond}
+
+	estimate, err := migrations.EstimateImportPermissions(ctx, store, &cfg, params)
+	testutil.MustDo(t, "estimate", err)
+	require.Equal(t, 6, estimate.Records)
+	require.Equal(t, 5, estimate.Rewrites)
+	require.Equal(t, 3, estimate.Batches)
+
+	testutil.MustDo(t, "migrate", migrations.MigrateImportPermissions(ctx, &concurrentStore{Store: store}, &cfg, params))
+	for i := 1; i < 6; i++ {
+		policy, err := authService.GetPolicy(ctx, fmt.Sprintf("policy%d", i))
+		testutil.MustDo(t, "get policy", err)
+		require.Equal(t, []string{"fs:Import*"}, policy.Statement[0].Action)
+		if i == 1 {
+			// the change written while the policy was migrated is kept
+			require.Equal(t, "changed", policy.Statement[0].Resource)
+		}
+	}
+
+	estimate, err = migrations.EstimateImportPermissions(ctx, store, &cfg, params)
+	testutil.MustDo(t, "estimate", err)
+	require.Equal(t, 0, estimate.Rewrites)
+}
+
 func createARN(name string) string {
 	return fmt.Sprintf("arn:%s:this:is:an:arn", name)
 }
@@ -338,6 +338,15 @@ func (s *Store) Scan(ctx context.Context, partitionKey []byte, options kv.ScanOp
 	return it, nil
 }
 
+// PoolSaturation returns the fraction of the connections of the pool acquired by queries
+func (s *Store) PoolSaturation() float64 {
+	stat := s.Pool.Stat()
+	if stat.MaxConns() == 0 {
+		return 0
+	}
+	return float64(stat.AcquiredConns()) / float64(stat.MaxConns())
+}
+
 func (s *Store) Close() {
 	if s.collector != nil {
 		prometheus.Unregister(s.collector)
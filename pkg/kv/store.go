@@ -109,6 +109,29 @@ type Store interface {
 	Close()
 }
 
+// PoolStats is implemented by stores that hold a pool of connections to their database
+type PoolStats interface {
+	// PoolSaturation returns the fraction of the connections of the pool in use, between 0 and 1
+	PoolSaturation() float64
+}
+
+// GetPoolStats returns the pool stats of store, looking through the wrappers of this package.
+// It returns false if the store does not hold a pool of connections.
+func GetPoolStats(store Store) (PoolStats, bool) {
+	for {
+		switch s := store.(type) {
+		case PoolStats:
+			return s, true
+		case *StoreMetricsWrapper:
+			store = s.Store
+		case *StoreLimiter:
+			store = s.Store
+		default:
+			return nil, false
+		}
+	}
+}
+
 // EntriesIterator used to enumerate over Scan results
 type EntriesIterator interface {
 	// Next should be called first before access Entry.
@@ -19,6 +19,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/jobs"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
 	"github.com/treeverse/lakefs/pkg/kv/kvtest"
@@ -83,7 +84,7 @@ func TestLocalLoad(t *testing.T) {
 		_ = c.Close()
 	})
 	auditChecker := version.NewDefaultAuditChecker(conf.Security.AuditCheckURL, "", nil)
-	handler := api.Serve(conf, c, authenticator, authService, blockAdapter, meta, migrator, &stats.NullCollector{}, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, nil, stats.DefaultUsageReporter)
+	handler := api.Serve(conf, c, authenticator, authService, nil, nil, blockAdapter, meta, migrator, &stats.NullCollector{}, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, nil, stats.DefaultUsageReporter, jobs.NewRunner(kvStore, conf.Jobs, logging.ContextUnavailable()), nil)
 
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
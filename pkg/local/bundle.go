@@ -0,0 +1,96 @@
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+// BundleVersion is the current version of the bundle format
+const BundleVersion = 1
+
+// Bundle pins the state of a synced directory, so that "lakectl local clone --from-bundle" reproduces it elsewhere
+type Bundle struct {
+	Version int `json:"version"`
+	// Source is the lakeFS path URI the directory syncs with
+	Source string `json:"source"`
+	// Commit is the commit ID the directory is synced to
+	Commit string `json:"commit"`
+	// GitCommit is the commit of the git repository containing the directory, if any
+	GitCommit string `json:"git_commit,omitempty"`
+	// RunSpecSHA256 is the SHA-256 digest of the spec of the run that used the directory, if any
+	RunSpecSHA256 string `json:"run_spec_sha256,omitempty"`
+	// Objects is the number of objects of Source at Commit, and ObjectsSHA256 the digest of their paths and checksums
+	Objects       int    `json:"objects"`
+	ObjectsSHA256 string `json:"objects_sha256"`
+}
+
+// ReadBundle reads the bundle at path
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBundle, err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBundleVersion, bundle.Version)
+	}
+	if bundle.Source == "" || bundle.Commit == "" || bundle.ObjectsSHA256 == "" {
+		return nil, fmt.Errorf("%w: missing source, commit or objects digest", ErrInvalidBundle)
+	}
+	return &bundle, nil
+}
+
+// WriteBundle writes bundle to w as indented JSON
+func WriteBundle(w io.Writer, bundle *Bundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// DigestObjects returns the number of objects and the SHA-256 digest of their paths and checksums, in the order they
+// are listed
+func DigestObjects(objects <-chan apigen.ObjectStats) (int, string) {
+	h := sha256.New()
+	count := 0
+	for o := range objects {
+		_, _ = fmt.Fprintf(h, "%s\t%s\n", o.Path, o.Checksum)
+		count++
+	}
+	return count, hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyObjects returns ErrBundleMismatch unless objects are the objects pinned by bundle
+func (b *Bundle) VerifyObjects(objects <-chan apigen.ObjectStats) error {
+	count, digest := DigestObjects(objects)
+	if count != b.Objects || digest != b.ObjectsSHA256 {
+		return fmt.Errorf("%w: %d objects with digest %s, bundle has %d objects with digest %s",
+			ErrBundleMismatch, count, digest, b.Objects, b.ObjectsSHA256)
+	}
+	return nil
+}
+
+// FileSHA256 returns the SHA-256 digest of the content of the file at path
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,72 @@
+package local_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/local"
+)
+
+func objectsChan(objects ...apigen.ObjectStats) <-chan apigen.ObjectStats {
+	ch := make(chan apigen.ObjectStats, len(objects))
+	for _, o := range objects {
+		ch <- o
+	}
+	close(ch)
+	return ch
+}
+
+func TestBundle(t *testing.T) {
+	objects := []apigen.ObjectStats{
+		{Path: "a.txt", Checksum: "acbd18db4cc2f85cedef654fccc4a4d8"},
+		{Path: "sub/b.txt", Checksum: "37b51d194a7513e45b56f6524f2d51f2"},
+	}
+	count, digest := local.DigestObjects(objectsChan(objects...))
+	require.Equal(t, 2, count)
+	bundle := &local.Bundle{
+		Version:       local.BundleVersion,
+		Source:        "lakefs://repo/main/data/",
+		Commit:        "c0ffee",
+		GitCommit:     "abc1234",
+		Objects:       count,
+		ObjectsSHA256: digest,
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	f, err := os.Create(bundlePath)
+	require.NoError(t, err)
+	require.NoError(t, local.WriteBundle(f, bundle))
+	require.NoError(t, f.Close())
+	read, err := local.ReadBundle(bundlePath)
+	require.NoError(t, err)
+	require.Equal(t, bundle, read)
+
+	require.NoError(t, read.VerifyObjects(objectsChan(objects...)))
+	changed := []apigen.ObjectStats{objects[0], {Path: "sub/b.txt", Checksum: "00000000000000000000000000000000"}}
+	require.ErrorIs(t, read.VerifyObjects(objectsChan(changed...)), local.ErrBundleMismatch)
+	require.ErrorIs(t, read.VerifyObjects(objectsChan(objects[0])), local.ErrBundleMismatch)
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{name: "not_json", content: "bundle", err: local.ErrInvalidBundle},
+		{name: "unsupported_version", content: `{"version": 99, "source": "lakefs://repo/main/", "commit": "c", "objects_sha256": "d"}`, err: local.ErrUnsupportedBundleVersion},
+		{name: "missing_commit", content: `{"version": 1, "source": "lakefs://repo/main/", "objects_sha256": "d"}`, err: local.ErrInvalidBundle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(dir, tt.name+".json")
+			require.NoError(t, os.WriteFile(p, []byte(tt.content), 0o644))
+			_, err := local.ReadBundle(p)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -2,15 +2,19 @@ package local
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
 	"fmt"
 	"io/fs"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/uri"
+	"golang.org/x/sync/errgroup"
 )
 
 type ChangeSource int
@@ -188,13 +192,71 @@ func Undo(c Changes) Changes {
 // is an immutable set so any changes found resulted from changes in the local directory
 // left is an object channel which contains results from a remote source. rightPath is the local directory to diff with
 func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string) (Changes, error) {
+	return diffLocal(left, rightPath, modifiedBySizeAndMtime, 1)
+}
+
+// DiffLocalWithHeadConcurrently Checks changes between a local directory and a remote path like DiffLocalWithHead,
+// reading up to parallelism local directories concurrently.
+func DiffLocalWithHeadConcurrently(left <-chan apigen.ObjectStats, rightPath string, parallelism int) (Changes, error) {
+	return diffLocal(left, rightPath, modifiedBySizeAndMtime, parallelism)
+}
+
+// DiffLocalWithChecksum Checks changes between a local directory and a remote path like DiffLocalWithHead, comparing the
+// content checksum of files with the same size instead of their mtime. Objects whose checksum is not an MD5 digest, such
+// as objects uploaded in multiple parts, are compared by mtime. Up to parallelism local directories are read, and
+// checksums of up to parallelism files are computed, concurrently.
+func DiffLocalWithChecksum(left <-chan apigen.ObjectStats, rightPath string, parallelism int) (Changes, error) {
+	return diffLocal(left, rightPath, modifiedByChecksum, parallelism)
+}
+
+// modifiedFunc reports whether the local file at path differs from the remote object with the same path
+type modifiedFunc func(path string, info fs.FileInfo, remote apigen.ObjectStats) (bool, error)
+
+func modifiedBySizeAndMtime(_ string, info fs.FileInfo, remote apigen.ObjectStats) (bool, error) {
+	remoteMtime, err := getMtimeFromStats(remote)
+	if err != nil {
+		return false, err
+	}
+	return info.Size() != swag.Int64Value(remote.SizeBytes) || info.ModTime().Unix() != remoteMtime, nil
+}
+
+func modifiedByChecksum(path string, info fs.FileInfo, remote apigen.ObjectStats) (bool, error) {
+	if info.Size() != swag.Int64Value(remote.SizeBytes) {
+		return true, nil
+	}
+	if !isMD5Checksum(remote.Checksum) {
+		return modifiedBySizeAndMtime(path, info, remote)
+	}
+	checksum, err := fileMD5(path)
+	if err != nil {
+		return false, err
+	}
+	return checksum != strings.ToLower(remote.Checksum), nil
+}
+
+func isMD5Checksum(checksum string) bool {
+	if len(checksum) != hex.EncodedLen(md5.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(checksum)
+	return err == nil
+}
+
+// diffLocal compares the files of rightPath with the remote objects of left. The directories of rightPath are read,
+// and files and objects of the same path are compared by modified, up to parallelism of them at once.
+func diffLocal(left <-chan apigen.ObjectStats, rightPath string, modified modifiedFunc, parallelism int) (Changes, error) {
 	// left should be the base commit
 	changes := make([]*Change, 0)
 	var (
 		currentRemoteFile apigen.ObjectStats
 		hasMore           bool
+		compare           errgroup.Group
+		mu                sync.Mutex
+		// unchanged are the changes of files found by their comparison to be the same as the remote object
+		unchanged = make(map[*Change]struct{})
 	)
-	err := filepath.Walk(rightPath, func(path string, info fs.FileInfo, err error) error {
+	compare.SetLimit(parallelism)
+	err := walkConcurrently(rightPath, parallelism, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -205,8 +267,6 @@ func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string) (Change
 		localPath = strings.TrimPrefix(localPath, string(filepath.Separator))
 		localPath = filepath.ToSlash(localPath) // normalize to use "/" always
 
-		localBytes := info.Size()
-		localMtime := info.ModTime().Unix()
 		for {
 			if currentRemoteFile.Path == "" {
 				if currentRemoteFile, hasMore = <-left; !hasMore {
@@ -220,15 +280,23 @@ func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string) (Change
 				changes = append(changes, &Change{ChangeSourceLocal, currentRemoteFile.Path, ChangeTypeRemoved})
 				currentRemoteFile.Path = ""
 			case currentRemoteFile.Path == localPath:
-				remoteMtime, err := getMtimeFromStats(currentRemoteFile)
-				if err != nil {
-					return err
-				}
-				if localBytes != swag.Int64Value(currentRemoteFile.SizeBytes) || localMtime != remoteMtime {
-					// we made a change!
-					changes = append(changes, &Change{ChangeSourceLocal, localPath, ChangeTypeModified})
-				}
+				// the change keeps its place in the diff until the comparison finds no change
+				change := &Change{ChangeSourceLocal, localPath, ChangeTypeModified}
+				changes = append(changes, change)
+				remoteFile := currentRemoteFile
 				currentRemoteFile.Path = ""
+				compare.Go(func() error {
+					isModified, err := modified(path, info, remoteFile)
+					if err != nil {
+						return err
+					}
+					if !isModified {
+						mu.Lock()
+						unchanged[change] = struct{}{}
+						mu.Unlock()
+					}
+					return nil
+				})
 				return nil
 			default: // currentRemoteFile.Path > localPath  - we added a new file locally
 				changes = append(changes, &Change{ChangeSourceLocal, localPath, ChangeTypeAdded})
@@ -237,9 +305,21 @@ func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string) (Change
 		}
 		return nil
 	})
+	if compareErr := compare.Wait(); err == nil {
+		err = compareErr
+	}
 	if err != nil {
 		return nil, err
 	}
+	if len(unchanged) > 0 {
+		modifiedChanges := changes[:0]
+		for _, change := range changes {
+			if _, ok := unchanged[change]; !ok {
+				modifiedChanges = append(modifiedChanges, change)
+			}
+		}
+		changes = modifiedChanges
+	}
 
 	// remaining remote files
 	if currentRemoteFile.Path != "" {
@@ -298,7 +378,7 @@ func ListRemote(ctx context.Context, client apigen.ClientWithResponsesInterface,
 
 func diffShouldIgnore(name string) bool {
 	switch name {
-	case IndexFileName, ".DS_Store":
+	case IndexFileName, LockFileName, ".DS_Store":
 		return true
 	default:
 		return false
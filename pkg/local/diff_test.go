@@ -132,6 +132,66 @@ func TestDiffLocal(t *testing.T) {
 				require.Equal(t, c.Path, tt.Expected[i].Path, "wrong path")
 				require.Equal(t, c.Type, tt.Expected[i].Type, "wrong type")
 			}
+
+			// reading directories concurrently finds the same changes
+			lc = make(chan apigen.ObjectStats, len(left))
+			makeChan(lc, left)
+			concurrentChanges, err := local.DiffLocalWithHeadConcurrently(lc, tt.LocalPath, 4)
+			require.NoError(t, err)
+			require.Equal(t, changes, concurrentChanges)
+		})
+	}
+}
+
+func TestDiffLocalWithChecksum(t *testing.T) {
+	const localPath = "testdata/localdiff/t1"
+	cases := []struct {
+		Name       string
+		RemoteList []apigen.ObjectStats
+		Expected   []*local.Change
+	}{
+		{
+			Name: "same_content_different_mtime",
+			RemoteList: []apigen.ObjectStats{
+				{Path: "sub/f.txt", SizeBytes: swag.Int64(3), Mtime: 1690957665, Checksum: "acbd18db4cc2f85cedef654fccc4a4d8"},
+				{Path: "sub/folder/f.txt", SizeBytes: swag.Int64(6), Mtime: 1690957665, Checksum: "A7B4042FAA80DD6F8916917061C85885"},
+			},
+			Expected: []*local.Change{},
+		},
+		{
+			Name: "different_content_same_mtime",
+			RemoteList: []apigen.ObjectStats{
+				{Path: "sub/f.txt", SizeBytes: swag.Int64(3), Mtime: diffTestCorrectTime, Checksum: "00000000000000000000000000000000"},
+				{Path: "sub/folder/f.txt", SizeBytes: swag.Int64(6), Mtime: diffTestCorrectTime, Checksum: "a7b4042faa80dd6f8916917061c85885"},
+			},
+			Expected: []*local.Change{
+				{Path: "sub/f.txt", Type: local.ChangeTypeModified},
+			},
+		},
+		{
+			Name: "multipart_checksum_compared_by_mtime",
+			RemoteList: []apigen.ObjectStats{
+				{Path: "sub/f.txt", SizeBytes: swag.Int64(3), Mtime: diffTestCorrectTime, Checksum: "0123456789abcdef0123456789abcdef-2"},
+				{Path: "sub/folder/f.txt", SizeBytes: swag.Int64(6), Mtime: 1690957665, Checksum: "0123456789abcdef0123456789abcdef-2"},
+			},
+			Expected: []*local.Change{
+				{Path: "sub/folder/f.txt", Type: local.ChangeTypeModified},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			fixTime(t, localPath)
+			lc := make(chan apigen.ObjectStats, len(tt.RemoteList))
+			makeChan(lc, tt.RemoteList)
+			changes, err := local.DiffLocalWithChecksum(lc, localPath, 2)
+			require.NoError(t, err)
+			require.Len(t, changes, len(tt.Expected))
+			for i, c := range changes {
+				require.Equal(t, tt.Expected[i].Path, c.Path, "wrong path")
+				require.Equal(t, tt.Expected[i].Type, c.Type, "wrong type")
+			}
 		})
 	}
 }
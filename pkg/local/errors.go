@@ -6,4 +6,12 @@ var (
 	ErrConflict        = errors.New("conflict")
 	ErrDownloadingFile = errors.New("error downloading file")
 	ErrRemoteFailure   = errors.New("remote failure")
+
+	ErrLocked                  = errors.New("directory is locked by another operation")
+	ErrInvalidIndex            = errors.New("invalid index")
+	ErrUnsupportedIndexVersion = errors.New("unsupported index version")
+
+	ErrInvalidBundle            = errors.New("invalid bundle")
+	ErrUnsupportedBundleVersion = errors.New("unsupported bundle version")
+	ErrBundleMismatch           = errors.New("objects do not match bundle")
 )
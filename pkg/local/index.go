@@ -17,12 +17,24 @@ const (
 	IndexFileName = ".lakefs_ref.yaml"
 	IgnoreMarker  = "ignored by lakectl local"
 	IndexFileMode = 0o644
+
+	// IndexVersion is the current version of the index file format. Index files written by older versions are
+	// migrated when read.
+	IndexVersion = 2
+	// indexVersionInitial is the version of index files written before versioning was introduced
+	indexVersionInitial = 1
 )
 
+// indexMigrations maps an index version to the migration which upgrades it to the following version
+var indexMigrations = map[int]func(idx *Index) error{
+	1: migrateIndexV1ToV2,
+}
+
 // Index defines the structure of the lakefs local reference file
 // consisting of the information linking local directory with lakefs path
 type Index struct {
 	root            string `yaml:"-"`
+	Version         int    `yaml:"version"`
 	PathURI         string `yaml:"src"`
 	AtHead          string `yaml:"at_head"`
 	ActiveOperation string `yaml:"active_operation"`
@@ -38,16 +50,22 @@ func (l *Index) GetCurrentURI() (*uri.URI, error) {
 
 func WriteIndex(path string, remote *uri.URI, atHead string, operation string) (*Index, error) {
 	idx := &Index{
+		root:            path,
+		Version:         IndexVersion,
 		PathURI:         remote.String(),
 		AtHead:          atHead,
 		ActiveOperation: operation,
 	}
+	return idx, writeIndexFile(idx)
+}
+
+func writeIndexFile(idx *Index) error {
 	data, err := yaml.Marshal(idx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	idxPath := filepath.Join(path, IndexFileName)
-	return idx, os.WriteFile(idxPath, data, IndexFileMode)
+	idxPath := filepath.Join(idx.root, IndexFileName)
+	return os.WriteFile(idxPath, data, IndexFileMode)
 }
 
 func IndexExists(baseAbs string) (bool, error) {
@@ -80,11 +98,71 @@ func ReadIndex(path string) (*Index, error) {
 	}
 	err = yaml.Unmarshal(data, idx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", idxPath, err)
+	}
+	migrated, err := migrateIndex(idx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", idxPath, err)
+	}
+	if migrated {
+		if err := writeIndexFile(idx); err != nil {
+			return nil, fmt.Errorf("write migrated index %s: %w", idxPath, err)
+		}
 	}
 	return idx, nil
 }
 
+// migrateIndex upgrades idx to IndexVersion, reporting whether any migration was applied
+func migrateIndex(idx *Index) (bool, error) {
+	if idx.Version == 0 {
+		idx.Version = indexVersionInitial
+	}
+	if idx.Version > IndexVersion {
+		return false, fmt.Errorf("index version %d, supported up to %d (upgrade lakectl): %w", idx.Version, IndexVersion, ErrUnsupportedIndexVersion)
+	}
+	migrated := false
+	for idx.Version < IndexVersion {
+		migrate, ok := indexMigrations[idx.Version]
+		if !ok {
+			return false, fmt.Errorf("no migration from index version %d: %w", idx.Version, ErrUnsupportedIndexVersion)
+		}
+		if err := migrate(idx); err != nil {
+			return false, fmt.Errorf("migrate index from version %d: %w", idx.Version, err)
+		}
+		idx.Version++
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// migrateIndexV1ToV2 stores the source URI in its canonical form. Version 1 kept the URI as given by the user.
+func migrateIndexV1ToV2(idx *Index) error {
+	u, err := uri.Parse(idx.PathURI)
+	if err != nil {
+		return fmt.Errorf("src: %w", err)
+	}
+	idx.PathURI = u.String()
+	return nil
+}
+
+// Validate checks that the index holds a usable reference to a lakeFS path
+func (l *Index) Validate() error {
+	if l.Version != IndexVersion {
+		return fmt.Errorf("version %d, expected %d: %w", l.Version, IndexVersion, ErrInvalidIndex)
+	}
+	u, err := l.GetCurrentURI()
+	if err != nil {
+		return fmt.Errorf("src: %w: %s", ErrInvalidIndex, err)
+	}
+	if err := u.ValidateFullyQualified(); err != nil {
+		return fmt.Errorf("src '%s': %w: %s", l.PathURI, ErrInvalidIndex, err)
+	}
+	if l.AtHead == "" {
+		return fmt.Errorf("missing at_head: %w", ErrInvalidIndex)
+	}
+	return nil
+}
+
 // FindIndices searches the specified root directory for index files, returning their relative directory paths while skipping hidden folders.
 func FindIndices(root string) ([]string, error) {
 	locs := make([]string, 0)
@@ -34,7 +34,7 @@ func writeIndex(t *testing.T, dir string) {
 }
 
 func TestWriteIndex(t *testing.T) {
-	expectedContent := fmt.Sprintf("src: lakefs://%s/%s/%s\nat_head: %s\nactive_operation: \"\"\n", repo, ref, uPath, head)
+	expectedContent := fmt.Sprintf("version: %d\nsrc: lakefs://%s/%s/%s\nat_head: %s\nactive_operation: \"\"\n", local.IndexVersion, repo, ref, uPath, head)
 	tmpDir := t.TempDir()
 	writeIndex(t, tmpDir)
 	buf, err := os.ReadFile(filepath.Join(tmpDir, local.IndexFileName))
@@ -106,3 +106,57 @@ func TestFindIndices(t *testing.T) {
 	require.Equal(t, 1, len(dirs))
 	require.Equal(t, ".", dirs[0])
 }
+
+func TestReadIndexMigration(t *testing.T) {
+	tmpDir := t.TempDir()
+	idxPath := filepath.Join(tmpDir, local.IndexFileName)
+	v1Content := fmt.Sprintf("src: %s\nat_head: %s\nactive_operation: \"\"\n", testUri, head)
+	require.NoError(t, os.WriteFile(idxPath, []byte(v1Content), local.IndexFileMode))
+
+	res, err := local.ReadIndex(tmpDir)
+	require.NoError(t, err)
+	require.Equal(t, local.IndexVersion, res.Version)
+	require.Equal(t, testUri.String(), res.PathURI)
+	require.Equal(t, head, res.AtHead)
+	require.NoError(t, res.Validate())
+
+	// migrated index is written back in the current format
+	buf, err := os.ReadFile(idxPath)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("version: %d\n%s", local.IndexVersion, v1Content), string(buf))
+}
+
+func TestReadIndexUnsupportedVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := fmt.Sprintf("version: %d\nsrc: %s\nat_head: %s\n", local.IndexVersion+1, testUri, head)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, local.IndexFileName), []byte(content), local.IndexFileMode))
+
+	_, err := local.ReadIndex(tmpDir)
+	require.ErrorIs(t, err, local.ErrUnsupportedIndexVersion)
+}
+
+func TestIndexValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{name: "valid", content: fmt.Sprintf("src: %s\nat_head: %s\n", testUri, head), valid: true},
+		{name: "missing_head", content: fmt.Sprintf("src: %s\n", testUri)},
+		{name: "missing_path", content: fmt.Sprintf("src: lakefs://%s/%s\nat_head: %s\n", repo, ref, head)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, local.IndexFileName), []byte(tt.content), local.IndexFileMode))
+			idx, err := local.ReadIndex(tmpDir)
+			require.NoError(t, err)
+			err = idx.Validate()
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, local.ErrInvalidIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+const journalFileMode = 0o644
+
+// JournalEntry is a file uploaded by an upload session
+type JournalEntry struct {
+	Path string `json:"path"`
+	// Destination is the URI of the object the file was uploaded to
+	Destination string `json:"destination"`
+	Size        int64  `json:"size"`
+	Mtime       int64  `json:"mtime"`
+}
+
+// journalKey identifies the upload of a file to an object, so that a journal reused for another destination does
+// not skip files uploaded elsewhere
+type journalKey struct {
+	path        string
+	destination string
+}
+
+// Journal records the files uploaded by an upload session, one JSON entry per line, so that an interrupted session
+// resumes without uploading them again. Files changed since they were recorded are uploaded again.
+type Journal struct {
+	path    string
+	mu      sync.Mutex
+	file    *os.File
+	entries map[journalKey]JournalEntry
+}
+
+// OpenJournal opens the journal at path for appending, reading the entries recorded by a previous session if it exists
+func OpenJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	entries := make(map[journalKey]JournalEntry)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var entry JournalEntry
+		// the last line may be partially written by an interrupted session
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries[journalKey{path: entry.Path, destination: entry.Destination}] = entry
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, journalFileMode)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		// terminate the partially written line, keeping it apart from the entries of this session
+		if _, err := file.Write([]byte("\n")); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	return &Journal{
+		path:    path,
+		file:    file,
+		entries: entries,
+	}, nil
+}
+
+// Uploaded reports whether the file at path was uploaded to destination by a previous session and not changed since
+func (j *Journal) Uploaded(path, destination string, info fs.FileInfo) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entry, ok := j.entries[journalKey{path: path, destination: destination}]
+	return ok && entry.Size == info.Size() && entry.Mtime == info.ModTime().Unix()
+}
+
+// Record records the upload of the file at path to destination
+func (j *Journal) Record(path, destination string, info fs.FileInfo) error {
+	entry := JournalEntry{
+		Path:        path,
+		Destination: destination,
+		Size:        info.Size(),
+		Mtime:       info.ModTime().Unix(),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	j.entries[journalKey{path: path, destination: destination}] = entry
+	return nil
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Remove closes and removes the journal of a completed session
+func (j *Journal) Remove() error {
+	if err := j.Close(); err != nil {
+		return err
+	}
+	return os.Remove(j.path)
+}
@@ -0,0 +1,67 @@
+package local_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/local"
+)
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "upload.journal")
+	writeFile := func(name, content string) os.FileInfo {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		info, err := os.Stat(p)
+		require.NoError(t, err)
+		return info
+	}
+	a := writeFile("a", "a")
+	b := writeFile("b", "b")
+	const (
+		destA = "lakefs://repo/main/data/a"
+		destB = "lakefs://repo/main/data/b"
+	)
+
+	journal, err := local.OpenJournal(journalPath)
+	require.NoError(t, err)
+	require.False(t, journal.Uploaded("a", destA, a))
+	require.NoError(t, journal.Record("a", destA, a))
+	require.True(t, journal.Uploaded("a", destA, a))
+	require.NoError(t, journal.Close())
+
+	// an interrupted session leaves a partially written entry
+	f, err := os.OpenFile(journalPath, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"path":"b","si`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	journal, err = local.OpenJournal(journalPath)
+	require.NoError(t, err)
+	require.True(t, journal.Uploaded("a", destA, a))
+	require.False(t, journal.Uploaded("b", destB, b))
+	require.NoError(t, journal.Record("b", destB, b))
+	require.NoError(t, journal.Close())
+
+	journal, err = local.OpenJournal(journalPath)
+	require.NoError(t, err)
+	require.True(t, journal.Uploaded("b", destB, b))
+	// files are uploaded again to another destination
+	require.False(t, journal.Uploaded("b", "lakefs://repo/dev/data/b", b))
+
+	// files changed since they were recorded are uploaded again
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "a"), time.Now(), time.Now().Add(time.Hour)))
+	changed, err := os.Stat(filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	require.False(t, journal.Uploaded("a", destA, changed))
+
+	require.NoError(t, journal.Remove())
+	_, err = os.Stat(journalPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,186 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LockFileName = ".lakefs_lock"
+	// LockStaleTimeout is the age after which a lock taken on another host is considered stale, as the
+	// process holding it cannot be checked
+	LockStaleTimeout = 24 * time.Hour
+)
+
+// LockInfo is the content of the lock file, identifying the process holding the lock
+type LockInfo struct {
+	PID       int       `yaml:"pid"`
+	Hostname  string    `yaml:"hostname"`
+	Operation string    `yaml:"operation"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+func (l *LockInfo) String() string {
+	return fmt.Sprintf("'%s' operation by pid %d on %s since %s", l.Operation, l.PID, l.Hostname, l.CreatedAt.Format(time.RFC3339))
+}
+
+// Lock is an advisory lock on a directory synced with lakeFS
+type Lock struct {
+	path string
+	data []byte
+}
+
+// AcquireLock takes the lock of the directory at path for operation. A lock held by a process which is no longer
+// running, or taken on another host more than LockStaleTimeout ago, is considered stale and is replaced. An
+// active lock fails with an error wrapping ErrLocked.
+func AcquireLock(path, operation string) (*Lock, error) {
+	lockPath := filepath.Join(path, LockFileName)
+	hostname, _ := os.Hostname()
+	info := &LockInfo{
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		Operation: operation,
+		CreatedAt: time.Now().UTC(),
+	}
+	data, err := yaml.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	err = createLockFile(lockPath, data)
+	if errors.Is(err, os.ErrExist) {
+		held, heldData, readErr := readLockFile(lockPath)
+		switch {
+		case errors.Is(readErr, os.ErrNotExist):
+			// released in the meantime
+		case readErr == nil && !held.IsStale(hostname):
+			return nil, fmt.Errorf("%s: %w", held, ErrLocked)
+		default:
+			// stale or unreadable, a lock file is written in a single call so partial content means its writer died
+			if err := takeOverLock(lockPath, heldData); err != nil {
+				return nil, err
+			}
+		}
+		err = createLockFile(lockPath, data)
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock taken concurrently: %w", ErrLocked)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{path: lockPath, data: data}, nil
+}
+
+// takeOverLock removes the stale lock file at lockPath if it still holds staleData. The lock file is first moved
+// aside, which only one of the processes taking over the same lock succeeds in, so that a lock taken by another
+// process since staleData was read is never removed: it is moved back instead.
+func takeOverLock(lockPath string, staleData []byte) error {
+	asidePath := fmt.Sprintf("%s.%d.%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lockPath, asidePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// taken over by another process
+			return nil
+		}
+		return err
+	}
+	asideData, err := os.ReadFile(asidePath)
+	if err == nil && !bytes.Equal(asideData, staleData) {
+		// the lock was taken by another process since it was read, put it back unless taken again
+		if err := os.Link(asidePath, lockPath); err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		_ = os.Remove(asidePath)
+		return fmt.Errorf("lock taken concurrently: %w", ErrLocked)
+	}
+	if err := os.Remove(asidePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func createLockFile(lockPath string, data []byte) error {
+	f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, IndexFileMode)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(lockPath)
+	}
+	return err
+}
+
+// ReadLock returns the lock information of the directory at path
+func ReadLock(path string) (*LockInfo, error) {
+	info, _, err := readLockFile(filepath.Join(path, LockFileName))
+	return info, err
+}
+
+// readLockFile returns the lock information of the lock file at lockPath and its content. The content is returned
+// even if the lock information cannot be parsed from it.
+func readLockFile(lockPath string) (*LockInfo, []byte, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var info LockInfo
+	if err := yaml.Unmarshal(data, &info); err != nil {
+		return nil, data, err
+	}
+	return &info, data, nil
+}
+
+// IsStale reports whether the lock is no longer held by its process, as seen from hostname
+func (l *LockInfo) IsStale(hostname string) bool {
+	if l.Hostname != hostname {
+		return time.Since(l.CreatedAt) > LockStaleTimeout
+	}
+	return !processExists(l.PID)
+}
+
+// Release removes the lock, unless it was taken over as stale by another process
+func (l *Lock) Release() error {
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, l.data) {
+		return nil
+	}
+	err = os.Remove(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = p.Release() }()
+	// on windows FindProcess fails for processes which are not running, other platforms require a signal check
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,138 @@
+package local_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/local"
+	"gopkg.in/yaml.v3"
+)
+
+func writeLock(t *testing.T, dir string, info local.LockInfo) {
+	t.Helper()
+	data, err := yaml.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, local.LockFileName), data, local.IndexFileMode))
+}
+
+func TestAcquireLock(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := local.AcquireLock(dir, "pull")
+	require.NoError(t, err)
+
+	info, err := local.ReadLock(dir)
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), info.PID)
+	require.Equal(t, "pull", info.Operation)
+
+	// lock is held by a running process
+	_, err = local.AcquireLock(dir, "commit")
+	require.ErrorIs(t, err, local.ErrLocked)
+
+	require.NoError(t, lock.Release())
+	_, err = os.Stat(filepath.Join(dir, local.LockFileName))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	lock, err = local.AcquireLock(dir, "commit")
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+	// releasing twice is allowed
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		info   local.LockInfo
+		locked bool
+	}{
+		{
+			name: "dead_process",
+			info: local.LockInfo{PID: 0, Hostname: hostname, Operation: "pull", CreatedAt: time.Now()},
+		},
+		{
+			name: "other_host_expired",
+			info: local.LockInfo{PID: os.Getpid(), Hostname: hostname + "-other", Operation: "pull", CreatedAt: time.Now().Add(-local.LockStaleTimeout - time.Minute)},
+		},
+		{
+			name:   "other_host_recent",
+			info:   local.LockInfo{PID: os.Getpid(), Hostname: hostname + "-other", Operation: "pull", CreatedAt: time.Now()},
+			locked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeLock(t, dir, tt.info)
+			lock, err := local.AcquireLock(dir, "commit")
+			if tt.locked {
+				require.ErrorIs(t, err, local.ErrLocked)
+				return
+			}
+			require.NoError(t, err)
+			info, err := local.ReadLock(dir)
+			require.NoError(t, err)
+			require.Equal(t, "commit", info.Operation)
+			require.NoError(t, lock.Release())
+		})
+	}
+}
+
+func TestAcquireLockStaleConcurrent(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	writeLock(t, dir, local.LockInfo{PID: 0, Hostname: hostname, Operation: "pull", CreatedAt: time.Now()})
+
+	const workers = 20
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		acquired []*local.Lock
+		errs     []error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lock, err := local.AcquireLock(dir, fmt.Sprintf("op%d", i))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			acquired = append(acquired, lock)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.ErrorIs(t, err, local.ErrLocked)
+	}
+	require.Len(t, acquired, 1, "stale lock taken over by more than one process")
+	require.NoError(t, acquired[0].Release())
+}
+
+func TestLockReleaseTakenOver(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := local.AcquireLock(dir, "pull")
+	require.NoError(t, err)
+
+	// the lock is taken over by another process
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	writeLock(t, dir, local.LockInfo{PID: os.Getpid(), Hostname: hostname, Operation: "commit", CreatedAt: time.Now()})
+
+	require.NoError(t, lock.Release())
+	info, err := local.ReadLock(dir)
+	require.NoError(t, err)
+	require.Equal(t, "commit", info.Operation)
+}
@@ -48,9 +48,10 @@ func getMtimeFromStats(stats apigen.ObjectStats) (int64, error) {
 }
 
 type Tasks struct {
-	Downloaded uint64
-	Uploaded   uint64
-	Removed    uint64
+	Downloaded    uint64
+	Uploaded      uint64
+	Removed       uint64
+	UploadedBytes uint64
 }
 
 type SyncManager struct {
@@ -60,6 +61,10 @@ type SyncManager struct {
 	progressBar *ProgressPool
 	flags       SyncFlags
 	tasks       Tasks
+	// localRemoved is set once a local file is removed, leaving directories that may be empty
+	localRemoved atomic.Bool
+	// journal records uploaded files when set
+	journal *Journal
 }
 
 func NewSyncManager(ctx context.Context, client *apigen.ClientWithResponses, flags SyncFlags) *SyncManager {
@@ -72,6 +77,11 @@ func NewSyncManager(ctx context.Context, client *apigen.ClientWithResponses, fla
 	}
 }
 
+// SetJournal records the files uploaded by the sync in journal
+func (s *SyncManager) SetJournal(journal *Journal) {
+	s.journal = journal
+}
+
 // Sync - sync changes between remote and local directory given the Changes channel.
 // For each change, will apply download, upload or delete according to the change type and change source
 func (s *SyncManager) Sync(rootPath string, remote *uri.URI, changeSet <-chan *Change) error {
@@ -92,6 +102,10 @@ func (s *SyncManager) Sync(rootPath string, remote *uri.URI, changeSet <-chan *C
 	if err := wg.Wait(); err != nil {
 		return err
 	}
+	if !s.localRemoved.Load() {
+		// keep directories of a local directory that was only read from
+		return nil
+	}
 	_, err := fileutil.PruneEmptyDirectories(rootPath)
 	return err
 }
@@ -238,12 +252,23 @@ func (s *SyncManager) download(ctx context.Context, rootPath string, remote *uri
 	return err
 }
 
+// UploadDestination returns the URI of the object the file at path, relative to the synced directory, is uploaded to
+func UploadDestination(remote *uri.URI, path string) *uri.URI {
+	dest := filepath.ToSlash(filepath.Join(remote.GetPath(), path))
+	return &uri.URI{
+		Repository: remote.Repository,
+		Ref:        remote.Ref,
+		Path:       &dest,
+	}
+}
+
 func (s *SyncManager) upload(ctx context.Context, rootPath string, remote *uri.URI, path string) error {
 	source := filepath.Join(rootPath, path)
 	if err := fileutil.VerifySafeFilename(source); err != nil {
 		return err
 	}
-	dest := filepath.ToSlash(filepath.Join(remote.GetPath(), path))
+	destination := UploadDestination(remote, path)
+	dest := destination.GetPath()
 
 	f, err := os.Open(source)
 	if err != nil {
@@ -264,6 +289,7 @@ func (s *SyncManager) upload(ctx context.Context, rootPath string, remote *uri.U
 			b.Error()
 		} else {
 			atomic.AddUint64(&s.tasks.Uploaded, 1)
+			atomic.AddUint64(&s.tasks.UploadedBytes, uint64(fileStat.Size()))
 			b.Done()
 		}
 	}()
@@ -278,11 +304,13 @@ func (s *SyncManager) upload(ctx context.Context, rootPath string, remote *uri.U
 	if s.flags.Presign {
 		_, err = helpers.ClientUploadPreSign(
 			ctx, s.client, remote.Repository, remote.Ref, dest, metadata, "", reader, s.flags.PresignMultipart)
-		return err
+	} else {
+		_, err = helpers.ClientUpload(
+			ctx, s.client, remote.Repository, remote.Ref, dest, metadata, "", reader)
+	}
+	if err == nil && s.journal != nil {
+		err = s.journal.Record(path, destination.String(), fileStat)
 	}
-	// not pre-signed
-	_, err = helpers.ClientUpload(
-		ctx, s.client, remote.Repository, remote.Ref, dest, metadata, "", reader)
 	return err
 }
 
@@ -303,6 +331,7 @@ func (s *SyncManager) deleteLocal(rootPath string, change *Change) (err error) {
 	if err != nil {
 		return err
 	}
+	s.localRemoved.Store(true)
 	return nil
 }
 
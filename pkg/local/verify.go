@@ -0,0 +1,117 @@
+package local
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+type VerifyStatus int
+
+const (
+	// VerifyStatusOK is a file whose content matches the checksum of its object
+	VerifyStatusOK VerifyStatus = iota
+	// VerifyStatusModified is a file edited since it was synced, its modification time changed with its content
+	VerifyStatusModified
+	// VerifyStatusCorrupted is a file whose content changed while its size and modification time still match the
+	// object, which normal edits do not do
+	VerifyStatusCorrupted
+	// VerifyStatusRemoved is an object with no local file
+	VerifyStatusRemoved
+	// VerifyStatusUnverifiable is a file whose object checksum is not an MD5 digest, such as an object uploaded in
+	// multiple parts, so its content cannot be compared
+	VerifyStatusUnverifiable
+)
+
+func VerifyStatusString(status VerifyStatus) string {
+	switch status {
+	case VerifyStatusOK:
+		return "ok"
+	case VerifyStatusModified:
+		return "modified"
+	case VerifyStatusCorrupted:
+		return "corrupted"
+	case VerifyStatusRemoved:
+		return "removed"
+	case VerifyStatusUnverifiable:
+		return "unverifiable"
+	default:
+		panic("invalid verify status")
+	}
+}
+
+type VerifyResult struct {
+	Path   string
+	Status VerifyStatus
+}
+
+// Verify re-hashes the files of the local directory at rightPath and compares them with the checksums of the objects
+// in left, the objects of the commit the directory is synced to. Files not in left are not verified.
+func Verify(left <-chan apigen.ObjectStats, rightPath string) ([]VerifyResult, error) {
+	var results []VerifyResult
+	for remote := range left {
+		status, err := verifyFile(filepath.Join(rightPath, filepath.FromSlash(remote.Path)), remote)
+		if err != nil {
+			// drain, so the remote listing does not block
+			for range left {
+			}
+			return nil, err
+		}
+		results = append(results, VerifyResult{Path: remote.Path, Status: status})
+	}
+	return results, nil
+}
+
+func verifyFile(path string, remote apigen.ObjectStats) (VerifyStatus, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return VerifyStatusRemoved, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if info.IsDir() {
+		return VerifyStatusRemoved, nil
+	}
+	if !isMD5Checksum(remote.Checksum) {
+		return VerifyStatusUnverifiable, nil
+	}
+	checksum, err := fileMD5(path)
+	if err != nil {
+		return 0, err
+	}
+	if checksum == strings.ToLower(remote.Checksum) {
+		return VerifyStatusOK, nil
+	}
+	remoteMtime, err := getMtimeFromStats(remote)
+	if err != nil {
+		return 0, err
+	}
+	// files are synced with the modification time of their object and edits replace it, so content that changed
+	// under the same modification time was not edited: it is corrupted or was tampered with
+	if info.ModTime().Unix() != remoteMtime {
+		return VerifyStatusModified, nil
+	}
+	return VerifyStatusCorrupted, nil
+}
+
+// fileMD5 returns the hex encoded MD5 digest of the content of the file at path
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := md5.New() //nolint:gosec
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,60 @@
+package local_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/local"
+)
+
+func TestVerify(t *testing.T) {
+	const (
+		// md5 of "foo"
+		fooChecksum = "acbd18db4cc2f85cedef654fccc4a4d8"
+		syncedMtime = diffTestCorrectTime
+	)
+	dir := t.TempDir()
+	writeFile := func(p, content string, mtime int64) {
+		t.Helper()
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(full, time.Now(), time.Unix(mtime, 0)))
+	}
+	writeFile("ok.txt", "foo", syncedMtime)
+	writeFile("sub/edited.txt", "bar", syncedMtime+60)
+	writeFile("sub/flipped.txt", "fog", syncedMtime)
+	writeFile("truncated.txt", "fo", syncedMtime)
+	writeFile("multipart.bin", "anything", syncedMtime)
+	writeFile("untracked.txt", "new", syncedMtime)
+
+	remote := []apigen.ObjectStats{
+		{Path: "gone.txt", Checksum: fooChecksum, SizeBytes: swag.Int64(3), Mtime: syncedMtime},
+		{Path: "multipart.bin", Checksum: "0123456789abcdef0123456789abcdef-2", SizeBytes: swag.Int64(8), Mtime: syncedMtime},
+		{Path: "ok.txt", Checksum: "ACBD18DB4CC2F85CEDEF654FCCC4A4D8", SizeBytes: swag.Int64(3), Mtime: syncedMtime},
+		{Path: "sub/edited.txt", Checksum: fooChecksum, SizeBytes: swag.Int64(3), Mtime: syncedMtime},
+		{Path: "sub/flipped.txt", Checksum: fooChecksum, SizeBytes: swag.Int64(3), Mtime: syncedMtime},
+		{Path: "truncated.txt", Checksum: fooChecksum, SizeBytes: swag.Int64(3), Mtime: syncedMtime},
+	}
+	left := make(chan apigen.ObjectStats, len(remote))
+	for _, o := range remote {
+		left <- o
+	}
+	close(left)
+
+	results, err := local.Verify(left, dir)
+	require.NoError(t, err)
+	require.Equal(t, []local.VerifyResult{
+		{Path: "gone.txt", Status: local.VerifyStatusRemoved},
+		{Path: "multipart.bin", Status: local.VerifyStatusUnverifiable},
+		{Path: "ok.txt", Status: local.VerifyStatusOK},
+		{Path: "sub/edited.txt", Status: local.VerifyStatusModified},
+		{Path: "sub/flipped.txt", Status: local.VerifyStatusCorrupted},
+		{Path: "truncated.txt", Status: local.VerifyStatusCorrupted},
+	}, results)
+}
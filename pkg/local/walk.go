@@ -0,0 +1,113 @@
+package local
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// dirListing is the result of reading a directory ahead of its walk
+type dirListing struct {
+	done  chan struct{}
+	infos []fs.FileInfo
+	err   error
+}
+
+// dirReader reads directories concurrently, up to the capacity of sem at once
+type dirReader struct {
+	sem  chan struct{}
+	stop chan struct{}
+}
+
+// read starts reading the directory at path, the listing is ready once its done channel is closed
+func (r *dirReader) read(path string) *dirListing {
+	listing := &dirListing{done: make(chan struct{})}
+	go func() {
+		defer close(listing.done)
+		select {
+		case r.sem <- struct{}{}:
+		case <-r.stop:
+			// the walk ended before reaching the directory
+			return
+		}
+		defer func() { <-r.sem }()
+		listing.infos, listing.err = readDirInfos(path)
+	}()
+	return listing
+}
+
+// readDirInfos returns the file info of the entries of the directory at path, sorted by name like filepath.Walk
+func readDirInfos(path string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// walkConcurrently walks the file tree rooted at root like filepath.Walk, calling fn for each file and directory in
+// the same lexical order, while up to parallelism directories are read ahead concurrently. With parallelism of 1 it
+// is filepath.Walk.
+func walkConcurrently(root string, parallelism int, fn filepath.WalkFunc) error {
+	if parallelism <= 1 {
+		return filepath.Walk(root, fn)
+	}
+	info, err := os.Lstat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	r := &dirReader{
+		sem:  make(chan struct{}, parallelism),
+		stop: make(chan struct{}),
+	}
+	defer close(r.stop)
+	var listing *dirListing
+	if info.IsDir() {
+		listing = r.read(root)
+	}
+	err = r.walk(root, info, listing, fn)
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func (r *dirReader) walk(path string, info fs.FileInfo, listing *dirListing, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+	if err := fn(path, info, nil); err != nil {
+		return err
+	}
+	<-listing.done
+	if listing.err != nil {
+		return fn(path, info, listing.err)
+	}
+
+	// start reading the subdirectories before walking the entries of the directory
+	subdirs := make([]*dirListing, len(listing.infos))
+	for i, child := range listing.infos {
+		if child.IsDir() {
+			subdirs[i] = r.read(filepath.Join(path, child.Name()))
+		}
+	}
+	for i, child := range listing.infos {
+		err := r.walk(filepath.Join(path, child.Name()), child, subdirs[i], fn)
+		if err == nil {
+			continue
+		}
+		if !child.IsDir() || !errors.Is(err, filepath.SkipDir) {
+			return err
+		}
+	}
+	return nil
+}
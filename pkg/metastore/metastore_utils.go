@@ -4,13 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/treeverse/lakefs/pkg/gateway/path"
 )
 
 const symlinkInputFormat = "org.apache.hadoop.hive.ql.io.SymlinkTextInputFormat"
 
-var ErrInvalidLocation = errors.New("got empty schema or host while parsing location url, location should be schema://host/path")
+var (
+	ErrInvalidLocation  = errors.New("got empty schema or host while parsing location url, location should be schema://host/path")
+	ErrUnexpectedBranch = errors.New("location is not on the expected branch")
+)
 
 func ReplaceBranchName(location, branch string) (string, error) {
 	u, err := url.Parse(location)
@@ -28,6 +32,21 @@ func ReplaceBranchName(location, branch string) (string, error) {
 	return u.Scheme + "://" + u.Host + "/" + branch + "/" + p.Path, nil
 }
 
+// ReplaceBranchNameFrom replaces the branch of location with branch, like ReplaceBranchName, failing if location is
+// not on fromBranch. An empty fromBranch accepts locations on any branch.
+func ReplaceBranchNameFrom(location, fromBranch, branch string) (string, error) {
+	if fromBranch != "" {
+		_, ref, err := ExtractRepoAndBranch(location)
+		if err != nil {
+			return "", err
+		}
+		if ref != fromBranch {
+			return "", fmt.Errorf("%w: %s is on %s, expected %s", ErrUnexpectedBranch, location, ref, fromBranch)
+		}
+	}
+	return ReplaceBranchName(location, branch)
+}
+
 func ReplaceExternalToLakeFSImported(location, repo, branch string) (string, error) {
 	u, err := url.Parse(location)
 	if err != nil {
@@ -54,6 +73,32 @@ func GetSymlinkLocation(location, locationPrefix string) (string, error) {
 	return locationPrefix + "/" + u.Host + "/" + p.Ref + "/" + p.Path, nil
 }
 
+// GetSymlinkManifestsLocation returns the location of the symlink manifests of the table or partition at location,
+// given the manifests location of the table at tablePath
+func GetSymlinkManifestsLocation(location, tablePath, manifestsLocation string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", ErrInvalidLocation
+	}
+	p, err := path.ResolvePath(u.Path)
+	if err != nil {
+		return "", err
+	}
+	tablePath = strings.Trim(tablePath, "/")
+	objectPath := strings.TrimSuffix(p.Path, "/")
+	if tablePath != "" && objectPath != tablePath && !strings.HasPrefix(objectPath, tablePath+"/") {
+		return "", fmt.Errorf("%w: %s not under table path %s", ErrInvalidLocation, location, tablePath)
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(objectPath, tablePath), "/")
+	if rel == "" {
+		return manifestsLocation, nil
+	}
+	return strings.TrimSuffix(manifestsLocation, "/") + "/" + rel, nil
+}
+
 func ExtractRepoAndBranch(metastoreLocationURI string) (string, string, error) {
 	u, err := url.Parse(metastoreLocationURI)
 	if err != nil {
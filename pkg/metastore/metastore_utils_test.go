@@ -1,6 +1,9 @@
 package metastore
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestReplaceBranchName(t *testing.T) {
 	type args struct {
@@ -68,6 +71,52 @@ func TestReplaceBranchName(t *testing.T) {
 	}
 }
 
+func TestReplaceBranchNameFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		location   string
+		fromBranch string
+		want       string
+		wantErr    error
+	}{
+		{
+			name:       "any branch",
+			location:   "s3a://repo/feature/path/to/table",
+			fromBranch: "",
+			want:       "s3a://repo/main/path/to/table",
+		},
+		{
+			name:       "expected branch",
+			location:   "s3a://repo/feature/path/to/table/partition=value",
+			fromBranch: "feature",
+			want:       "s3a://repo/main/path/to/table/partition=value",
+		},
+		{
+			name:       "unexpected branch",
+			location:   "s3a://repo/other/path/to/table",
+			fromBranch: "feature",
+			wantErr:    ErrUnexpectedBranch,
+		},
+		{
+			name:       "no schema",
+			location:   "noschema/repo",
+			fromBranch: "feature",
+			wantErr:    ErrInvalidLocation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReplaceBranchNameFrom(tt.location, tt.fromBranch, "main")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReplaceBranchNameFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReplaceBranchNameFrom() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetSymlinkLocation1(t *testing.T) {
 	type args struct {
 		location       string
@@ -141,3 +190,51 @@ func TestGetSymlinkLocation1(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSymlinkManifestsLocation(t *testing.T) {
+	const manifestsLocation = "s3://bucket/ns/symlinks/repo/c0ffee/tables/events"
+	tests := []struct {
+		name      string
+		location  string
+		tablePath string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "table",
+			location:  "s3a://repo/main/tables/events",
+			tablePath: "tables/events",
+			want:      manifestsLocation,
+		},
+		{
+			name:      "partition",
+			location:  "s3a://repo/main/tables/events/dt=2024-01-01",
+			tablePath: "/tables/events/",
+			want:      manifestsLocation + "/dt=2024-01-01",
+		},
+		{
+			name:      "not under table",
+			location:  "s3a://repo/main/tables/events_old/dt=2024-01-01",
+			tablePath: "tables/events",
+			wantErr:   true,
+		},
+		{
+			name:      "no schema",
+			location:  "noschema/repo",
+			tablePath: "tables/events",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSymlinkManifestsLocation(tt.location, tt.tablePath, manifestsLocation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSymlinkManifestsLocation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSymlinkManifestsLocation() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
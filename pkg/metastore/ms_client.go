@@ -41,10 +41,12 @@ type Client interface {
 	WriteClient
 }
 
-func CopyOrMerge(ctx context.Context, fromClient, toClient Client, fromDB, fromTable, toDB, toTable, toBranch, serde string, partition []string, fixSparkPlaceHolder bool, dbfsLocation string) error {
+// CopyOrMerge copies the table, or merges it into an existing destination table, setting the branch of the locations
+// of the table and its partitions to toBranch. When fromBranch is set, locations on other branches fail the copy.
+func CopyOrMerge(ctx context.Context, fromClient, toClient Client, fromDB, fromTable, toDB, toTable, fromBranch, toBranch, serde string, partition []string, fixSparkPlaceHolder bool, dbfsLocation string) error {
 	transformLocation := func(location string) (string, error) {
 		location = HandleDBFSLocation(ctx, location, dbfsLocation)
-		transformedLocation, err := ReplaceBranchName(location, toBranch)
+		transformedLocation, err := ReplaceBranchNameFrom(location, fromBranch, toBranch)
 		if err != nil {
 			return "", fmt.Errorf("failed to replace branch name with location: '%s' and branch: '%s': %w", location, toBranch, err)
 		}
@@ -354,6 +356,15 @@ func getColumnDiff(ctx context.Context, fromClient, toClient ReadClient, fromDB,
 	return Diff(colsIter, toColsIter)
 }
 
+// CopyOrMergeToSymlinkManifests copies the table, setting the locations of the table and its partitions to their
+// symlink manifests, written to manifestsLocation for the table at tablePath
+func CopyOrMergeToSymlinkManifests(ctx context.Context, fromClient, toClient Client, fromDB, fromTable, toDB, toTable, tablePath, manifestsLocation string, fixSparkPlaceHolder bool) error {
+	transformLocation := func(location string) (string, error) {
+		return GetSymlinkManifestsLocation(location, tablePath, manifestsLocation)
+	}
+	return copyOrMergeWithTransformLocation(ctx, fromClient, toClient, fromDB, fromTable, toDB, toTable, "", true, nil, transformLocation, fixSparkPlaceHolder)
+}
+
 func CopyOrMergeToSymlink(ctx context.Context, fromClient, toClient Client, fromDB, fromTable, toDB, toTable, locationPrefix string, fixSparkPlaceHolder bool) error {
 	transformLocation := func(location string) (string, error) {
 		return GetSymlinkLocation(location, locationPrefix)
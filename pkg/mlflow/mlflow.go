@@ -0,0 +1,132 @@
+package mlflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Environment variables set by MLflow for the processes of an active run
+const (
+	EnvRunID            = "MLFLOW_RUN_ID"
+	EnvExperimentID     = "MLFLOW_EXPERIMENT_ID"
+	EnvTrackingURI      = "MLFLOW_TRACKING_URI"
+	EnvTrackingToken    = "MLFLOW_TRACKING_TOKEN"
+	EnvTrackingUsername = "MLFLOW_TRACKING_USERNAME"
+	EnvTrackingPassword = "MLFLOW_TRACKING_PASSWORD"
+)
+
+// CommitURITag is the run tag holding the URI of the lakeFS commit created by the run
+const CommitURITag = "lakefs.commit_uri"
+
+const setTagPath = "api/2.0/mlflow/runs/set-tag"
+
+var (
+	ErrNoTrackingServer     = errors.New("no MLflow tracking server")
+	ErrUnexpectedStatusCode = errors.New("unexpected status code")
+)
+
+// Run is an MLflow run
+type Run struct {
+	ID           string
+	ExperimentID string
+	// TrackingURI is the tracking server URL, or a local path when the run is tracked in local files
+	TrackingURI string
+}
+
+// ActiveRun returns the MLflow run active in the environment of the process, or nil if there is none
+func ActiveRun() *Run {
+	id := os.Getenv(EnvRunID)
+	if id == "" {
+		return nil
+	}
+	return &Run{
+		ID:           id,
+		ExperimentID: os.Getenv(EnvExperimentID),
+		TrackingURI:  os.Getenv(EnvTrackingURI),
+	}
+}
+
+// trackingServerURL returns the URL of the tracking server of the run, or nil if it is not tracked by a server
+func (r *Run) trackingServerURL() *url.URL {
+	u, err := url.Parse(r.TrackingURI)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	return u
+}
+
+// redactURI returns uri without the credentials it may hold in its user info and query, so that it can be stored in
+// commit metadata readable by every user of the repository
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		// an unparsable URI may still hold credentials
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// MetadataFor returns commit metadata identifying the run, including a link to the run in the MLflow UI when the
+// run is tracked by a server. Credentials in the tracking URI are not included.
+func MetadataFor(r *Run) map[string]string {
+	kv := map[string]string{
+		"mlflow_run_id": r.ID,
+	}
+	if r.ExperimentID != "" {
+		kv["mlflow_experiment_id"] = r.ExperimentID
+	}
+	if trackingURI := redactURI(r.TrackingURI); trackingURI != "" {
+		kv["mlflow_tracking_uri"] = trackingURI
+	}
+	if u := r.trackingServerURL(); u != nil && r.ExperimentID != "" {
+		runURL := strings.TrimSuffix(redactURI(u.String()), "/") + "/#/experiments/" + url.PathEscape(r.ExperimentID) + "/runs/" + url.PathEscape(r.ID)
+		kv["::lakefs::mlflow::url[url:ui]"] = runURL
+	}
+	return kv
+}
+
+// SetTag sets a tag of the run on its tracking server, authenticating with the MLflow tracking credentials in the
+// environment
+func SetTag(ctx context.Context, client *http.Client, r *Run, key, value string) error {
+	u := r.trackingServerURL()
+	if u == nil {
+		return fmt.Errorf("tracking URI '%s': %w", r.TrackingURI, ErrNoTrackingServer)
+	}
+	body, err := json.Marshal(map[string]string{
+		"run_id": r.ID,
+		"key":    key,
+		"value":  value,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.JoinPath(setTagPath).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv(EnvTrackingToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if username := os.Getenv(EnvTrackingUsername); username != "" {
+		req.SetBasicAuth(username, os.Getenv(EnvTrackingPassword))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("set tag %s: HTTP %d: %w", key, resp.StatusCode, ErrUnexpectedStatusCode)
+	}
+	return nil
+}
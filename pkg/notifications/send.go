@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/config"
+)
+
+const shortCommitIDLength = 16
+
+var errUnexpectedStatusCode = errors.New("unexpected status code")
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// subject returns the title of the digest of rule
+func subject(rule config.NotificationRule, d *digest) string {
+	title := "lakeFS activity"
+	if rule.Name != "" {
+		title += " (" + rule.Name + ")"
+	}
+	return fmt.Sprintf("%s: %d commit(s) and merge(s)", title, d.total)
+}
+
+// formatDigest returns the digest text, a line per event
+func formatDigest(d *digest) string {
+	var b strings.Builder
+	for _, e := range d.events {
+		commitID := e.CommitID
+		if len(commitID) > shortCommitIDLength {
+			commitID = commitID[:shortCommitIDLength]
+		}
+		message, _, _ := strings.Cut(e.Message, "\n")
+		_, _ = fmt.Fprintf(&b, "%s %s/%s: %s %s by %s: %s\n",
+			e.Time.UTC().Format(time.RFC3339), e.Repository, e.Branch, e.Type, commitID, e.Committer, message)
+	}
+	if more := d.total - len(d.events); more > 0 {
+		_, _ = fmt.Fprintf(&b, "... and %d more\n", more)
+	}
+	return b.String()
+}
+
+func (s *Service) sendSlack(rule config.NotificationRule, d *digest) error {
+	payload, err := json.Marshal(slackMessage{
+		Text: "*" + subject(rule, d) + "*\n```\n" + formatDigest(d) + "```",
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.SlackWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slack webhook status code %d: %w", resp.StatusCode, errUnexpectedStatusCode)
+	}
+	return nil
+}
+
+func (s *Service) sendEmail(rule config.NotificationRule, d *digest) error {
+	smtpCfg := s.cfg.SMTP
+	var msg bytes.Buffer
+	_, _ = fmt.Fprintf(&msg, "From: %s\r\n", smtpCfg.From)
+	_, _ = fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(rule.Emails, ", "))
+	_, _ = fmt.Fprintf(&msg, "Subject: %s\r\n", subject(rule, d))
+	_, _ = fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(formatDigest(d), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if smtpCfg.Username != "" {
+		auth = smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password.SecureValue(), smtpCfg.Host)
+	}
+	addr := net.JoinHostPort(smtpCfg.Host, strconv.Itoa(smtpCfg.Port))
+	return s.sendMail(addr, auth, smtpCfg.From, rule.Emails, msg.Bytes())
+}
@@ -0,0 +1,219 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	EventTypeCommit = "commit"
+	EventTypeMerge  = "merge"
+
+	sendTimeout = 30 * time.Second
+)
+
+var ErrBadConfig = errors.New("invalid notifications configuration")
+
+// Event is a commit or a merge notified in a digest
+type Event struct {
+	Type       string
+	Repository string
+	Branch     string
+	CommitID   string
+	Committer  string
+	Message    string
+	Time       time.Time
+}
+
+// digest batches the events of a rule until they are sent
+type digest struct {
+	events []Event
+	// total counts all events, including those not kept once the digest is full
+	total int
+}
+
+// Service wraps a graveler.HooksHandler and notifies the commits and merges matching the configured rules, batched
+// into digests. Any hook is passed as is to the wrapped handler.
+type Service struct {
+	graveler.HooksHandler
+	cfg        config.Notifications
+	logger     logging.Logger
+	httpClient *http.Client
+	sendMail   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+	mu      sync.Mutex
+	pending map[int]*digest
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService returns a Service wrapping hooks, and starts sending digests every configured digest interval.
+// Stop must be called to send the remaining events and release the service.
+func NewService(cfg config.Notifications, hooks graveler.HooksHandler, logger logging.Logger) (*Service, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	s := &Service{
+		HooksHandler: hooks,
+		cfg:          cfg,
+		logger:       logger,
+		httpClient:   &http.Client{Timeout: sendTimeout},
+		sendMail:     smtp.SendMail,
+		pending:      make(map[int]*digest),
+		done:         make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+func validateConfig(cfg config.Notifications) error {
+	if cfg.DigestInterval <= 0 {
+		return fmt.Errorf("digest interval must be positive: %w", ErrBadConfig)
+	}
+	for i, rule := range cfg.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		for _, pattern := range append(append([]string{}, rule.Repositories...), rule.Branches...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: pattern %q: %w", name, pattern, ErrBadConfig)
+			}
+		}
+		for _, event := range rule.Events {
+			if event != EventTypeCommit && event != EventTypeMerge {
+				return fmt.Errorf("rule %s: unknown event %q: %w", name, event, ErrBadConfig)
+			}
+		}
+		if rule.SlackWebhookURL == "" && len(rule.Emails) == 0 {
+			return fmt.Errorf("rule %s: no slack webhook or emails: %w", name, ErrBadConfig)
+		}
+		if len(rule.Emails) > 0 && (cfg.SMTP.Host == "" || cfg.SMTP.From == "") {
+			return fmt.Errorf("rule %s: emails require smtp host and from: %w", name, ErrBadConfig)
+		}
+	}
+	return nil
+}
+
+func (s *Service) PostCommitHook(ctx context.Context, record graveler.HookRecord) error {
+	s.add(EventTypeCommit, record)
+	return s.HooksHandler.PostCommitHook(ctx, record)
+}
+
+func (s *Service) PostMergeHook(ctx context.Context, record graveler.HookRecord) error {
+	s.add(EventTypeMerge, record)
+	return s.HooksHandler.PostMergeHook(ctx, record)
+}
+
+// add adds the event of the record to the digests of the rules it matches
+func (s *Service) add(eventType string, record graveler.HookRecord) {
+	event := Event{
+		Type:       eventType,
+		Repository: record.RepositoryID.String(),
+		Branch:     record.BranchID.String(),
+		CommitID:   record.CommitID.String(),
+		Committer:  record.Commit.Committer,
+		Message:    record.Commit.Message,
+		Time:       record.Commit.CreationDate,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rule := range s.cfg.Rules {
+		if !ruleMatches(rule, event) {
+			continue
+		}
+		d, ok := s.pending[i]
+		if !ok {
+			d = &digest{}
+			s.pending[i] = d
+		}
+		d.total++
+		if len(d.events) < s.cfg.MaxDigestEvents {
+			d.events = append(d.events, event)
+		}
+	}
+}
+
+func ruleMatches(rule config.NotificationRule, event Event) bool {
+	return matchesAny(rule.Repositories, event.Repository) &&
+		matchesAny(rule.Branches, event.Branch) &&
+		(len(rule.Events) == 0 || contains(rule.Events, event.Type))
+}
+
+// matchesAny reports whether name matches one of the glob patterns, or there are no patterns
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) loop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.DigestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Stop stops sending digests periodically and sends the events not sent yet
+func (s *Service) Stop() {
+	close(s.done)
+	s.wg.Wait()
+	s.flush()
+}
+
+// flush sends the pending digest of every rule. A digest that fails to send is dropped.
+func (s *Service) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[int]*digest)
+	s.mu.Unlock()
+
+	for i, d := range pending {
+		rule := s.cfg.Rules[i]
+		log := s.logger.WithFields(logging.Fields{"rule": rule.Name, "events": d.total})
+		if rule.SlackWebhookURL != "" {
+			if err := s.sendSlack(rule, d); err != nil {
+				log.WithError(err).Warn("Failed to send notification digest to Slack")
+			}
+		}
+		if len(rule.Emails) > 0 {
+			if err := s.sendEmail(rule, d); err != nil {
+				log.WithError(err).Warn("Failed to send notification digest by email")
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// newSlackServer returns a Slack webhook server recording the text of the received messages
+func newSlackServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		texts = append(texts, msg.Text)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, texts...)
+	}
+}
+
+func hookRecord(repository, branch, commitID, message string) graveler.HookRecord {
+	return graveler.HookRecord{
+		RepositoryID: graveler.RepositoryID(repository),
+		BranchID:     graveler.BranchID(branch),
+		CommitID:     graveler.CommitID(commitID),
+		Commit: graveler.Commit{
+			Committer:    "alice",
+			Message:      message,
+			CreationDate: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+}
+
+func TestService_Digest(t *testing.T) {
+	ctx := context.Background()
+	server, slackTexts := newSlackServer(t)
+	var mails []string
+	cfg := config.Notifications{
+		DigestInterval:  time.Hour,
+		MaxDigestEvents: 2,
+		Rules: []config.NotificationRule{
+			{Name: "prod", Repositories: []string{"repo*"}, Branches: []string{"prod"}, SlackWebhookURL: server.URL},
+			{Name: "merges", Events: []string{EventTypeMerge}, Emails: []string{"data@example.com"}},
+		},
+	}
+	cfg.SMTP.Host = "smtp.example.com"
+	cfg.SMTP.Port = 587
+	cfg.SMTP.From = "lakefs@example.com"
+	s, err := NewService(cfg, &graveler.HooksNoOp{}, logging.ContextUnavailable())
+	require.NoError(t, err)
+	s.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		require.Equal(t, "smtp.example.com:587", addr)
+		require.Equal(t, "lakefs@example.com", from)
+		require.Equal(t, []string{"data@example.com"}, to)
+		mails = append(mails, string(msg))
+		return nil
+	}
+
+	require.NoError(t, s.PostCommitHook(ctx, hookRecord("repo1", "prod", "c1", "first\ndetails")))
+	require.NoError(t, s.PostCommitHook(ctx, hookRecord("repo1", "dev", "c2", "not notified")))
+	require.NoError(t, s.PostCommitHook(ctx, hookRecord("other", "prod", "c3", "not notified")))
+	require.NoError(t, s.PostMergeHook(ctx, hookRecord("repo2", "prod", "c4", "Merge 'dev' into 'prod'")))
+	require.NoError(t, s.PostCommitHook(ctx, hookRecord("repo1", "prod", "c5", "third")))
+	s.Stop()
+
+	require.Equal(t, []string{"*lakeFS activity (prod): 3 commit(s) and merge(s)*\n```\n" +
+		"2023-01-02T03:04:05Z repo1/prod: commit c1 by alice: first\n" +
+		"2023-01-02T03:04:05Z repo2/prod: merge c4 by alice: Merge 'dev' into 'prod'\n" +
+		"... and 1 more\n```"}, slackTexts())
+	require.Len(t, mails, 1)
+	require.Contains(t, mails[0], "Subject: lakeFS activity (merges): 1 commit(s) and merge(s)\r\n")
+	require.True(t, strings.HasSuffix(mails[0], "\r\n\r\n2023-01-02T03:04:05Z repo2/prod: merge c4 by alice: Merge 'dev' into 'prod'\r\n"))
+}
+
+func TestService_DigestInterval(t *testing.T) {
+	server, slackTexts := newSlackServer(t)
+	s, err := NewService(config.Notifications{
+		DigestInterval:  10 * time.Millisecond,
+		MaxDigestEvents: 10,
+		Rules:           []config.NotificationRule{{SlackWebhookURL: server.URL}},
+	}, &graveler.HooksNoOp{}, logging.ContextUnavailable())
+	require.NoError(t, err)
+	defer s.Stop()
+
+	require.NoError(t, s.PostCommitHook(context.Background(), hookRecord("repo1", "main", "c1", "message")))
+	require.Eventually(t, func() bool {
+		return len(slackTexts()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewService_BadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.NotificationRule
+	}{
+		{name: "bad pattern", rule: config.NotificationRule{Branches: []string{"["}, SlackWebhookURL: "http://slack"}},
+		{name: "unknown event", rule: config.NotificationRule{Events: []string{"tag"}, SlackWebhookURL: "http://slack"}},
+		{name: "no destination", rule: config.NotificationRule{}},
+		{name: "emails without smtp", rule: config.NotificationRule{Emails: []string{"data@example.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewService(config.Notifications{
+				DigestInterval: time.Minute,
+				Rules:          []config.NotificationRule{tt.rule},
+			}, &graveler.HooksNoOp{}, logging.ContextUnavailable())
+			if !errors.Is(err, ErrBadConfig) {
+				t.Fatalf("NewService() err=%v, expected %v", err, ErrBadConfig)
+			}
+		})
+	}
+}
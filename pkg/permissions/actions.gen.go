@@ -15,6 +15,10 @@ var Actions = []string{
 	"fs:WriteObject",
 	"fs:DeleteObject",
 	"fs:ListObjects",
+	"fs:RestoreObjects",
+	"fs:CreateObjectComment",
+	"fs:ListObjectComments",
+	"fs:TagObject",
 	"fs:CreateCommit",
 	"fs:CreateMetaRange",
 	"fs:ReadCommit",
@@ -24,6 +28,12 @@ var Actions = []string{
 	"fs:ReadBranch",
 	"fs:RevertBranch",
 	"fs:ListBranches",
+	"fs:ListDeletedBranches",
+	"fs:CreateStash",
+	"fs:ApplyStash",
+	"fs:ReadStash",
+	"fs:ListStashes",
+	"fs:DeleteStash",
 	"fs:CreateTag",
 	"fs:DeleteTag",
 	"fs:ReadTag",
@@ -50,11 +60,30 @@ var Actions = []string{
 	"auth:CreateCredentials",
 	"auth:DeleteCredentials",
 	"auth:ListCredentials",
+	"auth:ListSessions",
+	"auth:RevokeSession",
+	"auth:SimulateAuthorization",
+	"auth:CreateOrganization",
+	"auth:ReadOrganization",
+	"auth:UpdateOrganization",
+	"auth:DeleteOrganization",
+	"auth:ListOrganizations",
+	"auth:AddOrganizationMember",
+	"auth:RemoveOrganizationMember",
 	"ci:ReadAction",
 	"retention:PrepareGarbageCollectionCommits",
 	"retention:GetGarbageCollectionRules",
 	"retention:SetGarbageCollectionRules",
 	"retention:PrepareGarbageCollectionUncommitted",
+	"retention:GetLegalHolds",
+	"retention:SetLegalHolds",
+	"retention:GetLifecycleRules",
+	"retention:SetLifecycleRules",
+	"retention:GetObjectLockConfiguration",
+	"retention:SetObjectLockConfiguration",
+	"retention:SetObjectRetention",
+	"retention:BypassGovernanceRetention",
 	"branches:GetBranchProtectionRules",
 	"branches:SetBranchProtectionRules",
+	"branches:LockBranch",
 }
@@ -26,6 +26,10 @@ const (
 	WriteObjectAction                         = "fs:WriteObject"
 	DeleteObjectAction                        = "fs:DeleteObject"
 	ListObjectsAction                         = "fs:ListObjects"
+	RestoreObjectsAction                      = "fs:RestoreObjects"
+	CreateObjectCommentAction                 = "fs:CreateObjectComment"
+	ListObjectCommentsAction                  = "fs:ListObjectComments"
+	TagObjectAction                           = "fs:TagObject"
 	CreateCommitAction                        = "fs:CreateCommit"
 	CreateMetaRangeAction                     = "fs:CreateMetaRange"
 	ReadCommitAction                          = "fs:ReadCommit"
@@ -35,6 +39,12 @@ const (
 	ReadBranchAction                          = "fs:ReadBranch"
 	RevertBranchAction                        = "fs:RevertBranch"
 	ListBranchesAction                        = "fs:ListBranches"
+	ListDeletedBranchesAction                 = "fs:ListDeletedBranches"
+	CreateStashAction                         = "fs:CreateStash"
+	ApplyStashAction                          = "fs:ApplyStash"
+	ReadStashAction                           = "fs:ReadStash"
+	ListStashesAction                         = "fs:ListStashes"
+	DeleteStashAction                         = "fs:DeleteStash"
 	CreateTagAction                           = "fs:CreateTag"
 	DeleteTagAction                           = "fs:DeleteTag"
 	ReadTagAction                             = "fs:ReadTag"
@@ -61,13 +71,32 @@ const (
 	CreateCredentialsAction                   = "auth:CreateCredentials" //nolint:gosec
 	DeleteCredentialsAction                   = "auth:DeleteCredentials" //nolint:gosec
 	ListCredentialsAction                     = "auth:ListCredentials"   //nolint:gosec
+	ListSessionsAction                        = "auth:ListSessions"
+	RevokeSessionAction                       = "auth:RevokeSession"
+	SimulateAuthorizationAction               = "auth:SimulateAuthorization"
+	CreateOrganizationAction                  = "auth:CreateOrganization"
+	ReadOrganizationAction                    = "auth:ReadOrganization"
+	UpdateOrganizationAction                  = "auth:UpdateOrganization"
+	DeleteOrganizationAction                  = "auth:DeleteOrganization"
+	ListOrganizationsAction                   = "auth:ListOrganizations"
+	AddOrganizationMemberAction               = "auth:AddOrganizationMember"
+	RemoveOrganizationMemberAction            = "auth:RemoveOrganizationMember"
 	ReadActionsAction                         = "ci:ReadAction"
 	PrepareGarbageCollectionCommitsAction     = "retention:PrepareGarbageCollectionCommits"
 	GetGarbageCollectionRulesAction           = "retention:GetGarbageCollectionRules"
 	SetGarbageCollectionRulesAction           = "retention:SetGarbageCollectionRules"
 	PrepareGarbageCollectionUncommittedAction = "retention:PrepareGarbageCollectionUncommitted"
+	GetLegalHoldsAction                       = "retention:GetLegalHolds"
+	SetLegalHoldsAction                       = "retention:SetLegalHolds"
+	GetLifecycleRulesAction                   = "retention:GetLifecycleRules"
+	SetLifecycleRulesAction                   = "retention:SetLifecycleRules"
+	GetObjectLockConfigurationAction          = "retention:GetObjectLockConfiguration"
+	SetObjectLockConfigurationAction          = "retention:SetObjectLockConfiguration"
+	SetObjectRetentionAction                  = "retention:SetObjectRetention"
+	BypassGovernanceRetentionAction           = "retention:BypassGovernanceRetention"
 	GetBranchProtectionRulesAction            = "branches:GetBranchProtectionRules"
 	SetBranchProtectionRulesAction            = "branches:SetBranchProtectionRules"
+	LockBranchAction                          = "branches:LockBranch"
 )
 
 var serviceSet = map[string]struct{}{
@@ -5,11 +5,17 @@ const (
 	authArnPrefix = "arn:lakefs:auth:::"
 
 	All = "*"
+
+	// ObjectTagConditionKeyPrefix prefixes the condition keys matching the tags of the object a permission applies to
+	ObjectTagConditionKeyPrefix = "lakefs:ObjectTag/"
 )
 
 type Permission struct {
 	Action   string
 	Resource string
+	// ConditionValues returns the values of the condition keys of the request, matched by policy statements with
+	// conditions. It is called only when such a statement applies, and may be nil if the request has no condition keys.
+	ConditionValues func() (map[string]string, error)
 }
 
 type NodeType int
@@ -26,6 +32,35 @@ type Node struct {
 	Nodes      []Node
 }
 
+// WithConditionValues returns a copy of node with the condition values of the permissions on resource set to
+// conditionValues. Permissions whose condition values are already set keep them.
+func (n Node) WithConditionValues(resource string, conditionValues func() (map[string]string, error)) Node {
+	return n.withConditionValues(func(p Permission) bool {
+		return p.Resource == resource
+	}, conditionValues)
+}
+
+// WithActionConditionValues is like WithConditionValues, limited to the permissions of action on resource
+func (n Node) WithActionConditionValues(action, resource string, conditionValues func() (map[string]string, error)) Node {
+	return n.withConditionValues(func(p Permission) bool {
+		return p.Action == action && p.Resource == resource
+	}, conditionValues)
+}
+
+func (n Node) withConditionValues(match func(Permission) bool, conditionValues func() (map[string]string, error)) Node {
+	if n.Permission.ConditionValues == nil && match(n.Permission) {
+		n.Permission.ConditionValues = conditionValues
+	}
+	if len(n.Nodes) > 0 {
+		nodes := make([]Node, len(n.Nodes))
+		for i, node := range n.Nodes {
+			nodes[i] = node.withConditionValues(match, conditionValues)
+		}
+		n.Nodes = nodes
+	}
+	return n
+}
+
 func RepoArn(repoID string) string {
 	return fsArnPrefix + "repository/" + repoID
 }
@@ -57,3 +92,33 @@ func GroupArn(groupID string) string {
 func PolicyArn(policyID string) string {
 	return authArnPrefix + "policy/" + policyID
 }
+
+func OrganizationArn(organizationID string) string {
+	return authArnPrefix + "organization/" + organizationID
+}
+
+// ObjectTagsConditionValues returns a ConditionValues function returning the condition keys of the tags returned by
+// getTags. getTags is called at most once.
+func ObjectTagsConditionValues(getTags func() (map[string]string, error)) func() (map[string]string, error) {
+	var (
+		values map[string]string
+		err    error
+		done   bool
+	)
+	return func() (map[string]string, error) {
+		if done {
+			return values, err
+		}
+		done = true
+		var tags map[string]string
+		tags, err = getTags()
+		if err != nil {
+			return nil, err
+		}
+		values = make(map[string]string, len(tags))
+		for k, v := range tags {
+			values[ObjectTagConditionKeyPrefix+k] = v
+		}
+		return values, nil
+	}
+}
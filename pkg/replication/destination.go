@@ -0,0 +1,193 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/securityprovider"
+	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/version"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// DestinationRepository is a repository of the destination installation
+type DestinationRepository struct {
+	DefaultBranch string
+	ReadOnly      bool
+}
+
+// DestinationCommit is a commit of the destination installation
+type DestinationCommit struct {
+	ID       string
+	Parents  []string
+	Metadata map[string]string
+}
+
+// Destination is the lakeFS installation repositories are replicated to. All writes are forced, as replica
+// repositories are read-only.
+type Destination interface {
+	// GetRepository returns repository, ErrNotFound if it does not exist
+	GetRepository(ctx context.Context, repository string) (*DestinationRepository, error)
+	// GetBranchHead returns the head commit of branch, ErrNotFound if the branch does not exist
+	GetBranchHead(ctx context.Context, repository, branch string) (*DestinationCommit, error)
+	CreateBranch(ctx context.Context, repository, branch, source string) error
+	// ResetBranch discards the uncommitted changes of branch, left by a replication that did not complete
+	ResetBranch(ctx context.Context, repository, branch string) error
+	// UploadObject writes contents as the object of entry, with the content type and user metadata of entry
+	UploadObject(ctx context.Context, repository, branch string, entry *catalog.DBEntry, contents io.Reader) error
+	DeleteObject(ctx context.Context, repository, branch, path string) error
+	Commit(ctx context.Context, repository, branch, message string, metadata map[string]string, date time.Time) error
+}
+
+// APIDestination is a Destination accessed through the lakeFS API
+type APIDestination struct {
+	client apigen.ClientWithResponsesInterface
+}
+
+func NewAPIDestination(endpointURL, accessKeyID, secretAccessKey string) (*APIDestination, error) {
+	basicAuthProvider, err := securityprovider.NewSecurityProviderBasicAuth(accessKeyID, secretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+	serverEndpoint, err := apiutil.NormalizeLakeFSEndpoint(endpointURL)
+	if err != nil {
+		return nil, err
+	}
+	client, err := apigen.NewClientWithResponses(
+		serverEndpoint,
+		apigen.WithRequestEditorFn(basicAuthProvider.Intercept),
+		apigen.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("User-Agent", "lakefs-replication/"+version.Version)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &APIDestination{client: client}, nil
+}
+
+// responseError returns the error of an unsuccessful response, ErrNotFound when the status is not found
+func responseError(resp interface{ StatusCode() int }) error {
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, helpers.ResponseAsError(resp))
+	}
+	return helpers.ResponseAsError(resp)
+}
+
+func (d *APIDestination) GetRepository(ctx context.Context, repository string) (*DestinationRepository, error) {
+	resp, err := d.client.GetRepositoryWithResponse(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, responseError(resp)
+	}
+	return &DestinationRepository{
+		DefaultBranch: resp.JSON200.DefaultBranch,
+		ReadOnly:      swag.BoolValue(resp.JSON200.ReadOnly),
+	}, nil
+}
+
+func (d *APIDestination) GetBranchHead(ctx context.Context, repository, branch string) (*DestinationCommit, error) {
+	branchResp, err := d.client.GetBranchWithResponse(ctx, repository, branch)
+	if err != nil {
+		return nil, err
+	}
+	if branchResp.JSON200 == nil {
+		return nil, responseError(branchResp)
+	}
+	commitResp, err := d.client.GetCommitWithResponse(ctx, repository, branchResp.JSON200.CommitId)
+	if err != nil {
+		return nil, err
+	}
+	if commitResp.JSON200 == nil {
+		return nil, responseError(commitResp)
+	}
+	commit := &DestinationCommit{
+		ID:      commitResp.JSON200.Id,
+		Parents: commitResp.JSON200.Parents,
+	}
+	if commitResp.JSON200.Metadata != nil {
+		commit.Metadata = commitResp.JSON200.Metadata.AdditionalProperties
+	}
+	return commit, nil
+}
+
+func (d *APIDestination) CreateBranch(ctx context.Context, repository, branch, source string) error {
+	resp, err := d.client.CreateBranchWithResponse(ctx, repository, apigen.CreateBranchJSONRequestBody{
+		Name:   branch,
+		Source: source,
+		Force:  swag.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusCreated {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (d *APIDestination) ResetBranch(ctx context.Context, repository, branch string) error {
+	resp, err := d.client.ResetBranchWithResponse(ctx, repository, branch, apigen.ResetBranchJSONRequestBody{
+		Type:  "reset",
+		Force: swag.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (d *APIDestination) UploadObject(ctx context.Context, repository, branch string, entry *catalog.DBEntry, contents io.Reader) error {
+	_, err := helpers.ClientUploadWithParams(ctx, d.client, repository, branch, &apigen.UploadObjectParams{
+		Path:  entry.Path,
+		Force: swag.Bool(true),
+	}, entry.Metadata, entry.ContentType, contents)
+	return err
+}
+
+func (d *APIDestination) DeleteObject(ctx context.Context, repository, branch, path string) error {
+	resp, err := d.client.DeleteObjectWithResponse(ctx, repository, branch, &apigen.DeleteObjectParams{
+		Path:  path,
+		Force: swag.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	// an object missing on the destination is already deleted
+	if resp.StatusCode() != http.StatusNoContent && resp.StatusCode() != http.StatusNotFound {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (d *APIDestination) Commit(ctx context.Context, repository, branch, message string, metadata map[string]string, date time.Time) error {
+	resp, err := d.client.CommitWithResponse(ctx, repository, branch, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		Message:    message,
+		Metadata:   &apigen.CommitCreation_Metadata{AdditionalProperties: metadata},
+		Date:       swag.Int64(date.Unix()),
+		AllowEmpty: swag.Bool(true),
+		Force:      swag.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.JSON201 == nil {
+		return responseError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package replication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	lagSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "replication_lag_seconds",
+		Help: "Age of the oldest commit of the branch not replicated yet, zero when the branch is replicated",
+	}, []string{"repository", "branch"})
+
+	lastSuccessTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "replication_last_success_timestamp_seconds",
+		Help: "Time the branch was last found fully replicated",
+	}, []string{"repository", "branch"})
+
+	replicatedCommits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "replication_commits_total",
+		Help: "The total number of commits replicated",
+	}, []string{"repository"})
+
+	replicatedObjects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "replication_objects_total",
+		Help: "The total number of objects written or deleted on the destination",
+	}, []string{"repository", "operation"})
+
+	replicationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "replication_errors_total",
+		Help: "The total number of failed replications of branches",
+	}, []string{"repository"})
+)
@@ -0,0 +1,324 @@
+// Package replication replicates the commits and objects of repositories to read-only replica repositories of a
+// lakeFS installation in another region, which are promoted to take over writes on failover.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv/lease"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// MetadataKeySourceCommit is the metadata key of replicated commits holding the ID of the commit replicated
+	MetadataKeySourceCommit = "::lakefs::replication::source_commit"
+	// MetadataKeySourceCommitter is the metadata key of replicated commits holding the committer of the commit
+	// replicated, as replicated commits are committed by the replication user
+	MetadataKeySourceCommitter = "::lakefs::replication::source_committer"
+)
+
+var (
+	ErrBadConfig = errors.New("invalid replication configuration")
+	// ErrNotReplica is returned when the destination repository is not read-only, as it is once promoted
+	ErrNotReplica = errors.New("destination repository is not a read-only replica")
+	// ErrDiverged is returned when the head of a destination branch was not replicated
+	ErrDiverged = errors.New("destination branch diverged")
+)
+
+// Service replicates the commits of every branch of the configured repositories to the repositories of the same name
+// of a Destination. Every replicated repository is replicated by a single lakeFS instance at a time.
+type Service struct {
+	cfg         config.Replication
+	catalog     *catalog.Catalog
+	destination Destination
+	logger      logging.Logger
+}
+
+// NewService returns a Service replicating to destination. Replicate is run every configured interval as a
+// background job.
+func NewService(cfg config.Replication, c *catalog.Catalog, destination Destination, logger logging.Logger) (*Service, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return &Service{
+		cfg:         cfg,
+		catalog:     c,
+		destination: destination,
+		logger:      logger,
+	}, nil
+}
+
+func validateConfig(cfg config.Replication) error {
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval must be positive: %w", ErrBadConfig)
+	}
+	if cfg.MaxCommits <= 0 {
+		return fmt.Errorf("max commits must be positive: %w", ErrBadConfig)
+	}
+	if cfg.Destination.EndpointURL == "" {
+		return fmt.Errorf("missing destination endpoint: %w", ErrBadConfig)
+	}
+	return nil
+}
+
+// Replicate replicates the configured repositories, returning the errors of the repositories that failed
+func (s *Service) Replicate(ctx context.Context) error {
+	var errs []error
+	for _, repository := range s.cfg.Repositories {
+		if err := s.ReplicateRepository(ctx, repository); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.WithError(err).WithField("repository", repository).Error("Failed to replicate repository")
+			errs = append(errs, fmt.Errorf("repository %s: %w", repository, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReplicateRepository replicates the commits of the branches of repository not replicated yet. It does nothing if
+// another lakeFS instance is replicating the repository.
+func (s *Service) ReplicateRepository(ctx context.Context, repository string) error {
+	replicationLease, err := s.catalog.AcquireReplicationLease(ctx, repository)
+	if errors.Is(err, lease.ErrLeaseHeld) {
+		s.logger.WithField("repository", repository).WithError(err).Debug("Repository replicated by another instance")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := replicationLease.Release(context.WithoutCancel(ctx)); err != nil {
+			s.logger.WithError(err).Warn("Failed to release replication lease")
+		}
+	}()
+	ctx = replicationLease.Keep(ctx)
+
+	destinationRepository, err := s.destination.GetRepository(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("destination repository: %w", err)
+	}
+	if !destinationRepository.ReadOnly {
+		return fmt.Errorf("repository %s: %w", repository, ErrNotReplica)
+	}
+	repo, err := s.catalog.GetRepository(ctx, repository)
+	if err != nil {
+		return err
+	}
+	ctx, err = s.catalog.WithBlockAdapterOverride(ctx, repository)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	after := ""
+	for {
+		branches, hasMore, err := s.catalog.ListBranches(ctx, repository, "", catalog.ListBranchesLimitMax, after)
+		if err != nil {
+			return err
+		}
+		for _, branch := range branches {
+			if err := s.replicateBranch(ctx, repo, destinationRepository, branch); err != nil {
+				replicationErrors.WithLabelValues(repository).Inc()
+				errs = append(errs, fmt.Errorf("branch %s: %w", branch.Name, err))
+			}
+		}
+		if !hasMore || len(branches) == 0 {
+			break
+		}
+		after = branches[len(branches)-1].Name
+	}
+	return errors.Join(errs...)
+}
+
+// replicatedCommit returns the ID of the source commit replicated as commit, empty for the parentless initial
+// commit of the destination repository
+func replicatedCommit(commit *DestinationCommit) (string, error) {
+	if id, ok := commit.Metadata[MetadataKeySourceCommit]; ok {
+		return id, nil
+	}
+	if len(commit.Parents) == 0 {
+		return "", nil
+	}
+	return "", fmt.Errorf("commit %s not replicated: %w", commit.ID, ErrDiverged)
+}
+
+func (s *Service) replicateBranch(ctx context.Context, repo *catalog.Repository, destinationRepository *DestinationRepository, branch *catalog.Branch) error {
+	log := s.logger.WithFields(logging.Fields{"repository": repo.Name, "branch": branch.Name})
+	destinationHead, err := s.destination.GetBranchHead(ctx, repo.Name, branch.Name)
+	if errors.Is(err, ErrNotFound) {
+		// new branches start from the default branch, their commits are replicated relative to it
+		destinationHead, err = s.destination.GetBranchHead(ctx, repo.Name, destinationRepository.DefaultBranch)
+		if err != nil {
+			return fmt.Errorf("destination default branch: %w", err)
+		}
+		if err := s.destination.CreateBranch(ctx, repo.Name, branch.Name, destinationHead.ID); err != nil {
+			return fmt.Errorf("create destination branch: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("destination branch: %w", err)
+	}
+	base, err := replicatedCommit(destinationHead)
+	if err != nil {
+		return err
+	}
+	if base == branch.Reference {
+		lagSeconds.WithLabelValues(repo.Name, branch.Name).Set(0)
+		lastSuccessTimestamp.WithLabelValues(repo.Name, branch.Name).SetToCurrentTime()
+		return nil
+	}
+
+	commits, squash, err := s.pendingCommits(ctx, repo.Name, branch.Name, base)
+	if err != nil {
+		return err
+	}
+	if len(commits) > 0 {
+		s.setLag(log, repo.Name, branch.Name, time.Since(commits[0].CreationDate))
+	}
+	if err := s.destination.ResetBranch(ctx, repo.Name, branch.Name); err != nil {
+		return fmt.Errorf("reset destination branch: %w", err)
+	}
+	if squash {
+		// too many commits, or the branch history was rewritten: replicate the head as a single commit
+		head, err := s.catalog.GetCommit(ctx, repo.Name, branch.Reference)
+		if err != nil {
+			return err
+		}
+		log.WithFields(logging.Fields{"base": base, "head": head.Reference}).Info("Replicating branch changes as a single commit")
+		commits = []*catalog.CommitLog{head}
+	}
+	from := base
+	for i, commit := range commits {
+		if err := s.applyChanges(ctx, repo, branch.Name, from, commit.Reference); err != nil {
+			return err
+		}
+		metadata := maps.Clone(commit.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[MetadataKeySourceCommit] = commit.Reference
+		metadata[MetadataKeySourceCommitter] = commit.Committer
+		if err := s.destination.Commit(ctx, repo.Name, branch.Name, commit.Message, metadata, commit.CreationDate); err != nil {
+			return fmt.Errorf("commit %s: %w", commit.Reference, err)
+		}
+		replicatedCommits.WithLabelValues(repo.Name).Inc()
+		if i+1 < len(commits) {
+			s.setLag(log, repo.Name, branch.Name, time.Since(commits[i+1].CreationDate))
+		}
+		from = commit.Reference
+	}
+	lagSeconds.WithLabelValues(repo.Name, branch.Name).Set(0)
+	lastSuccessTimestamp.WithLabelValues(repo.Name, branch.Name).SetToCurrentTime()
+	log.WithField("commits", len(commits)).Debug("Replicated branch")
+	return nil
+}
+
+// setLag reports the replication lag of branch, warning when it exceeds the configured max lag
+func (s *Service) setLag(log logging.Logger, repository, branch string, lag time.Duration) {
+	lagSeconds.WithLabelValues(repository, branch).Set(lag.Seconds())
+	if s.cfg.MaxLag > 0 && lag > s.cfg.MaxLag {
+		log.WithFields(logging.Fields{"lag": lag, "max_lag": s.cfg.MaxLag}).Warn("Replication lag exceeds max lag")
+	}
+}
+
+// pendingCommits returns the first-parent commits of branch following base, oldest first. It returns squash when
+// base is not found within the configured max commits, together with the commits found.
+func (s *Service) pendingCommits(ctx context.Context, repository, branch, base string) ([]*catalog.CommitLog, bool, error) {
+	commits, hasMore, err := s.catalog.ListCommits(ctx, repository, branch, catalog.LogParams{
+		Amount:      s.cfg.MaxCommits + 1,
+		FirstParent: true,
+		StopAt:      base,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	found := false
+	switch {
+	case base == "":
+		found = !hasMore
+	case len(commits) > 0 && commits[len(commits)-1].Reference == base:
+		found = true
+		commits = commits[:len(commits)-1]
+	}
+	if len(commits) > s.cfg.MaxCommits {
+		commits = commits[:s.cfg.MaxCommits]
+		found = false
+	}
+	// oldest first
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, !found, nil
+}
+
+// applyChanges writes the changes between the from and to commits to branch of the destination. All objects of to
+// are written when from is empty.
+func (s *Service) applyChanges(ctx context.Context, repo *catalog.Repository, branch, from, to string) error {
+	after := ""
+	for {
+		if from == "" {
+			entries, hasMore, err := s.catalog.ListEntries(ctx, repo.Name, to, "", after, "", catalog.ListEntriesLimitMax)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if err := s.uploadObject(ctx, repo, branch, entry); err != nil {
+					return err
+				}
+			}
+			if !hasMore || len(entries) == 0 {
+				return nil
+			}
+			after = entries[len(entries)-1].Path
+			continue
+		}
+
+		diffs, hasMore, err := s.catalog.Diff(ctx, repo.Name, from, to, catalog.DiffParams{Limit: catalog.DiffLimitMax, After: after})
+		if err != nil {
+			return err
+		}
+		for _, diff := range diffs {
+			switch diff.Type {
+			case catalog.DifferenceTypeAdded, catalog.DifferenceTypeChanged:
+				entry := diff.DBEntry
+				if err := s.uploadObject(ctx, repo, branch, &entry); err != nil {
+					return err
+				}
+			case catalog.DifferenceTypeRemoved:
+				if err := s.destination.DeleteObject(ctx, repo.Name, branch, diff.Path); err != nil {
+					return fmt.Errorf("delete %s: %w", diff.Path, err)
+				}
+				replicatedObjects.WithLabelValues(repo.Name, "delete").Inc()
+			}
+		}
+		if !hasMore || len(diffs) == 0 {
+			return nil
+		}
+		after = diffs[len(diffs)-1].Path
+	}
+}
+
+// uploadObject copies the data of entry from the storage namespace of repo to branch of the destination
+func (s *Service) uploadObject(ctx context.Context, repo *catalog.Repository, branch string, entry *catalog.DBEntry) error {
+	reader, err := s.catalog.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}, entry.Size)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", entry.Path, err)
+	}
+	defer func() { _ = reader.Close() }()
+	if err := s.destination.UploadObject(ctx, repo.Name, branch, entry, reader); err != nil {
+		return fmt.Errorf("upload %s: %w", entry.Path, err)
+	}
+	replicatedObjects.WithLabelValues(repo.Name, "upload").Inc()
+	return nil
+}
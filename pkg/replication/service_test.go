@@ -0,0 +1,291 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+const (
+	repoName         = "repo"
+	storageNamespace = "mem://repo"
+)
+
+type fakeBranch struct {
+	head      *DestinationCommit
+	committed map[string]string
+	staged    map[string]string
+}
+
+// fakeDestination is an in-memory Destination holding a single repository
+type fakeDestination struct {
+	mu       sync.Mutex
+	repo     DestinationRepository
+	branches map[string]*fakeBranch
+	commits  []*DestinationCommit
+	nextID   int
+}
+
+func newFakeDestination() *fakeDestination {
+	d := &fakeDestination{
+		repo:     DestinationRepository{DefaultBranch: "main", ReadOnly: true},
+		branches: make(map[string]*fakeBranch),
+	}
+	d.branches["main"] = &fakeBranch{
+		head:      d.newCommit(nil, nil),
+		committed: map[string]string{},
+		staged:    map[string]string{},
+	}
+	return d
+}
+
+func (d *fakeDestination) newCommit(parents []string, metadata map[string]string) *DestinationCommit {
+	d.nextID++
+	commit := &DestinationCommit{ID: fmt.Sprintf("c%d", d.nextID), Parents: parents, Metadata: metadata}
+	d.commits = append(d.commits, commit)
+	return commit
+}
+
+func (d *fakeDestination) GetRepository(_ context.Context, _ string) (*DestinationRepository, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	repo := d.repo
+	return &repo, nil
+}
+
+func (d *fakeDestination) branch(name string) (*fakeBranch, error) {
+	b, ok := d.branches[name]
+	if !ok {
+		return nil, fmt.Errorf("branch %s: %w", name, ErrNotFound)
+	}
+	return b, nil
+}
+
+func (d *fakeDestination) GetBranchHead(_ context.Context, _, branch string) (*DestinationCommit, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	if err != nil {
+		return nil, err
+	}
+	return b.head, nil
+}
+
+func (d *fakeDestination) CreateBranch(_ context.Context, _, branch, source string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, b := range d.branches {
+		if b.head.ID == source {
+			d.branches[branch] = &fakeBranch{head: b.head, committed: maps.Clone(b.committed), staged: maps.Clone(b.committed)}
+			return nil
+		}
+	}
+	return fmt.Errorf("source %s: %w", source, ErrNotFound)
+}
+
+func (d *fakeDestination) ResetBranch(_ context.Context, _, branch string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	if err != nil {
+		return err
+	}
+	b.staged = maps.Clone(b.committed)
+	return nil
+}
+
+func (d *fakeDestination) UploadObject(_ context.Context, _, branch string, entry *catalog.DBEntry, contents io.Reader) error {
+	data, err := io.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	if err != nil {
+		return err
+	}
+	b.staged[entry.Path] = string(data)
+	return nil
+}
+
+func (d *fakeDestination) DeleteObject(_ context.Context, _, branch, path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	if err != nil {
+		return err
+	}
+	delete(b.staged, path)
+	return nil
+}
+
+func (d *fakeDestination) Commit(_ context.Context, _, branch, _ string, metadata map[string]string, _ time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	if err != nil {
+		return err
+	}
+	b.head = d.newCommit([]string{b.head.ID}, metadata)
+	b.committed = maps.Clone(b.staged)
+	return nil
+}
+
+// objects returns the committed objects of branch
+func (d *fakeDestination) objects(t *testing.T, branch string) map[string]string {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	require.NoError(t, err)
+	return maps.Clone(b.committed)
+}
+
+func (d *fakeDestination) headSource(t *testing.T, branch string) string {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := d.branch(branch)
+	require.NoError(t, err)
+	return b.head.Metadata[MetadataKeySourceCommit]
+}
+
+func setupCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	ctx := context.Background()
+	viper.Set(config.BlockstoreTypeKey, block.BlockstoreTypeMem)
+	cfg, err := config.NewConfig("")
+	testutil.MustDo(t, "config", err)
+	c, err := catalog.New(ctx, catalog.Config{
+		Config:       cfg,
+		KVStore:      kvtest.GetStore(ctx, t),
+		PathProvider: upload.DefaultPathProvider,
+	})
+	testutil.MustDo(t, "build catalog", err)
+	t.Cleanup(func() { _ = c.Close() })
+	_, err = c.CreateRepository(ctx, repoName, storageNamespace, "main", false)
+	testutil.MustDo(t, "create repository", err)
+	return c
+}
+
+func newTestService(c *catalog.Catalog, destination Destination, maxCommits int) *Service {
+	return &Service{
+		cfg:         config.Replication{MaxCommits: maxCommits},
+		catalog:     c,
+		destination: destination,
+		logger:      logging.ContextUnavailable(),
+	}
+}
+
+func writeObject(t *testing.T, c *catalog.Catalog, branch, path, data string) {
+	t.Helper()
+	ctx := context.Background()
+	address := "data/" + strings.ReplaceAll(branch+"/"+path, "/", "_")
+	err := c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
+	testutil.MustDo(t, "put object", err)
+	err = c.CreateEntry(ctx, repoName, branch, catalog.DBEntry{
+		Path:            path,
+		PhysicalAddress: address,
+		AddressType:     catalog.AddressTypeRelative,
+		Size:            int64(len(data)),
+		Checksum:        address,
+		CreationDate:    time.Now(),
+	})
+	testutil.MustDo(t, "create entry", err)
+}
+
+func commit(t *testing.T, c *catalog.Catalog, branch, message string) string {
+	t.Helper()
+	commitLog, err := c.Commit(context.Background(), repoName, branch, message, "tester", nil, nil, nil, false)
+	testutil.MustDo(t, "commit", err)
+	return commitLog.Reference
+}
+
+func TestService_ReplicateRepository(t *testing.T) {
+	ctx := context.Background()
+	c := setupCatalog(t)
+	destination := newFakeDestination()
+	s := newTestService(c, destination, 100)
+
+	writeObject(t, c, "main", "a", "data a")
+	writeObject(t, c, "main", "b", "data b")
+	first := commit(t, c, "main", "first")
+	require.NoError(t, s.ReplicateRepository(ctx, repoName))
+	require.Equal(t, map[string]string{"a": "data a", "b": "data b"}, destination.objects(t, "main"))
+	require.Equal(t, first, destination.headSource(t, "main"))
+
+	// nothing to replicate
+	commits := len(destination.commits)
+	require.NoError(t, s.ReplicateRepository(ctx, repoName))
+	require.Len(t, destination.commits, commits)
+
+	// deletes, changes and new branches are replicated
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repoName, "main", "a"))
+	writeObject(t, c, "main", "b", "data b2")
+	second := commit(t, c, "main", "second")
+	_, err := c.CreateBranch(ctx, repoName, "feature", "main")
+	testutil.MustDo(t, "create branch", err)
+	writeObject(t, c, "feature", "c", "data c")
+	feature := commit(t, c, "feature", "feature")
+
+	require.NoError(t, s.ReplicateRepository(ctx, repoName))
+	require.Equal(t, map[string]string{"b": "data b2"}, destination.objects(t, "main"))
+	require.Equal(t, second, destination.headSource(t, "main"))
+	require.Equal(t, map[string]string{"b": "data b2", "c": "data c"}, destination.objects(t, "feature"))
+	require.Equal(t, feature, destination.headSource(t, "feature"))
+}
+
+func TestService_ReplicateRepositorySquash(t *testing.T) {
+	ctx := context.Background()
+	c := setupCatalog(t)
+	destination := newFakeDestination()
+	s := newTestService(c, destination, 2)
+
+	var head string
+	for i := 0; i < 3; i++ {
+		writeObject(t, c, "main", fmt.Sprintf("obj%d", i), fmt.Sprintf("data %d", i))
+		head = commit(t, c, "main", fmt.Sprintf("commit %d", i))
+	}
+	require.NoError(t, s.ReplicateRepository(ctx, repoName))
+	require.Equal(t, map[string]string{"obj0": "data 0", "obj1": "data 1", "obj2": "data 2"}, destination.objects(t, "main"))
+	require.Equal(t, head, destination.headSource(t, "main"))
+	// initial commit and a single replicated commit
+	require.Len(t, destination.commits, 2)
+}
+
+func TestService_ReplicateRepositoryNotReplica(t *testing.T) {
+	c := setupCatalog(t)
+	destination := newFakeDestination()
+	destination.repo.ReadOnly = false
+	s := newTestService(c, destination, 100)
+	require.ErrorIs(t, s.ReplicateRepository(context.Background(), repoName), ErrNotReplica)
+}
+
+func TestService_ReplicateRepositoryDiverged(t *testing.T) {
+	c := setupCatalog(t)
+	destination := newFakeDestination()
+	main := destination.branches["main"]
+	main.head = destination.newCommit([]string{main.head.ID}, nil)
+	s := newTestService(c, destination, 100)
+	require.ErrorIs(t, s.ReplicateRepository(context.Background(), repoName), ErrDiverged)
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+// QueryParams are the parameters of a search
+type QueryParams struct {
+	// Query holds the words to search. Every word must begin a word of the path or the metadata of a result.
+	Query string
+	// Repository limits the results to a repository, when set
+	Repository string
+	// Branch limits the results to branches of this name, when set
+	Branch string
+	// After is the position returned by the previous page of results
+	After string
+	Limit int
+	// Allowed reports whether results of the repository may be returned, all repositories are allowed when nil
+	Allowed func(repository string) bool
+}
+
+// Query returns up to limit indexed objects matching the query, ordered by the first matching word, and the position
+// to continue the search after. The position is empty when there are no more results. A single query scans at most
+// the configured max scan index entries, and then returns the position to continue scanning after.
+func (s *Service) Query(ctx context.Context, params QueryParams) ([]*DocumentData, string, error) {
+	terms := Tokenize(params.Query)
+	if len(terms) == 0 {
+		return nil, "", ErrInvalidQuery
+	}
+	// scan the index by the longest word, it is likely the one with the fewest entries
+	scan := terms[0]
+	for _, term := range terms[1:] {
+		if len(term) > len(scan) {
+			scan = term
+		}
+	}
+	it, err := kv.NewSecondaryIterator(ctx, s.store, (&DocumentData{}).ProtoReflect().Type(), partition,
+		[]byte(kv.FormatPath(tokensPrefix, scan)), []byte(params.After))
+	if err != nil {
+		return nil, "", err
+	}
+	defer it.Close()
+
+	var (
+		results []*DocumentData
+		last    string
+		scanned int
+	)
+	for it.Next() {
+		entry := it.Entry()
+		key := string(entry.Key)
+		doc := entry.Value.(*DocumentData)
+		if !s.matches(params, terms, scan, key, doc) {
+			scanned++
+			if scanned >= s.cfg.MaxScan {
+				return results, key, nil
+			}
+			continue
+		}
+		if len(results) == params.Limit {
+			return results, last, nil
+		}
+		results = append(results, doc)
+		last = key
+		scanned++
+		if scanned >= s.cfg.MaxScan {
+			return results, key, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+	return results, "", nil
+}
+
+// matches reports whether doc, found by the index entry at key of a word beginning with scan, is a result of the
+// query. A document is indexed by every word beginning with scan, it is only a result for the first of them.
+func (s *Service) matches(params QueryParams, terms []string, scan, key string, doc *DocumentData) bool {
+	if params.Repository != "" && doc.Repository != params.Repository {
+		return false
+	}
+	if params.Branch != "" && doc.Branch != params.Branch {
+		return false
+	}
+	token := strings.TrimPrefix(key, tokensPrefix+kv.PathDelimiter)
+	token, _, _ = strings.Cut(token, kv.PathDelimiter)
+	if first, ok := firstWithPrefix(doc.Tokens, scan); !ok || first != token {
+		return false
+	}
+	for _, term := range terms {
+		if _, ok := firstWithPrefix(doc.Tokens, term); !ok {
+			return false
+		}
+	}
+	return params.Allowed == nil || params.Allowed(doc.Repository)
+}
+
+// firstWithPrefix returns the first of the sorted tokens beginning with prefix
+func firstWithPrefix(tokens []string, prefix string) (string, bool) {
+	for _, token := range tokens {
+		if strings.HasPrefix(token, prefix) {
+			return token, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,452 @@
+// Package search indexes the paths and user metadata of the objects committed on branches, so that objects can be
+// found by fragments of their names across repositories and branches.
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	partition = "search"
+
+	branchesPrefix  = "branches"
+	documentsPrefix = "documents"
+	tokensPrefix    = "tokens"
+
+	// MinTokenLength is the length of the shortest word indexed and searched
+	MinTokenLength = 2
+	// maxTokenLength truncates longer words, they are still found by their beginning
+	maxTokenLength = 64
+	// maxDocumentTokens bounds the words an object is indexed by
+	maxDocumentTokens = 128
+
+	listAmount = 1000
+
+	// eventTypeDeleteRepository queues dropping the index of a deleted repository. Repository deletion is not a
+	// graveler hook event.
+	eventTypeDeleteRepository graveler.EventType = "delete-repository"
+)
+
+var (
+	ErrBadConfig = errors.New("invalid search configuration")
+	// ErrInvalidQuery is returned for queries without any word to search
+	ErrInvalidQuery = fmt.Errorf("search query: %w", graveler.ErrInvalidValue)
+)
+
+// Catalog is the part of the catalog read to index branches
+type Catalog interface {
+	Diff(ctx context.Context, repositoryID string, leftReference string, rightReference string, params catalog.DiffParams) (catalog.Differences, bool, error)
+	ListEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int) ([]*catalog.DBEntry, bool, error)
+}
+
+// Service wraps a graveler.HooksHandler and indexes the objects of the configured branches after every commit and
+// merge, in the background. The index of a branch is updated by the diff from its last indexed commit, also when it
+// missed commits, e.g. when the branch was reset or events were dropped. It is built from all the objects of the
+// branch only when the branch is created or its last indexed commit no longer exists. Any hook is passed as is to
+// the wrapped handler.
+type Service struct {
+	graveler.HooksHandler
+	cfg     config.Search
+	store   kv.Store
+	catalog Catalog
+	logger  logging.Logger
+
+	// mu guards closing the queue while events are queued
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan graveler.HookRecord
+	wg      sync.WaitGroup
+}
+
+// NewService returns a Service wrapping hooks, and starts indexing. Stop must be called to release the service.
+func NewService(cfg config.Search, hooks graveler.HooksHandler, store kv.Store, c Catalog, logger logging.Logger) (*Service, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	s := &Service{
+		HooksHandler: hooks,
+		cfg:          cfg,
+		store:        store,
+		catalog:      c,
+		logger:       logger,
+		queue:        make(chan graveler.HookRecord, cfg.QueueSize),
+	}
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+func validateConfig(cfg config.Search) error {
+	if cfg.QueueSize <= 0 {
+		return fmt.Errorf("queue size must be positive: %w", ErrBadConfig)
+	}
+	if cfg.MaxScan <= 0 {
+		return fmt.Errorf("max scan must be positive: %w", ErrBadConfig)
+	}
+	for _, pattern := range append(append([]string{}, cfg.Repositories...), cfg.Branches...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, ErrBadConfig)
+		}
+	}
+	return nil
+}
+
+func (s *Service) PostCommitHook(ctx context.Context, record graveler.HookRecord) error {
+	s.enqueue(record)
+	return s.HooksHandler.PostCommitHook(ctx, record)
+}
+
+func (s *Service) PostMergeHook(ctx context.Context, record graveler.HookRecord) error {
+	s.enqueue(record)
+	return s.HooksHandler.PostMergeHook(ctx, record)
+}
+
+func (s *Service) PostCreateBranchHook(ctx context.Context, record graveler.HookRecord) {
+	s.enqueue(record)
+	s.HooksHandler.PostCreateBranchHook(ctx, record)
+}
+
+func (s *Service) PostDeleteBranchHook(ctx context.Context, record graveler.HookRecord) {
+	s.enqueue(record)
+	s.HooksHandler.PostDeleteBranchHook(ctx, record)
+}
+
+// DeleteRepository queues dropping the index of all branches of the deleted repository
+func (s *Service) DeleteRepository(repositoryID graveler.RepositoryID) {
+	if !matchesAny(s.cfg.Repositories, repositoryID.String()) {
+		return
+	}
+	s.push(graveler.HookRecord{EventType: eventTypeDeleteRepository, RepositoryID: repositoryID})
+}
+
+// enqueue queues the record to be indexed, unless its branch is not indexed or the queue is full
+func (s *Service) enqueue(record graveler.HookRecord) {
+	if !matchesAny(s.cfg.Repositories, record.RepositoryID.String()) || !matchesAny(s.cfg.Branches, record.BranchID.String()) {
+		return
+	}
+	s.push(record)
+}
+
+// push queues the record unless the queue is full
+func (s *Service) push(record graveler.HookRecord) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return
+	}
+	select {
+	case s.queue <- record:
+	default:
+		s.logger.WithFields(logging.Fields{
+			"repository": record.RepositoryID,
+			"branch":     record.BranchID,
+			"event_type": record.EventType,
+		}).Warn("Search index queue is full, dropping event")
+	}
+}
+
+// matchesAny reports whether name matches one of the glob patterns, or there are no patterns
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) loop() {
+	defer s.wg.Done()
+	for record := range s.queue {
+		if err := s.index(context.Background(), record); err != nil {
+			s.logger.WithError(err).WithFields(logging.Fields{
+				"repository": record.RepositoryID,
+				"branch":     record.BranchID,
+				"event_type": record.EventType,
+			}).Error("Failed to index branch for search")
+		}
+	}
+}
+
+// Stop indexes the queued events and stops indexing
+func (s *Service) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	close(s.queue)
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Service) index(ctx context.Context, record graveler.HookRecord) error {
+	repository := record.RepositoryID.String()
+	branch := record.BranchID.String()
+	switch record.EventType {
+	case eventTypeDeleteRepository:
+		return s.dropRepository(ctx, repository)
+	case graveler.EventTypePostDeleteBranch:
+		if err := s.dropBranch(ctx, repository, branch); err != nil {
+			return err
+		}
+		return s.store.Delete(ctx, []byte(partition), []byte(branchPath(repository, branch)))
+	case graveler.EventTypePostCreateBranch:
+		return s.reindexBranch(ctx, repository, branch, record.CommitID.String())
+	}
+
+	commitID := record.CommitID.String()
+	state := &BranchData{}
+	_, err := kv.GetMsg(ctx, s.store, partition, []byte(branchPath(repository, branch)), state)
+	if errors.Is(err, kv.ErrNotFound) {
+		return s.reindexBranch(ctx, repository, branch, commitID)
+	}
+	if err != nil {
+		return err
+	}
+	if state.CommitId == commitID {
+		return nil
+	}
+	// the diff from the last indexed commit also covers commits the index missed
+	err = s.updateBranch(ctx, repository, branch, state.CommitId, commitID)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return s.reindexBranch(ctx, repository, branch, commitID)
+	}
+	return err
+}
+
+// updateBranch indexes the diff between the indexed commit and commitID on the branch
+func (s *Service) updateBranch(ctx context.Context, repository, branch, indexedCommitID, commitID string) error {
+	after := ""
+	for {
+		diff, hasMore, err := s.catalog.Diff(ctx, repository, indexedCommitID, commitID, catalog.DiffParams{
+			Limit: listAmount,
+			After: after,
+		})
+		if err != nil {
+			return err
+		}
+		for _, d := range diff {
+			if d.Type == catalog.DifferenceTypeRemoved {
+				err = s.deleteDocument(ctx, repository, branch, d.Path)
+			} else {
+				err = s.setDocument(ctx, repository, branch, commitID, &d.DBEntry)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !hasMore || len(diff) == 0 {
+			break
+		}
+		after = diff[len(diff)-1].Path
+	}
+	return s.setBranch(ctx, repository, branch, commitID)
+}
+
+// reindexBranch drops the index of the branch and indexes all objects of commitID
+func (s *Service) reindexBranch(ctx context.Context, repository, branch, commitID string) error {
+	if err := s.dropBranch(ctx, repository, branch); err != nil {
+		return err
+	}
+	after := ""
+	for {
+		entries, hasMore, err := s.catalog.ListEntries(ctx, repository, commitID, "", after, "", listAmount)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := s.setDocument(ctx, repository, branch, commitID, entry); err != nil {
+				return err
+			}
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	return s.setBranch(ctx, repository, branch, commitID)
+}
+
+func (s *Service) setBranch(ctx context.Context, repository, branch, commitID string) error {
+	return kv.SetMsg(ctx, s.store, partition, []byte(branchPath(repository, branch)), &BranchData{
+		Repository: repository,
+		Branch:     branch,
+		CommitId:   commitID,
+		IndexedAt:  timestamppb.Now(),
+	})
+}
+
+// dropBranch deletes the documents of all objects of the branch
+func (s *Service) dropBranch(ctx context.Context, repository, branch string) error {
+	return s.dropDocuments(ctx, documentPath(repository, branch, ""))
+}
+
+// dropRepository deletes the documents and the index state of all branches of the repository
+func (s *Service) dropRepository(ctx context.Context, repository string) error {
+	if err := s.dropDocuments(ctx, kv.FormatPath(documentsPrefix, repository)+kv.PathDelimiter); err != nil {
+		return err
+	}
+	it, err := kv.NewPrimaryIterator(ctx, s.store, (&BranchData{}).ProtoReflect().Type(), partition,
+		[]byte(kv.FormatPath(branchesPrefix, repository)+kv.PathDelimiter), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := s.store.Delete(ctx, []byte(partition), it.Entry().Key); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// dropDocuments deletes the documents under the key prefix
+func (s *Service) dropDocuments(ctx context.Context, prefix string) error {
+	it, err := kv.NewPrimaryIterator(ctx, s.store, (&DocumentData{}).ProtoReflect().Type(), partition,
+		[]byte(prefix), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := s.deleteDocumentData(ctx, it.Entry().Value.(*DocumentData)); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// setDocument indexes the entry committed on the branch by commitID, replacing its previous document
+func (s *Service) setDocument(ctx context.Context, repository, branch, commitID string, entry *catalog.DBEntry) error {
+	if err := s.deleteDocument(ctx, repository, branch, entry.Path); err != nil {
+		return err
+	}
+	doc := &DocumentData{
+		Repository:   repository,
+		Branch:       branch,
+		Path:         entry.Path,
+		CommitId:     commitID,
+		Metadata:     entry.Metadata,
+		Tokens:       documentTokens(entry.Path, entry.Metadata),
+		LastModified: timestamppb.New(entry.CreationDate),
+	}
+	key := []byte(documentPath(repository, branch, entry.Path))
+	if err := kv.SetMsg(ctx, s.store, partition, key, doc); err != nil {
+		return err
+	}
+	for _, token := range doc.Tokens {
+		if err := kv.SetMsg(ctx, s.store, partition, []byte(tokenPath(token, repository, branch, entry.Path)), &kv.SecondaryIndex{PrimaryKey: key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDocument deletes the document of the object at p on the branch, if it is indexed
+func (s *Service) deleteDocument(ctx context.Context, repository, branch, p string) error {
+	doc := &DocumentData{}
+	_, err := kv.GetMsg(ctx, s.store, partition, []byte(documentPath(repository, branch, p)), doc)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.deleteDocumentData(ctx, doc)
+}
+
+func (s *Service) deleteDocumentData(ctx context.Context, doc *DocumentData) error {
+	for _, token := range doc.Tokens {
+		if err := s.store.Delete(ctx, []byte(partition), []byte(tokenPath(token, doc.Repository, doc.Branch, doc.Path))); err != nil {
+			return err
+		}
+	}
+	return s.store.Delete(ctx, []byte(partition), []byte(documentPath(doc.Repository, doc.Branch, doc.Path)))
+}
+
+func branchPath(repository, branch string) string {
+	return kv.FormatPath(branchesPrefix, repository, branch)
+}
+
+func documentPath(repository, branch, p string) string {
+	if p == "" {
+		// the prefix of the documents of the branch
+		return kv.FormatPath(documentsPrefix, repository, branch) + kv.PathDelimiter
+	}
+	return kv.FormatPath(documentsPrefix, repository, branch, p)
+}
+
+func tokenPath(token, repository, branch, p string) string {
+	return kv.FormatPath(tokensPrefix, token, repository, branch, p)
+}
+
+// Tokenize returns the sorted distinct lowercase words of the values, split on any character that is not a letter
+// or a digit. Words shorter than MinTokenLength are dropped.
+func Tokenize(values ...string) []string {
+	set := make(map[string]struct{})
+	for _, v := range values {
+		addTokens(set, v, -1)
+	}
+	return sortedTokens(set)
+}
+
+// addTokens adds the words of v to set, until it holds limit words unless limit is negative
+func addTokens(set map[string]struct{}, v string, limit int) {
+	words := strings.FieldsFunc(strings.ToLower(v), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if limit >= 0 && len(set) >= limit {
+			return
+		}
+		runes := []rune(word)
+		if len(runes) < MinTokenLength {
+			continue
+		}
+		if len(runes) > maxTokenLength {
+			word = string(runes[:maxTokenLength])
+		}
+		set[word] = struct{}{}
+	}
+}
+
+func sortedTokens(set map[string]struct{}) []string {
+	tokens := make([]string, 0, len(set))
+	for token := range set {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// documentTokens returns the words of the path and of the metadata keys and values of an object. Words of the path
+// are kept first when there are too many words.
+func documentTokens(p string, metadata map[string]string) []string {
+	set := make(map[string]struct{})
+	addTokens(set, p, maxDocumentTokens)
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		addTokens(set, k, maxDocumentTokens)
+		addTokens(set, metadata[k], maxDocumentTokens)
+	}
+	return sortedTokens(set)
+}
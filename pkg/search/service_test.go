@@ -0,0 +1,227 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	_ "github.com/treeverse/lakefs/pkg/kv/mem"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// fakeCatalog holds the objects of each commit as a map of path to metadata
+type fakeCatalog map[string]map[string]catalog.Metadata
+
+func (c fakeCatalog) Diff(_ context.Context, _ string, left string, right string, params catalog.DiffParams) (catalog.Differences, bool, error) {
+	_, leftFound := c[left]
+	_, rightFound := c[right]
+	if !leftFound || !rightFound {
+		return nil, false, graveler.ErrNotFound
+	}
+	var diff catalog.Differences
+	for p, metadata := range c[right] {
+		if p <= params.After {
+			continue
+		}
+		if _, ok := c[left][p]; ok {
+			diff = append(diff, catalog.Difference{DBEntry: catalog.DBEntry{Path: p, Metadata: metadata}, Type: catalog.DifferenceTypeChanged})
+		} else {
+			diff = append(diff, catalog.Difference{DBEntry: catalog.DBEntry{Path: p, Metadata: metadata}, Type: catalog.DifferenceTypeAdded})
+		}
+	}
+	for p := range c[left] {
+		if _, ok := c[right][p]; !ok && p > params.After {
+			diff = append(diff, catalog.Difference{DBEntry: catalog.DBEntry{Path: p}, Type: catalog.DifferenceTypeRemoved})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Path < diff[j].Path })
+	return diff, false, nil
+}
+
+func (c fakeCatalog) ListEntries(_ context.Context, _ string, reference string, _ string, after string, _ string, _ int) ([]*catalog.DBEntry, bool, error) {
+	var entries []*catalog.DBEntry
+	for p, metadata := range c[reference] {
+		if p > after {
+			entries = append(entries, &catalog.DBEntry{Path: p, Metadata: metadata})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, false, nil
+}
+
+// listingCatalog counts the listings of all the objects of a commit
+type listingCatalog struct {
+	fakeCatalog
+	listings int
+}
+
+func (c *listingCatalog) ListEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int) ([]*catalog.DBEntry, bool, error) {
+	if after == "" {
+		c.listings++
+	}
+	return c.fakeCatalog.ListEntries(ctx, repositoryID, reference, prefix, after, delimiter, limit)
+}
+
+func newTestService(t *testing.T, c Catalog) *Service {
+	t.Helper()
+	ctx := context.Background()
+	s, err := NewService(config.Search{Enabled: true, QueueSize: 10, MaxScan: 1000}, &graveler.HooksNoOp{}, kvtest.GetStore(ctx, t), c, logging.ContextUnavailable())
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func commitRecord(repository, branch, previous, commitID string) graveler.HookRecord {
+	return graveler.HookRecord{
+		EventType:        graveler.EventTypePostCommit,
+		RepositoryID:     graveler.RepositoryID(repository),
+		BranchID:         graveler.BranchID(branch),
+		CommitID:         graveler.CommitID(commitID),
+		PreviousCommitID: graveler.CommitID(previous),
+	}
+}
+
+// queryPaths returns the repository, branch and path of the results of the query
+func queryPaths(t *testing.T, s *Service, params QueryParams) []string {
+	t.Helper()
+	if params.Limit == 0 {
+		params.Limit = 100
+	}
+	docs, _, err := s.Query(context.Background(), params)
+	require.NoError(t, err)
+	paths := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		paths = append(paths, doc.Repository+"/"+doc.Branch+"/"+doc.Path)
+	}
+	return paths
+}
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"2023", "customer", "orders", "parquet", "part", "tables", "year"},
+		Tokenize("tables/Customer_Orders/year=2023/part-0.parquet", "orders"))
+	require.Empty(t, Tokenize("a/b-c"))
+}
+
+func TestService_Index(t *testing.T) {
+	ctx := context.Background()
+	c := fakeCatalog{
+		"c1": {
+			"tables/customer_orders/part-0.parquet": nil,
+			"tables/users/part-0.parquet":           {"owner": "growth"},
+		},
+		"c2": {
+			"tables/customer_orders/part-0.parquet": nil,
+			"tables/customer_orders/part-1.parquet": nil,
+			"tables/users/part-0.parquet":           {"owner": "identity"},
+		},
+		"c3": {
+			"raw/events.json": nil,
+		},
+	}
+	listing := &listingCatalog{fakeCatalog: c}
+	s := newTestService(t, listing)
+
+	// the first commit indexed builds the index of the branch
+	require.NoError(t, s.index(ctx, commitRecord("repo1", "main", "c0", "c1")))
+	require.Equal(t, 1, listing.listings)
+	require.Equal(t, []string{"repo1/main/tables/customer_orders/part-0.parquet"}, queryPaths(t, s, QueryParams{Query: "orders"}))
+	require.Equal(t, []string{"repo1/main/tables/users/part-0.parquet"}, queryPaths(t, s, QueryParams{Query: "grow"}))
+
+	// following commits update it
+	require.NoError(t, s.index(ctx, commitRecord("repo1", "main", "c1", "c2")))
+	require.Equal(t, []string{
+		"repo1/main/tables/customer_orders/part-0.parquet",
+		"repo1/main/tables/customer_orders/part-1.parquet",
+	}, queryPaths(t, s, QueryParams{Query: "cust ord"}))
+	require.Empty(t, queryPaths(t, s, QueryParams{Query: "growth"}))
+	require.Equal(t, []string{"repo1/main/tables/users/part-0.parquet"}, queryPaths(t, s, QueryParams{Query: "users identity"}))
+
+	// a commit not following the indexed one is indexed by the diff from the indexed commit
+	require.NoError(t, s.index(ctx, commitRecord("repo1", "main", "c9", "c3")))
+	require.Empty(t, queryPaths(t, s, QueryParams{Query: "parquet"}))
+	require.Equal(t, []string{"repo1/main/raw/events.json"}, queryPaths(t, s, QueryParams{Query: "events"}))
+	require.Equal(t, 1, listing.listings)
+
+	// the branch is indexed from scratch when its indexed commit no longer exists
+	c["c4"] = c["c3"]
+	delete(c, "c3")
+	require.NoError(t, s.index(ctx, commitRecord("repo1", "main", "c3", "c4")))
+	require.Equal(t, []string{"repo1/main/raw/events.json"}, queryPaths(t, s, QueryParams{Query: "events"}))
+	require.Equal(t, 2, listing.listings)
+
+	// branches are indexed separately
+	require.NoError(t, s.index(ctx, graveler.HookRecord{
+		EventType:    graveler.EventTypePostCreateBranch,
+		RepositoryID: "repo2",
+		BranchID:     "dev",
+		CommitID:     "c1",
+	}))
+	require.Equal(t, []string{
+		"repo1/main/raw/events.json",
+	}, queryPaths(t, s, QueryParams{Query: "json"}))
+	require.Equal(t, []string{"repo2/dev/tables/users/part-0.parquet"}, queryPaths(t, s, QueryParams{Query: "users"}))
+	require.Empty(t, queryPaths(t, s, QueryParams{Query: "users", Repository: "repo1"}))
+	require.Empty(t, queryPaths(t, s, QueryParams{Query: "users", Allowed: func(repository string) bool { return repository != "repo2" }}))
+
+	require.NoError(t, s.index(ctx, graveler.HookRecord{
+		EventType:    graveler.EventTypePostDeleteBranch,
+		RepositoryID: "repo2",
+		BranchID:     "dev",
+	}))
+	require.Empty(t, queryPaths(t, s, QueryParams{Query: "users"}))
+}
+
+func TestService_DeleteRepository(t *testing.T) {
+	ctx := context.Background()
+	c := fakeCatalog{"c1": {"tables/users/part-0.parquet": nil}}
+	s := newTestService(t, c)
+	for _, record := range []graveler.HookRecord{
+		commitRecord("repo", "main", "", "c1"),
+		commitRecord("repo", "dev", "", "c1"),
+		commitRecord("repo2", "main", "", "c1"),
+	} {
+		require.NoError(t, s.index(ctx, record))
+	}
+
+	require.NoError(t, s.index(ctx, graveler.HookRecord{EventType: eventTypeDeleteRepository, RepositoryID: "repo"}))
+	require.Equal(t, []string{"repo2/main/tables/users/part-0.parquet"}, queryPaths(t, s, QueryParams{Query: "users"}))
+	for _, branch := range []string{"main", "dev"} {
+		_, err := kv.GetMsg(ctx, s.store, partition, []byte(branchPath("repo", branch)), &BranchData{})
+		require.ErrorIs(t, err, kv.ErrNotFound)
+	}
+}
+
+func TestService_QueryPagination(t *testing.T) {
+	ctx := context.Background()
+	c := fakeCatalog{"c1": {}}
+	for _, p := range []string{"a/orders1", "b/orders2", "c/ordinal", "d/orders3"} {
+		c["c1"][p] = catalog.Metadata{"kind": "order_table"}
+	}
+	s := newTestService(t, c)
+	require.NoError(t, s.index(ctx, commitRecord("repo", "main", "", "c1")))
+
+	var all []string
+	after := ""
+	for {
+		docs, next, err := s.Query(ctx, QueryParams{Query: "ord", After: after, Limit: 1})
+		require.NoError(t, err)
+		for _, doc := range docs {
+			all = append(all, doc.Path)
+		}
+		if next == "" {
+			break
+		}
+		after = next
+	}
+	// every document is found once, by the first of its words beginning with the query
+	require.ElementsMatch(t, []string{"a/orders1", "b/orders2", "c/ordinal", "d/orders3"}, all)
+
+	_, _, err := s.Query(ctx, QueryParams{Query: "a/", Limit: 1})
+	require.ErrorIs(t, err, ErrInvalidQuery)
+}